@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/nicekwell/easyweb3-cli/internal/client"
@@ -29,6 +30,9 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		resume := fs.Bool("resume", true, "resume")
 		tagID := fs.Int("tag-id", 0, "tag id")
 		closed := fs.String("closed", "", "open|closed")
+		tagIDs := fs.String("tag-ids", "", "comma-separated tag ids to include")
+		excludeTagIDs := fs.String("exclude-tag-ids", "", "comma-separated tag ids to exclude")
+		profile := fs.String("profile", "", "named sync profile from config")
 		_ = fs.Parse(args[1:])
 
 		q := "?scope=" + urlQueryEscape(*scope)
@@ -45,9 +49,21 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		if strings.TrimSpace(*closed) != "" {
 			q += "&closed=" + urlQueryEscape(strings.TrimSpace(*closed))
 		}
+		if strings.TrimSpace(*tagIDs) != "" {
+			q += "&tag_ids=" + urlQueryEscape(strings.TrimSpace(*tagIDs))
+		}
+		if strings.TrimSpace(*excludeTagIDs) != "" {
+			q += "&exclude_tag_ids=" + urlQueryEscape(strings.TrimSpace(*excludeTagIDs))
+		}
+		if strings.TrimSpace(*profile) != "" {
+			q += "&profile=" + urlQueryEscape(strings.TrimSpace(*profile))
+		}
 
 		return polymarketDo(ctx, http.MethodPost, "/api/catalog/sync"+q, nil)
 
+	case "catalog-sync-profiles":
+		return polymarketDo(ctx, http.MethodGet, "/api/catalog/sync-profiles", nil)
+
 	case "catalog-events":
 		fs := flag.NewFlagSet("easyweb3 api polymarket catalog-events", flag.ContinueOnError)
 		fs.SetOutput(os.Stderr)
@@ -88,6 +104,54 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		}
 		return polymarketDo(ctx, http.MethodGet, "/api/catalog/markets"+q, nil)
 
+	case "catalog-tokens-batch":
+		fs := flag.NewFlagSet("easyweb3 api polymarket catalog-tokens-batch", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		ids := fs.String("ids", "", "comma-separated token ids")
+		_ = fs.Parse(args[1:])
+		list := []string{}
+		for _, id := range strings.Split(*ids, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				list = append(list, id)
+			}
+		}
+		if len(list) == 0 {
+			return errors.New("--ids required")
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/catalog/tokens/batch", map[string]any{"token_ids": list})
+
+	case "find":
+		usage := errors.New("usage: easyweb3 api polymarket find \"<query>\" [--act watch|label|plan] [--pick N] [--label L] [--size-usd N]")
+		if len(args) < 2 || strings.TrimSpace(args[1]) == "" || strings.HasPrefix(args[1], "-") {
+			return usage
+		}
+		fs := flag.NewFlagSet("easyweb3 api polymarket find", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		act := fs.String("act", "", "watch|label|plan (default: list matches)")
+		limit := fs.Int("limit", 10, "max matches")
+		pick := fs.Int("pick", 0, "act on the Nth match (1-based) when several match")
+		active := fs.Bool("active", true, "only open markets")
+		label := fs.String("label", "", "label to apply (--act label)")
+		source := fs.String("source", "watchlist:find", "stream pin owner (--act watch)")
+		outcome := fs.String("outcome", "yes", "outcome token to trade (--act plan)")
+		direction := fs.String("direction", "", "leg direction, e.g. BUY_YES (--act plan, default from outcome)")
+		sizeUSD := fs.Float64("size-usd", 0, "leg size in USD (--act plan)")
+		strategy := fs.String("strategy", "manual", "strategy name (--act plan)")
+		_ = fs.Parse(args[2:])
+		return polymarketFind(ctx, findOptions{
+			Query:     strings.TrimSpace(args[1]),
+			Act:       strings.ToLower(strings.TrimSpace(*act)),
+			Limit:     *limit,
+			Pick:      *pick,
+			Active:    *active,
+			Label:     *label,
+			Source:    strings.TrimSpace(*source),
+			Outcome:   strings.TrimSpace(*outcome),
+			Direction: *direction,
+			SizeUSD:   *sizeUSD,
+			Strategy:  strings.TrimSpace(*strategy),
+		})
+
 	case "opportunities":
 		fs := flag.NewFlagSet("easyweb3 api polymarket opportunities", flag.ContinueOnError)
 		fs.SetOutput(os.Stderr)
@@ -96,6 +160,7 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		status := fs.String("status", "", "status")
 		strategy := fs.String("strategy", "", "strategy")
 		category := fs.String("category", "", "category")
+		expand := fs.String("expand", "", "market,event,labels")
 		_ = fs.Parse(args[1:])
 
 		q := fmt.Sprintf("?limit=%d&offset=%d", *limit, *offset)
@@ -108,17 +173,28 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		if strings.TrimSpace(*category) != "" {
 			q += "&category=" + urlQueryEscape(strings.TrimSpace(*category))
 		}
+		if strings.TrimSpace(*expand) != "" {
+			q += "&expand=" + urlQueryEscape(strings.TrimSpace(*expand))
+		}
 		return polymarketDo(ctx, http.MethodGet, "/api/v2/opportunities"+q, nil)
 
 	case "opportunity-get":
 		if len(args) < 2 {
-			return errors.New("usage: easyweb3 api polymarket opportunity-get <id>")
+			return errors.New("usage: easyweb3 api polymarket opportunity-get <id> [--expand market,event,labels]")
 		}
 		id := strings.TrimSpace(args[1])
 		if id == "" {
 			return errors.New("id required")
 		}
-		return polymarketDo(ctx, http.MethodGet, "/api/v2/opportunities/"+id, nil)
+		fs := flag.NewFlagSet("easyweb3 api polymarket opportunity-get", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		expand := fs.String("expand", "", "market,event,labels")
+		_ = fs.Parse(args[2:])
+		q := ""
+		if strings.TrimSpace(*expand) != "" {
+			q = "?expand=" + urlQueryEscape(strings.TrimSpace(*expand))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/opportunities/"+id+q, nil)
 
 	case "opportunity-dismiss":
 		if len(args) < 2 {
@@ -130,6 +206,24 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		}
 		return polymarketDo(ctx, http.MethodPost, "/api/v2/opportunities/"+id+"/dismiss", map[string]any{})
 
+	case "opportunity-cost-forecast":
+		if len(args) < 2 {
+			return errors.New("usage: easyweb3 api polymarket opportunity-cost-forecast <id> [--size-usd N]")
+		}
+		id := strings.TrimSpace(args[1])
+		if id == "" {
+			return errors.New("id required")
+		}
+		fs := flag.NewFlagSet("easyweb3 api polymarket opportunity-cost-forecast", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		size := fs.String("size-usd", "", "planned size (default: risk-suggested size)")
+		_ = fs.Parse(args[2:])
+		q := ""
+		if strings.TrimSpace(*size) != "" {
+			q = "?size_usd=" + urlQueryEscape(strings.TrimSpace(*size))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/opportunities/"+id+"/cost-forecast"+q, nil)
+
 	case "opportunity-execute":
 		if len(args) < 2 {
 			return errors.New("usage: easyweb3 api polymarket opportunity-execute <id>")
@@ -146,12 +240,16 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		limit := fs.Int("limit", 50, "limit")
 		offset := fs.Int("offset", 0, "offset")
 		status := fs.String("status", "", "status")
+		triggerState := fs.String("trigger-state", "", "scheduled|fired|failed (time-triggered plans)")
 		_ = fs.Parse(args[1:])
 
 		q := fmt.Sprintf("?limit=%d&offset=%d", *limit, *offset)
 		if strings.TrimSpace(*status) != "" {
 			q += "&status=" + urlQueryEscape(strings.TrimSpace(*status))
 		}
+		if strings.TrimSpace(*triggerState) != "" {
+			q += "&trigger_state=" + urlQueryEscape(strings.TrimSpace(*triggerState))
+		}
 		return polymarketDo(ctx, http.MethodGet, "/api/v2/executions"+q, nil)
 
 	case "execution-get":
@@ -164,6 +262,23 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		}
 		return polymarketDo(ctx, http.MethodGet, "/api/v2/executions/"+id, nil)
 
+	case "execution-risk-report":
+		if len(args) < 2 {
+			return errors.New("usage: easyweb3 api polymarket execution-risk-report <id> [--markdown]")
+		}
+		id := strings.TrimSpace(args[1])
+		if id == "" {
+			return errors.New("id required")
+		}
+		fs := flag.NewFlagSet("easyweb3 api polymarket execution-risk-report", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		markdown := fs.Bool("markdown", false, "print the rendered markdown report")
+		_ = fs.Parse(args[2:])
+		if *markdown {
+			return polymarketRiskReportMarkdown(ctx, id)
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/executions/"+id+"/risk-report", nil)
+
 	case "execution-preflight":
 		if len(args) < 2 {
 			return errors.New("usage: easyweb3 api polymarket execution-preflight <id>")
@@ -194,6 +309,53 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		}
 		return polymarketDo(ctx, http.MethodPost, "/api/v2/executions/"+id+"/mark-executed", map[string]any{})
 
+	case "execution-fill-import":
+		usage := errors.New("usage: easyweb3 api polymarket execution-fill-import <id> --file fills.csv|fills.json [--dry-run]")
+		if len(args) < 2 || strings.TrimSpace(args[1]) == "" {
+			return usage
+		}
+		id := strings.TrimSpace(args[1])
+		fs := flag.NewFlagSet("easyweb3 api polymarket execution-fill-import", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		file := fs.String("file", "", "CSV (with header row) or JSON file of fills")
+		dryRun := fs.Bool("dry-run", false, "validate and report duplicates without recording")
+		_ = fs.Parse(args[2:])
+		if strings.TrimSpace(*file) == "" {
+			return usage
+		}
+		return polymarketFillImport(ctx, id, strings.TrimSpace(*file), *dryRun)
+
+	case "execution-trigger":
+		usage := errors.New("usage: easyweb3 api polymarket execution-trigger <id> --at RFC3339 | --minutes-before-end N [--market-id ...] | --clear")
+		if len(args) < 2 || strings.TrimSpace(args[1]) == "" {
+			return usage
+		}
+		id := strings.TrimSpace(args[1])
+		fs := flag.NewFlagSet("easyweb3 api polymarket execution-trigger", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		at := fs.String("at", "", "execute at this RFC3339 time")
+		minutes := fs.Int("minutes-before-end", 0, "execute this many minutes before the market's event ends")
+		marketID := fs.String("market-id", "", "anchor market (default: first leg's market)")
+		clear := fs.Bool("clear", false, "remove the scheduled trigger")
+		_ = fs.Parse(args[2:])
+		if *clear {
+			return polymarketDo(ctx, http.MethodDelete, "/api/v2/executions/"+id+"/trigger", nil)
+		}
+		body := map[string]any{}
+		if v := strings.TrimSpace(*at); v != "" {
+			body["at"] = v
+		}
+		if *minutes > 0 {
+			body["minutes_before_end"] = *minutes
+		}
+		if len(body) != 1 {
+			return usage
+		}
+		if v := strings.TrimSpace(*marketID); v != "" {
+			body["market_id"] = v
+		}
+		return polymarketDo(ctx, http.MethodPut, "/api/v2/executions/"+id+"/trigger", body)
+
 	case "execution-cancel":
 		if len(args) < 2 {
 			return errors.New("usage: easyweb3 api polymarket execution-cancel <id>")
@@ -253,6 +415,34 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		}
 		return polymarketDo(ctx, http.MethodPost, "/api/v2/executions/"+strings.TrimSpace(*planID)+"/settle", anyBody)
 
+	case "execution-pnl-recalc":
+		fs := flag.NewFlagSet("easyweb3 api polymarket execution-pnl-recalc", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		planID := fs.String("id", "", "plan id")
+		apply := fs.Bool("apply", false, "overwrite the stored pnl record")
+		reason := fs.String("reason", "", "why the correction is applied (required with --apply)")
+		outcomes := fs.String("outcomes", "", "market outcome overrides, e.g. m1=YES,m2=NO")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*planID) == "" {
+			return errors.New("--id required")
+		}
+		if *apply && strings.TrimSpace(*reason) == "" {
+			return errors.New("--reason required with --apply")
+		}
+		body := map[string]any{"apply": *apply, "reason": strings.TrimSpace(*reason)}
+		if strings.TrimSpace(*outcomes) != "" {
+			m := map[string]string{}
+			for _, part := range strings.Split(*outcomes, ",") {
+				k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+				if !ok || strings.TrimSpace(k) == "" || strings.TrimSpace(v) == "" {
+					return errors.New("--outcomes must be market_id=OUTCOME pairs")
+				}
+				m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+			}
+			body["market_outcomes"] = m
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/executions/"+strings.TrimSpace(*planID)+"/pnl/recalculate", body)
+
 	case "execution-submit":
 		if len(args) < 2 {
 			return errors.New("usage: easyweb3 api polymarket execution-submit <id>")
@@ -271,6 +461,7 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		status := fs.String("status", "", "status")
 		planID := fs.String("plan-id", "", "plan id")
 		tokenID := fs.String("token-id", "", "token id")
+		lineageID := fs.String("lineage-id", "", "logical order id (all amend replacements)")
 		_ = fs.Parse(args[1:])
 		q := fmt.Sprintf("?limit=%d&offset=%d", *limit, *offset)
 		if strings.TrimSpace(*status) != "" {
@@ -282,6 +473,9 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		if strings.TrimSpace(*tokenID) != "" {
 			q += "&token_id=" + urlQueryEscape(strings.TrimSpace(*tokenID))
 		}
+		if strings.TrimSpace(*lineageID) != "" {
+			q += "&lineage_id=" + urlQueryEscape(strings.TrimSpace(*lineageID))
+		}
 		return polymarketDo(ctx, http.MethodGet, "/api/v2/orders"+q, nil)
 
 	case "order-get":
@@ -304,6 +498,31 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		}
 		return polymarketDo(ctx, http.MethodPost, "/api/v2/orders/"+id+"/cancel", map[string]any{})
 
+	case "order-amend":
+		if len(args) < 2 {
+			return errors.New("usage: easyweb3 api polymarket order-amend <id> [--price <p>] [--size-usd <usd>]")
+		}
+		id := strings.TrimSpace(args[1])
+		if id == "" {
+			return errors.New("id required")
+		}
+		fs := flag.NewFlagSet("easyweb3 api polymarket order-amend", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		price := fs.Float64("price", 0, "new limit price")
+		sizeUSD := fs.Float64("size-usd", 0, "new total size in USD (including filled)")
+		_ = fs.Parse(args[2:])
+		body := map[string]any{}
+		if *price > 0 {
+			body["price"] = *price
+		}
+		if *sizeUSD > 0 {
+			body["size_usd"] = *sizeUSD
+		}
+		if len(body) == 0 {
+			return errors.New("--price or --size-usd required")
+		}
+		return polymarketDo(ctx, http.MethodPatch, "/api/v2/orders/"+id, body)
+
 	case "positions":
 		fs := flag.NewFlagSet("easyweb3 api polymarket positions", flag.ContinueOnError)
 		fs.SetOutput(os.Stderr)
@@ -312,28 +531,78 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		status := fs.String("status", "", "open|closed")
 		strategy := fs.String("strategy", "", "strategy_name")
 		marketID := fs.String("market-id", "", "market id")
+		source := fs.String("source", "", "system|external")
+		expand := fs.String("expand", "", "market,event,labels")
 		_ = fs.Parse(args[1:])
 		q := fmt.Sprintf("?limit=%d&offset=%d", *limit, *offset)
 		if strings.TrimSpace(*status) != "" {
 			q += "&status=" + urlQueryEscape(strings.TrimSpace(*status))
 		}
+		if strings.TrimSpace(*source) != "" {
+			q += "&source=" + urlQueryEscape(strings.TrimSpace(*source))
+		}
 		if strings.TrimSpace(*strategy) != "" {
 			q += "&strategy_name=" + urlQueryEscape(strings.TrimSpace(*strategy))
 		}
 		if strings.TrimSpace(*marketID) != "" {
 			q += "&market_id=" + urlQueryEscape(strings.TrimSpace(*marketID))
 		}
+		if strings.TrimSpace(*expand) != "" {
+			q += "&expand=" + urlQueryEscape(strings.TrimSpace(*expand))
+		}
 		return polymarketDo(ctx, http.MethodGet, "/api/v2/positions"+q, nil)
 
 	case "position-get":
 		if len(args) < 2 {
-			return errors.New("usage: easyweb3 api polymarket position-get <id>")
+			return errors.New("usage: easyweb3 api polymarket position-get <id> [--expand market,event,labels]")
 		}
 		id := strings.TrimSpace(args[1])
 		if id == "" {
 			return errors.New("id required")
 		}
-		return polymarketDo(ctx, http.MethodGet, "/api/v2/positions/"+id, nil)
+		fs := flag.NewFlagSet("easyweb3 api polymarket position-get", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		expand := fs.String("expand", "", "market,event,labels")
+		_ = fs.Parse(args[2:])
+		q := ""
+		if strings.TrimSpace(*expand) != "" {
+			q = "?expand=" + urlQueryEscape(strings.TrimSpace(*expand))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/positions/"+id+q, nil)
+
+	case "positions-rebuild":
+		fs := flag.NewFlagSet("easyweb3 api polymarket positions-rebuild", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		tokenID := fs.String("token-id", "", "only rebuild this token")
+		apply := fs.Bool("apply", false, "write rebuilt positions (default: dry run)")
+		_ = fs.Parse(args[1:])
+		body := map[string]any{"apply": *apply}
+		if strings.TrimSpace(*tokenID) != "" {
+			body["token_id"] = strings.TrimSpace(*tokenID)
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/positions/rebuild", body)
+
+	case "positions-import":
+		fs := flag.NewFlagSet("easyweb3 api polymarket positions-import", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		wallet := fs.String("wallet", "", "wallet address")
+		positions := fs.String("positions", "", "json array of holdings (default: fetch from data-api)")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*wallet) == "" {
+			return errors.New("--wallet required")
+		}
+		body := map[string]any{"wallet": strings.TrimSpace(*wallet)}
+		if strings.TrimSpace(*positions) != "" {
+			var items []any
+			if err := json.Unmarshal([]byte(*positions), &items); err != nil {
+				return errors.New("--positions must be a json array")
+			}
+			body["positions"] = items
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/positions/import", body)
+
+	case "positions-import-status":
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/positions/import/status", nil)
 
 	case "portfolio-summary":
 		return polymarketDo(ctx, http.MethodGet, "/api/v2/positions/summary", nil)
@@ -355,6 +624,27 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		}
 		return polymarketDo(ctx, http.MethodGet, "/api/v2/portfolio/history"+q, nil)
 
+	case "portfolio-anomalies":
+		fs := flag.NewFlagSet("easyweb3 api polymarket portfolio-anomalies", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		limit := fs.Int("limit", 50, "limit")
+		offset := fs.Int("offset", 0, "offset")
+		kind := fs.String("kind", "", "cost_basis_jump|pnl_swing|position_count")
+		since := fs.String("since", "", "RFC3339")
+		until := fs.String("until", "", "RFC3339")
+		_ = fs.Parse(args[1:])
+		q := fmt.Sprintf("?limit=%d&offset=%d", *limit, *offset)
+		if strings.TrimSpace(*kind) != "" {
+			q += "&kind=" + urlQueryEscape(strings.TrimSpace(*kind))
+		}
+		if strings.TrimSpace(*since) != "" {
+			q += "&since=" + urlQueryEscape(strings.TrimSpace(*since))
+		}
+		if strings.TrimSpace(*until) != "" {
+			q += "&until=" + urlQueryEscape(strings.TrimSpace(*until))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/portfolio/anomalies"+q, nil)
+
 	case "analytics-daily":
 		fs := flag.NewFlagSet("easyweb3 api polymarket analytics-daily", flag.ContinueOnError)
 		fs.SetOutput(os.Stderr)
@@ -382,25 +672,12 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		name := fs.String("strategy", "", "strategy name")
 		since := fs.String("since", "", "RFC3339")
 		until := fs.String("until", "", "RFC3339")
+		asOf := fs.String("as-of", "", "RFC3339 point-in-time")
 		_ = fs.Parse(args[1:])
 		if strings.TrimSpace(*name) == "" {
 			return errors.New("--strategy required")
 		}
-		q := ""
-		if strings.TrimSpace(*since) != "" || strings.TrimSpace(*until) != "" {
-			q = "?"
-			first := true
-			if strings.TrimSpace(*since) != "" {
-				q += "since=" + urlQueryEscape(strings.TrimSpace(*since))
-				first = false
-			}
-			if strings.TrimSpace(*until) != "" {
-				if !first {
-					q += "&"
-				}
-				q += "until=" + urlQueryEscape(strings.TrimSpace(*until))
-			}
-		}
+		q := analyticsQuery(*since, *until, *asOf)
 		return polymarketDo(ctx, http.MethodGet, "/api/v2/analytics/strategy/"+urlQueryEscape(strings.TrimSpace(*name))+"/attribution"+q, nil)
 
 	case "analytics-drawdown":
@@ -409,8 +686,88 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 	case "analytics-correlation":
 		return polymarketDo(ctx, http.MethodGet, "/api/v2/analytics/correlation", nil)
 
+	case "analytics-overview":
+		fs := flag.NewFlagSet("easyweb3 api polymarket analytics-overview", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		asOf := fs.String("as-of", "", "RFC3339 point-in-time")
+		_ = fs.Parse(args[1:])
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/analytics/overview"+analyticsQuery("", "", *asOf), nil)
+
 	case "analytics-ratios":
-		return polymarketDo(ctx, http.MethodGet, "/api/v2/analytics/ratios", nil)
+		fs := flag.NewFlagSet("easyweb3 api polymarket analytics-ratios", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		since := fs.String("since", "", "RFC3339")
+		until := fs.String("until", "", "RFC3339")
+		asOf := fs.String("as-of", "", "RFC3339 point-in-time")
+		_ = fs.Parse(args[1:])
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/analytics/ratios"+analyticsQuery(*since, *until, *asOf), nil)
+
+	case "analytics-breakdowns":
+		fs := flag.NewFlagSet("easyweb3 api polymarket analytics-breakdowns", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		dimension := fs.String("dimension", "", "label|time-of-day|hold-duration (default all)")
+		strategy := fs.String("strategy", "", "strategy_name")
+		bucketHours := fs.Int("bucket-hours", 0, "time-of-day bucket width in hours")
+		since := fs.String("since", "", "RFC3339")
+		until := fs.String("until", "", "RFC3339")
+		_ = fs.Parse(args[1:])
+		path := "/api/v2/analytics/breakdowns"
+		if v := strings.TrimSpace(*dimension); v != "" {
+			path += "/" + urlQueryEscape(v)
+		}
+		q := analyticsQuery(*since, *until, "")
+		sep := "?"
+		if q != "" {
+			sep = "&"
+		}
+		if v := strings.TrimSpace(*strategy); v != "" {
+			q += sep + "strategy_name=" + urlQueryEscape(v)
+			sep = "&"
+		}
+		if *bucketHours > 0 {
+			q += sep + fmt.Sprintf("bucket_hours=%d", *bucketHours)
+		}
+		return polymarketDo(ctx, http.MethodGet, path+q, nil)
+
+	case "analytics-cost-forecast-accuracy":
+		fs := flag.NewFlagSet("easyweb3 api polymarket analytics-cost-forecast-accuracy", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		strategy := fs.String("strategy", "", "strategy_name")
+		since := fs.String("since", "", "RFC3339")
+		until := fs.String("until", "", "RFC3339")
+		_ = fs.Parse(args[1:])
+		q := analyticsQuery(*since, *until, "")
+		if v := strings.TrimSpace(*strategy); v != "" {
+			sep := "?"
+			if q != "" {
+				sep = "&"
+			}
+			q += sep + "strategy_name=" + urlQueryEscape(v)
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/analytics/cost-forecast-accuracy"+q, nil)
+
+	case "analytics-edge-heatmap":
+		fs := flag.NewFlagSet("easyweb3 api polymarket analytics-edge-heatmap", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		strategy := fs.String("strategy", "", "strategy_name")
+		label := fs.String("label", "", "market label (unlabeled for markets without one)")
+		edgeBounds := fs.String("edge-bounds", "", "comma-separated expected edge bucket bounds, e.g. 0.01,0.02,0.05")
+		roiBounds := fs.String("roi-bounds", "", "comma-separated realized ROI bucket bounds, e.g. -0.1,0,0.1")
+		since := fs.String("since", "", "RFC3339")
+		until := fs.String("until", "", "RFC3339")
+		_ = fs.Parse(args[1:])
+		q := analyticsQuery(*since, *until, "")
+		sep := "?"
+		if q != "" {
+			sep = "&"
+		}
+		for _, kv := range [][2]string{{"strategy_name", *strategy}, {"label", *label}, {"edge_bounds", *edgeBounds}, {"roi_bounds", *roiBounds}} {
+			if v := strings.TrimSpace(kv[1]); v != "" {
+				q += sep + kv[0] + "=" + urlQueryEscape(v)
+				sep = "&"
+			}
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/analytics/edge-heatmap"+q, nil)
 
 	case "review":
 		fs := flag.NewFlagSet("easyweb3 api polymarket review", flag.ContinueOnError)
@@ -474,6 +831,399 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 			"lesson_tags": tags,
 		})
 
+	case "strategy-dependencies":
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/strategies/dependencies", nil)
+
+	case "strategy-schema":
+		fs := flag.NewFlagSet("easyweb3 api polymarket strategy-schema", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		name := fs.String("name", "", "strategy name")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*name) == "" {
+			return errors.New("--name required")
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/strategies/"+urlQueryEscape(strings.TrimSpace(*name))+"/schema", nil)
+
+	case "signal-types":
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/signals/types", nil)
+
+	case "signal-source-config", "signal-source-test-fire":
+		fs := flag.NewFlagSet("easyweb3 api polymarket "+op, flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		settings := fs.String("settings", "", "json object of collector settings (config applies them; test-fire tries them on a copy)")
+		if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+			return errors.New("usage: easyweb3 api polymarket " + op + " <collector> [--settings '{...}']")
+		}
+		name := strings.TrimSpace(args[1])
+		_ = fs.Parse(args[2:])
+		var body any
+		if strings.TrimSpace(*settings) != "" {
+			if err := json.Unmarshal([]byte(*settings), &body); err != nil {
+				return errors.New("--settings must be valid json")
+			}
+		}
+		path := "/api/v2/signals/sources/" + urlQueryEscape(name)
+		if op == "signal-source-test-fire" {
+			return polymarketDo(ctx, http.MethodPost, path+"/test-fire", body)
+		}
+		if body == nil {
+			return polymarketDo(ctx, http.MethodGet, path+"/config", nil)
+		}
+		return polymarketDo(ctx, http.MethodPut, path+"/config", body)
+
+	case "strategies-bulk-enable", "strategies-bulk-disable":
+		fs := flag.NewFlagSet("easyweb3 api polymarket "+op, flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		names := fs.String("names", "", "comma-separated strategy names")
+		category := fs.String("category", "", "strategy category")
+		autoSwitches := fs.Bool("auto-switches", false, "toggle dependent feature switches")
+		dryRun := fs.Bool("dry-run", false, "report the plan without applying it")
+		_ = fs.Parse(args[1:])
+		list := []string{}
+		for _, n := range strings.Split(*names, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				list = append(list, n)
+			}
+		}
+		if len(list) == 0 && strings.TrimSpace(*category) == "" {
+			return errors.New("--names or --category required")
+		}
+		action := strings.TrimPrefix(op, "strategies-bulk-")
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/strategies/bulk/"+action, map[string]any{
+			"names":         list,
+			"category":      strings.TrimSpace(*category),
+			"auto_switches": *autoSwitches,
+			"dry_run":       *dryRun,
+		})
+
+	case "strategy-launch-stage":
+		fs := flag.NewFlagSet("easyweb3 api polymarket strategy-launch-stage", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		name := fs.String("name", "", "strategy name")
+		stage := fs.String("stage", "", "dark|shadow|capped|full")
+		reason := fs.String("reason", "", "why the stage changes")
+		force := fs.Bool("force", false, "allow skipping stages on promotion")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*name) == "" || strings.TrimSpace(*stage) == "" {
+			return errors.New("--name and --stage required")
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/strategies/"+urlQueryEscape(strings.TrimSpace(*name))+"/launch-stage", map[string]any{
+			"stage":  strings.TrimSpace(*stage),
+			"reason": strings.TrimSpace(*reason),
+			"force":  *force,
+		})
+
+	case "strategy-budget":
+		fs := flag.NewFlagSet("easyweb3 api polymarket strategy-budget", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		name := fs.String("name", "", "strategy name")
+		dailyTurnover := fs.String("daily-turnover-usd", "", "daily gross notional cap (0 removes it)")
+		weeklyTurnover := fs.String("weekly-turnover-usd", "", "weekly gross notional cap (0 removes it)")
+		dailyFees := fs.String("daily-fees-usd", "", "daily fee cap (0 removes it)")
+		weeklyFees := fs.String("weekly-fees-usd", "", "weekly fee cap (0 removes it)")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*name) == "" {
+			return errors.New("--name required")
+		}
+		path := "/api/v2/strategies/" + urlQueryEscape(strings.TrimSpace(*name)) + "/budget"
+		body := map[string]any{}
+		for key, v := range map[string]string{
+			"daily_turnover_usd":  *dailyTurnover,
+			"weekly_turnover_usd": *weeklyTurnover,
+			"daily_fees_usd":      *dailyFees,
+			"weekly_fees_usd":     *weeklyFees,
+		} {
+			if strings.TrimSpace(v) != "" {
+				body[key] = strings.TrimSpace(v)
+			}
+		}
+		if len(body) == 0 {
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		}
+		return polymarketDo(ctx, http.MethodPut, path, body)
+
+	case "strategy-change-analysis":
+		fs := flag.NewFlagSet("easyweb3 api polymarket strategy-change-analysis", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		name := fs.String("name", "", "strategy name")
+		changeID := fs.Uint64("change-id", 0, "params change id (default: latest)")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*name) == "" {
+			return errors.New("--name required")
+		}
+		path := "/api/v2/strategies/" + urlQueryEscape(strings.TrimSpace(*name)) + "/change-analysis"
+		if *changeID > 0 {
+			path += fmt.Sprintf("?change_id=%d", *changeID)
+		}
+		return polymarketDo(ctx, http.MethodGet, path, nil)
+
+	case "strategy-capacity":
+		fs := flag.NewFlagSet("easyweb3 api polymarket strategy-capacity", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		name := fs.String("name", "", "strategy name")
+		refresh := fs.Bool("refresh", false, "re-estimate from current depth")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*name) == "" {
+			return errors.New("--name required")
+		}
+		path := "/api/v2/strategies/" + urlQueryEscape(strings.TrimSpace(*name)) + "/capacity"
+		if *refresh {
+			path += "?refresh=true"
+		}
+		return polymarketDo(ctx, http.MethodGet, path, nil)
+
+	case "strategy-drift":
+		fs := flag.NewFlagSet("easyweb3 api polymarket strategy-drift", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		name := fs.String("name", "", "strategy name (default: report for all)")
+		refresh := fs.Bool("refresh", false, "re-evaluate against the latest daily stats")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*name) == "" {
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/strategies/drift", nil)
+		}
+		path := "/api/v2/strategies/" + urlQueryEscape(strings.TrimSpace(*name)) + "/drift"
+		if *refresh {
+			path += "?refresh=true"
+		}
+		return polymarketDo(ctx, http.MethodGet, path, nil)
+
+	case "strategy-expectation", "strategy-expectation-set", "strategy-expectation-delete":
+		fs := flag.NewFlagSet("easyweb3 api polymarket "+op, flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		name := fs.String("name", "", "strategy name")
+		winRate := fs.String("win-rate", "", "expected share of decided trades won (0-1)")
+		edge := fs.String("edge", "", "expected average edge per trade, e.g. 0.03")
+		tradesPerDay := fs.String("trades-per-day", "", "expected trades per day")
+		source := fs.String("source", "", "where the profile came from, e.g. a backtest run id")
+		from := fs.String("backtest-from", "", "backtest window start (RFC3339)")
+		to := fs.String("backtest-to", "", "backtest window end (RFC3339)")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*name) == "" {
+			return errors.New("--name required")
+		}
+		path := "/api/v2/strategies/" + urlQueryEscape(strings.TrimSpace(*name)) + "/expectation"
+		switch op {
+		case "strategy-expectation":
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		case "strategy-expectation-delete":
+			return polymarketDo(ctx, http.MethodDelete, path, nil)
+		}
+		body := map[string]any{}
+		for _, f := range []struct{ key, raw string }{
+			{"win_rate", *winRate},
+			{"edge_pct", *edge},
+			{"trades_per_day", *tradesPerDay},
+		} {
+			if strings.TrimSpace(f.raw) == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(f.raw), 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", f.key, err)
+			}
+			body[f.key] = v
+		}
+		for _, f := range [][2]string{{"source", *source}, {"backtest_from", *from}, {"backtest_to", *to}} {
+			if v := strings.TrimSpace(f[1]); v != "" {
+				body[f[0]] = v
+			}
+		}
+		if len(body) == 0 {
+			return errors.New("nothing to set")
+		}
+		return polymarketDo(ctx, http.MethodPut, path, body)
+
+	case "execution-rule-simulate":
+		fs := flag.NewFlagSet("easyweb3 api polymarket execution-rule-simulate", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		name := fs.String("strategy", "", "strategy name")
+		days := fs.Int("days", 7, "lookback in days (max 90)")
+		minConfidence := fs.String("min-confidence", "", "candidate min confidence")
+		minEdge := fs.String("min-edge-pct", "", "candidate min edge pct")
+		stopLoss := fs.String("stop-loss-pct", "", "candidate stop loss pct")
+		takeProfit := fs.String("take-profit-pct", "", "candidate take profit pct")
+		maxHold := fs.Int("max-hold-hours", 0, "candidate max hold hours")
+		maxDaily := fs.Int("max-daily-trades", 0, "candidate max daily trades")
+		condition := fs.String("condition", "", "candidate condition expression")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*name) == "" {
+			return errors.New("--strategy required")
+		}
+		body := map[string]any{"strategy": strings.TrimSpace(*name), "days": *days}
+		if v := strings.TrimSpace(*minConfidence); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return errors.New("--min-confidence must be a number")
+			}
+			body["min_confidence"] = f
+		}
+		for key, v := range map[string]string{
+			"min_edge_pct":    *minEdge,
+			"stop_loss_pct":   *stopLoss,
+			"take_profit_pct": *takeProfit,
+		} {
+			if strings.TrimSpace(v) != "" {
+				body[key] = strings.TrimSpace(v)
+			}
+		}
+		if *maxHold > 0 {
+			body["max_hold_hours"] = *maxHold
+		}
+		if *maxDaily > 0 {
+			body["max_daily_trades"] = *maxDaily
+		}
+		if strings.TrimSpace(*condition) != "" {
+			body["condition"] = strings.TrimSpace(*condition)
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/execution-rules/simulate", body)
+
+	case "rule-tunings":
+		usage := errors.New("usage: easyweb3 api polymarket rule-tunings run <strategy> [--days N] [--train-fraction F] [--min-confidence a,b] [--min-edge-pct a,b] [--stop-loss-pct a,b] [--take-profit-pct a,b] [--max-hold-hours a,b]|list <strategy> [--limit N]|get <strategy> <id>|approve|reject <strategy> <id> [--note ...]")
+		if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+			return usage
+		}
+		base := "/api/v2/execution-rules/" + urlQueryEscape(strings.TrimSpace(args[2]))
+		switch args[1] {
+		case "run":
+			fs := flag.NewFlagSet("easyweb3 api polymarket rule-tunings run", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			days := fs.Int("days", 30, "lookback in days (max 90)")
+			trainFraction := fs.Float64("train-fraction", 0, "share of the lookback candidates are ranked on (default 0.7)")
+			minConfidence := fs.String("min-confidence", "", "comma-separated min confidence values")
+			minEdge := fs.String("min-edge-pct", "", "comma-separated min edge pct values")
+			stopLoss := fs.String("stop-loss-pct", "", "comma-separated stop loss pct values")
+			takeProfit := fs.String("take-profit-pct", "", "comma-separated take profit pct values")
+			maxHold := fs.String("max-hold-hours", "", "comma-separated max hold hours")
+			_ = fs.Parse(args[3:])
+			body := map[string]any{"days": *days}
+			if *trainFraction > 0 {
+				body["train_fraction"] = *trainFraction
+			}
+			for _, f := range []struct{ key, raw string }{
+				{"min_confidence", *minConfidence},
+				{"min_edge_pct", *minEdge},
+				{"stop_loss_pct", *stopLoss},
+				{"take_profit_pct", *takeProfit},
+				{"max_hold_hours", *maxHold},
+			} {
+				var values []float64
+				for _, part := range strings.Split(f.raw, ",") {
+					if part = strings.TrimSpace(part); part == "" {
+						continue
+					}
+					v, err := strconv.ParseFloat(part, 64)
+					if err != nil {
+						return fmt.Errorf("invalid %s: %w", f.key, err)
+					}
+					values = append(values, v)
+				}
+				if len(values) > 0 {
+					body[f.key] = values
+				}
+			}
+			return polymarketDo(ctx, http.MethodPost, base+"/tune", body)
+		case "list":
+			fs := flag.NewFlagSet("easyweb3 api polymarket rule-tunings list", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			limit := fs.Int("limit", 50, "limit")
+			_ = fs.Parse(args[3:])
+			return polymarketDo(ctx, http.MethodGet, fmt.Sprintf("%s/tunings?limit=%d", base, *limit), nil)
+		case "get":
+			if len(args) < 4 || strings.TrimSpace(args[3]) == "" {
+				return usage
+			}
+			return polymarketDo(ctx, http.MethodGet, base+"/tunings/"+strings.TrimSpace(args[3]), nil)
+		case "approve", "reject":
+			if len(args) < 4 || strings.TrimSpace(args[3]) == "" {
+				return usage
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket rule-tunings "+args[1], flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			note := fs.String("note", "", "review note")
+			_ = fs.Parse(args[4:])
+			var body any
+			if strings.TrimSpace(*note) != "" {
+				body = map[string]any{"note": strings.TrimSpace(*note)}
+			}
+			return polymarketDo(ctx, http.MethodPost, base+"/tunings/"+strings.TrimSpace(args[3])+"/"+args[1], body)
+		default:
+			return usage
+		}
+
+	case "param-tunings":
+		usage := errors.New("usage: easyweb3 api polymarket param-tunings run <strategy> --grid '{\"param\":[v1,v2]}' [--days N] [--train-fraction F]|list <strategy> [--limit N]|get <strategy> <id>|approve|reject <strategy> <id> [--note ...]")
+		if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+			return usage
+		}
+		base := "/api/v2/strategies/" + urlQueryEscape(strings.TrimSpace(args[2]))
+		switch args[1] {
+		case "run":
+			fs := flag.NewFlagSet("easyweb3 api polymarket param-tunings run", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			grid := fs.String("grid", "", "JSON object of param name to the values to try")
+			days := fs.Int("days", 30, "lookback in days (max 90)")
+			trainFraction := fs.Float64("train-fraction", 0, "share of the lookback candidates are ranked on (default 0.7)")
+			_ = fs.Parse(args[3:])
+			if strings.TrimSpace(*grid) == "" {
+				return usage
+			}
+			var values map[string][]json.RawMessage
+			if err := json.Unmarshal([]byte(*grid), &values); err != nil {
+				return fmt.Errorf("invalid grid: %w", err)
+			}
+			body := map[string]any{"grid": values, "days": *days}
+			if *trainFraction > 0 {
+				body["train_fraction"] = *trainFraction
+			}
+			return polymarketDo(ctx, http.MethodPost, base+"/tune", body)
+		case "list":
+			fs := flag.NewFlagSet("easyweb3 api polymarket param-tunings list", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			limit := fs.Int("limit", 50, "limit")
+			_ = fs.Parse(args[3:])
+			return polymarketDo(ctx, http.MethodGet, fmt.Sprintf("%s/tunings?limit=%d", base, *limit), nil)
+		case "get":
+			if len(args) < 4 || strings.TrimSpace(args[3]) == "" {
+				return usage
+			}
+			return polymarketDo(ctx, http.MethodGet, base+"/tunings/"+strings.TrimSpace(args[3]), nil)
+		case "approve", "reject":
+			if len(args) < 4 || strings.TrimSpace(args[3]) == "" {
+				return usage
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket param-tunings "+args[1], flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			note := fs.String("note", "", "review note")
+			_ = fs.Parse(args[4:])
+			var body any
+			if strings.TrimSpace(*note) != "" {
+				body = map[string]any{"note": strings.TrimSpace(*note)}
+			}
+			return polymarketDo(ctx, http.MethodPost, base+"/tunings/"+strings.TrimSpace(args[3])+"/"+args[1], body)
+		default:
+			return usage
+		}
+
+	case "strategy-bundle-export":
+		fs := flag.NewFlagSet("easyweb3 api polymarket strategy-bundle-export", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		names := fs.String("names", "", "comma-separated strategy names (default all)")
+		out := fs.String("out", "", "write the bundle to this file")
+		_ = fs.Parse(args[1:])
+		return polymarketBundleExport(ctx, *names, *out)
+
+	case "strategy-bundle-import":
+		fs := flag.NewFlagSet("easyweb3 api polymarket strategy-bundle-import", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		file := fs.String("file", "", "bundle file")
+		dryRun := fs.Bool("dry-run", false, "report changes without writing")
+		overwrite := fs.Bool("overwrite", false, "replace existing strategy definitions")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*file) == "" {
+			return errors.New("--file required")
+		}
+		return polymarketBundleImport(ctx, strings.TrimSpace(*file), *dryRun, *overwrite)
+
 	case "switches":
 		return polymarketDo(ctx, http.MethodGet, "/api/v2/system-settings/switches", nil)
 
@@ -524,6 +1274,38 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 			"enabled": val == "true",
 		})
 
+	case "safe-mode":
+		usage := errors.New("usage: easyweb3 api polymarket safe-mode status|enter --reason ... [--cancel-orders true|false]|resume")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "status":
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/system/safe-mode", nil)
+		case "enter":
+			fs := flag.NewFlagSet("easyweb3 api polymarket safe-mode enter", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			reason := fs.String("reason", "", "why safe mode is entered")
+			cancelOrders := fs.String("cancel-orders", "", "true|false; empty uses safe_mode.cancel_open_orders")
+			_ = fs.Parse(args[2:])
+			if strings.TrimSpace(*reason) == "" {
+				return errors.New("--reason required")
+			}
+			body := map[string]any{"reason": strings.TrimSpace(*reason)}
+			switch val := strings.ToLower(strings.TrimSpace(*cancelOrders)); val {
+			case "":
+			case "true", "false":
+				body["cancel_orders"] = val == "true"
+			default:
+				return errors.New("--cancel-orders must be true or false")
+			}
+			return polymarketDo(ctx, http.MethodPost, "/api/v2/system/safe-mode", body)
+		case "resume":
+			return polymarketDo(ctx, http.MethodPost, "/api/v2/system/safe-mode/resume", map[string]any{})
+		default:
+			return usage
+		}
+
 	case "setting-get":
 		if len(args) < 2 {
 			return errors.New("usage: easyweb3 api polymarket setting-get <key>")
@@ -568,8 +1350,1306 @@ func apiPolymarketCmd(ctx Context, args []string) error {
 		}
 		return polymarketDo(ctx, http.MethodPost, "/api/v2/system-settings/re-encrypt-sensitive"+q, map[string]any{})
 
-	default:
-		return fmt.Errorf("unknown polymarket operation: %s", args[0])
+	case "risk-var":
+		fs := flag.NewFlagSet("easyweb3 api polymarket risk-var", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		method := fs.String("method", "", "historical|monte_carlo (default: service config)")
+		confidence := fs.Float64("confidence", 0, "e.g. 0.95 (default: service config)")
+		_ = fs.Parse(args[1:])
+		q := ""
+		if strings.TrimSpace(*method) != "" {
+			q += "&method=" + urlQueryEscape(strings.TrimSpace(*method))
+		}
+		if *confidence > 0 {
+			q += fmt.Sprintf("&confidence=%g", *confidence)
+		}
+		if q != "" {
+			q = "?" + q[1:]
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/risk/var"+q, nil)
+
+	case "risk-limits":
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/risk/limits", nil)
+	case "risk-exposure-forecast":
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/risk/exposure-forecast", nil)
+
+	case "risk-decisions":
+		fs := flag.NewFlagSet("easyweb3 api polymarket risk-decisions", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		oppID := fs.Uint64("opportunity-id", 0, "decisions on this opportunity's strategy and event/market")
+		strategy := fs.String("strategy", "", "strategy name")
+		verdict := fs.String("verdict", "", "pass|reject")
+		rejectedBy := fs.String("rejected-by", "", "compliance|regime|stale_data|daily_loss|var|exposure|projected_exposure")
+		since := fs.String("since", "", "RFC3339")
+		until := fs.String("until", "", "RFC3339")
+		limit := fs.Int("limit", 50, "max items")
+		_ = fs.Parse(args[1:])
+		q := fmt.Sprintf("?limit=%d", *limit)
+		if *oppID > 0 {
+			q += fmt.Sprintf("&opportunity_id=%d", *oppID)
+		}
+		if v := strings.TrimSpace(*strategy); v != "" {
+			q += "&strategy_name=" + urlQueryEscape(v)
+		}
+		if v := strings.TrimSpace(*verdict); v != "" {
+			q += "&verdict=" + urlQueryEscape(v)
+		}
+		if v := strings.TrimSpace(*rejectedBy); v != "" {
+			q += "&rejected_by=" + urlQueryEscape(v)
+		}
+		if r := analyticsQuery(*since, *until, ""); r != "" {
+			q += "&" + r[1:]
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/risk/decisions"+q, nil)
+
+	case "regimes":
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/regimes", nil)
+
+	case "regime-history":
+		fs := flag.NewFlagSet("easyweb3 api polymarket regime-history", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		category := fs.String("category", "", "category, e.g. politics")
+		regime := fs.String("regime", "", "trending|choppy|news_driven|unknown")
+		since := fs.String("since", "", "RFC3339")
+		until := fs.String("until", "", "RFC3339")
+		limit := fs.Int("limit", 50, "max items")
+		_ = fs.Parse(args[1:])
+		q := fmt.Sprintf("?limit=%d", *limit)
+		if v := strings.TrimSpace(*category); v != "" {
+			q += "&category=" + urlQueryEscape(v)
+		}
+		if v := strings.TrimSpace(*regime); v != "" {
+			q += "&regime=" + urlQueryEscape(v)
+		}
+		if r := analyticsQuery(*since, *until, ""); r != "" {
+			q += "&" + r[1:]
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/regimes/history"+q, nil)
+
+	case "regime-run":
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/regimes/run", map[string]any{})
+
+	case "slo":
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/slo", nil)
+
+	case "slo-samples":
+		fs := flag.NewFlagSet("easyweb3 api polymarket slo-samples", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		slo := fs.String("slo", "", "catalog_freshness|book_freshness|settlement_latency")
+		since := fs.String("since", "", "RFC3339")
+		until := fs.String("until", "", "RFC3339")
+		limit := fs.Int("limit", 50, "max items")
+		_ = fs.Parse(args[1:])
+		q := fmt.Sprintf("?limit=%d", *limit)
+		if v := strings.TrimSpace(*slo); v != "" {
+			q += "&slo=" + urlQueryEscape(v)
+		}
+		if r := analyticsQuery(*since, *until, ""); r != "" {
+			q += "&" + r[1:]
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/slo/samples"+q, nil)
+
+	case "slo-evaluate":
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/slo/evaluate", map[string]any{})
+
+	case "outbox":
+		fs := flag.NewFlagSet("easyweb3 api polymarket outbox", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		status := fs.String("status", "", "pending|delivered|dead")
+		kind := fs.String("kind", "", "paas_log|notify|webhook")
+		limit := fs.Int("limit", 50, "max items")
+		_ = fs.Parse(args[1:])
+		q := fmt.Sprintf("?limit=%d", *limit)
+		if v := strings.TrimSpace(*status); v != "" {
+			q += "&status=" + urlQueryEscape(v)
+		}
+		if v := strings.TrimSpace(*kind); v != "" {
+			q += "&kind=" + urlQueryEscape(v)
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/outbox"+q, nil)
+
+	case "outbox-retry":
+		fs := flag.NewFlagSet("easyweb3 api polymarket outbox-retry", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		id := fs.Uint64("id", 0, "dead outbox message id")
+		_ = fs.Parse(args[1:])
+		if *id == 0 {
+			return errors.New("--id required")
+		}
+		return polymarketDo(ctx, http.MethodPost, fmt.Sprintf("/api/v2/outbox/%d/retry", *id), map[string]any{})
+
+	case "executor-intents":
+		fs := flag.NewFlagSet("easyweb3 api polymarket executor-intents", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		status := fs.String("status", "", "pending|resolved|failed|reconciled|orphaned")
+		action := fs.String("action", "", "submit|cancel|amend")
+		orderID := fs.Uint64("order-id", 0, "order id")
+		planID := fs.Uint64("plan-id", 0, "execution plan id")
+		limit := fs.Int("limit", 50, "max items")
+		_ = fs.Parse(args[1:])
+		q := fmt.Sprintf("?limit=%d", *limit)
+		if v := strings.TrimSpace(*status); v != "" {
+			q += "&status=" + urlQueryEscape(v)
+		}
+		if v := strings.TrimSpace(*action); v != "" {
+			q += "&action=" + urlQueryEscape(v)
+		}
+		if *orderID > 0 {
+			q += fmt.Sprintf("&order_id=%d", *orderID)
+		}
+		if *planID > 0 {
+			q += fmt.Sprintf("&plan_id=%d", *planID)
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/executor/intents"+q, nil)
+
+	case "executor-intents-reconcile":
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/executor/intents/reconcile", map[string]any{})
+
+	case "auto-executor-queue":
+		fs := flag.NewFlagSet("easyweb3 api polymarket auto-executor-queue", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		limit := fs.Int("limit", 100, "max items")
+		_ = fs.Parse(args[1:])
+		return polymarketDo(ctx, http.MethodGet, fmt.Sprintf("/api/v2/auto-executor/queue?limit=%d", *limit), nil)
+
+	case "risk-crypto-delta":
+		fs := flag.NewFlagSet("easyweb3 api polymarket risk-crypto-delta", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		hedge := fs.Bool("hedge", false, "include hedge suggestions (when enabled on the server)")
+		_ = fs.Parse(args[1:])
+		q := ""
+		if *hedge {
+			q = "?hedge=true"
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/risk/crypto-delta"+q, nil)
+
+	case "wallet-positions":
+		fs := flag.NewFlagSet("easyweb3 api polymarket wallet-positions", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		wallet := fs.String("wallet", "", "tracked wallet address")
+		_ = fs.Parse(args[1:])
+		q := ""
+		if strings.TrimSpace(*wallet) != "" {
+			q = "?wallet=" + urlQueryEscape(strings.TrimSpace(*wallet))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/signals/wallets/positions"+q, nil)
+
+	case "wallet-changes":
+		fs := flag.NewFlagSet("easyweb3 api polymarket wallet-changes", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		limit := fs.Int("limit", 50, "limit")
+		offset := fs.Int("offset", 0, "offset")
+		wallet := fs.String("wallet", "", "tracked wallet address")
+		marketID := fs.String("market-id", "", "market id")
+		since := fs.String("since", "", "RFC3339")
+		signalsOnly := fs.Bool("signals-only", false, "only changes that emitted smart_money_move")
+		_ = fs.Parse(args[1:])
+		q := fmt.Sprintf("?limit=%d&offset=%d", *limit, *offset)
+		if strings.TrimSpace(*wallet) != "" {
+			q += "&wallet=" + urlQueryEscape(strings.TrimSpace(*wallet))
+		}
+		if strings.TrimSpace(*marketID) != "" {
+			q += "&market_id=" + urlQueryEscape(strings.TrimSpace(*marketID))
+		}
+		if strings.TrimSpace(*since) != "" {
+			q += "&since=" + urlQueryEscape(strings.TrimSpace(*since))
+		}
+		if *signalsOnly {
+			q += "&signals_only=true"
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/signals/wallets/changes"+q, nil)
+
+	case "data-gaps":
+		fs := flag.NewFlagSet("easyweb3 api polymarket data-gaps", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		limit := fs.Int("limit", 50, "limit")
+		offset := fs.Int("offset", 0, "offset")
+		tokenID := fs.String("token-id", "", "token id")
+		status := fs.String("status", "", "open|backfilled|failed")
+		since := fs.String("since", "", "RFC3339")
+		_ = fs.Parse(args[1:])
+		q := fmt.Sprintf("?limit=%d&offset=%d", *limit, *offset)
+		if strings.TrimSpace(*tokenID) != "" {
+			q += "&token_id=" + urlQueryEscape(strings.TrimSpace(*tokenID))
+		}
+		if strings.TrimSpace(*status) != "" {
+			q += "&status=" + urlQueryEscape(strings.TrimSpace(*status))
+		}
+		if strings.TrimSpace(*since) != "" {
+			q += "&since=" + urlQueryEscape(strings.TrimSpace(*since))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/pipeline/gaps"+q, nil)
+
+	case "data-gaps-scan":
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/pipeline/gaps/scan", map[string]any{})
+
+	case "stream-subscriptions":
+		fs := flag.NewFlagSet("easyweb3 api polymarket stream-subscriptions", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		source := fs.String("source", "", "only tokens pinned by this source")
+		_ = fs.Parse(args[1:])
+		q := ""
+		if v := strings.TrimSpace(*source); v != "" {
+			q = "?source=" + urlQueryEscape(v)
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/pipeline/stream/subscriptions"+q, nil)
+
+	case "stream-pin", "stream-unpin":
+		fs := flag.NewFlagSet("easyweb3 api polymarket "+op, flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		source := fs.String("source", "", "pin owner, e.g. strategy:arb_sum or watchlist:btc")
+		tokens := fs.String("tokens", "", "comma-separated token ids (unpin: default all)")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*source) == "" {
+			return errors.New("--source required")
+		}
+		ids := []string{}
+		for _, id := range strings.Split(*tokens, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if op == "stream-unpin" {
+			q := ""
+			if len(ids) > 0 {
+				q = "?token_ids=" + urlQueryEscape(strings.Join(ids, ","))
+			}
+			return polymarketDo(ctx, http.MethodDelete, "/api/v2/pipeline/stream/pins/"+urlQueryEscape(strings.TrimSpace(*source))+q, nil)
+		}
+		if len(ids) == 0 {
+			return errors.New("--tokens required")
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/pipeline/stream/pins", map[string]any{
+			"source":    strings.TrimSpace(*source),
+			"token_ids": ids,
+		})
+
+	case "candles":
+		fs := flag.NewFlagSet("easyweb3 api polymarket candles", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		tokenID := fs.String("token-id", "", "token id")
+		since := fs.String("since", "", "RFC3339 (default: 6h ago)")
+		until := fs.String("until", "", "RFC3339 (default: now)")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*tokenID) == "" {
+			return errors.New("--token-id required")
+		}
+		q := "?token_id=" + urlQueryEscape(strings.TrimSpace(*tokenID))
+		if strings.TrimSpace(*since) != "" {
+			q += "&since=" + urlQueryEscape(strings.TrimSpace(*since))
+		}
+		if strings.TrimSpace(*until) != "" {
+			q += "&until=" + urlQueryEscape(strings.TrimSpace(*until))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/pipeline/candles"+q, nil)
+
+	case "market-trades":
+		if len(args) < 2 {
+			return errors.New("usage: easyweb3 api polymarket market-trades <market_id> [--token-id ...] [--side BUY|SELL] [--since ...] [--until ...]")
+		}
+		id := strings.TrimSpace(args[1])
+		if id == "" {
+			return errors.New("id required")
+		}
+		fs := flag.NewFlagSet("easyweb3 api polymarket market-trades", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		tokenID := fs.String("token-id", "", "token id (default: all market tokens)")
+		side := fs.String("side", "", "BUY|SELL")
+		since := fs.String("since", "", "RFC3339")
+		until := fs.String("until", "", "RFC3339")
+		limit := fs.Int("limit", 100, "limit")
+		offset := fs.Int("offset", 0, "offset")
+		_ = fs.Parse(args[2:])
+		q := fmt.Sprintf("?limit=%d&offset=%d", *limit, *offset)
+		if strings.TrimSpace(*tokenID) != "" {
+			q += "&token_id=" + urlQueryEscape(strings.TrimSpace(*tokenID))
+		}
+		if strings.TrimSpace(*side) != "" {
+			q += "&side=" + urlQueryEscape(strings.TrimSpace(*side))
+		}
+		if strings.TrimSpace(*since) != "" {
+			q += "&since=" + urlQueryEscape(strings.TrimSpace(*since))
+		}
+		if strings.TrimSpace(*until) != "" {
+			q += "&until=" + urlQueryEscape(strings.TrimSpace(*until))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/markets/"+id+"/trades"+q, nil)
+
+	case "volume-profile":
+		if len(args) < 2 {
+			return errors.New("usage: easyweb3 api polymarket volume-profile <market_id> [--token-id ...] [--hours 24] [--bucket 0.01]")
+		}
+		id := strings.TrimSpace(args[1])
+		if id == "" {
+			return errors.New("id required")
+		}
+		fs := flag.NewFlagSet("easyweb3 api polymarket volume-profile", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		tokenID := fs.String("token-id", "", "token id (default: all market tokens)")
+		hours := fs.Int("hours", 24, "lookback hours")
+		bucket := fs.Float64("bucket", 0.01, "price level width")
+		_ = fs.Parse(args[2:])
+		q := fmt.Sprintf("?hours=%d&bucket=%g", *hours, *bucket)
+		if strings.TrimSpace(*tokenID) != "" {
+			q += "&token_id=" + urlQueryEscape(strings.TrimSpace(*tokenID))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/markets/"+id+"/volume-profile"+q, nil)
+
+	case "market-changes":
+		// Optional leading market id; without it, changes across all markets.
+		path := "/api/v2/catalog/market-changes"
+		rest := args[1:]
+		if len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+			id := strings.TrimSpace(rest[0])
+			if id == "" {
+				return errors.New("id required")
+			}
+			path = "/api/v2/catalog/markets/" + id + "/changes"
+			rest = rest[1:]
+		}
+		fs := flag.NewFlagSet("easyweb3 api polymarket market-changes", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		field := fs.String("field", "", "question|description|resolution_source|end_date")
+		heldOnly := fs.Bool("held-only", false, "only markets with an open position")
+		since := fs.String("since", "", "RFC3339")
+		limit := fs.Int("limit", 50, "limit")
+		offset := fs.Int("offset", 0, "offset")
+		_ = fs.Parse(rest)
+		q := fmt.Sprintf("?limit=%d&offset=%d", *limit, *offset)
+		if strings.TrimSpace(*field) != "" {
+			q += "&field=" + urlQueryEscape(strings.TrimSpace(*field))
+		}
+		if *heldOnly {
+			q += "&held_only=true"
+		}
+		if strings.TrimSpace(*since) != "" {
+			q += "&since=" + urlQueryEscape(strings.TrimSpace(*since))
+		}
+		return polymarketDo(ctx, http.MethodGet, path+q, nil)
+
+	case "catalog-changes":
+		fs := flag.NewFlagSet("easyweb3 api polymarket catalog-changes", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		since := fs.String("since", "", "RFC3339 (first call)")
+		after := fs.Uint64("after", 0, "cursor from the previous call's meta.cursor")
+		entity := fs.String("entity", "", "event|market|label")
+		limit := fs.Int("limit", 500, "limit")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*since) == "" && *after == 0 {
+			return errors.New("--since or --after required")
+		}
+		q := fmt.Sprintf("?limit=%d", *limit)
+		if strings.TrimSpace(*since) != "" {
+			q += "&since=" + urlQueryEscape(strings.TrimSpace(*since))
+		}
+		if *after > 0 {
+			q += fmt.Sprintf("&after=%d", *after)
+		}
+		if strings.TrimSpace(*entity) != "" {
+			q += "&entity=" + urlQueryEscape(strings.TrimSpace(*entity))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/catalog/changes"+q, nil)
+
+	case "catalog-books":
+		fs := flag.NewFlagSet("easyweb3 api polymarket catalog-books", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		tokenIDs := fs.String("token-ids", "", "comma separated token ids")
+		marketIDs := fs.String("market-ids", "", "comma separated market ids (all their tokens)")
+		consistentOnly := fs.Bool("consistent-only", false, "drop books not written against the current catalog epoch")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*tokenIDs) == "" && strings.TrimSpace(*marketIDs) == "" {
+			return errors.New("--token-ids or --market-ids required")
+		}
+		q := fmt.Sprintf("?consistent_only=%t", *consistentOnly)
+		if strings.TrimSpace(*tokenIDs) != "" {
+			q += "&token_ids=" + urlQueryEscape(strings.TrimSpace(*tokenIDs))
+		}
+		if strings.TrimSpace(*marketIDs) != "" {
+			q += "&market_ids=" + urlQueryEscape(strings.TrimSpace(*marketIDs))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/catalog/books"+q, nil)
+
+	case "catalog-onchain-refresh":
+		fs := flag.NewFlagSet("easyweb3 api polymarket catalog-onchain-refresh", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		marketIDs := fs.String("market-ids", "", "comma separated market ids")
+		_ = fs.Parse(args[1:])
+		ids := []string{}
+		for _, id := range strings.Split(*marketIDs, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return errors.New("--market-ids required")
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/catalog/onchain/refresh", map[string]any{"market_ids": ids})
+
+	case "compliance":
+		usage := errors.New("usage: easyweb3 api polymarket compliance rules|check <market_id,...>|overrides [--market-id ...] [--active]|override <market_id> --reason ... [--ttl-hours N]|revoke <override_id> --reason ...")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "rules":
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/compliance/rules", nil)
+		case "check":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/compliance/check?market_ids="+urlQueryEscape(strings.TrimSpace(args[2])), nil)
+		case "overrides":
+			fs := flag.NewFlagSet("easyweb3 api polymarket compliance overrides", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			marketID := fs.String("market-id", "", "market id")
+			active := fs.Bool("active", false, "only overrides in force")
+			limit := fs.Int("limit", 50, "limit")
+			offset := fs.Int("offset", 0, "offset")
+			_ = fs.Parse(args[2:])
+			q := fmt.Sprintf("?limit=%d&offset=%d", *limit, *offset)
+			if strings.TrimSpace(*marketID) != "" {
+				q += "&market_id=" + urlQueryEscape(strings.TrimSpace(*marketID))
+			}
+			if *active {
+				q += "&active_only=true"
+			}
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/compliance/overrides"+q, nil)
+		case "override", "revoke":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			id := strings.TrimSpace(args[2])
+			fs := flag.NewFlagSet("easyweb3 api polymarket compliance "+args[1], flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			reason := fs.String("reason", "", "why (required, recorded in the audit trail)")
+			ttlHours := fs.Float64("ttl-hours", 0, "override lifetime in hours (default and cap: server override_ttl)")
+			_ = fs.Parse(args[3:])
+			if strings.TrimSpace(*reason) == "" {
+				return errors.New("--reason required")
+			}
+			if args[1] == "revoke" {
+				return polymarketDo(ctx, http.MethodPost, "/api/v2/compliance/overrides/"+id+"/revoke", map[string]any{"reason": *reason})
+			}
+			body := map[string]any{"reason": *reason}
+			if *ttlHours > 0 {
+				body["ttl_hours"] = *ttlHours
+			}
+			return polymarketDo(ctx, http.MethodPost, "/api/v2/compliance/markets/"+id+"/override", body)
+		default:
+			return usage
+		}
+
+	case "catalog-webhooks":
+		usage := errors.New("usage: easyweb3 api polymarket catalog-webhooks list|get <id>|create --url ... [filters]|update <id> [filters]|delete <id>|test <id>")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "list":
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/catalog/webhooks", nil)
+		case "get", "delete", "test":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			path := "/api/v2/catalog/webhooks/" + strings.TrimSpace(args[2])
+			switch args[1] {
+			case "delete":
+				return polymarketDo(ctx, http.MethodDelete, path, nil)
+			case "test":
+				return polymarketDo(ctx, http.MethodPost, path+"/test", nil)
+			}
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		case "create", "update":
+			rest := args[2:]
+			id := ""
+			if args[1] == "update" {
+				if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+					return usage
+				}
+				id = strings.TrimSpace(args[2])
+				rest = args[3:]
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket catalog-webhooks "+args[1], flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			name := fs.String("name", "", "display name")
+			endpoint := fs.String("url", "", "delivery URL (http or https)")
+			secret := fs.String("secret", "", "signing secret (create: generated when empty)")
+			entities := fs.String("entities", "", "comma-separated: event,market (default: both)")
+			tags := fs.String("tags", "", "comma-separated tag slugs or labels")
+			keywords := fs.String("keywords", "", "comma-separated title keywords")
+			minLiquidity := fs.Float64("min-liquidity", -1, "liquidity floor in USD")
+			enabled := fs.String("enabled", "", "true|false")
+			_ = fs.Parse(rest)
+			body := map[string]any{}
+			set := map[string]bool{}
+			fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+			if set["name"] {
+				body["name"] = *name
+			}
+			if set["url"] {
+				body["url"] = strings.TrimSpace(*endpoint)
+			}
+			if set["secret"] {
+				body["secret"] = *secret
+			}
+			splitList := func(raw string) []string {
+				items := []string{}
+				for _, item := range strings.Split(raw, ",") {
+					if v := strings.TrimSpace(item); v != "" {
+						items = append(items, v)
+					}
+				}
+				return items
+			}
+			if set["entities"] {
+				body["entity_types"] = splitList(*entities)
+			}
+			if set["tags"] {
+				body["tags"] = splitList(*tags)
+			}
+			if set["keywords"] {
+				body["keywords"] = splitList(*keywords)
+			}
+			if *minLiquidity >= 0 {
+				body["min_liquidity_usd"] = *minLiquidity
+			}
+			if set["enabled"] {
+				switch strings.ToLower(strings.TrimSpace(*enabled)) {
+				case "true":
+					body["enabled"] = true
+				case "false":
+					body["enabled"] = false
+				default:
+					return errors.New("--enabled must be true or false")
+				}
+			}
+			if args[1] == "create" {
+				if strings.TrimSpace(*endpoint) == "" {
+					return errors.New("--url required")
+				}
+				return polymarketDo(ctx, http.MethodPost, "/api/v2/catalog/webhooks", body)
+			}
+			return polymarketDo(ctx, http.MethodPut, "/api/v2/catalog/webhooks/"+id, body)
+		default:
+			return usage
+		}
+
+	case "webhooks":
+		usage := errors.New("usage: easyweb3 api polymarket webhooks list|get <id>|create --url ... [filters]|update <id> [filters]|delete <id>|test <id>|deliveries <id> [--status ...]")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "list":
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/webhooks", nil)
+		case "get", "delete", "test":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			path := "/api/v2/webhooks/" + strings.TrimSpace(args[2])
+			switch args[1] {
+			case "delete":
+				return polymarketDo(ctx, http.MethodDelete, path, nil)
+			case "test":
+				return polymarketDo(ctx, http.MethodPost, path+"/test", nil)
+			}
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		case "deliveries":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket webhooks deliveries", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			status := fs.String("status", "", "pending|delivered|failed")
+			eventType := fs.String("event", "", "order.filled|plan.executed|position.closed")
+			limit := fs.Int("limit", 50, "limit")
+			offset := fs.Int("offset", 0, "offset")
+			_ = fs.Parse(args[3:])
+			path := fmt.Sprintf("/api/v2/webhooks/%s/deliveries?limit=%d&offset=%d", strings.TrimSpace(args[2]), *limit, *offset)
+			if strings.TrimSpace(*status) != "" {
+				path += "&status=" + urlQueryEscape(strings.TrimSpace(*status))
+			}
+			if strings.TrimSpace(*eventType) != "" {
+				path += "&event_type=" + urlQueryEscape(strings.TrimSpace(*eventType))
+			}
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		case "create", "update":
+			rest := args[2:]
+			id := ""
+			if args[1] == "update" {
+				if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+					return usage
+				}
+				id = strings.TrimSpace(args[2])
+				rest = args[3:]
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket webhooks "+args[1], flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			name := fs.String("name", "", "display name")
+			endpoint := fs.String("url", "", "delivery URL (http or https)")
+			secret := fs.String("secret", "", "signing secret (create: generated when empty)")
+			eventTypes := fs.String("events", "", "comma-separated: order.filled,plan.executed,position.closed (default: all)")
+			strategies := fs.String("strategies", "", "comma-separated strategy names (default: all)")
+			enabled := fs.String("enabled", "", "true|false")
+			_ = fs.Parse(rest)
+			body := map[string]any{}
+			set := map[string]bool{}
+			fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+			if set["name"] {
+				body["name"] = *name
+			}
+			if set["url"] {
+				body["url"] = strings.TrimSpace(*endpoint)
+			}
+			if set["secret"] {
+				body["secret"] = *secret
+			}
+			splitList := func(raw string) []string {
+				items := []string{}
+				for _, item := range strings.Split(raw, ",") {
+					if v := strings.TrimSpace(item); v != "" {
+						items = append(items, v)
+					}
+				}
+				return items
+			}
+			if set["events"] {
+				body["event_types"] = splitList(*eventTypes)
+			}
+			if set["strategies"] {
+				body["strategies"] = splitList(*strategies)
+			}
+			if set["enabled"] {
+				switch strings.ToLower(strings.TrimSpace(*enabled)) {
+				case "true":
+					body["enabled"] = true
+				case "false":
+					body["enabled"] = false
+				default:
+					return errors.New("--enabled must be true or false")
+				}
+			}
+			if args[1] == "create" {
+				if strings.TrimSpace(*endpoint) == "" {
+					return errors.New("--url required")
+				}
+				return polymarketDo(ctx, http.MethodPost, "/api/v2/webhooks", body)
+			}
+			return polymarketDo(ctx, http.MethodPut, "/api/v2/webhooks/"+id, body)
+		default:
+			return usage
+		}
+
+	case "campaigns":
+		usage := errors.New("usage: easyweb3 api polymarket campaigns list [--status ...]|get <id>|create --name ... [limits]|update <id> [limits]|delete <id>|plans <id>|assign-plans <id> <ids> [--remove]|assign-opps <id> <ids> [--remove]|halt <id>|resume <id>")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "list":
+			fs := flag.NewFlagSet("easyweb3 api polymarket campaigns list", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			status := fs.String("status", "", "active|halted|ended")
+			limit := fs.Int("limit", 50, "limit")
+			offset := fs.Int("offset", 0, "offset")
+			_ = fs.Parse(args[2:])
+			path := fmt.Sprintf("/api/v2/campaigns?limit=%d&offset=%d", *limit, *offset)
+			if strings.TrimSpace(*status) != "" {
+				path += "&status=" + urlQueryEscape(strings.TrimSpace(*status))
+			}
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		case "get", "delete", "plans", "halt", "resume":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			path := "/api/v2/campaigns/" + strings.TrimSpace(args[2])
+			switch args[1] {
+			case "delete":
+				return polymarketDo(ctx, http.MethodDelete, path, nil)
+			case "plans":
+				return polymarketDo(ctx, http.MethodGet, path+"/plans", nil)
+			case "halt", "resume":
+				return polymarketDo(ctx, http.MethodPost, path+"/"+args[1], nil)
+			}
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		case "assign-plans", "assign-opps":
+			if len(args) < 4 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket campaigns "+args[1], flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			remove := fs.Bool("remove", false, "take the rows out of the campaign")
+			_ = fs.Parse(args[4:])
+			ids := []uint64{}
+			for _, item := range strings.Split(args[3], ",") {
+				item = strings.TrimSpace(item)
+				if item == "" {
+					continue
+				}
+				var id uint64
+				if _, err := fmt.Sscan(item, &id); err != nil {
+					return fmt.Errorf("invalid id %q", item)
+				}
+				ids = append(ids, id)
+			}
+			path := "/api/v2/campaigns/" + strings.TrimSpace(args[2]) + "/plans"
+			if args[1] == "assign-opps" {
+				path = "/api/v2/campaigns/" + strings.TrimSpace(args[2]) + "/opportunities"
+			}
+			return polymarketDo(ctx, http.MethodPost, path, map[string]any{"ids": ids, "remove": *remove})
+		case "create", "update":
+			rest := args[2:]
+			id := ""
+			if args[1] == "update" {
+				if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+					return usage
+				}
+				id = strings.TrimSpace(args[2])
+				rest = args[3:]
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket campaigns "+args[1], flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			name := fs.String("name", "", "campaign name")
+			description := fs.String("description", "", "description")
+			budget := fs.Float64("budget", 0, "aggregate budget in USD (0 = unlimited)")
+			maxLoss := fs.Float64("max-loss", 0, "realized loss limit in USD (0 = unlimited)")
+			startsAt := fs.String("starts-at", "", "RFC3339 start time")
+			endsAt := fs.String("ends-at", "", "RFC3339 end time")
+			_ = fs.Parse(rest)
+			body := map[string]any{}
+			set := map[string]bool{}
+			fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+			if set["name"] {
+				body["name"] = *name
+			}
+			if set["description"] {
+				body["description"] = *description
+			}
+			if set["budget"] {
+				body["budget_usd"] = *budget
+			}
+			if set["max-loss"] {
+				body["max_loss_usd"] = *maxLoss
+			}
+			if set["starts-at"] {
+				body["starts_at"] = strings.TrimSpace(*startsAt)
+			}
+			if set["ends-at"] {
+				body["ends_at"] = strings.TrimSpace(*endsAt)
+			}
+			if args[1] == "create" {
+				if strings.TrimSpace(*name) == "" {
+					return errors.New("--name required")
+				}
+				return polymarketDo(ctx, http.MethodPost, "/api/v2/campaigns", body)
+			}
+			return polymarketDo(ctx, http.MethodPut, "/api/v2/campaigns/"+id, body)
+		default:
+			return usage
+		}
+
+	case "tickets":
+		usage := errors.New("usage: easyweb3 api polymarket tickets list [--state ...]|get <id>|create --opportunity-id <id> [--size ...] [--notes ...]|update <id> [--size ...] [--notes ...]|review <id>|submit <id>|cancel <id>")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "list":
+			fs := flag.NewFlagSet("easyweb3 api polymarket tickets list", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			state := fs.String("state", "", "draft|reviewed|submitted|cancelled")
+			oppID := fs.Uint64("opportunity-id", 0, "opportunity id")
+			limit := fs.Int("limit", 50, "limit")
+			offset := fs.Int("offset", 0, "offset")
+			_ = fs.Parse(args[2:])
+			path := fmt.Sprintf("/api/v2/tickets?limit=%d&offset=%d", *limit, *offset)
+			if strings.TrimSpace(*state) != "" {
+				path += "&state=" + urlQueryEscape(strings.TrimSpace(*state))
+			}
+			if *oppID > 0 {
+				path += fmt.Sprintf("&opportunity_id=%d", *oppID)
+			}
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		case "get", "review", "submit", "cancel":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			path := "/api/v2/tickets/" + strings.TrimSpace(args[2])
+			if args[1] == "get" {
+				return polymarketDo(ctx, http.MethodGet, path, nil)
+			}
+			return polymarketDo(ctx, http.MethodPost, path+"/"+args[1], nil)
+		case "create", "update":
+			rest := args[2:]
+			id := ""
+			if args[1] == "update" {
+				if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+					return usage
+				}
+				id = strings.TrimSpace(args[2])
+				rest = args[3:]
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket tickets "+args[1], flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			oppID := fs.Uint64("opportunity-id", 0, "opportunity id")
+			size := fs.Float64("size", 0, "size in USD (default: suggested sizing)")
+			notes := fs.String("notes", "", "notes")
+			_ = fs.Parse(rest)
+			body := map[string]any{}
+			set := map[string]bool{}
+			fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+			if set["size"] {
+				body["size_usd"] = *size
+			}
+			if set["notes"] {
+				body["notes"] = *notes
+			}
+			if args[1] == "create" {
+				if *oppID == 0 {
+					return errors.New("--opportunity-id required")
+				}
+				body["opportunity_id"] = *oppID
+				return polymarketDo(ctx, http.MethodPost, "/api/v2/tickets", body)
+			}
+			return polymarketDo(ctx, http.MethodPut, "/api/v2/tickets/"+id, body)
+		default:
+			return usage
+		}
+
+	case "snapshots":
+		usage := errors.New("usage: easyweb3 api polymarket snapshots list [--name ...]|get <id>|create --name <name> [--market-ids a,b] [--event-id ...] [--label ...] [--active-only]")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "list":
+			fs := flag.NewFlagSet("easyweb3 api polymarket snapshots list", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			name := fs.String("name", "", "name contains")
+			limit := fs.Int("limit", 50, "limit")
+			offset := fs.Int("offset", 0, "offset")
+			_ = fs.Parse(args[2:])
+			path := fmt.Sprintf("/api/v2/research/snapshots?limit=%d&offset=%d", *limit, *offset)
+			if strings.TrimSpace(*name) != "" {
+				path += "&name=" + urlQueryEscape(strings.TrimSpace(*name))
+			}
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		case "get":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/research/snapshots/"+strings.TrimSpace(args[2]), nil)
+		case "create":
+			fs := flag.NewFlagSet("easyweb3 api polymarket snapshots create", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			name := fs.String("name", "", "snapshot name")
+			marketIDs := fs.String("market-ids", "", "comma-separated market ids")
+			eventID := fs.String("event-id", "", "include the markets of this event")
+			label := fs.String("label", "", "include the markets with this label")
+			activeOnly := fs.Bool("active-only", false, "drop closed or inactive markets")
+			_ = fs.Parse(args[2:])
+			if strings.TrimSpace(*name) == "" {
+				return errors.New("--name required")
+			}
+			ids := []string{}
+			for _, id := range strings.Split(*marketIDs, ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					ids = append(ids, id)
+				}
+			}
+			return polymarketDo(ctx, http.MethodPost, "/api/v2/research/snapshots", map[string]any{
+				"name":        strings.TrimSpace(*name),
+				"market_ids":  ids,
+				"event_id":    strings.TrimSpace(*eventID),
+				"label":       strings.TrimSpace(*label),
+				"active_only": *activeOnly,
+			})
+		default:
+			return usage
+		}
+
+	case "exports":
+		usage := errors.New("usage: easyweb3 api polymarket exports list|get <id>|delete <id>|run <id>|create --name <name> [--dataset trades|daily] [--strategy ...] [--since RFC3339] [--until RFC3339] [--normalize-sizes] [--min-market-volume N] [--watermark]")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "list":
+			fs := flag.NewFlagSet("easyweb3 api polymarket exports list", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			limit := fs.Int("limit", 50, "limit")
+			offset := fs.Int("offset", 0, "offset")
+			_ = fs.Parse(args[2:])
+			return polymarketDo(ctx, http.MethodGet, fmt.Sprintf("/api/v2/analytics/exports?limit=%d&offset=%d", *limit, *offset), nil)
+		case "get", "delete", "run":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			path := "/api/v2/analytics/exports/" + strings.TrimSpace(args[2])
+			switch args[1] {
+			case "delete":
+				return polymarketDo(ctx, http.MethodDelete, path, nil)
+			case "run":
+				return polymarketDo(ctx, http.MethodPost, path+"/run", nil)
+			}
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		case "create":
+			fs := flag.NewFlagSet("easyweb3 api polymarket exports create", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			name := fs.String("name", "", "export name")
+			dataset := fs.String("dataset", "trades", "trades|daily")
+			strategy := fs.String("strategy", "", "only this strategy")
+			since := fs.String("since", "", "settled at or after (RFC3339)")
+			until := fs.String("until", "", "settled at or before (RFC3339)")
+			normalize := fs.Bool("normalize-sizes", false, "drop dollar amounts, keep percent returns")
+			minVolume := fs.Float64("min-market-volume", 0, "strip market ids of markets below this volume (USD)")
+			watermark := fs.Bool("watermark", false, "stamp every run with a traceable watermark")
+			_ = fs.Parse(args[2:])
+			if strings.TrimSpace(*name) == "" {
+				return errors.New("--name required")
+			}
+			body := map[string]any{
+				"name":    strings.TrimSpace(*name),
+				"dataset": strings.TrimSpace(*dataset),
+				"masking": map[string]any{
+					"normalize_sizes":       *normalize,
+					"min_market_volume_usd": *minVolume,
+					"watermark":             *watermark,
+				},
+			}
+			if v := strings.TrimSpace(*strategy); v != "" {
+				body["strategy_name"] = v
+			}
+			if v := strings.TrimSpace(*since); v != "" {
+				body["since"] = v
+			}
+			if v := strings.TrimSpace(*until); v != "" {
+				body["until"] = v
+			}
+			return polymarketDo(ctx, http.MethodPost, "/api/v2/analytics/exports", body)
+		default:
+			return usage
+		}
+
+	case "retention":
+		usage := errors.New("usage: easyweb3 api polymarket retention get|set <table> [--window 168h] [--enabled true|false] [--run-hour N]|purge [--tables a,b] [--execute]")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "get":
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/retention", nil)
+		case "set":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket retention set", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			window := fs.String("window", "", "retention window, e.g. 168h; 0 keeps rows forever")
+			enabled := fs.String("enabled", "", "true|false")
+			runHour := fs.Int("run-hour", -2, "UTC hour of the nightly purge; -1 uses the default")
+			_ = fs.Parse(args[3:])
+			body := map[string]any{}
+			if strings.TrimSpace(*window) != "" {
+				body["window"] = strings.TrimSpace(*window)
+			}
+			switch strings.TrimSpace(*enabled) {
+			case "":
+			case "true":
+				body["enabled"] = true
+			case "false":
+				body["enabled"] = false
+			default:
+				return errors.New("--enabled must be true or false")
+			}
+			if *runHour > -2 {
+				body["run_hour"] = *runHour
+			}
+			if len(body) == 0 {
+				return errors.New("--window, --enabled or --run-hour required")
+			}
+			return polymarketDo(ctx, http.MethodPut, "/api/v2/retention/policies/"+urlQueryEscape(strings.TrimSpace(args[2])), body)
+		case "purge":
+			fs := flag.NewFlagSet("easyweb3 api polymarket retention purge", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			tables := fs.String("tables", "", "comma-separated tables (default: all enabled policies)")
+			execute := fs.Bool("execute", false, "delete rows; without it only a dry-run preview is returned")
+			_ = fs.Parse(args[2:])
+			path := fmt.Sprintf("/api/v2/retention/purge?dry_run=%t", !*execute)
+			if strings.TrimSpace(*tables) != "" {
+				path += "&tables=" + urlQueryEscape(strings.TrimSpace(*tables))
+			}
+			return polymarketDo(ctx, http.MethodPost, path, nil)
+		default:
+			return usage
+		}
+
+	case "cash-ops":
+		usage := errors.New("usage: easyweb3 api polymarket cash-ops list [--status ...] [--kind ...]|get <id>|balance|create --kind deposit|withdrawal --amount-usd N [--wallet ...] [--tx-hash ...] [--notes ...]|approve|reject <id> [--note ...]|complete <id> --tx-hash ...|cancel|reconcile <id>")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "list":
+			fs := flag.NewFlagSet("easyweb3 api polymarket cash-ops list", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			status := fs.String("status", "", "requested|approved|rejected|cancelled|completed|reconciled")
+			kind := fs.String("kind", "", "deposit|withdrawal")
+			limit := fs.Int("limit", 50, "limit")
+			offset := fs.Int("offset", 0, "offset")
+			_ = fs.Parse(args[2:])
+			path := fmt.Sprintf("/api/v2/cash-ops?limit=%d&offset=%d", *limit, *offset)
+			if strings.TrimSpace(*status) != "" {
+				path += "&status=" + urlQueryEscape(strings.TrimSpace(*status))
+			}
+			if strings.TrimSpace(*kind) != "" {
+				path += "&kind=" + urlQueryEscape(strings.TrimSpace(*kind))
+			}
+			return polymarketDo(ctx, http.MethodGet, path, nil)
+		case "balance":
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/cash-ops/balance", nil)
+		case "get":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/cash-ops/"+strings.TrimSpace(args[2]), nil)
+		case "create":
+			fs := flag.NewFlagSet("easyweb3 api polymarket cash-ops create", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			kind := fs.String("kind", "", "deposit|withdrawal")
+			amount := fs.Float64("amount-usd", 0, "amount in USD")
+			wallet := fs.String("wallet", "", "wallet address (defaults to the configured trading wallet)")
+			txHash := fs.String("tx-hash", "", "transfer tx hash, if already known")
+			notes := fs.String("notes", "", "notes")
+			_ = fs.Parse(args[2:])
+			if strings.TrimSpace(*kind) == "" || *amount <= 0 {
+				return errors.New("--kind and --amount-usd required")
+			}
+			return polymarketDo(ctx, http.MethodPost, "/api/v2/cash-ops", map[string]any{
+				"kind":       strings.TrimSpace(*kind),
+				"amount_usd": *amount,
+				"wallet":     strings.TrimSpace(*wallet),
+				"tx_hash":    strings.TrimSpace(*txHash),
+				"notes":      strings.TrimSpace(*notes),
+			})
+		case "approve", "reject", "complete", "cancel", "reconcile":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket cash-ops "+args[1], flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			note := fs.String("note", "", "review note")
+			txHash := fs.String("tx-hash", "", "transfer tx hash")
+			_ = fs.Parse(args[3:])
+			var body any
+			if strings.TrimSpace(*note) != "" || strings.TrimSpace(*txHash) != "" {
+				body = map[string]any{"note": strings.TrimSpace(*note), "tx_hash": strings.TrimSpace(*txHash)}
+			}
+			return polymarketDo(ctx, http.MethodPost, "/api/v2/cash-ops/"+strings.TrimSpace(args[2])+"/"+args[1], body)
+		default:
+			return usage
+		}
+
+	case "conditions":
+		usage := errors.New("usage: easyweb3 api polymarket conditions variables|validate <expression>|evaluate <expression> [--opportunity-id N] [--vars JSON]")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "variables":
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/conditions/variables", nil)
+		case "validate", "evaluate":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			body := map[string]any{"expression": args[2]}
+			if args[1] == "validate" {
+				return polymarketDo(ctx, http.MethodPost, "/api/v2/conditions/validate", body)
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket conditions evaluate", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			oppID := fs.Uint64("opportunity-id", 0, "evaluate against this opportunity")
+			vars := fs.String("vars", "", "JSON object of variables")
+			_ = fs.Parse(args[3:])
+			if *oppID > 0 {
+				body["opportunity_id"] = *oppID
+			}
+			if strings.TrimSpace(*vars) != "" {
+				var m map[string]any
+				if err := json.Unmarshal([]byte(*vars), &m); err != nil {
+					return fmt.Errorf("--vars must be a JSON object: %w", err)
+				}
+				body["variables"] = m
+			}
+			return polymarketDo(ctx, http.MethodPost, "/api/v2/conditions/evaluate", body)
+		default:
+			return usage
+		}
+
+	case "rewards-report":
+		fs := flag.NewFlagSet("easyweb3 api polymarket rewards-report", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		since := fs.String("since", "", "first epoch (YYYY-MM-DD)")
+		until := fs.String("until", "", "last epoch (YYYY-MM-DD)")
+		marketID := fs.String("market-id", "", "market id")
+		limit := fs.Int("limit", 100, "limit")
+		offset := fs.Int("offset", 0, "offset")
+		_ = fs.Parse(args[1:])
+		path := fmt.Sprintf("/api/v2/rewards/report?limit=%d&offset=%d", *limit, *offset)
+		if strings.TrimSpace(*since) != "" {
+			path += "&since=" + urlQueryEscape(strings.TrimSpace(*since))
+		}
+		if strings.TrimSpace(*until) != "" {
+			path += "&until=" + urlQueryEscape(strings.TrimSpace(*until))
+		}
+		if strings.TrimSpace(*marketID) != "" {
+			path += "&market_id=" + urlQueryEscape(strings.TrimSpace(*marketID))
+		}
+		return polymarketDo(ctx, http.MethodGet, path, nil)
+
+	case "rewards-received":
+		fs := flag.NewFlagSet("easyweb3 api polymarket rewards-received", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		epoch := fs.String("epoch", "", "epoch (YYYY-MM-DD)")
+		marketID := fs.String("market-id", "", "market id")
+		amount := fs.Float64("amount", -1, "reward received in USD")
+		_ = fs.Parse(args[1:])
+		if strings.TrimSpace(*epoch) == "" || strings.TrimSpace(*marketID) == "" || *amount < 0 {
+			return errors.New("usage: easyweb3 api polymarket rewards-received --epoch YYYY-MM-DD --market-id ... --amount <usd>")
+		}
+		body := map[string]any{
+			"epoch":      strings.TrimSpace(*epoch),
+			"market_id":  strings.TrimSpace(*marketID),
+			"amount_usd": *amount,
+		}
+		return polymarketDo(ctx, http.MethodPut, "/api/v2/rewards/received", body)
+
+	case "bench":
+		fs := flag.NewFlagSet("easyweb3 api polymarket bench", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		last := fs.Bool("last", false, "show the last run instead of starting one")
+		scenarios := fs.String("scenarios", "", "comma-separated scenarios (default: all)")
+		iterations := fs.Int("iterations", 0, "operations per scenario (default: server config)")
+		concurrency := fs.Int("concurrency", 0, "workers per scenario")
+		threshold := fs.Float64("threshold", 0, "allowed p95 growth in percent vs the server baseline")
+		_ = fs.Parse(args[1:])
+		if *last {
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/system/bench", nil)
+		}
+		body := map[string]any{}
+		if strings.TrimSpace(*scenarios) != "" {
+			body["scenarios"] = strings.Split(strings.TrimSpace(*scenarios), ",")
+		}
+		if *iterations > 0 {
+			body["iterations"] = *iterations
+		}
+		if *concurrency > 0 {
+			body["concurrency"] = *concurrency
+		}
+		if *threshold > 0 {
+			body["threshold_pct"] = *threshold
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/system/bench", body)
+
+	case "faults":
+		usage := errors.New("usage: easyweb3 api polymarket faults list|set <gamma|clob|db|ws> [--error-rate ...] [--latency-ms ...] [--status ...] [--remaining ...] [--duration ...]|clear [target]")
+		if len(args) < 2 {
+			return usage
+		}
+		switch args[1] {
+		case "list":
+			return polymarketDo(ctx, http.MethodGet, "/api/v2/system/faults", nil)
+		case "clear":
+			if len(args) > 2 && strings.TrimSpace(args[2]) != "" {
+				return polymarketDo(ctx, http.MethodDelete, "/api/v2/system/faults/"+strings.TrimSpace(args[2]), nil)
+			}
+			return polymarketDo(ctx, http.MethodDelete, "/api/v2/system/faults", nil)
+		case "set":
+			if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+				return usage
+			}
+			fs := flag.NewFlagSet("easyweb3 api polymarket faults set", flag.ContinueOnError)
+			fs.SetOutput(os.Stderr)
+			errorRate := fs.Float64("error-rate", 0, "probability 0-1 that a call fails")
+			latencyMS := fs.Int("latency-ms", 0, "delay added to every call")
+			status := fs.Int("status", 0, "HTTP status for failed gamma/clob calls (default: transport error)")
+			remaining := fs.Int("remaining", 0, "stop after this many failures (0 = until expiry)")
+			duration := fs.String("duration", "", "how long the fault lasts, e.g. 5m (default: server maximum)")
+			_ = fs.Parse(args[3:])
+			body := map[string]any{
+				"error_rate":  *errorRate,
+				"latency_ms":  *latencyMS,
+				"status_code": *status,
+				"remaining":   *remaining,
+			}
+			if strings.TrimSpace(*duration) != "" {
+				body["duration"] = strings.TrimSpace(*duration)
+			}
+			return polymarketDo(ctx, http.MethodPut, "/api/v2/system/faults/"+strings.TrimSpace(args[2]), body)
+		default:
+			return usage
+		}
+
+	case "signal-quality":
+		fs := flag.NewFlagSet("easyweb3 api polymarket signal-quality", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		since := fs.String("since", "", "RFC3339 (default: cached rolling window)")
+		_ = fs.Parse(args[1:])
+		q := ""
+		if strings.TrimSpace(*since) != "" {
+			q = "?since=" + urlQueryEscape(strings.TrimSpace(*since))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/signals/quality"+q, nil)
+
+	case "logs":
+		fs := flag.NewFlagSet("easyweb3 api polymarket logs", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		level := fs.String("level", "", "minimum level: debug|info|warn|error")
+		module := fs.String("module", "", "comma-separated modules, e.g. service,strategy")
+		limit := fs.Int("limit", 200, "entries to return without --follow")
+		tail := fs.Int("tail", 100, "entries to replay before following")
+		follow := fs.Bool("follow", false, "stream new entries until interrupted")
+		_ = fs.Parse(args[1:])
+		parts := []string{}
+		if v := strings.TrimSpace(*level); v != "" {
+			parts = append(parts, "level="+urlQueryEscape(v))
+		}
+		if v := strings.TrimSpace(*module); v != "" {
+			parts = append(parts, "module="+urlQueryEscape(v))
+		}
+		if *follow {
+			parts = append(parts, fmt.Sprintf("tail=%d", *tail))
+			return polymarketLogsFollow(ctx, "?"+strings.Join(parts, "&"))
+		}
+		parts = append(parts, fmt.Sprintf("limit=%d", *limit))
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/system/logs?"+strings.Join(parts, "&"), nil)
+
+	case "audit-export":
+		fs := flag.NewFlagSet("easyweb3 api polymarket audit-export", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		afterSeq := fs.Uint64("after-seq", 0, "return records after this seq")
+		limit := fs.Int("limit", 1000, "limit")
+		since := fs.String("since", "", "RFC3339")
+		until := fs.String("until", "", "RFC3339")
+		_ = fs.Parse(args[1:])
+		q := fmt.Sprintf("?after_seq=%d&limit=%d", *afterSeq, *limit)
+		if strings.TrimSpace(*since) != "" {
+			q += "&since=" + urlQueryEscape(strings.TrimSpace(*since))
+		}
+		if strings.TrimSpace(*until) != "" {
+			q += "&until=" + urlQueryEscape(strings.TrimSpace(*until))
+		}
+		return polymarketDo(ctx, http.MethodGet, "/api/v2/audit/export"+q, nil)
+
+	case "audit-verify":
+		fs := flag.NewFlagSet("easyweb3 api polymarket audit-verify", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		pageSize := fs.Int("page-size", 1000, "records per export page")
+		_ = fs.Parse(args[1:])
+		return polymarketAuditVerify(ctx, *pageSize)
+
+	default:
+		return fmt.Errorf("unknown polymarket operation: %s", args[0])
 	}
 }
 
@@ -595,3 +2675,22 @@ func polymarketDo(ctx Context, method, path string, body any) error {
 	}
 	return output.Write(os.Stdout, ctx.Output, resp)
 }
+
+// analyticsQuery builds the since/until/as_of query string shared by the
+// analytics operations.
+func analyticsQuery(since, until, asOf string) string {
+	parts := []string{}
+	if v := strings.TrimSpace(since); v != "" {
+		parts = append(parts, "since="+urlQueryEscape(v))
+	}
+	if v := strings.TrimSpace(until); v != "" {
+		parts = append(parts, "until="+urlQueryEscape(v))
+	}
+	if v := strings.TrimSpace(asOf); v != "" {
+		parts = append(parts, "as_of="+urlQueryEscape(v))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "?" + strings.Join(parts, "&")
+}
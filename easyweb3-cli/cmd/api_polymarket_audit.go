@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nicekwell/easyweb3-cli/internal/client"
+	"github.com/nicekwell/easyweb3-cli/internal/output"
+)
+
+// auditRecord mirrors the polymarket audit_records row as exported.
+type auditRecord struct {
+	Seq        uint64
+	CreatedAt  time.Time
+	Agent      string
+	Method     string
+	Path       string
+	Status     int
+	DurationMs int64
+	Project    string
+	Role       string
+	PrevHash   string
+	Hash       string
+}
+
+type auditExportResponse struct {
+	Data []auditRecord `json:"data"`
+}
+
+var auditGenesisHash = strings.Repeat("0", 64)
+
+// auditRecordHash must stay identical to service.AuditRecordHash in the
+// polymarket backend.
+func auditRecordHash(rec auditRecord) string {
+	payload := strings.Join([]string{
+		strconv.FormatUint(rec.Seq, 10),
+		rec.CreatedAt.UTC().Format(time.RFC3339Nano),
+		rec.Agent,
+		rec.Method,
+		rec.Path,
+		strconv.Itoa(rec.Status),
+		strconv.FormatInt(rec.DurationMs, 10),
+		rec.Project,
+		rec.Role,
+		rec.PrevHash,
+	}, "\n")
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
+
+// polymarketAuditVerify downloads the whole audit chain and recomputes every
+// hash locally, so it does not have to trust the service's own verify endpoint.
+func polymarketAuditVerify(ctx Context, pageSize int) error {
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+	c := &client.Client{BaseURL: ctx.APIBase, Token: strings.TrimSpace(ctx.Token)}
+	result := map[string]any{"valid": true}
+	checked := 0
+	var lastSeq uint64
+	lastHash := auditGenesisHash
+	for {
+		route := fmt.Sprintf("/api/v1/services/polymarket/api/v2/audit/export?after_seq=%d&limit=%d", lastSeq, pageSize)
+		req, err := c.NewRequest(http.MethodGet, route, nil)
+		if err != nil {
+			return err
+		}
+		var page auditExportResponse
+		if err := c.Do(req, &page); err != nil {
+			return err
+		}
+		if len(page.Data) == 0 {
+			break
+		}
+		for _, rec := range page.Data {
+			checked++
+			reason := ""
+			switch {
+			case rec.Seq != lastSeq+1:
+				reason = fmt.Sprintf("expected seq %d, got %d", lastSeq+1, rec.Seq)
+			case rec.PrevHash != lastHash:
+				reason = "prev_hash does not match previous record"
+			case auditRecordHash(rec) != rec.Hash:
+				reason = "hash does not match record contents"
+			}
+			if reason != "" {
+				result["valid"] = false
+				result["broken_at"] = rec.Seq
+				result["reason"] = reason
+				result["checked"] = checked
+				result["last_seq"] = lastSeq
+				result["last_hash"] = lastHash
+				return output.Write(os.Stdout, ctx.Output, result)
+			}
+			lastSeq = rec.Seq
+			lastHash = rec.Hash
+		}
+	}
+	result["checked"] = checked
+	result["last_seq"] = lastSeq
+	result["last_hash"] = lastHash
+	return output.Write(os.Stdout, ctx.Output, result)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/nicekwell/easyweb3-cli/internal/client"
+	"github.com/nicekwell/easyweb3-cli/internal/output"
+)
+
+// polymarketBundleExport fetches a strategy bundle. With out set the bare
+// bundle is written to that file, ready for strategy-bundle-import.
+func polymarketBundleExport(ctx Context, names, out string) error {
+	path := "/api/v2/strategies/bundle/export"
+	if v := strings.TrimSpace(names); v != "" {
+		path += "?names=" + urlQueryEscape(v)
+	}
+	if strings.TrimSpace(out) == "" {
+		return polymarketDo(ctx, http.MethodGet, path, nil)
+	}
+	c := &client.Client{BaseURL: ctx.APIBase, Token: strings.TrimSpace(ctx.Token)}
+	req, err := c.NewRequest(http.MethodGet, "/api/v1/services/polymarket"+path, nil)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := c.Do(req, &resp); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, resp.Data, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	if err := os.WriteFile(out, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return output.Write(os.Stdout, ctx.Output, map[string]any{"file": out, "bytes": buf.Len()})
+}
+
+// polymarketBundleImport posts a bundle file. Files holding a full API
+// response are accepted too; numbers are passed through untouched so the
+// signature still verifies.
+func polymarketBundleImport(ctx Context, file string, dryRun, overwrite bool) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var bundle map[string]any
+	if err := dec.Decode(&bundle); err != nil {
+		return fmt.Errorf("parse %s: %w", file, err)
+	}
+	if _, ok := bundle["format"]; !ok {
+		if data, ok := bundle["data"].(map[string]any); ok {
+			bundle = data
+		}
+	}
+	if _, ok := bundle["format"]; !ok {
+		return errors.New("file is not a strategy bundle")
+	}
+	return polymarketDo(ctx, http.MethodPost, fmt.Sprintf("/api/v2/strategies/bundle/import?dry_run=%t&overwrite=%t", dryRun, overwrite), bundle)
+}
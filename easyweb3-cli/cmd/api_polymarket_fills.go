@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// polymarketFillImport uploads a CSV (with a header row) or JSON file of
+// manual or offline fills to a plan. CSV rows are sent as {"fills": [...]}
+// keyed by the lowercased header names.
+func polymarketFillImport(ctx Context, id, file string, dryRun bool) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var body any
+	if strings.EqualFold(filepath.Ext(file), ".csv") {
+		records, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", file, err)
+		}
+		if len(records) < 2 {
+			return fmt.Errorf("%s has no fills", file)
+		}
+		header := records[0]
+		fills := make([]map[string]string, 0, len(records)-1)
+		for _, rec := range records[1:] {
+			row := map[string]string{}
+			for i, name := range header {
+				if i < len(rec) {
+					row[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = strings.TrimSpace(rec[i])
+				}
+			}
+			fills = append(fills, row)
+		}
+		body = map[string]any{"fills": fills}
+	} else {
+		var raw any
+		if err := json.Unmarshal(b, &raw); err != nil {
+			return fmt.Errorf("parse %s: %w", file, err)
+		}
+		body = raw
+	}
+	return polymarketDo(ctx, http.MethodPost, fmt.Sprintf("/api/v2/executions/%s/fills/import?dry_run=%t", id, dryRun), body)
+}
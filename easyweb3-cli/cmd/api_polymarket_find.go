@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/nicekwell/easyweb3-cli/internal/client"
+	"github.com/nicekwell/easyweb3-cli/internal/output"
+)
+
+// findOptions holds the flags of the find operation.
+type findOptions struct {
+	Query     string
+	Act       string
+	Limit     int
+	Pick      int
+	Active    bool
+	Label     string
+	Source    string
+	Outcome   string
+	Direction string
+	SizeUSD   float64
+	Strategy  string
+}
+
+type findMarket struct {
+	ID       string `json:"id"`
+	Question string `json:"question"`
+}
+
+type findToken struct {
+	ID      string `json:"id"`
+	Outcome string `json:"outcome"`
+}
+
+// polymarketFind searches the catalog by question and, with an action,
+// applies it to the match: watch pins its tokens to the stream, label tags
+// the market and plan opens a draft manual plan. Acting needs exactly one
+// match, or --pick to choose one of the listed results.
+func polymarketFind(ctx Context, opts findOptions) error {
+	q := fmt.Sprintf("?limit=%d&question=%s&order_by=volume&ascending=false", opts.Limit, urlQueryEscape(opts.Query))
+	if opts.Active {
+		q += "&active=true&closed=false"
+	}
+	var markets []findMarket
+	if err := polymarketGet(ctx, "/api/catalog/markets"+q, &markets); err != nil {
+		return err
+	}
+	if opts.Act == "" {
+		return output.Write(os.Stdout, ctx.Output, markets)
+	}
+	if len(markets) == 0 {
+		return fmt.Errorf("no market matches %q", opts.Query)
+	}
+	market := markets[0]
+	switch {
+	case opts.Pick > 0:
+		if opts.Pick > len(markets) {
+			return fmt.Errorf("--pick %d out of range, %d markets match", opts.Pick, len(markets))
+		}
+		market = markets[opts.Pick-1]
+	case len(markets) > 1:
+		_ = output.Write(os.Stderr, ctx.Output, markets)
+		return fmt.Errorf("%d markets match %q; narrow the query or pass --pick N", len(markets), opts.Query)
+	}
+
+	switch opts.Act {
+	case "label":
+		if strings.TrimSpace(opts.Label) == "" {
+			return errors.New("--label required for --act label")
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/markets/"+urlQueryEscape(market.ID)+"/labels", map[string]any{
+			"label": strings.TrimSpace(opts.Label),
+		})
+	case "watch", "plan":
+	default:
+		return errors.New("--act must be watch, label or plan")
+	}
+
+	var tokens []findToken
+	if err := polymarketGet(ctx, "/api/catalog/tokens?limit=100&market_id="+urlQueryEscape(market.ID), &tokens); err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("market %s has no tokens", market.ID)
+	}
+	if opts.Act == "watch" {
+		ids := make([]string, 0, len(tokens))
+		for _, tok := range tokens {
+			ids = append(ids, tok.ID)
+		}
+		return polymarketDo(ctx, http.MethodPost, "/api/v2/pipeline/stream/pins", map[string]any{
+			"source":    opts.Source,
+			"token_ids": ids,
+		})
+	}
+
+	if opts.SizeUSD <= 0 {
+		return errors.New("--size-usd required for --act plan")
+	}
+	var token *findToken
+	for i := range tokens {
+		if strings.EqualFold(strings.TrimSpace(tokens[i].Outcome), opts.Outcome) {
+			token = &tokens[i]
+			break
+		}
+	}
+	if token == nil {
+		return fmt.Errorf("market %s has no %q outcome", market.ID, opts.Outcome)
+	}
+	leg := map[string]any{
+		"token_id":  token.ID,
+		"market_id": market.ID,
+		"size_usd":  opts.SizeUSD,
+	}
+	if v := strings.TrimSpace(opts.Direction); v != "" {
+		leg["direction"] = strings.ToUpper(v)
+	}
+	return polymarketDo(ctx, http.MethodPost, "/api/v2/executions", map[string]any{
+		"strategy_name": opts.Strategy,
+		"legs":          []map[string]any{leg},
+		"note":          "find: " + market.Question,
+	})
+}
+
+// polymarketGet decodes the data of a GET response into out.
+func polymarketGet(ctx Context, path string, out any) error {
+	c := &client.Client{BaseURL: ctx.APIBase, Token: strings.TrimSpace(ctx.Token)}
+	req, err := c.NewRequest(http.MethodGet, "/api/v1/services/polymarket"+path, nil)
+	if err != nil {
+		return err
+	}
+	resp := struct {
+		Data any `json:"data"`
+	}{Data: out}
+	return c.Do(req, &resp)
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// findCall is one request the fake polymarket service received.
+type findCall struct {
+	Method string
+	Path   string
+	Query  string
+	Body   map[string]any
+}
+
+// findServer serves catalog searches whose result depends on the question
+// ("one", "two" or anything else for none) and records every call.
+func findServer(t *testing.T) (*httptest.Server, func() []findCall) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []findCall
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := findCall{Method: r.Method, Path: strings.TrimPrefix(r.URL.Path, "/api/v1/services/polymarket"), Query: r.URL.RawQuery}
+		if b, _ := io.ReadAll(r.Body); len(b) > 0 {
+			_ = json.Unmarshal(b, &call.Body)
+		}
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+
+		var data any = map[string]any{"ok": true}
+		switch call.Path {
+		case "/api/catalog/markets":
+			markets := []findMarket{}
+			switch r.URL.Query().Get("question") {
+			case "two":
+				markets = append(markets, findMarket{ID: "m2", Question: "Will two happen?"})
+				fallthrough
+			case "one":
+				markets = append(markets, findMarket{ID: "m1", Question: "Will one happen?"})
+			}
+			data = markets
+		case "/api/catalog/tokens":
+			data = []findToken{{ID: "t-yes", Outcome: "Yes"}, {ID: "t-no", Outcome: "No"}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []findCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]findCall(nil), calls...)
+	}
+}
+
+// quietOutput discards what the command prints for the test's duration.
+func quietOutput(t *testing.T) {
+	t.Helper()
+	devnull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = devnull, devnull
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		_ = devnull.Close()
+	})
+}
+
+func TestPolymarketFind(t *testing.T) {
+	quietOutput(t)
+	cases := []struct {
+		name string
+		opts findOptions
+		// last is the final call's method and path; body its expected fields.
+		last  string
+		body  map[string]any
+		calls int
+	}{
+		{name: "search only", opts: findOptions{Query: "one", Limit: 5}, last: "GET /api/catalog/markets", calls: 1},
+		{name: "watch", opts: findOptions{Query: "one", Limit: 5, Act: "watch", Source: "cli"}, last: "POST /api/v2/pipeline/stream/pins",
+			body: map[string]any{"source": "cli", "token_ids": []any{"t-yes", "t-no"}}, calls: 3},
+		{name: "label", opts: findOptions{Query: "one", Limit: 5, Act: "label", Label: " sports "}, last: "POST /api/v2/markets/m1/labels",
+			body: map[string]any{"label": "sports"}, calls: 2},
+		{name: "pick among several", opts: findOptions{Query: "two", Limit: 5, Act: "label", Label: "sports", Pick: 1}, last: "POST /api/v2/markets/m2/labels", calls: 2},
+		{name: "plan", opts: findOptions{Query: "one", Limit: 5, Act: "plan", Outcome: "yes", Direction: "buy_yes", SizeUSD: 25, Strategy: "manual"}, last: "POST /api/v2/executions",
+			body: map[string]any{
+				"strategy_name": "manual",
+				"note":          "find: Will one happen?",
+				"legs":          []any{map[string]any{"token_id": "t-yes", "market_id": "m1", "size_usd": 25.0, "direction": "BUY_YES"}},
+			}, calls: 3},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, calls := findServer(t)
+			if err := polymarketFind(Context{APIBase: srv.URL, Token: "tok"}, tc.opts); err != nil {
+				t.Fatal(err)
+			}
+			got := calls()
+			if len(got) != tc.calls {
+				t.Fatalf("calls=%+v", got)
+			}
+			last := got[len(got)-1]
+			if last.Method+" "+last.Path != tc.last {
+				t.Fatalf("last call=%s %s", last.Method, last.Path)
+			}
+			for k, want := range tc.body {
+				wantJSON, _ := json.Marshal(want)
+				gotJSON, _ := json.Marshal(last.Body[k])
+				if string(gotJSON) != string(wantJSON) {
+					t.Fatalf("body[%s]=%s want %s", k, gotJSON, wantJSON)
+				}
+			}
+		})
+	}
+}
+
+func TestPolymarketFindSearchQuery(t *testing.T) {
+	quietOutput(t)
+	srv, calls := findServer(t)
+	if err := polymarketFind(Context{APIBase: srv.URL}, findOptions{Query: "fed & rates", Limit: 3, Active: true}); err != nil {
+		t.Fatal(err)
+	}
+	got := calls()
+	want := "limit=3&question=fed%20%26%20rates&order_by=volume&ascending=false&active=true&closed=false"
+	if len(got) != 1 || got[0].Query != want {
+		t.Fatalf("calls=%+v", got)
+	}
+}
+
+func TestPolymarketFindRejects(t *testing.T) {
+	quietOutput(t)
+	cases := []struct {
+		name string
+		opts findOptions
+		want string
+	}{
+		{"no match", findOptions{Query: "none", Act: "watch"}, `no market matches "none"`},
+		{"ambiguous", findOptions{Query: "two", Act: "watch"}, "2 markets match"},
+		{"pick out of range", findOptions{Query: "two", Act: "watch", Pick: 3}, "--pick 3 out of range"},
+		{"label missing", findOptions{Query: "one", Act: "label"}, "--label required"},
+		{"unknown action", findOptions{Query: "one", Act: "sell"}, "--act must be"},
+		{"plan without size", findOptions{Query: "one", Act: "plan", Outcome: "yes"}, "--size-usd required"},
+		{"unknown outcome", findOptions{Query: "one", Act: "plan", Outcome: "maybe", SizeUSD: 10}, `no "maybe" outcome`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, calls := findServer(t)
+			err := polymarketFind(Context{APIBase: srv.URL, Token: "tok"}, tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err=%v want %q", err, tc.want)
+			}
+			for _, c := range calls() {
+				if c.Method != http.MethodGet {
+					t.Fatalf("rejected find still wrote: %s %s", c.Method, c.Path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nicekwell/easyweb3-cli/internal/client"
+	"github.com/nicekwell/easyweb3-cli/internal/output"
+)
+
+// logEntry mirrors logger.Entry in the polymarket backend.
+type logEntry struct {
+	Seq     uint64         `json:"seq"`
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Module  string         `json:"module"`
+	Message string         `json:"message"`
+	Caller  string         `json:"caller,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// polymarketLogsFollow tails the service log stream until it closes or the
+// process is interrupted. JSON output prints one entry per line; other
+// formats print a console line per entry.
+func polymarketLogsFollow(ctx Context, query string) error {
+	c := &client.Client{BaseURL: ctx.APIBase, Token: strings.TrimSpace(ctx.Token), HTTP: &http.Client{}}
+	req, err := c.NewRequest(http.MethodGet, "/api/v1/services/polymarket/api/v2/system/logs/stream"+query, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		var er client.ErrorResponse
+		if json.Unmarshal(b, &er) == nil && strings.TrimSpace(er.Error) != "" {
+			return fmt.Errorf("http %d: %s", resp.StatusCode, er.Error)
+		}
+		return fmt.Errorf("http %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if ctx.Output.Format == output.FormatJSON {
+			fmt.Fprintln(os.Stdout, data)
+			continue
+		}
+		var e logEntry
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			fmt.Fprintln(os.Stdout, data)
+			continue
+		}
+		fmt.Fprintln(os.Stdout, formatLogEntry(e))
+	}
+	return sc.Err()
+}
+
+func formatLogEntry(e logEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s %-10s %s", e.Time.Local().Format("15:04:05.000"), strings.ToUpper(e.Level), e.Module, e.Message)
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, e.Fields[k])
+	}
+	return b.String()
+}
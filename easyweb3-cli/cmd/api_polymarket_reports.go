@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/nicekwell/easyweb3-cli/internal/client"
+)
+
+// polymarketRiskReportMarkdown prints the rendered markdown of a plan's
+// preflight risk report.
+func polymarketRiskReportMarkdown(ctx Context, id string) error {
+	c := &client.Client{BaseURL: ctx.APIBase, Token: strings.TrimSpace(ctx.Token)}
+	req, err := c.NewRequest(http.MethodGet, "/api/v1/services/polymarket/api/v2/executions/"+id+"/risk-report", nil)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Data struct {
+			Markdown string `json:"markdown"`
+		} `json:"data"`
+	}
+	if err := c.Do(req, &resp); err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(os.Stdout, resp.Data.Markdown)
+	return err
+}
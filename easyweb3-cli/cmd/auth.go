@@ -4,6 +4,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 
@@ -19,7 +20,7 @@ type tokenResponse struct {
 
 func authCmd(ctx Context, args []string) error {
 	if len(args) == 0 {
-		return errors.New("auth subcommand required: login|register|grant|refresh|status")
+		return errors.New("auth subcommand required: login|register|grant|refresh|status|delegate|delegations|revoke-delegation")
 	}
 	switch args[0] {
 	case "login":
@@ -112,6 +113,68 @@ func authCmd(ctx Context, args []string) error {
 		}
 		return output.Write(os.Stdout, ctx.Output, resp)
 
+	case "delegate":
+		fs := flag.NewFlagSet("easyweb3 auth delegate", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		projectID := fs.String("project-id", "", "Project id the token acts as")
+		service := fs.String("service", "polymarket", "Service the token may read")
+		paths := fs.String("paths", "", "Comma-separated path prefixes, e.g. /api/v2/analytics,/api/v2/positions")
+		ttl := fs.String("ttl", "24h", "Token lifetime (Go duration)")
+		name := fs.String("name", "", "Label, e.g. the dashboard it is shared for")
+		_ = fs.Parse(args[1:])
+		var list []string
+		for _, p := range strings.Split(*paths, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				list = append(list, p)
+			}
+		}
+		if strings.TrimSpace(*projectID) == "" || len(list) == 0 {
+			return errors.New("usage: easyweb3 auth delegate --project-id <project> --paths </api/v2/analytics,...> [--service polymarket] [--ttl 24h] [--name <label>]")
+		}
+		c := &client.Client{BaseURL: ctx.APIBase, Token: ctx.Token}
+		req, err := c.NewRequest("POST", "/api/v1/auth/delegations", map[string]any{
+			"name":       strings.TrimSpace(*name),
+			"project_id": strings.TrimSpace(*projectID),
+			"service":    strings.TrimSpace(*service),
+			"paths":      list,
+			"ttl":        strings.TrimSpace(*ttl),
+		})
+		if err != nil {
+			return err
+		}
+		var resp any
+		if err := c.Do(req, &resp); err != nil {
+			return err
+		}
+		return output.Write(os.Stdout, ctx.Output, resp)
+
+	case "delegations":
+		c := &client.Client{BaseURL: ctx.APIBase, Token: ctx.Token}
+		req, err := c.NewRequest("GET", "/api/v1/auth/delegations", nil)
+		if err != nil {
+			return err
+		}
+		var resp any
+		if err := c.Do(req, &resp); err != nil {
+			return err
+		}
+		return output.Write(os.Stdout, ctx.Output, resp)
+
+	case "revoke-delegation":
+		if len(args) < 2 || strings.TrimSpace(args[1]) == "" {
+			return errors.New("usage: easyweb3 auth revoke-delegation <id>")
+		}
+		c := &client.Client{BaseURL: ctx.APIBase, Token: ctx.Token}
+		req, err := c.NewRequest("DELETE", "/api/v1/auth/delegations/"+url.PathEscape(strings.TrimSpace(args[1])), nil)
+		if err != nil {
+			return err
+		}
+		var resp any
+		if err := c.Do(req, &resp); err != nil {
+			return err
+		}
+		return output.Write(os.Stdout, ctx.Output, resp)
+
 	case "refresh":
 		c := &client.Client{BaseURL: ctx.APIBase, Token: ctx.Token}
 		req, err := c.NewRequest("POST", "/api/v1/auth/refresh", map[string]any{})
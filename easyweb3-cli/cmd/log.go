@@ -24,7 +24,7 @@ type createLogRequest struct {
 
 func logCmd(ctx Context, args []string) error {
 	if len(args) == 0 {
-		return errors.New("log subcommand required: create|list|get")
+		return errors.New("log subcommand required: create|list|get|trace")
 	}
 	switch args[0] {
 	case "create":
@@ -79,6 +79,7 @@ func logCmd(ctx Context, args []string) error {
 		action := fs.String("action", "", "action filter")
 		level := fs.String("level", "", "level filter")
 		limit := fs.Int("limit", 20, "limit")
+		correlationID := fs.String("correlation-id", "", "correlation (request) id filter")
 		_ = fs.Parse(args[1:])
 
 		q := "?limit=" + fmt.Sprintf("%d", *limit)
@@ -88,6 +89,9 @@ func logCmd(ctx Context, args []string) error {
 		if strings.TrimSpace(*level) != "" {
 			q += "&level=" + urlQueryEscape(strings.TrimSpace(*level))
 		}
+		if strings.TrimSpace(*correlationID) != "" {
+			q += "&correlation_id=" + urlQueryEscape(strings.TrimSpace(*correlationID))
+		}
 
 		tok, err := ensureBearerToken(ctx)
 		if err != nil {
@@ -127,6 +131,29 @@ func logCmd(ctx Context, args []string) error {
 		}
 		return output.Write(os.Stdout, ctx.Output, resp)
 
+	case "trace":
+		if len(args) < 2 {
+			return errors.New("usage: easyweb3 log trace <correlation-id>")
+		}
+		id := strings.TrimSpace(args[1])
+		if id == "" {
+			return errors.New("correlation id required")
+		}
+		tok, err := ensureBearerToken(ctx)
+		if err != nil {
+			return err
+		}
+		c := &client.Client{BaseURL: ctx.APIBase, Token: tok}
+		req, err := c.NewRequest("GET", "/api/v1/logs/trace/"+urlQueryEscape(id), nil)
+		if err != nil {
+			return err
+		}
+		var resp any
+		if err := c.Do(req, &resp); err != nil {
+			return err
+		}
+		return output.Write(os.Stdout, ctx.Output, resp)
+
 	default:
 		return fmt.Errorf("unknown log subcommand: %s", args[0])
 	}
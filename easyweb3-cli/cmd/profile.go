@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nicekwell/easyweb3-cli/internal/config"
+	"github.com/nicekwell/easyweb3-cli/internal/output"
+)
+
+type profileItem struct {
+	Name        string `json:"name"`
+	APIBase     string `json:"api_base,omitempty"`
+	Project     string `json:"project,omitempty"`
+	LogLevel    string `json:"log_level,omitempty"`
+	Current     bool   `json:"current"`
+	Credentials string `json:"credentials"`
+}
+
+func profileCmd(ctx Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("profile subcommand required: add|use|list|remove")
+	}
+	switch args[0] {
+	case "add":
+		if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+			return errors.New("usage: easyweb3 profile add <name> --api-base <url> [--project <id>] [--log-level <level>]")
+		}
+		name := strings.TrimSpace(args[1])
+		fs := flag.NewFlagSet("easyweb3 profile add", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		apiBase := fs.String("api-base", "", "PaaS API base URL")
+		project := fs.String("project", "", "Project id")
+		logLevel := fs.String("log-level", "", "Log level")
+		_ = fs.Parse(args[2:])
+		if err := config.ValidateProfileName(name); err != nil {
+			return err
+		}
+		if strings.TrimSpace(*apiBase) == "" {
+			return errors.New("--api-base is required")
+		}
+		f, err := config.LoadFile()
+		if err != nil {
+			return err
+		}
+		if f.Profiles == nil {
+			f.Profiles = map[string]config.Config{}
+		}
+		f.Profiles[name] = config.Config{
+			APIBase:  strings.TrimRight(strings.TrimSpace(*apiBase), "/"),
+			Project:  strings.TrimSpace(*project),
+			LogLevel: strings.TrimSpace(*logLevel),
+		}
+		if err := config.SaveFile(f); err != nil {
+			return err
+		}
+		return output.Write(os.Stdout, ctx.Output, profileItems(f)[profileIndex(f, name)])
+
+	case "use":
+		if len(args) < 2 {
+			return errors.New("usage: easyweb3 profile use <name|default>")
+		}
+		name := strings.TrimSpace(args[1])
+		f, err := config.LoadFile()
+		if err != nil {
+			return err
+		}
+		if name == config.DefaultProfile {
+			name = ""
+		} else if _, ok := f.Profiles[name]; !ok {
+			return fmt.Errorf("profile %q not found", name)
+		}
+		f.CurrentProfile = name
+		if err := config.SaveFile(f); err != nil {
+			return err
+		}
+		return output.Write(os.Stdout, ctx.Output, profileItems(f)[profileIndex(f, name)])
+
+	case "list":
+		f, err := config.LoadFile()
+		if err != nil {
+			return err
+		}
+		return output.Write(os.Stdout, ctx.Output, profileItems(f))
+
+	case "remove":
+		if len(args) < 2 {
+			return errors.New("usage: easyweb3 profile remove <name>")
+		}
+		name := strings.TrimSpace(args[1])
+		f, err := config.LoadFile()
+		if err != nil {
+			return err
+		}
+		if _, ok := f.Profiles[name]; !ok {
+			return fmt.Errorf("profile %q not found", name)
+		}
+		delete(f.Profiles, name)
+		if f.CurrentProfile == name {
+			f.CurrentProfile = ""
+		}
+		if err := config.SaveFile(f); err != nil {
+			return err
+		}
+		return output.Write(os.Stdout, ctx.Output, map[string]any{"removed": name})
+
+	default:
+		return fmt.Errorf("unknown profile subcommand: %s", args[0])
+	}
+}
+
+// profileItems lists the default profile first, then named profiles by name.
+func profileItems(f config.File) []profileItem {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	def := config.DefaultConfig()
+	items := []profileItem{{
+		Name:        config.DefaultProfile,
+		APIBase:     firstNonEmpty(f.APIBase, def.APIBase),
+		Project:     f.Project,
+		LogLevel:    f.LogLevel,
+		Current:     f.CurrentProfile == "",
+		Credentials: "credentials.json",
+	}}
+	for _, name := range names {
+		p := f.Profiles[name]
+		items = append(items, profileItem{
+			Name:        name,
+			APIBase:     p.APIBase,
+			Project:     p.Project,
+			LogLevel:    p.LogLevel,
+			Current:     f.CurrentProfile == name,
+			Credentials: "credentials." + name + ".json",
+		})
+	}
+	return items
+}
+
+func profileIndex(f config.File, name string) int {
+	for i, it := range profileItems(f) {
+		if it.Name == name || (name == "" && it.Name == config.DefaultProfile) {
+			return i
+		}
+	}
+	return 0
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
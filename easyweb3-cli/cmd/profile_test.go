@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/nicekwell/easyweb3-cli/internal/config"
+)
+
+func TestProfileAddUseRemove(t *testing.T) {
+	t.Setenv("EASYWEB3_DIR", t.TempDir())
+	ctx := Context{}
+
+	steps := []struct {
+		args    []string
+		current string
+		wantErr bool
+	}{
+		{args: []string{"add", "staging", "--api-base", "https://staging.example/"}, current: ""},
+		{args: []string{"add", "prod", "--api-base", "https://prod.example", "--project", "live"}, current: ""},
+		{args: []string{"add", "bad/name", "--api-base", "https://x.example"}, current: "", wantErr: true},
+		{args: []string{"add", "qa"}, current: "", wantErr: true},
+		{args: []string{"use", "prod"}, current: "prod"},
+		{args: []string{"use", "qa"}, current: "prod", wantErr: true},
+		{args: []string{"use", "staging"}, current: "staging"},
+		{args: []string{"use", "default"}, current: ""},
+		{args: []string{"use", "staging"}, current: "staging"},
+		{args: []string{"remove", "staging"}, current: ""},
+		{args: []string{"remove", "staging"}, current: "", wantErr: true},
+	}
+	for _, st := range steps {
+		err := profileCmd(ctx, st.args)
+		if (err != nil) != st.wantErr {
+			t.Fatalf("%v: err = %v, want error %v", st.args, err, st.wantErr)
+		}
+		f, err := config.LoadFile()
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		if f.CurrentProfile != st.current {
+			t.Fatalf("%v: current = %q, want %q", st.args, f.CurrentProfile, st.current)
+		}
+	}
+
+	f, _ := config.LoadFile()
+	if len(f.Profiles) != 1 || f.Profiles["prod"].APIBase != "https://prod.example" || f.Profiles["prod"].Project != "live" {
+		t.Fatalf("profiles = %+v", f.Profiles)
+	}
+}
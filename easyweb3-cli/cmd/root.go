@@ -13,7 +13,10 @@ type Context struct {
 	APIBase string
 	Token   string
 	Project string
-	Output  output.Format
+	Output  output.Options
+
+	// Profile is the active named profile; empty for the default settings.
+	Profile string
 }
 
 func Usage(w io.Writer) {
@@ -23,10 +26,13 @@ Global Flags:
   --api-base    PaaS API base URL (env: EASYWEB3_API_BASE)
   --token       Bearer Token (env: EASYWEB3_TOKEN)
   --output      json|text|markdown (default json)
+  --query       JSONPath subset applied to the response (e.g. '$.data[?(@.status=="open")]')
+  --columns     Comma-separated fields kept from each row; text/markdown print a table
   --project     Project id (env: EASYWEB3_PROJECT)
+  --profile     Named config profile (env: EASYWEB3_PROFILE)
 
 Commands:
-  auth     login/register/grant/refresh/status
+  auth     login/register/grant/refresh/status/delegate/delegations/revoke-delegation
   log      create/list/get
   notify   send/broadcast/config
   integrations query|polymarket
@@ -34,6 +40,7 @@ Commands:
   api      raw|polymarket
   docs     url/get (public docs)
   service  list/health/docs
+  profile  add/use/list/remove
 `)
 }
 
@@ -59,6 +66,8 @@ func Dispatch(ctx Context, args []string) error {
 		return docsCmd(ctx, args[1:])
 	case "service":
 		return serviceCmd(ctx, args[1:])
+	case "profile":
+		return profileCmd(ctx, args[1:])
 	case "help", "-h", "--help":
 		Usage(os.Stdout)
 		return nil
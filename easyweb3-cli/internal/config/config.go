@@ -14,6 +14,18 @@ type Config struct {
 	APIBase  string `json:"api_base"`
 	Project  string `json:"project"`
 	LogLevel string `json:"log_level"`
+
+	// Profile is the resolved profile name; empty means the default
+	// (top-level) settings. Not persisted.
+	Profile string `json:"-"`
+}
+
+// File is the on-disk config.json. The top-level fields are the default
+// profile; named profiles override them.
+type File struct {
+	Config
+	CurrentProfile string            `json:"current_profile,omitempty"`
+	Profiles       map[string]Config `json:"profiles,omitempty"`
 }
 
 type Credentials struct {
@@ -54,35 +66,118 @@ func ConfigPath() (string, error) {
 	return filepath.Join(d, "config.json"), nil
 }
 
+// CredentialsPath is credentials.json for the default profile and
+// credentials.<profile>.json for named profiles, so tokens never leak across
+// environments.
 func CredentialsPath() (string, error) {
 	d, err := Dir()
 	if err != nil {
 		return "", err
 	}
+	if activeProfile != "" {
+		return filepath.Join(d, "credentials."+activeProfile+".json"), nil
+	}
 	return filepath.Join(d, "credentials.json"), nil
 }
 
+// activeProfile selects the profile used by LoadConfig and the credentials
+// helpers. It is set once at startup via UseProfile or from current_profile.
+var activeProfile string
+
+// profilePinned is set by UseProfile; current_profile then no longer applies.
+var profilePinned bool
+
+// UseProfile pins the profile for this process over current_profile; "" or
+// "default" selects the top-level settings.
+func UseProfile(name string) error {
+	if err := selectProfile(name); err != nil {
+		return err
+	}
+	profilePinned = true
+	return nil
+}
+
+func selectProfile(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == DefaultProfile {
+		activeProfile = ""
+		return nil
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	activeProfile = name
+	return nil
+}
+
+const DefaultProfile = "default"
+
+func ValidateProfileName(name string) error {
+	if name == "" || name == DefaultProfile {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("invalid profile name %q: use letters, digits, - and _", name)
+		}
+	}
+	return nil
+}
+
+func LoadFile() (File, error) {
+	p, err := ConfigPath()
+	if err != nil {
+		return File{}, err
+	}
+	var f File
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return f, nil
+		}
+		return File{}, err
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return File{}, fmt.Errorf("parse %s: %w", p, err)
+	}
+	return f, nil
+}
+
+func SaveFile(f File) error {
+	d, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d, 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(d, "config.json"), b, 0o644)
+}
+
 func LoadConfig() (Config, error) {
 	cfg := DefaultConfig()
 
-	p, err := ConfigPath()
+	f, err := LoadFile()
 	if err != nil {
 		return Config{}, err
 	}
-	if b, err := os.ReadFile(p); err == nil {
-		var onDisk Config
-		if err := json.Unmarshal(b, &onDisk); err != nil {
-			return Config{}, fmt.Errorf("parse %s: %w", p, err)
+	applyConfig(&cfg, f.Config)
+	if !profilePinned {
+		if err := selectProfile(f.CurrentProfile); err != nil {
+			return Config{}, err
 		}
-		if strings.TrimSpace(onDisk.APIBase) != "" {
-			cfg.APIBase = strings.TrimRight(strings.TrimSpace(onDisk.APIBase), "/")
-		}
-		if strings.TrimSpace(onDisk.Project) != "" {
-			cfg.Project = strings.TrimSpace(onDisk.Project)
-		}
-		if strings.TrimSpace(onDisk.LogLevel) != "" {
-			cfg.LogLevel = strings.TrimSpace(onDisk.LogLevel)
+	}
+	if activeProfile != "" {
+		prof, ok := f.Profiles[activeProfile]
+		if !ok {
+			return Config{}, fmt.Errorf("profile %q not found; run: easyweb3 profile list", activeProfile)
 		}
+		applyConfig(&cfg, prof)
+		cfg.Profile = activeProfile
 	}
 
 	// Env overrides
@@ -99,6 +194,18 @@ func LoadConfig() (Config, error) {
 	return cfg, nil
 }
 
+func applyConfig(cfg *Config, onDisk Config) {
+	if strings.TrimSpace(onDisk.APIBase) != "" {
+		cfg.APIBase = strings.TrimRight(strings.TrimSpace(onDisk.APIBase), "/")
+	}
+	if strings.TrimSpace(onDisk.Project) != "" {
+		cfg.Project = strings.TrimSpace(onDisk.Project)
+	}
+	if strings.TrimSpace(onDisk.LogLevel) != "" {
+		cfg.LogLevel = strings.TrimSpace(onDisk.LogLevel)
+	}
+}
+
 func LoadCredentials() (Credentials, error) {
 	p, err := CredentialsPath()
 	if err != nil {
@@ -123,12 +230,19 @@ func SaveCredentials(c Credentials) error {
 	if err := os.MkdirAll(d, 0o755); err != nil {
 		return err
 	}
-	p := filepath.Join(d, "credentials.json")
+	p, err := CredentialsPath()
+	if err != nil {
+		return err
+	}
 	b, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(p, b, 0o600)
+	if err := os.WriteFile(p, b, 0o600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file; tokens stay owner-only.
+	return os.Chmod(p, 0o600)
 }
 
 func (c Credentials) ExpiresAtTime() (time.Time, bool) {
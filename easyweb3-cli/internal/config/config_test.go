@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupProfiles points the config dir at a temp dir holding a default
+// profile and two named ones, staging being current, and unpins the profile.
+func setupProfiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("EASYWEB3_DIR", dir)
+	t.Setenv("EASYWEB3_API_BASE", "")
+	t.Setenv("EASYWEB3_PROJECT", "")
+	t.Setenv("EASYWEB3_PROFILE", "")
+	activeProfile, profilePinned = "", false
+	t.Cleanup(func() { activeProfile, profilePinned = "", false })
+
+	f := File{
+		Config:         Config{APIBase: "http://local:8080", Project: "dev"},
+		CurrentProfile: "staging",
+		Profiles: map[string]Config{
+			"staging": {APIBase: "https://staging.example/", LogLevel: "debug"},
+			"prod":    {APIBase: "https://prod.example", Project: "live"},
+		},
+	}
+	if err := SaveFile(f); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	return dir
+}
+
+func TestLoadConfigSwitchesProfiles(t *testing.T) {
+	cases := []struct {
+		name     string
+		use      string
+		pin      bool
+		profile  string
+		apiBase  string
+		project  string
+		logLevel string
+	}{
+		{name: "current profile", profile: "staging", apiBase: "https://staging.example", project: "dev", logLevel: "debug"},
+		{name: "pinned profile", use: "prod", pin: true, profile: "prod", apiBase: "https://prod.example", project: "live", logLevel: "info"},
+		{name: "pinned default", use: "default", pin: true, profile: "", apiBase: "http://local:8080", project: "dev", logLevel: "info"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupProfiles(t)
+			if tc.pin {
+				if err := UseProfile(tc.use); err != nil {
+					t.Fatalf("use: %v", err)
+				}
+			}
+			cfg, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if cfg.Profile != tc.profile || cfg.APIBase != tc.apiBase || cfg.Project != tc.project || cfg.LogLevel != tc.logLevel {
+				t.Fatalf("config = %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoadConfigEnvOverridesProfile(t *testing.T) {
+	setupProfiles(t)
+	t.Setenv("EASYWEB3_API_BASE", "https://env.example/")
+	t.Setenv("EASYWEB3_PROJECT", "env-project")
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Profile != "staging" || cfg.APIBase != "https://env.example" || cfg.Project != "env-project" {
+		t.Fatalf("config = %+v", cfg)
+	}
+}
+
+func TestLoadConfigRejectsMissingProfile(t *testing.T) {
+	setupProfiles(t)
+	if err := UseProfile("qa"); err != nil {
+		t.Fatalf("use: %v", err)
+	}
+	if _, err := LoadConfig(); err == nil {
+		t.Fatalf("expected error for unknown profile")
+	}
+}
+
+func TestUseProfileValidatesName(t *testing.T) {
+	setupProfiles(t)
+	for _, name := range []string{"../prod", "a b", "prod.json"} {
+		if err := UseProfile(name); err == nil {
+			t.Errorf("UseProfile(%q) accepted", name)
+		}
+	}
+}
+
+func TestCredentialsArePerProfileAndOwnerOnly(t *testing.T) {
+	dir := setupProfiles(t)
+	if err := SaveCredentials(Credentials{Token: "default-token"}); err != nil {
+		t.Fatalf("save default: %v", err)
+	}
+	if err := UseProfile("prod"); err != nil {
+		t.Fatalf("use: %v", err)
+	}
+	// An existing file with loose permissions is tightened on save.
+	prodPath := filepath.Join(dir, "credentials.prod.json")
+	if err := os.WriteFile(prodPath, []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	if err := SaveCredentials(Credentials{Token: "prod-token"}); err != nil {
+		t.Fatalf("save prod: %v", err)
+	}
+
+	for _, path := range []string{filepath.Join(dir, "credentials.json"), prodPath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat %s: %v", path, err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("%s mode = %o, want 600", path, perm)
+		}
+	}
+	got, err := LoadCredentials()
+	if err != nil || got.Token != "prod-token" {
+		t.Fatalf("prod credentials = %+v, %v", got, err)
+	}
+	if err := UseProfile("default"); err != nil {
+		t.Fatalf("use default: %v", err)
+	}
+	got, err = LoadCredentials()
+	if err != nil || got.Token != "default-token" {
+		t.Fatalf("default credentials = %+v, %v", got, err)
+	}
+}
@@ -1,9 +1,12 @@
 package output
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+	"text/tabwriter"
 )
 
 type Format string
@@ -14,25 +17,195 @@ const (
 	FormatMarkdown Format = "markdown"
 )
 
-func Write(w io.Writer, format Format, v any) error {
-	switch format {
-	case FormatText, FormatMarkdown:
-		// For MVP, just JSON-encode even for text/markdown.
-		// We can implement richer formatting later.
-		fallthrough
-	case FormatJSON, "":
-		b, err := json.MarshalIndent(v, "", "  ")
+// Options controls how a response is written. Query (see ParseQuery)
+// selects part of the response first; Columns then keeps those fields of
+// each row, in order, and prints the rows as a table in text and markdown.
+// A column is a field name or a query relative to the row, e.g.
+// "market.slug".
+type Options struct {
+	Format  Format
+	Query   string
+	Columns []string
+}
+
+func Write(w io.Writer, opts Options, v any) error {
+	if strings.TrimSpace(opts.Query) == "" && len(opts.Columns) == 0 {
+		// For MVP, text and markdown are JSON-encoded too.
+		return writeJSON(w, v)
+	}
+	doc, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(opts.Query) != "" {
+		q, err := ParseQuery(opts.Query)
 		if err != nil {
 			return err
 		}
-		_, err = fmt.Fprintln(w, string(b))
+		doc = q.Eval(doc)
+	}
+	if len(opts.Columns) > 0 {
+		return writeColumns(w, opts.Format, doc, opts.Columns)
+	}
+	if opts.Format == FormatText || opts.Format == FormatMarkdown {
+		// Scalars print bare, and lists of scalars one per line, so a
+		// query result can be used in shell scripts.
+		if lines, ok := scalarLines(doc); ok {
+			_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+			return err
+		}
+	}
+	return writeJSON(w, doc)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
 		return err
-	default:
-		b, err := json.MarshalIndent(v, "", "  ")
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// normalize turns v into the generic value encoding/json decodes it to,
+// keeping numbers as written.
+func normalize(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// row is one projected row; it encodes as an object with its keys in
+// column order.
+type row struct {
+	keys   []string
+	values []any
+}
+
+func (r row) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range r.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		val, err := json.Marshal(r.values[i])
 		if err != nil {
-			return err
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(val)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func writeColumns(w io.Writer, format Format, doc any, columns []string) error {
+	queries := make([]*Query, len(columns))
+	for i, col := range columns {
+		q, err := ParseQuery(col)
+		if err != nil {
+			return fmt.Errorf("column %q: %w", col, err)
+		}
+		queries[i] = q
+	}
+	items, list := doc.([]any)
+	if !list {
+		items = []any{doc}
+	}
+	rows := make([]row, 0, len(items))
+	for _, item := range items {
+		r := row{keys: columns, values: make([]any, len(columns))}
+		for i, q := range queries {
+			r.values[i] = q.Eval(item)
+		}
+		rows = append(rows, r)
+	}
+
+	switch format {
+	case FormatText:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(columns, "\t"))
+		for _, r := range rows {
+			cells := make([]string, len(r.values))
+			for i, v := range r.values {
+				cells[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(cellText(v))
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	case FormatMarkdown:
+		var b strings.Builder
+		esc := strings.NewReplacer("|", `\|`, "\n", " ")
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = esc.Replace(col)
+		}
+		fmt.Fprintf(&b, "| %s |\n|%s\n", strings.Join(header, " | "), strings.Repeat(" --- |", len(columns)))
+		for _, r := range rows {
+			cells := make([]string, len(r.values))
+			for i, v := range r.values {
+				cells[i] = esc.Replace(cellText(v))
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
 		}
-		_, err = fmt.Fprintln(w, string(b))
+		_, err := io.WriteString(w, b.String())
 		return err
+	default:
+		if !list && len(rows) == 1 {
+			return writeJSON(w, rows[0])
+		}
+		return writeJSON(w, rows)
+	}
+}
+
+func scalarLines(doc any) ([]string, bool) {
+	items, list := doc.([]any)
+	if !list {
+		items = []any{doc}
+	}
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		switch item.(type) {
+		case map[string]any, []any:
+			return nil, false
+		}
+		lines = append(lines, scalarText(item))
+	}
+	return lines, true
+}
+
+// cellText renders a table cell: scalars bare, missing values empty, and
+// objects and lists as compact JSON.
+func cellText(v any) string {
+	switch v.(type) {
+	case nil:
+		return ""
+	case map[string]any, []any:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	return scalarText(v)
+}
+
+func scalarText(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return s
+	case json.Number:
+		return s.String()
 	}
+	return fmt.Sprint(v)
 }
@@ -0,0 +1,367 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Query selects part of a JSON value with a subset of JSONPath:
+//
+//	$                the whole value; optional, as is a leading "."
+//	.name ['name']   an object field
+//	[2] [-1]         an array element, negative counting from the end
+//	[1:3]            an array slice
+//	.* [*]           every array element or object field value
+//	[?(@.f op v)]    the elements whose field f compares to the JSON literal
+//	                 v with == != < <= > >=; [?(@.f)] keeps those having f
+//
+// A query without wildcards, slices or filters yields one value (null when
+// missing); otherwise it yields the list of matches.
+type Query struct {
+	segs  []segment
+	multi bool
+}
+
+type segmentKind int
+
+const (
+	segField segmentKind = iota
+	segIndex
+	segSlice
+	segWildcard
+	segFilter
+)
+
+type segment struct {
+	kind       segmentKind
+	name       string
+	index      int
+	start, end *int
+	filter     *filter
+}
+
+type filter struct {
+	path  []segment
+	op    string
+	value any
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// ParseQuery parses a query; an empty one selects the whole value.
+func ParseQuery(s string) (*Query, error) {
+	s = strings.TrimSpace(s)
+	q := &Query{}
+	i := 0
+	if strings.HasPrefix(s, "$") {
+		i = 1
+	}
+	for i < len(s) {
+		switch {
+		case s[i] == '[':
+			end := closingBracket(s, i)
+			if end < 0 {
+				return nil, fmt.Errorf("query %q: unclosed [ at %d", s, i)
+			}
+			seg, err := parseBracket(strings.TrimSpace(s[i+1 : end]))
+			if err != nil {
+				return nil, fmt.Errorf("query %q: %w", s, err)
+			}
+			if seg.kind != segField && seg.kind != segIndex {
+				q.multi = true
+			}
+			q.segs = append(q.segs, seg)
+			i = end + 1
+		case s[i] == '.' || i == 0:
+			if s[i] == '.' {
+				i++
+			}
+			if i < len(s) && s[i] == '*' {
+				q.segs = append(q.segs, segment{kind: segWildcard})
+				q.multi = true
+				i++
+				continue
+			}
+			j := i
+			for j < len(s) && s[j] != '.' && s[j] != '[' {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("query %q: empty field name at %d", s, i)
+			}
+			q.segs = append(q.segs, segment{kind: segField, name: s[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("query %q: unexpected %q at %d", s, s[i], i)
+		}
+	}
+	return q, nil
+}
+
+// closingBracket returns the index of the ] closing the [ at open, skipping
+// quoted strings, or -1.
+func closingBracket(s string, open int) int {
+	var quote byte
+	for i := open + 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func parseBracket(inner string) (segment, error) {
+	switch {
+	case inner == "*":
+		return segment{kind: segWildcard}, nil
+	case strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, `"`):
+		name, err := unquote(inner)
+		if err != nil {
+			return segment{}, err
+		}
+		return segment{kind: segField, name: name}, nil
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		f, err := parseFilter(strings.TrimSpace(inner[2 : len(inner)-1]))
+		if err != nil {
+			return segment{}, err
+		}
+		return segment{kind: segFilter, filter: f}, nil
+	case strings.Contains(inner, ":"):
+		lo, hi, _ := strings.Cut(inner, ":")
+		seg := segment{kind: segSlice}
+		for _, part := range []struct {
+			raw string
+			dst **int
+		}{{lo, &seg.start}, {hi, &seg.end}} {
+			if raw := strings.TrimSpace(part.raw); raw != "" {
+				n, err := strconv.Atoi(raw)
+				if err != nil {
+					return segment{}, fmt.Errorf("bad slice [%s]", inner)
+				}
+				*part.dst = &n
+			}
+		}
+		return seg, nil
+	default:
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return segment{}, fmt.Errorf("bad index [%s]", inner)
+		}
+		return segment{kind: segIndex, index: n}, nil
+	}
+}
+
+func parseFilter(body string) (*filter, error) {
+	if !strings.HasPrefix(body, "@") {
+		return nil, fmt.Errorf("filter %q must start with @", body)
+	}
+	f := &filter{}
+	lhs := body
+	for i := 1; i < len(body) && f.op == ""; i++ {
+		for _, op := range filterOps {
+			if strings.HasPrefix(body[i:], op) {
+				f.op, lhs = op, body[:i]
+				raw := strings.TrimSpace(body[i+len(op):])
+				if strings.HasPrefix(raw, "'") {
+					s, err := unquote(raw)
+					if err != nil {
+						return nil, err
+					}
+					f.value = s
+				} else if err := json.Unmarshal([]byte(raw), &f.value); err != nil {
+					return nil, fmt.Errorf("filter %q: bad value %q", body, raw)
+				}
+				break
+			}
+		}
+	}
+	path, err := ParseQuery("$" + strings.TrimSpace(lhs)[1:])
+	if err != nil {
+		return nil, err
+	}
+	for _, seg := range path.segs {
+		if seg.kind != segField {
+			return nil, fmt.Errorf("filter %q: only field paths are supported after @", body)
+		}
+	}
+	f.path = path.segs
+	return f, nil
+}
+
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("unterminated string %s", s)
+	}
+	if s[0] == '"' {
+		return strconv.Unquote(s)
+	}
+	return strings.ReplaceAll(s[1:len(s)-1], `\'`, "'"), nil
+}
+
+// Eval applies the query to v, a value decoded from JSON.
+func (q *Query) Eval(v any) any {
+	nodes := []any{v}
+	for _, seg := range q.segs {
+		var next []any
+		for _, node := range nodes {
+			next = append(next, seg.apply(node)...)
+		}
+		nodes = next
+	}
+	if q.multi {
+		if nodes == nil {
+			return []any{}
+		}
+		return nodes
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0]
+}
+
+func (seg segment) apply(node any) []any {
+	switch seg.kind {
+	case segField:
+		if obj, ok := node.(map[string]any); ok {
+			if v, ok := obj[seg.name]; ok {
+				return []any{v}
+			}
+		}
+	case segIndex:
+		if arr, ok := node.([]any); ok {
+			i := seg.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				return []any{arr[i]}
+			}
+		}
+	case segSlice:
+		if arr, ok := node.([]any); ok {
+			lo, hi := sliceBound(seg.start, 0, len(arr)), sliceBound(seg.end, len(arr), len(arr))
+			if lo < hi {
+				return append([]any(nil), arr[lo:hi]...)
+			}
+		}
+	case segWildcard:
+		return children(node)
+	case segFilter:
+		var out []any
+		for _, child := range children(node) {
+			if seg.filter.match(child) {
+				out = append(out, child)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func sliceBound(p *int, def, n int) int {
+	if p == nil {
+		return def
+	}
+	i := *p
+	if i < 0 {
+		i += n
+	}
+	return min(max(i, 0), n)
+}
+
+// children are an array's elements or an object's values in key order.
+func children(node any) []any {
+	switch v := node.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]any, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, v[k])
+		}
+		return out
+	}
+	return nil
+}
+
+func (f *filter) match(node any) bool {
+	nodes := []any{node}
+	for _, seg := range f.path {
+		var next []any
+		for _, n := range nodes {
+			next = append(next, seg.apply(n)...)
+		}
+		nodes = next
+	}
+	if len(nodes) == 0 {
+		return false
+	}
+	if f.op == "" {
+		return true
+	}
+	got := nodes[0]
+	if a, ok := number(got); ok {
+		if b, ok := number(f.value); ok {
+			return compare(a < b, a == b, f.op)
+		}
+	}
+	if a, ok := got.(string); ok {
+		if b, ok := f.value.(string); ok {
+			return compare(a < b, a == b, f.op)
+		}
+	}
+	switch f.op {
+	case "==":
+		return scalarText(got) == scalarText(f.value)
+	case "!=":
+		return scalarText(got) != scalarText(f.value)
+	}
+	return false
+}
+
+func compare(less, equal bool, op string) bool {
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	case ">=":
+		return !less
+	}
+	return false
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
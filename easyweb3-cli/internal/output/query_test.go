@@ -0,0 +1,120 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const queryDoc = `{
+	"data": {
+		"items": [
+			{"id": 1, "status": "open", "price": 0.4, "tags": ["a", "b"]},
+			{"id": 2, "status": "filled", "price": 0.55},
+			{"id": 3, "status": "open", "price": 0.7, "note": null}
+		],
+		"odd key": "spaced",
+		"it's": "quoted",
+		"meta": {"total": 3, "next": null}
+	}
+}`
+
+func decodeQueryDoc(t *testing.T) any {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(queryDoc))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return v
+}
+
+func TestQueryEval(t *testing.T) {
+	doc := decodeQueryDoc(t)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"", `{"data":{"it's":"quoted","items":[{"id":1,"price":0.4,"status":"open","tags":["a","b"]},{"id":2,"price":0.55,"status":"filled"},{"id":3,"note":null,"price":0.7,"status":"open"}],"meta":{"next":null,"total":3},"odd key":"spaced"}}`},
+		{"$.data.meta.total", `3`},
+		{"data.meta.total", `3`},
+		{".data.meta", `{"next":null,"total":3}`},
+		{"$['data']['odd key']", `"spaced"`},
+		{`$.data["it's"]`, `"quoted"`},
+		{`$.data['it\'s']`, `"quoted"`},
+		{"$.data.missing", `null`},
+		{"$.data.meta.total.deeper", `null`},
+		{"$.data.items[0].id", `1`},
+		{"$.data.items[-1].id", `3`},
+		{"$.data.items[5]", `null`},
+		{"$.data.items[0].tags[1]", `"b"`},
+		{"$.data.items[*].id", `[1,2,3]`},
+		{"$.data.items.*.status", `["open","filled","open"]`},
+		{"$.data.meta.*", `[null,3]`},
+		{"$.data.items[1:].id", `[2,3]`},
+		{"$.data.items[:2].id", `[1,2]`},
+		{"$.data.items[-2:].id", `[2,3]`},
+		{"$.data.items[2:1]", `[]`},
+		{"$.data.items[:100].id", `[1,2,3]`},
+		{"$.data.missing[*]", `[]`},
+		{"$.data.items[?(@.status == 'open')].id", `[1,3]`},
+		{`$.data.items[?(@.status != "open")].id`, `[2]`},
+		{"$.data.items[?(@.price > 0.5)].id", `[2,3]`},
+		{"$.data.items[?(@.price <= 0.55)].id", `[1,2]`},
+		{"$.data.items[?(@.id >= 2)].id", `[2,3]`},
+		{"$.data.items[?(@.id < 2)].id", `[1]`},
+		{"$.data.items[?(@.tags)].id", `[1]`},
+		{"$.data.items[?(@.note == null)].id", `[3]`},
+		{"$.data.items[?(@.status < 'g')].id", `[2]`},
+		{"$.data.items[?(@.id == '1')].id", `[1]`},
+	}
+	for _, tc := range cases {
+		q, err := ParseQuery(tc.query)
+		if err != nil {
+			t.Errorf("%q: %v", tc.query, err)
+			continue
+		}
+		got, err := json.Marshal(q.Eval(doc))
+		if err != nil {
+			t.Errorf("%q: marshal: %v", tc.query, err)
+			continue
+		}
+		if !bytes.Equal(got, []byte(tc.want)) {
+			t.Errorf("%q = %s, want %s", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestParseQueryRejectsMalformed(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"$.data[0", "unclosed ["},
+		{"$.data['x]", "unclosed ["},
+		{"$.data..items", "empty field name"},
+		{"$.data.", "empty field name"},
+		{"$.data[]", "bad index"},
+		{"$.data[x]", "bad index"},
+		{"$.data[1:y]", "bad slice"},
+		{"$.data['x'1]", "unterminated string"},
+		{"$data", "unexpected"},
+		{"$.data[?(status == 1)]", "must start with @"},
+		{"$.data[?(@.status == open)]", "bad value"},
+		{"$.data[?(@.* == 1)]", "only field paths"},
+		{"$.data[?(@.a == 'x)]", "unclosed ["},
+		{"$.data[?(@.a == 'x'y)]", "unterminated string"},
+	}
+	for _, tc := range cases {
+		_, err := ParseQuery(tc.query)
+		if err == nil {
+			t.Errorf("%q: parsed, want error containing %q", tc.query, tc.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%q: error %q, want it to contain %q", tc.query, err, tc.want)
+		}
+	}
+}
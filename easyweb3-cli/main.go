@@ -17,6 +17,9 @@ func main() {
 		token   = flag.String("token", "", "Bearer token (env: EASYWEB3_TOKEN)")
 		outFmt  = flag.String("output", "json", "Output format: json|text|markdown")
 		project = flag.String("project", "", "Project id (env: EASYWEB3_PROJECT)")
+		profile = flag.String("profile", "", "Named config profile (env: EASYWEB3_PROFILE)")
+		query   = flag.String("query", "", "JSONPath subset applied to the response, e.g. $.data[*].id")
+		columns = flag.String("columns", "", "Comma-separated fields to print as table columns")
 	)
 	flag.Parse()
 
@@ -26,39 +29,95 @@ func main() {
 		os.Exit(2)
 	}
 
+	if name := profileName(*profile); name != "" {
+		if err := config.UseProfile(name); err != nil {
+			fmt.Fprintln(os.Stderr, "config error:", err)
+			os.Exit(1)
+		}
+	}
+
 	cfg, err := config.LoadConfig()
-	if err != nil {
+	// The profile command must keep working to repair a broken current profile.
+	if err != nil && args[0] != "profile" {
 		fmt.Fprintln(os.Stderr, "config error:", err)
 		os.Exit(1)
 	}
 
-	if strings.TrimSpace(*apiBase) != "" {
-		cfg.APIBase = strings.TrimRight(strings.TrimSpace(*apiBase), "/")
+	applyFlags(&cfg, *apiBase, *project)
+
+	// Bad queries fail before any request is sent.
+	if _, err := output.ParseQuery(*query); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
-	if strings.TrimSpace(*project) != "" {
-		cfg.Project = strings.TrimSpace(*project)
+	var cols []string
+	for _, col := range strings.Split(*columns, ",") {
+		if col = strings.TrimSpace(col); col != "" {
+			if _, err := output.ParseQuery(col); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			cols = append(cols, col)
+		}
 	}
 
 	ctx := cmd.Context{
 		APIBase: cfg.APIBase,
 		Project: cfg.Project,
-		Output:  output.Format(strings.TrimSpace(*outFmt)),
+		Output: output.Options{
+			Format:  output.Format(strings.TrimSpace(*outFmt)),
+			Query:   strings.TrimSpace(*query),
+			Columns: cols,
+		},
+		Profile: cfg.Profile,
 	}
 
-	// Token resolution order:
-	// 1) flag --token
-	// 2) env EASYWEB3_TOKEN
-	// 3) credentials file
-	if strings.TrimSpace(*token) != "" {
-		ctx.Token = strings.TrimSpace(*token)
-	} else if v := strings.TrimSpace(os.Getenv("EASYWEB3_TOKEN")); v != "" {
-		ctx.Token = v
-	} else if cred, err := config.LoadCredentials(); err == nil {
-		ctx.Token = strings.TrimSpace(cred.Token)
+	// Announce named profiles on stderr so stdout stays machine-readable and
+	// nobody hits prod thinking they are on staging.
+	if ctx.Profile != "" && args[0] != "profile" {
+		fmt.Fprintf(os.Stderr, "profile: %s (%s)\n", ctx.Profile, ctx.APIBase)
 	}
 
+	ctx.Token = resolveToken(*token)
+
 	if err := cmd.Dispatch(ctx, args); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 }
+
+// profileName is --profile, else EASYWEB3_PROFILE; "" leaves the choice to
+// current_profile in config.json.
+func profileName(flagValue string) string {
+	if v := strings.TrimSpace(flagValue); v != "" {
+		return v
+	}
+	return strings.TrimSpace(os.Getenv("EASYWEB3_PROFILE"))
+}
+
+// applyFlags lets --api-base and --project win over the profile and env.
+func applyFlags(cfg *config.Config, apiBase, project string) {
+	if strings.TrimSpace(apiBase) != "" {
+		cfg.APIBase = strings.TrimRight(strings.TrimSpace(apiBase), "/")
+	}
+	if strings.TrimSpace(project) != "" {
+		cfg.Project = strings.TrimSpace(project)
+	}
+}
+
+// resolveToken picks the bearer token in this order:
+// 1) flag --token
+// 2) env EASYWEB3_TOKEN
+// 3) the active profile's credentials file
+func resolveToken(flagValue string) string {
+	if v := strings.TrimSpace(flagValue); v != "" {
+		return v
+	}
+	if v := strings.TrimSpace(os.Getenv("EASYWEB3_TOKEN")); v != "" {
+		return v
+	}
+	if cred, err := config.LoadCredentials(); err == nil {
+		return strings.TrimSpace(cred.Token)
+	}
+	return ""
+}
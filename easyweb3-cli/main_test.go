@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nicekwell/easyweb3-cli/internal/config"
+)
+
+func TestFlagsOverrideEnvAndProfile(t *testing.T) {
+	t.Setenv("EASYWEB3_DIR", t.TempDir())
+	t.Setenv("EASYWEB3_API_BASE", "https://env.example")
+	t.Setenv("EASYWEB3_PROJECT", "")
+	t.Setenv("EASYWEB3_PROFILE", "staging")
+	t.Cleanup(func() { _ = config.UseProfile("") })
+
+	f := config.File{Profiles: map[string]config.Config{
+		"staging": {APIBase: "https://staging.example", Project: "stage"},
+		"prod":    {APIBase: "https://prod.example", Project: "live"},
+	}}
+	if err := config.SaveFile(f); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cases := []struct {
+		name, profileFlag, apiBaseFlag, projectFlag string
+		profile, apiBase, project                   string
+	}{
+		{name: "env profile, env api base", profile: "staging", apiBase: "https://env.example", project: "stage"},
+		{name: "profile flag beats env", profileFlag: "prod", profile: "prod", apiBase: "https://env.example", project: "live"},
+		{name: "flags beat env and profile", profileFlag: "prod", apiBaseFlag: "https://flag.example/", projectFlag: "p1", profile: "prod", apiBase: "https://flag.example", project: "p1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := config.UseProfile(profileName(tc.profileFlag)); err != nil {
+				t.Fatalf("use: %v", err)
+			}
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			applyFlags(&cfg, tc.apiBaseFlag, tc.projectFlag)
+			if cfg.Profile != tc.profile || cfg.APIBase != tc.apiBase || cfg.Project != tc.project {
+				t.Fatalf("config = %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestResolveTokenPrecedence(t *testing.T) {
+	t.Setenv("EASYWEB3_DIR", t.TempDir())
+	t.Setenv("EASYWEB3_TOKEN", "")
+	t.Cleanup(func() { _ = config.UseProfile("") })
+	if err := config.UseProfile("prod"); err != nil {
+		t.Fatalf("use: %v", err)
+	}
+	if got := resolveToken(""); got != "" {
+		t.Fatalf("no token anywhere = %q", got)
+	}
+	if err := config.SaveCredentials(config.Credentials{Token: "file-token"}); err != nil {
+		t.Fatalf("save credentials: %v", err)
+	}
+	if got := resolveToken(""); got != "file-token" {
+		t.Fatalf("credentials file token = %q", got)
+	}
+	t.Setenv("EASYWEB3_TOKEN", "env-token")
+	if got := resolveToken(""); got != "env-token" {
+		t.Fatalf("env token = %q", got)
+	}
+	if got := resolveToken(" flag-token "); got != "flag-token" {
+		t.Fatalf("flag token = %q", got)
+	}
+
+	// Another profile does not see prod's credentials.
+	t.Setenv("EASYWEB3_TOKEN", "")
+	if err := config.UseProfile("staging"); err != nil {
+		t.Fatalf("use: %v", err)
+	}
+	if got := resolveToken(""); got != "" {
+		t.Fatalf("staging token = %q", got)
+	}
+}
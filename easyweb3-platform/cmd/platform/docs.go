@@ -0,0 +1,14 @@
+package main
+
+//go:generate swag init --dir ../.. -g cmd/platform/docs.go -o ../../docs --outputTypes json
+
+// @title           easyweb3 Platform API
+// @version         0.1.0
+// @description     Auth, logs, notify, cache, integrations and the service proxy of the easyweb3 gateway.
+// @host            localhost:8080
+// @BasePath        /
+// @schemes         http
+// @securityDefinitions.apikey BearerAuth
+// @in              header
+// @name            Authorization
+// @description     "Bearer " followed by a token from /api/v1/auth/login.
@@ -9,6 +9,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/nicekwell/easyweb3-platform/docs"
 	"github.com/nicekwell/easyweb3-platform/internal/auth"
 	"github.com/nicekwell/easyweb3-platform/internal/cache"
 	"github.com/nicekwell/easyweb3-platform/internal/config"
@@ -36,6 +37,11 @@ func main() {
 	if err := us.Load(); err != nil {
 		log.Fatalf("user store: %v", err)
 	}
+	ds := auth.NewFileDelegationStore(cfg.DelegationsFile)
+	if err := ds.Load(); err != nil {
+		log.Fatalf("delegation store: %v", err)
+	}
+	delegationPolicy := auth.DelegationPolicy{MaxTTL: cfg.DelegationMaxTTL, DeniedPaths: cfg.DelegationDeniedPaths}
 
 	logsStore := logging.NewFileStore(cfg.LogsFile)
 	logsHandler := &logging.Handler{Store: logsStore}
@@ -84,8 +90,19 @@ func main() {
 
 	proxy := gateway.NewProxy(cfg.Services)
 
-	authHandler := auth.Handler{Keys: ks, Users: us, JWT: jwt}
+	authHandler := auth.Handler{Keys: ks, Users: us, JWT: jwt, Delegations: ds, DelegationPolicy: delegationPolicy}
 	serviceHandler := service.Handler{Services: cfg.Services}
+	statusMonitor := &service.Monitor{
+		Services: cfg.Services,
+		Cache:    cacheStore,
+		Interval: cfg.StatusInterval,
+		History:  cfg.StatusHistory,
+	}
+	if project := cfg.StatusNotifyProject; project != "" {
+		statusMonitor.Notify = func(ctx context.Context, event, message string) error {
+			return notifyHandler.Emit(ctx, project, event, message)
+		}
+	}
 
 	router := gateway.Router{
 		Auth:         authHandler,
@@ -94,9 +111,13 @@ func main() {
 		Integrations: integrationHandler,
 		Cache:        cacheHandler,
 		Service:      serviceHandler,
+		Status:       statusMonitor,
 		Proxy:        proxy,
-		Docs:         publicdocs.Handler{Dir: cfg.DocsDir},
-		AuthMW:       auth.Middleware(jwt),
+		Docs:         publicdocs.Handler{Dir: cfg.DocsDir, OpenAPI: docs.SwaggerJSON},
+		AuthMW:       auth.Middleware(jwt, ds),
+
+		PublicPolymarketReads: cfg.PublicPolymarketReads,
+		DelegationPolicy:      delegationPolicy,
 	}
 
 	srv := &http.Server{
@@ -105,6 +126,10 @@ func main() {
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	defer stopMonitor()
+	go func() { _ = statusMonitor.Run(monitorCtx) }()
+
 	go func() {
 		log.Printf("easyweb3-platform listening on %s", cfg.ListenAddr)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
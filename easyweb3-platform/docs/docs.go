@@ -0,0 +1,9 @@
+// Package docs embeds the gateway's swagger spec, generated from the
+// handler annotations by `go generate ./cmd/platform`. Regenerate it with
+// every route or payload change; clients are checked against it.
+package docs
+
+import _ "embed"
+
+//go:embed swagger.json
+var SwaggerJSON []byte
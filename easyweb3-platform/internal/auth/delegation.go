@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RoleDelegate is the role of delegated read-only tokens. It is not one of
+// viewer/agent/admin, so a delegated token only passes the service proxy, and
+// only for GET/HEAD on the paths it was minted for.
+const RoleDelegate = "delegate"
+
+// Delegation is a revocable, time-limited grant to read some paths of one
+// business service, e.g. a polymarket dashboard's analytics and positions.
+// The token itself is a JWT carrying the delegation ID and its scopes; only
+// the record is stored, so a lost token cannot be shown again.
+type Delegation struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	ProjectID string     `json:"project_id"`
+	Service   string     `json:"service"`
+	Paths     []string   `json:"paths"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the delegation is neither revoked nor expired at now.
+func (d Delegation) Active(now time.Time) bool {
+	return d.RevokedAt == nil && now.Before(d.ExpiresAt)
+}
+
+// Scopes are the token permissions of the delegation: "service:path" for each
+// path prefix.
+func (d Delegation) Scopes() []string {
+	out := make([]string, 0, len(d.Paths))
+	for _, p := range d.Paths {
+		out = append(out, d.Service+":"+p)
+	}
+	return out
+}
+
+// ScopeAllows reports whether one of scopes covers a read of path on service.
+// A scope path matches itself and anything below it, never a longer sibling
+// ("/api/v2/analytics" does not cover "/api/v2/analytics-export").
+func ScopeAllows(scopes []string, service, path string) bool {
+	for _, scope := range scopes {
+		svc, prefix, ok := strings.Cut(scope, ":")
+		if !ok || svc != service {
+			continue
+		}
+		if PathUnder(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// PathUnder reports whether path is prefix or a sub-path of it.
+func PathUnder(path, prefix string) bool {
+	prefix = strings.TrimRight(prefix, "/")
+	if prefix == "" {
+		return true
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// CheckDelegated reports why claims of a delegated token may no longer be
+// used: no store to look the delegation up in, or a delegation that is
+// missing, revoked or expired. Other roles always pass.
+func CheckDelegated(store *FileDelegationStore, c Claims, now time.Time) error {
+	if c.Role != RoleDelegate {
+		return nil
+	}
+	if store == nil {
+		return errors.New("delegated tokens not enabled")
+	}
+	d, ok := store.Get(c.ID)
+	if !ok || !d.Active(now) {
+		return errors.New("delegation revoked or expired")
+	}
+	return nil
+}
+
+// DelegationRequest describes a delegation to mint.
+type DelegationRequest struct {
+	Name      string
+	ProjectID string
+	Service   string
+	Paths     []string
+	TTL       time.Duration
+	CreatedBy string
+}
+
+// DelegationPolicy bounds what may be delegated: MaxTTL caps the lifetime
+// (0 = no cap) and DeniedPaths can never be delegated or read.
+type DelegationPolicy struct {
+	MaxTTL      time.Duration
+	DeniedPaths []string
+}
+
+// Denied reports whether path falls under a denied prefix. Reads are checked
+// with it, so a denied sub-path does not block its parent's other children.
+func (p DelegationPolicy) Denied(path string) bool {
+	for _, denied := range p.DeniedPaths {
+		if PathUnder(path, denied) {
+			return true
+		}
+	}
+	return false
+}
+
+// Delegable reports whether a delegation may be minted for path: it must not
+// be denied, nor be a parent of a denied prefix.
+func (p DelegationPolicy) Delegable(path string) bool {
+	for _, denied := range p.DeniedPaths {
+		if PathUnder(path, denied) || PathUnder(denied, path) {
+			return false
+		}
+	}
+	return true
+}
+
+// FileDelegationStore keeps delegations in a JSON file, like FileKeyStore.
+type FileDelegationStore struct {
+	path string
+
+	mu    sync.RWMutex
+	items []Delegation
+}
+
+func NewFileDelegationStore(path string) *FileDelegationStore {
+	return &FileDelegationStore{path: path}
+}
+
+func (s *FileDelegationStore) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			s.items = nil
+			return nil
+		}
+		return err
+	}
+	var items []Delegation
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	s.items = items
+	return nil
+}
+
+func (s *FileDelegationStore) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(s.items, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, b, 0o600)
+}
+
+// Create validates req against policy and stores the delegation. A path
+// covering a denied prefix is refused, as is a TTL over the cap.
+func (s *FileDelegationStore) Create(req DelegationRequest, policy DelegationPolicy) (Delegation, error) {
+	projectID := strings.TrimSpace(req.ProjectID)
+	service := strings.TrimSpace(req.Service)
+	if projectID == "" {
+		return Delegation{}, errors.New("project_id required")
+	}
+	if service == "" {
+		return Delegation{}, errors.New("service required")
+	}
+	if req.TTL <= 0 {
+		return Delegation{}, errors.New("ttl must be positive")
+	}
+	if policy.MaxTTL > 0 && req.TTL > policy.MaxTTL {
+		return Delegation{}, fmt.Errorf("ttl exceeds %s", policy.MaxTTL)
+	}
+	var paths []string
+	for _, p := range req.Paths {
+		p = strings.TrimRight(strings.TrimSpace(p), "/")
+		if p == "" {
+			continue
+		}
+		if !strings.HasPrefix(p, "/") || strings.Contains(p, "..") {
+			return Delegation{}, fmt.Errorf("invalid path %q", p)
+		}
+		if !policy.Delegable(p) {
+			return Delegation{}, fmt.Errorf("path %q cannot be delegated", p)
+		}
+		paths = append(paths, p)
+	}
+	if len(paths) == 0 {
+		return Delegation{}, errors.New("at least one path required")
+	}
+
+	now := time.Now().UTC()
+	d := Delegation{
+		ID:        fmt.Sprintf("dlg_%d", now.UnixNano()),
+		Name:      strings.TrimSpace(req.Name),
+		ProjectID: projectID,
+		Service:   service,
+		Paths:     paths,
+		CreatedBy: strings.TrimSpace(req.CreatedBy),
+		CreatedAt: now,
+		ExpiresAt: now.Add(req.TTL),
+	}
+	s.mu.Lock()
+	s.items = append(s.items, d)
+	s.mu.Unlock()
+	if err := s.Save(); err != nil {
+		return Delegation{}, err
+	}
+	return d, nil
+}
+
+// List returns all delegations, newest first.
+func (s *FileDelegationStore) List() []Delegation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Delegation, 0, len(s.items))
+	for i := len(s.items) - 1; i >= 0; i-- {
+		out = append(out, s.items[i])
+	}
+	return out
+}
+
+// Get returns the delegation with id.
+func (s *FileDelegationStore) Get(id string) (Delegation, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, d := range s.items {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return Delegation{}, false
+}
+
+// Revoke marks the delegation revoked. Revoking twice keeps the first time.
+func (s *FileDelegationStore) Revoke(id string) (Delegation, bool, error) {
+	id = strings.TrimSpace(id)
+	now := time.Now().UTC()
+	s.mu.Lock()
+	var out Delegation
+	found := false
+	for i := range s.items {
+		if s.items[i].ID != id {
+			continue
+		}
+		if s.items[i].RevokedAt == nil {
+			s.items[i].RevokedAt = &now
+		}
+		out, found = s.items[i], true
+		break
+	}
+	s.mu.Unlock()
+	if !found {
+		return Delegation{}, false, nil
+	}
+	return out, true, s.Save()
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPathUnder(t *testing.T) {
+	cases := []struct {
+		path, prefix string
+		want         bool
+	}{
+		{"/api/v2/analytics", "/api/v2/analytics", true},
+		{"/api/v2/analytics/overview", "/api/v2/analytics", true},
+		{"/api/v2/analytics/overview", "/api/v2/analytics/", true},
+		{"/api/v2/analytics-export", "/api/v2/analytics", false},
+		{"/api/v2/ana", "/api/v2/analytics", false},
+		{"/anything", "/", true},
+	}
+	for _, tc := range cases {
+		if got := PathUnder(tc.path, tc.prefix); got != tc.want {
+			t.Errorf("PathUnder(%q, %q) = %v, want %v", tc.path, tc.prefix, got, tc.want)
+		}
+	}
+}
+
+func TestScopeAllows(t *testing.T) {
+	scopes := []string{"polymarket:/api/v2/analytics", "polymarket:/api/v2/positions"}
+	cases := []struct {
+		service, path string
+		want          bool
+	}{
+		{"polymarket", "/api/v2/analytics/overview", true},
+		{"polymarket", "/api/v2/positions", true},
+		{"polymarket", "/api/v2/analytics-export", false},
+		{"polymarket", "/api/v2/settings", false},
+		{"other", "/api/v2/analytics", false},
+	}
+	for _, tc := range cases {
+		if got := ScopeAllows(scopes, tc.service, tc.path); got != tc.want {
+			t.Errorf("ScopeAllows(%s, %s) = %v, want %v", tc.service, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestDelegationPolicy(t *testing.T) {
+	p := DelegationPolicy{DeniedPaths: []string{"/api/v2/settings"}}
+	cases := []struct {
+		path              string
+		denied, delegable bool
+	}{
+		{"/api/v2/settings", true, false},
+		{"/api/v2/settings/trading", true, false},
+		// A parent may be read, but delegating it would cover the denied path.
+		{"/api/v2", false, false},
+		{"/api/v2/settingsx", false, true},
+		{"/api/v2/analytics", false, true},
+	}
+	for _, tc := range cases {
+		if got := p.Denied(tc.path); got != tc.denied {
+			t.Errorf("Denied(%q) = %v, want %v", tc.path, got, tc.denied)
+		}
+		if got := p.Delegable(tc.path); got != tc.delegable {
+			t.Errorf("Delegable(%q) = %v, want %v", tc.path, got, tc.delegable)
+		}
+	}
+}
+
+func TestFileDelegationStoreCreateAndRevoke(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "delegations.json")
+	s := NewFileDelegationStore(path)
+	policy := DelegationPolicy{MaxTTL: 24 * time.Hour, DeniedPaths: []string{"/api/v2/settings"}}
+	req := DelegationRequest{ProjectID: "p1", Service: "polymarket", Paths: []string{"/api/v2/analytics/"}, TTL: time.Hour}
+
+	for name, bad := range map[string]DelegationRequest{
+		"no project":  {Service: "polymarket", Paths: req.Paths, TTL: time.Hour},
+		"no service":  {ProjectID: "p1", Paths: req.Paths, TTL: time.Hour},
+		"no ttl":      {ProjectID: "p1", Service: "polymarket", Paths: req.Paths},
+		"ttl too big": {ProjectID: "p1", Service: "polymarket", Paths: req.Paths, TTL: 48 * time.Hour},
+		"no paths":    {ProjectID: "p1", Service: "polymarket", TTL: time.Hour},
+		"relative":    {ProjectID: "p1", Service: "polymarket", Paths: []string{"api/v2"}, TTL: time.Hour},
+		"dotdot":      {ProjectID: "p1", Service: "polymarket", Paths: []string{"/api/../v2"}, TTL: time.Hour},
+		"denied":      {ProjectID: "p1", Service: "polymarket", Paths: []string{"/api/v2/settings/x"}, TTL: time.Hour},
+	} {
+		if _, err := s.Create(bad, policy); err == nil {
+			t.Errorf("%s: created", name)
+		}
+	}
+
+	d, err := s.Create(req, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Paths) != 1 || d.Paths[0] != "/api/v2/analytics" || !d.Active(time.Now().UTC()) {
+		t.Fatalf("delegation = %+v", d)
+	}
+	claims := Claims{Role: RoleDelegate}
+	claims.ID = d.ID
+	if err := CheckDelegated(s, claims, time.Now().UTC()); err != nil {
+		t.Fatalf("active delegation rejected: %v", err)
+	}
+	if err := CheckDelegated(s, claims, d.ExpiresAt); err == nil {
+		t.Fatal("expired delegation accepted")
+	}
+	if err := CheckDelegated(nil, claims, time.Now().UTC()); err == nil {
+		t.Fatal("delegated token accepted without a store")
+	}
+
+	revoked, found, err := s.Revoke(d.ID)
+	if err != nil || !found || revoked.RevokedAt == nil {
+		t.Fatalf("revoke = %+v, %v, %v", revoked, found, err)
+	}
+	if err := CheckDelegated(s, claims, time.Now().UTC()); err == nil {
+		t.Fatal("revoked delegation accepted")
+	}
+	if _, found, _ := s.Revoke("dlg_missing"); found {
+		t.Fatal("revoked a missing delegation")
+	}
+
+	// Revocation survives a reload.
+	reloaded := NewFileDelegationStore(path)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := reloaded.Get(d.ID); !ok || got.RevokedAt == nil {
+		t.Fatalf("reloaded = %+v, %v", got, ok)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+
 	"github.com/nicekwell/easyweb3-platform/internal/httpx"
 )
 
@@ -12,6 +14,9 @@ type Handler struct {
 	Keys  *FileKeyStore
 	Users *FileUserStore
 	JWT   JWT
+
+	Delegations      *FileDelegationStore
+	DelegationPolicy DelegationPolicy
 }
 
 type loginRequest struct {
@@ -26,6 +31,16 @@ type tokenResponse struct {
 	ExpiresAt string `json:"expires_at"`
 }
 
+// @Summary Exchange an API key or username/password for a JWT
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body loginRequest true "api_key, or username + password (+ project_id)"
+// @Success 200 {object} tokenResponse
+// @Failure 400 {object} httpx.ErrorResponse
+// @Failure 401 {object} httpx.ErrorResponse
+// @Failure 403 {object} httpx.ErrorResponse
+// @Router /api/v1/auth/login [post]
 func (h Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req loginRequest
 	if err := httpx.ReadJSON(r, &req, 1<<20); err != nil {
@@ -84,6 +99,13 @@ func (h Handler) Login(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// @Summary Issue a fresh JWT for the current token
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} tokenResponse
+// @Failure 401 {object} httpx.ErrorResponse
+// @Router /api/v1/auth/refresh [post]
 func (h Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 	// Refresh requires a valid Bearer token.
 	c, ok := ClaimsFromContext(r.Context())
@@ -91,6 +113,11 @@ func (h Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 		httpx.WriteError(w, http.StatusUnauthorized, "missing token")
 		return
 	}
+	// A delegated token lives exactly as long as its delegation.
+	if c.Role == RoleDelegate {
+		httpx.WriteError(w, http.StatusForbidden, "delegated tokens cannot be refreshed")
+		return
+	}
 
 	tok, exp, err := h.JWT.Sign(Claims{
 		ProjectID: c.ProjectID,
@@ -111,8 +138,15 @@ type statusResponse struct {
 	Project       string `json:"project,omitempty"`
 	Role          string `json:"role,omitempty"`
 	ExpiresAt     string `json:"expires_at,omitempty"`
+	// Scopes are the "service:path" prefixes a delegated token may read.
+	Scopes []string `json:"scopes,omitempty"`
 }
 
+// @Summary Describe the bearer token, if any
+// @Tags auth
+// @Produce json
+// @Success 200 {object} statusResponse
+// @Router /api/v1/auth/status [get]
 func (h Handler) Status(w http.ResponseWriter, r *http.Request) {
 	// Public endpoint: missing/invalid token just means authenticated=false.
 	tok := bearerTokenLocal(r.Header.Get("Authorization"))
@@ -125,11 +159,20 @@ func (h Handler) Status(w http.ResponseWriter, r *http.Request) {
 		httpx.WriteJSON(w, http.StatusOK, statusResponse{Authenticated: false})
 		return
 	}
+	// Services verify tokens here, so a revoked delegation must read as
+	// unauthenticated even though its JWT is still well signed.
+	if CheckDelegated(h.Delegations, c, time.Now().UTC()) != nil {
+		httpx.WriteJSON(w, http.StatusOK, statusResponse{Authenticated: false})
+		return
+	}
 	resp := statusResponse{
 		Authenticated: true,
 		Project:       c.ProjectID,
 		Role:          c.Role,
 	}
+	if c.Role == RoleDelegate {
+		resp.Scopes = c.Permissions
+	}
 	if c.ExpiresAt != nil {
 		resp.ExpiresAt = c.ExpiresAt.Time.UTC().Format(time.RFC3339)
 	}
@@ -147,6 +190,16 @@ type createKeyResponse struct {
 	Key    any    `json:"key"`
 }
 
+// @Summary Create an API key (admin)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body createKeyRequest true "key"
+// @Success 200 {object} createKeyResponse
+// @Failure 400 {object} httpx.ErrorResponse
+// @Failure 403 {object} httpx.ErrorResponse
+// @Router /api/v1/auth/keys [post]
 func (h Handler) CreateKey(w http.ResponseWriter, r *http.Request) {
 	c, ok := ClaimsFromContext(r.Context())
 	if !ok || c.Role != "admin" {
@@ -183,6 +236,14 @@ type registerResponse struct {
 	User any `json:"user"`
 }
 
+// @Summary Register a user without grants
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body registerRequest true "user"
+// @Success 200 {object} registerResponse
+// @Failure 400 {object} httpx.ErrorResponse
+// @Router /api/v1/auth/register [post]
 func (h Handler) Register(w http.ResponseWriter, r *http.Request) {
 	if h.Users == nil {
 		httpx.WriteError(w, http.StatusBadRequest, "registration not enabled")
@@ -215,6 +276,16 @@ type grantRequest struct {
 	Role      string `json:"role"`
 }
 
+// @Summary Grant a user a role on a project (agent or admin)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body grantRequest true "grant"
+// @Success 200 {object} map[string]any
+// @Failure 400 {object} httpx.ErrorResponse
+// @Failure 403 {object} httpx.ErrorResponse
+// @Router /api/v1/auth/grants [post]
 func (h Handler) Grant(w http.ResponseWriter, r *http.Request) {
 	c, ok := ClaimsFromContext(r.Context())
 	if !ok || (c.Role != "admin" && c.Role != "agent") {
@@ -244,6 +315,13 @@ func (h Handler) Grant(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// @Summary List users (agent or admin)
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]any
+// @Failure 403 {object} httpx.ErrorResponse
+// @Router /api/v1/auth/users [get]
 func (h Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	c, ok := ClaimsFromContext(r.Context())
 	if !ok || (c.Role != "admin" && c.Role != "agent") {
@@ -268,6 +346,151 @@ func (h Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	httpx.WriteJSON(w, http.StatusOK, map[string]any{"users": out})
 }
 
+type createDelegationRequest struct {
+	Name      string   `json:"name"`
+	ProjectID string   `json:"project_id"`
+	Service   string   `json:"service"`
+	Paths     []string `json:"paths"`
+	// TTL is a Go duration, e.g. "72h".
+	TTL string `json:"ttl"`
+}
+
+type createDelegationResponse struct {
+	Token      string     `json:"token"`
+	ExpiresAt  string     `json:"expires_at"`
+	Delegation Delegation `json:"delegation"`
+}
+
+type listDelegationsResponse struct {
+	Delegations []delegationItem `json:"delegations"`
+}
+
+type delegationItem struct {
+	Delegation
+	Active bool `json:"active"`
+}
+
+// @Summary Mint a delegated read-only token (admin)
+// @Description The token reads only GET/HEAD paths under the given prefixes of one service, until it expires or is revoked. Settings and other denied paths cannot be delegated.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body createDelegationRequest true "delegation"
+// @Success 200 {object} createDelegationResponse
+// @Failure 400 {object} httpx.ErrorResponse
+// @Failure 403 {object} httpx.ErrorResponse
+// @Router /api/v1/auth/delegations [post]
+func (h Handler) CreateDelegation(w http.ResponseWriter, r *http.Request) {
+	c, ok := ClaimsFromContext(r.Context())
+	if !ok || c.Role != "admin" {
+		httpx.WriteError(w, http.StatusForbidden, "admin required")
+		return
+	}
+	if h.Delegations == nil {
+		httpx.WriteError(w, http.StatusBadRequest, "delegations not enabled")
+		return
+	}
+	var req createDelegationRequest
+	if err := httpx.ReadJSON(r, &req, 1<<20); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ttl, err := time.ParseDuration(strings.TrimSpace(req.TTL))
+	if err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, "invalid ttl")
+		return
+	}
+	d, err := h.Delegations.Create(DelegationRequest{
+		Name:      req.Name,
+		ProjectID: req.ProjectID,
+		Service:   req.Service,
+		Paths:     req.Paths,
+		TTL:       ttl,
+		CreatedBy: c.ProjectID,
+	}, h.DelegationPolicy)
+	if err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims := Claims{
+		ProjectID:   d.ProjectID,
+		Role:        RoleDelegate,
+		Permissions: d.Scopes(),
+	}
+	claims.ID = d.ID
+	claims.Subject = d.Name
+	claims.ExpiresAt = jwt.NewNumericDate(d.ExpiresAt)
+	tok, exp, err := h.JWT.Sign(claims)
+	if err != nil {
+		httpx.WriteError(w, http.StatusInternalServerError, "failed to sign token")
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, createDelegationResponse{
+		Token:      tok,
+		ExpiresAt:  exp.UTC().Format(time.RFC3339),
+		Delegation: d,
+	})
+}
+
+// @Summary List delegated tokens (admin)
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} listDelegationsResponse
+// @Failure 403 {object} httpx.ErrorResponse
+// @Router /api/v1/auth/delegations [get]
+func (h Handler) ListDelegations(w http.ResponseWriter, r *http.Request) {
+	c, ok := ClaimsFromContext(r.Context())
+	if !ok || c.Role != "admin" {
+		httpx.WriteError(w, http.StatusForbidden, "admin required")
+		return
+	}
+	if h.Delegations == nil {
+		httpx.WriteError(w, http.StatusBadRequest, "delegations not enabled")
+		return
+	}
+	now := time.Now().UTC()
+	items := h.Delegations.List()
+	out := make([]delegationItem, 0, len(items))
+	for _, d := range items {
+		out = append(out, delegationItem{Delegation: d, Active: d.Active(now)})
+	}
+	httpx.WriteJSON(w, http.StatusOK, listDelegationsResponse{Delegations: out})
+}
+
+// @Summary Revoke a delegated token (admin)
+// @Description Takes effect on the token's next request.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "delegation id"
+// @Success 200 {object} Delegation
+// @Failure 403 {object} httpx.ErrorResponse
+// @Failure 404 {object} httpx.ErrorResponse
+// @Router /api/v1/auth/delegations/{id} [delete]
+func (h Handler) RevokeDelegation(w http.ResponseWriter, r *http.Request, id string) {
+	c, ok := ClaimsFromContext(r.Context())
+	if !ok || c.Role != "admin" {
+		httpx.WriteError(w, http.StatusForbidden, "admin required")
+		return
+	}
+	if h.Delegations == nil {
+		httpx.WriteError(w, http.StatusBadRequest, "delegations not enabled")
+		return
+	}
+	d, found, err := h.Delegations.Revoke(id)
+	if err != nil {
+		httpx.WriteError(w, http.StatusInternalServerError, "failed to persist revocation")
+		return
+	}
+	if !found {
+		httpx.WriteError(w, http.StatusNotFound, "delegation not found")
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, d)
+}
+
 func bearerTokenLocal(v string) string {
 	v = strings.TrimSpace(v)
 	if v == "" {
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newDelegationHandler(t *testing.T) Handler {
+	t.Helper()
+	return Handler{
+		JWT:              JWT{Secret: []byte("test-secret"), TokenTTL: time.Hour},
+		Delegations:      NewFileDelegationStore(filepath.Join(t.TempDir(), "delegations.json")),
+		DelegationPolicy: DelegationPolicy{MaxTTL: 72 * time.Hour, DeniedPaths: []string{"/api/v2/settings"}},
+	}
+}
+
+func asRole(r *http.Request, role string) *http.Request {
+	return r.WithContext(WithClaims(r.Context(), Claims{ProjectID: "p1", Role: role}))
+}
+
+func mintDelegation(t *testing.T, h Handler, role, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := asRole(httptest.NewRequest(http.MethodPost, "/api/v1/auth/delegations", strings.NewReader(body)), role)
+	w := httptest.NewRecorder()
+	h.CreateDelegation(w, r)
+	return w
+}
+
+func authStatus(t *testing.T, h Handler, token string) statusResponse {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/auth/status", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	h.Status(w, r)
+	var out statusResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestDelegationsAreAdminOnly(t *testing.T) {
+	h := newDelegationHandler(t)
+	body := `{"project_id":"p1","service":"polymarket","paths":["/api/v2/analytics"],"ttl":"1h"}`
+	for _, role := range []string{"viewer", "agent", RoleDelegate} {
+		if w := mintDelegation(t, h, role, body); w.Code != http.StatusForbidden {
+			t.Errorf("%s mint = %d", role, w.Code)
+		}
+		w := httptest.NewRecorder()
+		h.ListDelegations(w, asRole(httptest.NewRequest(http.MethodGet, "/api/v1/auth/delegations", nil), role))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s list = %d", role, w.Code)
+		}
+		w = httptest.NewRecorder()
+		h.RevokeDelegation(w, asRole(httptest.NewRequest(http.MethodDelete, "/", nil), role), "dlg_1")
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s revoke = %d", role, w.Code)
+		}
+	}
+	if len(h.Delegations.List()) != 0 {
+		t.Fatal("non-admin minted a delegation")
+	}
+}
+
+func TestDelegationMintRejectsDeniedPathsAndTTL(t *testing.T) {
+	h := newDelegationHandler(t)
+	for _, body := range []string{
+		`{"project_id":"p1","service":"polymarket","paths":["/api/v2/settings"],"ttl":"1h"}`,
+		`{"project_id":"p1","service":"polymarket","paths":["/api/v2"],"ttl":"1h"}`,
+		`{"project_id":"p1","service":"polymarket","paths":["/api/v2/analytics"],"ttl":"100h"}`,
+		`{"project_id":"p1","service":"polymarket","paths":["/api/v2/analytics"],"ttl":"soon"}`,
+	} {
+		if w := mintDelegation(t, h, "admin", body); w.Code != http.StatusBadRequest {
+			t.Errorf("mint %s = %d", body, w.Code)
+		}
+	}
+}
+
+func TestDelegatedTokenLifecycle(t *testing.T) {
+	h := newDelegationHandler(t)
+	w := mintDelegation(t, h, "admin", `{"name":"dash","project_id":"p1","service":"polymarket","paths":["/api/v2/analytics"],"ttl":"1h"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("mint = %d %s", w.Code, w.Body.String())
+	}
+	var minted createDelegationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &minted); err != nil {
+		t.Fatal(err)
+	}
+
+	st := authStatus(t, h, minted.Token)
+	if !st.Authenticated || st.Role != RoleDelegate || len(st.Scopes) != 1 || st.Scopes[0] != "polymarket:/api/v2/analytics" {
+		t.Fatalf("status = %+v", st)
+	}
+
+	w = httptest.NewRecorder()
+	h.ListDelegations(w, asRole(httptest.NewRequest(http.MethodGet, "/api/v1/auth/delegations", nil), "admin"))
+	var listed listDelegationsResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &listed)
+	if len(listed.Delegations) != 1 || !listed.Delegations[0].Active || listed.Delegations[0].ID != minted.Delegation.ID {
+		t.Fatalf("list = %+v", listed)
+	}
+
+	reached := false
+	protected := Middleware(h.JWT, h.Delegations)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	call := func() int {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+minted.Token)
+		w := httptest.NewRecorder()
+		protected.ServeHTTP(w, r)
+		return w.Code
+	}
+	if code := call(); code != http.StatusOK || !reached {
+		t.Fatalf("middleware before revoke = %d", code)
+	}
+
+	w = httptest.NewRecorder()
+	h.RevokeDelegation(w, asRole(httptest.NewRequest(http.MethodDelete, "/", nil), "admin"), minted.Delegation.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("revoke = %d", w.Code)
+	}
+	reached = false
+	if code := call(); code != http.StatusUnauthorized || reached {
+		t.Fatalf("middleware after revoke = %d", code)
+	}
+	if st := authStatus(t, h, minted.Token); st.Authenticated {
+		t.Fatalf("status after revoke = %+v", st)
+	}
+
+	w = httptest.NewRecorder()
+	h.RevokeDelegation(w, asRole(httptest.NewRequest(http.MethodDelete, "/", nil), "admin"), "dlg_missing")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("revoke missing = %d", w.Code)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/nicekwell/easyweb3-platform/internal/httpx"
 )
@@ -21,7 +22,10 @@ func WithClaims(ctx context.Context, c Claims) context.Context {
 	return context.WithValue(ctx, claimsKey, c)
 }
 
-func Middleware(jwt JWT) func(http.Handler) http.Handler {
+// Middleware verifies the bearer token. Delegated tokens must also name a
+// delegation in delegations that has not been revoked; with a nil store they
+// are refused.
+func Middleware(jwt JWT, delegations *FileDelegationStore) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			tok := bearerToken(r.Header.Get("Authorization"))
@@ -34,6 +38,10 @@ func Middleware(jwt JWT) func(http.Handler) http.Handler {
 				httpx.WriteError(w, http.StatusUnauthorized, "invalid token")
 				return
 			}
+			if err := CheckDelegated(delegations, claims, time.Now().UTC()); err != nil {
+				httpx.WriteError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
 			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
 		})
 	}
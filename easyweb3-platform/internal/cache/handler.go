@@ -24,12 +24,24 @@ type putRequest struct {
 	TTLSeconds  int64  `json:"ttl_seconds"`
 }
 
+type okResponse struct {
+	OK bool `json:"ok"`
+}
+
 type getResponse struct {
 	Key         string `json:"key"`
 	Found       bool   `json:"found"`
 	ValueBase64 string `json:"value_base64,omitempty"`
 }
 
+// @Summary Read a cache key
+// @Tags cache
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "key"
+// @Success 200 {object} getResponse
+// @Failure 503 {object} httpx.ErrorResponse
+// @Router /api/v1/cache/{key} [get]
 func (h Handler) Get(w http.ResponseWriter, r *http.Request, key string) {
 	if _, ok := auth.ClaimsFromContext(r.Context()); !ok {
 		httpx.WriteError(w, http.StatusUnauthorized, "missing token")
@@ -52,6 +64,16 @@ func (h Handler) Get(w http.ResponseWriter, r *http.Request, key string) {
 	httpx.WriteJSON(w, http.StatusOK, resp)
 }
 
+// @Summary Write a cache key
+// @Tags cache
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "key"
+// @Param body body putRequest true "value or value_base64; ttl_seconds < 0 never expires"
+// @Success 200 {object} okResponse
+// @Failure 400 {object} httpx.ErrorResponse
+// @Router /api/v1/cache/{key} [put]
 func (h Handler) Put(w http.ResponseWriter, r *http.Request, key string) {
 	if _, ok := auth.ClaimsFromContext(r.Context()); !ok {
 		httpx.WriteError(w, http.StatusUnauthorized, "missing token")
@@ -101,9 +123,16 @@ func (h Handler) Put(w http.ResponseWriter, r *http.Request, key string) {
 		httpx.WriteError(w, http.StatusInternalServerError, "cache set failed")
 		return
 	}
-	httpx.WriteJSON(w, http.StatusOK, map[string]any{"ok": true})
+	httpx.WriteJSON(w, http.StatusOK, okResponse{OK: true})
 }
 
+// @Summary Delete a cache key
+// @Tags cache
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "key"
+// @Success 200 {object} okResponse
+// @Router /api/v1/cache/{key} [delete]
 func (h Handler) Delete(w http.ResponseWriter, r *http.Request, key string) {
 	if _, ok := auth.ClaimsFromContext(r.Context()); !ok {
 		httpx.WriteError(w, http.StatusUnauthorized, "missing token")
@@ -117,5 +146,5 @@ func (h Handler) Delete(w http.ResponseWriter, r *http.Request, key string) {
 		httpx.WriteError(w, http.StatusInternalServerError, "cache delete failed")
 		return
 	}
-	httpx.WriteJSON(w, http.StatusOK, map[string]any{"ok": true})
+	httpx.WriteJSON(w, http.StatusOK, okResponse{OK: true})
 }
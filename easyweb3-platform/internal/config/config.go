@@ -18,6 +18,35 @@ type ServiceConfig struct {
 	HealthPath string `json:"health_path"`
 	// DocsPath is appended to BaseURL when fetching docs (optional).
 	DocsPath string `json:"docs_path"`
+	// Transforms are applied in order to proxied requests whose upstream path
+	// matches (optional). They let the gateway shim backend API changes.
+	Transforms []TransformRule `json:"transforms"`
+}
+
+// TransformRule rewrites a proxied request and/or its JSON response.
+// Field paths are dotted (e.g. "data.items.*.secret"); "*" matches every
+// array element or object key at that level.
+type TransformRule struct {
+	// PathPrefix matches the upstream path (after /api/v1/services/{name})
+	// and its sub-paths, by whole segments. Empty matches every path.
+	PathPrefix string `json:"path_prefix"`
+	// Methods limits the rule to these HTTP methods. Empty matches all.
+	Methods []string `json:"methods"`
+
+	// SetHeaders are injected into the upstream request.
+	SetHeaders map[string]string `json:"set_headers"`
+	// RemoveHeaders are stripped from the upstream request.
+	RemoveHeaders []string `json:"remove_headers"`
+	// RewritePrefix replaces PathPrefix in the upstream path when set.
+	RewritePrefix string `json:"rewrite_prefix"`
+
+	// SetResponseHeaders are set on the response to the client.
+	SetResponseHeaders map[string]string `json:"set_response_headers"`
+	// RedactFields replaces matching JSON response fields with "[redacted]".
+	RedactFields []string `json:"redact_fields"`
+	// RenameFields maps a field path to a new key name for the last segment,
+	// e.g. {"data.*.pnl": "realized_pnl"}.
+	RenameFields map[string]string `json:"rename_fields"`
 }
 
 type Config struct {
@@ -25,11 +54,12 @@ type Config struct {
 	JWTSecret  []byte
 	TokenTTL   time.Duration
 
-	APIKeysFile string
-	UsersFile   string
-	LogsFile    string
-	NotifyFile  string
-	DocsDir     string
+	APIKeysFile     string
+	UsersFile       string
+	DelegationsFile string
+	LogsFile        string
+	NotifyFile      string
+	DocsDir         string
 
 	DexscreenerBaseURL string
 	GoPlusBaseURL      string
@@ -40,28 +70,53 @@ type Config struct {
 	RedisPassword      string
 	RedisDB            int
 
+	// StatusInterval is how often the status monitor probes each service.
+	StatusInterval time.Duration
+	// StatusHistory is the number of probes kept per service.
+	StatusHistory int
+	// StatusNotifyProject names the project whose notify config receives
+	// service.status_changed events. Empty disables notifications.
+	StatusNotifyProject string
+
+	// DelegationMaxTTL caps the lifetime of delegated read-only tokens.
+	DelegationMaxTTL time.Duration
+	// DelegationDeniedPaths are upstream path prefixes a delegated token can
+	// never read, whatever it was minted for (e.g. settings).
+	DelegationDeniedPaths []string
+	// PublicPolymarketReads lets polymarket GETs through without a token
+	// (early rollout). Turn it off to make delegated tokens the only way to
+	// share read access.
+	PublicPolymarketReads bool
+
 	Services map[string]ServiceConfig
 }
 
 func Load() (Config, error) {
 	cfg := Config{
-		ListenAddr:         getenv("EASYWEB3_LISTEN", ":8080"),
-		JWTSecret:          []byte(getenv("EASYWEB3_JWT_SECRET", "dev-secret-change-me")),
-		TokenTTL:           mustDuration(getenv("EASYWEB3_TOKEN_TTL", "24h")),
-		APIKeysFile:        getenv("EASYWEB3_API_KEYS_FILE", "./data/api_keys.json"),
-		UsersFile:          getenv("EASYWEB3_USERS_FILE", "./data/users.json"),
-		LogsFile:           getenv("EASYWEB3_LOGS_FILE", "./data/logs.jsonl"),
-		NotifyFile:         getenv("EASYWEB3_NOTIFY_FILE", "./data/notify_config.json"),
-		DocsDir:            strings.TrimSpace(getenv("EASYWEB3_DOCS_DIR", "")),
-		DexscreenerBaseURL: getenv("EASYWEB3_DEXSCREENER_BASE_URL", "https://api.dexscreener.com"),
-		GoPlusBaseURL:      getenv("EASYWEB3_GOPLUS_BASE_URL", "https://api.gopluslabs.io"),
-		GoPlusAPIKey:       getenv("EASYWEB3_GOPLUS_API_KEY", ""),
-		CacheBackend:       strings.ToLower(strings.TrimSpace(getenv("EASYWEB3_CACHE_BACKEND", "memory"))),
-		CacheDefaultTTL:    mustDuration(getenv("EASYWEB3_CACHE_DEFAULT_TTL", "30s")),
-		RedisAddr:          strings.TrimSpace(getenv("EASYWEB3_REDIS_ADDR", "")),
-		RedisPassword:      getenv("EASYWEB3_REDIS_PASSWORD", ""),
-		RedisDB:            mustInt(getenv("EASYWEB3_REDIS_DB", "0"), 0),
-		Services:           map[string]ServiceConfig{},
+		ListenAddr:            getenv("EASYWEB3_LISTEN", ":8080"),
+		JWTSecret:             []byte(getenv("EASYWEB3_JWT_SECRET", "dev-secret-change-me")),
+		TokenTTL:              mustDuration(getenv("EASYWEB3_TOKEN_TTL", "24h")),
+		APIKeysFile:           getenv("EASYWEB3_API_KEYS_FILE", "./data/api_keys.json"),
+		UsersFile:             getenv("EASYWEB3_USERS_FILE", "./data/users.json"),
+		DelegationsFile:       getenv("EASYWEB3_DELEGATIONS_FILE", "./data/delegations.json"),
+		LogsFile:              getenv("EASYWEB3_LOGS_FILE", "./data/logs.jsonl"),
+		NotifyFile:            getenv("EASYWEB3_NOTIFY_FILE", "./data/notify_config.json"),
+		DocsDir:               strings.TrimSpace(getenv("EASYWEB3_DOCS_DIR", "")),
+		DexscreenerBaseURL:    getenv("EASYWEB3_DEXSCREENER_BASE_URL", "https://api.dexscreener.com"),
+		GoPlusBaseURL:         getenv("EASYWEB3_GOPLUS_BASE_URL", "https://api.gopluslabs.io"),
+		GoPlusAPIKey:          getenv("EASYWEB3_GOPLUS_API_KEY", ""),
+		CacheBackend:          strings.ToLower(strings.TrimSpace(getenv("EASYWEB3_CACHE_BACKEND", "memory"))),
+		CacheDefaultTTL:       mustDuration(getenv("EASYWEB3_CACHE_DEFAULT_TTL", "30s")),
+		RedisAddr:             strings.TrimSpace(getenv("EASYWEB3_REDIS_ADDR", "")),
+		RedisPassword:         getenv("EASYWEB3_REDIS_PASSWORD", ""),
+		RedisDB:               mustInt(getenv("EASYWEB3_REDIS_DB", "0"), 0),
+		StatusInterval:        mustDuration(getenv("EASYWEB3_STATUS_INTERVAL", "30s")),
+		StatusHistory:         mustInt(getenv("EASYWEB3_STATUS_HISTORY", "120"), 120),
+		StatusNotifyProject:   strings.TrimSpace(getenv("EASYWEB3_STATUS_NOTIFY_PROJECT", "")),
+		DelegationMaxTTL:      mustDuration(getenv("EASYWEB3_DELEGATION_MAX_TTL", "720h")),
+		DelegationDeniedPaths: splitList(getenv("EASYWEB3_DELEGATION_DENIED_PATHS", "/api/v2/system-settings,/api/v2/system,/api/v2/audit")),
+		PublicPolymarketReads: getenv("EASYWEB3_PUBLIC_POLYMARKET_READS", "true") != "false",
+		Services:              map[string]ServiceConfig{},
 	}
 
 	if len(cfg.JWTSecret) < 16 {
@@ -126,9 +181,33 @@ func normalizeService(sc ServiceConfig) ServiceConfig {
 	if sc.DocsPath != "" && !strings.HasPrefix(sc.DocsPath, "/") {
 		sc.DocsPath = "/" + sc.DocsPath
 	}
+	for i := range sc.Transforms {
+		t := &sc.Transforms[i]
+		t.PathPrefix = strings.TrimSpace(t.PathPrefix)
+		if t.PathPrefix != "" && !strings.HasPrefix(t.PathPrefix, "/") {
+			t.PathPrefix = "/" + t.PathPrefix
+		}
+		t.RewritePrefix = strings.TrimSpace(t.RewritePrefix)
+		if t.RewritePrefix != "" && !strings.HasPrefix(t.RewritePrefix, "/") {
+			t.RewritePrefix = "/" + t.RewritePrefix
+		}
+		for j, m := range t.Methods {
+			t.Methods[j] = strings.ToUpper(strings.TrimSpace(m))
+		}
+	}
 	return sc
 }
 
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 func getenv(k, def string) string {
 	if v := os.Getenv(k); v != "" {
 		return v
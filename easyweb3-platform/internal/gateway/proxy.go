@@ -29,6 +29,23 @@ func NewProxy(services map[string]config.ServiceConfig) *Proxy {
 	return &Proxy{services: m, proxies: map[string]*httputil.ReverseProxy{}}
 }
 
+// ServeHTTP forwards /api/v1/services/{name}/{path} to the service's
+// base_url with the caller's identity in X-Easyweb3-* headers.
+//
+// @Summary Proxy to a business service
+// @Description Reads need viewer, writes agent or admin. The upstream response is passed through; each service documents its own routes (see /api/v1/service/docs).
+// @Tags proxy
+// @Security BearerAuth
+// @Param name path string true "service name"
+// @Param path path string true "upstream path"
+// @Success 200 {object} map[string]any
+// @Failure 404 {object} httpx.ErrorResponse
+// @Failure 502 {object} httpx.ErrorResponse
+// @Router /api/v1/services/{name}/{path} [get]
+// @Router /api/v1/services/{name}/{path} [post]
+// @Router /api/v1/services/{name}/{path} [put]
+// @Router /api/v1/services/{name}/{path} [patch]
+// @Router /api/v1/services/{name}/{path} [delete]
 func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	name, rest, ok := parseServicePath(r.URL.Path)
 	if !ok {
@@ -53,28 +70,38 @@ func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		r.URL.Path = "/"
 	}
 
+	// Identity headers come only from verified claims. Drop whatever the
+	// client sent so a caller cannot pick another project or role.
+	r.Header.Del("X-Easyweb3-Project")
+	r.Header.Del("X-Easyweb3-Role")
+	claims, hasClaims := auth.ClaimsFromContext(r.Context())
+
 	// Temporary: public read for polymarket query endpoints.
 	// The polymarket backend expects a Bearer token presence for /api/* routes,
 	// but does not validate the token itself (it relies on gateway validation).
 	// For public GET/HEAD reads, inject a minimal "viewer" context so upstream can serve data.
-	if name == "polymarket" && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+	publicRead := false
+	if name == "polymarket" && !hasClaims && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
 		if r.URL.Path == "/healthz" || strings.HasPrefix(r.URL.Path, "/api/v2/") || strings.HasPrefix(r.URL.Path, "/api/catalog/") {
+			publicRead = true
 			if strings.TrimSpace(r.Header.Get("Authorization")) == "" {
 				r.Header.Set("Authorization", "Bearer public")
 			}
-			if strings.TrimSpace(r.Header.Get("X-Easyweb3-Project")) == "" {
-				r.Header.Set("X-Easyweb3-Project", "polymarket")
-			}
-			if strings.TrimSpace(r.Header.Get("X-Easyweb3-Role")) == "" {
-				r.Header.Set("X-Easyweb3-Role", "viewer")
-			}
 		}
 	}
 
-	// Inject some context headers for debugging / future use.
-	if c, ok := auth.ClaimsFromContext(r.Context()); ok {
-		r.Header.Set("X-Easyweb3-Project", c.ProjectID)
-		r.Header.Set("X-Easyweb3-Role", c.Role)
+	// Per-route transformation rules from the services config. Identity headers
+	// below are set afterwards so rules cannot spoof them.
+	r = applyRequestTransforms(r, matchTransforms(cfg.Transforms, r.Method, r.URL.Path))
+
+	r.Header.Del("X-Easyweb3-Project")
+	r.Header.Del("X-Easyweb3-Role")
+	if hasClaims {
+		r.Header.Set("X-Easyweb3-Project", claims.ProjectID)
+		r.Header.Set("X-Easyweb3-Role", claims.Role)
+	} else if publicRead {
+		r.Header.Set("X-Easyweb3-Project", "polymarket")
+		r.Header.Set("X-Easyweb3-Role", "viewer")
 	}
 
 	proxy.ServeHTTP(w, r)
@@ -100,6 +127,7 @@ func (p *Proxy) getProxy(name string, cfg config.ServiceConfig) (*httputil.Rever
 		// Keep the upstream host as Host header.
 		req.Host = u.Host
 	}
+	rp.ModifyResponse = transformResponse
 
 	p.mu.Lock()
 	p.proxies[name] = rp
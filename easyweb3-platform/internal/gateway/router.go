@@ -21,20 +21,49 @@ type Router struct {
 	Integrations integration.Handler
 	Cache        cache.Handler
 	Service      service.Handler
+	Status       *service.Monitor
 	Proxy        *Proxy
 	Docs         publicdocs.Handler
 
 	AuthMW func(http.Handler) http.Handler
+
+	// PublicPolymarketReads keeps polymarket query endpoints readable without
+	// a token. Turn it off to require a viewer or delegated token.
+	PublicPolymarketReads bool
+	// DelegationPolicy lists paths delegated tokens can never read, whatever
+	// their scopes say.
+	DelegationPolicy auth.DelegationPolicy
 }
 
 func (rt Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Correlation ID: keep a well-formed client ID, otherwise assign one. The
+	// proxy forwards the header so backends log under the same ID.
+	if id := strings.TrimSpace(r.Header.Get(httpx.HeaderRequestID)); !httpx.ValidRequestID(id) {
+		r.Header.Set(httpx.HeaderRequestID, httpx.NewRequestID())
+	}
+	w.Header().Set(httpx.HeaderRequestID, r.Header.Get(httpx.HeaderRequestID))
+
 	// Health.
 	if r.Method == http.MethodGet && r.URL.Path == "/healthz" {
-		httpx.WriteJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+		healthz(w, r)
+		return
+	}
+
+	// Public service status (no auth).
+	if r.URL.Path == "/status" && rt.Status != nil {
+		if r.Method != http.MethodGet {
+			httpx.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		rt.Status.Status(w, r)
 		return
 	}
 
 	// Public docs (no auth).
+	if r.URL.Path == "/docs/openapi.json" {
+		rt.Docs.OpenAPISpec(w, r)
+		return
+	}
 	if r.URL.Path == "/docs" || r.URL.Path == "/docs/" {
 		rt.Docs.Index(w, r)
 		return
@@ -102,6 +131,32 @@ func (rt Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		rt.requireAuth(http.HandlerFunc(rt.Auth.ListUsers)).ServeHTTP(w, r)
 		return
 	}
+	if r.URL.Path == "/api/v1/auth/delegations" {
+		switch r.Method {
+		case http.MethodPost:
+			rt.requireAuth(http.HandlerFunc(rt.Auth.CreateDelegation)).ServeHTTP(w, r)
+		case http.MethodGet:
+			rt.requireAuth(http.HandlerFunc(rt.Auth.ListDelegations)).ServeHTTP(w, r)
+		default:
+			httpx.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, "/api/v1/auth/delegations/") {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/auth/delegations/"), "/")
+		if id == "" || strings.Contains(id, "/") {
+			httpx.WriteError(w, http.StatusNotFound, "not found")
+			return
+		}
+		if r.Method != http.MethodDelete {
+			httpx.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		rt.requireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rt.Auth.RevokeDelegation(w, r, id)
+		})).ServeHTTP(w, r)
+		return
+	}
 
 	// Logging.
 	if r.URL.Path == "/api/v1/logs" {
@@ -125,6 +180,21 @@ func (rt Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		rt.requireAuth(rt.requireRole(http.HandlerFunc(rt.Logs.Stats), "viewer", "agent", "admin")).ServeHTTP(w, r)
 		return
 	}
+	if strings.HasPrefix(r.URL.Path, "/api/v1/logs/trace/") {
+		if r.Method != http.MethodGet {
+			httpx.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/api/v1/logs/trace/"))
+		if !httpx.ValidRequestID(id) {
+			httpx.WriteError(w, http.StatusBadRequest, "invalid correlation id")
+			return
+		}
+		rt.requireAuth(rt.requireRole(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rt.Logs.Trace(w, r, id)
+		}), "viewer", "agent", "admin")).ServeHTTP(w, r)
+		return
+	}
 	if strings.HasPrefix(r.URL.Path, "/api/v1/logs/") {
 		if r.Method != http.MethodGet {
 			httpx.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -266,7 +336,7 @@ func (rt Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		// so the web UI can be opened without login during early rollout.
 		//
 		// Note: write methods still require agent/admin. Other services remain protected.
-		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		if rt.PublicPolymarketReads && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
 			if name, rest, ok := parseServicePath(r.URL.Path); ok && name == "polymarket" {
 				if rest == "/healthz" || strings.HasPrefix(rest, "/api/v2/") || strings.HasPrefix(rest, "/api/catalog/") {
 					rt.Proxy.ServeHTTP(w, r)
@@ -275,9 +345,10 @@ func (rt Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		// Viewer can only read. Agent/admin can write.
+		// Viewer can only read. Agent/admin can write. Delegated tokens read
+		// only within their scopes.
 		if r.Method == http.MethodGet || r.Method == http.MethodHead {
-			rt.requireAuth(rt.requireRole(rt.Proxy, "viewer", "agent", "admin")).ServeHTTP(w, r)
+			rt.requireAuth(rt.requireServiceRead(rt.Proxy)).ServeHTTP(w, r)
 			return
 		}
 		rt.requireAuth(rt.requireRole(rt.Proxy, "agent", "admin")).ServeHTTP(w, r)
@@ -287,6 +358,15 @@ func (rt Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	httpx.WriteError(w, http.StatusNotFound, "not found")
 }
 
+// @Summary Gateway health check
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func healthz(w http.ResponseWriter, r *http.Request) {
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
 func (rt Router) requireAuth(h http.Handler) http.Handler {
 	if rt.AuthMW == nil {
 		return h
@@ -314,6 +394,25 @@ func (rt Router) requireRole(h http.Handler, roles ...string) http.Handler {
 	})
 }
 
+// requireServiceRead admits viewer/agent/admin, and a delegated token when the
+// requested service path is within its scopes and not denied by policy.
+func (rt Router) requireServiceRead(h http.Handler) http.Handler {
+	byRole := rt.requireRole(h, "viewer", "agent", "admin")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, ok := auth.ClaimsFromContext(r.Context())
+		if !ok || c.Role != auth.RoleDelegate {
+			byRole.ServeHTTP(w, r)
+			return
+		}
+		name, rest, ok := parseServicePath(r.URL.Path)
+		if !ok || rt.DelegationPolicy.Denied(rest) || !auth.ScopeAllows(c.Permissions, name, rest) {
+			httpx.WriteError(w, http.StatusForbidden, "outside delegated scope")
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
 func parseIntegrationProvider(path string) (provider string, ok bool) {
 	// /api/v1/integrations/{provider}/query
 	const prefix = "/api/v1/integrations/"
@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nicekwell/easyweb3-platform/internal/auth"
+	"github.com/nicekwell/easyweb3-platform/internal/config"
+	"github.com/nicekwell/easyweb3-platform/internal/service"
+)
+
+func TestRequireServiceReadScopesDelegatedTokens(t *testing.T) {
+	rt := Router{DelegationPolicy: auth.DelegationPolicy{DeniedPaths: []string{"/api/v2/analytics/private"}}}
+	h := rt.requireServiceRead(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	delegate := auth.Claims{Role: auth.RoleDelegate, Permissions: []string{"polymarket:/api/v2/analytics"}}
+	cases := []struct {
+		name   string
+		claims auth.Claims
+		path   string
+		want   int
+	}{
+		{"delegate in scope", delegate, "/api/v1/services/polymarket/api/v2/analytics/overview", http.StatusNoContent},
+		{"delegate scope root", delegate, "/api/v1/services/polymarket/api/v2/analytics", http.StatusNoContent},
+		{"delegate sibling path", delegate, "/api/v1/services/polymarket/api/v2/analytics-export", http.StatusForbidden},
+		{"delegate out of scope", delegate, "/api/v1/services/polymarket/api/v2/orders", http.StatusForbidden},
+		{"delegate other service", delegate, "/api/v1/services/other/api/v2/analytics", http.StatusForbidden},
+		{"delegate denied path", delegate, "/api/v1/services/polymarket/api/v2/analytics/private/x", http.StatusForbidden},
+		{"viewer", auth.Claims{Role: "viewer"}, "/api/v1/services/polymarket/api/v2/orders", http.StatusNoContent},
+		{"unknown role", auth.Claims{Role: "guest"}, "/api/v1/services/polymarket/api/v2/orders", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		r = r.WithContext(auth.WithClaims(r.Context(), tc.claims))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+}
+
+func TestStatusPageIsPublic(t *testing.T) {
+	rt := Router{
+		Status: &service.Monitor{Services: map[string]config.ServiceConfig{"polymarket": {BaseURL: "http://127.0.0.1:1"}}},
+		AuthMW: func(http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			})
+		},
+	}
+	cases := []struct {
+		method string
+		want   int
+	}{
+		{http.MethodGet, http.StatusOK},
+		{http.MethodPost, http.StatusMethodNotAllowed},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(tc.method, "/status", nil))
+		if w.Code != tc.want {
+			t.Errorf("%s /status: status = %d, want %d", tc.method, w.Code, tc.want)
+		}
+	}
+}
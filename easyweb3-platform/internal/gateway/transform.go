@@ -0,0 +1,262 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nicekwell/easyweb3-platform/internal/auth"
+	"github.com/nicekwell/easyweb3-platform/internal/config"
+)
+
+const redactedValue = "[redacted]"
+
+// Responses larger than this are passed through untransformed.
+const maxTransformBodyBytes = 8 << 20
+
+type transformCtxKey struct{}
+
+// matchTransforms returns the rules that apply to an upstream path. Prefixes
+// match whole segments: "/api/v2/orders" covers "/api/v2/orders/1" but not
+// "/api/v2/orders-export".
+func matchTransforms(rules []config.TransformRule, method string, path string) []config.TransformRule {
+	var out []config.TransformRule
+	for _, rule := range rules {
+		if !auth.PathUnder(path, rule.PathPrefix) {
+			continue
+		}
+		if len(rule.Methods) > 0 && !containsString(rule.Methods, method) {
+			continue
+		}
+		out = append(out, rule)
+	}
+	return out
+}
+
+func containsString(items []string, v string) bool {
+	for _, it := range items {
+		if it == v {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRequestTransforms mutates r before it is proxied and stashes the rules
+// in its context for the response side.
+func applyRequestTransforms(r *http.Request, rules []config.TransformRule) *http.Request {
+	if len(rules) == 0 {
+		return r
+	}
+	needsBody := false
+	for _, rule := range rules {
+		for _, h := range rule.RemoveHeaders {
+			r.Header.Del(h)
+		}
+		for k, v := range rule.SetHeaders {
+			r.Header.Set(k, v)
+		}
+		if rule.RewritePrefix != "" && auth.PathUnder(r.URL.Path, rule.PathPrefix) {
+			r.URL.Path = rewritePrefix(r.URL.Path, rule.PathPrefix, rule.RewritePrefix)
+			r.URL.RawPath = ""
+		}
+		if len(rule.RedactFields) > 0 || len(rule.RenameFields) > 0 {
+			needsBody = true
+		}
+	}
+	if needsBody {
+		// Ask for an uncompressed body so it can be rewritten.
+		r.Header.Del("Accept-Encoding")
+	}
+	return r.WithContext(context.WithValue(r.Context(), transformCtxKey{}, rules))
+}
+
+// rewritePrefix replaces the segments of from at the start of path, which
+// must be under it, with to.
+func rewritePrefix(path, from, to string) string {
+	rest := strings.TrimPrefix(path, strings.TrimRight(from, "/"))
+	out := strings.TrimRight(to, "/") + rest
+	if out == "" {
+		return "/"
+	}
+	return out
+}
+
+// transformResponse is installed as ReverseProxy.ModifyResponse.
+func transformResponse(resp *http.Response) error {
+	if resp == nil || resp.Request == nil {
+		return nil
+	}
+	rules, _ := resp.Request.Context().Value(transformCtxKey{}).([]config.TransformRule)
+	if len(rules) == 0 {
+		return nil
+	}
+	var redact []string
+	rename := map[string]string{}
+	for _, rule := range rules {
+		for k, v := range rule.SetResponseHeaders {
+			resp.Header.Set(k, v)
+		}
+		redact = append(redact, rule.RedactFields...)
+		for from, to := range rule.RenameFields {
+			rename[from] = to
+		}
+	}
+	if len(redact) == 0 && len(rename) == 0 {
+		return nil
+	}
+	if !strings.Contains(strings.ToLower(resp.Header.Get("Content-Type")), "json") {
+		return nil
+	}
+	if enc := strings.TrimSpace(resp.Header.Get("Content-Encoding")); enc != "" && !strings.EqualFold(enc, "identity") {
+		return nil
+	}
+	if resp.ContentLength > maxTransformBodyBytes {
+		return nil
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxTransformBodyBytes+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return err
+	}
+	if len(raw) > maxTransformBodyBytes {
+		// Chunked and too large: stitch the read prefix back onto the rest
+		// of the stream and pass it through untouched.
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(raw), resp.Body), Closer: resp.Body}
+		return nil
+	}
+	_ = resp.Body.Close()
+	out := raw
+	if doc, ok := decodeJSONBody(raw); ok {
+		for _, path := range redact {
+			doc = redactPath(doc, splitFieldPath(path))
+		}
+		for from, to := range rename {
+			doc = renamePath(doc, splitFieldPath(from), strings.TrimSpace(to))
+		}
+		if b, err := encodeJSONBody(doc); err == nil {
+			out = b
+		}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(out))
+	resp.ContentLength = int64(len(out))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(out)))
+	return nil
+}
+
+// decodeJSONBody parses a single JSON document, keeping numbers as their
+// literal text so large IDs and amounts survive the round trip.
+func decodeJSONBody(raw []byte) (any, bool) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, false
+	}
+	return doc, true
+}
+
+// encodeJSONBody writes doc back without escaping HTML characters the
+// upstream sent as is.
+func encodeJSONBody(doc any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func splitFieldPath(path string) []string {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// walkPath calls fn on every object that owns the last path segment.
+func walkPath(v any, segs []string, fn func(obj map[string]any, key string)) {
+	if len(segs) == 0 {
+		return
+	}
+	head, rest := segs[0], segs[1:]
+	switch node := v.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			if head == "*" {
+				// fn may add and delete keys (renames), so walk a copy of
+				// the keys rather than the live map.
+				keys := make([]string, 0, len(node))
+				for k := range node {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					fn(node, k)
+				}
+				return
+			}
+			if _, ok := node[head]; ok {
+				fn(node, head)
+			}
+			return
+		}
+		if head == "*" {
+			for _, child := range node {
+				walkPath(child, rest, fn)
+			}
+			return
+		}
+		if child, ok := node[head]; ok {
+			walkPath(child, rest, fn)
+		}
+	case []any:
+		// Arrays are transparent for "*" and numeric segments.
+		if head == "*" {
+			for _, child := range node {
+				walkPath(child, rest, fn)
+			}
+			return
+		}
+		if idx, err := strconv.Atoi(head); err == nil && idx >= 0 && idx < len(node) {
+			walkPath(node[idx], rest, fn)
+		}
+	}
+}
+
+func redactPath(doc any, segs []string) any {
+	walkPath(doc, segs, func(obj map[string]any, key string) {
+		obj[key] = redactedValue
+	})
+	return doc
+}
+
+func renamePath(doc any, segs []string, to string) any {
+	if to == "" {
+		return doc
+	}
+	walkPath(doc, segs, func(obj map[string]any, key string) {
+		if key == to {
+			return
+		}
+		obj[to] = obj[key]
+		delete(obj, key)
+	})
+	return doc
+}
@@ -0,0 +1,160 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nicekwell/easyweb3-platform/internal/config"
+)
+
+func TestMatchTransformsMatchesWholeSegments(t *testing.T) {
+	rules := []config.TransformRule{
+		{PathPrefix: "/api/v2/orders"},
+		{PathPrefix: "/api/v2/positions/", Methods: []string{http.MethodGet}},
+		{PathPrefix: ""},
+	}
+	cases := []struct {
+		method, path string
+		want         []string
+	}{
+		{http.MethodGet, "/api/v2/orders", []string{"/api/v2/orders", ""}},
+		{http.MethodGet, "/api/v2/orders/1", []string{"/api/v2/orders", ""}},
+		{http.MethodGet, "/api/v2/orders-export", []string{""}},
+		{http.MethodGet, "/api/v2/positions", []string{"/api/v2/positions/", ""}},
+		{http.MethodGet, "/api/v2/positions/9", []string{"/api/v2/positions/", ""}},
+		{http.MethodPost, "/api/v2/positions/9", []string{""}},
+		{http.MethodGet, "/api/v2/positionsx", []string{""}},
+	}
+	for _, tc := range cases {
+		got := matchTransforms(rules, tc.method, tc.path)
+		var prefixes []string
+		for _, r := range got {
+			prefixes = append(prefixes, r.PathPrefix)
+		}
+		if strings.Join(prefixes, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s %s: matched %q, want %q", tc.method, tc.path, prefixes, tc.want)
+		}
+	}
+}
+
+func TestApplyRequestTransformsRewritesWholeSegments(t *testing.T) {
+	cases := []struct {
+		prefix, rewrite, path, want string
+	}{
+		{"/v1", "/v2", "/v1/orders", "/v2/orders"},
+		{"/v1/", "/v2/", "/v1/orders", "/v2/orders"},
+		{"/v1", "/v2", "/v1", "/v2"},
+		{"/v1", "/v2", "/v1x/orders", "/v1x/orders"},
+		{"/legacy", "/", "/legacy/orders", "/orders"},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		r = applyRequestTransforms(r, []config.TransformRule{{PathPrefix: tc.prefix, RewritePrefix: tc.rewrite}})
+		if r.URL.Path != tc.want {
+			t.Errorf("%s -> %s on %s: path = %q, want %q", tc.prefix, tc.rewrite, tc.path, r.URL.Path, tc.want)
+		}
+	}
+}
+
+func TestTransformResponseRewritesJSON(t *testing.T) {
+	cases := []struct {
+		name string
+		rule config.TransformRule
+		body string
+		want string
+	}{
+		{
+			name: "redact nested field in every element",
+			rule: config.TransformRule{RedactFields: []string{"data.*.secret"}},
+			body: `{"data":[{"id":1,"secret":"a"},{"id":2,"secret":"b"}]}`,
+			want: `{"data":[{"id":1,"secret":"[redacted]"},{"id":2,"secret":"[redacted]"}]}`,
+		},
+		{
+			name: "redact missing field is a no-op",
+			rule: config.TransformRule{RedactFields: []string{"data.token"}},
+			body: `{"data":{"id":1}}`,
+			want: `{"data":{"id":1}}`,
+		},
+		{
+			name: "rename by array index",
+			rule: config.TransformRule{RenameFields: map[string]string{"items.0.pnl": "realized_pnl"}},
+			body: `{"items":[{"pnl":1},{"pnl":2}]}`,
+			want: `{"items":[{"realized_pnl":1},{"pnl":2}]}`,
+		},
+		{
+			name: "rename every key under a wildcard",
+			rule: config.TransformRule{RenameFields: map[string]string{"data.*": "x"}},
+			body: `{"data":{"a":1}}`,
+			want: `{"data":{"x":1}}`,
+		},
+		{
+			name: "large integers and decimals keep their text",
+			rule: config.TransformRule{RedactFields: []string{"secret"}},
+			body: `{"id":12345678901234567890,"amount":0.10000000000000000001,"exp":1e400,"secret":"s"}`,
+			want: `{"amount":0.10000000000000000001,"exp":1e400,"id":12345678901234567890,"secret":"[redacted]"}`,
+		},
+		{
+			name: "html characters are not escaped",
+			rule: config.TransformRule{RedactFields: []string{"secret"}},
+			body: `{"note":"a<b&c>","secret":"s"}`,
+			want: `{"note":"a<b&c>","secret":"[redacted]"}`,
+		},
+		{
+			name: "trailing data is passed through",
+			rule: config.TransformRule{RedactFields: []string{"secret"}},
+			body: `{"secret":"s"} {"secret":"t"}`,
+			want: `{"secret":"s"} {"secret":"t"}`,
+		},
+		{
+			name: "invalid json is passed through",
+			rule: config.TransformRule{RedactFields: []string{"secret"}},
+			body: `{"secret":`,
+			want: `{"secret":`,
+		},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/x", nil)
+		req = req.WithContext(context.WithValue(req.Context(), transformCtxKey{}, []config.TransformRule{tc.rule}))
+		resp := &http.Response{
+			Request:       req,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(strings.NewReader(tc.body)),
+			ContentLength: int64(len(tc.body)),
+		}
+		if err := transformResponse(resp); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		got, _ := io.ReadAll(resp.Body)
+		if string(got) != tc.want {
+			t.Errorf("%s: body = %s, want %s", tc.name, got, tc.want)
+		}
+		if resp.ContentLength != int64(len(got)) {
+			t.Errorf("%s: content length = %d, want %d", tc.name, resp.ContentLength, len(got))
+		}
+	}
+}
+
+func TestTransformResponseSkipsNonJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/x", nil)
+	req = req.WithContext(context.WithValue(req.Context(), transformCtxKey{}, []config.TransformRule{{
+		RedactFields:       []string{"secret"},
+		SetResponseHeaders: map[string]string{"X-Shim": "1"},
+	}}))
+	body := `{"secret":"s"}`
+	resp := &http.Response{
+		Request: req,
+		Header:  http.Header{"Content-Type": []string{"text/plain"}},
+		Body:    io.NopCloser(strings.NewReader(body)),
+	}
+	if err := transformResponse(resp); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	if string(got) != body || resp.Header.Get("X-Shim") != "1" {
+		t.Fatalf("body = %s, headers = %v", got, resp.Header)
+	}
+}
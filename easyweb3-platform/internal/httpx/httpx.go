@@ -1,14 +1,41 @@
 package httpx
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 )
 
+// HeaderRequestID carries the correlation ID the gateway assigns to each
+// request and forwards to upstream services.
+const HeaderRequestID = "X-Request-Id"
+
+// NewRequestID returns a random 32-char hex ID.
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// ValidRequestID bounds client-supplied IDs so they are safe to forward and log.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 func WriteJSON(w http.ResponseWriter, status int, v any) {
@@ -20,7 +47,8 @@ func WriteJSON(w http.ResponseWriter, status int, v any) {
 }
 
 func WriteError(w http.ResponseWriter, status int, msg string) {
-	WriteJSON(w, status, ErrorResponse{Error: msg})
+	// The gateway sets the response's X-Request-Id before any handler runs.
+	WriteJSON(w, status, ErrorResponse{Error: msg, RequestID: w.Header().Get(HeaderRequestID)})
 }
 
 func ReadJSON(r *http.Request, dst any, maxBytes int64) error {
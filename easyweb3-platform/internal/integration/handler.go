@@ -23,6 +23,18 @@ type QueryRequest struct {
 	Params map[string]any `json:"params"`
 }
 
+// @Summary Query a data integration
+// @Description The response is the provider's JSON, passed through.
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param provider path string true "dexscreener|goplus|polymarket"
+// @Param body body QueryRequest true "method and params"
+// @Success 200 {object} map[string]any
+// @Failure 404 {object} httpx.ErrorResponse
+// @Failure 502 {object} httpx.ErrorResponse
+// @Router /api/v1/integrations/{provider}/query [post]
 func (h Handler) Query(w http.ResponseWriter, r *http.Request, provider string) {
 	if _, ok := auth.ClaimsFromContext(r.Context()); !ok {
 		httpx.WriteError(w, http.StatusUnauthorized, "missing token")
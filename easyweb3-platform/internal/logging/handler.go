@@ -22,11 +22,33 @@ type createLogRequest struct {
 	Agent      string          `json:"agent"`
 	Action     string          `json:"action"`
 	Level      string          `json:"level"`
-	Details    json.RawMessage `json:"details"`
+	Details    json.RawMessage `json:"details" swaggertype:"object"`
 	SessionKey string          `json:"session_key"`
-	Metadata   json.RawMessage `json:"metadata"`
+	Metadata   json.RawMessage `json:"metadata" swaggertype:"object"`
+	// CorrelationID defaults to the X-Request-Id of the log call itself;
+	// services logging asynchronously pass the ID of the original request.
+	CorrelationID string `json:"correlation_id"`
 }
 
+type createLogResponse struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at"`
+}
+
+type traceResponse struct {
+	CorrelationID string         `json:"correlation_id"`
+	Logs          []OperationLog `json:"logs"`
+}
+
+// @Summary Write an operation log
+// @Tags logs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body createLogRequest true "log"
+// @Success 200 {object} createLogResponse
+// @Failure 400 {object} httpx.ErrorResponse
+// @Router /api/v1/logs [post]
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	c, ok := auth.ClaimsFromContext(r.Context())
 	if !ok {
@@ -53,29 +75,47 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		req.Metadata = json.RawMessage("{}")
 	}
 
+	correlationID := strings.TrimSpace(req.CorrelationID)
+	if correlationID == "" {
+		correlationID = strings.TrimSpace(r.Header.Get(httpx.HeaderRequestID))
+	}
+
 	now := time.Now().UTC()
 	id := NewLogID(now, atomic.AddInt64(&h.seq, 1))
 	l := OperationLog{
-		ID:         id,
-		ProjectID:  c.ProjectID,
-		Agent:      strings.TrimSpace(req.Agent),
-		Action:     strings.TrimSpace(req.Action),
-		Level:      strings.TrimSpace(req.Level),
-		Details:    req.Details,
-		SessionKey: strings.TrimSpace(req.SessionKey),
-		CreatedAt:  now,
-		Metadata:   req.Metadata,
+		ID:            id,
+		ProjectID:     c.ProjectID,
+		Agent:         strings.TrimSpace(req.Agent),
+		Action:        strings.TrimSpace(req.Action),
+		Level:         strings.TrimSpace(req.Level),
+		Details:       req.Details,
+		SessionKey:    strings.TrimSpace(req.SessionKey),
+		CreatedAt:     now,
+		Metadata:      req.Metadata,
+		CorrelationID: correlationID,
 	}
 	if err := h.Store.Create(l); err != nil {
 		httpx.WriteError(w, http.StatusInternalServerError, "failed to store log")
 		return
 	}
-	httpx.WriteJSON(w, http.StatusOK, map[string]any{
-		"id":         l.ID,
-		"created_at": l.CreatedAt.UTC().Format(time.RFC3339),
+	httpx.WriteJSON(w, http.StatusOK, createLogResponse{
+		ID:        l.ID,
+		CreatedAt: l.CreatedAt.UTC().Format(time.RFC3339),
 	})
 }
 
+// @Summary List the project's logs, newest first
+// @Tags logs
+// @Produce json
+// @Security BearerAuth
+// @Param action query string false "action"
+// @Param level query string false "level"
+// @Param correlation_id query string false "correlation id"
+// @Param from query string false "RFC3339"
+// @Param to query string false "RFC3339"
+// @Param limit query int false "limit (default 100)"
+// @Success 200 {array} OperationLog
+// @Router /api/v1/logs [get]
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	c, ok := auth.ClaimsFromContext(r.Context())
 	if !ok {
@@ -85,10 +125,11 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 
 	q := r.URL.Query()
 	flt := ListFilter{
-		ProjectID: c.ProjectID,
-		Action:    strings.TrimSpace(q.Get("action")),
-		Level:     strings.TrimSpace(q.Get("level")),
-		Limit:     atoiDefault(q.Get("limit"), 100),
+		ProjectID:     c.ProjectID,
+		Action:        strings.TrimSpace(q.Get("action")),
+		Level:         strings.TrimSpace(q.Get("level")),
+		Limit:         atoiDefault(q.Get("limit"), 100),
+		CorrelationID: strings.TrimSpace(q.Get("correlation_id")),
 	}
 	if v := strings.TrimSpace(q.Get("from")); v != "" {
 		if t, err := time.Parse(time.RFC3339, v); err == nil {
@@ -109,6 +150,14 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	httpx.WriteJSON(w, http.StatusOK, logs)
 }
 
+// @Summary Get one log
+// @Tags logs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "log id"
+// @Success 200 {object} OperationLog
+// @Failure 404 {object} httpx.ErrorResponse
+// @Router /api/v1/logs/{id} [get]
 func (h *Handler) Get(w http.ResponseWriter, r *http.Request, id string) {
 	c, ok := auth.ClaimsFromContext(r.Context())
 	if !ok {
@@ -127,6 +176,47 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request, id string) {
 	httpx.WriteJSON(w, http.StatusOK, l)
 }
 
+// Trace returns every log of one correlation ID, oldest first. Admins see
+// logs of all projects, so a request can be followed from the gateway into
+// each backend that logged under its own project.
+//
+// @Summary Every log of one correlation ID, oldest first
+// @Tags logs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "correlation id"
+// @Success 200 {object} traceResponse
+// @Failure 400 {object} httpx.ErrorResponse
+// @Router /api/v1/logs/trace/{id} [get]
+func (h *Handler) Trace(w http.ResponseWriter, r *http.Request, correlationID string) {
+	c, ok := auth.ClaimsFromContext(r.Context())
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, "missing token")
+		return
+	}
+	flt := ListFilter{CorrelationID: correlationID, Limit: 1000}
+	if !strings.EqualFold(c.Role, "admin") {
+		flt.ProjectID = c.ProjectID
+	}
+	logs, err := h.Store.List(flt)
+	if err != nil {
+		httpx.WriteError(w, http.StatusInternalServerError, "failed to list logs")
+		return
+	}
+	if logs == nil {
+		logs = []OperationLog{}
+	}
+	httpx.WriteJSON(w, http.StatusOK, traceResponse{CorrelationID: correlationID, Logs: logs})
+}
+
+// @Summary Count the project's logs by action
+// @Tags logs
+// @Produce json
+// @Security BearerAuth
+// @Param action query string false "action"
+// @Param level query string false "level"
+// @Success 200 {object} map[string]int
+// @Router /api/v1/logs/stats [get]
 func (h *Handler) Stats(w http.ResponseWriter, r *http.Request) {
 	c, ok := auth.ClaimsFromContext(r.Context())
 	if !ok {
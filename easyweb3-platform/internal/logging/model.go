@@ -11,17 +11,21 @@ type OperationLog struct {
 	Agent      string          `json:"agent"`
 	Action     string          `json:"action"`
 	Level      string          `json:"level"`
-	Details    json.RawMessage `json:"details"`
+	Details    json.RawMessage `json:"details" swaggertype:"object"`
 	SessionKey string          `json:"session_key"`
 	CreatedAt  time.Time       `json:"created_at"`
-	Metadata   json.RawMessage `json:"metadata"`
+	Metadata   json.RawMessage `json:"metadata" swaggertype:"object"`
+	// CorrelationID is the gateway request ID the log belongs to.
+	CorrelationID string `json:"correlation_id,omitempty"`
 }
 
 type ListFilter struct {
 	ProjectID string
 	Action    string
 	Level     string
-	From      *time.Time
-	To        *time.Time
-	Limit     int
+	// CorrelationID matches exactly when set.
+	CorrelationID string
+	From          *time.Time
+	To            *time.Time
+	Limit         int
 }
@@ -164,6 +164,9 @@ func match(l OperationLog, f ListFilter) bool {
 	if f.Level != "" && l.Level != f.Level {
 		return false
 	}
+	if f.CorrelationID != "" && l.CorrelationID != f.CorrelationID {
+		return false
+	}
 	if f.From != nil && l.CreatedAt.Before(f.From.UTC()) {
 		return false
 	}
@@ -3,6 +3,7 @@ package notification
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -29,6 +30,15 @@ type sendResult struct {
 	Error string `json:"error,omitempty"`
 }
 
+// @Summary Send one message to a channel target
+// @Tags notify
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body sendRequest true "message"
+// @Success 200 {object} sendResult
+// @Failure 400 {object} httpx.ErrorResponse
+// @Router /api/v1/notify/send [post]
 func (h Handler) Send(w http.ResponseWriter, r *http.Request) {
 	c, ok := auth.ClaimsFromContext(r.Context())
 	if !ok {
@@ -87,6 +97,15 @@ type broadcastResponse struct {
 	Items   []broadcastItem `json:"items"`
 }
 
+// @Summary Send a message to every project channel subscribed to the event
+// @Tags notify
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body broadcastRequest true "message"
+// @Success 200 {object} broadcastResponse
+// @Failure 404 {object} httpx.ErrorResponse
+// @Router /api/v1/notify/broadcast [post]
 func (h Handler) Broadcast(w http.ResponseWriter, r *http.Request) {
 	c, ok := auth.ClaimsFromContext(r.Context())
 	if !ok {
@@ -115,9 +134,34 @@ func (h Handler) Broadcast(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 8*time.Second)
 	defer cancel()
 
-	resp := broadcastResponse{Project: c.ProjectID, Event: req.Event}
+	resp := broadcastResponse{Project: c.ProjectID, Event: req.Event, Items: h.broadcast(ctx, c.ProjectID, cfg, req.Event, req.Message)}
+	httpx.WriteJSON(w, http.StatusOK, resp)
+}
+
+// Emit broadcasts message to the channels of project subscribed to event.
+// It is used by platform-internal producers such as the service status
+// monitor; a project without notify config is a no-op.
+func (h Handler) Emit(ctx context.Context, project, event, message string) error {
+	if h.Store == nil {
+		return nil
+	}
+	cfg, ok := h.Store.Get(project)
+	if !ok {
+		return nil
+	}
+	var errs []error
+	for _, item := range h.broadcast(ctx, project, cfg, event, message) {
+		if !item.OK {
+			errs = append(errs, fmt.Errorf("%s %s: %s", item.Channel, item.Target, item.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h Handler) broadcast(ctx context.Context, project string, cfg ProjectConfig, event, message string) []broadcastItem {
+	items := []broadcastItem{}
 	for _, ch := range cfg.Channels {
-		if !eventMatch(ch.Events, req.Event) {
+		if !eventMatch(ch.Events, event) {
 			continue
 		}
 		var target string
@@ -127,21 +171,27 @@ func (h Handler) Broadcast(w http.ResponseWriter, r *http.Request) {
 		case "telegram":
 			target = strings.TrimSpace(ch.ChatID)
 		default:
-			resp.Items = append(resp.Items, broadcastItem{Channel: ch.Type, Target: "", OK: false, Error: "unsupported channel"})
+			items = append(items, broadcastItem{Channel: ch.Type, Target: "", OK: false, Error: "unsupported channel"})
 			continue
 		}
 
-		err := h.sendOne(ctx, c.ProjectID, ch.Type, target, req.Message, req.Event, &ch)
+		err := h.sendOne(ctx, project, ch.Type, target, message, event, &ch)
 		if err != nil {
-			resp.Items = append(resp.Items, broadcastItem{Channel: ch.Type, Target: target, OK: false, Error: err.Error()})
+			items = append(items, broadcastItem{Channel: ch.Type, Target: target, OK: false, Error: err.Error()})
 			continue
 		}
-		resp.Items = append(resp.Items, broadcastItem{Channel: ch.Type, Target: target, OK: true})
+		items = append(items, broadcastItem{Channel: ch.Type, Target: target, OK: true})
 	}
-
-	httpx.WriteJSON(w, http.StatusOK, resp)
+	return items
 }
 
+// @Summary Get the project's notify channels
+// @Tags notify
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} ProjectConfig
+// @Failure 404 {object} httpx.ErrorResponse
+// @Router /api/v1/notify/config [get]
 func (h Handler) GetConfig(w http.ResponseWriter, r *http.Request) {
 	c, ok := auth.ClaimsFromContext(r.Context())
 	if !ok {
@@ -156,6 +206,14 @@ func (h Handler) GetConfig(w http.ResponseWriter, r *http.Request) {
 	httpx.WriteJSON(w, http.StatusOK, cfg)
 }
 
+// @Summary Replace the project's notify channels
+// @Tags notify
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body ProjectConfig true "config"
+// @Success 200 {object} ProjectConfig
+// @Router /api/v1/notify/config [put]
 func (h Handler) PutConfig(w http.ResponseWriter, r *http.Request) {
 	c, ok := auth.ClaimsFromContext(r.Context())
 	if !ok {
@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestEmitBroadcastsToSubscribedChannels(t *testing.T) {
+	var mu sync.Mutex
+	var got []WebhookPayload
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p WebhookPayload
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		got = append(got, p)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hook.Close()
+
+	store := NewFileStore(filepath.Join(t.TempDir(), "notify.json"))
+	if err := store.Put("ops", ProjectConfig{Project: "ops", Channels: []ChannelConfig{
+		{Type: "webhook", URL: hook.URL, Events: []string{"service.status_changed"}},
+		{Type: "webhook", URL: hook.URL, Events: []string{"trade.filled"}},
+		{Type: "webhook", URL: hook.URL},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	h := Handler{Store: store}
+	ctx := context.Background()
+
+	if err := h.Emit(ctx, "ops", "service.status_changed", "service api is down (was up)"); err != nil {
+		t.Fatal(err)
+	}
+	// The subscribed channel and the catch-all one receive it; the other does not.
+	if len(got) != 2 {
+		t.Fatalf("deliveries=%+v", got)
+	}
+	for _, p := range got {
+		if p.Project != "ops" || p.Event != "service.status_changed" || p.Message != "service api is down (was up)" {
+			t.Fatalf("payload=%+v", p)
+		}
+	}
+
+	// A project without notify config is a no-op.
+	if err := h.Emit(ctx, "other", "service.status_changed", "x"); err != nil || len(got) != 2 {
+		t.Fatalf("unconfigured project: err=%v deliveries=%d", err, len(got))
+	}
+	if err := (Handler{}).Emit(ctx, "ops", "service.status_changed", "x"); err != nil {
+		t.Fatalf("no store: %v", err)
+	}
+}
+
+func TestEmitJoinsChannelErrors(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "notify.json"))
+	if err := store.Put("ops", ProjectConfig{Project: "ops", Channels: []ChannelConfig{
+		{Type: "webhook"},
+		{Type: "telegram", ChatID: "42"},
+		{Type: "sms"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	err := Handler{Store: store}.Emit(context.Background(), "ops", "service.status_changed", "x")
+	if err == nil {
+		t.Fatal("want error")
+	}
+	for _, want := range []string{"webhook url missing", "telegram 42: telegram bot_token missing", "sms : unsupported channel"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not mention %q", err, want)
+		}
+	}
+}
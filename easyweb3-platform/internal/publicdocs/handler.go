@@ -15,6 +15,9 @@ type Handler struct {
 	// Dir holds markdown files to be served publicly.
 	// Expected file names: ARCHITECTURE.md, OPENCLAW.md.
 	Dir string
+	// OpenAPI is the gateway's own swagger spec, served at
+	// /docs/openapi.json.
+	OpenAPI []byte
 }
 
 func (h Handler) Index(w http.ResponseWriter, r *http.Request) {
@@ -52,6 +55,9 @@ func (h Handler) Index(w http.ResponseWriter, r *http.Request) {
 	}
 	var b strings.Builder
 	b.WriteString("# Public Docs\n\n")
+	if len(h.OpenAPI) > 0 {
+		b.WriteString("- /docs/openapi.json\n")
+	}
 	for _, name := range names {
 		b.WriteString("- /docs/")
 		b.WriteString(name)
@@ -60,6 +66,21 @@ func (h Handler) Index(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(b.String()))
 }
 
+// OpenAPISpec serves the gateway's swagger spec.
+func (h Handler) OpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		httpx.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if len(h.OpenAPI) == 0 {
+		httpx.WriteError(w, http.StatusNotFound, "spec not configured")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(h.OpenAPI)
+}
+
 func (h Handler) Architecture(w http.ResponseWriter, r *http.Request) {
 	h.serve(w, r, "ARCHITECTURE.md")
 }
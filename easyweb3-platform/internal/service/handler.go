@@ -18,6 +18,12 @@ type Handler struct {
 	Client   *http.Client
 }
 
+// @Summary List the registered business services
+// @Tags services
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} map[string]any
+// @Router /api/v1/service/list [get]
 func (h Handler) List(w http.ResponseWriter, r *http.Request) {
 	if _, ok := auth.ClaimsFromContext(r.Context()); !ok {
 		httpx.WriteError(w, http.StatusUnauthorized, "missing token")
@@ -36,6 +42,14 @@ func (h Handler) List(w http.ResponseWriter, r *http.Request) {
 	httpx.WriteJSON(w, http.StatusOK, out)
 }
 
+// @Summary Probe a service's health path
+// @Tags services
+// @Produce json
+// @Security BearerAuth
+// @Param name query string true "service name"
+// @Success 200 {object} map[string]any
+// @Failure 404 {object} httpx.ErrorResponse
+// @Router /api/v1/service/health [get]
 func (h Handler) Health(w http.ResponseWriter, r *http.Request, name string) {
 	if _, ok := auth.ClaimsFromContext(r.Context()); !ok {
 		httpx.WriteError(w, http.StatusUnauthorized, "missing token")
@@ -75,6 +89,14 @@ func (h Handler) Health(w http.ResponseWriter, r *http.Request, name string) {
 	httpx.WriteJSON(w, http.StatusOK, map[string]any{"name": name, "status": status, "http_status": resp.StatusCode})
 }
 
+// @Summary Fetch a service's markdown docs
+// @Tags services
+// @Produce plain
+// @Security BearerAuth
+// @Param name query string true "service name"
+// @Success 200 {string} string
+// @Failure 502 {object} httpx.ErrorResponse
+// @Router /api/v1/service/docs [get]
 func (h Handler) Docs(w http.ResponseWriter, r *http.Request, name string) {
 	if _, ok := auth.ClaimsFromContext(r.Context()); !ok {
 		httpx.WriteError(w, http.StatusUnauthorized, "missing token")
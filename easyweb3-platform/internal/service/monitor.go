@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nicekwell/easyweb3-platform/internal/cache"
+	"github.com/nicekwell/easyweb3-platform/internal/config"
+	"github.com/nicekwell/easyweb3-platform/internal/httpx"
+)
+
+const (
+	StatusUp      = "up"
+	StatusDown    = "down"
+	StatusUnknown = "unknown"
+
+	// EventStatusChanged is the notify event emitted on up/down transitions.
+	EventStatusChanged = "service.status_changed"
+)
+
+// Probe is one health check of a service.
+type Probe struct {
+	At         time.Time `json:"at"`
+	Status     string    `json:"status"`
+	LatencyMS  int64     `json:"latency_ms"`
+	HTTPStatus int       `json:"http_status,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// serviceState is persisted per service in the cache store so history
+// survives restarts when the cache is Redis.
+type serviceState struct {
+	Status  string    `json:"status"`
+	Since   time.Time `json:"since"`
+	History []Probe   `json:"history"`
+}
+
+// Notifier delivers status change events; notification.Handler.Emit fits.
+type Notifier func(ctx context.Context, event, message string) error
+
+// Monitor probes the health path of every registered service on an
+// interval, keeps a bounded latency/availability history and notifies on
+// up/down transitions.
+type Monitor struct {
+	Services map[string]config.ServiceConfig
+	Client   *http.Client
+	Cache    cache.Store
+	Interval time.Duration
+	// History is the number of probes kept per service.
+	History int
+	Notify  Notifier
+
+	mu     sync.RWMutex
+	states map[string]*serviceState
+}
+
+func (m *Monitor) interval() time.Duration {
+	if m.Interval <= 0 {
+		return 30 * time.Second
+	}
+	return m.Interval
+}
+
+func (m *Monitor) historyLimit() int {
+	if m.History <= 0 {
+		return 120
+	}
+	return m.History
+}
+
+func cacheKey(name string) string {
+	return "platform:status:" + name
+}
+
+// Run probes immediately and then every Interval until ctx is done.
+func (m *Monitor) Run(ctx context.Context) error {
+	m.restore(ctx)
+	m.ProbeAll(ctx)
+	ticker := time.NewTicker(m.interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			m.ProbeAll(ctx)
+		}
+	}
+}
+
+func (m *Monitor) restore(ctx context.Context) {
+	if m.Cache == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.states == nil {
+		m.states = map[string]*serviceState{}
+	}
+	for name := range m.Services {
+		b, found, err := m.Cache.Get(ctx, cacheKey(name))
+		if err != nil || !found {
+			continue
+		}
+		var st serviceState
+		if json.Unmarshal(b, &st) == nil {
+			m.states[name] = &st
+		}
+	}
+}
+
+// ProbeAll checks every service once, concurrently.
+func (m *Monitor) ProbeAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for name, sc := range m.Services {
+		if sc.BaseURL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(name string, sc config.ServiceConfig) {
+			defer wg.Done()
+			m.record(ctx, name, m.probe(ctx, sc))
+		}(name, sc)
+	}
+	wg.Wait()
+}
+
+func (m *Monitor) probe(ctx context.Context, sc config.ServiceConfig) Probe {
+	p := Probe{At: time.Now().UTC(), Status: StatusDown}
+	u, err := url.Parse(sc.BaseURL)
+	if err != nil {
+		p.Error = "bad upstream"
+		return p
+	}
+	u.Path = sc.HealthPath
+
+	client := m.Client
+	if client == nil {
+		client = &http.Client{Timeout: 3 * time.Second}
+	}
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	start := time.Now()
+	resp, err := client.Do(req)
+	p.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	_ = resp.Body.Close()
+	p.HTTPStatus = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		p.Status = StatusUp
+	}
+	return p
+}
+
+func (m *Monitor) record(ctx context.Context, name string, p Probe) {
+	m.mu.Lock()
+	if m.states == nil {
+		m.states = map[string]*serviceState{}
+	}
+	st, ok := m.states[name]
+	if !ok {
+		st = &serviceState{Status: StatusUnknown, Since: p.At}
+		m.states[name] = st
+	}
+	prev := st.Status
+	if prev != p.Status {
+		st.Status = p.Status
+		st.Since = p.At
+	}
+	st.History = append(st.History, p)
+	if over := len(st.History) - m.historyLimit(); over > 0 {
+		st.History = append([]Probe(nil), st.History[over:]...)
+	}
+	b, _ := json.Marshal(st)
+	m.mu.Unlock()
+
+	if m.Cache != nil {
+		if err := m.Cache.Set(ctx, cacheKey(name), b, 0); err != nil {
+			log.Printf("status: store %s: %v", name, err)
+		}
+	}
+	// The first probe after start only establishes the baseline.
+	if prev == p.Status || prev == StatusUnknown || m.Notify == nil {
+		return
+	}
+	msg := fmt.Sprintf("service %s is %s (was %s)", name, p.Status, prev)
+	if p.Error != "" {
+		msg += ": " + p.Error
+	} else if p.HTTPStatus != 0 && p.Status == StatusDown {
+		msg += fmt.Sprintf(": http %d", p.HTTPStatus)
+	}
+	if err := m.Notify(ctx, EventStatusChanged, msg); err != nil {
+		log.Printf("status: notify %s: %v", name, err)
+	}
+}
+
+// ServiceStatus is the public status of one service.
+type ServiceStatus struct {
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	Since        time.Time `json:"since"`
+	LastProbe    *Probe    `json:"last_probe,omitempty"`
+	Availability float64   `json:"availability"`
+	AvgLatencyMS int64     `json:"avg_latency_ms"`
+	Probes       int       `json:"probes"`
+	History      []Probe   `json:"history"`
+}
+
+// Snapshot returns the status of every service by name. Availability and
+// average latency cover the kept history; latency averages successful probes.
+func (m *Monitor) Snapshot() []ServiceStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]ServiceStatus, 0, len(m.Services))
+	for name := range m.Services {
+		s := ServiceStatus{Name: name, Status: StatusUnknown, History: []Probe{}}
+		if st, ok := m.states[name]; ok {
+			s.Status = st.Status
+			s.Since = st.Since
+			s.History = append(s.History, st.History...)
+			s.Probes = len(st.History)
+			var up int
+			var latency int64
+			for _, p := range st.History {
+				if p.Status == StatusUp {
+					up++
+					latency += p.LatencyMS
+				}
+			}
+			if s.Probes > 0 {
+				last := st.History[s.Probes-1]
+				s.LastProbe = &last
+				s.Availability = float64(up) / float64(s.Probes)
+			}
+			if up > 0 {
+				s.AvgLatencyMS = latency / int64(up)
+			}
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Status serves the public status page. It omits upstream URLs.
+//
+// @Summary Public service status
+// @Tags services
+// @Produce json
+// @Success 200 {object} statusPage
+// @Router /status [get]
+func (m *Monitor) Status(w http.ResponseWriter, r *http.Request) {
+	services := m.Snapshot()
+	overall := "operational"
+	for _, s := range services {
+		switch s.Status {
+		case StatusDown:
+			overall = "degraded"
+		case StatusUnknown:
+			if overall == "operational" {
+				overall = StatusUnknown
+			}
+		}
+	}
+	httpx.WriteJSON(w, http.StatusOK, statusPage{
+		Status:          overall,
+		IntervalSeconds: int(m.interval().Seconds()),
+		Services:        services,
+	})
+}
+
+type statusPage struct {
+	Status          string          `json:"status"`
+	IntervalSeconds int             `json:"interval_seconds"`
+	Services        []ServiceStatus `json:"services"`
+}
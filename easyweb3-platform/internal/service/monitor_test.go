@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nicekwell/easyweb3-platform/internal/cache"
+	"github.com/nicekwell/easyweb3-platform/internal/config"
+)
+
+// healthServer answers its health path with the current status code.
+func healthServer(t *testing.T, code *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(int(code.Load()))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMonitorNotifiesOnTransitions(t *testing.T) {
+	var code atomic.Int32
+	code.Store(http.StatusOK)
+	srv := healthServer(t, &code)
+
+	var mu sync.Mutex
+	var events []string
+	store := cache.NewMemoryStore()
+	m := &Monitor{
+		Services: map[string]config.ServiceConfig{"api": {BaseURL: srv.URL, HealthPath: "/health"}},
+		Cache:    store,
+		History:  3,
+		Notify: func(_ context.Context, event, message string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event+" "+message)
+			return nil
+		},
+	}
+	ctx := context.Background()
+
+	// The first probe only sets the baseline; a steady status stays quiet.
+	m.ProbeAll(ctx)
+	m.ProbeAll(ctx)
+	code.Store(http.StatusServiceUnavailable)
+	m.ProbeAll(ctx)
+	code.Store(http.StatusOK)
+	m.ProbeAll(ctx)
+
+	want := []string{
+		EventStatusChanged + " service api is down (was up): http 503",
+		EventStatusChanged + " service api is up (was down)",
+	}
+	if strings.Join(events, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("events=%q", events)
+	}
+
+	snap := m.Snapshot()
+	if len(snap) != 1 {
+		t.Fatalf("snapshot=%+v", snap)
+	}
+	s := snap[0]
+	// History keeps the last three probes: up, down, up.
+	if s.Status != StatusUp || s.Probes != 3 || s.LastProbe == nil || s.LastProbe.HTTPStatus != http.StatusOK {
+		t.Fatalf("status=%+v", s)
+	}
+	if s.Availability < 0.66 || s.Availability > 0.67 || !s.Since.Equal(s.LastProbe.At) {
+		t.Fatalf("availability=%v since=%v last=%v", s.Availability, s.Since, s.LastProbe.At)
+	}
+
+	// A new monitor on the same cache picks the history back up.
+	restored := &Monitor{Services: m.Services, Cache: store}
+	restored.restore(ctx)
+	if got := restored.Snapshot()[0]; got.Status != StatusUp || got.Probes != 3 {
+		t.Fatalf("restored=%+v", got)
+	}
+}
+
+func TestMonitorProbeFailure(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	m := &Monitor{}
+	p := m.probe(context.Background(), config.ServiceConfig{BaseURL: srv.URL, HealthPath: "/health"})
+	if p.Status != StatusDown || p.Error == "" || p.HTTPStatus != 0 {
+		t.Fatalf("probe=%+v", p)
+	}
+}
+
+func TestMonitorStatusPage(t *testing.T) {
+	var up, down atomic.Int32
+	up.Store(http.StatusOK)
+	down.Store(http.StatusInternalServerError)
+	upSrv, downSrv := healthServer(t, &up), healthServer(t, &down)
+
+	cases := []struct {
+		name     string
+		services map[string]config.ServiceConfig
+		want     string
+	}{
+		{"all up", map[string]config.ServiceConfig{"a": {BaseURL: upSrv.URL, HealthPath: "/health"}}, "operational"},
+		{"one down", map[string]config.ServiceConfig{"a": {BaseURL: upSrv.URL, HealthPath: "/health"}, "b": {BaseURL: downSrv.URL, HealthPath: "/health"}}, "degraded"},
+		// Services without an upstream are never probed.
+		{"never probed", map[string]config.ServiceConfig{"a": {BaseURL: upSrv.URL, HealthPath: "/health"}, "c": {}}, StatusUnknown},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Monitor{Services: tc.services}
+			m.ProbeAll(context.Background())
+			w := httptest.NewRecorder()
+			m.Status(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("code=%d", w.Code)
+			}
+			if strings.Contains(w.Body.String(), "127.0.0.1") {
+				t.Fatalf("status page leaks upstream urls: %s", w.Body.String())
+			}
+			var page statusPage
+			if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+				t.Fatal(err)
+			}
+			if page.Status != tc.want || page.IntervalSeconds != 30 || len(page.Services) != len(tc.services) {
+				t.Fatalf("page=%+v", page)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Command bench runs the throughput suite against a seeded in-memory database
+// and writes a JSON report. With -baseline it compares p95 latencies with a
+// saved report and exits 1 on a regression.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"polymarket/internal/bench"
+	"polymarket/internal/db"
+)
+
+func main() {
+	out := flag.String("out", "bench-report.json", "report path")
+	baseline := flag.String("baseline", "", "baseline report to compare with (skipped when the file does not exist)")
+	threshold := flag.Float64("threshold", bench.DefaultThresholdPct, "allowed p95 growth in percent")
+	iterations := flag.Int("iterations", 500, "operations per scenario")
+	concurrency := flag.Int("concurrency", 4, "concurrent workers per scenario")
+	markets := flag.Int("markets", 200, "binary markets to seed")
+	seed := flag.Int64("seed", 1, "random seed for inputs")
+	scenarios := flag.String("scenarios", "", "comma-separated scenarios (default: "+strings.Join(bench.Names(), ",")+")")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, *out, *baseline, *threshold, bench.Options{
+		Scenarios:   splitList(*scenarios),
+		Iterations:  *iterations,
+		Concurrency: *concurrency,
+		Markets:     *markets,
+		Seed:        *seed,
+	}))
+}
+
+func run(ctx context.Context, out, baseline string, threshold float64, opts bench.Options) int {
+	conn, err := bench.OpenMemory()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "open db:", err)
+		return 2
+	}
+	defer db.Close(conn)
+
+	rep, err := bench.Run(ctx, conn.Gorm, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "bench:", err)
+		return 2
+	}
+	for _, s := range rep.Scenarios {
+		fmt.Printf("%-22s %8.0f ops/s  p50 %8.3fms  p95 %8.3fms  p99 %8.3fms  errors %d\n",
+			s.Name, s.OpsPerSec, s.P50Ms, s.P95Ms, s.P99Ms, s.Errors)
+	}
+	if out != "" {
+		if err := bench.WriteReport(out, rep); err != nil {
+			fmt.Fprintln(os.Stderr, "write report:", err)
+			return 2
+		}
+		fmt.Println("report:", out)
+	}
+	if baseline == "" {
+		return 0
+	}
+	base, err := bench.ReadReport(baseline)
+	if os.IsNotExist(err) {
+		fmt.Println("no baseline at", baseline, "- skipping comparison")
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "read baseline:", err)
+		return 2
+	}
+	cmp := bench.Compare(base, rep, threshold)
+	raw, _ := json.MarshalIndent(cmp, "", "  ")
+	fmt.Println(string(raw))
+	if !cmp.Passed {
+		fmt.Fprintf(os.Stderr, "p95 regression beyond %.0f%% against %s\n", cmp.ThresholdPct, baseline)
+		return 1
+	}
+	return 0
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
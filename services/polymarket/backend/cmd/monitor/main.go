@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,22 +16,32 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
+	"polymarket/internal/chaos"
+	"polymarket/internal/client/polygon"
 	"polymarket/internal/client/polymarket/clob"
 	polymarketgamma "polymarket/internal/client/polymarket/gamma"
+	"polymarket/internal/compliance"
 	"polymarket/internal/config"
 	cronrunner "polymarket/internal/cron"
 	"polymarket/internal/db"
+	"polymarket/internal/events"
+	"polymarket/internal/governor"
+	"polymarket/internal/grpcapi"
 	"polymarket/internal/handler"
 	"polymarket/internal/labeler"
 	"polymarket/internal/logger"
 	"polymarket/internal/opportunity"
 	"polymarket/internal/paas"
+	"polymarket/internal/regime"
+	"polymarket/internal/repository/bookcache"
 	gormrepository "polymarket/internal/repository/gorm"
 	"polymarket/internal/risk"
 	"polymarket/internal/service"
 	signalhub "polymarket/internal/signal"
 	"polymarket/internal/strategy"
+	"polymarket/internal/tradingday"
 
 	_ "polymarket/docs"
 )
@@ -51,7 +62,11 @@ func main() {
 		panic(err)
 	}
 
-	logger, err := logger.New(cfg.Log)
+	var logRing *logger.Ring
+	if cfg.Log.RingSize > 0 {
+		logRing = logger.NewRing(cfg.Log.RingSize)
+	}
+	logger, err := logger.New(cfg.Log, logRing)
 	if err != nil {
 		panic(err)
 	}
@@ -69,24 +84,101 @@ func main() {
 	if err := db.AutoMigrate(dbConn); err != nil {
 		logger.Fatal("auto-migrate failed", zap.Error(err))
 	}
+	tradingCalendar, err := tradingday.New(cfg.TradingDay)
+	if err != nil {
+		logger.Fatal("invalid trading day config", zap.Error(err))
+	}
+
+	faults := chaos.New(cfg.Chaos, cfg.App.Env)
+	if cfg.Chaos.Enabled {
+		if faults == nil {
+			logger.Warn("chaos.enabled ignored in production", zap.String("env", cfg.App.Env))
+		} else {
+			logger.Warn("fault injection enabled", zap.Duration("max_duration", cfg.Chaos.MaxDuration))
+		}
+	}
+	if err := faults.RegisterDB(dbConn.Gorm); err != nil {
+		logger.Fatal("register db fault hooks failed", zap.Error(err))
+	}
 
-	gammaHTTP := &http.Client{Timeout: cfg.Gamma.Timeout}
+	gammaHTTP := &http.Client{Timeout: cfg.Gamma.Timeout, Transport: faults.Transport(chaos.TargetGamma, nil)}
 	gammaClient := polymarketgamma.NewClientWithHost(gammaHTTP, cfg.Gamma.BaseURL)
-	clobHTTP := &http.Client{Timeout: cfg.ClobREST.Timeout}
-	clobClient := clob.NewClient(clobHTTP, cfg.ClobREST.BaseURL)
-	store := gormrepository.New(dbConn.Gorm)
-	settingsSvc := &service.SystemSettingsService{Repo: store}
+	clobHTTP := &http.Client{Timeout: cfg.ClobREST.Timeout, Transport: faults.Transport(chaos.TargetCLOB, nil)}
+	clobRESTPool := clob.NewEndpointPool(clob.EndpointPoolOptions{
+		Name:         "rest",
+		URLs:         append([]string{cfg.ClobREST.BaseURL}, cfg.ClobREST.Endpoints...),
+		Probe:        clob.HTTPProbe(clobHTTP),
+		ProbeTimeout: cfg.ClobEndpoints.ProbeTimeout,
+		MaxFailures:  cfg.ClobEndpoints.MaxFailures,
+		SwitchMargin: cfg.ClobEndpoints.SwitchMargin,
+		Logger:       logger,
+	})
+	clobWSURL := cfg.ClobStream.URL
+	if strings.TrimSpace(clobWSURL) == "" {
+		clobWSURL = clob.DefaultMarketWSSURL
+	}
+	clobWSPool := clob.NewEndpointPool(clob.EndpointPoolOptions{
+		Name:         "ws",
+		URLs:         append([]string{clobWSURL}, cfg.ClobStream.Endpoints...),
+		Probe:        clob.WSProbe(),
+		ProbeTimeout: cfg.ClobEndpoints.ProbeTimeout,
+		MaxFailures:  cfg.ClobEndpoints.MaxFailures,
+		SwitchMargin: cfg.ClobEndpoints.SwitchMargin,
+		Logger:       logger,
+	})
+	clobClient := clob.NewPooledClient(clobHTTP, clobRESTPool)
+	// All readers and writers share one book cache: the CLOB stream and REST
+	// resync fill it, strategies/risk/preflight read from it.
+	gormStore := gormrepository.New(dbConn.Gorm)
+	// Row-change events: Postgres NOTIFY reaches every process through the
+	// listener; SQLite has no LISTEN, so the bus is fed in-process.
+	eventBus := events.NewBus()
+	var eventListener *events.Listener
+	if cfg.Events.Enabled {
+		if db.IsSQLite(dbConn.Gorm) {
+			gormStore.SetEventPublisher(eventBus)
+		} else {
+			gormStore.SetEventPublisher(&events.PGNotifier{DB: dbConn.Gorm, Channel: cfg.Events.Channel, Logger: logger})
+			eventListener = &events.Listener{
+				DSN:            cfg.DB.DSN,
+				Channel:        cfg.Events.Channel,
+				Bus:            eventBus,
+				ReconnectDelay: cfg.Events.ReconnectDelay,
+				Logger:         logger,
+			}
+		}
+	}
+	store := bookcache.New(gormStore, cfg.ClobStream.BookCacheMaxAge)
+	settingsCache := &service.SettingsCache{Repo: store, Logger: logger, RefreshInterval: cfg.Settings.RefreshInterval}
+	settingsSvc := &service.SystemSettingsService{Repo: store, Cache: settingsCache}
 	if err := settingsSvc.EnsureDefaultSwitches(context.Background()); err != nil {
 		logger.Warn("init default system switches failed", zap.Error(err))
 	}
-	catalogService := &service.CatalogSyncService{
-		Store:  store,
-		Gamma:  gammaClient,
-		Clob:   clobClient,
+	catalogWebhooks := &service.CatalogWebhookService{
+		Repo:   store,
+		Config: cfg.CatalogSync.Webhooks,
+		Client: &http.Client{Timeout: cfg.CatalogSync.Webhooks.Timeout},
 		Logger: logger,
 	}
+	catalogService := &service.CatalogSyncService{
+		Store:                store,
+		Gamma:                gammaClient,
+		Clob:                 clobClient,
+		Logger:               logger,
+		DisabledQualityRules: cfg.CatalogSync.DisabledQualityRules,
+		Webhooks:             catalogWebhooks,
+		Profiles:             cfg.CatalogSync.Profiles,
+		Anomalies:            &service.CatalogAnomalyMonitor{Repo: store, Config: cfg.CatalogSync.Anomalies, Logger: logger},
+	}
 	queryService := &service.CatalogQueryService{Repo: store}
-	streamService := &service.CLOBStreamService{Repo: store, Logger: logger}
+	streamService := &service.CLOBStreamService{
+		Repo:          store,
+		Logger:        logger,
+		Subscriptions: &service.StreamSubscriptions{Cap: cfg.ClobStream.MaxAssets, IdleTTL: cfg.ClobStream.PinIdleTTL},
+	}
+	if cfg.ClobStream.PinOpenPositions {
+		streamService.Positions = store
+	}
 
 	var marketLabeler *labeler.MarketLabeler
 	marketLabeler = &labeler.MarketLabeler{
@@ -100,13 +192,35 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	engine := gin.New()
+	engine.Use(paas.RequestIDMiddleware(logger))
 	engine.Use(gin.Recovery())
 	engine.Use(corsMiddleware())
 
-	paasClient := initPaaSClient(logger)
+	paasClient := initPaaSClient(logger, cfg.PaaSLogs)
+	// The log pipeline outlives the signal context so logs written during
+	// shutdown are still flushed or spilled.
+	logsCtx, stopLogs := context.WithCancel(context.Background())
+	defer stopLogs()
+	if paasClient != nil && paasClient.Logs != nil {
+		go paasClient.Logs.Run(logsCtx)
+	}
 	engine.Use(paas.RequireBearerMiddleware())
 	engine.Use(paas.InjectClientMiddleware(paasClient))
-	engine.Use(paas.PaaSWriteAuditMiddleware(paasClient, logger))
+	engine.Use(paas.TenantMiddleware())
+	// Notifications and catalog webhooks go through the outbox when it is
+	// enabled, so a PaaS or receiver outage delays them instead of losing them.
+	outbox := &service.OutboxDispatcher{Repo: store, Config: cfg.Outbox, Paas: paasClient, Logger: logger, CatalogWebhooks: catalogWebhooks}
+	if cfg.Events.Enabled {
+		outbox.Wake = eventBus.Subscribe(16, events.TopicOutboxEnqueued)
+	}
+	catalogWebhooks.Outbox = outbox
+	catalogService.Anomalies.Notify = outbox.Notify
+	auditSvc := &service.AuditChainService{Repo: store}
+	engine.Use(paas.PaaSWriteAuditMiddleware(paasClient, auditSvc, logger))
+	engine.Use(handler.Idempotency(store, cfg.Server.IdempotencyTTL, logger))
+	// Heavy admin and background jobs share this budget so live queries keep
+	// their DB connections.
+	gov := governor.New(cfg.Governor, cfg.DB.MaxOpenConns)
 
 	healthHandler := &handler.HealthHandler{DB: dbConn.Gorm}
 	healthHandler.Register(engine)
@@ -115,21 +229,86 @@ func main() {
 		Service:      catalogService,
 		QueryService: queryService,
 		Logger:       logger,
+		Governor:     gov,
+		Outbox:       outbox,
 	}
 	catalogHandler.Register(engine)
 
 	// V2 API (read-mostly skeleton; strategy engine wiring is added in later phases).
-	v2Signals := &handler.V2SignalHandler{Repo: store}
+	signalQualitySvc := &service.SignalQualityService{Repo: store, Config: cfg.StrategyEngine.SignalQuality}
+	v2Signals := &handler.V2SignalHandler{Repo: store, Quality: signalQualitySvc, Outbox: outbox}
 	v2Signals.Register(engine)
-	v2Strategies := &handler.V2StrategyHandler{Repo: store}
+	ruleSimulator := &service.ExecutionRuleSimulator{Repo: store, Config: cfg.AutoExecutor, Calendar: tradingCalendar}
+	strategyBudgets := &service.StrategyBudgetService{Repo: store, Logger: logger, Calendar: tradingCalendar}
+	v2Strategies := &handler.V2StrategyHandler{
+		Repo:    store,
+		Toggle:  &service.StrategyToggleService{Repo: store, Settings: settingsSvc},
+		Budgets: strategyBudgets,
+		Changes: &service.StrategyChangeService{Repo: store, Config: cfg.StrategyHoldout},
+		Outbox:  outbox,
+		Tuner: &service.StrategyParamTuner{
+			Repo:      store,
+			Simulator: ruleSimulator,
+			Logger:    logger,
+			Outbox:    outbox,
+			Defaults:  cfg.StrategyDefaults,
+		},
+	}
 	v2Strategies.Register(engine)
-	riskMgr := &risk.Manager{Config: cfg.Risk, Repo: store, Logger: logger}
-	v2Opps := &handler.V2OpportunityHandler{Repo: store, Risk: riskMgr}
+	v2Bundles := &handler.V2StrategyBundleHandler{Bundles: &service.StrategyBundleService{
+		Repo:             store,
+		TrustedKeys:      cfg.StrategyEngine.Bundles.TrustedKeys,
+		RequireSignature: cfg.StrategyEngine.Bundles.RequireSignature,
+		NewStrategyStage: cfg.StrategyEngine.NewStrategyStage,
+	}, Governor: gov, Outbox: outbox}
+	v2Bundles.Register(engine)
+	calibrationSvc := &service.CalibrationService{Repo: store}
+	complianceChecker := &compliance.Checker{Config: cfg.Compliance, Repo: store}
+	regimeDetector := &regime.Detector{Config: cfg.Regime, Repo: store, Logger: logger}
+	riskMgr := &risk.Manager{Config: cfg.Risk, Repo: store, Logger: logger, Calibration: calibrationSvc, Compliance: complianceChecker, Regimes: regimeDetector, Calendar: tradingCalendar}
+	campaignSvc := &service.CampaignService{Repo: store, Logger: logger}
+	costForecaster := &service.CostForecaster{Repo: store, Config: cfg.Risk.ExecutionCost}
+	strategyCapacity := &service.StrategyCapacityService{Repo: store, Config: cfg.Capacity, Costs: costForecaster, Risk: riskMgr, Logger: logger, Notify: outbox.Notify}
+	v2Strategies.Capacity = strategyCapacity
+	strategyDrift := &service.StrategyDriftService{Repo: store, Config: cfg.StrategyDrift, Logger: logger, Notify: outbox.Notify}
+	v2Strategies.Drift = strategyDrift
+	tradeWebhooks := &service.TradeWebhookService{Repo: store, Config: cfg.TradeWebhooks, Outbox: outbox, Logger: logger}
+	outbox.TradeWebhooks = tradeWebhooks
+	if cfg.Events.Enabled {
+		tradeWebhooks.Events = eventBus.Subscribe(256, events.TopicFillCreated, events.TopicPlanStatus, events.TopicPositionClosed)
+	}
+	v2Outbox := &handler.V2OutboxHandler{Repo: store, Outbox: outbox}
+	v2Outbox.Register(engine)
+	v2Webhooks := &handler.V2WebhookHandler{Repo: store, Webhooks: tradeWebhooks, Outbox: outbox}
+	v2Webhooks.Register(engine)
+	v2Opps := &handler.V2OpportunityHandler{Repo: store, Risk: riskMgr, Campaigns: campaignSvc, Costs: costForecaster, Outbox: outbox}
 	v2Opps.Register(engine)
-	v2Labels := &handler.V2LabelHandler{Repo: store, Labeler: marketLabeler}
+	v2Labels := &handler.V2LabelHandler{Repo: store, Labeler: marketLabeler, Governor: gov}
 	v2Labels.Register(engine)
+	v2Trades := &handler.V2TradeHandler{Repo: store}
+	v2Trades.Register(engine)
+	onchainConditions := &service.OnchainConditionService{Repo: store, Config: cfg.Onchain, Logger: logger, Notify: outbox.Notify}
+	if cfg.Onchain.Enabled {
+		onchainConditions.Reader = polygon.NewCTFReader(&http.Client{Timeout: 15 * time.Second}, cfg.Onchain.RPCURL, cfg.Onchain.CTFAddress)
+	}
+	v2Catalog := &handler.V2CatalogHandler{Repo: store, Onchain: onchainConditions}
+	v2Catalog.Register(engine)
+	v2CatalogWebhooks := &handler.V2CatalogWebhookHandler{Repo: store, Webhooks: catalogWebhooks, Outbox: outbox}
+	v2CatalogWebhooks.Register(engine)
+	v2Compliance := &handler.V2ComplianceHandler{Repo: store, Checker: complianceChecker, Outbox: outbox}
+	v2Compliance.Register(engine)
+	v2Regimes := &handler.V2RegimeHandler{Repo: store, Detector: regimeDetector, Outbox: outbox}
+	v2Regimes.Register(engine)
+	sloSvc := &service.SLOService{Repo: store, Config: cfg.SLO, Stream: streamService, Flags: settingsSvc, Logger: logger, Notify: outbox.Notify}
+	v2SLO := &handler.V2SLOHandler{Repo: store, SLO: sloSvc, Outbox: outbox}
+	v2SLO.Register(engine)
+	v2Bench := &handler.V2BenchHandler{Config: cfg.Bench, Governor: gov}
+	v2Bench.Register(engine)
+	v2Faults := &handler.V2FaultHandler{Injector: faults, Outbox: outbox}
+	v2Faults.Register(engine)
 	journalSvc := &service.JournalService{Repo: store}
 	positionSyncSvc := &service.PositionSyncService{Repo: store, Logger: logger, Flags: settingsSvc}
+	portfolioAnomalies := &service.PortfolioAnomalyService{Repo: store, Config: cfg.PortfolioDiff, Logger: logger, Notify: outbox.Notify}
 	execMode := "live"
 	if cfg.AutoExecutor.DryRun {
 		execMode = "dry-run"
@@ -140,34 +319,171 @@ func main() {
 		Logger:       logger,
 		PositionSync: positionSyncSvc,
 		Client:       clobClient,
+		Throttle:     service.NewMarketThrottle(cfg.AutoExecutor.MarketMinInterval),
+		Settings:     settingsCache,
+		Notify:       outbox.Notify,
 		Config: service.ExecutorConfig{
 			Mode:                 execMode,
 			MaxOrderSizeUSD:      decimal.Zero,
 			SlippageToleranceBps: 200,
+			MergeChildOrders:     cfg.AutoExecutor.MergeChildOrders,
+			Maker: service.MakerConfig{
+				Improvement:  cfg.AutoExecutor.Maker.Improvement,
+				StepSize:     cfg.AutoExecutor.Maker.StepSize,
+				StepInterval: cfg.AutoExecutor.Maker.StepInterval,
+				TakerAfter:   cfg.AutoExecutor.Maker.TakerAfter,
+				TickSize:     cfg.AutoExecutor.Maker.TickSize,
+			},
+			Repricer: service.RepricerConfig{
+				Enabled:         cfg.AutoExecutor.Repricer.Enabled,
+				Threshold:       cfg.AutoExecutor.Repricer.Threshold,
+				AggressionTicks: cfg.AutoExecutor.Repricer.AggressionTicks,
+				MaxStep:         cfg.AutoExecutor.Repricer.MaxStep,
+				MinInterval:     cfg.AutoExecutor.Repricer.MinInterval,
+			},
+			Dependencies: service.LegDependencyConfig{
+				Timeout:   cfg.AutoExecutor.LegDependencies.Timeout,
+				OnTimeout: cfg.AutoExecutor.LegDependencies.OnTimeout,
+			},
 		},
 	}
-	v2Positions := &handler.V2PositionHandler{Repo: store}
+	positionImportSvc := &service.ExternalPositionService{
+		Repo:    store,
+		Logger:  logger,
+		Flags:   settingsSvc,
+		Adapter: &service.DataAPIPositionAdapter{Endpoint: cfg.PositionImport.Endpoint},
+		Config:  cfg.PositionImport,
+	}
+	v2Positions := &handler.V2PositionHandler{Repo: store, Sync: positionSyncSvc, Import: positionImportSvc, Governor: gov, Outbox: outbox}
 	v2Positions.Register(engine)
-	v2Exec := &handler.V2ExecutionHandler{Repo: store, Risk: riskMgr}
+	v2Exec := &handler.V2ExecutionHandler{Repo: store, Risk: riskMgr, Outbox: outbox}
 	v2Exec.Journal = journalSvc
 	v2Exec.PositionSync = positionSyncSvc
+	v2Exec.Campaigns = campaignSvc
+	v2Exec.Dependencies = clobExecutor.Config.Dependencies
+	v2Exec.Costs = costForecaster
+	planTriggers := &service.PlanTriggerService{
+		Repo:     store,
+		Risk:     riskMgr,
+		Executor: clobExecutor,
+		Logger:   logger,
+		MaxDelay: cfg.Cron.PlanTriggerMaxDelay,
+	}
+	v2Exec.Triggers = planTriggers
 	v2Exec.Register(engine)
-	v2Analytics := &handler.V2AnalyticsHandler{Repo: store}
+	v2Campaigns := &handler.V2CampaignHandler{Repo: store, Campaigns: campaignSvc, Outbox: outbox}
+	v2Campaigns.Register(engine)
+	v2Rewards := &handler.V2RewardHandler{Repo: store, Outbox: outbox}
+	v2Rewards.Register(engine)
+	v2Analytics := &handler.V2AnalyticsHandler{Repo: store, Calibration: calibrationSvc, Costs: costForecaster, Calendar: tradingCalendar}
 	v2Analytics.Register(engine)
 	v2Review := &handler.V2ReviewHandler{Repo: store}
 	v2Review.Register(engine)
-	v2Settlements := &handler.V2SettlementHandler{Repo: store}
+	v2Settlements := &handler.V2SettlementHandler{Repo: store, Outbox: outbox}
 	v2Settlements.Register(engine)
-	v2Rules := &handler.V2ExecutionRuleHandler{Repo: store}
+	v2Rules := &handler.V2ExecutionRuleHandler{
+		Repo:      store,
+		Simulator: ruleSimulator,
+		Tuner:     &service.ExecutionRuleTuner{Repo: store, Simulator: ruleSimulator},
+	}
 	v2Rules.Register(engine)
+	v2Conditions := &handler.V2ConditionHandler{Repo: store}
+	v2Conditions.Register(engine)
 	v2Orders := &handler.V2OrderHandler{Repo: store, Executor: clobExecutor}
 	v2Orders.Register(engine)
+	v2ExecutorIntents := &handler.V2ExecutorIntentHandler{Repo: store, Executor: clobExecutor}
+	v2ExecutorIntents.Register(engine)
+	v2Tickets := &handler.V2TicketHandler{Repo: store, Risk: riskMgr, Executor: clobExecutor, Campaigns: campaignSvc, Costs: costForecaster, Outbox: outbox}
+	v2Tickets.Register(engine)
 	v2Journal := &handler.V2JournalHandler{Repo: store}
 	v2Journal.Register(engine)
-	v2Settings := &handler.V2SystemSettingsHandler{Repo: store, Settings: settingsSvc}
+	v2Snapshots := &handler.V2ResearchSnapshotHandler{Repo: store, Snapshots: &service.ResearchSnapshotService{Repo: store}, Outbox: outbox}
+	v2Snapshots.Register(engine)
+	v2Exports := &handler.V2AnalyticsExportHandler{Repo: store, Exports: &service.AnalyticsExportService{Repo: store}, Outbox: outbox}
+	v2Exports.Register(engine)
+	cashOpsSvc := &service.CashOpsService{Repo: store, Config: cfg.CashOps, Logger: logger}
+	if strings.TrimSpace(cfg.CashOps.Wallet) != "" {
+		cashOpsSvc.Source = &service.EtherscanTransferSource{Endpoint: cfg.CashOps.Endpoint, APIKey: cfg.CashOps.APIKey, Contract: cfg.CashOps.TokenContract}
+	}
+	v2CashOps := &handler.V2CashOpsHandler{Repo: store, Risk: riskMgr, CashOps: cashOpsSvc, Outbox: outbox}
+	v2CashOps.Register(engine)
+	retentionSvc := &service.RetentionService{Repo: store, Settings: settingsSvc, Config: cfg.Retention, Logger: logger, Governor: gov}
+	v2Retention := &handler.V2RetentionHandler{Retention: retentionSvc, Settings: settingsSvc, Governor: gov, Outbox: outbox}
+	v2Retention.Register(engine)
+	v2Settings := &handler.V2SystemSettingsHandler{Repo: store, Settings: settingsSvc, Governor: gov}
 	v2Settings.Register(engine)
-	v2Pipeline := &handler.V2PipelineHandler{Repo: store}
+	safeModeSvc := &service.SafeModeService{
+		Repo:     store,
+		Settings: settingsSvc,
+		Orders:   clobExecutor,
+		Config:   cfg.SafeMode,
+		Logger:   logger,
+		Notify:   outbox.Notify,
+	}
+	v2SafeMode := &handler.V2SafeModeHandler{SafeMode: safeModeSvc}
+	v2SafeMode.Register(engine)
+	gapSvc := &service.MarketDataGapService{
+		Repo:   store,
+		Clob:   clobClient,
+		Config:   cfg.MarketDataGaps,
+		Logger:   logger,
+		Flags:    settingsSvc,
+		Governor: gov,
+	}
+	v2Pipeline := &handler.V2PipelineHandler{Repo: store, Gaps: gapSvc, Stream: streamService, Throttle: clobExecutor.Throttle, Governor: gov, Endpoints: []*clob.EndpointPool{clobRESTPool, clobWSPool}, Outbox: outbox}
+	if paasClient != nil {
+		v2Pipeline.Logs = paasClient.Logs
+	}
 	v2Pipeline.Register(engine)
+	v2Audit := &handler.V2AuditHandler{Repo: store, Audit: auditSvc}
+	v2Audit.Register(engine)
+	cryptoDelta := &service.CryptoDeltaService{
+		Repo:    store,
+		Config:  cfg.Risk.CryptoDelta,
+		Symbols: cfg.SignalSources.CryptoSymbol,
+		HTTP:    &http.Client{Timeout: 5 * time.Second},
+		Logger:  logger,
+	}
+	v2Risk := &handler.V2RiskHandler{Repo: store, Risk: riskMgr, CryptoDelta: cryptoDelta}
+	v2Risk.Register(engine)
+	v2Logs := &handler.V2SystemLogsHandler{Ring: logRing}
+	v2Logs.Register(engine)
+	auto := &service.AutoExecutorService{
+		Repo:      store,
+		Risk:      riskMgr,
+		Logger:    logger,
+		Config:    cfg.AutoExecutor,
+		Flags:     settingsSvc,
+		Executor:  clobExecutor,
+		Campaigns: campaignSvc,
+		Calendar:  tradingCalendar,
+		Budgets:   strategyBudgets,
+		Costs:     costForecaster,
+	}
+	if cfg.Events.Enabled {
+		auto.Wake = eventBus.Subscribe(64, events.TopicOpportunityCreated)
+		auto.Config.ScanInterval = eventPollInterval(cfg.Events, auto.Config.ScanInterval)
+	}
+	v2Auto := &handler.V2AutoExecutorHandler{Auto: auto}
+	v2Auto.Register(engine)
+
+	// gRPC interface for co-located bots: same services and auth as V2.
+	var grpcSrv *grpc.Server
+	var grpcAPI *grpcapi.Server
+	if strings.TrimSpace(cfg.Server.GRPCAddr) != "" {
+		grpcSrv = grpc.NewServer(paas.NewGRPCAuth(paasClient, auditSvc, logger).ServerOptions()...)
+		grpcAPI = &grpcapi.Server{
+			Repo:         store,
+			Risk:         riskMgr,
+			Campaigns:    campaignSvc,
+			Executor:     clobExecutor,
+			Costs:        costForecaster,
+			Outbox:       outbox,
+			Logger:       logger,
+			PollInterval: cfg.Server.GRPCPollInterval,
+		}
+		grpcAPI.Register(grpcSrv)
+	}
 
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -184,6 +500,30 @@ func main() {
 		baseCtx = paas.WithClient(ctx, paasClient)
 	}
 
+	// Settle venue requests a previous run was cut off in the middle of
+	// before the executor sends new ones.
+	if res, err := clobExecutor.ReconcileIntents(baseCtx); err != nil {
+		logger.Warn("executor intent reconcile failed", zap.Error(err))
+	} else if res.Checked > 0 {
+		logger.Info("executor intents reconciled",
+			zap.Int("checked", res.Checked),
+			zap.Int("reconciled", res.Reconciled),
+			zap.Int("orphaned", res.Orphaned),
+			zap.Int("failed", res.Failed),
+			zap.Uint64s("needs_review", res.NeedsReview),
+		)
+	}
+
+	go catalogWebhooks.Run(baseCtx)
+	go outbox.Run(baseCtx)
+	go tradeWebhooks.Run(baseCtx)
+
+	go func() {
+		if err := settingsCache.Run(baseCtx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn("settings cache stopped", zap.Error(err))
+		}
+	}()
+
 	cronRunner := cronrunner.New(logger, baseCtx)
 	scope := cfg.CatalogSync.Scope
 	limit := cfg.CatalogSync.PageLimit
@@ -194,27 +534,30 @@ func main() {
 		tagID = &cfg.CatalogSync.TagID
 	}
 	closed := parseClosedFilter(cfg.CatalogSync.Closed)
+	syncOpts := service.SyncOptions{
+		Scope:             scope,
+		Limit:             limit,
+		MaxPages:          maxPages,
+		Resume:            resume,
+		TagID:             tagID,
+		Closed:            closed,
+		BookMaxAssets:     cfg.CatalogSync.BookMaxAssets,
+		BookBatchSize:     cfg.CatalogSync.BookBatchSize,
+		BookSleepPerBatch: cfg.CatalogSync.BookSleepPerBatch,
+	}
+	if err := catalogService.ApplyProfile(&syncOpts, cfg.CatalogSync.Profile); err != nil {
+		logger.Fatal("invalid catalog_sync.profile", zap.Error(err))
+	}
 
 	_, err = cronRunner.Add(cfg.Cron.CatalogSync, func(ctx context.Context) {
 		if !settingsSvc.IsEnabled(ctx, service.FeatureCatalogSync, true) {
 			return
 		}
-		result, err := catalogService.Sync(ctx, service.SyncOptions{
-			Scope:             scope,
-			Limit:             limit,
-			MaxPages:          maxPages,
-			Resume:            resume,
-			TagID:             tagID,
-			Closed:            closed,
-			BookMaxAssets:     cfg.CatalogSync.BookMaxAssets,
-			BookBatchSize:     cfg.CatalogSync.BookBatchSize,
-			BookSleepPerBatch: cfg.CatalogSync.BookSleepPerBatch,
-		})
+		result, err := catalogService.Sync(ctx, syncOpts)
 		if err != nil {
 			logger.Warn("cron catalog sync failed", zap.Error(err))
 			if paasClient != nil {
-				ctx2, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-				_ = paasClient.CreateLog(ctx2, paas.CreateLogRequest{
+				paasClient.Log(paas.CreateLogRequest{
 					Agent:  "polymarket-service",
 					Action: "polymarket_cron_catalog_sync_failed",
 					Level:  "warn",
@@ -224,7 +567,6 @@ func main() {
 					SessionKey: "",
 					Metadata:   map[string]any{},
 				})
-				cancel()
 			}
 			return
 		}
@@ -238,8 +580,7 @@ func main() {
 			zap.Int("tags", result.Tags),
 		)
 		if paasClient != nil {
-			ctx2, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			_ = paasClient.CreateLog(ctx2, paas.CreateLogRequest{
+			paasClient.Log(paas.CreateLogRequest{
 				Agent:  "polymarket-service",
 				Action: "polymarket_cron_catalog_sync_ok",
 				Level:  "info",
@@ -255,13 +596,39 @@ func main() {
 				SessionKey: "",
 				Metadata:   map[string]any{},
 			})
-			cancel()
 		}
 	})
 	if err != nil {
 		logger.Warn("cron register catalog sync failed", zap.Error(err))
 	}
 
+	for _, job := range cfg.Cron.CatalogSyncProfiles {
+		profileOpts := service.SyncOptions{Scope: "events", Limit: limit, MaxPages: maxPages, Resume: resume, Closed: closed}
+		if err := catalogService.ApplyProfile(&profileOpts, job.Profile); err != nil || profileOpts.Profile == "" {
+			logger.Warn("cron catalog sync profile skipped", zap.String("profile", job.Profile), zap.Error(err))
+			continue
+		}
+		_, err = cronRunner.Add(job.Schedule, func(ctx context.Context) {
+			if !settingsSvc.IsEnabled(ctx, service.FeatureCatalogSync, true) {
+				return
+			}
+			result, err := catalogService.Sync(ctx, profileOpts)
+			if err != nil {
+				logger.Warn("cron catalog profile sync failed", zap.String("profile", profileOpts.Profile), zap.Error(err))
+				return
+			}
+			logger.Info("cron catalog profile sync ok",
+				zap.String("profile", profileOpts.Profile),
+				zap.Int("pages", result.Pages),
+				zap.Int("events", result.Events),
+				zap.Int("markets", result.Markets),
+			)
+		})
+		if err != nil {
+			logger.Warn("cron register catalog profile sync failed", zap.String("profile", job.Profile), zap.Error(err))
+		}
+	}
+
 	_, err = cronRunner.Add("@every 30s", func(ctx context.Context) {
 		if err := positionSyncSvc.RefreshOpenPositionsPrices(ctx); err != nil {
 			logger.Warn("position price refresh failed", zap.Error(err))
@@ -274,12 +641,50 @@ func main() {
 	_, err = cronRunner.Add("@every 1h", func(ctx context.Context) {
 		if err := positionSyncSvc.SnapshotPortfolio(ctx); err != nil {
 			logger.Warn("portfolio snapshot failed", zap.Error(err))
+			return
+		}
+		if _, err := portfolioAnomalies.Check(ctx); err != nil {
+			logger.Warn("portfolio anomaly check failed", zap.Error(err))
 		}
 	})
 	if err != nil {
 		logger.Warn("cron register portfolio snapshot failed", zap.Error(err))
 	}
 
+	_, err = cronRunner.Add("@every 1h", func(ctx context.Context) {
+		n, err := store.DeleteExpiredIdempotencyKeys(ctx, time.Now().UTC())
+		if err != nil {
+			logger.Warn("delete expired idempotency keys failed", zap.Error(err))
+			return
+		}
+		if n > 0 {
+			logger.Info("deleted expired idempotency keys", zap.Int64("count", n))
+		}
+	})
+	if err != nil {
+		logger.Warn("cron register idempotency key cleanup failed", zap.Error(err))
+	}
+
+	if cfg.ClobStream.TradeRetention > 0 || cfg.ClobStream.TradeMaxPerToken > 0 {
+		_, err = cronRunner.Add("@every 1h", func(ctx context.Context) {
+			var before time.Time
+			if cfg.ClobStream.TradeRetention > 0 {
+				before = time.Now().UTC().Add(-cfg.ClobStream.TradeRetention)
+			}
+			n, err := store.PruneTrades(ctx, before, cfg.ClobStream.TradeMaxPerToken)
+			if err != nil {
+				logger.Warn("prune trades failed", zap.Error(err))
+				return
+			}
+			if n > 0 {
+				logger.Info("pruned trades", zap.Int64("count", n))
+			}
+		})
+		if err != nil {
+			logger.Warn("cron register trade prune failed", zap.Error(err))
+		}
+	}
+
 	_, err = cronRunner.Add("@every 5s", func(ctx context.Context) {
 		if err := clobExecutor.PollOrders(ctx); err != nil {
 			logger.Warn("order poll failed", zap.Error(err))
@@ -288,6 +693,72 @@ func main() {
 	if err != nil {
 		logger.Warn("cron register order poll failed", zap.Error(err))
 	}
+
+	if spec := strings.TrimSpace(cfg.Cron.PlanTriggers); spec != "" {
+		_, err = cronRunner.Add(spec, func(ctx context.Context) {
+			n, err := planTriggers.RunDue(ctx, time.Now().UTC())
+			if err != nil {
+				logger.Warn("plan triggers failed", zap.Error(err))
+				return
+			}
+			if n > 0 {
+				logger.Info("plan triggers fired", zap.Int("count", n))
+			}
+		})
+		if err != nil {
+			logger.Warn("cron register plan triggers failed", zap.Error(err))
+		}
+	}
+	if cfg.Regime.Enabled && cfg.Regime.Interval > 0 {
+		_, err = cronRunner.Add("@every "+cfg.Regime.Interval.String(), func(ctx context.Context) {
+			if _, err := regimeDetector.Run(ctx, time.Now().UTC()); err != nil {
+				logger.Warn("regime detection failed", zap.Error(err))
+			}
+		})
+		if err != nil {
+			logger.Warn("cron register regime detection failed", zap.Error(err))
+		}
+	}
+	if cfg.Capacity.Enabled && cfg.Capacity.Interval > 0 {
+		_, err = cronRunner.Add("@every "+cfg.Capacity.Interval.String(), func(ctx context.Context) {
+			if _, err := strategyCapacity.RunOnce(ctx, time.Now().UTC()); err != nil {
+				logger.Warn("strategy capacity estimation failed", zap.Error(err))
+			}
+		})
+		if err != nil {
+			logger.Warn("cron register strategy capacity failed", zap.Error(err))
+		}
+	}
+	if cfg.StrategyDrift.Enabled && cfg.StrategyDrift.Interval > 0 {
+		_, err = cronRunner.Add("@every "+cfg.StrategyDrift.Interval.String(), func(ctx context.Context) {
+			if _, err := strategyDrift.RunOnce(ctx, time.Now().UTC()); err != nil {
+				logger.Warn("strategy drift evaluation failed", zap.Error(err))
+			}
+		})
+		if err != nil {
+			logger.Warn("cron register strategy drift failed", zap.Error(err))
+		}
+	}
+	if cfg.Onchain.Enabled && cfg.Onchain.Interval > 0 {
+		_, err = cronRunner.Add("@every "+cfg.Onchain.Interval.String(), func(ctx context.Context) {
+			if _, err := onchainConditions.RunOnce(ctx, time.Now().UTC()); err != nil {
+				logger.Warn("onchain condition sync failed", zap.Error(err))
+			}
+		})
+		if err != nil {
+			logger.Warn("cron register onchain condition sync failed", zap.Error(err))
+		}
+	}
+	if cfg.SLO.Enabled && cfg.SLO.Interval > 0 {
+		_, err = cronRunner.Add("@every "+cfg.SLO.Interval.String(), func(ctx context.Context) {
+			if _, err := sloSvc.RunOnce(ctx, time.Now().UTC()); err != nil {
+				logger.Warn("slo evaluation failed", zap.Error(err))
+			}
+		})
+		if err != nil {
+			logger.Warn("cron register slo evaluation failed", zap.Error(err))
+		}
+	}
 	cronRunner.Start()
 	defer cronRunner.Stop()
 
@@ -310,9 +781,16 @@ func main() {
 	if settingsSvc.IsEnabled(baseCtx, service.FeatureCLOBStream, true) {
 		go func() {
 			err := streamService.RunMarketStream(baseCtx, service.CLOBStreamOptions{
-				URL:             cfg.ClobStream.URL,
-				RefreshInterval: cfg.ClobStream.RefreshInterval,
-				MaxAssets:       cfg.ClobStream.MaxAssets,
+				URL:               cfg.ClobStream.URL,
+				RefreshInterval:   cfg.ClobStream.RefreshInterval,
+				MaxAssets:         cfg.ClobStream.MaxAssets,
+				HeartbeatInterval: cfg.ClobStream.HeartbeatInterval,
+				PingTimeout:       cfg.ClobStream.PingTimeout,
+				StallTimeout:      cfg.ClobStream.StallTimeout,
+				BackoffMin:        cfg.ClobStream.BackoffMin,
+				BackoffMax:        cfg.ClobStream.BackoffMax,
+				Endpoints:         clobWSPool,
+				FaultHook:         faults.Hook(chaos.TargetWS),
 			})
 			if err != nil && !errors.Is(err, context.Canceled) {
 				logger.Warn("clob stream stopped", zap.Error(err))
@@ -352,6 +830,9 @@ func main() {
 
 	if settingsSvc.IsEnabled(baseCtx, service.FeatureStrategyEngine, false) {
 		hub := signalhub.NewHub(store, logger)
+		catalogService.Anomalies.SetIngest(hub.Ingest)
+		hub.SetScheduler(&signalhub.AdaptiveScheduler{Repo: store, Logger: logger, Config: cfg.SignalSources.Adaptive})
+		v2Signals.Hub = hub
 		hub.Register(&signalhub.SettlementHistoryCollector{
 			Repo:       store,
 			Logger:     logger,
@@ -386,6 +867,14 @@ func main() {
 				TriggerPct:    cfg.SignalSources.BinancePrice.TriggerPct,
 			})
 		}
+		if cfg.SignalSources.CryptoSymbol.Enabled && settingsSvc.IsEnabled(baseCtx, service.FeatureSignalBinancePrice, false) {
+			hub.Register(&signalhub.CryptoSymbolCollector{
+				Repo:   store,
+				Logger: logger,
+				Config: cfg.SignalSources.CryptoSymbol,
+				Price:  cfg.SignalSources.BinancePrice,
+			})
+		}
 		if settingsSvc.IsEnabled(baseCtx, service.FeatureSignalPriceChange, false) {
 			hub.Register(&signalhub.PriceChangeCollector{
 				Repo:   store,
@@ -407,17 +896,50 @@ func main() {
 				Config: cfg.SignalSources.Certainty,
 			})
 		}
+		if settingsSvc.IsEnabled(baseCtx, service.FeatureSignalMarketClose, false) {
+			hub.Register(&signalhub.MarketCloseCollector{
+				Repo:   store,
+				Logger: logger,
+				Config: cfg.SignalSources.MarketClose,
+			})
+		}
+		if settingsSvc.IsEnabled(baseCtx, service.FeatureSignalSmartMoney, false) {
+			hub.Register(&signalhub.SmartMoneyCollector{
+				Repo:   store,
+				Logger: logger,
+				Config: cfg.SignalSources.SmartMoney,
+			})
+		}
+		oppManager := &opportunity.Manager{Repo: store, Logger: logger, MaxActive: cfg.StrategyEngine.MaxOpportunities}
+		if cfg.OpportunityDecay.Enabled {
+			oppManager.Decay = opportunity.DecayPoliciesFromConfig(cfg.OpportunityDecay.Policies)
+			oppManager.MinDecayFactor = cfg.OpportunityDecay.MinFactor
+		}
+		preMarketFDV := &strategy.PreMarketFDVStrategy{Repo: store, Logger: logger}
+		if trCfg := cfg.StrategyEngine.TokenRisk; trCfg.Enabled {
+			if paasClient != nil {
+				preMarketFDV.TokenRisk = &paas.TokenRiskClient{Client: paasClient, TTL: trCfg.CacheTTL, Timeout: trCfg.Timeout}
+			} else {
+				logger.Warn("token risk enabled but paas client is not configured")
+			}
+		}
 		stratEngine := &strategy.Engine{
 			Repo:             store,
 			Hub:              hub,
 			Logger:           logger,
 			Risk:             riskMgr,
-			Opps:             &opportunity.Manager{Repo: store, Logger: logger, MaxActive: cfg.StrategyEngine.MaxOpportunities},
+			Opps:             oppManager,
+			Quality:          signalQualitySvc,
 			StrategyDefaults: cfg.StrategyDefaults,
+			NewStrategyStage: cfg.StrategyEngine.NewStrategyStage,
+			CappedMaxUSD:     decimal.NewFromFloat(cfg.StrategyEngine.CappedMaxUSD),
+			Running: func(ctx context.Context) bool {
+				return settingsSvc.IsEnabled(ctx, service.FeatureStrategyEngine, false)
+			},
 			Evaluators: []strategy.StrategyEvaluator{
 				&strategy.ArbitrageSumStrategy{Repo: store, Logger: logger},
 				&strategy.SystematicNOStrategy{Repo: store, Logger: logger},
-				&strategy.PreMarketFDVStrategy{Repo: store, Logger: logger},
+				preMarketFDV,
 				&strategy.NewsAlphaStrategy{Repo: store, Logger: logger},
 				&strategy.VolatilityArbStrategy{Repo: store, Logger: logger},
 				&strategy.WeatherStrategy{Repo: store, Logger: logger},
@@ -427,6 +949,8 @@ func main() {
 				&strategy.CertaintySweepStrategy{Repo: store, Logger: logger},
 				&strategy.LiquidityRewardStrategy{Repo: store, Logger: logger},
 			&strategy.MarketAnomalyStrategy{Repo: store, Logger: logger},
+				&strategy.CopyFlowStrategy{Repo: store, Logger: logger},
+				&strategy.EnsembleStrategy{Logger: logger},
 			},
 		}
 		go func() {
@@ -439,6 +963,11 @@ func main() {
 				logger.Warn("strategy engine stopped", zap.Error(err))
 			}
 		}()
+		go func() {
+			if err := oppManager.Run(baseCtx, cfg.OpportunityDecay.TickInterval); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Warn("opportunity decay stopped", zap.Error(err))
+			}
+		}()
 		go func() {
 			updater := &strategy.StatsUpdater{
 				Repo:     store,
@@ -464,6 +993,23 @@ func main() {
 		if err != nil {
 			logger.Warn("cron register signal cleanup failed", zap.Error(err))
 		}
+
+		// Periodic cleanup: evaluation runs are debugging data with a fixed retention.
+		if cfg.StrategyEngine.RunRetention > 0 {
+			_, err = cronRunner.Add("@every 1h", func(ctx context.Context) {
+				n, err := store.DeleteEvaluationRunsBefore(ctx, time.Now().UTC().Add(-cfg.StrategyEngine.RunRetention))
+				if err != nil {
+					logger.Warn("delete evaluation runs failed", zap.Error(err))
+					return
+				}
+				if n > 0 {
+					logger.Info("deleted evaluation runs", zap.Int64("count", n))
+				}
+			})
+			if err != nil {
+				logger.Warn("cron register evaluation run cleanup failed", zap.Error(err))
+			}
+		}
 	}
 
 	ingestor := &service.SettlementIngestService{
@@ -472,6 +1018,10 @@ func main() {
 		Config: cfg.SettlementIngest,
 		Logger: logger,
 		Flags:  settingsSvc,
+		Enrichers: []service.SettlementEnricher{
+			&service.InitialPriceEnricher{Repo: store},
+		},
+		Governor: gov,
 	}
 	go func() {
 		if err := ingestor.Run(baseCtx); err != nil && !errors.Is(err, context.Canceled) {
@@ -479,35 +1029,86 @@ func main() {
 		}
 	}()
 
-	auto := &service.AutoExecutorService{
-		Repo:     store,
-		Risk:     riskMgr,
-		Logger:   logger,
-		Config:   cfg.AutoExecutor,
-		Flags:    settingsSvc,
-		Executor: clobExecutor,
+	for _, pool := range []*clob.EndpointPool{clobRESTPool, clobWSPool} {
+		go func(pool *clob.EndpointPool) {
+			if err := pool.Run(baseCtx, cfg.ClobEndpoints.ProbeInterval); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Warn("clob endpoint prober stopped", zap.Error(err))
+			}
+		}(pool)
+	}
+
+	go func() {
+		if err := cashOpsSvc.Run(baseCtx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn("cash ops reconciler stopped", zap.Error(err))
+		}
+	}()
+
+	go func() {
+		if err := retentionSvc.Run(baseCtx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn("retention purger stopped", zap.Error(err))
+		}
+	}()
+
+	go func() {
+		if err := positionImportSvc.Run(baseCtx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn("external position reconciler stopped", zap.Error(err))
+		}
+	}()
+
+	go func() {
+		if err := gapSvc.Run(baseCtx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn("market data gap service stopped", zap.Error(err))
+		}
+	}()
+
+	if eventListener != nil {
+		go func() {
+			if err := eventListener.Run(baseCtx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Warn("event listener stopped", zap.Error(err))
+			}
+		}()
 	}
 	go func() {
 		if err := auto.Run(baseCtx); err != nil && !errors.Is(err, context.Canceled) {
 			logger.Warn("auto executor stopped", zap.Error(err))
 		}
 	}()
+	go func() {
+		if err := campaignSvc.Run(baseCtx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Warn("campaign service stopped", zap.Error(err))
+		}
+	}()
+	if cfg.Rewards.Enabled {
+		rewardTracker := &service.RewardTracker{Repo: store, Logger: logger, Interval: cfg.Rewards.SampleInterval}
+		go func() {
+			if err := rewardTracker.Run(baseCtx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Warn("reward tracker stopped", zap.Error(err))
+			}
+		}()
+	}
 
 	positionManager := &service.PositionManager{
 		Repo:   store,
 		Logger: logger,
 		Flags:  settingsSvc,
 	}
+	positionInterval := 30 * time.Second
+	if cfg.Events.Enabled {
+		positionManager.Wake = eventBus.Subscribe(64, events.TopicFillCreated, events.TopicPlanStatus)
+		positionInterval = eventPollInterval(cfg.Events, positionInterval)
+	}
 	go func() {
-		if err := positionManager.Run(baseCtx, 30*time.Second); err != nil && !errors.Is(err, context.Canceled) {
+		if err := positionManager.Run(baseCtx, positionInterval); err != nil && !errors.Is(err, context.Canceled) {
 			logger.Warn("position manager stopped", zap.Error(err))
 		}
 	}()
 
 	dailyStats := &service.DailyStatsService{
-		Repo:   store,
-		Logger: logger,
-		Flags:  settingsSvc,
+		Repo:     store,
+		Logger:   logger,
+		Flags:    settingsSvc,
+		Governor: gov,
+		Calendar: tradingCalendar,
 	}
 	go func() {
 		if err := dailyStats.Run(baseCtx, 6*time.Hour); err != nil && !errors.Is(err, context.Canceled) {
@@ -534,6 +1135,19 @@ func main() {
 			errCh <- err
 		}
 	}()
+	if grpcSrv != nil {
+		go func() {
+			lis, err := net.Listen("tcp", cfg.Server.GRPCAddr)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			logger.Info("grpc server starting", zap.String("addr", cfg.Server.GRPCAddr))
+			if err := grpcSrv.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				errCh <- err
+			}
+		}()
+	}
 
 	select {
 	case <-ctx.Done():
@@ -545,6 +1159,35 @@ func main() {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	_ = srv.Shutdown(shutdownCtx)
+	if grpcSrv != nil {
+		grpcAPI.Stop()
+		stopped := make(chan struct{})
+		go func() {
+			grpcSrv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcSrv.Stop()
+		}
+	}
+	if paasClient != nil && paasClient.Logs != nil {
+		stopLogs()
+		select {
+		case <-paasClient.Logs.Stopped():
+		case <-shutdownCtx.Done():
+		}
+	}
+}
+
+// eventPollInterval is the fallback poll of a service woken by events: no
+// more often than the events poll interval.
+func eventPollInterval(cfg config.EventsConfig, interval time.Duration) time.Duration {
+	if cfg.PollInterval > interval {
+		return cfg.PollInterval
+	}
+	return interval
 }
 
 func parseClosedFilter(value string) *bool {
@@ -564,7 +1207,8 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization,Idempotency-Key,X-Request-Id")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-Id")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		if c.Request.Method == http.MethodOptions {
 			c.AbortWithStatus(204)
@@ -574,7 +1218,7 @@ func corsMiddleware() gin.HandlerFunc {
 	}
 }
 
-func initPaaSClient(logger *zap.Logger) *paas.Client {
+func initPaaSClient(logger *zap.Logger, logsCfg config.PaaSLogsConfig) *paas.Client {
 	base := strings.TrimSpace(os.Getenv("EASYWEB3_API_BASE"))
 	apiKey := strings.TrimSpace(os.Getenv("EASYWEB3_API_KEY"))
 	if base == "" || apiKey == "" {
@@ -582,13 +1226,24 @@ func initPaaSClient(logger *zap.Logger) *paas.Client {
 	}
 
 	p := &paas.Client{BaseURL: base, APIKey: apiKey}
+	p.Logs = paas.NewLogBuffer(p, paas.LogBufferOptions{
+		Capacity:      logsCfg.BufferSize,
+		BatchSize:     logsCfg.BatchSize,
+		FlushInterval: logsCfg.FlushInterval,
+		SendTimeout:   logsCfg.SendTimeout,
+		MaxBackoff:    logsCfg.MaxBackoff,
+		SpillPath:     logsCfg.SpillPath,
+		SpillMaxBytes: logsCfg.SpillMaxBytes,
+	}, logger)
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	if err := p.Login(ctx); err != nil {
+		// Logs are buffered and spilled until the PaaS becomes reachable;
+		// CreateLog logs in again on demand.
 		if logger != nil {
-			logger.Warn("paas login failed (logs/notify disabled)", zap.Error(err))
+			logger.Warn("paas login failed (logs buffered until reachable)", zap.Error(err))
 		}
-		return nil
+		return p
 	}
 	if logger != nil {
 		logger.Info("paas login ok")
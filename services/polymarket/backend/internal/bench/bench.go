@@ -0,0 +1,240 @@
+// Package bench measures throughput of the hot paths (strategy ticks, signal
+// ingestion and the repository queries behind them) against a seeded
+// database. Inputs come from a fixed random seed so runs are comparable, and
+// a report can be checked against a saved baseline to catch p95 latency
+// regressions.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+)
+
+// Options sizes a run. Zero values take the defaults.
+type Options struct {
+	// Scenarios to run; empty runs all of them, in Names order.
+	Scenarios   []string `json:"scenarios,omitempty"`
+	Iterations  int      `json:"iterations"`
+	Concurrency int      `json:"concurrency"`
+	// Markets is the number of binary markets seeded, four per event.
+	Markets int   `json:"markets"`
+	Seed    int64 `json:"seed"`
+}
+
+func (o Options) withDefaults() Options {
+	if o.Iterations <= 0 {
+		o.Iterations = 500
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	if o.Markets <= 0 {
+		o.Markets = 200
+	}
+	if o.Seed == 0 {
+		o.Seed = 1
+	}
+	return o
+}
+
+// Report is the JSON document a run produces and a baseline is read from.
+type Report struct {
+	StartedAt  time.Time        `json:"started_at"`
+	GoVersion  string           `json:"go_version"`
+	GOOS       string           `json:"goos"`
+	GOARCH     string           `json:"goarch"`
+	NumCPU     int              `json:"num_cpu"`
+	Driver     string           `json:"driver"`
+	Options    Options          `json:"options"`
+	Scenarios  []ScenarioResult `json:"scenarios"`
+	DurationMs float64          `json:"duration_ms"`
+}
+
+// ScenarioResult holds one scenario's latency distribution in milliseconds.
+type ScenarioResult struct {
+	Name      string  `json:"name"`
+	Ops       int     `json:"ops"`
+	Errors    int     `json:"errors"`
+	OpsPerSec float64 `json:"ops_per_sec"`
+	MeanMs    float64 `json:"mean_ms"`
+	P50Ms     float64 `json:"p50_ms"`
+	P95Ms     float64 `json:"p95_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+	MaxMs     float64 `json:"max_ms"`
+}
+
+// Scenario is a load generator. Prepare runs once, untimed, and returns the
+// operation each iteration calls with its index.
+type Scenario struct {
+	Name    string
+	Prepare func(ctx context.Context, env *Env) (func(ctx context.Context, i int) error, error)
+}
+
+// Find returns the named scenario.
+func Find(name string) (Scenario, bool) {
+	for _, s := range scenarios {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Scenario{}, false
+}
+
+// Names lists the scenarios in run order.
+func Names() []string {
+	out := make([]string, 0, len(scenarios))
+	for _, s := range scenarios {
+		out = append(out, s.Name)
+	}
+	return out
+}
+
+// OpenMemory opens a private in-memory SQLite database with the full schema.
+// The driver keeps a single connection, which holds the database.
+func OpenMemory() (*db.DB, error) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(conn); err != nil {
+		_ = db.Close(conn)
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Run seeds gdb and runs the selected scenarios one after another.
+func Run(ctx context.Context, gdb *gorm.DB, opts Options) (Report, error) {
+	opts = opts.withDefaults()
+	selected := make([]Scenario, 0, len(scenarios))
+	if len(opts.Scenarios) == 0 {
+		selected = append(selected, scenarios...)
+	} else {
+		for _, name := range opts.Scenarios {
+			s, ok := Find(name)
+			if !ok {
+				return Report{}, fmt.Errorf("unknown scenario %q", name)
+			}
+			selected = append(selected, s)
+		}
+	}
+	rep := Report{
+		StartedAt: time.Now().UTC(),
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		Driver:    gdb.Dialector.Name(),
+		Options:   opts,
+	}
+	env, err := Seed(ctx, gdb, opts.Markets, opts.Seed)
+	if err != nil {
+		return Report{}, fmt.Errorf("seed: %w", err)
+	}
+	start := time.Now()
+	for _, s := range selected {
+		res, err := runScenario(ctx, env, s, opts)
+		if err != nil {
+			return Report{}, fmt.Errorf("%s: %w", s.Name, err)
+		}
+		rep.Scenarios = append(rep.Scenarios, res)
+	}
+	rep.DurationMs = ms(time.Since(start))
+	return rep, nil
+}
+
+func runScenario(ctx context.Context, env *Env, s Scenario, opts Options) (ScenarioResult, error) {
+	op, err := s.Prepare(ctx, env)
+	if err != nil {
+		return ScenarioResult{}, err
+	}
+	lat := make([]time.Duration, opts.Iterations)
+	errs := make([]bool, opts.Iterations)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				t0 := time.Now()
+				errs[i] = op(ctx, i) != nil
+				lat[i] = time.Since(t0)
+			}
+		}()
+	}
+	for i := 0; i < opts.Iterations; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return ScenarioResult{}, err
+	}
+	wall := time.Since(start)
+	res := ScenarioResult{Name: s.Name, Ops: opts.Iterations}
+	for _, failed := range errs {
+		if failed {
+			res.Errors++
+		}
+	}
+	if wall > 0 {
+		res.OpsPerSec = float64(res.Ops) / wall.Seconds()
+	}
+	sort.Slice(lat, func(i, j int) bool { return lat[i] < lat[j] })
+	var total time.Duration
+	for _, d := range lat {
+		total += d
+	}
+	res.MeanMs = ms(total / time.Duration(len(lat)))
+	res.P50Ms = ms(percentile(lat, 0.50))
+	res.P95Ms = ms(percentile(lat, 0.95))
+	res.P99Ms = ms(percentile(lat, 0.99))
+	res.MaxMs = ms(lat[len(lat)-1])
+	return res, nil
+}
+
+// percentile uses the nearest-rank method on sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// inputs returns n indexes in [0, size) from a seeded source, so every run
+// with the same seed touches the same rows in the same order.
+func inputs(seed int64, n, size int) []int {
+	r := rand.New(rand.NewSource(seed))
+	out := make([]int, n)
+	for i := range out {
+		out[i] = r.Intn(size)
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package bench
+
+import (
+	"context"
+	"testing"
+
+	"polymarket/internal/db"
+)
+
+func TestRun_SeededMemoryDB(t *testing.T) {
+	conn, err := OpenMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+
+	rep, err := Run(context.Background(), conn.Gorm, Options{Iterations: 20, Concurrency: 2, Markets: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Scenarios) != len(Names()) {
+		t.Fatalf("scenarios = %d, want %d", len(rep.Scenarios), len(Names()))
+	}
+	for _, s := range rep.Scenarios {
+		if s.Ops != 20 || s.Errors != 0 || s.P95Ms < s.P50Ms || s.MaxMs < s.P99Ms {
+			t.Fatalf("%s: %+v", s.Name, s)
+		}
+	}
+	if _, err := Run(context.Background(), conn.Gorm, Options{Scenarios: []string{"nope"}}); err == nil {
+		t.Fatal("unknown scenario accepted")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := Report{Scenarios: []ScenarioResult{
+		{Name: "a", P95Ms: 10},
+		{Name: "b", P95Ms: 0.2},
+		{Name: "gone", P95Ms: 1},
+	}}
+	cur := Report{Scenarios: []ScenarioResult{
+		{Name: "a", P95Ms: 13},
+		{Name: "b", P95Ms: 0.4},
+		{Name: "new", P95Ms: 5},
+	}}
+	cmp := Compare(base, cur, 20)
+	if cmp.Passed {
+		t.Fatal("a grew 30% and should fail")
+	}
+	if !cmp.Scenarios[0].Regressed || cmp.Scenarios[1].Regressed || cmp.Scenarios[2].Regressed {
+		t.Fatalf("deltas: %+v", cmp.Scenarios)
+	}
+	if len(cmp.Missing) != 1 || cmp.Missing[0] != "gone" {
+		t.Fatalf("missing = %v", cmp.Missing)
+	}
+	if !Compare(base, cur, 50).Passed {
+		t.Fatal("30% is within a 50% threshold")
+	}
+}
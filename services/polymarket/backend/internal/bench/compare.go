@@ -0,0 +1,93 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// DefaultThresholdPct is the p95 growth a scenario may show before it counts
+// as a regression.
+const DefaultThresholdPct = 20
+
+// minDeltaMs ignores p95 growth below this many milliseconds; sub-millisecond
+// scenarios jitter by more than any sensible percentage.
+const minDeltaMs = 0.5
+
+// Comparison is a report checked against a baseline.
+type Comparison struct {
+	ThresholdPct float64         `json:"threshold_pct"`
+	Passed       bool            `json:"passed"`
+	Scenarios    []ScenarioDelta `json:"scenarios"`
+	// Missing lists baseline scenarios the current report did not run.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// ScenarioDelta is one scenario's p95 change; DeltaPct is relative to the
+// baseline.
+type ScenarioDelta struct {
+	Name          string  `json:"name"`
+	BaselineP95Ms float64 `json:"baseline_p95_ms"`
+	CurrentP95Ms  float64 `json:"current_p95_ms"`
+	DeltaPct      float64 `json:"delta_pct"`
+	Regressed     bool    `json:"regressed"`
+}
+
+// Compare fails every scenario whose p95 grew by more than thresholdPct (and
+// minDeltaMs). Scenarios absent from the baseline are reported but never fail.
+func Compare(baseline, current Report, thresholdPct float64) Comparison {
+	if thresholdPct <= 0 {
+		thresholdPct = DefaultThresholdPct
+	}
+	out := Comparison{ThresholdPct: thresholdPct, Passed: true}
+	base := make(map[string]ScenarioResult, len(baseline.Scenarios))
+	for _, s := range baseline.Scenarios {
+		base[s.Name] = s
+	}
+	seen := map[string]struct{}{}
+	for _, cur := range current.Scenarios {
+		seen[cur.Name] = struct{}{}
+		b, ok := base[cur.Name]
+		if !ok {
+			out.Scenarios = append(out.Scenarios, ScenarioDelta{Name: cur.Name, CurrentP95Ms: cur.P95Ms})
+			continue
+		}
+		d := ScenarioDelta{Name: cur.Name, BaselineP95Ms: b.P95Ms, CurrentP95Ms: cur.P95Ms}
+		if b.P95Ms > 0 {
+			d.DeltaPct = (cur.P95Ms - b.P95Ms) / b.P95Ms * 100
+		}
+		d.Regressed = d.DeltaPct > thresholdPct && cur.P95Ms-b.P95Ms > minDeltaMs
+		if d.Regressed {
+			out.Passed = false
+		}
+		out.Scenarios = append(out.Scenarios, d)
+	}
+	for _, s := range baseline.Scenarios {
+		if _, ok := seen[s.Name]; !ok {
+			out.Missing = append(out.Missing, s.Name)
+		}
+	}
+	return out
+}
+
+// ReadReport loads a report written by WriteReport.
+func ReadReport(path string) (Report, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Report{}, err
+	}
+	var rep Report
+	if err := json.Unmarshal(raw, &rep); err != nil {
+		return Report{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return rep, nil
+}
+
+// WriteReport writes rep as indented JSON.
+func WriteReport(path string, rep Report) error {
+	raw, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(raw, '\n'), 0o644)
+}
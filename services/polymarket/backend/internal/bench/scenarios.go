@@ -0,0 +1,113 @@
+package bench
+
+import (
+	"context"
+	"time"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/risk"
+	"polymarket/internal/signal"
+	"polymarket/internal/strategy"
+)
+
+const (
+	ScenarioStrategyTick  = "strategy_tick"
+	ScenarioSignalIngest  = "signal_ingest"
+	ScenarioEventMarkets  = "repo_event_markets"
+	ScenarioOrderbooks    = "repo_orderbooks"
+	ScenarioRecentSignals = "repo_recent_signals"
+)
+
+// booksPerQuery is the token batch of the order book scenario, about one
+// evaluator's worth of legs.
+const booksPerQuery = 20
+
+var scenarios = []Scenario{
+	{Name: ScenarioStrategyTick, Prepare: prepareStrategyTick},
+	{Name: ScenarioSignalIngest, Prepare: prepareSignalIngest},
+	{Name: ScenarioEventMarkets, Prepare: prepareEventMarkets},
+	{Name: ScenarioOrderbooks, Prepare: prepareOrderbooks},
+	{Name: ScenarioRecentSignals, Prepare: prepareRecentSignals},
+}
+
+// prepareStrategyTick evaluates one arb_sum_deviation signal per iteration
+// and passes the result through the risk filter, as an engine worker does.
+func prepareStrategyTick(ctx context.Context, env *Env) (func(context.Context, int) error, error) {
+	ev := &strategy.ArbitrageSumStrategy{Repo: env.Store}
+	rm := &risk.Manager{Repo: env.Store}
+	picks := inputs(env.Seed, 1<<16, len(env.EventIDs))
+	return func(ctx context.Context, i int) error {
+		eventID := env.EventIDs[picks[i%len(picks)]]
+		sig := models.Signal{SignalType: "arb_sum_deviation", Source: "bench", EventID: &eventID, CreatedAt: time.Now().UTC()}
+		opps, err := ev.Evaluate(ctx, []models.Signal{sig})
+		if err != nil {
+			return err
+		}
+		rm.Filter(opps)
+		return nil
+	}, nil
+}
+
+// prepareSignalIngest pushes signals through the hub. Iterations are an hour
+// apart, beyond every dedup window, so none is dropped as a duplicate.
+func prepareSignalIngest(ctx context.Context, env *Env) (func(context.Context, int) error, error) {
+	hub := signal.NewHub(env.Store, nil)
+	base := time.Now().UTC()
+	picks := inputs(env.Seed, 1<<16, len(env.TokenIDs))
+	return func(ctx context.Context, i int) error {
+		tokenID := env.TokenIDs[picks[i%len(picks)]]
+		hub.Ingest(ctx, models.Signal{
+			SignalType: "price_anomaly",
+			Source:     "bench",
+			TokenID:    &tokenID,
+			Strength:   0.5,
+			CreatedAt:  base.Add(time.Duration(i) * time.Hour),
+		})
+		return nil
+	}, nil
+}
+
+func prepareEventMarkets(ctx context.Context, env *Env) (func(context.Context, int) error, error) {
+	picks := inputs(env.Seed, 1<<16, len(env.EventIDs))
+	return func(ctx context.Context, i int) error {
+		markets, err := env.Store.ListMarketsByEventID(ctx, env.EventIDs[picks[i%len(picks)]])
+		if err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(markets))
+		for _, m := range markets {
+			ids = append(ids, m.ID)
+		}
+		_, err = env.Store.ListTokensByMarketIDs(ctx, ids)
+		return err
+	}, nil
+}
+
+func prepareOrderbooks(ctx context.Context, env *Env) (func(context.Context, int) error, error) {
+	picks := inputs(env.Seed, 1<<16, len(env.TokenIDs))
+	return func(ctx context.Context, i int) error {
+		ids := make([]string, booksPerQuery)
+		for j := range ids {
+			ids[j] = env.TokenIDs[picks[(i*booksPerQuery+j)%len(picks)]]
+		}
+		_, err := env.Store.ListOrderbookLatestByTokenIDs(ctx, ids)
+		return err
+	}, nil
+}
+
+func prepareRecentSignals(ctx context.Context, env *Env) (func(context.Context, int) error, error) {
+	sigType := "arb_sum_deviation"
+	desc := false
+	return func(ctx context.Context, i int) error {
+		since := time.Now().UTC().Add(-time.Hour)
+		_, err := env.Store.ListSignals(ctx, repository.ListSignalsParams{
+			Limit:   100,
+			Type:    &sigType,
+			Since:   &since,
+			OrderBy: "created_at",
+			Asc:     &desc,
+		})
+		return err
+	}, nil
+}
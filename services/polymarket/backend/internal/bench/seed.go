@@ -0,0 +1,116 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	gormrepo "polymarket/internal/repository/gorm"
+)
+
+const (
+	marketsPerEvent = 4
+	seedSignals     = 2000
+	seedBatch       = 200
+)
+
+// Env is a seeded database and the IDs scenarios draw their inputs from.
+type Env struct {
+	Store    *gormrepo.Store
+	Seed     int64
+	EventIDs []string
+	// TokenIDs holds the YES and NO token of every market.
+	TokenIDs []string
+}
+
+// Seed fills gdb with markets binary markets grouped four to an event, their
+// tokens and order books, and a backlog of signals. YES asks are drawn so
+// that about a third of the events sum below 1 and give arb_sum work to do.
+func Seed(ctx context.Context, gdb *gorm.DB, markets int, seed int64) (*Env, error) {
+	r := rand.New(rand.NewSource(seed))
+	now := time.Now().UTC()
+	env := &Env{Store: gormrepo.New(gdb), Seed: seed}
+	raw := datatypes.JSON([]byte(`{}`))
+
+	var (
+		events []models.Event
+		mkts   []models.Market
+		tokens []models.Token
+		books  []models.OrderbookLatest
+	)
+	for e := 0; e*marketsPerEvent < markets; e++ {
+		eventID := fmt.Sprintf("bench-e%d", e)
+		env.EventIDs = append(env.EventIDs, eventID)
+		events = append(events, models.Event{ID: eventID, Slug: eventID, Title: eventID, Active: true, LastSeenAt: now, RawJSON: raw})
+		// Scale YES asks so the event sums to 0.9-1.1.
+		target := 0.9 + r.Float64()*0.2
+		for m := 0; m < marketsPerEvent && e*marketsPerEvent+m < markets; m++ {
+			marketID := fmt.Sprintf("%s-m%d", eventID, m)
+			mkts = append(mkts, models.Market{
+				ID: marketID, EventID: eventID, Question: marketID, ConditionID: marketID,
+				TickSize: decimal.RequireFromString("0.01"), Active: true, LastSeenAt: now, RawJSON: raw,
+			})
+			yesAsk := round2(target / marketsPerEvent)
+			noAsk := round2(1.02 - yesAsk)
+			for i, side := range []struct {
+				outcome string
+				ask     float64
+			}{{"Yes", yesAsk}, {"No", noAsk}} {
+				tokenID := fmt.Sprintf("%s-t%d", marketID, i)
+				env.TokenIDs = append(env.TokenIDs, tokenID)
+				tokens = append(tokens, models.Token{ID: tokenID, MarketID: marketID, Outcome: side.outcome, OutcomeIndex: i, LastSeenAt: now, RawJSON: raw})
+				books = append(books, seedBook(tokenID, side.ask, now))
+			}
+		}
+	}
+	signals := make([]models.Signal, 0, seedSignals)
+	for i := 0; i < seedSignals; i++ {
+		eventID := env.EventIDs[r.Intn(len(env.EventIDs))]
+		signals = append(signals, models.Signal{
+			SignalType: "arb_sum_deviation",
+			Source:     "bench",
+			EventID:    &eventID,
+			Strength:   r.Float64(),
+			Payload:    raw,
+			CreatedAt:  now.Add(-time.Duration(r.Intn(3600)) * time.Second),
+		})
+	}
+
+	err := gdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, items := range []any{&events, &mkts, &tokens, &books, &signals} {
+			if err := tx.CreateInBatches(items, seedBatch).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func seedBook(tokenID string, ask float64, now time.Time) models.OrderbookLatest {
+	bid := round2(ask - 0.01)
+	mid := (ask + bid) / 2
+	return models.OrderbookLatest{
+		TokenID:    tokenID,
+		SnapshotTS: now,
+		BidsJSON:   datatypes.JSON([]byte(fmt.Sprintf(`[{"price":"%.2f","size":"500"}]`, bid))),
+		AsksJSON:   datatypes.JSON([]byte(fmt.Sprintf(`[{"price":"%.2f","size":"500"}]`, ask))),
+		BestBid:    &bid,
+		BestAsk:    &ask,
+		Mid:        &mid,
+		UpdatedAt:  now,
+	}
+}
+
+func round2(v float64) float64 {
+	return float64(int(v*100+0.5)) / 100
+}
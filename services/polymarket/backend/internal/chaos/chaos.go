@@ -0,0 +1,298 @@
+// Package chaos injects faults into outbound clients, the database and the
+// CLOB market stream so operators can watch retries, circuit breakers and
+// reconcilers react before trusting live trading. Faults live in memory
+// only, expire on their own, and the nil *Injector injects nothing.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"polymarket/internal/config"
+)
+
+// Fault targets.
+const (
+	TargetGamma = "gamma"
+	TargetCLOB  = "clob"
+	TargetDB    = "db"
+	TargetWS    = "ws"
+)
+
+// Targets lists the injectable targets.
+var Targets = []string{TargetGamma, TargetCLOB, TargetDB, TargetWS}
+
+// ErrInjected is wrapped by every injected error.
+var ErrInjected = errors.New("injected fault")
+
+// Fault describes what to inject into one target. Each call on the target
+// first sleeps LatencyMS, then fails with probability ErrorRate. HTTP targets
+// answer with StatusCode instead of a transport error when it is set; for
+// ws a failure drops the connection. Remaining, when positive, stops the
+// fault after that many failures.
+type Fault struct {
+	Target     string    `json:"target"`
+	ErrorRate  float64   `json:"error_rate"`
+	LatencyMS  int       `json:"latency_ms"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Remaining  int       `json:"remaining,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	Injected   int64     `json:"injected"`
+	Delayed    int64     `json:"delayed"`
+}
+
+// Injector holds the active faults.
+type Injector struct {
+	maxDuration time.Duration
+
+	mu     sync.Mutex
+	faults map[string]*Fault
+	now    func() time.Time
+	rand   func() float64
+}
+
+// New returns an Injector, or nil when fault injection is disabled or env
+// names production.
+func New(cfg config.ChaosConfig, env string) *Injector {
+	if !cfg.Enabled || IsProduction(env) {
+		return nil
+	}
+	max := cfg.MaxDuration
+	if max <= 0 {
+		max = 15 * time.Minute
+	}
+	return &Injector{maxDuration: max, faults: map[string]*Fault{}, now: time.Now, rand: rand.Float64}
+}
+
+// IsProduction reports whether env is a production environment name.
+func IsProduction(env string) bool {
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "prod", "production", "live":
+		return true
+	}
+	return false
+}
+
+// Set installs f on its target, replacing any fault there. A zero duration
+// lasts the configured maximum; longer ones are capped.
+func (i *Injector) Set(f Fault, duration time.Duration) (Fault, error) {
+	if i == nil {
+		return Fault{}, errors.New("fault injection disabled")
+	}
+	f.Target = strings.ToLower(strings.TrimSpace(f.Target))
+	if !validTarget(f.Target) {
+		return Fault{}, fmt.Errorf("unknown target %q", f.Target)
+	}
+	if f.ErrorRate < 0 || f.ErrorRate > 1 {
+		return Fault{}, errors.New("error_rate must be between 0 and 1")
+	}
+	if f.LatencyMS < 0 || f.Remaining < 0 {
+		return Fault{}, errors.New("latency_ms and remaining must not be negative")
+	}
+	if f.StatusCode != 0 && (f.StatusCode < 400 || f.StatusCode > 599) {
+		return Fault{}, errors.New("status_code must be 4xx or 5xx")
+	}
+	if f.ErrorRate == 0 && f.LatencyMS == 0 {
+		return Fault{}, errors.New("fault injects nothing")
+	}
+	if duration <= 0 || duration > i.maxDuration {
+		duration = i.maxDuration
+	}
+	now := i.now().UTC()
+	f.CreatedAt, f.ExpiresAt = now, now.Add(duration)
+	f.Injected, f.Delayed = 0, 0
+	i.mu.Lock()
+	i.faults[f.Target] = &f
+	i.mu.Unlock()
+	return f, nil
+}
+
+// Clear removes the fault on target, or every fault when target is empty.
+func (i *Injector) Clear(target string) {
+	if i == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if target == "" {
+		i.faults = map[string]*Fault{}
+		return
+	}
+	delete(i.faults, strings.ToLower(strings.TrimSpace(target)))
+}
+
+// List returns the active faults by target.
+func (i *Injector) List() []Fault {
+	if i == nil {
+		return nil
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	now := i.now()
+	out := make([]Fault, 0, len(i.faults))
+	for target, f := range i.faults {
+		if !now.Before(f.ExpiresAt) {
+			delete(i.faults, target)
+			continue
+		}
+		out = append(out, *f)
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Target < out[b].Target })
+	return out
+}
+
+// Apply runs the fault on target for one call: it sleeps the latency and
+// returns an error wrapping ErrInjected when the call should fail.
+func (i *Injector) Apply(ctx context.Context, target string) error {
+	_, err := i.apply(ctx, target)
+	return err
+}
+
+// apply also returns the status code an HTTP target should answer with.
+func (i *Injector) apply(ctx context.Context, target string) (int, error) {
+	if i == nil {
+		return 0, nil
+	}
+	i.mu.Lock()
+	f, ok := i.faults[target]
+	if ok && !i.now().Before(f.ExpiresAt) {
+		delete(i.faults, target)
+		ok = false
+	}
+	if !ok {
+		i.mu.Unlock()
+		return 0, nil
+	}
+	latency := time.Duration(f.LatencyMS) * time.Millisecond
+	if latency > 0 {
+		f.Delayed++
+	}
+	fail := f.ErrorRate > 0 && i.rand() < f.ErrorRate
+	status := f.StatusCode
+	if fail {
+		f.Injected++
+		if f.Remaining > 0 {
+			f.Remaining--
+			if f.Remaining == 0 {
+				delete(i.faults, target)
+			}
+		}
+	}
+	i.mu.Unlock()
+
+	if latency > 0 {
+		t := time.NewTimer(latency)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return 0, ctx.Err()
+		case <-t.C:
+		}
+	}
+	if !fail {
+		return 0, nil
+	}
+	return status, fmt.Errorf("%s: %w", target, ErrInjected)
+}
+
+// Transport wraps base (nil = http.DefaultTransport) with the fault on
+// target. The nil Injector returns base unchanged.
+func (i *Injector) Transport(target string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if i == nil {
+		return base
+	}
+	return &faultTransport{injector: i, target: target, base: base}
+}
+
+type faultTransport struct {
+	injector *Injector
+	target   string
+	base     http.RoundTripper
+}
+
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	status, err := t.injector.apply(req.Context(), t.target)
+	if err == nil {
+		return t.base.RoundTrip(req)
+	}
+	if status == 0 || !errors.Is(err, ErrInjected) {
+		return nil, err
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader(err.Error())),
+		Request:    req,
+	}, nil
+}
+
+// RegisterDB makes every query, create, update, delete, row and raw
+// statement on db subject to the db fault.
+func (i *Injector) RegisterDB(db *gorm.DB) error {
+	if i == nil || db == nil {
+		return nil
+	}
+	inject := func(tx *gorm.DB) {
+		if tx.Error != nil {
+			return
+		}
+		ctx := tx.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if err := i.Apply(ctx, TargetDB); err != nil {
+			_ = tx.AddError(err)
+		}
+	}
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Query().Before("gorm:query").Register("chaos:query", inject),
+		cb.Create().Before("gorm:create").Register("chaos:create", inject),
+		cb.Update().Before("gorm:update").Register("chaos:update", inject),
+		cb.Delete().Before("gorm:delete").Register("chaos:delete", inject),
+		cb.Row().Before("gorm:row").Register("chaos:row", inject),
+		cb.Raw().Before("gorm:raw").Register("chaos:raw", inject),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Hook returns a func applying the fault on target, for components that
+// take a plain hook such as the CLOB market stream. The nil Injector
+// returns nil.
+func (i *Injector) Hook(target string) func(context.Context) error {
+	if i == nil {
+		return nil
+	}
+	return func(ctx context.Context) error { return i.Apply(ctx, target) }
+}
+
+func validTarget(target string) bool {
+	for _, t := range Targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
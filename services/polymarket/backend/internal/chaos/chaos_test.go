@@ -0,0 +1,85 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+)
+
+func TestNew_RefusesProduction(t *testing.T) {
+	if New(config.ChaosConfig{Enabled: true}, "prod") != nil {
+		t.Fatal("injector created in prod")
+	}
+	if New(config.ChaosConfig{}, "dev") != nil {
+		t.Fatal("injector created while disabled")
+	}
+	var nilInjector *Injector
+	if err := nilInjector.Apply(context.Background(), TargetDB); err != nil {
+		t.Fatalf("nil injector: %v", err)
+	}
+}
+
+func TestTransport_StatusAndRemaining(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	inj := New(config.ChaosConfig{Enabled: true}, "dev")
+	if _, err := inj.Set(Fault{Target: TargetGamma, ErrorRate: 1, StatusCode: 503, Remaining: 2}, 0); err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: inj.Transport(TargetGamma, nil)}
+	codes := []int{}
+	for range 3 {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		codes = append(codes, resp.StatusCode)
+	}
+	if codes[0] != 503 || codes[1] != 503 || codes[2] != 200 {
+		t.Fatalf("codes = %v", codes)
+	}
+	if len(inj.List()) != 0 {
+		t.Fatal("fault not removed after Remaining failures")
+	}
+}
+
+func TestInjector_ExpiresAndDB(t *testing.T) {
+	inj := New(config.ChaosConfig{Enabled: true, MaxDuration: time.Minute}, "dev")
+	now := time.Now()
+	inj.now = func() time.Time { return now }
+	f, err := inj.Set(Fault{Target: TargetDB, ErrorRate: 1}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.ExpiresAt.Sub(f.CreatedAt) != time.Minute {
+		t.Fatalf("duration not capped: %v", f.ExpiresAt.Sub(f.CreatedAt))
+	}
+
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.SystemSetting{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := inj.RegisterDB(conn.Gorm); err != nil {
+		t.Fatal(err)
+	}
+	var n int64
+	if err := conn.Gorm.Model(&models.SystemSetting{}).Count(&n).Error; !errors.Is(err, ErrInjected) {
+		t.Fatalf("query err = %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if err := conn.Gorm.Model(&models.SystemSetting{}).Count(&n).Error; err != nil {
+		t.Fatalf("expired fault still injected: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package polygon reads Polymarket state from Polygon over JSON-RPC.
+package polygon
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// DefaultCTFAddress is the Conditional Tokens contract Polymarket settles
+// on Polygon.
+const DefaultCTFAddress = "0x4D97DCd97eC945f40cF65F87097ACe5EA0476045"
+
+// Function selectors of the Conditional Tokens views the reader calls.
+const (
+	selGetOutcomeSlotCount = "d42dc0c2"
+	selPayoutDenominator   = "dd34de67"
+	selPayoutNumerators    = "0504c814"
+)
+
+// maxOutcomeSlots bounds the payout numerators read for one condition.
+const maxOutcomeSlots = 256
+
+// Condition is the onchain state of a CTF condition. OutcomeSlotCount is 0
+// for a condition that was never prepared. Once the oracle has reported,
+// PayoutDenominator is positive and PayoutNumerators holds each outcome
+// slot's share of it.
+type Condition struct {
+	ConditionID       string
+	OutcomeSlotCount  int
+	PayoutDenominator *big.Int
+	PayoutNumerators  []*big.Int
+}
+
+// Resolved reports whether the condition's payouts have been reported.
+func (c *Condition) Resolved() bool {
+	return c != nil && c.PayoutDenominator != nil && c.PayoutDenominator.Sign() > 0
+}
+
+// CTFReader reads conditions from the Conditional Tokens contract with
+// eth_call against a Polygon JSON-RPC endpoint.
+type CTFReader struct {
+	rpcURL     string
+	contract   string
+	httpClient *http.Client
+}
+
+func NewCTFReader(httpClient *http.Client, rpcURL, contract string) *CTFReader {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	contract = strings.TrimSpace(contract)
+	if contract == "" {
+		contract = DefaultCTFAddress
+	}
+	return &CTFReader{rpcURL: strings.TrimSpace(rpcURL), contract: contract, httpClient: httpClient}
+}
+
+// Condition reads the slot count and payouts of conditionID, a 0x-prefixed
+// 32 byte hex string.
+func (r *CTFReader) Condition(ctx context.Context, conditionID string) (*Condition, error) {
+	id, err := conditionWord(conditionID)
+	if err != nil {
+		return nil, err
+	}
+	out, err := r.calls(ctx, []string{selGetOutcomeSlotCount + id, selPayoutDenominator + id})
+	if err != nil {
+		return nil, err
+	}
+	slots := out[0]
+	if !slots.IsInt64() || slots.Int64() > maxOutcomeSlots {
+		return nil, fmt.Errorf("condition %s: implausible outcome slot count %s", conditionID, slots)
+	}
+	cond := &Condition{ConditionID: conditionID, OutcomeSlotCount: int(slots.Int64()), PayoutDenominator: out[1]}
+	if !cond.Resolved() || cond.OutcomeSlotCount == 0 {
+		return cond, nil
+	}
+	data := make([]string, cond.OutcomeSlotCount)
+	for i := range data {
+		data[i] = selPayoutNumerators + id + fmt.Sprintf("%064x", i)
+	}
+	if cond.PayoutNumerators, err = r.calls(ctx, data); err != nil {
+		return nil, err
+	}
+	return cond, nil
+}
+
+func conditionWord(conditionID string) (string, error) {
+	raw := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(conditionID)), "0x")
+	if len(raw) != 64 {
+		return "", fmt.Errorf("invalid condition id %q", conditionID)
+	}
+	if _, err := hex.DecodeString(raw); err != nil {
+		return "", fmt.Errorf("invalid condition id %q", conditionID)
+	}
+	return raw, nil
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	ID     int       `json:"id"`
+	Result string    `json:"result"`
+	Error  *rpcError `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// calls runs one eth_call per calldata in a single batch and decodes each
+// result as a uint256.
+func (r *CTFReader) calls(ctx context.Context, data []string) ([]*big.Int, error) {
+	if r.rpcURL == "" {
+		return nil, errors.New("polygon rpc url not configured")
+	}
+	batch := make([]rpcRequest, len(data))
+	for i, d := range data {
+		batch[i] = rpcRequest{
+			JSONRPC: "2.0",
+			ID:      i,
+			Method:  "eth_call",
+			Params:  []any{map[string]string{"to": r.contract, "data": "0x" + d}, "latest"},
+		}
+	}
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("polygon rpc http %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	var results []rpcResponse
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return nil, fmt.Errorf("polygon rpc: %w", err)
+	}
+	out := make([]*big.Int, len(data))
+	for _, res := range results {
+		if res.ID < 0 || res.ID >= len(out) {
+			continue
+		}
+		if res.Error != nil {
+			return nil, fmt.Errorf("polygon rpc: %s (%d)", res.Error.Message, res.Error.Code)
+		}
+		word := strings.TrimPrefix(res.Result, "0x")
+		v, ok := new(big.Int).SetString(word, 16)
+		if word == "" || !ok {
+			return nil, fmt.Errorf("polygon rpc: invalid result %q", res.Result)
+		}
+		out[res.ID] = v
+	}
+	for i, v := range out {
+		if v == nil {
+			return nil, fmt.Errorf("polygon rpc: no result for call %d", i)
+		}
+	}
+	return out, nil
+}
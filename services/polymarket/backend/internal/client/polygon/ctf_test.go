@@ -0,0 +1,84 @@
+package polygon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCTFReaderCondition(t *testing.T) {
+	cond := "0x" + strings.Repeat("ab", 32)
+	resolved := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Fatal(err)
+		}
+		out := make([]map[string]any, 0, len(batch))
+		for _, req := range batch {
+			call := req.Params[0].(map[string]any)
+			data := call["data"].(string)
+			if !strings.Contains(data, strings.Repeat("ab", 32)) {
+				t.Fatalf("calldata %s missing condition id", data)
+			}
+			var v int
+			switch data[2:10] {
+			case selGetOutcomeSlotCount:
+				v = 2
+			case selPayoutDenominator:
+				if resolved {
+					v = 1
+				}
+			case selPayoutNumerators:
+				// Slot 1 wins.
+				if strings.HasSuffix(data, "1") {
+					v = 1
+				}
+			}
+			out = append(out, map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": fmt.Sprintf("0x%064x", v)})
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	r := NewCTFReader(srv.Client(), srv.URL, "")
+	got, err := r.Condition(context.Background(), cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.OutcomeSlotCount != 2 || !got.Resolved() || len(got.PayoutNumerators) != 2 {
+		t.Fatalf("condition=%+v", got)
+	}
+	if got.PayoutNumerators[0].Int64() != 0 || got.PayoutNumerators[1].Int64() != 1 {
+		t.Fatalf("payouts=%v want [0 1]", got.PayoutNumerators)
+	}
+
+	resolved = false
+	got, err = r.Condition(context.Background(), cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Resolved() || got.PayoutNumerators != nil {
+		t.Fatalf("unresolved condition=%+v", got)
+	}
+
+	if _, err := r.Condition(context.Background(), "0x1234"); err == nil {
+		t.Fatal("short condition id accepted")
+	}
+}
+
+func TestCTFReaderRPCError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"jsonrpc":"2.0","id":0,"error":{"code":-32000,"message":"execution reverted"}},{"jsonrpc":"2.0","id":1,"result":"0x0"}]`))
+	}))
+	defer srv.Close()
+
+	r := NewCTFReader(srv.Client(), srv.URL, "")
+	if _, err := r.Condition(context.Background(), "0x"+strings.Repeat("00", 32)); err == nil || !strings.Contains(err.Error(), "execution reverted") {
+		t.Fatalf("err=%v want rpc error", err)
+	}
+}
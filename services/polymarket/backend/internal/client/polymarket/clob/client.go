@@ -2,6 +2,7 @@ package clob
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,6 +13,9 @@ import (
 type Client struct {
 	host       string
 	httpClient *http.Client
+	// endpoints, when set, replaces host with its selected endpoint and
+	// receives the outcome of every request.
+	endpoints *EndpointPool
 }
 
 type APIError struct {
@@ -34,8 +38,42 @@ func NewClient(httpClient *http.Client, host string) *Client {
 	}
 }
 
+// NewPooledClient sends each request to the endpoint pool's current
+// selection.
+func NewPooledClient(httpClient *http.Client, pool *EndpointPool) *Client {
+	c := NewClient(httpClient, pool.Current())
+	c.endpoints = pool
+	return c
+}
+
+func (c *Client) baseURL() string {
+	if c.endpoints != nil {
+		if u := c.endpoints.Current(); u != "" {
+			return u
+		}
+	}
+	return c.host
+}
+
+// observe feeds a request outcome to the endpoint pool. Server errors count
+// as endpoint failures; other statuses are the API answering.
+func (c *Client) observe(base string, resp *http.Response, err error) {
+	if c.endpoints == nil || (err != nil && errors.Is(err, context.Canceled)) {
+		return
+	}
+	switch {
+	case err != nil:
+		c.endpoints.ReportFailure(base, err)
+	case resp.StatusCode >= 500:
+		c.endpoints.ReportFailure(base, fmt.Errorf("http %d", resp.StatusCode))
+	default:
+		c.endpoints.ReportSuccess(base)
+	}
+}
+
 func (c *Client) doRequest(ctx context.Context, path string, query url.Values) ([]byte, error) {
-	fullURL := c.host + path
+	base := c.baseURL()
+	fullURL := base + path
 	if query != nil && len(query) > 0 {
 		fullURL = fullURL + "?" + query.Encode()
 	}
@@ -45,6 +83,7 @@ func (c *Client) doRequest(ctx context.Context, path string, query url.Values) (
 	}
 	req.Header.Set("Accept", "application/json")
 	resp, err := c.httpClient.Do(req)
+	c.observe(base, resp, err)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -0,0 +1,362 @@
+package clob
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"nhooyr.io/websocket"
+)
+
+// EndpointProbe checks that url answers; its duration is the latency sample.
+type EndpointProbe func(ctx context.Context, url string) error
+
+type EndpointPoolOptions struct {
+	// Name labels the pool in logs and stats, e.g. "rest" or "ws".
+	Name string
+	URLs []string
+	// Probe defaults to HTTPProbe with a plain client.
+	Probe        EndpointProbe
+	ProbeTimeout time.Duration
+	// MaxFailures consecutive request or probe failures mark an endpoint
+	// down until a probe succeeds again.
+	MaxFailures int
+	// SwitchMargin is how much faster (as a fraction) another healthy
+	// endpoint must be before the selection moves off a healthy one.
+	SwitchMargin float64
+	Logger       *zap.Logger
+}
+
+// EndpointPool selects the fastest healthy endpoint among equivalent CLOB
+// hosts. The first URL is used until probes have measured the others.
+type EndpointPool struct {
+	opts EndpointPoolOptions
+
+	mu        sync.Mutex
+	endpoints []*endpointState
+	current   int
+	switches  int64
+}
+
+type endpointState struct {
+	url              string
+	latency          float64
+	lastLatency      float64
+	probes           int64
+	failures         int64
+	consecutiveFails int
+	down             bool
+	lastProbeAt      *time.Time
+	lastError        string
+}
+
+// EndpointStats reports one endpoint. LatencyMs is a moving average of the
+// probe round trips; zero until the first successful probe.
+type EndpointStats struct {
+	URL                 string     `json:"url"`
+	Selected            bool       `json:"selected"`
+	Healthy             bool       `json:"healthy"`
+	LatencyMs           float64    `json:"latency_ms"`
+	LastLatencyMs       float64    `json:"last_latency_ms"`
+	Probes              int64      `json:"probes"`
+	Failures            int64      `json:"failures"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastProbeAt         *time.Time `json:"last_probe_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+type EndpointPoolStats struct {
+	Name      string          `json:"name"`
+	Selected  string          `json:"selected"`
+	Switches  int64           `json:"switches"`
+	Endpoints []EndpointStats `json:"endpoints"`
+}
+
+// latencyWeight is the weight of a new probe in the moving average.
+const latencyWeight = 0.3
+
+func NewEndpointPool(opts EndpointPoolOptions) *EndpointPool {
+	if opts.Probe == nil {
+		opts.Probe = HTTPProbe(&http.Client{})
+	}
+	if opts.ProbeTimeout <= 0 {
+		opts.ProbeTimeout = 5 * time.Second
+	}
+	if opts.MaxFailures <= 0 {
+		opts.MaxFailures = 3
+	}
+	if opts.SwitchMargin < 0 {
+		opts.SwitchMargin = 0
+	}
+	p := &EndpointPool{opts: opts}
+	seen := map[string]bool{}
+	for _, u := range opts.URLs {
+		u = strings.TrimRight(strings.TrimSpace(u), "/")
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		p.endpoints = append(p.endpoints, &endpointState{url: u})
+	}
+	return p
+}
+
+// Current returns the selected endpoint, or "" for an empty pool.
+func (p *EndpointPool) Current() string {
+	if p == nil {
+		return ""
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.endpoints) == 0 {
+		return ""
+	}
+	return p.endpoints[p.current].url
+}
+
+// ReportSuccess records a request that reached url.
+func (p *EndpointPool) ReportSuccess(url string) {
+	p.report(url, nil)
+}
+
+// ReportFailure records a request to url that failed at the transport level
+// or with a server error. Enough of them fail the selection over.
+func (p *EndpointPool) ReportFailure(url string, err error) {
+	if err == nil {
+		err = fmt.Errorf("request failed")
+	}
+	p.report(url, err)
+}
+
+func (p *EndpointPool) report(url string, err error) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep := p.find(url)
+	if ep == nil {
+		return
+	}
+	if err == nil {
+		ep.consecutiveFails = 0
+		ep.down = false
+		return
+	}
+	p.fail(ep, err)
+	p.reselect()
+}
+
+func (p *EndpointPool) fail(ep *endpointState, err error) {
+	ep.failures++
+	ep.consecutiveFails++
+	ep.lastError = err.Error()
+	if ep.consecutiveFails >= p.opts.MaxFailures {
+		ep.down = true
+	}
+}
+
+func (p *EndpointPool) find(url string) *endpointState {
+	url = strings.TrimRight(strings.TrimSpace(url), "/")
+	for _, ep := range p.endpoints {
+		if ep.url == url {
+			return ep
+		}
+	}
+	return nil
+}
+
+// reselect moves off a down endpoint to the fastest healthy one, or off a
+// healthy one when another is faster by more than SwitchMargin. Unprobed
+// endpoints rank after probed ones in configured order. Callers hold mu.
+func (p *EndpointPool) reselect() {
+	if len(p.endpoints) < 2 {
+		return
+	}
+	best := -1
+	for i, ep := range p.endpoints {
+		if ep.down {
+			continue
+		}
+		if best < 0 || faster(ep, p.endpoints[best]) {
+			best = i
+		}
+	}
+	if best < 0 || best == p.current {
+		return
+	}
+	cur := p.endpoints[p.current]
+	next := p.endpoints[best]
+	if !cur.down {
+		if cur.latency == 0 || next.latency == 0 || next.latency >= cur.latency*(1-p.opts.SwitchMargin) {
+			return
+		}
+	}
+	p.current = best
+	p.switches++
+	if p.opts.Logger != nil {
+		p.opts.Logger.Info("clob endpoint switched",
+			zap.String("pool", p.opts.Name),
+			zap.String("from", cur.url),
+			zap.String("to", next.url),
+			zap.Bool("from_down", cur.down),
+			zap.Float64("latency_ms", next.latency),
+		)
+	}
+}
+
+func faster(a, b *endpointState) bool {
+	switch {
+	case a.latency == 0:
+		return false
+	case b.latency == 0:
+		return true
+	}
+	return a.latency < b.latency
+}
+
+// ProbeAll probes every endpoint once and updates the selection.
+func (p *EndpointPool) ProbeAll(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	urls := make([]string, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		urls = append(urls, ep.url)
+	}
+	p.mu.Unlock()
+
+	type result struct {
+		url     string
+		latency time.Duration
+		err     error
+	}
+	results := make([]result, len(urls))
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, p.opts.ProbeTimeout)
+			defer cancel()
+			start := time.Now()
+			err := p.opts.Probe(probeCtx, u)
+			results[i] = result{url: u, latency: time.Since(start), err: err}
+		}(i, u)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, r := range results {
+		ep := p.find(r.url)
+		if ep == nil {
+			continue
+		}
+		ep.probes++
+		ep.lastProbeAt = &now
+		if r.err != nil {
+			p.fail(ep, r.err)
+			continue
+		}
+		ms := float64(r.latency.Microseconds()) / 1000
+		ep.lastLatency = ms
+		if ep.latency == 0 {
+			ep.latency = ms
+		} else {
+			ep.latency = latencyWeight*ms + (1-latencyWeight)*ep.latency
+		}
+		ep.consecutiveFails = 0
+		ep.down = false
+		ep.lastError = ""
+	}
+	p.reselect()
+}
+
+// Run probes every interval until ctx is done.
+func (p *EndpointPool) Run(ctx context.Context, interval time.Duration) error {
+	if p == nil || len(p.endpoints) == 0 {
+		return nil
+	}
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	p.ProbeAll(ctx)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			p.ProbeAll(ctx)
+		}
+	}
+}
+
+func (p *EndpointPool) Stats() EndpointPoolStats {
+	if p == nil {
+		return EndpointPoolStats{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := EndpointPoolStats{Name: p.opts.Name, Switches: p.switches, Endpoints: make([]EndpointStats, 0, len(p.endpoints))}
+	for i, ep := range p.endpoints {
+		if i == p.current {
+			out.Selected = ep.url
+		}
+		out.Endpoints = append(out.Endpoints, EndpointStats{
+			URL:                 ep.url,
+			Selected:            i == p.current,
+			Healthy:             !ep.down,
+			LatencyMs:           ep.latency,
+			LastLatencyMs:       ep.lastLatency,
+			Probes:              ep.probes,
+			Failures:            ep.failures,
+			ConsecutiveFailures: ep.consecutiveFails,
+			LastProbeAt:         ep.lastProbeAt,
+			LastError:           ep.lastError,
+		})
+	}
+	return out
+}
+
+// HTTPProbe requests {url}/time, the CLOB server time; any answer below 500
+// counts as reachable.
+func HTTPProbe(client *http.Client) EndpointProbe {
+	return func(ctx context.Context, url string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/time", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			return fmt.Errorf("http %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// WSProbe completes a websocket handshake with url and closes it.
+func WSProbe() EndpointProbe {
+	return func(ctx context.Context, url string) error {
+		conn, _, err := websocket.Dial(ctx, url, nil)
+		if err != nil {
+			return err
+		}
+		return conn.Close(websocket.StatusNormalClosure, "probe")
+	}
+}
@@ -0,0 +1,68 @@
+package clob
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeProbe answers with a fixed delay per url, or fails for urls in down.
+type fakeProbe struct {
+	mu    sync.Mutex
+	delay map[string]time.Duration
+	down  map[string]bool
+}
+
+func (f *fakeProbe) probe(ctx context.Context, url string) error {
+	f.mu.Lock()
+	d, down := f.delay[url], f.down[url]
+	f.mu.Unlock()
+	if down {
+		return errors.New("unreachable")
+	}
+	time.Sleep(d)
+	return nil
+}
+
+func TestEndpointPoolSelectsFastest(t *testing.T) {
+	f := &fakeProbe{delay: map[string]time.Duration{"https://a": 40 * time.Millisecond, "https://b": 5 * time.Millisecond}}
+	p := NewEndpointPool(EndpointPoolOptions{URLs: []string{"https://a/", "https://b", "https://a"}, Probe: f.probe, SwitchMargin: 0.2})
+	if got := p.Current(); got != "https://a" {
+		t.Fatalf("initial=%q want first url", got)
+	}
+	p.ProbeAll(context.Background())
+	st := p.Stats()
+	if st.Selected != "https://b" || st.Switches != 1 || len(st.Endpoints) != 2 {
+		t.Fatalf("stats=%+v", st)
+	}
+	if st.Endpoints[1].LatencyMs <= 0 || !st.Endpoints[1].Selected {
+		t.Fatalf("b=%+v", st.Endpoints[1])
+	}
+}
+
+func TestEndpointPoolFailsOver(t *testing.T) {
+	f := &fakeProbe{delay: map[string]time.Duration{}, down: map[string]bool{}}
+	p := NewEndpointPool(EndpointPoolOptions{URLs: []string{"https://a", "https://b"}, Probe: f.probe, MaxFailures: 2})
+	p.ReportFailure("https://a", errors.New("timeout"))
+	if p.Current() != "https://a" {
+		t.Fatalf("switched after one failure")
+	}
+	p.ReportFailure("https://a", errors.New("timeout"))
+	if p.Current() != "https://b" {
+		t.Fatalf("current=%q want failover to b", p.Current())
+	}
+
+	// a stays down until a probe reaches it; a success on b keeps b.
+	f.down["https://b"] = true
+	p.ReportSuccess("https://b")
+	p.ProbeAll(context.Background())
+	p.ProbeAll(context.Background())
+	if p.Current() != "https://a" {
+		t.Fatalf("current=%q want a after b went down", p.Current())
+	}
+	if st := p.Stats(); !st.Endpoints[0].Healthy || st.Endpoints[1].Healthy {
+		t.Fatalf("stats=%+v", st)
+	}
+}
@@ -39,6 +39,7 @@ type PlaceOrderRequest struct {
 	SizeUSD       float64 `json:"size_usd"`
 	ClientOrderID string  `json:"client_order_id,omitempty"`
 	PlanID        uint64  `json:"plan_id,omitempty"`
+	PostOnly      *bool   `json:"post_only,omitempty"`
 }
 
 type PlaceSignedOrderRequest struct {
@@ -48,6 +49,12 @@ type PlaceSignedOrderRequest struct {
 	PostOnly  *bool  `json:"postOnly,omitempty"`
 }
 
+// AmendOrderRequest modifies a resting order in place. Nil fields are left unchanged.
+type AmendOrderRequest struct {
+	Price   *float64 `json:"price,omitempty"`
+	SizeUSD *float64 `json:"size_usd,omitempty"`
+}
+
 type TradingOrder struct {
 	OrderID     string
 	Status      string
@@ -104,11 +111,25 @@ func (c *Client) CancelOrder(ctx context.Context, pathTemplate, orderID string,
 	return parseTradingOrder(body)
 }
 
+func (c *Client) AmendOrder(ctx context.Context, pathTemplate, orderID string, req AmendOrderRequest, auth TradingAuth) (*TradingOrder, error) {
+	orderID = strings.TrimSpace(orderID)
+	if orderID == "" {
+		return nil, fmt.Errorf("order id is required")
+	}
+	path := renderOrderPath(pathTemplate, "/orders/{order_id}", orderID)
+	body, err := c.doJSON(ctx, http.MethodPatch, path, nil, req, auth)
+	if err != nil {
+		return nil, err
+	}
+	return parseTradingOrder(body)
+}
+
 func (c *Client) doJSON(ctx context.Context, method, path string, query url.Values, payload any, auth TradingAuth) ([]byte, error) {
 	if c == nil || c.httpClient == nil {
 		return nil, fmt.Errorf("client is nil")
 	}
-	fullURL := c.host + normalizePath(path, "")
+	base := c.baseURL()
+	fullURL := base + normalizePath(path, "")
 	if query != nil && len(query) > 0 {
 		fullURL += "?" + query.Encode()
 	}
@@ -176,6 +197,7 @@ func (c *Client) doJSON(ctx context.Context, method, path string, query url.Valu
 		req.Header.Set(sh, sig)
 	}
 	resp, err := c.httpClient.Do(req)
+	c.observe(base, resp, err)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -123,16 +124,58 @@ type MarketStreamOptions struct {
 	RefreshInterval   time.Duration
 	HeartbeatInterval time.Duration
 	PingTimeout       time.Duration
-	BackoffMin        time.Duration
-	BackoffMax        time.Duration
-	Logger            *zap.Logger
+	// StallTimeout reconnects when no message arrives for this long even
+	// though pings still succeed.
+	StallTimeout time.Duration
+	BackoffMin   time.Duration
+	BackoffMax   time.Duration
+	Logger       *zap.Logger
+	// Endpoints, when set, replaces URL with its selected endpoint on each
+	// connect and learns of connection failures. The stream reconnects when
+	// the selection moves.
+	Endpoints *EndpointPool
+	// FaultHook, when set, runs before each connect and after each message;
+	// an error fails the connect or drops the connection. Used for fault
+	// injection.
+	FaultHook func(context.Context) error
 }
 
 type MarketStream struct {
 	opts      MarketStreamOptions
 	seenFirst bool
+
+	mu    sync.Mutex
+	stats MarketStreamStats
+	// assets is the last subscribed asset set, reused on reconnect when the
+	// provider is unavailable.
+	assets []string
+	// resync asks the refresh loop to query the provider now.
+	resync chan struct{}
+}
+
+// MarketStreamStats reports connection supervision state.
+type MarketStreamStats struct {
+	Connected         bool       `json:"connected"`
+	Connects          int64      `json:"connects"`
+	Reconnects        int64      `json:"reconnects"`
+	ConnectFailures   int64      `json:"connect_failures"`
+	HeartbeatFailures int64      `json:"heartbeat_failures"`
+	Stalls            int64      `json:"stalls"`
+	Messages          int64      `json:"messages"`
+	SubscribedAssets  int        `json:"subscribed_assets"`
+	Backoff           string     `json:"backoff,omitempty"`
+	LastConnectedAt   *time.Time `json:"last_connected_at,omitempty"`
+	LastDisconnectAt  *time.Time `json:"last_disconnect_at,omitempty"`
+	LastMessageAt     *time.Time `json:"last_message_at,omitempty"`
+	LastError         string     `json:"last_error,omitempty"`
 }
 
+var (
+	errHeartbeat      = errors.New("clob ws heartbeat failed")
+	errStalled        = errors.New("clob ws stalled")
+	errEndpointSwitch = errors.New("clob ws endpoint switched")
+)
+
 func NewMarketStream(opts MarketStreamOptions) *MarketStream {
 	if opts.URL == "" {
 		opts.URL = DefaultMarketWSSURL
@@ -152,7 +195,62 @@ func NewMarketStream(opts MarketStreamOptions) *MarketStream {
 	if opts.RefreshInterval == 0 {
 		opts.RefreshInterval = 30 * time.Second
 	}
-	return &MarketStream{opts: opts}
+	if opts.StallTimeout == 0 {
+		opts.StallTimeout = 2 * time.Minute
+	}
+	return &MarketStream{opts: opts, resync: make(chan struct{}, 1)}
+}
+
+// Resync makes a connected stream diff its subscription against the asset
+// provider now instead of at the next refresh tick. It does not block.
+func (s *MarketStream) Resync() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.resync <- struct{}{}:
+	default:
+	}
+}
+
+// Stats returns a snapshot of the supervision counters.
+func (s *MarketStream) Stats() MarketStreamStats {
+	if s == nil {
+		return MarketStreamStats{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+func (s *MarketStream) update(fn func(st *MarketStreamStats)) {
+	s.mu.Lock()
+	fn(&s.stats)
+	s.mu.Unlock()
+}
+
+// currentAssets resolves the asset set for a (re)subscribe: the provider when
+// it answers, else the last subscribed set, else the static list.
+func (s *MarketStream) currentAssets(ctx context.Context) []string {
+	if s.opts.AssetIDProvider != nil {
+		if ids, err := s.opts.AssetIDProvider(ctx); err == nil && len(ids) > 0 {
+			return ids
+		}
+	}
+	s.mu.Lock()
+	last := append([]string(nil), s.assets...)
+	s.mu.Unlock()
+	if len(last) > 0 {
+		return last
+	}
+	return s.opts.AssetIDs
+}
+
+func (s *MarketStream) setAssets(ids []string) {
+	s.mu.Lock()
+	s.assets = append([]string(nil), ids...)
+	s.stats.SubscribedAssets = len(ids)
+	s.mu.Unlock()
 }
 
 func (s *MarketStream) Run(ctx context.Context, onMessage func(MarketEnvelope, []byte)) error {
@@ -160,98 +258,168 @@ func (s *MarketStream) Run(ctx context.Context, onMessage func(MarketEnvelope, [
 		return fmt.Errorf("stream is nil")
 	}
 	backoff := s.opts.BackoffMin
+	wait := func(reason error) error {
+		s.update(func(st *MarketStreamStats) {
+			st.Backoff = backoff.String()
+			if reason != nil {
+				st.LastError = reason.Error()
+			}
+		})
+		if err := sleepWithJitter(ctx, backoff); err != nil {
+			return err
+		}
+		backoff = nextBackoff(backoff, s.opts.BackoffMax)
+		return nil
+	}
 	for {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
-		client := NewWSClient(s.opts.URL)
+		url := s.url()
+		client := NewWSClient(url)
+		if err := s.fault(ctx); err != nil {
+			s.update(func(st *MarketStreamStats) { st.ConnectFailures++ })
+			if err := wait(err); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := client.Connect(ctx); err != nil {
 			if s.opts.Logger != nil {
-				s.opts.Logger.Warn("clob ws connect failed", zap.Error(err))
+				s.opts.Logger.Warn("clob ws connect failed", zap.String("url", url), zap.Error(err), zap.Duration("backoff", backoff))
 			}
-			if err := sleepWithJitter(ctx, backoff); err != nil {
+			s.opts.Endpoints.ReportFailure(url, err)
+			s.update(func(st *MarketStreamStats) { st.ConnectFailures++ })
+			if err := wait(err); err != nil {
 				return err
 			}
-			backoff = nextBackoff(backoff, s.opts.BackoffMax)
 			continue
 		}
+		s.opts.Endpoints.ReportSuccess(url)
 		if s.opts.Logger != nil {
-			s.opts.Logger.Info("clob ws connected")
-		}
-		assetIDs := s.opts.AssetIDs
-		if s.opts.AssetIDProvider != nil {
-			if ids, err := s.opts.AssetIDProvider(ctx); err == nil {
-				assetIDs = ids
-			}
+			s.opts.Logger.Info("clob ws connected", zap.String("url", url))
 		}
+		assetIDs := s.currentAssets(ctx)
 		if len(assetIDs) == 0 {
 			if s.opts.Logger != nil {
 				s.opts.Logger.Warn("clob ws subscribe skipped: no assets")
 			}
 			_ = client.Close(websocket.StatusInternalError, "no assets to subscribe")
-			if err := sleepWithJitter(ctx, backoff); err != nil {
+			if err := wait(errors.New("no assets to subscribe")); err != nil {
 				return err
 			}
-			backoff = nextBackoff(backoff, s.opts.BackoffMax)
 			continue
 		}
-		if len(assetIDs) > 0 {
-			if err := client.SubscribeMarket(ctx, assetIDs); err != nil {
-				if s.opts.Logger != nil {
-					s.opts.Logger.Warn("clob ws subscribe failed", zap.Error(err))
-				}
-				_ = client.Close(websocket.StatusInternalError, "subscribe failed")
-				if err := sleepWithJitter(ctx, backoff); err != nil {
-					return err
-				}
-				backoff = nextBackoff(backoff, s.opts.BackoffMax)
-				continue
-			}
+		if err := client.SubscribeMarket(ctx, assetIDs); err != nil {
 			if s.opts.Logger != nil {
-				s.opts.Logger.Info("clob ws subscribed", zap.Int("assets", len(assetIDs)))
+				s.opts.Logger.Warn("clob ws subscribe failed", zap.Error(err))
 			}
+			_ = client.Close(websocket.StatusInternalError, "subscribe failed")
+			if err := wait(err); err != nil {
+				return err
+			}
+			continue
 		}
-		backoff = s.opts.BackoffMin
+		if s.opts.Logger != nil {
+			s.opts.Logger.Info("clob ws subscribed", zap.Int("assets", len(assetIDs)))
+		}
+		s.setAssets(assetIDs)
+		connectedAt := time.Now().UTC()
+		s.update(func(st *MarketStreamStats) {
+			if st.Connects > 0 {
+				st.Reconnects++
+			}
+			st.Connects++
+			st.Connected = true
+			st.LastConnectedAt = &connectedAt
+			st.Backoff = ""
+		})
 
-		current := setFromSlice(assetIDs)
-		err := s.consume(ctx, client, onMessage, current)
+		err := s.consume(ctx, client, url, onMessage, setFromSlice(assetIDs))
 		_ = client.Close(websocket.StatusNormalClosure, "reconnect")
+		disconnectedAt := time.Now().UTC()
+		s.update(func(st *MarketStreamStats) {
+			st.Connected = false
+			st.LastDisconnectAt = &disconnectedAt
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if err == nil || errors.Is(err, context.Canceled) {
 			return err
 		}
-		if err := sleepWithJitter(ctx, backoff); err != nil {
+		if errors.Is(err, errEndpointSwitch) {
+			backoff = s.opts.BackoffMin
+			continue
+		}
+		if errors.Is(err, errHeartbeat) || errors.Is(err, errStalled) {
+			s.opts.Endpoints.ReportFailure(url, err)
+		}
+		// Only a connection that stayed up for a while resets the backoff, so
+		// a flapping endpoint still backs off exponentially.
+		if disconnectedAt.Sub(connectedAt) >= s.opts.BackoffMax {
+			backoff = s.opts.BackoffMin
+		}
+		if err := wait(err); err != nil {
 			return err
 		}
-		backoff = nextBackoff(backoff, s.opts.BackoffMax)
 	}
 }
 
-func (s *MarketStream) consume(ctx context.Context, client *WSClient, onMessage func(MarketEnvelope, []byte), current map[string]struct{}) error {
+// url returns the endpoint for the next connect.
+func (s *MarketStream) url() string {
+	if u := s.opts.Endpoints.Current(); u != "" {
+		return u
+	}
+	return s.opts.URL
+}
+
+// consume reads until the connection fails. A failed ping, no market data
+// for StallTimeout, or the endpoint pool selecting another endpoint than url
+// cancels the blocking read so Run can reconnect.
+func (s *MarketStream) consume(ctx context.Context, client *WSClient, url string, onMessage func(MarketEnvelope, []byte), current map[string]struct{}) error {
 	if client == nil {
 		return fmt.Errorf("ws client is nil")
 	}
-	heartbeatErr := make(chan error, 1)
-	heartbeatCtx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	readCtx, cancelRead := context.WithCancelCause(ctx)
+	defer cancelRead(nil)
 
-	var refreshErr chan error
-	refreshCtx, cancelRefresh := context.WithCancel(ctx)
-	defer cancelRefresh()
+	var lastMessage sync.Mutex
+	lastAt := time.Now()
+	touch := func() {
+		lastMessage.Lock()
+		lastAt = time.Now()
+		lastMessage.Unlock()
+	}
+	silentFor := func() time.Duration {
+		lastMessage.Lock()
+		defer lastMessage.Unlock()
+		return time.Since(lastAt)
+	}
 
 	go func() {
 		ticker := time.NewTicker(s.opts.HeartbeatInterval)
 		defer ticker.Stop()
 		for {
 			select {
-			case <-heartbeatCtx.Done():
-				heartbeatErr <- heartbeatCtx.Err()
+			case <-readCtx.Done():
 				return
 			case <-ticker.C:
-				pingCtx, cancelPing := context.WithTimeout(heartbeatCtx, s.opts.PingTimeout)
+				pingCtx, cancelPing := context.WithTimeout(readCtx, s.opts.PingTimeout)
 				err := client.conn.Ping(pingCtx)
 				cancelPing()
-				if err != nil {
-					heartbeatErr <- err
+				if err != nil && readCtx.Err() == nil {
+					s.update(func(st *MarketStreamStats) { st.HeartbeatFailures++ })
+					cancelRead(fmt.Errorf("%w: %v", errHeartbeat, err))
+					return
+				}
+				if silent := silentFor(); s.opts.StallTimeout > 0 && silent > s.opts.StallTimeout {
+					s.update(func(st *MarketStreamStats) { st.Stalls++ })
+					cancelRead(fmt.Errorf("%w: no messages for %s", errStalled, silent.Truncate(time.Second)))
+					return
+				}
+				if next := s.url(); next != url {
+					cancelRead(fmt.Errorf("%w to %s", errEndpointSwitch, next))
 					return
 				}
 			}
@@ -259,59 +427,61 @@ func (s *MarketStream) consume(ctx context.Context, client *WSClient, onMessage
 	}()
 
 	if s.opts.AssetIDProvider != nil && s.opts.RefreshInterval > 0 {
-		refreshErr = make(chan error, 1)
 		go func() {
 			ticker := time.NewTicker(s.opts.RefreshInterval)
 			defer ticker.Stop()
 			for {
 				select {
-				case <-refreshCtx.Done():
-					refreshErr <- refreshCtx.Err()
+				case <-readCtx.Done():
 					return
 				case <-ticker.C:
-					ids, err := s.opts.AssetIDProvider(refreshCtx)
-					if err != nil {
-						continue
-					}
-					next := setFromSlice(ids)
-					added, removed := diffSets(current, next)
-					if len(added) > 0 {
-						_ = client.UpdateMarketSubscription(refreshCtx, added, "subscribe")
-					}
-					if len(removed) > 0 {
-						_ = client.UpdateMarketSubscription(refreshCtx, removed, "unsubscribe")
-					}
-					current = next
+				case <-s.resync:
+				}
+				ids, err := s.opts.AssetIDProvider(readCtx)
+				if err != nil {
+					continue
 				}
+				next := setFromSlice(ids)
+				added, removed := diffSets(current, next)
+				if len(added) > 0 {
+					_ = client.UpdateMarketSubscription(readCtx, added, "subscribe")
+				}
+				if len(removed) > 0 {
+					_ = client.UpdateMarketSubscription(readCtx, removed, "unsubscribe")
+				}
+				current = next
+				s.setAssets(ids)
 			}
 		}()
 	}
 
 	for {
-		select {
-		case err := <-heartbeatErr:
-			if err != nil && !errors.Is(err, context.Canceled) {
-				return err
+		env, raw, err := client.Read(readCtx)
+		if err != nil {
+			if cause := context.Cause(readCtx); cause != nil && ctx.Err() == nil {
+				err = cause
 			}
-			return nil
-		case err := <-refreshErr:
-			if err != nil && !errors.Is(err, context.Canceled) {
-				return err
+			if s.opts.Logger != nil && !errors.Is(err, context.Canceled) {
+				s.opts.Logger.Warn("clob ws read failed", zap.Error(err))
 			}
-			return nil
-		default:
+			return err
 		}
-		env, raw, err := client.Read(ctx)
-		if err != nil {
+		if err := s.fault(readCtx); err != nil {
 			if s.opts.Logger != nil && !errors.Is(err, context.Canceled) {
-				s.opts.Logger.Warn("clob ws read failed", zap.Error(err))
+				s.opts.Logger.Warn("clob ws connection dropped by fault hook", zap.Error(err))
 			}
 			return err
 		}
 		if isPingPayload(env, raw) {
-			_ = client.respondPong(ctx)
+			_ = client.respondPong(readCtx)
 			continue
 		}
+		touch()
+		now := time.Now().UTC()
+		s.update(func(st *MarketStreamStats) {
+			st.Messages++
+			st.LastMessageAt = &now
+		})
 		if s.opts.Logger != nil && !s.seenFirst {
 			s.seenFirst = true
 			s.opts.Logger.Info("clob ws first message", zap.String("event_type", env.EventType))
@@ -351,6 +521,13 @@ func isPingPayload(env MarketEnvelope, raw []byte) bool {
 	return false
 }
 
+func (s *MarketStream) fault(ctx context.Context) error {
+	if s.opts.FaultHook == nil {
+		return nil
+	}
+	return s.opts.FaultHook(ctx)
+}
+
 func nextBackoff(current, max time.Duration) time.Duration {
 	next := current * 2
 	if next > max {
@@ -363,7 +540,10 @@ func sleepWithJitter(ctx context.Context, base time.Duration) error {
 	if base <= 0 {
 		return nil
 	}
-	jitter := time.Duration(rand.Int63n(int64(base / 2)))
+	jitter := time.Duration(0)
+	if half := int64(base / 2); half > 0 {
+		jitter = time.Duration(rand.Int63n(half))
+	}
 	timer := time.NewTimer(base + jitter)
 	defer timer.Stop()
 	select {
@@ -0,0 +1,262 @@
+package clob
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// fakeMarketWS accepts market stream connections and records every message a
+// client sends. Messages to push are written to each new connection.
+type fakeMarketWS struct {
+	mu       sync.Mutex
+	conns    int
+	received []map[string]any
+	push     []string
+}
+
+func (f *fakeMarketWS) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			t.Errorf("accept: %v", err)
+			return
+		}
+		defer conn.CloseNow()
+		f.mu.Lock()
+		f.conns++
+		push := append([]string(nil), f.push...)
+		f.mu.Unlock()
+		ctx := r.Context()
+		for _, msg := range push {
+			if err := conn.Write(ctx, websocket.MessageText, []byte(msg)); err != nil {
+				return
+			}
+		}
+		for {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			var m map[string]any
+			_ = json.Unmarshal(data, &m)
+			f.mu.Lock()
+			f.received = append(f.received, m)
+			f.mu.Unlock()
+		}
+	}
+}
+
+func (f *fakeMarketWS) snapshot() (int, []map[string]any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.conns, append([]map[string]any(nil), f.received...)
+}
+
+func wsURL(srv *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("condition not met before timeout")
+}
+
+func assetsOf(m map[string]any) []string {
+	raw, _ := m["assets_ids"].([]any)
+	out := make([]string, 0, len(raw))
+	for _, v := range raw {
+		s, _ := v.(string)
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestMarketStreamReconnectsOnStall(t *testing.T) {
+	fake := &fakeMarketWS{}
+	srv := httptest.NewServer(fake.handler(t))
+	defer srv.Close()
+
+	stream := NewMarketStream(MarketStreamOptions{
+		URL:               wsURL(srv),
+		AssetIDs:          []string{"a1", "a2"},
+		HeartbeatInterval: 10 * time.Millisecond,
+		PingTimeout:       time.Second,
+		StallTimeout:      50 * time.Millisecond,
+		BackoffMin:        time.Millisecond,
+		BackoffMax:        5 * time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- stream.Run(ctx, nil) }()
+
+	waitFor(t, 5*time.Second, func() bool {
+		conns, _ := fake.snapshot()
+		return conns >= 2 && stream.Stats().Reconnects >= 1
+	})
+	cancel()
+	<-done
+
+	st := stream.Stats()
+	if st.Stalls < 1 || st.Connects < 2 || st.SubscribedAssets != 2 || st.Connected {
+		t.Fatalf("stats=%+v", st)
+	}
+	if !strings.Contains(st.LastError, errStalled.Error()) {
+		t.Fatalf("last error=%q", st.LastError)
+	}
+	// Every connection subscribes to the same asset set again.
+	_, received := fake.snapshot()
+	subs := 0
+	for _, m := range received {
+		if m["type"] != "market" {
+			continue
+		}
+		subs++
+		if got := assetsOf(m); strings.Join(got, ",") != "a1,a2" {
+			t.Fatalf("resubscribe assets=%v", got)
+		}
+	}
+	if subs < 2 {
+		t.Fatalf("subscribes=%d want>=2", subs)
+	}
+}
+
+func TestMarketStreamResyncDiffsSubscription(t *testing.T) {
+	fake := &fakeMarketWS{push: []string{`{"event_type":"book","asset_id":"a1"}`, `{"event_type":"ping"}`}}
+	srv := httptest.NewServer(fake.handler(t))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	assets := []string{"a1", "a2"}
+	provider := func(context.Context) ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), assets...), nil
+	}
+	var messages sync.WaitGroup
+	messages.Add(1)
+	var once sync.Once
+	stream := NewMarketStream(MarketStreamOptions{
+		URL:             wsURL(srv),
+		AssetIDProvider: provider,
+		RefreshInterval: time.Hour,
+		StallTimeout:    time.Hour,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- stream.Run(ctx, func(env MarketEnvelope, _ []byte) {
+			if env.EventType == "book" {
+				once.Do(messages.Done)
+			}
+		})
+	}()
+	messages.Wait()
+
+	mu.Lock()
+	assets = []string{"a2", "a3"}
+	mu.Unlock()
+	stream.Resync()
+
+	var updates []map[string]any
+	waitFor(t, 5*time.Second, func() bool {
+		_, received := fake.snapshot()
+		updates = updates[:0]
+		for _, m := range received {
+			if _, ok := m["operation"]; ok {
+				updates = append(updates, m)
+			}
+		}
+		return len(updates) == 2
+	})
+	cancel()
+	<-done
+
+	ops := map[string]string{}
+	for _, m := range updates {
+		ops[m["operation"].(string)] = strings.Join(assetsOf(m), ",")
+	}
+	if ops["subscribe"] != "a3" || ops["unsubscribe"] != "a1" {
+		t.Fatalf("updates=%v", ops)
+	}
+	// The ping is answered, not counted as market data.
+	_, received := fake.snapshot()
+	pongs := 0
+	for _, m := range received {
+		if m["event_type"] == "pong" {
+			pongs++
+		}
+	}
+	st := stream.Stats()
+	if pongs != 1 || st.Messages != 1 || st.SubscribedAssets != 2 || st.Reconnects != 0 {
+		t.Fatalf("pongs=%d stats=%+v", pongs, st)
+	}
+}
+
+func TestMarketStreamBacksOffWhenConnectFails(t *testing.T) {
+	stream := NewMarketStream(MarketStreamOptions{
+		URL:        "ws://127.0.0.1:1",
+		AssetIDs:   []string{"a1"},
+		BackoffMin: time.Millisecond,
+		BackoffMax: 4 * time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- stream.Run(ctx, nil) }()
+	waitFor(t, 5*time.Second, func() bool { return stream.Stats().ConnectFailures >= 4 })
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("run err=%v", err)
+	}
+	st := stream.Stats()
+	if st.Connects != 0 || st.Backoff != "4ms" || st.LastError == "" {
+		t.Fatalf("stats=%+v", st)
+	}
+}
+
+func TestIsPingPayload(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want bool
+	}{
+		{`ping`, true},
+		{` ping `, true},
+		{`{"event_type":"PING"}`, true},
+		{`{"type":"ping"}`, true},
+		{`{"event_type":"book"}`, false},
+		{``, false},
+	}
+	for _, tc := range cases {
+		var env MarketEnvelope
+		_ = json.Unmarshal([]byte(tc.raw), &env)
+		if got := isPingPayload(env, []byte(tc.raw)); got != tc.want {
+			t.Errorf("%q = %v, want %v", tc.raw, got, tc.want)
+		}
+	}
+}
+
+func TestDiffSets(t *testing.T) {
+	added, removed := diffSets(setFromSlice([]string{"a", "b", " "}), setFromSlice([]string{"b", " c "}))
+	if strings.Join(added, ",") != "c" || strings.Join(removed, ",") != "a" {
+		t.Fatalf("added=%v removed=%v", added, removed)
+	}
+	if got := nextBackoff(3*time.Second, 5*time.Second); got != 5*time.Second {
+		t.Fatalf("nextBackoff=%s", got)
+	}
+}
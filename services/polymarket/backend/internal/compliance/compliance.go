@@ -0,0 +1,264 @@
+// Package compliance enforces pre-trade restrictions on what markets may be
+// traded: configured market labels and event categories, plus the extra rules
+// of the active jurisdiction. An admin override lets one restricted market
+// through until it expires or is revoked.
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+const (
+	RuleLabel    = "label"
+	RuleCategory = "category"
+)
+
+// Violation is one restriction a market hits. OverrideID is set when an
+// active override lets the market trade anyway.
+type Violation struct {
+	MarketID     string `json:"market_id"`
+	Rule         string `json:"rule"`
+	Value        string `json:"value"`
+	Jurisdiction string `json:"jurisdiction,omitempty"`
+	OverrideID   uint64 `json:"override_id,omitempty"`
+}
+
+// Result holds the violations of the checked markets.
+type Result struct {
+	Violations []Violation `json:"violations"`
+}
+
+// Blocked returns the market IDs with a violation that no override covers,
+// sorted.
+func (r Result) Blocked() []string {
+	seen := map[string]struct{}{}
+	var out []string
+	for _, v := range r.Violations {
+		if v.OverrideID != 0 {
+			continue
+		}
+		if _, ok := seen[v.MarketID]; ok {
+			continue
+		}
+		seen[v.MarketID] = struct{}{}
+		out = append(out, v.MarketID)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// IsBlocked reports whether marketID has an uncovered violation.
+func (r Result) IsBlocked(marketID string) bool {
+	for _, v := range r.Violations {
+		if v.MarketID == marketID && v.OverrideID == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules is the effective restriction set: the base lists merged with the
+// active jurisdiction's.
+type Rules struct {
+	Enabled              bool     `json:"enabled"`
+	Jurisdiction         string   `json:"jurisdiction,omitempty"`
+	RestrictedLabels     []string `json:"restricted_labels"`
+	RestrictedCategories []string `json:"restricted_categories"`
+}
+
+// Checker evaluates markets against the configured rules. A nil Checker, or
+// one that is disabled, restricts nothing.
+type Checker struct {
+	Config config.ComplianceConfig
+	Repo   repository.Repository
+}
+
+func (c *Checker) enabled() bool {
+	return c != nil && c.Repo != nil && c.Config.Enabled
+}
+
+// Rules returns the effective rules.
+func (c *Checker) Rules() Rules {
+	if c == nil {
+		return Rules{}
+	}
+	jurisdiction := normalize(c.Config.Jurisdiction)
+	labels := append([]string{}, c.Config.RestrictedLabels...)
+	categories := append([]string{}, c.Config.RestrictedCategories...)
+	for name, rules := range c.Config.Jurisdictions {
+		if jurisdiction == "" || normalize(name) != jurisdiction {
+			continue
+		}
+		labels = append(labels, rules.RestrictedLabels...)
+		categories = append(categories, rules.RestrictedCategories...)
+	}
+	return Rules{
+		Enabled:              c.enabled(),
+		Jurisdiction:         jurisdiction,
+		RestrictedLabels:     normalizeAll(labels),
+		RestrictedCategories: normalizeAll(categories),
+	}
+}
+
+// Check returns the violations of marketIDs at now, marking those covered by
+// an active override.
+func (c *Checker) Check(ctx context.Context, marketIDs []string, now time.Time) (Result, error) {
+	if !c.enabled() || len(marketIDs) == 0 {
+		return Result{}, nil
+	}
+	rules := c.Rules()
+	if len(rules.RestrictedLabels) == 0 && len(rules.RestrictedCategories) == 0 {
+		return Result{}, nil
+	}
+	ids := uniqueIDs(marketIDs)
+	var out []Violation
+	if len(rules.RestrictedLabels) > 0 {
+		restricted := toSet(rules.RestrictedLabels)
+		labels, err := c.Repo.ListMarketLabelsByMarketIDs(ctx, ids)
+		if err != nil {
+			return Result{}, err
+		}
+		for _, l := range labels {
+			if _, ok := restricted[normalize(l.Label)]; ok {
+				out = append(out, Violation{MarketID: l.MarketID, Rule: RuleLabel, Value: normalize(l.Label)})
+			}
+		}
+	}
+	if len(rules.RestrictedCategories) > 0 {
+		found, err := c.categoryViolations(ctx, ids, toSet(rules.RestrictedCategories))
+		if err != nil {
+			return Result{}, err
+		}
+		out = append(out, found...)
+	}
+	if len(out) == 0 {
+		return Result{}, nil
+	}
+	for i := range out {
+		out[i].Jurisdiction = rules.Jurisdiction
+	}
+	overrides, err := c.Repo.ListActiveComplianceOverrides(ctx, ids, now)
+	if err != nil {
+		return Result{}, err
+	}
+	byMarket := map[string]uint64{}
+	for _, o := range overrides {
+		if _, ok := byMarket[o.MarketID]; !ok {
+			byMarket[o.MarketID] = o.ID
+		}
+	}
+	for i := range out {
+		out[i].OverrideID = byMarket[out[i].MarketID]
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].MarketID < out[j].MarketID })
+	return Result{Violations: out}, nil
+}
+
+// categoryViolations matches the markets' event tags, by slug or label,
+// against the restricted categories.
+func (c *Checker) categoryViolations(ctx context.Context, marketIDs []string, restricted map[string]struct{}) ([]Violation, error) {
+	markets, err := c.Repo.ListMarketsByIDs(ctx, marketIDs)
+	if err != nil {
+		return nil, err
+	}
+	eventIDs := make([]string, 0, len(markets))
+	for _, m := range markets {
+		if m.EventID != "" {
+			eventIDs = append(eventIDs, m.EventID)
+		}
+	}
+	if len(eventIDs) == 0 {
+		return nil, nil
+	}
+	tags, err := c.Repo.ListTagsByEventIDs(ctx, uniqueIDs(eventIDs))
+	if err != nil {
+		return nil, err
+	}
+	var out []Violation
+	for _, m := range markets {
+		seen := map[string]struct{}{}
+		for _, t := range tags[m.EventID] {
+			for _, key := range []string{normalize(t.Slug), normalize(t.Label)} {
+				if _, ok := restricted[key]; !ok {
+					continue
+				}
+				if _, dup := seen[key]; dup {
+					continue
+				}
+				seen[key] = struct{}{}
+				out = append(out, Violation{MarketID: m.ID, Rule: RuleCategory, Value: key})
+			}
+		}
+	}
+	return out, nil
+}
+
+// OpportunityMarketIDs lists the markets an opportunity trades.
+func OpportunityMarketIDs(opp models.Opportunity) []string {
+	var ids []string
+	if opp.PrimaryMarketID != nil && strings.TrimSpace(*opp.PrimaryMarketID) != "" {
+		ids = append(ids, strings.TrimSpace(*opp.PrimaryMarketID))
+	}
+	if len(opp.MarketIDs) > 0 {
+		var more []string
+		if err := json.Unmarshal(opp.MarketIDs, &more); err == nil {
+			ids = append(ids, more...)
+		}
+	}
+	return uniqueIDs(ids)
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func normalizeAll(items []string) []string {
+	seen := map[string]struct{}{}
+	out := []string{}
+	for _, it := range items {
+		key := normalize(it)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func toSet(items []string) map[string]struct{} {
+	out := make(map[string]struct{}, len(items))
+	for _, it := range items {
+		out[it] = struct{}{}
+	}
+	return out
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := map[string]struct{}{}
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
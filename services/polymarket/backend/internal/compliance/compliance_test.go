@@ -0,0 +1,80 @@
+package compliance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// catalogRepo answers the label, market, tag and override lookups.
+type catalogRepo struct {
+	repository.Repository
+	labels    []models.MarketLabel
+	markets   []models.Market
+	tags      map[string][]models.Tag
+	overrides []models.ComplianceOverride
+}
+
+func (r *catalogRepo) ListMarketLabelsByMarketIDs(ctx context.Context, marketIDs []string) ([]models.MarketLabel, error) {
+	return r.labels, nil
+}
+
+func (r *catalogRepo) ListMarketsByIDs(ctx context.Context, marketIDs []string) ([]models.Market, error) {
+	return r.markets, nil
+}
+
+func (r *catalogRepo) ListTagsByEventIDs(ctx context.Context, eventIDs []string) (map[string][]models.Tag, error) {
+	return r.tags, nil
+}
+
+func (r *catalogRepo) ListActiveComplianceOverrides(ctx context.Context, marketIDs []string, now time.Time) ([]models.ComplianceOverride, error) {
+	return r.overrides, nil
+}
+
+func TestChecker_LabelsCategoriesAndOverrides(t *testing.T) {
+	repo := &catalogRepo{
+		labels:  []models.MarketLabel{{MarketID: "m1", Label: "Assassination"}, {MarketID: "m2", Label: "sports"}},
+		markets: []models.Market{{ID: "m2", EventID: "e2"}, {ID: "m3", EventID: "e3"}},
+		tags: map[string][]models.Tag{
+			"e2": {{Slug: "nba", Label: "NBA"}},
+			"e3": {{Slug: "us-elections", Label: "Elections"}},
+		},
+		overrides: []models.ComplianceOverride{{ID: 7, MarketID: "m3"}},
+	}
+	c := &Checker{Repo: repo, Config: config.ComplianceConfig{
+		Enabled:          true,
+		RestrictedLabels: []string{" assassination "},
+		Jurisdiction:     "US",
+		Jurisdictions: map[string]config.ComplianceRules{
+			"us": {RestrictedCategories: []string{"elections"}},
+			"uk": {RestrictedCategories: []string{"nba"}},
+		},
+	}}
+
+	res, err := c.Check(context.Background(), []string{"m1", "m2", "m3"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Blocked(); len(got) != 1 || got[0] != "m1" {
+		t.Fatalf("blocked = %v, want [m1]", got)
+	}
+	if len(res.Violations) != 2 {
+		t.Fatalf("violations = %+v", res.Violations)
+	}
+	v := res.Violations[1]
+	if v.MarketID != "m3" || v.Rule != RuleCategory || v.Value != "elections" || v.Jurisdiction != "us" || v.OverrideID != 7 {
+		t.Fatalf("m3 violation = %+v", v)
+	}
+	if res.IsBlocked("m2") || res.IsBlocked("m3") {
+		t.Fatal("m2/m3 should trade")
+	}
+
+	c.Config.Enabled = false
+	if res, _ := c.Check(context.Background(), []string{"m1"}, time.Now()); len(res.Violations) != 0 {
+		t.Fatalf("disabled checker restricted %+v", res.Violations)
+	}
+}
@@ -17,6 +17,11 @@ type Config struct {
 	CatalogSync CatalogSyncConfig `mapstructure:"catalog_sync"`
 	ClobStream  ClobStreamConfig  `mapstructure:"clob_stream"`
 	ClobREST    ClobRESTConfig    `mapstructure:"clob_rest"`
+	PaaSLogs    PaaSLogsConfig    `mapstructure:"paas_logs"`
+	Settings    SettingsConfig    `mapstructure:"settings"`
+
+	// ClobEndpoints tunes selection among the CLOB REST and WS endpoints.
+	ClobEndpoints ClobEndpointsConfig `mapstructure:"clob_endpoints"`
 
 	// V2 extensions (L4-L6).
 	StrategyEngine   StrategyEngineConfig   `mapstructure:"strategy_engine"`
@@ -24,7 +29,29 @@ type Config struct {
 	Risk             RiskConfig             `mapstructure:"risk"`
 	Labeler          LabelerConfig          `mapstructure:"labeler"`
 	SettlementIngest SettlementIngestConfig `mapstructure:"settlement_ingest"`
+	PositionImport   PositionImportConfig   `mapstructure:"position_import"`
+	CashOps          CashOpsConfig          `mapstructure:"cash_ops"`
+	Retention        RetentionConfig        `mapstructure:"retention"`
+	OpportunityDecay OpportunityDecayConfig `mapstructure:"opportunity_decay"`
+	MarketDataGaps   MarketDataGapsConfig   `mapstructure:"market_data_gaps"`
 	AutoExecutor     AutoExecutorConfig     `mapstructure:"auto_executor"`
+	Governor         GovernorConfig         `mapstructure:"governor"`
+	Compliance       ComplianceConfig       `mapstructure:"compliance"`
+	Bench            BenchConfig            `mapstructure:"bench"`
+	TradingDay       TradingDayConfig       `mapstructure:"trading_day"`
+	Chaos            ChaosConfig            `mapstructure:"chaos"`
+	Rewards          RewardsConfig          `mapstructure:"rewards"`
+	Regime           RegimeConfig           `mapstructure:"regime"`
+	SLO              SLOConfig              `mapstructure:"slo"`
+	Events           EventsConfig           `mapstructure:"events"`
+	Outbox           OutboxConfig           `mapstructure:"outbox"`
+	StrategyHoldout  StrategyHoldoutConfig  `mapstructure:"strategy_holdout"`
+	TradeWebhooks    TradeWebhooksConfig    `mapstructure:"trade_webhooks"`
+	PortfolioDiff    PortfolioDiffConfig    `mapstructure:"portfolio_diff"`
+	Capacity         CapacityConfig         `mapstructure:"capacity"`
+	StrategyDrift    StrategyDriftConfig    `mapstructure:"strategy_drift"`
+	Onchain          OnchainConfig          `mapstructure:"onchain"`
+	SafeMode         SafeModeConfig         `mapstructure:"safe_mode"`
 	StrategyDefaults map[string]any         `mapstructure:"strategy_defaults"`
 }
 
@@ -34,6 +61,15 @@ type AppConfig struct {
 
 type ServerConfig struct {
 	HTTPAddr string `mapstructure:"http_addr"`
+	// IdempotencyTTL is how long a V2 write's Idempotency-Key and response
+	// are kept for replay.
+	IdempotencyTTL time.Duration `mapstructure:"idempotency_ttl"`
+	// GRPCAddr, when set, serves the polymarket.v1 gRPC interface for
+	// co-located bots. It has no gateway in front, so callers' tokens are
+	// verified with the platform; keep it on a private interface anyway.
+	GRPCAddr string `mapstructure:"grpc_addr"`
+	// GRPCPollInterval is how often gRPC streams look for changed rows.
+	GRPCPollInterval time.Duration `mapstructure:"grpc_poll_interval"`
 }
 
 type LogConfig struct {
@@ -43,9 +79,15 @@ type LogConfig struct {
 	Sampling          bool   `mapstructure:"sampling"`
 	DisableCaller     bool   `mapstructure:"disable_caller"`
 	DisableStacktrace bool   `mapstructure:"disable_stacktrace"`
+	// RingSize is how many recent entries are kept in memory for the
+	// /api/v2/system/logs endpoints; 0 disables them.
+	RingSize int `mapstructure:"ring_size"`
 }
 
 type DBConfig struct {
+	// Driver is "postgres" (default) or "sqlite" for local development; with
+	// sqlite the DSN is a file path such as "polymarket.db".
+	Driver          string        `mapstructure:"driver"`
 	DSN             string        `mapstructure:"dsn"`
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
@@ -54,9 +96,305 @@ type DBConfig struct {
 	Timezone        string        `mapstructure:"timezone"`
 }
 
+// GovernorConfig bounds heavy admin and background jobs (stats rebuilds,
+// backfills, re-encryption) so they cannot starve the live path. Every job
+// takes one of MaxConcurrent slots and its class's DB connections out of
+// DBConnBudget, leaving the rest of the pool to live queries. Jobs that do not
+// fit wait in a queue of at most MaxQueue, higher class priority first;
+// admin requests give up after QueueTimeout. Classes override the built-in
+// rebuild, backfill and admin classes.
+type GovernorConfig struct {
+	Enabled       bool                      `mapstructure:"enabled"`
+	MaxConcurrent int                       `mapstructure:"max_concurrent"`
+	DBConnBudget  int                       `mapstructure:"db_conn_budget"`
+	MaxQueue      int                       `mapstructure:"max_queue"`
+	QueueTimeout  time.Duration             `mapstructure:"queue_timeout"`
+	Classes       map[string]JobClassConfig `mapstructure:"classes"`
+}
+
+// JobClassConfig limits one class of heavy jobs. CPUShare below 1 throttles
+// each job to about that fraction of a core by pausing between batches.
+type JobClassConfig struct {
+	MaxConcurrent int     `mapstructure:"max_concurrent"`
+	DBConns       int     `mapstructure:"db_conns"`
+	CPUShare      float64 `mapstructure:"cpu_share"`
+	Priority      int     `mapstructure:"priority"`
+}
+
+// ComplianceConfig restricts trading in markets carrying a restricted market
+// label or whose event has a restricted category (catalog tag slug or label).
+// Jurisdiction picks an entry of Jurisdictions whose rules add to the base
+// lists. Restricted markets fail preflight and are dropped by the strategy
+// engine unless an admin override is active; overrides last OverrideTTL
+// unless the request asks for less.
+type ComplianceConfig struct {
+	Enabled              bool                       `mapstructure:"enabled"`
+	RestrictedLabels     []string                   `mapstructure:"restricted_labels"`
+	RestrictedCategories []string                   `mapstructure:"restricted_categories"`
+	Jurisdiction         string                     `mapstructure:"jurisdiction"`
+	Jurisdictions        map[string]ComplianceRules `mapstructure:"jurisdictions"`
+	OverrideTTL          time.Duration              `mapstructure:"override_ttl"`
+}
+
+// ComplianceRules are the extra restrictions of one jurisdiction.
+type ComplianceRules struct {
+	RestrictedLabels     []string `mapstructure:"restricted_labels"`
+	RestrictedCategories []string `mapstructure:"restricted_categories"`
+}
+
+// BenchConfig sets up the on-demand benchmark endpoint. Runs use a private,
+// seeded in-memory database, never the service's own. When BaselinePath names
+// a report, each run is compared with it and fails if a scenario's p95
+// latency grew by more than ThresholdPct.
+type BenchConfig struct {
+	BaselinePath string  `mapstructure:"baseline_path"`
+	ThresholdPct float64 `mapstructure:"threshold_pct"`
+	Iterations   int     `mapstructure:"iterations"`
+	Markets      int     `mapstructure:"markets"`
+}
+
+// TradingDayConfig sets where one trading day ends and the next begins:
+// BoundaryHour (0-23) local time in Timezone, an IANA name. Daily stats,
+// daily loss limits and daily trade caps all use it; the default is UTC
+// midnight.
+type TradingDayConfig struct {
+	Timezone     string `mapstructure:"timezone"`
+	BoundaryHour int    `mapstructure:"boundary_hour"`
+}
+
+// ChaosConfig enables the fault-injection admin API. It is refused when
+// app.env is prod/production/live whatever Enabled says. A fault lasts at
+// most MaxDuration.
+type ChaosConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	MaxDuration time.Duration `mapstructure:"max_duration"`
+}
+
+// RewardsConfig controls liquidity reward tracking: every SampleInterval our
+// resting orders are scored against the latest books of their markets.
+type RewardsConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	SampleInterval time.Duration `mapstructure:"sample_interval"`
+}
+
+// RegimeConfig classifies each of Categories (catalog tag slug or label)
+// every Interval from the one-minute candles of up to MaxMarkets of its open
+// markets over Lookback, plus its settlements over the same window:
+//   - news_driven when at least NewsJumpShare of the markets moved
+//     JumpThreshold or more within a minute, or when NewsSettlements (0 off)
+//     or more markets settled;
+//   - trending when the moves were directional: efficiency (net over path
+//     length) at least TrendEfficiency and mean net move at least
+//     TrendMomentum;
+//   - choppy otherwise.
+//
+// Markets with fewer than MinCandles candles are left out. Adjustments maps
+// a regime to the risk filter's adjustment of opportunities in that regime;
+// Strategies overrides it per strategy name and regime.
+type RegimeConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Interval        time.Duration `mapstructure:"interval"`
+	Categories      []string      `mapstructure:"categories"`
+	Lookback        time.Duration `mapstructure:"lookback"`
+	MaxMarkets      int           `mapstructure:"max_markets"`
+	MinCandles      int           `mapstructure:"min_candles"`
+	TrendEfficiency float64       `mapstructure:"trend_efficiency"`
+	TrendMomentum   float64       `mapstructure:"trend_momentum"`
+	JumpThreshold   float64       `mapstructure:"jump_threshold"`
+	NewsJumpShare   float64       `mapstructure:"news_jump_share"`
+	NewsSettlements int           `mapstructure:"news_settlements"`
+
+	Adjustments map[string]RegimeAdjustment            `mapstructure:"adjustments"`
+	Strategies  map[string]map[string]RegimeAdjustment `mapstructure:"strategies"`
+}
+
+// RegimeAdjustment tightens risk for opportunities in a regime: Block rejects
+// them, MinEdgePct (a fraction, as in strategy params) rejects thinner
+// edges, and SizeMultiplier (0 = unchanged) scales their max size.
+type RegimeAdjustment struct {
+	Block          bool    `mapstructure:"block"`
+	MinEdgePct     float64 `mapstructure:"min_edge_pct"`
+	SizeMultiplier float64 `mapstructure:"size_multiplier"`
+}
+
+// SLOConfig defines the data pipeline freshness SLOs, evaluated every
+// Interval: catalog sync scopes succeeded within Catalog.MaxAge, books of
+// streamed tokens updated within Books.MaxAge, and closed markets of the
+// last SettlementLookback settled within Settlements.MaxAge of closing. Each
+// evaluation counts good and total items; an SLO burns its error budget
+// (1 - Objective) when the bad share exceeds it. Fast and Slow are the
+// multi-window burn-rate alerts, broadcast as NotifyEvent through the
+// platform notification config and repeated at most once per Cooldown.
+type SLOConfig struct {
+	Enabled            bool          `mapstructure:"enabled"`
+	Interval           time.Duration `mapstructure:"interval"`
+	Catalog            SLOTarget     `mapstructure:"catalog"`
+	CatalogScopes      []string      `mapstructure:"catalog_scopes"`
+	Books              SLOTarget     `mapstructure:"books"`
+	Settlements        SLOTarget     `mapstructure:"settlements"`
+	SettlementLookback time.Duration `mapstructure:"settlement_lookback"`
+	Fast               SLOBurnAlert  `mapstructure:"fast"`
+	Slow               SLOBurnAlert  `mapstructure:"slow"`
+	Cooldown           time.Duration `mapstructure:"cooldown"`
+	NotifyEvent        string        `mapstructure:"notify_event"`
+}
+
+// SLOTarget is one SLO: items older than MaxAge are bad, and Objective is
+// the good share to meet (e.g. 0.99).
+type SLOTarget struct {
+	MaxAge    time.Duration `mapstructure:"max_age"`
+	Objective float64       `mapstructure:"objective"`
+}
+
+// SLOBurnAlert fires when the burn rate over both LongWindow and
+// ShortWindow reaches BurnRate; the short window makes it resolve quickly
+// once the pipeline recovers.
+type SLOBurnAlert struct {
+	Severity    string        `mapstructure:"severity"`
+	LongWindow  time.Duration `mapstructure:"long_window"`
+	ShortWindow time.Duration `mapstructure:"short_window"`
+	BurnRate    float64       `mapstructure:"burn_rate"`
+}
+
+// EventsConfig is the Postgres LISTEN/NOTIFY bridge: repository writes
+// NOTIFY Channel on new opportunities, plan status changes and fills, and
+// the auto executor and position manager wake on them instead of waiting for
+// their next poll. While it is on they poll only every PollInterval as a
+// fallback. With SQLite events are delivered in-process only.
+type EventsConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Channel        string        `mapstructure:"channel"`
+	PollInterval   time.Duration `mapstructure:"poll_interval"`
+	ReconnectDelay time.Duration `mapstructure:"reconnect_delay"`
+}
+
+// OutboxConfig drives delivery of the side effects handlers record in the
+// outbox alongside their writes. Failed deliveries back off exponentially
+// from RetryBackoff up to MaxBackoff and are marked dead after MaxAttempts;
+// delivered messages are deleted after Retention. While it is off handlers
+// send those side effects best-effort after the write, as before.
+type OutboxConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Interval     time.Duration `mapstructure:"interval"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	MaxBackoff   time.Duration `mapstructure:"max_backoff"`
+	// Lease is how long a claimed message is hidden from other dispatchers
+	// while it is being delivered.
+	Lease          time.Duration `mapstructure:"lease"`
+	Retention      time.Duration `mapstructure:"retention"`
+	WebhookTimeout time.Duration `mapstructure:"webhook_timeout"`
+	// WebhookSecret, when set, signs webhook deliveries like catalog
+	// webhooks, in X-Outbox-Signature.
+	WebhookSecret string `mapstructure:"webhook_secret"`
+}
+
+// StrategyHoldoutConfig forward-tests strategy params changes: for Duration
+// after a change, Pct percent of the strategy's markets keep being evaluated
+// under the old params. A change is judged once each cohort has MinSamples
+// settled plans.
+type StrategyHoldoutConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Pct        float64       `mapstructure:"pct"`
+	Duration   time.Duration `mapstructure:"duration"`
+	MinSamples int           `mapstructure:"min_samples"`
+}
+
+// TradeWebhooksConfig controls delivery of fill, executed-plan and
+// closed-position webhooks. Deliveries go through the outbox, which retries
+// them with its backoff; each attempt is bounded by Timeout.
+type TradeWebhooksConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// PortfolioDiffConfig flags anomalies between consecutive portfolio
+// snapshots: cost basis moving by CostBasisJumpPct percent, total PnL
+// swinging by PnLSwingUSD, and the open position count changing by more
+// than the fills in between could explain. Money moves under MinChangeUSD
+// are never flagged. New flags are broadcast as NotifyEvent.
+type PortfolioDiffConfig struct {
+	Enabled          bool    `mapstructure:"enabled"`
+	CostBasisJumpPct float64 `mapstructure:"cost_basis_jump_pct"`
+	PnLSwingUSD      float64 `mapstructure:"pnl_swing_usd"`
+	MinChangeUSD     float64 `mapstructure:"min_change_usd"`
+	NotifyEvent      string  `mapstructure:"notify_event"`
+}
+
+// CapacityConfig estimates every Interval how much each enabled strategy
+// can deploy: up to MaxOpportunities of its active opportunities are sized
+// up to MaxSizeUSD each until net edge falls to MinNetEdgePct or a book runs
+// out. Forecast slippage is scaled by the strategy's realized to forecast
+// cost over ImpactLookback once it has ImpactMinSamples plans. A strategy
+// whose exposure cap rises above its capacity is broadcast as NotifyEvent.
+type CapacityConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	Interval         time.Duration `mapstructure:"interval"`
+	MaxOpportunities int           `mapstructure:"max_opportunities"`
+	MaxSizeUSD       float64       `mapstructure:"max_size_usd"`
+	MinNetEdgePct    float64       `mapstructure:"min_net_edge_pct"`
+	ImpactLookback   time.Duration `mapstructure:"impact_lookback"`
+	ImpactMinSamples int           `mapstructure:"impact_min_samples"`
+	NotifyEvent      string        `mapstructure:"notify_event"`
+}
+
+// StrategyDriftConfig compares every Interval each strategy with a backtest
+// expectation against its live daily stats over the last Window. It is
+// flagged when trades per day fall below (1-FrequencyDropPct) of expected
+// and, once the window holds MinTrades, when its win rate is more than
+// WinRateDrop below expected or its average edge below (1-EdgeDropPct) of
+// expected. A strategy that starts drifting is broadcast as NotifyEvent.
+type StrategyDriftConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	Interval         time.Duration `mapstructure:"interval"`
+	Window           time.Duration `mapstructure:"window"`
+	MinTrades        int           `mapstructure:"min_trades"`
+	FrequencyDropPct float64       `mapstructure:"frequency_drop_pct"`
+	WinRateDrop      float64       `mapstructure:"win_rate_drop"`
+	EdgeDropPct      float64       `mapstructure:"edge_drop_pct"`
+	NotifyEvent      string        `mapstructure:"notify_event"`
+}
+
+// OnchainConfig reads, every Interval, the CTF condition of up to BatchSize
+// open markets from a Polygon JSON-RPC endpoint and stores their outcome
+// slot count and reported payouts. A market seen resolved onchain before
+// Gamma closes it is broadcast as NotifyEvent.
+type OnchainConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	RPCURL      string        `mapstructure:"rpc_url"`
+	CTFAddress  string        `mapstructure:"ctf_address"`
+	Interval    time.Duration `mapstructure:"interval"`
+	BatchSize   int           `mapstructure:"batch_size"`
+	NotifyEvent string        `mapstructure:"notify_event"`
+}
+
+// SafeModeConfig shapes the safe mode operators enter during incidents. It
+// always turns off the auto executor and strategy engine switches, plus the
+// sync switches in PauseSwitches; position price refresh keeps running.
+type SafeModeConfig struct {
+	CancelOpenOrders bool     `mapstructure:"cancel_open_orders"`
+	PauseSwitches    []string `mapstructure:"pause_switches"`
+	NotifyEvent      string   `mapstructure:"notify_event"`
+}
+
 type CronConfig struct {
 	Enabled     bool   `mapstructure:"enabled"`
 	CatalogSync string `mapstructure:"catalog_sync"`
+	// PlanTriggers is how often time-triggered plans are checked; empty
+	// disables them.
+	PlanTriggers string `mapstructure:"plan_triggers"`
+	// PlanTriggerMaxDelay fails a trigger found more than this late, e.g.
+	// after downtime, instead of firing it; 0 always fires.
+	PlanTriggerMaxDelay time.Duration `mapstructure:"plan_trigger_max_delay"`
+	// CatalogSyncProfiles are extra events syncs, each on its own schedule
+	// and scoped by a catalog_sync profile.
+	CatalogSyncProfiles []CatalogSyncJob `mapstructure:"catalog_sync_profiles"`
+}
+
+type CatalogSyncJob struct {
+	Profile  string `mapstructure:"profile"`
+	Schedule string `mapstructure:"schedule"`
 }
 
 type GammaConfig struct {
@@ -65,42 +403,218 @@ type GammaConfig struct {
 }
 
 type CatalogSyncConfig struct {
-	Scope             string        `mapstructure:"scope"`
-	PageLimit         int           `mapstructure:"page_limit"`
-	MaxPages          int           `mapstructure:"max_pages"`
-	Resume            bool          `mapstructure:"resume"`
-	TagID             int           `mapstructure:"tag_id"`
+	Scope     string `mapstructure:"scope"`
+	PageLimit int    `mapstructure:"page_limit"`
+	MaxPages  int    `mapstructure:"max_pages"`
+	Resume    bool   `mapstructure:"resume"`
+	TagID     int    `mapstructure:"tag_id"`
+	// Profile scopes the main sync job by a named profile instead of TagID.
+	Profile           string        `mapstructure:"profile"`
 	Closed            string        `mapstructure:"closed"`
 	BookMaxAssets     int           `mapstructure:"book_max_assets"`
 	BookBatchSize     int           `mapstructure:"book_batch_size"`
 	BookSleepPerBatch time.Duration `mapstructure:"book_sleep_per_batch"`
+
+	// DisabledQualityRules turns off row-level data-quality rules by name.
+	DisabledQualityRules []string `mapstructure:"disabled_quality_rules"`
+
+	Webhooks CatalogWebhooksConfig `mapstructure:"webhooks"`
+
+	Anomalies CatalogAnomalyConfig `mapstructure:"anomalies"`
+
+	// Profiles are named tag scopes for the events sync, e.g. politics and
+	// crypto without sports. Each keeps its own resume cursor.
+	Profiles map[string]CatalogSyncProfile `mapstructure:"profiles"`
+}
+
+// CatalogSyncProfile syncs the events of any TagIDs (all events when empty)
+// that carry none of ExcludeTagIDs.
+type CatalogSyncProfile struct {
+	TagIDs        []int `mapstructure:"tag_ids"`
+	ExcludeTagIDs []int `mapstructure:"exclude_tag_ids"`
+}
+
+// CatalogWebhooksConfig controls delivery of new-entity webhooks. Deliveries
+// are queued (at most QueueSize) and retried up to MaxAttempts times,
+// RetryBackoff apart, each attempt bounded by Timeout.
+type CatalogWebhooksConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	QueueSize    int           `mapstructure:"queue_size"`
+}
+
+// CatalogAnomalyConfig compares each synced market's liquidity and volume
+// growth with its trailing averages, weighted Alpha per sync. Once a market
+// has MinSamples syncs, liquidity below (1-LiquidityDropPct) of a baseline
+// of at least MinBaselineLiquidityUSD is a liquidity_collapse, and volume
+// growing VolumeSpikeMultiple times faster than usual and at least
+// MinVolumeRateUSD an hour is a volume_spike. Signals expire after
+// SignalTTL. Anomalies newly seen on held markets, or on any market with
+// NotifyUnheld, are broadcast as NotifyEvent.
+type CatalogAnomalyConfig struct {
+	Enabled                 bool          `mapstructure:"enabled"`
+	Alpha                   float64       `mapstructure:"alpha"`
+	MinSamples              int           `mapstructure:"min_samples"`
+	LiquidityDropPct        float64       `mapstructure:"liquidity_drop_pct"`
+	MinBaselineLiquidityUSD float64       `mapstructure:"min_baseline_liquidity_usd"`
+	VolumeSpikeMultiple     float64       `mapstructure:"volume_spike_multiple"`
+	MinVolumeRateUSD        float64       `mapstructure:"min_volume_rate_usd"`
+	SignalTTL               time.Duration `mapstructure:"signal_ttl"`
+	NotifyUnheld            bool          `mapstructure:"notify_unheld"`
+	NotifyEvent             string        `mapstructure:"notify_event"`
 }
 
 type ClobStreamConfig struct {
 	URL             string        `mapstructure:"url"`
 	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
 	MaxAssets       int           `mapstructure:"max_assets"`
+	// BookCacheMaxAge is how long an in-memory book is served before the
+	// database copy is read again.
+	BookCacheMaxAge time.Duration `mapstructure:"book_cache_max_age"`
+	// Connection supervision: pings every HeartbeatInterval, reconnects when
+	// a ping fails or nothing arrives for StallTimeout, with jittered
+	// exponential backoff between BackoffMin and BackoffMax.
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+	PingTimeout       time.Duration `mapstructure:"ping_timeout"`
+	StallTimeout      time.Duration `mapstructure:"stall_timeout"`
+	BackoffMin        time.Duration `mapstructure:"backoff_min"`
+	BackoffMax        time.Duration `mapstructure:"backoff_max"`
+	// The trade tape keeps prints for TradeRetention and at most
+	// TradeMaxPerToken per token; zero disables either limit.
+	TradeRetention   time.Duration `mapstructure:"trade_retention"`
+	TradeMaxPerToken int           `mapstructure:"trade_max_per_token"`
+	// Endpoints are alternatives to URL; each reconnect uses the fastest
+	// healthy one.
+	Endpoints []string `mapstructure:"endpoints"`
+	// Pins add tokens to the top-market snapshot until removed or idle for
+	// PinIdleTTL (0 = never idle); MaxAssets caps the union and evicts the
+	// least recently active tokens. PinOpenPositions pins position tokens.
+	PinIdleTTL       time.Duration `mapstructure:"pin_idle_ttl"`
+	PinOpenPositions bool          `mapstructure:"pin_open_positions"`
+}
+
+// PaaSLogsConfig tunes the async PaaS log pipeline. The PaaS itself is
+// configured via EASYWEB3_API_BASE / EASYWEB3_API_KEY.
+type PaaSLogsConfig struct {
+	BufferSize    int           `mapstructure:"buffer_size"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	SendTimeout   time.Duration `mapstructure:"send_timeout"`
+	MaxBackoff    time.Duration `mapstructure:"max_backoff"`
+	// SpillPath holds undeliverable logs until the PaaS is reachable again;
+	// empty drops them instead.
+	SpillPath     string `mapstructure:"spill_path"`
+	SpillMaxBytes int64  `mapstructure:"spill_max_bytes"`
+}
+
+// SettingsConfig tunes the in-memory system_settings cache.
+type SettingsConfig struct {
+	// RefreshInterval bounds how long a switch flipped by another process
+	// takes to apply here.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
 }
 
 type ClobRESTConfig struct {
 	BaseURL string        `mapstructure:"base_url"`
 	Timeout time.Duration `mapstructure:"timeout"`
+	// Endpoints are alternatives to BaseURL (e.g. regional hosts); the
+	// fastest healthy one serves requests.
+	Endpoints []string `mapstructure:"endpoints"`
+}
+
+// ClobEndpointsConfig drives latency probing of the CLOB endpoints. An
+// endpoint with MaxFailures consecutive failed requests or probes is
+// skipped until a probe succeeds; a healthy selection only moves to an
+// endpoint at least SwitchMargin (fraction) faster.
+type ClobEndpointsConfig struct {
+	ProbeInterval time.Duration `mapstructure:"probe_interval"`
+	ProbeTimeout  time.Duration `mapstructure:"probe_timeout"`
+	MaxFailures   int           `mapstructure:"max_failures"`
+	SwitchMargin  float64       `mapstructure:"switch_margin"`
 }
 
 type StrategyEngineConfig struct {
 	Enabled          bool          `mapstructure:"enabled"`
 	ScanInterval     time.Duration `mapstructure:"scan_interval"`
 	MaxOpportunities int           `mapstructure:"max_opportunities"`
+	// RunRetention is how long evaluation_runs rows are kept.
+	RunRetention time.Duration `mapstructure:"run_retention"`
+	// NewStrategyStage is the launch stage of first-time registered
+	// strategies; CappedMaxUSD clamps opportunity size in the capped stage.
+	NewStrategyStage string  `mapstructure:"new_strategy_stage"`
+	CappedMaxUSD     float64 `mapstructure:"capped_max_usd"`
+
+	SignalQuality SignalQualityConfig  `mapstructure:"signal_quality"`
+	Bundles       StrategyBundleConfig `mapstructure:"bundles"`
+	TokenRisk     TokenRiskConfig      `mapstructure:"token_risk"`
+}
+
+// TokenRiskConfig lets crypto strategies read token liquidity and security
+// from the platform's Dexscreener/GoPlus integrations. It needs the PaaS
+// client (EASYWEB3_API_BASE / EASYWEB3_API_KEY).
+type TokenRiskConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// StrategyBundleConfig controls strategy bundle import. TrustedKeys are
+// base64 ed25519 public keys of deployments whose bundles are accepted; the
+// local signing key (PM_STRATEGY_BUNDLE_SIGNING_KEY) is always trusted.
+type StrategyBundleConfig struct {
+	TrustedKeys      []string `mapstructure:"trusted_keys"`
+	RequireSignature bool     `mapstructure:"require_signature"`
+}
+
+// SignalQualityConfig controls per signal-type precision scoring. Types whose
+// score falls below IgnoreBelow are not evaluated; between IgnoreBelow and
+// FullWeightAt opportunity confidence is scaled down, never below MinWeight.
+type SignalQualityConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Window       time.Duration `mapstructure:"window"`
+	RefreshTTL   time.Duration `mapstructure:"refresh_ttl"`
+	MinSamples   int           `mapstructure:"min_samples"`
+	IgnoreBelow  float64       `mapstructure:"ignore_below"`
+	FullWeightAt float64       `mapstructure:"full_weight_at"`
+	MinWeight    float64       `mapstructure:"min_weight"`
 }
 
 type SignalSourcesConfig struct {
 	BinanceWS    BinanceWSConfig        `mapstructure:"binance_ws"`
 	BinancePrice BinancePriceConfig     `mapstructure:"binance_price"`
+	CryptoSymbol CryptoSymbolConfig     `mapstructure:"crypto_symbol_map"`
 	WeatherAPI   WeatherAPIConfig       `mapstructure:"weather_api"`
 	NewsRSS      NewsRSSConfig          `mapstructure:"news_rss"`
 	PriceChange  PriceChangeConfig      `mapstructure:"price_change"`
 	Orderbook    OrderbookPatternConfig `mapstructure:"orderbook_pattern"`
 	Certainty    CertaintySweepConfig   `mapstructure:"certainty_sweep"`
+	SmartMoney   SmartMoneyConfig       `mapstructure:"smart_money"`
+	MarketClose  MarketCloseConfig      `mapstructure:"market_close"`
+	Adaptive     AdaptiveScheduleConfig `mapstructure:"adaptive"`
+}
+
+// AdaptiveScheduleConfig paces polling collectors by market activity.
+// Activity is the share of recently updated tokens that jumped at least
+// JumpBps (volatility) or traded (volume), scaled between the quiet and hot
+// shares. Hot markets move a collector toward MinInterval, quiet ones toward
+// MaxInterval; only collectors listed in Collectors adapt.
+type AdaptiveScheduleConfig struct {
+	Enabled         bool                             `mapstructure:"enabled"`
+	SampleInterval  time.Duration                    `mapstructure:"sample_interval"`
+	Window          time.Duration                    `mapstructure:"window"`
+	JumpBps         float64                          `mapstructure:"jump_bps"`
+	QuietJumpShare  float64                          `mapstructure:"quiet_jump_share"`
+	HotJumpShare    float64                          `mapstructure:"hot_jump_share"`
+	QuietTradeShare float64                          `mapstructure:"quiet_trade_share"`
+	HotTradeShare   float64                          `mapstructure:"hot_trade_share"`
+	Collectors      map[string]CollectorBoundsConfig `mapstructure:"collectors"`
+}
+
+type CollectorBoundsConfig struct {
+	MinInterval time.Duration `mapstructure:"min_interval"`
+	MaxInterval time.Duration `mapstructure:"max_interval"`
 }
 
 type BinanceWSConfig struct {
@@ -117,6 +631,19 @@ type BinancePriceConfig struct {
 	TriggerPct    float64       `mapstructure:"trigger_pct"`
 }
 
+// CryptoSymbolConfig discovers crypto threshold markets ("Will Bitcoin be
+// above $100,000 on March 31?") in the catalog and runs one Binance price
+// collector per derived pair, using binance_price's window and trigger.
+// Assets maps extra question aliases to base assets (e.g. "pepe": "PEPE").
+type CryptoSymbolConfig struct {
+	Enabled          bool              `mapstructure:"enabled"`
+	RefreshInterval  time.Duration     `mapstructure:"refresh_interval"`
+	EndpointTemplate string            `mapstructure:"endpoint_template"`
+	Quote            string            `mapstructure:"quote"`
+	MaxSymbols       int               `mapstructure:"max_symbols"`
+	Assets           map[string]string `mapstructure:"assets"`
+}
+
 type WeatherAPIConfig struct {
 	Enabled bool               `mapstructure:"enabled"`
 	Sources []WeatherAPISource `mapstructure:"sources"`
@@ -162,6 +689,27 @@ type CertaintySweepConfig struct {
 	Limit         int           `mapstructure:"limit"`
 }
 
+// MarketCloseConfig configures the countdown collector that feeds the
+// certainty_sweep time ladder. Markets whose YES ask is at least MinPrice
+// (or at most 1-MinPrice) within HoursToExpiry of the event end are emitted.
+type MarketCloseConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Interval      time.Duration `mapstructure:"interval"`
+	HoursToExpiry int           `mapstructure:"hours_to_expiry"`
+	MinPrice      float64       `mapstructure:"min_price"`
+	Limit         int           `mapstructure:"limit"`
+}
+
+// SmartMoneyConfig configures peer benchmark ingestion: public positions of
+// tracked wallets are polled and large builds emit smart_money_move signals.
+// The collector is switched on by the feature.signal.smart_money setting.
+type SmartMoneyConfig struct {
+	Endpoint     string        `mapstructure:"endpoint"`
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	Wallets      []string      `mapstructure:"wallets"`
+	MinDeltaUSD  float64       `mapstructure:"min_delta_usd"`
+}
+
 type RiskConfig struct {
 	MaxTotalExposureUSD  float64 `mapstructure:"max_total_exposure_usd"`
 	MaxPerMarketUSD      float64 `mapstructure:"max_per_market_usd"`
@@ -172,6 +720,101 @@ type RiskConfig struct {
 	MinDataFreshnessMs   int     `mapstructure:"min_data_freshness_ms"`
 	StaleDataAction      string  `mapstructure:"stale_data_action"`
 	RequirePreflightPass bool    `mapstructure:"require_preflight_pass"`
+	// BuyingPowerCheck fails preflight when a plan needs more than the cash
+	// ledger balance left after open exposure and reserved withdrawals.
+	BuyingPowerCheck bool `mapstructure:"buying_power_check"`
+
+	// Global rate guards; 0 disables a guard.
+	MaxPlansPerHour          int `mapstructure:"max_plans_per_hour"`
+	MaxOpenOrders            int `mapstructure:"max_open_orders"`
+	MaxAutoExecutionsPerHour int `mapstructure:"max_auto_executions_per_hour"`
+
+	// Tenants overrides limits per desk; zero values fall back to the base limits.
+	Tenants map[string]TenantRiskLimits `mapstructure:"tenants"`
+
+	VaR VaRConfig `mapstructure:"var"`
+
+	Forecast ExposureForecastConfig `mapstructure:"forecast"`
+
+	ExecutionCost ExecutionCostConfig `mapstructure:"execution_cost"`
+
+	DecisionAudit RiskDecisionAuditConfig `mapstructure:"decision_audit"`
+
+	CryptoDelta CryptoDeltaConfig `mapstructure:"crypto_delta"`
+}
+
+// RiskDecisionAuditConfig persists risk filter decisions to risk_decisions.
+// Each decision is kept with its verdict's sample rate (0..1): rejections
+// are usually all kept and passes sampled, as passes are the bulk of traffic.
+type RiskDecisionAuditConfig struct {
+	Enabled          bool    `mapstructure:"enabled"`
+	RejectSampleRate float64 `mapstructure:"reject_sample_rate"`
+	PassSampleRate   float64 `mapstructure:"pass_sample_rate"`
+}
+
+// CryptoDeltaConfig prices crypto threshold positions as digital options on
+// the underlying to report their spot delta. Volatility holds annualised
+// volatility per base asset (e.g. "BTC": 0.55); others use
+// DefaultVolatility. Spot prices come from crypto_symbol_map's endpoint.
+type CryptoDeltaConfig struct {
+	DefaultVolatility float64            `mapstructure:"default_volatility"`
+	Volatility        map[string]float64 `mapstructure:"volatility"`
+	PriceCacheTTL     time.Duration      `mapstructure:"price_cache_ttl"`
+	Hedge             CryptoHedgeConfig  `mapstructure:"hedge"`
+}
+
+// CryptoHedgeConfig sizes hedge suggestions on a spot or perp venue. Nothing
+// is traded: a suggestion is emitted per asset whose net delta is worth at
+// least MinNotionalUSD, rounded down to QtyStep and capped at MaxNotionalUSD
+// (0 = no cap). SymbolTemplate takes the base asset.
+type CryptoHedgeConfig struct {
+	Enabled        bool               `mapstructure:"enabled"`
+	Venue          string             `mapstructure:"venue"`
+	SymbolTemplate string             `mapstructure:"symbol_template"`
+	MinNotionalUSD float64            `mapstructure:"min_notional_usd"`
+	MaxNotionalUSD float64            `mapstructure:"max_notional_usd"`
+	QtyStep        map[string]float64 `mapstructure:"qty_step"`
+}
+
+// ExposureForecastConfig adds active, not yet planned opportunities to the
+// exposure the risk filter checks, each weighted by the chance its strategy's
+// opportunities become plans. The chance blends the strategy's plan rate over
+// Lookback with a prior worth MinSamples opportunities: AutoExecutePrior when
+// the strategy has an auto-execute rule, ManualPrior otherwise.
+type ExposureForecastConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	Lookback         time.Duration `mapstructure:"lookback"`
+	MinSamples       int           `mapstructure:"min_samples"`
+	AutoExecutePrior float64       `mapstructure:"auto_execute_prior"`
+	ManualPrior      float64       `mapstructure:"manual_prior"`
+}
+
+// ExecutionCostConfig prices the all-in cost a plan is forecast to pay before
+// it is approved: slippage from walking the latest ask book, FeeBps of the
+// notional and an AdverseSelectionBps buffer. Legs whose book has no depth
+// use UnknownDepthSlippagePct of their size.
+type ExecutionCostConfig struct {
+	FeeBps                  float64 `mapstructure:"fee_bps"`
+	AdverseSelectionBps     float64 `mapstructure:"adverse_selection_bps"`
+	UnknownDepthSlippagePct float64 `mapstructure:"unknown_depth_slippage_pct"`
+}
+
+type VaRConfig struct {
+	// Method is "historical" (daily portfolio returns) or "monte_carlo"
+	// (settlement of open positions at calibrated probabilities).
+	Method       string  `mapstructure:"method"`
+	Confidence   float64 `mapstructure:"confidence"`
+	LookbackDays int     `mapstructure:"lookback_days"`
+	Simulations  int     `mapstructure:"simulations"`
+	// MaxVaRUSD blocks new opportunities while VaR is at or above it. 0 disables the gate.
+	MaxVaRUSD float64 `mapstructure:"max_var_usd"`
+}
+
+type TenantRiskLimits struct {
+	MaxTotalExposureUSD float64 `mapstructure:"max_total_exposure_usd"`
+	MaxPerMarketUSD     float64 `mapstructure:"max_per_market_usd"`
+	MaxPerStrategyUSD   float64 `mapstructure:"max_per_strategy_usd"`
+	MaxDailyLossUSD     float64 `mapstructure:"max_daily_loss_usd"`
 }
 
 type LabelerConfig struct {
@@ -186,6 +829,77 @@ type SettlementIngestConfig struct {
 	BatchSize    int           `mapstructure:"batch_size"`
 }
 
+// PositionImportConfig drives the reconciler that imports positions held by
+// our wallets outside this system (source=external).
+type PositionImportConfig struct {
+	Interval time.Duration `mapstructure:"interval"`
+	// Endpoint is the data-api positions endpoint.
+	Endpoint string   `mapstructure:"endpoint"`
+	Wallets  []string `mapstructure:"wallets"`
+	Tenant   string   `mapstructure:"tenant"`
+}
+
+// CashOpsConfig drives reconciliation of completed deposits and withdrawals
+// against ERC-20 transfers of the collateral token, read from an
+// Etherscan-compatible tokentx API (Polygonscan). Wallet is the trading
+// wallet used when an operation names none; empty disables reconciliation.
+type CashOpsConfig struct {
+	Wallet            string        `mapstructure:"wallet"`
+	Endpoint          string        `mapstructure:"endpoint"`
+	APIKey            string        `mapstructure:"api_key"`
+	TokenContract     string        `mapstructure:"token_contract"`
+	ReconcileInterval time.Duration `mapstructure:"reconcile_interval"`
+	// ToleranceUSD is the largest gap between the recorded and the onchain
+	// amount that still reconciles.
+	ToleranceUSD float64 `mapstructure:"tolerance_usd"`
+}
+
+// RetentionConfig drives the purger of the retention.<table> policies kept
+// in system settings. A policy without its own run hour is purged at RunHour
+// (UTC), once a day.
+type RetentionConfig struct {
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	RunHour       int           `mapstructure:"run_hour"`
+	// BatchSize caps the rows deleted per statement.
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// OpportunityDecayConfig maps an opportunity decay_type to how its edge
+// fades and when it expires.
+type OpportunityDecayConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	TickInterval time.Duration `mapstructure:"tick_interval"`
+	// MinFactor expires an opportunity once its decayed edge falls to this
+	// share of the observed edge.
+	MinFactor float64                      `mapstructure:"min_factor"`
+	Policies  map[string]DecayPolicyConfig `mapstructure:"policies"`
+}
+
+// DecayPolicyConfig is one decay policy: kind is none, linear, exponential
+// or event_anchored.
+type DecayPolicyConfig struct {
+	Kind     string        `mapstructure:"kind"`
+	TTL      time.Duration `mapstructure:"ttl"`
+	HalfLife time.Duration `mapstructure:"half_life"`
+	Buffer   time.Duration `mapstructure:"buffer"`
+}
+
+// MarketDataGapsConfig drives stream gap detection and REST backfill.
+type MarketDataGapsConfig struct {
+	ScanInterval time.Duration `mapstructure:"scan_interval"`
+	// Lookback is the window of received WS updates each scan inspects.
+	Lookback time.Duration `mapstructure:"lookback"`
+	// A gap is a silence longer than both MinGap and GapFactor times the
+	// token's median update interval.
+	MinGap     time.Duration `mapstructure:"min_gap"`
+	GapFactor  float64       `mapstructure:"gap_factor"`
+	MinSamples int           `mapstructure:"min_samples"`
+	MaxTokens  int           `mapstructure:"max_tokens"`
+	// BackfillBatch caps REST backfills per scan; MaxAttempts retries failed ones.
+	BackfillBatch int `mapstructure:"backfill_batch"`
+	MaxAttempts   int `mapstructure:"max_attempts"`
+}
+
 type AutoExecutorConfig struct {
 	Enabled              bool          `mapstructure:"enabled"`
 	ScanInterval         time.Duration `mapstructure:"scan_interval"`
@@ -193,6 +907,45 @@ type AutoExecutorConfig struct {
 	DefaultMinConfidence float64       `mapstructure:"default_min_confidence"`
 	DefaultMinEdgePct    float64       `mapstructure:"default_min_edge_pct"`
 	DryRun               bool          `mapstructure:"dry_run"`
+	// MarketMinInterval spaces live order submissions on one market;
+	// submissions to a market are always serialized.
+	MarketMinInterval time.Duration `mapstructure:"market_min_interval"`
+	MergeChildOrders  bool          `mapstructure:"merge_child_orders"`
+	// StarvationAfter moves an opportunity that has been queued this long
+	// ahead of higher-priority ones; 0 disables.
+	StarvationAfter time.Duration `mapstructure:"starvation_after"`
+	// Preemption cancels pending auto plans of lower priority when the
+	// total exposure limit leaves too little room for a queued opportunity.
+	Preemption bool `mapstructure:"preemption"`
+	// Maker holds the defaults for plans with params.pricing_mode=maker.
+	Maker MakerPricingConfig `mapstructure:"maker"`
+	// Repricer moves resting orders back toward the book when it moves
+	// in our favor.
+	Repricer RepricerConfig `mapstructure:"repricer"`
+	// LegDependencies holds the defaults for plan legs that wait on
+	// other legs to fill.
+	LegDependencies LegDependencyConfig `mapstructure:"leg_dependencies"`
+}
+
+type MakerPricingConfig struct {
+	Improvement  float64       `mapstructure:"improvement"`
+	StepSize     float64       `mapstructure:"step_size"`
+	StepInterval time.Duration `mapstructure:"step_interval"`
+	TakerAfter   time.Duration `mapstructure:"taker_after"`
+	TickSize     float64       `mapstructure:"tick_size"`
+}
+
+type RepricerConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Threshold       float64       `mapstructure:"threshold"`
+	AggressionTicks int           `mapstructure:"aggression_ticks"`
+	MaxStep         float64       `mapstructure:"max_step"`
+	MinInterval     time.Duration `mapstructure:"min_interval"`
+}
+
+type LegDependencyConfig struct {
+	Timeout   time.Duration `mapstructure:"timeout"`
+	OnTimeout string        `mapstructure:"on_timeout"`
 }
 
 func Load(path string, envOnly bool) (Config, error) {
@@ -204,12 +957,126 @@ func Load(path string, envOnly bool) (Config, error) {
 	v.AutomaticEnv()
 	v.SetDefault("app.env", "dev")
 	v.SetDefault("server.http_addr", ":8080")
+	v.SetDefault("server.idempotency_ttl", "24h")
+	v.SetDefault("server.grpc_addr", "")
+	v.SetDefault("server.grpc_poll_interval", "250ms")
+
+	v.SetDefault("governor.enabled", true)
+	v.SetDefault("governor.max_concurrent", 2)
+	v.SetDefault("governor.db_conn_budget", 0)
+	v.SetDefault("governor.max_queue", 16)
+	v.SetDefault("governor.queue_timeout", "30s")
+	v.SetDefault("compliance.enabled", true)
+	v.SetDefault("compliance.restricted_labels", []string{})
+	v.SetDefault("compliance.restricted_categories", []string{})
+	v.SetDefault("compliance.jurisdiction", "")
+	v.SetDefault("compliance.override_ttl", "168h")
+	v.SetDefault("bench.baseline_path", "")
+	v.SetDefault("bench.threshold_pct", 20.0)
+	v.SetDefault("bench.iterations", 500)
+	v.SetDefault("bench.markets", 200)
+	v.SetDefault("trading_day.timezone", "UTC")
+	v.SetDefault("trading_day.boundary_hour", 0)
+	v.SetDefault("chaos.enabled", false)
+	v.SetDefault("chaos.max_duration", "15m")
+	v.SetDefault("rewards.enabled", true)
+	v.SetDefault("rewards.sample_interval", "1m")
+	v.SetDefault("regime.enabled", true)
+	v.SetDefault("regime.interval", "10m")
+	v.SetDefault("regime.categories", []string{"politics", "crypto", "sports"})
+	v.SetDefault("regime.lookback", "6h")
+	v.SetDefault("regime.max_markets", 50)
+	v.SetDefault("regime.min_candles", 10)
+	v.SetDefault("regime.trend_efficiency", 0.35)
+	v.SetDefault("regime.trend_momentum", 0.02)
+	v.SetDefault("regime.jump_threshold", 0.08)
+	v.SetDefault("regime.news_jump_share", 0.25)
+	v.SetDefault("regime.news_settlements", 0)
+
+	v.SetDefault("slo.enabled", true)
+	v.SetDefault("slo.interval", "1m")
+	v.SetDefault("slo.catalog.max_age", "30m")
+	v.SetDefault("slo.catalog.objective", 0.99)
+	v.SetDefault("slo.catalog_scopes", []string{"events", "markets"})
+	v.SetDefault("slo.books.max_age", "60s")
+	v.SetDefault("slo.books.objective", 0.95)
+	v.SetDefault("slo.settlements.max_age", "12h")
+	v.SetDefault("slo.settlements.objective", 0.9)
+	v.SetDefault("slo.settlement_lookback", "72h")
+	v.SetDefault("slo.fast.severity", "critical")
+	v.SetDefault("slo.fast.long_window", "1h")
+	v.SetDefault("slo.fast.short_window", "5m")
+	v.SetDefault("slo.fast.burn_rate", 14.4)
+	v.SetDefault("slo.slow.severity", "warning")
+	v.SetDefault("slo.slow.long_window", "6h")
+	v.SetDefault("slo.slow.short_window", "30m")
+	v.SetDefault("slo.slow.burn_rate", 6)
+	v.SetDefault("slo.cooldown", "1h")
+	v.SetDefault("slo.notify_event", "polymarket.slo_burn")
+
+	v.SetDefault("events.enabled", true)
+	v.SetDefault("events.channel", "polymarket_events")
+	v.SetDefault("events.poll_interval", "1m")
+	v.SetDefault("events.reconnect_delay", "5s")
+	v.SetDefault("outbox.enabled", true)
+	v.SetDefault("outbox.interval", "2s")
+	v.SetDefault("outbox.batch_size", 50)
+	v.SetDefault("outbox.max_attempts", 10)
+	v.SetDefault("outbox.retry_backoff", "5s")
+	v.SetDefault("outbox.max_backoff", "10m")
+	v.SetDefault("outbox.lease", "1m")
+	v.SetDefault("outbox.retention", "168h")
+	v.SetDefault("outbox.webhook_timeout", "10s")
+	v.SetDefault("strategy_holdout.enabled", true)
+	v.SetDefault("strategy_holdout.pct", 20)
+	v.SetDefault("strategy_holdout.duration", "168h")
+	v.SetDefault("strategy_holdout.min_samples", 20)
+	v.SetDefault("trade_webhooks.enabled", true)
+	v.SetDefault("trade_webhooks.timeout", "10s")
+	v.SetDefault("portfolio_diff.enabled", true)
+	v.SetDefault("portfolio_diff.cost_basis_jump_pct", 50)
+	v.SetDefault("portfolio_diff.pnl_swing_usd", 500)
+	v.SetDefault("portfolio_diff.min_change_usd", 50)
+	v.SetDefault("portfolio_diff.notify_event", "polymarket.portfolio_anomaly")
+	v.SetDefault("capacity.enabled", true)
+	v.SetDefault("capacity.interval", "6h")
+	v.SetDefault("capacity.max_opportunities", 200)
+	v.SetDefault("capacity.max_size_usd", 100000)
+	v.SetDefault("capacity.min_net_edge_pct", 0)
+	v.SetDefault("capacity.impact_lookback", "720h")
+	v.SetDefault("capacity.impact_min_samples", 10)
+	v.SetDefault("capacity.notify_event", "polymarket.strategy_capacity")
+	v.SetDefault("strategy_drift.enabled", true)
+	v.SetDefault("strategy_drift.interval", "24h")
+	v.SetDefault("strategy_drift.window", "336h")
+	v.SetDefault("strategy_drift.min_trades", 10)
+	v.SetDefault("strategy_drift.frequency_drop_pct", 0.5)
+	v.SetDefault("strategy_drift.win_rate_drop", 0.15)
+	v.SetDefault("strategy_drift.edge_drop_pct", 0.5)
+	v.SetDefault("strategy_drift.notify_event", "polymarket.strategy_drift")
+	v.SetDefault("onchain.enabled", false)
+	v.SetDefault("onchain.rpc_url", "https://polygon-rpc.com")
+	v.SetDefault("onchain.ctf_address", "0x4D97DCd97eC945f40cF65F87097ACe5EA0476045")
+	v.SetDefault("onchain.interval", "5m")
+	v.SetDefault("onchain.batch_size", 50)
+	v.SetDefault("onchain.notify_event", "polymarket.onchain_resolution")
+	v.SetDefault("safe_mode.cancel_open_orders", true)
+	v.SetDefault("safe_mode.pause_switches", []string{
+		"feature.catalog_sync",
+		"feature.labeler",
+		"feature.settlement_ingest",
+		"feature.market_data_backfill",
+		"feature.position_import",
+	})
+	v.SetDefault("safe_mode.notify_event", "polymarket.safe_mode")
 	v.SetDefault("log.level", "info")
 	v.SetDefault("log.encoding", "console")
 	v.SetDefault("log.development", true)
 	v.SetDefault("log.sampling", false)
 	v.SetDefault("log.disable_caller", false)
 	v.SetDefault("log.disable_stacktrace", false)
+	v.SetDefault("log.ring_size", 2000)
+	v.SetDefault("db.driver", "postgres")
 	v.SetDefault("db.max_open_conns", 20)
 	v.SetDefault("db.max_idle_conns", 5)
 	v.SetDefault("db.conn_max_lifetime", "30m")
@@ -217,6 +1084,8 @@ func Load(path string, envOnly bool) (Config, error) {
 	v.SetDefault("db.timezone", "UTC")
 	v.SetDefault("cron.enabled", true)
 	v.SetDefault("cron.catalog_sync", "@every 10m")
+	v.SetDefault("cron.plan_triggers", "@every 15s")
+	v.SetDefault("cron.plan_trigger_max_delay", "5m")
 	v.SetDefault("gamma.base_url", "https://gamma-api.polymarket.com")
 	v.SetDefault("gamma.timeout", "15s")
 	v.SetDefault("catalog_sync.enabled", true)
@@ -225,20 +1094,73 @@ func Load(path string, envOnly bool) (Config, error) {
 	v.SetDefault("catalog_sync.max_pages", 5)
 	v.SetDefault("catalog_sync.resume", true)
 	v.SetDefault("catalog_sync.tag_id", 0)
+	v.SetDefault("catalog_sync.profile", "")
 	v.SetDefault("catalog_sync.closed", "open")
 	v.SetDefault("catalog_sync.book_max_assets", 200)
 	v.SetDefault("catalog_sync.book_batch_size", 20)
 	v.SetDefault("catalog_sync.book_sleep_per_batch", "3s")
+	v.SetDefault("catalog_sync.webhooks.enabled", true)
+	v.SetDefault("catalog_sync.webhooks.timeout", "10s")
+	v.SetDefault("catalog_sync.webhooks.max_attempts", 3)
+	v.SetDefault("catalog_sync.webhooks.retry_backoff", "5s")
+	v.SetDefault("catalog_sync.webhooks.queue_size", 256)
+	v.SetDefault("catalog_sync.anomalies.enabled", true)
+	v.SetDefault("catalog_sync.anomalies.alpha", 0.3)
+	v.SetDefault("catalog_sync.anomalies.min_samples", 4)
+	v.SetDefault("catalog_sync.anomalies.liquidity_drop_pct", 0.6)
+	v.SetDefault("catalog_sync.anomalies.min_baseline_liquidity_usd", 5000)
+	v.SetDefault("catalog_sync.anomalies.volume_spike_multiple", 5)
+	v.SetDefault("catalog_sync.anomalies.min_volume_rate_usd", 1000)
+	v.SetDefault("catalog_sync.anomalies.signal_ttl", "1h")
+	v.SetDefault("catalog_sync.anomalies.notify_unheld", false)
+	v.SetDefault("catalog_sync.anomalies.notify_event", "polymarket.catalog_anomaly")
 	v.SetDefault("clob_stream.url", "")
 	v.SetDefault("clob_stream.refresh_interval", "30s")
 	v.SetDefault("clob_stream.max_assets", 200)
+	v.SetDefault("clob_stream.book_cache_max_age", "30s")
+	v.SetDefault("clob_stream.heartbeat_interval", "20s")
+	v.SetDefault("clob_stream.ping_timeout", "5s")
+	v.SetDefault("clob_stream.stall_timeout", "2m")
+	v.SetDefault("clob_stream.trade_retention", "168h")
+	v.SetDefault("clob_stream.trade_max_per_token", 20000)
+	v.SetDefault("clob_stream.backoff_min", "1s")
+	v.SetDefault("clob_stream.backoff_max", "30s")
+	v.SetDefault("clob_stream.pin_idle_ttl", "6h")
+	v.SetDefault("clob_stream.pin_open_positions", true)
 	v.SetDefault("clob_rest.base_url", "https://clob.polymarket.com")
 	v.SetDefault("clob_rest.timeout", "15s")
+	v.SetDefault("clob_endpoints.probe_interval", "30s")
+	v.SetDefault("clob_endpoints.probe_timeout", "5s")
+	v.SetDefault("clob_endpoints.max_failures", 3)
+	v.SetDefault("clob_endpoints.switch_margin", 0.2)
+	v.SetDefault("paas_logs.buffer_size", 1000)
+	v.SetDefault("paas_logs.batch_size", 50)
+	v.SetDefault("paas_logs.flush_interval", "2s")
+	v.SetDefault("paas_logs.send_timeout", "5s")
+	v.SetDefault("paas_logs.max_backoff", "1m")
+	v.SetDefault("paas_logs.spill_path", "paas_logs_spill.jsonl")
+	v.SetDefault("paas_logs.spill_max_bytes", 50<<20)
+	v.SetDefault("settings.refresh_interval", "5s")
 
 	// V2 defaults: keep disabled by default to avoid behavior changes until engine is wired.
 	v.SetDefault("strategy_engine.enabled", false)
 	v.SetDefault("strategy_engine.scan_interval", "5s")
 	v.SetDefault("strategy_engine.max_opportunities", 100)
+	v.SetDefault("strategy_engine.run_retention", "72h")
+	v.SetDefault("strategy_engine.new_strategy_stage", "dark")
+	v.SetDefault("strategy_engine.capped_max_usd", 25)
+	v.SetDefault("strategy_engine.signal_quality.enabled", true)
+	v.SetDefault("strategy_engine.signal_quality.window", "720h")
+	v.SetDefault("strategy_engine.signal_quality.refresh_ttl", "15m")
+	v.SetDefault("strategy_engine.signal_quality.min_samples", 20)
+	v.SetDefault("strategy_engine.signal_quality.ignore_below", 0.2)
+	v.SetDefault("strategy_engine.signal_quality.full_weight_at", 0.5)
+	v.SetDefault("strategy_engine.signal_quality.min_weight", 0.25)
+	v.SetDefault("strategy_engine.bundles.trusted_keys", []string{})
+	v.SetDefault("strategy_engine.bundles.require_signature", true)
+	v.SetDefault("strategy_engine.token_risk.enabled", false)
+	v.SetDefault("strategy_engine.token_risk.cache_ttl", "30m")
+	v.SetDefault("strategy_engine.token_risk.timeout", "5s")
 
 	v.SetDefault("signal_sources.binance_ws.enabled", false)
 	v.SetDefault("signal_sources.binance_ws.url", "wss://stream.binance.com:9443/ws/btcusdt@depth20@100ms")
@@ -250,6 +1172,12 @@ func Load(path string, envOnly bool) (Config, error) {
 	v.SetDefault("signal_sources.binance_price.window_seconds", 300)
 	v.SetDefault("signal_sources.binance_price.trigger_pct", 2.0)
 
+	v.SetDefault("signal_sources.crypto_symbol_map.enabled", false)
+	v.SetDefault("signal_sources.crypto_symbol_map.refresh_interval", "10m")
+	v.SetDefault("signal_sources.crypto_symbol_map.endpoint_template", "https://api.binance.com/api/v3/ticker/price?symbol=%s")
+	v.SetDefault("signal_sources.crypto_symbol_map.quote", "USDT")
+	v.SetDefault("signal_sources.crypto_symbol_map.max_symbols", 10)
+
 	v.SetDefault("signal_sources.weather_api.enabled", false)
 	v.SetDefault("signal_sources.news_rss.enabled", false)
 	v.SetDefault("signal_sources.news_rss.poll_interval", "2m")
@@ -257,12 +1185,59 @@ func Load(path string, envOnly bool) (Config, error) {
 	v.SetDefault("settlement_ingest.scan_interval", "6h")
 	v.SetDefault("settlement_ingest.lookback_days", 14)
 	v.SetDefault("settlement_ingest.batch_size", 200)
+
+	v.SetDefault("position_import.interval", "5m")
+	v.SetDefault("position_import.endpoint", "https://data-api.polymarket.com/positions")
+	v.SetDefault("position_import.tenant", "default")
+	v.SetDefault("cash_ops.wallet", "")
+	v.SetDefault("cash_ops.endpoint", "https://api.polygonscan.com/api")
+	v.SetDefault("cash_ops.api_key", "")
+	v.SetDefault("cash_ops.token_contract", "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174")
+	v.SetDefault("cash_ops.reconcile_interval", "10m")
+	v.SetDefault("cash_ops.tolerance_usd", 0.01)
+	v.SetDefault("retention.check_interval", "10m")
+	v.SetDefault("retention.run_hour", 3)
+	v.SetDefault("retention.batch_size", 5000)
+	v.SetDefault("opportunity_decay.enabled", true)
+	v.SetDefault("opportunity_decay.tick_interval", "30s")
+	v.SetDefault("opportunity_decay.min_factor", 0.25)
+	v.SetDefault("opportunity_decay.policies", map[string]any{
+		"exponential": map[string]any{"kind": "exponential", "half_life": "10m"},
+		"linear":      map[string]any{"kind": "linear", "ttl": "30m"},
+		"time_bound":  map[string]any{"kind": "event_anchored", "buffer": "5m"},
+		"step":        map[string]any{"kind": "none"},
+		"none":        map[string]any{"kind": "none"},
+	})
+	v.SetDefault("market_data_gaps.scan_interval", "5m")
+	v.SetDefault("market_data_gaps.lookback", "6h")
+	v.SetDefault("market_data_gaps.min_gap", "2m")
+	v.SetDefault("market_data_gaps.gap_factor", 5.0)
+	v.SetDefault("market_data_gaps.min_samples", 20)
+	v.SetDefault("market_data_gaps.max_tokens", 200)
+	v.SetDefault("market_data_gaps.backfill_batch", 50)
+	v.SetDefault("market_data_gaps.max_attempts", 3)
 	v.SetDefault("auto_executor.enabled", false)
 	v.SetDefault("auto_executor.scan_interval", "10s")
 	v.SetDefault("auto_executor.max_opportunities", 100)
 	v.SetDefault("auto_executor.default_min_confidence", 0.8)
 	v.SetDefault("auto_executor.default_min_edge_pct", 0.05)
 	v.SetDefault("auto_executor.dry_run", true)
+	v.SetDefault("auto_executor.market_min_interval", "2s")
+	v.SetDefault("auto_executor.merge_child_orders", true)
+	v.SetDefault("auto_executor.starvation_after", "10m")
+	v.SetDefault("auto_executor.preemption", false)
+	v.SetDefault("auto_executor.maker.improvement", 0.01)
+	v.SetDefault("auto_executor.maker.step_size", 0.01)
+	v.SetDefault("auto_executor.maker.step_interval", "30s")
+	v.SetDefault("auto_executor.maker.taker_after", "5m")
+	v.SetDefault("auto_executor.maker.tick_size", 0.01)
+	v.SetDefault("auto_executor.repricer.enabled", false)
+	v.SetDefault("auto_executor.repricer.threshold", 0.02)
+	v.SetDefault("auto_executor.repricer.aggression_ticks", 1)
+	v.SetDefault("auto_executor.repricer.max_step", 0.05)
+	v.SetDefault("auto_executor.repricer.min_interval", "30s")
+	v.SetDefault("auto_executor.leg_dependencies.timeout", "10m")
+	v.SetDefault("auto_executor.leg_dependencies.on_timeout", "cancel")
 
 	v.SetDefault("signal_sources.price_change.enabled", false)
 	v.SetDefault("signal_sources.price_change.interval", "5s")
@@ -281,6 +1256,32 @@ func Load(path string, envOnly bool) (Config, error) {
 	v.SetDefault("signal_sources.certainty_sweep.hours_to_expiry", 6)
 	v.SetDefault("signal_sources.certainty_sweep.limit", 50)
 
+	v.SetDefault("signal_sources.market_close.enabled", false)
+	v.SetDefault("signal_sources.market_close.interval", "1m")
+	v.SetDefault("signal_sources.market_close.hours_to_expiry", 24)
+	v.SetDefault("signal_sources.market_close.min_price", 0.95)
+	v.SetDefault("signal_sources.market_close.limit", 100)
+
+	v.SetDefault("signal_sources.smart_money.endpoint", "https://data-api.polymarket.com/positions")
+	v.SetDefault("signal_sources.smart_money.poll_interval", "1m")
+	v.SetDefault("signal_sources.smart_money.min_delta_usd", 1000)
+	v.SetDefault("signal_sources.adaptive.enabled", false)
+	v.SetDefault("signal_sources.adaptive.sample_interval", "30s")
+	v.SetDefault("signal_sources.adaptive.window", "5m")
+	v.SetDefault("signal_sources.adaptive.jump_bps", 300)
+	v.SetDefault("signal_sources.adaptive.quiet_jump_share", 0.01)
+	v.SetDefault("signal_sources.adaptive.hot_jump_share", 0.1)
+	v.SetDefault("signal_sources.adaptive.quiet_trade_share", 0.05)
+	v.SetDefault("signal_sources.adaptive.hot_trade_share", 0.4)
+	v.SetDefault("signal_sources.adaptive.collectors", map[string]any{
+		"price_change":      map[string]any{"min_interval": "2s", "max_interval": "30s"},
+		"orderbook_pattern": map[string]any{"min_interval": "5s", "max_interval": "1m"},
+		"certainty_sweep":   map[string]any{"min_interval": "15s", "max_interval": "2m"},
+		"internal_scan":     map[string]any{"min_interval": "3s", "max_interval": "1m"},
+		"market_close":      map[string]any{"min_interval": "30s", "max_interval": "5m"},
+		"smart_money":       map[string]any{"min_interval": "30s", "max_interval": "5m"},
+	})
+
 	v.SetDefault("risk.max_total_exposure_usd", 5000)
 	v.SetDefault("risk.max_per_market_usd", 500)
 	v.SetDefault("risk.max_per_strategy_usd", 2000)
@@ -290,6 +1291,33 @@ func Load(path string, envOnly bool) (Config, error) {
 	v.SetDefault("risk.min_data_freshness_ms", 5000)
 	v.SetDefault("risk.stale_data_action", "warn")
 	v.SetDefault("risk.require_preflight_pass", false)
+	v.SetDefault("risk.buying_power_check", false)
+	v.SetDefault("risk.max_plans_per_hour", 60)
+	v.SetDefault("risk.max_open_orders", 100)
+	v.SetDefault("risk.max_auto_executions_per_hour", 30)
+	v.SetDefault("risk.var.method", "historical")
+	v.SetDefault("risk.var.confidence", 0.95)
+	v.SetDefault("risk.var.lookback_days", 90)
+	v.SetDefault("risk.var.simulations", 10000)
+	v.SetDefault("risk.var.max_var_usd", 0)
+	v.SetDefault("risk.forecast.enabled", true)
+	v.SetDefault("risk.forecast.lookback", "168h")
+	v.SetDefault("risk.forecast.min_samples", 20)
+	v.SetDefault("risk.forecast.auto_execute_prior", 0.6)
+	v.SetDefault("risk.forecast.manual_prior", 0.05)
+	v.SetDefault("risk.execution_cost.fee_bps", 0)
+	v.SetDefault("risk.execution_cost.adverse_selection_bps", 50)
+	v.SetDefault("risk.execution_cost.unknown_depth_slippage_pct", 0.01)
+	v.SetDefault("risk.decision_audit.enabled", true)
+	v.SetDefault("risk.decision_audit.reject_sample_rate", 1.0)
+	v.SetDefault("risk.decision_audit.pass_sample_rate", 0.1)
+	v.SetDefault("risk.crypto_delta.default_volatility", 0.6)
+	v.SetDefault("risk.crypto_delta.price_cache_ttl", "30s")
+	v.SetDefault("risk.crypto_delta.hedge.enabled", false)
+	v.SetDefault("risk.crypto_delta.hedge.venue", "binance_perp")
+	v.SetDefault("risk.crypto_delta.hedge.symbol_template", "%sUSDT")
+	v.SetDefault("risk.crypto_delta.hedge.min_notional_usd", 100)
+	v.SetDefault("risk.crypto_delta.hedge.max_notional_usd", 0)
 
 	v.SetDefault("labeler.enabled", false)
 	v.SetDefault("labeler.scan_interval", "5m")
@@ -2,6 +2,8 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -21,7 +23,17 @@ func Open(cfg config.DBConfig) (*DB, error) {
 		Logger: logger.Default.LogMode(logger.Silent),
 	}
 
-	gdb, err := gorm.Open(postgres.Open(cfg.DSN), gcfg)
+	var dialector gorm.Dialector
+	switch driver := strings.ToLower(strings.TrimSpace(cfg.Driver)); driver {
+	case "", "postgres", "postgresql":
+		dialector = postgres.Open(cfg.DSN)
+	case "sqlite", "sqlite3":
+		dialector = openSQLite(cfg.DSN)
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", cfg.Driver)
+	}
+
+	gdb, err := gorm.Open(dialector, gcfg)
 	if err != nil {
 		return nil, err
 	}
@@ -31,8 +43,14 @@ func Open(cfg config.DBConfig) (*DB, error) {
 		return nil, err
 	}
 
-	sqldb.SetMaxOpenConns(cfg.MaxOpenConns)
-	sqldb.SetMaxIdleConns(cfg.MaxIdleConns)
+	if IsSQLite(gdb) {
+		// SQLite allows a single writer; one connection avoids SQLITE_BUSY.
+		sqldb.SetMaxOpenConns(1)
+		sqldb.SetMaxIdleConns(1)
+	} else {
+		sqldb.SetMaxOpenConns(cfg.MaxOpenConns)
+		sqldb.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
 	sqldb.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 	sqldb.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
@@ -53,8 +71,14 @@ func Ping(db *DB) error {
 	return db.SQL.Ping()
 }
 
+// IsSQLite reports whether gdb runs on the local-development SQLite driver.
+func IsSQLite(gdb *gorm.DB) bool {
+	return gdb != nil && gdb.Dialector != nil && gdb.Dialector.Name() == "sqlite"
+}
+
 func SetTimezone(db *DB, tz string) error {
-	if tz == "" {
+	if tz == "" || db == nil || IsSQLite(db.Gorm) {
+		// SQLite stores timestamps as given; the app writes UTC everywhere.
 		return nil
 	}
 	_, err := db.SQL.Exec("SET TIME ZONE '" + tz + "'")
@@ -9,6 +9,23 @@ func AutoMigrate(db *DB) error {
 		return nil
 	}
 
+	// Positions and system settings became unique per (tenant, key); drop
+	// the single-column unique indexes of older schemas first.
+	for _, legacy := range []struct {
+		model any
+		index string
+	}{
+		{&models.Position{}, "idx_positions_token_id"},
+		{&models.SystemSetting{}, "idx_system_settings_key"},
+	} {
+		m := db.Gorm.Migrator()
+		if m.HasTable(legacy.model) && m.HasIndex(legacy.model, legacy.index) {
+			if err := m.DropIndex(legacy.model, legacy.index); err != nil {
+				return err
+			}
+		}
+	}
+
 	if err := db.Gorm.AutoMigrate(
 		&models.Series{},
 		&models.Event{},
@@ -22,6 +39,36 @@ func AutoMigrate(db *DB) error {
 		&models.LastTradePrice{},
 		&models.RawWSEvent{},
 		&models.RawRESTSnapshot{},
+		&models.PriceCandle{},
+		&models.Trade{},
+		&models.MarketDataGap{},
+		&models.CatalogQuarantine{},
+		&models.MarketChange{},
+		&models.CatalogChange{},
+		&models.MarketBaseline{},
+		&models.ComplianceOverride{},
+		&models.CatalogWebhook{},
+		&models.Campaign{},
+		&models.RewardEpoch{},
+		&models.TradeTicket{},
+		&models.StrategyBudget{},
+		&models.ResearchSnapshot{},
+		&models.AnalyticsExport{},
+		&models.CashOperation{},
+		&models.CostForecast{},
+		&models.RiskDecision{},
+		&models.MarketRegime{},
+		&models.SLOSample{},
+		&models.OutboxMessage{},
+		&models.ExecutorIntent{},
+		&models.StrategyChange{},
+		&models.StrategyCapacity{},
+		&models.StrategyExpectation{},
+		&models.StrategyDriftReport{},
+		&models.TradeWebhook{},
+		&models.TradeWebhookDelivery{},
+		&models.RuleTuning{},
+		&models.StrategyParamTuning{},
 		// L4-L6 (V2)
 		&models.Signal{},
 		&models.SignalSource{},
@@ -37,9 +84,15 @@ func AutoMigrate(db *DB) error {
 		&models.SystemSetting{},
 		&models.Position{},
 		&models.PortfolioSnapshot{},
+		&models.PortfolioAnomaly{},
 		&models.Order{},
 		&models.StrategyDailyStats{},
 		&models.MarketReview{},
+		&models.EvaluationRun{},
+		&models.AuditRecord{},
+		&models.IdempotencyKey{},
+		&models.WalletPosition{},
+		&models.WalletPositionChange{},
 	); err != nil {
 		return err
 	}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// sqliteDialector maps the Postgres column types used in model tags onto
+// types SQLite understands. In particular timestamptz must become datetime,
+// otherwise the driver returns timestamps as plain text.
+type sqliteDialector struct {
+	*sqlite.Dialector
+}
+
+func openSQLite(dsn string) gorm.Dialector {
+	return sqliteDialector{Dialector: sqlite.Open(sqliteDSN(dsn)).(*sqlite.Dialector)}
+}
+
+func (d sqliteDialector) DataTypeOf(field *schema.Field) string {
+	typ := d.Dialector.DataTypeOf(field)
+	switch strings.ToLower(strings.TrimSpace(typ)) {
+	case "timestamptz", "timestamp", "timestamp with time zone":
+		return "datetime"
+	case "jsonb":
+		return "text"
+	}
+	return typ
+}
+
+func (d sqliteDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	m := d.Dialector.Migrator(db).(sqlite.Migrator)
+	m.Dialector = d
+	return m
+}
+
+// sqliteDSN enables WAL and foreign keys unless the DSN already sets pragmas.
+func sqliteDSN(dsn string) string {
+	dsn = strings.TrimSpace(dsn)
+	if dsn == "" {
+		dsn = "polymarket.db"
+	}
+	if strings.Contains(dsn, "_pragma=") {
+		return dsn
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
@@ -0,0 +1,124 @@
+// Package events carries row-change notifications from repository writes to
+// services that would otherwise poll for them. On Postgres the repository
+// publishes with NOTIFY and a Listener feeds the Bus from LISTEN, so writes
+// from any process wake every subscriber; elsewhere the Bus is fed in-process.
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Topics published by the repository.
+const (
+	TopicOpportunityCreated = "opportunity_created"
+	TopicPlanStatus         = "plan_status"
+	TopicFillCreated        = "fill_created"
+	TopicOutboxEnqueued     = "outbox_enqueued"
+	TopicPositionClosed     = "position_closed"
+)
+
+// Event identifies a changed row; subscribers reload what they need. PlanID
+// is set on fills, Status on plan status changes.
+type Event struct {
+	Topic    string    `json:"topic"`
+	ID       uint64    `json:"id"`
+	PlanID   uint64    `json:"plan_id,omitempty"`
+	Strategy string    `json:"strategy,omitempty"`
+	Status   string    `json:"status,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// Publisher is what the repository writes to. Publishing is best-effort: a
+// lost event only delays the subscriber until its next poll.
+type Publisher interface {
+	Publish(ctx context.Context, ev Event)
+}
+
+// TxPublisher is a Publisher that can publish as part of a transaction, so
+// the event is only sent if it commits. Publishers without it are called
+// directly, before the commit.
+type TxPublisher interface {
+	PublishTx(ctx context.Context, tx *gorm.DB, ev Event)
+}
+
+// Bus fans events out to subscribers by topic. A subscriber that falls
+// behind loses events rather than blocking the publisher.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[string][]chan Event
+
+	published atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: map[string][]chan Event{}}
+}
+
+// Subscribe returns a channel receiving events of the given topics.
+func (b *Bus) Subscribe(buf int, topics ...string) <-chan Event {
+	if buf <= 0 {
+		buf = 16
+	}
+	ch := make(chan Event, buf)
+	if b == nil {
+		return ch
+	}
+	b.mu.Lock()
+	for _, topic := range topics {
+		b.subs[topic] = append(b.subs[topic], ch)
+	}
+	b.mu.Unlock()
+	return ch
+}
+
+// Publish delivers ev to the topic's subscribers without blocking.
+func (b *Bus) Publish(ctx context.Context, ev Event) {
+	if b == nil {
+		return
+	}
+	if ev.At.IsZero() {
+		ev.At = time.Now().UTC()
+	}
+	b.published.Add(1)
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, ch := range b.subs[ev.Topic] {
+		select {
+		case ch <- ev:
+		default:
+			b.dropped.Add(1)
+		}
+	}
+}
+
+// BusStats counts events published to the bus and deliveries dropped on
+// full subscribers.
+type BusStats struct {
+	Published uint64 `json:"published"`
+	Dropped   uint64 `json:"dropped"`
+}
+
+func (b *Bus) Stats() BusStats {
+	if b == nil {
+		return BusStats{}
+	}
+	return BusStats{Published: b.published.Load(), Dropped: b.dropped.Load()}
+}
+
+// Drain discards the events already queued on ch, so a burst of writes
+// wakes a poller once.
+func Drain(ch <-chan Event) {
+	for {
+		select {
+		case <-ch:
+		default:
+			return
+		}
+	}
+}
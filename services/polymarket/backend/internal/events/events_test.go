@@ -0,0 +1,35 @@
+package events
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBusFanoutAndDrop(t *testing.T) {
+	bus := NewBus()
+	plans := bus.Subscribe(1, TopicPlanStatus, TopicFillCreated)
+	opps := bus.Subscribe(4, TopicOpportunityCreated)
+	ctx := context.Background()
+
+	bus.Publish(ctx, Event{Topic: TopicPlanStatus, ID: 1, Status: "executing"})
+	// plans is full: the fill is dropped instead of blocking.
+	bus.Publish(ctx, Event{Topic: TopicFillCreated, ID: 2, PlanID: 1})
+	bus.Publish(ctx, Event{Topic: TopicOpportunityCreated, ID: 3})
+	bus.Publish(ctx, Event{Topic: TopicOpportunityCreated, ID: 4})
+
+	if ev := <-plans; ev.ID != 1 || ev.At.IsZero() {
+		t.Fatalf("plan event=%+v", ev)
+	}
+	if st := bus.Stats(); st.Published != 4 || st.Dropped != 1 {
+		t.Fatalf("stats=%+v", st)
+	}
+	if ev := <-opps; ev.ID != 3 {
+		t.Fatalf("opportunity event=%+v", ev)
+	}
+	Drain(opps)
+	select {
+	case ev := <-opps:
+		t.Fatalf("not drained: %+v", ev)
+	default:
+	}
+}
@@ -0,0 +1,140 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DefaultChannel is the NOTIFY channel when none is configured.
+const DefaultChannel = "polymarket_events"
+
+// PGNotifier publishes events with pg_notify. Postgres delivers them when the
+// writing statement commits, to every Listener on the channel including this
+// process's own.
+type PGNotifier struct {
+	DB      *gorm.DB
+	Channel string
+	Logger  *zap.Logger
+}
+
+func (n *PGNotifier) Publish(ctx context.Context, ev Event) {
+	if n == nil {
+		return
+	}
+	n.PublishTx(ctx, n.DB, ev)
+}
+
+// PublishTx notifies through tx; Postgres holds the notification until tx
+// commits and drops it on rollback.
+func (n *PGNotifier) PublishTx(ctx context.Context, tx *gorm.DB, ev Event) {
+	if n == nil || tx == nil {
+		return
+	}
+	if ev.At.IsZero() {
+		ev.At = time.Now().UTC()
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	if err := tx.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", channelOrDefault(n.Channel), string(payload)).Error; err != nil && n.Logger != nil {
+		n.Logger.Debug("event notify failed", zap.String("topic", ev.Topic), zap.Error(err))
+	}
+}
+
+// Listener holds a dedicated connection LISTENing on Channel and publishes
+// what arrives to Bus. It reconnects after ReconnectDelay when the connection
+// drops; events sent meanwhile are lost, which polling covers.
+type Listener struct {
+	DSN            string
+	Channel        string
+	Bus            *Bus
+	ReconnectDelay time.Duration
+	Logger         *zap.Logger
+
+	connected atomic.Bool
+	received  atomic.Uint64
+}
+
+// Connected reports whether the listener currently holds a connection.
+func (l *Listener) Connected() bool {
+	return l != nil && l.connected.Load()
+}
+
+// Received counts notifications received since start.
+func (l *Listener) Received() uint64 {
+	if l == nil {
+		return 0
+	}
+	return l.received.Load()
+}
+
+func (l *Listener) Run(ctx context.Context) error {
+	if l == nil || l.Bus == nil || strings.TrimSpace(l.DSN) == "" {
+		return nil
+	}
+	delay := l.ReconnectDelay
+	if delay <= 0 {
+		delay = 5 * time.Second
+	}
+	for {
+		err := l.listen(ctx)
+		l.connected.Store(false)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil && l.Logger != nil {
+			l.Logger.Warn("event listener disconnected", zap.Error(err), zap.Duration("retry_in", delay))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.DSN)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	channel := channelOrDefault(l.Channel)
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+	l.connected.Store(true)
+	if l.Logger != nil {
+		l.Logger.Info("event listener connected", zap.String("channel", channel))
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var ev Event
+		if err := json.Unmarshal([]byte(n.Payload), &ev); err != nil || ev.Topic == "" {
+			if l.Logger != nil {
+				l.Logger.Debug("event listener skipped payload", zap.String("payload", n.Payload))
+			}
+			continue
+		}
+		l.received.Add(1)
+		l.Bus.Publish(ctx, ev)
+	}
+}
+
+func channelOrDefault(channel string) string {
+	if channel = strings.TrimSpace(channel); channel != "" {
+		return channel
+	}
+	return DefaultChannel
+}
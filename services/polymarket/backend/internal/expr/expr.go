@@ -0,0 +1,749 @@
+// Package expr is a small, side-effect free condition language shared by
+// rule conditions, e.g.
+//
+//	confidence > 0.7 && edge_pct > 0.03 && label in ["safe_no"]
+//
+// Expressions are compiled against a declared set of typed variables, so
+// unknown names and type mismatches are reported before a rule is saved.
+// There are no function calls, loops or assignments, and source length and
+// nesting depth are bounded, so evaluation always terminates quickly.
+//
+// Operators by precedence (lowest first): ||, &&, comparisons (== != < <= >
+// >= in, not in), + -, * /, unary ! and -. Literals are numbers, "strings"
+// or 'strings', true/false and [lists]. `x in list` is true when x equals an
+// element; when x is itself a list it is true if any element of x is in the
+// list.
+package expr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxLength bounds the source of an expression.
+	MaxLength = 2000
+	// MaxDepth bounds the nesting of an expression.
+	MaxDepth = 32
+	// MaxListLen bounds list literals.
+	MaxListLen = 200
+)
+
+// Kind is the type of a variable or subexpression.
+type Kind int
+
+const (
+	Number Kind = iota + 1
+	String
+	Bool
+	List
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Number:
+		return "number"
+	case String:
+		return "string"
+	case Bool:
+		return "bool"
+	case List:
+		return "list"
+	}
+	return "unknown"
+}
+
+// Error is a compile or evaluation error. Pos is the byte offset in the
+// source, or -1 when it does not apply.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	if e.Pos < 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+func errorf(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Program is a compiled boolean expression.
+type Program struct {
+	src  string
+	root node
+	vars []string
+}
+
+func (p *Program) String() string { return p.src }
+
+// Vars returns the variables the expression reads, sorted.
+func (p *Program) Vars() []string {
+	return append([]string(nil), p.vars...)
+}
+
+// Uses reports whether the expression reads name.
+func (p *Program) Uses(name string) bool {
+	i := sort.SearchStrings(p.vars, name)
+	return i < len(p.vars) && p.vars[i] == name
+}
+
+// Compile parses src and type-checks it against vars. The expression must
+// be boolean.
+func Compile(src string, vars map[string]Kind) (*Program, error) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return nil, errorf(-1, "empty expression")
+	}
+	if len(src) > MaxLength {
+		return nil, errorf(-1, "expression longer than %d characters", MaxLength)
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errorf(t.pos, "unexpected %q", t.text)
+	}
+	used := map[string]bool{}
+	kind, err := check(root, vars, used)
+	if err != nil {
+		return nil, err
+	}
+	if kind != Bool {
+		return nil, errorf(root.position(), "expression must be boolean, got %s", kind)
+	}
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &Program{src: src, root: root, vars: names}, nil
+}
+
+// Eval evaluates the program. Numbers in env may be any Go integer or float
+// type; lists may be []any, []string or []float64. Every variable the
+// expression uses must be present.
+func (p *Program) Eval(env map[string]any) (bool, error) {
+	v, err := eval(p.root, env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, errorf(-1, "expression did not evaluate to a bool")
+	}
+	return b, nil
+}
+
+// ---- lexer
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+	num  float64
+}
+
+var twoCharOps = []string{"&&", "||", "==", "!=", "<=", ">="}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		ch := src[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch >= '0' && ch <= '9' || ch == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.' || src[i] == 'e' || src[i] == 'E' ||
+				(src[i] == '-' || src[i] == '+') && (src[i-1] == 'e' || src[i-1] == 'E')) {
+				i++
+			}
+			n, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil || math.IsInf(n, 0) {
+				return nil, errorf(start, "invalid number %q", src[start:i])
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[start:i], pos: start, num: n})
+		case ch == '"' || ch == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, errorf(start, "unterminated string")
+				}
+				if src[i] == ch {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+				}
+				b.WriteByte(src[i])
+				i++
+			}
+			toks = append(toks, token{kind: tokString, text: b.String(), pos: start})
+		case ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z':
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] == '.' || src[i] >= 'a' && src[i] <= 'z' || src[i] >= 'A' && src[i] <= 'Z' || src[i] >= '0' && src[i] <= '9') {
+				i++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range twoCharOps {
+				if strings.HasPrefix(src[i:], op) {
+					toks = append(toks, token{kind: tokOp, text: op, pos: i})
+					i += 2
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			if strings.IndexByte("!<>+-*/()[],", ch) < 0 {
+				return nil, errorf(i, "unexpected character %q", string(ch))
+			}
+			toks = append(toks, token{kind: tokOp, text: string(ch), pos: i})
+			i++
+		}
+	}
+	return append(toks, token{kind: tokEOF, text: "end of expression", pos: len(src)}), nil
+}
+
+// ---- parser
+
+type node interface{ position() int }
+
+type (
+	litNode struct {
+		pos int
+		val any
+	}
+	varNode struct {
+		pos  int
+		name string
+	}
+	listNode struct {
+		pos   int
+		items []node
+	}
+	unaryNode struct {
+		pos int
+		op  string
+		x   node
+	}
+	binaryNode struct {
+		pos  int
+		op   string
+		l, r node
+	}
+)
+
+func (n *litNode) position() int    { return n.pos }
+func (n *varNode) position() int    { return n.pos }
+func (n *listNode) position() int   { return n.pos }
+func (n *unaryNode) position() int  { return n.pos }
+func (n *binaryNode) position() int { return n.pos }
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) isOp(text string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.text == text
+}
+
+func (p *parser) isKeyword(text string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.isOp(text) {
+		t := p.peek()
+		return errorf(t.pos, "expected %q, found %q", text, t.text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parseOr(depth int) (node, error) {
+	if depth > MaxDepth {
+		return nil, errorf(p.peek().pos, "expression nested deeper than %d", MaxDepth)
+	}
+	l, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		t := p.next()
+		r, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{pos: t.pos, op: "||", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd(depth int) (node, error) {
+	l, err := p.parseCompare(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		t := p.next()
+		r, err := p.parseCompare(depth)
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{pos: t.pos, op: "&&", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseCompare(depth int) (node, error) {
+	l, err := p.parseAdd(depth)
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	op := ""
+	switch {
+	case t.kind == tokOp && (t.text == "==" || t.text == "!=" || t.text == "<" || t.text == "<=" || t.text == ">" || t.text == ">="):
+		op = t.text
+		p.next()
+	case p.isKeyword("in"):
+		op = "in"
+		p.next()
+	case p.isKeyword("not"):
+		p.next()
+		if !p.isKeyword("in") {
+			return nil, errorf(p.peek().pos, "expected \"in\" after \"not\"")
+		}
+		p.next()
+		op = "not in"
+	default:
+		return l, nil
+	}
+	r, err := p.parseAdd(depth)
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{pos: t.pos, op: op, l: l, r: r}, nil
+}
+
+func (p *parser) parseAdd(depth int) (node, error) {
+	l, err := p.parseMul(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+") || p.isOp("-") {
+		t := p.next()
+		r, err := p.parseMul(depth)
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{pos: t.pos, op: t.text, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseMul(depth int) (node, error) {
+	l, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*") || p.isOp("/") {
+		t := p.next()
+		r, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{pos: t.pos, op: t.text, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary(depth int) (node, error) {
+	if p.isOp("!") || p.isOp("-") {
+		if depth > MaxDepth {
+			return nil, errorf(p.peek().pos, "expression nested deeper than %d", MaxDepth)
+		}
+		t := p.next()
+		x, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{pos: t.pos, op: t.text, x: x}, nil
+	}
+	return p.parsePrimary(depth)
+}
+
+func (p *parser) parsePrimary(depth int) (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &litNode{pos: t.pos, val: t.num}, nil
+	case tokString:
+		return &litNode{pos: t.pos, val: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &litNode{pos: t.pos, val: true}, nil
+		case "false":
+			return &litNode{pos: t.pos, val: false}, nil
+		case "in", "not":
+			return nil, errorf(t.pos, "unexpected %q", t.text)
+		}
+		if p.isOp("(") {
+			return nil, errorf(t.pos, "function calls are not supported")
+		}
+		return &varNode{pos: t.pos, name: t.text}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			x, err := p.parseOr(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			list := &listNode{pos: t.pos}
+			for !p.isOp("]") {
+				if len(list.items) > 0 {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+				if len(list.items) >= MaxListLen {
+					return nil, errorf(t.pos, "list longer than %d items", MaxListLen)
+				}
+				item, err := p.parseAdd(depth + 1)
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+			}
+			p.next()
+			return list, nil
+		}
+	}
+	return nil, errorf(t.pos, "unexpected %q", t.text)
+}
+
+// ---- type checking
+
+func check(n node, vars map[string]Kind, used map[string]bool) (Kind, error) {
+	switch n := n.(type) {
+	case *litNode:
+		switch n.val.(type) {
+		case float64:
+			return Number, nil
+		case string:
+			return String, nil
+		}
+		return Bool, nil
+	case *varNode:
+		kind, ok := vars[n.name]
+		if !ok {
+			return 0, errorf(n.pos, "unknown variable %q", n.name)
+		}
+		used[n.name] = true
+		return kind, nil
+	case *listNode:
+		for _, item := range n.items {
+			kind, err := check(item, vars, used)
+			if err != nil {
+				return 0, err
+			}
+			if kind != Number && kind != String && kind != Bool {
+				return 0, errorf(item.position(), "list items must be numbers, strings or bools")
+			}
+		}
+		return List, nil
+	case *unaryNode:
+		kind, err := check(n.x, vars, used)
+		if err != nil {
+			return 0, err
+		}
+		want := Bool
+		if n.op == "-" {
+			want = Number
+		}
+		if kind != want {
+			return 0, errorf(n.pos, "operator %s needs a %s, got %s", n.op, want, kind)
+		}
+		return want, nil
+	case *binaryNode:
+		l, err := check(n.l, vars, used)
+		if err != nil {
+			return 0, err
+		}
+		r, err := check(n.r, vars, used)
+		if err != nil {
+			return 0, err
+		}
+		switch n.op {
+		case "&&", "||":
+			if l != Bool || r != Bool {
+				return 0, errorf(n.pos, "operator %s needs bools, got %s and %s", n.op, l, r)
+			}
+			return Bool, nil
+		case "+", "-", "*", "/":
+			if l != Number || r != Number {
+				return 0, errorf(n.pos, "operator %s needs numbers, got %s and %s", n.op, l, r)
+			}
+			return Number, nil
+		case "<", "<=", ">", ">=":
+			if l != r || (l != Number && l != String) {
+				return 0, errorf(n.pos, "operator %s needs two numbers or two strings, got %s and %s", n.op, l, r)
+			}
+			return Bool, nil
+		case "==", "!=":
+			if l != r || l == List {
+				return 0, errorf(n.pos, "operator %s needs operands of the same scalar type, got %s and %s", n.op, l, r)
+			}
+			return Bool, nil
+		case "in", "not in":
+			if r != List {
+				return 0, errorf(n.pos, "operator %s needs a list on the right, got %s", n.op, r)
+			}
+			return Bool, nil
+		}
+	}
+	return 0, errorf(n.position(), "invalid expression")
+}
+
+// ---- evaluation
+
+func eval(n node, env map[string]any) (any, error) {
+	switch n := n.(type) {
+	case *litNode:
+		return n.val, nil
+	case *varNode:
+		v, ok := env[n.name]
+		if !ok {
+			return nil, errorf(n.pos, "variable %q is not set", n.name)
+		}
+		return normalize(v), nil
+	case *listNode:
+		out := make([]any, 0, len(n.items))
+		for _, item := range n.items {
+			v, err := eval(item, env)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case *unaryNode:
+		v, err := eval(n.x, env)
+		if err != nil {
+			return nil, err
+		}
+		switch x := v.(type) {
+		case bool:
+			if n.op == "!" {
+				return !x, nil
+			}
+		case float64:
+			if n.op == "-" {
+				return -x, nil
+			}
+		}
+		return nil, errorf(n.pos, "operator %s cannot apply to %T", n.op, v)
+	case *binaryNode:
+		// && and || short-circuit.
+		if n.op == "&&" || n.op == "||" {
+			l, err := eval(n.l, env)
+			if err != nil {
+				return nil, err
+			}
+			lb, ok := l.(bool)
+			if !ok {
+				return nil, errorf(n.pos, "operator %s needs bools", n.op)
+			}
+			if n.op == "&&" && !lb || n.op == "||" && lb {
+				return lb, nil
+			}
+			r, err := eval(n.r, env)
+			if err != nil {
+				return nil, err
+			}
+			rb, ok := r.(bool)
+			if !ok {
+				return nil, errorf(n.pos, "operator %s needs bools", n.op)
+			}
+			return rb, nil
+		}
+		l, err := eval(n.l, env)
+		if err != nil {
+			return nil, err
+		}
+		r, err := eval(n.r, env)
+		if err != nil {
+			return nil, err
+		}
+		return binary(n, l, r)
+	}
+	return nil, errorf(n.position(), "invalid expression")
+}
+
+func binary(n *binaryNode, l, r any) (any, error) {
+	switch n.op {
+	case "in", "not in":
+		list, ok := r.([]any)
+		if !ok {
+			return nil, errorf(n.pos, "operator %s needs a list on the right", n.op)
+		}
+		found := false
+		if items, ok := l.([]any); ok {
+			for _, item := range items {
+				if contains(list, item) {
+					found = true
+					break
+				}
+			}
+		} else {
+			found = contains(list, l)
+		}
+		return found == (n.op == "in"), nil
+	case "==":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	}
+	if ls, ok := l.(string); ok {
+		rs, ok := r.(string)
+		if !ok {
+			return nil, errorf(n.pos, "operator %s needs two strings", n.op)
+		}
+		switch n.op {
+		case "<":
+			return ls < rs, nil
+		case "<=":
+			return ls <= rs, nil
+		case ">":
+			return ls > rs, nil
+		case ">=":
+			return ls >= rs, nil
+		}
+		return nil, errorf(n.pos, "operator %s cannot apply to strings", n.op)
+	}
+	lf, lok := l.(float64)
+	rf, rok := r.(float64)
+	if !lok || !rok {
+		return nil, errorf(n.pos, "operator %s needs numbers", n.op)
+	}
+	switch n.op {
+	case "<":
+		return lf < rf, nil
+	case "<=":
+		return lf <= rf, nil
+	case ">":
+		return lf > rf, nil
+	case ">=":
+		return lf >= rf, nil
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, errorf(n.pos, "division by zero")
+		}
+		return lf / rf, nil
+	}
+	return nil, errorf(n.pos, "unknown operator %s", n.op)
+}
+
+func contains(list []any, v any) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize maps env values to the evaluator's float64, string, bool and
+// []any representations.
+func normalize(v any) any {
+	switch x := v.(type) {
+	case int:
+		return float64(x)
+	case int32:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case uint:
+		return float64(x)
+	case uint32:
+		return float64(x)
+	case uint64:
+		return float64(x)
+	case float32:
+		return float64(x)
+	case []string:
+		out := make([]any, len(x))
+		for i, s := range x {
+			out[i] = s
+		}
+		return out
+	case []float64:
+		out := make([]any, len(x))
+		for i, f := range x {
+			out[i] = f
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, item := range x {
+			out[i] = normalize(item)
+		}
+		return out
+	}
+	return v
+}
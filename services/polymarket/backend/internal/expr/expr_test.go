@@ -0,0 +1,97 @@
+package expr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testVars = map[string]Kind{
+	"confidence": Number,
+	"edge_pct":   Number,
+	"strategy":   String,
+	"label":      List,
+	"shadow":     Bool,
+}
+
+func TestEval(t *testing.T) {
+	env := map[string]any{
+		"confidence": 0.8,
+		"edge_pct":   4,
+		"strategy":   "arb_sum",
+		"label":      []string{"safe_no", "sports"},
+		"shadow":     false,
+	}
+	cases := map[string]bool{
+		`confidence > 0.7 && edge_pct > 3 && label in ["safe_no"]`: true,
+		`confidence > 0.9 || strategy == 'arb_sum'`:                true,
+		`label not in ["politics", "crypto"]`:                      true,
+		`"sports" in label && !shadow`:                             true,
+		`edge_pct * 2 - 1 >= 7 && -edge_pct < 0`:                   true,
+		`(confidence + 0.1) / 2 == 0.45`:                           true,
+		`strategy in ["a", "b"]`:                                   false,
+		`!(edge_pct > 3)`:                                          false,
+	}
+	for src, want := range cases {
+		p, err := Compile(src, testVars)
+		if err != nil {
+			t.Fatalf("%s: compile: %v", src, err)
+		}
+		got, err := p.Eval(env)
+		if err != nil {
+			t.Fatalf("%s: eval: %v", src, err)
+		}
+		if got != want {
+			t.Fatalf("%s = %v want %v", src, got, want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	cases := map[string]string{
+		``:                    "empty expression",
+		`confidence >`:        "unexpected",
+		`confidnce > 0.5`:     `unknown variable "confidnce"`,
+		`confidence + 1`:      "must be boolean",
+		`strategy > 1`:        "two numbers or two strings",
+		`edge_pct in 3`:       "list on the right",
+		`confidence && true`:  "needs bools",
+		`len(label) > 0`:      "function calls",
+		`strategy == "x`:      "unterminated string",
+		`edge_pct > 1 ; drop`: "unexpected character",
+		`label == ["a"]`:      "same scalar type",
+		strings.Repeat("(", 40) + "true" + strings.Repeat(")", 40): "nested deeper",
+	}
+	for src, want := range cases {
+		_, err := Compile(src, testVars)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: err=%v want %q", src, err, want)
+		}
+	}
+	_, err := Compile(`confidence > 0.5 && nope`, testVars)
+	var exprErr *Error
+	if !errors.As(err, &exprErr) || exprErr.Pos != 20 {
+		t.Fatalf("err=%v want position 20", err)
+	}
+}
+
+func TestProgramVarsAndRuntimeErrors(t *testing.T) {
+	p, err := Compile(`edge_pct / confidence > 1 && label in ["x"]`, testVars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Vars(); len(got) != 3 || !p.Uses("label") || p.Uses("strategy") {
+		t.Fatalf("vars=%v", got)
+	}
+	if _, err := p.Eval(map[string]any{"edge_pct": 1.0, "confidence": 0.0, "label": nil}); err == nil || !strings.Contains(err.Error(), "division by zero") {
+		t.Fatalf("err=%v", err)
+	}
+	if _, err := p.Eval(map[string]any{"edge_pct": 1.0}); err == nil || !strings.Contains(err.Error(), "not set") {
+		t.Fatalf("err=%v", err)
+	}
+	// && short-circuits before the missing variable.
+	p, _ = Compile(`edge_pct > 5 && label in ["x"]`, testVars)
+	if ok, err := p.Eval(map[string]any{"edge_pct": 1.0}); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+}
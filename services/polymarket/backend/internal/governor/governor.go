@@ -0,0 +1,353 @@
+// Package governor admits heavy admin and background jobs against shared
+// concurrency and DB connection budgets so they do not starve the live
+// trading path.
+package governor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"polymarket/internal/config"
+)
+
+// Job classes used by the service.
+const (
+	ClassRebuild  = "rebuild"
+	ClassBackfill = "backfill"
+	ClassAdmin    = "admin"
+)
+
+var defaultClasses = map[string]config.JobClassConfig{
+	ClassRebuild:  {MaxConcurrent: 1, DBConns: 2, CPUShare: 0.5, Priority: 20},
+	ClassBackfill: {MaxConcurrent: 1, DBConns: 2, CPUShare: 0.5, Priority: 10},
+	ClassAdmin:    {MaxConcurrent: 1, DBConns: 1, CPUShare: 1, Priority: 30},
+}
+
+// ErrQueueFull is returned when MaxQueue jobs are already waiting.
+var ErrQueueFull = errors.New("job queue full")
+
+// maxPause caps one throttling pause so a job never looks hung.
+const maxPause = 2 * time.Second
+
+type waiter struct {
+	class    string
+	cls      config.JobClassConfig
+	seq      uint64
+	ready    chan struct{}
+	granted  bool
+	enqueued time.Time
+}
+
+// Governor hands out job tickets. A nil Governor admits everything at once.
+type Governor struct {
+	cfg      config.GovernorConfig
+	classes  map[string]config.JobClassConfig
+	dbBudget int
+
+	mu      sync.Mutex
+	seq     uint64
+	running map[string]int
+	total   int
+	dbInUse int
+	queue   []*waiter
+	stats   map[string]*ClassStats
+}
+
+// ClassStats counts one class's admissions since start.
+type ClassStats struct {
+	Running   int           `json:"running"`
+	Queued    int           `json:"queued"`
+	Admitted  int64         `json:"admitted"`
+	Rejected  int64         `json:"rejected"`
+	Abandoned int64         `json:"abandoned"`
+	Waited    time.Duration `json:"waited_ns"`
+}
+
+type Stats struct {
+	Enabled       bool                   `json:"enabled"`
+	MaxConcurrent int                    `json:"max_concurrent"`
+	Running       int                    `json:"running"`
+	DBConnBudget  int                    `json:"db_conn_budget"`
+	DBConnsInUse  int                    `json:"db_conns_in_use"`
+	Queued        int                    `json:"queued"`
+	Classes       map[string]*ClassStats `json:"classes"`
+}
+
+// New builds a governor. dbMaxOpen is the pool size; without an explicit
+// DBConnBudget half of it goes to heavy jobs.
+func New(cfg config.GovernorConfig, dbMaxOpen int) *Governor {
+	g := &Governor{
+		cfg:     cfg,
+		classes: map[string]config.JobClassConfig{},
+		running: map[string]int{},
+		stats:   map[string]*ClassStats{},
+	}
+	for name, cls := range defaultClasses {
+		g.classes[name] = cls
+	}
+	for name, cls := range cfg.Classes {
+		g.classes[name] = cls
+	}
+	if g.cfg.MaxConcurrent <= 0 {
+		g.cfg.MaxConcurrent = 2
+	}
+	if g.cfg.MaxQueue <= 0 {
+		g.cfg.MaxQueue = 16
+	}
+	g.dbBudget = cfg.DBConnBudget
+	if g.dbBudget <= 0 {
+		g.dbBudget = dbMaxOpen / 2
+	}
+	if g.dbBudget <= 0 {
+		g.dbBudget = 1
+	}
+	return g
+}
+
+// QueueTimeout is how long admin requests wait for a ticket.
+func (g *Governor) QueueTimeout() time.Duration {
+	if g == nil || g.cfg.QueueTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return g.cfg.QueueTimeout
+}
+
+func (g *Governor) class(name string) config.JobClassConfig {
+	cls, ok := g.classes[name]
+	if !ok {
+		cls = g.classes[ClassAdmin]
+	}
+	if cls.MaxConcurrent <= 0 {
+		cls.MaxConcurrent = 1
+	}
+	if cls.DBConns <= 0 {
+		cls.DBConns = 1
+	}
+	// A job needing more than the whole budget would never start.
+	if cls.DBConns > g.dbBudget {
+		cls.DBConns = g.dbBudget
+	}
+	if cls.CPUShare <= 0 || cls.CPUShare > 1 {
+		cls.CPUShare = 1
+	}
+	return cls
+}
+
+// Acquire waits for a ticket of class until ctx ends. The ticket must be
+// released. Waiters are served by class priority, then arrival.
+func (g *Governor) Acquire(ctx context.Context, class string) (*Ticket, error) {
+	if g == nil || !g.cfg.Enabled {
+		return &Ticket{class: class, share: 1, busySince: time.Now()}, nil
+	}
+	g.mu.Lock()
+	st := g.classStats(class)
+	if len(g.queue) >= g.cfg.MaxQueue {
+		st.Rejected++
+		g.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	g.seq++
+	w := &waiter{class: class, cls: g.class(class), seq: g.seq, ready: make(chan struct{}), enqueued: time.Now()}
+	g.queue = append(g.queue, w)
+	sort.SliceStable(g.queue, func(i, j int) bool {
+		if g.queue[i].cls.Priority != g.queue[j].cls.Priority {
+			return g.queue[i].cls.Priority > g.queue[j].cls.Priority
+		}
+		return g.queue[i].seq < g.queue[j].seq
+	})
+	g.dispatchLocked()
+	g.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return &Ticket{g: g, class: class, cls: w.cls, share: w.cls.CPUShare, busySince: time.Now()}, nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if w.granted {
+			// Granted while giving up: hand the slot back.
+			g.releaseLocked(class, w.cls)
+		} else {
+			g.removeLocked(w)
+		}
+		g.classStats(class).Abandoned++
+		return nil, ctx.Err()
+	}
+}
+
+// Run executes fn under a ticket of class. The ticket travels in fn's
+// context so long loops can call Yield.
+func (g *Governor) Run(ctx context.Context, class string, fn func(ctx context.Context) error) error {
+	t, err := g.Acquire(ctx, class)
+	if err != nil {
+		return err
+	}
+	defer t.Release()
+	return fn(WithTicket(ctx, t))
+}
+
+// dispatchLocked admits queued jobs in order. A job held back only by its
+// own class cap is skipped; one that needs shared slots or connections
+// blocks those behind it, so lower priorities cannot starve it.
+func (g *Governor) dispatchLocked() {
+	kept := g.queue[:0]
+	blocked := false
+	for _, w := range g.queue {
+		if blocked || g.running[w.class] >= w.cls.MaxConcurrent {
+			kept = append(kept, w)
+			continue
+		}
+		if g.total >= g.cfg.MaxConcurrent || g.dbInUse+w.cls.DBConns > g.dbBudget {
+			blocked = true
+			kept = append(kept, w)
+			continue
+		}
+		g.running[w.class]++
+		g.total++
+		g.dbInUse += w.cls.DBConns
+		w.granted = true
+		st := g.classStats(w.class)
+		st.Admitted++
+		st.Waited += time.Since(w.enqueued)
+		close(w.ready)
+	}
+	for i := len(kept); i < len(g.queue); i++ {
+		g.queue[i] = nil
+	}
+	g.queue = kept
+}
+
+func (g *Governor) removeLocked(w *waiter) {
+	for i, q := range g.queue {
+		if q == w {
+			g.queue = append(g.queue[:i], g.queue[i+1:]...)
+			break
+		}
+	}
+	// Removing the head may unblock those behind it.
+	g.dispatchLocked()
+}
+
+func (g *Governor) releaseLocked(class string, cls config.JobClassConfig) {
+	g.running[class]--
+	g.total--
+	g.dbInUse -= cls.DBConns
+	g.dispatchLocked()
+}
+
+func (g *Governor) classStats(class string) *ClassStats {
+	st, ok := g.stats[class]
+	if !ok {
+		st = &ClassStats{}
+		g.stats[class] = st
+	}
+	return st
+}
+
+// Stats reports current usage and per-class counters.
+func (g *Governor) Stats() Stats {
+	if g == nil {
+		return Stats{}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	out := Stats{
+		Enabled:       g.cfg.Enabled,
+		MaxConcurrent: g.cfg.MaxConcurrent,
+		Running:       g.total,
+		DBConnBudget:  g.dbBudget,
+		DBConnsInUse:  g.dbInUse,
+		Queued:        len(g.queue),
+		Classes:       map[string]*ClassStats{},
+	}
+	for name, st := range g.stats {
+		cp := *st
+		cp.Running = g.running[name]
+		out.Classes[name] = &cp
+	}
+	for _, w := range g.queue {
+		if st, ok := out.Classes[w.class]; ok {
+			st.Queued++
+		}
+	}
+	return out
+}
+
+// Ticket is an admitted job. Its zero value (from a disabled governor)
+// neither limits nor throttles.
+type Ticket struct {
+	g     *Governor
+	class string
+	cls   config.JobClassConfig
+	share float64
+
+	mu        sync.Mutex
+	busySince time.Time
+	released  bool
+}
+
+// Release returns the ticket's slot and connections. It is idempotent.
+func (t *Ticket) Release() {
+	if t == nil || t.g == nil {
+		return
+	}
+	t.mu.Lock()
+	done := t.released
+	t.released = true
+	t.mu.Unlock()
+	if done {
+		return
+	}
+	t.g.mu.Lock()
+	defer t.g.mu.Unlock()
+	t.g.releaseLocked(t.class, t.cls)
+}
+
+// Yield enforces the class CPU share: after busy time b it pauses
+// b*(1/share-1), so a job at share 0.5 runs about half the time. Jobs call
+// it between batches.
+func (t *Ticket) Yield(ctx context.Context) error {
+	if t == nil || t.share >= 1 {
+		return ctx.Err()
+	}
+	t.mu.Lock()
+	busy := time.Since(t.busySince)
+	t.mu.Unlock()
+	pause := time.Duration(float64(busy) * (1/t.share - 1))
+	if pause > maxPause {
+		pause = maxPause
+	}
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	t.mu.Lock()
+	t.busySince = time.Now()
+	t.mu.Unlock()
+	return ctx.Err()
+}
+
+type ticketKey struct{}
+
+// WithTicket attaches t to ctx for Yield.
+func WithTicket(ctx context.Context, t *Ticket) context.Context {
+	return context.WithValue(ctx, ticketKey{}, t)
+}
+
+// Yield throttles the job whose ticket ctx carries. Without a ticket it
+// only reports ctx's error.
+func Yield(ctx context.Context) error {
+	t, _ := ctx.Value(ticketKey{}).(*Ticket)
+	if t == nil {
+		return ctx.Err()
+	}
+	return t.Yield(ctx)
+}
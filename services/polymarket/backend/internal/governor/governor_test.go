@@ -0,0 +1,124 @@
+package governor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+)
+
+func TestGovernor_PriorityAndClassCap(t *testing.T) {
+	g := New(config.GovernorConfig{Enabled: true, MaxConcurrent: 1, MaxQueue: 4}, 10)
+	ctx := context.Background()
+
+	first, err := g.Acquire(ctx, ClassBackfill)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := make(chan string, 2)
+	for _, class := range []string{ClassBackfill, ClassAdmin} {
+		go func(class string) {
+			tk, err := g.Acquire(ctx, class)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			order <- class
+			tk.Release()
+		}(class)
+		for g.Stats().Queued == 0 || (class == ClassAdmin && g.Stats().Queued < 2) {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	first.Release()
+	if got := <-order; got != ClassAdmin {
+		t.Fatalf("first admitted = %s, want admin", got)
+	}
+	if got := <-order; got != ClassBackfill {
+		t.Fatalf("second admitted = %s, want backfill", got)
+	}
+	if st := g.Stats(); st.Running != 0 || st.DBConnsInUse != 0 || st.Queued != 0 {
+		t.Fatalf("stats after drain: %+v", st)
+	}
+}
+
+func TestGovernor_QueueFullAndTimeout(t *testing.T) {
+	g := New(config.GovernorConfig{Enabled: true, MaxConcurrent: 1, MaxQueue: 1}, 10)
+	held, err := g.Acquire(context.Background(), ClassRebuild)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer held.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := g.Acquire(ctx, ClassAdmin)
+		done <- err
+	}()
+	for g.Stats().Queued == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := g.Acquire(context.Background(), ClassAdmin); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("err = %v, want ErrQueueFull", err)
+	}
+	if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline", err)
+	}
+	if st := g.Stats(); st.Queued != 0 || st.Classes[ClassAdmin].Abandoned != 1 || st.Classes[ClassAdmin].Rejected != 1 {
+		t.Fatalf("stats: %+v", st.Classes[ClassAdmin])
+	}
+}
+
+func TestGovernor_DBBudget(t *testing.T) {
+	// Budget 3 fits one rebuild (2) and one admin (1) but not two rebuild-sized jobs.
+	g := New(config.GovernorConfig{Enabled: true, MaxConcurrent: 4, DBConnBudget: 3, MaxQueue: 4}, 0)
+	ctx := context.Background()
+	a, _ := g.Acquire(ctx, ClassRebuild)
+	b, _ := g.Acquire(ctx, ClassAdmin)
+	defer b.Release()
+
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := g.Acquire(short, ClassBackfill); err == nil {
+		t.Fatal("backfill admitted over DB budget")
+	}
+	a.Release()
+	c, err := g.Acquire(ctx, ClassBackfill)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Release()
+}
+
+func TestGovernor_DisabledAndYield(t *testing.T) {
+	var nilGov *Governor
+	if err := nilGov.Run(context.Background(), ClassAdmin, Yield); err != nil {
+		t.Fatal(err)
+	}
+	g := New(config.GovernorConfig{Enabled: false, MaxConcurrent: 1}, 10)
+	a, _ := g.Acquire(context.Background(), ClassAdmin)
+	b, _ := g.Acquire(context.Background(), ClassAdmin)
+	a.Release()
+	b.Release()
+
+	// A half-share job that was busy 20ms pauses about 20ms.
+	g = New(config.GovernorConfig{Enabled: true, MaxConcurrent: 1}, 10)
+	err := g.Run(context.Background(), ClassBackfill, func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		start := time.Now()
+		if err := Yield(ctx); err != nil {
+			return err
+		}
+		if d := time.Since(start); d < 15*time.Millisecond {
+			t.Fatalf("yield paused %v", d)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
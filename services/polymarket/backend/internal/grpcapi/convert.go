@@ -0,0 +1,142 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "polymarket/internal/grpcapi/polymarketv1"
+	"polymarket/internal/models"
+	"polymarket/internal/risk"
+	"polymarket/internal/service"
+)
+
+func opportunityPB(o models.Opportunity) *pb.Opportunity {
+	var marketIDs []string
+	_ = json.Unmarshal(o.MarketIDs, &marketIDs)
+	out := &pb.Opportunity{
+		Id:                o.ID,
+		Tenant:            o.Tenant,
+		Strategy:          o.Strategy.Name,
+		Status:            o.Status,
+		EventId:           deref(o.EventID),
+		PrimaryMarketId:   deref(o.PrimaryMarketID),
+		MarketIds:         marketIDs,
+		EdgePct:           o.EdgePct.String(),
+		EdgeUsd:           o.EdgeUSD.String(),
+		MaxSizeUsd:        o.MaxSize.String(),
+		Confidence:        o.Confidence,
+		CurrentEdgePct:    o.CurrentEdgePct().String(),
+		CurrentConfidence: o.CurrentConfidence(),
+		RiskScore:         o.RiskScore,
+		DecayType:         o.DecayType,
+		ExpiresAt:         ts(o.ExpiresAt),
+		LegsJson:          o.Legs,
+		SignalType:        o.SignalType,
+		Reasoning:         o.Reasoning,
+		DataAgeMs:         int64(o.DataAgeMs),
+		Shadow:            o.Shadow,
+		CreatedAt:         timestamppb.New(o.CreatedAt),
+		UpdatedAt:         timestamppb.New(o.UpdatedAt),
+	}
+	if o.CampaignID != nil {
+		out.CampaignId = *o.CampaignID
+	}
+	return out
+}
+
+func orderPB(o models.Order) *pb.Order {
+	return &pb.Order{
+		Id:            o.ID,
+		PlanId:        o.PlanID,
+		ClobOrderId:   o.ClobOrderID,
+		TokenId:       o.TokenID,
+		LineageId:     o.Lineage(),
+		Side:          o.Side,
+		OrderType:     o.OrderType,
+		Price:         o.Price.String(),
+		SizeUsd:       o.SizeUSD.String(),
+		FilledUsd:     o.FilledUSD.String(),
+		PricingMode:   o.PricingMode,
+		Status:        o.Status,
+		FailureReason: o.FailureReason,
+		SubmittedAt:   ts(o.SubmittedAt),
+		FilledAt:      ts(o.FilledAt),
+		CancelledAt:   ts(o.CancelledAt),
+		CreatedAt:     timestamppb.New(o.CreatedAt),
+		UpdatedAt:     timestamppb.New(o.UpdatedAt),
+	}
+}
+
+func positionPB(p models.Position) *pb.Position {
+	return &pb.Position{
+		Id:            p.ID,
+		TokenId:       p.TokenID,
+		MarketId:      p.MarketID,
+		EventId:       p.EventID,
+		Tenant:        p.Tenant,
+		Source:        p.Source,
+		Direction:     p.Direction,
+		Quantity:      p.Quantity.String(),
+		AvgEntryPrice: p.AvgEntryPrice.String(),
+		CurrentPrice:  p.CurrentPrice.String(),
+		CostBasis:     p.CostBasis.String(),
+		UnrealizedPnl: p.UnrealizedPnL.String(),
+		RealizedPnl:   p.RealizedPnL.String(),
+		Status:        p.Status,
+		StrategyName:  p.StrategyName,
+		OpenedAt:      timestamppb.New(p.OpenedAt),
+		ClosedAt:      ts(p.ClosedAt),
+		UpdatedAt:     timestamppb.New(p.UpdatedAt),
+	}
+}
+
+func planPB(p models.ExecutionPlan) *pb.ExecutionPlan {
+	return &pb.ExecutionPlan{
+		Id:             p.ID,
+		OpportunityId:  p.OpportunityID,
+		Status:         p.Status,
+		StrategyName:   p.StrategyName,
+		Tenant:         p.Tenant,
+		PlannedSizeUsd: p.PlannedSizeUSD.String(),
+		MaxLossUsd:     p.MaxLossUSD.String(),
+		LegsJson:       p.Legs,
+		CreatedAt:      timestamppb.New(p.CreatedAt),
+	}
+}
+
+func preflightPB(r risk.PreflightResult) *pb.Preflight {
+	out := &pb.Preflight{Passed: r.Passed, Checks: make([]*pb.PreflightCheck, 0, len(r.Checks))}
+	for _, chk := range r.Checks {
+		c := &pb.PreflightCheck{Name: chk.Name, Status: chk.Status, Msg: chk.Msg}
+		if chk.Value != nil {
+			c.ValueJson, _ = json.Marshal(chk.Value)
+		}
+		out.Checks = append(out.Checks, c)
+	}
+	return out
+}
+
+func submitPB(r service.SubmitResult) *pb.SubmitResult {
+	return &pb.SubmitResult{
+		PlanId:     r.PlanID,
+		OrderIds:   r.OrderIDs,
+		Mode:       r.Mode,
+		PlanStatus: r.PlanStatus,
+	}
+}
+
+func ts(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
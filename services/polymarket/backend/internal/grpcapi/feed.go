@@ -0,0 +1,125 @@
+package grpcapi
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	pb "polymarket/internal/grpcapi/polymarketv1"
+)
+
+// feedWindow is how many of the most recently updated rows a stream polls.
+// A row updated while outside the window is sent when its update brings it
+// back to the top.
+const feedWindow = 500
+
+// feed turns polling of an updated_at-ordered table into a change stream.
+type feed[T any] struct {
+	// list returns up to feedWindow matching rows, most recently updated
+	// first.
+	list func(ctx context.Context) ([]T, error)
+	// get loads a row that left the window, to tell an update that moved it
+	// out of the filter from one that only aged out. nil means the row is
+	// gone.
+	get     func(ctx context.Context, id uint64) (*T, error)
+	key     func(T) (uint64, time.Time)
+	visible func(ctx context.Context, row T) (bool, error)
+	send    func(kind pb.ChangeKind, row *T) error
+}
+
+// run sends the snapshot when asked, then every poll sends the rows whose
+// updated_at moved, oldest change first, until ctx ends.
+func (f feed[T]) run(ctx context.Context, interval time.Duration, snapshot bool) error {
+	rows, err := f.poll(ctx)
+	if err != nil {
+		return err
+	}
+	seen := make(map[uint64]time.Time, len(rows))
+	for _, row := range rows {
+		id, at := f.key(row)
+		seen[id] = at
+	}
+	if snapshot {
+		for i := range rows {
+			if err := f.send(pb.ChangeKind_CHANGE_KIND_SNAPSHOT, &rows[i]); err != nil {
+				return err
+			}
+		}
+		if err := f.send(pb.ChangeKind_CHANGE_KIND_SNAPSHOT_DONE, nil); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		rows, err := f.poll(ctx)
+		if err != nil {
+			return err
+		}
+		next := make(map[uint64]time.Time, len(rows))
+		var changed []T
+		for _, row := range rows {
+			id, at := f.key(row)
+			next[id] = at
+			if prev, ok := seen[id]; !ok || !prev.Equal(at) {
+				changed = append(changed, row)
+			}
+		}
+		for id, prev := range seen {
+			if _, ok := next[id]; ok {
+				continue
+			}
+			row, err := f.get(ctx, id)
+			if err != nil {
+				return err
+			}
+			if row == nil {
+				continue
+			}
+			if _, at := f.key(*row); !at.Equal(prev) {
+				if ok, err := f.visible(ctx, *row); err != nil {
+					return err
+				} else if ok {
+					changed = append(changed, *row)
+				}
+			}
+		}
+		sort.SliceStable(changed, func(i, j int) bool {
+			_, a := f.key(changed[i])
+			_, b := f.key(changed[j])
+			return a.Before(b)
+		})
+		for i := range changed {
+			if err := f.send(pb.ChangeKind_CHANGE_KIND_UPDATE, &changed[i]); err != nil {
+				return err
+			}
+		}
+		seen = next
+	}
+}
+
+// poll lists the window oldest-updated first, dropping rows the caller may
+// not see.
+func (f feed[T]) poll(ctx context.Context) ([]T, error) {
+	rows, err := f.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, 0, len(rows))
+	for i := len(rows) - 1; i >= 0; i-- {
+		ok, err := f.visible(ctx, rows[i])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, rows[i])
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,1810 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: polymarket/v1/execution.proto
+
+// polymarket.v1 is the binary interface for co-located execution bots. It
+// serves the same data and actions as the V2 HTTP API: opportunities, orders
+// and positions as change streams, and plan creation and order submission as
+// unary calls. Send a platform token as authorization ("Bearer ..."); it is
+// verified with the platform, and its project and role scope the call as
+// the gateway would. Unary calls need an agent or admin token.
+//
+// Money and price fields are decimal strings, as in the JSON API.
+
+package polymarketv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChangeKind int32
+
+const (
+	ChangeKind_CHANGE_KIND_UNSPECIFIED ChangeKind = 0
+	// SNAPSHOT events carry the rows that matched when the stream opened.
+	ChangeKind_CHANGE_KIND_SNAPSHOT ChangeKind = 1
+	// SNAPSHOT_DONE ends the snapshot; it carries no row.
+	ChangeKind_CHANGE_KIND_SNAPSHOT_DONE ChangeKind = 2
+	// UPDATE events carry a row created or changed since.
+	ChangeKind_CHANGE_KIND_UPDATE ChangeKind = 3
+)
+
+// Enum value maps for ChangeKind.
+var (
+	ChangeKind_name = map[int32]string{
+		0: "CHANGE_KIND_UNSPECIFIED",
+		1: "CHANGE_KIND_SNAPSHOT",
+		2: "CHANGE_KIND_SNAPSHOT_DONE",
+		3: "CHANGE_KIND_UPDATE",
+	}
+	ChangeKind_value = map[string]int32{
+		"CHANGE_KIND_UNSPECIFIED":   0,
+		"CHANGE_KIND_SNAPSHOT":      1,
+		"CHANGE_KIND_SNAPSHOT_DONE": 2,
+		"CHANGE_KIND_UPDATE":        3,
+	}
+)
+
+func (x ChangeKind) Enum() *ChangeKind {
+	p := new(ChangeKind)
+	*p = x
+	return p
+}
+
+func (x ChangeKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChangeKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_polymarket_v1_execution_proto_enumTypes[0].Descriptor()
+}
+
+func (ChangeKind) Type() protoreflect.EnumType {
+	return &file_polymarket_v1_execution_proto_enumTypes[0]
+}
+
+func (x ChangeKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChangeKind.Descriptor instead.
+func (ChangeKind) EnumDescriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{0}
+}
+
+type StreamOpportunitiesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// status defaults to "active".
+	Status        string  `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Strategy      string  `protobuf:"bytes,2,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	MinEdgePct    string  `protobuf:"bytes,3,opt,name=min_edge_pct,json=minEdgePct,proto3" json:"min_edge_pct,omitempty"`
+	MinConfidence float64 `protobuf:"fixed64,4,opt,name=min_confidence,json=minConfidence,proto3" json:"min_confidence,omitempty"`
+	// snapshot sends the matching rows before the first update.
+	Snapshot      bool `protobuf:"varint,5,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOpportunitiesRequest) Reset() {
+	*x = StreamOpportunitiesRequest{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOpportunitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOpportunitiesRequest) ProtoMessage() {}
+
+func (x *StreamOpportunitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOpportunitiesRequest.ProtoReflect.Descriptor instead.
+func (*StreamOpportunitiesRequest) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamOpportunitiesRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StreamOpportunitiesRequest) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *StreamOpportunitiesRequest) GetMinEdgePct() string {
+	if x != nil {
+		return x.MinEdgePct
+	}
+	return ""
+}
+
+func (x *StreamOpportunitiesRequest) GetMinConfidence() float64 {
+	if x != nil {
+		return x.MinConfidence
+	}
+	return 0
+}
+
+func (x *StreamOpportunitiesRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+type StreamOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlanId        uint64                 `protobuf:"varint,1,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Snapshot      bool                   `protobuf:"varint,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrdersRequest) Reset() {
+	*x = StreamOrdersRequest{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrdersRequest) ProtoMessage() {}
+
+func (x *StreamOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrdersRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StreamOrdersRequest) GetPlanId() uint64 {
+	if x != nil {
+		return x.PlanId
+	}
+	return 0
+}
+
+func (x *StreamOrdersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StreamOrdersRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+type StreamPositionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Snapshot      bool                   `protobuf:"varint,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPositionsRequest) Reset() {
+	*x = StreamPositionsRequest{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPositionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPositionsRequest) ProtoMessage() {}
+
+func (x *StreamPositionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPositionsRequest.ProtoReflect.Descriptor instead.
+func (*StreamPositionsRequest) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StreamPositionsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StreamPositionsRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+type OpportunityEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          ChangeKind             `protobuf:"varint,1,opt,name=kind,proto3,enum=polymarket.v1.ChangeKind" json:"kind,omitempty"`
+	Opportunity   *Opportunity           `protobuf:"bytes,2,opt,name=opportunity,proto3" json:"opportunity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpportunityEvent) Reset() {
+	*x = OpportunityEvent{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpportunityEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpportunityEvent) ProtoMessage() {}
+
+func (x *OpportunityEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpportunityEvent.ProtoReflect.Descriptor instead.
+func (*OpportunityEvent) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OpportunityEvent) GetKind() ChangeKind {
+	if x != nil {
+		return x.Kind
+	}
+	return ChangeKind_CHANGE_KIND_UNSPECIFIED
+}
+
+func (x *OpportunityEvent) GetOpportunity() *Opportunity {
+	if x != nil {
+		return x.Opportunity
+	}
+	return nil
+}
+
+type OrderEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          ChangeKind             `protobuf:"varint,1,opt,name=kind,proto3,enum=polymarket.v1.ChangeKind" json:"kind,omitempty"`
+	Order         *Order                 `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderEvent) Reset() {
+	*x = OrderEvent{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderEvent) ProtoMessage() {}
+
+func (x *OrderEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderEvent.ProtoReflect.Descriptor instead.
+func (*OrderEvent) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderEvent) GetKind() ChangeKind {
+	if x != nil {
+		return x.Kind
+	}
+	return ChangeKind_CHANGE_KIND_UNSPECIFIED
+}
+
+func (x *OrderEvent) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type PositionEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          ChangeKind             `protobuf:"varint,1,opt,name=kind,proto3,enum=polymarket.v1.ChangeKind" json:"kind,omitempty"`
+	Position      *Position              `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PositionEvent) Reset() {
+	*x = PositionEvent{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PositionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PositionEvent) ProtoMessage() {}
+
+func (x *PositionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PositionEvent.ProtoReflect.Descriptor instead.
+func (*PositionEvent) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PositionEvent) GetKind() ChangeKind {
+	if x != nil {
+		return x.Kind
+	}
+	return ChangeKind_CHANGE_KIND_UNSPECIFIED
+}
+
+func (x *PositionEvent) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+type Opportunity struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tenant          string                 `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Strategy        string                 `protobuf:"bytes,3,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	Status          string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	EventId         string                 `protobuf:"bytes,5,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	PrimaryMarketId string                 `protobuf:"bytes,6,opt,name=primary_market_id,json=primaryMarketId,proto3" json:"primary_market_id,omitempty"`
+	MarketIds       []string               `protobuf:"bytes,7,rep,name=market_ids,json=marketIds,proto3" json:"market_ids,omitempty"`
+	CampaignId      uint64                 `protobuf:"varint,8,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	EdgePct         string                 `protobuf:"bytes,9,opt,name=edge_pct,json=edgePct,proto3" json:"edge_pct,omitempty"`
+	EdgeUsd         string                 `protobuf:"bytes,10,opt,name=edge_usd,json=edgeUsd,proto3" json:"edge_usd,omitempty"`
+	MaxSizeUsd      string                 `protobuf:"bytes,11,opt,name=max_size_usd,json=maxSizeUsd,proto3" json:"max_size_usd,omitempty"`
+	Confidence      float64                `protobuf:"fixed64,12,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	// current_edge_pct and current_confidence are after decay.
+	CurrentEdgePct    string                 `protobuf:"bytes,13,opt,name=current_edge_pct,json=currentEdgePct,proto3" json:"current_edge_pct,omitempty"`
+	CurrentConfidence float64                `protobuf:"fixed64,14,opt,name=current_confidence,json=currentConfidence,proto3" json:"current_confidence,omitempty"`
+	RiskScore         float64                `protobuf:"fixed64,15,opt,name=risk_score,json=riskScore,proto3" json:"risk_score,omitempty"`
+	DecayType         string                 `protobuf:"bytes,16,opt,name=decay_type,json=decayType,proto3" json:"decay_type,omitempty"`
+	ExpiresAt         *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// legs_json is the legs array as in the JSON API.
+	LegsJson      []byte                 `protobuf:"bytes,18,opt,name=legs_json,json=legsJson,proto3" json:"legs_json,omitempty"`
+	SignalType    string                 `protobuf:"bytes,19,opt,name=signal_type,json=signalType,proto3" json:"signal_type,omitempty"`
+	Reasoning     string                 `protobuf:"bytes,20,opt,name=reasoning,proto3" json:"reasoning,omitempty"`
+	DataAgeMs     int64                  `protobuf:"varint,21,opt,name=data_age_ms,json=dataAgeMs,proto3" json:"data_age_ms,omitempty"`
+	Shadow        bool                   `protobuf:"varint,22,opt,name=shadow,proto3" json:"shadow,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,23,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,24,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Opportunity) Reset() {
+	*x = Opportunity{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Opportunity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Opportunity) ProtoMessage() {}
+
+func (x *Opportunity) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Opportunity.ProtoReflect.Descriptor instead.
+func (*Opportunity) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Opportunity) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Opportunity) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *Opportunity) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *Opportunity) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Opportunity) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Opportunity) GetPrimaryMarketId() string {
+	if x != nil {
+		return x.PrimaryMarketId
+	}
+	return ""
+}
+
+func (x *Opportunity) GetMarketIds() []string {
+	if x != nil {
+		return x.MarketIds
+	}
+	return nil
+}
+
+func (x *Opportunity) GetCampaignId() uint64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *Opportunity) GetEdgePct() string {
+	if x != nil {
+		return x.EdgePct
+	}
+	return ""
+}
+
+func (x *Opportunity) GetEdgeUsd() string {
+	if x != nil {
+		return x.EdgeUsd
+	}
+	return ""
+}
+
+func (x *Opportunity) GetMaxSizeUsd() string {
+	if x != nil {
+		return x.MaxSizeUsd
+	}
+	return ""
+}
+
+func (x *Opportunity) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *Opportunity) GetCurrentEdgePct() string {
+	if x != nil {
+		return x.CurrentEdgePct
+	}
+	return ""
+}
+
+func (x *Opportunity) GetCurrentConfidence() float64 {
+	if x != nil {
+		return x.CurrentConfidence
+	}
+	return 0
+}
+
+func (x *Opportunity) GetRiskScore() float64 {
+	if x != nil {
+		return x.RiskScore
+	}
+	return 0
+}
+
+func (x *Opportunity) GetDecayType() string {
+	if x != nil {
+		return x.DecayType
+	}
+	return ""
+}
+
+func (x *Opportunity) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Opportunity) GetLegsJson() []byte {
+	if x != nil {
+		return x.LegsJson
+	}
+	return nil
+}
+
+func (x *Opportunity) GetSignalType() string {
+	if x != nil {
+		return x.SignalType
+	}
+	return ""
+}
+
+func (x *Opportunity) GetReasoning() string {
+	if x != nil {
+		return x.Reasoning
+	}
+	return ""
+}
+
+func (x *Opportunity) GetDataAgeMs() int64 {
+	if x != nil {
+		return x.DataAgeMs
+	}
+	return 0
+}
+
+func (x *Opportunity) GetShadow() bool {
+	if x != nil {
+		return x.Shadow
+	}
+	return false
+}
+
+func (x *Opportunity) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Opportunity) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PlanId        uint64                 `protobuf:"varint,2,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`
+	ClobOrderId   string                 `protobuf:"bytes,3,opt,name=clob_order_id,json=clobOrderId,proto3" json:"clob_order_id,omitempty"`
+	TokenId       string                 `protobuf:"bytes,4,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	LineageId     uint64                 `protobuf:"varint,5,opt,name=lineage_id,json=lineageId,proto3" json:"lineage_id,omitempty"`
+	Side          string                 `protobuf:"bytes,6,opt,name=side,proto3" json:"side,omitempty"`
+	OrderType     string                 `protobuf:"bytes,7,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	Price         string                 `protobuf:"bytes,8,opt,name=price,proto3" json:"price,omitempty"`
+	SizeUsd       string                 `protobuf:"bytes,9,opt,name=size_usd,json=sizeUsd,proto3" json:"size_usd,omitempty"`
+	FilledUsd     string                 `protobuf:"bytes,10,opt,name=filled_usd,json=filledUsd,proto3" json:"filled_usd,omitempty"`
+	PricingMode   string                 `protobuf:"bytes,11,opt,name=pricing_mode,json=pricingMode,proto3" json:"pricing_mode,omitempty"`
+	Status        string                 `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`
+	FailureReason string                 `protobuf:"bytes,13,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	SubmittedAt   *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=submitted_at,json=submittedAt,proto3" json:"submitted_at,omitempty"`
+	FilledAt      *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=filled_at,json=filledAt,proto3" json:"filled_at,omitempty"`
+	CancelledAt   *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Order) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Order) GetPlanId() uint64 {
+	if x != nil {
+		return x.PlanId
+	}
+	return 0
+}
+
+func (x *Order) GetClobOrderId() string {
+	if x != nil {
+		return x.ClobOrderId
+	}
+	return ""
+}
+
+func (x *Order) GetTokenId() string {
+	if x != nil {
+		return x.TokenId
+	}
+	return ""
+}
+
+func (x *Order) GetLineageId() uint64 {
+	if x != nil {
+		return x.LineageId
+	}
+	return 0
+}
+
+func (x *Order) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *Order) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+func (x *Order) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *Order) GetSizeUsd() string {
+	if x != nil {
+		return x.SizeUsd
+	}
+	return ""
+}
+
+func (x *Order) GetFilledUsd() string {
+	if x != nil {
+		return x.FilledUsd
+	}
+	return ""
+}
+
+func (x *Order) GetPricingMode() string {
+	if x != nil {
+		return x.PricingMode
+	}
+	return ""
+}
+
+func (x *Order) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Order) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *Order) GetSubmittedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SubmittedAt
+	}
+	return nil
+}
+
+func (x *Order) GetFilledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FilledAt
+	}
+	return nil
+}
+
+func (x *Order) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Position struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TokenId       string                 `protobuf:"bytes,2,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	MarketId      string                 `protobuf:"bytes,3,opt,name=market_id,json=marketId,proto3" json:"market_id,omitempty"`
+	EventId       string                 `protobuf:"bytes,4,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Tenant        string                 `protobuf:"bytes,5,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Source        string                 `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	Direction     string                 `protobuf:"bytes,7,opt,name=direction,proto3" json:"direction,omitempty"`
+	Quantity      string                 `protobuf:"bytes,8,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	AvgEntryPrice string                 `protobuf:"bytes,9,opt,name=avg_entry_price,json=avgEntryPrice,proto3" json:"avg_entry_price,omitempty"`
+	CurrentPrice  string                 `protobuf:"bytes,10,opt,name=current_price,json=currentPrice,proto3" json:"current_price,omitempty"`
+	CostBasis     string                 `protobuf:"bytes,11,opt,name=cost_basis,json=costBasis,proto3" json:"cost_basis,omitempty"`
+	UnrealizedPnl string                 `protobuf:"bytes,12,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	RealizedPnl   string                 `protobuf:"bytes,13,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	Status        string                 `protobuf:"bytes,14,opt,name=status,proto3" json:"status,omitempty"`
+	StrategyName  string                 `protobuf:"bytes,15,opt,name=strategy_name,json=strategyName,proto3" json:"strategy_name,omitempty"`
+	OpenedAt      *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=opened_at,json=openedAt,proto3" json:"opened_at,omitempty"`
+	ClosedAt      *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=closed_at,json=closedAt,proto3" json:"closed_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Position) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Position) GetTokenId() string {
+	if x != nil {
+		return x.TokenId
+	}
+	return ""
+}
+
+func (x *Position) GetMarketId() string {
+	if x != nil {
+		return x.MarketId
+	}
+	return ""
+}
+
+func (x *Position) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Position) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *Position) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Position) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *Position) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *Position) GetAvgEntryPrice() string {
+	if x != nil {
+		return x.AvgEntryPrice
+	}
+	return ""
+}
+
+func (x *Position) GetCurrentPrice() string {
+	if x != nil {
+		return x.CurrentPrice
+	}
+	return ""
+}
+
+func (x *Position) GetCostBasis() string {
+	if x != nil {
+		return x.CostBasis
+	}
+	return ""
+}
+
+func (x *Position) GetUnrealizedPnl() string {
+	if x != nil {
+		return x.UnrealizedPnl
+	}
+	return ""
+}
+
+func (x *Position) GetRealizedPnl() string {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return ""
+}
+
+func (x *Position) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Position) GetStrategyName() string {
+	if x != nil {
+		return x.StrategyName
+	}
+	return ""
+}
+
+func (x *Position) GetOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return nil
+}
+
+func (x *Position) GetClosedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return nil
+}
+
+func (x *Position) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ExecutionPlan struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OpportunityId  uint64                 `protobuf:"varint,2,opt,name=opportunity_id,json=opportunityId,proto3" json:"opportunity_id,omitempty"`
+	Status         string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	StrategyName   string                 `protobuf:"bytes,4,opt,name=strategy_name,json=strategyName,proto3" json:"strategy_name,omitempty"`
+	Tenant         string                 `protobuf:"bytes,5,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	PlannedSizeUsd string                 `protobuf:"bytes,6,opt,name=planned_size_usd,json=plannedSizeUsd,proto3" json:"planned_size_usd,omitempty"`
+	MaxLossUsd     string                 `protobuf:"bytes,7,opt,name=max_loss_usd,json=maxLossUsd,proto3" json:"max_loss_usd,omitempty"`
+	LegsJson       []byte                 `protobuf:"bytes,8,opt,name=legs_json,json=legsJson,proto3" json:"legs_json,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExecutionPlan) Reset() {
+	*x = ExecutionPlan{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionPlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionPlan) ProtoMessage() {}
+
+func (x *ExecutionPlan) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionPlan.ProtoReflect.Descriptor instead.
+func (*ExecutionPlan) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExecutionPlan) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ExecutionPlan) GetOpportunityId() uint64 {
+	if x != nil {
+		return x.OpportunityId
+	}
+	return 0
+}
+
+func (x *ExecutionPlan) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExecutionPlan) GetStrategyName() string {
+	if x != nil {
+		return x.StrategyName
+	}
+	return ""
+}
+
+func (x *ExecutionPlan) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *ExecutionPlan) GetPlannedSizeUsd() string {
+	if x != nil {
+		return x.PlannedSizeUsd
+	}
+	return ""
+}
+
+func (x *ExecutionPlan) GetMaxLossUsd() string {
+	if x != nil {
+		return x.MaxLossUsd
+	}
+	return ""
+}
+
+func (x *ExecutionPlan) GetLegsJson() []byte {
+	if x != nil {
+		return x.LegsJson
+	}
+	return nil
+}
+
+func (x *ExecutionPlan) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ExecuteOpportunityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OpportunityId uint64                 `protobuf:"varint,1,opt,name=opportunity_id,json=opportunityId,proto3" json:"opportunity_id,omitempty"`
+	// size_usd overrides the size suggested by the risk manager.
+	SizeUsd string `protobuf:"bytes,2,opt,name=size_usd,json=sizeUsd,proto3" json:"size_usd,omitempty"`
+	// submit places the orders when the preflight passes.
+	Submit        bool `protobuf:"varint,3,opt,name=submit,proto3" json:"submit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteOpportunityRequest) Reset() {
+	*x = ExecuteOpportunityRequest{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteOpportunityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteOpportunityRequest) ProtoMessage() {}
+
+func (x *ExecuteOpportunityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteOpportunityRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteOpportunityRequest) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExecuteOpportunityRequest) GetOpportunityId() uint64 {
+	if x != nil {
+		return x.OpportunityId
+	}
+	return 0
+}
+
+func (x *ExecuteOpportunityRequest) GetSizeUsd() string {
+	if x != nil {
+		return x.SizeUsd
+	}
+	return ""
+}
+
+func (x *ExecuteOpportunityRequest) GetSubmit() bool {
+	if x != nil {
+		return x.Submit
+	}
+	return false
+}
+
+type ExecuteOpportunityResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Plan           *ExecutionPlan         `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	SizingWarnings []string               `protobuf:"bytes,2,rep,name=sizing_warnings,json=sizingWarnings,proto3" json:"sizing_warnings,omitempty"`
+	Preflight      *Preflight             `protobuf:"bytes,3,opt,name=preflight,proto3" json:"preflight,omitempty"`
+	// submit is set when the plan was submitted.
+	Submit        *SubmitResult `protobuf:"bytes,4,opt,name=submit,proto3" json:"submit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteOpportunityResponse) Reset() {
+	*x = ExecuteOpportunityResponse{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteOpportunityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteOpportunityResponse) ProtoMessage() {}
+
+func (x *ExecuteOpportunityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteOpportunityResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteOpportunityResponse) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExecuteOpportunityResponse) GetPlan() *ExecutionPlan {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+func (x *ExecuteOpportunityResponse) GetSizingWarnings() []string {
+	if x != nil {
+		return x.SizingWarnings
+	}
+	return nil
+}
+
+func (x *ExecuteOpportunityResponse) GetPreflight() *Preflight {
+	if x != nil {
+		return x.Preflight
+	}
+	return nil
+}
+
+func (x *ExecuteOpportunityResponse) GetSubmit() *SubmitResult {
+	if x != nil {
+		return x.Submit
+	}
+	return nil
+}
+
+type Preflight struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Passed        bool                   `protobuf:"varint,1,opt,name=passed,proto3" json:"passed,omitempty"`
+	Checks        []*PreflightCheck      `protobuf:"bytes,2,rep,name=checks,proto3" json:"checks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Preflight) Reset() {
+	*x = Preflight{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Preflight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Preflight) ProtoMessage() {}
+
+func (x *Preflight) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Preflight.ProtoReflect.Descriptor instead.
+func (*Preflight) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Preflight) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *Preflight) GetChecks() []*PreflightCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+type PreflightCheck struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// status is pass, warn or fail.
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Msg    string `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	// value_json is the check's value as in the JSON API.
+	ValueJson     []byte `protobuf:"bytes,4,opt,name=value_json,json=valueJson,proto3" json:"value_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreflightCheck) Reset() {
+	*x = PreflightCheck{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreflightCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreflightCheck) ProtoMessage() {}
+
+func (x *PreflightCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreflightCheck.ProtoReflect.Descriptor instead.
+func (*PreflightCheck) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PreflightCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PreflightCheck) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PreflightCheck) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *PreflightCheck) GetValueJson() []byte {
+	if x != nil {
+		return x.ValueJson
+	}
+	return nil
+}
+
+type SubmitPlanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlanId        uint64                 `protobuf:"varint,1,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitPlanRequest) Reset() {
+	*x = SubmitPlanRequest{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitPlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitPlanRequest) ProtoMessage() {}
+
+func (x *SubmitPlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitPlanRequest.ProtoReflect.Descriptor instead.
+func (*SubmitPlanRequest) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SubmitPlanRequest) GetPlanId() uint64 {
+	if x != nil {
+		return x.PlanId
+	}
+	return 0
+}
+
+type SubmitResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlanId        uint64                 `protobuf:"varint,1,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`
+	OrderIds      []uint64               `protobuf:"varint,2,rep,packed,name=order_ids,json=orderIds,proto3" json:"order_ids,omitempty"`
+	Mode          string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	PlanStatus    string                 `protobuf:"bytes,4,opt,name=plan_status,json=planStatus,proto3" json:"plan_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitResult) Reset() {
+	*x = SubmitResult{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitResult) ProtoMessage() {}
+
+func (x *SubmitResult) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitResult.ProtoReflect.Descriptor instead.
+func (*SubmitResult) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SubmitResult) GetPlanId() uint64 {
+	if x != nil {
+		return x.PlanId
+	}
+	return 0
+}
+
+func (x *SubmitResult) GetOrderIds() []uint64 {
+	if x != nil {
+		return x.OrderIds
+	}
+	return nil
+}
+
+func (x *SubmitResult) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *SubmitResult) GetPlanStatus() string {
+	if x != nil {
+		return x.PlanStatus
+	}
+	return ""
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       uint64                 `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_polymarket_v1_execution_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_polymarket_v1_execution_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_polymarket_v1_execution_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CancelOrderRequest) GetOrderId() uint64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+var File_polymarket_v1_execution_proto protoreflect.FileDescriptor
+
+const file_polymarket_v1_execution_proto_rawDesc = "" +
+	"\n" +
+	"\x1dpolymarket/v1/execution.proto\x12\rpolymarket.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb5\x01\n" +
+	"\x1aStreamOpportunitiesRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1a\n" +
+	"\bstrategy\x18\x02 \x01(\tR\bstrategy\x12 \n" +
+	"\fmin_edge_pct\x18\x03 \x01(\tR\n" +
+	"minEdgePct\x12%\n" +
+	"\x0emin_confidence\x18\x04 \x01(\x01R\rminConfidence\x12\x1a\n" +
+	"\bsnapshot\x18\x05 \x01(\bR\bsnapshot\"b\n" +
+	"\x13StreamOrdersRequest\x12\x17\n" +
+	"\aplan_id\x18\x01 \x01(\x04R\x06planId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1a\n" +
+	"\bsnapshot\x18\x03 \x01(\bR\bsnapshot\"L\n" +
+	"\x16StreamPositionsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1a\n" +
+	"\bsnapshot\x18\x02 \x01(\bR\bsnapshot\"\x7f\n" +
+	"\x10OpportunityEvent\x12-\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x19.polymarket.v1.ChangeKindR\x04kind\x12<\n" +
+	"\vopportunity\x18\x02 \x01(\v2\x1a.polymarket.v1.OpportunityR\vopportunity\"g\n" +
+	"\n" +
+	"OrderEvent\x12-\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x19.polymarket.v1.ChangeKindR\x04kind\x12*\n" +
+	"\x05order\x18\x02 \x01(\v2\x14.polymarket.v1.OrderR\x05order\"s\n" +
+	"\rPositionEvent\x12-\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x19.polymarket.v1.ChangeKindR\x04kind\x123\n" +
+	"\bposition\x18\x02 \x01(\v2\x17.polymarket.v1.PositionR\bposition\"\xc4\x06\n" +
+	"\vOpportunity\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\x12\x1a\n" +
+	"\bstrategy\x18\x03 \x01(\tR\bstrategy\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x19\n" +
+	"\bevent_id\x18\x05 \x01(\tR\aeventId\x12*\n" +
+	"\x11primary_market_id\x18\x06 \x01(\tR\x0fprimaryMarketId\x12\x1d\n" +
+	"\n" +
+	"market_ids\x18\a \x03(\tR\tmarketIds\x12\x1f\n" +
+	"\vcampaign_id\x18\b \x01(\x04R\n" +
+	"campaignId\x12\x19\n" +
+	"\bedge_pct\x18\t \x01(\tR\aedgePct\x12\x19\n" +
+	"\bedge_usd\x18\n" +
+	" \x01(\tR\aedgeUsd\x12 \n" +
+	"\fmax_size_usd\x18\v \x01(\tR\n" +
+	"maxSizeUsd\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\f \x01(\x01R\n" +
+	"confidence\x12(\n" +
+	"\x10current_edge_pct\x18\r \x01(\tR\x0ecurrentEdgePct\x12-\n" +
+	"\x12current_confidence\x18\x0e \x01(\x01R\x11currentConfidence\x12\x1d\n" +
+	"\n" +
+	"risk_score\x18\x0f \x01(\x01R\triskScore\x12\x1d\n" +
+	"\n" +
+	"decay_type\x18\x10 \x01(\tR\tdecayType\x129\n" +
+	"\n" +
+	"expires_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1b\n" +
+	"\tlegs_json\x18\x12 \x01(\fR\blegsJson\x12\x1f\n" +
+	"\vsignal_type\x18\x13 \x01(\tR\n" +
+	"signalType\x12\x1c\n" +
+	"\treasoning\x18\x14 \x01(\tR\treasoning\x12\x1e\n" +
+	"\vdata_age_ms\x18\x15 \x01(\x03R\tdataAgeMs\x12\x16\n" +
+	"\x06shadow\x18\x16 \x01(\bR\x06shadow\x129\n" +
+	"\n" +
+	"created_at\x18\x17 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x18 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xa0\x05\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x17\n" +
+	"\aplan_id\x18\x02 \x01(\x04R\x06planId\x12\"\n" +
+	"\rclob_order_id\x18\x03 \x01(\tR\vclobOrderId\x12\x19\n" +
+	"\btoken_id\x18\x04 \x01(\tR\atokenId\x12\x1d\n" +
+	"\n" +
+	"lineage_id\x18\x05 \x01(\x04R\tlineageId\x12\x12\n" +
+	"\x04side\x18\x06 \x01(\tR\x04side\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\a \x01(\tR\torderType\x12\x14\n" +
+	"\x05price\x18\b \x01(\tR\x05price\x12\x19\n" +
+	"\bsize_usd\x18\t \x01(\tR\asizeUsd\x12\x1d\n" +
+	"\n" +
+	"filled_usd\x18\n" +
+	" \x01(\tR\tfilledUsd\x12!\n" +
+	"\fpricing_mode\x18\v \x01(\tR\vpricingMode\x12\x16\n" +
+	"\x06status\x18\f \x01(\tR\x06status\x12%\n" +
+	"\x0efailure_reason\x18\r \x01(\tR\rfailureReason\x12=\n" +
+	"\fsubmitted_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\vsubmittedAt\x127\n" +
+	"\tfilled_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\bfilledAt\x12=\n" +
+	"\fcancelled_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\x129\n" +
+	"\n" +
+	"created_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xf7\x04\n" +
+	"\bPosition\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x19\n" +
+	"\btoken_id\x18\x02 \x01(\tR\atokenId\x12\x1b\n" +
+	"\tmarket_id\x18\x03 \x01(\tR\bmarketId\x12\x19\n" +
+	"\bevent_id\x18\x04 \x01(\tR\aeventId\x12\x16\n" +
+	"\x06tenant\x18\x05 \x01(\tR\x06tenant\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06source\x12\x1c\n" +
+	"\tdirection\x18\a \x01(\tR\tdirection\x12\x1a\n" +
+	"\bquantity\x18\b \x01(\tR\bquantity\x12&\n" +
+	"\x0favg_entry_price\x18\t \x01(\tR\ravgEntryPrice\x12#\n" +
+	"\rcurrent_price\x18\n" +
+	" \x01(\tR\fcurrentPrice\x12\x1d\n" +
+	"\n" +
+	"cost_basis\x18\v \x01(\tR\tcostBasis\x12%\n" +
+	"\x0eunrealized_pnl\x18\f \x01(\tR\runrealizedPnl\x12!\n" +
+	"\frealized_pnl\x18\r \x01(\tR\vrealizedPnl\x12\x16\n" +
+	"\x06status\x18\x0e \x01(\tR\x06status\x12#\n" +
+	"\rstrategy_name\x18\x0f \x01(\tR\fstrategyName\x127\n" +
+	"\topened_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\bopenedAt\x127\n" +
+	"\tclosed_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\bclosedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xbf\x02\n" +
+	"\rExecutionPlan\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12%\n" +
+	"\x0eopportunity_id\x18\x02 \x01(\x04R\ropportunityId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12#\n" +
+	"\rstrategy_name\x18\x04 \x01(\tR\fstrategyName\x12\x16\n" +
+	"\x06tenant\x18\x05 \x01(\tR\x06tenant\x12(\n" +
+	"\x10planned_size_usd\x18\x06 \x01(\tR\x0eplannedSizeUsd\x12 \n" +
+	"\fmax_loss_usd\x18\a \x01(\tR\n" +
+	"maxLossUsd\x12\x1b\n" +
+	"\tlegs_json\x18\b \x01(\fR\blegsJson\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"u\n" +
+	"\x19ExecuteOpportunityRequest\x12%\n" +
+	"\x0eopportunity_id\x18\x01 \x01(\x04R\ropportunityId\x12\x19\n" +
+	"\bsize_usd\x18\x02 \x01(\tR\asizeUsd\x12\x16\n" +
+	"\x06submit\x18\x03 \x01(\bR\x06submit\"\xe4\x01\n" +
+	"\x1aExecuteOpportunityResponse\x120\n" +
+	"\x04plan\x18\x01 \x01(\v2\x1c.polymarket.v1.ExecutionPlanR\x04plan\x12'\n" +
+	"\x0fsizing_warnings\x18\x02 \x03(\tR\x0esizingWarnings\x126\n" +
+	"\tpreflight\x18\x03 \x01(\v2\x18.polymarket.v1.PreflightR\tpreflight\x123\n" +
+	"\x06submit\x18\x04 \x01(\v2\x1b.polymarket.v1.SubmitResultR\x06submit\"Z\n" +
+	"\tPreflight\x12\x16\n" +
+	"\x06passed\x18\x01 \x01(\bR\x06passed\x125\n" +
+	"\x06checks\x18\x02 \x03(\v2\x1d.polymarket.v1.PreflightCheckR\x06checks\"m\n" +
+	"\x0ePreflightCheck\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x10\n" +
+	"\x03msg\x18\x03 \x01(\tR\x03msg\x12\x1d\n" +
+	"\n" +
+	"value_json\x18\x04 \x01(\fR\tvalueJson\",\n" +
+	"\x11SubmitPlanRequest\x12\x17\n" +
+	"\aplan_id\x18\x01 \x01(\x04R\x06planId\"y\n" +
+	"\fSubmitResult\x12\x17\n" +
+	"\aplan_id\x18\x01 \x01(\x04R\x06planId\x12\x1b\n" +
+	"\torder_ids\x18\x02 \x03(\x04R\borderIds\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x1f\n" +
+	"\vplan_status\x18\x04 \x01(\tR\n" +
+	"planStatus\"/\n" +
+	"\x12CancelOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x04R\aorderId*z\n" +
+	"\n" +
+	"ChangeKind\x12\x1b\n" +
+	"\x17CHANGE_KIND_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14CHANGE_KIND_SNAPSHOT\x10\x01\x12\x1d\n" +
+	"\x19CHANGE_KIND_SNAPSHOT_DONE\x10\x02\x12\x16\n" +
+	"\x12CHANGE_KIND_UPDATE\x10\x032\xa2\x04\n" +
+	"\x10ExecutionService\x12c\n" +
+	"\x13StreamOpportunities\x12).polymarket.v1.StreamOpportunitiesRequest\x1a\x1f.polymarket.v1.OpportunityEvent0\x01\x12O\n" +
+	"\fStreamOrders\x12\".polymarket.v1.StreamOrdersRequest\x1a\x19.polymarket.v1.OrderEvent0\x01\x12X\n" +
+	"\x0fStreamPositions\x12%.polymarket.v1.StreamPositionsRequest\x1a\x1c.polymarket.v1.PositionEvent0\x01\x12i\n" +
+	"\x12ExecuteOpportunity\x12(.polymarket.v1.ExecuteOpportunityRequest\x1a).polymarket.v1.ExecuteOpportunityResponse\x12K\n" +
+	"\n" +
+	"SubmitPlan\x12 .polymarket.v1.SubmitPlanRequest\x1a\x1b.polymarket.v1.SubmitResult\x12F\n" +
+	"\vCancelOrder\x12!.polymarket.v1.CancelOrderRequest\x1a\x14.polymarket.v1.OrderB7Z5polymarket/internal/grpcapi/polymarketv1;polymarketv1b\x06proto3"
+
+var (
+	file_polymarket_v1_execution_proto_rawDescOnce sync.Once
+	file_polymarket_v1_execution_proto_rawDescData []byte
+)
+
+func file_polymarket_v1_execution_proto_rawDescGZIP() []byte {
+	file_polymarket_v1_execution_proto_rawDescOnce.Do(func() {
+		file_polymarket_v1_execution_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_polymarket_v1_execution_proto_rawDesc), len(file_polymarket_v1_execution_proto_rawDesc)))
+	})
+	return file_polymarket_v1_execution_proto_rawDescData
+}
+
+var file_polymarket_v1_execution_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_polymarket_v1_execution_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_polymarket_v1_execution_proto_goTypes = []any{
+	(ChangeKind)(0),                    // 0: polymarket.v1.ChangeKind
+	(*StreamOpportunitiesRequest)(nil), // 1: polymarket.v1.StreamOpportunitiesRequest
+	(*StreamOrdersRequest)(nil),        // 2: polymarket.v1.StreamOrdersRequest
+	(*StreamPositionsRequest)(nil),     // 3: polymarket.v1.StreamPositionsRequest
+	(*OpportunityEvent)(nil),           // 4: polymarket.v1.OpportunityEvent
+	(*OrderEvent)(nil),                 // 5: polymarket.v1.OrderEvent
+	(*PositionEvent)(nil),              // 6: polymarket.v1.PositionEvent
+	(*Opportunity)(nil),                // 7: polymarket.v1.Opportunity
+	(*Order)(nil),                      // 8: polymarket.v1.Order
+	(*Position)(nil),                   // 9: polymarket.v1.Position
+	(*ExecutionPlan)(nil),              // 10: polymarket.v1.ExecutionPlan
+	(*ExecuteOpportunityRequest)(nil),  // 11: polymarket.v1.ExecuteOpportunityRequest
+	(*ExecuteOpportunityResponse)(nil), // 12: polymarket.v1.ExecuteOpportunityResponse
+	(*Preflight)(nil),                  // 13: polymarket.v1.Preflight
+	(*PreflightCheck)(nil),             // 14: polymarket.v1.PreflightCheck
+	(*SubmitPlanRequest)(nil),          // 15: polymarket.v1.SubmitPlanRequest
+	(*SubmitResult)(nil),               // 16: polymarket.v1.SubmitResult
+	(*CancelOrderRequest)(nil),         // 17: polymarket.v1.CancelOrderRequest
+	(*timestamppb.Timestamp)(nil),      // 18: google.protobuf.Timestamp
+}
+var file_polymarket_v1_execution_proto_depIdxs = []int32{
+	0,  // 0: polymarket.v1.OpportunityEvent.kind:type_name -> polymarket.v1.ChangeKind
+	7,  // 1: polymarket.v1.OpportunityEvent.opportunity:type_name -> polymarket.v1.Opportunity
+	0,  // 2: polymarket.v1.OrderEvent.kind:type_name -> polymarket.v1.ChangeKind
+	8,  // 3: polymarket.v1.OrderEvent.order:type_name -> polymarket.v1.Order
+	0,  // 4: polymarket.v1.PositionEvent.kind:type_name -> polymarket.v1.ChangeKind
+	9,  // 5: polymarket.v1.PositionEvent.position:type_name -> polymarket.v1.Position
+	18, // 6: polymarket.v1.Opportunity.expires_at:type_name -> google.protobuf.Timestamp
+	18, // 7: polymarket.v1.Opportunity.created_at:type_name -> google.protobuf.Timestamp
+	18, // 8: polymarket.v1.Opportunity.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 9: polymarket.v1.Order.submitted_at:type_name -> google.protobuf.Timestamp
+	18, // 10: polymarket.v1.Order.filled_at:type_name -> google.protobuf.Timestamp
+	18, // 11: polymarket.v1.Order.cancelled_at:type_name -> google.protobuf.Timestamp
+	18, // 12: polymarket.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	18, // 13: polymarket.v1.Order.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 14: polymarket.v1.Position.opened_at:type_name -> google.protobuf.Timestamp
+	18, // 15: polymarket.v1.Position.closed_at:type_name -> google.protobuf.Timestamp
+	18, // 16: polymarket.v1.Position.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 17: polymarket.v1.ExecutionPlan.created_at:type_name -> google.protobuf.Timestamp
+	10, // 18: polymarket.v1.ExecuteOpportunityResponse.plan:type_name -> polymarket.v1.ExecutionPlan
+	13, // 19: polymarket.v1.ExecuteOpportunityResponse.preflight:type_name -> polymarket.v1.Preflight
+	16, // 20: polymarket.v1.ExecuteOpportunityResponse.submit:type_name -> polymarket.v1.SubmitResult
+	14, // 21: polymarket.v1.Preflight.checks:type_name -> polymarket.v1.PreflightCheck
+	1,  // 22: polymarket.v1.ExecutionService.StreamOpportunities:input_type -> polymarket.v1.StreamOpportunitiesRequest
+	2,  // 23: polymarket.v1.ExecutionService.StreamOrders:input_type -> polymarket.v1.StreamOrdersRequest
+	3,  // 24: polymarket.v1.ExecutionService.StreamPositions:input_type -> polymarket.v1.StreamPositionsRequest
+	11, // 25: polymarket.v1.ExecutionService.ExecuteOpportunity:input_type -> polymarket.v1.ExecuteOpportunityRequest
+	15, // 26: polymarket.v1.ExecutionService.SubmitPlan:input_type -> polymarket.v1.SubmitPlanRequest
+	17, // 27: polymarket.v1.ExecutionService.CancelOrder:input_type -> polymarket.v1.CancelOrderRequest
+	4,  // 28: polymarket.v1.ExecutionService.StreamOpportunities:output_type -> polymarket.v1.OpportunityEvent
+	5,  // 29: polymarket.v1.ExecutionService.StreamOrders:output_type -> polymarket.v1.OrderEvent
+	6,  // 30: polymarket.v1.ExecutionService.StreamPositions:output_type -> polymarket.v1.PositionEvent
+	12, // 31: polymarket.v1.ExecutionService.ExecuteOpportunity:output_type -> polymarket.v1.ExecuteOpportunityResponse
+	16, // 32: polymarket.v1.ExecutionService.SubmitPlan:output_type -> polymarket.v1.SubmitResult
+	8,  // 33: polymarket.v1.ExecutionService.CancelOrder:output_type -> polymarket.v1.Order
+	28, // [28:34] is the sub-list for method output_type
+	22, // [22:28] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_polymarket_v1_execution_proto_init() }
+func file_polymarket_v1_execution_proto_init() {
+	if File_polymarket_v1_execution_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_polymarket_v1_execution_proto_rawDesc), len(file_polymarket_v1_execution_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_polymarket_v1_execution_proto_goTypes,
+		DependencyIndexes: file_polymarket_v1_execution_proto_depIdxs,
+		EnumInfos:         file_polymarket_v1_execution_proto_enumTypes,
+		MessageInfos:      file_polymarket_v1_execution_proto_msgTypes,
+	}.Build()
+	File_polymarket_v1_execution_proto = out.File
+	file_polymarket_v1_execution_proto_goTypes = nil
+	file_polymarket_v1_execution_proto_depIdxs = nil
+}
@@ -0,0 +1,344 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: polymarket/v1/execution.proto
+
+// polymarket.v1 is the binary interface for co-located execution bots. It
+// serves the same data and actions as the V2 HTTP API: opportunities, orders
+// and positions as change streams, and plan creation and order submission as
+// unary calls. Send a platform token as authorization ("Bearer ..."); it is
+// verified with the platform, and its project and role scope the call as
+// the gateway would. Unary calls need an agent or admin token.
+//
+// Money and price fields are decimal strings, as in the JSON API.
+
+package polymarketv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ExecutionService_StreamOpportunities_FullMethodName = "/polymarket.v1.ExecutionService/StreamOpportunities"
+	ExecutionService_StreamOrders_FullMethodName        = "/polymarket.v1.ExecutionService/StreamOrders"
+	ExecutionService_StreamPositions_FullMethodName     = "/polymarket.v1.ExecutionService/StreamPositions"
+	ExecutionService_ExecuteOpportunity_FullMethodName  = "/polymarket.v1.ExecutionService/ExecuteOpportunity"
+	ExecutionService_SubmitPlan_FullMethodName          = "/polymarket.v1.ExecutionService/SubmitPlan"
+	ExecutionService_CancelOrder_FullMethodName         = "/polymarket.v1.ExecutionService/CancelOrder"
+)
+
+// ExecutionServiceClient is the client API for ExecutionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExecutionServiceClient interface {
+	// StreamOpportunities sends opportunities as they are created or change.
+	StreamOpportunities(ctx context.Context, in *StreamOpportunitiesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OpportunityEvent], error)
+	// StreamOrders sends orders as they are placed, fill or are cancelled.
+	StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderEvent], error)
+	// StreamPositions sends positions as they open, reprice or close.
+	StreamPositions(ctx context.Context, in *StreamPositionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PositionEvent], error)
+	// ExecuteOpportunity creates a plan from an active opportunity, runs the
+	// risk preflight and, when asked and the preflight passed, submits it.
+	ExecuteOpportunity(ctx context.Context, in *ExecuteOpportunityRequest, opts ...grpc.CallOption) (*ExecuteOpportunityResponse, error)
+	// SubmitPlan places the orders of a plan that passed preflight.
+	SubmitPlan(ctx context.Context, in *SubmitPlanRequest, opts ...grpc.CallOption) (*SubmitResult, error)
+	// CancelOrder cancels a working order.
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*Order, error)
+}
+
+type executionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExecutionServiceClient(cc grpc.ClientConnInterface) ExecutionServiceClient {
+	return &executionServiceClient{cc}
+}
+
+func (c *executionServiceClient) StreamOpportunities(ctx context.Context, in *StreamOpportunitiesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OpportunityEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ExecutionService_ServiceDesc.Streams[0], ExecutionService_StreamOpportunities_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamOpportunitiesRequest, OpportunityEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExecutionService_StreamOpportunitiesClient = grpc.ServerStreamingClient[OpportunityEvent]
+
+func (c *executionServiceClient) StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ExecutionService_ServiceDesc.Streams[1], ExecutionService_StreamOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamOrdersRequest, OrderEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExecutionService_StreamOrdersClient = grpc.ServerStreamingClient[OrderEvent]
+
+func (c *executionServiceClient) StreamPositions(ctx context.Context, in *StreamPositionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PositionEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ExecutionService_ServiceDesc.Streams[2], ExecutionService_StreamPositions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamPositionsRequest, PositionEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExecutionService_StreamPositionsClient = grpc.ServerStreamingClient[PositionEvent]
+
+func (c *executionServiceClient) ExecuteOpportunity(ctx context.Context, in *ExecuteOpportunityRequest, opts ...grpc.CallOption) (*ExecuteOpportunityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteOpportunityResponse)
+	err := c.cc.Invoke(ctx, ExecutionService_ExecuteOpportunity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executionServiceClient) SubmitPlan(ctx context.Context, in *SubmitPlanRequest, opts ...grpc.CallOption) (*SubmitResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitResult)
+	err := c.cc.Invoke(ctx, ExecutionService_SubmitPlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executionServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, ExecutionService_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExecutionServiceServer is the server API for ExecutionService service.
+// All implementations must embed UnimplementedExecutionServiceServer
+// for forward compatibility.
+type ExecutionServiceServer interface {
+	// StreamOpportunities sends opportunities as they are created or change.
+	StreamOpportunities(*StreamOpportunitiesRequest, grpc.ServerStreamingServer[OpportunityEvent]) error
+	// StreamOrders sends orders as they are placed, fill or are cancelled.
+	StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[OrderEvent]) error
+	// StreamPositions sends positions as they open, reprice or close.
+	StreamPositions(*StreamPositionsRequest, grpc.ServerStreamingServer[PositionEvent]) error
+	// ExecuteOpportunity creates a plan from an active opportunity, runs the
+	// risk preflight and, when asked and the preflight passed, submits it.
+	ExecuteOpportunity(context.Context, *ExecuteOpportunityRequest) (*ExecuteOpportunityResponse, error)
+	// SubmitPlan places the orders of a plan that passed preflight.
+	SubmitPlan(context.Context, *SubmitPlanRequest) (*SubmitResult, error)
+	// CancelOrder cancels a working order.
+	CancelOrder(context.Context, *CancelOrderRequest) (*Order, error)
+	mustEmbedUnimplementedExecutionServiceServer()
+}
+
+// UnimplementedExecutionServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedExecutionServiceServer struct{}
+
+func (UnimplementedExecutionServiceServer) StreamOpportunities(*StreamOpportunitiesRequest, grpc.ServerStreamingServer[OpportunityEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOpportunities not implemented")
+}
+func (UnimplementedExecutionServiceServer) StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[OrderEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrders not implemented")
+}
+func (UnimplementedExecutionServiceServer) StreamPositions(*StreamPositionsRequest, grpc.ServerStreamingServer[PositionEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPositions not implemented")
+}
+func (UnimplementedExecutionServiceServer) ExecuteOpportunity(context.Context, *ExecuteOpportunityRequest) (*ExecuteOpportunityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteOpportunity not implemented")
+}
+func (UnimplementedExecutionServiceServer) SubmitPlan(context.Context, *SubmitPlanRequest) (*SubmitResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitPlan not implemented")
+}
+func (UnimplementedExecutionServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedExecutionServiceServer) mustEmbedUnimplementedExecutionServiceServer() {}
+func (UnimplementedExecutionServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeExecutionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExecutionServiceServer will
+// result in compilation errors.
+type UnsafeExecutionServiceServer interface {
+	mustEmbedUnimplementedExecutionServiceServer()
+}
+
+func RegisterExecutionServiceServer(s grpc.ServiceRegistrar, srv ExecutionServiceServer) {
+	// If the following call pancis, it indicates UnimplementedExecutionServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ExecutionService_ServiceDesc, srv)
+}
+
+func _ExecutionService_StreamOpportunities_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOpportunitiesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecutionServiceServer).StreamOpportunities(m, &grpc.GenericServerStream[StreamOpportunitiesRequest, OpportunityEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExecutionService_StreamOpportunitiesServer = grpc.ServerStreamingServer[OpportunityEvent]
+
+func _ExecutionService_StreamOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecutionServiceServer).StreamOrders(m, &grpc.GenericServerStream[StreamOrdersRequest, OrderEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExecutionService_StreamOrdersServer = grpc.ServerStreamingServer[OrderEvent]
+
+func _ExecutionService_StreamPositions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPositionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecutionServiceServer).StreamPositions(m, &grpc.GenericServerStream[StreamPositionsRequest, PositionEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExecutionService_StreamPositionsServer = grpc.ServerStreamingServer[PositionEvent]
+
+func _ExecutionService_ExecuteOpportunity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteOpportunityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutionServiceServer).ExecuteOpportunity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExecutionService_ExecuteOpportunity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutionServiceServer).ExecuteOpportunity(ctx, req.(*ExecuteOpportunityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExecutionService_SubmitPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutionServiceServer).SubmitPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExecutionService_SubmitPlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutionServiceServer).SubmitPlan(ctx, req.(*SubmitPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExecutionService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutionServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExecutionService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutionServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExecutionService_ServiceDesc is the grpc.ServiceDesc for ExecutionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExecutionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "polymarket.v1.ExecutionService",
+	HandlerType: (*ExecutionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExecuteOpportunity",
+			Handler:    _ExecutionService_ExecuteOpportunity_Handler,
+		},
+		{
+			MethodName: "SubmitPlan",
+			Handler:    _ExecutionService_SubmitPlan_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _ExecutionService_CancelOrder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamOpportunities",
+			Handler:       _ExecutionService_StreamOpportunities_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamOrders",
+			Handler:       _ExecutionService_StreamOrders_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamPositions",
+			Handler:       _ExecutionService_StreamPositions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "polymarket/v1/execution.proto",
+}
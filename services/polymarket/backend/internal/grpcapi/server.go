@@ -0,0 +1,417 @@
+// Package grpcapi serves the polymarket.v1 gRPC interface for co-located
+// execution bots. It shares the repository, risk manager and executor with
+// the V2 HTTP API and its auth with the paas middleware.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "polymarket/internal/grpcapi/polymarketv1"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+	"polymarket/internal/risk"
+	"polymarket/internal/service"
+)
+
+type Server struct {
+	pb.UnimplementedExecutionServiceServer
+
+	Repo      repository.Repository
+	Risk      *risk.Manager
+	Campaigns *service.CampaignService
+	Executor  *service.CLOBExecutor
+	Costs     *service.CostForecaster
+	Outbox    *service.OutboxDispatcher
+	Logger    *zap.Logger
+	// PollInterval is how often streams look for changed rows.
+	PollInterval time.Duration
+
+	stopOnce sync.Once
+	stopped  chan struct{}
+	initOnce sync.Once
+}
+
+func (s *Server) Register(g *grpc.Server) {
+	pb.RegisterExecutionServiceServer(g, s)
+}
+
+// Stop ends the open streams so a graceful stop of the grpc.Server does not
+// wait on them; clients see Unavailable and reconnect elsewhere.
+func (s *Server) Stop() {
+	s.stopOnce.Do(func() { close(s.done()) })
+}
+
+func (s *Server) done() chan struct{} {
+	s.initOnce.Do(func() { s.stopped = make(chan struct{}) })
+	return s.stopped
+}
+
+// streamContext is the stream's context, also ended by Stop.
+func (s *Server) streamContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-s.done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+func (s *Server) interval() time.Duration {
+	if s.PollInterval > 0 {
+		return s.PollInterval
+	}
+	return 250 * time.Millisecond
+}
+
+func (s *Server) StreamOpportunities(req *pb.StreamOpportunitiesRequest, stream pb.ExecutionService_StreamOpportunitiesServer) error {
+	if s.Repo == nil {
+		return status.Error(codes.Internal, "repo unavailable")
+	}
+	ctx, cancel := s.streamContext(stream.Context())
+	defer cancel()
+	params := repository.ListOpportunitiesParams{
+		Limit:   feedWindow,
+		Tenant:  tenantScope(ctx),
+		OrderBy: "updated_at",
+	}
+	st := strings.TrimSpace(req.GetStatus())
+	if st == "" {
+		st = "active"
+	}
+	params.Status = &st
+	if v := strings.TrimSpace(req.GetStrategy()); v != "" {
+		params.StrategyName = &v
+	}
+	if v := strings.TrimSpace(req.GetMinEdgePct()); v != "" {
+		minEdge, err := decimal.NewFromString(v)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "invalid min_edge_pct")
+		}
+		// Allow both "0.05" and "5" to mean 5%.
+		if minEdge.GreaterThan(decimal.NewFromInt(1)) {
+			minEdge = minEdge.Div(decimal.NewFromInt(100))
+		}
+		params.MinEdgePct = &minEdge
+	}
+	if v := req.GetMinConfidence(); v != 0 {
+		if v < 0 || v > 1 {
+			return status.Error(codes.InvalidArgument, "min_confidence must be between 0 and 1")
+		}
+		params.MinConfidence = &v
+	}
+	f := feed[models.Opportunity]{
+		list: func(ctx context.Context) ([]models.Opportunity, error) {
+			return s.Repo.ListOpportunities(ctx, params)
+		},
+		get: s.Repo.GetOpportunityByID,
+		key: func(o models.Opportunity) (uint64, time.Time) { return o.ID, o.UpdatedAt },
+		visible: func(ctx context.Context, o models.Opportunity) (bool, error) {
+			return tenantVisible(ctx, o.Tenant), nil
+		},
+		send: func(kind pb.ChangeKind, o *models.Opportunity) error {
+			ev := &pb.OpportunityEvent{Kind: kind}
+			if o != nil {
+				ev.Opportunity = opportunityPB(*o)
+			}
+			return stream.Send(ev)
+		},
+	}
+	return s.streamError(f.run(ctx, s.interval(), req.GetSnapshot()))
+}
+
+// StreamOrders sends orders of the caller's desk; orders carry no tenant,
+// so it is taken from their plan.
+func (s *Server) StreamOrders(req *pb.StreamOrdersRequest, stream pb.ExecutionService_StreamOrdersServer) error {
+	if s.Repo == nil {
+		return status.Error(codes.Internal, "repo unavailable")
+	}
+	ctx, cancel := s.streamContext(stream.Context())
+	defer cancel()
+	params := repository.ListOrdersParams{Limit: feedWindow, OrderBy: "updated_at"}
+	if v := req.GetPlanId(); v > 0 {
+		params.PlanID = &v
+	}
+	if v := strings.TrimSpace(req.GetStatus()); v != "" {
+		params.Status = &v
+	}
+	tenants := &planTenants{repo: s.Repo, byPlan: map[uint64]string{}}
+	f := feed[models.Order]{
+		list: func(ctx context.Context) ([]models.Order, error) {
+			return s.Repo.ListOrders(ctx, params)
+		},
+		get: s.Repo.GetOrderByID,
+		key: func(o models.Order) (uint64, time.Time) { return o.ID, o.UpdatedAt },
+		visible: func(ctx context.Context, o models.Order) (bool, error) {
+			if paas.TenantFromContext(ctx) == "" {
+				return true, nil
+			}
+			tenant, err := tenants.of(ctx, o.PlanID)
+			if err != nil {
+				return false, err
+			}
+			return tenantVisible(ctx, tenant), nil
+		},
+		send: func(kind pb.ChangeKind, o *models.Order) error {
+			ev := &pb.OrderEvent{Kind: kind}
+			if o != nil {
+				ev.Order = orderPB(*o)
+			}
+			return stream.Send(ev)
+		},
+	}
+	return s.streamError(f.run(ctx, s.interval(), req.GetSnapshot()))
+}
+
+func (s *Server) StreamPositions(req *pb.StreamPositionsRequest, stream pb.ExecutionService_StreamPositionsServer) error {
+	if s.Repo == nil {
+		return status.Error(codes.Internal, "repo unavailable")
+	}
+	ctx, cancel := s.streamContext(stream.Context())
+	defer cancel()
+	params := repository.ListPositionsParams{
+		Limit:   feedWindow,
+		Tenant:  tenantScope(ctx),
+		OrderBy: "updated_at",
+	}
+	if v := strings.TrimSpace(req.GetStatus()); v != "" {
+		if v != "open" && v != "closed" {
+			return status.Error(codes.InvalidArgument, "status must be open or closed")
+		}
+		params.Status = &v
+	}
+	f := feed[models.Position]{
+		list: func(ctx context.Context) ([]models.Position, error) {
+			return s.Repo.ListPositions(ctx, params)
+		},
+		get: s.Repo.GetPositionByID,
+		key: func(p models.Position) (uint64, time.Time) { return p.ID, p.UpdatedAt },
+		visible: func(ctx context.Context, p models.Position) (bool, error) {
+			return tenantVisible(ctx, p.Tenant), nil
+		},
+		send: func(kind pb.ChangeKind, p *models.Position) error {
+			ev := &pb.PositionEvent{Kind: kind}
+			if p != nil {
+				ev.Position = positionPB(*p)
+			}
+			return stream.Send(ev)
+		},
+	}
+	return s.streamError(f.run(ctx, s.interval(), req.GetSnapshot()))
+}
+
+func (s *Server) ExecuteOpportunity(ctx context.Context, req *pb.ExecuteOpportunityRequest) (*pb.ExecuteOpportunityResponse, error) {
+	if s.Repo == nil {
+		return nil, status.Error(codes.Internal, "repo unavailable")
+	}
+	if s.Risk == nil {
+		return nil, status.Error(codes.Unavailable, "risk manager unavailable")
+	}
+	if req.GetOpportunityId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid opportunity_id")
+	}
+	var size *decimal.Decimal
+	if v := strings.TrimSpace(req.GetSizeUsd()); v != "" {
+		d, err := decimal.NewFromString(v)
+		if err != nil || !d.IsPositive() {
+			return nil, status.Error(codes.InvalidArgument, "size_usd must be positive")
+		}
+		size = &d
+	}
+	opp, err := s.Repo.GetOpportunityByID(ctx, req.GetOpportunityId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if opp == nil || !tenantVisible(ctx, opp.Tenant) {
+		return nil, status.Error(codes.NotFound, "opportunity not found")
+	}
+	if strings.TrimSpace(opp.Status) != "" && opp.Status != "active" {
+		return nil, status.Errorf(codes.FailedPrecondition, "opportunity not active: %s", opp.Status)
+	}
+	if opp.Shadow {
+		return nil, status.Error(codes.FailedPrecondition, "shadow opportunity is not executable")
+	}
+
+	plan, warnings, err := service.CreateOpportunityPlan(ctx, s.Repo, s.Risk, s.Campaigns, s.Costs, s.Outbox, *opp, size)
+	if err != nil {
+		if blocked, ok := service.IsCampaignBlocked(err); ok {
+			if blocked.Reason == service.CampaignReasonNotFound {
+				return nil, status.Error(codes.InvalidArgument, "campaign does not admit this plan: "+blocked.Reason)
+			}
+			return nil, status.Error(codes.FailedPrecondition, "campaign does not admit this plan: "+blocked.Reason)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	out := &pb.ExecuteOpportunityResponse{Plan: planPB(*plan), SizingWarnings: warnings}
+
+	result, err := service.PreflightPlan(ctx, s.Repo, s.Risk, s.Outbox, plan.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if result == nil {
+		return nil, status.Error(codes.NotFound, "execution plan not found")
+	}
+	out.Preflight = preflightPB(*result)
+	if !result.Passed || !req.GetSubmit() {
+		out.Plan.Status = planStatus(ctx, s.Repo, plan.ID, out.Plan.Status)
+		return out, nil
+	}
+	sub, err := s.submit(ctx, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+	out.Submit = submitPB(*sub)
+	out.Plan.Status = sub.PlanStatus
+	return out, nil
+}
+
+func (s *Server) SubmitPlan(ctx context.Context, req *pb.SubmitPlanRequest) (*pb.SubmitResult, error) {
+	if s.Repo == nil {
+		return nil, status.Error(codes.Internal, "repo unavailable")
+	}
+	if req.GetPlanId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid plan_id")
+	}
+	plan, err := s.Repo.GetExecutionPlanByID(ctx, req.GetPlanId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if plan == nil || !tenantVisible(ctx, plan.Tenant) {
+		return nil, status.Error(codes.NotFound, "plan not found")
+	}
+	sub, err := s.submit(ctx, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+	return submitPB(*sub), nil
+}
+
+func (s *Server) submit(ctx context.Context, planID uint64) (*service.SubmitResult, error) {
+	if s.Executor == nil {
+		return nil, status.Error(codes.Unavailable, "executor unavailable")
+	}
+	out, err := s.Executor.SubmitPlan(ctx, planID)
+	var limitErr *risk.RateLimitError
+	if errors.As(err, &limitErr) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if out == nil {
+		return nil, status.Error(codes.NotFound, "plan not found")
+	}
+	return out, nil
+}
+
+func (s *Server) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.Order, error) {
+	if s.Repo == nil {
+		return nil, status.Error(codes.Internal, "repo unavailable")
+	}
+	if s.Executor == nil {
+		return nil, status.Error(codes.Unavailable, "executor unavailable")
+	}
+	if req.GetOrderId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	order, err := s.Repo.GetOrderByID(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if order == nil {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	if paas.TenantFromContext(ctx) != "" {
+		plan, err := s.Repo.GetExecutionPlanByID(ctx, order.PlanID)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if plan == nil || !tenantVisible(ctx, plan.Tenant) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+	}
+	if err := s.Executor.CancelOrder(ctx, order.ID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if updated, err := s.Repo.GetOrderByID(ctx, order.ID); err == nil && updated != nil {
+		order = updated
+	}
+	return orderPB(*order), nil
+}
+
+// planStatus re-reads a plan's status after preflight moved it.
+func planStatus(ctx context.Context, repo repository.Repository, planID uint64, fallback string) string {
+	plan, err := repo.GetExecutionPlanByID(ctx, planID)
+	if err != nil || plan == nil {
+		return fallback
+	}
+	return plan.Status
+}
+
+// streamError maps a feed's repository error; a stream ended by the client
+// returns nil from the feed.
+func (s *Server) streamError(err error) error {
+	select {
+	case <-s.done():
+		return status.Error(codes.Unavailable, "server shutting down")
+	default:
+	}
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func tenantScope(ctx context.Context) *string {
+	tenant := paas.TenantFromContext(ctx)
+	if tenant == "" {
+		return nil
+	}
+	return &tenant
+}
+
+func tenantVisible(ctx context.Context, rowTenant string) bool {
+	tenant := paas.TenantFromContext(ctx)
+	return tenant == "" || strings.EqualFold(strings.TrimSpace(rowTenant), tenant)
+}
+
+// planTenants caches the desk of each plan an order stream has seen.
+type planTenants struct {
+	repo   repository.Repository
+	byPlan map[uint64]string
+}
+
+func (t *planTenants) of(ctx context.Context, planID uint64) (string, error) {
+	if tenant, ok := t.byPlan[planID]; ok {
+		return tenant, nil
+	}
+	plan, err := t.repo.GetExecutionPlanByID(ctx, planID)
+	if err != nil {
+		return "", err
+	}
+	tenant := ""
+	if plan != nil {
+		tenant = plan.Tenant
+	}
+	t.byPlan[planID] = tenant
+	return tenant, nil
+}
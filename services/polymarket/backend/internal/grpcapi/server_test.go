@@ -0,0 +1,181 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "polymarket/internal/grpcapi/polymarketv1"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+type oppRepo struct {
+	repository.Repository
+	mu   sync.Mutex
+	opps map[uint64]models.Opportunity
+}
+
+func (r *oppRepo) ListOpportunities(_ context.Context, p repository.ListOpportunitiesParams) ([]models.Opportunity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.Opportunity
+	for _, o := range r.opps {
+		if p.Status != nil && o.Status != *p.Status {
+			continue
+		}
+		if p.Tenant != nil && o.Tenant != *p.Tenant {
+			continue
+		}
+		out = append(out, o)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.After(out[j].UpdatedAt) })
+	return out, nil
+}
+
+func (r *oppRepo) GetOpportunityByID(_ context.Context, id uint64) (*models.Opportunity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o, ok := r.opps[id]
+	if !ok {
+		return nil, nil
+	}
+	return &o, nil
+}
+
+func (r *oppRepo) put(o models.Opportunity) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.opps[o.ID] = o
+}
+
+func dialTest(t *testing.T, srv *Server) pb.ExecutionServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	auth := paas.NewGRPCAuth(nil, nil, nil)
+	// Test tokens are "<role>:<project>".
+	auth.Verify = func(_ context.Context, token string) (paas.TokenClaims, error) {
+		role, project, ok := strings.Cut(token, ":")
+		if !ok {
+			return paas.TokenClaims{}, paas.ErrInvalidToken
+		}
+		return paas.TokenClaims{Project: project, Role: role}, nil
+	}
+	g := grpc.NewServer(auth.ServerOptions()...)
+	srv.Register(g)
+	go func() { _ = g.Serve(lis) }()
+	t.Cleanup(func() {
+		srv.Stop()
+		g.Stop()
+	})
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewExecutionServiceClient(conn)
+}
+
+func withAuth(ctx context.Context, project string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer agent:"+project)
+}
+
+func TestStreamOpportunities(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &oppRepo{opps: map[uint64]models.Opportunity{
+		1: {ID: 1, Tenant: "default", Status: "active", UpdatedAt: t0},
+		2: {ID: 2, Tenant: "default", Status: "executing", UpdatedAt: t0},
+	}}
+	client := dialTest(t, &Server{Repo: repo, PollInterval: 5 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(withAuth(context.Background(), "default"), 5*time.Second)
+	defer cancel()
+	stream, err := client.StreamOpportunities(ctx, &pb.StreamOpportunitiesRequest{Snapshot: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recv := func() *pb.OpportunityEvent {
+		t.Helper()
+		ev, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ev
+	}
+	if ev := recv(); ev.Kind != pb.ChangeKind_CHANGE_KIND_SNAPSHOT || ev.Opportunity.GetId() != 1 {
+		t.Fatalf("snapshot=%v", ev)
+	}
+	if ev := recv(); ev.Kind != pb.ChangeKind_CHANGE_KIND_SNAPSHOT_DONE || ev.Opportunity != nil {
+		t.Fatalf("snapshot done=%v", ev)
+	}
+
+	// A new active row is sent, and so is one leaving the active filter.
+	repo.put(models.Opportunity{ID: 3, Tenant: "default", Status: "active", UpdatedAt: t0.Add(time.Second)})
+	if ev := recv(); ev.Kind != pb.ChangeKind_CHANGE_KIND_UPDATE || ev.Opportunity.GetId() != 3 {
+		t.Fatalf("new=%v", ev)
+	}
+	repo.put(models.Opportunity{ID: 1, Tenant: "default", Status: "executing", UpdatedAt: t0.Add(2 * time.Second)})
+	if ev := recv(); ev.Opportunity.GetId() != 1 || ev.Opportunity.GetStatus() != "executing" {
+		t.Fatalf("left filter=%v", ev)
+	}
+}
+
+func TestAuthAndTenancy(t *testing.T) {
+	t.Setenv("PM_TENANCY_ENABLED", "true")
+	repo := &oppRepo{opps: map[uint64]models.Opportunity{
+		1: {ID: 1, Tenant: "desk-a", Status: "active"},
+	}}
+	client := dialTest(t, &Server{Repo: repo})
+
+	_, err := client.SubmitPlan(context.Background(), &pb.SubmitPlanRequest{PlanId: 1})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("no token: %v", err)
+	}
+	bogus := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer x")
+	if _, err := client.SubmitPlan(bogus, &pb.SubmitPlanRequest{PlanId: 1}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("bogus token: %v", err)
+	}
+	viewer := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer viewer:desk-a")
+	if _, err := client.SubmitPlan(viewer, &pb.SubmitPlanRequest{PlanId: 1}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("viewer write: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for desk, want := range map[string]int{"desk-a": 1, "desk-b": 0} {
+		// The project metadata is ignored; only the token's project counts.
+		callCtx := metadata.AppendToOutgoingContext(withAuth(ctx, desk), "x-easyweb3-project", "desk-a")
+		stream, err := client.StreamOpportunities(callCtx, &pb.StreamOpportunitiesRequest{Snapshot: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := 0
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("%s: %v", desk, err)
+			}
+			if ev.Kind == pb.ChangeKind_CHANGE_KIND_SNAPSHOT_DONE {
+				break
+			}
+			rows++
+		}
+		if rows != want {
+			t.Fatalf("%s sees %d rows, want %d", desk, rows, want)
+		}
+	}
+}
@@ -2,16 +2,16 @@ package handler
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 
+	"polymarket/internal/governor"
 	"polymarket/internal/models"
-	"polymarket/internal/paas"
 	"polymarket/internal/repository"
 	"polymarket/internal/service"
 )
@@ -20,17 +20,26 @@ type CatalogHandler struct {
 	Service      *service.CatalogSyncService
 	QueryService *service.CatalogQueryService
 	Logger       *zap.Logger
+	// Governor, when set, queues syncs and reprocessing behind other heavy jobs.
+	Governor *governor.Governor
+	// Outbox records the audit logs of syncs and reprocessing.
+	Outbox *service.OutboxDispatcher
 }
 
 func (h *CatalogHandler) Register(r *gin.Engine) {
 	group := r.Group("/api/catalog")
-	group.POST("/sync", h.syncCatalog)
+	group.POST("/sync", heavyJob(h.Governor, governor.ClassBackfill), h.syncCatalog)
 	group.GET("/sync-state", h.listSyncState)
+	group.GET("/sync-profiles", h.listSyncProfiles)
 	group.GET("/events", h.listEvents)
 	group.GET("/markets", h.listMarkets)
 	group.GET("/tokens", h.listTokens)
+	group.POST("/tokens/batch", h.batchTokens)
 	group.GET("/markets/realtime", h.getMarketRealtime)
 	group.GET("/events/realtime", h.getEventRealtime)
+	group.GET("/quarantine", h.listQuarantine)
+	group.GET("/quarantine/metrics", h.quarantineMetrics)
+	group.POST("/quarantine/reprocess", heavyJob(h.Governor, governor.ClassBackfill), h.reprocessQuarantine)
 }
 
 // @Summary Run catalog sync
@@ -40,6 +49,9 @@ func (h *CatalogHandler) Register(r *gin.Engine) {
 // @Param max_pages query int false "max pages"
 // @Param resume query bool false "resume from cursor"
 // @Param tag_id query int false "tag id"
+// @Param tag_ids query string false "comma-separated include tag ids"
+// @Param exclude_tag_ids query string false "comma-separated exclude tag ids"
+// @Param profile query string false "sync profile (replaces tag filters)"
 // @Param closed query string false "open|closed"
 // @Param book_max_assets query int false "max assets for /book resync"
 // @Param book_batch_size query int false "batch size for /book resync"
@@ -60,36 +72,55 @@ func (h *CatalogHandler) syncCatalog(c *gin.Context) {
 	bookMaxAssets := intQuery(c, "book_max_assets", 0)
 	bookBatchSize := intQuery(c, "book_batch_size", 0)
 	bookSleepPerBatch := durationQuery(c, "book_sleep_per_batch")
+	tagIDs, ok := intListQuery(c, "tag_ids")
+	if !ok {
+		Error(c, http.StatusBadRequest, "invalid tag_ids", nil)
+		return
+	}
+	excludeTagIDs, ok := intListQuery(c, "exclude_tag_ids")
+	if !ok {
+		Error(c, http.StatusBadRequest, "invalid exclude_tag_ids", nil)
+		return
+	}
 
-	result, err := h.Service.Sync(c.Request.Context(), service.SyncOptions{
+	opts := service.SyncOptions{
 		Scope:             scope,
 		Limit:             limit,
 		MaxPages:          maxPages,
 		Resume:            resume,
 		TagID:             tagID,
+		TagIDs:            tagIDs,
+		ExcludeTagIDs:     excludeTagIDs,
 		Closed:            closed,
 		BookMaxAssets:     bookMaxAssets,
 		BookBatchSize:     bookBatchSize,
 		BookSleepPerBatch: bookSleepPerBatch,
-	})
+	}
+	if err := h.Service.ApplyProfile(&opts, c.Query("profile")); err != nil {
+		Error(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	result, err := h.Service.Sync(c.Request.Context(), opts)
 	if err != nil {
 		if h.Logger != nil {
 			h.Logger.Warn("catalog sync failed", zap.Error(err))
 		}
-		paas.LogBestEffort(c, "polymarket_catalog_sync_failed", "warn", map[string]any{
+		logWithOutbox(c, h.Outbox, "polymarket_catalog_sync_failed", "warn", map[string]any{
 			"error": err.Error(),
 		})
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	paas.LogBestEffort(c, "polymarket_catalog_sync_ok", "info", map[string]any{
-		"scope":   result.Scope,
-		"pages":   result.Pages,
-		"events":  result.Events,
-		"markets": result.Markets,
-		"tokens":  result.Tokens,
-		"series":  result.Series,
-		"tags":    result.Tags,
+	logWithOutbox(c, h.Outbox, "polymarket_catalog_sync_ok", "info", map[string]any{
+		"scope":       result.Scope,
+		"profile":     opts.Profile,
+		"pages":       result.Pages,
+		"events":      result.Events,
+		"markets":     result.Markets,
+		"tokens":      result.Tokens,
+		"series":      result.Series,
+		"tags":        result.Tags,
+		"quarantined": result.Quarantined,
 	})
 	Ok(c, result, nil)
 }
@@ -114,6 +145,153 @@ func (h *CatalogHandler) listSyncState(c *gin.Context) {
 	Ok(c, states, nil)
 }
 
+// @Summary List catalog sync profiles with their events sync state
+// @Tags catalog
+// @Success 200 {object} apiResponse
+// @Router /api/catalog/sync-profiles [get]
+func (h *CatalogHandler) listSyncProfiles(c *gin.Context) {
+	if h.Service == nil || h.Service.Store == nil {
+		Error(c, http.StatusInternalServerError, "service unavailable", nil)
+		return
+	}
+	names := make([]string, 0, len(h.Service.Profiles))
+	for name := range h.Service.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make([]map[string]any, 0, len(names))
+	for _, name := range names {
+		profile := h.Service.Profiles[name]
+		state, err := h.Service.Store.GetSyncState(c.Request.Context(), service.SyncStateScope("events", name))
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		out = append(out, map[string]any{
+			"name":            name,
+			"tag_ids":         profile.TagIDs,
+			"exclude_tag_ids": profile.ExcludeTagIDs,
+			"state":           state,
+		})
+	}
+	Ok(c, out, nil)
+}
+
+// @Summary List quarantined catalog rows
+// @Tags catalog
+// @Param limit query int false "limit"
+// @Param offset query int false "offset"
+// @Param status query string false "quarantined|released"
+// @Param rule query string false "data-quality rule"
+// @Param entity_id query string false "entity id"
+// @Success 200 {object} apiResponse
+// @Router /api/catalog/quarantine [get]
+func (h *CatalogHandler) listQuarantine(c *gin.Context) {
+	if h.Service == nil || h.Service.Store == nil {
+		Error(c, http.StatusInternalServerError, "service unavailable", nil)
+		return
+	}
+	limit := intQuery(c, "limit", 50)
+	offset := intQuery(c, "offset", 0)
+	params := repository.ListCatalogQuarantineParams{
+		Limit:    limit,
+		Offset:   offset,
+		EntityID: strQueryPtr(c, "entity_id"),
+		Rule:     strQueryPtr(c, "rule"),
+		Status:   strQueryPtr(c, "status"),
+		OrderBy: parseOrder(c.Query("order_by"), map[string]string{
+			"last_seen_at":  "last_seen_at",
+			"first_seen_at": "first_seen_at",
+			"hits":          "hits",
+		}),
+		Asc: boolQueryPtr(c, "ascending"),
+	}
+	items, err := h.Service.Store.ListCatalogQuarantine(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Service.Store.CountCatalogQuarantine(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(limit, offset, total))
+}
+
+// @Summary Quarantine metrics by rule and status
+// @Tags catalog
+// @Success 200 {object} apiResponse
+// @Router /api/catalog/quarantine/metrics [get]
+func (h *CatalogHandler) quarantineMetrics(c *gin.Context) {
+	if h.Service == nil || h.Service.Store == nil {
+		Error(c, http.StatusInternalServerError, "service unavailable", nil)
+		return
+	}
+	rows, err := h.Service.Store.CatalogQuarantineMetrics(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	out := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, map[string]any{
+			"rule":         row.Rule,
+			"status":       row.Status,
+			"count":        row.Count,
+			"hits":         row.Hits,
+			"last_seen_at": row.LastSeenAt,
+		})
+	}
+	Ok(c, out, map[string]any{"disabled_rules": h.Service.DisabledQualityRules})
+}
+
+type reprocessQuarantineRequest struct {
+	IDs   []uint64 `json:"ids"`
+	Rule  string   `json:"rule"`
+	Limit int      `json:"limit"`
+}
+
+// @Summary Re-process quarantined rows against current rules
+// @Tags catalog
+// @Success 200 {object} apiResponse
+// @Router /api/catalog/quarantine/reprocess [post]
+func (h *CatalogHandler) reprocessQuarantine(c *gin.Context) {
+	if h.Service == nil || h.Service.Store == nil {
+		Error(c, http.StatusInternalServerError, "service unavailable", nil)
+		return
+	}
+	var req reprocessQuarantineRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			Error(c, http.StatusBadRequest, "invalid body", nil)
+			return
+		}
+	}
+	var rule *string
+	if v := strings.TrimSpace(req.Rule); v != "" {
+		rule = &v
+	}
+	result, err := h.Service.ReprocessQuarantine(c.Request.Context(), service.QuarantineReprocessOptions{
+		IDs:   req.IDs,
+		Rule:  rule,
+		Limit: req.Limit,
+	})
+	if err != nil {
+		if h.Logger != nil {
+			h.Logger.Warn("reprocess quarantine failed", zap.Error(err))
+		}
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_catalog_quarantine_reprocess", "info", map[string]any{
+		"scanned":           result.Scanned,
+		"released":          result.Released,
+		"still_quarantined": result.StillQuarantined,
+	})
+	Ok(c, result, nil)
+}
+
 // @Summary List events
 // @Tags catalog
 // @Param limit query int false "limit"
@@ -270,10 +448,51 @@ func (h *CatalogHandler) listTokens(c *gin.Context) {
 	Ok(c, result.Items, meta)
 }
 
+// maxBatchTokens bounds one multi-get; the repository chunks the IN lists.
+const maxBatchTokens = 20000
+
+type batchTokensRequest struct {
+	TokenIDs []string `json:"token_ids"`
+}
+
+// @Summary Get tokens with latest book, data health and last trade
+// @Tags catalog
+// @Success 200 {object} apiResponse
+// @Router /api/catalog/tokens/batch [post]
+func (h *CatalogHandler) batchTokens(c *gin.Context) {
+	if h.QueryService == nil || h.QueryService.Repo == nil {
+		Error(c, http.StatusInternalServerError, "service unavailable", nil)
+		return
+	}
+	var req batchTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if len(req.TokenIDs) == 0 {
+		Error(c, http.StatusBadRequest, "token_ids required", nil)
+		return
+	}
+	if len(req.TokenIDs) > maxBatchTokens {
+		Error(c, http.StatusBadRequest, "too many token_ids", map[string]any{"max": maxBatchTokens})
+		return
+	}
+	items, err := repository.LoadTokenSnapshots(c.Request.Context(), h.QueryService.Repo, req.TokenIDs)
+	if err != nil {
+		if h.Logger != nil {
+			h.Logger.Warn("batch tokens failed", zap.Error(err))
+		}
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, map[string]any{"requested": len(req.TokenIDs), "found": len(items)})
+}
+
 type realtimeToken struct {
 	TokenID          string     `json:"token_id"`
 	Outcome          string     `json:"outcome"`
 	Side             *string    `json:"side"`
+	OutcomeIndex     int        `json:"outcome_index"`
 	MarketID         string     `json:"market_id"`
 	MarketQuestion   string     `json:"market_question"`
 	BestBid          *float64   `json:"best_bid"`
@@ -366,6 +585,7 @@ func (h *CatalogHandler) getMarketRealtime(c *gin.Context) {
 			TokenID:          token.ID,
 			Outcome:          token.Outcome,
 			Side:             token.Side,
+			OutcomeIndex:     token.OutcomeIndex,
 			MarketID:         token.MarketID,
 			MarketQuestion:   market.Question,
 			BestBid:          book.BestBid,
@@ -475,6 +695,7 @@ func (h *CatalogHandler) getEventRealtime(c *gin.Context) {
 			TokenID:          token.ID,
 			Outcome:          token.Outcome,
 			Side:             token.Side,
+			OutcomeIndex:     token.OutcomeIndex,
 			MarketID:         token.MarketID,
 			MarketQuestion:   market.Question,
 			BestBid:          book.BestBid,
@@ -508,6 +729,23 @@ func intQueryPtr(c *gin.Context, key string) *int {
 	return nil
 }
 
+// intListQuery parses a comma-separated list of ints; ok is false when an
+// item is not a number.
+func intListQuery(c *gin.Context, key string) ([]int, bool) {
+	var out []int
+	for _, part := range strings.Split(c.Query(key), ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		i, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		out = append(out, i)
+	}
+	return out, true
+}
+
 func boolQueryDefault(c *gin.Context, key string, def bool) bool {
 	if val := c.Query(key); val != "" {
 		if b, err := strconv.ParseBool(val); err == nil {
@@ -582,15 +820,6 @@ func paginationMeta(limit, offset int, total int64) map[string]any {
 	}
 }
 
-func decimalQueryPtr(c *gin.Context, key string) *decimal.Decimal {
-	if val := strings.TrimSpace(c.Query(key)); val != "" {
-		if d, err := decimal.NewFromString(val); err == nil {
-			return &d
-		}
-	}
-	return nil
-}
-
 func cleanStrings(items []string) []string {
 	out := make([]string, 0, len(items))
 	seen := map[string]struct{}{}
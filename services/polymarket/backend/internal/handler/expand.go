@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// expandOptions is parsed from ?expand=market,event,labels.
+type expandOptions struct {
+	Market bool
+	Event  bool
+	Labels bool
+}
+
+func (o expandOptions) any() bool { return o.Market || o.Event || o.Labels }
+
+func parseExpand(c *gin.Context) expandOptions {
+	var out expandOptions
+	for _, part := range strings.Split(c.Query("expand"), ",") {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "market", "markets":
+			out.Market = true
+		case "event", "events":
+			out.Event = true
+		case "labels", "label":
+			out.Labels = true
+		}
+	}
+	return out
+}
+
+type expandedMarket struct {
+	ID       string     `json:"id"`
+	Question string     `json:"question"`
+	Slug     *string    `json:"slug,omitempty"`
+	EventID  string     `json:"event_id"`
+	Active   bool       `json:"active"`
+	Closed   bool       `json:"closed"`
+	EndTime  *time.Time `json:"end_time,omitempty"`
+	YesPrice *float64   `json:"yes_price,omitempty"`
+}
+
+type expandedEvent struct {
+	ID      string     `json:"id"`
+	Title   string     `json:"title"`
+	Slug    string     `json:"slug"`
+	EndTime *time.Time `json:"end_time,omitempty"`
+	Closed  bool       `json:"closed"`
+}
+
+// catalogExpansion holds batched catalog lookups for one response page.
+type catalogExpansion struct {
+	markets map[string]*expandedMarket
+	events  map[string]*expandedEvent
+	labels  map[string][]string
+}
+
+// loadExpansion resolves the requested catalog data with one query per table
+// regardless of page size.
+func loadExpansion(ctx context.Context, repo repository.Repository, opts expandOptions, marketIDs, eventIDs []string) (*catalogExpansion, error) {
+	out := &catalogExpansion{
+		markets: map[string]*expandedMarket{},
+		events:  map[string]*expandedEvent{},
+		labels:  map[string][]string{},
+	}
+	marketIDs = uniqueNonEmpty(marketIDs)
+	if opts.Market && len(marketIDs) > 0 {
+		markets, err := repo.ListMarketsByIDs(ctx, marketIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range markets {
+			out.markets[m.ID] = &expandedMarket{
+				ID:       m.ID,
+				Question: m.Question,
+				Slug:     m.Slug,
+				EventID:  m.EventID,
+				Active:   m.Active,
+				Closed:   m.Closed,
+			}
+			// Markets carry no end time of their own; it comes from the event.
+			eventIDs = append(eventIDs, m.EventID)
+		}
+		if err := out.loadYesPrices(ctx, repo, marketIDs); err != nil {
+			return nil, err
+		}
+	}
+	eventIDs = uniqueNonEmpty(eventIDs)
+	if (opts.Market || opts.Event) && len(eventIDs) > 0 {
+		events, err := repo.ListEventsByIDs(ctx, eventIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range events {
+			out.events[ev.ID] = &expandedEvent{
+				ID:      ev.ID,
+				Title:   ev.Title,
+				Slug:    ev.Slug,
+				EndTime: ev.EndTime,
+				Closed:  ev.Closed,
+			}
+		}
+		for _, m := range out.markets {
+			if ev := out.events[m.EventID]; ev != nil {
+				m.EndTime = ev.EndTime
+			}
+		}
+	}
+	if opts.Labels && len(marketIDs) > 0 {
+		labels, err := repo.ListMarketLabelsByMarketIDs(ctx, marketIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range labels {
+			out.labels[l.MarketID] = append(out.labels[l.MarketID], l.Label)
+		}
+	}
+	return out, nil
+}
+
+func (x *catalogExpansion) loadYesPrices(ctx context.Context, repo repository.Repository, marketIDs []string) error {
+	tokens, err := repo.ListTokensByMarketIDs(ctx, marketIDs)
+	if err != nil {
+		return err
+	}
+	yesByToken := map[string]string{}
+	tokenIDs := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if strings.EqualFold(strings.TrimSpace(t.Outcome), "yes") {
+			yesByToken[t.ID] = t.MarketID
+			tokenIDs = append(tokenIDs, t.ID)
+		}
+	}
+	if len(tokenIDs) == 0 {
+		return nil
+	}
+	books, err := repo.ListOrderbookLatestByTokenIDs(ctx, tokenIDs)
+	if err != nil {
+		return err
+	}
+	for _, b := range books {
+		m := x.markets[yesByToken[b.TokenID]]
+		if m == nil {
+			continue
+		}
+		switch {
+		case b.Mid != nil && *b.Mid > 0:
+			m.YesPrice = b.Mid
+		case b.BestAsk != nil && *b.BestAsk > 0:
+			m.YesPrice = b.BestAsk
+		}
+	}
+	return nil
+}
+
+func (x *catalogExpansion) market(id string) *expandedMarket {
+	if x == nil {
+		return nil
+	}
+	return x.markets[strings.TrimSpace(id)]
+}
+
+func (x *catalogExpansion) event(id string) *expandedEvent {
+	if x == nil {
+		return nil
+	}
+	return x.events[strings.TrimSpace(id)]
+}
+
+// labelsFor returns the union of labels over the given markets.
+func (x *catalogExpansion) labelsFor(marketIDs ...string) []string {
+	if x == nil {
+		return nil
+	}
+	var out []string
+	seen := map[string]bool{}
+	for _, id := range marketIDs {
+		for _, l := range x.labels[strings.TrimSpace(id)] {
+			if !seen[l] {
+				seen[l] = true
+				out = append(out, l)
+			}
+		}
+	}
+	return out
+}
+
+func uniqueNonEmpty(items []string) []string {
+	out := make([]string, 0, len(items))
+	seen := map[string]bool{}
+	for _, it := range items {
+		it = strings.TrimSpace(it)
+		if it == "" || seen[it] {
+			continue
+		}
+		seen[it] = true
+		out = append(out, it)
+	}
+	return out
+}
+
+// opportunityView embeds catalog data next to the opportunity fields.
+type opportunityView struct {
+	models.Opportunity
+	Markets []*expandedMarket `json:"markets,omitempty"`
+	Event   *expandedEvent    `json:"event,omitempty"`
+	Labels  []string          `json:"labels,omitempty"`
+}
+
+func expandOpportunities(ctx context.Context, repo repository.Repository, opts expandOptions, items []models.Opportunity) ([]opportunityView, error) {
+	marketIDs := []string{}
+	eventIDs := []string{}
+	perItem := make([][]string, len(items))
+	for i, it := range items {
+		ids := opportunityMarketIDs(it)
+		perItem[i] = ids
+		marketIDs = append(marketIDs, ids...)
+		if it.EventID != nil {
+			eventIDs = append(eventIDs, *it.EventID)
+		}
+	}
+	x, err := loadExpansion(ctx, repo, opts, marketIDs, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]opportunityView, len(items))
+	for i, it := range items {
+		view := opportunityView{Opportunity: it}
+		if opts.Market {
+			for _, id := range perItem[i] {
+				if m := x.market(id); m != nil {
+					view.Markets = append(view.Markets, m)
+				}
+			}
+		}
+		if opts.Event && it.EventID != nil {
+			view.Event = x.event(*it.EventID)
+		}
+		if opts.Labels {
+			view.Labels = x.labelsFor(perItem[i]...)
+		}
+		out[i] = view
+	}
+	return out, nil
+}
+
+func opportunityMarketIDs(it models.Opportunity) []string {
+	ids := []string{}
+	if it.PrimaryMarketID != nil {
+		ids = append(ids, *it.PrimaryMarketID)
+	}
+	var more []string
+	if len(it.MarketIDs) > 0 && json.Unmarshal(it.MarketIDs, &more) == nil {
+		ids = append(ids, more...)
+	}
+	return uniqueNonEmpty(ids)
+}
+
+// positionView embeds catalog data next to the position fields.
+type positionView struct {
+	models.Position
+	Market *expandedMarket `json:"market,omitempty"`
+	Event  *expandedEvent  `json:"event,omitempty"`
+	Labels []string        `json:"labels,omitempty"`
+}
+
+func expandPositions(ctx context.Context, repo repository.Repository, opts expandOptions, items []models.Position) ([]positionView, error) {
+	marketIDs := make([]string, 0, len(items))
+	eventIDs := make([]string, 0, len(items))
+	for _, it := range items {
+		marketIDs = append(marketIDs, it.MarketID)
+		eventIDs = append(eventIDs, it.EventID)
+	}
+	x, err := loadExpansion(ctx, repo, opts, marketIDs, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]positionView, len(items))
+	for i, it := range items {
+		view := positionView{Position: it}
+		if opts.Market {
+			view.Market = x.market(it.MarketID)
+		}
+		if opts.Event {
+			eventID := it.EventID
+			if eventID == "" && view.Market != nil {
+				eventID = view.Market.EventID
+			}
+			view.Event = x.event(eventID)
+		}
+		if opts.Labels {
+			view.Labels = x.labelsFor(it.MarketID)
+		}
+		out[i] = view
+	}
+	return out, nil
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// expandRepo serves a small catalog and counts the queries made against it.
+type expandRepo struct {
+	repository.Repository
+	calls map[string]int
+}
+
+func (r *expandRepo) ListMarketsByIDs(_ context.Context, ids []string) ([]models.Market, error) {
+	r.calls["markets"]++
+	all := map[string]models.Market{
+		"m1": {ID: "m1", Question: "Will it rain?", EventID: "e1", Active: true},
+		"m2": {ID: "m2", Question: "Will it snow?", EventID: "e1", Active: true},
+	}
+	var out []models.Market
+	for _, id := range ids {
+		if m, ok := all[id]; ok {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (r *expandRepo) ListEventsByIDs(_ context.Context, ids []string) ([]models.Event, error) {
+	r.calls["events"]++
+	end := time.Date(2026, 11, 3, 0, 0, 0, 0, time.UTC)
+	var out []models.Event
+	for _, id := range ids {
+		if id == "e1" {
+			out = append(out, models.Event{ID: "e1", Title: "Weather", Slug: "weather", EndTime: &end})
+		}
+	}
+	return out, nil
+}
+
+func (r *expandRepo) ListMarketLabelsByMarketIDs(_ context.Context, ids []string) ([]models.MarketLabel, error) {
+	r.calls["labels"]++
+	return []models.MarketLabel{{MarketID: "m1", Label: "weather"}, {MarketID: "m1", Label: "us"}, {MarketID: "m2", Label: "weather"}}, nil
+}
+
+func (r *expandRepo) ListTokensByMarketIDs(_ context.Context, ids []string) ([]models.Token, error) {
+	r.calls["tokens"]++
+	return []models.Token{{ID: "y1", MarketID: "m1", Outcome: "Yes"}, {ID: "n1", MarketID: "m1", Outcome: "No"}, {ID: "y2", MarketID: "m2", Outcome: "YES"}}, nil
+}
+
+func (r *expandRepo) ListOrderbookLatestByTokenIDs(_ context.Context, ids []string) ([]models.OrderbookLatest, error) {
+	r.calls["books"]++
+	mid, ask := 0.42, 0.31
+	return []models.OrderbookLatest{{TokenID: "y1", Mid: &mid}, {TokenID: "y2", BestAsk: &ask}}, nil
+}
+
+func TestParseExpand(t *testing.T) {
+	cases := []struct {
+		query string
+		want  expandOptions
+	}{
+		{"", expandOptions{}},
+		{"expand=market", expandOptions{Market: true}},
+		{"expand=Markets,%20labels", expandOptions{Market: true, Labels: true}},
+		{"expand=event,label,bogus", expandOptions{Event: true, Labels: true}},
+	}
+	for _, tc := range cases {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/x?"+tc.query, nil)
+		if got := parseExpand(c); got != tc.want {
+			t.Errorf("%q = %+v, want %+v", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestExpandOpportunities(t *testing.T) {
+	repo := &expandRepo{calls: map[string]int{}}
+	m1, e1 := "m1", "e1"
+	items := []models.Opportunity{
+		{ID: 1, PrimaryMarketID: &m1, MarketIDs: datatypes.JSON(`["m1","m2"]`), EventID: &e1},
+		{ID: 2, MarketIDs: datatypes.JSON(`["m2","gone"]`)},
+		{ID: 3},
+	}
+	out, err := expandOpportunities(context.Background(), repo, expandOptions{Market: true, Event: true, Labels: true}, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One query per table for the whole page.
+	for _, table := range []string{"markets", "events", "labels", "tokens", "books"} {
+		if repo.calls[table] != 1 {
+			t.Errorf("%s queried %d times", table, repo.calls[table])
+		}
+	}
+
+	first := out[0]
+	if len(first.Markets) != 2 || first.Markets[0].ID != "m1" || first.Markets[1].ID != "m2" {
+		t.Fatalf("markets = %+v", first.Markets)
+	}
+	if p := first.Markets[0].YesPrice; p == nil || *p != 0.42 {
+		t.Fatalf("m1 yes price = %v", p)
+	}
+	if p := first.Markets[1].YesPrice; p == nil || *p != 0.31 {
+		t.Fatalf("m2 yes price from best ask = %v", p)
+	}
+	if first.Markets[0].EndTime == nil || first.Event == nil || first.Event.Title != "Weather" {
+		t.Fatalf("event = %+v end = %v", first.Event, first.Markets[0].EndTime)
+	}
+	if !reflect.DeepEqual(first.Labels, []string{"weather", "us"}) {
+		t.Fatalf("labels = %v", first.Labels)
+	}
+
+	second := out[1]
+	if len(second.Markets) != 1 || second.Markets[0].ID != "m2" || second.Event != nil {
+		t.Fatalf("second = %+v", second)
+	}
+	if !reflect.DeepEqual(second.Labels, []string{"weather"}) {
+		t.Fatalf("second labels = %v", second.Labels)
+	}
+	if third := out[2]; third.Markets != nil || third.Event != nil || third.Labels != nil {
+		t.Fatalf("third = %+v", third)
+	}
+}
+
+func TestExpandPositionsOnlyLoadsWhatIsAsked(t *testing.T) {
+	repo := &expandRepo{calls: map[string]int{}}
+	items := []models.Position{{ID: 1, MarketID: "m2"}, {ID: 2, MarketID: "m1", EventID: "e1"}}
+	out, err := expandPositions(context.Background(), repo, expandOptions{Event: true}, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.calls["markets"] != 0 || repo.calls["labels"] != 0 || repo.calls["events"] != 1 {
+		t.Fatalf("calls = %v", repo.calls)
+	}
+	// Without market expansion the first position's event is unknown.
+	if out[0].Market != nil || out[0].Event != nil || out[1].Event == nil || out[1].Event.ID != "e1" {
+		t.Fatalf("views = %+v", out)
+	}
+
+	repo = &expandRepo{calls: map[string]int{}}
+	out, err = expandPositions(context.Background(), repo, expandOptions{Market: true, Event: true}, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The market supplies the event the position lacks.
+	if out[0].Market == nil || out[0].Event == nil || out[0].Event.ID != "e1" {
+		t.Fatalf("first view = %+v", out[0])
+	}
+	if repo.calls["markets"] != 1 || repo.calls["events"] != 1 {
+		t.Fatalf("calls = %v", repo.calls)
+	}
+}
+
+func TestUniqueNonEmpty(t *testing.T) {
+	got := uniqueNonEmpty([]string{" a", "", "b", "a ", "  "})
+	if strings.Join(got, ",") != "a,b" {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/governor"
+)
+
+// heavyJob admits the request as a job of class before the handler runs, so
+// rebuilds, backfills and admin sweeps queue behind each other instead of
+// competing with live queries for DB connections. A full queue is a 429 and
+// waiting past the governor's queue timeout is a 503. The ticket rides on the
+// request context, where long loops pick it up through governor.Yield.
+func heavyJob(g *governor.Governor, class string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if g == nil {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), g.QueueTimeout())
+		t, err := g.Acquire(ctx, class)
+		cancel()
+		if err != nil {
+			switch {
+			case errors.Is(err, governor.ErrQueueFull):
+				Error(c, http.StatusTooManyRequests, "too many heavy jobs queued", map[string]any{"class": class})
+			case errors.Is(err, context.DeadlineExceeded):
+				c.Header("Retry-After", "30")
+				Error(c, http.StatusServiceUnavailable, "timed out waiting for a job slot", map[string]any{"class": class})
+			default:
+				Error(c, http.StatusServiceUnavailable, err.Error(), map[string]any{"class": class})
+			}
+			c.Abort()
+			return
+		}
+		defer t.Release()
+		c.Request = c.Request.WithContext(governor.WithTicket(c.Request.Context(), t))
+		c.Next()
+	}
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+const (
+	idempotencyHeader = "Idempotency-Key"
+	replayedHeader    = "Idempotent-Replayed"
+	maxIdempotencyKey = 255
+)
+
+// idempotencyPendingLease is how long a reservation holds its key without
+// renewal. The lease is renewed while the first request runs, so a slow
+// handler keeps its key; a process that dies mid-request stops renewing and
+// retries get the key back once the lease runs out instead of a 409 for the
+// whole TTL.
+var idempotencyPendingLease = 5 * time.Minute
+
+// Idempotency makes V2 writes (POST, PUT, PATCH, DELETE) that carry an
+// Idempotency-Key header safe to retry. The first request reserves the key
+// for the caller's tenant and its response is stored for ttl; a retry with
+// the same method, path and body gets that response back with
+// Idempotent-Replayed: true. Reusing a key for a different request is a 422
+// and retrying while the first is still running is a 409. Responses of 500
+// and above, and handler panics, are not stored, so the client can retry
+// them.
+func Idempotency(repo repository.Repository, ttl time.Duration, logger *zap.Logger) gin.HandlerFunc {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(idempotencyHeader))
+		if key == "" || repo == nil || !idempotentWrite(c.Request) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			Error(c, http.StatusBadRequest, "idempotency key too long", map[string]any{"max_length": maxIdempotencyKey})
+			c.Abort()
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			Error(c, http.StatusBadRequest, "read request body failed", nil)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now().UTC()
+		lease := idempotencyPendingLease
+		if lease > ttl {
+			lease = ttl
+		}
+		item := &models.IdempotencyKey{
+			Tenant:      paas.TenantFromGin(c),
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: requestHash(c.Request, body),
+			Status:      "pending",
+			ExpiresAt:   now.Add(lease),
+		}
+		got, created, err := repo.ReserveIdempotencyKey(c.Request.Context(), item)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			c.Abort()
+			return
+		}
+		if !created {
+			replayIdempotent(c, got, item.RequestHash)
+			return
+		}
+
+		stopRenew := make(chan struct{})
+		renewDone := make(chan struct{})
+		go func() {
+			defer close(renewDone)
+			renewIdempotencyLease(c.Request.Context(), repo, item.ID, lease, stopRenew, logger)
+		}()
+
+		w := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		finished := false
+		// Deferred so a panicking handler releases the key on its way to
+		// the recovery middleware.
+		defer func() {
+			close(stopRenew)
+			<-renewDone
+			// The response is already sent; store it even if the client went away.
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+			defer cancel()
+			status := w.Status()
+			var err error
+			if !finished || status >= http.StatusInternalServerError {
+				err = repo.DeleteIdempotencyKey(ctx, item.ID)
+			} else {
+				err = repo.CompleteIdempotencyKey(ctx, item.ID, status, w.Header().Get("Content-Type"), w.body.String(), time.Now().UTC().Add(ttl))
+			}
+			if err != nil && logger != nil {
+				logger.Warn("idempotency key update failed", zap.String("key", key), zap.Error(err))
+			}
+		}()
+		c.Next()
+		finished = true
+	}
+}
+
+// renewIdempotencyLease pushes the pending key's expiry a lease ahead every
+// third of a lease until stop is closed.
+func renewIdempotencyLease(ctx context.Context, repo repository.Repository, id uint64, lease time.Duration, stop <-chan struct{}, logger *zap.Logger) {
+	every := lease / 3
+	if every <= 0 {
+		every = lease
+	}
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	// The handler may outlive a cancelled client; the key must outlive it too.
+	ctx = context.WithoutCancel(ctx)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			renewCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			err := repo.RenewIdempotencyKey(renewCtx, id, time.Now().UTC().Add(lease))
+			cancel()
+			if err != nil && logger != nil {
+				logger.Warn("idempotency lease renewal failed", zap.Uint64("id", id), zap.Error(err))
+			}
+		}
+	}
+}
+
+func idempotentWrite(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return strings.HasPrefix(r.URL.Path, "/api/v2/")
+	}
+	return false
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replayIdempotent(c *gin.Context, prior *models.IdempotencyKey, hash string) {
+	defer c.Abort()
+	if prior == nil {
+		Error(c, http.StatusConflict, "idempotency key in use", nil)
+		return
+	}
+	if prior.RequestHash != hash {
+		Error(c, http.StatusUnprocessableEntity, "idempotency key reused with a different request", map[string]any{"method": prior.Method, "path": prior.Path})
+		return
+	}
+	if prior.Status != "complete" {
+		Error(c, http.StatusConflict, "request with this idempotency key is still in progress", nil)
+		return
+	}
+	c.Header(replayedHeader, "true")
+	contentType := prior.ContentType
+	if contentType == "" {
+		contentType = "application/json; charset=utf-8"
+	}
+	c.Data(prior.ResponseStatus, contentType, []byte(prior.ResponseBody))
+}
+
+// captureWriter keeps a copy of the response body for the idempotency store.
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type idempotencyRepo struct {
+	repository.Repository
+	mu     sync.Mutex
+	nextID uint64
+	keys   map[string]*models.IdempotencyKey
+}
+
+func (r *idempotencyRepo) ReserveIdempotencyKey(_ context.Context, item *models.IdempotencyKey) (*models.IdempotencyKey, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	scope := item.Tenant + "|" + item.Key
+	if prior, ok := r.keys[scope]; ok && prior.ExpiresAt.After(time.Now()) {
+		cp := *prior
+		return &cp, false, nil
+	}
+	r.nextID++
+	item.ID = r.nextID
+	cp := *item
+	r.keys[scope] = &cp
+	return item, true, nil
+}
+
+func (r *idempotencyRepo) CompleteIdempotencyKey(_ context.Context, id uint64, status int, contentType string, body string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.ID == id {
+			k.Status, k.ResponseStatus, k.ContentType, k.ResponseBody = "complete", status, contentType, body
+			k.ExpiresAt = expiresAt
+		}
+	}
+	return nil
+}
+
+func (r *idempotencyRepo) RenewIdempotencyKey(_ context.Context, id uint64, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.ID == id && k.Status == "pending" {
+			k.ExpiresAt = expiresAt
+		}
+	}
+	return nil
+}
+
+func (r *idempotencyRepo) DeleteIdempotencyKey(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for scope, k := range r.keys {
+		if k.ID == id {
+			delete(r.keys, scope)
+		}
+	}
+	return nil
+}
+
+func TestIdempotency_ReplaysFirstResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &idempotencyRepo{keys: map[string]*models.IdempotencyKey{}}
+	calls := 0
+	r := gin.New()
+	r.Use(Idempotency(repo, time.Hour, nil))
+	r.POST("/api/v2/opportunities/:id/dismiss", func(c *gin.Context) {
+		calls++
+		if c.Param("id") == "boom" {
+			Error(c, http.StatusInternalServerError, "boom", nil)
+			return
+		}
+		Ok(c, gin.H{"call": calls}, nil)
+	})
+	send := func(path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("/api/v2/opportunities/1/dismiss", "k1", `{"reason":"x"}`)
+	again := send("/api/v2/opportunities/1/dismiss", "k1", `{"reason":"x"}`)
+	if calls != 1 || again.Code != http.StatusOK || again.Body.String() != first.Body.String() || again.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("replay: calls=%d code=%d body=%s", calls, again.Code, again.Body.String())
+	}
+
+	if w := send("/api/v2/opportunities/2/dismiss", "k1", `{"reason":"x"}`); w.Code != http.StatusUnprocessableEntity || calls != 1 {
+		t.Fatalf("reused key: code=%d calls=%d", w.Code, calls)
+	}
+
+	// Server errors release the key so the retry runs again.
+	send("/api/v2/opportunities/boom/dismiss", "k2", "")
+	send("/api/v2/opportunities/boom/dismiss", "k2", "")
+	if calls != 3 {
+		t.Fatalf("5xx retry: calls=%d want=3", calls)
+	}
+
+	// Requests without a key are not deduplicated.
+	send("/api/v2/opportunities/1/dismiss", "", "")
+	send("/api/v2/opportunities/1/dismiss", "", "")
+	if calls != 5 {
+		t.Fatalf("keyless: calls=%d want=5", calls)
+	}
+
+	// A retry while the first request is still running is refused.
+	hash := requestHash(httptest.NewRequest(http.MethodPost, "/api/v2/opportunities/1/dismiss", nil), nil)
+	repo.keys["|k3"] = &models.IdempotencyKey{ID: 99, Key: "k3", Status: "pending", RequestHash: hash, ExpiresAt: time.Now().Add(time.Hour)}
+	if w := send("/api/v2/opportunities/1/dismiss", "k3", ""); w.Code != http.StatusConflict {
+		t.Fatalf("in-flight: code=%d", w.Code)
+	}
+}
+
+func TestIdempotency_ReleasesKeyOnPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &idempotencyRepo{keys: map[string]*models.IdempotencyKey{}}
+	calls := 0
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, err any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.Use(Idempotency(repo, time.Hour, nil))
+	r.POST("/api/v2/executions/:id/submit", func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		Ok(c, gin.H{"call": calls}, nil)
+	})
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/executions/1/submit", nil)
+		req.Header.Set("Idempotency-Key", "k1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	if w := send(); w.Code != http.StatusInternalServerError {
+		t.Fatalf("panic: code=%d", w.Code)
+	}
+	if w := send(); w.Code != http.StatusOK || calls != 2 {
+		t.Fatalf("retry after panic: code=%d calls=%d", w.Code, calls)
+	}
+	// The completed key is kept for the TTL, not just the pending lease.
+	if k := repo.keys["|k1"]; k == nil || k.Status != "complete" || time.Until(k.ExpiresAt) < 50*time.Minute {
+		t.Fatalf("completed key = %+v", k)
+	}
+}
+
+func TestIdempotency_PendingKeyHasShortLease(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &idempotencyRepo{keys: map[string]*models.IdempotencyKey{}}
+	var pending models.IdempotencyKey
+	r := gin.New()
+	r.Use(Idempotency(repo, 24*time.Hour, nil))
+	r.POST("/api/v2/executions/:id/submit", func(c *gin.Context) {
+		pending = *repo.keys["|k1"]
+		Ok(c, gin.H{}, nil)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/executions/1/submit", nil)
+	req.Header.Set("Idempotency-Key", "k1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if pending.Status != "pending" || time.Until(pending.ExpiresAt) > idempotencyPendingLease {
+		t.Fatalf("pending key = %+v", pending)
+	}
+}
+
+func TestIdempotency_RenewsLeaseWhileHandlerRuns(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer func(prev time.Duration) { idempotencyPendingLease = prev }(idempotencyPendingLease)
+	idempotencyPendingLease = 60 * time.Millisecond
+
+	repo := &idempotencyRepo{keys: map[string]*models.IdempotencyKey{}}
+	var mu sync.Mutex
+	calls := 0
+	started := make(chan struct{}, 1)
+	r := gin.New()
+	r.Use(Idempotency(repo, time.Hour, nil))
+	r.POST("/api/v2/executions/:id/submit", func(c *gin.Context) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		started <- struct{}{}
+		// Runs well past the lease.
+		time.Sleep(300 * time.Millisecond)
+		Ok(c, gin.H{}, nil)
+	})
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/executions/1/submit", nil)
+		req.Header.Set("Idempotency-Key", "k1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- send() }()
+	<-started
+	time.Sleep(200 * time.Millisecond)
+	if w := send(); w.Code != http.StatusConflict {
+		t.Fatalf("retry past the lease: code=%d", w.Code)
+	}
+	if w := <-done; w.Code != http.StatusOK {
+		t.Fatalf("first request: code=%d", w.Code)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 1 {
+		t.Fatalf("handler ran %d times", calls)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/paas"
 )
 
 type apiResponse struct {
@@ -11,6 +13,8 @@ type apiResponse struct {
 	Message string         `json:"message"`
 	Data    any            `json:"data,omitempty"`
 	Meta    map[string]any `json:"meta,omitempty"`
+	// RequestID is set on errors so callers can quote it when reporting.
+	RequestID string `json:"request_id,omitempty"`
 }
 
 func Ok(c *gin.Context, data any, meta map[string]any) {
@@ -24,8 +28,9 @@ func Ok(c *gin.Context, data any, meta map[string]any) {
 
 func Error(c *gin.Context, status int, message string, meta map[string]any) {
 	c.JSON(status, apiResponse{
-		Code:    status,
-		Message: message,
-		Meta:    meta,
+		Code:      status,
+		Message:   message,
+		Meta:      meta,
+		RequestID: paas.RequestIDFromGin(c),
 	})
 }
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// tenantScope returns the desk filter for list queries, or nil when the
+// request is unscoped.
+func tenantScope(c *gin.Context) *string {
+	tenant := paas.TenantFromGin(c)
+	if tenant == "" {
+		return nil
+	}
+	return &tenant
+}
+
+func tenantVisible(c *gin.Context, rowTenant string) bool {
+	tenant := paas.TenantFromGin(c)
+	return tenant == "" || strings.EqualFold(strings.TrimSpace(rowTenant), tenant)
+}
+
+// tenantGuard answers 404 for /:param routes whose row belongs to another
+// desk. Unscoped requests and missing rows fall through to the handler.
+func tenantGuard(param string, notFound string, lookup func(c *gin.Context) (string, bool, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.TrimSpace(c.Param(param)) == "" || paas.TenantFromGin(c) == "" {
+			c.Next()
+			return
+		}
+		rowTenant, ok, err := lookup(c)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			c.Abort()
+			return
+		}
+		if ok && !tenantVisible(c, rowTenant) {
+			Error(c, http.StatusNotFound, notFound, nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func (h *V2ExecutionHandler) planTenant(c *gin.Context) (string, bool, error) {
+	return planTenantByID(c, h.Repo, uint64QueryParam(c, "id"))
+}
+
+func (h *V2OpportunityHandler) opportunityTenant(c *gin.Context) (string, bool, error) {
+	if h.Repo == nil {
+		return "", false, nil
+	}
+	opp, err := h.Repo.GetOpportunityByID(c.Request.Context(), uint64QueryParam(c, "id"))
+	if err != nil || opp == nil {
+		return "", false, err
+	}
+	return opp.Tenant, true, nil
+}
+
+func (h *V2PositionHandler) positionTenant(c *gin.Context) (string, bool, error) {
+	if h.Repo == nil {
+		return "", false, nil
+	}
+	pos, err := h.Repo.GetPositionByID(c.Request.Context(), uint64QueryParam(c, "id"))
+	if err != nil || pos == nil {
+		return "", false, err
+	}
+	return pos.Tenant, true, nil
+}
+
+func (h *V2StrategyHandler) strategyTenant(c *gin.Context) (string, bool, error) {
+	return strategyTenantByName(c, h.Repo, c.Param("name"))
+}
+
+func (h *V2AnalyticsHandler) strategyTenant(c *gin.Context) (string, bool, error) {
+	return strategyTenantByName(c, h.Repo, c.Param("name"))
+}
+
+func (h *V2ExecutionRuleHandler) strategyTenant(c *gin.Context) (string, bool, error) {
+	return strategyTenantByName(c, h.Repo, c.Param("strategy"))
+}
+
+func strategyTenantByName(c *gin.Context, repo repository.Repository, name string) (string, bool, error) {
+	if repo == nil {
+		return "", false, nil
+	}
+	strat, err := repo.GetStrategyByName(c.Request.Context(), strings.TrimSpace(name))
+	if err != nil || strat == nil {
+		return "", false, err
+	}
+	return strat.Tenant, true, nil
+}
+
+// Orders and journal entries have no tenant column; they belong to the
+// desk of their plan.
+func (h *V2OrderHandler) orderTenant(c *gin.Context) (string, bool, error) {
+	if h.Repo == nil {
+		return "", false, nil
+	}
+	order, err := h.Repo.GetOrderByID(c.Request.Context(), uint64QueryParam(c, "id"))
+	if err != nil || order == nil {
+		return "", false, err
+	}
+	return planTenantByID(c, h.Repo, order.PlanID)
+}
+
+func (h *V2JournalHandler) journalTenant(c *gin.Context) (string, bool, error) {
+	return planTenantByID(c, h.Repo, uint64QueryParam(c, "execution_plan_id"))
+}
+
+func planTenantByID(c *gin.Context, repo repository.Repository, planID uint64) (string, bool, error) {
+	if repo == nil {
+		return "", false, nil
+	}
+	plan, err := repo.GetExecutionPlanByID(c.Request.Context(), planID)
+	if err != nil || plan == nil {
+		return "", false, err
+	}
+	return plan.Tenant, true, nil
+}
@@ -1,30 +1,144 @@
 package handler
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"polymarket/internal/paas"
 	"polymarket/internal/repository"
+	"polymarket/internal/service"
+	"polymarket/internal/tradingday"
 )
 
 type V2AnalyticsHandler struct {
-	Repo repository.Repository
+	Repo        repository.Repository
+	Calibration *service.CalibrationService
+	// Costs scores execution cost forecasts against realized fills.
+	Costs *service.CostForecaster
+	// Calendar labels daily rows; nil is UTC midnight.
+	Calendar *tradingday.Calendar
 }
 
 func (h *V2AnalyticsHandler) Register(r *gin.Engine) {
-	group := r.Group("/api/v2/analytics")
-	group.GET("/overview", h.overview)
+	group := r.Group("/api/v2/analytics", tenantGuard("name", "strategy not found", h.strategyTenant))
+	group.GET("/overview", validateQuery[asOfQuery](), h.overview)
 	group.GET("/by-strategy", h.byStrategy)
 	group.GET("/failures", h.failures)
-	group.GET("/daily", h.daily)
-	group.GET("/strategy/:name/daily", h.strategyDaily)
-	group.GET("/strategy/:name/attribution", h.attribution)
+	group.GET("/daily", validateQuery[dailyStatsQuery](), h.daily)
+	group.GET("/strategy/:name/daily", validateQuery[dailyStatsQuery](), h.strategyDaily)
+	group.GET("/strategy/:name/attribution", validateQuery[windowAsOfQuery](), h.attribution)
 	group.GET("/drawdown", h.drawdown)
-	group.GET("/correlation", h.correlation)
-	group.GET("/ratios", h.ratios)
+	group.GET("/correlation", validateQuery[timeRangeQuery](), h.correlation)
+	group.GET("/ratios", validateQuery[windowAsOfQuery](), h.ratios)
+	group.GET("/calibration", validateQuery[calibrationQuery](), h.calibration)
+	group.GET("/breakdowns", validateQuery[breakdownQuery](), h.breakdowns)
+	group.GET("/breakdowns/:dimension", validateQuery[breakdownQuery](), h.breakdown)
+	group.GET("/cost-forecast-accuracy", validateQuery[costForecastAccuracyQuery](), h.costForecastAccuracy)
+	group.GET("/edge-heatmap", validateQuery[edgeHeatmapQuery](), h.edgeHeatmap)
+}
+
+// asOfQuery selects point-in-time analytics with ?as_of=RFC3339.
+type asOfQuery struct {
+	AsOf *time.Time `form:"as_of"`
+}
+
+type windowAsOfQuery struct {
+	timeRangeQuery
+	asOfQuery
+}
+
+type dailyStatsQuery struct {
+	timeRangeQuery
+	Limit        int     `form:"limit" default:"365" binding:"min=1,max=3650"`
+	Offset       int     `form:"offset" default:"0" binding:"min=0"`
+	StrategyName *string `form:"strategy_name"`
+}
+
+type breakdownQuery struct {
+	timeRangeQuery
+	StrategyName *string `form:"strategy_name"`
+	BucketHours  int     `form:"bucket_hours" default:"4" binding:"min=1,max=12"`
+}
+
+type costForecastAccuracyQuery struct {
+	timeRangeQuery
+	StrategyName *string `form:"strategy_name"`
+}
+
+type edgeHeatmapQuery struct {
+	timeRangeQuery
+	StrategyName *string `form:"strategy_name"`
+	Label        *string `form:"label"`
+	// EdgeBounds and ROIBounds override the bucket boundaries, as fractions.
+	EdgeBounds []string `form:"edge_bounds"`
+	ROIBounds  []string `form:"roi_bounds"`
+}
+
+type calibrationQuery struct {
+	timeRangeQuery
+	Horizon string `form:"horizon"`
+}
+
+// breakdownDimensions maps the /breakdowns/:dimension path to its query.
+var breakdownDimensions = map[string]func(repository.Repository, *gin.Context, repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error){
+	"label": func(r repository.Repository, c *gin.Context, p repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+		return r.PnLBreakdownByLabel(c.Request.Context(), p)
+	},
+	"time-of-day": func(r repository.Repository, c *gin.Context, p repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+		return r.PnLBreakdownByEntryHour(c.Request.Context(), p)
+	},
+	"hold-duration": func(r repository.Repository, c *gin.Context, p repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+		return r.PnLBreakdownByHoldDuration(c.Request.Context(), p)
+	},
+}
+
+func breakdownParams(c *gin.Context) repository.PnLBreakdownParams {
+	q := queryOf[breakdownQuery](c)
+	return repository.PnLBreakdownParams{Since: q.Since, Until: q.Until, Tenant: tenantScope(c), StrategyName: q.StrategyName, BucketHours: q.BucketHours}
+}
+
+// breakdown pivots settled PnL and win rate by one dimension: label,
+// time-of-day (UTC entry hour) or hold-duration.
+func (h *V2AnalyticsHandler) breakdown(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	query, ok := breakdownDimensions[strings.TrimSpace(c.Param("dimension"))]
+	if !ok {
+		Error(c, http.StatusBadRequest, "dimension must be label, time-of-day or hold-duration", nil)
+		return
+	}
+	rows, err := query(h.Repo, c, breakdownParams(c))
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, rows, nil)
+}
+
+// breakdowns returns every dimension in one response.
+func (h *V2AnalyticsHandler) breakdowns(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	params := breakdownParams(c)
+	out := gin.H{}
+	for name, query := range breakdownDimensions {
+		rows, err := query(h.Repo, c, params)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		out[name] = rows
+	}
+	Ok(c, out, nil)
 }
 
 func (h *V2AnalyticsHandler) overview(c *gin.Context) {
@@ -32,12 +146,22 @@ func (h *V2AnalyticsHandler) overview(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	row, err := h.Repo.AnalyticsOverview(c.Request.Context())
+	asOf := queryOf[asOfQuery](c).AsOf
+	row, err := h.Repo.AnalyticsOverview(c.Request.Context(), asOf, paas.TenantFromGin(c))
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, row, nil)
+	meta := asOfMeta(asOf)
+	if asOf != nil && tenantScope(c) == nil {
+		// Portfolio state is only recoverable from the hourly snapshots,
+		// which cover every desk.
+		snaps, err := h.Repo.ListPortfolioSnapshots(c.Request.Context(), repository.ListPortfolioSnapshotsParams{Limit: 1, Until: asOf})
+		if err == nil && len(snaps) > 0 {
+			meta["portfolio_snapshot"] = snaps[0]
+		}
+	}
+	Ok(c, row, meta)
 }
 
 func (h *V2AnalyticsHandler) byStrategy(c *gin.Context) {
@@ -45,7 +169,7 @@ func (h *V2AnalyticsHandler) byStrategy(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	rows, err := h.Repo.AnalyticsByStrategy(c.Request.Context())
+	rows, err := h.Repo.AnalyticsByStrategy(c.Request.Context(), paas.TenantFromGin(c))
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
@@ -58,7 +182,7 @@ func (h *V2AnalyticsHandler) failures(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	rows, err := h.Repo.AnalyticsFailures(c.Request.Context())
+	rows, err := h.Repo.AnalyticsFailures(c.Request.Context(), paas.TenantFromGin(c))
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
@@ -71,25 +195,7 @@ func (h *V2AnalyticsHandler) daily(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	limit := intQuery(c, "limit", 365)
-	offset := intQuery(c, "offset", 0)
-	since, until := timeRangeFromQuery(c)
-	var strategyName *string
-	if v := strings.TrimSpace(c.Query("strategy_name")); v != "" {
-		strategyName = &v
-	}
-	rows, err := h.Repo.ListStrategyDailyStats(c.Request.Context(), repository.ListDailyStatsParams{
-		Limit:        limit,
-		Offset:       offset,
-		StrategyName: strategyName,
-		Since:        since,
-		Until:        until,
-	})
-	if err != nil {
-		Error(c, http.StatusBadGateway, err.Error(), nil)
-		return
-	}
-	Ok(c, rows, paginationMeta(limit, offset, int64(len(rows))))
+	h.listDaily(c, queryOf[dailyStatsQuery](c), nil)
 }
 
 func (h *V2AnalyticsHandler) strategyDaily(c *gin.Context) {
@@ -102,21 +208,32 @@ func (h *V2AnalyticsHandler) strategyDaily(c *gin.Context) {
 		Error(c, http.StatusBadRequest, "invalid strategy name", nil)
 		return
 	}
-	limit := intQuery(c, "limit", 365)
-	offset := intQuery(c, "offset", 0)
-	since, until := timeRangeFromQuery(c)
-	rows, err := h.Repo.ListStrategyDailyStats(c.Request.Context(), repository.ListDailyStatsParams{
-		Limit:        limit,
-		Offset:       offset,
-		StrategyName: &name,
-		Since:        since,
-		Until:        until,
-	})
+	h.listDaily(c, queryOf[dailyStatsQuery](c), &name)
+}
+
+// listDaily serves daily rows. since/until select the trading days that
+// contain them, and meta.trading_day names the boundary the rows use.
+func (h *V2AnalyticsHandler) listDaily(c *gin.Context, q *dailyStatsQuery, name *string) {
+	params := repository.ListDailyStatsParams{Limit: q.Limit, Offset: q.Offset, Tenant: tenantScope(c), StrategyName: q.StrategyName}
+	if name != nil {
+		params.StrategyName = name
+	}
+	if q.Since != nil {
+		d := h.Calendar.Date(*q.Since)
+		params.Since = &d
+	}
+	if q.Until != nil {
+		d := h.Calendar.Date(*q.Until)
+		params.Until = &d
+	}
+	rows, err := h.Repo.ListStrategyDailyStats(c.Request.Context(), params)
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, rows, paginationMeta(limit, offset, int64(len(rows))))
+	meta := paginationMeta(q.Limit, q.Offset, int64(len(rows)))
+	meta["trading_day"] = h.Calendar.String()
+	Ok(c, rows, meta)
 }
 
 func (h *V2AnalyticsHandler) attribution(c *gin.Context) {
@@ -129,13 +246,13 @@ func (h *V2AnalyticsHandler) attribution(c *gin.Context) {
 		Error(c, http.StatusBadRequest, "invalid strategy name", nil)
 		return
 	}
-	since, until := timeRangeFromQuery(c)
-	row, err := h.Repo.AttributionByStrategy(c.Request.Context(), name, since, until)
+	q := queryOf[windowAsOfQuery](c)
+	row, err := h.Repo.AttributionByStrategy(c.Request.Context(), name, q.Since, q.Until, q.AsOf)
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, row, nil)
+	Ok(c, row, asOfMeta(q.AsOf))
 }
 
 func (h *V2AnalyticsHandler) drawdown(c *gin.Context) {
@@ -143,7 +260,7 @@ func (h *V2AnalyticsHandler) drawdown(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	row, err := h.Repo.PortfolioDrawdown(c.Request.Context())
+	row, err := h.Repo.PortfolioDrawdown(c.Request.Context(), paas.TenantFromGin(c))
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
@@ -156,8 +273,8 @@ func (h *V2AnalyticsHandler) correlation(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	since, until := timeRangeFromQuery(c)
-	rows, err := h.Repo.StrategyCorrelation(c.Request.Context(), since, until)
+	q := queryOf[timeRangeQuery](c)
+	rows, err := h.Repo.StrategyCorrelation(c.Request.Context(), q.Since, q.Until, paas.TenantFromGin(c))
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
@@ -170,29 +287,178 @@ func (h *V2AnalyticsHandler) ratios(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	since, until := timeRangeFromQuery(c)
-	row, err := h.Repo.PerformanceRatios(c.Request.Context(), since, until)
+	q := queryOf[windowAsOfQuery](c)
+	row, err := h.Repo.PerformanceRatios(c.Request.Context(), q.Since, q.Until, q.AsOf, paas.TenantFromGin(c))
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, row, nil)
+	Ok(c, row, asOfMeta(q.AsOf))
+}
+
+// calibration returns reliability diagram data. Without a time range the
+// cached model is used; a range triggers a one-off fit.
+func (h *V2AnalyticsHandler) calibration(c *gin.Context) {
+	if h.Calibration == nil {
+		Error(c, http.StatusInternalServerError, "calibration unavailable", nil)
+		return
+	}
+	q := queryOf[calibrationQuery](c)
+	var (
+		model *service.CalibrationModel
+		err   error
+	)
+	if q.Since != nil || q.Until != nil {
+		model, err = h.Calibration.Fit(c.Request.Context(), q.Since, q.Until)
+	} else {
+		model, err = h.Calibration.Model(c.Request.Context())
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, map[string]any{
+		"samples":     model.Samples,
+		"brier_score": model.BrierScore,
+		"fitted_at":   model.FittedAt,
+		"bins":        model.BinsForHorizon(q.Horizon),
+	}, nil)
+}
+
+// costForecastAccuracy compares forecast execution costs of filled plans
+// with their realized fees and slippage, overall and per strategy.
+func (h *V2AnalyticsHandler) costForecastAccuracy(c *gin.Context) {
+	if h.Costs == nil {
+		Error(c, http.StatusInternalServerError, "cost forecaster unavailable", nil)
+		return
+	}
+	q := queryOf[costForecastAccuracyQuery](c)
+	rows, err := h.Costs.Accuracy(c.Request.Context(), repository.CostForecastOutcomeParams{
+		Since:        q.Since,
+		Until:        q.Until,
+		Tenant:       tenantScope(c),
+		StrategyName: q.StrategyName,
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, rows, nil)
+}
+
+// Default edge heatmap buckets: expected edge up to 10% and realized ROI
+// from a 10% loss to a 10% gain.
+var (
+	defaultEdgeHeatmapEdgeBounds = []float64{0.01, 0.02, 0.05, 0.10}
+	defaultEdgeHeatmapROIBounds  = []float64{-0.10, -0.02, 0, 0.02, 0.05, 0.10}
+)
+
+// edgeHeatmapGroup is one strategy and label of the edge heatmap. Counts
+// is indexed [edge bucket][ROI bucket].
+type edgeHeatmapGroup struct {
+	StrategyName string                       `json:"strategy_name"`
+	Label        string                       `json:"label"`
+	Trades       int64                        `json:"trades"`
+	RealizedUSD  float64                      `json:"realized_usd"`
+	Counts       [][]int64                    `json:"counts"`
+	Cells        []repository.EdgeHeatmapCell `json:"cells"`
 }
 
-func timeRangeFromQuery(c *gin.Context) (*time.Time, *time.Time) {
-	var since *time.Time
-	var until *time.Time
-	if raw := strings.TrimSpace(c.Query("since")); raw != "" {
-		if ts, err := time.Parse(time.RFC3339, raw); err == nil {
-			t := ts.UTC()
-			since = &t
+// edgeHeatmap pivots settled trades into expected edge by realized ROI
+// matrices, one per strategy and market label, for calibration heatmaps.
+func (h *V2AnalyticsHandler) edgeHeatmap(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[edgeHeatmapQuery](c)
+	edgeBounds, ok := heatmapBounds(q.EdgeBounds, defaultEdgeHeatmapEdgeBounds)
+	if !ok {
+		Error(c, http.StatusBadRequest, "edge_bounds must be ascending numbers", nil)
+		return
+	}
+	roiBounds, ok := heatmapBounds(q.ROIBounds, defaultEdgeHeatmapROIBounds)
+	if !ok {
+		Error(c, http.StatusBadRequest, "roi_bounds must be ascending numbers", nil)
+		return
+	}
+	cells, err := h.Repo.EdgeRealizationHeatmap(c.Request.Context(), repository.EdgeHeatmapParams{
+		Since:        q.Since,
+		Until:        q.Until,
+		Tenant:       tenantScope(c),
+		StrategyName: q.StrategyName,
+		Label:        q.Label,
+		EdgeBounds:   edgeBounds,
+		ROIBounds:    roiBounds,
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	groups := make([]*edgeHeatmapGroup, 0)
+	byKey := map[string]*edgeHeatmapGroup{}
+	for _, cell := range cells {
+		key := cell.StrategyName + "\x00" + cell.Label
+		g, ok := byKey[key]
+		if !ok {
+			g = &edgeHeatmapGroup{StrategyName: cell.StrategyName, Label: cell.Label, Counts: make([][]int64, len(edgeBounds)+1)}
+			for i := range g.Counts {
+				g.Counts[i] = make([]int64, len(roiBounds)+1)
+			}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		if cell.EdgeBucket >= 0 && cell.EdgeBucket <= len(edgeBounds) && cell.ROIBucket >= 0 && cell.ROIBucket <= len(roiBounds) {
+			g.Counts[cell.EdgeBucket][cell.ROIBucket] += cell.Trades
 		}
+		g.Trades += cell.Trades
+		g.RealizedUSD += cell.RealizedUSD
+		g.Cells = append(g.Cells, cell)
 	}
-	if raw := strings.TrimSpace(c.Query("until")); raw != "" {
-		if ts, err := time.Parse(time.RFC3339, raw); err == nil {
-			t := ts.UTC()
-			until = &t
+	Ok(c, gin.H{
+		"edge_buckets": bucketLabels(edgeBounds),
+		"roi_buckets":  bucketLabels(roiBounds),
+		"groups":       groups,
+	}, nil)
+}
+
+// heatmapBounds parses ascending bucket boundaries, falling back to def when
+// none are given.
+func heatmapBounds(raw []string, def []float64) ([]float64, bool) {
+	if len(raw) == 0 {
+		return def, true
+	}
+	out := make([]float64, 0, len(raw))
+	for _, r := range raw {
+		v, err := strconv.ParseFloat(strings.TrimSpace(r), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) || (len(out) > 0 && v <= out[len(out)-1]) {
+			return nil, false
 		}
+		out = append(out, v)
+	}
+	return out, true
+}
+
+// bucketLabels names the buckets bounds make as percentages, e.g. "<1%",
+// "1%-2%" and ">=2%".
+func bucketLabels(bounds []float64) []string {
+	pct := func(v float64) string {
+		return strconv.FormatFloat(math.Round(v*1e8)/1e6, 'f', -1, 64) + "%"
+	}
+	if len(bounds) == 0 {
+		return []string{"all"}
+	}
+	out := make([]string, 0, len(bounds)+1)
+	out = append(out, "<"+pct(bounds[0]))
+	for i := 1; i < len(bounds); i++ {
+		out = append(out, pct(bounds[i-1])+"-"+pct(bounds[i]))
+	}
+	return append(out, ">="+pct(bounds[len(bounds)-1]))
+}
+
+func asOfMeta(asOf *time.Time) map[string]any {
+	if asOf == nil {
+		return nil
 	}
-	return since, until
+	return map[string]any{"as_of": asOf.Format(time.RFC3339)}
 }
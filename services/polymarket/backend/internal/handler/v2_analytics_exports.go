@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2AnalyticsExportHandler manages export jobs for sharing performance data
+// outside, each with its own masking.
+type V2AnalyticsExportHandler struct {
+	Repo    repository.Repository
+	Exports *service.AnalyticsExportService
+	// Outbox records the audit logs of export runs.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2AnalyticsExportHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/analytics/exports")
+	group.GET("", validateQuery[pageQuery](), h.list)
+	group.POST("", h.create)
+	group.GET("/:id", h.get)
+	group.DELETE("/:id", h.delete)
+	group.POST("/:id/run", h.run)
+}
+
+type analyticsExportRequest struct {
+	Name         string                `json:"name"`
+	Dataset      string                `json:"dataset"`
+	StrategyName *string               `json:"strategy_name"`
+	Since        *time.Time            `json:"since"`
+	Until        *time.Time            `json:"until"`
+	Masking      service.ExportMasking `json:"masking"`
+}
+
+func (h *V2AnalyticsExportHandler) list(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[pageQuery](c)
+	params := repository.ListAnalyticsExportsParams{Limit: q.Limit, Offset: q.Offset, Tenant: tenantScope(c)}
+	items, err := h.Repo.ListAnalyticsExports(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountAnalyticsExports(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
+func (h *V2AnalyticsExportHandler) create(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	var req analyticsExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		Error(c, http.StatusBadRequest, "name required", nil)
+		return
+	}
+	dataset := strings.ToLower(strings.TrimSpace(req.Dataset))
+	if dataset == "" {
+		dataset = models.AnalyticsExportTrades
+	}
+	if dataset != models.AnalyticsExportTrades && dataset != models.AnalyticsExportDaily {
+		Error(c, http.StatusBadRequest, "dataset must be trades or daily", nil)
+		return
+	}
+	if req.Since != nil && req.Until != nil && req.Until.Before(*req.Since) {
+		Error(c, http.StatusBadRequest, "until before since", nil)
+		return
+	}
+	if req.Masking.MinMarketVolumeUSD < 0 {
+		Error(c, http.StatusBadRequest, "min_market_volume_usd must not be negative", nil)
+		return
+	}
+	masking, _ := json.Marshal(req.Masking)
+	item := &models.AnalyticsExport{
+		Name:         name,
+		Tenant:       paas.TenantOrDefault(c.Request.Context()),
+		Dataset:      dataset,
+		StrategyName: req.StrategyName,
+		Since:        req.Since,
+		Until:        req.Until,
+		Masking:      datatypes.JSON(masking),
+	}
+	if err := h.Repo.InsertAnalyticsExport(c.Request.Context(), item); err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, item, nil)
+}
+
+func (h *V2AnalyticsExportHandler) load(c *gin.Context) *models.AnalyticsExport {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return nil
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return nil
+	}
+	item, err := h.Repo.GetAnalyticsExportByID(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return nil
+	}
+	if item == nil || !tenantVisible(c, item.Tenant) {
+		Error(c, http.StatusNotFound, "export not found", nil)
+		return nil
+	}
+	return item
+}
+
+func (h *V2AnalyticsExportHandler) get(c *gin.Context) {
+	if item := h.load(c); item != nil {
+		Ok(c, item, nil)
+	}
+}
+
+func (h *V2AnalyticsExportHandler) delete(c *gin.Context) {
+	item := h.load(c)
+	if item == nil {
+		return
+	}
+	if err := h.Repo.DeleteAnalyticsExport(c.Request.Context(), item.ID); err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, map[string]any{"deleted": item.ID}, nil)
+}
+
+// run generates the export. Each run is logged with its watermark so a
+// shared file can be matched to who ran it and when.
+func (h *V2AnalyticsExportHandler) run(c *gin.Context) {
+	if h.Exports == nil {
+		Error(c, http.StatusInternalServerError, "exports unavailable", nil)
+		return
+	}
+	item := h.load(c)
+	if item == nil {
+		return
+	}
+	res, err := h.Exports.Run(c.Request.Context(), *item, time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_analytics_export_run", "info", map[string]any{
+		"export_id": item.ID,
+		"name":      item.Name,
+		"dataset":   item.Dataset,
+		"watermark": res.Watermark,
+	})
+	Ok(c, res, nil)
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// asOfRepo records the as_of and tenant analytics are asked for.
+type asOfRepo struct {
+	repository.Repository
+	asOf      *time.Time
+	tenant    string
+	snapUntil *time.Time
+}
+
+func (r *asOfRepo) AnalyticsOverview(_ context.Context, asOf *time.Time, tenant string) (repository.AnalyticsOverview, error) {
+	r.asOf, r.tenant = asOf, tenant
+	return repository.AnalyticsOverview{TotalPlans: 2}, nil
+}
+
+func (r *asOfRepo) ListPortfolioSnapshots(_ context.Context, params repository.ListPortfolioSnapshotsParams) ([]models.PortfolioSnapshot, error) {
+	r.snapUntil = params.Until
+	return []models.PortfolioSnapshot{{ID: 7, SnapshotAt: params.Until.Add(-30 * time.Minute)}}, nil
+}
+
+func TestAnalyticsOverviewAsOf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name     string
+		asOf     string
+		tenant   string
+		status   int
+		wantAsOf string
+		snapshot bool
+	}{
+		{name: "now", status: http.StatusOK},
+		{name: "as of, unscoped", asOf: "2026-03-04T13:06:07+08:00", status: http.StatusOK, wantAsOf: "2026-03-04T05:06:07Z", snapshot: true},
+		// Snapshots cover every desk, so a scoped request does not get one.
+		{name: "as of, scoped", asOf: "2026-03-04T05:06:07Z", tenant: "desk-a", status: http.StatusOK, wantAsOf: "2026-03-04T05:06:07Z"},
+		{name: "malformed", asOf: "yesterday", status: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &asOfRepo{}
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tc.tenant != "" {
+					c.Request = c.Request.WithContext(paas.WithTenant(c.Request.Context(), tc.tenant))
+				}
+			})
+			(&V2AnalyticsHandler{Repo: repo}).Register(r)
+			target := "/api/v2/analytics/overview"
+			if tc.asOf != "" {
+				target += "?as_of=" + url.QueryEscape(tc.asOf)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+			if w.Code != tc.status {
+				t.Fatalf("status = %d body = %s", w.Code, w.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			var out struct {
+				Meta map[string]any `json:"meta"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+				t.Fatal(err)
+			}
+			gotAsOf := ""
+			if repo.asOf != nil {
+				gotAsOf = repo.asOf.UTC().Format(time.RFC3339)
+			}
+			if gotAsOf != tc.wantAsOf || repo.tenant != tc.tenant {
+				t.Fatalf("repo got as_of=%q tenant=%q", gotAsOf, repo.tenant)
+			}
+			if tc.wantAsOf != "" && out.Meta["as_of"] != tc.wantAsOf {
+				t.Fatalf("meta = %v", out.Meta)
+			}
+			if _, ok := out.Meta["portfolio_snapshot"]; ok != tc.snapshot {
+				t.Fatalf("portfolio snapshot in meta = %v, want %v", ok, tc.snapshot)
+			}
+		})
+	}
+}
+
+// breakdownRepo records the breakdown dimension and params it is asked for.
+type breakdownRepo struct {
+	repository.Repository
+	dimension string
+	params    repository.PnLBreakdownParams
+}
+
+func (r *breakdownRepo) record(dimension string, params repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+	r.dimension, r.params = dimension, params
+	return []repository.PnLBreakdownRow{{Bucket: dimension, Trades: 1}}, nil
+}
+
+func (r *breakdownRepo) PnLBreakdownByLabel(_ context.Context, p repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+	return r.record("label", p)
+}
+
+func (r *breakdownRepo) PnLBreakdownByEntryHour(_ context.Context, p repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+	return r.record("time-of-day", p)
+}
+
+func (r *breakdownRepo) PnLBreakdownByHoldDuration(_ context.Context, p repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+	return r.record("hold-duration", p)
+}
+
+func TestAnalyticsBreakdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name        string
+		target      string
+		tenant      string
+		status      int
+		dimension   string
+		bucketHours int
+	}{
+		{name: "label", target: "/label", status: http.StatusOK, dimension: "label", bucketHours: 4},
+		{name: "time of day", target: "/time-of-day?bucket_hours=6&strategy_name=arb", tenant: "desk-a", status: http.StatusOK, dimension: "time-of-day", bucketHours: 6},
+		{name: "hold duration", target: "/hold-duration", status: http.StatusOK, dimension: "hold-duration", bucketHours: 4},
+		{name: "unknown dimension", target: "/weekday", status: http.StatusBadRequest},
+		// Entry hour buckets are at most half a day wide.
+		{name: "bucket too wide", target: "/time-of-day?bucket_hours=24", status: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &breakdownRepo{}
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tc.tenant != "" {
+					c.Request = c.Request.WithContext(paas.WithTenant(c.Request.Context(), tc.tenant))
+				}
+			})
+			(&V2AnalyticsHandler{Repo: repo}).Register(r)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/analytics/breakdowns"+tc.target, nil))
+			if w.Code != tc.status {
+				t.Fatalf("status = %d body = %s", w.Code, w.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				if repo.dimension != "" {
+					t.Fatalf("repo queried for %q", repo.dimension)
+				}
+				return
+			}
+			if repo.dimension != tc.dimension || repo.params.BucketHours != tc.bucketHours {
+				t.Fatalf("repo got dimension=%q params=%+v", repo.dimension, repo.params)
+			}
+			if got := repo.params.Tenant; (got == nil) != (tc.tenant == "") || (got != nil && *got != tc.tenant) {
+				t.Fatalf("tenant = %v, want %q", got, tc.tenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+type V2AuditHandler struct {
+	Repo  repository.Repository
+	Audit *service.AuditChainService
+}
+
+func (h *V2AuditHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/audit")
+	group.GET("/export", validateQuery[auditExportQuery](), h.export)
+	group.GET("/verify", h.verify)
+}
+
+type auditExportQuery struct {
+	timeRangeQuery
+	AfterSeq uint64 `form:"after_seq"`
+	Limit    int    `form:"limit" default:"1000" binding:"min=1,max=10000"`
+}
+
+// export pages the audit chain in seq order. Clients resume with
+// after_seq=meta.next_after_seq and verify the hashes themselves.
+func (h *V2AuditHandler) export(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[auditExportQuery](c)
+	afterSeq := q.AfterSeq
+	items, err := h.Repo.ListAuditRecords(c.Request.Context(), repository.ListAuditRecordsParams{
+		Limit:    q.Limit,
+		AfterSeq: afterSeq,
+		Since:    q.Since,
+		Until:    q.Until,
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	next := afterSeq
+	if len(items) > 0 {
+		next = items[len(items)-1].Seq
+	}
+	Ok(c, items, map[string]any{
+		"after_seq":      afterSeq,
+		"next_after_seq": next,
+		"count":          len(items),
+	})
+}
+
+func (h *V2AuditHandler) verify(c *gin.Context) {
+	if h.Audit == nil {
+		Error(c, http.StatusInternalServerError, "audit unavailable", nil)
+		return
+	}
+	res, err := h.Audit.Verify(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, res, nil)
+}
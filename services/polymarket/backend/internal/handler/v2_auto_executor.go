@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/service"
+)
+
+type V2AutoExecutorHandler struct {
+	Auto *service.AutoExecutorService
+}
+
+func (h *V2AutoExecutorHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/auto-executor")
+	group.GET("/queue", validateQuery[autoQueueQuery](), h.queue)
+}
+
+type autoQueueQuery struct {
+	Limit int `form:"limit" default:"100" binding:"min=1,max=1000"`
+}
+
+// queue shows the auto executor's processing order as of its last scan and
+// the pending plans it recently preempted. Scoped requests see their desk.
+func (h *V2AutoExecutorHandler) queue(c *gin.Context) {
+	if h.Auto == nil {
+		Error(c, http.StatusInternalServerError, "auto executor unavailable", nil)
+		return
+	}
+	q := queryOf[autoQueueQuery](c)
+	out := h.Auto.Queue()
+	if tenantScope(c) != nil {
+		items := out.Items[:0]
+		for _, it := range out.Items {
+			if tenantVisible(c, it.Tenant) {
+				items = append(items, it)
+			}
+		}
+		out.Items = items
+		// Preemptions name plans, not desks; scoped tokens don't see them.
+		out.RecentPreemptions = []service.AutoPreemption{}
+	}
+	total := len(out.Items)
+	starved := 0
+	for _, it := range out.Items {
+		if it.Starved {
+			starved++
+		}
+	}
+	if len(out.Items) > q.Limit {
+		out.Items = out.Items[:q.Limit]
+	}
+	Ok(c, out, map[string]any{"total": total, "starved": starved})
+}
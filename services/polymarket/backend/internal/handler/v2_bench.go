@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/bench"
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/governor"
+)
+
+// V2BenchHandler runs the throughput suite on demand. Each run seeds its own
+// in-memory database, so it measures this build on this host without
+// touching service data; it still competes for CPU and is admitted as an
+// admin job. The result is compared with the configured baseline, if any.
+type V2BenchHandler struct {
+	Config   config.BenchConfig
+	Governor *governor.Governor
+
+	mu   sync.Mutex
+	last *benchResponse
+}
+
+func (h *V2BenchHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/system/bench")
+	group.GET("", h.lastRun)
+	group.POST("", heavyJob(h.Governor, governor.ClassAdmin), h.run)
+}
+
+type benchRequest struct {
+	Scenarios    []string `json:"scenarios"`
+	Iterations   int      `json:"iterations"`
+	Concurrency  int      `json:"concurrency"`
+	Markets      int      `json:"markets"`
+	Seed         int64    `json:"seed"`
+	ThresholdPct float64  `json:"threshold_pct"`
+}
+
+type benchResponse struct {
+	Report     bench.Report      `json:"report"`
+	Baseline   string            `json:"baseline,omitempty"`
+	Comparison *bench.Comparison `json:"comparison,omitempty"`
+}
+
+func (h *V2BenchHandler) lastRun(c *gin.Context) {
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "benchmarks require an unscoped token", nil)
+		return
+	}
+	h.mu.Lock()
+	last := h.last
+	h.mu.Unlock()
+	if last == nil {
+		Error(c, http.StatusNotFound, "no benchmark run yet", nil)
+		return
+	}
+	Ok(c, last, nil)
+}
+
+func (h *V2BenchHandler) run(c *gin.Context) {
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "benchmarks require an unscoped token", nil)
+		return
+	}
+	var req benchRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			Error(c, http.StatusBadRequest, "invalid body", nil)
+			return
+		}
+	}
+	for _, name := range req.Scenarios {
+		if _, ok := bench.Find(strings.TrimSpace(name)); !ok {
+			Error(c, http.StatusBadRequest, "unknown scenario", map[string]any{"scenario": name, "available": bench.Names()})
+			return
+		}
+	}
+	if req.Iterations < 0 || req.Iterations > 20000 || req.Markets < 0 || req.Markets > 5000 || req.Concurrency < 0 || req.Concurrency > 64 {
+		Error(c, http.StatusBadRequest, "iterations, markets or concurrency out of range", nil)
+		return
+	}
+	opts := bench.Options{
+		Scenarios:   req.Scenarios,
+		Iterations:  req.Iterations,
+		Concurrency: req.Concurrency,
+		Markets:     req.Markets,
+		Seed:        req.Seed,
+	}
+	if opts.Iterations == 0 {
+		opts.Iterations = h.Config.Iterations
+	}
+	if opts.Markets == 0 {
+		opts.Markets = h.Config.Markets
+	}
+	conn, err := bench.OpenMemory()
+	if err != nil {
+		Error(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	defer db.Close(conn)
+	rep, err := bench.Run(c.Request.Context(), conn.Gorm, opts)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	out := &benchResponse{Report: rep}
+	if path := strings.TrimSpace(h.Config.BaselinePath); path != "" {
+		base, err := bench.ReadReport(path)
+		switch {
+		case err == nil:
+			threshold := req.ThresholdPct
+			if threshold <= 0 {
+				threshold = h.Config.ThresholdPct
+			}
+			cmp := bench.Compare(base, rep, threshold)
+			out.Baseline = path
+			out.Comparison = &cmp
+		case !os.IsNotExist(err):
+			Error(c, http.StatusInternalServerError, "read baseline: "+err.Error(), nil)
+			return
+		}
+	}
+	h.mu.Lock()
+	h.last = out
+	h.mu.Unlock()
+	Ok(c, out, nil)
+}
@@ -0,0 +1,444 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2CampaignHandler manages campaigns: CRUD, plan and opportunity
+// membership, manual halt/resume and the aggregate report. Budget and loss
+// limits are enforced by service.CampaignService.
+type V2CampaignHandler struct {
+	Repo      repository.Repository
+	Campaigns *service.CampaignService
+	// Outbox records the audit logs of campaign writes with them.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2CampaignHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/campaigns")
+	group.GET("", validateQuery[listCampaignsQuery](), h.list)
+	group.POST("", h.create)
+	group.GET("/:id", h.get)
+	group.PUT("/:id", h.update)
+	group.DELETE("/:id", h.remove)
+	group.GET("/:id/plans", validateQuery[pageQuery](), h.plans)
+	group.POST("/:id/plans", h.assignPlans)
+	group.POST("/:id/opportunities", h.assignOpportunities)
+	group.POST("/:id/halt", h.halt)
+	group.POST("/:id/resume", h.resume)
+}
+
+type listCampaignsQuery struct {
+	pageQuery
+	Status *string `form:"status" binding:"omitempty,oneof=active halted ended"`
+}
+
+type campaignRequest struct {
+	Name        *string    `json:"name"`
+	Description *string    `json:"description"`
+	BudgetUSD   *float64   `json:"budget_usd"`
+	MaxLossUSD  *float64   `json:"max_loss_usd"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+}
+
+type campaignMembersRequest struct {
+	IDs []uint64 `json:"ids"`
+	// Remove takes the rows out of this campaign instead.
+	Remove bool `json:"remove"`
+}
+
+func (h *V2CampaignHandler) list(c *gin.Context) {
+	if h.Repo == nil || h.Campaigns == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[listCampaignsQuery](c)
+	params := repository.ListCampaignsParams{Limit: q.Limit, Offset: q.Offset, Tenant: tenantScope(c), Status: q.Status}
+	items, err := h.Repo.ListCampaigns(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountCampaigns(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	out := make([]service.CampaignReport, 0, len(items))
+	for _, item := range items {
+		rep, err := h.Campaigns.Report(c.Request.Context(), item)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		out = append(out, rep)
+	}
+	Ok(c, out, paginationMeta(q.Limit, q.Offset, total))
+}
+
+func (h *V2CampaignHandler) get(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	rep, err := h.Campaigns.Report(c.Request.Context(), *item)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, rep, nil)
+}
+
+func (h *V2CampaignHandler) create(c *gin.Context) {
+	if h.Repo == nil || h.Campaigns == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	var req campaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	item := &models.Campaign{Status: models.CampaignStatusActive, Tenant: paas.TenantOrDefault(c.Request.Context())}
+	if msg := applyCampaignRequest(item, req); msg != "" {
+		Error(c, http.StatusBadRequest, msg, nil)
+		return
+	}
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).InsertCampaign(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_campaign_created", "info", map[string]any{
+			"campaign_id":  item.ID,
+			"name":         item.Name,
+			"budget_usd":   item.BudgetUSD.String(),
+			"max_loss_usd": item.MaxLossUSD.String(),
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, item, nil)
+}
+
+func (h *V2CampaignHandler) update(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var req campaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if msg := applyCampaignRequest(item, req); msg != "" {
+		Error(c, http.StatusBadRequest, msg, nil)
+		return
+	}
+	// New limits or dates may lift or trigger a halt.
+	rep, err := h.Campaigns.Enforce(c.Request.Context(), item, time.Now().UTC())
+	if err == nil {
+		err = h.save(c, item, "polymarket_campaign_updated", "info", map[string]any{
+			"campaign_id":  item.ID,
+			"budget_usd":   item.BudgetUSD.String(),
+			"max_loss_usd": item.MaxLossUSD.String(),
+			"status":       item.Status,
+		})
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	rep.Campaign = *item
+	Ok(c, rep, nil)
+}
+
+// remove ends a campaign. Rows keep their campaign_id for reporting.
+func (h *V2CampaignHandler) remove(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	item.Status = models.CampaignStatusEnded
+	item.HaltReason = ""
+	item.HaltedAt = nil
+	if err := h.save(c, item, "polymarket_campaign_ended", "info", map[string]any{"campaign_id": item.ID}); err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, item, nil)
+}
+
+func (h *V2CampaignHandler) plans(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	q := queryOf[pageQuery](c)
+	params := repository.ListExecutionPlansParams{Limit: q.Limit, Offset: q.Offset, CampaignID: &item.ID}
+	plans, err := h.Repo.ListExecutionPlans(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountExecutionPlans(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, plans, paginationMeta(q.Limit, q.Offset, total))
+}
+
+// assignPlans adds existing plans to the campaign. Plans are checked against
+// the remaining budget together; removing plans is always allowed.
+func (h *V2CampaignHandler) assignPlans(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var req campaignMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.IDs) == 0 {
+		Error(c, http.StatusBadRequest, "ids required", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	size := decimal.Zero
+	for _, id := range req.IDs {
+		plan, err := h.Repo.GetExecutionPlanByID(ctx, id)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		if plan == nil || !strings.EqualFold(plan.Tenant, item.Tenant) {
+			Error(c, http.StatusBadRequest, "plan not found in campaign tenant", map[string]any{"plan_id": id})
+			return
+		}
+		if req.Remove && (plan.CampaignID == nil || *plan.CampaignID != item.ID) {
+			Error(c, http.StatusBadRequest, "plan not in campaign", map[string]any{"plan_id": id})
+			return
+		}
+		if !req.Remove && plan.CampaignID != nil && *plan.CampaignID != item.ID {
+			Error(c, http.StatusConflict, "plan belongs to another campaign", map[string]any{"plan_id": id, "campaign_id": *plan.CampaignID})
+			return
+		}
+		if !req.Remove && plan.CampaignID == nil {
+			size = size.Add(plan.PlannedSizeUSD)
+		}
+	}
+	target := &item.ID
+	if req.Remove {
+		target = nil
+	} else if _, err := h.Campaigns.Admit(ctx, item.ID, "", size, time.Now().UTC()); err != nil {
+		campaignError(c, err)
+		return
+	}
+	var n int64
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		var err error
+		if n, err = h.Repo.WithTx(tx).AssignPlansToCampaign(ctx, target, req.IDs); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_campaign_plans_assigned", "info", map[string]any{
+			"campaign_id": item.ID,
+			"plan_ids":    req.IDs,
+			"removed":     req.Remove,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, map[string]any{"campaign_id": item.ID, "updated": n}, nil)
+}
+
+// assignOpportunities tags opportunities so the plans made from them join
+// the campaign.
+func (h *V2CampaignHandler) assignOpportunities(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var req campaignMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.IDs) == 0 {
+		Error(c, http.StatusBadRequest, "ids required", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	for _, id := range req.IDs {
+		opp, err := h.Repo.GetOpportunityByID(ctx, id)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		if opp == nil || !strings.EqualFold(opp.Tenant, item.Tenant) {
+			Error(c, http.StatusBadRequest, "opportunity not found in campaign tenant", map[string]any{"opportunity_id": id})
+			return
+		}
+	}
+	target := &item.ID
+	if req.Remove {
+		target = nil
+	}
+	var n int64
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		var err error
+		if n, err = h.Repo.WithTx(tx).AssignOpportunitiesToCampaign(ctx, target, req.IDs); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_campaign_opportunities_assigned", "info", map[string]any{
+			"campaign_id":     item.ID,
+			"opportunity_ids": req.IDs,
+			"removed":         req.Remove,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, map[string]any{"campaign_id": item.ID, "updated": n}, nil)
+}
+
+func (h *V2CampaignHandler) halt(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if item.Status == models.CampaignStatusEnded {
+		Error(c, http.StatusConflict, "campaign ended", nil)
+		return
+	}
+	now := time.Now().UTC()
+	item.Status = models.CampaignStatusHalted
+	item.HaltReason = service.CampaignReasonManual
+	item.HaltedAt = &now
+	if err := h.save(c, item, "polymarket_campaign_halted", "warn", map[string]any{"campaign_id": item.ID, "reason": item.HaltReason}); err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, item, nil)
+}
+
+// resume clears a manual or loss halt. The limits are re-applied at once,
+// so a campaign still over its loss limit needs a higher limit first.
+func (h *V2CampaignHandler) resume(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if item.Status != models.CampaignStatusHalted {
+		Error(c, http.StatusConflict, "campaign not halted", map[string]any{"status": item.Status})
+		return
+	}
+	item.Status = models.CampaignStatusActive
+	item.HaltReason = ""
+	item.HaltedAt = nil
+	rep, err := h.Campaigns.Enforce(c.Request.Context(), item, time.Now().UTC())
+	if err == nil {
+		err = h.save(c, item, "polymarket_campaign_resumed", "info", map[string]any{"campaign_id": item.ID, "status": item.Status})
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	rep.Campaign = *item
+	Ok(c, rep, nil)
+}
+
+// save updates item and records action with it.
+func (h *V2CampaignHandler) save(c *gin.Context, item *models.Campaign, action, level string, details map[string]any) error {
+	return writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).UpdateCampaign(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, action, level, details), nil
+	})
+}
+
+func (h *V2CampaignHandler) load(c *gin.Context) (*models.Campaign, bool) {
+	if h.Repo == nil || h.Campaigns == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return nil, false
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return nil, false
+	}
+	item, err := h.Repo.GetCampaignByID(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return nil, false
+	}
+	if item == nil || !tenantVisible(c, item.Tenant) {
+		Error(c, http.StatusNotFound, "campaign not found", nil)
+		return nil, false
+	}
+	return item, true
+}
+
+func applyCampaignRequest(item *models.Campaign, req campaignRequest) string {
+	if req.Name != nil {
+		item.Name = strings.TrimSpace(*req.Name)
+	}
+	if item.Name == "" {
+		return "name required"
+	}
+	if req.Description != nil {
+		item.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.BudgetUSD != nil {
+		if *req.BudgetUSD < 0 {
+			return "budget_usd must be >= 0"
+		}
+		item.BudgetUSD = decimal.NewFromFloat(*req.BudgetUSD)
+	}
+	if req.MaxLossUSD != nil {
+		if *req.MaxLossUSD < 0 {
+			return "max_loss_usd must be >= 0"
+		}
+		item.MaxLossUSD = decimal.NewFromFloat(*req.MaxLossUSD)
+	}
+	if req.StartsAt != nil {
+		t := req.StartsAt.UTC()
+		item.StartsAt = &t
+	}
+	if req.EndsAt != nil {
+		t := req.EndsAt.UTC()
+		item.EndsAt = &t
+	}
+	if item.StartsAt != nil && item.EndsAt != nil && !item.EndsAt.After(*item.StartsAt) {
+		return "ends_at must be after starts_at"
+	}
+	return ""
+}
+
+// campaignError answers a failed campaign admission: 409 with the reason
+// when the campaign blocks, 502 otherwise.
+func campaignError(c *gin.Context, err error) {
+	if blocked, ok := service.IsCampaignBlocked(err); ok {
+		meta := map[string]any{"campaign_id": blocked.CampaignID, "reason": blocked.Reason}
+		if blocked.RemainingUSD != nil {
+			meta["remaining_budget_usd"] = *blocked.RemainingUSD
+		}
+		status := http.StatusConflict
+		if blocked.Reason == service.CampaignReasonNotFound {
+			status = http.StatusBadRequest
+		}
+		Error(c, status, "campaign does not admit this plan", meta)
+		return
+	}
+	Error(c, http.StatusBadGateway, err.Error(), nil)
+}
@@ -0,0 +1,385 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+	"polymarket/internal/risk"
+	"polymarket/internal/service"
+)
+
+// V2CashOpsHandler records deposits to and withdrawals from the trading
+// wallet. A desk requests an operation; approving or rejecting it needs an
+// unscoped token. Completed operations feed the cash ledger behind the
+// buying-power preflight check and are reconciled against onchain transfers.
+type V2CashOpsHandler struct {
+	Repo    repository.Repository
+	Risk    *risk.Manager
+	CashOps *service.CashOpsService
+	// Outbox records the audit logs of cash operation writes with them.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2CashOpsHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/cash-ops")
+	group.GET("", validateQuery[listCashOpsQuery](), h.list)
+	group.POST("", h.create)
+	group.GET("/balance", h.balance)
+	group.GET("/:id", h.get)
+	group.PUT("/:id", h.update)
+	group.POST("/:id/approve", h.review(service.CashOpActionApprove))
+	group.POST("/:id/reject", h.review(service.CashOpActionReject))
+	group.POST("/:id/complete", h.complete)
+	group.POST("/:id/cancel", h.cancel)
+	group.POST("/:id/reconcile", h.reconcile)
+}
+
+type listCashOpsQuery struct {
+	pageQuery
+	Status *string `form:"status" binding:"omitempty,oneof=requested approved rejected cancelled completed reconciled"`
+	Kind   *string `form:"kind" binding:"omitempty,oneof=deposit withdrawal"`
+}
+
+type createCashOpRequest struct {
+	Kind      string  `json:"kind"`
+	AmountUSD float64 `json:"amount_usd"`
+	Wallet    string  `json:"wallet"`
+	TxHash    string  `json:"tx_hash"`
+	Notes     string  `json:"notes"`
+}
+
+type updateCashOpRequest struct {
+	AmountUSD *float64 `json:"amount_usd"`
+	Wallet    *string  `json:"wallet"`
+	TxHash    *string  `json:"tx_hash"`
+	Notes     *string  `json:"notes"`
+}
+
+type cashOpNoteRequest struct {
+	Note   string `json:"note"`
+	TxHash string `json:"tx_hash"`
+}
+
+// cashOpView is an operation with the workflow steps allowed next.
+type cashOpView struct {
+	Operation   models.CashOperation `json:"operation"`
+	NextActions []string             `json:"next_actions"`
+}
+
+func newCashOpView(item models.CashOperation) cashOpView {
+	return cashOpView{Operation: item, NextActions: service.CashOpActions(item.Status)}
+}
+
+// cashOpActor names the caller from the identity headers the gateway sets.
+func cashOpActor(c *gin.Context) string {
+	project := strings.TrimSpace(c.GetHeader("X-Easyweb3-Project"))
+	role := strings.TrimSpace(c.GetHeader("X-Easyweb3-Role"))
+	switch {
+	case project != "" && role != "":
+		return project + "/" + role
+	case project != "":
+		return project
+	}
+	return role
+}
+
+func (h *V2CashOpsHandler) list(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[listCashOpsQuery](c)
+	params := repository.ListCashOperationsParams{Limit: q.Limit, Offset: q.Offset, Tenant: tenantScope(c), Status: q.Status, Kind: q.Kind}
+	items, err := h.Repo.ListCashOperations(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountCashOperations(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
+func (h *V2CashOpsHandler) create(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	var req createCashOpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	kind := strings.ToLower(strings.TrimSpace(req.Kind))
+	if kind != models.CashOpDeposit && kind != models.CashOpWithdrawal {
+		Error(c, http.StatusBadRequest, "kind must be deposit or withdrawal", nil)
+		return
+	}
+	if req.AmountUSD <= 0 {
+		Error(c, http.StatusBadRequest, "amount_usd must be positive", nil)
+		return
+	}
+	item := &models.CashOperation{
+		Tenant:      paas.TenantOrDefault(c.Request.Context()),
+		Kind:        kind,
+		Status:      models.CashOpRequested,
+		AmountUSD:   decimal.NewFromFloat(req.AmountUSD),
+		Wallet:      strings.TrimSpace(req.Wallet),
+		TxHash:      strings.TrimSpace(req.TxHash),
+		Notes:       strings.TrimSpace(req.Notes),
+		RequestedBy: cashOpActor(c),
+	}
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).InsertCashOperation(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return cashOpLog(c, "polymarket_cash_op_requested", "info", *item), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, newCashOpView(*item), nil)
+}
+
+// balance reports the cash ledger and the buying power left after open
+// exposure for the caller's desk.
+func (h *V2CashOpsHandler) balance(c *gin.Context) {
+	if h.Repo == nil || h.Risk == nil {
+		Error(c, http.StatusInternalServerError, "risk manager unavailable", nil)
+		return
+	}
+	bp, err := h.Risk.ForTenant(paas.TenantFromGin(c)).BuyingPower(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, bp, map[string]any{"buying_power_check": h.Risk.Config.BuyingPowerCheck})
+}
+
+func (h *V2CashOpsHandler) get(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	Ok(c, newCashOpView(*item), nil)
+}
+
+// update edits a requested operation. Once approved only the tx hash and
+// notes can change, so the approved amount stays what was reviewed.
+func (h *V2CashOpsHandler) update(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var req updateCashOpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	editable := item.Status == models.CashOpRequested
+	if !editable && item.Status != models.CashOpApproved && item.Status != models.CashOpCompleted {
+		Error(c, http.StatusConflict, "cash operation is not editable", map[string]any{"status": item.Status})
+		return
+	}
+	if !editable && (req.AmountUSD != nil || req.Wallet != nil) {
+		Error(c, http.StatusConflict, "amount_usd and wallet are fixed once approved", map[string]any{"status": item.Status})
+		return
+	}
+	if req.AmountUSD != nil {
+		if *req.AmountUSD <= 0 {
+			Error(c, http.StatusBadRequest, "amount_usd must be positive", nil)
+			return
+		}
+		item.AmountUSD = decimal.NewFromFloat(*req.AmountUSD)
+	}
+	if req.Wallet != nil {
+		item.Wallet = strings.TrimSpace(*req.Wallet)
+	}
+	if req.TxHash != nil {
+		item.TxHash = strings.TrimSpace(*req.TxHash)
+		item.ReconcileError = ""
+	}
+	if req.Notes != nil {
+		item.Notes = strings.TrimSpace(*req.Notes)
+	}
+	h.write(c, item, "polymarket_cash_op_updated", "info")
+}
+
+// review approves or rejects a requested operation.
+func (h *V2CashOpsHandler) review(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tenantScope(c) != nil {
+			Error(c, http.StatusForbidden, "cash operation review requires an unscoped token", nil)
+			return
+		}
+		item, ok := h.load(c)
+		if !ok {
+			return
+		}
+		var req cashOpNoteRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				Error(c, http.StatusBadRequest, "invalid body", nil)
+				return
+			}
+		}
+		if !h.transition(c, item, action) {
+			return
+		}
+		now := time.Now().UTC()
+		item.ReviewedBy = cashOpActor(c)
+		item.ReviewNote = strings.TrimSpace(req.Note)
+		item.ReviewedAt = &now
+		h.save(c, item, "polymarket_cash_op_"+item.Status)
+	}
+}
+
+// complete records that the transfer was made; tx_hash is required so the
+// operation can be reconciled.
+func (h *V2CashOpsHandler) complete(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var req cashOpNoteRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			Error(c, http.StatusBadRequest, "invalid body", nil)
+			return
+		}
+	}
+	if hash := strings.TrimSpace(req.TxHash); hash != "" {
+		item.TxHash = hash
+	}
+	if item.TxHash == "" {
+		Error(c, http.StatusBadRequest, "tx_hash required", nil)
+		return
+	}
+	if !h.transition(c, item, service.CashOpActionComplete) {
+		return
+	}
+	now := time.Now().UTC()
+	item.CompletedAt = &now
+	h.save(c, item, "polymarket_cash_op_completed")
+}
+
+func (h *V2CashOpsHandler) cancel(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if !h.transition(c, item, service.CashOpActionCancel) {
+		return
+	}
+	h.save(c, item, "polymarket_cash_op_cancelled")
+}
+
+// reconcile matches a completed operation against the wallet's onchain
+// transfers right away instead of waiting for the next pass.
+func (h *V2CashOpsHandler) reconcile(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if item.Status != models.CashOpCompleted {
+		Error(c, http.StatusConflict, "only completed cash operations reconcile", map[string]any{"status": item.Status})
+		return
+	}
+	ops := []models.CashOperation{*item}
+	report, err := h.CashOps.Reconcile(c.Request.Context(), ops, time.Now().UTC())
+	if errors.Is(err, service.ErrNoTransferSource) {
+		Error(c, http.StatusServiceUnavailable, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if len(report.Errors) > 0 {
+		Error(c, http.StatusBadGateway, strings.Join(report.Errors, "; "), nil)
+		return
+	}
+	Ok(c, newCashOpView(ops[0]), map[string]any{"reconciled": report.Reconciled == 1})
+}
+
+func (h *V2CashOpsHandler) transition(c *gin.Context, item *models.CashOperation, action string) bool {
+	next, ok := service.CashOpTransition(item.Status, action)
+	if !ok {
+		Error(c, http.StatusConflict, "cannot "+action+" a "+item.Status+" cash operation", map[string]any{
+			"status":       item.Status,
+			"next_actions": service.CashOpActions(item.Status),
+		})
+		return false
+	}
+	item.Status = next
+	return true
+}
+
+func (h *V2CashOpsHandler) save(c *gin.Context, item *models.CashOperation, event string) {
+	level := "info"
+	if item.Kind == models.CashOpWithdrawal {
+		level = "warn"
+	}
+	h.write(c, item, event, level)
+}
+
+// write updates item and records event with it.
+func (h *V2CashOpsHandler) write(c *gin.Context, item *models.CashOperation, event, level string) {
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).UpdateCashOperation(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return cashOpLog(c, event, level, *item), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, newCashOpView(*item), nil)
+}
+
+func cashOpLog(c *gin.Context, event, level string, item models.CashOperation) []models.OutboxMessage {
+	return outboxLog(c, event, level, map[string]any{
+		"cash_op_id": item.ID,
+		"kind":       item.Kind,
+		"status":     item.Status,
+		"amount_usd": item.AmountUSD.StringFixed(2),
+		"tx_hash":    item.TxHash,
+		"actor":      cashOpActor(c),
+	})
+}
+
+func (h *V2CashOpsHandler) load(c *gin.Context) (*models.CashOperation, bool) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return nil, false
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return nil, false
+	}
+	item, err := h.Repo.GetCashOperationByID(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return nil, false
+	}
+	if item == nil || !tenantVisible(c, item.Tenant) {
+		Error(c, http.StatusNotFound, "cash operation not found", nil)
+		return nil, false
+	}
+	return item, true
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2CatalogHandler serves the changelog of semantic market edits recorded
+// during catalog sync, the change journal the frontend syncs from and books
+// checked against the catalog epoch.
+type V2CatalogHandler struct {
+	Repo repository.Repository
+	// Onchain refreshes markets' CTF condition state on demand; nil
+	// disables the refresh endpoint.
+	Onchain *service.OnchainConditionService
+}
+
+func (h *V2CatalogHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/catalog")
+	group.GET("/market-changes", validateQuery[listMarketChangesQuery](), h.listChanges)
+	group.GET("/markets/:id/changes", validateQuery[listMarketChangesQuery](), h.marketChanges)
+	group.GET("/changes", validateQuery[catalogChangesQuery](), h.changes)
+	group.GET("/books", validateQuery[catalogBooksQuery](), h.books)
+	group.POST("/onchain/refresh", h.refreshOnchain)
+}
+
+// catalogBooksQuery takes comma separated ids; market_ids expands to every
+// token of those markets.
+type catalogBooksQuery struct {
+	TokenIDs       []string `form:"token_ids"`
+	MarketIDs      []string `form:"market_ids"`
+	ConsistentOnly bool     `form:"consistent_only"`
+}
+
+// maxCatalogBooks bounds one books request.
+const maxCatalogBooks = 500
+
+type catalogBookItem struct {
+	repository.CatalogBook
+	Consistent bool   `json:"consistent"`
+	Staleness  string `json:"staleness,omitempty"`
+}
+
+// books returns each token's book with its token and market metadata read
+// in one statement. consistent is true when all three carry the same
+// catalog epoch; otherwise staleness says which side is behind.
+func (h *V2CatalogHandler) books(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[catalogBooksQuery](c)
+	ids := append([]string(nil), q.TokenIDs...)
+	if len(q.MarketIDs) > 0 {
+		tokens, err := h.Repo.ListTokensByMarketIDs(c.Request.Context(), q.MarketIDs)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		for _, t := range tokens {
+			ids = append(ids, t.ID)
+		}
+	}
+	if len(ids) == 0 {
+		Error(c, http.StatusBadRequest, "token_ids or market_ids required", nil)
+		return
+	}
+	if len(ids) > maxCatalogBooks {
+		Error(c, http.StatusBadRequest, "too many tokens", map[string]any{"max": maxCatalogBooks})
+		return
+	}
+	rows, err := repository.LoadCatalogBooks(c.Request.Context(), h.Repo, ids)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	items := make([]catalogBookItem, 0, len(rows))
+	stale := map[string]int{}
+	for _, r := range rows {
+		reason := r.Staleness()
+		if reason != "" {
+			stale[reason]++
+			if q.ConsistentOnly {
+				continue
+			}
+		}
+		items = append(items, catalogBookItem{CatalogBook: r, Consistent: reason == "", Staleness: reason})
+	}
+	Ok(c, items, map[string]any{"tokens": len(rows), "stale": stale})
+}
+
+// catalogChangesQuery reads the journal after the After cursor (returned as
+// meta.cursor by the previous call) or, on the first call, after Since.
+type catalogChangesQuery struct {
+	Since  *time.Time `form:"since"`
+	After  uint64     `form:"after"`
+	Entity *string    `form:"entity" binding:"omitempty,oneof=event market label"`
+	Limit  int        `form:"limit" default:"500" binding:"min=1,max=500"`
+}
+
+// changes returns compact change records for incremental sync. has_more
+// means another page is waiting at meta.cursor.
+func (h *V2CatalogHandler) changes(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[catalogChangesQuery](c)
+	if q.Since == nil && q.After == 0 {
+		Error(c, http.StatusBadRequest, "since or after required", nil)
+		return
+	}
+	serverTime := time.Now().UTC()
+	items, err := h.Repo.ListCatalogChanges(c.Request.Context(), repository.ListCatalogChangesParams{
+		Limit:   q.Limit,
+		AfterID: q.After,
+		Since:   q.Since,
+		Entity:  q.Entity,
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	cursor := q.After
+	if len(items) > 0 {
+		cursor = items[len(items)-1].ID
+	}
+	Ok(c, service.CompactCatalogChanges(items), map[string]any{
+		"cursor":      cursor,
+		"has_more":    len(items) == q.Limit,
+		"server_time": serverTime,
+	})
+}
+
+type listMarketChangesQuery struct {
+	pageQuery
+	timeRangeQuery
+	Field    *string `form:"field" binding:"omitempty,oneof=question description resolution_source end_date"`
+	HeldOnly bool    `form:"held_only"`
+}
+
+func (h *V2CatalogHandler) listChanges(c *gin.Context) {
+	h.respondChanges(c, nil)
+}
+
+func (h *V2CatalogHandler) marketChanges(c *gin.Context) {
+	marketID := strings.TrimSpace(c.Param("id"))
+	h.respondChanges(c, &marketID)
+}
+
+func (h *V2CatalogHandler) respondChanges(c *gin.Context, marketID *string) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[listMarketChangesQuery](c)
+	params := repository.ListMarketChangesParams{
+		Limit:    q.Limit,
+		Offset:   q.Offset,
+		MarketID: marketID,
+		Field:    q.Field,
+		HeldOnly: q.HeldOnly,
+		Since:    q.Since,
+		Until:    q.Until,
+	}
+	items, err := h.Repo.ListMarketChanges(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountMarketChanges(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxOnchainRefresh bounds the markets one refresh reads from Polygon.
+const maxOnchainRefresh = 50
+
+type onchainRefreshRequest struct {
+	MarketIDs []string `json:"market_ids"`
+}
+
+// refreshOnchain reads the CTF condition of the given markets now instead
+// of waiting for the onchain job to reach them.
+func (h *V2CatalogHandler) refreshOnchain(c *gin.Context) {
+	if h.Repo == nil || h.Onchain == nil || h.Onchain.Reader == nil {
+		Error(c, http.StatusServiceUnavailable, "onchain reader unavailable", nil)
+		return
+	}
+	var req onchainRefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if len(req.MarketIDs) == 0 {
+		Error(c, http.StatusBadRequest, "market_ids required", nil)
+		return
+	}
+	if len(req.MarketIDs) > maxOnchainRefresh {
+		Error(c, http.StatusBadRequest, "too many markets", map[string]any{"max": maxOnchainRefresh})
+		return
+	}
+	ctx := c.Request.Context()
+	markets, err := h.Repo.ListMarketsByIDs(ctx, req.MarketIDs)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	res, err := h.Onchain.Check(ctx, markets, time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, res, map[string]any{"requested": len(req.MarketIDs), "found": len(markets)})
+}
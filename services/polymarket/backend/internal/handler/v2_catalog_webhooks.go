@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2CatalogWebhookHandler manages subscriptions to new catalog events and
+// markets. The signing secret is returned once, on create; every other
+// response masks it. Scoped tokens only see their own desk's webhooks.
+type V2CatalogWebhookHandler struct {
+	Repo     repository.Repository
+	Webhooks *service.CatalogWebhookService
+	// Outbox records the audit logs of webhook writes with them.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2CatalogWebhookHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/catalog/webhooks")
+	group.GET("", h.list)
+	group.POST("", h.create)
+	group.GET("/:id", h.get)
+	group.PUT("/:id", h.update)
+	group.DELETE("/:id", h.remove)
+	group.POST("/:id/test", h.test)
+}
+
+type catalogWebhookRequest struct {
+	Name            *string  `json:"name"`
+	URL             *string  `json:"url"`
+	Secret          *string  `json:"secret"`
+	EntityTypes     []string `json:"entity_types"`
+	Tags            []string `json:"tags"`
+	Keywords        []string `json:"keywords"`
+	MinLiquidityUSD *float64 `json:"min_liquidity_usd"`
+	Enabled         *bool    `json:"enabled"`
+}
+
+func (h *V2CatalogWebhookHandler) list(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	items, err := h.Repo.ListCatalogWebhooks(c.Request.Context(), repository.ListCatalogWebhooksParams{Tenant: tenantScope(c)})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	for i := range items {
+		items[i] = sanitizeCatalogWebhook(items[i])
+	}
+	Ok(c, items, nil)
+}
+
+func (h *V2CatalogWebhookHandler) get(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	Ok(c, sanitizeCatalogWebhook(*item), nil)
+}
+
+func (h *V2CatalogWebhookHandler) create(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	var req catalogWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if req.URL == nil {
+		Error(c, http.StatusBadRequest, "url required", nil)
+		return
+	}
+	item := &models.CatalogWebhook{Enabled: true}
+	if tenant := tenantScope(c); tenant != nil {
+		item.Tenant = *tenant
+	}
+	if msg := applyCatalogWebhookRequest(item, req); msg != "" {
+		Error(c, http.StatusBadRequest, msg, nil)
+		return
+	}
+	secret := ""
+	if req.Secret != nil {
+		secret = strings.TrimSpace(*req.Secret)
+	}
+	if secret == "" {
+		secret = service.NewCatalogWebhookSecret()
+	}
+	item.Secret = string(service.ProtectSettingValue(service.CatalogWebhookSecretKey, []byte(secret)))
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).InsertCatalogWebhook(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_catalog_webhook_created", "info", map[string]any{
+			"webhook_id": item.ID,
+			"url":        item.URL,
+			"tenant":     item.Tenant,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	h.Webhooks.Invalidate()
+	out := *item
+	out.Secret = secret
+	Ok(c, out, nil)
+}
+
+func (h *V2CatalogWebhookHandler) update(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var req catalogWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if msg := applyCatalogWebhookRequest(item, req); msg != "" {
+		Error(c, http.StatusBadRequest, msg, nil)
+		return
+	}
+	if req.Secret != nil {
+		secret := strings.TrimSpace(*req.Secret)
+		if secret == "" {
+			Error(c, http.StatusBadRequest, "secret cannot be empty", nil)
+			return
+		}
+		item.Secret = string(service.ProtectSettingValue(service.CatalogWebhookSecretKey, []byte(secret)))
+	}
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).UpdateCatalogWebhook(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_catalog_webhook_updated", "info", map[string]any{
+			"webhook_id":     item.ID,
+			"url":            item.URL,
+			"enabled":        item.Enabled,
+			"secret_rotated": req.Secret != nil,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	h.Webhooks.Invalidate()
+	Ok(c, sanitizeCatalogWebhook(*item), nil)
+}
+
+func (h *V2CatalogWebhookHandler) remove(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).DeleteCatalogWebhook(c.Request.Context(), item.ID); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_catalog_webhook_deleted", "info", map[string]any{
+			"webhook_id": item.ID,
+			"url":        item.URL,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	h.Webhooks.Invalidate()
+	Ok(c, map[string]any{"deleted": item.ID}, nil)
+}
+
+// test sends a ping delivery with no entities, signed like a real one.
+func (h *V2CatalogWebhookHandler) test(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if h.Webhooks == nil {
+		Error(c, http.StatusServiceUnavailable, "catalog webhooks unavailable", nil)
+		return
+	}
+	res := h.Webhooks.Deliver(c.Request.Context(), *item, service.CatalogWebhookPayload{
+		Type:      "catalog.ping",
+		WebhookID: item.ID,
+		SentAt:    time.Now().UTC(),
+		Events:    []service.CatalogWebhookItem{},
+		Markets:   []service.CatalogWebhookItem{},
+	})
+	if res.Error != "" {
+		Error(c, http.StatusBadGateway, res.Error, map[string]any{"delivery": res})
+		return
+	}
+	Ok(c, res, nil)
+}
+
+// load fetches the :id webhook, answering 404 for missing rows and rows of
+// another desk.
+func (h *V2CatalogWebhookHandler) load(c *gin.Context) (*models.CatalogWebhook, bool) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return nil, false
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return nil, false
+	}
+	item, err := h.Repo.GetCatalogWebhookByID(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return nil, false
+	}
+	if item == nil || !tenantVisible(c, item.Tenant) {
+		Error(c, http.StatusNotFound, "webhook not found", nil)
+		return nil, false
+	}
+	return item, true
+}
+
+// applyCatalogWebhookRequest copies the set fields of req onto item and
+// returns a validation message, or "" when the result is valid.
+func applyCatalogWebhookRequest(item *models.CatalogWebhook, req catalogWebhookRequest) string {
+	if req.Name != nil {
+		item.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.URL != nil {
+		raw := strings.TrimSpace(*req.URL)
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "url must be an absolute http(s) URL"
+		}
+		item.URL = raw
+	}
+	if req.EntityTypes != nil {
+		types := make([]string, 0, len(req.EntityTypes))
+		for _, t := range req.EntityTypes {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t != service.CatalogEntityEvent && t != service.CatalogEntityMarket {
+				return "entity_types must be event or market"
+			}
+			types = append(types, t)
+		}
+		item.EntityTypes = stringListJSON(types)
+	}
+	if req.Tags != nil {
+		item.Tags = stringListJSON(req.Tags)
+	}
+	if req.Keywords != nil {
+		item.Keywords = stringListJSON(req.Keywords)
+	}
+	if req.MinLiquidityUSD != nil {
+		if *req.MinLiquidityUSD < 0 {
+			return "min_liquidity_usd must be >= 0"
+		}
+		item.MinLiquidityUSD = *req.MinLiquidityUSD
+	}
+	if req.Enabled != nil {
+		item.Enabled = *req.Enabled
+	}
+	if item.Name == "" {
+		item.Name = item.URL
+	}
+	return ""
+}
+
+func stringListJSON(items []string) datatypes.JSON {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if v := strings.TrimSpace(item); v != "" {
+			out = append(out, v)
+		}
+	}
+	raw, _ := json.Marshal(out)
+	return datatypes.JSON(raw)
+}
+
+func sanitizeCatalogWebhook(item models.CatalogWebhook) models.CatalogWebhook {
+	item.Secret = "***"
+	return item
+}
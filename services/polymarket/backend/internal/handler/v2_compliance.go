@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"polymarket/internal/compliance"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// minOverrideReason keeps "ok" and "n/a" out of the audit trail.
+const minOverrideReason = 10
+
+// V2ComplianceHandler exposes the compliance rules and the override workflow.
+// Granting or revoking an override needs an unscoped token and a reason; the
+// write lands in the audit chain under the market's path and the reason is
+// kept on the override and sent to the PaaS log.
+type V2ComplianceHandler struct {
+	Repo    repository.Repository
+	Checker *compliance.Checker
+	// Outbox records the audit logs of override writes with them.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2ComplianceHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/compliance")
+	group.GET("/rules", h.rules)
+	group.GET("/check", validateQuery[complianceCheckQuery](), h.check)
+	group.GET("/overrides", validateQuery[listComplianceOverridesQuery](), h.listOverrides)
+	group.POST("/markets/:id/override", h.grantOverride)
+	group.POST("/overrides/:id/revoke", h.revokeOverride)
+}
+
+type complianceCheckQuery struct {
+	MarketIDs string `form:"market_ids" binding:"required"`
+}
+
+type listComplianceOverridesQuery struct {
+	pageQuery
+	MarketID   *string `form:"market_id"`
+	ActiveOnly bool    `form:"active_only"`
+}
+
+func (h *V2ComplianceHandler) rules(c *gin.Context) {
+	Ok(c, h.Checker.Rules(), nil)
+}
+
+func (h *V2ComplianceHandler) check(c *gin.Context) {
+	q := queryOf[complianceCheckQuery](c)
+	res, err := h.Checker.Check(c.Request.Context(), strings.Split(q.MarketIDs, ","), time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	blocked := res.Blocked()
+	if blocked == nil {
+		blocked = []string{}
+	}
+	Ok(c, map[string]any{"blocked": blocked, "violations": res.Violations}, nil)
+}
+
+func (h *V2ComplianceHandler) listOverrides(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[listComplianceOverridesQuery](c)
+	params := repository.ListComplianceOverridesParams{
+		Limit:    q.Limit,
+		Offset:   q.Offset,
+		MarketID: q.MarketID,
+	}
+	if q.ActiveOnly {
+		now := time.Now().UTC()
+		params.ActiveAt = &now
+	}
+	items, err := h.Repo.ListComplianceOverrides(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountComplianceOverrides(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
+type grantOverrideRequest struct {
+	Reason   string  `json:"reason"`
+	TTLHours float64 `json:"ttl_hours"`
+}
+
+func (h *V2ComplianceHandler) grantOverride(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "compliance overrides require an unscoped token", nil)
+		return
+	}
+	marketID := strings.TrimSpace(c.Param("id"))
+	if marketID == "" {
+		Error(c, http.StatusBadRequest, "market id required", nil)
+		return
+	}
+	var req grantOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if len(req.Reason) < minOverrideReason {
+		Error(c, http.StatusBadRequest, "reason required", map[string]any{"min_length": minOverrideReason})
+		return
+	}
+	if req.TTLHours < 0 {
+		Error(c, http.StatusBadRequest, "invalid ttl_hours", nil)
+		return
+	}
+	ttl := 168 * time.Hour
+	if h.Checker != nil && h.Checker.Config.OverrideTTL > 0 {
+		ttl = h.Checker.Config.OverrideTTL
+	}
+	if req.TTLHours > 0 {
+		if asked := time.Duration(req.TTLHours * float64(time.Hour)); asked < ttl {
+			ttl = asked
+		}
+	}
+	now := time.Now().UTC()
+	item := &models.ComplianceOverride{
+		MarketID:  marketID,
+		Reason:    req.Reason,
+		Project:   strings.TrimSpace(c.GetHeader("X-Easyweb3-Project")),
+		Role:      strings.TrimSpace(c.GetHeader("X-Easyweb3-Role")),
+		ExpiresAt: now.Add(ttl),
+	}
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).InsertComplianceOverride(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_compliance_override_granted", "warn", map[string]any{
+			"override_id": item.ID,
+			"market_id":   marketID,
+			"reason":      item.Reason,
+			"project":     item.Project,
+			"role":        item.Role,
+			"expires_at":  item.ExpiresAt.Format(time.RFC3339),
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, item, nil)
+}
+
+type revokeOverrideRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h *V2ComplianceHandler) revokeOverride(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "compliance overrides require an unscoped token", nil)
+		return
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	var req revokeOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if len(req.Reason) < minOverrideReason {
+		Error(c, http.StatusBadRequest, "reason required", map[string]any{"min_length": minOverrideReason})
+		return
+	}
+	item, err := h.Repo.GetComplianceOverrideByID(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if item == nil {
+		Error(c, http.StatusNotFound, "override not found", nil)
+		return
+	}
+	if item.RevokedAt != nil {
+		Error(c, http.StatusConflict, "override already revoked", nil)
+		return
+	}
+	now := time.Now().UTC()
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).RevokeComplianceOverride(c.Request.Context(), id, req.Reason, now); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_compliance_override_revoked", "info", map[string]any{
+			"override_id": id,
+			"market_id":   item.MarketID,
+			"reason":      req.Reason,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	item.RevokedAt = &now
+	item.RevokeReason = req.Reason
+	Ok(c, item, nil)
+}
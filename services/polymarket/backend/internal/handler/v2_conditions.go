@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/expr"
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2ConditionHandler documents, validates and test-evaluates rule condition
+// expressions before they are saved on an execution rule.
+type V2ConditionHandler struct {
+	Repo repository.Repository
+}
+
+func (h *V2ConditionHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/conditions")
+	group.GET("/variables", h.variables)
+	group.POST("/validate", h.validate)
+	group.POST("/evaluate", h.evaluate)
+}
+
+type conditionRequest struct {
+	Expression string `json:"expression"`
+	// OpportunityID evaluates against a stored opportunity; Variables
+	// override or stand in for its values.
+	OpportunityID uint64         `json:"opportunity_id"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// conditionErrorMeta exposes the position of an expression error.
+func conditionErrorMeta(err error) map[string]any {
+	var exprErr *expr.Error
+	if errors.As(err, &exprErr) && exprErr.Pos >= 0 {
+		return map[string]any{"position": exprErr.Pos}
+	}
+	return nil
+}
+
+func (h *V2ConditionHandler) variables(c *gin.Context) {
+	Ok(c, map[string]any{
+		"opportunity": service.OpportunityConditionVars(),
+	}, nil)
+}
+
+func (h *V2ConditionHandler) validate(c *gin.Context) {
+	var req conditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	prog, err := service.CompileOpportunityCondition(req.Expression)
+	if err != nil {
+		out := map[string]any{"valid": false, "error": err.Error()}
+		for k, v := range conditionErrorMeta(err) {
+			out[k] = v
+		}
+		Ok(c, out, nil)
+		return
+	}
+	Ok(c, map[string]any{"valid": true, "expression": prog.String(), "variables": prog.Vars()}, nil)
+}
+
+// evaluate runs an expression against an opportunity and/or explicit
+// variables and returns the result with the variables it saw.
+func (h *V2ConditionHandler) evaluate(c *gin.Context) {
+	var req conditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	prog, err := service.CompileOpportunityCondition(req.Expression)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "invalid condition: "+err.Error(), conditionErrorMeta(err))
+		return
+	}
+	env := map[string]any{}
+	if req.OpportunityID > 0 {
+		if h.Repo == nil {
+			Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+			return
+		}
+		opp, err := h.Repo.GetOpportunityByID(c.Request.Context(), req.OpportunityID)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		if opp == nil || !tenantVisible(c, opp.Tenant) {
+			Error(c, http.StatusNotFound, "opportunity not found", nil)
+			return
+		}
+		env, err = service.OpportunityConditionEnv(c.Request.Context(), h.Repo, *opp, prog)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+	}
+	for k, v := range req.Variables {
+		env[strings.TrimSpace(k)] = v
+	}
+	used := map[string]any{}
+	for _, name := range prog.Vars() {
+		if v, ok := env[name]; ok {
+			used[name] = v
+		}
+	}
+	result, err := prog.Eval(env)
+	if err != nil {
+		Error(c, http.StatusUnprocessableEntity, err.Error(), map[string]any{"variables": used})
+		return
+	}
+	Ok(c, map[string]any{"result": result, "variables": used}, nil)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/models"
+	"polymarket/internal/service"
+)
+
+// tuneExecutionRuleRequest is the grid to search around the stored rule.
+type tuneExecutionRuleRequest struct {
+	service.RuleTuningGrid
+	// Days is the lookback; default 30, max 90.
+	Days int `json:"days"`
+	// TrainFraction is the share of the lookback candidates are ranked on;
+	// the rest scores them out of sample. Default 0.7.
+	TrainFraction float64 `json:"train_fraction"`
+}
+
+func (h *V2ExecutionRuleHandler) tune(c *gin.Context) {
+	if h.Repo == nil || h.Tuner == nil {
+		Error(c, http.StatusInternalServerError, "rule tuner unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("strategy"))
+	var req tuneExecutionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	days := req.Days
+	if days <= 0 {
+		days = 30
+	}
+	if days > 90 {
+		Error(c, http.StatusBadRequest, "days must be <= 90", nil)
+		return
+	}
+	rule, err := h.Repo.GetExecutionRuleByStrategyName(c.Request.Context(), name)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if rule == nil {
+		Error(c, http.StatusNotFound, "execution rule not found", nil)
+		return
+	}
+	until := time.Now().UTC()
+	since := until.AddDate(0, 0, -days)
+	run, err := h.Tuner.Tune(c.Request.Context(), *rule, req.RuleTuningGrid, since, until, req.TrainFraction)
+	switch {
+	case errors.Is(err, service.ErrRuleTuningInvalid):
+		Error(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	case err != nil:
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, run, nil)
+}
+
+type ruleTuningsQuery struct {
+	Limit int `form:"limit" default:"50" binding:"min=1,max=500"`
+}
+
+func (h *V2ExecutionRuleHandler) listTunings(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[ruleTuningsQuery](c)
+	items, err := h.Repo.ListRuleTunings(c.Request.Context(), c.Param("strategy"), q.Limit)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, nil)
+}
+
+func (h *V2ExecutionRuleHandler) getTuning(c *gin.Context) {
+	run, ok := h.loadTuning(c)
+	if !ok {
+		return
+	}
+	Ok(c, run, nil)
+}
+
+// reviewTuning approves or rejects a proposed run. Approving writes the
+// proposed params to the execution rule.
+func (h *V2ExecutionRuleHandler) reviewTuning(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.Tuner == nil {
+			Error(c, http.StatusInternalServerError, "rule tuner unavailable", nil)
+			return
+		}
+		run, ok := h.loadTuning(c)
+		if !ok {
+			return
+		}
+		var req cashOpNoteRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				Error(c, http.StatusBadRequest, "invalid body", nil)
+				return
+			}
+		}
+		review := h.Tuner.Reject
+		if status == models.RuleTuningApproved {
+			review = h.Tuner.Approve
+		}
+		run, err := review(c.Request.Context(), run.ID, cashOpActor(c), req.Note, time.Now().UTC())
+		switch {
+		case errors.Is(err, service.ErrRuleTuningNotProposed), errors.Is(err, service.ErrRuleTuningInvalid):
+			Error(c, http.StatusConflict, err.Error(), nil)
+			return
+		case err != nil:
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		case run == nil:
+			Error(c, http.StatusNotFound, "rule tuning not found", nil)
+			return
+		}
+		Ok(c, run, nil)
+	}
+}
+
+// loadTuning fetches the :id run and checks it belongs to :strategy.
+func (h *V2ExecutionRuleHandler) loadTuning(c *gin.Context) (*models.RuleTuning, bool) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return nil, false
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return nil, false
+	}
+	run, err := h.Repo.GetRuleTuning(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return nil, false
+	}
+	if run == nil || run.StrategyName != strings.TrimSpace(c.Param("strategy")) {
+		Error(c, http.StatusNotFound, "rule tuning not found", nil)
+		return nil, false
+	}
+	return run, true
+}
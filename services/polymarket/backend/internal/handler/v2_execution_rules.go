@@ -10,18 +10,29 @@ import (
 
 	"polymarket/internal/models"
 	"polymarket/internal/repository"
+	"polymarket/internal/service"
 )
 
 type V2ExecutionRuleHandler struct {
 	Repo repository.Repository
+	// Simulator replays candidate rules; nil disables /simulate.
+	Simulator *service.ExecutionRuleSimulator
+	// Tuner grid-searches rules; nil disables the tuning routes.
+	Tuner *service.ExecutionRuleTuner
 }
 
 func (h *V2ExecutionRuleHandler) Register(r *gin.Engine) {
-	g := r.Group("/api/v2/execution-rules")
+	g := r.Group("/api/v2/execution-rules", tenantGuard("strategy", "execution rule not found", h.strategyTenant))
 	g.GET("", h.list)
+	g.POST("/simulate", h.simulate)
 	g.GET("/:strategy", h.get)
 	g.PUT("/:strategy", h.put)
 	g.DELETE("/:strategy", h.delete)
+	g.POST("/:strategy/tune", h.tune)
+	g.GET("/:strategy/tunings", validateQuery[ruleTuningsQuery](), h.listTunings)
+	g.GET("/:strategy/tunings/:id", h.getTuning)
+	g.POST("/:strategy/tunings/:id/approve", h.reviewTuning(models.RuleTuningApproved))
+	g.POST("/:strategy/tunings/:id/reject", h.reviewTuning(models.RuleTuningRejected))
 }
 
 func (h *V2ExecutionRuleHandler) list(c *gin.Context) {
@@ -34,6 +45,24 @@ func (h *V2ExecutionRuleHandler) list(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
+	if tenantScope(c) != nil {
+		strategies, err := h.Repo.ListStrategies(c.Request.Context())
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		visible := map[string]bool{}
+		for _, s := range strategies {
+			visible[s.Name] = tenantVisible(c, s.Tenant)
+		}
+		kept := items[:0]
+		for _, it := range items {
+			if visible[it.StrategyName] {
+				kept = append(kept, it)
+			}
+		}
+		items = kept
+	}
 	Ok(c, items, nil)
 }
 
@@ -67,6 +96,8 @@ type putExecutionRuleRequest struct {
 	TakeProfitPct  *string  `json:"take_profit_pct"`
 	MaxHoldHours   *int     `json:"max_hold_hours"`
 	MaxDailyTrades *int     `json:"max_daily_trades"`
+	// Condition replaces the rule's expression; "" clears it.
+	Condition *string `json:"condition"`
 }
 
 func (h *V2ExecutionRuleHandler) put(c *gin.Context) {
@@ -90,18 +121,38 @@ func (h *V2ExecutionRuleHandler) put(c *gin.Context) {
 		return
 	}
 	if item == nil {
-		item = &models.ExecutionRule{
-			StrategyName:   name,
-			AutoExecute:    false,
-			MinConfidence:  0.8,
-			MinEdgePct:     decimal.NewFromFloat(0.05),
-			StopLossPct:    decimal.NewFromFloat(0.10),
-			TakeProfitPct:  decimal.NewFromFloat(0.20),
-			MaxHoldHours:   72,
-			MaxDailyTrades: 10,
-			CreatedAt:      time.Now().UTC(),
-		}
+		item = defaultExecutionRule(name)
+	}
+	if !applyExecutionRuleRequest(c, item, req) {
+		return
+	}
+	item.StrategyName = name
+	item.UpdatedAt = time.Now().UTC()
+	if err := h.Repo.UpsertExecutionRule(c.Request.Context(), item); err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
 	}
+	Ok(c, item, nil)
+}
+
+// defaultExecutionRule is the rule a strategy gets on its first PUT.
+func defaultExecutionRule(name string) *models.ExecutionRule {
+	return &models.ExecutionRule{
+		StrategyName:   name,
+		AutoExecute:    false,
+		MinConfidence:  0.8,
+		MinEdgePct:     decimal.NewFromFloat(0.05),
+		StopLossPct:    decimal.NewFromFloat(0.10),
+		TakeProfitPct:  decimal.NewFromFloat(0.20),
+		MaxHoldHours:   72,
+		MaxDailyTrades: 10,
+		CreatedAt:      time.Now().UTC(),
+	}
+}
+
+// applyExecutionRuleRequest sets the fields req carries on item. It writes
+// the error response and returns false on an invalid field.
+func applyExecutionRuleRequest(c *gin.Context, item *models.ExecutionRule, req putExecutionRuleRequest) bool {
 	if req.AutoExecute != nil {
 		item.AutoExecute = *req.AutoExecute
 	}
@@ -112,7 +163,7 @@ func (h *V2ExecutionRuleHandler) put(c *gin.Context) {
 		v, err := decimal.NewFromString(strings.TrimSpace(*req.MinEdgePct))
 		if err != nil {
 			Error(c, http.StatusBadRequest, "invalid min_edge_pct", nil)
-			return
+			return false
 		}
 		item.MinEdgePct = v
 	}
@@ -120,7 +171,7 @@ func (h *V2ExecutionRuleHandler) put(c *gin.Context) {
 		v, err := decimal.NewFromString(strings.TrimSpace(*req.StopLossPct))
 		if err != nil {
 			Error(c, http.StatusBadRequest, "invalid stop_loss_pct", nil)
-			return
+			return false
 		}
 		item.StopLossPct = v
 	}
@@ -128,7 +179,7 @@ func (h *V2ExecutionRuleHandler) put(c *gin.Context) {
 		v, err := decimal.NewFromString(strings.TrimSpace(*req.TakeProfitPct))
 		if err != nil {
 			Error(c, http.StatusBadRequest, "invalid take_profit_pct", nil)
-			return
+			return false
 		}
 		item.TakeProfitPct = v
 	}
@@ -138,13 +189,70 @@ func (h *V2ExecutionRuleHandler) put(c *gin.Context) {
 	if req.MaxDailyTrades != nil {
 		item.MaxDailyTrades = *req.MaxDailyTrades
 	}
-	item.StrategyName = name
-	item.UpdatedAt = time.Now().UTC()
-	if err := h.Repo.UpsertExecutionRule(c.Request.Context(), item); err != nil {
+	if req.Condition != nil {
+		cond := strings.TrimSpace(*req.Condition)
+		if cond != "" {
+			if _, err := service.CompileOpportunityCondition(cond); err != nil {
+				Error(c, http.StatusBadRequest, "invalid condition: "+err.Error(), conditionErrorMeta(err))
+				return false
+			}
+		}
+		item.Condition = cond
+	}
+	return true
+}
+
+// simulateExecutionRuleRequest is a candidate rule for strategy. Fields left
+// out come from the stored rule, or the defaults when there is none.
+type simulateExecutionRuleRequest struct {
+	Strategy string `json:"strategy"`
+	// Days is the lookback; default 7, max 90.
+	Days int `json:"days"`
+	putExecutionRuleRequest
+}
+
+func (h *V2ExecutionRuleHandler) simulate(c *gin.Context) {
+	if h.Repo == nil || h.Simulator == nil {
+		Error(c, http.StatusInternalServerError, "simulator unavailable", nil)
+		return
+	}
+	var req simulateExecutionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	name := strings.TrimSpace(req.Strategy)
+	if name == "" {
+		Error(c, http.StatusBadRequest, "strategy required", nil)
+		return
+	}
+	days := req.Days
+	if days <= 0 {
+		days = 7
+	}
+	if days > 90 {
+		Error(c, http.StatusBadRequest, "days must be <= 90", nil)
+		return
+	}
+	item, err := h.Repo.GetExecutionRuleByStrategyName(c.Request.Context(), name)
+	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, item, nil)
+	if item == nil {
+		item = defaultExecutionRule(name)
+	}
+	if !applyExecutionRuleRequest(c, item, req.putExecutionRuleRequest) {
+		return
+	}
+	until := time.Now().UTC()
+	since := until.AddDate(0, 0, -days)
+	out, err := h.Simulator.Simulate(c.Request.Context(), name, *item, since, until)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, map[string]any{"rule": item, "result": out}, nil)
 }
 
 func (h *V2ExecutionRuleHandler) delete(c *gin.Context) {
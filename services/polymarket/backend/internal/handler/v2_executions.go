@@ -3,15 +3,16 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 
 	"polymarket/internal/models"
-	"polymarket/internal/paas"
 	"polymarket/internal/repository"
 	"polymarket/internal/risk"
 	"polymarket/internal/service"
@@ -22,6 +23,14 @@ type V2ExecutionHandler struct {
 	Risk         *risk.Manager
 	Journal      *service.JournalService
 	PositionSync *service.PositionSyncService
+	Campaigns    *service.CampaignService
+	Triggers     *service.PlanTriggerService
+	// Outbox records the side effects of status changes with them.
+	Outbox *service.OutboxDispatcher
+	// Dependencies holds the executor's defaults for dependent legs.
+	Dependencies service.LegDependencyConfig
+	// Costs records the execution cost forecast of manual plans.
+	Costs *service.CostForecaster
 }
 
 type planLegTarget struct {
@@ -35,17 +44,36 @@ type planLegMarket struct {
 }
 
 func (h *V2ExecutionHandler) Register(r *gin.Engine) {
-	group := r.Group("/api/v2/executions")
-	group.GET("", h.list)
+	group := r.Group("/api/v2/executions", tenantGuard("id", "execution plan not found", h.planTenant))
+	group.GET("", validateQuery[listExecutionsQuery](), h.list)
+	group.POST("", h.createManual)
 	group.GET("/:id", h.get)
 	group.GET("/:id/pnl", h.getPnL)
+	group.GET("/:id/risk-report", validateQuery[riskReportQuery](), h.getRiskReport)
 	group.POST("/:id/preflight", h.preflight)
 	group.POST("/:id/fill", h.addFill)
+	group.POST("/:id/fills/import", h.importFills)
 	group.POST("/:id/mark-executing", h.markExecuting)
 	group.POST("/:id/mark-executed", h.markExecuted)
 	group.POST("/:id/cancel", h.cancel)
+	group.PUT("/:id/trigger", h.scheduleTrigger)
+	group.DELETE("/:id/trigger", h.unscheduleTrigger)
 	group.PUT("/:id/pnl", h.upsertPnL)
 	group.POST("/:id/settle", h.settle)
+	group.POST("/:id/pnl/recalculate", h.recalculatePnL)
+}
+
+type listExecutionsQuery struct {
+	pageQuery
+	Status     *string `form:"status"`
+	Source     *string `form:"source" binding:"omitempty,oneof=opportunity manual"`
+	CampaignID *uint64 `form:"campaign_id"`
+	// TriggerState lists time-triggered plans, e.g. trigger_state=scheduled.
+	TriggerState *string `form:"trigger_state" binding:"omitempty,oneof=scheduled fired failed"`
+}
+
+type riskReportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=json markdown"`
 }
 
 func (h *V2ExecutionHandler) list(c *gin.Context) {
@@ -53,32 +81,34 @@ func (h *V2ExecutionHandler) list(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	status := strings.TrimSpace(c.Query("status"))
-	limit := intQuery(c, "limit", 50)
-	offset := intQuery(c, "offset", 0)
-	var statusPtr *string
-	if status != "" {
-		statusPtr = &status
-	}
+	q := queryOf[listExecutionsQuery](c)
 	items, err := h.Repo.ListExecutionPlans(c.Request.Context(), repository.ListExecutionPlansParams{
-		Limit:   limit,
-		Offset:  offset,
-		Status:  statusPtr,
-		OrderBy: "created_at",
-		Asc:     boolPtr(false),
+		Limit:        q.Limit,
+		Offset:       q.Offset,
+		Status:       q.Status,
+		Source:       q.Source,
+		Tenant:       tenantScope(c),
+		CampaignID:   q.CampaignID,
+		TriggerState: q.TriggerState,
+		OrderBy:      "created_at",
+		Asc:          boolPtr(false),
 	})
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
 	total, err := h.Repo.CountExecutionPlans(c.Request.Context(), repository.ListExecutionPlansParams{
-		Status: statusPtr,
+		Status:       q.Status,
+		Source:       q.Source,
+		Tenant:       tenantScope(c),
+		CampaignID:   q.CampaignID,
+		TriggerState: q.TriggerState,
 	})
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	meta := paginationMeta(limit, offset, total)
+	meta := paginationMeta(q.Limit, q.Offset, total)
 	Ok(c, items, meta)
 }
 
@@ -101,6 +131,17 @@ func (h *V2ExecutionHandler) get(c *gin.Context) {
 		Error(c, http.StatusNotFound, "execution plan not found", nil)
 		return
 	}
+	if deps := service.PlanLegDependencies(*item, nil, time.Now().UTC(), h.Dependencies); len(deps) > 0 {
+		planID := item.ID
+		orders, err := h.Repo.ListOrders(c.Request.Context(), repository.ListOrdersParams{Limit: 1000, PlanID: &planID})
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		deps = service.PlanLegDependencies(*item, orders, time.Now().UTC(), h.Dependencies)
+		Ok(c, item, map[string]any{"leg_dependencies": deps})
+		return
+	}
 	Ok(c, item, nil)
 }
 
@@ -126,6 +167,43 @@ func (h *V2ExecutionHandler) getPnL(c *gin.Context) {
 	Ok(c, rec, nil)
 }
 
+// getRiskReport returns the report written at the plan's last preflight;
+// ?format=markdown returns only the rendered markdown.
+func (h *V2ExecutionHandler) getRiskReport(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	plan, err := h.Repo.GetExecutionPlanByID(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if plan == nil {
+		Error(c, http.StatusNotFound, "execution plan not found", nil)
+		return
+	}
+	if len(plan.RiskReport) == 0 {
+		Error(c, http.StatusNotFound, "risk report not found; run preflight first", nil)
+		return
+	}
+	var report risk.RiskReport
+	if err := json.Unmarshal(plan.RiskReport, &report); err != nil {
+		Error(c, http.StatusInternalServerError, "invalid risk report", nil)
+		return
+	}
+	if queryOf[riskReportQuery](c).Format == "markdown" {
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(report.Markdown))
+		return
+	}
+	Ok(c, report, nil)
+}
+
 func (h *V2ExecutionHandler) preflight(c *gin.Context) {
 	if h.Repo == nil {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
@@ -140,7 +218,7 @@ func (h *V2ExecutionHandler) preflight(c *gin.Context) {
 		Error(c, http.StatusServiceUnavailable, "risk manager unavailable", nil)
 		return
 	}
-	result, err := h.Risk.PreflightPlan(c.Request.Context(), id)
+	result, err := service.PreflightPlan(c.Request.Context(), h.Repo, h.Risk, h.Outbox, id)
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
@@ -149,38 +227,7 @@ func (h *V2ExecutionHandler) preflight(c *gin.Context) {
 		Error(c, http.StatusNotFound, "execution plan not found", nil)
 		return
 	}
-	// Best-effort failure reason journaling for analytics.
-	if !result.Passed {
-		plan, _ := h.Repo.GetExecutionPlanByID(c.Request.Context(), id)
-		if plan != nil {
-			reason := preflightFailureReason(*result)
-			if reason != "" {
-				rec, _ := h.Repo.GetPnLRecordByPlanID(c.Request.Context(), id)
-				if rec == nil {
-					rec = &models.PnLRecord{
-						PlanID:       id,
-						StrategyName: plan.StrategyName,
-						ExpectedEdge: decimal.Zero,
-						Outcome:      "pending",
-						CreatedAt:    time.Now().UTC(),
-					}
-				}
-				r := reason
-				rec.FailureReason = &r
-				if strings.TrimSpace(rec.Outcome) == "" {
-					rec.Outcome = "pending"
-				}
-				_ = h.Repo.UpsertPnLRecord(c.Request.Context(), rec)
-			}
-		}
-	}
 	Ok(c, result, nil)
-
-	paas.LogBestEffort(c, "polymarket_execution_preflight", "info", map[string]any{
-		"plan_id": id,
-		"passed":  result.Passed,
-		"checks":  len(result.Checks),
-	})
 }
 
 func (h *V2ExecutionHandler) markExecuting(c *gin.Context) {
@@ -208,15 +255,44 @@ func (h *V2ExecutionHandler) markExecuting(c *gin.Context) {
 			return
 		}
 	}
-	_ = h.Repo.UpdateExecutionPlanStatus(c.Request.Context(), id, "executing")
-	_ = h.Repo.UpdateOpportunityStatus(c.Request.Context(), plan.OpportunityID, "executing")
+	// Compliance is enforced even when preflight is optional.
+	restricted, err := h.Risk.PlanCompliance(c.Request.Context(), *plan, time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if blocked := restricted.Blocked(); len(blocked) > 0 {
+		Error(c, http.StatusConflict, "restricted by compliance rules", map[string]any{"market_ids": blocked})
+		return
+	}
+	if plan.CampaignID != nil {
+		if err := h.Campaigns.CheckExecutable(c.Request.Context(), *plan.CampaignID, time.Now().UTC()); err != nil {
+			campaignError(c, err)
+			return
+		}
+	}
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		ctx := c.Request.Context()
+		if err := h.Repo.UpdateExecutionPlanStatusTx(ctx, tx, id, "executing"); err != nil {
+			return nil, err
+		}
+		if plan.OpportunityID > 0 {
+			if err := h.Repo.UpdateOpportunityStatusTx(ctx, tx, plan.OpportunityID, "executing"); err != nil {
+				return nil, err
+			}
+		}
+		return outboxLog(c, "polymarket_execution_mark_executing", "info", map[string]any{
+			"plan_id":        id,
+			"opportunity_id": plan.OpportunityID,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
 	if h.Journal != nil {
 		_ = h.Journal.CaptureEntry(c.Request.Context(), id)
 	}
-	paas.LogBestEffort(c, "polymarket_execution_mark_executing", "info", map[string]any{
-		"plan_id":        id,
-		"opportunity_id": plan.OpportunityID,
-	})
 	Ok(c, map[string]any{"id": id, "status": "executing"}, nil)
 }
 
@@ -240,13 +316,26 @@ func (h *V2ExecutionHandler) markExecuted(c *gin.Context) {
 		return
 	}
 	now := time.Now().UTC()
-	_ = h.Repo.UpdateExecutionPlanExecutedAt(c.Request.Context(), id, "executed", &now)
-	_ = h.Repo.UpdateOpportunityStatus(c.Request.Context(), plan.OpportunityID, "executed")
-	paas.LogBestEffort(c, "polymarket_execution_mark_executed", "info", map[string]any{
-		"plan_id":        id,
-		"opportunity_id": plan.OpportunityID,
-		"executed_at":    now.Format(time.RFC3339),
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		ctx := c.Request.Context()
+		if err := h.Repo.UpdateExecutionPlanExecutedAtTx(ctx, tx, id, "executed", &now); err != nil {
+			return nil, err
+		}
+		if plan.OpportunityID > 0 {
+			if err := h.Repo.UpdateOpportunityStatusTx(ctx, tx, plan.OpportunityID, "executed"); err != nil {
+				return nil, err
+			}
+		}
+		return outboxLog(c, "polymarket_execution_mark_executed", "info", map[string]any{
+			"plan_id":        id,
+			"opportunity_id": plan.OpportunityID,
+			"executed_at":    now.Format(time.RFC3339),
+		}), nil
 	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
 	Ok(c, map[string]any{"id": id, "status": "executed", "executed_at": now}, nil)
 }
 
@@ -261,18 +350,92 @@ func (h *V2ExecutionHandler) cancel(c *gin.Context) {
 		return
 	}
 	plan, _ := h.Repo.GetExecutionPlanByID(c.Request.Context(), id)
-	_ = h.Repo.UpdateExecutionPlanStatus(c.Request.Context(), id, "cancelled")
-	if plan != nil {
-		_ = h.Repo.UpdateOpportunityStatus(c.Request.Context(), plan.OpportunityID, "cancelled")
-	}
-	details := map[string]any{"plan_id": id}
-	if plan != nil {
-		details["opportunity_id"] = plan.OpportunityID
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		ctx := c.Request.Context()
+		if err := h.Repo.UpdateExecutionPlanStatusTx(ctx, tx, id, "cancelled"); err != nil {
+			return nil, err
+		}
+		details := map[string]any{"plan_id": id}
+		if plan != nil && plan.OpportunityID > 0 {
+			if err := h.Repo.UpdateOpportunityStatusTx(ctx, tx, plan.OpportunityID, "cancelled"); err != nil {
+				return nil, err
+			}
+			details["opportunity_id"] = plan.OpportunityID
+		}
+		return outboxLog(c, "polymarket_execution_cancelled", "info", details), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
 	}
-	paas.LogBestEffort(c, "polymarket_execution_cancelled", "info", details)
 	Ok(c, map[string]any{"id": id, "status": "cancelled"}, nil)
 }
 
+// scheduleTrigger makes the plan execute at a time, or a number of minutes
+// before its market's event ends, through preflight and submit. Replaces any
+// existing trigger.
+func (h *V2ExecutionHandler) scheduleTrigger(c *gin.Context) {
+	if h.Triggers == nil {
+		Error(c, http.StatusInternalServerError, "plan triggers unavailable", nil)
+		return
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	var req service.PlanTriggerSpec
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	plan, err := h.Triggers.Schedule(c.Request.Context(), id, req, time.Now().UTC())
+	switch {
+	case errors.Is(err, service.ErrPlanNotSchedulable):
+		Error(c, http.StatusConflict, err.Error(), nil)
+		return
+	case errors.Is(err, service.ErrPlanTriggerInvalid):
+		Error(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	case err != nil:
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	case plan == nil:
+		Error(c, http.StatusNotFound, "execution plan not found", nil)
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_execution_trigger_scheduled", "info", map[string]any{
+		"plan_id":            id,
+		"trigger_at":         plan.TriggerAt,
+		"minutes_before_end": plan.TriggerMinutesBeforeEnd,
+		"market_id":          plan.TriggerMarketID,
+	})
+	Ok(c, plan, nil)
+}
+
+func (h *V2ExecutionHandler) unscheduleTrigger(c *gin.Context) {
+	if h.Triggers == nil {
+		Error(c, http.StatusInternalServerError, "plan triggers unavailable", nil)
+		return
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	removed, err := h.Triggers.Unschedule(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if !removed {
+		Error(c, http.StatusNotFound, "no scheduled trigger", nil)
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_execution_trigger_cancelled", "info", map[string]any{"plan_id": id})
+	Ok(c, map[string]any{"id": id, "trigger_state": ""}, nil)
+}
+
 type upsertPnLRequest struct {
 	ExpectedEdge  *string `json:"expected_edge"`
 	RealizedPnL   *string `json:"realized_pnl"`
@@ -363,14 +526,19 @@ func (h *V2ExecutionHandler) upsertPnL(c *gin.Context) {
 			rec.Notes = &val
 		}
 	}
-	if err := h.Repo.UpsertPnLRecord(c.Request.Context(), rec); err != nil {
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.UpsertPnLRecordTx(c.Request.Context(), tx, rec); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_pnl_upserted", "info", map[string]any{
+			"plan_id": id,
+			"outcome": rec.Outcome,
+		}), nil
+	})
+	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	paas.LogBestEffort(c, "polymarket_pnl_upserted", "info", map[string]any{
-		"plan_id": id,
-		"outcome": rec.Outcome,
-	})
 	Ok(c, rec, nil)
 }
 
@@ -426,8 +594,8 @@ func (h *V2ExecutionHandler) settle(c *gin.Context) {
 	outcomes := map[string]string{}
 	for k, v := range req.MarketOutcomes {
 		mid := strings.TrimSpace(k)
-		val := strings.ToUpper(strings.TrimSpace(v))
-		if mid != "" && (val == "YES" || val == "NO") {
+		val := models.OutcomeCode(v)
+		if mid != "" && val != "" {
 			outcomes[mid] = val
 		}
 	}
@@ -441,8 +609,7 @@ func (h *V2ExecutionHandler) settle(c *gin.Context) {
 			if _, ok := outcomes[mid]; ok {
 				continue
 			}
-			val := strings.ToUpper(strings.TrimSpace(r.Outcome))
-			if val == "YES" || val == "NO" {
+			if val := models.OutcomeCode(r.Outcome); val != "" {
 				outcomes[mid] = val
 			}
 		}
@@ -484,20 +651,14 @@ func (h *V2ExecutionHandler) settle(c *gin.Context) {
 		}
 		outcome := outcomes[mid]
 		payout := decimal.Zero
-		dir := strings.ToUpper(strings.TrimSpace(f.Direction))
-		switch dir {
-		case "BUY_YES":
-			if outcome == "YES" {
-				payout = decimal.NewFromInt(1)
-			}
-		case "BUY_NO":
-			if outcome == "NO" {
-				payout = decimal.NewFromInt(1)
-			}
-		default:
-			// Ignore unknown directions for now.
+		action, dirOutcome, ok := models.SplitDirection(f.Direction)
+		if !ok || action != "BUY" || dirOutcome == "" {
+			// Ignore sells and unknown directions for now.
 			continue
 		}
+		if dirOutcome == outcome {
+			payout = decimal.NewFromInt(1)
+		}
 		cost := f.AvgPrice.Mul(f.FilledSize).Add(f.Fee)
 		pnl := payout.Sub(f.AvgPrice).Mul(f.FilledSize).Sub(f.Fee)
 		totalCost = totalCost.Add(cost)
@@ -529,28 +690,116 @@ func (h *V2ExecutionHandler) settle(c *gin.Context) {
 	} else {
 		rec.Outcome = "partial"
 	}
-	if err := h.Repo.UpsertPnLRecord(c.Request.Context(), rec); err != nil {
+	ctx := c.Request.Context()
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.UpsertPnLRecordTx(ctx, tx, rec); err != nil {
+			return nil, err
+		}
+		// Settlement implies the plan is done from an accounting perspective.
+		if plan.Status != "cancelled" && plan.Status != "failed" {
+			now := time.Now().UTC()
+			if err := h.Repo.UpdateExecutionPlanExecutedAtTx(ctx, tx, id, "executed", &now); err != nil {
+				return nil, err
+			}
+			if plan.OpportunityID > 0 {
+				if err := h.Repo.UpdateOpportunityStatusTx(ctx, tx, plan.OpportunityID, "executed"); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return outboxLog(c, "polymarket_execution_settled", "info", map[string]any{
+			"plan_id":        id,
+			"opportunity_id": plan.OpportunityID,
+			"outcome":        rec.Outcome,
+			"settled_at":     settledAt.Format(time.RFC3339),
+		}), nil
+	})
+	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	// Settlement implies the plan is done from an accounting perspective.
-	if plan.Status != "cancelled" && plan.Status != "failed" {
-		now := time.Now().UTC()
-		_ = h.Repo.UpdateExecutionPlanExecutedAt(c.Request.Context(), id, "executed", &now)
-		_ = h.Repo.UpdateOpportunityStatus(c.Request.Context(), plan.OpportunityID, "executed")
-	}
-	paas.LogBestEffort(c, "polymarket_execution_settled", "info", map[string]any{
-		"plan_id":        id,
-		"opportunity_id": plan.OpportunityID,
-		"outcome":        rec.Outcome,
-		"settled_at":     settledAt.Format(time.RFC3339),
-	})
 	if h.Journal != nil {
 		_ = h.Journal.CaptureExit(c.Request.Context(), id)
 	}
 	Ok(c, rec, nil)
 }
 
+type recalculatePnLRequest struct {
+	// Apply overwrites the stored PnL record with the recalculation.
+	Apply          bool              `json:"apply"`
+	Reason         string            `json:"reason"`
+	MarketOutcomes map[string]string `json:"market_outcomes"` // market_id -> YES|NO
+}
+
+// recalculatePnL recomputes the plan's PnL from its fills and reports where
+// the stored record differs. With apply the record is corrected; a reason
+// is required so the audit trail says why.
+func (h *V2ExecutionHandler) recalculatePnL(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	plan, err := h.Repo.GetExecutionPlanByID(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if plan == nil {
+		Error(c, http.StatusNotFound, "execution plan not found", nil)
+		return
+	}
+	var req recalculatePnLRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			Error(c, http.StatusBadRequest, "invalid body", nil)
+			return
+		}
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Apply && req.Reason == "" {
+		Error(c, http.StatusBadRequest, "reason is required to apply", nil)
+		return
+	}
+	svc := &service.PnLRecalcService{Repo: h.Repo}
+	res, err := svc.Recalculate(c.Request.Context(), id, req.MarketOutcomes)
+	if errors.Is(err, service.ErrNoFills) {
+		Error(c, http.StatusConflict, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	applied := false
+	if req.Apply && len(res.Discrepancies) > 0 {
+		before := res.Stored
+		rec, err := svc.Apply(c.Request.Context(), *plan, res, req.Reason, time.Now().UTC())
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		applied = true
+		fields := map[string]any{
+			"plan_id":       id,
+			"reason":        req.Reason,
+			"realized_pnl":  res.RealizedPnL.String(),
+			"outcome":       rec.Outcome,
+			"discrepancies": res.Discrepancies,
+		}
+		if before != nil && before.RealizedPnL != nil {
+			fields["previous_realized_pnl"] = before.RealizedPnL.String()
+		}
+		logWithOutbox(c, h.Outbox, "polymarket_pnl_recalculated", "warn", fields)
+		res.Stored = rec
+	}
+	Ok(c, gin.H{"recalculation": res, "applied": applied}, nil)
+}
+
 type addFillRequest struct {
 	TokenID     string `json:"token_id"`
 	Direction   string `json:"direction"`
@@ -640,77 +889,203 @@ func (h *V2ExecutionHandler) addFill(c *gin.Context) {
 		FilledAt:   filledAt,
 		CreatedAt:  time.Now().UTC(),
 	}
-	if err := h.Repo.InsertFill(c.Request.Context(), item); err != nil {
+	// The fill, the position, the plan status and the slippage loss commit
+	// together, with the log recorded in the outbox.
+	ctx := c.Request.Context()
+	syncPositions := h.PositionSync.Enabled(ctx)
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.recordFill(ctx, h.Repo.WithTx(tx), *plan, item, syncPositions); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_fill_added", "info", map[string]any{
+			"plan_id":   id,
+			"token_id":  item.TokenID,
+			"direction": item.Direction,
+			"size":      item.FilledSize.String(),
+			"avg_price": item.AvgPrice.String(),
+			"fee":       item.Fee.String(),
+			"filled_at": item.FilledAt.Format(time.RFC3339),
+		}), nil
+	})
+	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	if h.PositionSync != nil {
-		_ = h.PositionSync.SyncFromFill(c.Request.Context(), *item)
+	Ok(c, item, nil)
+}
+
+// importFills records a batch of manual or offline fills from a CSV body (by
+// Content-Type) or JSON. The batch is validated as a whole and rejected on any
+// bad row; rows whose external trade id is already recorded are skipped, so a
+// re-uploaded file only adds what is new. Each imported fill then goes through
+// the same position and PnL updates as a single fill.
+func (h *V2ExecutionHandler) importFills(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
 	}
-	paas.LogBestEffort(c, "polymarket_fill_added", "info", map[string]any{
-		"plan_id":   id,
-		"token_id":  item.TokenID,
-		"direction": item.Direction,
-		"size":      item.FilledSize.String(),
-		"avg_price": item.AvgPrice.String(),
-		"fee":       item.Fee.String(),
-		"filled_at": item.FilledAt.Format(time.RFC3339),
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	plan, err := h.Repo.GetExecutionPlanByID(ctx, id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if plan == nil {
+		Error(c, http.StatusNotFound, "execution plan not found", nil)
+		return
+	}
+	if h.Risk != nil && h.Risk.Config.RequirePreflightPass {
+		switch plan.Status {
+		case "preflight_pass", "executing", "partial":
+			// ok
+		default:
+			Error(c, http.StatusConflict, "preflight required", map[string]any{"status": plan.Status})
+			return
+		}
+	}
+	var rows []service.FillImportRow
+	if strings.Contains(strings.ToLower(c.ContentType()), "csv") {
+		rows, err = service.ParseFillImportCSV(c.Request.Body)
+	} else {
+		var raw []byte
+		if raw, err = c.GetRawData(); err == nil {
+			rows, err = service.ParseFillImportJSON(raw)
+		}
+	}
+	if err != nil {
+		Error(c, http.StatusBadRequest, "invalid body: "+err.Error(), nil)
+		return
+	}
+	if len(rows) == 0 {
+		Error(c, http.StatusBadRequest, "no fills to import", nil)
+		return
+	}
+	fills, rowErrs := service.ValidateFillImport(*plan, rows, time.Now().UTC())
+	if len(rowErrs) > 0 {
+		Error(c, http.StatusBadRequest, "invalid fills", map[string]any{"errors": rowErrs})
+		return
+	}
+	tradeIDs := make([]string, 0, len(fills))
+	for _, f := range fills {
+		tradeIDs = append(tradeIDs, *f.ExternalTradeID)
+	}
+	recorded, err := h.Repo.ListRecordedExternalTradeIDs(ctx, tradeIDs)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	known := make(map[string]struct{}, len(recorded))
+	for _, tradeID := range recorded {
+		known[tradeID] = struct{}{}
+	}
+	fresh := make([]models.Fill, 0, len(fills))
+	duplicates := make([]string, 0, len(recorded))
+	for _, f := range fills {
+		if _, ok := known[*f.ExternalTradeID]; ok {
+			duplicates = append(duplicates, *f.ExternalTradeID)
+			continue
+		}
+		fresh = append(fresh, f)
+	}
+	if c.Query("dry_run") == "true" {
+		Ok(c, gin.H{"imported": 0, "fills": fresh, "duplicates": duplicates}, map[string]any{"dry_run": true})
+		return
+	}
+
+	// The batch commits as a whole, with its position and status updates.
+	syncPositions := h.PositionSync.Enabled(ctx)
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		repo := h.Repo.WithTx(tx)
+		for i := range fresh {
+			if err := h.recordFill(ctx, repo, *plan, &fresh[i], syncPositions); err != nil {
+				return nil, err
+			}
+		}
+		return outboxLog(c, "polymarket_fills_imported", "info", map[string]any{
+			"plan_id":    id,
+			"rows":       len(rows),
+			"imported":   len(fresh),
+			"duplicates": len(duplicates),
+		}), nil
 	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), map[string]any{"imported": 0})
+		return
+	}
+	Ok(c, gin.H{"imported": len(fresh), "fills": fresh, "duplicates": duplicates}, nil)
+}
 
-	// Update plan/opportunity status based on fill coverage.
-	_ = h.updateStatusFromFills(c.Request.Context(), *plan)
+// recordFill inserts a fill through repo and applies it: the position (when
+// syncPositions), the plan and opportunity status from fill coverage, and the
+// plan's slippage loss.
+func (h *V2ExecutionHandler) recordFill(ctx context.Context, repo repository.Repository, plan models.ExecutionPlan, item *models.Fill, syncPositions bool) error {
+	if err := repo.InsertFill(ctx, item); err != nil {
+		return err
+	}
+	if syncPositions {
+		if err := h.PositionSync.ApplyFill(ctx, repo, *item); err != nil {
+			return err
+		}
+	}
+	if err := updateStatusFromFills(ctx, repo, plan); err != nil {
+		return err
+	}
+	return accumulateSlippageLoss(ctx, repo, plan, *item)
+}
 
-	// Update slippage loss (MVP). If the request does not specify slippage, try computing from plan legs.
+// accumulateSlippageLoss adds a fill's slippage loss to the plan's PnL record
+// (MVP). If the fill does not specify slippage, try computing from plan legs.
+func accumulateSlippageLoss(ctx context.Context, repo repository.Repository, plan models.ExecutionPlan, item models.Fill) error {
 	slippageLossDelta := decimal.Zero
 	if item.Slippage != nil {
 		slippageLossDelta = item.Slippage.Mul(item.FilledSize)
-	} else if plan != nil {
-		if target := findTargetPrice(plan.Legs, item.TokenID); target != nil {
-			perShare := item.AvgPrice.Sub(*target)
-			slippageLossDelta = perShare.Mul(item.FilledSize)
-		}
-	}
-	if !slippageLossDelta.IsZero() {
-		rec, _ := h.Repo.GetPnLRecordByPlanID(c.Request.Context(), id)
-		if rec == nil {
-			strategyName := ""
-			if plan != nil {
-				strategyName = plan.StrategyName
-			}
-			rec = &models.PnLRecord{
-				PlanID:       id,
-				StrategyName: strategyName,
-				ExpectedEdge: decimal.Zero,
-				Outcome:      "pending",
-				CreatedAt:    time.Now().UTC(),
-			}
-		}
-		// Keep ExpectedEdge and realized fields as-is; only accumulate slippage loss here.
-		if rec.SlippageLoss == nil {
-			v := slippageLossDelta
-			rec.SlippageLoss = &v
-		} else {
-			v := rec.SlippageLoss.Add(slippageLossDelta)
-			rec.SlippageLoss = &v
-		}
-		if strings.TrimSpace(rec.Outcome) == "" {
-			rec.Outcome = "pending"
+	} else if target := findTargetPrice(plan.Legs, item.TokenID); target != nil {
+		perShare := item.AvgPrice.Sub(*target)
+		slippageLossDelta = perShare.Mul(item.FilledSize)
+	}
+	if slippageLossDelta.IsZero() {
+		return nil
+	}
+	rec, err := repo.GetPnLRecordByPlanID(ctx, plan.ID)
+	if err != nil {
+		return err
+	}
+	if rec == nil {
+		rec = &models.PnLRecord{
+			PlanID:       plan.ID,
+			StrategyName: plan.StrategyName,
+			ExpectedEdge: decimal.Zero,
+			Outcome:      "pending",
+			CreatedAt:    time.Now().UTC(),
 		}
-		_ = h.Repo.UpsertPnLRecord(c.Request.Context(), rec)
 	}
-
-	Ok(c, item, nil)
+	// Keep ExpectedEdge and realized fields as-is; only accumulate slippage loss here.
+	if rec.SlippageLoss == nil {
+		v := slippageLossDelta
+		rec.SlippageLoss = &v
+	} else {
+		v := rec.SlippageLoss.Add(slippageLossDelta)
+		rec.SlippageLoss = &v
+	}
+	if strings.TrimSpace(rec.Outcome) == "" {
+		rec.Outcome = "pending"
+	}
+	return repo.UpsertPnLRecord(ctx, rec)
 }
 
-func (h *V2ExecutionHandler) updateStatusFromFills(ctx context.Context, plan models.ExecutionPlan) error {
-	if h == nil || h.Repo == nil {
-		return nil
-	}
+// updateStatusFromFills moves the plan and its opportunity to executed or
+// partial by fill coverage.
+func updateStatusFromFills(ctx context.Context, repo repository.Repository, plan models.ExecutionPlan) error {
 	switch plan.Status {
 	case "cancelled", "failed":
 		return nil
 	}
-	fills, err := h.Repo.ListFillsByPlanID(ctx, plan.ID)
+	fills, err := repo.ListFillsByPlanID(ctx, plan.ID)
 	if err != nil {
 		return err
 	}
@@ -771,17 +1146,10 @@ func (h *V2ExecutionHandler) updateStatusFromFills(ctx context.Context, plan mod
 			}
 		}
 		if allDone {
-			now := time.Now().UTC()
-			_ = h.Repo.UpdateExecutionPlanExecutedAt(ctx, plan.ID, "executed", &now)
-			_ = h.Repo.UpdateOpportunityStatus(ctx, plan.OpportunityID, "executed")
-			return nil
+			return markPlanFromFills(ctx, repo, plan, "executed")
 		}
 		// Not all legs done => partial once any fill exists.
-		if plan.Status != "partial" {
-			_ = h.Repo.UpdateExecutionPlanStatus(ctx, plan.ID, "partial")
-			_ = h.Repo.UpdateOpportunityStatus(ctx, plan.OpportunityID, "executing")
-		}
-		return nil
+		return markPlanFromFills(ctx, repo, plan, "partial")
 	}
 
 	// Fallback: If we have essentially the full planned stake spent, mark executed.
@@ -789,17 +1157,34 @@ func (h *V2ExecutionHandler) updateStatusFromFills(ctx context.Context, plan mod
 	if planned.GreaterThan(decimal.Zero) {
 		threshold := planned.Mul(decimal.NewFromFloat(0.98))
 		if totalCost.GreaterThanOrEqual(threshold) {
-			now := time.Now().UTC()
-			_ = h.Repo.UpdateExecutionPlanExecutedAt(ctx, plan.ID, "executed", &now)
-			_ = h.Repo.UpdateOpportunityStatus(ctx, plan.OpportunityID, "executed")
-			return nil
+			return markPlanFromFills(ctx, repo, plan, "executed")
 		}
 	}
-	if plan.Status != "partial" {
-		_ = h.Repo.UpdateExecutionPlanStatus(ctx, plan.ID, "partial")
-		_ = h.Repo.UpdateOpportunityStatus(ctx, plan.OpportunityID, "executing")
+	return markPlanFromFills(ctx, repo, plan, "partial")
+}
+
+// markPlanFromFills sets the plan to executed or partial and its
+// opportunity, if any (manual plans have none), to executed or executing.
+func markPlanFromFills(ctx context.Context, repo repository.Repository, plan models.ExecutionPlan, status string) error {
+	oppStatus := "executing"
+	switch {
+	case status == "executed":
+		now := time.Now().UTC()
+		if err := repo.UpdateExecutionPlanExecutedAt(ctx, plan.ID, status, &now); err != nil {
+			return err
+		}
+		oppStatus = "executed"
+	case plan.Status == status:
+		return nil
+	default:
+		if err := repo.UpdateExecutionPlanStatus(ctx, plan.ID, status); err != nil {
+			return err
+		}
 	}
-	return nil
+	if plan.OpportunityID == 0 {
+		return nil
+	}
+	return repo.UpdateOpportunityStatus(ctx, plan.OpportunityID, oppStatus)
 }
 
 func findTargetPrice(legsJSON []byte, tokenID string) *decimal.Decimal {
@@ -850,22 +1235,3 @@ func planMarketIDsFromLegs(legsJSON []byte) []string {
 	}
 	return out
 }
-
-func preflightFailureReason(res risk.PreflightResult) string {
-	for _, chk := range res.Checks {
-		if chk.Status != "fail" {
-			continue
-		}
-		switch chk.Name {
-		case "data_freshness":
-			return "latency"
-		case "edge_recheck":
-			return "price_jump"
-		case "capital_limit":
-			return "rule_mismatch"
-		default:
-			// keep looking
-		}
-	}
-	return ""
-}
@@ -0,0 +1,364 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/service"
+)
+
+type manualPlanLeg struct {
+	TokenID     string   `json:"token_id"`
+	MarketID    string   `json:"market_id"`
+	Direction   string   `json:"direction"`
+	SizeUSD     float64  `json:"size_usd"`
+	TargetPrice *float64 `json:"target_price"`
+}
+
+type createManualPlanRequest struct {
+	StrategyName  string          `json:"strategy_name"`
+	Legs          []manualPlanLeg `json:"legs"`
+	Params        map[string]any  `json:"params"`
+	Note          string          `json:"note"`
+	SkipPreflight bool            `json:"skip_preflight"`
+	CampaignID    *uint64         `json:"campaign_id"`
+}
+
+// manualPlanParam is a plan param a manual plan may set, with the values it
+// accepts.
+type manualPlanParam struct {
+	want  string
+	valid func(v any) bool
+}
+
+// manualPlanParams are the params the executor and preflight read. Plan params
+// are decoded into typed structs, so one value of the wrong type would drop
+// every override; unknown keys are rejected for the same reason a typo is.
+var manualPlanParams = map[string]manualPlanParam{
+	"slippage_tolerance":          {"a number between 0 and 1", paramNumber(func(f float64) bool { return f >= 0 && f <= 1 })},
+	"max_capital":                 {"a non-negative number", paramNumber(func(f float64) bool { return f >= 0 })},
+	"time_limit_seconds":          {"a positive whole number", paramNumber(func(f float64) bool { return f > 0 && f == float64(int64(f)) })},
+	"execution_order":             {"sequential or parallel", paramOneOf("sequential", "parallel")},
+	"limit_vs_market":             {"limit or market", paramOneOf("limit", "market")},
+	"pricing_mode":                {"taker or maker", paramOneOf(service.PricingModeTaker, service.PricingModeMaker)},
+	"maker_improvement":           {"a number between 0 and 1", paramNumber(func(f float64) bool { return f >= 0 && f < 1 })},
+	"maker_step_size":             {"a number between 0 and 1", paramNumber(func(f float64) bool { return f > 0 && f < 1 })},
+	"maker_step_interval_seconds": {"a positive number", paramNumber(func(f float64) bool { return f > 0 })},
+	"maker_taker_after_seconds":   {"a non-negative number", paramNumber(func(f float64) bool { return f >= 0 })},
+	"note":                        {"a string", func(v any) bool { _, ok := v.(string); return ok }},
+}
+
+func paramNumber(ok func(float64) bool) func(v any) bool {
+	return func(v any) bool {
+		f, isNum := v.(float64)
+		return isNum && ok(f)
+	}
+}
+
+func paramOneOf(values ...string) func(v any) bool {
+	return func(v any) bool {
+		str, ok := v.(string)
+		if !ok {
+			return false
+		}
+		for _, want := range values {
+			if strings.EqualFold(strings.TrimSpace(str), want) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// validateManualPlanParams checks params against manualPlanParams.
+func validateManualPlanParams(params map[string]any) error {
+	for key, v := range params {
+		p, ok := manualPlanParams[key]
+		if !ok {
+			return fmt.Errorf("unknown param %s", key)
+		}
+		if !p.valid(v) {
+			return fmt.Errorf("param %s must be %s", key, p.want)
+		}
+	}
+	return nil
+}
+
+// createManual builds a plan from raw legs instead of an opportunity. The plan
+// goes through the same sizing and preflight as opportunity plans, so the
+// executor, journal and analytics treat it like any other plan.
+func (h *V2ExecutionHandler) createManual(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	var req createManualPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if len(req.Legs) == 0 {
+		Error(c, http.StatusBadRequest, "legs required", nil)
+		return
+	}
+	if err := validateManualPlanParams(req.Params); err != nil {
+		Error(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	ctx := c.Request.Context()
+
+	tokenIDs := make([]string, 0, len(req.Legs))
+	requested := decimal.Zero
+	for i := range req.Legs {
+		leg := &req.Legs[i]
+		leg.TokenID = strings.TrimSpace(leg.TokenID)
+		leg.MarketID = strings.TrimSpace(leg.MarketID)
+		leg.Direction = strings.ToUpper(strings.TrimSpace(leg.Direction))
+		if leg.TokenID == "" {
+			Error(c, http.StatusBadRequest, "leg token_id required", map[string]any{"leg": i})
+			return
+		}
+		if leg.Direction != "" {
+			if _, outcome, ok := models.SplitDirection(leg.Direction); !ok || outcome == "" {
+				Error(c, http.StatusBadRequest, "invalid leg direction", map[string]any{"leg": i, "direction": leg.Direction})
+				return
+			}
+		}
+		if leg.SizeUSD <= 0 {
+			Error(c, http.StatusBadRequest, "leg size_usd must be positive", map[string]any{"leg": i})
+			return
+		}
+		if leg.TargetPrice != nil && (*leg.TargetPrice <= 0 || *leg.TargetPrice >= 1) {
+			Error(c, http.StatusBadRequest, "leg target_price must be between 0 and 1", map[string]any{"leg": i})
+			return
+		}
+		tokenIDs = append(tokenIDs, leg.TokenID)
+		requested = requested.Add(decimal.NewFromFloat(leg.SizeUSD))
+	}
+
+	tokens, err := h.Repo.ListTokensByIDs(ctx, tokenIDs)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	tokenByID := map[string]models.Token{}
+	for _, tok := range tokens {
+		tokenByID[tok.ID] = tok
+	}
+	marketIDs := make([]string, 0, len(req.Legs))
+	for i := range req.Legs {
+		leg := &req.Legs[i]
+		tok, ok := tokenByID[leg.TokenID]
+		if !ok {
+			Error(c, http.StatusBadRequest, "unknown token_id", map[string]any{"leg": i, "token_id": leg.TokenID})
+			return
+		}
+		// Directions name the outcome traded: BUY_YES and BUY_NO on binary
+		// tokens, BUY_<OUTCOME> on the matching token of other markets.
+		code := models.OutcomeCode(tok.Outcome)
+		binary := code == "" || code == models.OutcomeYes || code == models.OutcomeNo
+		if leg.Direction == "" {
+			leg.Direction = "BUY_YES"
+			if !binary {
+				leg.Direction = models.Direction("BUY", code)
+			}
+		}
+		if _, outcome, _ := models.SplitDirection(leg.Direction); !binary && outcome != code {
+			Error(c, http.StatusBadRequest, "leg direction does not match token outcome", map[string]any{"leg": i, "direction": leg.Direction, "outcome": tok.Outcome})
+			return
+		}
+		marketID := strings.TrimSpace(tok.MarketID)
+		if leg.MarketID != "" && marketID != "" && leg.MarketID != marketID {
+			Error(c, http.StatusBadRequest, "token_id does not belong to market_id", map[string]any{"leg": i})
+			return
+		}
+		if leg.MarketID == "" {
+			leg.MarketID = marketID
+		}
+		marketIDs = append(marketIDs, leg.MarketID)
+	}
+
+	stratName := strings.TrimSpace(req.StrategyName)
+	if stratName == "" {
+		stratName = "manual"
+	}
+	tenant, ok := h.manualPlanTenant(c, stratName, req.CampaignID)
+	if !ok {
+		return
+	}
+
+	plannedSize := requested
+	maxLoss := plannedSize
+	var kellyFraction *float64
+	warnings := []string{}
+	if h.Risk != nil {
+		// Size against the same caps as opportunity plans.
+		marketIDsJSON, _ := json.Marshal(marketIDs)
+		ps, ml, kf, ws := h.Risk.SuggestPlanSizing(ctx, models.Opportunity{
+			MaxSize:   requested,
+			MarketIDs: datatypes.JSON(marketIDsJSON),
+			Tenant:    tenant,
+		}, stratName)
+		plannedSize = ps
+		maxLoss = ml
+		kellyFraction = kf
+		warnings = append(warnings, ws...)
+	}
+	if !plannedSize.IsPositive() {
+		Error(c, http.StatusConflict, "no risk capacity for manual plan", map[string]any{"sizing_warnings": warnings})
+		return
+	}
+
+	if req.CampaignID != nil {
+		if _, err := h.Campaigns.Admit(ctx, *req.CampaignID, tenant, plannedSize, time.Now().UTC()); err != nil {
+			campaignError(c, err)
+			return
+		}
+	}
+
+	// Scale legs down proportionally when risk caps trimmed the request.
+	scale := plannedSize.Div(requested)
+	legs := make([]map[string]any, 0, len(req.Legs))
+	for i, leg := range req.Legs {
+		size, _ := decimal.NewFromFloat(leg.SizeUSD).Mul(scale).Round(2).Float64()
+		item := map[string]any{
+			"token_id":  leg.TokenID,
+			"market_id": leg.MarketID,
+			"direction": leg.Direction,
+			"size_usd":  size,
+			"priority":  i + 1,
+		}
+		if leg.TargetPrice != nil {
+			item["target_price"] = *leg.TargetPrice
+		}
+		legs = append(legs, item)
+	}
+	legsJSON, _ := json.Marshal(legs)
+
+	params := map[string]any{
+		"slippage_tolerance": 0.02,
+		"execution_order":    "sequential",
+		"limit_vs_market":    "limit",
+		"time_limit_seconds": 300,
+	}
+	for k, v := range req.Params {
+		params[k] = v
+	}
+	if note := strings.TrimSpace(req.Note); note != "" {
+		params["note"] = note
+	}
+	paramsJSON, _ := json.Marshal(params)
+
+	now := time.Now().UTC()
+	plan := &models.ExecutionPlan{
+		Source:          "manual",
+		Tenant:          tenant,
+		CampaignID:      req.CampaignID,
+		Status:          "draft",
+		StrategyName:    stratName,
+		PlannedSizeUSD:  plannedSize,
+		MaxLossUSD:      maxLoss,
+		KellyFraction:   kellyFraction,
+		Params:          datatypes.JSON(paramsJSON),
+		PreflightResult: datatypes.JSON([]byte(`{}`)),
+		Legs:            datatypes.JSON(legsJSON),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.InsertExecutionPlanTx(ctx, tx, plan); err != nil {
+			return nil, err
+		}
+		// Seed a PnL record so analytics include manual plans from the start.
+		if err := h.Repo.UpsertPnLRecordTx(ctx, tx, &models.PnLRecord{
+			PlanID:       plan.ID,
+			StrategyName: plan.StrategyName,
+			ExpectedEdge: decimal.Zero,
+			Outcome:      "pending",
+			CreatedAt:    now,
+		}); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_execution_plan_created", "info", map[string]any{
+			"plan_id":          plan.ID,
+			"source":           plan.Source,
+			"strategy":         plan.StrategyName,
+			"legs":             len(legs),
+			"planned_size_usd": plan.PlannedSizeUSD.String(),
+			"max_loss_usd":     plan.MaxLossUSD.String(),
+			"warnings":         warnings,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+
+	out := map[string]any{"sizing_warnings": warnings}
+	if h.Costs != nil {
+		// Manual plans carry no edge estimate, so the forecast is all cost.
+		if fc, err := h.Costs.Forecast(ctx, models.Opportunity{Legs: plan.Legs}, plannedSize); err == nil {
+			_ = h.Costs.Record(ctx, plan, fc)
+			out["cost_forecast"] = fc
+		}
+	}
+	if h.Risk != nil && !req.SkipPreflight {
+		result, err := h.Risk.PreflightPlan(ctx, plan.ID)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		out["preflight"] = result
+	}
+	if next, _ := h.Repo.GetExecutionPlanByID(ctx, plan.ID); next != nil {
+		plan = next
+	}
+	out["plan"] = plan
+
+	Ok(c, out, nil)
+}
+
+// manualPlanTenant is the desk a manual plan is stamped with: the request's
+// scope, or for unscoped requests the desk of the named strategy or campaign.
+// A scoped request may not trade another desk's strategy. It writes the error
+// response and returns false when there is no desk to stamp.
+func (h *V2ExecutionHandler) manualPlanTenant(c *gin.Context, stratName string, campaignID *uint64) (string, bool) {
+	ctx := c.Request.Context()
+	strat, err := h.Repo.GetStrategyByName(ctx, stratName)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return "", false
+	}
+	if tenant := paas.TenantFromGin(c); tenant != "" {
+		if strat != nil && !tenantVisible(c, strat.Tenant) {
+			Error(c, http.StatusNotFound, "strategy not found", nil)
+			return "", false
+		}
+		return tenant, true
+	}
+	if strat != nil && strings.TrimSpace(strat.Tenant) != "" {
+		return paas.NormalizeTenant(strat.Tenant), true
+	}
+	if campaignID != nil {
+		campaign, err := h.Repo.GetCampaignByID(ctx, *campaignID)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return "", false
+		}
+		if campaign != nil && strings.TrimSpace(campaign.Tenant) != "" {
+			return paas.NormalizeTenant(campaign.Tenant), true
+		}
+	}
+	Error(c, http.StatusBadRequest, "tenant required: scope the request to a project or name a known strategy or campaign", nil)
+	return "", false
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	gormrepository "polymarket/internal/repository/gorm"
+	"polymarket/internal/service"
+)
+
+func TestRecordFillCommitsWithItsFollowUps(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := db.AutoMigrate(conn); err != nil {
+		t.Fatal(err)
+	}
+	store := gormrepository.New(conn.Gorm)
+	ctx := context.Background()
+	plan := models.ExecutionPlan{
+		StrategyName:   "manual",
+		Status:         "executing",
+		PlannedSizeUSD: decimal.NewFromInt(100),
+		MaxLossUSD:     decimal.NewFromInt(100),
+		Legs:           datatypes.JSON(`[{"token_id":"t1","target_price":0.40}]`),
+	}
+	if err := conn.Gorm.Create(&plan).Error; err != nil {
+		t.Fatal(err)
+	}
+	h := &V2ExecutionHandler{Repo: store}
+	fill := func() *models.Fill {
+		return &models.Fill{PlanID: plan.ID, TokenID: "t1", Direction: "BUY_YES", FilledSize: decimal.NewFromInt(10), AvgPrice: decimal.RequireFromString("0.42"), FilledAt: time.Now().UTC()}
+	}
+
+	// A failure after the fill rolls it back with everything it touched.
+	boom := errors.New("boom")
+	err = store.InTx(ctx, func(tx *gorm.DB) error {
+		if err := h.recordFill(ctx, store.WithTx(tx), plan, fill(), false); err != nil {
+			return err
+		}
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("tx err = %v", err)
+	}
+	if fills, _ := store.ListFillsByPlanID(ctx, plan.ID); len(fills) != 0 {
+		t.Fatalf("fills after rollback = %d", len(fills))
+	}
+	if rec, _ := store.GetPnLRecordByPlanID(ctx, plan.ID); rec != nil {
+		t.Fatalf("pnl record after rollback = %+v", rec)
+	}
+
+	err = store.InTx(ctx, func(tx *gorm.DB) error {
+		return h.recordFill(ctx, store.WithTx(tx), plan, fill(), false)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := store.GetExecutionPlanByID(ctx, plan.ID)
+	if got == nil || got.Status != "partial" {
+		t.Fatalf("plan = %+v", got)
+	}
+	rec, _ := store.GetPnLRecordByPlanID(ctx, plan.ID)
+	if rec == nil || rec.SlippageLoss == nil || !rec.SlippageLoss.Equal(decimal.RequireFromString("0.2")) {
+		t.Fatalf("pnl record = %+v", rec)
+	}
+}
+
+func TestValidateManualPlanParams(t *testing.T) {
+	ok := map[string]any{"slippage_tolerance": 0.05, "time_limit_seconds": float64(120), "pricing_mode": "Maker", "note": "hedge"}
+	if err := validateManualPlanParams(ok); err != nil {
+		t.Fatalf("valid params: %v", err)
+	}
+	for _, bad := range []map[string]any{
+		{"slippage_tolerance": "0.05"},
+		{"slippage_tolerance": 1.5},
+		{"time_limit_seconds": 1.5},
+		{"pricing_mode": "aggressive"},
+		{"slipage_tolerance": 0.05},
+	} {
+		if err := validateManualPlanParams(bad); err == nil {
+			t.Fatalf("params %v accepted", bad)
+		}
+	}
+}
+
+func TestCreateManualPlan(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := db.AutoMigrate(conn); err != nil {
+		t.Fatal(err)
+	}
+	store := gormrepository.New(conn.Gorm)
+	now := time.Now().UTC()
+	for _, tok := range []models.Token{
+		{ID: "yes1", MarketID: "m1", Outcome: "Yes", LastSeenAt: now, RawJSON: datatypes.JSON(`{}`)},
+		{ID: "no1", MarketID: "m1", Outcome: "No", OutcomeIndex: 1, LastSeenAt: now, RawJSON: datatypes.JSON(`{}`)},
+	} {
+		if err := conn.Gorm.Create(&tok).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, st := range []models.Strategy{
+		{Name: "desk_a_arb", DisplayName: "A", Category: "arb", Tenant: "desk-a", Params: datatypes.JSON(`{}`)},
+		{Name: "desk_b_arb", DisplayName: "B", Category: "arb", Tenant: "desk-b", Params: datatypes.JSON(`{}`)},
+	} {
+		if err := conn.Gorm.Create(&st).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	// Stands in for the PaaS tenant middleware.
+	r.Use(func(c *gin.Context) {
+		if p := c.GetHeader("X-Easyweb3-Project"); p != "" {
+			c.Request = c.Request.WithContext(paas.WithTenant(c.Request.Context(), p))
+		}
+	})
+	h := &V2ExecutionHandler{Repo: store, Costs: &service.CostForecaster{Repo: store}}
+	h.Register(r)
+
+	post := func(project, body string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/executions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if project != "" {
+			req.Header.Set("X-Easyweb3-Project", project)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var out map[string]any
+		_ = json.Unmarshal(w.Body.Bytes(), &out)
+		return w.Code, out
+	}
+
+	leg := `{"token_id":"yes1","size_usd":20}`
+	cases := []struct {
+		name    string
+		project string
+		body    string
+		status  int
+		message string
+	}{
+		{"no legs", "desk-a", `{"legs":[]}`, http.StatusBadRequest, "legs required"},
+		{"unknown param", "desk-a", `{"legs":[` + leg + `],"params":{"slipage":0.1}}`, http.StatusBadRequest, "unknown param slipage"},
+		{"param of wrong type", "desk-a", `{"legs":[` + leg + `],"params":{"slippage_tolerance":"0.1"}}`, http.StatusBadRequest, "param slippage_tolerance must be a number between 0 and 1"},
+		{"param out of range", "desk-a", `{"legs":[` + leg + `],"params":{"time_limit_seconds":0}}`, http.StatusBadRequest, "param time_limit_seconds must be a positive whole number"},
+		{"bad enum param", "desk-a", `{"legs":[` + leg + `],"params":{"pricing_mode":"aggressive"}}`, http.StatusBadRequest, "param pricing_mode must be taker or maker"},
+		{"non-positive size", "desk-a", `{"legs":[{"token_id":"yes1","size_usd":0}]}`, http.StatusBadRequest, "leg size_usd must be positive"},
+		{"target price out of range", "desk-a", `{"legs":[{"token_id":"yes1","size_usd":5,"target_price":1.2}]}`, http.StatusBadRequest, "leg target_price must be between 0 and 1"},
+		{"bad direction", "desk-a", `{"legs":[{"token_id":"yes1","size_usd":5,"direction":"HOLD"}]}`, http.StatusBadRequest, "invalid leg direction"},
+		{"unknown token", "desk-a", `{"legs":[{"token_id":"zzz","size_usd":5}]}`, http.StatusBadRequest, "unknown token_id"},
+		{"token outside market", "desk-a", `{"legs":[{"token_id":"yes1","market_id":"m2","size_usd":5}]}`, http.StatusBadRequest, "token_id does not belong to market_id"},
+		{"unscoped without a desk", "", `{"legs":[` + leg + `]}`, http.StatusBadRequest, "tenant required: scope the request to a project or name a known strategy or campaign"},
+		{"other desk's strategy", "desk-a", `{"strategy_name":"desk_b_arb","legs":[` + leg + `]}`, http.StatusNotFound, "strategy not found"},
+	}
+	for _, tc := range cases {
+		status, out := post(tc.project, tc.body)
+		if status != tc.status || out["message"] != tc.message {
+			t.Errorf("%s: %d %v, want %d %q", tc.name, status, out["message"], tc.status, tc.message)
+		}
+	}
+	var plans int64
+	conn.Gorm.Model(&models.ExecutionPlan{}).Count(&plans)
+	if plans != 0 {
+		t.Fatalf("rejected requests created %d plans", plans)
+	}
+
+	// Scoped requests stamp the request's desk; unscoped ones the strategy's.
+	for _, tc := range []struct{ project, body, tenant string }{
+		{"desk-a", `{"legs":[` + leg + `,{"token_id":"no1","size_usd":10,"direction":"buy_no"}],"params":{"slippage_tolerance":0.05}}`, "desk-a"},
+		{"", `{"strategy_name":"desk_b_arb","legs":[` + leg + `]}`, "desk-b"},
+	} {
+		status, out := post(tc.project, tc.body)
+		if status != http.StatusOK {
+			t.Fatalf("create: %d %v", status, out)
+		}
+		data := out["data"].(map[string]any)
+		plan := data["plan"].(map[string]any)
+		if plan["Tenant"] != tc.tenant || plan["Source"] != "manual" {
+			t.Fatalf("plan = %v", plan)
+		}
+		if data["cost_forecast"] == nil {
+			t.Fatalf("no cost forecast in %v", data)
+		}
+		fc, err := store.GetCostForecastByPlanID(context.Background(), uint64(plan["ID"].(float64)))
+		if err != nil || fc == nil {
+			t.Fatalf("recorded forecast = %v, %v", fc, err)
+		}
+	}
+}
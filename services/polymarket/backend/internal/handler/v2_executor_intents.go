@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2ExecutorIntentHandler shows the executor's write-ahead intent log and
+// reconciles intents left pending by a crash.
+type V2ExecutorIntentHandler struct {
+	Repo     repository.Repository
+	Executor *service.CLOBExecutor
+}
+
+func (h *V2ExecutorIntentHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/executor/intents")
+	group.GET("", validateQuery[executorIntentQuery](), h.list)
+	group.POST("/reconcile", h.reconcile)
+}
+
+type executorIntentQuery struct {
+	pageQuery
+	Status  *string `form:"status" binding:"omitempty,oneof=pending resolved failed reconciled orphaned"`
+	Action  *string `form:"action" binding:"omitempty,oneof=submit cancel amend"`
+	OrderID *uint64 `form:"order_id" binding:"omitempty,min=1"`
+	PlanID  *uint64 `form:"plan_id" binding:"omitempty,min=1"`
+}
+
+func (h *V2ExecutorIntentHandler) list(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[executorIntentQuery](c)
+	params := repository.ListExecutorIntentsParams{
+		Limit:   q.Limit,
+		Offset:  q.Offset,
+		Tenant:  tenantScope(c),
+		Status:  q.Status,
+		Action:  q.Action,
+		OrderID: q.OrderID,
+		PlanID:  q.PlanID,
+	}
+	items, err := h.Repo.ListExecutorIntents(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountExecutorIntents(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	meta := paginationMeta(q.Limit, q.Offset, total)
+	if tenantScope(c) == nil {
+		// The status counts span every desk.
+		byStatus, err := h.Repo.CountExecutorIntentsByStatus(c.Request.Context())
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		meta["by_status"] = byStatus
+	}
+	Ok(c, items, meta)
+}
+
+// reconcile runs the startup reconcile pass on demand, e.g. after the venue
+// was unreachable when the service started.
+func (h *V2ExecutorIntentHandler) reconcile(c *gin.Context) {
+	if h.Executor == nil {
+		Error(c, http.StatusInternalServerError, "executor unavailable", nil)
+		return
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "intent reconcile requires an unscoped token", nil)
+		return
+	}
+	res, err := h.Executor.ReconcileIntents(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, res, nil)
+}
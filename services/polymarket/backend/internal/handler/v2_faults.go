@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/chaos"
+	"polymarket/internal/service"
+)
+
+// V2FaultHandler drives fault injection for resilience drills: Gamma and
+// CLOB client errors, artificial latency, DB failures and market stream
+// drops. Every route answers 404 unless chaos is enabled outside
+// production.
+type V2FaultHandler struct {
+	Injector *chaos.Injector
+	// Outbox records the audit logs of fault changes.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2FaultHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/system/faults")
+	group.GET("", h.list)
+	group.PUT("/:target", h.set)
+	group.DELETE("/:target", h.clear)
+	group.DELETE("", h.clearAll)
+}
+
+type faultRequest struct {
+	ErrorRate  float64 `json:"error_rate"`
+	LatencyMS  int     `json:"latency_ms"`
+	StatusCode int     `json:"status_code"`
+	Remaining  int     `json:"remaining"`
+	// Duration is how long the fault lasts, e.g. "5m"; empty or longer than
+	// chaos.max_duration uses the maximum.
+	Duration string `json:"duration"`
+}
+
+func (h *V2FaultHandler) allowed(c *gin.Context) bool {
+	if h.Injector == nil {
+		Error(c, http.StatusNotFound, "fault injection disabled", nil)
+		return false
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "fault injection requires an unscoped token", nil)
+		return false
+	}
+	return true
+}
+
+func (h *V2FaultHandler) list(c *gin.Context) {
+	if !h.allowed(c) {
+		return
+	}
+	Ok(c, h.Injector.List(), map[string]any{"targets": chaos.Targets})
+}
+
+func (h *V2FaultHandler) set(c *gin.Context) {
+	if !h.allowed(c) {
+		return
+	}
+	var req faultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	var duration time.Duration
+	if raw := strings.TrimSpace(req.Duration); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			Error(c, http.StatusBadRequest, "invalid duration", nil)
+			return
+		}
+		duration = d
+	}
+	fault, err := h.Injector.Set(chaos.Fault{
+		Target:     c.Param("target"),
+		ErrorRate:  req.ErrorRate,
+		LatencyMS:  req.LatencyMS,
+		StatusCode: req.StatusCode,
+		Remaining:  req.Remaining,
+	}, duration)
+	if err != nil {
+		Error(c, http.StatusBadRequest, err.Error(), map[string]any{"targets": chaos.Targets})
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_fault_injected", "warn", map[string]any{
+		"target":      fault.Target,
+		"error_rate":  fault.ErrorRate,
+		"latency_ms":  fault.LatencyMS,
+		"status_code": fault.StatusCode,
+		"remaining":   fault.Remaining,
+		"expires_at":  fault.ExpiresAt,
+	})
+	Ok(c, fault, nil)
+}
+
+func (h *V2FaultHandler) clear(c *gin.Context) {
+	if !h.allowed(c) {
+		return
+	}
+	target := strings.ToLower(strings.TrimSpace(c.Param("target")))
+	h.Injector.Clear(target)
+	logWithOutbox(c, h.Outbox, "polymarket_fault_cleared", "info", map[string]any{"target": target})
+	Ok(c, map[string]any{"cleared": target}, nil)
+}
+
+func (h *V2FaultHandler) clearAll(c *gin.Context) {
+	if !h.allowed(c) {
+		return
+	}
+	h.Injector.Clear("")
+	logWithOutbox(c, h.Outbox, "polymarket_fault_cleared", "info", map[string]any{"target": "all"})
+	Ok(c, map[string]any{"cleared": "all"}, nil)
+}
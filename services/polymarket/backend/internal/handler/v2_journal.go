@@ -16,58 +16,35 @@ type V2JournalHandler struct {
 }
 
 func (h *V2JournalHandler) Register(r *gin.Engine) {
-	g := r.Group("/api/v2/journal")
-	g.GET("", h.list)
+	g := r.Group("/api/v2/journal", tenantGuard("execution_plan_id", "journal not found", h.journalTenant))
+	g.GET("", validateQuery[listJournalQuery](), h.list)
 	g.GET("/:execution_plan_id", h.get)
 	g.PUT("/:execution_plan_id/notes", h.putNotes)
 }
 
+type listJournalQuery struct {
+	pageQuery
+	timeRangeQuery
+	StrategyName *string  `form:"strategy_name"`
+	Outcome      *string  `form:"outcome"`
+	Tags         []string `form:"tags"`
+}
+
 func (h *V2JournalHandler) list(c *gin.Context) {
 	if h.Repo == nil {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	limit := intQuery(c, "limit", 50)
-	offset := intQuery(c, "offset", 0)
-	var strategyName *string
-	if v := strings.TrimSpace(c.Query("strategy_name")); v != "" {
-		strategyName = &v
-	}
-	var outcome *string
-	if v := strings.TrimSpace(c.Query("outcome")); v != "" {
-		outcome = &v
-	}
-	var since *time.Time
-	if v := strings.TrimSpace(c.Query("since")); v != "" {
-		if ts, err := time.Parse(time.RFC3339, v); err == nil {
-			t := ts.UTC()
-			since = &t
-		}
-	}
-	var until *time.Time
-	if v := strings.TrimSpace(c.Query("until")); v != "" {
-		if ts, err := time.Parse(time.RFC3339, v); err == nil {
-			t := ts.UTC()
-			until = &t
-		}
-	}
-	var tags []string
-	if raw := strings.TrimSpace(c.Query("tags")); raw != "" {
-		for _, v := range strings.Split(raw, ",") {
-			tag := strings.TrimSpace(v)
-			if tag != "" {
-				tags = append(tags, tag)
-			}
-		}
-	}
+	q := queryOf[listJournalQuery](c)
 	params := repository.ListTradeJournalParams{
-		Limit:        limit,
-		Offset:       offset,
-		StrategyName: strategyName,
-		Outcome:      outcome,
-		Since:        since,
-		Until:        until,
-		Tags:         tags,
+		Limit:        q.Limit,
+		Offset:       q.Offset,
+		Tenant:       tenantScope(c),
+		StrategyName: q.StrategyName,
+		Outcome:      q.Outcome,
+		Since:        q.Since,
+		Until:        q.Until,
+		Tags:         q.Tags,
 		OrderBy:      "created_at",
 		Asc:          boolPtr(false),
 	}
@@ -81,7 +58,7 @@ func (h *V2JournalHandler) list(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, items, paginationMeta(limit, offset, total))
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
 }
 
 func (h *V2JournalHandler) get(c *gin.Context) {
@@ -110,6 +87,8 @@ type putJournalNotesRequest struct {
 	Notes      string   `json:"notes"`
 	Tags       []string `json:"tags"`
 	ReviewedAt *string  `json:"reviewed_at"`
+	// SnapshotIDs, when present, replaces the referenced research snapshots.
+	SnapshotIDs *[]uint64 `json:"snapshot_ids"`
 }
 
 func (h *V2JournalHandler) putNotes(c *gin.Context) {
@@ -137,11 +116,29 @@ func (h *V2JournalHandler) putNotes(c *gin.Context) {
 		t := ts.UTC()
 		reviewedAt = &t
 	}
+	if req.SnapshotIDs != nil {
+		ok, err := checkSnapshotRefs(c, h.Repo, *req.SnapshotIDs)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		if !ok {
+			Error(c, http.StatusBadRequest, "unknown snapshot_ids", nil)
+			return
+		}
+	}
 	tagsRaw, _ := json.Marshal(req.Tags)
 	if err := h.Repo.UpdateTradeJournalNotes(c.Request.Context(), planID, req.Notes, tagsRaw, reviewedAt); err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
+	if req.SnapshotIDs != nil {
+		idsRaw, _ := json.Marshal(*req.SnapshotIDs)
+		if err := h.Repo.UpdateTradeJournalSnapshots(c.Request.Context(), planID, idsRaw); err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+	}
 	item, _ := h.Repo.GetTradeJournalByPlanID(c.Request.Context(), planID)
 	Ok(c, item, nil)
 }
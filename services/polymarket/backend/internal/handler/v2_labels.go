@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"polymarket/internal/governor"
 	"polymarket/internal/labeler"
 	"polymarket/internal/models"
 	"polymarket/internal/repository"
@@ -14,14 +15,23 @@ import (
 type V2LabelHandler struct {
 	Repo    repository.Repository
 	Labeler *labeler.MarketLabeler
+	// Governor, when set, queues auto-label runs behind other heavy jobs.
+	Governor *governor.Governor
 }
 
 func (h *V2LabelHandler) Register(r *gin.Engine) {
 	group := r.Group("/api/v2/markets")
-	group.GET("/labels", h.listLabels)
+	group.GET("/labels", validateQuery[listLabelsQuery](), h.listLabels)
 	group.POST("/:id/labels", h.addLabel)
 	group.DELETE("/:id/labels/:label", h.deleteLabel)
-	group.POST("/auto-label", h.autoLabel)
+	group.POST("/auto-label", heavyJob(h.Governor, governor.ClassBackfill), h.autoLabel)
+}
+
+type listLabelsQuery struct {
+	Limit    int     `form:"limit" default:"200" binding:"min=1,max=1000"`
+	Offset   int     `form:"offset" default:"0" binding:"min=0"`
+	MarketID *string `form:"market_id"`
+	Label    *string `form:"label"`
 }
 
 func (h *V2LabelHandler) listLabels(c *gin.Context) {
@@ -29,25 +39,12 @@ func (h *V2LabelHandler) listLabels(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	marketID := strings.TrimSpace(c.Query("market_id"))
-	label := strings.TrimSpace(c.Query("label"))
-	limit := intQuery(c, "limit", 200)
-	offset := intQuery(c, "offset", 0)
-
-	var marketPtr *string
-	if marketID != "" {
-		marketPtr = &marketID
-	}
-	var labelPtr *string
-	if label != "" {
-		labelPtr = &label
-	}
-
+	q := queryOf[listLabelsQuery](c)
 	items, err := h.Repo.ListMarketLabels(c.Request.Context(), repository.ListMarketLabelsParams{
-		Limit:    limit,
-		Offset:   offset,
-		MarketID: marketPtr,
-		Label:    labelPtr,
+		Limit:    q.Limit,
+		Offset:   q.Offset,
+		MarketID: q.MarketID,
+		Label:    q.Label,
 		OrderBy:  "created_at",
 		Asc:      boolPtr(false),
 	})
@@ -55,7 +52,7 @@ func (h *V2LabelHandler) listLabels(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	meta := paginationMeta(limit, offset, int64(len(items)))
+	meta := paginationMeta(q.Limit, q.Offset, int64(len(items)))
 	Ok(c, items, meta)
 }
 
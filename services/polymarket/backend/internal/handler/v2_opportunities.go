@@ -1,43 +1,63 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shopspring/decimal"
-	"gorm.io/datatypes"
+	"gorm.io/gorm"
 
 	"polymarket/internal/models"
-	"polymarket/internal/paas"
 	"polymarket/internal/repository"
 	"polymarket/internal/risk"
+	"polymarket/internal/service"
 )
 
 type V2OpportunityHandler struct {
-	Repo repository.Repository
-	Risk *risk.Manager
+	Repo      repository.Repository
+	Risk      *risk.Manager
+	Campaigns *service.CampaignService
+	// Costs forecasts execution cost before a plan is approved.
+	Costs *service.CostForecaster
+	// Outbox records the side effects of plan creation and dismissals
+	// with them.
+	Outbox *service.OutboxDispatcher
 }
 
 func (h *V2OpportunityHandler) Register(r *gin.Engine) {
-	group := r.Group("/api/v2/opportunities")
-	group.GET("", h.listOpportunities)
+	group := r.Group("/api/v2/opportunities", tenantGuard("id", "opportunity not found", h.opportunityTenant))
+	group.GET("", validateQuery[listOpportunitiesQuery](), h.listOpportunities)
 	group.GET("/:id", h.getOpportunity)
+	group.GET("/:id/cost-forecast", validateQuery[costForecastQuery](), h.costForecast)
 	group.POST("/:id/dismiss", h.dismissOpportunity)
 	group.POST("/:id/execute", h.createExecutionPlan)
 }
 
+type listOpportunitiesQuery struct {
+	pageQuery
+	Status        *string          `form:"status"`
+	Strategy      *string          `form:"strategy"`
+	Category      *string          `form:"category"`
+	MinEdge       *decimal.Decimal `form:"min_edge"`
+	MinConfidence *float64         `form:"min_confidence" binding:"omitempty,min=0,max=1"`
+	SortBy        string           `form:"sort_by" default:"created_at" binding:"oneof=edge_usd edge_pct decayed_edge_pct confidence risk_score created_at updated_at"`
+	Order         string           `form:"order" default:"desc" binding:"oneof=asc desc"`
+}
+
+// costForecastQuery sizes a cost forecast; without size_usd the risk
+// manager's suggested size is used.
+type costForecastQuery struct {
+	SizeUSD *float64 `form:"size_usd" binding:"omitempty,gt=0"`
+}
+
 func (h *V2OpportunityHandler) listOpportunities(c *gin.Context) {
 	if h.Repo == nil {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	status := strings.TrimSpace(c.Query("status"))
-	strategy := strings.TrimSpace(c.Query("strategy"))
-	category := strings.TrimSpace(c.Query("category"))
-	minEdge := decimalQueryPtr(c, "min_edge")
+	q := queryOf[listOpportunitiesQuery](c)
+	minEdge := q.MinEdge
 	if minEdge != nil {
 		// Allow both "0.05" and "5" to mean 5%.
 		if minEdge.GreaterThan(decimal.NewFromInt(1)) {
@@ -45,75 +65,45 @@ func (h *V2OpportunityHandler) listOpportunities(c *gin.Context) {
 			minEdge = &v
 		}
 	}
-	minConfidenceRaw := strings.TrimSpace(c.Query("min_confidence"))
-	var minConfidence *float64
-	if minConfidenceRaw != "" {
-		if f, err := decimal.NewFromString(minConfidenceRaw); err == nil {
-			v, _ := f.Float64()
-			minConfidence = &v
-		}
-	}
-	sortBy := strings.TrimSpace(c.Query("sort_by"))
-	order := strings.TrimSpace(strings.ToLower(c.Query("order")))
-	limit := intQuery(c, "limit", 50)
-	offset := intQuery(c, "offset", 0)
-
-	var statusPtr *string
-	if status != "" {
-		statusPtr = &status
-	}
-	var strategyPtr *string
-	if strategy != "" {
-		strategyPtr = &strategy
-	}
-	var categoryPtr *string
-	if category != "" {
-		categoryPtr = &category
-	}
-
-	orderBy := parseOrder(sortBy, map[string]string{
-		"edge_usd":   "edge_usd",
-		"edge_pct":   "edge_pct",
-		"confidence": "confidence",
-		"risk_score": "risk_score",
-		"created_at": "created_at",
-		"updated_at": "updated_at",
-	})
-	if orderBy == "" {
-		orderBy = "created_at"
-	}
-	asc := false
-	if order == "asc" {
-		asc = true
-	}
 
 	items, err := h.Repo.ListOpportunities(c.Request.Context(), repository.ListOpportunitiesParams{
-		Limit:         limit,
-		Offset:        offset,
-		Status:        statusPtr,
-		StrategyName:  strategyPtr,
-		Category:      categoryPtr,
+		Limit:         q.Limit,
+		Offset:        q.Offset,
+		Status:        q.Status,
+		StrategyName:  q.Strategy,
+		Category:      q.Category,
 		MinEdgePct:    minEdge,
-		MinConfidence: minConfidence,
-		OrderBy:       orderBy,
-		Asc:           boolPtr(asc),
+		MinConfidence: q.MinConfidence,
+		Tenant:        tenantScope(c),
+		OrderBy:       q.SortBy,
+		Asc:           boolPtr(q.Order == "asc"),
 	})
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
 	total, err := h.Repo.CountOpportunities(c.Request.Context(), repository.ListOpportunitiesParams{
-		Status:        statusPtr,
-		StrategyName:  strategyPtr,
-		Category:      categoryPtr,
+		Status:        q.Status,
+		StrategyName:  q.Strategy,
+		Category:      q.Category,
 		MinEdgePct:    minEdge,
-		MinConfidence: minConfidence,
+		MinConfidence: q.MinConfidence,
+		Tenant:        tenantScope(c),
 	})
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	meta := paginationMeta(limit, offset, total)
+	meta := paginationMeta(q.Limit, q.Offset, total)
+	if opts := parseExpand(c); opts.any() {
+		views, err := expandOpportunities(c.Request.Context(), h.Repo, opts, items)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		Ok(c, views, meta)
+		return
+	}
 	Ok(c, items, meta)
 }
 
@@ -136,6 +126,15 @@ func (h *V2OpportunityHandler) getOpportunity(c *gin.Context) {
 		Error(c, http.StatusNotFound, "opportunity not found", nil)
 		return
 	}
+	if opts := parseExpand(c); opts.any() {
+		views, err := expandOpportunities(c.Request.Context(), h.Repo, opts, []models.Opportunity{*item})
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		Ok(c, views[0], nil)
+		return
+	}
 	Ok(c, item, nil)
 }
 
@@ -149,13 +148,18 @@ func (h *V2OpportunityHandler) dismissOpportunity(c *gin.Context) {
 		Error(c, http.StatusBadRequest, "invalid id", nil)
 		return
 	}
-	if err := h.Repo.UpdateOpportunityStatus(c.Request.Context(), id, "cancelled"); err != nil {
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.UpdateOpportunityStatusTx(c.Request.Context(), tx, id, "cancelled"); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_opportunity_dismissed", "info", map[string]any{
+			"opportunity_id": id,
+		}), nil
+	})
+	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	paas.LogBestEffort(c, "polymarket_opportunity_dismissed", "info", map[string]any{
-		"opportunity_id": id,
-	})
 	Ok(c, map[string]any{"id": id, "status": "cancelled"}, nil)
 }
 
@@ -178,105 +182,73 @@ func (h *V2OpportunityHandler) createExecutionPlan(c *gin.Context) {
 		Error(c, http.StatusNotFound, "opportunity not found", nil)
 		return
 	}
-	if strings.TrimSpace(opp.Status) != "" && opp.Status != "active" {
-		Error(c, http.StatusConflict, "opportunity not active", map[string]any{"status": opp.Status})
+	if !opportunityExecutable(c, *opp) {
 		return
 	}
-	stratName := ""
-	if opp.Strategy.Name != "" {
-		stratName = opp.Strategy.Name
-	}
-	if stratName == "" {
-		stratName = "unknown"
+	plan, warnings, err := service.CreateOpportunityPlan(c.Request.Context(), h.Repo, h.Risk, h.Campaigns, h.Costs, h.Outbox, *opp, nil)
+	if err != nil {
+		campaignError(c, err)
+		return
 	}
-
-	plannedSize := opp.MaxSize
-	maxLoss := plannedSize
-	var kellyFraction *float64
-	warnings := []string{}
-	if h.Risk != nil {
-		ps, ml, kf, ws := h.Risk.SuggestPlanSizing(c.Request.Context(), *opp, stratName)
-		plannedSize = ps
-		maxLoss = ml
-		kellyFraction = kf
-		warnings = append(warnings, ws...)
+	out := map[string]any{"plan": plan, "sizing_warnings": warnings}
+	if fc, _ := h.Repo.GetCostForecastByPlanID(c.Request.Context(), plan.ID); fc != nil {
+		out["cost_forecast"] = fc
 	}
+	Ok(c, out, nil)
+}
 
-	plan := &models.ExecutionPlan{
-		OpportunityID:   opp.ID,
-		Status:          "draft",
-		StrategyName:    stratName,
-		PlannedSizeUSD:  plannedSize,
-		MaxLossUSD:      maxLoss,
-		KellyFraction:   kellyFraction,
-		Params:          datatypes.JSON([]byte(`{"slippage_tolerance":0.02,"execution_order":"sequential","limit_vs_market":"limit","time_limit_seconds":300}`)),
-		PreflightResult: datatypes.JSON([]byte(`{}`)),
-		Legs:            addPlanLegSizing(opp.Legs, plannedSize),
-		CreatedAt:       time.Now().UTC(),
-		UpdatedAt:       time.Now().UTC(),
+// costForecast previews the all-in execution cost of executing the
+// opportunity: gross edge less slippage from the latest books, fees and the
+// adverse-selection buffer.
+func (h *V2OpportunityHandler) costForecast(c *gin.Context) {
+	if h.Repo == nil || h.Costs == nil {
+		Error(c, http.StatusInternalServerError, "cost forecaster unavailable", nil)
+		return
 	}
-	// If opportunity legs are missing, keep plan invalid but insertable.
-	if len(plan.Legs) == 0 {
-		legsJSON, _ := json.Marshal([]any{})
-		plan.Legs = datatypes.JSON(legsJSON)
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return
 	}
-
-	if err := h.Repo.InsertExecutionPlan(c.Request.Context(), plan); err != nil {
+	opp, err := h.Repo.GetOpportunityByID(c.Request.Context(), id)
+	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-
-	// Move opportunity into execution lifecycle once a plan exists.
-	_ = h.Repo.UpdateOpportunityStatus(c.Request.Context(), opp.ID, "executing")
-
-	// Seed a PnL record so analytics can show "planned" stats even before settlement.
-	_ = h.Repo.UpsertPnLRecord(c.Request.Context(), &models.PnLRecord{
-		PlanID:       plan.ID,
-		StrategyName: plan.StrategyName,
-		ExpectedEdge: opp.EdgePct,
-		Outcome:      "pending",
-		CreatedAt:    time.Now().UTC(),
-	})
-
-	paas.LogBestEffort(c, "polymarket_execution_plan_created", "info", map[string]any{
-		"opportunity_id":   opp.ID,
-		"plan_id":          plan.ID,
-		"strategy":         plan.StrategyName,
-		"planned_size_usd": plan.PlannedSizeUSD.String(),
-		"max_loss_usd":     plan.MaxLossUSD.String(),
-		"warnings":         warnings,
-	})
-
-	Ok(c, map[string]any{"plan": plan, "sizing_warnings": warnings}, nil)
-}
-
-func addPlanLegSizing(legsJSON []byte, plannedSizeUSD decimal.Decimal) datatypes.JSON {
-	if len(legsJSON) == 0 {
-		return datatypes.JSON(legsJSON)
-	}
-	var legs []map[string]any
-	if err := json.Unmarshal(legsJSON, &legs); err != nil {
-		return datatypes.JSON(legsJSON)
-	}
-	if len(legs) == 0 {
-		return datatypes.JSON(legsJSON)
+	if opp == nil {
+		Error(c, http.StatusNotFound, "opportunity not found", nil)
+		return
 	}
-	perLeg := plannedSizeUSD.Div(decimal.NewFromInt(int64(len(legs))))
-	perLegF, _ := perLeg.Float64()
-	for i := range legs {
-		// Add sizing hints for the UI and future execution engine.
-		if _, ok := legs[i]["size_usd"]; !ok {
-			legs[i]["size_usd"] = perLegF
-		}
-		if _, ok := legs[i]["priority"]; !ok {
-			legs[i]["priority"] = i + 1
+	size := opp.MaxSize
+	if q := queryOf[costForecastQuery](c); q.SizeUSD != nil {
+		size = decimal.NewFromFloat(*q.SizeUSD)
+	} else if h.Risk != nil {
+		name := opp.Strategy.Name
+		if name == "" {
+			name = "unknown"
 		}
+		size, _, _, _ = h.Risk.SuggestPlanSizing(c.Request.Context(), *opp, name)
 	}
-	raw, err := json.Marshal(legs)
+	fc, err := h.Costs.Forecast(c.Request.Context(), *opp, size)
 	if err != nil {
-		return datatypes.JSON(legsJSON)
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, fc, nil)
+}
+
+// opportunityExecutable answers 409 for opportunities a plan may not be
+// created from.
+func opportunityExecutable(c *gin.Context, opp models.Opportunity) bool {
+	if strings.TrimSpace(opp.Status) != "" && opp.Status != "active" {
+		Error(c, http.StatusConflict, "opportunity not active", map[string]any{"status": opp.Status})
+		return false
+	}
+	if opp.Shadow {
+		Error(c, http.StatusConflict, "shadow opportunity is not executable", map[string]any{"launch_stage": models.LaunchStageShadow})
+		return false
 	}
-	return datatypes.JSON(raw)
+	return true
 }
 
 func uint64QueryParam(c *gin.Context, key string) uint64 {
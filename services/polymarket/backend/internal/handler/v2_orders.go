@@ -1,12 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 
 	"polymarket/internal/repository"
+	"polymarket/internal/risk"
 	"polymarket/internal/service"
 )
 
@@ -16,44 +18,42 @@ type V2OrderHandler struct {
 }
 
 func (h *V2OrderHandler) Register(r *gin.Engine) {
-	o := r.Group("/api/v2/orders")
-	o.GET("", h.list)
+	o := r.Group("/api/v2/orders", tenantGuard("id", "order not found", h.orderTenant))
+	o.GET("", validateQuery[listOrdersQuery](), h.list)
 	o.GET("/:id", h.get)
+	o.PATCH("/:id", h.amend)
 	o.POST("/:id/cancel", h.cancel)
 
-	e := r.Group("/api/v2/executions")
+	e := r.Group("/api/v2/executions", tenantGuard("id", "execution plan not found", func(c *gin.Context) (string, bool, error) {
+		return planTenantByID(c, h.Repo, uint64QueryParam(c, "id"))
+	}))
 	e.POST("/:id/submit", h.submitPlan)
 }
 
+type listOrdersQuery struct {
+	pageQuery
+	Status    *string `form:"status"`
+	TokenID   *string `form:"token_id"`
+	LineageID *uint64 `form:"lineage_id" binding:"omitempty,min=1"`
+	PlanID    *uint64 `form:"plan_id" binding:"omitempty,min=1"`
+}
+
 func (h *V2OrderHandler) list(c *gin.Context) {
 	if h.Repo == nil {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	limit := intQuery(c, "limit", 50)
-	offset := intQuery(c, "offset", 0)
-	var status *string
-	if v := strings.TrimSpace(c.Query("status")); v != "" {
-		status = &v
-	}
-	var tokenID *string
-	if v := strings.TrimSpace(c.Query("token_id")); v != "" {
-		tokenID = &v
-	}
-	var planID *uint64
-	if v := strings.TrimSpace(c.Query("plan_id")); v != "" {
-		if id := parseUint64(v); id > 0 {
-			planID = &id
-		}
-	}
+	q := queryOf[listOrdersQuery](c)
 	params := repository.ListOrdersParams{
-		Limit:   limit,
-		Offset:  offset,
-		Status:  status,
-		PlanID:  planID,
-		TokenID: tokenID,
-		OrderBy: "created_at",
-		Asc:     boolPtr(false),
+		Limit:     q.Limit,
+		Offset:    q.Offset,
+		Tenant:    tenantScope(c),
+		Status:    q.Status,
+		PlanID:    q.PlanID,
+		TokenID:   q.TokenID,
+		LineageID: q.LineageID,
+		OrderBy:   "created_at",
+		Asc:       boolPtr(false),
 	}
 	items, err := h.Repo.ListOrders(c.Request.Context(), params)
 	if err != nil {
@@ -65,7 +65,7 @@ func (h *V2OrderHandler) list(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, items, paginationMeta(limit, offset, total))
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
 }
 
 func (h *V2OrderHandler) get(c *gin.Context) {
@@ -108,6 +108,68 @@ func (h *V2OrderHandler) cancel(c *gin.Context) {
 	Ok(c, item, nil)
 }
 
+type amendOrderRequest struct {
+	Price   *float64 `json:"price"`
+	SizeUSD *float64 `json:"size_usd"`
+}
+
+// amend modifies price and/or size of a working order. size_usd is the total
+// size of the logical order including what already filled.
+func (h *V2OrderHandler) amend(c *gin.Context) {
+	if h.Executor == nil {
+		Error(c, http.StatusServiceUnavailable, "executor unavailable", nil)
+		return
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	var req amendOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if req.Price == nil && req.SizeUSD == nil {
+		Error(c, http.StatusBadRequest, "price or size_usd is required", nil)
+		return
+	}
+	var params service.AmendOrderRequest
+	if req.Price != nil {
+		if *req.Price <= 0 || *req.Price >= 1 {
+			Error(c, http.StatusBadRequest, "price must be between 0 and 1", nil)
+			return
+		}
+		v := decimal.NewFromFloat(*req.Price)
+		params.Price = &v
+	}
+	if req.SizeUSD != nil {
+		if *req.SizeUSD <= 0 {
+			Error(c, http.StatusBadRequest, "size_usd must be positive", nil)
+			return
+		}
+		v := decimal.NewFromFloat(*req.SizeUSD)
+		params.SizeUSD = &v
+	}
+	out, err := h.Executor.AmendOrder(c.Request.Context(), id, params)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAmendInvalid):
+			Error(c, http.StatusBadRequest, err.Error(), nil)
+		case errors.Is(err, service.ErrOrderNotAmendable):
+			Error(c, http.StatusConflict, err.Error(), nil)
+		default:
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+		}
+		return
+	}
+	if out == nil {
+		Error(c, http.StatusNotFound, "order not found", nil)
+		return
+	}
+	Ok(c, out, nil)
+}
+
 func (h *V2OrderHandler) submitPlan(c *gin.Context) {
 	if h.Executor == nil {
 		Error(c, http.StatusServiceUnavailable, "executor unavailable", nil)
@@ -119,6 +181,11 @@ func (h *V2OrderHandler) submitPlan(c *gin.Context) {
 		return
 	}
 	out, err := h.Executor.SubmitPlan(c.Request.Context(), id)
+	var limitErr *risk.RateLimitError
+	if errors.As(err, &limitErr) {
+		Error(c, http.StatusTooManyRequests, err.Error(), nil)
+		return
+	}
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
@@ -129,19 +196,3 @@ func (h *V2OrderHandler) submitPlan(c *gin.Context) {
 	}
 	Ok(c, out, nil)
 }
-
-func parseUint64(v string) uint64 {
-	v = strings.TrimSpace(v)
-	if v == "" {
-		return 0
-	}
-	var out uint64
-	for i := 0; i < len(v); i++ {
-		ch := v[i]
-		if ch < '0' || ch > '9' {
-			return 0
-		}
-		out = out*10 + uint64(ch-'0')
-	}
-	return out
-}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2OutboxHandler shows the side effects waiting in the transactional
+// outbox and requeues dead ones. Messages span every desk, so both need an
+// unscoped token.
+type V2OutboxHandler struct {
+	Repo   repository.Repository
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2OutboxHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/outbox")
+	group.GET("", validateQuery[outboxQuery](), h.list)
+	group.POST("/:id/retry", h.retry)
+}
+
+type outboxQuery struct {
+	pageQuery
+	Status *string `form:"status" binding:"omitempty,oneof=pending delivered dead"`
+	Kind   *string `form:"kind" binding:"omitempty,oneof=paas_log notify webhook trade_webhook catalog_webhook"`
+}
+
+func (h *V2OutboxHandler) list(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "the outbox requires an unscoped token", nil)
+		return
+	}
+	q := queryOf[outboxQuery](c)
+	params := repository.ListOutboxMessagesParams{Limit: q.Limit, Offset: q.Offset, Status: q.Status, Kind: q.Kind}
+	items, err := h.Repo.ListOutboxMessages(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountOutboxMessages(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	byStatus, err := h.Repo.CountOutboxMessagesByStatus(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	meta := paginationMeta(q.Limit, q.Offset, total)
+	meta["by_status"] = byStatus
+	meta["enabled"] = h.Outbox.Enabled()
+	Ok(c, items, meta)
+}
+
+// retry requeues a dead message for immediate delivery.
+func (h *V2OutboxHandler) retry(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "the outbox requires an unscoped token", nil)
+		return
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	ok, err := h.Repo.RetryOutboxMessage(c.Request.Context(), id, time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if !ok {
+		Error(c, http.StatusConflict, "outbox message is not dead", map[string]any{"id": id})
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_outbox_retried", "info", map[string]any{"id": id})
+	Ok(c, map[string]any{"id": id, "status": models.OutboxStatusPending}, nil)
+}
+
+// outboxLog is the outbox form of paas.LogBestEffort. The message is always
+// recorded: the dispatcher delivers it with its own PaaS client, so it does
+// not depend on the request carrying one.
+func outboxLog(c *gin.Context, action, level string, details map[string]any) []models.OutboxMessage {
+	return []models.OutboxMessage{service.NewOutboxPaasLog(c.Request.Context(), action, level, details)}
+}
+
+// writeWithOutbox runs write in a transaction that also records the
+// messages it returns; see service.WriteWithOutbox.
+func writeWithOutbox(c *gin.Context, repo repository.Repository, outbox *service.OutboxDispatcher, write func(tx *gorm.DB) ([]models.OutboxMessage, error)) error {
+	return service.WriteWithOutbox(c.Request.Context(), repo, outbox, write)
+}
+
+// logWithOutbox records a PaaS log through the outbox for events whose
+// write the handler does not make itself, such as service calls and
+// in-memory changes.
+func logWithOutbox(c *gin.Context, outbox *service.OutboxDispatcher, action, level string, details map[string]any) {
+	outbox.Log(c.Request.Context(), action, level, details)
+}
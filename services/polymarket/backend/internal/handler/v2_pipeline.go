@@ -2,20 +2,65 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"polymarket/internal/client/polymarket/clob"
+	"polymarket/internal/governor"
+	"polymarket/internal/paas"
 	"polymarket/internal/repository"
+	"polymarket/internal/repository/bookcache"
+	"polymarket/internal/service"
 )
 
 type V2PipelineHandler struct {
 	Repo repository.Repository
+	Gaps *service.MarketDataGapService
+	Logs *paas.LogBuffer
+	// Stream, when set, reports CLOB websocket reconnect metrics.
+	Stream *service.CLOBStreamService
+	// Throttle, when set, reports per-market submission throttling.
+	Throttle *service.MarketThrottle
+	// Governor, when set, queues gap scans behind other heavy jobs.
+	Governor *governor.Governor
+	// Endpoints report latency and health of the CLOB REST and WS endpoints.
+	Endpoints []*clob.EndpointPool
+	// Outbox records the audit logs of stream pin changes.
+	Outbox *service.OutboxDispatcher
 }
 
 func (h *V2PipelineHandler) Register(r *gin.Engine) {
 	group := r.Group("/api/v2/pipeline")
 	group.GET("/health", h.health)
+	group.GET("/gaps", validateQuery[listGapsQuery](), h.listGaps)
+	group.POST("/gaps/scan", heavyJob(h.Governor, governor.ClassBackfill), h.scanGaps)
+	group.GET("/candles", validateQuery[candlesQuery](), h.candles)
+	group.GET("/stream/subscriptions", validateQuery[streamSubscriptionsQuery](), h.streamSubscriptions)
+	group.POST("/stream/pins", h.pinStreamTokens)
+	group.DELETE("/stream/pins/:source", validateQuery[unpinStreamQuery](), h.unpinStreamTokens)
+}
+
+type streamSubscriptionsQuery struct {
+	Source string `form:"source"`
+}
+
+type unpinStreamQuery struct {
+	TokenIDs []string `form:"token_ids"`
+}
+
+type listGapsQuery struct {
+	Limit   int        `form:"limit" default:"100" binding:"min=1,max=1000"`
+	Offset  int        `form:"offset" default:"0" binding:"min=0"`
+	TokenID *string    `form:"token_id"`
+	Status  *string    `form:"status"`
+	Since   *time.Time `form:"since"`
+}
+
+type candlesQuery struct {
+	timeRangeQuery
+	TokenID string `form:"token_id" binding:"required"`
 }
 
 func (h *V2PipelineHandler) health(c *gin.Context) {
@@ -54,7 +99,7 @@ func (h *V2PipelineHandler) health(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	out := gin.H{
 		"markets_total":          marketsTotal,
 		"markets_active":         marketsActive,
 		"orderbook_latest_count": obTotal,
@@ -64,5 +109,152 @@ func (h *V2PipelineHandler) health(c *gin.Context) {
 		"opportunities_active":   oppsActive,
 		"strategies_enabled":     strategiesEnabled,
 		"strategies_total":       len(strategies),
-	})
+	}
+	if cache, ok := h.Repo.(interface{ Stats() bookcache.Stats }); ok {
+		out["book_cache"] = cache.Stats()
+	}
+	if h.Logs != nil {
+		out["paas_logs"] = h.Logs.Stats()
+	}
+	if st, ok := h.Stream.Stats(); ok {
+		out["clob_stream"] = st
+	}
+	if h.Stream != nil && h.Stream.Subscriptions != nil {
+		out["clob_stream_subscriptions"] = h.Stream.Subscriptions.State()
+	}
+	if h.Throttle != nil {
+		out["market_throttle"] = h.Throttle.Stats()
+	}
+	if h.Governor != nil {
+		out["governor"] = h.Governor.Stats()
+	}
+	if len(h.Endpoints) > 0 {
+		pools := make([]clob.EndpointPoolStats, 0, len(h.Endpoints))
+		for _, p := range h.Endpoints {
+			pools = append(pools, p.Stats())
+		}
+		out["clob_endpoints"] = pools
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *V2PipelineHandler) listGaps(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[listGapsQuery](c)
+	params := repository.ListMarketDataGapsParams{Limit: q.Limit, Offset: q.Offset, TokenID: q.TokenID, Status: q.Status, Since: q.Since}
+	items, err := h.Repo.ListMarketDataGaps(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountMarketDataGaps(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
+// scanGaps runs one detection and backfill pass immediately.
+func (h *V2PipelineHandler) scanGaps(c *gin.Context) {
+	if h.Gaps == nil {
+		Error(c, http.StatusInternalServerError, "gap service unavailable", nil)
+		return
+	}
+	result, err := h.Gaps.RunOnce(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, result, nil)
+}
+
+// candles returns one-minute price candles for a token, default last 6h.
+func (h *V2PipelineHandler) candles(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[candlesQuery](c)
+	until := time.Now().UTC()
+	since := until.Add(-6 * time.Hour)
+	if q.Until != nil {
+		until = *q.Until
+	}
+	if q.Since != nil {
+		since = *q.Since
+	}
+	items, err := h.Repo.ListPriceCandles(c.Request.Context(), q.TokenID, since, until)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, map[string]any{"since": since, "until": until})
+}
+
+func (h *V2PipelineHandler) subscriptionsAvailable(c *gin.Context) bool {
+	if h.Stream == nil || h.Stream.Subscriptions == nil {
+		Error(c, http.StatusInternalServerError, "stream subscriptions unavailable", nil)
+		return false
+	}
+	return true
+}
+
+// streamSubscriptions lists the tokens the CLOB stream wants, with their
+// pinning sources and whether they are within the cap.
+func (h *V2PipelineHandler) streamSubscriptions(c *gin.Context) {
+	if !h.subscriptionsAvailable(c) {
+		return
+	}
+	q := queryOf[streamSubscriptionsQuery](c)
+	Ok(c, h.Stream.Subscriptions.List(q.Source), map[string]any{"state": h.Stream.Subscriptions.State()})
+}
+
+type pinStreamRequest struct {
+	// Source names the pin owner, e.g. "strategy:arb_sum" or "watchlist:btc".
+	Source   string   `json:"source"`
+	TokenIDs []string `json:"token_ids"`
+}
+
+// pinStreamTokens subscribes the tokens immediately; pins outlive refreshes
+// of the top-market snapshot.
+func (h *V2PipelineHandler) pinStreamTokens(c *gin.Context) {
+	if !h.subscriptionsAvailable(c) {
+		return
+	}
+	var req pinStreamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	source := strings.TrimSpace(req.Source)
+	if source == "" || len(req.TokenIDs) == 0 {
+		Error(c, http.StatusBadRequest, "source and token_ids required", nil)
+		return
+	}
+	if source == service.StreamSourcePositions {
+		Error(c, http.StatusBadRequest, "positions pins follow open positions", nil)
+		return
+	}
+	n := h.Stream.Pin(source, req.TokenIDs)
+	logWithOutbox(c, h.Outbox, "polymarket_stream_pinned", "info", map[string]any{"source": source, "tokens": n})
+	Ok(c, map[string]any{"source": source, "pinned": n}, map[string]any{"state": h.Stream.Subscriptions.State()})
+}
+
+// unpinStreamTokens drops the source's pins on token_ids, or all its pins.
+func (h *V2PipelineHandler) unpinStreamTokens(c *gin.Context) {
+	if !h.subscriptionsAvailable(c) {
+		return
+	}
+	source := strings.TrimSpace(c.Param("source"))
+	if source == service.StreamSourcePositions {
+		Error(c, http.StatusBadRequest, "positions pins follow open positions", nil)
+		return
+	}
+	n := h.Stream.Unpin(source, queryOf[unpinStreamQuery](c).TokenIDs)
+	logWithOutbox(c, h.Outbox, "polymarket_stream_unpinned", "info", map[string]any{"source": source, "tokens": n})
+	Ok(c, map[string]any{"source": source, "unpinned": n}, map[string]any{"state": h.Stream.Subscriptions.State()})
 }
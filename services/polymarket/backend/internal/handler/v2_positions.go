@@ -3,25 +3,65 @@ package handler
 import (
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"polymarket/internal/governor"
+	"polymarket/internal/models"
 	"polymarket/internal/repository"
+	"polymarket/internal/service"
 )
 
 type V2PositionHandler struct {
 	Repo repository.Repository
+	Sync *service.PositionSyncService
+	// Import, when set, enables importing externally held positions.
+	Import *service.ExternalPositionService
+	// Governor, when set, queues rebuilds and imports behind other heavy jobs.
+	Governor *governor.Governor
+	// Outbox records the audit logs of imports.
+	Outbox *service.OutboxDispatcher
 }
 
 func (h *V2PositionHandler) Register(r *gin.Engine) {
-	p := r.Group("/api/v2/positions")
-	p.GET("", h.list)
+	p := r.Group("/api/v2/positions", tenantGuard("id", "position not found", h.positionTenant))
+	p.GET("", validateQuery[listPositionsQuery](), h.list)
 	p.GET("/summary", h.summary)
+	p.POST("/rebuild", heavyJob(h.Governor, governor.ClassRebuild), validateQuery[rebuildPositionsQuery](), h.rebuild)
+	p.POST("/import", heavyJob(h.Governor, governor.ClassAdmin), h.importExternal)
+	p.GET("/import/status", h.importStatus)
 	p.GET("/:id", h.get)
 
 	portfolio := r.Group("/api/v2/portfolio")
-	portfolio.GET("/history", h.history)
+	portfolio.GET("/history", validateQuery[portfolioHistoryQuery](), h.history)
+	portfolio.GET("/anomalies", validateQuery[portfolioAnomaliesQuery](), h.anomalies)
+}
+
+type listPositionsQuery struct {
+	pageQuery
+	OrderBy      string  `form:"order_by" default:"opened_at" binding:"oneof=unrealized_pnl cost_basis opened_at created_at"`
+	Order        string  `form:"order" default:"desc" binding:"oneof=asc desc"`
+	Status       *string `form:"status" binding:"omitempty,oneof=open closed"`
+	StrategyName *string `form:"strategy_name"`
+	MarketID     *string `form:"market_id"`
+	Source       *string `form:"source" binding:"omitempty,oneof=system external"`
+}
+
+type rebuildPositionsQuery struct {
+	TokenID string `form:"token_id"`
+	Apply   bool   `form:"apply"`
+}
+
+type portfolioHistoryQuery struct {
+	timeRangeQuery
+	Limit  int `form:"limit" default:"168" binding:"min=1,max=10000"`
+	Offset int `form:"offset" default:"0" binding:"min=0"`
+}
+
+type portfolioAnomaliesQuery struct {
+	pageQuery
+	timeRangeQuery
+	Kind *string `form:"kind" binding:"omitempty,oneof=cost_basis_jump pnl_swing position_count"`
 }
 
 func (h *V2PositionHandler) list(c *gin.Context) {
@@ -29,44 +69,17 @@ func (h *V2PositionHandler) list(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	limit := intQuery(c, "limit", 50)
-	offset := intQuery(c, "offset", 0)
-	orderBy := parseOrder(strings.TrimSpace(c.Query("order_by")), map[string]string{
-		"unrealized_pnl": "unrealized_pnl",
-		"cost_basis":     "cost_basis",
-		"opened_at":      "opened_at",
-		"created_at":     "created_at",
-	})
-	if orderBy == "" {
-		orderBy = "opened_at"
-	}
-	order := strings.ToLower(strings.TrimSpace(c.Query("order")))
-	asc := false
-	if order == "asc" {
-		asc = true
-	}
-
-	var status *string
-	if v := strings.TrimSpace(c.Query("status")); v != "" {
-		status = &v
-	}
-	var strategyName *string
-	if v := strings.TrimSpace(c.Query("strategy_name")); v != "" {
-		strategyName = &v
-	}
-	var marketID *string
-	if v := strings.TrimSpace(c.Query("market_id")); v != "" {
-		marketID = &v
-	}
-
+	q := queryOf[listPositionsQuery](c)
 	params := repository.ListPositionsParams{
-		Limit:        limit,
-		Offset:       offset,
-		Status:       status,
-		StrategyName: strategyName,
-		MarketID:     marketID,
-		OrderBy:      orderBy,
-		Asc:          boolPtr(asc),
+		Limit:        q.Limit,
+		Offset:       q.Offset,
+		Status:       q.Status,
+		StrategyName: q.StrategyName,
+		MarketID:     q.MarketID,
+		Tenant:       tenantScope(c),
+		Source:       q.Source,
+		OrderBy:      q.OrderBy,
+		Asc:          boolPtr(q.Order == "asc"),
 	}
 	items, err := h.Repo.ListPositions(c.Request.Context(), params)
 	if err != nil {
@@ -78,7 +91,16 @@ func (h *V2PositionHandler) list(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, items, paginationMeta(limit, offset, total))
+	if opts := parseExpand(c); opts.any() {
+		views, err := expandPositions(c.Request.Context(), h.Repo, opts, items)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		Ok(c, views, paginationMeta(q.Limit, q.Offset, total))
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
 }
 
 func (h *V2PositionHandler) get(c *gin.Context) {
@@ -100,6 +122,15 @@ func (h *V2PositionHandler) get(c *gin.Context) {
 		Error(c, http.StatusNotFound, "position not found", nil)
 		return
 	}
+	if opts := parseExpand(c); opts.any() {
+		views, err := expandPositions(c.Request.Context(), h.Repo, opts, []models.Position{*item})
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		Ok(c, views[0], nil)
+		return
+	}
 	Ok(c, item, nil)
 }
 
@@ -116,36 +147,126 @@ func (h *V2PositionHandler) summary(c *gin.Context) {
 	Ok(c, out, nil)
 }
 
-func (h *V2PositionHandler) history(c *gin.Context) {
-	if h.Repo == nil {
-		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+// rebuild replays fills into positions. It is a dry run unless apply is true.
+func (h *V2PositionHandler) rebuild(c *gin.Context) {
+	if h.Sync == nil {
+		Error(c, http.StatusInternalServerError, "position sync unavailable", nil)
 		return
 	}
-	limit := intQuery(c, "limit", 168)
-	offset := intQuery(c, "offset", 0)
-	var since *time.Time
-	if raw := strings.TrimSpace(c.Query("since")); raw != "" {
-		if ts, err := time.Parse(time.RFC3339, raw); err == nil {
-			t := ts.UTC()
-			since = &t
-		}
+	var req struct {
+		TokenID string `json:"token_id"`
+		Apply   bool   `json:"apply"`
 	}
-	var until *time.Time
-	if raw := strings.TrimSpace(c.Query("until")); raw != "" {
-		if ts, err := time.Parse(time.RFC3339, raw); err == nil {
-			t := ts.UTC()
-			until = &t
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			Error(c, http.StatusBadRequest, "invalid request", nil)
+			return
 		}
 	}
+	q := queryOf[rebuildPositionsQuery](c)
+	if q.TokenID != "" {
+		req.TokenID = q.TokenID
+	}
+	if q.Apply {
+		req.Apply = true
+	}
+	out, err := h.Sync.RebuildFromFills(c.Request.Context(), req.TokenID, req.Apply)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, out, nil)
+}
+
+// importExternal imports the holdings of a wallet as external positions. The
+// holdings are fetched through the configured adapter unless the request
+// carries them in positions.
+func (h *V2PositionHandler) importExternal(c *gin.Context) {
+	if h.Import == nil {
+		Error(c, http.StatusInternalServerError, "position import unavailable", nil)
+		return
+	}
+	var req struct {
+		Wallet    string                     `json:"wallet"`
+		Positions []service.ExternalPosition `json:"positions"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid request", nil)
+		return
+	}
+	if strings.TrimSpace(req.Wallet) == "" {
+		Error(c, http.StatusBadRequest, "wallet is required", nil)
+		return
+	}
+	var adapter service.PositionImportAdapter
+	if req.Positions != nil {
+		adapter = service.StaticPositionAdapter(req.Positions)
+	}
+	report, err := h.Import.Import(c.Request.Context(), req.Wallet, adapter)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_positions_import", "info", map[string]any{
+		"wallet":  report.Wallet,
+		"adapter": report.Adapter,
+		"created": report.Created,
+		"updated": report.Updated,
+		"closed":  report.Closed,
+		"skipped": len(report.SkippedTokens),
+	})
+	Ok(c, report, nil)
+}
+
+func (h *V2PositionHandler) importStatus(c *gin.Context) {
+	if h.Import == nil {
+		Error(c, http.StatusInternalServerError, "position import unavailable", nil)
+		return
+	}
+	Ok(c, h.Import.Status(), nil)
+}
+
+func (h *V2PositionHandler) history(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[portfolioHistoryQuery](c)
 	items, err := h.Repo.ListPortfolioSnapshots(c.Request.Context(), repository.ListPortfolioSnapshotsParams{
-		Limit:  limit,
-		Offset: offset,
-		Since:  since,
-		Until:  until,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+		Since:  q.Since,
+		Until:  q.Until,
 	})
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, items, paginationMeta(limit, offset, int64(len(items))))
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, int64(len(items))))
+}
+
+func (h *V2PositionHandler) anomalies(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[portfolioAnomaliesQuery](c)
+	params := repository.ListPortfolioAnomaliesParams{
+		Limit:  q.Limit,
+		Offset: q.Offset,
+		Kind:   q.Kind,
+		Since:  q.Since,
+		Until:  q.Until,
+	}
+	items, err := h.Repo.ListPortfolioAnomalies(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountPortfolioAnomalies(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
 }
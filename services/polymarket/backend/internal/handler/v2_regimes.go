@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/regime"
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2RegimeHandler exposes the current market category regimes, their
+// history and the risk adjustments configured per regime.
+type V2RegimeHandler struct {
+	Repo     repository.Repository
+	Detector *regime.Detector
+	// Outbox records the audit logs of detection runs.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2RegimeHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/regimes")
+	group.GET("", h.current)
+	group.GET("/history", validateQuery[regimeHistoryQuery](), h.history)
+	group.POST("/run", h.run)
+}
+
+type regimeHistoryQuery struct {
+	pageQuery
+	timeRangeQuery
+	Category *string `form:"category"`
+	Regime   *string `form:"regime" binding:"omitempty,oneof=trending choppy news_driven unknown"`
+}
+
+func (h *V2RegimeHandler) current(c *gin.Context) {
+	if h.Detector == nil {
+		Error(c, http.StatusInternalServerError, "regime detector unavailable", nil)
+		return
+	}
+	current, err := h.Detector.Current(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	items := make([]models.MarketRegime, 0, len(current))
+	for _, category := range h.Detector.Categories() {
+		if item, ok := current[category]; ok {
+			items = append(items, item)
+		}
+	}
+	cfg := h.Detector.Config
+	strategies := map[string]any{}
+	for name, byRegime := range cfg.Strategies {
+		strategies[name] = regimeAdjustmentsView(byRegime)
+	}
+	Ok(c, items, map[string]any{
+		"enabled":     cfg.Enabled,
+		"categories":  h.Detector.Categories(),
+		"lookback":    cfg.Lookback.String(),
+		"adjustments": regimeAdjustmentsView(cfg.Adjustments),
+		"strategies":  strategies,
+	})
+}
+
+func (h *V2RegimeHandler) history(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[regimeHistoryQuery](c)
+	params := repository.ListMarketRegimesParams{
+		Limit:    q.Limit,
+		Offset:   q.Offset,
+		Category: q.Category,
+		Regime:   q.Regime,
+		Since:    q.Since,
+		Until:    q.Until,
+	}
+	items, err := h.Repo.ListMarketRegimes(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountMarketRegimes(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
+// run classifies every category now instead of waiting for the next
+// scheduled run.
+func (h *V2RegimeHandler) run(c *gin.Context) {
+	if h.Detector == nil {
+		Error(c, http.StatusInternalServerError, "regime detector unavailable", nil)
+		return
+	}
+	if !h.Detector.Config.Enabled {
+		Error(c, http.StatusConflict, "regime detection disabled", nil)
+		return
+	}
+	items, err := h.Detector.Run(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	regimes := map[string]string{}
+	for _, item := range items {
+		regimes[item.Category] = item.Regime
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_regimes_run", "info", map[string]any{"regimes": regimes})
+	Ok(c, items, nil)
+}
+
+func regimeAdjustmentsView(items map[string]config.RegimeAdjustment) map[string]any {
+	out := make(map[string]any, len(items))
+	for name, adj := range items {
+		out[name] = gin.H{
+			"block":           adj.Block,
+			"min_edge_pct":    adj.MinEdgePct,
+			"size_multiplier": adj.SizeMultiplier,
+		}
+	}
+	return out
+}
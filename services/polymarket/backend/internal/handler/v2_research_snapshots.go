@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2ResearchSnapshotHandler stores and serves immutable research snapshots.
+// There is no update or delete: a snapshot ID always names the same data.
+type V2ResearchSnapshotHandler struct {
+	Repo      repository.Repository
+	Snapshots *service.ResearchSnapshotService
+	// Outbox records the audit logs of new snapshots.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2ResearchSnapshotHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/research/snapshots")
+	group.GET("", validateQuery[listResearchSnapshotsQuery](), h.list)
+	group.POST("", h.create)
+	group.GET("/:id", h.get)
+}
+
+type listResearchSnapshotsQuery struct {
+	pageQuery
+	Name *string `form:"name"`
+}
+
+type researchSnapshotRequest struct {
+	Name string `json:"name"`
+	service.SnapshotQuery
+}
+
+func (h *V2ResearchSnapshotHandler) list(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[listResearchSnapshotsQuery](c)
+	params := repository.ListResearchSnapshotsParams{Limit: q.Limit, Offset: q.Offset, Tenant: tenantScope(c), Name: q.Name}
+	items, err := h.Repo.ListResearchSnapshots(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountResearchSnapshots(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
+func (h *V2ResearchSnapshotHandler) create(c *gin.Context) {
+	if h.Repo == nil || h.Snapshots == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	var req researchSnapshotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		Error(c, http.StatusBadRequest, "name required", nil)
+		return
+	}
+	if len(req.MarketIDs) == 0 && strings.TrimSpace(req.EventID) == "" && strings.TrimSpace(req.Label) == "" {
+		Error(c, http.StatusBadRequest, "market_ids, event_id or label required", nil)
+		return
+	}
+	item, err := h.Snapshots.Create(c.Request.Context(), req.Name, paas.TenantOrDefault(c.Request.Context()), req.SnapshotQuery, time.Now().UTC())
+	if errors.Is(err, service.ErrEmptySnapshot) {
+		Error(c, http.StatusUnprocessableEntity, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_research_snapshot_created", "info", map[string]any{
+		"snapshot_id":  item.ID,
+		"name":         item.Name,
+		"market_count": item.MarketCount,
+		"checksum":     item.Checksum,
+	})
+	Ok(c, item, nil)
+}
+
+func (h *V2ResearchSnapshotHandler) get(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	item, err := h.Repo.GetResearchSnapshotByID(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if item == nil || !tenantVisible(c, item.Tenant) {
+		Error(c, http.StatusNotFound, "snapshot not found", nil)
+		return
+	}
+	Ok(c, item, nil)
+}
+
+// checkSnapshotRefs reports whether every id names a snapshot the caller
+// may see.
+func checkSnapshotRefs(c *gin.Context, repo repository.Repository, ids []uint64) (bool, error) {
+	unique := map[uint64]bool{}
+	for _, id := range ids {
+		if id == 0 {
+			return false, nil
+		}
+		unique[id] = true
+	}
+	if len(unique) == 0 {
+		return true, nil
+	}
+	list := make([]uint64, 0, len(unique))
+	for id := range unique {
+		list = append(list, id)
+	}
+	n, err := repo.CountResearchSnapshotsByIDs(c.Request.Context(), list, tenantScope(c))
+	if err != nil {
+		return false, err
+	}
+	return n == int64(len(list)), nil
+}
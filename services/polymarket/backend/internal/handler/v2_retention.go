@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/governor"
+	"polymarket/internal/service"
+)
+
+// V2RetentionHandler manages the per-table retention policies and runs the
+// purge on demand. Policies are shared by all desks, so changing them or
+// deleting rows needs an unscoped token.
+type V2RetentionHandler struct {
+	Retention *service.RetentionService
+	Settings  *service.SystemSettingsService
+	// Governor, when set, queues purges behind other heavy jobs.
+	Governor *governor.Governor
+	// Outbox records the audit logs of policy changes.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2RetentionHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/retention")
+	group.GET("", h.get)
+	group.PUT("/policies/:table", h.putPolicy)
+	group.POST("/purge", heavyJob(h.Governor, governor.ClassAdmin), validateQuery[retentionPurgeQuery](), h.purge)
+}
+
+type retentionPurgeQuery struct {
+	// DryRun defaults to true; pass dry_run=false to delete.
+	DryRun *bool  `form:"dry_run"`
+	Tables string `form:"tables"`
+}
+
+// Omitted fields keep their value. Window "0" keeps rows forever; a
+// negative run_hour falls back to retention.run_hour.
+type putRetentionPolicyRequest struct {
+	Window  *string `json:"window"`
+	Enabled *bool   `json:"enabled"`
+	RunHour *int    `json:"run_hour"`
+}
+
+func (h *V2RetentionHandler) get(c *gin.Context) {
+	if h.Retention == nil {
+		Error(c, http.StatusInternalServerError, "retention unavailable", nil)
+		return
+	}
+	policies, err := h.Retention.Policies(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, gin.H{
+		"enabled":  h.Settings.IsEnabled(c.Request.Context(), service.FeatureRetention, false),
+		"policies": policies,
+		"last_run": h.Retention.LastRun(),
+	}, nil)
+}
+
+func (h *V2RetentionHandler) putPolicy(c *gin.Context) {
+	if h.Retention == nil {
+		Error(c, http.StatusInternalServerError, "retention unavailable", nil)
+		return
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "retention policies require an unscoped token", nil)
+		return
+	}
+	var req putRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	table := strings.TrimSpace(c.Param("table"))
+	before, err := h.Retention.Policy(c.Request.Context(), table)
+	if errors.Is(err, service.ErrUnknownRetentionTable) {
+		Error(c, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	next := before
+	if req.Window != nil {
+		next.Window = *req.Window
+	}
+	if req.Enabled != nil {
+		next.Enabled = *req.Enabled
+	}
+	if req.RunHour != nil {
+		next.RunHour = req.RunHour
+		if *req.RunHour < 0 {
+			next.RunHour = nil
+		}
+	}
+	saved, err := h.Retention.SavePolicy(c.Request.Context(), next)
+	if err != nil {
+		Error(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_retention_policy_updated", "info", map[string]any{
+		"table":           saved.Table,
+		"window":          saved.Window,
+		"enabled":         saved.Enabled,
+		"previous_window": before.Window,
+	})
+	Ok(c, saved, nil)
+}
+
+func (h *V2RetentionHandler) purge(c *gin.Context) {
+	if h.Retention == nil {
+		Error(c, http.StatusInternalServerError, "retention unavailable", nil)
+		return
+	}
+	q := queryOf[retentionPurgeQuery](c)
+	dryRun := q.DryRun == nil || *q.DryRun
+	if !dryRun && tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "purging requires an unscoped token", nil)
+		return
+	}
+	var tables []string
+	for _, t := range strings.Split(q.Tables, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tables = append(tables, t)
+		}
+	}
+	run, err := h.Retention.Purge(c.Request.Context(), tables, dryRun, "manual", time.Now().UTC())
+	if errors.Is(err, service.ErrUnknownRetentionTable) {
+		Error(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, run, nil)
+}
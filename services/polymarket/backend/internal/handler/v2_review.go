@@ -17,36 +17,38 @@ type V2ReviewHandler struct {
 
 func (h *V2ReviewHandler) Register(r *gin.Engine) {
 	g := r.Group("/api/v2/review")
-	g.GET("", h.list)
-	g.GET("/missed", h.missed)
+	g.GET("", validateQuery[listReviewQuery](), h.list)
+	g.GET("/missed", validateQuery[missedReviewQuery](), h.missed)
 	g.GET("/regret-index", h.regretIndex)
 	g.GET("/label-performance", h.labelPerformance)
 	g.PUT("/:id/notes", h.putNotes)
 }
 
+type listReviewQuery struct {
+	pageQuery
+	timeRangeQuery
+	OurAction    *string `form:"our_action"`
+	StrategyName *string `form:"strategy_name"`
+}
+
+type missedReviewQuery struct {
+	Limit  int `form:"limit" default:"100" binding:"min=1,max=1000"`
+	Offset int `form:"offset" default:"0" binding:"min=0"`
+}
+
 func (h *V2ReviewHandler) list(c *gin.Context) {
 	if h.Repo == nil {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	limit := intQuery(c, "limit", 50)
-	offset := intQuery(c, "offset", 0)
-	since, until := timeRangeFromQuery(c)
-	var ourAction *string
-	if v := strings.TrimSpace(c.Query("our_action")); v != "" {
-		ourAction = &v
-	}
-	var strategyName *string
-	if v := strings.TrimSpace(c.Query("strategy_name")); v != "" {
-		strategyName = &v
-	}
+	q := queryOf[listReviewQuery](c)
 	params := repository.ListMarketReviewParams{
-		Limit:        limit,
-		Offset:       offset,
-		OurAction:    ourAction,
-		StrategyName: strategyName,
-		Since:        since,
-		Until:        until,
+		Limit:        q.Limit,
+		Offset:       q.Offset,
+		OurAction:    q.OurAction,
+		StrategyName: q.StrategyName,
+		Since:        q.Since,
+		Until:        q.Until,
 		OrderBy:      "hypothetical_pnl",
 		Asc:          boolPtr(false),
 	}
@@ -60,7 +62,7 @@ func (h *V2ReviewHandler) list(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, items, paginationMeta(limit, offset, total))
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
 }
 
 func (h *V2ReviewHandler) missed(c *gin.Context) {
@@ -68,10 +70,11 @@ func (h *V2ReviewHandler) missed(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
+	q := queryOf[missedReviewQuery](c)
 	min := decimal.Zero
 	items, err := h.Repo.ListMarketReviews(c.Request.Context(), repository.ListMarketReviewParams{
-		Limit:   intQuery(c, "limit", 100),
-		Offset:  intQuery(c, "offset", 0),
+		Limit:   q.Limit,
+		Offset:  q.Offset,
 		MinPnL:  &min,
 		OrderBy: "hypothetical_pnl",
 		Asc:     boolPtr(false),
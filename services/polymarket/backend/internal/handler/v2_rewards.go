@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2RewardHandler reports liquidity reward quoting per market and epoch as
+// sampled by service.RewardTracker, and records the payouts actually
+// received so estimates can be checked against them.
+type V2RewardHandler struct {
+	Repo repository.Repository
+	// Outbox records the audit logs of reward writes with them.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2RewardHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/rewards")
+	group.GET("/report", validateQuery[rewardReportQuery](), h.report)
+	group.PUT("/received", h.received)
+}
+
+type rewardReportQuery struct {
+	Limit  int `form:"limit" default:"100" binding:"min=1,max=500"`
+	Offset int `form:"offset" default:"0" binding:"min=0"`
+	// Since and Until are inclusive epochs (UTC dates).
+	Since    *string `form:"since" binding:"omitempty,datetime=2006-01-02"`
+	Until    *string `form:"until" binding:"omitempty,datetime=2006-01-02"`
+	MarketID *string `form:"market_id"`
+}
+
+type rewardReceivedRequest struct {
+	Epoch     string   `json:"epoch"`
+	MarketID  string   `json:"market_id"`
+	AmountUSD *float64 `json:"amount_usd"`
+}
+
+// report lists market epochs newest first. Meta carries the estimated and
+// received totals of the listed rows; the difference only counts rows with
+// a recorded payout.
+func (h *V2RewardHandler) report(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[rewardReportQuery](c)
+	params := repository.ListRewardEpochsParams{Limit: q.Limit, Offset: q.Offset, MarketID: q.MarketID, Since: q.Since, Until: q.Until}
+	items, err := h.Repo.ListRewardEpochs(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountRewardEpochs(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	out := make([]service.RewardReportRow, 0, len(items))
+	estimated, received, diff := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, item := range items {
+		out = append(out, service.NewRewardReportRow(item))
+		estimated = estimated.Add(item.EstimatedUSD)
+		if item.ReceivedUSD != nil {
+			received = received.Add(*item.ReceivedUSD)
+			diff = diff.Add(item.ReceivedUSD.Sub(item.EstimatedUSD))
+		}
+	}
+	meta := paginationMeta(q.Limit, q.Offset, total)
+	meta["estimated_usd"] = estimated.StringFixed(2)
+	meta["received_usd"] = received.StringFixed(2)
+	meta["diff_usd"] = diff.StringFixed(2)
+	Ok(c, out, meta)
+}
+
+// received records the payout of one market epoch, creating the row when
+// the tracker never sampled it.
+func (h *V2RewardHandler) received(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	var req rewardReceivedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	epoch := strings.TrimSpace(req.Epoch)
+	marketID := strings.TrimSpace(req.MarketID)
+	if _, err := time.Parse("2006-01-02", epoch); err != nil {
+		Error(c, http.StatusBadRequest, "epoch must be YYYY-MM-DD", nil)
+		return
+	}
+	if marketID == "" {
+		Error(c, http.StatusBadRequest, "market_id required", nil)
+		return
+	}
+	if req.AmountUSD == nil || *req.AmountUSD < 0 {
+		Error(c, http.StatusBadRequest, "amount_usd must be >= 0", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	item, err := h.Repo.GetRewardEpoch(ctx, epoch, marketID)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if item == nil {
+		item = &models.RewardEpoch{Epoch: epoch, MarketID: marketID}
+	}
+	amount := decimal.NewFromFloat(*req.AmountUSD)
+	now := time.Now().UTC()
+	item.ReceivedUSD = &amount
+	item.ReceivedAt = &now
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).SaveRewardEpoch(ctx, item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_reward_received", "info", map[string]any{
+			"epoch":         epoch,
+			"market_id":     marketID,
+			"received_usd":  amount.String(),
+			"estimated_usd": item.EstimatedUSD.String(),
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, service.NewRewardReportRow(*item), nil)
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/repository"
+	"polymarket/internal/risk"
+	"polymarket/internal/service"
+)
+
+type V2RiskHandler struct {
+	Repo        repository.Repository
+	Risk        *risk.Manager
+	CryptoDelta *service.CryptoDeltaService
+}
+
+func (h *V2RiskHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/risk")
+	group.GET("/var", validateQuery[varQuery](), h.valueAtRisk)
+	group.GET("/limits", h.limits)
+	group.GET("/exposure-forecast", h.exposureForecast)
+	group.GET("/crypto-delta", validateQuery[cryptoDeltaQuery](), h.cryptoDelta)
+	group.GET("/decisions", validateQuery[riskDecisionsQuery](), h.decisions)
+}
+
+type riskDecisionsQuery struct {
+	pageQuery
+	timeRangeQuery
+	OpportunityID *uint64 `form:"opportunity_id" binding:"omitempty,min=1"`
+	StrategyName  *string `form:"strategy_name"`
+	Verdict       *string `form:"verdict" binding:"omitempty,oneof=pass reject"`
+	RejectedBy    *string `form:"rejected_by"`
+}
+
+// decisions lists recorded risk filter decisions, newest first. Risk runs
+// before an opportunity is stored, so opportunity_id matches the decisions on
+// the opportunity's strategy and event or market, which includes rejections
+// of re-emits that never replaced it.
+func (h *V2RiskHandler) decisions(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[riskDecisionsQuery](c)
+	ctx := c.Request.Context()
+	params := repository.ListRiskDecisionsParams{
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+		Tenant:     tenantScope(c),
+		Verdict:    q.Verdict,
+		RejectedBy: q.RejectedBy,
+		Since:      q.Since,
+		Until:      q.Until,
+	}
+	meta := map[string]any{}
+	if q.OpportunityID != nil {
+		params.OpportunityID = q.OpportunityID
+		opp, err := h.Repo.GetOpportunityByID(ctx, *q.OpportunityID)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		if opp != nil && !tenantVisible(c, opp.Tenant) {
+			Error(c, http.StatusNotFound, "opportunity not found", nil)
+			return
+		}
+		if opp != nil {
+			key := risk.OpportunityKey(*opp)
+			params.OpportunityKeys = []string{key}
+			meta["opportunity_key"] = key
+		}
+	}
+	if q.StrategyName != nil && strings.TrimSpace(*q.StrategyName) != "" {
+		strat, err := h.Repo.GetStrategyByName(ctx, strings.TrimSpace(*q.StrategyName))
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		if strat == nil {
+			Error(c, http.StatusNotFound, "strategy not found", nil)
+			return
+		}
+		params.StrategyID = &strat.ID
+	}
+	items, err := h.Repo.ListRiskDecisions(ctx, params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountRiskDecisions(ctx, params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	for k, v := range paginationMeta(q.Limit, q.Offset, total) {
+		meta[k] = v
+	}
+	if h.Risk != nil {
+		audit := h.Risk.Config.DecisionAudit
+		meta["audit"] = gin.H{
+			"enabled":            audit.Enabled,
+			"reject_sample_rate": audit.RejectSampleRate,
+			"pass_sample_rate":   audit.PassSampleRate,
+		}
+	}
+	Ok(c, items, meta)
+}
+
+type cryptoDeltaQuery struct {
+	Hedge bool `form:"hedge"`
+}
+
+// cryptoDelta reports net spot delta per asset from crypto threshold
+// positions and, with hedge=true, the configured venue's hedge sizes.
+// Scoped requests see their desk.
+func (h *V2RiskHandler) cryptoDelta(c *gin.Context) {
+	if h.CryptoDelta == nil {
+		Error(c, http.StatusInternalServerError, "crypto delta unavailable", nil)
+		return
+	}
+	q := queryOf[cryptoDeltaQuery](c)
+	tenant := ""
+	if scope := tenantScope(c); scope != nil {
+		tenant = *scope
+	}
+	rep, err := h.CryptoDelta.Compute(c.Request.Context(), tenant, q.Hedge)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, rep, map[string]any{"hedge_enabled": h.CryptoDelta.Config.Hedge.Enabled})
+}
+
+// exposureForecast reports plan exposure plus active opportunities weighted
+// by their strategy's execution probability. Scoped requests see their desk.
+func (h *V2RiskHandler) exposureForecast(c *gin.Context) {
+	if h.Risk == nil {
+		Error(c, http.StatusInternalServerError, "risk unavailable", nil)
+		return
+	}
+	mgr := h.Risk
+	if scope := tenantScope(c); scope != nil {
+		mgr = mgr.ForTenant(*scope)
+	}
+	Ok(c, mgr.Forecast(c.Request.Context()), nil)
+}
+
+// limits reports the global rate guards with their remaining budget, plus the
+// static exposure limits of the caller's desk.
+func (h *V2RiskHandler) limits(c *gin.Context) {
+	if h.Risk == nil {
+		Error(c, http.StatusInternalServerError, "risk unavailable", nil)
+		return
+	}
+	rates, err := h.Risk.RateLimits(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	mgr := h.Risk
+	if scope := tenantScope(c); scope != nil {
+		mgr = mgr.ForTenant(*scope)
+	}
+	Ok(c, gin.H{
+		"rate_limits": rates,
+		"exposure": gin.H{
+			"max_total_exposure_usd": mgr.Config.MaxTotalExposureUSD,
+			"max_per_market_usd":     mgr.Config.MaxPerMarketUSD,
+			"max_per_strategy_usd":   mgr.Config.MaxPerStrategyUSD,
+			"max_daily_loss_usd":     mgr.Config.MaxDailyLossUSD,
+		},
+	}, nil)
+}
+
+type varQuery struct {
+	Method     string  `form:"method" binding:"omitempty,oneof=historical monte_carlo"`
+	Confidence float64 `form:"confidence" binding:"omitempty,gt=0,lt=1"`
+}
+
+// valueAtRisk reports 1-day VaR and expected shortfall. Scoped requests see
+// their desk's positions.
+func (h *V2RiskHandler) valueAtRisk(c *gin.Context) {
+	if h.Risk == nil {
+		Error(c, http.StatusInternalServerError, "risk unavailable", nil)
+		return
+	}
+	q := queryOf[varQuery](c)
+	opts := risk.VaROptions{Method: q.Method, Confidence: q.Confidence}
+	mgr := h.Risk
+	if scope := tenantScope(c); scope != nil {
+		mgr = mgr.ForTenant(*scope)
+	}
+	res, err := mgr.ComputeVaR(c.Request.Context(), opts)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unknown var method") {
+			Error(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, res, map[string]any{
+		"max_var_usd": mgr.Config.VaR.MaxVaRUSD,
+	})
+}
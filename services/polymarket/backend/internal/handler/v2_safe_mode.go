@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/service"
+)
+
+// V2SafeModeHandler lets operators stop automated trading and background
+// syncs in one call during an incident, and resume afterwards.
+type V2SafeModeHandler struct {
+	SafeMode *service.SafeModeService
+}
+
+func (h *V2SafeModeHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/system/safe-mode")
+	group.GET("", h.status)
+	group.POST("", h.enter)
+	group.POST("/resume", h.resume)
+}
+
+type safeModeRequest struct {
+	Reason string `json:"reason"`
+	// CancelOrders overrides safe_mode.cancel_open_orders when set.
+	CancelOrders *bool `json:"cancel_orders"`
+}
+
+func (h *V2SafeModeHandler) allowed(c *gin.Context) bool {
+	if h.SafeMode == nil {
+		Error(c, http.StatusServiceUnavailable, "safe mode unavailable", nil)
+		return false
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "safe mode requires an unscoped token", nil)
+		return false
+	}
+	return true
+}
+
+func (h *V2SafeModeHandler) status(c *gin.Context) {
+	if h.SafeMode == nil {
+		Error(c, http.StatusServiceUnavailable, "safe mode unavailable", nil)
+		return
+	}
+	state, err := h.SafeMode.Status(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, state, nil)
+}
+
+func (h *V2SafeModeHandler) enter(c *gin.Context) {
+	if !h.allowed(c) {
+		return
+	}
+	var req safeModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		Error(c, http.StatusBadRequest, "reason required", nil)
+		return
+	}
+	cancel := h.SafeMode.Config.CancelOpenOrders
+	if req.CancelOrders != nil {
+		cancel = *req.CancelOrders
+	}
+	res, err := h.SafeMode.Enter(c.Request.Context(), req.Reason, cancel, time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, res, nil)
+}
+
+func (h *V2SafeModeHandler) resume(c *gin.Context) {
+	if !h.allowed(c) {
+		return
+	}
+	res, err := h.SafeMode.Resume(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, res, nil)
+}
@@ -9,20 +9,27 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shopspring/decimal"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 
 	"polymarket/internal/models"
-	"polymarket/internal/paas"
 	"polymarket/internal/repository"
+	"polymarket/internal/service"
 )
 
 type V2SettlementHandler struct {
 	Repo repository.Repository
+	// Outbox records the audit logs of settlement writes with them.
+	Outbox *service.OutboxDispatcher
 }
 
 func (h *V2SettlementHandler) Register(r *gin.Engine) {
 	group := r.Group("/api/v2/settlements")
 	group.POST("", h.upsert)
-	group.GET("/label-rates", h.labelRates)
+	group.GET("/label-rates", validateQuery[labelRatesQuery](), h.labelRates)
+}
+
+type labelRatesQuery struct {
+	Labels []string `form:"labels"`
 }
 
 type upsertSettlementRequest struct {
@@ -125,17 +132,25 @@ func (h *V2SettlementHandler) upsert(c *gin.Context) {
 	if req.Category != nil {
 		item.Category = strings.TrimSpace(*req.Category)
 	}
+	if initial != nil {
+		item.InitialYesPriceSource = service.InitialPriceSourceManual
+	}
 
-	if err := h.Repo.UpsertMarketSettlementHistory(c.Request.Context(), item); err != nil {
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).UpsertMarketSettlementHistory(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_settlement_upserted", "info", map[string]any{
+			"market_id":  item.MarketID,
+			"event_id":   item.EventID,
+			"outcome":    item.Outcome,
+			"settled_at": item.SettledAt.Format(time.RFC3339),
+		}), nil
+	})
+	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	paas.LogBestEffort(c, "polymarket_settlement_upserted", "info", map[string]any{
-		"market_id":  item.MarketID,
-		"event_id":   item.EventID,
-		"outcome":    item.Outcome,
-		"settled_at": item.SettledAt.Format(time.RFC3339),
-	})
 	Ok(c, item, nil)
 }
 
@@ -144,12 +159,7 @@ func (h *V2SettlementHandler) labelRates(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	labels := strings.TrimSpace(c.Query("labels"))
-	var filter []string
-	if labels != "" {
-		filter = strings.Split(labels, ",")
-	}
-	rows, err := h.Repo.ListLabelNoRateStats(c.Request.Context(), filter)
+	rows, err := h.Repo.ListLabelNoRateStats(c.Request.Context(), queryOf[labelRatesQuery](c).Labels)
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/signal"
+)
+
+// testFireTimeout bounds one sandboxed collection cycle.
+const testFireTimeout = 30 * time.Second
+
+// getSourceConfig shows a running collector's settings and whether it can
+// be reconfigured or test-fired.
+func (h *V2SignalHandler) getSourceConfig(c *gin.Context) {
+	if h.Hub == nil {
+		Error(c, http.StatusInternalServerError, "signal hub unavailable", nil)
+		return
+	}
+	item, err := h.Hub.Collector(c.Param("name"))
+	if err != nil {
+		collectorError(c, err)
+		return
+	}
+	Ok(c, item, nil)
+}
+
+// putSourceConfig merges the body over the collector's settings and
+// restarts it. Collectors are shared by all desks, so this needs an
+// unscoped token; settings revert to the config file on restart.
+func (h *V2SignalHandler) putSourceConfig(c *gin.Context) {
+	if h.Hub == nil {
+		Error(c, http.StatusInternalServerError, "signal hub unavailable", nil)
+		return
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "collector settings require an unscoped token", nil)
+		return
+	}
+	body, err := c.GetRawData()
+	if err != nil || !jsonObject(body) {
+		Error(c, http.StatusBadRequest, "body must be a JSON object of settings", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	before, err := h.Hub.Collector(name)
+	if err != nil {
+		collectorError(c, err)
+		return
+	}
+	after, err := h.Hub.Reconfigure(name, body)
+	if err != nil {
+		collectorError(c, err)
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_signal_source_reconfigured", "info", map[string]any{
+		"collector": name,
+		"before":    before.Settings,
+		"after":     after.Settings,
+	})
+	Ok(c, after, map[string]any{"previous": before.Settings})
+}
+
+// testFireSource runs one collection cycle and returns the signals it would
+// emit without persisting them. An optional body of settings is tried on a
+// copy and not applied.
+func (h *V2SignalHandler) testFireSource(c *gin.Context) {
+	if h.Hub == nil {
+		Error(c, http.StatusInternalServerError, "signal hub unavailable", nil)
+		return
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "collector test-fire requires an unscoped token", nil)
+		return
+	}
+	body, err := c.GetRawData()
+	if err != nil || (len(bytes.TrimSpace(body)) > 0 && !jsonObject(body)) {
+		Error(c, http.StatusBadRequest, "body must be empty or a JSON object of settings", nil)
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), testFireTimeout)
+	defer cancel()
+	res, err := h.Hub.TestFire(ctx, c.Param("name"), body)
+	if err != nil {
+		collectorError(c, err)
+		return
+	}
+	Ok(c, res, map[string]any{"count": len(res.Signals), "sandbox_settings": len(bytes.TrimSpace(body)) > 0})
+}
+
+func collectorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, signal.ErrUnknownCollector):
+		Error(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, signal.ErrNotReconfigurable), errors.Is(err, signal.ErrNoTestFire):
+		Error(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, signal.ErrInvalidSettings):
+		Error(c, http.StatusBadRequest, err.Error(), nil)
+	default:
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+	}
+}
+
+func jsonObject(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return len(b) > 0 && b[0] == '{'
+}
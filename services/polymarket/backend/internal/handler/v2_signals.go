@@ -2,58 +2,101 @@ package handler
 
 import (
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"polymarket/internal/repository"
+	"polymarket/internal/service"
+	"polymarket/internal/signal"
+	"polymarket/internal/strategy"
 )
 
 type V2SignalHandler struct {
-	Repo repository.Repository
+	Repo    repository.Repository
+	Quality *service.SignalQualityService
+	// Hub serves collector settings and test-fire; nil while the strategy
+	// engine is off.
+	Hub *signal.SignalHub
+	// Outbox records the audit logs of collector changes.
+	Outbox *service.OutboxDispatcher
 }
 
 func (h *V2SignalHandler) Register(r *gin.Engine) {
 	group := r.Group("/api/v2/signals")
-	group.GET("", h.listSignals)
+	group.GET("", validateQuery[listSignalsQuery](), h.listSignals)
 	group.GET("/sources", h.listSources)
+	group.GET("/sources/:name/config", h.getSourceConfig)
+	group.PUT("/sources/:name/config", h.putSourceConfig)
+	group.POST("/sources/:name/test-fire", h.testFireSource)
+	group.GET("/types", h.types)
+	group.GET("/quality", validateQuery[timeRangeQuery](), h.quality)
+	group.GET("/wallets/positions", validateQuery[walletPositionsQuery](), h.listWalletPositions)
+	group.GET("/wallets/changes", validateQuery[listWalletChangesQuery](), h.listWalletChanges)
 }
 
-func (h *V2SignalHandler) listSignals(c *gin.Context) {
-	if h.Repo == nil {
-		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
-		return
+type listSignalsQuery struct {
+	pageQuery
+	Type   *string    `form:"type"`
+	Source *string    `form:"source"`
+	Since  *time.Time `form:"since"`
+}
+
+type walletPositionsQuery struct {
+	Wallet string `form:"wallet"`
+}
+
+type listWalletChangesQuery struct {
+	pageQuery
+	Wallet      *string    `form:"wallet"`
+	MarketID    *string    `form:"market_id"`
+	Since       *time.Time `form:"since"`
+	SignalsOnly bool       `form:"signals_only"`
+}
+
+// signalTypeDoc is a signal type with the switch of its collector and the
+// built-in strategies consuming it.
+type signalTypeDoc struct {
+	signal.SignalTypeInfo
+	FeatureSwitch string   `json:"feature_switch,omitempty"`
+	Strategies    []string `json:"strategies"`
+}
+
+func (h *V2SignalHandler) types(c *gin.Context) {
+	consumers := map[string][]string{}
+	for _, ev := range strategy.Builtins() {
+		for _, sig := range ev.RequiredSignals() {
+			consumers[sig] = append(consumers[sig], ev.Name())
+		}
 	}
-	typ := strings.TrimSpace(c.Query("type"))
-	source := strings.TrimSpace(c.Query("source"))
-	since := strings.TrimSpace(c.Query("since"))
-	limit := intQuery(c, "limit", 50)
-	offset := intQuery(c, "offset", 0)
-
-	var sinceTime *time.Time
-	if since != "" {
-		if parsed, err := time.Parse(time.RFC3339, since); err == nil {
-			parsed = parsed.UTC()
-			sinceTime = &parsed
+	items := signal.SignalTypes()
+	out := make([]signalTypeDoc, 0, len(items))
+	for _, item := range items {
+		strategies := consumers[item.Type]
+		if strategies == nil {
+			strategies = []string{}
 		}
+		out = append(out, signalTypeDoc{
+			SignalTypeInfo: item,
+			FeatureSwitch:  service.SignalFeatureSwitches[item.Type],
+			Strategies:     strategies,
+		})
 	}
+	Ok(c, out, nil)
+}
 
-	var typePtr *string
-	if typ != "" {
-		typePtr = &typ
-	}
-	var sourcePtr *string
-	if source != "" {
-		sourcePtr = &source
+func (h *V2SignalHandler) listSignals(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
 	}
-
+	q := queryOf[listSignalsQuery](c)
 	items, err := h.Repo.ListSignals(c.Request.Context(), repository.ListSignalsParams{
-		Limit:   limit,
-		Offset:  offset,
-		Type:    typePtr,
-		Source:  sourcePtr,
-		Since:   sinceTime,
+		Limit:   q.Limit,
+		Offset:  q.Offset,
+		Type:    q.Type,
+		Source:  q.Source,
+		Since:   q.Since,
 		OrderBy: "created_at",
 		Asc:     boolPtr(false),
 	})
@@ -61,10 +104,33 @@ func (h *V2SignalHandler) listSignals(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	meta := paginationMeta(limit, offset, int64(len(items)))
+	meta := paginationMeta(q.Limit, q.Offset, int64(len(items)))
 	Ok(c, items, meta)
 }
 
+// quality returns per signal-type precision and the weight the strategy
+// engine applies. Without since the cached scores are returned.
+func (h *V2SignalHandler) quality(c *gin.Context) {
+	if h.Quality == nil {
+		Error(c, http.StatusInternalServerError, "signal quality unavailable", nil)
+		return
+	}
+	var (
+		report *service.SignalQualityReport
+		err    error
+	)
+	if since := queryOf[timeRangeQuery](c).Since; since != nil {
+		report, err = h.Quality.Compute(c.Request.Context(), since)
+	} else {
+		report, err = h.Quality.Report(c.Request.Context())
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, report, nil)
+}
+
 func (h *V2SignalHandler) listSources(c *gin.Context) {
 	if h.Repo == nil {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
@@ -78,4 +144,47 @@ func (h *V2SignalHandler) listSources(c *gin.Context) {
 	Ok(c, items, nil)
 }
 
+// listWalletPositions returns the latest known positions of tracked wallets.
+func (h *V2SignalHandler) listWalletPositions(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	items, err := h.Repo.ListWalletPositions(c.Request.Context(), queryOf[walletPositionsQuery](c).Wallet)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, nil)
+}
+
+// listWalletChanges pages position changes of tracked wallets, newest first.
+// signals_only=true keeps only changes that emitted smart_money_move.
+func (h *V2SignalHandler) listWalletChanges(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[listWalletChangesQuery](c)
+	params := repository.ListWalletPositionChangesParams{
+		Limit:       q.Limit,
+		Offset:      q.Offset,
+		Wallet:      q.Wallet,
+		MarketID:    q.MarketID,
+		Since:       q.Since,
+		SignalsOnly: q.SignalsOnly,
+	}
+	items, err := h.Repo.ListWalletPositionChanges(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountWalletPositionChanges(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
 func boolPtr(v bool) *bool { return &v }
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2SLOHandler exposes the data pipeline freshness SLOs for the dashboard:
+// their burn rates and alerts, and the stored samples.
+type V2SLOHandler struct {
+	Repo repository.Repository
+	SLO  *service.SLOService
+	// Outbox records the audit logs of manual evaluations.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2SLOHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/slo")
+	group.GET("", h.status)
+	group.GET("/samples", validateQuery[sloSamplesQuery](), h.samples)
+	group.POST("/evaluate", h.evaluate)
+}
+
+type sloSamplesQuery struct {
+	pageQuery
+	timeRangeQuery
+	SLO *string `form:"slo" binding:"omitempty,oneof=catalog_freshness book_freshness settlement_latency"`
+}
+
+func (h *V2SLOHandler) status(c *gin.Context) {
+	if h.SLO == nil {
+		Error(c, http.StatusInternalServerError, "slo service unavailable", nil)
+		return
+	}
+	items, err := h.SLO.Status(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	cfg := h.SLO.Config
+	Ok(c, items, map[string]any{
+		"enabled":      cfg.Enabled,
+		"interval":     cfg.Interval.String(),
+		"last_run":     h.SLO.LastRun(),
+		"notify_event": cfg.NotifyEvent,
+	})
+}
+
+func (h *V2SLOHandler) samples(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[sloSamplesQuery](c)
+	params := repository.ListSLOSamplesParams{
+		Limit:  q.Limit,
+		Offset: q.Offset,
+		SLO:    q.SLO,
+		Since:  q.Since,
+		Until:  q.Until,
+	}
+	items, err := h.Repo.ListSLOSamples(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountSLOSamples(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
+// evaluate runs one evaluation and alerting pass now instead of waiting for
+// the next scheduled one.
+func (h *V2SLOHandler) evaluate(c *gin.Context) {
+	if h.SLO == nil {
+		Error(c, http.StatusInternalServerError, "slo service unavailable", nil)
+		return
+	}
+	if !h.SLO.Config.Enabled {
+		Error(c, http.StatusConflict, "slo evaluation disabled", nil)
+		return
+	}
+	items, err := h.SLO.RunOnce(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	firing := []string{}
+	for _, st := range items {
+		for _, a := range st.Alerts {
+			if a.Firing {
+				firing = append(firing, st.SLO+"/"+a.Severity)
+			}
+		}
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_slo_evaluated", "info", map[string]any{"firing": firing})
+	Ok(c, items, nil)
+}
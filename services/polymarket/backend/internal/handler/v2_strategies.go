@@ -5,23 +5,60 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
+	"polymarket/internal/models"
 	"polymarket/internal/paas"
 	"polymarket/internal/repository"
+	"polymarket/internal/service"
+	"polymarket/internal/strategy"
 )
 
 type V2StrategyHandler struct {
-	Repo repository.Repository
+	Repo    repository.Repository
+	Toggle  *service.StrategyToggleService
+	Budgets *service.StrategyBudgetService
+	// Changes, when set, records params updates with a holdout cohort.
+	Changes *service.StrategyChangeService
+	// Capacity estimates how much the strategy can deploy.
+	Capacity *service.StrategyCapacityService
+	// Drift compares live stats with backtest expectations.
+	Drift *service.StrategyDriftService
+	// Outbox records the audit logs of strategy writes with them.
+	Outbox *service.OutboxDispatcher
+	// Tuner grid-searches params; nil disables the tuning routes.
+	Tuner *service.StrategyParamTuner
 }
 
 func (h *V2StrategyHandler) Register(r *gin.Engine) {
-	group := r.Group("/api/v2/strategies")
+	group := r.Group("/api/v2/strategies", tenantGuard("name", "strategy not found", h.strategyTenant))
 	group.GET("", h.listStrategies)
+	group.GET("/dependencies", h.dependencies)
+	group.GET("/drift", h.driftReport)
+	group.POST("/bulk/enable", h.bulkEnable)
+	group.POST("/bulk/disable", h.bulkDisable)
 	group.GET("/:name", h.getStrategy)
 	group.GET("/:name/stats", h.stats)
+	group.GET("/:name/schema", h.schema)
+	group.GET("/:name/runs", validateQuery[listRunsQuery](), h.runs)
 	group.POST("/:name/enable", h.enableStrategy)
 	group.POST("/:name/disable", h.disableStrategy)
 	group.PUT("/:name/params", h.updateParams)
+	group.POST("/:name/tenant", h.setTenant)
+	group.POST("/:name/launch-stage", h.setLaunchStage)
+	group.GET("/:name/budget", h.budget)
+	group.PUT("/:name/budget", h.putBudget)
+	group.GET("/:name/change-analysis", h.changeAnalysis)
+	group.GET("/:name/capacity", h.capacity)
+	group.GET("/:name/expectation", h.expectation)
+	group.PUT("/:name/expectation", h.putExpectation)
+	group.DELETE("/:name/expectation", h.deleteExpectation)
+	group.GET("/:name/drift", h.drift)
+	group.POST("/:name/tune", h.tune)
+	group.GET("/:name/tunings", validateQuery[paramTuningsQuery](), h.listTunings)
+	group.GET("/:name/tunings/:id", h.getTuning)
+	group.POST("/:name/tunings/:id/approve", h.reviewTuning(models.RuleTuningApproved))
+	group.POST("/:name/tunings/:id/reject", h.reviewTuning(models.RuleTuningRejected))
 }
 
 func (h *V2StrategyHandler) listStrategies(c *gin.Context) {
@@ -34,6 +71,15 @@ func (h *V2StrategyHandler) listStrategies(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
+	if tenantScope(c) != nil {
+		visible := make([]models.Strategy, 0, len(items))
+		for _, item := range items {
+			if tenantVisible(c, item.Tenant) {
+				visible = append(visible, item)
+			}
+		}
+		items = visible
+	}
 	Ok(c, items, nil)
 }
 
@@ -87,7 +133,7 @@ func (h *V2StrategyHandler) stats(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	analyticsRows, err := h.Repo.AnalyticsByStrategy(c.Request.Context())
+	analyticsRows, err := h.Repo.AnalyticsByStrategy(c.Request.Context(), paas.TenantFromGin(c))
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
@@ -115,6 +161,78 @@ func (h *V2StrategyHandler) stats(c *gin.Context) {
 	}, nil)
 }
 
+// schema documents a built-in strategy's parameters for form rendering,
+// with its most recent opportunities as examples.
+func (h *V2StrategyHandler) schema(c *gin.Context) {
+	name := strings.TrimSpace(c.Param("name"))
+	out, ok := strategy.FindSchema(name)
+	if !ok {
+		Error(c, http.StatusNotFound, "strategy not found", nil)
+		return
+	}
+	examples := []models.Opportunity{}
+	if h.Repo != nil {
+		items, err := h.Repo.ListOpportunities(c.Request.Context(), repository.ListOpportunitiesParams{
+			Limit:        3,
+			StrategyName: &name,
+			Tenant:       tenantScope(c),
+			OrderBy:      "created_at",
+			Asc:          boolPtr(false),
+		})
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		examples = append(examples, items...)
+	}
+	Ok(c, map[string]any{
+		"schema":                out,
+		"feature_switches":      service.StrategyDependencies(models.Strategy{RequiredSignals: stringListJSON(out.RequiredSignals)}),
+		"example_opportunities": examples,
+	}, nil)
+}
+
+type listRunsQuery struct {
+	timeRangeQuery
+	Limit      int  `form:"limit" default:"100" binding:"min=1,max=1000"`
+	Offset     int  `form:"offset" default:"0" binding:"min=0"`
+	ErrorsOnly bool `form:"errors_only"`
+}
+
+func (h *V2StrategyHandler) runs(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" {
+		Error(c, http.StatusBadRequest, "name required", nil)
+		return
+	}
+	q := queryOf[listRunsQuery](c)
+	params := repository.ListEvaluationRunsParams{
+		Limit:        q.Limit,
+		Offset:       q.Offset,
+		StrategyName: &name,
+		Since:        q.Since,
+		Until:        q.Until,
+		ErrorsOnly:   q.ErrorsOnly,
+		OrderBy:      "started_at",
+		Asc:          boolPtr(false),
+	}
+	items, err := h.Repo.ListEvaluationRuns(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountEvaluationRuns(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
 func (h *V2StrategyHandler) enableStrategy(c *gin.Context) {
 	h.setEnabled(c, true)
 }
@@ -133,21 +251,100 @@ func (h *V2StrategyHandler) setEnabled(c *gin.Context, enabled bool) {
 		Error(c, http.StatusBadRequest, "name required", nil)
 		return
 	}
-	if err := h.Repo.SetStrategyEnabled(c.Request.Context(), name, enabled); err != nil {
-		Error(c, http.StatusBadGateway, err.Error(), nil)
-		return
-	}
 	action := "polymarket_strategy_disabled"
 	if enabled {
 		action = "polymarket_strategy_enabled"
 	}
-	paas.LogBestEffort(c, action, "info", map[string]any{
-		"name":    name,
-		"enabled": enabled,
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).SetStrategyEnabled(c.Request.Context(), name, enabled); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, action, "info", map[string]any{
+			"name":    name,
+			"enabled": enabled,
+		}), nil
 	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
 	Ok(c, map[string]any{"name": name, "enabled": enabled}, nil)
 }
 
+// dependencies lists the feature switches each strategy requires.
+func (h *V2StrategyHandler) dependencies(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	items, err := h.Repo.ListStrategies(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	out := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if !tenantVisible(c, item.Tenant) {
+			continue
+		}
+		out = append(out, map[string]any{
+			"name":     item.Name,
+			"enabled":  item.Enabled,
+			"requires": service.StrategyDependencies(item),
+		})
+	}
+	Ok(c, out, nil)
+}
+
+func (h *V2StrategyHandler) bulkEnable(c *gin.Context) {
+	h.bulkSetEnabled(c, true)
+}
+
+func (h *V2StrategyHandler) bulkDisable(c *gin.Context) {
+	h.bulkSetEnabled(c, false)
+}
+
+// bulkSetEnabled enables or disables strategies by name and/or category.
+// Switches are global, so auto_switches requires an unscoped token.
+func (h *V2StrategyHandler) bulkSetEnabled(c *gin.Context, enabled bool) {
+	if h.Toggle == nil {
+		Error(c, http.StatusInternalServerError, "strategy toggle service unavailable", nil)
+		return
+	}
+	var req service.StrategyToggleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if len(req.Names) == 0 && strings.TrimSpace(req.Category) == "" {
+		Error(c, http.StatusBadRequest, "names or category required", nil)
+		return
+	}
+	if req.AutoSwitches && tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "auto_switches requires an unscoped token", nil)
+		return
+	}
+	req.Enable = enabled
+	plan, err := h.Toggle.Apply(c.Request.Context(), req, func(s models.Strategy) bool {
+		return tenantVisible(c, s.Tenant)
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if plan.Applied {
+		action := "polymarket_strategy_bulk_disabled"
+		if enabled {
+			action = "polymarket_strategy_bulk_enabled"
+		}
+		logWithOutbox(c, h.Outbox, action, "info", map[string]any{
+			"strategies": plan.Strategies,
+			"switches":   plan.Switches,
+		})
+	}
+	Ok(c, plan, nil)
+}
+
 func (h *V2StrategyHandler) updateParams(c *gin.Context) {
 	if h.Repo == nil {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
@@ -167,12 +364,191 @@ func (h *V2StrategyHandler) updateParams(c *gin.Context) {
 		Error(c, http.StatusBadRequest, "params required", nil)
 		return
 	}
-	if err := h.Repo.UpdateStrategyParams(c.Request.Context(), name, body); err != nil {
+	if h.Changes == nil {
+		err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+			if err := h.Repo.WithTx(tx).UpdateStrategyParams(c.Request.Context(), name, body); err != nil {
+				return nil, err
+			}
+			return outboxLog(c, "polymarket_strategy_params_updated", "info", map[string]any{
+				"name": name,
+			}), nil
+		})
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		Ok(c, map[string]any{"name": name}, nil)
+		return
+	}
+	pct, duration, err := holdoutOverrides(c)
+	if err != nil {
+		Error(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	change, err := h.Changes.ChangeParams(c.Request.Context(), name, body, pct, duration)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	details := map[string]any{"name": name}
+	if change != nil {
+		details["change_id"] = change.ID
+		details["holdout_pct"] = change.HoldoutPct
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_strategy_params_updated", "info", details)
+	Ok(c, map[string]any{"name": name, "change": change}, nil)
+}
+
+type setStrategyTenantRequest struct {
+	Tenant string `json:"tenant"`
+}
+
+// setTenant assigns a strategy to a desk. Only unscoped (operator) requests
+// may move strategies between desks.
+func (h *V2StrategyHandler) setTenant(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "tenant assignment requires an unscoped token", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" {
+		Error(c, http.StatusBadRequest, "name required", nil)
+		return
+	}
+	var req setStrategyTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	tenant := paas.NormalizeTenant(req.Tenant)
+	if tenant == "" {
+		Error(c, http.StatusBadRequest, "tenant required", nil)
+		return
+	}
+	strat, err := h.Repo.GetStrategyByName(c.Request.Context(), name)
+	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	paas.LogBestEffort(c, "polymarket_strategy_params_updated", "info", map[string]any{
-		"name": name,
+	if strat == nil {
+		Error(c, http.StatusNotFound, "strategy not found", nil)
+		return
+	}
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).SetStrategyTenant(c.Request.Context(), name, tenant); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_strategy_tenant_updated", "info", map[string]any{
+			"name":     name,
+			"previous": strat.Tenant,
+			"tenant":   tenant,
+		}), nil
 	})
-	Ok(c, map[string]any{"name": name}, nil)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, map[string]any{"name": name, "tenant": tenant}, nil)
+}
+
+type setLaunchStageRequest struct {
+	Stage  string `json:"stage"`
+	Reason string `json:"reason"`
+	// Force allows skipping stages on promotion.
+	Force bool `json:"force"`
+}
+
+var launchStageOrder = map[string]int{
+	models.LaunchStageDark:   0,
+	models.LaunchStageShadow: 1,
+	models.LaunchStageCapped: 2,
+	models.LaunchStageFull:   3,
+}
+
+// setLaunchStage moves a strategy through dark → shadow → capped → full.
+// Promotions go one stage at a time unless forced; demotions may jump back
+// any number of stages. Demoting to dark or shadow cancels the strategy's
+// active opportunities so none stay executable.
+func (h *V2StrategyHandler) setLaunchStage(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" {
+		Error(c, http.StatusBadRequest, "name required", nil)
+		return
+	}
+	var req setLaunchStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	stage := strings.ToLower(strings.TrimSpace(req.Stage))
+	if !models.ValidLaunchStage(stage) {
+		Error(c, http.StatusBadRequest, "stage must be dark, shadow, capped or full", nil)
+		return
+	}
+	strat, err := h.Repo.GetStrategyByName(c.Request.Context(), name)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if strat == nil {
+		Error(c, http.StatusNotFound, "strategy not found", nil)
+		return
+	}
+	previous := strat.LaunchStage
+	if !models.ValidLaunchStage(previous) {
+		previous = models.LaunchStageFull
+	}
+	if launchStageOrder[stage] > launchStageOrder[previous]+1 && !req.Force {
+		Error(c, http.StatusConflict, "promotion must go one stage at a time", map[string]any{"from": previous, "to": stage})
+		return
+	}
+	ctx := c.Request.Context()
+	cancelled := int64(0)
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		repo := h.Repo.WithTx(tx)
+		if err := repo.SetStrategyLaunchStage(ctx, name, stage); err != nil {
+			return nil, err
+		}
+		if launchStageOrder[stage] < launchStageOrder[previous] && launchStageOrder[stage] <= launchStageOrder[models.LaunchStageShadow] {
+			active := "active"
+			items, err := repo.ListOpportunities(ctx, repository.ListOpportunitiesParams{
+				Limit:        500,
+				Status:       &active,
+				StrategyName: &name,
+			})
+			if err != nil {
+				return nil, err
+			}
+			ids := make([]uint64, 0, len(items))
+			for _, it := range items {
+				ids = append(ids, it.ID)
+			}
+			if len(ids) > 0 {
+				if cancelled, err = repo.BulkUpdateOpportunityStatus(ctx, ids, "cancelled"); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return outboxLog(c, "polymarket_strategy_launch_stage_updated", "info", map[string]any{
+			"name":                    name,
+			"previous":                previous,
+			"stage":                   stage,
+			"reason":                  strings.TrimSpace(req.Reason),
+			"forced":                  req.Force,
+			"cancelled_opportunities": cancelled,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, map[string]any{"name": name, "previous": previous, "stage": stage, "cancelled_opportunities": cancelled}, nil)
 }
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+)
+
+type putStrategyBudgetRequest struct {
+	// Each cap is a USD amount; "0" removes it.
+	DailyTurnoverUSD  *string `json:"daily_turnover_usd"`
+	WeeklyTurnoverUSD *string `json:"weekly_turnover_usd"`
+	DailyFeesUSD      *string `json:"daily_fees_usd"`
+	WeeklyFeesUSD     *string `json:"weekly_fees_usd"`
+}
+
+func (h *V2StrategyHandler) budget(c *gin.Context) {
+	if h.Repo == nil || h.Budgets == nil {
+		Error(c, http.StatusInternalServerError, "budgets unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" {
+		Error(c, http.StatusBadRequest, "name required", nil)
+		return
+	}
+	strat, err := h.Repo.GetStrategyByName(c.Request.Context(), name)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if strat == nil {
+		Error(c, http.StatusNotFound, "strategy not found", nil)
+		return
+	}
+	usage, err := h.Budgets.Usage(c.Request.Context(), name, time.Now().UTC())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, usage, nil)
+}
+
+// putBudget sets the strategy's caps. The pause state is re-evaluated right
+// away, so raising a spent budget resumes the strategy.
+func (h *V2StrategyHandler) putBudget(c *gin.Context) {
+	if h.Repo == nil || h.Budgets == nil {
+		Error(c, http.StatusInternalServerError, "budgets unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" {
+		Error(c, http.StatusBadRequest, "name required", nil)
+		return
+	}
+	var req putStrategyBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	strat, err := h.Repo.GetStrategyByName(ctx, name)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if strat == nil {
+		Error(c, http.StatusNotFound, "strategy not found", nil)
+		return
+	}
+	item, err := h.Repo.GetStrategyBudget(ctx, name)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if item == nil {
+		item = &models.StrategyBudget{StrategyName: name}
+	}
+	for _, f := range []struct {
+		key string
+		raw *string
+		dst *decimal.Decimal
+	}{
+		{"daily_turnover_usd", req.DailyTurnoverUSD, &item.DailyTurnoverUSD},
+		{"weekly_turnover_usd", req.WeeklyTurnoverUSD, &item.WeeklyTurnoverUSD},
+		{"daily_fees_usd", req.DailyFeesUSD, &item.DailyFeesUSD},
+		{"weekly_fees_usd", req.WeeklyFeesUSD, &item.WeeklyFeesUSD},
+	} {
+		if f.raw == nil {
+			continue
+		}
+		v, err := decimal.NewFromString(strings.TrimSpace(*f.raw))
+		if err != nil || v.IsNegative() {
+			Error(c, http.StatusBadRequest, "invalid "+f.key, nil)
+			return
+		}
+		*f.dst = v
+	}
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).SaveStrategyBudget(ctx, item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_strategy_budget_set", "info", map[string]any{
+			"strategy":            name,
+			"daily_turnover_usd":  item.DailyTurnoverUSD.String(),
+			"weekly_turnover_usd": item.WeeklyTurnoverUSD.String(),
+			"daily_fees_usd":      item.DailyFeesUSD.String(),
+			"weekly_fees_usd":     item.WeeklyFeesUSD.String(),
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	now := time.Now().UTC()
+	if _, err := h.Budgets.Check(ctx, name, now); err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	usage, err := h.Budgets.Usage(ctx, name, now)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, usage, nil)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/governor"
+	"polymarket/internal/service"
+)
+
+// V2StrategyBundleHandler exports and imports strategy bundles. Bundles move
+// strategies across desks and deployments, so tenant-scoped tokens are
+// refused.
+type V2StrategyBundleHandler struct {
+	Bundles *service.StrategyBundleService
+	// Governor, when set, queues imports behind other heavy jobs.
+	Governor *governor.Governor
+	// Outbox records the audit logs of exports and imports.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2StrategyBundleHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/strategies/bundle")
+	group.GET("/export", validateQuery[bundleExportQuery](), h.export)
+	group.POST("/import", heavyJob(h.Governor, governor.ClassAdmin), validateQuery[bundleImportQuery](), h.importBundle)
+}
+
+type bundleExportQuery struct {
+	Names []string `form:"names"`
+}
+
+type bundleImportQuery struct {
+	DryRun    bool `form:"dry_run"`
+	Overwrite bool `form:"overwrite"`
+}
+
+func (h *V2StrategyBundleHandler) ready(c *gin.Context) bool {
+	if h.Bundles == nil || h.Bundles.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return false
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "strategy bundles require an unscoped token", nil)
+		return false
+	}
+	return true
+}
+
+// export bundles the strategies in ?names= (comma-separated), or all.
+func (h *V2StrategyBundleHandler) export(c *gin.Context) {
+	if !h.ready(c) {
+		return
+	}
+	bundle, err := h.Bundles.Export(c.Request.Context(), queryOf[bundleExportQuery](c).Names)
+	if err != nil {
+		if errors.Is(err, service.ErrBundleInvalid) {
+			Error(c, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_strategy_bundle_exported", "info", map[string]any{
+		"strategies": len(bundle.Strategies),
+		"signed":     bundle.Signature != nil,
+	})
+	Ok(c, bundle, nil)
+}
+
+// importBundle writes a bundle's strategies; ?dry_run=true reports what would
+// change and ?overwrite=true replaces existing definitions.
+func (h *V2StrategyBundleHandler) importBundle(c *gin.Context) {
+	if !h.ready(c) {
+		return
+	}
+	var bundle service.StrategyBundle
+	if err := c.ShouldBindJSON(&bundle); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	q := queryOf[bundleImportQuery](c)
+	opts := service.BundleImportOptions{DryRun: q.DryRun, Overwrite: q.Overwrite}
+	report, err := h.Bundles.Import(c.Request.Context(), &bundle, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrBundleUnsigned),
+			errors.Is(err, service.ErrBundleBadSignature),
+			errors.Is(err, service.ErrBundleUntrustedKey):
+			Error(c, http.StatusUnauthorized, err.Error(), nil)
+		case errors.Is(err, service.ErrBundleInvalid):
+			Error(c, http.StatusBadRequest, err.Error(), nil)
+		default:
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+		}
+		return
+	}
+	logWithOutbox(c, h.Outbox, "polymarket_strategy_bundle_imported", "info", map[string]any{
+		"strategies": len(report.Results),
+		"signed":     report.Signed,
+		"signed_by":  report.SignedBy,
+		"dry_run":    report.DryRun,
+		"overwrite":  opts.Overwrite,
+	})
+	Ok(c, report, nil)
+}
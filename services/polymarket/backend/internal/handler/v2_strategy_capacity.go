@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/models"
+	"polymarket/internal/service"
+)
+
+const strategyCapacityHistoryLimit = 30
+
+// capacity returns the strategy's latest capacity estimate and the ones
+// before it. refresh=true estimates anew first, as does a strategy without
+// an estimate yet.
+func (h *V2StrategyHandler) capacity(c *gin.Context) {
+	if h.Repo == nil || h.Capacity == nil {
+		Error(c, http.StatusInternalServerError, "capacity unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" {
+		Error(c, http.StatusBadRequest, "name required", nil)
+		return
+	}
+	refresh := false
+	if raw := strings.TrimSpace(c.Query("refresh")); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			Error(c, http.StatusBadRequest, "invalid refresh", nil)
+			return
+		}
+		refresh = v
+	}
+	ctx := c.Request.Context()
+	var (
+		latest *models.StrategyCapacity
+		err    error
+	)
+	if !refresh {
+		if latest, err = h.Repo.GetLatestStrategyCapacity(ctx, name); err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+	}
+	if latest == nil {
+		latest, err = h.Capacity.Estimate(ctx, name, time.Now().UTC())
+		if errors.Is(err, service.ErrStrategyNotFound) {
+			Error(c, http.StatusNotFound, err.Error(), map[string]any{"name": name})
+			return
+		}
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+	}
+	history, err := h.Repo.ListStrategyCapacities(ctx, name, strategyCapacityHistoryLimit)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, gin.H{"estimate": latest, "history": history}, nil)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/service"
+)
+
+// holdoutOverrides reads the optional holdout_pct and holdout (duration)
+// query params of a params update.
+func holdoutOverrides(c *gin.Context) (*float64, *time.Duration, error) {
+	var (
+		pct      *float64
+		duration *time.Duration
+	)
+	if raw := strings.TrimSpace(c.Query("holdout_pct")); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v < 0 || v > 100 {
+			return nil, nil, errors.New("holdout_pct must be between 0 and 100")
+		}
+		pct = &v
+	}
+	if raw := strings.TrimSpace(c.Query("holdout")); raw != "" {
+		v, err := time.ParseDuration(raw)
+		if err != nil || v < 0 {
+			return nil, nil, errors.New("invalid holdout duration")
+		}
+		duration = &v
+	}
+	return pct, duration, nil
+}
+
+// changeAnalysis compares the cohorts of the strategy's latest params change,
+// or of change_id, and says whether the change improved outcomes.
+func (h *V2StrategyHandler) changeAnalysis(c *gin.Context) {
+	if h.Changes == nil {
+		Error(c, http.StatusInternalServerError, "strategy changes unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" {
+		Error(c, http.StatusBadRequest, "name required", nil)
+		return
+	}
+	var changeID uint64
+	if raw := strings.TrimSpace(c.Query("change_id")); raw != "" {
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || v == 0 {
+			Error(c, http.StatusBadRequest, "invalid change_id", nil)
+			return
+		}
+		changeID = v
+	}
+	out, err := h.Changes.Analyze(c.Request.Context(), name, changeID, time.Now().UTC())
+	if errors.Is(err, service.ErrStrategyChangeNotFound) {
+		Error(c, http.StatusNotFound, err.Error(), map[string]any{"name": name})
+		return
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, out, nil)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/service"
+)
+
+const strategyDriftHistoryLimit = 30
+
+type putStrategyExpectationRequest struct {
+	WinRate      *float64   `json:"win_rate"`
+	EdgePct      *float64   `json:"edge_pct"`
+	TradesPerDay *float64   `json:"trades_per_day"`
+	Source       *string    `json:"source"`
+	BacktestFrom *time.Time `json:"backtest_from"`
+	BacktestTo   *time.Time `json:"backtest_to"`
+}
+
+// driftReport lists every strategy with an expectation and its latest
+// drift report.
+func (h *V2StrategyHandler) driftReport(c *gin.Context) {
+	if h.Repo == nil || h.Drift == nil {
+		Error(c, http.StatusInternalServerError, "strategy drift unavailable", nil)
+		return
+	}
+	items, err := h.Drift.Report(c.Request.Context())
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if tenantScope(c) != nil {
+		strategies, err := h.Repo.ListStrategies(c.Request.Context())
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		visible := map[string]bool{}
+		for _, s := range strategies {
+			visible[s.Name] = tenantVisible(c, s.Tenant)
+		}
+		kept := items[:0]
+		for _, it := range items {
+			if visible[it.Expectation.StrategyName] {
+				kept = append(kept, it)
+			}
+		}
+		items = kept
+	}
+	drifting := 0
+	for _, it := range items {
+		if it.Latest != nil && it.Latest.Status == models.StrategyDriftDrifting {
+			drifting++
+		}
+	}
+	Ok(c, items, map[string]any{"total": len(items), "drifting": drifting})
+}
+
+func (h *V2StrategyHandler) expectation(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	item, err := h.Repo.GetStrategyExpectation(c.Request.Context(), name)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if item == nil {
+		Error(c, http.StatusNotFound, "expectation not found", map[string]any{"name": name})
+		return
+	}
+	Ok(c, item, nil)
+}
+
+// putExpectation stores the strategy's backtest expectation; fields left
+// out keep their value.
+func (h *V2StrategyHandler) putExpectation(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" {
+		Error(c, http.StatusBadRequest, "name required", nil)
+		return
+	}
+	var req putStrategyExpectationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if req.WinRate != nil && (*req.WinRate < 0 || *req.WinRate > 1) {
+		Error(c, http.StatusBadRequest, "win_rate must be between 0 and 1", nil)
+		return
+	}
+	if req.TradesPerDay != nil && *req.TradesPerDay < 0 {
+		Error(c, http.StatusBadRequest, "trades_per_day must not be negative", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	strat, err := h.Repo.GetStrategyByName(ctx, name)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if strat == nil {
+		Error(c, http.StatusNotFound, "strategy not found", nil)
+		return
+	}
+	item, err := h.Repo.GetStrategyExpectation(ctx, name)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if item == nil {
+		item = &models.StrategyExpectation{StrategyName: name}
+	}
+	if req.WinRate != nil {
+		item.WinRate = *req.WinRate
+	}
+	if req.EdgePct != nil {
+		item.EdgePct = *req.EdgePct
+	}
+	if req.TradesPerDay != nil {
+		item.TradesPerDay = *req.TradesPerDay
+	}
+	if req.Source != nil {
+		item.Source = strings.TrimSpace(*req.Source)
+	}
+	if req.BacktestFrom != nil {
+		item.BacktestFrom = req.BacktestFrom
+	}
+	if req.BacktestTo != nil {
+		item.BacktestTo = req.BacktestTo
+	}
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).SaveStrategyExpectation(ctx, item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_strategy_expectation_set", "info", map[string]any{
+			"strategy":       name,
+			"win_rate":       item.WinRate,
+			"edge_pct":       item.EdgePct,
+			"trades_per_day": item.TradesPerDay,
+			"source":         item.Source,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, item, nil)
+}
+
+func (h *V2StrategyHandler) deleteExpectation(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	if err := h.Repo.DeleteStrategyExpectation(c.Request.Context(), name); err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, map[string]any{"deleted": name}, nil)
+}
+
+// drift returns the strategy's latest drift report and the ones before it.
+// refresh=true evaluates anew first, as does a strategy without a report
+// yet.
+func (h *V2StrategyHandler) drift(c *gin.Context) {
+	if h.Repo == nil || h.Drift == nil {
+		Error(c, http.StatusInternalServerError, "strategy drift unavailable", nil)
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	refresh := false
+	if raw := strings.TrimSpace(c.Query("refresh")); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			Error(c, http.StatusBadRequest, "invalid refresh", nil)
+			return
+		}
+		refresh = v
+	}
+	ctx := c.Request.Context()
+	var (
+		latest *models.StrategyDriftReport
+		err    error
+	)
+	if !refresh {
+		if latest, err = h.Repo.GetLatestStrategyDriftReport(ctx, name); err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+	}
+	if latest == nil {
+		latest, err = h.Drift.Evaluate(ctx, name, time.Now().UTC())
+		if errors.Is(err, service.ErrNoStrategyExpectation) {
+			Error(c, http.StatusNotFound, err.Error(), map[string]any{"name": name})
+			return
+		}
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+	}
+	history, err := h.Repo.ListStrategyDriftReports(ctx, name, strategyDriftHistoryLimit)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, gin.H{"report": latest, "history": history}, nil)
+}
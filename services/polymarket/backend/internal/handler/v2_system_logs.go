@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/logger"
+)
+
+// V2SystemLogsHandler serves the in-memory service log ring so operators
+// can read logs without shell access. Logs span all desks, so tenant-scoped
+// tokens are refused.
+type V2SystemLogsHandler struct {
+	Ring *logger.Ring
+}
+
+func (h *V2SystemLogsHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/system/logs")
+	group.GET("", validateQuery[logsQuery](), h.recent)
+	group.GET("/stream", validateQuery[logsQuery](), h.stream)
+}
+
+// logsQuery filters both endpoints; limit applies to recent and tail to
+// stream.
+type logsQuery struct {
+	Level    string   `form:"level" default:"info" binding:"oneof=debug info warn error dpanic panic fatal"`
+	Modules  []string `form:"module"`
+	AfterSeq uint64   `form:"after_seq"`
+	Limit    int      `form:"limit" default:"200" binding:"min=1,max=2000"`
+	Tail     int      `form:"tail" default:"100" binding:"min=0,max=2000"`
+}
+
+func (q *logsQuery) filter() logger.Filter {
+	f := logger.Filter{Modules: q.Modules, AfterSeq: q.AfterSeq}
+	_ = f.MinLevel.Set(q.Level)
+	return f
+}
+
+func (h *V2SystemLogsHandler) ready(c *gin.Context) bool {
+	if h.Ring == nil {
+		Error(c, http.StatusServiceUnavailable, "log ring disabled", nil)
+		return false
+	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "service logs require an unscoped token", nil)
+		return false
+	}
+	return true
+}
+
+func (h *V2SystemLogsHandler) recent(c *gin.Context) {
+	if !h.ready(c) {
+		return
+	}
+	q := queryOf[logsQuery](c)
+	Ok(c, h.Ring.Recent(q.filter(), q.Limit), nil)
+}
+
+// stream is a server-sent event tail: it replays the last `tail` matching
+// entries, then pushes new ones as "log" events until the client leaves.
+// Clients resume with after_seq set to the last seq they saw.
+func (h *V2SystemLogsHandler) stream(c *gin.Context) {
+	if !h.ready(c) {
+		return
+	}
+	q := queryOf[logsQuery](c)
+	f, tail := q.filter(), q.Tail
+
+	// Subscribe before reading the backlog so nothing falls in between.
+	ch, cancel := h.Ring.Subscribe(512)
+	defer cancel()
+	backlog := []logger.Entry{}
+	if tail > 0 {
+		backlog = h.Ring.Recent(f, tail)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	last := f.AfterSeq
+	for _, e := range backlog {
+		c.SSEvent("log", e)
+		last = e.Seq
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case e := <-ch:
+			f.AfterSeq = last
+			if !f.Match(e) {
+				continue
+			}
+			c.SSEvent("log", e)
+			c.Writer.Flush()
+			last = e.Seq
+		}
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/datatypes"
 
+	"polymarket/internal/governor"
 	"polymarket/internal/models"
+	"polymarket/internal/paas"
 	"polymarket/internal/repository"
 	"polymarket/internal/service"
 )
@@ -17,34 +19,48 @@ import (
 type V2SystemSettingsHandler struct {
 	Repo     repository.Repository
 	Settings *service.SystemSettingsService
+	// Governor, when set, queues re-encryption sweeps behind other heavy jobs.
+	Governor *governor.Governor
 }
 
 func (h *V2SystemSettingsHandler) Register(r *gin.Engine) {
 	g := r.Group("/api/v2/system-settings")
-	g.GET("", h.list)
-	g.POST("/re-encrypt-sensitive", h.reencryptSensitive)
-	g.GET("/switches", h.listSwitches)
+	g.GET("", validateQuery[listSettingsQuery](), h.list)
+	g.POST("/re-encrypt-sensitive", heavyJob(h.Governor, governor.ClassAdmin), validateQuery[reencryptQuery](), h.reencryptSensitive)
+	g.GET("/switches", validateQuery[listSwitchesQuery](), h.listSwitches)
 	g.GET("/switches/:name", h.getSwitch)
 	g.PUT("/switches/:name", h.putSwitch)
 	g.GET("/:key", h.get)
 	g.PUT("/:key", h.put)
 }
 
+type listSettingsQuery struct {
+	Limit  int     `form:"limit" default:"200" binding:"min=1,max=1000"`
+	Offset int     `form:"offset" default:"0" binding:"min=0"`
+	Prefix *string `form:"prefix"`
+}
+
+type reencryptQuery struct {
+	Limit  int     `form:"limit" default:"5000" binding:"min=1,max=20000"`
+	Prefix *string `form:"prefix"`
+}
+
+type listSwitchesQuery struct {
+	Limit  int `form:"limit" default:"200" binding:"min=1,max=1000"`
+	Offset int `form:"offset" default:"0" binding:"min=0"`
+}
+
 func (h *V2SystemSettingsHandler) list(c *gin.Context) {
 	if h.Repo == nil {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	limit := intQuery(c, "limit", 200)
-	offset := intQuery(c, "offset", 0)
-	var prefix *string
-	if v := strings.TrimSpace(c.Query("prefix")); v != "" {
-		prefix = &v
-	}
+	q := queryOf[listSettingsQuery](c)
 	params := repository.ListSystemSettingsParams{
-		Limit:   limit,
-		Offset:  offset,
-		Prefix:  prefix,
+		Limit:   q.Limit,
+		Offset:  q.Offset,
+		Prefix:  q.Prefix,
+		Tenant:  tenantScope(c),
 		OrderBy: "key",
 		Asc:     boolPtr(true),
 	}
@@ -62,7 +78,7 @@ func (h *V2SystemSettingsHandler) list(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	Ok(c, safe, paginationMeta(limit, offset, total))
+	Ok(c, safe, paginationMeta(q.Limit, q.Offset, total))
 }
 
 func (h *V2SystemSettingsHandler) get(c *gin.Context) {
@@ -75,12 +91,12 @@ func (h *V2SystemSettingsHandler) get(c *gin.Context) {
 		Error(c, http.StatusBadRequest, "invalid key", nil)
 		return
 	}
-	item, err := h.Repo.GetSystemSettingByKey(c.Request.Context(), key)
+	item, err := h.lookup(c, key)
 	if err != nil {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	if item == nil {
+	if item == nil || !settingVisible(c, *item) {
 		Error(c, http.StatusNotFound, "setting not found", nil)
 		return
 	}
@@ -118,9 +134,13 @@ func (h *V2SystemSettingsHandler) put(c *gin.Context) {
 		Error(c, http.StatusBadRequest, "invalid value", nil)
 		return
 	}
+	// A scoped request writes its desk's row, which shadows the shared one
+	// for that desk only.
+	tenant := paas.TenantOrDefault(c.Request.Context())
 	raw = service.ProtectSettingValue(key, raw)
 	item := &models.SystemSetting{
 		Key:         key,
+		Tenant:      tenant,
 		Value:       datatypes.JSON(raw),
 		Description: strings.TrimSpace(req.Description),
 		UpdatedAt:   time.Now().UTC(),
@@ -129,7 +149,10 @@ func (h *V2SystemSettingsHandler) put(c *gin.Context) {
 		Error(c, http.StatusBadGateway, err.Error(), nil)
 		return
 	}
-	next, _ := h.Repo.GetSystemSettingByKey(c.Request.Context(), key)
+	if tenant == paas.DefaultTenant {
+		h.Settings.Changed(key, item.Value)
+	}
+	next, _ := h.Repo.GetTenantSystemSetting(c.Request.Context(), tenant, key)
 	if next == nil {
 		Ok(c, next, nil)
 		return
@@ -143,18 +166,15 @@ func (h *V2SystemSettingsHandler) reencryptSensitive(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
-	limit := intQuery(c, "limit", 5000)
-	if limit <= 0 || limit > 20000 {
-		limit = 5000
-	}
-	var prefix *string
-	if v := strings.TrimSpace(c.Query("prefix")); v != "" {
-		prefix = &v
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "re-encryption requires an unscoped token", nil)
+		return
 	}
+	q := queryOf[reencryptQuery](c)
 	items, err := h.Repo.ListSystemSettings(c.Request.Context(), repository.ListSystemSettingsParams{
-		Limit:   limit,
+		Limit:   q.Limit,
 		Offset:  0,
-		Prefix:  prefix,
+		Prefix:  q.Prefix,
 		OrderBy: "key",
 		Asc:     boolPtr(true),
 	})
@@ -165,12 +185,17 @@ func (h *V2SystemSettingsHandler) reencryptSensitive(c *gin.Context) {
 	changed := 0
 	now := time.Now().UTC()
 	for _, it := range items {
+		if err := governor.Yield(c.Request.Context()); err != nil {
+			Error(c, http.StatusServiceUnavailable, err.Error(), nil)
+			return
+		}
 		next, ok := service.ReencryptSensitiveValue(it.Key, it.Value)
 		if !ok {
 			continue
 		}
 		row := &models.SystemSetting{
 			Key:         it.Key,
+			Tenant:      it.Tenant,
 			Value:       datatypes.JSON(next),
 			Description: it.Description,
 			UpdatedAt:   now,
@@ -179,6 +204,9 @@ func (h *V2SystemSettingsHandler) reencryptSensitive(c *gin.Context) {
 			Error(c, http.StatusBadGateway, err.Error(), nil)
 			return
 		}
+		if row.Tenant == "" || row.Tenant == paas.DefaultTenant {
+			h.Settings.Changed(it.Key, row.Value)
+		}
 		changed++
 	}
 	Ok(c, reencryptSensitiveResult{Scanned: len(items), Changed: changed}, nil)
@@ -189,10 +217,11 @@ func (h *V2SystemSettingsHandler) listSwitches(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
 		return
 	}
+	q := queryOf[listSwitchesQuery](c)
 	prefix := "feature."
 	params := repository.ListSystemSettingsParams{
-		Limit:   intQuery(c, "limit", 200),
-		Offset:  intQuery(c, "offset", 0),
+		Limit:   q.Limit,
+		Offset:  q.Offset,
 		Prefix:  &prefix,
 		OrderBy: "key",
 		Asc:     boolPtr(true),
@@ -245,6 +274,10 @@ func (h *V2SystemSettingsHandler) putSwitch(c *gin.Context) {
 		Error(c, http.StatusInternalServerError, "settings service unavailable", nil)
 		return
 	}
+	if tenantScope(c) != nil {
+		Error(c, http.StatusForbidden, "feature switches require an unscoped token", nil)
+		return
+	}
 	name := strings.TrimSpace(c.Param("name"))
 	if name == "" {
 		Error(c, http.StatusBadRequest, "invalid switch name", nil)
@@ -295,3 +328,22 @@ func isSensitiveSystemSettingKey(key string) bool {
 	}
 	return false
 }
+
+// lookup returns the desk's own row of key for scoped requests, falling back
+// to the shared row.
+func (h *V2SystemSettingsHandler) lookup(c *gin.Context, key string) (*models.SystemSetting, error) {
+	ctx := c.Request.Context()
+	if tenant := paas.TenantFromGin(c); tenant != "" && tenant != paas.DefaultTenant {
+		item, err := h.Repo.GetTenantSystemSetting(ctx, tenant, key)
+		if err != nil || item != nil {
+			return item, err
+		}
+	}
+	return h.Repo.GetSystemSettingByKey(ctx, key)
+}
+
+// settingVisible lets scoped requests read their own settings and the shared
+// ones owned by the default tenant.
+func settingVisible(c *gin.Context, item models.SystemSetting) bool {
+	return item.Tenant == paas.DefaultTenant || tenantVisible(c, item.Tenant)
+}
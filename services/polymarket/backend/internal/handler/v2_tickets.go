@@ -0,0 +1,505 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/risk"
+	"polymarket/internal/service"
+)
+
+// V2TicketHandler runs the discretionary trade-ticket workflow: a ticket
+// opens a draft plan on an opportunity, takes size and note edits, is
+// reviewed by running preflight and is then submitted to the CLOB executor.
+// Each step reuses the plan, preflight and submit paths of the
+// opportunity, execution and order APIs.
+type V2TicketHandler struct {
+	Repo      repository.Repository
+	Risk      *risk.Manager
+	Executor  *service.CLOBExecutor
+	Campaigns *service.CampaignService
+	// Costs records the execution cost forecast of new plans.
+	Costs *service.CostForecaster
+	// Outbox records the side effects of plan and ticket writes with them.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2TicketHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/tickets", tenantGuard("id", "ticket not found", h.ticketTenant))
+	group.GET("", validateQuery[listTicketsQuery](), h.list)
+	group.POST("", h.create)
+	group.GET("/:id", h.get)
+	group.PUT("/:id", h.update)
+	group.POST("/:id/review", h.review)
+	group.POST("/:id/submit", h.submit)
+	group.POST("/:id/cancel", h.cancel)
+}
+
+type listTicketsQuery struct {
+	pageQuery
+	State         *string `form:"state" binding:"omitempty,oneof=draft reviewed submitted cancelled"`
+	OpportunityID *uint64 `form:"opportunity_id" binding:"omitempty,min=1"`
+}
+
+type createTicketRequest struct {
+	OpportunityID uint64   `json:"opportunity_id"`
+	SizeUSD       *float64 `json:"size_usd"`
+	Notes         string   `json:"notes"`
+}
+
+type updateTicketRequest struct {
+	SizeUSD *float64 `json:"size_usd"`
+	Notes   *string  `json:"notes"`
+}
+
+// ticketView is a ticket with its plan and opportunity. NextActions lists
+// the workflow steps allowed in the current state.
+type ticketView struct {
+	Ticket          models.TradeTicket    `json:"ticket"`
+	Plan            *models.ExecutionPlan `json:"plan,omitempty"`
+	Opportunity     *models.Opportunity   `json:"opportunity,omitempty"`
+	SizingWarnings  []string              `json:"sizing_warnings,omitempty"`
+	NextActions     []string              `json:"next_actions"`
+	PreflightPassed *bool                 `json:"preflight_passed,omitempty"`
+}
+
+func ticketActions(state string) []string {
+	switch state {
+	case models.TicketStateDraft:
+		return []string{"edit", "review", "cancel"}
+	case models.TicketStateReviewed:
+		return []string{"edit", "submit", "cancel"}
+	}
+	return []string{}
+}
+
+func ticketOpen(state string) bool {
+	return state == models.TicketStateDraft || state == models.TicketStateReviewed
+}
+
+func (h *V2TicketHandler) ticketTenant(c *gin.Context) (string, bool, error) {
+	if h.Repo == nil {
+		return "", false, nil
+	}
+	item, err := h.Repo.GetTradeTicketByID(c.Request.Context(), uint64QueryParam(c, "id"))
+	if err != nil || item == nil {
+		return "", false, err
+	}
+	return item.Tenant, true, nil
+}
+
+func (h *V2TicketHandler) list(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	q := queryOf[listTicketsQuery](c)
+	params := repository.ListTradeTicketsParams{Limit: q.Limit, Offset: q.Offset, Tenant: tenantScope(c), State: q.State, OpportunityID: q.OpportunityID}
+	items, err := h.Repo.ListTradeTickets(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountTradeTickets(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
+// create opens a ticket and its draft plan. Without size_usd the plan takes
+// the risk manager's suggested size.
+func (h *V2TicketHandler) create(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	var req createTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if req.OpportunityID == 0 {
+		Error(c, http.StatusBadRequest, "opportunity_id required", nil)
+		return
+	}
+	var size *decimal.Decimal
+	if req.SizeUSD != nil {
+		if *req.SizeUSD <= 0 {
+			Error(c, http.StatusBadRequest, "size_usd must be positive", nil)
+			return
+		}
+		v := decimal.NewFromFloat(*req.SizeUSD)
+		size = &v
+	}
+	ctx := c.Request.Context()
+	opp, err := h.Repo.GetOpportunityByID(ctx, req.OpportunityID)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if opp == nil || !tenantVisible(c, opp.Tenant) {
+		Error(c, http.StatusNotFound, "opportunity not found", nil)
+		return
+	}
+	if !opportunityExecutable(c, *opp) {
+		return
+	}
+	plan, warnings, err := service.CreateOpportunityPlan(ctx, h.Repo, h.Risk, h.Campaigns, h.Costs, h.Outbox, *opp, size)
+	if err != nil {
+		campaignError(c, err)
+		return
+	}
+	item := &models.TradeTicket{
+		Tenant:        plan.Tenant,
+		OpportunityID: opp.ID,
+		PlanID:        &plan.ID,
+		State:         models.TicketStateDraft,
+		SizeUSD:       plan.PlannedSizeUSD,
+		Notes:         strings.TrimSpace(req.Notes),
+	}
+	err = writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).InsertTradeTicket(ctx, item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_ticket_created", "info", map[string]any{
+			"ticket_id":      item.ID,
+			"opportunity_id": opp.ID,
+			"plan_id":        plan.ID,
+			"size_usd":       item.SizeUSD.String(),
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	view := ticketView{Ticket: *item, Plan: plan, Opportunity: opp, SizingWarnings: warnings, NextActions: ticketActions(item.State)}
+	Ok(c, view, nil)
+}
+
+func (h *V2TicketHandler) get(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	view, err := h.view(c, *item)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, view, nil)
+}
+
+// update edits notes and sizing of an open ticket. A new size resizes the
+// plan and sends the ticket back to draft for another review.
+func (h *V2TicketHandler) update(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var req updateTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if !ticketOpen(item.State) {
+		Error(c, http.StatusConflict, "ticket is not editable", map[string]any{"state": item.State})
+		return
+	}
+	if req.Notes != nil {
+		item.Notes = strings.TrimSpace(*req.Notes)
+	}
+	if req.SizeUSD != nil {
+		if *req.SizeUSD <= 0 {
+			Error(c, http.StatusBadRequest, "size_usd must be positive", nil)
+			return
+		}
+		size := decimal.NewFromFloat(*req.SizeUSD)
+		if !size.Equal(item.SizeUSD) {
+			if err := h.resize(c, item, size); err != nil {
+				campaignError(c, err)
+				return
+			}
+		}
+	}
+	if err := h.save(c, item, "polymarket_ticket_updated", map[string]any{
+		"ticket_id": item.ID,
+		"size_usd":  item.SizeUSD.String(),
+		"state":     item.State,
+	}); err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	view, err := h.view(c, *item)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, view, nil)
+}
+
+// resize moves the ticket's plan to size. Campaigns only admit the
+// increase since the plan's current size already counts against the budget.
+func (h *V2TicketHandler) resize(c *gin.Context, item *models.TradeTicket, size decimal.Decimal) error {
+	ctx := c.Request.Context()
+	plan, err := h.ticketPlan(ctx, *item)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		return errors.New("ticket plan not found")
+	}
+	if plan.CampaignID != nil && size.GreaterThan(plan.PlannedSizeUSD) {
+		if _, err := h.Campaigns.Admit(ctx, *plan.CampaignID, plan.Tenant, size.Sub(plan.PlannedSizeUSD), time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+	maxLoss := service.ScaleMaxLoss(plan.MaxLossUSD, plan.PlannedSizeUSD, size)
+	legs := scalePlanLegs(plan.Legs, plan.PlannedSizeUSD, size)
+	if err := h.Repo.UpdateExecutionPlanSizing(ctx, plan.ID, size, maxLoss, legs); err != nil {
+		return err
+	}
+	item.SizeUSD = size
+	item.State = models.TicketStateDraft
+	item.PreflightResult = nil
+	item.ReviewedAt = nil
+	return nil
+}
+
+// review runs preflight on the ticket's plan. A pass marks the ticket
+// reviewed; a failure keeps it in draft with the failed checks attached.
+func (h *V2TicketHandler) review(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if !ticketOpen(item.State) {
+		Error(c, http.StatusConflict, "ticket is not open", map[string]any{"state": item.State})
+		return
+	}
+	if h.Risk == nil {
+		Error(c, http.StatusServiceUnavailable, "risk manager unavailable", nil)
+		return
+	}
+	if item.PlanID == nil {
+		Error(c, http.StatusConflict, "ticket has no plan", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	result, err := h.Risk.PreflightPlan(ctx, *item.PlanID)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if result == nil {
+		Error(c, http.StatusNotFound, "execution plan not found", nil)
+		return
+	}
+	raw, _ := json.Marshal(result)
+	item.PreflightResult = datatypes.JSON(raw)
+	if result.Passed {
+		now := time.Now().UTC()
+		item.State = models.TicketStateReviewed
+		item.ReviewedAt = &now
+	} else {
+		item.State = models.TicketStateDraft
+		item.ReviewedAt = nil
+	}
+	if err := h.save(c, item, "polymarket_ticket_reviewed", map[string]any{
+		"ticket_id": item.ID,
+		"plan_id":   *item.PlanID,
+		"passed":    result.Passed,
+	}); err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	view, err := h.view(c, *item)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	view.PreflightPassed = &result.Passed
+	Ok(c, view, nil)
+}
+
+// submit sends a reviewed ticket's plan to the CLOB executor, which runs
+// preflight once more before placing orders.
+func (h *V2TicketHandler) submit(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if item.State != models.TicketStateReviewed {
+		Error(c, http.StatusConflict, "ticket must be reviewed before submit", map[string]any{"state": item.State})
+		return
+	}
+	if h.Executor == nil {
+		Error(c, http.StatusServiceUnavailable, "executor unavailable", nil)
+		return
+	}
+	if item.PlanID == nil {
+		Error(c, http.StatusConflict, "ticket has no plan", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	out, err := h.Executor.SubmitPlan(ctx, *item.PlanID)
+	var limitErr *risk.RateLimitError
+	if errors.As(err, &limitErr) {
+		Error(c, http.StatusTooManyRequests, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	if out == nil {
+		Error(c, http.StatusNotFound, "plan not found", nil)
+		return
+	}
+	raw, _ := json.Marshal(out)
+	now := time.Now().UTC()
+	item.State = models.TicketStateSubmitted
+	item.SubmitResult = datatypes.JSON(raw)
+	item.SubmittedAt = &now
+	if err := h.save(c, item, "polymarket_ticket_submitted", map[string]any{
+		"ticket_id": item.ID,
+		"plan_id":   *item.PlanID,
+		"size_usd":  item.SizeUSD.String(),
+	}); err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	view, err := h.view(c, *item)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, view, nil)
+}
+
+// cancel abandons an open ticket and cancels its plan like
+// POST /api/v2/executions/:id/cancel does.
+func (h *V2TicketHandler) cancel(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if !ticketOpen(item.State) {
+		Error(c, http.StatusConflict, "ticket is not open", map[string]any{"state": item.State})
+		return
+	}
+	ctx := c.Request.Context()
+	item.State = models.TicketStateCancelled
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if item.PlanID != nil {
+			if err := h.Repo.UpdateExecutionPlanStatusTx(ctx, tx, *item.PlanID, "cancelled"); err != nil {
+				return nil, err
+			}
+		}
+		if err := h.Repo.UpdateOpportunityStatusTx(ctx, tx, item.OpportunityID, "cancelled"); err != nil {
+			return nil, err
+		}
+		if err := h.Repo.WithTx(tx).UpdateTradeTicket(ctx, item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_ticket_cancelled", "info", map[string]any{
+			"ticket_id":      item.ID,
+			"opportunity_id": item.OpportunityID,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, ticketView{Ticket: *item, NextActions: ticketActions(item.State)}, nil)
+}
+
+// save updates item and records action with it.
+func (h *V2TicketHandler) save(c *gin.Context, item *models.TradeTicket, action string, details map[string]any) error {
+	return writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).UpdateTradeTicket(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, action, "info", details), nil
+	})
+}
+
+func (h *V2TicketHandler) load(c *gin.Context) (*models.TradeTicket, bool) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return nil, false
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return nil, false
+	}
+	item, err := h.Repo.GetTradeTicketByID(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return nil, false
+	}
+	if item == nil || !tenantVisible(c, item.Tenant) {
+		Error(c, http.StatusNotFound, "ticket not found", nil)
+		return nil, false
+	}
+	return item, true
+}
+
+func (h *V2TicketHandler) ticketPlan(ctx context.Context, item models.TradeTicket) (*models.ExecutionPlan, error) {
+	if item.PlanID == nil {
+		return nil, nil
+	}
+	return h.Repo.GetExecutionPlanByID(ctx, *item.PlanID)
+}
+
+func (h *V2TicketHandler) view(c *gin.Context, item models.TradeTicket) (ticketView, error) {
+	ctx := c.Request.Context()
+	view := ticketView{Ticket: item, NextActions: ticketActions(item.State)}
+	plan, err := h.ticketPlan(ctx, item)
+	if err != nil {
+		return view, err
+	}
+	view.Plan = plan
+	opp, err := h.Repo.GetOpportunityByID(ctx, item.OpportunityID)
+	if err != nil {
+		return view, err
+	}
+	view.Opportunity = opp
+	return view, nil
+}
+
+// scalePlanLegs rescales each leg's size_usd from oldSize to newSize; legs
+// are split evenly when the old size is zero or a leg has no size.
+func scalePlanLegs(legsJSON []byte, oldSize, newSize decimal.Decimal) []byte {
+	var legs []map[string]any
+	if err := json.Unmarshal(legsJSON, &legs); err != nil || len(legs) == 0 {
+		return legsJSON
+	}
+	even := newSize.Div(decimal.NewFromInt(int64(len(legs)))).InexactFloat64()
+	for i := range legs {
+		cur, ok := legs[i]["size_usd"].(float64)
+		if !ok || oldSize.LessThanOrEqual(decimal.Zero) {
+			legs[i]["size_usd"] = even
+			continue
+		}
+		legs[i]["size_usd"] = decimal.NewFromFloat(cur).Mul(newSize).Div(oldSize).InexactFloat64()
+	}
+	raw, err := json.Marshal(legs)
+	if err != nil {
+		return legsJSON
+	}
+	return raw
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestScalePlanLegs(t *testing.T) {
+	legs := []byte(`[{"token_id":"a","size_usd":60,"priority":1},{"token_id":"b","size_usd":40,"priority":2}]`)
+	var out []map[string]any
+	if err := json.Unmarshal(scalePlanLegs(legs, decimal.NewFromInt(100), decimal.NewFromInt(50)), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out[0]["size_usd"] != 30.0 || out[1]["size_usd"] != 20.0 || out[0]["token_id"] != "a" {
+		t.Fatalf("legs=%v", out)
+	}
+
+	// Without a previous size the legs are split evenly.
+	if err := json.Unmarshal(scalePlanLegs([]byte(`[{"token_id":"a"},{"token_id":"b"}]`), decimal.Zero, decimal.NewFromInt(50)), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out[0]["size_usd"] != 25.0 || out[1]["size_usd"] != 25.0 {
+		t.Fatalf("even legs=%v", out)
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// V2TradeHandler serves the per-token trade tape recorded from the CLOB
+// stream and volume profiles built from it.
+type V2TradeHandler struct {
+	Repo repository.Repository
+}
+
+func (h *V2TradeHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/markets")
+	group.GET("/:id/trades", validateQuery[listTradesQuery](), h.listTrades)
+	group.GET("/:id/volume-profile", validateQuery[volumeProfileQuery](), h.volumeProfile)
+}
+
+type listTradesQuery struct {
+	timeRangeQuery
+	Limit   int     `form:"limit" default:"100" binding:"min=1,max=500"`
+	Offset  int     `form:"offset" default:"0" binding:"min=0"`
+	TokenID *string `form:"token_id"`
+	Side    *string `form:"side" binding:"omitempty,oneof=BUY SELL buy sell"`
+}
+
+type volumeProfileQuery struct {
+	TokenID *string `form:"token_id"`
+	Hours   int     `form:"hours" default:"24" binding:"min=1,max=720"`
+	Bucket  float64 `form:"bucket" default:"0.01" binding:"gt=0,lte=0.5"`
+}
+
+// marketTokens returns the market's tokens, narrowed to tokenID when given.
+// It writes the error response and returns false when the market or token is
+// unknown.
+func (h *V2TradeHandler) marketTokens(c *gin.Context, tokenID *string) ([]models.Token, bool) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return nil, false
+	}
+	marketID := strings.TrimSpace(c.Param("id"))
+	tokens, err := h.Repo.ListTokensByMarketIDs(c.Request.Context(), []string{marketID})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return nil, false
+	}
+	if len(tokens) == 0 {
+		Error(c, http.StatusNotFound, "market not found", nil)
+		return nil, false
+	}
+	if tokenID == nil || *tokenID == "" {
+		return tokens, true
+	}
+	for _, t := range tokens {
+		if t.ID == *tokenID {
+			return []models.Token{t}, true
+		}
+	}
+	Error(c, http.StatusBadRequest, "token not in market", nil)
+	return nil, false
+}
+
+func (h *V2TradeHandler) listTrades(c *gin.Context) {
+	q := queryOf[listTradesQuery](c)
+	tokens, ok := h.marketTokens(c, q.TokenID)
+	if !ok {
+		return
+	}
+	ids := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		ids = append(ids, t.ID)
+	}
+	params := repository.ListTradesParams{Limit: q.Limit, Offset: q.Offset, TokenIDs: ids, Side: q.Side, Since: q.Since, Until: q.Until}
+	items, err := h.Repo.ListTrades(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountTrades(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
+type tokenVolumeProfile struct {
+	Outcome string `json:"outcome"`
+	repository.VolumeProfile
+}
+
+// volumeProfile returns one profile per market token over the last hours.
+func (h *V2TradeHandler) volumeProfile(c *gin.Context) {
+	q := queryOf[volumeProfileQuery](c)
+	tokens, ok := h.marketTokens(c, q.TokenID)
+	if !ok {
+		return
+	}
+	since := time.Now().UTC().Add(-time.Duration(q.Hours) * time.Hour)
+	out := make([]tokenVolumeProfile, 0, len(tokens))
+	for _, t := range tokens {
+		profile, err := h.Repo.TradeVolumeProfile(c.Request.Context(), t.ID, since, q.Bucket)
+		if err != nil {
+			Error(c, http.StatusBadGateway, err.Error(), nil)
+			return
+		}
+		out = append(out, tokenVolumeProfile{Outcome: t.Outcome, VolumeProfile: profile})
+	}
+	Ok(c, out, map[string]any{"since": since, "hours": q.Hours, "bucket": q.Bucket})
+}
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/service"
+)
+
+// V2WebhookHandler manages trade webhooks: subscriptions of external risk
+// and compliance systems to fills, executed plans and closed positions, and
+// their delivery logs. Secrets are handled as for catalog webhooks: returned
+// once on create, masked everywhere else. Scoped tokens only see their own
+// desk's webhooks, which only receive that desk's events.
+type V2WebhookHandler struct {
+	Repo     repository.Repository
+	Webhooks *service.TradeWebhookService
+	// Outbox records the audit logs of webhook writes with them.
+	Outbox *service.OutboxDispatcher
+}
+
+func (h *V2WebhookHandler) Register(r *gin.Engine) {
+	group := r.Group("/api/v2/webhooks")
+	group.GET("", h.list)
+	group.POST("", h.create)
+	group.GET("/:id", h.get)
+	group.PUT("/:id", h.update)
+	group.DELETE("/:id", h.remove)
+	group.POST("/:id/test", h.test)
+	group.GET("/:id/deliveries", validateQuery[webhookDeliveriesQuery](), h.deliveries)
+}
+
+type tradeWebhookRequest struct {
+	Name       *string  `json:"name"`
+	URL        *string  `json:"url"`
+	Secret     *string  `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	Strategies []string `json:"strategies"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+type webhookDeliveriesQuery struct {
+	pageQuery
+	Status    *string `form:"status" binding:"omitempty,oneof=pending delivered failed"`
+	EventType *string `form:"event_type" binding:"omitempty,oneof=order.filled plan.executed position.closed"`
+}
+
+func (h *V2WebhookHandler) list(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	items, err := h.Repo.ListTradeWebhooks(c.Request.Context(), repository.ListTradeWebhooksParams{Tenant: tenantScope(c)})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	for i := range items {
+		items[i] = sanitizeTradeWebhook(items[i])
+	}
+	Ok(c, items, map[string]any{"enabled": h.Webhooks.Enabled(), "event_types": service.TradeEventTypes})
+}
+
+func (h *V2WebhookHandler) get(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	Ok(c, sanitizeTradeWebhook(*item), nil)
+}
+
+func (h *V2WebhookHandler) create(c *gin.Context) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return
+	}
+	var req tradeWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if req.URL == nil {
+		Error(c, http.StatusBadRequest, "url required", nil)
+		return
+	}
+	item := &models.TradeWebhook{Enabled: true}
+	if tenant := tenantScope(c); tenant != nil {
+		item.Tenant = *tenant
+	}
+	if msg := applyTradeWebhookRequest(item, req); msg != "" {
+		Error(c, http.StatusBadRequest, msg, nil)
+		return
+	}
+	secret := ""
+	if req.Secret != nil {
+		secret = strings.TrimSpace(*req.Secret)
+	}
+	if secret == "" {
+		secret = service.NewCatalogWebhookSecret()
+	}
+	item.Secret = string(service.ProtectSettingValue(service.TradeWebhookSecretKey, []byte(secret)))
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).InsertTradeWebhook(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_trade_webhook_created", "info", map[string]any{
+			"webhook_id": item.ID,
+			"url":        item.URL,
+			"tenant":     item.Tenant,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	h.Webhooks.Invalidate()
+	out := *item
+	out.Secret = secret
+	Ok(c, out, nil)
+}
+
+func (h *V2WebhookHandler) update(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var req tradeWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "invalid body", nil)
+		return
+	}
+	if msg := applyTradeWebhookRequest(item, req); msg != "" {
+		Error(c, http.StatusBadRequest, msg, nil)
+		return
+	}
+	if req.Secret != nil {
+		secret := strings.TrimSpace(*req.Secret)
+		if secret == "" {
+			Error(c, http.StatusBadRequest, "secret cannot be empty", nil)
+			return
+		}
+		item.Secret = string(service.ProtectSettingValue(service.TradeWebhookSecretKey, []byte(secret)))
+	}
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).UpdateTradeWebhook(c.Request.Context(), item); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_trade_webhook_updated", "info", map[string]any{
+			"webhook_id":     item.ID,
+			"url":            item.URL,
+			"enabled":        item.Enabled,
+			"secret_rotated": req.Secret != nil,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	h.Webhooks.Invalidate()
+	Ok(c, sanitizeTradeWebhook(*item), nil)
+}
+
+func (h *V2WebhookHandler) remove(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	err := writeWithOutbox(c, h.Repo, h.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := h.Repo.WithTx(tx).DeleteTradeWebhook(c.Request.Context(), item.ID); err != nil {
+			return nil, err
+		}
+		return outboxLog(c, "polymarket_trade_webhook_deleted", "info", map[string]any{
+			"webhook_id": item.ID,
+			"url":        item.URL,
+		}), nil
+	})
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	h.Webhooks.Invalidate()
+	Ok(c, map[string]any{"deleted": item.ID}, nil)
+}
+
+// test sends a signed ping; it is not logged as a delivery.
+func (h *V2WebhookHandler) test(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if h.Webhooks == nil {
+		Error(c, http.StatusServiceUnavailable, "trade webhooks unavailable", nil)
+		return
+	}
+	res := h.Webhooks.Ping(c.Request.Context(), *item)
+	if res.Error != "" {
+		Error(c, http.StatusBadGateway, res.Error, map[string]any{"delivery": res})
+		return
+	}
+	Ok(c, res, nil)
+}
+
+// deliveries lists the webhook's delivery log, newest first.
+func (h *V2WebhookHandler) deliveries(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	q := queryOf[webhookDeliveriesQuery](c)
+	params := repository.ListTradeWebhookDeliveriesParams{Limit: q.Limit, Offset: q.Offset, WebhookID: item.ID, Status: q.Status, EventType: q.EventType}
+	items, err := h.Repo.ListTradeWebhookDeliveries(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	total, err := h.Repo.CountTradeWebhookDeliveries(c.Request.Context(), params)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return
+	}
+	Ok(c, items, paginationMeta(q.Limit, q.Offset, total))
+}
+
+// load fetches the :id webhook, answering 404 for missing rows and rows of
+// another desk.
+func (h *V2WebhookHandler) load(c *gin.Context) (*models.TradeWebhook, bool) {
+	if h.Repo == nil {
+		Error(c, http.StatusInternalServerError, "repo unavailable", nil)
+		return nil, false
+	}
+	id := uint64QueryParam(c, "id")
+	if id == 0 {
+		Error(c, http.StatusBadRequest, "invalid id", nil)
+		return nil, false
+	}
+	item, err := h.Repo.GetTradeWebhookByID(c.Request.Context(), id)
+	if err != nil {
+		Error(c, http.StatusBadGateway, err.Error(), nil)
+		return nil, false
+	}
+	if item == nil || !tenantVisible(c, item.Tenant) {
+		Error(c, http.StatusNotFound, "webhook not found", nil)
+		return nil, false
+	}
+	return item, true
+}
+
+// applyTradeWebhookRequest copies the set fields of req onto item and
+// returns a validation message, or "" when the result is valid.
+func applyTradeWebhookRequest(item *models.TradeWebhook, req tradeWebhookRequest) string {
+	if req.Name != nil {
+		item.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.URL != nil {
+		raw := strings.TrimSpace(*req.URL)
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "url must be an absolute http(s) URL"
+		}
+		item.URL = raw
+	}
+	if req.EventTypes != nil {
+		types := make([]string, 0, len(req.EventTypes))
+		for _, t := range req.EventTypes {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if !slices.Contains(service.TradeEventTypes, t) {
+				return "event_types must be " + strings.Join(service.TradeEventTypes, ", ")
+			}
+			types = append(types, t)
+		}
+		item.EventTypes = stringListJSON(types)
+	}
+	if req.Strategies != nil {
+		item.Strategies = stringListJSON(req.Strategies)
+	}
+	if req.Enabled != nil {
+		item.Enabled = *req.Enabled
+	}
+	if item.Name == "" {
+		item.Name = item.URL
+	}
+	return ""
+}
+
+func sanitizeTradeWebhook(item models.TradeWebhook) models.TradeWebhook {
+	item.Secret = "***"
+	return item
+}
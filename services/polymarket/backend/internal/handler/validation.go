@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+const boundQueryKey = "handler.query"
+
+// fieldError is one rejected query parameter, reported under meta.fields.
+type fieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// queryChecker is implemented by query structs with checks that span fields.
+type queryChecker interface {
+	check() []fieldError
+}
+
+// validateQuery binds the query string into a T and validates it before the
+// handler runs. Fields are read from `form` tags and fall back to `default`
+// tags; strings are trimmed and times are RFC3339, normalized to UTC. Slices
+// accept repeated and comma-separated values. `binding` tags carry the
+// validator rules (limits, oneof enums). A bad request gets a 400 listing
+// every rejected field. Handlers read the result with queryOf.
+func validateQuery[T any]() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := new(T)
+		if fields := bindQuery(c.Request.URL.Query(), req); len(fields) > 0 {
+			Error(c, http.StatusBadRequest, "invalid query", map[string]any{"fields": fields})
+			c.Abort()
+			return
+		}
+		c.Set(boundQueryKey, req)
+		c.Next()
+	}
+}
+
+// queryOf returns the query bound by validateQuery[T]. It is the zero T when
+// the route was registered without the middleware.
+func queryOf[T any](c *gin.Context) *T {
+	if v, ok := c.Get(boundQueryKey); ok {
+		if req, ok := v.(*T); ok {
+			return req
+		}
+	}
+	return new(T)
+}
+
+func bindQuery(values url.Values, req any) []fieldError {
+	v := reflect.ValueOf(req).Elem()
+	names := map[string]string{}
+	fields := decodeQuery(values, v, names)
+	if len(fields) > 0 {
+		return fields
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		var verrs validator.ValidationErrors
+		if !errors.As(err, &verrs) {
+			return []fieldError{{Rule: "invalid", Message: err.Error()}}
+		}
+		for _, fe := range verrs {
+			fields = append(fields, describeFieldError(fe, names))
+		}
+		return fields
+	}
+	if chk, ok := req.(queryChecker); ok {
+		return chk.check()
+	}
+	return nil
+}
+
+// decodeQuery fills v's tagged fields, recursing into embedded structs, and
+// records the query name of every Go field in names.
+func decodeQuery(values url.Values, v reflect.Value, names map[string]string) []fieldError {
+	var fields []fieldError
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, decodeQuery(values, v.Field(i), names)...)
+			continue
+		}
+		key := sf.Tag.Get("form")
+		if key == "" || key == "-" {
+			continue
+		}
+		names[sf.Name] = key
+		raw := cleanStrings(values[key])
+		if len(raw) == 0 {
+			if def, ok := sf.Tag.Lookup("default"); ok {
+				raw = []string{def}
+			} else {
+				continue
+			}
+		}
+		if fe := setQueryField(v.Field(i), raw); fe != nil {
+			fe.Field = key
+			fields = append(fields, *fe)
+		}
+	}
+	return fields
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	decimalType  = reflect.TypeOf(decimal.Decimal{})
+)
+
+func setQueryField(f reflect.Value, raw []string) *fieldError {
+	if f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.String {
+		var items []string
+		for _, r := range raw {
+			items = append(items, strings.Split(r, ",")...)
+		}
+		f.Set(reflect.ValueOf(cleanStrings(items)).Convert(f.Type()))
+		return nil
+	}
+	if f.Kind() == reflect.Pointer {
+		elem := reflect.New(f.Type().Elem())
+		if fe := setQueryField(elem.Elem(), raw); fe != nil {
+			return fe
+		}
+		f.Set(elem)
+		return nil
+	}
+	s := raw[len(raw)-1]
+	switch f.Type() {
+	case timeType:
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return &fieldError{Rule: "rfc3339", Message: "must be an RFC3339 timestamp"}
+		}
+		f.Set(reflect.ValueOf(ts.UTC()))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return &fieldError{Rule: "duration", Message: "must be a duration such as 90s or 2h"}
+		}
+		f.SetInt(int64(d))
+		return nil
+	case decimalType:
+		d, err := decimal.NewFromString(s)
+		if err != nil {
+			return &fieldError{Rule: "decimal", Message: "must be a decimal number"}
+		}
+		f.Set(reflect.ValueOf(d))
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return &fieldError{Rule: "bool", Message: "must be true or false"}
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, f.Type().Bits())
+		if err != nil {
+			return &fieldError{Rule: "int", Message: "must be an integer"}
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, f.Type().Bits())
+		if err != nil {
+			return &fieldError{Rule: "uint", Message: "must be a non-negative integer"}
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, f.Type().Bits())
+		if err != nil {
+			return &fieldError{Rule: "number", Message: "must be a number"}
+		}
+		f.SetFloat(n)
+	default:
+		return &fieldError{Rule: "type", Message: "unsupported query field type " + f.Type().String()}
+	}
+	return nil
+}
+
+func describeFieldError(fe validator.FieldError, names map[string]string) fieldError {
+	out := fieldError{Field: names[fe.StructField()], Rule: fe.Tag(), Param: fe.Param()}
+	if out.Field == "" {
+		out.Field = fe.Field()
+	}
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice:
+		unit = " items"
+	}
+	switch fe.Tag() {
+	case "required":
+		out.Message = "is required"
+	case "min", "gte":
+		out.Message = fmt.Sprintf("must be at least %s%s", fe.Param(), unit)
+	case "max", "lte":
+		out.Message = fmt.Sprintf("must be at most %s%s", fe.Param(), unit)
+	case "gt":
+		out.Message = fmt.Sprintf("must be greater than %s", fe.Param())
+	case "lt":
+		out.Message = fmt.Sprintf("must be less than %s", fe.Param())
+	case "oneof":
+		out.Message = "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	default:
+		out.Message = "failed " + fe.Tag() + " validation"
+	}
+	return out
+}
+
+// pageQuery is limit/offset paging for list endpoints that default to 50.
+// Endpoints with other defaults declare their own fields.
+type pageQuery struct {
+	Limit  int `form:"limit" default:"50" binding:"min=1,max=1000"`
+	Offset int `form:"offset" default:"0" binding:"min=0"`
+}
+
+// timeRangeQuery is an optional RFC3339 since/until window.
+type timeRangeQuery struct {
+	Since *time.Time `form:"since"`
+	Until *time.Time `form:"until"`
+}
+
+func (q timeRangeQuery) check() []fieldError {
+	if q.Since != nil && q.Until != nil && q.Until.Before(*q.Since) {
+		return []fieldError{{Field: "until", Rule: "gtefield", Param: "since", Message: "must not be before since"}}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testListQuery struct {
+	pageQuery
+	timeRangeQuery
+	Order  string   `form:"order" default:"desc" binding:"oneof=asc desc"`
+	Tags   []string `form:"tags"`
+	Source *string  `form:"source"`
+}
+
+func serveQuery(t *testing.T, rawQuery string) (*httptest.ResponseRecorder, *testListQuery) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var got *testListQuery
+	r.GET("/", validateQuery[testListQuery](), func(c *gin.Context) {
+		got = queryOf[testListQuery](c)
+		Ok(c, nil, nil)
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?"+rawQuery, nil))
+	return w, got
+}
+
+func TestValidateQuery_BindsDefaultsAndValues(t *testing.T) {
+	w, q := serveQuery(t, "since=2026-01-02T03:04:05%2B08:00&tags=a,+b&tags=c&source=+gamma+")
+	if w.Code != http.StatusOK || q == nil {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if q.Limit != 50 || q.Offset != 0 || q.Order != "desc" {
+		t.Fatalf("defaults not applied: %+v", q)
+	}
+	if q.Since == nil || !q.Since.Equal(time.Date(2026, 1, 1, 19, 4, 5, 0, time.UTC)) || q.Since.Location() != time.UTC {
+		t.Fatalf("since=%v", q.Since)
+	}
+	if len(q.Tags) != 3 || q.Tags[1] != "b" || q.Source == nil || *q.Source != "gamma" || q.Until != nil {
+		t.Fatalf("unexpected bind: %+v", q)
+	}
+}
+
+func TestValidateQuery_FieldErrors(t *testing.T) {
+	w, q := serveQuery(t, "limit=5000&offset=x&order=sideways&since=yesterday")
+	if w.Code != http.StatusBadRequest || q != nil {
+		t.Fatalf("status=%d handler ran=%v", w.Code, q != nil)
+	}
+	var body struct {
+		Meta struct {
+			Fields []fieldError `json:"fields"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// Parse errors are reported before rule checks run.
+	got := map[string]string{}
+	for _, f := range body.Meta.Fields {
+		got[f.Field] = f.Rule
+	}
+	if len(got) != 2 || got["offset"] != "int" || got["since"] != "rfc3339" {
+		t.Fatalf("parse errors: %+v", body.Meta.Fields)
+	}
+
+	w, _ = serveQuery(t, "limit=5000&order=sideways&since=2026-02-01T00:00:00Z&until=2026-01-01T00:00:00Z")
+	body.Meta.Fields = nil
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	want := map[string]fieldError{
+		"limit": {Field: "limit", Rule: "max", Param: "1000", Message: "must be at most 1000"},
+		"order": {Field: "order", Rule: "oneof", Param: "asc desc", Message: "must be one of: asc, desc"},
+	}
+	if len(body.Meta.Fields) != len(want) {
+		t.Fatalf("rule errors: %+v", body.Meta.Fields)
+	}
+	for _, f := range body.Meta.Fields {
+		if f != want[f.Field] {
+			t.Fatalf("field %s: got %+v want %+v", f.Field, f, want[f.Field])
+		}
+	}
+
+	// Cross-field checks run once the fields themselves are valid.
+	w, _ = serveQuery(t, "since=2026-02-01T00:00:00Z&until=2026-01-01T00:00:00Z")
+	body.Meta.Fields = nil
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusBadRequest || len(body.Meta.Fields) != 1 || body.Meta.Fields[0].Field != "until" {
+		t.Fatalf("range check: %d %+v", w.Code, body.Meta.Fields)
+	}
+}
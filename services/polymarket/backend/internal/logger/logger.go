@@ -9,7 +9,9 @@ import (
 	"polymarket/internal/config"
 )
 
-func New(cfg config.LogConfig) (*zap.Logger, error) {
+// New builds the service logger. When ring is set, entries at the
+// configured level are also kept there for tailing over HTTP.
+func New(cfg config.LogConfig, ring *Ring) (*zap.Logger, error) {
 	level := zapcore.InfoLevel
 	if err := level.Set(strings.ToLower(cfg.Level)); err != nil {
 		level = zapcore.InfoLevel
@@ -38,5 +40,10 @@ func New(cfg config.LogConfig) (*zap.Logger, error) {
 		}
 	}
 
-	return zc.Build()
+	if ring == nil {
+		return zc.Build()
+	}
+	return zc.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, ring.Core(zc.Level))
+	}))
 }
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Entry is one structured log line kept by a Ring.
+type Entry struct {
+	Seq     uint64         `json:"seq"`
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Module  string         `json:"module"`
+	Message string         `json:"message"`
+	Caller  string         `json:"caller,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// Filter selects ring entries.
+type Filter struct {
+	// MinLevel drops entries below this level; the zero value is info.
+	MinLevel zapcore.Level
+	// Modules keeps entries whose module is in the list.
+	Modules []string
+	// AfterSeq keeps entries newer than this sequence number.
+	AfterSeq uint64
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e Entry) bool {
+	if e.Seq <= f.AfterSeq {
+		return false
+	}
+	var lvl zapcore.Level
+	if err := lvl.Set(e.Level); err == nil && lvl < f.MinLevel {
+		return false
+	}
+	if len(f.Modules) == 0 {
+		return true
+	}
+	for _, m := range f.Modules {
+		if strings.EqualFold(m, e.Module) {
+			return true
+		}
+	}
+	return false
+}
+
+// Ring keeps the most recent log entries in memory and fans new ones out to
+// subscribers, so logs can be tailed over HTTP without shell access.
+type Ring struct {
+	mu      sync.RWMutex
+	entries []Entry
+	next    int
+	full    bool
+	seq     uint64
+	subs    map[chan Entry]struct{}
+}
+
+// NewRing keeps up to size entries; size <= 0 uses 2000.
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		size = 2000
+	}
+	return &Ring{entries: make([]Entry, size), subs: map[chan Entry]struct{}{}}
+}
+
+// Recent returns up to limit of the newest entries matching f, oldest first.
+func (r *Ring) Recent(f Filter, limit int) []Entry {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n := r.next
+	if r.full {
+		n = len(r.entries)
+	}
+	out := []Entry{}
+	// Walk newest to oldest, then reverse.
+	for i := 0; i < n && (limit <= 0 || len(out) < limit); i++ {
+		idx := (r.next - 1 - i + len(r.entries)) % len(r.entries)
+		if e := r.entries[idx]; f.Match(e) {
+			out = append(out, e)
+		}
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// Subscribe returns a channel receiving every new entry and a func that
+// ends the subscription. Slow subscribers miss entries rather than block
+// logging.
+func (r *Ring) Subscribe(buffer int) (<-chan Entry, func()) {
+	if buffer <= 0 {
+		buffer = 256
+	}
+	ch := make(chan Entry, buffer)
+	r.mu.Lock()
+	r.subs[ch] = struct{}{}
+	r.mu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			r.mu.Lock()
+			delete(r.subs, ch)
+			r.mu.Unlock()
+		})
+	}
+}
+
+func (r *Ring) add(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	e.Seq = r.seq
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	for ch := range r.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Core returns a zapcore.Core that records entries at or above level.
+func (r *Ring) Core(level zapcore.LevelEnabler) zapcore.Core {
+	return &ringCore{LevelEnabler: level, ring: r}
+}
+
+type ringCore struct {
+	zapcore.LevelEnabler
+	ring   *Ring
+	fields []zapcore.Field
+}
+
+func (c *ringCore) With(fields []zapcore.Field) zapcore.Core {
+	next := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	next = append(next, c.fields...)
+	next = append(next, fields...)
+	return &ringCore{LevelEnabler: c.LevelEnabler, ring: c.ring, fields: next}
+}
+
+func (c *ringCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *ringCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	e := Entry{
+		Time:    ent.Time.UTC(),
+		Level:   ent.Level.String(),
+		Module:  entryModule(ent),
+		Message: ent.Message,
+	}
+	if ent.Caller.Defined {
+		e.Caller = ent.Caller.TrimmedPath()
+	}
+	if len(enc.Fields) > 0 {
+		e.Fields = enc.Fields
+	}
+	c.ring.add(e)
+	return nil
+}
+
+func (c *ringCore) Sync() error { return nil }
+
+// entryModule is the logger name when set, otherwise the package directory
+// of the caller (e.g. "service" for internal/service/clob_executor.go).
+func entryModule(ent zapcore.Entry) string {
+	if ent.LoggerName != "" {
+		return ent.LoggerName
+	}
+	if !ent.Caller.Defined {
+		return ""
+	}
+	return path.Base(path.Dir(ent.Caller.File))
+}
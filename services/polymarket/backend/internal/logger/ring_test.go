@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRing_RecentAndSubscribe(t *testing.T) {
+	ring := NewRing(3)
+	log := zap.New(ring.Core(zapcore.DebugLevel), zap.AddCaller())
+	ch, cancel := ring.Subscribe(8)
+	defer cancel()
+
+	log.Debug("one")
+	log.Info("two", zap.String("k", "v"))
+	log.Warn("three")
+	log.Error("four")
+
+	got := ring.Recent(Filter{MinLevel: zapcore.DebugLevel}, 0)
+	if len(got) != 3 || got[0].Message != "two" || got[2].Message != "four" || got[2].Seq != 4 {
+		t.Fatalf("recent=%+v", got)
+	}
+	if got[0].Fields["k"] != "v" || got[0].Module != "logger" {
+		t.Fatalf("entry=%+v", got[0])
+	}
+	if got := ring.Recent(Filter{MinLevel: zapcore.WarnLevel, AfterSeq: 3}, 0); len(got) != 1 || got[0].Message != "four" {
+		t.Fatalf("filtered=%+v", got)
+	}
+	if got := ring.Recent(Filter{Modules: []string{"service"}}, 0); len(got) != 0 {
+		t.Fatalf("module filter=%+v", got)
+	}
+	if e := <-ch; e.Message != "one" {
+		t.Fatalf("first streamed=%+v", e)
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Analytics export datasets.
+const (
+	AnalyticsExportTrades = "trades"
+	AnalyticsExportDaily  = "daily"
+)
+
+// AnalyticsExport is a saved export job: which settled trades to share and
+// how to mask them. Masking holds a service.ExportMasking. Every run gets a
+// fresh watermark; the last one is kept here so a leaked file can be traced
+// back to its job.
+type AnalyticsExport struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement"`
+	Name   string `gorm:"type:varchar(100);not null;index"`
+	Tenant string `gorm:"type:varchar(50);not null;default:'default';index"`
+
+	Dataset      string         `gorm:"type:varchar(20);not null"`
+	StrategyName *string        `gorm:"type:varchar(50)"`
+	Since        *time.Time     `gorm:"type:timestamptz"`
+	Until        *time.Time     `gorm:"type:timestamptz"`
+	Masking      datatypes.JSON `gorm:"type:jsonb;not null"`
+
+	RunCount      int        `gorm:"not null;default:0"`
+	LastWatermark string     `gorm:"type:varchar(64)"`
+	LastRunAt     *time.Time `gorm:"type:timestamptz"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime;index"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (AnalyticsExport) TableName() string {
+	return "analytics_exports"
+}
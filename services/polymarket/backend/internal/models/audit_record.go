@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// AuditRecord is an append-only, hash-chained record of one write request.
+// Hash covers the record fields and PrevHash, so editing or deleting any row
+// breaks verification of every later row.
+type AuditRecord struct {
+	ID  uint64 `gorm:"primaryKey;autoIncrement"`
+	Seq uint64 `gorm:"not null;uniqueIndex;comment:链上序号(从1开始)"`
+
+	Agent      string `gorm:"type:varchar(100);not null;comment:写入方"`
+	Method     string `gorm:"type:varchar(10);not null;comment:HTTP方法"`
+	Path       string `gorm:"type:text;not null;comment:请求路径"`
+	Status     int    `gorm:"not null;comment:响应状态码"`
+	DurationMs int64  `gorm:"not null;default:0;comment:耗时(毫秒)"`
+	Project    string `gorm:"type:varchar(100);comment:PaaS项目"`
+	Role       string `gorm:"type:varchar(50);comment:PaaS角色"`
+
+	PrevHash string `gorm:"type:varchar(64);not null;comment:上一条记录哈希"`
+	Hash     string `gorm:"type:varchar(64);not null;uniqueIndex;comment:本条记录哈希"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;not null;index;comment:记录时间(微秒精度,参与哈希)"`
+}
+
+func (AuditRecord) TableName() string {
+	return "audit_records"
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	CampaignStatusActive = "active"
+	CampaignStatusHalted = "halted"
+	CampaignStatusEnded  = "ended"
+)
+
+// Campaign groups plans and opportunities under one multi-day theme. Plans
+// in the campaign share BudgetUSD (planned size of live plans) and
+// MaxLossUSD (realized loss); hitting either halts the campaign, which
+// stops new plans and blocks its pending plans from executing. A zero limit
+// is not enforced.
+type Campaign struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement"`
+	Tenant      string `gorm:"type:varchar(50);not null;default:'default';index"`
+	Name        string `gorm:"type:varchar(100);not null"`
+	Description string `gorm:"type:text;not null;default:''"`
+	Status      string `gorm:"type:varchar(20);not null;default:'active';index"`
+
+	BudgetUSD  decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	MaxLossUSD decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+
+	StartsAt *time.Time `gorm:"type:timestamptz"`
+	EndsAt   *time.Time `gorm:"type:timestamptz;index"`
+
+	HaltReason string     `gorm:"type:varchar(50);not null;default:''"`
+	HaltedAt   *time.Time `gorm:"type:timestamptz"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (Campaign) TableName() string {
+	return "campaigns"
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	CashOpDeposit    = "deposit"
+	CashOpWithdrawal = "withdrawal"
+)
+
+// Cash operation statuses. An operation moves requested -> approved ->
+// completed -> reconciled; it can be rejected while requested and cancelled
+// until it completes.
+const (
+	CashOpRequested  = "requested"
+	CashOpApproved   = "approved"
+	CashOpRejected   = "rejected"
+	CashOpCancelled  = "cancelled"
+	CashOpCompleted  = "completed"
+	CashOpReconciled = "reconciled"
+)
+
+// CashOperation is a deposit to or withdrawal from the trading wallet.
+// Completed and reconciled operations make up the cash ledger; approved
+// withdrawals are reserved against buying power until they complete.
+type CashOperation struct {
+	ID        uint64          `gorm:"primaryKey;autoIncrement"`
+	Tenant    string          `gorm:"type:varchar(50);not null;default:'default';index"`
+	Kind      string          `gorm:"type:varchar(20);not null"`
+	Status    string          `gorm:"type:varchar(20);not null;default:'requested';index"`
+	AmountUSD decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	Wallet    string          `gorm:"type:varchar(64);not null;default:''"`
+	TxHash    string          `gorm:"type:varchar(100);not null;default:'';index"`
+	Notes     string          `gorm:"type:text;not null;default:''"`
+
+	RequestedBy string `gorm:"type:varchar(100);not null;default:''"`
+	ReviewedBy  string `gorm:"type:varchar(100);not null;default:''"`
+	ReviewNote  string `gorm:"type:text;not null;default:''"`
+
+	// OnchainAmountUSD is the amount of the matched onchain transfer;
+	// ReconcileError says why the last reconciliation found no match.
+	OnchainAmountUSD *decimal.Decimal `gorm:"type:numeric(30,10)"`
+	ReconcileError   string           `gorm:"type:text;not null;default:''"`
+
+	ReviewedAt   *time.Time `gorm:"type:timestamptz"`
+	CompletedAt  *time.Time `gorm:"type:timestamptz"`
+	ReconciledAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt    time.Time  `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (CashOperation) TableName() string {
+	return "cash_operations"
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Catalog change kinds.
+const (
+	CatalogChangeCreated   = "created"
+	CatalogChangeUpdated   = "updated"
+	CatalogChangeClosed    = "closed"
+	CatalogChangeLabeled   = "labeled"
+	CatalogChangeUnlabeled = "unlabeled"
+)
+
+// CatalogChange is one entry of the catalog change journal the frontend
+// syncs from incrementally. Sync writes created, updated and closed entries
+// for events and markets; label writes add labeled and unlabeled entries
+// with EntityID set to the market and Fields to the label.
+type CatalogChange struct {
+	ID       uint64 `gorm:"primaryKey;autoIncrement"`
+	Entity   string `gorm:"type:varchar(10);not null;comment:event|market|label"`
+	EntityID string `gorm:"type:varchar(100);not null;index"`
+	Kind     string `gorm:"type:varchar(10);not null;comment:created|updated|closed|labeled|unlabeled"`
+	// Fields lists the changed fields of an update, comma-separated.
+	Fields string `gorm:"type:varchar(200);not null;default:''"`
+
+	ChangedAt time.Time `gorm:"type:timestamptz;not null;index"`
+}
+
+func (CatalogChange) TableName() string {
+	return "catalog_changes"
+}
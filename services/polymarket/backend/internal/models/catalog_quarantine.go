@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// CatalogQuarantine holds catalog rows rejected by data-quality rules during sync.
+// Payload keeps the mapped rows so they can be re-processed once rules are fixed.
+type CatalogQuarantine struct {
+	ID         uint64 `gorm:"primaryKey;autoIncrement"`
+	EntityType string `gorm:"type:varchar(20);not null;uniqueIndex:uniq_catalog_quarantine;comment:实体类型(market)"`
+	EntityID   string `gorm:"type:varchar(100);not null;uniqueIndex:uniq_catalog_quarantine;comment:实体ID"`
+	Rule       string `gorm:"type:varchar(50);not null;uniqueIndex:uniq_catalog_quarantine;index;comment:触发的数据质量规则"`
+	Reason     string `gorm:"type:text;not null;comment:隔离原因"`
+	Scope      string `gorm:"type:varchar(20);not null;comment:同步范围"`
+	Status     string `gorm:"type:varchar(20);not null;default:'quarantined';index;comment:quarantined|released"`
+
+	Payload datatypes.JSON `gorm:"type:jsonb;comment:隔离时的映射数据"`
+	Hits    int            `gorm:"not null;default:1;comment:命中次数"`
+
+	FirstSeenAt time.Time  `gorm:"type:timestamptz;not null;comment:首次隔离时间"`
+	LastSeenAt  time.Time  `gorm:"type:timestamptz;not null;comment:最近隔离时间"`
+	ReleasedAt  *time.Time `gorm:"type:timestamptz;comment:释放时间"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (CatalogQuarantine) TableName() string {
+	return "catalog_quarantine"
+}
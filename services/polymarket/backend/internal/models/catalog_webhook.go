@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// CatalogWebhook subscribes an external endpoint to new catalog events and
+// markets. EntityTypes, Tags and Keywords are JSON string arrays; an empty
+// filter matches everything. Secret signs deliveries and is stored
+// encrypted when a settings key is configured.
+type CatalogWebhook struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement"`
+	Tenant string `gorm:"type:varchar(64);not null;default:'';index"`
+	Name   string `gorm:"type:varchar(100);not null"`
+	URL    string `gorm:"type:text;not null"`
+	Secret string `gorm:"type:text;not null"`
+
+	EntityTypes     datatypes.JSON `gorm:"type:jsonb"`
+	Tags            datatypes.JSON `gorm:"type:jsonb"`
+	Keywords        datatypes.JSON `gorm:"type:jsonb"`
+	MinLiquidityUSD float64        `gorm:"not null;default:0"`
+	Enabled         bool           `gorm:"not null;index"`
+
+	LastDeliveryAt      *time.Time `gorm:"type:timestamptz"`
+	LastStatus          int        `gorm:"not null;default:0"`
+	LastError           string     `gorm:"type:text;not null;default:''"`
+	ConsecutiveFailures int        `gorm:"not null;default:0"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (CatalogWebhook) TableName() string {
+	return "catalog_webhooks"
+}
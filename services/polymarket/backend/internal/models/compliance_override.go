@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ComplianceOverride lets a market restricted by the compliance rules be
+// traded until ExpiresAt. Reason is mandatory; Project and Role identify the
+// admin token that granted it, as in the audit chain.
+type ComplianceOverride struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement"`
+	MarketID  string     `gorm:"type:varchar(100);not null;index"`
+	Reason    string     `gorm:"type:text;not null"`
+	Project   string     `gorm:"type:varchar(100);not null;default:''"`
+	Role      string     `gorm:"type:varchar(50);not null;default:''"`
+	ExpiresAt time.Time  `gorm:"type:timestamptz;not null;index"`
+	RevokedAt *time.Time `gorm:"type:timestamptz"`
+	// RevokeReason is required when an override is withdrawn early.
+	RevokeReason string    `gorm:"type:text;not null;default:''"`
+	CreatedAt    time.Time `gorm:"type:timestamptz;autoCreateTime"`
+}
+
+func (ComplianceOverride) TableName() string {
+	return "compliance_overrides"
+}
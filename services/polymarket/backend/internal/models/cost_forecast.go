@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CostForecast is the all-in execution cost forecast when a plan was created
+// from an opportunity. NetEdgeUSD is GrossEdgeUSD less slippage, fees and the
+// adverse-selection buffer. Realized costs are read from the plan's fills and
+// PnL record when accuracy is scored.
+type CostForecast struct {
+	ID            uint64 `gorm:"primaryKey;autoIncrement"`
+	PlanID        uint64 `gorm:"not null;uniqueIndex"`
+	OpportunityID uint64 `gorm:"not null;index"`
+	StrategyName  string `gorm:"type:varchar(50);not null;index"`
+
+	SizeUSD             decimal.Decimal `gorm:"type:numeric(30,10);not null"`
+	GrossEdgeUSD        decimal.Decimal `gorm:"type:numeric(30,10);not null"`
+	SlippageUSD         decimal.Decimal `gorm:"type:numeric(30,10);not null"`
+	FeesUSD             decimal.Decimal `gorm:"type:numeric(30,10);not null"`
+	AdverseSelectionUSD decimal.Decimal `gorm:"type:numeric(30,10);not null"`
+	NetEdgeUSD          decimal.Decimal `gorm:"type:numeric(30,10);not null"`
+	// UnknownDepthLegs counts legs priced with the flat slippage fallback.
+	UnknownDepthLegs int `gorm:"not null;default:0"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime;index"`
+}
+
+func (CostForecast) TableName() string {
+	return "cost_forecasts"
+}
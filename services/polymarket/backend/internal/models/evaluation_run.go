@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// EvaluationRun records one strategy evaluation tick (one batch of signals).
+type EvaluationRun struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement"`
+	StrategyName string `gorm:"type:varchar(50);not null;index:idx_evaluation_runs_strategy_started,priority:1"`
+	SignalType   string `gorm:"type:varchar(50);not null"`
+
+	SignalsConsumed      int `gorm:"not null;default:0"`
+	MarketsScanned       int `gorm:"not null;default:0"`
+	OpportunitiesFound   int `gorm:"not null;default:0"` // before risk filtering
+	OpportunitiesEmitted int `gorm:"not null;default:0"` // after risk filtering
+
+	Rejects    datatypes.JSON `gorm:"type:jsonb"` // {"reason": count}
+	DurationMs int64          `gorm:"not null;default:0"`
+	Error      string         `gorm:"type:text"`
+
+	StartedAt time.Time `gorm:"type:timestamptz;not null;index:idx_evaluation_runs_strategy_started,priority:2;index"`
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+}
+
+func (EvaluationRun) TableName() string {
+	return "evaluation_runs"
+}
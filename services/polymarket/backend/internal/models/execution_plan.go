@@ -8,12 +8,19 @@ import (
 )
 
 // ExecutionPlan is L6: plan produced from an opportunity, optionally after risk preflight.
+// Manual plans are built from raw legs and have Source=manual and OpportunityID=0.
 type ExecutionPlan struct {
 	ID            uint64 `gorm:"primaryKey;autoIncrement"`
 	OpportunityID uint64 `gorm:"not null;index"`
 
 	Status       string `gorm:"type:varchar(20);not null;default:'draft';index"`
 	StrategyName string `gorm:"type:varchar(50);not null;index"`
+	Tenant       string `gorm:"type:varchar(50);not null;default:'default';index"`
+	Source       string `gorm:"type:varchar(20);not null;default:'opportunity';index"` // opportunity|manual
+	// AutoExecuted marks plans created by the auto executor.
+	AutoExecuted bool `gorm:"not null;default:false;index"`
+	// CampaignID groups the plan under a campaign's budget and loss limit.
+	CampaignID *uint64 `gorm:"index"`
 
 	PlannedSizeUSD decimal.Decimal `gorm:"type:numeric(30,10);not null"`
 	MaxLossUSD     decimal.Decimal `gorm:"type:numeric(30,10);not null"`
@@ -22,6 +29,18 @@ type ExecutionPlan struct {
 	Params          datatypes.JSON `gorm:"type:jsonb"`
 	PreflightResult datatypes.JSON `gorm:"type:jsonb"`
 	Legs            datatypes.JSON `gorm:"type:jsonb;not null"`
+	// RiskReport is written at preflight (see risk.RiskReport) for audit and
+	// approval review.
+	RiskReport datatypes.JSON `gorm:"type:jsonb"`
+
+	// Time trigger: a scheduled plan is preflighted and submitted once
+	// TriggerAt passes. With TriggerMinutesBeforeEnd set, TriggerAt tracks
+	// that many minutes before the end of TriggerMarketID's event.
+	TriggerState            string     `gorm:"type:varchar(20);not null;default:'';index"` // scheduled|fired|failed
+	TriggerAt               *time.Time `gorm:"type:timestamptz;index"`
+	TriggerMinutesBeforeEnd *int
+	TriggerMarketID         *string `gorm:"type:varchar(100)"`
+	TriggerError            string  `gorm:"type:text"`
 
 	ExecutedAt *time.Time `gorm:"type:timestamptz;index"`
 	CreatedAt  time.Time  `gorm:"type:timestamptz;autoCreateTime"`
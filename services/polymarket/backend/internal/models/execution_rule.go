@@ -21,6 +21,10 @@ type ExecutionRule struct {
 	MaxHoldHours   int             `gorm:"not null;default:72"`
 	MaxDailyTrades int             `gorm:"not null;default:10"`
 
+	// Condition is an optional expression (see package expr) that must also
+	// hold, e.g. `confidence > 0.7 && label in ["safe_no"]`.
+	Condition string `gorm:"type:text;not null;default:''"`
+
 	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
 	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
 }
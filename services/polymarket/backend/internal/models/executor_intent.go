@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Executor intent actions, one per kind of request sent to the venue.
+const (
+	ExecutorIntentSubmit = "submit"
+	ExecutorIntentCancel = "cancel"
+	ExecutorIntentAmend  = "amend"
+)
+
+// Executor intent statuses. An intent is pending from just before its
+// request is sent until the response is recorded as resolved or failed.
+// One still pending at startup was cut off by a crash: it is reconciled
+// from the venue's view of the order, or orphaned when the venue never
+// acknowledged the order and it cannot be looked up.
+const (
+	ExecutorIntentPending    = "pending"
+	ExecutorIntentResolved   = "resolved"
+	ExecutorIntentFailed     = "failed"
+	ExecutorIntentReconciled = "reconciled"
+	ExecutorIntentOrphaned   = "orphaned"
+)
+
+// ExecutorIntent is the write-ahead record of one venue request for an
+// order. Request holds what was sent; Outcome is the order status and
+// Response the order updates it produced.
+type ExecutorIntent struct {
+	ID          uint64         `gorm:"primaryKey;autoIncrement"`
+	Action      string         `gorm:"type:varchar(16);not null;index"`
+	OrderID     uint64         `gorm:"not null;index"`
+	PlanID      uint64         `gorm:"not null;index"`
+	TokenID     string         `gorm:"type:varchar(100);not null;default:''"`
+	ClobOrderID string         `gorm:"type:varchar(100);not null;default:''"`
+	Request     datatypes.JSON `gorm:"type:jsonb"`
+	Status      string         `gorm:"type:varchar(16);not null;default:'pending';index"`
+	Outcome     string         `gorm:"type:varchar(20);not null;default:''"`
+	Response    datatypes.JSON `gorm:"type:jsonb"`
+	Error       string         `gorm:"type:text;not null;default:''"`
+	ResolvedAt  *time.Time     `gorm:"type:timestamptz"`
+	CreatedAt   time.Time      `gorm:"type:timestamptz;autoCreateTime;index"`
+	UpdatedAt   time.Time      `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (ExecutorIntent) TableName() string {
+	return "executor_intents"
+}
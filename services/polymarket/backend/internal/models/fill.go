@@ -11,7 +11,13 @@ type Fill struct {
 	ID        uint64 `gorm:"primaryKey;autoIncrement"`
 	PlanID    uint64 `gorm:"not null;index"`
 	TokenID   string `gorm:"type:varchar(100);not null;index"`
-	Direction string `gorm:"type:varchar(10);not null"`
+	Direction string `gorm:"type:varchar(64);not null"`
+	// OrderLineageID ties the fill to a logical order (see Order.LineageID);
+	// 0 for fills recorded outside the executor.
+	OrderLineageID uint64 `gorm:"not null;default:0;index"`
+	// ExternalTradeID is the venue trade id of an imported manual or offline
+	// fill; imports skip ids already recorded.
+	ExternalTradeID *string `gorm:"type:varchar(100);uniqueIndex"`
 
 	FilledSize decimal.Decimal  `gorm:"type:numeric(30,10);not null"`
 	AvgPrice   decimal.Decimal  `gorm:"type:numeric(20,10);not null"`
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// IdempotencyKey records a V2 write made with an Idempotency-Key header so a
+// retry within ExpiresAt gets the original response instead of re-applying
+// the effect. Keys are unique per tenant.
+type IdempotencyKey struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement"`
+	Tenant      string `gorm:"type:varchar(64);not null;default:'';uniqueIndex:uq_idempotency_keys_scope,priority:1"`
+	Key         string `gorm:"column:idempotency_key;type:varchar(255);not null;uniqueIndex:uq_idempotency_keys_scope,priority:2"`
+	Method      string `gorm:"type:varchar(10);not null"`
+	Path        string `gorm:"type:varchar(255);not null"`
+	RequestHash string `gorm:"type:varchar(64);not null"`
+	// Status is pending while the first request runs, then complete.
+	Status         string    `gorm:"type:varchar(16);not null;default:'pending'"`
+	ResponseStatus int       `gorm:"not null;default:0"`
+	ContentType    string    `gorm:"type:varchar(100);not null;default:''"`
+	ResponseBody   string    `gorm:"type:text;not null;default:''"`
+	CreatedAt      time.Time `gorm:"type:timestamptz;autoCreateTime"`
+	ExpiresAt      time.Time `gorm:"type:timestamptz;not null;index"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
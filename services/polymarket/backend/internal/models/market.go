@@ -25,6 +25,17 @@ type Market struct {
 	ExternalUpdatedAt *time.Time       `gorm:"type:timestamptz;index;comment:外部更新时间"`
 	LastSeenAt        time.Time        `gorm:"type:timestamptz;not null;comment:最近同步时间"`
 	RawJSON           datatypes.JSON   `gorm:"type:jsonb;not null;comment:原始数据"`
+	// CatalogEpoch changes when the market's tradable metadata or token set
+	// changes; tokens carry the same value and books the value they were
+	// written against.
+	CatalogEpoch int64 `gorm:"not null;default:0;comment:目录一致性纪元"`
+	// Onchain state of the CTF condition read from Polygon: the outcome
+	// slot count, each slot's payout share once reported and when the report
+	// was first seen. Catalog sync leaves these alone.
+	OnchainOutcomeSlots *int           `gorm:"comment:链上结果槽位数"`
+	OnchainPayouts      datatypes.JSON `gorm:"type:jsonb;comment:链上赔付比例"`
+	OnchainResolvedAt   *time.Time     `gorm:"type:timestamptz;index;comment:链上结算发现时间"`
+	OnchainCheckedAt    *time.Time     `gorm:"type:timestamptz;comment:链上状态检查时间"`
 }
 
 func (Market) TableName() string {
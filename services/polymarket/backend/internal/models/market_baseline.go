@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// MarketBaseline is the trailing activity of a catalog market, updated on
+// every sync as exponentially weighted averages: its liquidity and the
+// rate its cumulative volume grows at, in USD per hour. LiquidityCollapsed
+// and VolumeSpiking remember an anomaly is ongoing so it is reported once.
+type MarketBaseline struct {
+	MarketID      string    `gorm:"primaryKey;type:varchar(100)"`
+	Liquidity     float64   `gorm:"not null;default:0;comment:流动性均值"`
+	VolumeRate    float64   `gorm:"not null;default:0;comment:每小时成交额均值"`
+	LastVolume    float64   `gorm:"not null;default:0;comment:上次累计成交额"`
+	Samples       int       `gorm:"not null;default:0;comment:样本数"`
+	LastSampledAt time.Time `gorm:"type:timestamptz;not null;comment:上次采样时间"`
+
+	LiquidityCollapsed bool `gorm:"not null;default:false"`
+	VolumeSpiking      bool `gorm:"not null;default:false"`
+
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (MarketBaseline) TableName() string {
+	return "catalog_market_baselines"
+}
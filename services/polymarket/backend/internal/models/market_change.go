@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// MarketChange records a semantic edit Polymarket made to a market after we
+// first stored it: question or description text, resolution source, or end
+// date. HeldPosition marks edits to markets we had an open position in.
+type MarketChange struct {
+	ID       uint64 `gorm:"primaryKey;autoIncrement"`
+	MarketID string `gorm:"type:varchar(100);not null;index:idx_catalog_market_changes_market_detected,priority:1"`
+	Field    string `gorm:"type:varchar(30);not null;index;comment:question|description|resolution_source|end_date"`
+	OldValue string `gorm:"type:text;comment:变更前的值"`
+	NewValue string `gorm:"type:text;comment:变更后的值"`
+	Scope    string `gorm:"type:varchar(20);not null;comment:同步范围"`
+
+	HeldPosition bool `gorm:"not null;default:false;index"`
+
+	DetectedAt time.Time `gorm:"type:timestamptz;not null;index:idx_catalog_market_changes_market_detected,priority:2"`
+	CreatedAt  time.Time `gorm:"type:timestamptz;autoCreateTime"`
+}
+
+func (MarketChange) TableName() string {
+	return "catalog_market_changes"
+}
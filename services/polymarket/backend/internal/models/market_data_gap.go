@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// MarketDataGap is a window in which a streamed token received no updates
+// although its usual cadence says it should have.
+type MarketDataGap struct {
+	ID      uint64    `gorm:"primaryKey;autoIncrement"`
+	TokenID string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_market_data_gaps_token_start,priority:1"`
+	Start   time.Time `gorm:"column:gap_start;type:timestamptz;not null;uniqueIndex:idx_market_data_gaps_token_start,priority:2"`
+	End     time.Time `gorm:"column:gap_end;type:timestamptz;not null"`
+
+	// ExpectedSeconds is the token's median update interval when detected.
+	ExpectedSeconds float64 `gorm:"type:numeric(12,3);not null;default:0"`
+	// Status: open | backfilled | failed
+	Status           string     `gorm:"type:varchar(20);not null;default:'open';index"`
+	Attempts         int        `gorm:"not null;default:0"`
+	BackfilledPoints int        `gorm:"not null;default:0"`
+	LastError        string     `gorm:"type:text"`
+	BackfilledAt     *time.Time `gorm:"type:timestamptz"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime;index"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (MarketDataGap) TableName() string {
+	return "market_data_gaps"
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// MarketRegime is one classification of a market category. A row is written
+// per category each detector run, so the latest row per category is its
+// current regime and the rest are its history. Momentum is the mean absolute
+// net price move per market over the lookback, Volatility the mean path
+// length (sum of absolute one-minute moves), Efficiency their ratio summed
+// over markets and JumpShare the share of markets with a one-minute jump.
+type MarketRegime struct {
+	ID       uint64 `gorm:"primaryKey;autoIncrement"`
+	Category string `gorm:"type:varchar(50);not null;index:idx_market_regimes_category_computed,priority:1"`
+	Regime   string `gorm:"type:varchar(20);not null;index"`
+
+	Markets     int     `gorm:"not null;default:0"`
+	Momentum    float64 `gorm:"not null;default:0"`
+	Volatility  float64 `gorm:"not null;default:0"`
+	Efficiency  float64 `gorm:"not null;default:0"`
+	JumpShare   float64 `gorm:"not null;default:0"`
+	Settlements int     `gorm:"not null;default:0"`
+
+	ComputedAt time.Time `gorm:"type:timestamptz;not null;index:idx_market_regimes_category_computed,priority:2"`
+}
+
+func (MarketRegime) TableName() string {
+	return "market_regimes"
+}
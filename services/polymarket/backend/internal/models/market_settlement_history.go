@@ -19,7 +19,12 @@ type MarketSettlementHistory struct {
 	Labels   datatypes.JSON `gorm:"type:jsonb"`
 
 	InitialYesPrice *decimal.Decimal `gorm:"type:numeric(20,10)"`
-	FinalYesPrice   *decimal.Decimal `gorm:"type:numeric(20,10)"`
+	// InitialYesPriceSource records where InitialYesPrice came from (gamma,
+	// manual, price_candle, ws_trade, ws_book, rest_book) and InitialYesPriceAt
+	// when that price was observed, if known.
+	InitialYesPriceSource string           `gorm:"type:varchar(30);index"`
+	InitialYesPriceAt     *time.Time       `gorm:"type:timestamptz"`
+	FinalYesPrice         *decimal.Decimal `gorm:"type:numeric(20,10)"`
 
 	SettledAt time.Time `gorm:"type:timestamptz;not null;index"`
 	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
@@ -12,13 +12,19 @@ type Opportunity struct {
 	ID         uint64 `gorm:"primaryKey;autoIncrement"`
 	StrategyID uint64 `gorm:"not null;index"`
 	Strategy   Strategy
+	Tenant     string `gorm:"type:varchar(50);not null;default:'default';index"`
 
 	Status  string  `gorm:"type:varchar(20);not null;index;default:'active'"`
 	EventID *string `gorm:"type:varchar(100);index"`
+	// Shadow opportunities come from a strategy in the shadow launch stage;
+	// they are listed for review but never planned.
+	Shadow bool `gorm:"not null;default:false"`
 	// PrimaryMarketID is used to deduplicate opportunities that are scoped to a single market.
 	PrimaryMarketID *string `gorm:"type:varchar(100);index"`
 
 	MarketIDs datatypes.JSON `gorm:"type:jsonb"`
+	// CampaignID is inherited by plans created from the opportunity.
+	CampaignID *uint64 `gorm:"index"`
 
 	// Core metrics. Store money-like values as numeric to avoid float errors.
 	EdgePct decimal.Decimal `gorm:"type:numeric(20,10);not null"`
@@ -30,12 +36,28 @@ type Opportunity struct {
 
 	DecayType string     `gorm:"type:varchar(20)"`
 	ExpiresAt *time.Time `gorm:"type:timestamptz;index"`
+	// Decay state kept by the opportunity manager. EdgePct and Confidence stay
+	// as the strategy reported them at EdgeObservedAt; the decayed values are
+	// what remains under the DecayType policy. AnchorAt is the event end used
+	// by event-anchored policies.
+	EdgeObservedAt    *time.Time       `gorm:"type:timestamptz"`
+	DecayedEdgePct    *decimal.Decimal `gorm:"type:numeric(20,10)"`
+	DecayedConfidence *float64
+	AnchorAt          *time.Time `gorm:"type:timestamptz"`
 
 	Legs      datatypes.JSON `gorm:"type:jsonb;not null"`
 	SignalIDs datatypes.JSON `gorm:"type:jsonb"`
-	Reasoning string         `gorm:"type:text"`
-	DataAgeMs int            `gorm:"not null"`
-	Warnings  datatypes.JSON `gorm:"type:jsonb"`
+	// SignalType is the signal stream that triggered the evaluation; it feeds
+	// per-type signal quality scoring.
+	SignalType string         `gorm:"type:varchar(50);index"`
+	Reasoning  string         `gorm:"type:text"`
+	DataAgeMs  int            `gorm:"not null"`
+	Warnings   datatypes.JSON `gorm:"type:jsonb"`
+
+	// StrategyChangeID and Cohort tag opportunities emitted while a params
+	// change was under holdout; the control cohort ran on the old params.
+	StrategyChangeID *uint64 `gorm:"index"`
+	Cohort           string  `gorm:"type:varchar(10);not null;default:''"`
 
 	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime;index"`
 	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
@@ -44,3 +66,19 @@ type Opportunity struct {
 func (Opportunity) TableName() string {
 	return "opportunities"
 }
+
+// CurrentEdgePct is the decayed edge when the manager has scored one.
+func (o Opportunity) CurrentEdgePct() decimal.Decimal {
+	if o.DecayedEdgePct != nil {
+		return *o.DecayedEdgePct
+	}
+	return o.EdgePct
+}
+
+// CurrentConfidence is the decayed confidence when the manager has scored one.
+func (o Opportunity) CurrentConfidence() float64 {
+	if o.DecayedConfidence != nil {
+		return *o.DecayedConfidence
+	}
+	return o.Confidence
+}
@@ -6,19 +6,69 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// Working order statuses: an order in one of them may still rest on the
+// venue or fill. Held orders wait on other legs; needs_review orders lost
+// their submit response and may or may not be resting.
+const (
+	OrderStatusPending     = "pending"
+	OrderStatusHeld        = "held"
+	OrderStatusSubmitted   = "submitted"
+	OrderStatusPartial     = "partial"
+	OrderStatusNeedsReview = "needs_review"
+)
+
+// OpenOrderStatuses returns the working order statuses.
+func OpenOrderStatuses() []string {
+	return []string{OrderStatusPending, OrderStatusHeld, OrderStatusSubmitted, OrderStatusPartial, OrderStatusNeedsReview}
+}
+
+// IsOpenOrderStatus reports whether status is a working order status.
+func IsOpenOrderStatus(status string) bool {
+	switch status {
+	case OrderStatusPending, OrderStatusHeld, OrderStatusSubmitted, OrderStatusPartial, OrderStatusNeedsReview:
+		return true
+	}
+	return false
+}
+
 type Order struct {
 	ID          uint64 `gorm:"primaryKey;autoIncrement"`
 	PlanID      uint64 `gorm:"not null;index"`
 	ClobOrderID string `gorm:"type:varchar(100);index"`
 	TokenID     string `gorm:"type:varchar(100);not null;index"`
 
-	Side      string `gorm:"type:varchar(10);not null"`
+	// LineageID is the ID of the first order of a logical order. Amending by
+	// cancel-and-replace creates a new row in the same lineage; 0 on rows
+	// created before lineage tracking means the order is its own lineage.
+	LineageID       uint64  `gorm:"not null;default:0;index"`
+	ReplacesOrderID *uint64 `gorm:"index"`
+	// MergedIntoID is set when the order went to the book as part of one
+	// order with other plans' orders: it is the ID of the order that
+	// submitted them, itself included. Fills of the book order are split
+	// across the group by size.
+	MergedIntoID *uint64 `gorm:"index"`
+
+	Side      string `gorm:"type:varchar(64);not null"`
 	OrderType string `gorm:"type:varchar(20);not null;default:'limit'"`
 
 	Price     decimal.Decimal `gorm:"type:numeric(20,10);not null"`
 	SizeUSD   decimal.Decimal `gorm:"type:numeric(30,10);not null"`
 	FilledUSD decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
 
+	// PricingMode is taker or maker. Maker orders rest inside the spread and
+	// are stepped toward the far touch until filled or switched to taker.
+	PricingMode string `gorm:"type:varchar(10);not null;default:'taker'"`
+	MakerSteps  int    `gorm:"not null;default:0"`
+	// QueueAhead is the book size, in shares, resting ahead of a maker order
+	// at its price or better as of the last poll.
+	QueueAhead *float64   `gorm:"type:numeric"`
+	RepricedAt *time.Time `gorm:"type:timestamptz"`
+	// PriceImprovement is how far, per share, the repricer has moved the
+	// lineage's price in our favor; ImprovementUSD is what fills on this row
+	// captured from it.
+	PriceImprovement decimal.Decimal `gorm:"type:numeric(20,10);not null;default:0"`
+	ImprovementUSD   decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+
 	Status        string `gorm:"type:varchar(20);not null;default:'pending';index"`
 	FailureReason string `gorm:"type:text"`
 
@@ -33,3 +83,11 @@ type Order struct {
 func (Order) TableName() string {
 	return "orders"
 }
+
+// Lineage returns the logical order ID this row belongs to.
+func (o Order) Lineage() uint64 {
+	if o.LineageID > 0 {
+		return o.LineageID
+	}
+	return o.ID
+}
@@ -17,6 +17,9 @@ type OrderbookLatest struct {
 	Source         *string        `gorm:"type:text;comment:数据来源"`
 	DataAgeSeconds int            `gorm:"not null;comment:数据新鲜度秒数"`
 	UpdatedAt      time.Time      `gorm:"type:timestamptz;not null;comment:更新时间"`
+	// CatalogEpoch is the token's epoch when the book was subscribed or
+	// fetched; 0 when unknown.
+	CatalogEpoch int64 `gorm:"not null;default:0;comment:写入时的目录纪元"`
 }
 
 func (OrderbookLatest) TableName() string {
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Outbox message kinds.
+const (
+	OutboxKindPaasLog = "paas_log"
+	OutboxKindNotify  = "notify"
+	OutboxKindWebhook = "webhook"
+	// OutboxKindTradeWebhook delivers a TradeWebhookDelivery, signed with
+	// its webhook's secret.
+	OutboxKindTradeWebhook = "trade_webhook"
+	// OutboxKindCatalogWebhook delivers a catalog webhook payload, signed
+	// with its webhook's secret.
+	OutboxKindCatalogWebhook = "catalog_webhook"
+)
+
+// Outbox message statuses. A pending message is retried at NextAttemptAt
+// until it is delivered or runs out of attempts and is marked dead.
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusDead      = "dead"
+)
+
+// OutboxMessage is a side effect recorded in the same transaction as the
+// write that caused it, so it is delivered if and only if the write commits.
+// Payload is the kind's JSON body.
+type OutboxMessage struct {
+	ID            uint64         `gorm:"primaryKey;autoIncrement"`
+	Kind          string         `gorm:"type:varchar(20);not null;index"`
+	Payload       datatypes.JSON `gorm:"type:jsonb;not null"`
+	Status        string         `gorm:"type:varchar(16);not null;default:'pending';index:idx_outbox_messages_due,priority:1"`
+	Attempts      int            `gorm:"not null;default:0"`
+	NextAttemptAt time.Time      `gorm:"type:timestamptz;not null;index:idx_outbox_messages_due,priority:2"`
+	LastError     string         `gorm:"type:text;not null;default:''"`
+	DeliveredAt   *time.Time     `gorm:"type:timestamptz"`
+	CreatedAt     time.Time      `gorm:"type:timestamptz;autoCreateTime;index"`
+	UpdatedAt     time.Time      `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
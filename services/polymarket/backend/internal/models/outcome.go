@@ -0,0 +1,118 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// Outcome codes name a token's outcome in directions and positions. Binary
+// markets use YES and NO; other outcomes are upper-cased with runs of other
+// characters folded to "_", so "Over 2.5" is OVER_2_5.
+const (
+	OutcomeYes = "YES"
+	OutcomeNo  = "NO"
+)
+
+// OutcomeCode normalizes an outcome label to its code.
+func OutcomeCode(outcome string) string {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.ToUpper(strings.TrimSpace(outcome)) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			if sep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			sep = false
+			continue
+		}
+		sep = true
+	}
+	return b.String()
+}
+
+// Direction joins an action (BUY or SELL) and an outcome label into a leg or
+// fill direction such as BUY_YES or SELL_LAKERS.
+func Direction(action, outcome string) string {
+	return strings.ToUpper(strings.TrimSpace(action)) + "_" + OutcomeCode(outcome)
+}
+
+// SplitDirection parses BUY_<OUTCOME> or SELL_<OUTCOME>. A bare BUY or SELL
+// has an empty outcome.
+func SplitDirection(direction string) (action, outcome string, ok bool) {
+	dir := strings.ToUpper(strings.TrimSpace(direction))
+	for _, a := range []string{"BUY", "SELL"} {
+		if dir == a {
+			return a, "", true
+		}
+		if rest, found := strings.CutPrefix(dir, a+"_"); found && rest != "" {
+			return a, OutcomeCode(rest), true
+		}
+	}
+	return "", "", false
+}
+
+// IsBinaryMarket reports whether tokens are one market's Yes/No pair.
+func IsBinaryMarket(tokens []Token) bool {
+	if len(tokens) != 2 {
+		return false
+	}
+	a, b := OutcomeCode(tokens[0].Outcome), OutcomeCode(tokens[1].Outcome)
+	return (a == OutcomeYes && b == OutcomeNo) || (a == OutcomeNo && b == OutcomeYes)
+}
+
+// OutcomeLeg is one token of an outcome set.
+type OutcomeLeg struct {
+	MarketID string
+	TokenID  string
+	Outcome  string
+	// NoTokenID is the NO token of a binary market's YES leg.
+	NoTokenID string
+}
+
+// OutcomeSet is a group of mutually exclusive tokens of which exactly one
+// pays out: the YES tokens of an event's binary markets, or every token of
+// one non-binary market. MarketID is set for the latter.
+type OutcomeSet struct {
+	MarketID string
+	Legs     []OutcomeLeg
+}
+
+// OutcomeSets groups an event's tokens into outcome sets. Binary markets
+// form one set in market order; each non-binary market forms its own, in
+// outcome order. Markets without tokens are skipped.
+func OutcomeSets(markets []Market, tokens []Token) []OutcomeSet {
+	byMarket := map[string][]Token{}
+	for _, t := range tokens {
+		if t.MarketID == "" || t.ID == "" {
+			continue
+		}
+		byMarket[t.MarketID] = append(byMarket[t.MarketID], t)
+	}
+	binary := OutcomeSet{}
+	var sets []OutcomeSet
+	for _, m := range markets {
+		toks := byMarket[m.ID]
+		if len(toks) == 0 {
+			continue
+		}
+		if IsBinaryMarket(toks) {
+			yes, no := toks[0], toks[1]
+			if OutcomeCode(yes.Outcome) != OutcomeYes {
+				yes, no = no, yes
+			}
+			binary.Legs = append(binary.Legs, OutcomeLeg{MarketID: m.ID, TokenID: yes.ID, Outcome: OutcomeYes, NoTokenID: no.ID})
+			continue
+		}
+		sort.SliceStable(toks, func(i, j int) bool { return toks[i].OutcomeIndex < toks[j].OutcomeIndex })
+		set := OutcomeSet{MarketID: m.ID}
+		for _, t := range toks {
+			set.Legs = append(set.Legs, OutcomeLeg{MarketID: m.ID, TokenID: t.ID, Outcome: OutcomeCode(t.Outcome)})
+		}
+		sets = append(sets, set)
+	}
+	if len(binary.Legs) > 0 {
+		sets = append([]OutcomeSet{binary}, sets...)
+	}
+	return sets
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Portfolio anomaly kinds.
+const (
+	PortfolioAnomalyCostBasisJump = "cost_basis_jump"
+	PortfolioAnomalyPnLSwing      = "pnl_swing"
+	PortfolioAnomalyPositionCount = "position_count"
+)
+
+// PortfolioAnomaly flags a change between two consecutive portfolio
+// snapshots that is larger than expected. A snapshot gets at most one flag
+// per kind. Previous and Current are the compared values; Fills is how many
+// fills landed between the snapshots.
+type PortfolioAnomaly struct {
+	ID             uint64    `gorm:"primaryKey;autoIncrement"`
+	SnapshotID     uint64    `gorm:"not null;uniqueIndex:idx_portfolio_anomalies_snapshot_kind,priority:1"`
+	PrevSnapshotID uint64    `gorm:"not null"`
+	SnapshotAt     time.Time `gorm:"type:timestamptz;not null;index"`
+	Kind           string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_portfolio_anomalies_snapshot_kind,priority:2"`
+
+	Previous  float64 `gorm:"type:numeric(30,10);not null"`
+	Current   float64 `gorm:"type:numeric(30,10);not null"`
+	Change    float64 `gorm:"type:numeric(30,10);not null"`
+	Threshold float64 `gorm:"type:numeric(30,10);not null"`
+	Fills     int64   `gorm:"not null;default:0"`
+	Message   string  `gorm:"type:text;not null"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+}
+
+func (PortfolioAnomaly) TableName() string {
+	return "portfolio_anomalies"
+}
@@ -8,11 +8,18 @@ import (
 
 type Position struct {
 	ID       uint64 `gorm:"primaryKey;autoIncrement"`
-	TokenID  string `gorm:"type:varchar(100);not null;uniqueIndex"`
+	TokenID  string `gorm:"type:varchar(100);not null;uniqueIndex:uq_positions_tenant_token,priority:2;index:idx_positions_token"`
 	MarketID string `gorm:"type:varchar(100);not null;index"`
 	EventID  string `gorm:"type:varchar(100);index"`
-
-	Direction string `gorm:"type:varchar(10);not null"`
+	// Tenant is taken from the plan that opened the position. Positions are
+	// keyed by (tenant, token), so each desk holding a token has its own row.
+	Tenant string `gorm:"type:varchar(50);not null;default:'default';uniqueIndex:uq_positions_tenant_token,priority:1"`
+	// Source is system for positions built from our fills and external for
+	// positions imported from a wallet. ExternalWallet is set on imports.
+	Source         string `gorm:"type:varchar(20);not null;default:'system';index"`
+	ExternalWallet string `gorm:"type:varchar(64);index"`
+
+	Direction string `gorm:"type:varchar(64);not null"`
 
 	Quantity      decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
 	AvgEntryPrice decimal.Decimal `gorm:"type:numeric(20,10);not null;default:0"`
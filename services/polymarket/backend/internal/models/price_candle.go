@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// PriceCandle is a one-minute trade price candle per token. The CLOB stream
+// writes it from last_trade_price events; gap backfill patches missing
+// minutes from the REST price history.
+type PriceCandle struct {
+	TokenID     string    `gorm:"primaryKey;type:varchar(100)"`
+	BucketStart time.Time `gorm:"primaryKey;type:timestamptz"`
+
+	Open    float64   `gorm:"type:numeric(10,6);not null"`
+	High    float64   `gorm:"type:numeric(10,6);not null"`
+	Low     float64   `gorm:"type:numeric(10,6);not null"`
+	Close   float64   `gorm:"type:numeric(10,6);not null"`
+	OpenTS  time.Time `gorm:"type:timestamptz;not null"`
+	CloseTS time.Time `gorm:"type:timestamptz;not null"`
+	Points  int       `gorm:"not null;default:0"`
+	// Source is ws, rest_backfill or mixed.
+	Source string `gorm:"type:varchar(20);not null;default:'ws'"`
+
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (PriceCandle) TableName() string {
+	return "price_candles"
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ResearchSnapshot freezes the markets, books and labels a piece of research
+// looked at. Rows are never updated: Query records how the market set was
+// selected, Payload the captured data and Checksum its SHA-256, so a later
+// reader can verify they analyze the same bytes.
+type ResearchSnapshot struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement"`
+	Name   string `gorm:"type:varchar(100);not null;index"`
+	Tenant string `gorm:"type:varchar(50);not null;default:'default';index"`
+
+	Query       datatypes.JSON `gorm:"type:jsonb;not null"`
+	MarketCount int            `gorm:"not null;default:0"`
+	Payload     datatypes.JSON `gorm:"type:jsonb;not null"`
+	Checksum    string         `gorm:"type:varchar(64);not null"`
+
+	TakenAt   time.Time `gorm:"type:timestamptz;not null"`
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime;index"`
+}
+
+func (ResearchSnapshot) TableName() string {
+	return "research_snapshots"
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RewardEpoch accumulates liquidity-reward quoting metrics for one market
+// over one reward epoch (a UTC day). The tracker samples open maker orders
+// against the latest books; each sample adds to the counters below, so
+// ratios are Quoted/Samples (uptime), Compliant/Quoted (spread compliance)
+// and SizeAtBBO/Quoted (average shares at the touch).
+type RewardEpoch struct {
+	ID       uint64 `gorm:"primaryKey;autoIncrement"`
+	Epoch    string `gorm:"type:varchar(10);not null;uniqueIndex:uniq_reward_epoch_market,priority:1"`
+	MarketID string `gorm:"type:varchar(100);not null;uniqueIndex:uniq_reward_epoch_market,priority:2"`
+
+	// Reward program parameters as last seen in the catalog.
+	MaxSpreadCents float64 `gorm:"not null;default:0"`
+	MinSize        float64 `gorm:"not null;default:0"`
+	DailyRateUSD   float64 `gorm:"not null;default:0"`
+
+	Samples          int     `gorm:"not null;default:0"`
+	QuotedSamples    int     `gorm:"not null;default:0"`
+	CompliantSamples int     `gorm:"not null;default:0"`
+	TwoSidedSamples  int     `gorm:"not null;default:0"`
+	SizeAtBBO        float64 `gorm:"not null;default:0"`
+	// ShareSum adds our share of the market's reward score per sample.
+	ShareSum float64 `gorm:"not null;default:0"`
+
+	EstimatedUSD decimal.Decimal  `gorm:"type:numeric(30,10);not null;default:0"`
+	ReceivedUSD  *decimal.Decimal `gorm:"type:numeric(30,10)"`
+	ReceivedAt   *time.Time       `gorm:"type:timestamptz"`
+
+	LastSampledAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt     time.Time  `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt     time.Time  `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (RewardEpoch) TableName() string {
+	return "reward_epochs"
+}
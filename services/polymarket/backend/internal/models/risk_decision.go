@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+)
+
+// RiskDecision is one risk filter verdict on an opportunity. Opportunities
+// are filtered before they are stored, so decisions are keyed by
+// OpportunityKey, the strategy plus event or market the opportunity manager
+// deduplicates on; OpportunityID is set only when the opportunity already had
+// one. Checks holds the checks evaluated in order, each with its value and
+// threshold; RejectedBy names the first failed check.
+type RiskDecision struct {
+	ID             uint64  `gorm:"primaryKey;autoIncrement"`
+	OpportunityKey string  `gorm:"type:varchar(200);not null;index"`
+	OpportunityID  *uint64 `gorm:"index"`
+	StrategyID     uint64  `gorm:"not null;index"`
+	Tenant         string  `gorm:"type:varchar(50);not null;default:'default';index"`
+
+	Verdict    string         `gorm:"type:varchar(10);not null;index"`
+	RejectedBy string         `gorm:"type:varchar(40)"`
+	Checks     datatypes.JSON `gorm:"type:jsonb;not null"`
+	Warnings   datatypes.JSON `gorm:"type:jsonb"`
+
+	EdgePct decimal.Decimal `gorm:"type:numeric(20,10);not null"`
+	MaxSize decimal.Decimal `gorm:"type:numeric(30,10);not null"`
+	// SampleRate is the share of decisions with this verdict that were kept.
+	SampleRate float64 `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime;index"`
+}
+
+func (RiskDecision) TableName() string {
+	return "risk_decisions"
+}
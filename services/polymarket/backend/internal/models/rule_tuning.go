@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Rule tuning statuses. A run whose best candidate beats the current rule
+// out of sample is proposed until an operator approves or rejects it; a run
+// without one is recorded as no_improvement.
+const (
+	RuleTuningProposed      = "proposed"
+	RuleTuningNoImprovement = "no_improvement"
+	RuleTuningApproved      = "approved"
+	RuleTuningRejected      = "rejected"
+)
+
+// RuleTuning records a grid search over a strategy's execution rule. Every
+// candidate is replayed over the training window [Since, SplitAt) and the
+// held-out window [SplitAt, Until). Frontier keeps the best candidates by
+// training PnL with both scores, Baseline scores the rule as it was, and
+// Proposed holds the params written to the rule on approval.
+type RuleTuning struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement"`
+	StrategyName string `gorm:"type:varchar(50);not null;index:idx_rule_tunings_name_created,priority:1"`
+	Status       string `gorm:"type:varchar(20);not null;index"`
+
+	Since      time.Time `gorm:"type:timestamptz;not null"`
+	SplitAt    time.Time `gorm:"type:timestamptz;not null"`
+	Until      time.Time `gorm:"type:timestamptz;not null"`
+	Candidates int       `gorm:"not null;default:0"`
+
+	Grid     datatypes.JSON `gorm:"type:jsonb"`
+	Baseline datatypes.JSON `gorm:"type:jsonb"`
+	Frontier datatypes.JSON `gorm:"type:jsonb"`
+	Proposed datatypes.JSON `gorm:"type:jsonb"`
+
+	ReviewedBy string     `gorm:"type:varchar(100);not null;default:''"`
+	ReviewNote string     `gorm:"type:text;not null;default:''"`
+	ReviewedAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"type:timestamptz;autoCreateTime;index:idx_rule_tunings_name_created,priority:2"`
+	UpdatedAt  time.Time  `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (RuleTuning) TableName() string {
+	return "rule_tunings"
+}
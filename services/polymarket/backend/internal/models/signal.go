@@ -17,7 +17,7 @@ type Signal struct {
 	TokenID  *string `gorm:"type:varchar(100);index"`
 
 	Strength  float64        `gorm:"not null"`
-	Direction string         `gorm:"type:varchar(10)"`
+	Direction string         `gorm:"type:varchar(64)"`
 	Payload   datatypes.JSON `gorm:"type:jsonb"`
 
 	ExpiresAt *time.Time `gorm:"type:timestamptz;index"`
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// SLOSample is one evaluation of a freshness SLO: of Total items checked
+// (sync scopes, streamed books, closed markets), Good were within the SLO's
+// max age. Burn rates are computed from the samples of a window. WorstAge
+// is the oldest item's age in seconds.
+type SLOSample struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	SLO         string    `gorm:"column:slo;type:varchar(30);not null;index:idx_slo_samples_slo_evaluated,priority:1"`
+	Good        int       `gorm:"not null;default:0"`
+	Total       int       `gorm:"not null;default:0"`
+	WorstAge    float64   `gorm:"not null;default:0"`
+	EvaluatedAt time.Time `gorm:"type:timestamptz;not null;index:idx_slo_samples_slo_evaluated,priority:2"`
+}
+
+func (SLOSample) TableName() string {
+	return "slo_samples"
+}
@@ -6,6 +6,14 @@ import (
 	"gorm.io/datatypes"
 )
 
+// Strategy launch stages, in promotion order.
+const (
+	LaunchStageDark   = "dark"
+	LaunchStageShadow = "shadow"
+	LaunchStageCapped = "capped"
+	LaunchStageFull   = "full"
+)
+
 // Strategy is L5: strategy config and state.
 type Strategy struct {
 	ID          uint64 `gorm:"primaryKey;autoIncrement"`
@@ -13,9 +21,15 @@ type Strategy struct {
 	DisplayName string `gorm:"type:varchar(100);not null"`
 	Description string `gorm:"type:text"`
 	Category    string `gorm:"type:varchar(30);not null;index"`
+	// Tenant is the desk that owns this strategy; its opportunities inherit it.
+	Tenant string `gorm:"type:varchar(50);not null;default:'default';index"`
 
 	Enabled  bool `gorm:"default:false;index"`
 	Priority int  `gorm:"default:0;index"`
+	// LaunchStage gates what an enabled strategy may do: dark only records
+	// evaluation runs, shadow emits non-executable opportunities, capped
+	// clamps opportunity size, full is unrestricted.
+	LaunchStage string `gorm:"type:varchar(10);not null;default:'full'"`
 
 	Params          datatypes.JSON `gorm:"type:jsonb;not null"`
 	RequiredSignals datatypes.JSON `gorm:"type:jsonb"`
@@ -28,3 +42,12 @@ type Strategy struct {
 func (Strategy) TableName() string {
 	return "strategies"
 }
+
+// ValidLaunchStage reports whether stage is one of the launch stages.
+func ValidLaunchStage(stage string) bool {
+	switch stage {
+	case LaunchStageDark, LaunchStageShadow, LaunchStageCapped, LaunchStageFull:
+		return true
+	}
+	return false
+}
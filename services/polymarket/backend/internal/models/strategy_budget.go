@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StrategyBudget caps a strategy's gross traded notional and fees per trading
+// day and per week (Monday to Sunday trading days); zero means uncapped. The
+// auto executor pauses the strategy while any cap is exceeded and resumes it
+// once the window rolls over or the budget is raised.
+type StrategyBudget struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement"`
+	StrategyName string `gorm:"type:varchar(50);not null;uniqueIndex"`
+
+	DailyTurnoverUSD  decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	WeeklyTurnoverUSD decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	DailyFeesUSD      decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	WeeklyFeesUSD     decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+
+	// PausedAt is set while the strategy is paused; PauseReason names the
+	// exceeded caps, e.g. "daily_turnover".
+	PausedAt    *time.Time `gorm:"type:timestamptz"`
+	PauseReason string     `gorm:"type:varchar(200);not null;default:''"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (StrategyBudget) TableName() string {
+	return "strategy_budgets"
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+)
+
+// StrategyCapacity is an estimate of how much capital a strategy can deploy
+// across its candidate markets before execution costs consume its edge.
+// Each active opportunity is sized up against the latest books until its
+// net edge, with slippage scaled by the strategy's ImpactFactor of realized
+// to forecast cost, falls to the floor or its book runs out. CapacityUSD
+// sums those sizes; Markets holds them per opportunity. CapExceeded is set
+// when the strategy's exposure cap is above its capacity.
+type StrategyCapacity struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement"`
+	StrategyName string `gorm:"type:varchar(50);not null;index:idx_strategy_capacities_name_at,priority:1"`
+
+	CapacityUSD     decimal.Decimal `gorm:"type:numeric(30,10);not null"`
+	MedianMarketUSD decimal.Decimal `gorm:"type:numeric(30,10);not null"`
+	Opportunities   int             `gorm:"not null"`
+	ImpactFactor    float64         `gorm:"not null;default:1"`
+	ImpactSamples   int             `gorm:"not null;default:0"`
+	ExposureCapUSD  decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	CapExceeded     bool            `gorm:"not null;default:false"`
+	Markets         datatypes.JSON  `gorm:"type:jsonb"`
+
+	EstimatedAt time.Time `gorm:"type:timestamptz;not null;index:idx_strategy_capacities_name_at,priority:2"`
+}
+
+func (StrategyCapacity) TableName() string {
+	return "strategy_capacities"
+}
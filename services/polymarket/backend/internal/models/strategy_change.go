@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Opportunity cohorts of a strategy change under holdout.
+const (
+	CohortControl   = "control"
+	CohortTreatment = "treatment"
+)
+
+// StrategyChange records a params update. Until HoldoutUntil, HoldoutPct of
+// the strategy's markets keep being evaluated under OldParams as the control
+// cohort, so the change can be judged against what it replaced.
+type StrategyChange struct {
+	ID           uint64         `gorm:"primaryKey;autoIncrement"`
+	StrategyName string         `gorm:"type:varchar(50);not null;index:idx_strategy_changes_name_created,priority:1"`
+	OldParams    datatypes.JSON `gorm:"type:jsonb;not null"`
+	NewParams    datatypes.JSON `gorm:"type:jsonb;not null"`
+	HoldoutPct   float64        `gorm:"not null;default:0"`
+	HoldoutUntil *time.Time     `gorm:"type:timestamptz"`
+	Note         string         `gorm:"type:text;not null;default:''"`
+	CreatedAt    time.Time      `gorm:"type:timestamptz;autoCreateTime;index:idx_strategy_changes_name_created,priority:2"`
+}
+
+func (StrategyChange) TableName() string {
+	return "strategy_changes"
+}
+
+// HoldoutActive reports whether the control cohort is still held out at now.
+func (c StrategyChange) HoldoutActive(now time.Time) bool {
+	return c.HoldoutPct > 0 && c.HoldoutUntil != nil && now.Before(*c.HoldoutUntil)
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// StrategyExpectation is what a strategy's backtest says live trading
+// should look like: the share of decided trades won, the average expected
+// edge per trade (a fraction, as in strategy_daily_stats) and the trades
+// per day.
+type StrategyExpectation struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement"`
+	StrategyName string `gorm:"type:varchar(50);not null;uniqueIndex"`
+
+	WinRate      float64 `gorm:"not null;default:0"`
+	EdgePct      float64 `gorm:"not null;default:0"`
+	TradesPerDay float64 `gorm:"not null;default:0"`
+
+	// Source and the backtest window record where the profile came from.
+	Source       string     `gorm:"type:varchar(200);not null;default:''"`
+	BacktestFrom *time.Time `gorm:"type:timestamptz"`
+	BacktestTo   *time.Time `gorm:"type:timestamptz"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (StrategyExpectation) TableName() string {
+	return "strategy_expectations"
+}
+
+// Strategy drift statuses.
+const (
+	StrategyDriftOK           = "ok"
+	StrategyDriftDrifting     = "drifting"
+	StrategyDriftInsufficient = "insufficient_data"
+)
+
+// StrategyDriftReport compares a strategy's live daily stats over a window
+// with its expectation. Flags names the deviations found, e.g.
+// "trade_frequency_collapse"; Status is drifting when there are any.
+type StrategyDriftReport struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement"`
+	StrategyName string `gorm:"type:varchar(50);not null;index:idx_strategy_drift_name_at,priority:1"`
+
+	WindowDays   int     `gorm:"not null"`
+	Trades       int     `gorm:"not null;default:0"`
+	TradesPerDay float64 `gorm:"not null;default:0"`
+	WinRate      float64 `gorm:"not null;default:0"`
+	EdgePct      float64 `gorm:"not null;default:0"`
+
+	ExpectedTradesPerDay float64 `gorm:"not null;default:0"`
+	ExpectedWinRate      float64 `gorm:"not null;default:0"`
+	ExpectedEdgePct      float64 `gorm:"not null;default:0"`
+
+	Status string         `gorm:"type:varchar(20);not null;index"`
+	Flags  datatypes.JSON `gorm:"type:jsonb"`
+
+	EvaluatedAt time.Time `gorm:"type:timestamptz;not null;index:idx_strategy_drift_name_at,priority:2"`
+}
+
+func (StrategyDriftReport) TableName() string {
+	return "strategy_drift_reports"
+}
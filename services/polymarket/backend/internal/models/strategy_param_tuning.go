@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// StrategyParamTuning records a grid search over a strategy's params. The
+// strategy's stored signals are replayed through a fresh evaluator per
+// candidate over the training window [Since, SplitAt) and the held-out
+// window [SplitAt, Until), and the opportunities it would have emitted are
+// priced on candles. Statuses are the RuleTuning ones; Proposed holds the
+// keys written to strategies.params on approval.
+type StrategyParamTuning struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement"`
+	StrategyName string `gorm:"type:varchar(50);not null;index:idx_strategy_param_tunings_name_created,priority:1"`
+	Status       string `gorm:"type:varchar(20);not null;index"`
+
+	Since      time.Time `gorm:"type:timestamptz;not null"`
+	SplitAt    time.Time `gorm:"type:timestamptz;not null"`
+	Until      time.Time `gorm:"type:timestamptz;not null"`
+	Candidates int       `gorm:"not null;default:0"`
+	Signals    int       `gorm:"not null;default:0"`
+
+	BaseParams datatypes.JSON `gorm:"type:jsonb"`
+	Grid       datatypes.JSON `gorm:"type:jsonb"`
+	Baseline   datatypes.JSON `gorm:"type:jsonb"`
+	Frontier   datatypes.JSON `gorm:"type:jsonb"`
+	Proposed   datatypes.JSON `gorm:"type:jsonb"`
+
+	ReviewedBy string     `gorm:"type:varchar(100);not null;default:''"`
+	ReviewNote string     `gorm:"type:text;not null;default:''"`
+	ReviewedAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"type:timestamptz;autoCreateTime;index:idx_strategy_param_tunings_name_created,priority:2"`
+	UpdatedAt  time.Time  `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (StrategyParamTuning) TableName() string {
+	return "strategy_param_tunings"
+}
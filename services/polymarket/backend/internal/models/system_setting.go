@@ -10,7 +10,10 @@ import (
 type SystemSetting struct {
 	ID uint64 `gorm:"primaryKey;autoIncrement"`
 
-	Key string `gorm:"type:varchar(120);not null;uniqueIndex"`
+	// Key is unique per tenant: a desk's row shadows the shared one.
+	Key string `gorm:"type:varchar(120);not null;uniqueIndex:uq_system_settings_tenant_key,priority:2;index:idx_system_settings_key_name"`
+	// Tenant is the desk that owns the setting; "default" rows are shared.
+	Tenant string `gorm:"type:varchar(50);not null;default:'default';uniqueIndex:uq_system_settings_tenant_key,priority:1"`
 
 	// JSON value, e.g. true/false for switches, or object for richer settings.
 	Value datatypes.JSON `gorm:"type:jsonb;not null"`
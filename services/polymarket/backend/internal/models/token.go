@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/datatypes"
 )
 
@@ -11,10 +12,15 @@ type Token struct {
 	MarketID          string         `gorm:"type:text;index;not null;comment:关联市场ID"`
 	Outcome           string         `gorm:"type:text;not null;comment:结果名称(Yes/No)"`
 	Side              *string        `gorm:"type:text;comment:结果方向标识"`
+	OutcomeIndex      int            `gorm:"not null;default:0;comment:结果在市场outcomes中的序号"`
 	ExternalCreatedAt *time.Time     `gorm:"type:timestamptz;comment:外部创建时间"`
 	ExternalUpdatedAt *time.Time     `gorm:"type:timestamptz;index;comment:外部更新时间"`
 	LastSeenAt        time.Time      `gorm:"type:timestamptz;not null;comment:最近同步时间"`
 	RawJSON           datatypes.JSON `gorm:"type:jsonb;not null;comment:原始数据"`
+	CatalogEpoch      int64          `gorm:"not null;default:0;comment:目录一致性纪元"`
+	// OnchainPayout is the outcome's share of the reported CTF payout, nil
+	// until the condition resolves onchain.
+	OnchainPayout *decimal.Decimal `gorm:"type:numeric(20,10);comment:链上赔付比例"`
 }
 
 func (Token) TableName() string {
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Trade is one trade print from the CLOB stream's last_trade_price events.
+// The tape is kept per token and pruned by age and per-token row count.
+type Trade struct {
+	ID      uint64 `gorm:"primaryKey;autoIncrement"`
+	TokenID string `gorm:"type:varchar(100);not null;index:idx_trades_token_ts,priority:1"`
+	// Market is the condition id reported with the print.
+	Market     *string   `gorm:"type:varchar(100)"`
+	Price      float64   `gorm:"type:numeric(10,6);not null"`
+	Size       float64   `gorm:"type:numeric(20,6);not null;default:0"`
+	Side       string    `gorm:"type:varchar(8);not null;default:''"`
+	FeeRateBps *float64  `gorm:"type:numeric(10,4)"`
+	TradeTS    time.Time `gorm:"type:timestamptz;not null;index:idx_trades_token_ts,priority:2;index"`
+	Source     string    `gorm:"type:varchar(20);not null;default:'ws'"`
+	CreatedAt  time.Time `gorm:"type:timestamptz;autoCreateTime"`
+}
+
+func (Trade) TableName() string {
+	return "trades"
+}
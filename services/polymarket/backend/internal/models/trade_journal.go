@@ -29,6 +29,8 @@ type TradeJournal struct {
 	Notes      string         `gorm:"type:text"`
 	Tags       datatypes.JSON `gorm:"type:jsonb"`
 	ReviewedAt *time.Time     `gorm:"type:timestamptz"`
+	// SnapshotIDs references the research snapshots behind the trade.
+	SnapshotIDs datatypes.JSON `gorm:"type:jsonb"`
 
 	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime;index"`
 	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+)
+
+const (
+	TicketStateDraft     = "draft"
+	TicketStateReviewed  = "reviewed"
+	TicketStateSubmitted = "submitted"
+	TicketStateCancelled = "cancelled"
+)
+
+// TradeTicket is a discretionary trader's session around one opportunity:
+// it owns the draft execution plan, the last preflight and the submit
+// result. Editing the size sends a reviewed ticket back to draft, so only a
+// preflight of the current sizing can be submitted.
+type TradeTicket struct {
+	ID            uint64  `gorm:"primaryKey;autoIncrement"`
+	Tenant        string  `gorm:"type:varchar(50);not null;default:'default';index"`
+	OpportunityID uint64  `gorm:"not null;index"`
+	PlanID        *uint64 `gorm:"index"`
+	State         string  `gorm:"type:varchar(20);not null;default:'draft';index"`
+
+	SizeUSD decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	Notes   string          `gorm:"type:text;not null;default:''"`
+
+	PreflightResult datatypes.JSON `gorm:"type:jsonb"`
+	SubmitResult    datatypes.JSON `gorm:"type:jsonb"`
+
+	ReviewedAt  *time.Time `gorm:"type:timestamptz"`
+	SubmittedAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt   time.Time  `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt   time.Time  `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (TradeTicket) TableName() string {
+	return "trade_tickets"
+}
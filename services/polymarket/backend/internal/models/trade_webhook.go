@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Trade events delivered to trade webhooks.
+const (
+	TradeEventOrderFilled    = "order.filled"
+	TradeEventPlanExecuted   = "plan.executed"
+	TradeEventPositionClosed = "position.closed"
+)
+
+// Trade webhook delivery statuses. A failed delivery is retried by the
+// outbox until it runs out of attempts.
+const (
+	TradeDeliveryPending   = "pending"
+	TradeDeliveryDelivered = "delivered"
+	TradeDeliveryFailed    = "failed"
+)
+
+// TradeWebhook subscribes an external risk or compliance endpoint to fills,
+// executed plans and closed positions. EventTypes and Strategies are JSON
+// string arrays; an empty filter matches everything. A webhook with a
+// Tenant only receives that desk's events. Secret signs deliveries and is
+// stored encrypted when a settings key is configured.
+type TradeWebhook struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement"`
+	Tenant string `gorm:"type:varchar(64);not null;default:'';index"`
+	Name   string `gorm:"type:varchar(100);not null"`
+	URL    string `gorm:"type:text;not null"`
+	Secret string `gorm:"type:text;not null"`
+
+	EventTypes datatypes.JSON `gorm:"type:jsonb"`
+	Strategies datatypes.JSON `gorm:"type:jsonb"`
+	Enabled    bool           `gorm:"not null;index"`
+
+	LastDeliveryAt      *time.Time `gorm:"type:timestamptz"`
+	LastStatus          int        `gorm:"not null;default:0"`
+	LastError           string     `gorm:"type:text;not null;default:''"`
+	ConsecutiveFailures int        `gorm:"not null;default:0"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (TradeWebhook) TableName() string {
+	return "trade_webhooks"
+}
+
+// TradeWebhookDelivery is the log of one event sent to one webhook. EventKey
+// identifies the event (e.g. "fill:42") so an event seen by several replicas
+// is queued once per webhook. Payload is the body sent on every attempt.
+type TradeWebhookDelivery struct {
+	ID        uint64         `gorm:"primaryKey;autoIncrement"`
+	WebhookID uint64         `gorm:"not null;uniqueIndex:idx_trade_webhook_deliveries_event,priority:1"`
+	EventType string         `gorm:"type:varchar(30);not null;index"`
+	EventKey  string         `gorm:"type:varchar(100);not null;uniqueIndex:idx_trade_webhook_deliveries_event,priority:2"`
+	Payload   datatypes.JSON `gorm:"type:jsonb;not null"`
+
+	Status      string     `gorm:"type:varchar(20);not null;default:'pending';index"`
+	Attempts    int        `gorm:"not null;default:0"`
+	HTTPStatus  int        `gorm:"column:http_status;not null;default:0"`
+	LastError   string     `gorm:"type:text;not null;default:''"`
+	DeliveredAt *time.Time `gorm:"type:timestamptz"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime;index"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (TradeWebhookDelivery) TableName() string {
+	return "trade_webhook_deliveries"
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WalletPosition is the latest known position of a tracked (peer benchmark)
+// wallet in one outcome token, from Polymarket's public positions API.
+type WalletPosition struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement"`
+	Wallet      string `gorm:"type:varchar(64);not null;uniqueIndex:idx_wallet_positions_wallet_token,priority:1"`
+	TokenID     string `gorm:"type:varchar(100);not null;uniqueIndex:idx_wallet_positions_wallet_token,priority:2"`
+	ConditionID string `gorm:"type:varchar(100);index"`
+	MarketID    string `gorm:"type:varchar(100);index"`
+	Outcome     string `gorm:"type:varchar(20)"`
+	Title       string `gorm:"type:text"`
+
+	Size         decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	AvgPrice     decimal.Decimal `gorm:"type:numeric(20,10);not null;default:0"`
+	CurrentPrice decimal.Decimal `gorm:"type:numeric(20,10);not null;default:0"`
+	CurrentValue decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+
+	ObservedAt time.Time `gorm:"type:timestamptz;not null"`
+	CreatedAt  time.Time `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+func (WalletPosition) TableName() string {
+	return "wallet_positions"
+}
+
+// WalletPositionChange records a size change between two polls of a tracked
+// wallet. A closed position is recorded with NewSize 0.
+type WalletPositionChange struct {
+	ID       uint64 `gorm:"primaryKey;autoIncrement"`
+	Wallet   string `gorm:"type:varchar(64);not null;index:idx_wallet_position_changes_wallet_observed,priority:1"`
+	TokenID  string `gorm:"type:varchar(100);not null;index"`
+	MarketID string `gorm:"type:varchar(100);index"`
+	Outcome  string `gorm:"type:varchar(20)"`
+	Title    string `gorm:"type:text"`
+
+	PrevSize  decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	NewSize   decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	DeltaSize decimal.Decimal `gorm:"type:numeric(30,10);not null;default:0"`
+	DeltaUSD  decimal.Decimal `gorm:"column:delta_usd;type:numeric(30,10);not null;default:0"`
+	Price     decimal.Decimal `gorm:"type:numeric(20,10);not null;default:0"`
+
+	SignalEmitted bool `gorm:"not null;default:false"`
+
+	ObservedAt time.Time `gorm:"type:timestamptz;not null;index:idx_wallet_position_changes_wallet_observed,priority:2;index"`
+	CreatedAt  time.Time `gorm:"type:timestamptz;autoCreateTime"`
+}
+
+func (WalletPositionChange) TableName() string {
+	return "wallet_position_changes"
+}
@@ -0,0 +1,106 @@
+package opportunity
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"polymarket/internal/config"
+)
+
+// Decay policy kinds.
+const (
+	DecayKindNone          = "none"
+	DecayKindLinear        = "linear"
+	DecayKindExponential   = "exponential"
+	DecayKindEventAnchored = "event_anchored"
+)
+
+// DecayPolicy describes how the edge and confidence of an opportunity fade
+// after the strategy last observed them.
+type DecayPolicy struct {
+	Kind string
+	// TTL is when a linear edge reaches zero.
+	TTL time.Duration
+	// HalfLife halves an exponential edge.
+	HalfLife time.Duration
+	// Buffer ends an event-anchored opportunity this long before the event.
+	Buffer time.Duration
+}
+
+// DecayPoliciesFromConfig maps config policies by decay type.
+func DecayPoliciesFromConfig(cfg map[string]config.DecayPolicyConfig) map[string]DecayPolicy {
+	out := make(map[string]DecayPolicy, len(cfg))
+	for decayType, p := range cfg {
+		out[strings.ToLower(strings.TrimSpace(decayType))] = DecayPolicy{
+			Kind:     strings.ToLower(strings.TrimSpace(p.Kind)),
+			TTL:      p.TTL,
+			HalfLife: p.HalfLife,
+			Buffer:   p.Buffer,
+		}
+	}
+	return out
+}
+
+// Decays reports whether the policy changes anything over time. An
+// event-anchored policy needs an anchor.
+func (p DecayPolicy) Decays(anchor *time.Time) bool {
+	switch p.Kind {
+	case DecayKindLinear:
+		return p.TTL > 0
+	case DecayKindExponential:
+		return p.HalfLife > 0
+	case DecayKindEventAnchored:
+		return anchor != nil
+	default:
+		return false
+	}
+}
+
+// Factor is the share of edge and confidence left at now, in [0,1].
+func (p DecayPolicy) Factor(observedAt, now time.Time, anchor *time.Time) float64 {
+	if !p.Decays(anchor) {
+		return 1
+	}
+	age := now.Sub(observedAt)
+	if age <= 0 {
+		return 1
+	}
+	switch p.Kind {
+	case DecayKindLinear:
+		return math.Max(0, 1-float64(age)/float64(p.TTL))
+	case DecayKindExponential:
+		return math.Pow(0.5, float64(age)/float64(p.HalfLife))
+	case DecayKindEventAnchored:
+		// Linear from the observation to Buffer before the event.
+		span := anchor.Add(-p.Buffer).Sub(observedAt)
+		if span <= 0 {
+			return 0
+		}
+		return math.Max(0, 1-float64(age)/float64(span))
+	}
+	return 1
+}
+
+// Expiry is when Factor falls to minFactor. Exponential decay needs a
+// positive minFactor to ever expire.
+func (p DecayPolicy) Expiry(observedAt time.Time, anchor *time.Time, minFactor float64) (time.Time, bool) {
+	if !p.Decays(anchor) {
+		return time.Time{}, false
+	}
+	minFactor = math.Min(math.Max(minFactor, 0), 1)
+	switch p.Kind {
+	case DecayKindLinear:
+		return observedAt.Add(time.Duration((1 - minFactor) * float64(p.TTL))), true
+	case DecayKindExponential:
+		if minFactor <= 0 {
+			return time.Time{}, false
+		}
+		halves := math.Log2(1 / minFactor)
+		return observedAt.Add(time.Duration(halves * float64(p.HalfLife))), true
+	case DecayKindEventAnchored:
+		end := anchor.Add(-p.Buffer)
+		return observedAt.Add(time.Duration((1 - minFactor) * float64(end.Sub(observedAt)))), true
+	}
+	return time.Time{}, false
+}
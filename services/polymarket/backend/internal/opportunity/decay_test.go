@@ -0,0 +1,50 @@
+package opportunity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecayPolicy_Factor(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	linear := DecayPolicy{Kind: DecayKindLinear, TTL: 30 * time.Minute}
+	if got := linear.Factor(at, at.Add(15*time.Minute), nil); got != 0.5 {
+		t.Fatalf("linear=%v", got)
+	}
+	if got := linear.Factor(at, at.Add(time.Hour), nil); got != 0 {
+		t.Fatalf("linear past ttl=%v", got)
+	}
+	exp := DecayPolicy{Kind: DecayKindExponential, HalfLife: 10 * time.Minute}
+	if got := exp.Factor(at, at.Add(20*time.Minute), nil); got != 0.25 {
+		t.Fatalf("exponential=%v", got)
+	}
+	anchor := at.Add(65 * time.Minute)
+	event := DecayPolicy{Kind: DecayKindEventAnchored, Buffer: 5 * time.Minute}
+	if got := event.Factor(at, at.Add(30*time.Minute), &anchor); got != 0.5 {
+		t.Fatalf("event=%v", got)
+	}
+	// Without an anchor an event-anchored policy leaves the edge alone.
+	if got := event.Factor(at, at.Add(30*time.Minute), nil); got != 1 {
+		t.Fatalf("unanchored=%v", got)
+	}
+}
+
+func TestDecayPolicy_Expiry(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if got, ok := (DecayPolicy{Kind: DecayKindLinear, TTL: 40 * time.Minute}).Expiry(at, nil, 0.25); !ok || !got.Equal(at.Add(30*time.Minute)) {
+		t.Fatalf("linear=%v ok=%v", got, ok)
+	}
+	if got, ok := (DecayPolicy{Kind: DecayKindExponential, HalfLife: 10 * time.Minute}).Expiry(at, nil, 0.25); !ok || !got.Equal(at.Add(20*time.Minute)) {
+		t.Fatalf("exponential=%v ok=%v", got, ok)
+	}
+	if _, ok := (DecayPolicy{Kind: DecayKindExponential, HalfLife: 10 * time.Minute}).Expiry(at, nil, 0); ok {
+		t.Fatalf("exponential decay never reaches zero")
+	}
+	anchor := at.Add(65 * time.Minute)
+	if got, ok := (DecayPolicy{Kind: DecayKindEventAnchored, Buffer: 5 * time.Minute}).Expiry(at, &anchor, 0); !ok || !got.Equal(at.Add(time.Hour)) {
+		t.Fatalf("event=%v ok=%v", got, ok)
+	}
+	if _, ok := (DecayPolicy{Kind: DecayKindNone}).Expiry(at, nil, 0.25); ok {
+		t.Fatalf("none must keep the strategy expiry")
+	}
+}
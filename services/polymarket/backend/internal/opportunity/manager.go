@@ -2,8 +2,11 @@ package opportunity
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"github.com/shopspring/decimal"
+
 	"go.uber.org/zap"
 
 	"polymarket/internal/models"
@@ -16,12 +19,19 @@ type Manager struct {
 	Logger *zap.Logger
 
 	MaxActive int
+
+	// Decay maps an opportunity decay_type to its policy; types without a
+	// policy keep the expiry the strategy set. MinDecayFactor expires an
+	// opportunity once its decayed edge falls to that share of the original.
+	Decay          map[string]DecayPolicy
+	MinDecayFactor float64
 }
 
 func (m *Manager) Upsert(ctx context.Context, opp *models.Opportunity) error {
 	if m == nil || m.Repo == nil || opp == nil {
 		return nil
 	}
+	m.observe(ctx, opp, time.Now().UTC())
 	if err := m.Repo.UpsertActiveOpportunity(ctx, opp); err != nil {
 		return err
 	}
@@ -64,3 +74,157 @@ func (m *Manager) enforceMax(ctx context.Context) {
 		m.Logger.Info("expired old opportunities to enforce max", zap.Int("expired", len(ids)), zap.Int("max_active", m.MaxActive))
 	}
 }
+
+// observe resets the decay of a freshly evaluated opportunity: the reported
+// edge is current again, and the expiry moves out to where the policy says
+// the edge will have faded. Re-emitting an opportunity extends its life.
+func (m *Manager) observe(ctx context.Context, opp *models.Opportunity, now time.Time) {
+	policy, ok := m.policy(opp.DecayType)
+	if !ok {
+		return
+	}
+	opp.EdgeObservedAt = &now
+	edge, conf := opp.EdgePct, opp.Confidence
+	opp.DecayedEdgePct, opp.DecayedConfidence = &edge, &conf
+	if policy.Kind == DecayKindEventAnchored && opp.AnchorAt == nil {
+		opp.AnchorAt = m.eventEnd(ctx, opp)
+	}
+	if expiry, ok := policy.Expiry(now, opp.AnchorAt, m.MinDecayFactor); ok {
+		opp.ExpiresAt = &expiry
+	}
+}
+
+// Rescore applies decay to every active opportunity as of now. Opportunities
+// whose edge has faded to MinDecayFactor are expired. It returns the number
+// re-scored and expired.
+func (m *Manager) Rescore(ctx context.Context, now time.Time) (int, int, error) {
+	if m == nil || m.Repo == nil || len(m.Decay) == 0 {
+		return 0, 0, nil
+	}
+	active := "active"
+	scored := 0
+	var expire []uint64
+	for offset := 0; ; offset += 500 {
+		items, err := m.Repo.ListOpportunities(ctx, repository.ListOpportunitiesParams{
+			Status:  &active,
+			Limit:   500,
+			Offset:  offset,
+			OrderBy: "created_at",
+			Asc:     boolPtr(true),
+		})
+		if err != nil {
+			return scored, 0, err
+		}
+		for _, opp := range items {
+			policy, ok := m.policy(opp.DecayType)
+			if !ok {
+				continue
+			}
+			observedAt := opp.CreatedAt
+			if opp.EdgeObservedAt != nil {
+				observedAt = *opp.EdgeObservedAt
+			}
+			anchor := opp.AnchorAt
+			if policy.Kind == DecayKindEventAnchored && anchor == nil {
+				anchor = m.eventEnd(ctx, &opp)
+			}
+			if !policy.Decays(anchor) {
+				continue
+			}
+			factor := policy.Factor(observedAt, now, anchor)
+			if factor <= m.MinDecayFactor {
+				expire = append(expire, opp.ID)
+				continue
+			}
+			decay := repository.OpportunityDecay{
+				DecayedEdgePct:    opp.EdgePct.Mul(decimal.NewFromFloat(factor)),
+				DecayedConfidence: opp.Confidence * factor,
+				ExpiresAt:         opp.ExpiresAt,
+				AnchorAt:          anchor,
+			}
+			if expiry, ok := policy.Expiry(observedAt, anchor, m.MinDecayFactor); ok {
+				decay.ExpiresAt = &expiry
+			}
+			if err := m.Repo.UpdateOpportunityDecay(ctx, opp.ID, decay); err != nil {
+				return scored, 0, err
+			}
+			scored++
+		}
+		if len(items) < 500 {
+			break
+		}
+	}
+	if len(expire) > 0 {
+		if _, err := m.Repo.BulkUpdateOpportunityStatus(ctx, expire, "expired"); err != nil {
+			return scored, 0, err
+		}
+		paas.LogBestEffortCtx(ctx, "polymarket_opportunities_decayed", "info", map[string]any{
+			"expired":    len(expire),
+			"min_factor": m.MinDecayFactor,
+		})
+	}
+	return scored, len(expire), nil
+}
+
+// Run re-scores active opportunities every interval.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) error {
+	if m == nil || m.Repo == nil || len(m.Decay) == 0 {
+		return nil
+	}
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			scored, expired, err := m.Rescore(ctx, time.Now().UTC())
+			if err != nil {
+				if m.Logger != nil {
+					m.Logger.Warn("opportunity rescore failed", zap.Error(err))
+				}
+				continue
+			}
+			if expired > 0 && m.Logger != nil {
+				m.Logger.Info("expired decayed opportunities", zap.Int("expired", expired), zap.Int("scored", scored))
+			}
+		}
+	}
+}
+
+func (m *Manager) policy(decayType string) (DecayPolicy, bool) {
+	p, ok := m.Decay[strings.ToLower(strings.TrimSpace(decayType))]
+	if !ok || p.Kind == "" || p.Kind == DecayKindNone {
+		return DecayPolicy{}, false
+	}
+	return p, true
+}
+
+// eventEnd is the end time of the opportunity's event, falling back to the
+// event of its primary market.
+func (m *Manager) eventEnd(ctx context.Context, opp *models.Opportunity) *time.Time {
+	eventID := ""
+	if opp.EventID != nil {
+		eventID = strings.TrimSpace(*opp.EventID)
+	}
+	if eventID == "" && opp.PrimaryMarketID != nil && strings.TrimSpace(*opp.PrimaryMarketID) != "" {
+		markets, err := m.Repo.ListMarketsByIDs(ctx, []string{strings.TrimSpace(*opp.PrimaryMarketID)})
+		if err == nil && len(markets) > 0 {
+			eventID = strings.TrimSpace(markets[0].EventID)
+		}
+	}
+	if eventID == "" {
+		return nil
+	}
+	events, err := m.Repo.ListEventsByIDs(ctx, []string{eventID})
+	if err != nil || len(events) == 0 || events[0].EndTime == nil {
+		return nil
+	}
+	end := events[0].EndTime.UTC()
+	return &end
+}
+
+func boolPtr(v bool) *bool { return &v }
@@ -0,0 +1,22 @@
+package paas
+
+import (
+	"context"
+	"time"
+)
+
+// AuditEntry describes one write request seen by PaaSWriteAuditMiddleware.
+type AuditEntry struct {
+	Agent    string
+	Method   string
+	Path     string
+	Status   int
+	Duration time.Duration
+	Project  string
+	Role     string
+}
+
+// AuditSink stores audit entries locally (e.g. the hash-chained audit table).
+type AuditSink interface {
+	AppendAudit(ctx context.Context, entry AuditEntry) error
+}
@@ -1,16 +1,14 @@
 package paas
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
+
+	"polymarket/internal/paas/platformapi"
 )
 
 type Client struct {
@@ -22,16 +20,13 @@ type Client struct {
 	expiresAt time.Time
 
 	HTTP *http.Client
-}
 
-type loginResponse struct {
-	Token     string `json:"token"`
-	ExpiresAt string `json:"expires_at"`
+	// Logs, when set, makes Log asynchronous (see LogBuffer).
+	Logs *LogBuffer
 }
 
 func (c *Client) Login(ctx context.Context) error {
-	base := strings.TrimRight(strings.TrimSpace(c.BaseURL), "/")
-	if base == "" {
+	if strings.TrimSpace(c.BaseURL) == "" {
 		return errors.New("paas base url is empty")
 	}
 	apiKey := strings.TrimSpace(c.APIKey)
@@ -39,34 +34,14 @@ func (c *Client) Login(ctx context.Context) error {
 		return errors.New("paas api key is empty")
 	}
 
-	body, _ := json.Marshal(map[string]any{"api_key": apiKey})
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/api/v1/auth/login", bytes.NewReader(body))
+	lr, err := c.api().Login(ctx, platformapi.LoginRequest{APIKey: apiKey})
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient().Do(req)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("paas login http %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
-	}
-	var lr loginResponse
-	if err := json.Unmarshal(b, &lr); err != nil {
-		return err
-	}
-	exp, _ := time.Parse(time.RFC3339, strings.TrimSpace(lr.ExpiresAt))
 
 	c.mu.Lock()
 	c.token = strings.TrimSpace(lr.Token)
-	c.expiresAt = exp
+	c.expiresAt = lr.Expiry()
 	c.mu.Unlock()
 	return nil
 }
@@ -91,46 +66,86 @@ func (c *Client) EnsureToken(ctx context.Context) error {
 	return nil
 }
 
-type CreateLogRequest struct {
-	Agent      string         `json:"agent"`
-	Action     string         `json:"action"`
-	Level      string         `json:"level"`
-	Details    map[string]any `json:"details"`
-	SessionKey string         `json:"session_key"`
-	Metadata   map[string]any `json:"metadata"`
-}
+// CreateLogRequest is the platform's log body.
+type CreateLogRequest = platformapi.CreateLogRequest
 
 func (c *Client) CreateLog(ctx context.Context, req CreateLogRequest) error {
-	if err := c.EnsureToken(ctx); err != nil {
-		return err
+	_, err := c.api().CreateLog(ctx, req)
+	return err
+}
+
+// Log is the best-effort entry point for all service logs. With a LogBuffer
+// it returns immediately; otherwise it sends inline with a short timeout.
+func (c *Client) Log(req CreateLogRequest) {
+	if c == nil {
+		return
 	}
-	base := strings.TrimRight(strings.TrimSpace(c.BaseURL), "/")
-	b, err := json.Marshal(req)
-	if err != nil {
-		return err
+	if req.Agent == "" {
+		req.Agent = "polymarket-service"
 	}
-	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/api/v1/logs", bytes.NewReader(b))
-	if err != nil {
-		return err
+	if req.Metadata == nil {
+		req.Metadata = map[string]any{}
+	}
+	if c.Logs != nil {
+		c.Logs.Enqueue(req)
+		return
 	}
-	hreq.Header.Set("Content-Type", "application/json")
-	hreq.Header.Set("Authorization", "Bearer "+c.Token())
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = c.CreateLog(ctx, req)
+}
 
-	resp, err := c.httpClient().Do(hreq)
+// TokenClaims are what the platform vouches for a caller's bearer token.
+type TokenClaims struct {
+	Project   string
+	Role      string
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+// ErrInvalidToken is returned by VerifyToken for tokens the platform does
+// not accept.
+var ErrInvalidToken = errors.New("invalid token")
+
+// VerifyToken asks the platform whether a caller's token is valid and whose
+// it is. It is for callers that do not come through the gateway.
+func (c *Client) VerifyToken(ctx context.Context, token string) (TokenClaims, error) {
+	token = strings.TrimSpace(token)
+	if c == nil || token == "" {
+		return TokenClaims{}, ErrInvalidToken
+	}
+	api := &platformapi.Client{
+		BaseURL: c.BaseURL,
+		HTTP:    c.HTTP,
+		Token:   func(context.Context) (string, error) { return token, nil },
+	}
+	st, err := api.AuthStatus(ctx)
 	if err != nil {
-		return err
+		return TokenClaims{}, err
 	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bb, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
-		return fmt.Errorf("paas create log http %d: %s", resp.StatusCode, strings.TrimSpace(string(bb)))
+	if !st.Authenticated || strings.TrimSpace(st.Project) == "" {
+		return TokenClaims{}, ErrInvalidToken
 	}
-	return nil
+	out := TokenClaims{Project: st.Project, Role: st.Role, Scopes: st.Scopes}
+	if st.ExpiresAt != "" {
+		if t, err := time.Parse(time.RFC3339, st.ExpiresAt); err == nil {
+			out.ExpiresAt = t
+		}
+	}
+	return out, nil
 }
 
-func (c *Client) httpClient() *http.Client {
-	if c.HTTP != nil {
-		return c.HTTP
+// api is the typed platform client over c's transport; authenticated calls
+// log in or refresh first.
+func (c *Client) api() *platformapi.Client {
+	return &platformapi.Client{
+		BaseURL: c.BaseURL,
+		HTTP:    c.HTTP,
+		Token: func(ctx context.Context) (string, error) {
+			if err := c.EnsureToken(ctx); err != nil {
+				return "", err
+			}
+			return c.Token(), nil
+		},
 	}
-	return &http.Client{Timeout: 10 * time.Second}
 }
@@ -1,9 +1,6 @@
 package paas
 
 import (
-	"context"
-	"time"
-
 	"github.com/gin-gonic/gin"
 )
 
@@ -31,14 +28,13 @@ func LogBestEffort(c *gin.Context, action, level string, details map[string]any)
 	if p == nil {
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-	_ = p.CreateLog(ctx, CreateLogRequest{
-		Agent:      "polymarket-service",
-		Action:     action,
-		Level:      level,
-		Details:    details,
-		SessionKey: "",
-		Metadata:   map[string]any{},
+	p.Log(CreateLogRequest{
+		Agent:         "polymarket-service",
+		Action:        action,
+		Level:         level,
+		Details:       details,
+		SessionKey:    "",
+		Metadata:      map[string]any{},
+		CorrelationID: RequestIDFromGin(c),
 	})
 }
@@ -0,0 +1,269 @@
+package paas
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"polymarket/internal/paas/platformapi"
+)
+
+// GRPCAuth applies the gateway's auth to gRPC calls, which have no gateway
+// in front: it verifies the bearer token with the platform, scopes the call
+// to the token's project with PM_TENANCY_ENABLED, and puts the correlation
+// ID and PaaS client in the context. Project and role come only from the
+// verified claims, never from metadata. Unary calls are the write path:
+// they need an agent or admin token and are audited like HTTP writes;
+// streams are read-only and take a viewer, agent or admin token. Delegated
+// tokens are scoped to HTTP paths of the gateway and open neither.
+type GRPCAuth struct {
+	PaaS   *Client
+	Audit  AuditSink
+	Logger *zap.Logger
+	// Verify checks a bearer token; nil verifies with PaaS.
+	Verify func(ctx context.Context, token string) (TokenClaims, error)
+	// CacheTTL is how long a verified token is trusted before it is checked
+	// again, and so how long a revoked token may still open calls.
+	CacheTTL time.Duration
+
+	disabled bool
+	tenancy  bool
+	agent    string
+
+	mu     sync.Mutex
+	tokens map[[sha256.Size]byte]cachedClaims
+}
+
+type cachedClaims struct {
+	claims  TokenClaims
+	expires time.Time
+}
+
+// Roles that may call each kind of RPC.
+var (
+	grpcWriteRoles = []string{"agent", "admin"}
+	grpcReadRoles  = []string{"viewer", "agent", "admin"}
+)
+
+// maxCachedTokens bounds the verified token cache; it is cleared when full.
+const maxCachedTokens = 10000
+
+type grpcClaimsKey struct{}
+
+// NewGRPCAuth reads the same environment switches as the HTTP middleware.
+func NewGRPCAuth(p *Client, sink AuditSink, logger *zap.Logger) *GRPCAuth {
+	agent := strings.TrimSpace(os.Getenv("PM_PAAS_AGENT"))
+	if agent == "" {
+		agent = "polymarket-service"
+	}
+	return &GRPCAuth{
+		PaaS:     p,
+		Audit:    sink,
+		Logger:   logger,
+		disabled: strings.EqualFold(os.Getenv("PM_AUTH_DISABLED"), "true") || os.Getenv("PM_AUTH_DISABLED") == "1",
+		tenancy:  strings.EqualFold(os.Getenv("PM_TENANCY_ENABLED"), "true") || os.Getenv("PM_TENANCY_ENABLED") == "1",
+		agent:    agent,
+		CacheTTL: time.Minute,
+	}
+}
+
+// ServerOptions returns the interceptors to build a grpc.Server with.
+func (a *GRPCAuth) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(a.unary),
+		grpc.ChainStreamInterceptor(a.stream),
+	}
+}
+
+func (a *GRPCAuth) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authorize(ctx, grpcWriteRoles)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := next(ctx, req)
+	a.audit(ctx, info.FullMethod, err, time.Since(start))
+	return resp, err
+}
+
+func (a *GRPCAuth) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+	ctx, err := a.authorize(ss.Context(), grpcReadRoles)
+	if err != nil {
+		return err
+	}
+	return next(srv, &grpcStream{ServerStream: ss, ctx: ctx})
+}
+
+func (a *GRPCAuth) authorize(ctx context.Context, roles []string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := strings.TrimSpace(firstMD(md, strings.ToLower(HeaderRequestID)))
+	if !validRequestID(id) {
+		id = NewRequestID()
+	}
+	ctx = WithRequestID(ctx, id)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(HeaderRequestID), id))
+
+	if a.PaaS != nil {
+		ctx = WithClient(ctx, a.PaaS)
+	}
+	if a.disabled {
+		return ctx, nil
+	}
+	token, ok := strings.CutPrefix(strings.TrimSpace(firstMD(md, "authorization")), "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	claims, err := a.verify(ctx, strings.TrimSpace(token))
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) || platformapi.IsStatus(err, http.StatusUnauthorized) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		return nil, status.Error(codes.Unavailable, "token verification failed")
+	}
+	if !slices.Contains(roles, claims.Role) {
+		return nil, status.Errorf(codes.PermissionDenied, "needs a %s token", strings.Join(roles, ", "))
+	}
+	ctx = context.WithValue(ctx, grpcClaimsKey{}, claims)
+	if a.tenancy {
+		if tenant := TenantForProject(claims.Project); tenant != "" {
+			ctx = WithTenant(ctx, tenant)
+		}
+	}
+	return ctx, nil
+}
+
+func (a *GRPCAuth) audit(ctx context.Context, method string, callErr error, dur time.Duration) {
+	if a.PaaS == nil && a.Audit == nil {
+		return
+	}
+	code := status.Code(callErr)
+	httpStatus := HTTPStatusFromCode(code)
+	claims, _ := ctx.Value(grpcClaimsKey{}).(TokenClaims)
+	proj, role := claims.Project, claims.Role
+
+	auditCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if a.Audit != nil {
+		err := a.Audit.AppendAudit(auditCtx, AuditEntry{
+			Agent:    a.agent,
+			Method:   "GRPC",
+			Path:     method,
+			Status:   httpStatus,
+			Duration: dur,
+			Project:  proj,
+			Role:     role,
+		})
+		if err != nil && a.Logger != nil {
+			a.Logger.Warn("local audit append failed", zap.Error(err))
+		}
+	}
+	a.PaaS.Log(CreateLogRequest{
+		Agent:  a.agent,
+		Action: "polymarket_grpc_write",
+		Level:  levelFromStatus(httpStatus),
+		Details: map[string]any{
+			"method":   method,
+			"code":     code.String(),
+			"status":   httpStatus,
+			"duration": dur.String(),
+			"project":  proj,
+			"role":     role,
+		},
+		SessionKey:    "",
+		Metadata:      map[string]any{},
+		CorrelationID: RequestIDFromContext(ctx),
+	})
+}
+
+// verify checks token, serving repeats from the cache until CacheTTL or the
+// token's expiry.
+func (a *GRPCAuth) verify(ctx context.Context, token string) (TokenClaims, error) {
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+	a.mu.Lock()
+	if c, ok := a.tokens[key]; ok && now.Before(c.expires) {
+		a.mu.Unlock()
+		return c.claims, nil
+	}
+	a.mu.Unlock()
+
+	verify := a.Verify
+	if verify == nil {
+		if a.PaaS == nil {
+			return TokenClaims{}, errors.New("token verification unavailable")
+		}
+		verify = a.PaaS.VerifyToken
+	}
+	claims, err := verify(ctx, token)
+	if err != nil {
+		return TokenClaims{}, err
+	}
+	expires := now.Add(a.CacheTTL)
+	if !claims.ExpiresAt.IsZero() && claims.ExpiresAt.Before(expires) {
+		expires = claims.ExpiresAt
+	}
+	if a.CacheTTL > 0 {
+		a.mu.Lock()
+		if a.tokens == nil || len(a.tokens) >= maxCachedTokens {
+			a.tokens = map[[sha256.Size]byte]cachedClaims{}
+		}
+		a.tokens[key] = cachedClaims{claims: claims, expires: expires}
+		a.mu.Unlock()
+	}
+	return claims, nil
+}
+
+// HTTPStatusFromCode maps a gRPC code to the HTTP status the V2 API answers
+// the same failure with.
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.FailedPrecondition, codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Canceled:
+		return 499
+	}
+	return http.StatusBadGateway
+}
+
+func firstMD(md metadata.MD, key string) string {
+	if vals := md.Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// grpcStream carries the authorized context into stream handlers.
+type grpcStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *grpcStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,76 @@
+package paas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCAuthVerifiesWithPlatform(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/auth/status" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		calls.Add(1)
+		if r.Header.Get("Authorization") != "Bearer good" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"authenticated": false})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"authenticated": true, "project": "desk-a", "role": "agent"})
+	}))
+	defer srv.Close()
+
+	a := NewGRPCAuth(&Client{BaseURL: srv.URL}, nil, nil)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		claims, err := a.verify(ctx, "good")
+		if err != nil || claims.Project != "desk-a" || claims.Role != "agent" {
+			t.Fatalf("verify good = %+v, %v", claims, err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("platform called %d times, want 1 with the cache", n)
+	}
+	if _, err := a.verify(ctx, "x"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("verify bogus = %v", err)
+	}
+}
+
+func TestGRPCAuthChecksRoles(t *testing.T) {
+	a := &GRPCAuth{Verify: func(ctx context.Context, token string) (TokenClaims, error) {
+		return TokenClaims{Project: "p", Role: token}, nil
+	}}
+	cases := []struct {
+		role   string
+		roles  []string
+		wantOK bool
+	}{
+		{"viewer", grpcReadRoles, true},
+		{"agent", grpcReadRoles, true},
+		{"delegate", grpcReadRoles, false},
+		{"", grpcReadRoles, false},
+		{"viewer", grpcWriteRoles, false},
+		{"admin", grpcWriteRoles, true},
+		{"delegate", grpcWriteRoles, false},
+	}
+	for _, tc := range cases {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+tc.role))
+		_, err := a.authorize(ctx, tc.roles)
+		if (err == nil) != tc.wantOK {
+			t.Errorf("role %q with %v: err = %v", tc.role, tc.roles, err)
+		}
+		if err != nil && tc.role != "" && status.Code(err) != codes.PermissionDenied {
+			t.Errorf("role %q: code = %v", tc.role, status.Code(err))
+		}
+	}
+}
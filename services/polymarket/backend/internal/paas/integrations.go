@@ -0,0 +1,218 @@
+package paas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"polymarket/internal/paas/platformapi"
+)
+
+// QueryIntegration calls one of the platform's data integrations
+// (dexscreener, goplus, ...) through /api/v1/integrations/{provider}/query and
+// returns the provider's raw JSON.
+func (c *Client) QueryIntegration(ctx context.Context, provider, method string, params map[string]any) (json.RawMessage, error) {
+	if c == nil {
+		return nil, errors.New("paas client is nil")
+	}
+	return c.api().QueryIntegration(ctx, provider, platformapi.QueryRequest{Method: method, Params: params})
+}
+
+// IntegrationQuerier is the part of Client that TokenRiskClient needs.
+type IntegrationQuerier interface {
+	QueryIntegration(ctx context.Context, provider, method string, params map[string]any) (json.RawMessage, error)
+}
+
+// TokenRisk is the on-chain state of a project's token: its most liquid DEX
+// pair from Dexscreener and, on EVM chains, GoPlus security flags. Found is
+// false when no pair matches, which for pre-market projects is expected.
+type TokenRisk struct {
+	Query           string    `json:"query"`
+	Found           bool      `json:"found"`
+	Chain           string    `json:"chain,omitempty"`
+	Address         string    `json:"address,omitempty"`
+	Symbol          string    `json:"symbol,omitempty"`
+	Name            string    `json:"name,omitempty"`
+	LiquidityUSD    float64   `json:"liquidity_usd"`
+	FDVUSD          float64   `json:"fdv_usd"`
+	SecurityChecked bool      `json:"security_checked"`
+	Flags           []string  `json:"flags"`
+	FetchedAt       time.Time `json:"fetched_at"`
+}
+
+// goplusChainIDs maps Dexscreener chain ids to GoPlus token_security chains.
+var goplusChainIDs = map[string]string{
+	"ethereum":  "1",
+	"bsc":       "56",
+	"polygon":   "137",
+	"arbitrum":  "42161",
+	"base":      "8453",
+	"optimism":  "10",
+	"avalanche": "43114",
+}
+
+// goplusFlags are the GoPlus boolean fields reported as risk flags when "1".
+var goplusFlags = []string{
+	"is_honeypot",
+	"is_mintable",
+	"hidden_owner",
+	"owner_change_balance",
+	"cannot_sell_all",
+	"transfer_pausable",
+	"is_blacklisted",
+	"selfdestruct",
+}
+
+// highTaxRate flags buy or sell taxes above 10%.
+const highTaxRate = 0.1
+
+// TokenRiskClient looks tokens up through the platform integrations and
+// caches the results, including misses, for TTL.
+type TokenRiskClient struct {
+	Client  IntegrationQuerier
+	TTL     time.Duration
+	Timeout time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*TokenRisk
+}
+
+// Lookup returns the token risk for a project name or ticker. A Dexscreener
+// failure is returned as an error; a GoPlus failure only leaves
+// SecurityChecked false.
+func (t *TokenRiskClient) Lookup(ctx context.Context, project string) (*TokenRisk, error) {
+	if t == nil || t.Client == nil {
+		return nil, errors.New("token risk client unavailable")
+	}
+	key := strings.ToLower(strings.TrimSpace(project))
+	if key == "" {
+		return nil, errors.New("project required")
+	}
+	ttl := t.TTL
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	now := time.Now().UTC()
+	t.mu.Lock()
+	if hit, ok := t.cache[key]; ok && now.Sub(hit.FetchedAt) < ttl {
+		t.mu.Unlock()
+		return hit, nil
+	}
+	t.mu.Unlock()
+
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	out, err := t.fetch(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	out.FetchedAt = now
+
+	t.mu.Lock()
+	if t.cache == nil {
+		t.cache = map[string]*TokenRisk{}
+	}
+	t.cache[key] = out
+	t.mu.Unlock()
+	return out, nil
+}
+
+type dexSearchResponse struct {
+	Pairs []struct {
+		ChainID   string `json:"chainId"`
+		BaseToken struct {
+			Address string `json:"address"`
+			Name    string `json:"name"`
+			Symbol  string `json:"symbol"`
+		} `json:"baseToken"`
+		Liquidity struct {
+			USD float64 `json:"usd"`
+		} `json:"liquidity"`
+		FDV float64 `json:"fdv"`
+	} `json:"pairs"`
+}
+
+func (t *TokenRiskClient) fetch(ctx context.Context, query string) (*TokenRisk, error) {
+	out := &TokenRisk{Query: query, Flags: []string{}}
+	raw, err := t.Client.QueryIntegration(ctx, "dexscreener", "search", map[string]any{"q": query})
+	if err != nil {
+		return nil, err
+	}
+	var search dexSearchResponse
+	if err := json.Unmarshal(raw, &search); err != nil {
+		return nil, fmt.Errorf("decode dexscreener search: %w", err)
+	}
+	// Only pairs whose base token is the project count; search also returns
+	// pairs quoted in it and lookalikes.
+	matches := search.Pairs[:0]
+	for _, p := range search.Pairs {
+		name := strings.ToLower(strings.TrimSpace(p.BaseToken.Name))
+		symbol := strings.ToLower(strings.TrimSpace(p.BaseToken.Symbol))
+		if name == query || symbol == query {
+			matches = append(matches, p)
+		}
+	}
+	if len(matches) == 0 {
+		return out, nil
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Liquidity.USD > matches[j].Liquidity.USD })
+	best := matches[0]
+	out.Found = true
+	out.Chain = best.ChainID
+	out.Address = best.BaseToken.Address
+	out.Symbol = best.BaseToken.Symbol
+	out.Name = best.BaseToken.Name
+	out.LiquidityUSD = best.Liquidity.USD
+	out.FDVUSD = best.FDV
+
+	chainID, ok := goplusChainIDs[strings.ToLower(best.ChainID)]
+	if !ok || out.Address == "" {
+		return out, nil
+	}
+	raw, err = t.Client.QueryIntegration(ctx, "goplus", "token_security", map[string]any{
+		"chain_id":           chainID,
+		"contract_addresses": out.Address,
+	})
+	if err != nil {
+		return out, nil
+	}
+	var sec struct {
+		Result map[string]map[string]any `json:"result"`
+	}
+	if err := json.Unmarshal(raw, &sec); err != nil {
+		return out, nil
+	}
+	var fields map[string]any
+	for addr, f := range sec.Result {
+		if strings.EqualFold(addr, out.Address) {
+			fields = f
+			break
+		}
+	}
+	if fields == nil {
+		return out, nil
+	}
+	out.SecurityChecked = true
+	for _, name := range goplusFlags {
+		if s, _ := fields[name].(string); s == "1" {
+			out.Flags = append(out.Flags, name)
+		}
+	}
+	for _, name := range []string{"buy_tax", "sell_tax"} {
+		s, _ := fields[name].(string)
+		if v, err := strconv.ParseFloat(s, 64); err == nil && v > highTaxRate {
+			out.Flags = append(out.Flags, "high_"+name)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package paas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenRiskClient_LookupThroughPlatform(t *testing.T) {
+	var dexCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/auth/login":
+			_ = json.NewEncoder(w).Encode(map[string]any{"token": "tok", "expires_at": time.Now().Add(time.Hour).Format(time.RFC3339)})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch r.URL.Path {
+		case "/api/v1/integrations/dexscreener/query":
+			dexCalls.Add(1)
+			if req.Method != "search" || req.Params["q"] != "monad" {
+				t.Errorf("dexscreener request %+v", req)
+			}
+			_, _ = w.Write([]byte(`{"pairs":[
+				{"chainId":"ethereum","baseToken":{"address":"0xFAKE","name":"Monad Inu","symbol":"MONINU"},"liquidity":{"usd":9000000},"fdv":1},
+				{"chainId":"ethereum","baseToken":{"address":"0xAbC","name":"Monad","symbol":"MON"},"liquidity":{"usd":250000},"fdv":2500000000},
+				{"chainId":"solana","baseToken":{"address":"So1","name":"Monad","symbol":"MON"},"liquidity":{"usd":1000},"fdv":2400000000}
+			]}`))
+		case "/api/v1/integrations/goplus/query":
+			if req.Params["chain_id"] != "1" || !strings.EqualFold(req.Params["contract_addresses"].(string), "0xabc") {
+				t.Errorf("goplus request %+v", req)
+			}
+			_, _ = w.Write([]byte(`{"code":1,"result":{"0xabc":{"is_honeypot":"0","is_mintable":"1","sell_tax":"0.25","buy_tax":"0"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tr := &TokenRiskClient{Client: &Client{BaseURL: srv.URL, APIKey: "k"}}
+	got, err := tr.Lookup(context.Background(), " Monad ")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if !got.Found || got.Chain != "ethereum" || got.Symbol != "MON" || got.FDVUSD != 2.5e9 || got.LiquidityUSD != 250000 {
+		t.Fatalf("unexpected pair: %+v", got)
+	}
+	if !got.SecurityChecked || strings.Join(got.Flags, ",") != "is_mintable,high_sell_tax" {
+		t.Fatalf("flags: %+v", got)
+	}
+
+	// Cached until TTL.
+	if _, err := tr.Lookup(context.Background(), "MONAD"); err != nil || dexCalls.Load() != 1 {
+		t.Fatalf("expected cached lookup, calls=%d err=%v", dexCalls.Load(), err)
+	}
+}
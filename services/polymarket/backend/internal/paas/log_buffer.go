@@ -0,0 +1,373 @@
+package paas
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LogBufferOptions tunes the async log pipeline; zero values use defaults.
+type LogBufferOptions struct {
+	// Capacity bounds the in-memory queue; overflow goes to the spill file.
+	Capacity      int
+	BatchSize     int
+	FlushInterval time.Duration
+	// SendTimeout bounds each CreateLog call.
+	SendTimeout time.Duration
+	MaxBackoff  time.Duration
+	// SpillPath is a JSONL file holding logs that could not be delivered.
+	// Empty disables spillover: undeliverable logs are dropped.
+	SpillPath     string
+	SpillMaxBytes int64
+}
+
+// LogBuffer delivers PaaS logs off the request path. Logs are queued in
+// memory and sent in batches; when the PaaS is unreachable they are appended
+// to a local spill file and replayed, oldest first, once delivery recovers.
+type LogBuffer struct {
+	client *Client
+	opts   LogBufferOptions
+	logger *zap.Logger
+	send   func(ctx context.Context, req CreateLogRequest) error
+
+	queue   chan CreateLogRequest
+	stopped chan struct{}
+
+	// spillMu guards the spill file and pending, its line count.
+	spillMu sync.Mutex
+	pending int
+
+	sent    atomic.Int64
+	failed  atomic.Int64
+	spilled atomic.Int64
+	dropped atomic.Int64
+	down    atomic.Bool
+}
+
+// LogBufferStats is exposed for health endpoints.
+type LogBufferStats struct {
+	Queued       int   `json:"queued"`
+	Sent         int64 `json:"sent"`
+	Failed       int64 `json:"failed"`
+	Spilled      int64 `json:"spilled"`
+	Dropped      int64 `json:"dropped"`
+	SpillPending int   `json:"spill_pending"`
+	Degraded     bool  `json:"degraded"`
+}
+
+func NewLogBuffer(client *Client, opts LogBufferOptions, logger *zap.Logger) *LogBuffer {
+	if opts.Capacity <= 0 {
+		opts.Capacity = 1000
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 50
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 2 * time.Second
+	}
+	if opts.SendTimeout <= 0 {
+		opts.SendTimeout = 5 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Minute
+	}
+	if opts.SpillMaxBytes <= 0 {
+		opts.SpillMaxBytes = 50 << 20
+	}
+	b := &LogBuffer{
+		client:  client,
+		opts:    opts,
+		logger:  logger,
+		queue:   make(chan CreateLogRequest, opts.Capacity),
+		stopped: make(chan struct{}),
+	}
+	if client != nil {
+		b.send = client.CreateLog
+	}
+	if opts.SpillPath != "" {
+		// Pick up logs spilled by a previous run.
+		if data, err := os.ReadFile(opts.SpillPath); err == nil {
+			b.pending = bytes.Count(data, []byte("\n"))
+		}
+	}
+	return b
+}
+
+// Enqueue never blocks: a full queue spills straight to disk.
+func (b *LogBuffer) Enqueue(req CreateLogRequest) {
+	if b == nil {
+		return
+	}
+	select {
+	case b.queue <- req:
+	default:
+		b.spill([]CreateLogRequest{req})
+	}
+}
+
+// Stopped is closed once Run has returned and pending logs were flushed or
+// spilled.
+func (b *LogBuffer) Stopped() <-chan struct{} {
+	return b.stopped
+}
+
+// Run delivers queued logs until ctx is done, then makes one last bounded
+// attempt and spills whatever is left.
+func (b *LogBuffer) Run(ctx context.Context) {
+	defer close(b.stopped)
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+	backoff := b.opts.FlushInterval
+	var retryAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			b.shutdown()
+			return
+		case <-ticker.C:
+		}
+		if !retryAt.IsZero() && time.Now().Before(retryAt) {
+			// Still backing off: keep the queue moving into the spill file so
+			// delivery order is preserved on recovery.
+			b.spill(b.drain(b.opts.Capacity))
+			continue
+		}
+		if err := b.flush(ctx); err != nil {
+			b.down.Store(true)
+			retryAt = time.Now().Add(backoff)
+			if b.logger != nil {
+				b.logger.Debug("paas log delivery failed; spilling", zap.Error(err), zap.Duration("retry_in", backoff))
+			}
+			backoff *= 2
+			if backoff > b.opts.MaxBackoff {
+				backoff = b.opts.MaxBackoff
+			}
+			continue
+		}
+		if b.down.Swap(false) && b.logger != nil {
+			b.logger.Info("paas log delivery recovered")
+		}
+		backoff = b.opts.FlushInterval
+		retryAt = time.Time{}
+	}
+}
+
+// maxReplayBatches bounds spill replay per tick so a large backlog cannot
+// starve the live queue indefinitely.
+const maxReplayBatches = 20
+
+// flush replays spilled logs, then drains the queue. The first failed send
+// spills everything not yet delivered.
+func (b *LogBuffer) flush(ctx context.Context) error {
+	for i := 0; i < maxReplayBatches && b.SpillPending() > 0; i++ {
+		if err := b.replaySpill(ctx); err != nil {
+			b.spill(b.drain(b.opts.Capacity))
+			return err
+		}
+	}
+	if b.SpillPending() > 0 {
+		// Keep order: new logs wait behind older spilled ones.
+		b.spill(b.drain(b.opts.Capacity))
+		return nil
+	}
+	for {
+		batch := b.drain(b.opts.BatchSize)
+		if len(batch) == 0 {
+			return nil
+		}
+		if n, err := b.sendBatch(ctx, batch); err != nil {
+			b.spill(batch[n:])
+			b.spill(b.drain(b.opts.Capacity))
+			return err
+		}
+	}
+}
+
+func (b *LogBuffer) sendBatch(ctx context.Context, batch []CreateLogRequest) (int, error) {
+	if b.send == nil {
+		return 0, errors.New("paas client unavailable")
+	}
+	for i, req := range batch {
+		sctx, cancel := context.WithTimeout(ctx, b.opts.SendTimeout)
+		err := b.send(sctx, req)
+		cancel()
+		if err != nil {
+			b.failed.Add(1)
+			return i, err
+		}
+		b.sent.Add(1)
+	}
+	return len(batch), nil
+}
+
+func (b *LogBuffer) drain(max int) []CreateLogRequest {
+	var out []CreateLogRequest
+	for len(out) < max {
+		select {
+		case req := <-b.queue:
+			out = append(out, req)
+		default:
+			return out
+		}
+	}
+	return out
+}
+
+func (b *LogBuffer) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if b.down.Load() || b.SpillPending() > 0 {
+		b.spill(b.drain(b.opts.Capacity))
+		return
+	}
+	batch := b.drain(b.opts.Capacity)
+	if n, err := b.sendBatch(ctx, batch); err != nil {
+		b.spill(batch[n:])
+	}
+}
+
+func (b *LogBuffer) spill(items []CreateLogRequest) {
+	if len(items) == 0 {
+		return
+	}
+	if b.opts.SpillPath == "" {
+		b.dropped.Add(int64(len(items)))
+		return
+	}
+	b.spillMu.Lock()
+	defer b.spillMu.Unlock()
+	if info, err := os.Stat(b.opts.SpillPath); err == nil && info.Size() >= b.opts.SpillMaxBytes {
+		b.dropped.Add(int64(len(items)))
+		return
+	}
+	if dir := filepath.Dir(b.opts.SpillPath); dir != "" {
+		_ = os.MkdirAll(dir, 0o755)
+	}
+	f, err := os.OpenFile(b.opts.SpillPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		b.dropped.Add(int64(len(items)))
+		if b.logger != nil {
+			b.logger.Warn("paas log spill failed", zap.Error(err))
+		}
+		return
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	for _, it := range items {
+		line, err := json.Marshal(it)
+		if err != nil {
+			b.dropped.Add(1)
+			continue
+		}
+		_, _ = w.Write(append(line, '\n'))
+		b.spilled.Add(1)
+		b.pending++
+	}
+	_ = w.Flush()
+}
+
+// replaySpill sends up to one batch from the head of the spill file and
+// removes the delivered lines. The lock is not held while sending; only Run
+// removes lines, so the head of the file is stable while other goroutines
+// append.
+func (b *LogBuffer) replaySpill(ctx context.Context) error {
+	if b.opts.SpillPath == "" {
+		return nil
+	}
+	b.spillMu.Lock()
+	data, err := os.ReadFile(b.opts.SpillPath)
+	if err != nil || len(data) == 0 {
+		b.pending = 0
+		b.spillMu.Unlock()
+		return nil
+	}
+	b.spillMu.Unlock()
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	batch := make([]CreateLogRequest, 0, b.opts.BatchSize)
+	consumed := 0
+	for _, line := range lines {
+		if len(batch) >= b.opts.BatchSize {
+			break
+		}
+		consumed++
+		var req CreateLogRequest
+		if len(bytes.TrimSpace(line)) == 0 || json.Unmarshal(line, &req) != nil {
+			continue
+		}
+		batch = append(batch, req)
+	}
+	n, sendErr := b.sendBatch(ctx, batch)
+	if sendErr != nil {
+		// Keep the unsent tail: skip only lines that were delivered.
+		consumed = skipDelivered(lines, n)
+	}
+	if consumed == 0 {
+		return sendErr
+	}
+
+	b.spillMu.Lock()
+	defer b.spillMu.Unlock()
+	data, err = os.ReadFile(b.opts.SpillPath)
+	if err != nil {
+		return err
+	}
+	lines = bytes.SplitAfter(data, []byte("\n"))
+	if consumed > len(lines) {
+		consumed = len(lines)
+	}
+	rest := bytes.Join(lines[consumed:], nil)
+	b.pending = bytes.Count(rest, []byte("\n"))
+	if len(rest) == 0 {
+		_ = os.Remove(b.opts.SpillPath)
+	} else if err := os.WriteFile(b.opts.SpillPath, rest, 0o600); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// skipDelivered returns how many leading lines hold the first n valid entries.
+func skipDelivered(lines [][]byte, n int) int {
+	i := 0
+	for ; i < len(lines) && n > 0; i++ {
+		var req CreateLogRequest
+		if len(bytes.TrimSpace(lines[i])) != 0 && json.Unmarshal(lines[i], &req) == nil {
+			n--
+		}
+	}
+	return i
+}
+
+// SpillPending counts logs waiting in the spill file.
+func (b *LogBuffer) SpillPending() int {
+	if b == nil {
+		return 0
+	}
+	b.spillMu.Lock()
+	defer b.spillMu.Unlock()
+	return b.pending
+}
+
+func (b *LogBuffer) Stats() LogBufferStats {
+	if b == nil {
+		return LogBufferStats{}
+	}
+	return LogBufferStats{
+		Queued:       len(b.queue),
+		Sent:         b.sent.Load(),
+		Failed:       b.failed.Load(),
+		Spilled:      b.spilled.Load(),
+		Dropped:      b.dropped.Load(),
+		SpillPending: b.SpillPending(),
+		Degraded:     b.down.Load(),
+	}
+}
@@ -0,0 +1,74 @@
+package paas
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type fakeSender struct {
+	mu   sync.Mutex
+	down bool
+	got  []string
+}
+
+func (f *fakeSender) send(ctx context.Context, req CreateLogRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return errors.New("unreachable")
+	}
+	f.got = append(f.got, req.Action)
+	return nil
+}
+
+func TestLogBuffer_SpillsWhileDownAndReplaysInOrder(t *testing.T) {
+	sender := &fakeSender{down: true}
+	b := NewLogBuffer(nil, LogBufferOptions{BatchSize: 2, SpillPath: filepath.Join(t.TempDir(), "spill.jsonl")}, nil)
+	b.send = sender.send
+	ctx := context.Background()
+
+	b.Enqueue(CreateLogRequest{Action: "a"})
+	b.Enqueue(CreateLogRequest{Action: "b"})
+	if err := b.flush(ctx); err == nil {
+		t.Fatalf("expected delivery error while down")
+	}
+	b.Enqueue(CreateLogRequest{Action: "c"})
+	// Still down: c must queue behind the spilled a and b.
+	if err := b.flush(ctx); err == nil {
+		t.Fatalf("expected delivery error while down")
+	}
+	if got := b.SpillPending(); got != 3 {
+		t.Fatalf("spill pending=%d", got)
+	}
+
+	sender.down = false
+	b.Enqueue(CreateLogRequest{Action: "d"})
+	if err := b.flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if b.SpillPending() != 0 {
+		t.Fatalf("spill not drained: %d", b.SpillPending())
+	}
+	want := []string{"a", "b", "c", "d"}
+	if len(sender.got) != len(want) {
+		t.Fatalf("got=%v", sender.got)
+	}
+	for i := range want {
+		if sender.got[i] != want[i] {
+			t.Fatalf("got=%v want=%v", sender.got, want)
+		}
+	}
+}
+
+func TestLogBuffer_DropsWithoutSpillPath(t *testing.T) {
+	b := NewLogBuffer(nil, LogBufferOptions{}, nil)
+	b.send = (&fakeSender{down: true}).send
+	b.Enqueue(CreateLogRequest{Action: "a"})
+	_ = b.flush(context.Background())
+	if st := b.Stats(); st.Dropped != 1 || st.Failed != 1 {
+		t.Fatalf("stats=%+v", st)
+	}
+}
@@ -44,8 +44,10 @@ func RequireBearerMiddleware() gin.HandlerFunc {
 	}
 }
 
-func PaaSWriteAuditMiddleware(p *Client, logger *zap.Logger) gin.HandlerFunc {
-	if p == nil {
+// PaaSWriteAuditMiddleware reports write requests to the PaaS log service and,
+// when sink is set, appends them to the local audit chain.
+func PaaSWriteAuditMiddleware(p *Client, sink AuditSink, logger *zap.Logger) gin.HandlerFunc {
+	if p == nil && sink == nil {
 		return func(c *gin.Context) { c.Next() }
 	}
 	agent := strings.TrimSpace(os.Getenv("PM_PAAS_AGENT"))
@@ -74,7 +76,21 @@ func PaaSWriteAuditMiddleware(p *Client, logger *zap.Logger) gin.HandlerFunc {
 
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
-		err := p.CreateLog(ctx, CreateLogRequest{
+		if sink != nil {
+			err := sink.AppendAudit(ctx, AuditEntry{
+				Agent:    agent,
+				Method:   method,
+				Path:     path,
+				Status:   status,
+				Duration: dur,
+				Project:  proj,
+				Role:     role,
+			})
+			if err != nil && logger != nil {
+				logger.Warn("local audit append failed", zap.Error(err))
+			}
+		}
+		p.Log(CreateLogRequest{
 			Agent:  agent,
 			Action: "polymarket_http_write",
 			Level:  levelFromStatus(status),
@@ -86,12 +102,10 @@ func PaaSWriteAuditMiddleware(p *Client, logger *zap.Logger) gin.HandlerFunc {
 				"project":  proj,
 				"role":     role,
 			},
-			SessionKey: "",
-			Metadata:   map[string]any{},
+			SessionKey:    "",
+			Metadata:      map[string]any{},
+			CorrelationID: RequestIDFromGin(c),
 		})
-		if err != nil && logger != nil {
-			logger.Debug("paas audit log failed", zap.Error(err))
-		}
 	}
 }
 
@@ -0,0 +1,28 @@
+package paas
+
+import (
+	"context"
+	"errors"
+
+	"polymarket/internal/paas/platformapi"
+)
+
+// Broadcast sends message to the channels of the service's project that
+// subscribe to event, as configured in the platform notify config.
+func (c *Client) Broadcast(ctx context.Context, event, message string) error {
+	if c == nil {
+		return errors.New("paas client unavailable")
+	}
+	_, err := c.api().NotifyBroadcast(ctx, platformapi.BroadcastRequest{Message: message, Event: event})
+	return err
+}
+
+// BroadcastCtx broadcasts through the client in ctx; without one it is a
+// no-op.
+func BroadcastCtx(ctx context.Context, event, message string) error {
+	p := ClientFromContext(ctx)
+	if p == nil {
+		return nil
+	}
+	return p.Broadcast(ctx, event, message)
+}
@@ -0,0 +1,222 @@
+// Package platformapi is a typed client for the easyweb3 gateway's own API
+// (auth, logs, notify, cache, integrations, service management), following
+// easyweb3-platform/docs/swagger.json. Every operation is declared in Routes
+// and goes through Client.do, so transport, auth and errors live in one
+// place; the tests fail when Routes and the spec disagree.
+package platformapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Route is one operation of the spec. Path placeholders are {name}.
+type Route struct {
+	Name   string
+	Method string
+	Path   string
+}
+
+var (
+	RouteHealthz          = Route{"healthz", http.MethodGet, "/healthz"}
+	RouteStatus           = Route{"status", http.MethodGet, "/status"}
+	RouteLogin            = Route{"login", http.MethodPost, "/api/v1/auth/login"}
+	RouteRefresh          = Route{"refresh", http.MethodPost, "/api/v1/auth/refresh"}
+	RouteAuthStatus       = Route{"auth status", http.MethodGet, "/api/v1/auth/status"}
+	RouteRegister         = Route{"register", http.MethodPost, "/api/v1/auth/register"}
+	RouteCreateKey        = Route{"create key", http.MethodPost, "/api/v1/auth/keys"}
+	RouteGrant            = Route{"grant", http.MethodPost, "/api/v1/auth/grants"}
+	RouteListUsers        = Route{"list users", http.MethodGet, "/api/v1/auth/users"}
+	RouteCreateDelegation = Route{"create delegation", http.MethodPost, "/api/v1/auth/delegations"}
+	RouteListDelegations  = Route{"list delegations", http.MethodGet, "/api/v1/auth/delegations"}
+	RouteRevokeDelegation = Route{"revoke delegation", http.MethodDelete, "/api/v1/auth/delegations/{id}"}
+	RouteCreateLog        = Route{"create log", http.MethodPost, "/api/v1/logs"}
+	RouteListLogs         = Route{"list logs", http.MethodGet, "/api/v1/logs"}
+	RouteGetLog           = Route{"get log", http.MethodGet, "/api/v1/logs/{id}"}
+	RouteTraceLogs        = Route{"trace logs", http.MethodGet, "/api/v1/logs/trace/{id}"}
+	RouteLogStats         = Route{"log stats", http.MethodGet, "/api/v1/logs/stats"}
+	RouteNotifySend       = Route{"notify send", http.MethodPost, "/api/v1/notify/send"}
+	RouteNotifyBroadcast  = Route{"notify broadcast", http.MethodPost, "/api/v1/notify/broadcast"}
+	RouteGetNotifyConfig  = Route{"get notify config", http.MethodGet, "/api/v1/notify/config"}
+	RoutePutNotifyConfig  = Route{"put notify config", http.MethodPut, "/api/v1/notify/config"}
+	RouteIntegration      = Route{"query", http.MethodPost, "/api/v1/integrations/{provider}/query"}
+	RouteCacheGet         = Route{"cache get", http.MethodGet, "/api/v1/cache/{key}"}
+	RouteCachePut         = Route{"cache put", http.MethodPut, "/api/v1/cache/{key}"}
+	RouteCacheDelete      = Route{"cache delete", http.MethodDelete, "/api/v1/cache/{key}"}
+	RouteListServices     = Route{"list services", http.MethodGet, "/api/v1/service/list"}
+	RouteServiceHealth    = Route{"service health", http.MethodGet, "/api/v1/service/health"}
+	RouteServiceDocs      = Route{"service docs", http.MethodGet, "/api/v1/service/docs"}
+)
+
+// Routes lists every operation the client implements.
+var Routes = []Route{
+	RouteHealthz, RouteStatus,
+	RouteLogin, RouteRefresh, RouteAuthStatus, RouteRegister, RouteCreateKey, RouteGrant, RouteListUsers,
+	RouteCreateDelegation, RouteListDelegations, RouteRevokeDelegation,
+	RouteCreateLog, RouteListLogs, RouteGetLog, RouteTraceLogs, RouteLogStats,
+	RouteNotifySend, RouteNotifyBroadcast, RouteGetNotifyConfig, RoutePutNotifyConfig,
+	RouteIntegration,
+	RouteCacheGet, RouteCachePut, RouteCacheDelete,
+	RouteListServices, RouteServiceHealth, RouteServiceDocs,
+}
+
+// Client calls the gateway at BaseURL.
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+	// Token returns the bearer token of authenticated calls. Nil, or an
+	// empty token, sends the request without Authorization.
+	Token func(ctx context.Context) (string, error)
+}
+
+// Error is a non-2xx answer. Message is the platform's error field, or the
+// body text when it is not the standard error JSON.
+type Error struct {
+	Op        string
+	Status    int
+	Message   string
+	RequestID string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("paas %s http %d: %s", e.Op, e.Status, e.Message)
+}
+
+// IsStatus reports whether err is an Error with the given HTTP status.
+func IsStatus(err error, status int) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Status == status
+}
+
+// maxResponseBytes bounds every response body read.
+const maxResponseBytes = 4 << 20
+
+// call describes one request: params fill the path placeholders in order.
+type call struct {
+	route  Route
+	op     string
+	params []string
+	query  url.Values
+	body   any
+	auth   bool
+}
+
+// do sends c and decodes a 2xx JSON body into out; out may be nil, or a
+// *json.RawMessage or *[]byte to keep the body as is.
+func (c *Client) do(ctx context.Context, req call, out any) error {
+	if c == nil {
+		return errors.New("platform client is nil")
+	}
+	base := strings.TrimRight(strings.TrimSpace(c.BaseURL), "/")
+	if base == "" {
+		return errors.New("paas base url is empty")
+	}
+	path, err := expandPath(req.route.Path, req.params)
+	if err != nil {
+		return err
+	}
+	u := base + path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+	var body io.Reader
+	if req.body != nil {
+		b, err := json.Marshal(req.body)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	hreq, err := http.NewRequestWithContext(ctx, req.route.Method, u, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		hreq.Header.Set("Content-Type", "application/json")
+	}
+	if req.auth && c.Token != nil {
+		tok, err := c.Token(ctx)
+		if err != nil {
+			return err
+		}
+		if tok = strings.TrimSpace(tok); tok != "" {
+			hreq.Header.Set("Authorization", "Bearer "+tok)
+		}
+	}
+
+	resp, err := c.httpClient().Do(hreq)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		op := req.op
+		if op == "" {
+			op = req.route.Name
+		}
+		apiErr := &Error{Op: op, Status: resp.StatusCode, Message: strings.TrimSpace(string(b))}
+		var eb struct {
+			Error     string `json:"error"`
+			RequestID string `json:"request_id"`
+		}
+		if json.Unmarshal(b, &eb) == nil && eb.Error != "" {
+			apiErr.Message, apiErr.RequestID = eb.Error, eb.RequestID
+		}
+		return apiErr
+	}
+	switch v := out.(type) {
+	case nil:
+		return nil
+	case *json.RawMessage:
+		*v = append(json.RawMessage(nil), b...)
+		return nil
+	case *[]byte:
+		*v = append([]byte(nil), b...)
+		return nil
+	}
+	return json.Unmarshal(b, out)
+}
+
+// expandPath fills the {placeholders} of path with params, escaped, in
+// order.
+func expandPath(path string, params []string) (string, error) {
+	var sb strings.Builder
+	rest := path
+	for _, p := range params {
+		open := strings.IndexByte(rest, '{')
+		end := strings.IndexByte(rest, '}')
+		if open < 0 || end < open {
+			return "", fmt.Errorf("%s: too many path params", path)
+		}
+		if strings.TrimSpace(p) == "" {
+			return "", fmt.Errorf("%s: empty %s", path, rest[open+1:end])
+		}
+		sb.WriteString(rest[:open])
+		sb.WriteString(url.PathEscape(p))
+		rest = rest[end+1:]
+	}
+	if strings.IndexByte(rest, '{') >= 0 {
+		return "", fmt.Errorf("%s: missing path params", path)
+	}
+	sb.WriteString(rest)
+	return sb.String(), nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return &http.Client{Timeout: 10 * time.Second}
+}
@@ -0,0 +1,116 @@
+package platformapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// specPath is the gateway spec in the repo checkout; the drift test skips
+// when the service is built on its own.
+const specPath = "../../../../../../easyweb3-platform/docs/swagger.json"
+
+func TestRoutesMatchPlatformSpec(t *testing.T) {
+	b, err := os.ReadFile(specPath)
+	if err != nil {
+		t.Skipf("platform spec not available: %v", err)
+	}
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		t.Fatal(err)
+	}
+	inSpec := map[string]bool{}
+	for path, ops := range spec.Paths {
+		// The service proxy forwards to business services and has no fixed
+		// shape; those services ship their own clients.
+		if strings.HasPrefix(path, "/api/v1/services/") {
+			continue
+		}
+		for method := range ops {
+			inSpec[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+	inClient := map[string]bool{}
+	for _, r := range Routes {
+		inClient[r.Method+" "+r.Path] = true
+	}
+	var missing, unknown []string
+	for k := range inSpec {
+		if !inClient[k] {
+			missing = append(missing, k)
+		}
+	}
+	for k := range inClient {
+		if !inSpec[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unknown)
+	if len(missing) > 0 {
+		t.Errorf("spec operations without a client route: %v", missing)
+	}
+	if len(unknown) > 0 {
+		t.Errorf("client routes not in the spec: %v", unknown)
+	}
+}
+
+func TestClientDo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v1/auth/login":
+			if r.Header.Get("Authorization") != "" {
+				t.Errorf("login sent a bearer token")
+			}
+			_, _ = w.Write([]byte(`{"token":"tok","expires_at":"2030-01-02T03:04:05Z"}`))
+		case "/api/v1/cache/a%2Fb":
+			if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+				t.Errorf("authorization = %q", got)
+			}
+			_, _ = w.Write([]byte(`{"key":"a/b","found":true,"value_base64":"eA=="}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found","request_id":"rid-1"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL + "/", Token: func(context.Context) (string, error) { return "tok", nil }}
+	ctx := context.Background()
+
+	tr, err := c.Login(ctx, LoginRequest{APIKey: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Token != "tok" || tr.Expiry().Year() != 2030 {
+		t.Fatalf("token = %+v", tr)
+	}
+	entry, err := c.CacheGet(ctx, "a/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entry.Found || entry.ValueBase64 != "eA==" {
+		t.Fatalf("entry = %+v", entry)
+	}
+
+	_, err = c.GetLog(ctx, "x")
+	if !IsStatus(err, http.StatusNotFound) {
+		t.Fatalf("err = %v, want 404", err)
+	}
+	if want := "paas get log http 404: not found"; err.Error() != want {
+		t.Fatalf("err = %q, want %q", err.Error(), want)
+	}
+	if e := err.(*Error); e.RequestID != "rid-1" {
+		t.Fatalf("request id = %q", e.RequestID)
+	}
+	if _, err := c.GetLog(ctx, " "); err == nil {
+		t.Fatal("empty path param accepted")
+	}
+}
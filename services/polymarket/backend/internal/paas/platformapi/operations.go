@@ -0,0 +1,256 @@
+package platformapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Healthz is the unauthenticated liveness probe.
+func (c *Client) Healthz(ctx context.Context) (map[string]string, error) {
+	var out map[string]string
+	err := c.do(ctx, call{route: RouteHealthz}, &out)
+	return out, err
+}
+
+// Status is the public service status page.
+func (c *Client) Status(ctx context.Context) (*StatusPage, error) {
+	var out StatusPage
+	if err := c.do(ctx, call{route: RouteStatus}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Login exchanges an API key or user credentials for a token. It never
+// sends a bearer token.
+func (c *Client) Login(ctx context.Context, req LoginRequest) (*TokenResponse, error) {
+	var out TokenResponse
+	if err := c.do(ctx, call{route: RouteLogin, body: req}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Refresh renews the current token.
+func (c *Client) Refresh(ctx context.Context) (*TokenResponse, error) {
+	var out TokenResponse
+	if err := c.do(ctx, call{route: RouteRefresh, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) AuthStatus(ctx context.Context) (*AuthStatus, error) {
+	var out AuthStatus
+	if err := c.do(ctx, call{route: RouteAuthStatus, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Register creates a user; the answer is the user record as served.
+func (c *Client) Register(ctx context.Context, req RegisterRequest) (json.RawMessage, error) {
+	var out json.RawMessage
+	err := c.do(ctx, call{route: RouteRegister, body: req}, &out)
+	return out, err
+}
+
+func (c *Client) CreateKey(ctx context.Context, req CreateKeyRequest) (*CreateKeyResponse, error) {
+	var out CreateKeyResponse
+	if err := c.do(ctx, call{route: RouteCreateKey, body: req, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) Grant(ctx context.Context, req GrantRequest) (json.RawMessage, error) {
+	var out json.RawMessage
+	err := c.do(ctx, call{route: RouteGrant, body: req, auth: true}, &out)
+	return out, err
+}
+
+func (c *Client) ListUsers(ctx context.Context) (json.RawMessage, error) {
+	var out json.RawMessage
+	err := c.do(ctx, call{route: RouteListUsers, auth: true}, &out)
+	return out, err
+}
+
+// CreateDelegation mints a read-only token scoped to paths of one service.
+// The token is only returned here.
+func (c *Client) CreateDelegation(ctx context.Context, req CreateDelegationRequest) (*CreateDelegationResponse, error) {
+	var out CreateDelegationResponse
+	if err := c.do(ctx, call{route: RouteCreateDelegation, body: req, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) ListDelegations(ctx context.Context) ([]DelegationItem, error) {
+	var out struct {
+		Delegations []DelegationItem `json:"delegations"`
+	}
+	if err := c.do(ctx, call{route: RouteListDelegations, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out.Delegations, nil
+}
+
+func (c *Client) RevokeDelegation(ctx context.Context, id string) (*Delegation, error) {
+	var out Delegation
+	if err := c.do(ctx, call{route: RouteRevokeDelegation, params: []string{id}, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) CreateLog(ctx context.Context, req CreateLogRequest) (*CreateLogResponse, error) {
+	var out CreateLogResponse
+	if err := c.do(ctx, call{route: RouteCreateLog, body: req, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) ListLogs(ctx context.Context, p ListLogsParams) ([]OperationLog, error) {
+	q := url.Values{}
+	setQuery(q, "action", p.Action)
+	setQuery(q, "level", p.Level)
+	setQuery(q, "correlation_id", p.CorrelationID)
+	if p.From != nil {
+		q.Set("from", p.From.UTC().Format(time.RFC3339))
+	}
+	if p.To != nil {
+		q.Set("to", p.To.UTC().Format(time.RFC3339))
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	var out []OperationLog
+	err := c.do(ctx, call{route: RouteListLogs, query: q, auth: true}, &out)
+	return out, err
+}
+
+func (c *Client) GetLog(ctx context.Context, id string) (*OperationLog, error) {
+	var out OperationLog
+	if err := c.do(ctx, call{route: RouteGetLog, params: []string{id}, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TraceLogs returns every log written under one correlation ID.
+func (c *Client) TraceLogs(ctx context.Context, correlationID string) (*LogTrace, error) {
+	var out LogTrace
+	if err := c.do(ctx, call{route: RouteTraceLogs, params: []string{correlationID}, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LogStats counts logs by level, optionally filtered.
+func (c *Client) LogStats(ctx context.Context, action, level string) (map[string]int, error) {
+	q := url.Values{}
+	setQuery(q, "action", action)
+	setQuery(q, "level", level)
+	var out map[string]int
+	err := c.do(ctx, call{route: RouteLogStats, query: q, auth: true}, &out)
+	return out, err
+}
+
+func (c *Client) NotifySend(ctx context.Context, req SendRequest) (*SendResult, error) {
+	var out SendResult
+	if err := c.do(ctx, call{route: RouteNotifySend, body: req, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) NotifyBroadcast(ctx context.Context, req BroadcastRequest) (*BroadcastResponse, error) {
+	var out BroadcastResponse
+	if err := c.do(ctx, call{route: RouteNotifyBroadcast, body: req, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) GetNotifyConfig(ctx context.Context) (*ProjectConfig, error) {
+	var out ProjectConfig
+	if err := c.do(ctx, call{route: RouteGetNotifyConfig, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) PutNotifyConfig(ctx context.Context, cfg ProjectConfig) (*ProjectConfig, error) {
+	var out ProjectConfig
+	if err := c.do(ctx, call{route: RoutePutNotifyConfig, body: cfg, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// QueryIntegration calls provider's method through the gateway. Provider
+// answers have no common shape, so the body is returned as is.
+func (c *Client) QueryIntegration(ctx context.Context, provider string, req QueryRequest) (json.RawMessage, error) {
+	var out json.RawMessage
+	err := c.do(ctx, call{
+		route:  RouteIntegration,
+		op:     provider + " " + req.Method,
+		params: []string{provider},
+		body:   req,
+		auth:   true,
+	}, &out)
+	return out, err
+}
+
+func (c *Client) CacheGet(ctx context.Context, key string) (*CacheEntry, error) {
+	var out CacheEntry
+	if err := c.do(ctx, call{route: RouteCacheGet, params: []string{key}, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) CachePut(ctx context.Context, key string, req CachePutRequest) error {
+	return c.do(ctx, call{route: RouteCachePut, params: []string{key}, body: req, auth: true}, nil)
+}
+
+func (c *Client) CacheDelete(ctx context.Context, key string) error {
+	return c.do(ctx, call{route: RouteCacheDelete, params: []string{key}, auth: true}, nil)
+}
+
+func (c *Client) ListServices(ctx context.Context) ([]ServiceInfo, error) {
+	var out []ServiceInfo
+	err := c.do(ctx, call{route: RouteListServices, auth: true}, &out)
+	return out, err
+}
+
+// ServiceHealth probes name's health path through the gateway; a down
+// service is a successful call with Status "down".
+func (c *Client) ServiceHealth(ctx context.Context, name string) (*ServiceHealth, error) {
+	var out ServiceHealth
+	q := url.Values{"name": {name}}
+	if err := c.do(ctx, call{route: RouteServiceHealth, query: q, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ServiceDocs returns name's markdown docs.
+func (c *Client) ServiceDocs(ctx context.Context, name string) (string, error) {
+	var out []byte
+	q := url.Values{"name": {name}}
+	if err := c.do(ctx, call{route: RouteServiceDocs, query: q, auth: true}, &out); err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func setQuery(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
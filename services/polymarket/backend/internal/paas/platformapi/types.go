@@ -0,0 +1,253 @@
+package platformapi
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// The types below mirror the definitions of the platform spec; the spec
+// name is noted where it differs.
+
+// LoginRequest takes an API key, or a username and password with the
+// project to log into (auth.loginRequest).
+type LoginRequest struct {
+	APIKey    string `json:"api_key,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Password  string `json:"password,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+}
+
+// TokenResponse is auth.tokenResponse. ExpiresAt is RFC3339.
+type TokenResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// Expiry parses ExpiresAt; zero when absent or malformed.
+func (t TokenResponse) Expiry() time.Time {
+	exp, _ := time.Parse(time.RFC3339, t.ExpiresAt)
+	return exp
+}
+
+// AuthStatus is auth.statusResponse.
+type AuthStatus struct {
+	Authenticated bool   `json:"authenticated"`
+	Project       string `json:"project,omitempty"`
+	Role          string `json:"role,omitempty"`
+	ExpiresAt     string `json:"expires_at,omitempty"`
+	// Scopes are set for delegated tokens only.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+type RegisterRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type CreateKeyRequest struct {
+	ProjectID string `json:"project_id"`
+	Role      string `json:"role"`
+	Name      string `json:"name"`
+}
+
+// CreateKeyResponse carries the raw key once; Key describes it.
+type CreateKeyResponse struct {
+	APIKey string          `json:"api_key"`
+	Key    json.RawMessage `json:"key"`
+}
+
+// GrantRequest gives User (id or username) Role on ProjectID.
+type GrantRequest struct {
+	User      string `json:"user"`
+	ProjectID string `json:"project_id"`
+	Role      string `json:"role"`
+}
+
+// CreateDelegationRequest is auth.createDelegationRequest; TTL is a Go
+// duration such as "72h".
+type CreateDelegationRequest struct {
+	Name      string   `json:"name,omitempty"`
+	ProjectID string   `json:"project_id"`
+	Service   string   `json:"service"`
+	Paths     []string `json:"paths"`
+	TTL       string   `json:"ttl"`
+}
+
+// CreateDelegationResponse carries the delegated token once.
+type CreateDelegationResponse struct {
+	Token      string     `json:"token"`
+	ExpiresAt  string     `json:"expires_at"`
+	Delegation Delegation `json:"delegation"`
+}
+
+// Delegation is auth.Delegation.
+type Delegation struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	ProjectID string     `json:"project_id"`
+	Service   string     `json:"service"`
+	Paths     []string   `json:"paths"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// DelegationItem is auth.delegationItem: a delegation and whether it is
+// still usable.
+type DelegationItem struct {
+	Delegation
+	Active bool `json:"active"`
+}
+
+type CreateLogRequest struct {
+	Agent      string         `json:"agent"`
+	Action     string         `json:"action"`
+	Level      string         `json:"level"`
+	Details    map[string]any `json:"details"`
+	SessionKey string         `json:"session_key"`
+	Metadata   map[string]any `json:"metadata"`
+	// CorrelationID links the log to the request that caused it; the
+	// platform serves every log of one ID at /api/v1/logs/trace/{id}.
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+type CreateLogResponse struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at"`
+}
+
+// OperationLog is a stored log; ProjectID is "project" on the wire.
+type OperationLog struct {
+	ID            string          `json:"id"`
+	ProjectID     string          `json:"project"`
+	Agent         string          `json:"agent"`
+	Action        string          `json:"action"`
+	Level         string          `json:"level"`
+	Details       json.RawMessage `json:"details"`
+	SessionKey    string          `json:"session_key"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Metadata      json.RawMessage `json:"metadata"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+}
+
+// ListLogsParams filters GET /api/v1/logs; zero values are omitted.
+type ListLogsParams struct {
+	Action        string
+	Level         string
+	CorrelationID string
+	From          *time.Time
+	To            *time.Time
+	Limit         int
+}
+
+type LogTrace struct {
+	CorrelationID string         `json:"correlation_id"`
+	Logs          []OperationLog `json:"logs"`
+}
+
+// SendRequest is notification.sendRequest; Channel is webhook or telegram.
+type SendRequest struct {
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+	Message string `json:"message"`
+	Event   string `json:"event"`
+}
+
+// SendResult reports delivery; the call itself succeeds either way.
+type SendResult struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type BroadcastRequest struct {
+	Message string `json:"message"`
+	Event   string `json:"event"`
+}
+
+type BroadcastItem struct {
+	Channel string `json:"channel"`
+	Target  string `json:"target"`
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BroadcastResponse struct {
+	Project string          `json:"project"`
+	Event   string          `json:"event"`
+	Items   []BroadcastItem `json:"items"`
+}
+
+type ChannelConfig struct {
+	Type     string   `json:"type"`
+	Events   []string `json:"events"`
+	BotToken string   `json:"bot_token,omitempty"`
+	ChatID   string   `json:"chat_id,omitempty"`
+	URL      string   `json:"url,omitempty"`
+}
+
+type ProjectConfig struct {
+	Project  string          `json:"project"`
+	Channels []ChannelConfig `json:"channels"`
+}
+
+// QueryRequest is integration.QueryRequest.
+type QueryRequest struct {
+	Method string         `json:"method"`
+	Params map[string]any `json:"params"`
+}
+
+// CacheEntry is cache.getResponse; the value is base64 on the wire.
+type CacheEntry struct {
+	Key         string `json:"key"`
+	Found       bool   `json:"found"`
+	ValueBase64 string `json:"value_base64,omitempty"`
+}
+
+// CachePutRequest sets Value (any JSON) or ValueBase64. TTLSeconds 0 uses
+// the platform default and a negative value never expires.
+type CachePutRequest struct {
+	Value       any    `json:"value,omitempty"`
+	ValueBase64 string `json:"value_base64,omitempty"`
+	TTLSeconds  int64  `json:"ttl_seconds,omitempty"`
+}
+
+type ServiceInfo struct {
+	Name       string `json:"name"`
+	BaseURL    string `json:"base_url"`
+	HealthPath string `json:"health_path"`
+	DocsPath   string `json:"docs_path"`
+}
+
+type ServiceHealth struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type Probe struct {
+	At         time.Time `json:"at"`
+	Status     string    `json:"status"`
+	LatencyMS  int64     `json:"latency_ms"`
+	HTTPStatus int       `json:"http_status,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type ServiceStatus struct {
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	Since        time.Time `json:"since"`
+	LastProbe    *Probe    `json:"last_probe,omitempty"`
+	Availability float64   `json:"availability"`
+	AvgLatencyMS int64     `json:"avg_latency_ms"`
+	Probes       int       `json:"probes"`
+	History      []Probe   `json:"history"`
+}
+
+// StatusPage is the public /status answer (service.statusPage).
+type StatusPage struct {
+	Status          string          `json:"status"`
+	IntervalSeconds int             `json:"interval_seconds"`
+	Services        []ServiceStatus `json:"services"`
+}
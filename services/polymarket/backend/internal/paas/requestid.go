@@ -0,0 +1,97 @@
+package paas
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// HeaderRequestID carries the correlation ID. The platform gateway sets it
+// on every proxied request; direct callers may set their own.
+const HeaderRequestID = "X-Request-Id"
+
+const requestIDCtxKey ctxKey = 3
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, requestIDCtxKey, id)
+}
+
+// RequestIDFromContext returns the correlation ID of the request, or "" for
+// background work.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	v, _ := ctx.Value(requestIDCtxKey).(string)
+	return v
+}
+
+func RequestIDFromGin(c *gin.Context) string {
+	if c == nil || c.Request == nil {
+		return ""
+	}
+	return RequestIDFromContext(c.Request.Context())
+}
+
+// NewRequestID returns a random 32-char hex ID.
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID bounds IDs taken from headers so they are safe to log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestIDMiddleware adopts the gateway's correlation ID (or generates one),
+// stores it in the request context for audit logs and error responses,
+// echoes it in the response header and logs API requests with it.
+// Register it first so every later middleware sees the ID.
+func RequestIDMiddleware(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := strings.TrimSpace(c.GetHeader(HeaderRequestID))
+		if !validRequestID(id) {
+			id = NewRequestID()
+		}
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+		c.Header(HeaderRequestID, id)
+
+		start := time.Now()
+		c.Next()
+
+		if logger == nil || !strings.HasPrefix(c.Request.URL.Path, "/api/") {
+			return
+		}
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("request_id", id),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("duration", time.Since(start)),
+		}
+		if status >= 500 {
+			logger.Warn("http request failed", fields...)
+			return
+		}
+		logger.Debug("http request", fields...)
+	}
+}
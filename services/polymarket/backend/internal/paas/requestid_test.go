@@ -0,0 +1,41 @@
+package paas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestIDMiddleware_AdoptsOrGenerates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestIDMiddleware(nil))
+	var seen string
+	r.GET("/api/x", func(c *gin.Context) { seen = RequestIDFromGin(c) })
+
+	cases := []struct {
+		header string
+		keep   bool
+	}{
+		{header: "gw-abc_123.4", keep: true},
+		{header: "", keep: false},
+		{header: "bad id\nspoof", keep: false},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/api/x", nil)
+		if tc.header != "" {
+			req.Header.Set(HeaderRequestID, tc.header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		got := w.Header().Get(HeaderRequestID)
+		if got == "" || got != seen {
+			t.Fatalf("header %q: response id %q, context id %q", tc.header, got, seen)
+		}
+		if tc.keep != (got == tc.header) {
+			t.Fatalf("header %q: got %q", tc.header, got)
+		}
+	}
+}
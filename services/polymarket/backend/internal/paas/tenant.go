@@ -0,0 +1,83 @@
+package paas
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultTenant owns rows created before tenancy and rows created by
+// background jobs without a desk.
+const DefaultTenant = "default"
+
+const tenantCtxKey ctxKey = 2
+
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, tenantCtxKey, NormalizeTenant(tenant))
+}
+
+// TenantFromContext returns the desk the request is scoped to, or "" when the
+// request is unscoped (tenancy disabled, internal jobs).
+func TenantFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	v, _ := ctx.Value(tenantCtxKey).(string)
+	return v
+}
+
+// TenantOrDefault is used when stamping new rows.
+func TenantOrDefault(ctx context.Context) string {
+	if t := TenantFromContext(ctx); t != "" {
+		return t
+	}
+	return DefaultTenant
+}
+
+func NormalizeTenant(tenant string) string {
+	return strings.ToLower(strings.TrimSpace(tenant))
+}
+
+// TenantForProject maps a token's project to the desk it is scoped to.
+// PM_TENANCY_DEFAULT_PROJECT names the project that owns the DefaultTenant
+// rows, i.e. everything written before tenancy was turned on, so that
+// desk's tokens keep seeing its history.
+func TenantForProject(project string) string {
+	tenant := NormalizeTenant(project)
+	if tenant != "" && tenant == NormalizeTenant(os.Getenv("PM_TENANCY_DEFAULT_PROJECT")) {
+		return DefaultTenant
+	}
+	return tenant
+}
+
+// TenantMiddleware scopes requests to the project carried by the PaaS token.
+// The gateway drops any client-sent X-Easyweb3-Project and sets it from the
+// verified token claims, so the header is only trusted behind the gateway.
+// Scoping is opt-in via PM_TENANCY_ENABLED; see TenantForProject for the
+// project that owns the pre-tenancy rows.
+func TenantMiddleware() gin.HandlerFunc {
+	enabled := strings.EqualFold(os.Getenv("PM_TENANCY_ENABLED"), "true") || os.Getenv("PM_TENANCY_ENABLED") == "1"
+	return func(c *gin.Context) {
+		if !enabled || c.Request == nil {
+			c.Next()
+			return
+		}
+		tenant := TenantForProject(c.GetHeader("X-Easyweb3-Project"))
+		if tenant != "" {
+			c.Request = c.Request.WithContext(WithTenant(c.Request.Context(), tenant))
+		}
+		c.Next()
+	}
+}
+
+func TenantFromGin(c *gin.Context) string {
+	if c == nil || c.Request == nil {
+		return ""
+	}
+	return TenantFromContext(c.Request.Context())
+}
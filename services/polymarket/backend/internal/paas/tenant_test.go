@@ -0,0 +1,17 @@
+package paas
+
+import "testing"
+
+func TestTenantForProject(t *testing.T) {
+	t.Setenv("PM_TENANCY_DEFAULT_PROJECT", "Main-Desk")
+	for project, want := range map[string]string{
+		"main-desk":  DefaultTenant,
+		" MAIN-DESK": DefaultTenant,
+		"desk-a":     "desk-a",
+		"":           "",
+	} {
+		if got := TenantForProject(project); got != want {
+			t.Errorf("TenantForProject(%q) = %q, want %q", project, got, want)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+// Package regime classifies market categories (politics, crypto, sports, ...)
+// into trading regimes from the momentum and volatility of their markets'
+// candles and the flow of settlements. Every run stores one row per category,
+// so the newest row is the current regime and the rest its history. The risk
+// filter uses the current regime to tighten thresholds per regime and
+// strategy.
+package regime
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// Regimes.
+const (
+	Trending   = "trending"
+	Choppy     = "choppy"
+	NewsDriven = "news_driven"
+	// Unknown is stored when no market of the category had enough candles.
+	Unknown = "unknown"
+)
+
+// Indices are the aggregate measures a category is classified from.
+type Indices struct {
+	Markets     int
+	Momentum    float64
+	Volatility  float64
+	Efficiency  float64
+	JumpShare   float64
+	Settlements int
+}
+
+// Measure aggregates the close series of a category's markets: Momentum is
+// the mean absolute net move, Volatility the mean path length, Efficiency
+// total net move over total path length, and JumpShare the share of markets
+// with a one-minute move of at least jump.
+func Measure(series [][]float64, jump float64) Indices {
+	var idx Indices
+	var net, path float64
+	jumps := 0
+	for _, closes := range series {
+		if len(closes) < 2 {
+			continue
+		}
+		idx.Markets++
+		net += math.Abs(closes[len(closes)-1] - closes[0])
+		jumped := false
+		for i := 1; i < len(closes); i++ {
+			move := math.Abs(closes[i] - closes[i-1])
+			path += move
+			if jump > 0 && move >= jump {
+				jumped = true
+			}
+		}
+		if jumped {
+			jumps++
+		}
+	}
+	if idx.Markets == 0 {
+		return idx
+	}
+	n := float64(idx.Markets)
+	idx.Momentum = net / n
+	idx.Volatility = path / n
+	if path > 0 {
+		idx.Efficiency = net / path
+	}
+	idx.JumpShare = float64(jumps) / n
+	return idx
+}
+
+// Classify picks the regime of idx under cfg's thresholds.
+func Classify(idx Indices, cfg config.RegimeConfig) string {
+	if idx.Markets == 0 {
+		return Unknown
+	}
+	if cfg.NewsJumpShare > 0 && idx.JumpShare >= cfg.NewsJumpShare {
+		return NewsDriven
+	}
+	if cfg.NewsSettlements > 0 && idx.Settlements >= cfg.NewsSettlements {
+		return NewsDriven
+	}
+	if idx.Efficiency >= cfg.TrendEfficiency && idx.Momentum >= cfg.TrendMomentum {
+		return Trending
+	}
+	return Choppy
+}
+
+// Detector computes and serves category regimes. A nil or disabled Detector
+// reports no regimes.
+type Detector struct {
+	Config config.RegimeConfig
+	Repo   repository.Repository
+	Logger *zap.Logger
+
+	mu      sync.RWMutex
+	loaded  bool
+	current map[string]models.MarketRegime
+	// marketCategory caches each looked-up market's category ("" for none);
+	// it is dropped on every run so retagged events are picked up.
+	marketCategory map[string]string
+}
+
+func (d *Detector) enabled() bool {
+	return d != nil && d.Repo != nil && d.Config.Enabled
+}
+
+// Categories returns the configured categories, normalized.
+func (d *Detector) Categories() []string {
+	if d == nil {
+		return nil
+	}
+	seen := map[string]struct{}{}
+	var out []string
+	for _, c := range d.Config.Categories {
+		key := normalize(c)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, key)
+	}
+	return out
+}
+
+// Run classifies every category at now, stores the results and makes them
+// current.
+func (d *Detector) Run(ctx context.Context, now time.Time) ([]models.MarketRegime, error) {
+	if !d.enabled() {
+		return nil, nil
+	}
+	now = now.UTC()
+	var out []models.MarketRegime
+	for _, category := range d.Categories() {
+		item, err := d.compute(ctx, category, now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	if err := d.Repo.InsertMarketRegimes(ctx, out); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	d.loaded = true
+	d.current = make(map[string]models.MarketRegime, len(out))
+	for _, item := range out {
+		d.current[item.Category] = item
+	}
+	d.marketCategory = nil
+	d.mu.Unlock()
+	if d.Logger != nil {
+		for _, item := range out {
+			d.Logger.Info("regime classified",
+				zap.String("category", item.Category),
+				zap.String("regime", item.Regime),
+				zap.Int("markets", item.Markets),
+				zap.Float64("momentum", item.Momentum),
+				zap.Float64("efficiency", item.Efficiency),
+				zap.Float64("jump_share", item.JumpShare),
+			)
+		}
+	}
+	return out, nil
+}
+
+func (d *Detector) compute(ctx context.Context, category string, now time.Time) (models.MarketRegime, error) {
+	lookback := d.Config.Lookback
+	if lookback <= 0 {
+		lookback = 6 * time.Hour
+	}
+	since := now.Add(-lookback)
+	tokens, err := d.Repo.ListCategoryTokens(ctx, category, d.Config.MaxMarkets)
+	if err != nil {
+		return models.MarketRegime{}, err
+	}
+	minCandles := max(d.Config.MinCandles, 2)
+	var series [][]float64
+	for _, tok := range tokens {
+		candles, err := d.Repo.ListPriceCandles(ctx, tok.ID, since, now)
+		if err != nil {
+			return models.MarketRegime{}, err
+		}
+		if len(candles) < minCandles {
+			continue
+		}
+		closes := make([]float64, len(candles))
+		for i, c := range candles {
+			closes[i] = c.Close
+		}
+		series = append(series, closes)
+	}
+	idx := Measure(series, d.Config.JumpThreshold)
+	settled, err := d.Repo.CountCategorySettlements(ctx, category, since)
+	if err != nil {
+		return models.MarketRegime{}, err
+	}
+	idx.Settlements = int(settled)
+	return models.MarketRegime{
+		Category:    category,
+		Regime:      Classify(idx, d.Config),
+		Markets:     idx.Markets,
+		Momentum:    idx.Momentum,
+		Volatility:  idx.Volatility,
+		Efficiency:  idx.Efficiency,
+		JumpShare:   idx.JumpShare,
+		Settlements: idx.Settlements,
+		ComputedAt:  now,
+	}, nil
+}
+
+// Current returns the current regime of each category, loading the latest
+// stored ones before the first run.
+func (d *Detector) Current(ctx context.Context) (map[string]models.MarketRegime, error) {
+	if !d.enabled() {
+		return map[string]models.MarketRegime{}, nil
+	}
+	d.mu.RLock()
+	if d.loaded {
+		out := make(map[string]models.MarketRegime, len(d.current))
+		for k, v := range d.current {
+			out[k] = v
+		}
+		d.mu.RUnlock()
+		return out, nil
+	}
+	d.mu.RUnlock()
+	items, err := d.Repo.ListLatestMarketRegimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	configured := map[string]struct{}{}
+	for _, c := range d.Categories() {
+		configured[c] = struct{}{}
+	}
+	out := map[string]models.MarketRegime{}
+	for _, item := range items {
+		if _, ok := configured[item.Category]; ok {
+			out[item.Category] = item
+		}
+	}
+	d.mu.Lock()
+	if !d.loaded {
+		d.loaded = true
+		d.current = out
+	}
+	d.mu.Unlock()
+	return d.Current(ctx)
+}
+
+// ForMarkets returns the current regime of each market's category. A market
+// whose event carries several configured categories takes the first one in
+// config order. Regimes older than three intervals are ignored, so a stalled
+// detector stops adjusting risk rather than pinning an old regime.
+func (d *Detector) ForMarkets(ctx context.Context, marketIDs []string, now time.Time) (map[string]models.MarketRegime, error) {
+	if !d.enabled() || len(marketIDs) == 0 {
+		return nil, nil
+	}
+	current, err := d.Current(ctx)
+	if err != nil || len(current) == 0 {
+		return nil, err
+	}
+	categories, err := d.marketCategories(ctx, marketIDs)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]models.MarketRegime{}
+	for marketID, category := range categories {
+		item, ok := current[category]
+		if !ok {
+			continue
+		}
+		if d.Config.Interval > 0 && now.Sub(item.ComputedAt) > 3*d.Config.Interval {
+			continue
+		}
+		out[marketID] = item
+	}
+	return out, nil
+}
+
+func (d *Detector) marketCategories(ctx context.Context, marketIDs []string) (map[string]string, error) {
+	out := map[string]string{}
+	var missing []string
+	d.mu.RLock()
+	for _, id := range marketIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if category, ok := d.marketCategory[id]; ok {
+			out[id] = category
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	d.mu.RUnlock()
+	if len(missing) == 0 {
+		return out, nil
+	}
+	markets, err := d.Repo.ListMarketsByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	var eventIDs []string
+	for _, m := range markets {
+		if m.EventID != "" {
+			eventIDs = append(eventIDs, m.EventID)
+		}
+	}
+	tags, err := d.Repo.ListTagsByEventIDs(ctx, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]string, len(missing))
+	for _, id := range missing {
+		found[id] = ""
+	}
+	for _, m := range markets {
+		found[m.ID] = d.categoryOf(tags[m.EventID])
+	}
+	d.mu.Lock()
+	if d.marketCategory == nil {
+		d.marketCategory = map[string]string{}
+	}
+	for id, category := range found {
+		d.marketCategory[id] = category
+		out[id] = category
+	}
+	d.mu.Unlock()
+	return out, nil
+}
+
+func (d *Detector) categoryOf(tags []models.Tag) string {
+	for _, category := range d.Categories() {
+		for _, t := range tags {
+			if normalize(t.Slug) == category || normalize(t.Label) == category {
+				return category
+			}
+		}
+	}
+	return ""
+}
+
+// Adjustment returns the risk adjustment for strategyName's opportunities in
+// regime: the strategy's own entry if configured, else the regime's.
+func (d *Detector) Adjustment(strategyName, regime string) (config.RegimeAdjustment, bool) {
+	if d == nil {
+		return config.RegimeAdjustment{}, false
+	}
+	if byRegime, ok := d.Config.Strategies[normalize(strategyName)]; ok {
+		if adj, ok := byRegime[regime]; ok {
+			return adj, true
+		}
+	}
+	adj, ok := d.Config.Adjustments[regime]
+	return adj, ok
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
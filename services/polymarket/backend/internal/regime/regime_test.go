@@ -0,0 +1,134 @@
+package regime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+var testConfig = config.RegimeConfig{
+	Enabled:         true,
+	Interval:        10 * time.Minute,
+	Categories:      []string{"Politics", "crypto"},
+	MinCandles:      3,
+	TrendEfficiency: 0.35,
+	TrendMomentum:   0.02,
+	JumpThreshold:   0.08,
+	NewsJumpShare:   0.25,
+}
+
+func TestMeasureAndClassify(t *testing.T) {
+	trend := Measure([][]float64{{0.40, 0.42, 0.44, 0.46}, {0.60, 0.58, 0.57, 0.55}}, 0.08)
+	if trend.Markets != 2 || trend.Efficiency != 1 || trend.JumpShare != 0 {
+		t.Fatalf("trend=%+v", trend)
+	}
+	if got := Classify(trend, testConfig); got != Trending {
+		t.Fatalf("regime=%s want trending", got)
+	}
+
+	chop := Measure([][]float64{{0.50, 0.53, 0.50, 0.53, 0.50}, {0.30, 0.31}}, 0.08)
+	if got := Classify(chop, testConfig); got != Choppy {
+		t.Fatalf("regime=%s want choppy (%+v)", got, chop)
+	}
+
+	news := Measure([][]float64{{0.20, 0.21, 0.45}, {0.50, 0.51, 0.50}, {0.7, 0.7}, {0.1, 0.1}}, 0.08)
+	if news.JumpShare != 0.25 || Classify(news, testConfig) != NewsDriven {
+		t.Fatalf("news=%+v", news)
+	}
+	if got := Classify(Indices{}, testConfig); got != Unknown {
+		t.Fatalf("regime=%s want unknown", got)
+	}
+}
+
+type regimeRepo struct {
+	repository.Repository
+	candles  map[string][]float64
+	inserted []models.MarketRegime
+}
+
+func (r *regimeRepo) ListCategoryTokens(ctx context.Context, category string, limit int) ([]models.Token, error) {
+	if category != "politics" {
+		return nil, nil
+	}
+	return []models.Token{{ID: "t1"}, {ID: "t2"}}, nil
+}
+
+func (r *regimeRepo) ListPriceCandles(ctx context.Context, tokenID string, since, until time.Time) ([]models.PriceCandle, error) {
+	var out []models.PriceCandle
+	for _, c := range r.candles[tokenID] {
+		out = append(out, models.PriceCandle{TokenID: tokenID, Close: c})
+	}
+	return out, nil
+}
+
+func (r *regimeRepo) CountCategorySettlements(ctx context.Context, category string, since time.Time) (int64, error) {
+	return 1, nil
+}
+
+func (r *regimeRepo) InsertMarketRegimes(ctx context.Context, items []models.MarketRegime) error {
+	r.inserted = append(r.inserted, items...)
+	return nil
+}
+
+func (r *regimeRepo) ListMarketsByIDs(ctx context.Context, ids []string) ([]models.Market, error) {
+	return []models.Market{{ID: "m1", EventID: "e1"}, {ID: "m2", EventID: "e2"}}, nil
+}
+
+func (r *regimeRepo) ListTagsByEventIDs(ctx context.Context, eventIDs []string) (map[string][]models.Tag, error) {
+	return map[string][]models.Tag{
+		"e1": {{Slug: "us-elections"}, {Slug: "politics", Label: "Politics"}},
+		"e2": {{Slug: "nba"}},
+	}, nil
+}
+
+func TestDetector_RunAndForMarkets(t *testing.T) {
+	repo := &regimeRepo{candles: map[string][]float64{
+		"t1": {0.40, 0.43, 0.46, 0.50},
+		"t2": {0.50, 0.51}, // too few candles
+	}}
+	d := &Detector{Config: testConfig, Repo: repo}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	items, err := d.Run(context.Background(), now)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(items) != 2 || len(repo.inserted) != 2 {
+		t.Fatalf("items=%+v", items)
+	}
+	if p := items[0]; p.Category != "politics" || p.Regime != Trending || p.Markets != 1 || p.Settlements != 1 {
+		t.Fatalf("politics=%+v", p)
+	}
+	if c := items[1]; c.Category != "crypto" || c.Regime != Unknown {
+		t.Fatalf("crypto=%+v", c)
+	}
+
+	got, err := d.ForMarkets(context.Background(), []string{"m1", "m2"}, now.Add(5*time.Minute))
+	if err != nil || len(got) != 1 || got["m1"].Regime != Trending {
+		t.Fatalf("for markets=%+v err=%v", got, err)
+	}
+	if got, _ := d.ForMarkets(context.Background(), []string{"m1"}, now.Add(time.Hour)); len(got) != 0 {
+		t.Fatalf("stale regime applied: %+v", got)
+	}
+}
+
+func TestDetector_AdjustmentPrefersStrategy(t *testing.T) {
+	d := &Detector{Config: config.RegimeConfig{
+		Adjustments: map[string]config.RegimeAdjustment{NewsDriven: {MinEdgePct: 0.03}},
+		Strategies: map[string]map[string]config.RegimeAdjustment{
+			"certainty_sweep": {NewsDriven: {Block: true}},
+		},
+	}}
+	if adj, ok := d.Adjustment("Certainty_Sweep", NewsDriven); !ok || !adj.Block {
+		t.Fatalf("adj=%+v ok=%v", adj, ok)
+	}
+	if adj, ok := d.Adjustment("other", NewsDriven); !ok || adj.MinEdgePct != 0.03 {
+		t.Fatalf("adj=%+v ok=%v", adj, ok)
+	}
+	if _, ok := d.Adjustment("other", Choppy); ok {
+		t.Fatalf("choppy has no adjustment")
+	}
+}
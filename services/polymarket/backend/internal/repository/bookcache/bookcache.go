@@ -0,0 +1,118 @@
+// Package bookcache keeps the latest order book per token in memory in front
+// of a repository.Repository.
+//
+// Writes to orderbook_latest (CLOB stream, REST resync) go through the cache
+// before reaching the database, so readers of ListOrderbookLatestByTokenIDs
+// (strategies, risk, preflight) see a book as soon as it is parsed. Tokens that
+// are missing or older than MaxAge are read from the database and cached.
+package bookcache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type Repository struct {
+	repository.Repository
+
+	// MaxAge bounds how long a cached book is served without checking the
+	// database, which other processes may have updated.
+	MaxAge time.Duration
+
+	mu    sync.RWMutex
+	books map[string]models.OrderbookLatest
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type Stats struct {
+	Books  int   `json:"books"`
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+func New(inner repository.Repository, maxAge time.Duration) *Repository {
+	return &Repository{Repository: inner, MaxAge: maxAge, books: map[string]models.OrderbookLatest{}}
+}
+
+// UpsertOrderbookLatest updates the cache first and then persists the book.
+func (r *Repository) UpsertOrderbookLatest(ctx context.Context, item *models.OrderbookLatest) error {
+	if item == nil {
+		return nil
+	}
+	r.Put(*item)
+	return r.Repository.UpsertOrderbookLatest(ctx, item)
+}
+
+// ListOrderbookLatestByTokenIDs serves fresh books from memory and loads the
+// rest from the database in a single query.
+func (r *Repository) ListOrderbookLatestByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.OrderbookLatest, error) {
+	if len(tokenIDs) == 0 {
+		return nil, nil
+	}
+	now := time.Now().UTC()
+	out := make([]models.OrderbookLatest, 0, len(tokenIDs))
+	var missing []string
+	r.mu.RLock()
+	for _, id := range tokenIDs {
+		book, ok := r.books[strings.TrimSpace(id)]
+		if ok && r.fresh(book, now) {
+			out = append(out, book)
+			continue
+		}
+		missing = append(missing, id)
+	}
+	r.mu.RUnlock()
+	r.hits.Add(int64(len(out)))
+	if len(missing) == 0 {
+		return out, nil
+	}
+	r.misses.Add(int64(len(missing)))
+	loaded, err := r.Repository.ListOrderbookLatestByTokenIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, book := range loaded {
+		r.Put(book)
+	}
+	return append(out, loaded...), nil
+}
+
+// Put stores a book unless the cache already holds a newer snapshot.
+func (r *Repository) Put(book models.OrderbookLatest) {
+	tokenID := strings.TrimSpace(book.TokenID)
+	if tokenID == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.books == nil {
+		r.books = map[string]models.OrderbookLatest{}
+	}
+	if prev, ok := r.books[tokenID]; ok && prev.UpdatedAt.After(book.UpdatedAt) {
+		return
+	}
+	r.books[tokenID] = book
+}
+
+func (r *Repository) Stats() Stats {
+	r.mu.RLock()
+	n := len(r.books)
+	r.mu.RUnlock()
+	return Stats{Books: n, Hits: r.hits.Load(), Misses: r.misses.Load()}
+}
+
+func (r *Repository) fresh(book models.OrderbookLatest, now time.Time) bool {
+	maxAge := r.MaxAge
+	if maxAge <= 0 {
+		maxAge = 30 * time.Second
+	}
+	return now.Sub(book.UpdatedAt) < maxAge
+}
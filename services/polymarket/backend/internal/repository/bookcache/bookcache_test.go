@@ -0,0 +1,88 @@
+package bookcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type fakeRepo struct {
+	repository.Repository
+	rows    map[string]models.OrderbookLatest
+	queried [][]string
+	upserts int
+}
+
+func (f *fakeRepo) UpsertOrderbookLatest(ctx context.Context, item *models.OrderbookLatest) error {
+	f.upserts++
+	f.rows[item.TokenID] = *item
+	return nil
+}
+
+func (f *fakeRepo) ListOrderbookLatestByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.OrderbookLatest, error) {
+	f.queried = append(f.queried, tokenIDs)
+	var out []models.OrderbookLatest
+	for _, id := range tokenIDs {
+		if row, ok := f.rows[id]; ok {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func TestRepository_ReadThroughAndWriteThrough(t *testing.T) {
+	now := time.Now().UTC()
+	ask := 0.42
+	inner := &fakeRepo{rows: map[string]models.OrderbookLatest{
+		"db": {TokenID: "db", UpdatedAt: now},
+	}}
+	r := New(inner, time.Minute)
+
+	if err := r.UpsertOrderbookLatest(context.Background(), &models.OrderbookLatest{TokenID: "ws", BestAsk: &ask, UpdatedAt: now}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if inner.upserts != 1 {
+		t.Fatalf("upserts=%d want 1", inner.upserts)
+	}
+
+	books, err := r.ListOrderbookLatestByTokenIDs(context.Background(), []string{"ws", "db"})
+	if err != nil || len(books) != 2 {
+		t.Fatalf("books=%v err=%v", books, err)
+	}
+	if len(inner.queried) != 1 || len(inner.queried[0]) != 1 || inner.queried[0][0] != "db" {
+		t.Fatalf("queried=%v want only db", inner.queried)
+	}
+
+	// Second read is served entirely from memory.
+	if _, err := r.ListOrderbookLatestByTokenIDs(context.Background(), []string{"ws", "db"}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(inner.queried) != 1 {
+		t.Fatalf("queried=%v want no new db reads", inner.queried)
+	}
+	if st := r.Stats(); st.Books != 2 || st.Hits != 3 || st.Misses != 1 {
+		t.Fatalf("stats=%+v", st)
+	}
+}
+
+func TestRepository_StaleAndOutOfOrder(t *testing.T) {
+	now := time.Now().UTC()
+	inner := &fakeRepo{rows: map[string]models.OrderbookLatest{}}
+	r := New(inner, time.Second)
+
+	r.Put(models.OrderbookLatest{TokenID: "t", UpdatedAt: now})
+	r.Put(models.OrderbookLatest{TokenID: "t", UpdatedAt: now.Add(-time.Hour)})
+	if got := r.books["t"].UpdatedAt; !got.Equal(now) {
+		t.Fatalf("older snapshot replaced newer one: %s", got)
+	}
+
+	r.Put(models.OrderbookLatest{TokenID: "old", UpdatedAt: now.Add(-time.Minute)})
+	inner.rows["old"] = models.OrderbookLatest{TokenID: "old", UpdatedAt: now}
+	books, _ := r.ListOrderbookLatestByTokenIDs(context.Background(), []string{"old"})
+	if len(books) != 1 || !books[0].UpdatedAt.Equal(now) {
+		t.Fatalf("stale entry not refreshed: %v", books)
+	}
+}
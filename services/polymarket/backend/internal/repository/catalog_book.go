@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+)
+
+// Why a CatalogBook is not consistent.
+const (
+	BookStaleNoBook       = "no_book"
+	BookStaleUnstamped    = "unstamped"
+	BookStaleTokenEpoch   = "token_behind_market"
+	BookStaleCatalogEpoch = "book_behind_catalog"
+)
+
+// CatalogBook is a token's latest book joined with its token and market
+// rows in one statement. The book columns are nil when the token has no
+// book yet.
+type CatalogBook struct {
+	TokenID      string          `json:"token_id"`
+	MarketID     string          `json:"market_id"`
+	Outcome      string          `json:"outcome"`
+	OutcomeIndex int             `json:"outcome_index"`
+	Question     string          `json:"question"`
+	ConditionID  string          `json:"condition_id"`
+	TickSize     decimal.Decimal `json:"tick_size"`
+	NegRisk      *bool           `json:"neg_risk,omitempty"`
+	Active       bool            `json:"active"`
+	Closed       bool            `json:"closed"`
+	BestBid      *float64        `json:"best_bid,omitempty"`
+	BestAsk      *float64        `json:"best_ask,omitempty"`
+	Mid          *float64        `json:"mid,omitempty"`
+	BidsJSON     datatypes.JSON  `json:"bids,omitempty"`
+	AsksJSON     datatypes.JSON  `json:"asks,omitempty"`
+	SnapshotTS   *time.Time      `json:"snapshot_ts,omitempty"`
+	BookSource   *string         `json:"book_source,omitempty"`
+	MarketEpoch  int64           `json:"market_epoch"`
+	TokenEpoch   int64           `json:"token_epoch"`
+	BookEpoch    *int64          `json:"book_epoch"`
+}
+
+// Staleness is "" when book, token and market share one non-zero epoch,
+// i.e. the book was written against the token set the catalog holds now.
+// Otherwise it names the first mismatch.
+func (b CatalogBook) Staleness() string {
+	switch {
+	case b.BookEpoch == nil:
+		return BookStaleNoBook
+	case *b.BookEpoch == 0 || b.TokenEpoch == 0 || b.MarketEpoch == 0:
+		return BookStaleUnstamped
+	case b.TokenEpoch != b.MarketEpoch:
+		return BookStaleTokenEpoch
+	case *b.BookEpoch != b.TokenEpoch:
+		return BookStaleCatalogEpoch
+	}
+	return ""
+}
+
+func (b CatalogBook) Consistent() bool { return b.Staleness() == "" }
+
+// LoadCatalogBooks reads the joined rows for tokenIDs, preserving their
+// order and skipping unknown tokens. Unlike LoadTokenSnapshots it reads the
+// store directly so book and catalog come from the same statement.
+func LoadCatalogBooks(ctx context.Context, repo CatalogRepository, tokenIDs []string) ([]CatalogBook, error) {
+	ids := make([]string, 0, len(tokenIDs))
+	seen := map[string]bool{}
+	for _, id := range tokenIDs {
+		if id = strings.TrimSpace(id); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if repo == nil || len(ids) == 0 {
+		return []CatalogBook{}, nil
+	}
+	rows, err := repo.ListCatalogBooks(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]CatalogBook, len(rows))
+	for _, r := range rows {
+		byID[r.TokenID] = r
+	}
+	out := make([]CatalogBook, 0, len(ids))
+	for _, id := range ids {
+		if r, ok := byID[id]; ok {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
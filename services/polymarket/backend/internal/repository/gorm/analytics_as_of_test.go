@@ -0,0 +1,101 @@
+package gormrepository
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+)
+
+func TestAnalyticsAsOfMasksLaterSettlements(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.PnLRecord{}, &models.Fill{}, &models.ExecutionPlan{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	dec := func(f float64) *decimal.Decimal { v := decimal.NewFromFloat(f); return &v }
+	settled := func(d int) *time.Time { t := day(d); return &t }
+	records := []models.PnLRecord{
+		// Won 10 on day 3.
+		{PlanID: 1, StrategyName: "s", Outcome: "win", RealizedPnL: dec(10), RealizedROI: dec(0.5), SettledAt: settled(3), CreatedAt: day(1)},
+		// Lost 4 on day 5.
+		{PlanID: 2, StrategyName: "s", Outcome: "loss", RealizedPnL: dec(-4), RealizedROI: dec(-0.2), SettledAt: settled(5), CreatedAt: day(2)},
+		// Opened after every as_of below but the last.
+		{PlanID: 3, StrategyName: "s", Outcome: "pending", CreatedAt: day(6)},
+	}
+	for i := range records {
+		if err := conn.Gorm.Create(&records[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name                      string
+		asOf                      *time.Time
+		plans, wins, losses, open int64
+		pnl                       float64
+	}{
+		{"before any settlement", settled(2), 2, 0, 0, 2, 0},
+		{"after the win", settled(4), 2, 1, 0, 1, 10},
+		{"after the loss", settled(5), 2, 1, 1, 0, 6},
+		{"now", nil, 3, 1, 1, 1, 6},
+	}
+	for _, tc := range cases {
+		got, err := store.AnalyticsOverview(ctx, tc.asOf, "")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got.TotalPlans != tc.plans || got.WinCount != tc.wins || got.LossCount != tc.losses || got.PendingCount != tc.open || math.Abs(got.TotalPnLUSD-tc.pnl) > 1e-9 {
+			t.Errorf("%s: overview = %+v", tc.name, got)
+		}
+	}
+
+	ratios, err := store.PerformanceRatios(ctx, nil, nil, settled(4), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The loss is not known yet; the pending rows count as flat.
+	if ratios.WinRate != 0.5 || ratios.AvgWin != 10 || ratios.AvgLoss != 0 {
+		t.Errorf("ratios as of day 4 = %+v", ratios)
+	}
+	ratios, err = store.PerformanceRatios(ctx, nil, nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(ratios.WinRate-1.0/3) > 1e-9 || ratios.AvgLoss != -4 {
+		t.Errorf("ratios now = %+v", ratios)
+	}
+
+	// as_of composes with the tenant scope.
+	for id, tenant := range map[uint64]string{1: "desk-a", 2: "desk-b", 3: "desk-a"} {
+		plan := models.ExecutionPlan{ID: id, Tenant: tenant, StrategyName: "s", Status: "executed", Legs: datatypes.JSON(`[]`)}
+		if err := conn.Gorm.Create(&plan).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, err := store.AnalyticsOverview(ctx, settled(5), "desk-a"); err != nil || got.TotalPlans != 1 || got.WinCount != 1 || math.Abs(got.TotalPnLUSD-10) > 1e-9 {
+		t.Errorf("desk-a overview as of day 5 = %+v, %v", got, err)
+	}
+
+	attr, err := store.AttributionByStrategy(ctx, "s", nil, nil, settled(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(attr.NetPnL-10) > 1e-9 {
+		t.Errorf("attribution as of day 4 = %+v", attr)
+	}
+}
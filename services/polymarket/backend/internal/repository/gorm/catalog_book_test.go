@@ -0,0 +1,65 @@
+package gormrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestListCatalogBooksEpochs(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.Market{}, &models.Token{}, &models.OrderbookLatest{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	err = store.InTx(ctx, func(tx *gorm.DB) error {
+		if err := store.UpsertMarketsTx(ctx, tx, []models.Market{{ID: "m1", EventID: "e1", Question: "q?", ConditionID: "c1", TickSize: decimal.RequireFromString("0.01"), Active: true, LastSeenAt: now, RawJSON: datatypes.JSON(`{}`), CatalogEpoch: 7}}); err != nil {
+			return err
+		}
+		return store.UpsertTokensTx(ctx, tx, []models.Token{
+			{ID: "yes", MarketID: "m1", Outcome: "Yes", LastSeenAt: now, RawJSON: datatypes.JSON(`{}`), CatalogEpoch: 7},
+			{ID: "no", MarketID: "m1", Outcome: "No", OutcomeIndex: 1, LastSeenAt: now, RawJSON: datatypes.JSON(`{}`), CatalogEpoch: 7},
+			{ID: "new", MarketID: "m1", Outcome: "Maybe", OutcomeIndex: 2, LastSeenAt: now, RawJSON: datatypes.JSON(`{}`), CatalogEpoch: 7},
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bid := 0.4
+	for id, epoch := range map[string]int64{"yes": 7, "no": 5} {
+		if err := store.UpsertOrderbookLatest(ctx, &models.OrderbookLatest{TokenID: id, SnapshotTS: now, BestBid: &bid, BidsJSON: datatypes.JSON(`[]`), AsksJSON: datatypes.JSON(`[]`), UpdatedAt: now, CatalogEpoch: epoch}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := repository.LoadCatalogBooks(ctx, store, []string{"no", "yes", "missing", "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0].TokenID != "no" || rows[1].TokenID != "yes" || rows[2].TokenID != "new" {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if rows[1].Question != "q?" || rows[1].BestBid == nil || *rows[1].BestBid != 0.4 || rows[1].SnapshotTS == nil {
+		t.Fatalf("joined row = %+v", rows[1])
+	}
+	for i, want := range []string{repository.BookStaleCatalogEpoch, "", repository.BookStaleNoBook} {
+		if got := rows[i].Staleness(); got != want {
+			t.Errorf("%s staleness = %q, want %q", rows[i].TokenID, got, want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package gormrepository
+
+import "gorm.io/gorm"
+
+// inChunkSize bounds the keys bound into one IN (?) list. Postgres caps a
+// statement at 65535 parameters; smaller chunks also keep plans cheap.
+var inChunkSize = 5000
+
+// findInChunks runs query with column IN chunk for each chunk of keys and
+// concatenates the rows. Keys should already be deduplicated; row order
+// across chunks is unspecified.
+func findInChunks[T any, K any](query *gorm.DB, column string, keys []K) ([]T, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	query = query.Session(&gorm.Session{})
+	var out []T
+	for start := 0; start < len(keys); start += inChunkSize {
+		end := min(start+inChunkSize, len(keys))
+		var items []T
+		if err := query.Where(column+" IN ?", keys[start:end]).Find(&items).Error; err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+package gormrepository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestChunkedLookupsAndSnapshots(t *testing.T) {
+	prev := inChunkSize
+	inChunkSize = 2
+	defer func() { inChunkSize = prev }()
+
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.Token{}, &models.OrderbookLatest{}, &models.MarketDataHealth{}, &models.LastTradePrice{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	ids := []string{}
+	for i := range 5 {
+		id := fmt.Sprintf("t%d", i)
+		ids = append(ids, id)
+		if err := conn.Gorm.Create(&models.Token{ID: id, MarketID: "m1", Outcome: "Yes", LastSeenAt: now, RawJSON: datatypes.JSON(`{}`)}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	bid := 0.4
+	if err := store.UpsertOrderbookLatest(ctx, &models.OrderbookLatest{TokenID: "t3", BestBid: &bid, BidsJSON: datatypes.JSON(`[]`), AsksJSON: datatypes.JSON(`[]`), UpdatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := store.ListTokensByIDs(ctx, append(ids, "t0", "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 5 {
+		t.Fatalf("tokens = %d, want 5", len(tokens))
+	}
+
+	snaps, err := repository.LoadTokenSnapshots(ctx, store, []string{"t4", "missing", "t3", "t4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 || snaps[0].Token.ID != "t4" || snaps[1].Token.ID != "t3" {
+		t.Fatalf("snapshots = %+v", snaps)
+	}
+	if snaps[0].Orderbook != nil || snaps[1].Orderbook == nil || *snaps[1].Orderbook.BestBid != 0.4 {
+		t.Fatalf("books not merged: %+v", snaps)
+	}
+}
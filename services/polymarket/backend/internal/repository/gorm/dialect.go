@@ -0,0 +1,104 @@
+package gormrepository
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The store targets Postgres; SQLite is supported for local development. The
+// few queries that use Postgres-only syntax go through these helpers.
+
+func isSQLite(db *gorm.DB) bool {
+	return db != nil && db.Dialector != nil && db.Dialector.Name() == "sqlite"
+}
+
+// ilike returns a case-insensitive LIKE condition on column with one placeholder.
+func (s *Store) ilike(column string) string {
+	if isSQLite(s.db) {
+		// SQLite LIKE is already case-insensitive for ASCII.
+		return column + " LIKE ?"
+	}
+	return column + " ILIKE ?"
+}
+
+// hoursBetween returns an expression for (later - earlier) in hours.
+func (s *Store) hoursBetween(later, earlier string) string {
+	if isSQLite(s.db) {
+		return "((julianday(" + later + ") - julianday(" + earlier + ")) * 24.0)"
+	}
+	return "(EXTRACT(EPOCH FROM (" + later + " - " + earlier + "))/3600.0)"
+}
+
+// utcHour returns an integer expression for the UTC hour of day of column.
+func (s *Store) utcHour(column string) string {
+	if isSQLite(s.db) {
+		return "CAST(strftime('%H', " + column + ") AS INTEGER)"
+	}
+	return "CAST(EXTRACT(HOUR FROM " + column + " AT TIME ZONE 'UTC') AS INTEGER)"
+}
+
+// rowID returns the physical row identifier, which every table has whatever
+// its primary key.
+func (s *Store) rowID() string {
+	if isSQLite(s.db) {
+		return "rowid"
+	}
+	return "ctid"
+}
+
+// sqlTime scans computed timestamp columns (MAX(...), COALESCE(...), DATE(...)).
+// Postgres returns time.Time; SQLite returns text because such expressions
+// carry no declared column type.
+type sqlTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+var sqliteTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func (t *sqlTime) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*t = sqlTime{}
+		return nil
+	case time.Time:
+		*t = sqlTime{Time: v, Valid: true}
+		return nil
+	case []byte:
+		return t.Scan(string(v))
+	case string:
+		for _, layout := range sqliteTimeLayouts {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				*t = sqlTime{Time: parsed, Valid: true}
+				return nil
+			}
+		}
+		return fmt.Errorf("sqlTime: cannot parse %q", v)
+	default:
+		return fmt.Errorf("sqlTime: unsupported type %T", src)
+	}
+}
+
+func (t sqlTime) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	return t.Time, nil
+}
+
+func (t sqlTime) Ptr() *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
@@ -0,0 +1,117 @@
+package gormrepository
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+)
+
+func TestDialectHelpersOnSQLite(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	g := conn.Gorm.WithContext(context.Background())
+	if err := g.Exec("CREATE TABLE dialect_rows (name text, opened_at datetime, closed_at datetime)").Error; err != nil {
+		t.Fatal(err)
+	}
+	// 21:30 in UTC+8 is 13:30 UTC; the row stays open 36.5 hours.
+	opened := time.Date(2026, 3, 1, 21, 30, 0, 0, time.FixedZone("UTC+8", 8*3600))
+	closed := opened.Add(36*time.Hour + 30*time.Minute)
+	for _, name := range []string{"Trump Wins", "trump loses", "Biden"} {
+		if err := g.Exec("INSERT INTO dialect_rows (name, opened_at, closed_at) VALUES (?, ?, ?)", name, opened, closed).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := New(conn.Gorm)
+
+	var matches int64
+	if err := g.Table("dialect_rows").Where(s.ilike("name"), "%TRUMP%").Count(&matches).Error; err != nil {
+		t.Fatal(err)
+	}
+	if matches != 2 {
+		t.Fatalf("ilike matched %d rows", matches)
+	}
+
+	var hours float64
+	if err := g.Raw("SELECT " + s.hoursBetween("closed_at", "opened_at") + " FROM dialect_rows LIMIT 1").Scan(&hours).Error; err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(hours-36.5) > 1e-6 {
+		t.Fatalf("hoursBetween = %v", hours)
+	}
+
+	var hour int
+	if err := g.Raw("SELECT " + s.utcHour("opened_at") + " FROM dialect_rows LIMIT 1").Scan(&hour).Error; err != nil {
+		t.Fatal(err)
+	}
+	if hour != 13 {
+		t.Fatalf("utcHour = %d", hour)
+	}
+
+	var ids []int64
+	if err := g.Raw("SELECT " + s.rowID() + " FROM dialect_rows ORDER BY " + s.rowID()).Scan(&ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || ids[0] == ids[1] {
+		t.Fatalf("rowID = %v", ids)
+	}
+	if err := g.Exec("DELETE FROM dialect_rows WHERE "+s.rowID()+" = ?", ids[0]).Error; err != nil {
+		t.Fatal(err)
+	}
+	var left int64
+	g.Table("dialect_rows").Count(&left)
+	if left != 2 {
+		t.Fatalf("rows after delete by rowID = %d", left)
+	}
+
+	var last sqlTime
+	if err := g.Raw("SELECT MAX(closed_at) FROM dialect_rows").Scan(&last).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !last.Valid || !last.Time.Equal(closed) {
+		t.Fatalf("MAX(closed_at) = %+v, want %s", last, closed)
+	}
+}
+
+func TestDialectHelpersOnPostgres(t *testing.T) {
+	s := &Store{}
+	cases := []struct{ got, want string }{
+		{s.ilike("name"), "name ILIKE ?"},
+		{s.hoursBetween("closed_at", "opened_at"), "(EXTRACT(EPOCH FROM (closed_at - opened_at))/3600.0)"},
+		{s.utcHour("opened_at"), "CAST(EXTRACT(HOUR FROM opened_at AT TIME ZONE 'UTC') AS INTEGER)"},
+		{s.rowID(), "ctid"},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("got %q, want %q", tc.got, tc.want)
+		}
+	}
+}
+
+func TestSQLTimeScan(t *testing.T) {
+	want := time.Date(2026, 3, 1, 13, 30, 0, 0, time.UTC)
+	for _, src := range []any{
+		want,
+		"2026-03-01 13:30:00+00:00",
+		"2026-03-01T13:30:00+00:00",
+		[]byte("2026-03-01 13:30:00"),
+	} {
+		var got sqlTime
+		if err := got.Scan(src); err != nil || !got.Valid || !got.Time.Equal(want) {
+			t.Errorf("Scan(%v) = %+v, %v", src, got, err)
+		}
+	}
+	var null sqlTime
+	if err := null.Scan(nil); err != nil || null.Valid || null.Ptr() != nil {
+		t.Errorf("Scan(nil) = %+v, %v", null, err)
+	}
+	if err := null.Scan("not a time"); err == nil {
+		t.Errorf("Scan of garbage accepted")
+	}
+}
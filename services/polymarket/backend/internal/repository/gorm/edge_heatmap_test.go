@@ -0,0 +1,86 @@
+package gormrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestEdgeRealizationHeatmap(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.Strategy{}, &models.Opportunity{}, &models.ExecutionPlan{}, &models.PnLRecord{}, &models.MarketLabel{}, &models.CatalogChange{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	strat := &models.Strategy{Name: "arb", Params: datatypes.JSON(`{}`)}
+	if err := store.UpsertStrategy(ctx, strat); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpsertMarketLabel(ctx, &models.MarketLabel{MarketID: "m1", Label: "sports"}); err != nil {
+		t.Fatal(err)
+	}
+
+	settle := func(market, outcome string, edge, roi float64) {
+		t.Helper()
+		opp := &models.Opportunity{StrategyID: strat.ID, Status: "executed", Legs: datatypes.JSON(`[]`), PrimaryMarketID: &market}
+		if err := store.InsertOpportunity(ctx, opp); err != nil {
+			t.Fatal(err)
+		}
+		plan := &models.ExecutionPlan{OpportunityID: opp.ID, StrategyName: "arb", Status: "executed", Legs: datatypes.JSON(`[]`)}
+		if err := store.InsertExecutionPlan(ctx, plan); err != nil {
+			t.Fatal(err)
+		}
+		r := decimal.NewFromFloat(roi)
+		pnl := r.Mul(decimal.NewFromInt(100))
+		rec := &models.PnLRecord{PlanID: plan.ID, StrategyName: "arb", ExpectedEdge: decimal.NewFromFloat(edge), RealizedROI: &r, RealizedPnL: &pnl, Outcome: outcome, SettledAt: &now}
+		if err := store.UpsertPnLRecord(ctx, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	settle("m1", "win", 0.03, 0.04)
+	settle("m1", "win", 0.04, 0.01)
+	settle("m1", "loss", 0.005, -0.2)
+	settle("m2", "win", 0.2, 0.5)
+
+	bounds := repository.EdgeHeatmapParams{EdgeBounds: []float64{0.01, 0.05}, ROIBounds: []float64{0, 0.02}}
+	cells, err := store.EdgeRealizationHeatmap(ctx, bounds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []repository.EdgeHeatmapCell{
+		{StrategyName: "arb", Label: "sports", EdgeBucket: 0, ROIBucket: 0, Trades: 1},
+		{StrategyName: "arb", Label: "sports", EdgeBucket: 1, ROIBucket: 1, Trades: 1, Wins: 1},
+		{StrategyName: "arb", Label: "sports", EdgeBucket: 1, ROIBucket: 2, Trades: 1, Wins: 1},
+		{StrategyName: "arb", Label: "unlabeled", EdgeBucket: 2, ROIBucket: 2, Trades: 1, Wins: 1},
+	}
+	if len(cells) != len(want) {
+		t.Fatalf("cells=%+v", cells)
+	}
+	for i, w := range want {
+		got := cells[i]
+		if got.StrategyName != w.StrategyName || got.Label != w.Label || got.EdgeBucket != w.EdgeBucket || got.ROIBucket != w.ROIBucket || got.Trades != w.Trades || got.Wins != w.Wins {
+			t.Fatalf("cell %d=%+v want %+v", i, got, w)
+		}
+	}
+
+	label := "unlabeled"
+	bounds.Label = &label
+	cells, err = store.EdgeRealizationHeatmap(ctx, bounds)
+	if err != nil || len(cells) != 1 || cells[0].RealizedUSD != 50 {
+		t.Fatalf("unlabeled cells=%+v err=%v", cells, err)
+	}
+}
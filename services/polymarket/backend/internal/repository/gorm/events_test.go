@@ -0,0 +1,54 @@
+package gormrepository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/events"
+	"polymarket/internal/models"
+)
+
+func TestStorePublishesRowEvents(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.ExecutionPlan{}, &models.Fill{}); err != nil {
+		t.Fatal(err)
+	}
+	bus := events.NewBus()
+	ch := bus.Subscribe(8, events.TopicPlanStatus, events.TopicFillCreated)
+	store := New(conn.Gorm)
+	store.SetEventPublisher(bus)
+	ctx := context.Background()
+
+	plan := &models.ExecutionPlan{StrategyName: "s", Status: "draft", Legs: datatypes.JSON(`[]`)}
+	if err := store.InsertExecutionPlan(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpdateExecutionPlanStatus(ctx, plan.ID, "executing"); err != nil {
+		t.Fatal(err)
+	}
+	fill := &models.Fill{PlanID: plan.ID, TokenID: "t1", Direction: "BUY", FilledSize: decimal.NewFromInt(1), AvgPrice: decimal.NewFromFloat(0.5)}
+	if err := store.InsertFill(ctx, fill); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []events.Event{
+		{Topic: events.TopicPlanStatus, ID: plan.ID, Status: "draft"},
+		{Topic: events.TopicPlanStatus, ID: plan.ID, Status: "executing"},
+		{Topic: events.TopicFillCreated, ID: fill.ID, PlanID: plan.ID},
+	}
+	for i, w := range want {
+		got := <-ch
+		if got.Topic != w.Topic || got.ID != w.ID || got.Status != w.Status || got.PlanID != w.PlanID {
+			t.Fatalf("event %d=%+v want %+v", i, got, w)
+		}
+	}
+}
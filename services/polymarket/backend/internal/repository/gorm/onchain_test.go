@@ -0,0 +1,102 @@
+package gormrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestSaveOnchainCondition(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.Market{}, &models.Token{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, m := range []models.Market{
+		{ID: "m1", EventID: "e1", Question: "q1", ConditionID: "c1", LastSeenAt: now, RawJSON: datatypes.JSON(`{}`)},
+		{ID: "m2", EventID: "e1", Question: "q2", ConditionID: "c2", LastSeenAt: now, RawJSON: datatypes.JSON(`{}`)},
+		{ID: "m3", EventID: "e1", Question: "q3", ConditionID: "c3", Closed: true, LastSeenAt: now, RawJSON: datatypes.JSON(`{}`)},
+	} {
+		if err := conn.Gorm.Create(&m).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tok := range []models.Token{
+		{ID: "t-yes", MarketID: "m1", Outcome: "Yes", OutcomeIndex: 0, LastSeenAt: now, RawJSON: datatypes.JSON(`{}`)},
+		{ID: "t-no", MarketID: "m1", Outcome: "No", OutcomeIndex: 1, LastSeenAt: now, RawJSON: datatypes.JSON(`{}`)},
+	} {
+		if err := conn.Gorm.Create(&tok).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.SaveOnchainCondition(ctx, "m2", repository.OnchainConditionState{OutcomeSlots: 2, CheckedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	due, err := store.ListMarketsForOnchainCheck(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 2 || due[0].ID != "m1" || due[1].ID != "m2" {
+		t.Fatalf("due=%v want unchecked m1 before m2, closed m3 skipped", marketIDs(due))
+	}
+
+	payouts := []decimal.Decimal{decimal.Zero, decimal.NewFromInt(1)}
+	if err := store.SaveOnchainCondition(ctx, "m1", repository.OnchainConditionState{OutcomeSlots: 2, Payouts: payouts, CheckedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	later := now.Add(time.Hour)
+	if err := store.SaveOnchainCondition(ctx, "m1", repository.OnchainConditionState{OutcomeSlots: 2, Payouts: payouts, CheckedAt: later}); err != nil {
+		t.Fatal(err)
+	}
+	markets, err := store.ListMarketsByIDs(ctx, []string{"m1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m1 := markets[0]
+	if m1.OnchainResolvedAt == nil || !m1.OnchainResolvedAt.Equal(now) {
+		t.Fatalf("resolved_at=%v want first sighting %v", m1.OnchainResolvedAt, now)
+	}
+	if m1.OnchainCheckedAt == nil || !m1.OnchainCheckedAt.Equal(later) || m1.OnchainOutcomeSlots == nil || *m1.OnchainOutcomeSlots != 2 {
+		t.Fatalf("market=%+v", m1)
+	}
+	tokens, err := store.ListTokensByIDs(ctx, []string{"t-yes", "t-no"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tok := range tokens {
+		want := payouts[tok.OutcomeIndex]
+		if tok.OnchainPayout == nil || !tok.OnchainPayout.Equal(want) {
+			t.Fatalf("token %s payout=%v want %s", tok.ID, tok.OnchainPayout, want)
+		}
+	}
+	due, err = store.ListMarketsForOnchainCheck(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 1 || due[0].ID != "m2" {
+		t.Fatalf("due=%v want m2 only once m1 resolved", marketIDs(due))
+	}
+}
+
+func marketIDs(items []models.Market) []string {
+	out := make([]string, 0, len(items))
+	for _, m := range items {
+		out = append(out, m.ID)
+	}
+	return out
+}
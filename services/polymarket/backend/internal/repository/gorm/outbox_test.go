@@ -0,0 +1,86 @@
+package gormrepository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestOutboxCommitsWithWrite(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.ExecutionPlan{}, &models.OutboxMessage{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	msg := func() []models.OutboxMessage {
+		return []models.OutboxMessage{{Kind: models.OutboxKindPaasLog, Payload: datatypes.JSON(`{}`)}}
+	}
+
+	boom := errors.New("boom")
+	err = store.InTx(ctx, func(tx *gorm.DB) error {
+		if err := store.InsertExecutionPlanTx(ctx, tx, &models.ExecutionPlan{StrategyName: "s", Status: "draft", Legs: datatypes.JSON(`[]`)}); err != nil {
+			return err
+		}
+		if err := store.InsertOutboxMessagesTx(ctx, tx, msg()); err != nil {
+			return err
+		}
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("err=%v", err)
+	}
+	if n, _ := store.CountOutboxMessages(ctx, repository.ListOutboxMessagesParams{}); n != 0 {
+		t.Fatalf("rolled back write left %d messages", n)
+	}
+
+	err = store.InTx(ctx, func(tx *gorm.DB) error {
+		return store.InsertOutboxMessagesTx(ctx, tx, msg())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	claimed, err := store.ClaimOutboxMessages(ctx, now, time.Minute, 10)
+	if err != nil || len(claimed) != 1 || claimed[0].Attempts != 1 {
+		t.Fatalf("claimed=%+v err=%v", claimed, err)
+	}
+	// Leased: not claimable again until the lease runs out.
+	if again, _ := store.ClaimOutboxMessages(ctx, now, time.Minute, 10); len(again) != 0 {
+		t.Fatalf("claimed twice: %+v", again)
+	}
+	id := claimed[0].ID
+	if err := store.MarkOutboxMessageFailed(ctx, id, "down", nil); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := store.RetryOutboxMessage(ctx, id, now); err != nil || !ok {
+		t.Fatalf("retry ok=%v err=%v", ok, err)
+	}
+	claimed, _ = store.ClaimOutboxMessages(ctx, now, time.Minute, 10)
+	if len(claimed) != 1 || claimed[0].Attempts != 1 || claimed[0].LastError != "down" {
+		t.Fatalf("after retry claimed=%+v", claimed)
+	}
+	if err := store.MarkOutboxMessageDelivered(ctx, id, now.Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	counts, err := store.CountOutboxMessagesByStatus(ctx)
+	if err != nil || counts[models.OutboxStatusDelivered] != 1 {
+		t.Fatalf("counts=%v err=%v", counts, err)
+	}
+	if n, err := store.DeleteDeliveredOutboxMessages(ctx, now.Add(-time.Hour)); err != nil || n != 1 {
+		t.Fatalf("deleted=%d err=%v", n, err)
+	}
+}
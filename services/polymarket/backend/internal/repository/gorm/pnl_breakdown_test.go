@@ -0,0 +1,130 @@
+package gormrepository
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestPnLBreakdowns(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.Strategy{}, &models.Opportunity{}, &models.ExecutionPlan{}, &models.PnLRecord{}, &models.MarketLabel{}, &models.CatalogChange{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	strat := &models.Strategy{Name: "arb", Params: datatypes.JSON(`{}`)}
+	if err := store.UpsertStrategy(ctx, strat); err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range []models.MarketLabel{{MarketID: "m1", Label: "sports"}, {MarketID: "m1", Label: "politics"}, {MarketID: "m2", Label: "sports"}} {
+		l := l
+		if err := store.UpsertMarketLabel(ctx, &l); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	trade := func(market, tenant, outcome string, entryHour int, hold time.Duration, pnl, roi float64) {
+		t.Helper()
+		opp := &models.Opportunity{StrategyID: strat.ID, Status: "executed", Legs: datatypes.JSON(`[]`), PrimaryMarketID: &market}
+		if err := store.InsertOpportunity(ctx, opp); err != nil {
+			t.Fatal(err)
+		}
+		entry := day.Add(time.Duration(entryHour) * time.Hour)
+		plan := &models.ExecutionPlan{OpportunityID: opp.ID, StrategyName: "arb", Tenant: tenant, Status: "executed", Legs: datatypes.JSON(`[]`), ExecutedAt: &entry}
+		if err := store.InsertExecutionPlan(ctx, plan); err != nil {
+			t.Fatal(err)
+		}
+		rec := &models.PnLRecord{PlanID: plan.ID, StrategyName: "arb", Outcome: outcome}
+		if outcome != "pending" {
+			p, r := decimal.NewFromFloat(pnl), decimal.NewFromFloat(roi)
+			settled := entry.Add(hold)
+			rec.RealizedPnL, rec.RealizedROI, rec.SettledAt = &p, &r, &settled
+		}
+		if err := store.UpsertPnLRecord(ctx, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	trade("m1", "default", "win", 1, 30*time.Minute, 10, 0.1)
+	trade("m1", "default", "loss", 9, 10*time.Hour, -4, -0.2)
+	trade("m2", "default", "win", 2, 100*time.Hour, 6, 0.3)
+	trade("m3", "desk-b", "partial", 23, 200*time.Hour, 1, 0.05)
+	// Unsettled trades are left out of every breakdown.
+	trade("m1", "default", "pending", 5, 0, 0, 0)
+
+	type want struct {
+		bucket       string
+		trades, wins int64
+		losses       int64
+		realized     float64
+	}
+	check := func(name string, rows []repository.PnLBreakdownRow, err error, wants []want) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(rows) != len(wants) {
+			t.Fatalf("%s: rows=%+v", name, rows)
+		}
+		for i, w := range wants {
+			got := rows[i]
+			if got.Bucket != w.bucket || got.Trades != w.trades || got.Wins != w.wins || got.Losses != w.losses || math.Abs(got.RealizedUSD-w.realized) > 1e-9 {
+				t.Fatalf("%s: row %d=%+v want %+v", name, i, got, w)
+			}
+			if math.Abs(got.WinRate-float64(w.wins)/float64(w.trades)) > 1e-9 {
+				t.Fatalf("%s: row %d win rate=%v", name, i, got.WinRate)
+			}
+		}
+	}
+
+	rows, err := store.PnLBreakdownByLabel(ctx, repository.PnLBreakdownParams{})
+	check("label", rows, err, []want{
+		{"sports", 3, 2, 1, 12},
+		{"politics", 2, 1, 1, 6},
+	})
+	if math.Abs(rows[0].AvgROI-0.2/3) > 1e-9 {
+		t.Fatalf("sports avg roi=%v", rows[0].AvgROI)
+	}
+
+	rows, err = store.PnLBreakdownByEntryHour(ctx, repository.PnLBreakdownParams{})
+	check("entry hour", rows, err, []want{
+		{"00-04", 2, 2, 0, 16},
+		{"08-12", 1, 0, 1, -4},
+		{"20-24", 1, 0, 0, 1},
+	})
+	rows, err = store.PnLBreakdownByEntryHour(ctx, repository.PnLBreakdownParams{BucketHours: 12})
+	check("entry half day", rows, err, []want{
+		{"00-12", 3, 2, 1, 12},
+		{"12-24", 1, 0, 0, 1},
+	})
+
+	rows, err = store.PnLBreakdownByHoldDuration(ctx, repository.PnLBreakdownParams{})
+	check("hold", rows, err, []want{
+		{"<1h", 1, 1, 0, 10},
+		{"6h-24h", 1, 0, 1, -4},
+		{"3d-7d", 1, 1, 0, 6},
+		{">=7d", 1, 0, 0, 1},
+	})
+
+	desk := "desk-b"
+	rows, err = store.PnLBreakdownByHoldDuration(ctx, repository.PnLBreakdownParams{Tenant: &desk})
+	check("hold for desk", rows, err, []want{{">=7d", 1, 0, 0, 1}})
+
+	since, until := day.Add(2*time.Hour), day.Add(24*time.Hour)
+	rows, err = store.PnLBreakdownByEntryHour(ctx, repository.PnLBreakdownParams{Since: &since, Until: &until})
+	check("settled window", rows, err, []want{{"08-12", 1, 0, 1, -4}})
+}
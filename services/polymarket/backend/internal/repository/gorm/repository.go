@@ -2,27 +2,76 @@ package gormrepository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
+	"polymarket/internal/events"
 	"polymarket/internal/models"
 	"polymarket/internal/repository"
+	"polymarket/internal/tradingday"
 )
 
 type Store struct {
 	db *gorm.DB
+	// events receives new opportunities, plan status changes and fills;
+	// nil publishes nothing.
+	events events.Publisher
+	// inTx marks a Store made by WithTx: db is the transaction.
+	inTx bool
 }
 
 func New(db *gorm.DB) *Store {
 	return &Store{db: db}
 }
 
+// WithTx returns a Store that runs in tx; its events are published with the
+// transaction where the publisher supports it.
+func (s *Store) WithTx(tx *gorm.DB) repository.Repository {
+	if s == nil || tx == nil {
+		return s
+	}
+	return &Store{db: tx, events: s.events, inTx: true}
+}
+
+// SetEventPublisher makes successful writes publish row-change events.
+func (s *Store) SetEventPublisher(p events.Publisher) {
+	if s != nil {
+		s.events = p
+	}
+}
+
+func (s *Store) publish(ctx context.Context, ev events.Event) {
+	if s.inTx {
+		s.publishTx(ctx, s.db, ev)
+		return
+	}
+	if s.events != nil {
+		s.events.Publish(ctx, ev)
+	}
+}
+
+// publishTx publishes ev as part of tx when the publisher supports it, so a
+// rolled-back write sends nothing.
+func (s *Store) publishTx(ctx context.Context, tx *gorm.DB, ev events.Event) {
+	if p, ok := s.events.(events.TxPublisher); ok && tx != nil {
+		p.PublishTx(ctx, tx, ev)
+		return
+	}
+	if s.events != nil {
+		s.events.Publish(ctx, ev)
+	}
+}
+
 func (s *Store) InTx(ctx context.Context, fn func(tx *gorm.DB) error) error {
 	if s == nil || s.db == nil {
 		return nil
@@ -114,6 +163,166 @@ func (s *Store) ListSignalSources(ctx context.Context) ([]models.SignalSource, e
 	return items, nil
 }
 
+// --- Tracked wallets ---------------------------------------------------------
+
+func (s *Store) ListWalletPositions(ctx context.Context, wallet string) ([]models.WalletPosition, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.WalletPosition{})
+	if wallet = strings.ToLower(strings.TrimSpace(wallet)); wallet != "" {
+		query = query.Where("wallet = ?", wallet)
+	}
+	var items []models.WalletPosition
+	if err := query.Order("wallet asc, current_value desc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) UpsertWalletPosition(ctx context.Context, item *models.WalletPosition) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	item.Wallet = strings.ToLower(strings.TrimSpace(item.Wallet))
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "wallet"}, {Name: "token_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"condition_id",
+			"market_id",
+			"outcome",
+			"title",
+			"size",
+			"avg_price",
+			"current_price",
+			"current_value",
+			"observed_at",
+			"updated_at",
+		}),
+	}).Create(item).Error
+}
+
+func (s *Store) DeleteWalletPosition(ctx context.Context, wallet string, tokenID string) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).
+		Where("wallet = ? AND token_id = ?", strings.ToLower(strings.TrimSpace(wallet)), strings.TrimSpace(tokenID)).
+		Delete(&models.WalletPosition{}).Error
+}
+
+func (s *Store) InsertWalletPositionChange(ctx context.Context, item *models.WalletPositionChange) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	item.Wallet = strings.ToLower(strings.TrimSpace(item.Wallet))
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) ListWalletPositionChanges(ctx context.Context, params repository.ListWalletPositionChangesParams) ([]models.WalletPositionChange, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := applyWalletPositionChangeFilters(s.db.WithContext(ctx).Model(&models.WalletPositionChange{}), params)
+	limit := normalizeLimit(params.Limit, 100)
+	offset := normalizeOffset(params.Offset)
+	var items []models.WalletPositionChange
+	if err := query.Order("observed_at desc, id desc").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountWalletPositionChanges(ctx context.Context, params repository.ListWalletPositionChangesParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var total int64
+	if err := applyWalletPositionChangeFilters(s.db.WithContext(ctx).Model(&models.WalletPositionChange{}), params).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func applyWalletPositionChangeFilters(query *gorm.DB, params repository.ListWalletPositionChangesParams) *gorm.DB {
+	if params.Wallet != nil && strings.TrimSpace(*params.Wallet) != "" {
+		query = query.Where("wallet = ?", strings.ToLower(strings.TrimSpace(*params.Wallet)))
+	}
+	if params.MarketID != nil && strings.TrimSpace(*params.MarketID) != "" {
+		query = query.Where("market_id = ?", strings.TrimSpace(*params.MarketID))
+	}
+	if params.Since != nil && !params.Since.IsZero() {
+		query = query.Where("observed_at >= ?", params.Since.UTC())
+	}
+	if params.SignalsOnly {
+		query = query.Where("signal_emitted = ?", true)
+	}
+	return query
+}
+
+// InsertMarketDataGap records a gap once; it reports false when the gap was
+// already known.
+func (s *Store) InsertMarketDataGap(ctx context.Context, item *models.MarketDataGap) (bool, error) {
+	if s == nil || s.db == nil || item == nil {
+		return false, nil
+	}
+	res := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token_id"}, {Name: "gap_start"}},
+		DoNothing: true,
+	}).Create(item)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (s *Store) UpdateMarketDataGap(ctx context.Context, item *models.MarketDataGap) error {
+	if s == nil || s.db == nil || item == nil || item.ID == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Save(item).Error
+}
+
+func (s *Store) ListMarketDataGaps(ctx context.Context, params repository.ListMarketDataGapsParams) ([]models.MarketDataGap, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := applyMarketDataGapFilters(s.db.WithContext(ctx).Model(&models.MarketDataGap{}), params)
+	order := "gap_start desc, id desc"
+	if params.OldestFirst {
+		order = "gap_start asc, id asc"
+	}
+	var items []models.MarketDataGap
+	if err := query.Order(order).Limit(normalizeLimit(params.Limit, 100)).Offset(normalizeOffset(params.Offset)).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountMarketDataGaps(ctx context.Context, params repository.ListMarketDataGapsParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var total int64
+	if err := applyMarketDataGapFilters(s.db.WithContext(ctx).Model(&models.MarketDataGap{}), params).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func applyMarketDataGapFilters(query *gorm.DB, params repository.ListMarketDataGapsParams) *gorm.DB {
+	if params.TokenID != nil && strings.TrimSpace(*params.TokenID) != "" {
+		query = query.Where("token_id = ?", strings.TrimSpace(*params.TokenID))
+	}
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	if params.Since != nil && !params.Since.IsZero() {
+		query = query.Where("gap_start >= ?", params.Since.UTC())
+	}
+	if params.MaxAttempts > 0 {
+		query = query.Where("attempts < ?", params.MaxAttempts)
+	}
+	return query
+}
+
 func (s *Store) ListMarketDataHealthCandidates(ctx context.Context, limit int, minSpreadBps float64) ([]models.MarketDataHealth, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
@@ -135,6 +344,30 @@ func (s *Store) ListMarketDataHealthCandidates(ctx context.Context, limit int, m
 	return items, nil
 }
 
+func (s *Store) MarketActivity(ctx context.Context, since time.Time, minJumpBps float64) (repository.MarketActivity, error) {
+	var out repository.MarketActivity
+	if s == nil || s.db == nil {
+		return out, nil
+	}
+	if err := s.db.WithContext(ctx).Model(&models.MarketDataHealth{}).
+		Where("updated_at >= ?", since).
+		Count(&out.Tokens).Error; err != nil {
+		return out, err
+	}
+	if err := s.db.WithContext(ctx).Model(&models.MarketDataHealth{}).
+		Where("updated_at >= ?", since).
+		Where("price_jump_bps >= ?", minJumpBps).
+		Count(&out.Jumping).Error; err != nil {
+		return out, err
+	}
+	if err := s.db.WithContext(ctx).Model(&models.LastTradePrice{}).
+		Where("trade_ts >= ?", since).
+		Count(&out.Traded).Error; err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
 func (s *Store) ListYesTokenJumpCandidates(ctx context.Context, limit int, minJumpBps float64, maxSpreadBps float64) ([]repository.TokenJumpCandidate, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
@@ -239,117 +472,175 @@ func (s *Store) SetStrategyEnabled(ctx context.Context, name string, enabled boo
 		Error
 }
 
-func (s *Store) UpdateStrategyParams(ctx context.Context, name string, params []byte) error {
+func (s *Store) SetStrategyTenant(ctx context.Context, name string, tenant string) error {
 	if s == nil || s.db == nil {
 		return nil
 	}
 	name = strings.TrimSpace(name)
-	if name == "" {
+	tenant = strings.TrimSpace(tenant)
+	if name == "" || tenant == "" {
 		return nil
 	}
 	return s.db.WithContext(ctx).
 		Model(&models.Strategy{}).
 		Where("name = ?", name).
-		Updates(map[string]any{"params": params, "updated_at": time.Now().UTC()}).
+		Updates(map[string]any{"tenant": tenant, "updated_at": time.Now().UTC()}).
 		Error
 }
 
-func (s *Store) UpdateStrategyStats(ctx context.Context, name string, stats []byte) error {
+func (s *Store) SetStrategyLaunchStage(ctx context.Context, name string, stage string) error {
 	if s == nil || s.db == nil {
 		return nil
 	}
 	name = strings.TrimSpace(name)
-	if name == "" {
+	stage = strings.TrimSpace(stage)
+	if name == "" || stage == "" {
 		return nil
 	}
 	return s.db.WithContext(ctx).
 		Model(&models.Strategy{}).
 		Where("name = ?", name).
-		Updates(map[string]any{"stats": stats, "updated_at": time.Now().UTC()}).
+		Updates(map[string]any{"launch_stage": stage, "updated_at": time.Now().UTC()}).
 		Error
 }
 
-func (s *Store) InsertOpportunity(ctx context.Context, item *models.Opportunity) error {
+func (s *Store) InsertEvaluationRun(ctx context.Context, item *models.EvaluationRun) error {
 	if s == nil || s.db == nil || item == nil {
 		return nil
 	}
 	return s.db.WithContext(ctx).Create(item).Error
 }
 
-func (s *Store) UpsertActiveOpportunity(ctx context.Context, item *models.Opportunity) error {
-	if s == nil || s.db == nil || item == nil {
-		return nil
+func (s *Store) ListEvaluationRuns(ctx context.Context, params repository.ListEvaluationRunsParams) ([]models.EvaluationRun, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
 	}
-	if item.StrategyID == 0 {
-		return s.InsertOpportunity(ctx, item)
+	query := applyEvaluationRunFilters(s.db.WithContext(ctx).Model(&models.EvaluationRun{}), params)
+	query = applyOrder(query, params.OrderBy, params.Asc, "started_at")
+	limit := normalizeLimit(params.Limit, 100)
+	offset := normalizeOffset(params.Offset)
+	var items []models.EvaluationRun
+	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
 	}
+	return items, nil
+}
 
-	keyEventID := ""
-	if item.EventID != nil {
-		keyEventID = strings.TrimSpace(*item.EventID)
+func (s *Store) CountEvaluationRuns(ctx context.Context, params repository.ListEvaluationRunsParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
 	}
-	keyMarketID := ""
-	if item.PrimaryMarketID != nil {
-		keyMarketID = strings.TrimSpace(*item.PrimaryMarketID)
+	query := applyEvaluationRunFilters(s.db.WithContext(ctx).Model(&models.EvaluationRun{}), params)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
 	}
-	if keyEventID == "" && keyMarketID == "" {
-		return s.InsertOpportunity(ctx, item)
+	return total, nil
+}
+
+func applyEvaluationRunFilters(query *gorm.DB, params repository.ListEvaluationRunsParams) *gorm.DB {
+	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
+		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+	}
+	if params.Since != nil {
+		query = query.Where("started_at >= ?", params.Since.UTC())
 	}
+	if params.Until != nil {
+		query = query.Where("started_at < ?", params.Until.UTC())
+	}
+	if params.ErrorsOnly {
+		query = query.Where("error <> ''")
+	}
+	return query
+}
 
-	var existing models.Opportunity
-	query := s.db.WithContext(ctx).
-		Model(&models.Opportunity{}).
-		Where("strategy_id = ?", item.StrategyID).
-		Where("status = ?", "active")
-	if keyEventID != "" {
-		query = query.Where("event_id = ?", keyEventID)
-	} else {
-		query = query.Where("primary_market_id = ?", keyMarketID)
+func (s *Store) DeleteEvaluationRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	if s == nil || s.db == nil || before.IsZero() {
+		return 0, nil
 	}
-	err := query.Order("created_at desc").First(&existing).Error
-	if err != nil && err != gorm.ErrRecordNotFound {
-		return err
+	res := s.db.WithContext(ctx).
+		Where("started_at < ?", before.UTC()).
+		Delete(&models.EvaluationRun{})
+	return res.RowsAffected, res.Error
+}
+
+func (s *Store) ReserveIdempotencyKey(ctx context.Context, item *models.IdempotencyKey) (*models.IdempotencyKey, bool, error) {
+	if s == nil || s.db == nil || item == nil {
+		return nil, false, nil
 	}
-	if err == gorm.ErrRecordNotFound {
-		return s.InsertOpportunity(ctx, item)
+	db := s.db.WithContext(ctx)
+	// A stale row for the key is cleared first so the insert can claim it.
+	if err := db.Where("tenant = ? AND idempotency_key = ? AND expires_at <= ?", item.Tenant, item.Key, time.Now().UTC()).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return nil, false, err
 	}
-	// Update core fields in-place, keep status/strategy/event stable.
-	updates := map[string]any{
-		"primary_market_id": item.PrimaryMarketID,
-		"market_ids":        item.MarketIDs,
-		"edge_pct":          item.EdgePct,
-		"edge_usd":          item.EdgeUSD,
-		"max_size":          item.MaxSize,
-		"confidence":        item.Confidence,
-		"risk_score":        item.RiskScore,
-		"decay_type":        item.DecayType,
-		"expires_at":        item.ExpiresAt,
-		"legs":              item.Legs,
-		"signal_ids":        item.SignalIDs,
-		"reasoning":         item.Reasoning,
-		"data_age_ms":       item.DataAgeMs,
-		"warnings":          item.Warnings,
-		"updated_at":        time.Now().UTC(),
+	res := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant"}, {Name: "idempotency_key"}},
+		DoNothing: true,
+	}).Create(item)
+	if res.Error != nil {
+		return nil, false, res.Error
 	}
-	return s.db.WithContext(ctx).
-		Model(&models.Opportunity{}).
-		Where("id = ?", existing.ID).
-		Updates(updates).Error
+	if res.RowsAffected > 0 {
+		return item, true, nil
+	}
+	var existing models.IdempotencyKey
+	if err := db.Where("tenant = ? AND idempotency_key = ?", item.Tenant, item.Key).First(&existing).Error; err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
 }
 
-func (s *Store) GetOpportunityByID(ctx context.Context, id uint64) (*models.Opportunity, error) {
+func (s *Store) CompleteIdempotencyKey(ctx context.Context, id uint64, status int, contentType string, body string, expiresAt time.Time) error {
 	if s == nil || s.db == nil {
-		return nil, nil
+		return nil
 	}
-	if id == 0 {
+	return s.db.WithContext(ctx).Model(&models.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]any{
+		"status":          "complete",
+		"response_status": status,
+		"content_type":    contentType,
+		"response_body":   body,
+		"expires_at":      expiresAt.UTC(),
+	}).Error
+}
+
+func (s *Store) RenewIdempotencyKey(ctx context.Context, id uint64, expiresAt time.Time) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Model(&models.IdempotencyKey{}).
+		Where("id = ? AND status = ?", id, "pending").
+		Update("expires_at", expiresAt.UTC()).Error
+}
+
+func (s *Store) DeleteIdempotencyKey(ctx context.Context, id uint64) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.IdempotencyKey{}).Error
+}
+
+func (s *Store) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	res := s.db.WithContext(ctx).Where("expires_at <= ?", now.UTC()).Delete(&models.IdempotencyKey{})
+	return res.RowsAffected, res.Error
+}
+
+func (s *Store) InsertComplianceOverride(ctx context.Context, item *models.ComplianceOverride) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) GetComplianceOverrideByID(ctx context.Context, id uint64) (*models.ComplianceOverride, error) {
+	if s == nil || s.db == nil || id == 0 {
 		return nil, nil
 	}
-	var item models.Opportunity
-	err := s.db.WithContext(ctx).
-		Model(&models.Opportunity{}).
-		Preload("Strategy").
-		Where("id = ?", id).
-		First(&item).Error
+	var item models.ComplianceOverride
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -359,248 +650,272 @@ func (s *Store) GetOpportunityByID(ctx context.Context, id uint64) (*models.Oppo
 	return &item, nil
 }
 
-func (s *Store) ListOpportunities(ctx context.Context, params repository.ListOpportunitiesParams) ([]models.Opportunity, error) {
+func (s *Store) ListComplianceOverrides(ctx context.Context, params repository.ListComplianceOverridesParams) ([]models.ComplianceOverride, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.Opportunity{}).Preload("Strategy")
-	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
-		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	limit := normalizeLimit(params.Limit, 100)
+	offset := normalizeOffset(params.Offset)
+	var items []models.ComplianceOverride
+	query := applyComplianceOverrideFilters(s.db.WithContext(ctx).Model(&models.ComplianceOverride{}), params)
+	if err := query.Order("created_at desc, id desc").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
 	}
-	needsStratJoin := (params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "") ||
-		(params.Category != nil && strings.TrimSpace(*params.Category) != "")
-	if needsStratJoin {
-		query = query.Joins("JOIN strategies ON strategies.id = opportunities.strategy_id").
-			Where("1 = 1")
-		if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
-			query = query.Where("strategies.name = ?", strings.TrimSpace(*params.StrategyName))
-		}
-		if params.Category != nil && strings.TrimSpace(*params.Category) != "" {
-			query = query.Where("strategies.category = ?", strings.TrimSpace(*params.Category))
-		}
+	return items, nil
+}
+
+func (s *Store) CountComplianceOverrides(ctx context.Context, params repository.ListComplianceOverridesParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
 	}
-	if params.MinEdgePct != nil {
-		query = query.Where("edge_pct >= ?", *params.MinEdgePct)
+	var total int64
+	if err := applyComplianceOverrideFilters(s.db.WithContext(ctx).Model(&models.ComplianceOverride{}), params).Count(&total).Error; err != nil {
+		return 0, err
 	}
-	if params.MinConfidence != nil {
-		query = query.Where("confidence >= ?", *params.MinConfidence)
+	return total, nil
+}
+
+func applyComplianceOverrideFilters(query *gorm.DB, params repository.ListComplianceOverridesParams) *gorm.DB {
+	if params.MarketID != nil && strings.TrimSpace(*params.MarketID) != "" {
+		query = query.Where("market_id = ?", strings.TrimSpace(*params.MarketID))
 	}
-	query = applyOrder(query, params.OrderBy, params.Asc, "created_at")
-	limit := normalizeLimit(params.Limit, 200)
-	offset := normalizeOffset(params.Offset)
-	var items []models.Opportunity
-	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+	if params.ActiveAt != nil && !params.ActiveAt.IsZero() {
+		query = query.Where("revoked_at IS NULL AND expires_at > ?", params.ActiveAt.UTC())
+	}
+	return query
+}
+
+func (s *Store) ListActiveComplianceOverrides(ctx context.Context, marketIDs []string, now time.Time) ([]models.ComplianceOverride, error) {
+	marketIDs = cleanStrings(marketIDs)
+	if s == nil || s.db == nil || len(marketIDs) == 0 {
+		return nil, nil
+	}
+	var items []models.ComplianceOverride
+	if err := s.db.WithContext(ctx).
+		Where("market_id IN ? AND revoked_at IS NULL AND expires_at > ?", marketIDs, now.UTC()).
+		Order("expires_at desc").
+		Find(&items).Error; err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
-func (s *Store) CountOpportunities(ctx context.Context, params repository.ListOpportunitiesParams) (int64, error) {
-	if s == nil || s.db == nil {
-		return 0, nil
+func (s *Store) RevokeComplianceOverride(ctx context.Context, id uint64, reason string, at time.Time) error {
+	if s == nil || s.db == nil || id == 0 {
+		return nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.Opportunity{})
-	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
-		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	return s.db.WithContext(ctx).Model(&models.ComplianceOverride{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{"revoked_at": at.UTC(), "revoke_reason": reason}).Error
+}
+
+func (s *Store) InsertCampaign(ctx context.Context, item *models.Campaign) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
 	}
-	needsStratJoin := (params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "") ||
-		(params.Category != nil && strings.TrimSpace(*params.Category) != "")
-	if needsStratJoin {
-		query = query.Joins("JOIN strategies ON strategies.id = opportunities.strategy_id").
-			Where("1 = 1")
-		if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
-			query = query.Where("strategies.name = ?", strings.TrimSpace(*params.StrategyName))
-		}
-		if params.Category != nil && strings.TrimSpace(*params.Category) != "" {
-			query = query.Where("strategies.category = ?", strings.TrimSpace(*params.Category))
-		}
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) UpdateCampaign(ctx context.Context, item *models.Campaign) error {
+	if s == nil || s.db == nil || item == nil || item.ID == 0 {
+		return nil
 	}
-	if params.MinEdgePct != nil {
-		query = query.Where("edge_pct >= ?", *params.MinEdgePct)
+	return s.db.WithContext(ctx).Save(item).Error
+}
+
+func (s *Store) GetCampaignByID(ctx context.Context, id uint64) (*models.Campaign, error) {
+	if s == nil || s.db == nil || id == 0 {
+		return nil, nil
 	}
-	if params.MinConfidence != nil {
-		query = query.Where("confidence >= ?", *params.MinConfidence)
+	var item models.Campaign
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
 	}
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return 0, err
+	if err != nil {
+		return nil, err
 	}
-	return total, nil
+	return &item, nil
 }
 
-func (s *Store) UpdateOpportunityStatus(ctx context.Context, id uint64, status string) error {
-	if s == nil || s.db == nil {
-		return nil
+func (s *Store) campaignsQuery(ctx context.Context, params repository.ListCampaignsParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.Campaign{})
+	if params.Tenant != nil {
+		query = query.Where("tenant = ?", strings.TrimSpace(*params.Tenant))
 	}
-	if id == 0 || strings.TrimSpace(status) == "" {
-		return nil
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
 	}
-	return s.db.WithContext(ctx).
-		Model(&models.Opportunity{}).
-		Where("id = ?", id).
-		Updates(map[string]any{"status": strings.TrimSpace(status), "updated_at": time.Now().UTC()}).
-		Error
+	return query
 }
 
-func (s *Store) ExpireDueOpportunities(ctx context.Context, now time.Time) (int64, error) {
+func (s *Store) ListCampaigns(ctx context.Context, params repository.ListCampaignsParams) ([]models.Campaign, error) {
 	if s == nil || s.db == nil {
-		return 0, nil
-	}
-	if now.IsZero() {
-		now = time.Now().UTC()
+		return nil, nil
 	}
-	res := s.db.WithContext(ctx).
-		Model(&models.Opportunity{}).
-		Where("status = ?", "active").
-		Where("expires_at IS NOT NULL").
-		Where("expires_at < ?", now).
-		Updates(map[string]any{"status": "expired", "updated_at": time.Now().UTC()})
-	return res.RowsAffected, res.Error
+	var items []models.Campaign
+	err := s.campaignsQuery(ctx, params).
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 100)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
 }
 
-func (s *Store) CountActiveOpportunities(ctx context.Context) (int64, error) {
+func (s *Store) CountCampaigns(ctx context.Context, params repository.ListCampaignsParams) (int64, error) {
 	if s == nil || s.db == nil {
 		return 0, nil
 	}
 	var total int64
-	err := s.db.WithContext(ctx).
-		Model(&models.Opportunity{}).
-		Where("status = ?", "active").
-		Count(&total).Error
+	err := s.campaignsQuery(ctx, params).Count(&total).Error
 	return total, err
 }
 
-func (s *Store) ListOldestActiveOpportunityIDs(ctx context.Context, limit int) ([]uint64, error) {
-	if s == nil || s.db == nil {
-		return nil, nil
+func (s *Store) AssignPlansToCampaign(ctx context.Context, campaignID *uint64, planIDs []uint64) (int64, error) {
+	if s == nil || s.db == nil || len(planIDs) == 0 {
+		return 0, nil
 	}
-	limit = normalizeLimit(limit, 200)
-	var ids []uint64
-	err := s.db.WithContext(ctx).
-		Model(&models.Opportunity{}).
-		Where("status = ?", "active").
-		Order("created_at asc").
-		Limit(limit).
-		Pluck("id", &ids).Error
-	return ids, err
+	res := s.db.WithContext(ctx).Model(&models.ExecutionPlan{}).
+		Where("id IN ?", planIDs).
+		Updates(map[string]any{"campaign_id": campaignID, "updated_at": time.Now().UTC()})
+	return res.RowsAffected, res.Error
 }
 
-func (s *Store) BulkUpdateOpportunityStatus(ctx context.Context, ids []uint64, status string) (int64, error) {
-	if s == nil || s.db == nil {
-		return 0, nil
-	}
-	if len(ids) == 0 || strings.TrimSpace(status) == "" {
+func (s *Store) AssignOpportunitiesToCampaign(ctx context.Context, campaignID *uint64, opportunityIDs []uint64) (int64, error) {
+	if s == nil || s.db == nil || len(opportunityIDs) == 0 {
 		return 0, nil
 	}
-	res := s.db.WithContext(ctx).
-		Model(&models.Opportunity{}).
-		Where("id IN ?", ids).
-		Updates(map[string]any{"status": strings.TrimSpace(status), "updated_at": time.Now().UTC()})
+	res := s.db.WithContext(ctx).Model(&models.Opportunity{}).
+		Where("id IN ?", opportunityIDs).
+		Updates(map[string]any{"campaign_id": campaignID, "updated_at": time.Now().UTC()})
 	return res.RowsAffected, res.Error
 }
 
-func (s *Store) UpsertMarketLabel(ctx context.Context, item *models.MarketLabel) error {
-	if s == nil || s.db == nil || item == nil {
-		return nil
+func (s *Store) CampaignStats(ctx context.Context, campaignID uint64) (repository.CampaignStats, error) {
+	if s == nil || s.db == nil || campaignID == 0 {
+		return repository.CampaignStats{}, nil
 	}
-	if strings.TrimSpace(item.MarketID) == "" || strings.TrimSpace(item.Label) == "" {
+	var row struct {
+		Plans        int64
+		LivePlans    int64
+		PendingPlans int64
+		SettledPlans int64
+		CommittedUSD float64
+		RealizedUSD  float64
+	}
+	err := s.db.WithContext(ctx).
+		Table("execution_plans").
+		Joins("LEFT JOIN pnl_records ON pnl_records.plan_id = execution_plans.id").
+		Select(`
+			COUNT(*) AS plans,
+			COALESCE(SUM(CASE WHEN execution_plans.status NOT IN ('cancelled','failed','preflight_fail') THEN 1 ELSE 0 END),0) AS live_plans,
+			COALESCE(SUM(CASE WHEN execution_plans.status IN ('draft','preflight_pass') THEN 1 ELSE 0 END),0) AS pending_plans,
+			COALESCE(SUM(CASE WHEN pnl_records.settled_at IS NOT NULL THEN 1 ELSE 0 END),0) AS settled_plans,
+			COALESCE(SUM(CASE WHEN execution_plans.status NOT IN ('cancelled','failed','preflight_fail') THEN execution_plans.planned_size_usd ELSE 0 END),0) AS committed_usd,
+			COALESCE(SUM(COALESCE(pnl_records.realized_pnl,0)),0) AS realized_usd
+		`).
+		Where("execution_plans.campaign_id = ?", campaignID).
+		Scan(&row).Error
+	if err != nil {
+		return repository.CampaignStats{}, err
+	}
+	var opps int64
+	if err := s.db.WithContext(ctx).Model(&models.Opportunity{}).Where("campaign_id = ?", campaignID).Count(&opps).Error; err != nil {
+		return repository.CampaignStats{}, err
+	}
+	return repository.CampaignStats{
+		Plans:          row.Plans,
+		LivePlans:      row.LivePlans,
+		PendingPlans:   row.PendingPlans,
+		SettledPlans:   row.SettledPlans,
+		Opportunities:  opps,
+		CommittedUSD:   row.CommittedUSD,
+		RealizedPnLUSD: row.RealizedUSD,
+	}, nil
+}
+
+func (s *Store) InsertResearchSnapshot(ctx context.Context, item *models.ResearchSnapshot) error {
+	if s == nil || s.db == nil || item == nil {
 		return nil
 	}
-	// Uniqueness is enforced by uniq_market_label (market_id, label).
-	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "market_id"}, {Name: "label"}},
-		DoNothing: true,
-	}).Create(item).Error
+	return s.db.WithContext(ctx).Create(item).Error
 }
 
-func (s *Store) ListMarketLabels(ctx context.Context, params repository.ListMarketLabelsParams) ([]models.MarketLabel, error) {
-	if s == nil || s.db == nil {
+func (s *Store) GetResearchSnapshotByID(ctx context.Context, id uint64) (*models.ResearchSnapshot, error) {
+	if s == nil || s.db == nil || id == 0 {
 		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.MarketLabel{})
-	if params.MarketID != nil && strings.TrimSpace(*params.MarketID) != "" {
-		query = query.Where("market_id = ?", strings.TrimSpace(*params.MarketID))
-	}
-	if params.Label != nil && strings.TrimSpace(*params.Label) != "" {
-		query = query.Where("label = ?", strings.TrimSpace(*params.Label))
-	}
-	if params.SubLabel != nil && strings.TrimSpace(*params.SubLabel) != "" {
-		query = query.Where("sub_label = ?", strings.TrimSpace(*params.SubLabel))
+	var item models.ResearchSnapshot
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
 	}
-	query = applyOrder(query, params.OrderBy, params.Asc, "created_at")
-	limit := normalizeLimit(params.Limit, 500)
-	offset := normalizeOffset(params.Offset)
-	var items []models.MarketLabel
-	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+	if err != nil {
 		return nil, err
 	}
-	return items, nil
+	return &item, nil
 }
 
-func (s *Store) DeleteMarketLabel(ctx context.Context, marketID string, label string) error {
-	if s == nil || s.db == nil {
-		return nil
+func (s *Store) researchSnapshotsQuery(ctx context.Context, params repository.ListResearchSnapshotsParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.ResearchSnapshot{})
+	if params.Tenant != nil && strings.TrimSpace(*params.Tenant) != "" {
+		query = query.Where("tenant = ?", strings.TrimSpace(*params.Tenant))
 	}
-	marketID = strings.TrimSpace(marketID)
-	label = strings.TrimSpace(label)
-	if marketID == "" || label == "" {
-		return nil
+	if params.Name != nil && strings.TrimSpace(*params.Name) != "" {
+		query = query.Where(s.ilike("name"), "%"+strings.TrimSpace(*params.Name)+"%")
 	}
-	return s.db.WithContext(ctx).
-		Where("market_id = ? AND label = ?", marketID, label).
-		Delete(&models.MarketLabel{}).Error
+	return query
 }
 
-func (s *Store) ListTagsByEventIDs(ctx context.Context, eventIDs []string) (map[string][]models.Tag, error) {
+func (s *Store) ListResearchSnapshots(ctx context.Context, params repository.ListResearchSnapshotsParams) ([]models.ResearchSnapshot, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if len(eventIDs) == 0 {
-		return map[string][]models.Tag{}, nil
-	}
-	type row struct {
-		EventID string
-		Tag     models.Tag
-	}
-	var rows []struct {
-		EventID string
-		ID      string
-		Label   string
-		Slug    string
+	var items []models.ResearchSnapshot
+	err := s.researchSnapshotsQuery(ctx, params).
+		Omit("payload").
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 50)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) CountResearchSnapshots(ctx context.Context, params repository.ListResearchSnapshotsParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
 	}
-	if err := s.db.WithContext(ctx).
-		Table("catalog_event_tags AS et").
-		Select("et.event_id AS event_id, t.id AS id, t.label AS label, t.slug AS slug").
-		Joins("JOIN catalog_tags AS t ON t.id = et.tag_id").
-		Where("et.event_id IN ?", eventIDs).
-		Scan(&rows).Error; err != nil {
-		return nil, err
+	var total int64
+	err := s.researchSnapshotsQuery(ctx, params).Count(&total).Error
+	return total, err
+}
+
+func (s *Store) CountResearchSnapshotsByIDs(ctx context.Context, ids []uint64, tenant *string) (int64, error) {
+	if s == nil || s.db == nil || len(ids) == 0 {
+		return 0, nil
 	}
-	out := map[string][]models.Tag{}
-	for _, r := range rows {
-		out[r.EventID] = append(out[r.EventID], models.Tag{ID: r.ID, Label: r.Label, Slug: r.Slug})
+	query := s.db.WithContext(ctx).Model(&models.ResearchSnapshot{}).Where("id IN ?", ids)
+	if tenant != nil && strings.TrimSpace(*tenant) != "" {
+		query = query.Where("tenant = ?", strings.TrimSpace(*tenant))
 	}
-	return out, nil
+	var total int64
+	err := query.Count(&total).Error
+	return total, err
 }
 
-// --- Execution & Analytics (L6) ---------------------------------------------
-
-func (s *Store) InsertExecutionPlan(ctx context.Context, item *models.ExecutionPlan) error {
+func (s *Store) InsertAnalyticsExport(ctx context.Context, item *models.AnalyticsExport) error {
 	if s == nil || s.db == nil || item == nil {
 		return nil
 	}
 	return s.db.WithContext(ctx).Create(item).Error
 }
 
-func (s *Store) GetExecutionPlanByID(ctx context.Context, id uint64) (*models.ExecutionPlan, error) {
-	if s == nil || s.db == nil {
+func (s *Store) GetAnalyticsExportByID(ctx context.Context, id uint64) (*models.AnalyticsExport, error) {
+	if s == nil || s.db == nil || id == 0 {
 		return nil, nil
 	}
-	if id == 0 {
-		return nil, nil
-	}
-	var item models.ExecutionPlan
-	err := s.db.WithContext(ctx).Model(&models.ExecutionPlan{}).Where("id = ?", id).First(&item).Error
+	var item models.AnalyticsExport
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -610,508 +925,724 @@ func (s *Store) GetExecutionPlanByID(ctx context.Context, id uint64) (*models.Ex
 	return &item, nil
 }
 
-func (s *Store) ListExecutionPlans(ctx context.Context, params repository.ListExecutionPlansParams) ([]models.ExecutionPlan, error) {
+func (s *Store) analyticsExportsQuery(ctx context.Context, params repository.ListAnalyticsExportsParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.AnalyticsExport{})
+	if params.Tenant != nil && strings.TrimSpace(*params.Tenant) != "" {
+		query = query.Where("tenant = ?", strings.TrimSpace(*params.Tenant))
+	}
+	return query
+}
+
+func (s *Store) ListAnalyticsExports(ctx context.Context, params repository.ListAnalyticsExportsParams) ([]models.AnalyticsExport, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.ExecutionPlan{})
-	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
-		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
-	}
-	query = applyOrder(query, params.OrderBy, params.Asc, "created_at")
-	limit := normalizeLimit(params.Limit, 200)
-	offset := normalizeOffset(params.Offset)
-	var items []models.ExecutionPlan
-	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	var items []models.AnalyticsExport
+	err := s.analyticsExportsQuery(ctx, params).
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 50)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
 }
 
-func (s *Store) CountExecutionPlans(ctx context.Context, params repository.ListExecutionPlansParams) (int64, error) {
+func (s *Store) CountAnalyticsExports(ctx context.Context, params repository.ListAnalyticsExportsParams) (int64, error) {
 	if s == nil || s.db == nil {
 		return 0, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.ExecutionPlan{})
-	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
-		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
-	}
 	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return 0, err
+	err := s.analyticsExportsQuery(ctx, params).Count(&total).Error
+	return total, err
+}
+
+func (s *Store) DeleteAnalyticsExport(ctx context.Context, id uint64) error {
+	if s == nil || s.db == nil || id == 0 {
+		return nil
 	}
-	return total, nil
+	return s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.AnalyticsExport{}).Error
 }
 
-func (s *Store) ListExecutionPlansByStatuses(ctx context.Context, statuses []string, limit int) ([]models.ExecutionPlan, error) {
-	if s == nil || s.db == nil {
-		return nil, nil
+func (s *Store) RecordAnalyticsExportRun(ctx context.Context, id uint64, watermark string, at time.Time) error {
+	if s == nil || s.db == nil || id == 0 {
+		return nil
 	}
-	statuses = cleanStrings(statuses)
-	if len(statuses) == 0 {
-		return nil, nil
+	return s.db.WithContext(ctx).Model(&models.AnalyticsExport{}).Where("id = ?", id).Updates(map[string]any{
+		"run_count":      gorm.Expr("run_count + 1"),
+		"last_watermark": watermark,
+		"last_run_at":    at.UTC(),
+	}).Error
+}
+
+func (s *Store) InsertCashOperation(ctx context.Context, item *models.CashOperation) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
 	}
-	limit = normalizeLimit(limit, 5000)
-	var items []models.ExecutionPlan
-	if err := s.db.WithContext(ctx).
-		Model(&models.ExecutionPlan{}).
-		Where("status IN ?", statuses).
-		Order("created_at desc").
-		Limit(limit).
-		Find(&items).Error; err != nil {
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) UpdateCashOperation(ctx context.Context, item *models.CashOperation) error {
+	if s == nil || s.db == nil || item == nil || item.ID == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Save(item).Error
+}
+
+func (s *Store) GetCashOperationByID(ctx context.Context, id uint64) (*models.CashOperation, error) {
+	if s == nil || s.db == nil || id == 0 {
+		return nil, nil
+	}
+	var item models.CashOperation
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
 		return nil, err
 	}
-	return items, nil
+	return &item, nil
 }
 
-func (s *Store) UpdateExecutionPlanStatus(ctx context.Context, id uint64, status string) error {
-	if s == nil || s.db == nil {
-		return nil
+func (s *Store) cashOperationsQuery(ctx context.Context, params repository.ListCashOperationsParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.CashOperation{})
+	if params.Tenant != nil {
+		query = query.Where("tenant = ?", strings.TrimSpace(*params.Tenant))
 	}
-	if id == 0 || strings.TrimSpace(status) == "" {
-		return nil
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
 	}
-	return s.db.WithContext(ctx).
-		Model(&models.ExecutionPlan{}).
-		Where("id = ?", id).
-		Updates(map[string]any{"status": strings.TrimSpace(status), "updated_at": time.Now().UTC()}).
-		Error
+	if params.Kind != nil && strings.TrimSpace(*params.Kind) != "" {
+		query = query.Where("kind = ?", strings.TrimSpace(*params.Kind))
+	}
+	return query
 }
 
-func (s *Store) UpdateExecutionPlanPreflight(ctx context.Context, id uint64, status string, preflightResult []byte) error {
+func (s *Store) ListCashOperations(ctx context.Context, params repository.ListCashOperationsParams) ([]models.CashOperation, error) {
 	if s == nil || s.db == nil {
-		return nil
-	}
-	if id == 0 || strings.TrimSpace(status) == "" {
-		return nil
+		return nil, nil
 	}
-	updates := map[string]any{
-		"status":           strings.TrimSpace(status),
-		"preflight_result": preflightResult,
-		"updated_at":       time.Now().UTC(),
+	var items []models.CashOperation
+	err := s.cashOperationsQuery(ctx, params).
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 100)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) CountCashOperations(ctx context.Context, params repository.ListCashOperationsParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
 	}
-	return s.db.WithContext(ctx).
-		Model(&models.ExecutionPlan{}).
-		Where("id = ?", id).
-		Updates(updates).Error
+	var total int64
+	err := s.cashOperationsQuery(ctx, params).Count(&total).Error
+	return total, err
 }
 
-func (s *Store) UpdateExecutionPlanExecutedAt(ctx context.Context, id uint64, status string, executedAt *time.Time) error {
+func (s *Store) CashBalance(ctx context.Context, tenant *string) (repository.CashBalance, error) {
 	if s == nil || s.db == nil {
-		return nil
+		return repository.CashBalance{}, nil
 	}
-	if id == 0 || strings.TrimSpace(status) == "" {
-		return nil
+	var row struct {
+		Deposits    decimal.Decimal
+		Withdrawals decimal.Decimal
+		Reserved    decimal.Decimal
+		Entries     int64
 	}
-	updates := map[string]any{
-		"status":      strings.TrimSpace(status),
-		"executed_at": executedAt,
-		"updated_at":  time.Now().UTC(),
+	settled := []string{models.CashOpCompleted, models.CashOpReconciled}
+	query := s.db.WithContext(ctx).
+		Model(&models.CashOperation{}).
+		Select(`
+			COALESCE(SUM(CASE WHEN kind = ? AND status IN ? THEN amount_usd ELSE 0 END),0) AS deposits,
+			COALESCE(SUM(CASE WHEN kind = ? AND status IN ? THEN amount_usd ELSE 0 END),0) AS withdrawals,
+			COALESCE(SUM(CASE WHEN kind = ? AND status = ? THEN amount_usd ELSE 0 END),0) AS reserved,
+			COUNT(*) FILTER (WHERE status IN ?) AS entries
+		`, models.CashOpDeposit, settled, models.CashOpWithdrawal, settled, models.CashOpWithdrawal, models.CashOpApproved, settled)
+	if tenant != nil {
+		query = query.Where("tenant = ?", strings.TrimSpace(*tenant))
 	}
-	return s.db.WithContext(ctx).
-		Model(&models.ExecutionPlan{}).
-		Where("id = ?", id).
-		Updates(updates).Error
+	if err := query.Scan(&row).Error; err != nil {
+		return repository.CashBalance{}, err
+	}
+	return repository.CashBalance{DepositsUSD: row.Deposits, WithdrawalsUSD: row.Withdrawals, ReservedUSD: row.Reserved, Entries: row.Entries}, nil
 }
 
-func (s *Store) CountExecutionPlansByStrategySince(ctx context.Context, strategyName string, since time.Time) (int64, error) {
+func retentionColumn(table string) (string, error) {
+	column, ok := repository.RetentionColumns[table]
+	if !ok {
+		return "", fmt.Errorf("table %q has no retention column", table)
+	}
+	return column, nil
+}
+
+func (s *Store) RetentionBacklog(ctx context.Context, table string, before time.Time) (repository.RetentionBacklog, error) {
+	var out repository.RetentionBacklog
 	if s == nil || s.db == nil {
-		return 0, nil
+		return out, nil
 	}
-	strategyName = strings.TrimSpace(strategyName)
-	if strategyName == "" || since.IsZero() {
+	column, err := retentionColumn(table)
+	if err != nil {
+		return out, err
+	}
+	var row struct {
+		RowCount int64
+		Oldest   sqlTime
+	}
+	if err := s.db.WithContext(ctx).Table(table).
+		Select("COUNT(*) AS row_count, MIN("+column+") AS oldest").
+		Where(column+" < ?", before.UTC()).
+		Scan(&row).Error; err != nil {
+		return out, err
+	}
+	out.Rows = row.RowCount
+	out.Oldest = row.Oldest.Ptr()
+	return out, nil
+}
+
+func (s *Store) PurgeRetention(ctx context.Context, table string, before time.Time, limit int) (int64, error) {
+	if s == nil || s.db == nil || before.IsZero() {
 		return 0, nil
 	}
-	query := s.db.WithContext(ctx).
-		Model(&models.ExecutionPlan{}).
-		Where("strategy_name = ?", strategyName).
-		Where("created_at >= ?", since.UTC())
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
+	column, err := retentionColumn(table)
+	if err != nil {
 		return 0, err
 	}
-	return total, nil
+	if limit <= 0 {
+		limit = 1000
+	}
+	rowID := s.rowID()
+	res := s.db.WithContext(ctx).Exec(
+		"DELETE FROM "+table+" WHERE "+rowID+" IN (SELECT "+rowID+" FROM "+table+" WHERE "+column+" < ? LIMIT ?)",
+		before.UTC(), limit,
+	)
+	return res.RowsAffected, res.Error
 }
 
-func (s *Store) InsertFill(ctx context.Context, item *models.Fill) error {
+func (s *Store) InsertCostForecast(ctx context.Context, item *models.CostForecast) error {
 	if s == nil || s.db == nil || item == nil {
 		return nil
 	}
 	return s.db.WithContext(ctx).Create(item).Error
 }
 
-func (s *Store) ListFillsByPlanID(ctx context.Context, planID uint64) ([]models.Fill, error) {
-	if s == nil || s.db == nil {
+func (s *Store) GetCostForecastByPlanID(ctx context.Context, planID uint64) (*models.CostForecast, error) {
+	if s == nil || s.db == nil || planID == 0 {
 		return nil, nil
 	}
-	if planID == 0 {
+	var item models.CostForecast
+	err := s.db.WithContext(ctx).Where("plan_id = ?", planID).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
-	var items []models.Fill
-	if err := s.db.WithContext(ctx).
-		Model(&models.Fill{}).
-		Where("plan_id = ?", planID).
-		Order("filled_at asc").
-		Find(&items).Error; err != nil {
+	if err != nil {
 		return nil, err
 	}
-	return items, nil
+	return &item, nil
 }
 
-func (s *Store) UpsertPnLRecord(ctx context.Context, item *models.PnLRecord) error {
-	if s == nil || s.db == nil || item == nil {
+func (s *Store) InsertRiskDecisions(ctx context.Context, items []models.RiskDecision) error {
+	if s == nil || s.db == nil || len(items) == 0 {
 		return nil
 	}
-	if item.PlanID == 0 {
-		return s.db.WithContext(ctx).Create(item).Error
+	return s.db.WithContext(ctx).CreateInBatches(items, 200).Error
+}
+
+func (s *Store) riskDecisionsQuery(ctx context.Context, params repository.ListRiskDecisionsParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.RiskDecision{})
+	switch {
+	case params.OpportunityID != nil && len(params.OpportunityKeys) > 0:
+		query = query.Where("opportunity_id = ? OR opportunity_key IN ?", *params.OpportunityID, params.OpportunityKeys)
+	case params.OpportunityID != nil:
+		query = query.Where("opportunity_id = ?", *params.OpportunityID)
+	case len(params.OpportunityKeys) > 0:
+		query = query.Where("opportunity_key IN ?", params.OpportunityKeys)
 	}
-	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "plan_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"strategy_name", "expected_edge", "realized_pnl", "realized_roi", "slippage_loss", "outcome", "failure_reason", "settled_at", "notes"}),
-	}).Create(item).Error
+	if params.StrategyID != nil {
+		query = query.Where("strategy_id = ?", *params.StrategyID)
+	}
+	if params.Tenant != nil {
+		query = query.Where("tenant = ?", strings.TrimSpace(*params.Tenant))
+	}
+	if params.Verdict != nil && strings.TrimSpace(*params.Verdict) != "" {
+		query = query.Where("verdict = ?", strings.TrimSpace(*params.Verdict))
+	}
+	if params.RejectedBy != nil && strings.TrimSpace(*params.RejectedBy) != "" {
+		query = query.Where("rejected_by = ?", strings.TrimSpace(*params.RejectedBy))
+	}
+	if params.Since != nil {
+		query = query.Where("created_at >= ?", params.Since.UTC())
+	}
+	if params.Until != nil {
+		query = query.Where("created_at < ?", params.Until.UTC())
+	}
+	return query
 }
 
-func (s *Store) GetPnLRecordByPlanID(ctx context.Context, planID uint64) (*models.PnLRecord, error) {
+func (s *Store) ListRiskDecisions(ctx context.Context, params repository.ListRiskDecisionsParams) ([]models.RiskDecision, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if planID == 0 {
-		return nil, nil
-	}
-	var item models.PnLRecord
-	err := s.db.WithContext(ctx).
-		Where("plan_id = ?", planID).
-		First(&item).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return &item, nil
+	var items []models.RiskDecision
+	err := s.riskDecisionsQuery(ctx, params).
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 100)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
 }
 
-func (s *Store) SumRealizedPnLSince(ctx context.Context, since time.Time) (decimal.Decimal, error) {
+func (s *Store) CountRiskDecisions(ctx context.Context, params repository.ListRiskDecisionsParams) (int64, error) {
 	if s == nil || s.db == nil {
-		return decimal.Zero, nil
+		return 0, nil
 	}
-	if since.IsZero() {
-		return decimal.Zero, nil
+	var total int64
+	err := s.riskDecisionsQuery(ctx, params).Count(&total).Error
+	return total, err
+}
+
+// categoryEvents selects the ids of events tagged with category by slug or
+// label.
+func (s *Store) categoryEvents(ctx context.Context, category string) *gorm.DB {
+	category = strings.ToLower(strings.TrimSpace(category))
+	return s.db.WithContext(ctx).
+		Table("catalog_event_tags AS et").
+		Select("et.event_id").
+		Joins("JOIN catalog_tags AS t ON t.id = et.tag_id").
+		Where("LOWER(t.slug) = ? OR LOWER(t.label) = ?", category, category)
+}
+
+func (s *Store) ListCategoryTokens(ctx context.Context, category string, limit int) ([]models.Token, error) {
+	if s == nil || s.db == nil || strings.TrimSpace(category) == "" {
+		return nil, nil
 	}
-	var out float64
+	var items []models.Token
 	err := s.db.WithContext(ctx).
-		Table("pnl_records").
-		Select("COALESCE(SUM(COALESCE(realized_pnl,0)),0)").
-		Where("created_at >= ?", since.UTC()).
-		Scan(&out).Error
-	if err != nil {
-		return decimal.Zero, err
+		Table("catalog_tokens AS tk").
+		Select("tk.*").
+		Joins("JOIN catalog_markets AS m ON m.id = tk.market_id").
+		Where("tk.outcome_index = 0 AND m.active = ? AND m.closed = ?", true, false).
+		Where("m.event_id IN (?)", s.categoryEvents(ctx, category)).
+		Order("m.volume DESC NULLS LAST").
+		Order("m.id ASC").
+		Limit(normalizeLimit(limit, 50)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) CountCategorySettlements(ctx context.Context, category string, since time.Time) (int64, error) {
+	if s == nil || s.db == nil || strings.TrimSpace(category) == "" {
+		return 0, nil
 	}
-	return decimal.NewFromFloat(out), nil
+	var total int64
+	err := s.db.WithContext(ctx).
+		Model(&models.MarketSettlementHistory{}).
+		Where("settled_at >= ?", since.UTC()).
+		Where("event_id IN (?)", s.categoryEvents(ctx, category)).
+		Count(&total).Error
+	return total, err
 }
 
-func (s *Store) UpsertExecutionRule(ctx context.Context, item *models.ExecutionRule) error {
-	if s == nil || s.db == nil || item == nil {
-		return nil
-	}
-	item.StrategyName = strings.TrimSpace(item.StrategyName)
-	if item.StrategyName == "" {
+func (s *Store) InsertMarketRegimes(ctx context.Context, items []models.MarketRegime) error {
+	if s == nil || s.db == nil || len(items) == 0 {
 		return nil
 	}
-	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "strategy_name"}},
-		DoUpdates: clause.AssignmentColumns([]string{
-			"auto_execute",
-			"min_confidence",
-			"min_edge_pct",
-			"stop_loss_pct",
-			"take_profit_pct",
-			"max_hold_hours",
-			"max_daily_trades",
-			"updated_at",
-		}),
-	}).Create(item).Error
+	return s.db.WithContext(ctx).Create(&items).Error
 }
 
-func (s *Store) GetExecutionRuleByStrategyName(ctx context.Context, strategyName string) (*models.ExecutionRule, error) {
+func (s *Store) ListLatestMarketRegimes(ctx context.Context) ([]models.MarketRegime, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	strategyName = strings.TrimSpace(strategyName)
-	if strategyName == "" {
-		return nil, nil
-	}
-	var item models.ExecutionRule
+	latest := s.db.WithContext(ctx).
+		Model(&models.MarketRegime{}).
+		Select("MAX(id)").
+		Group("category")
+	var items []models.MarketRegime
 	err := s.db.WithContext(ctx).
-		Model(&models.ExecutionRule{}).
-		Where("strategy_name = ?", strategyName).
-		First(&item).Error
-	if err == gorm.ErrRecordNotFound {
-		return nil, nil
+		Where("id IN (?)", latest).
+		Order("category asc").
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) marketRegimesQuery(ctx context.Context, params repository.ListMarketRegimesParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.MarketRegime{})
+	if params.Category != nil && strings.TrimSpace(*params.Category) != "" {
+		query = query.Where("category = ?", strings.ToLower(strings.TrimSpace(*params.Category)))
 	}
-	if err != nil {
-		return nil, err
+	if params.Regime != nil && strings.TrimSpace(*params.Regime) != "" {
+		query = query.Where("regime = ?", strings.TrimSpace(*params.Regime))
 	}
-	return &item, nil
+	if params.Since != nil {
+		query = query.Where("computed_at >= ?", params.Since.UTC())
+	}
+	if params.Until != nil {
+		query = query.Where("computed_at < ?", params.Until.UTC())
+	}
+	return query
 }
 
-func (s *Store) ListExecutionRules(ctx context.Context) ([]models.ExecutionRule, error) {
+func (s *Store) ListMarketRegimes(ctx context.Context, params repository.ListMarketRegimesParams) ([]models.MarketRegime, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	var items []models.ExecutionRule
-	if err := s.db.WithContext(ctx).
-		Model(&models.ExecutionRule{}).
-		Order("strategy_name asc").
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	var items []models.MarketRegime
+	err := s.marketRegimesQuery(ctx, params).
+		Order("computed_at desc").
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 100)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
 }
 
-func (s *Store) DeleteExecutionRuleByStrategyName(ctx context.Context, strategyName string) error {
+func (s *Store) CountMarketRegimes(ctx context.Context, params repository.ListMarketRegimesParams) (int64, error) {
 	if s == nil || s.db == nil {
-		return nil
-	}
-	strategyName = strings.TrimSpace(strategyName)
-	if strategyName == "" {
-		return nil
+		return 0, nil
 	}
-	return s.db.WithContext(ctx).
-		Where("strategy_name = ?", strategyName).
-		Delete(&models.ExecutionRule{}).Error
+	var total int64
+	err := s.marketRegimesQuery(ctx, params).Count(&total).Error
+	return total, err
 }
 
-func (s *Store) InsertTradeJournal(ctx context.Context, item *models.TradeJournal) error {
-	if s == nil || s.db == nil || item == nil {
+func (s *Store) InsertSLOSamples(ctx context.Context, items []models.SLOSample) error {
+	if s == nil || s.db == nil || len(items) == 0 {
 		return nil
 	}
-	return s.db.WithContext(ctx).Create(item).Error
+	return s.db.WithContext(ctx).Create(&items).Error
 }
 
-func (s *Store) GetTradeJournalByPlanID(ctx context.Context, planID uint64) (*models.TradeJournal, error) {
+func (s *Store) SumSLOSamples(ctx context.Context, slo string, since time.Time) (int64, int64, error) {
 	if s == nil || s.db == nil {
-		return nil, nil
+		return 0, 0, nil
 	}
-	if planID == 0 {
-		return nil, nil
+	var row struct {
+		Good  int64
+		Total int64
 	}
-	var item models.TradeJournal
 	err := s.db.WithContext(ctx).
-		Model(&models.TradeJournal{}).
-		Where("execution_plan_id = ?", planID).
-		First(&item).Error
-	if err == gorm.ErrRecordNotFound {
-		return nil, nil
+		Model(&models.SLOSample{}).
+		Select("COALESCE(SUM(good),0) AS good, COALESCE(SUM(total),0) AS total").
+		Where("slo = ?", slo).
+		Where("evaluated_at >= ?", since.UTC()).
+		Scan(&row).Error
+	return row.Good, row.Total, err
+}
+
+func (s *Store) sloSamplesQuery(ctx context.Context, params repository.ListSLOSamplesParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.SLOSample{})
+	if params.SLO != nil && strings.TrimSpace(*params.SLO) != "" {
+		query = query.Where("slo = ?", strings.TrimSpace(*params.SLO))
 	}
-	if err != nil {
-		return nil, err
+	if params.Since != nil {
+		query = query.Where("evaluated_at >= ?", params.Since.UTC())
 	}
-	return &item, nil
+	if params.Until != nil {
+		query = query.Where("evaluated_at < ?", params.Until.UTC())
+	}
+	return query
 }
 
-func (s *Store) UpdateTradeJournalExit(ctx context.Context, planID uint64, updates map[string]any) error {
+func (s *Store) ListSLOSamples(ctx context.Context, params repository.ListSLOSamplesParams) ([]models.SLOSample, error) {
 	if s == nil || s.db == nil {
-		return nil
+		return nil, nil
 	}
-	if planID == 0 || len(updates) == 0 {
-		return nil
-	}
-	updates["updated_at"] = time.Now().UTC()
-	return s.db.WithContext(ctx).
-		Model(&models.TradeJournal{}).
-		Where("execution_plan_id = ?", planID).
-		Updates(updates).Error
+	var items []models.SLOSample
+	err := s.sloSamplesQuery(ctx, params).
+		Order("evaluated_at desc").
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 100)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
 }
 
-func (s *Store) UpdateTradeJournalNotes(ctx context.Context, planID uint64, notes string, tags []byte, reviewedAt *time.Time) error {
+func (s *Store) CountSLOSamples(ctx context.Context, params repository.ListSLOSamplesParams) (int64, error) {
 	if s == nil || s.db == nil {
-		return nil
+		return 0, nil
 	}
-	if planID == 0 {
+	var total int64
+	err := s.sloSamplesQuery(ctx, params).Count(&total).Error
+	return total, err
+}
+
+func (s *Store) InsertOutboxMessagesTx(ctx context.Context, tx *gorm.DB, items []models.OutboxMessage) error {
+	if tx == nil || len(items) == 0 {
 		return nil
 	}
-	updates := map[string]any{
-		"notes":       strings.TrimSpace(notes),
-		"tags":        tags,
-		"reviewed_at": reviewedAt,
-		"updated_at":  time.Now().UTC(),
+	now := time.Now().UTC()
+	for i := range items {
+		if items[i].Status == "" {
+			items[i].Status = models.OutboxStatusPending
+		}
+		if items[i].NextAttemptAt.IsZero() {
+			items[i].NextAttemptAt = now
+		}
 	}
-	return s.db.WithContext(ctx).
-		Model(&models.TradeJournal{}).
-		Where("execution_plan_id = ?", planID).
-		Updates(updates).Error
+	if err := tx.WithContext(ctx).Create(&items).Error; err != nil {
+		return err
+	}
+	s.publishTx(ctx, tx, events.Event{Topic: events.TopicOutboxEnqueued, ID: items[len(items)-1].ID})
+	return nil
 }
 
-func (s *Store) ListTradeJournals(ctx context.Context, params repository.ListTradeJournalParams) ([]models.TradeJournal, error) {
+func (s *Store) ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.TradeJournal{})
-	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
-		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+	now = now.UTC()
+	var due []models.OutboxMessage
+	err := s.db.WithContext(ctx).
+		Where("status = ?", models.OutboxStatusPending).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at asc").
+		Order("id asc").
+		Limit(normalizeLimit(limit, 50)).
+		Find(&due).Error
+	if err != nil {
+		return nil, err
 	}
-	if params.Outcome != nil && strings.TrimSpace(*params.Outcome) != "" {
-		query = query.Where("outcome = ?", strings.TrimSpace(*params.Outcome))
+	// Attempts versions the row: a claim only succeeds if no other
+	// dispatcher claimed it since it was read.
+	out := make([]models.OutboxMessage, 0, len(due))
+	for _, item := range due {
+		res := s.db.WithContext(ctx).
+			Model(&models.OutboxMessage{}).
+			Where("id = ? AND status = ? AND attempts = ?", item.ID, models.OutboxStatusPending, item.Attempts).
+			Updates(map[string]any{"next_attempt_at": now.Add(lease), "attempts": gorm.Expr("attempts + 1"), "updated_at": now})
+		if res.Error != nil {
+			return out, res.Error
+		}
+		if res.RowsAffected == 1 {
+			item.Attempts++
+			item.NextAttemptAt = now.Add(lease)
+			out = append(out, item)
+		}
 	}
-	if params.Since != nil && !params.Since.IsZero() {
-		query = query.Where("created_at >= ?", params.Since.UTC())
+	return out, nil
+}
+
+func (s *Store) MarkOutboxMessageDelivered(ctx context.Context, id uint64, at time.Time) error {
+	if s == nil || s.db == nil || id == 0 {
+		return nil
 	}
-	if params.Until != nil && !params.Until.IsZero() {
-		query = query.Where("created_at <= ?", params.Until.UTC())
+	at = at.UTC()
+	return s.db.WithContext(ctx).
+		Model(&models.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": models.OutboxStatusDelivered, "delivered_at": &at, "last_error": "", "updated_at": at}).
+		Error
+}
+
+func (s *Store) MarkOutboxMessageFailed(ctx context.Context, id uint64, errMsg string, retryAt *time.Time) error {
+	if s == nil || s.db == nil || id == 0 {
+		return nil
 	}
-	for _, tag := range cleanStrings(params.Tags) {
-		like := "%" + tag + "%"
-		query = query.Where("CAST(tags AS TEXT) LIKE ?", like)
+	updates := map[string]any{"last_error": errMsg, "updated_at": time.Now().UTC()}
+	if retryAt != nil {
+		updates["next_attempt_at"] = retryAt.UTC()
+	} else {
+		updates["status"] = models.OutboxStatusDead
 	}
-	query = applyOrder(query, params.OrderBy, params.Asc, "created_at")
-	limit := normalizeLimit(params.Limit, 200)
-	offset := normalizeOffset(params.Offset)
-	var items []models.TradeJournal
-	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
-		return nil, err
+	return s.db.WithContext(ctx).
+		Model(&models.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(updates).
+		Error
+}
+
+func (s *Store) RetryOutboxMessage(ctx context.Context, id uint64, now time.Time) (bool, error) {
+	if s == nil || s.db == nil || id == 0 {
+		return false, nil
 	}
-	return items, nil
+	res := s.db.WithContext(ctx).
+		Model(&models.OutboxMessage{}).
+		Where("id = ? AND status = ?", id, models.OutboxStatusDead).
+		Updates(map[string]any{"status": models.OutboxStatusPending, "attempts": 0, "next_attempt_at": now.UTC(), "updated_at": now.UTC()})
+	return res.RowsAffected == 1, res.Error
 }
 
-func (s *Store) CountTradeJournals(ctx context.Context, params repository.ListTradeJournalParams) (int64, error) {
+func (s *Store) outboxMessagesQuery(ctx context.Context, params repository.ListOutboxMessagesParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.OutboxMessage{})
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	if params.Kind != nil && strings.TrimSpace(*params.Kind) != "" {
+		query = query.Where("kind = ?", strings.TrimSpace(*params.Kind))
+	}
+	return query
+}
+
+func (s *Store) ListOutboxMessages(ctx context.Context, params repository.ListOutboxMessagesParams) ([]models.OutboxMessage, error) {
 	if s == nil || s.db == nil {
-		return 0, nil
+		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.TradeJournal{})
-	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
-		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+	var items []models.OutboxMessage
+	err := s.outboxMessagesQuery(ctx, params).
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 100)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) CountOutboxMessages(ctx context.Context, params repository.ListOutboxMessagesParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
 	}
-	if params.Outcome != nil && strings.TrimSpace(*params.Outcome) != "" {
-		query = query.Where("outcome = ?", strings.TrimSpace(*params.Outcome))
+	var total int64
+	err := s.outboxMessagesQuery(ctx, params).Count(&total).Error
+	return total, err
+}
+
+func (s *Store) CountOutboxMessagesByStatus(ctx context.Context) (map[string]int64, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
 	}
-	if params.Since != nil && !params.Since.IsZero() {
-		query = query.Where("created_at >= ?", params.Since.UTC())
+	var rows []struct {
+		Status string
+		N      int64
 	}
-	if params.Until != nil && !params.Until.IsZero() {
-		query = query.Where("created_at <= ?", params.Until.UTC())
+	err := s.db.WithContext(ctx).
+		Model(&models.OutboxMessage{}).
+		Select("status, COUNT(*) AS n").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
 	}
-	for _, tag := range cleanStrings(params.Tags) {
-		like := "%" + tag + "%"
-		query = query.Where("CAST(tags AS TEXT) LIKE ?", like)
+	out := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		out[r.Status] = r.N
 	}
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return 0, err
+	return out, nil
+}
+
+func (s *Store) DeleteDeliveredOutboxMessages(ctx context.Context, before time.Time) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
 	}
-	return total, nil
+	res := s.db.WithContext(ctx).
+		Where("status = ? AND delivered_at < ?", models.OutboxStatusDelivered, before.UTC()).
+		Delete(&models.OutboxMessage{})
+	return res.RowsAffected, res.Error
 }
 
-func (s *Store) UpsertSystemSetting(ctx context.Context, item *models.SystemSetting) error {
+func (s *Store) InsertExecutorIntent(ctx context.Context, item *models.ExecutorIntent) error {
 	if s == nil || s.db == nil || item == nil {
 		return nil
 	}
-	item.Key = strings.TrimSpace(item.Key)
-	if item.Key == "" {
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) ResolveExecutorIntent(ctx context.Context, id uint64, status string, updates map[string]any) error {
+	if s == nil || s.db == nil || id == 0 {
 		return nil
 	}
-	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "key"}},
-		DoUpdates: clause.AssignmentColumns([]string{
-			"value",
-			"description",
-			"updated_at",
-		}),
-	}).Create(item).Error
+	now := time.Now().UTC()
+	values := map[string]any{"status": status, "resolved_at": &now, "updated_at": now}
+	for k, v := range updates {
+		values[k] = v
+	}
+	return s.db.WithContext(ctx).
+		Model(&models.ExecutorIntent{}).
+		Where("id = ? AND status = ?", id, models.ExecutorIntentPending).
+		Updates(values).
+		Error
 }
 
-func (s *Store) GetSystemSettingByKey(ctx context.Context, key string) (*models.SystemSetting, error) {
-	if s == nil || s.db == nil {
-		return nil, nil
+func (s *Store) executorIntentsQuery(ctx context.Context, params repository.ListExecutorIntentsParams) *gorm.DB {
+	query := planTenantScope(s.db.WithContext(ctx).Model(&models.ExecutorIntent{}), "plan_id", tenantParam(params.Tenant))
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
 	}
-	key = strings.TrimSpace(key)
-	if key == "" {
-		return nil, nil
+	if params.Action != nil && strings.TrimSpace(*params.Action) != "" {
+		query = query.Where("action = ?", strings.TrimSpace(*params.Action))
 	}
-	var item models.SystemSetting
-	err := s.db.WithContext(ctx).Model(&models.SystemSetting{}).Where("key = ?", key).First(&item).Error
-	if err == gorm.ErrRecordNotFound {
-		return nil, nil
+	if params.OrderID != nil && *params.OrderID > 0 {
+		query = query.Where("order_id = ?", *params.OrderID)
 	}
-	if err != nil {
-		return nil, err
+	if params.PlanID != nil && *params.PlanID > 0 {
+		query = query.Where("plan_id = ?", *params.PlanID)
 	}
-	return &item, nil
+	return query
 }
 
-func (s *Store) ListSystemSettings(ctx context.Context, params repository.ListSystemSettingsParams) ([]models.SystemSetting, error) {
+func (s *Store) ListExecutorIntents(ctx context.Context, params repository.ListExecutorIntentsParams) ([]models.ExecutorIntent, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.SystemSetting{})
-	if params.Prefix != nil && strings.TrimSpace(*params.Prefix) != "" {
-		pattern := strings.TrimSpace(*params.Prefix) + "%"
-		query = query.Where("key LIKE ?", pattern)
-	}
-	query = applyOrder(query, params.OrderBy, params.Asc, "key")
-	limit := normalizeLimit(params.Limit, 500)
-	offset := normalizeOffset(params.Offset)
-	var items []models.SystemSetting
-	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	var items []models.ExecutorIntent
+	err := s.executorIntentsQuery(ctx, params).
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 100)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
 }
 
-func (s *Store) CountSystemSettings(ctx context.Context, params repository.ListSystemSettingsParams) (int64, error) {
+func (s *Store) CountExecutorIntents(ctx context.Context, params repository.ListExecutorIntentsParams) (int64, error) {
 	if s == nil || s.db == nil {
 		return 0, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.SystemSetting{})
-	if params.Prefix != nil && strings.TrimSpace(*params.Prefix) != "" {
-		pattern := strings.TrimSpace(*params.Prefix) + "%"
-		query = query.Where("key LIKE ?", pattern)
-	}
 	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return 0, err
+	err := s.executorIntentsQuery(ctx, params).Count(&total).Error
+	return total, err
+}
+
+func (s *Store) CountExecutorIntentsByStatus(ctx context.Context) (map[string]int64, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
 	}
-	return total, nil
+	var rows []struct {
+		Status string
+		N      int64
+	}
+	err := s.db.WithContext(ctx).
+		Model(&models.ExecutorIntent{}).
+		Select("status, COUNT(*) AS n").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		out[r.Status] = r.N
+	}
+	return out, nil
 }
 
-func (s *Store) UpsertPosition(ctx context.Context, item *models.Position) error {
+func (s *Store) InsertStrategyChange(ctx context.Context, item *models.StrategyChange) error {
 	if s == nil || s.db == nil || item == nil {
 		return nil
 	}
-	item.TokenID = strings.TrimSpace(item.TokenID)
-	if item.TokenID == "" {
-		return nil
-	}
-	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "token_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{
-			"market_id",
-			"event_id",
-			"direction",
-			"quantity",
-			"avg_entry_price",
-			"current_price",
-			"cost_basis",
-			"unrealized_pnl",
-			"realized_pnl",
-			"status",
-			"strategy_name",
-			"opened_at",
-			"closed_at",
-			"updated_at",
-		}),
-	}).Create(item).Error
+	return s.db.WithContext(ctx).Create(item).Error
 }
 
-func (s *Store) GetPositionByID(ctx context.Context, id uint64) (*models.Position, error) {
-	if s == nil || s.db == nil {
+func (s *Store) GetStrategyChange(ctx context.Context, id uint64) (*models.StrategyChange, error) {
+	if s == nil || s.db == nil || id == 0 {
 		return nil, nil
 	}
-	if id == 0 {
+	var item models.StrategyChange
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
-	var item models.Position
-	err := s.db.WithContext(ctx).Model(&models.Position{}).Where("id = ?", id).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) GetLatestStrategyChange(ctx context.Context, strategyName string) (*models.StrategyChange, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var item models.StrategyChange
+	err := s.db.WithContext(ctx).
+		Where("strategy_name = ?", strings.TrimSpace(strategyName)).
+		Order("created_at desc").Order("id desc").
+		First(&item).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -1121,16 +1652,63 @@ func (s *Store) GetPositionByID(ctx context.Context, id uint64) (*models.Positio
 	return &item, nil
 }
 
-func (s *Store) GetPositionByTokenID(ctx context.Context, tokenID string) (*models.Position, error) {
+func (s *Store) ListActiveStrategyChanges(ctx context.Context, now time.Time) ([]models.StrategyChange, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	tokenID = strings.TrimSpace(tokenID)
-	if tokenID == "" {
+	// A later change ends the holdout of the one before it, so only the
+	// latest change per strategy counts.
+	latest := s.db.Model(&models.StrategyChange{}).Select("MAX(id)").Group("strategy_name")
+	var items []models.StrategyChange
+	err := s.db.WithContext(ctx).
+		Where("id IN (?)", latest).
+		Where("holdout_pct > 0 AND holdout_until > ?", now.UTC()).
+		Order("strategy_name asc").
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) StrategyChangeCohorts(ctx context.Context, changeID uint64) ([]repository.CohortOutcome, error) {
+	if s == nil || s.db == nil || changeID == 0 {
 		return nil, nil
 	}
-	var item models.Position
-	err := s.db.WithContext(ctx).Model(&models.Position{}).Where("token_id = ?", tokenID).First(&item).Error
+	var rows []repository.CohortOutcome
+	err := s.db.WithContext(ctx).
+		Table("opportunities AS o").
+		Joins("LEFT JOIN execution_plans AS e ON e.opportunity_id = o.id").
+		Joins("LEFT JOIN pnl_records AS r ON r.plan_id = e.id AND r.settled_at IS NOT NULL AND r.realized_pnl IS NOT NULL").
+		Select(`
+			o.cohort AS cohort,
+			COUNT(DISTINCT o.id) AS opportunities,
+			COUNT(DISTINCT e.id) AS plans,
+			COUNT(r.id) AS settled,
+			COALESCE(SUM(CASE WHEN r.realized_pnl > 0 THEN 1 ELSE 0 END),0) AS wins,
+			COALESCE(SUM(r.realized_pnl),0) AS sum_pn_l,
+			COALESCE(SUM(r.realized_pnl * r.realized_pnl),0) AS sum_pn_l_sq
+		`).
+		Where("o.strategy_change_id = ?", changeID).
+		Group("o.cohort").
+		Order("o.cohort asc").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (s *Store) InsertStrategyCapacity(ctx context.Context, item *models.StrategyCapacity) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) GetLatestStrategyCapacity(ctx context.Context, strategyName string) (*models.StrategyCapacity, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var item models.StrategyCapacity
+	err := s.db.WithContext(ctx).
+		Where("strategy_name = ?", strings.TrimSpace(strategyName)).
+		Order("estimated_at desc").Order("id desc").
+		First(&item).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -1140,166 +1718,118 @@ func (s *Store) GetPositionByTokenID(ctx context.Context, tokenID string) (*mode
 	return &item, nil
 }
 
-func (s *Store) ListPositions(ctx context.Context, params repository.ListPositionsParams) ([]models.Position, error) {
+func (s *Store) ListStrategyCapacities(ctx context.Context, strategyName string, limit int) ([]models.StrategyCapacity, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.Position{})
-	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
-		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
-	}
-	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
-		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
-	}
-	if params.MarketID != nil && strings.TrimSpace(*params.MarketID) != "" {
-		query = query.Where("market_id = ?", strings.TrimSpace(*params.MarketID))
-	}
-	query = applyOrder(query, params.OrderBy, params.Asc, "opened_at")
-	limit := normalizeLimit(params.Limit, 200)
-	offset := normalizeOffset(params.Offset)
-	var items []models.Position
-	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	var items []models.StrategyCapacity
+	err := s.db.WithContext(ctx).
+		Where("strategy_name = ?", strings.TrimSpace(strategyName)).
+		Order("estimated_at desc").Order("id desc").
+		Limit(normalizeLimit(limit, 500)).
+		Find(&items).Error
+	return items, err
 }
 
-func (s *Store) CountPositions(ctx context.Context, params repository.ListPositionsParams) (int64, error) {
+func (s *Store) GetStrategyExpectation(ctx context.Context, strategyName string) (*models.StrategyExpectation, error) {
 	if s == nil || s.db == nil {
-		return 0, nil
-	}
-	query := s.db.WithContext(ctx).Model(&models.Position{})
-	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
-		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
-	}
-	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
-		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+		return nil, nil
 	}
-	if params.MarketID != nil && strings.TrimSpace(*params.MarketID) != "" {
-		query = query.Where("market_id = ?", strings.TrimSpace(*params.MarketID))
+	var item models.StrategyExpectation
+	err := s.db.WithContext(ctx).Where("strategy_name = ?", strings.TrimSpace(strategyName)).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
 	}
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return 0, err
+	if err != nil {
+		return nil, err
 	}
-	return total, nil
+	return &item, nil
 }
 
-func (s *Store) ListOpenPositions(ctx context.Context) ([]models.Position, error) {
+func (s *Store) ListStrategyExpectations(ctx context.Context) ([]models.StrategyExpectation, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	var items []models.Position
-	if err := s.db.WithContext(ctx).Model(&models.Position{}).
-		Where("status = ?", "open").
-		Order("opened_at asc").
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	var items []models.StrategyExpectation
+	err := s.db.WithContext(ctx).Order("strategy_name asc").Find(&items).Error
+	return items, err
 }
 
-func (s *Store) ClosePosition(ctx context.Context, id uint64, realizedPnL decimal.Decimal, closedAt time.Time) error {
-	if s == nil || s.db == nil {
-		return nil
-	}
-	if id == 0 {
+func (s *Store) SaveStrategyExpectation(ctx context.Context, item *models.StrategyExpectation) error {
+	if s == nil || s.db == nil || item == nil {
 		return nil
 	}
-	if closedAt.IsZero() {
-		closedAt = time.Now().UTC()
+	if item.ID == 0 {
+		return s.db.WithContext(ctx).Create(item).Error
 	}
-	return s.db.WithContext(ctx).Model(&models.Position{}).Where("id = ?", id).Updates(map[string]any{
-		"status":         "closed",
-		"closed_at":      &closedAt,
-		"quantity":       decimal.Zero,
-		"cost_basis":     decimal.Zero,
-		"unrealized_pnl": decimal.Zero,
-		"realized_pnl":   realizedPnL,
-		"updated_at":     time.Now().UTC(),
-	}).Error
+	return s.db.WithContext(ctx).Save(item).Error
 }
 
-func (s *Store) PositionsSummary(ctx context.Context) (repository.PositionsSummary, error) {
+func (s *Store) DeleteStrategyExpectation(ctx context.Context, strategyName string) error {
 	if s == nil || s.db == nil {
-		return repository.PositionsSummary{}, nil
-	}
-	var row struct {
-		TotalOpen      int64
-		TotalCostBasis float64
-		TotalMarketVal float64
-		UnrealizedPnL  float64
-		RealizedPnL    float64
-	}
-	err := s.db.WithContext(ctx).
-		Table("positions").
-		Select(`
-			COALESCE(SUM(CASE WHEN status = 'open' THEN 1 ELSE 0 END),0) AS total_open,
-			COALESCE(SUM(CASE WHEN status = 'open' THEN cost_basis ELSE 0 END),0) AS total_cost_basis,
-			COALESCE(SUM(CASE WHEN status = 'open' THEN (current_price * quantity) ELSE 0 END),0) AS total_market_val,
-			COALESCE(SUM(CASE WHEN status = 'open' THEN unrealized_pnl ELSE 0 END),0) AS unrealized_pnl,
-			COALESCE(SUM(realized_pnl),0) AS realized_pnl
-		`).
-		Scan(&row).Error
-	if err != nil {
-		return repository.PositionsSummary{}, err
+		return nil
 	}
-	return repository.PositionsSummary{
-		TotalOpen:      row.TotalOpen,
-		TotalCostBasis: row.TotalCostBasis,
-		TotalMarketVal: row.TotalMarketVal,
-		UnrealizedPnL:  row.UnrealizedPnL,
-		RealizedPnL:    row.RealizedPnL,
-		NetLiquidation: row.TotalMarketVal + row.RealizedPnL,
-	}, nil
+	return s.db.WithContext(ctx).Where("strategy_name = ?", strings.TrimSpace(strategyName)).Delete(&models.StrategyExpectation{}).Error
 }
 
-func (s *Store) InsertPortfolioSnapshot(ctx context.Context, item *models.PortfolioSnapshot) error {
+func (s *Store) InsertStrategyDriftReport(ctx context.Context, item *models.StrategyDriftReport) error {
 	if s == nil || s.db == nil || item == nil {
 		return nil
 	}
-	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "snapshot_at"}},
-		DoUpdates: clause.AssignmentColumns([]string{"total_positions", "total_cost_basis", "total_market_val", "unrealized_pnl", "realized_pnl", "net_liquidation"}),
-	}).Create(item).Error
+	return s.db.WithContext(ctx).Create(item).Error
 }
 
-func (s *Store) ListPortfolioSnapshots(ctx context.Context, params repository.ListPortfolioSnapshotsParams) ([]models.PortfolioSnapshot, error) {
+func (s *Store) GetLatestStrategyDriftReport(ctx context.Context, strategyName string) (*models.StrategyDriftReport, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.PortfolioSnapshot{})
-	if params.Since != nil && !params.Since.IsZero() {
-		query = query.Where("snapshot_at >= ?", params.Since.UTC())
-	}
-	if params.Until != nil && !params.Until.IsZero() {
-		query = query.Where("snapshot_at <= ?", params.Until.UTC())
+	var item models.StrategyDriftReport
+	err := s.db.WithContext(ctx).
+		Where("strategy_name = ?", strings.TrimSpace(strategyName)).
+		Order("evaluated_at desc").Order("id desc").
+		First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
 	}
-	limit := normalizeLimit(params.Limit, 500)
-	offset := normalizeOffset(params.Offset)
-	var items []models.PortfolioSnapshot
-	if err := query.Order("snapshot_at desc").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+	if err != nil {
 		return nil, err
 	}
-	return items, nil
+	return &item, nil
 }
 
-func (s *Store) InsertOrder(ctx context.Context, item *models.Order) error {
+func (s *Store) ListStrategyDriftReports(ctx context.Context, strategyName string, limit int) ([]models.StrategyDriftReport, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var items []models.StrategyDriftReport
+	err := s.db.WithContext(ctx).
+		Where("strategy_name = ?", strings.TrimSpace(strategyName)).
+		Order("evaluated_at desc").Order("id desc").
+		Limit(normalizeLimit(limit, 500)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) InsertRuleTuning(ctx context.Context, item *models.RuleTuning) error {
 	if s == nil || s.db == nil || item == nil {
 		return nil
 	}
 	return s.db.WithContext(ctx).Create(item).Error
 }
 
-func (s *Store) GetOrderByID(ctx context.Context, id uint64) (*models.Order, error) {
-	if s == nil || s.db == nil {
-		return nil, nil
+func (s *Store) UpdateRuleTuning(ctx context.Context, item *models.RuleTuning) error {
+	if s == nil || s.db == nil || item == nil || item.ID == 0 {
+		return nil
 	}
-	if id == 0 {
+	return s.db.WithContext(ctx).Save(item).Error
+}
+
+func (s *Store) GetRuleTuning(ctx context.Context, id uint64) (*models.RuleTuning, error) {
+	if s == nil || s.db == nil || id == 0 {
 		return nil, nil
 	}
-	var item models.Order
-	err := s.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).First(&item).Error
+	var item models.RuleTuning
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -1309,125 +1839,2286 @@ func (s *Store) GetOrderByID(ctx context.Context, id uint64) (*models.Order, err
 	return &item, nil
 }
 
-func (s *Store) ListOrders(ctx context.Context, params repository.ListOrdersParams) ([]models.Order, error) {
+func (s *Store) ListRuleTunings(ctx context.Context, strategyName string, limit int) ([]models.RuleTuning, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.Order{})
-	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
-		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	var items []models.RuleTuning
+	err := s.db.WithContext(ctx).
+		Where("strategy_name = ?", strings.TrimSpace(strategyName)).
+		Order("created_at desc").Order("id desc").
+		Limit(normalizeLimit(limit, 500)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) InsertStrategyParamTuning(ctx context.Context, item *models.StrategyParamTuning) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
 	}
-	if params.PlanID != nil && *params.PlanID > 0 {
-		query = query.Where("plan_id = ?", *params.PlanID)
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) UpdateStrategyParamTuning(ctx context.Context, item *models.StrategyParamTuning) error {
+	if s == nil || s.db == nil || item == nil || item.ID == 0 {
+		return nil
 	}
-	if params.TokenID != nil && strings.TrimSpace(*params.TokenID) != "" {
-		query = query.Where("token_id = ?", strings.TrimSpace(*params.TokenID))
+	return s.db.WithContext(ctx).Save(item).Error
+}
+
+func (s *Store) GetStrategyParamTuning(ctx context.Context, id uint64) (*models.StrategyParamTuning, error) {
+	if s == nil || s.db == nil || id == 0 {
+		return nil, nil
 	}
-	query = applyOrder(query, params.OrderBy, params.Asc, "created_at")
-	limit := normalizeLimit(params.Limit, 200)
-	offset := normalizeOffset(params.Offset)
-	var items []models.Order
-	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+	var item models.StrategyParamTuning
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
 		return nil, err
 	}
-	return items, nil
+	return &item, nil
 }
 
-func (s *Store) CountOrders(ctx context.Context, params repository.ListOrdersParams) (int64, error) {
+func (s *Store) ListStrategyParamTunings(ctx context.Context, strategyName string, limit int) ([]models.StrategyParamTuning, error) {
 	if s == nil || s.db == nil {
-		return 0, nil
+		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Model(&models.Order{})
-	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
-		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	var items []models.StrategyParamTuning
+	err := s.db.WithContext(ctx).
+		Where("strategy_name = ?", strings.TrimSpace(strategyName)).
+		Order("created_at desc").Order("id desc").
+		Limit(normalizeLimit(limit, 500)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) CountClosedMarketSettlements(ctx context.Context, from, to time.Time) (int64, int64, error) {
+	if s == nil || s.db == nil {
+		return 0, 0, nil
 	}
-	if params.PlanID != nil && *params.PlanID > 0 {
-		query = query.Where("plan_id = ?", *params.PlanID)
+	var row struct {
+		Closed  int64
+		Settled int64
 	}
-	if params.TokenID != nil && strings.TrimSpace(*params.TokenID) != "" {
-		query = query.Where("token_id = ?", strings.TrimSpace(*params.TokenID))
+	err := s.db.WithContext(ctx).
+		Table("catalog_markets AS m").
+		Select("COUNT(*) AS closed, COUNT(h.market_id) AS settled").
+		Joins("LEFT JOIN market_settlement_history AS h ON h.market_id = m.id").
+		Where("m.closed = ?", true).
+		Where("m.external_updated_at >= ? AND m.external_updated_at < ?", from.UTC(), to.UTC()).
+		Scan(&row).Error
+	return row.Closed, row.Settled, err
+}
+
+func (s *Store) ListCostForecastOutcomes(ctx context.Context, params repository.CostForecastOutcomeParams) ([]repository.CostForecastOutcomeRow, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
 	}
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return 0, err
+	fills := s.db.WithContext(ctx).
+		Table("fills").
+		Select("plan_id, COALESCE(SUM(filled_size * avg_price),0) AS filled, COALESCE(SUM(fee),0) AS fees, COUNT(*) AS fills").
+		Group("plan_id")
+	query := s.db.WithContext(ctx).
+		Table("cost_forecasts AS cf").
+		Select(`cf.*,
+			f.filled AS filled_usd,
+			f.fees AS realized_fees_usd,
+			COALESCE(pr.slippage_loss,0) AS realized_slippage_usd,
+			f.fills AS fills`).
+		Joins("JOIN (?) AS f ON f.plan_id = cf.plan_id", fills).
+		Joins("LEFT JOIN pnl_records AS pr ON pr.plan_id = cf.plan_id")
+	query = planTenantScope(query, "cf.plan_id", tenantParam(params.Tenant))
+	if params.Since != nil {
+		query = query.Where("cf.created_at >= ?", params.Since.UTC())
+	}
+	if params.Until != nil {
+		query = query.Where("cf.created_at < ?", params.Until.UTC())
 	}
-	return total, nil
+	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
+		query = query.Where("cf.strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+	}
+	var rows []repository.CostForecastOutcomeRow
+	err := query.Order("cf.id asc").Scan(&rows).Error
+	return rows, err
 }
 
-func (s *Store) UpdateOrderStatus(ctx context.Context, id uint64, status string, updates map[string]any) error {
+func (s *Store) GetStrategyBudget(ctx context.Context, strategyName string) (*models.StrategyBudget, error) {
 	if s == nil || s.db == nil {
-		return nil
-	}
-	if id == 0 || strings.TrimSpace(status) == "" {
-		return nil
+		return nil, nil
 	}
-	next := map[string]any{
-		"status":     strings.TrimSpace(status),
-		"updated_at": time.Now().UTC(),
+	var item models.StrategyBudget
+	err := s.db.WithContext(ctx).Where("strategy_name = ?", strings.TrimSpace(strategyName)).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
 	}
-	for k, v := range updates {
-		next[k] = v
+	if err != nil {
+		return nil, err
 	}
-	return s.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).Updates(next).Error
+	return &item, nil
 }
 
-func (s *Store) UpsertStrategyDailyStats(ctx context.Context, item *models.StrategyDailyStats) error {
+func (s *Store) SaveStrategyBudget(ctx context.Context, item *models.StrategyBudget) error {
 	if s == nil || s.db == nil || item == nil {
 		return nil
 	}
-	item.StrategyName = strings.TrimSpace(item.StrategyName)
-	if item.StrategyName == "" || item.Date.IsZero() {
-		return nil
+	if item.ID == 0 {
+		return s.db.WithContext(ctx).Create(item).Error
 	}
-	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "strategy_name"}, {Name: "date"}},
-		DoUpdates: clause.AssignmentColumns([]string{
-			"trades_count",
-			"win_count",
-			"loss_count",
-			"pnl_usd",
-			"avg_edge_pct",
-			"avg_slippage_bps",
-			"avg_hold_hours",
-			"max_drawdown_usd",
-			"cumulative_pnl",
-			"updated_at",
-		}),
-	}).Create(item).Error
+	return s.db.WithContext(ctx).Save(item).Error
 }
 
-func (s *Store) ListStrategyDailyStats(ctx context.Context, params repository.ListDailyStatsParams) ([]models.StrategyDailyStats, error) {
+func (s *Store) StrategyTurnover(ctx context.Context, strategyName string, since time.Time) (repository.StrategyTurnover, error) {
 	if s == nil || s.db == nil {
-		return nil, nil
-	}
-	query := s.db.WithContext(ctx).Model(&models.StrategyDailyStats{})
-	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
-		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+		return repository.StrategyTurnover{}, nil
 	}
-	if params.Since != nil && !params.Since.IsZero() {
-		query = query.Where("date >= ?", params.Since.UTC())
-	}
-	if params.Until != nil && !params.Until.IsZero() {
-		query = query.Where("date <= ?", params.Until.UTC())
+	var row struct {
+		Notional decimal.Decimal
+		Fees     decimal.Decimal
+		Fills    int64
 	}
-	limit := normalizeLimit(params.Limit, 500)
-	offset := normalizeOffset(params.Offset)
-	var items []models.StrategyDailyStats
-	if err := query.Order("date desc, strategy_name asc").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
-		return nil, err
+	err := s.db.WithContext(ctx).
+		Table("fills AS f").
+		Select(`
+			COALESCE(SUM(f.filled_size * f.avg_price),0) AS notional,
+			COALESCE(SUM(COALESCE(f.fee,0)),0) AS fees,
+			COUNT(*) AS fills
+		`).
+		Joins("JOIN execution_plans AS p ON p.id = f.plan_id").
+		Where("p.strategy_name = ?", strings.TrimSpace(strategyName)).
+		Where("f.filled_at >= ?", since.UTC()).
+		Scan(&row).Error
+	if err != nil {
+		return repository.StrategyTurnover{}, err
 	}
-	return items, nil
+	return repository.StrategyTurnover{NotionalUSD: row.Notional, FeesUSD: row.Fees, Fills: row.Fills}, nil
 }
 
-func (s *Store) AttributionByStrategy(ctx context.Context, strategyName string, since, until *time.Time) (repository.AttributionResult, error) {
+func (s *Store) GetRewardEpoch(ctx context.Context, epoch, marketID string) (*models.RewardEpoch, error) {
 	if s == nil || s.db == nil {
-		return repository.AttributionResult{}, nil
+		return nil, nil
+	}
+	var item models.RewardEpoch
+	err := s.db.WithContext(ctx).
+		Where("epoch = ? AND market_id = ?", strings.TrimSpace(epoch), strings.TrimSpace(marketID)).
+		First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) SaveRewardEpoch(ctx context.Context, item *models.RewardEpoch) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	if item.ID == 0 {
+		return s.db.WithContext(ctx).Create(item).Error
+	}
+	return s.db.WithContext(ctx).Save(item).Error
+}
+
+func (s *Store) rewardEpochsQuery(ctx context.Context, params repository.ListRewardEpochsParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.RewardEpoch{})
+	if params.MarketID != nil && strings.TrimSpace(*params.MarketID) != "" {
+		query = query.Where("market_id = ?", strings.TrimSpace(*params.MarketID))
+	}
+	if params.Since != nil && strings.TrimSpace(*params.Since) != "" {
+		query = query.Where("epoch >= ?", strings.TrimSpace(*params.Since))
+	}
+	if params.Until != nil && strings.TrimSpace(*params.Until) != "" {
+		query = query.Where("epoch <= ?", strings.TrimSpace(*params.Until))
+	}
+	return query
+}
+
+func (s *Store) ListRewardEpochs(ctx context.Context, params repository.ListRewardEpochsParams) ([]models.RewardEpoch, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var items []models.RewardEpoch
+	err := s.rewardEpochsQuery(ctx, params).
+		Order("epoch desc, market_id asc").
+		Limit(normalizeLimit(params.Limit, 500)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) CountRewardEpochs(ctx context.Context, params repository.ListRewardEpochsParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var total int64
+	err := s.rewardEpochsQuery(ctx, params).Count(&total).Error
+	return total, err
+}
+
+func (s *Store) InsertTradeTicket(ctx context.Context, item *models.TradeTicket) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) UpdateTradeTicket(ctx context.Context, item *models.TradeTicket) error {
+	if s == nil || s.db == nil || item == nil || item.ID == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Save(item).Error
+}
+
+func (s *Store) GetTradeTicketByID(ctx context.Context, id uint64) (*models.TradeTicket, error) {
+	if s == nil || s.db == nil || id == 0 {
+		return nil, nil
+	}
+	var item models.TradeTicket
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) tradeTicketsQuery(ctx context.Context, params repository.ListTradeTicketsParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.TradeTicket{})
+	if params.Tenant != nil {
+		query = query.Where("tenant = ?", strings.TrimSpace(*params.Tenant))
+	}
+	if params.State != nil && strings.TrimSpace(*params.State) != "" {
+		query = query.Where("state = ?", strings.TrimSpace(*params.State))
+	}
+	if params.OpportunityID != nil {
+		query = query.Where("opportunity_id = ?", *params.OpportunityID)
+	}
+	return query
+}
+
+func (s *Store) ListTradeTickets(ctx context.Context, params repository.ListTradeTicketsParams) ([]models.TradeTicket, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var items []models.TradeTicket
+	err := s.tradeTicketsQuery(ctx, params).
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 100)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) CountTradeTickets(ctx context.Context, params repository.ListTradeTicketsParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var total int64
+	err := s.tradeTicketsQuery(ctx, params).Count(&total).Error
+	return total, err
+}
+
+func (s *Store) InsertCatalogWebhook(ctx context.Context, item *models.CatalogWebhook) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) UpdateCatalogWebhook(ctx context.Context, item *models.CatalogWebhook) error {
+	if s == nil || s.db == nil || item == nil || item.ID == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Model(&models.CatalogWebhook{}).Where("id = ?", item.ID).Updates(map[string]any{
+		"name":              item.Name,
+		"url":               item.URL,
+		"secret":            item.Secret,
+		"entity_types":      item.EntityTypes,
+		"tags":              item.Tags,
+		"keywords":          item.Keywords,
+		"min_liquidity_usd": item.MinLiquidityUSD,
+		"enabled":           item.Enabled,
+		"updated_at":        time.Now().UTC(),
+	}).Error
+}
+
+func (s *Store) GetCatalogWebhookByID(ctx context.Context, id uint64) (*models.CatalogWebhook, error) {
+	if s == nil || s.db == nil || id == 0 {
+		return nil, nil
+	}
+	var item models.CatalogWebhook
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) ListCatalogWebhooks(ctx context.Context, params repository.ListCatalogWebhooksParams) ([]models.CatalogWebhook, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.CatalogWebhook{})
+	if params.Tenant != nil {
+		query = query.Where("tenant = ?", strings.ToLower(strings.TrimSpace(*params.Tenant)))
+	}
+	if params.EnabledOnly {
+		query = query.Where("enabled = ?", true)
+	}
+	var items []models.CatalogWebhook
+	if err := query.Order("id asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) DeleteCatalogWebhook(ctx context.Context, id uint64) error {
+	if s == nil || s.db == nil || id == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.CatalogWebhook{}).Error
+}
+
+func (s *Store) RecordCatalogWebhookDelivery(ctx context.Context, id uint64, status int, errMsg string, at time.Time) error {
+	if s == nil || s.db == nil || id == 0 {
+		return nil
+	}
+	failures := gorm.Expr("0")
+	if errMsg != "" {
+		failures = gorm.Expr("consecutive_failures + 1")
+	}
+	return s.db.WithContext(ctx).Model(&models.CatalogWebhook{}).Where("id = ?", id).Updates(map[string]any{
+		"last_delivery_at":     at.UTC(),
+		"last_status":          status,
+		"last_error":           errMsg,
+		"consecutive_failures": failures,
+	}).Error
+}
+
+func (s *Store) InsertTradeWebhook(ctx context.Context, item *models.TradeWebhook) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) UpdateTradeWebhook(ctx context.Context, item *models.TradeWebhook) error {
+	if s == nil || s.db == nil || item == nil || item.ID == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Model(&models.TradeWebhook{}).Where("id = ?", item.ID).Updates(map[string]any{
+		"name":        item.Name,
+		"url":         item.URL,
+		"secret":      item.Secret,
+		"event_types": item.EventTypes,
+		"strategies":  item.Strategies,
+		"enabled":     item.Enabled,
+		"updated_at":  time.Now().UTC(),
+	}).Error
+}
+
+func (s *Store) GetTradeWebhookByID(ctx context.Context, id uint64) (*models.TradeWebhook, error) {
+	if s == nil || s.db == nil || id == 0 {
+		return nil, nil
+	}
+	var item models.TradeWebhook
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) ListTradeWebhooks(ctx context.Context, params repository.ListTradeWebhooksParams) ([]models.TradeWebhook, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.TradeWebhook{})
+	if params.Tenant != nil {
+		query = query.Where("tenant = ?", strings.ToLower(strings.TrimSpace(*params.Tenant)))
+	}
+	if params.EnabledOnly {
+		query = query.Where("enabled = ?", true)
+	}
+	var items []models.TradeWebhook
+	if err := query.Order("id asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) DeleteTradeWebhook(ctx context.Context, id uint64) error {
+	if s == nil || s.db == nil || id == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&models.TradeWebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.TradeWebhook{}).Error
+	})
+}
+
+func (s *Store) InsertTradeWebhookDeliveryTx(ctx context.Context, tx *gorm.DB, item *models.TradeWebhookDelivery) (bool, error) {
+	if s == nil || tx == nil || item == nil {
+		return false, nil
+	}
+	if item.Status == "" {
+		item.Status = models.TradeDeliveryPending
+	}
+	res := tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "webhook_id"}, {Name: "event_key"}},
+		DoNothing: true,
+	}).Create(item)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (s *Store) GetTradeWebhookDelivery(ctx context.Context, id uint64) (*models.TradeWebhookDelivery, error) {
+	if s == nil || s.db == nil || id == 0 {
+		return nil, nil
+	}
+	var item models.TradeWebhookDelivery
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) tradeWebhookDeliveriesQuery(ctx context.Context, params repository.ListTradeWebhookDeliveriesParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.TradeWebhookDelivery{})
+	if params.WebhookID > 0 {
+		query = query.Where("webhook_id = ?", params.WebhookID)
+	}
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	if params.EventType != nil && strings.TrimSpace(*params.EventType) != "" {
+		query = query.Where("event_type = ?", strings.TrimSpace(*params.EventType))
+	}
+	return query
+}
+
+func (s *Store) ListTradeWebhookDeliveries(ctx context.Context, params repository.ListTradeWebhookDeliveriesParams) ([]models.TradeWebhookDelivery, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var items []models.TradeWebhookDelivery
+	err := s.tradeWebhookDeliveriesQuery(ctx, params).
+		Order("id desc").
+		Limit(normalizeLimit(params.Limit, 100)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) CountTradeWebhookDeliveries(ctx context.Context, params repository.ListTradeWebhookDeliveriesParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var total int64
+	err := s.tradeWebhookDeliveriesQuery(ctx, params).Count(&total).Error
+	return total, err
+}
+
+func (s *Store) RecordTradeWebhookAttempt(ctx context.Context, deliveryID uint64, status int, errMsg string, at time.Time) error {
+	if s == nil || s.db == nil || deliveryID == 0 {
+		return nil
+	}
+	at = at.UTC()
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var item models.TradeWebhookDelivery
+		if err := tx.Where("id = ?", deliveryID).First(&item).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+		updates := map[string]any{
+			"attempts":    gorm.Expr("attempts + 1"),
+			"http_status": status,
+			"last_error":  errMsg,
+			"status":      models.TradeDeliveryFailed,
+			"updated_at":  at,
+		}
+		failures := gorm.Expr("consecutive_failures + 1")
+		if errMsg == "" {
+			updates["status"] = models.TradeDeliveryDelivered
+			updates["delivered_at"] = at
+			failures = gorm.Expr("0")
+		}
+		if err := tx.Model(&models.TradeWebhookDelivery{}).Where("id = ?", deliveryID).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.TradeWebhook{}).Where("id = ?", item.WebhookID).Updates(map[string]any{
+			"last_delivery_at":     at,
+			"last_status":          status,
+			"last_error":           errMsg,
+			"consecutive_failures": failures,
+		}).Error
+	})
+}
+
+func (s *Store) UpdateStrategyParams(ctx context.Context, name string, params []byte) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+	return s.db.WithContext(ctx).
+		Model(&models.Strategy{}).
+		Where("name = ?", name).
+		Updates(map[string]any{"params": params, "updated_at": time.Now().UTC()}).
+		Error
+}
+
+func (s *Store) UpdateStrategyStats(ctx context.Context, name string, stats []byte) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+	return s.db.WithContext(ctx).
+		Model(&models.Strategy{}).
+		Where("name = ?", name).
+		Updates(map[string]any{"stats": stats, "updated_at": time.Now().UTC()}).
+		Error
+}
+
+func (s *Store) InsertOpportunity(ctx context.Context, item *models.Opportunity) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Create(item).Error; err != nil {
+		return err
+	}
+	s.publish(ctx, events.Event{Topic: events.TopicOpportunityCreated, ID: item.ID, Strategy: item.Strategy.Name, Status: item.Status})
+	return nil
+}
+
+func (s *Store) UpsertActiveOpportunity(ctx context.Context, item *models.Opportunity) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	if item.StrategyID == 0 {
+		return s.InsertOpportunity(ctx, item)
+	}
+
+	keyEventID := ""
+	if item.EventID != nil {
+		keyEventID = strings.TrimSpace(*item.EventID)
+	}
+	keyMarketID := ""
+	if item.PrimaryMarketID != nil {
+		keyMarketID = strings.TrimSpace(*item.PrimaryMarketID)
+	}
+	if keyEventID == "" && keyMarketID == "" {
+		return s.InsertOpportunity(ctx, item)
+	}
+
+	var existing models.Opportunity
+	query := s.db.WithContext(ctx).
+		Model(&models.Opportunity{}).
+		Where("strategy_id = ?", item.StrategyID).
+		Where("status = ?", "active")
+	if keyEventID != "" {
+		query = query.Where("event_id = ?", keyEventID)
+	} else {
+		query = query.Where("primary_market_id = ?", keyMarketID)
+	}
+	err := query.Order("created_at desc").First(&existing).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	if err == gorm.ErrRecordNotFound {
+		return s.InsertOpportunity(ctx, item)
+	}
+	// Update core fields in-place, keep status/strategy/event stable.
+	updates := map[string]any{
+		"primary_market_id":  item.PrimaryMarketID,
+		"shadow":             item.Shadow,
+		"market_ids":         item.MarketIDs,
+		"edge_pct":           item.EdgePct,
+		"edge_usd":           item.EdgeUSD,
+		"max_size":           item.MaxSize,
+		"confidence":         item.Confidence,
+		"risk_score":         item.RiskScore,
+		"decay_type":         item.DecayType,
+		"expires_at":         item.ExpiresAt,
+		"edge_observed_at":   item.EdgeObservedAt,
+		"decayed_edge_pct":   item.DecayedEdgePct,
+		"decayed_confidence": item.DecayedConfidence,
+		"anchor_at":          item.AnchorAt,
+		"legs":               item.Legs,
+		"signal_ids":         item.SignalIDs,
+		"signal_type":        item.SignalType,
+		"strategy_change_id": item.StrategyChangeID,
+		"cohort":             item.Cohort,
+		"reasoning":          item.Reasoning,
+		"data_age_ms":        item.DataAgeMs,
+		"warnings":           item.Warnings,
+		"updated_at":         time.Now().UTC(),
+	}
+	return s.db.WithContext(ctx).
+		Model(&models.Opportunity{}).
+		Where("id = ?", existing.ID).
+		Updates(updates).Error
+}
+
+func (s *Store) GetOpportunityByID(ctx context.Context, id uint64) (*models.Opportunity, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	if id == 0 {
+		return nil, nil
+	}
+	var item models.Opportunity
+	err := s.db.WithContext(ctx).
+		Model(&models.Opportunity{}).
+		Preload("Strategy").
+		Where("id = ?", id).
+		First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) ListOpportunities(ctx context.Context, params repository.ListOpportunitiesParams) ([]models.Opportunity, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.Opportunity{}).Preload("Strategy")
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	needsStratJoin := (params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "") ||
+		(params.Category != nil && strings.TrimSpace(*params.Category) != "")
+	if needsStratJoin {
+		query = query.Joins("JOIN strategies ON strategies.id = opportunities.strategy_id").
+			Where("1 = 1")
+		if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
+			query = query.Where("strategies.name = ?", strings.TrimSpace(*params.StrategyName))
+		}
+		if params.Category != nil && strings.TrimSpace(*params.Category) != "" {
+			query = query.Where("strategies.category = ?", strings.TrimSpace(*params.Category))
+		}
+	}
+	if params.Tenant != nil && strings.TrimSpace(*params.Tenant) != "" {
+		query = query.Where("opportunities.tenant = ?", strings.TrimSpace(*params.Tenant))
+	}
+	if params.MinEdgePct != nil {
+		query = query.Where("edge_pct >= ?", *params.MinEdgePct)
+	}
+	if params.MinConfidence != nil {
+		query = query.Where("confidence >= ?", *params.MinConfidence)
+	}
+	if params.Since != nil {
+		query = query.Where("opportunities.created_at >= ?", *params.Since)
+	}
+	if params.Until != nil {
+		query = query.Where("opportunities.created_at < ?", *params.Until)
+	}
+	query = applyOrder(query, params.OrderBy, params.Asc, "created_at")
+	limit := normalizeLimit(params.Limit, 200)
+	offset := normalizeOffset(params.Offset)
+	var items []models.Opportunity
+	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountOpportunities(ctx context.Context, params repository.ListOpportunitiesParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.Opportunity{})
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	needsStratJoin := (params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "") ||
+		(params.Category != nil && strings.TrimSpace(*params.Category) != "")
+	if needsStratJoin {
+		query = query.Joins("JOIN strategies ON strategies.id = opportunities.strategy_id").
+			Where("1 = 1")
+		if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
+			query = query.Where("strategies.name = ?", strings.TrimSpace(*params.StrategyName))
+		}
+		if params.Category != nil && strings.TrimSpace(*params.Category) != "" {
+			query = query.Where("strategies.category = ?", strings.TrimSpace(*params.Category))
+		}
+	}
+	if params.Tenant != nil && strings.TrimSpace(*params.Tenant) != "" {
+		query = query.Where("opportunities.tenant = ?", strings.TrimSpace(*params.Tenant))
+	}
+	if params.MinEdgePct != nil {
+		query = query.Where("edge_pct >= ?", *params.MinEdgePct)
+	}
+	if params.MinConfidence != nil {
+		query = query.Where("confidence >= ?", *params.MinConfidence)
+	}
+	if params.Since != nil {
+		query = query.Where("opportunities.created_at >= ?", *params.Since)
+	}
+	if params.Until != nil {
+		query = query.Where("opportunities.created_at < ?", *params.Until)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (s *Store) UpdateOpportunityStatus(ctx context.Context, id uint64, status string) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.UpdateOpportunityStatusTx(ctx, s.db, id, status)
+}
+
+func (s *Store) UpdateOpportunityStatusTx(ctx context.Context, tx *gorm.DB, id uint64, status string) error {
+	if tx == nil {
+		return nil
+	}
+	if id == 0 || strings.TrimSpace(status) == "" {
+		return nil
+	}
+	return tx.WithContext(ctx).
+		Model(&models.Opportunity{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": strings.TrimSpace(status), "updated_at": time.Now().UTC()}).
+		Error
+}
+
+func (s *Store) ExpireDueOpportunities(ctx context.Context, now time.Time) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	res := s.db.WithContext(ctx).
+		Model(&models.Opportunity{}).
+		Where("status = ?", "active").
+		Where("expires_at IS NOT NULL").
+		Where("expires_at < ?", now).
+		Updates(map[string]any{"status": "expired", "updated_at": time.Now().UTC()})
+	return res.RowsAffected, res.Error
+}
+
+func (s *Store) UpdateOpportunityDecay(ctx context.Context, id uint64, decay repository.OpportunityDecay) error {
+	if s == nil || s.db == nil || id == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).
+		Model(&models.Opportunity{}).
+		Where("id = ?", id).
+		Where("status = ?", "active").
+		Updates(map[string]any{
+			"decayed_edge_pct":   decay.DecayedEdgePct,
+			"decayed_confidence": decay.DecayedConfidence,
+			"expires_at":         decay.ExpiresAt,
+			"anchor_at":          decay.AnchorAt,
+			"updated_at":         time.Now().UTC(),
+		}).Error
+}
+
+func (s *Store) CountActiveOpportunities(ctx context.Context) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var total int64
+	err := s.db.WithContext(ctx).
+		Model(&models.Opportunity{}).
+		Where("status = ?", "active").
+		Count(&total).Error
+	return total, err
+}
+
+func (s *Store) ListOldestActiveOpportunityIDs(ctx context.Context, limit int) ([]uint64, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	limit = normalizeLimit(limit, 200)
+	var ids []uint64
+	err := s.db.WithContext(ctx).
+		Model(&models.Opportunity{}).
+		Where("status = ?", "active").
+		Order("created_at asc").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (s *Store) BulkUpdateOpportunityStatus(ctx context.Context, ids []uint64, status string) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	if len(ids) == 0 || strings.TrimSpace(status) == "" {
+		return 0, nil
+	}
+	res := s.db.WithContext(ctx).
+		Model(&models.Opportunity{}).
+		Where("id IN ?", ids).
+		Updates(map[string]any{"status": strings.TrimSpace(status), "updated_at": time.Now().UTC()})
+	return res.RowsAffected, res.Error
+}
+
+func (s *Store) UpsertMarketLabel(ctx context.Context, item *models.MarketLabel) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	if strings.TrimSpace(item.MarketID) == "" || strings.TrimSpace(item.Label) == "" {
+		return nil
+	}
+	// Uniqueness is enforced by uniq_market_label (market_id, label).
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "market_id"}, {Name: "label"}},
+			DoNothing: true,
+		}).Create(item)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Create(labelChange(item.MarketID, item.Label, models.CatalogChangeLabeled)).Error
+	})
+}
+
+// labelChange journals a label added to or removed from a market.
+func labelChange(marketID, label, kind string) *models.CatalogChange {
+	return &models.CatalogChange{
+		Entity:    "label",
+		EntityID:  strings.TrimSpace(marketID),
+		Kind:      kind,
+		Fields:    strings.TrimSpace(label),
+		ChangedAt: time.Now().UTC(),
+	}
+}
+
+func (s *Store) ListMarketLabels(ctx context.Context, params repository.ListMarketLabelsParams) ([]models.MarketLabel, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.MarketLabel{})
+	if params.MarketID != nil && strings.TrimSpace(*params.MarketID) != "" {
+		query = query.Where("market_id = ?", strings.TrimSpace(*params.MarketID))
+	}
+	if params.Label != nil && strings.TrimSpace(*params.Label) != "" {
+		query = query.Where("label = ?", strings.TrimSpace(*params.Label))
+	}
+	if params.SubLabel != nil && strings.TrimSpace(*params.SubLabel) != "" {
+		query = query.Where("sub_label = ?", strings.TrimSpace(*params.SubLabel))
+	}
+	query = applyOrder(query, params.OrderBy, params.Asc, "created_at")
+	limit := normalizeLimit(params.Limit, 500)
+	offset := normalizeOffset(params.Offset)
+	var items []models.MarketLabel
+	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) ListMarketLabelsByMarketIDs(ctx context.Context, marketIDs []string) ([]models.MarketLabel, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	if len(marketIDs) == 0 {
+		return nil, nil
+	}
+	var items []models.MarketLabel
+	if err := s.db.WithContext(ctx).
+		Model(&models.MarketLabel{}).
+		Where("market_id IN ?", marketIDs).
+		Order("market_id asc, label asc").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) DeleteMarketLabel(ctx context.Context, marketID string, label string) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	marketID = strings.TrimSpace(marketID)
+	label = strings.TrimSpace(label)
+	if marketID == "" || label == "" {
+		return nil
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("market_id = ? AND label = ?", marketID, label).Delete(&models.MarketLabel{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Create(labelChange(marketID, label, models.CatalogChangeUnlabeled)).Error
+	})
+}
+
+func (s *Store) ListTagsByEventIDs(ctx context.Context, eventIDs []string) (map[string][]models.Tag, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	if len(eventIDs) == 0 {
+		return map[string][]models.Tag{}, nil
+	}
+	type row struct {
+		EventID string
+		Tag     models.Tag
+	}
+	var rows []struct {
+		EventID string
+		ID      string
+		Label   string
+		Slug    string
+	}
+	if err := s.db.WithContext(ctx).
+		Table("catalog_event_tags AS et").
+		Select("et.event_id AS event_id, t.id AS id, t.label AS label, t.slug AS slug").
+		Joins("JOIN catalog_tags AS t ON t.id = et.tag_id").
+		Where("et.event_id IN ?", eventIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := map[string][]models.Tag{}
+	for _, r := range rows {
+		out[r.EventID] = append(out[r.EventID], models.Tag{ID: r.ID, Label: r.Label, Slug: r.Slug})
+	}
+	return out, nil
+}
+
+// --- Execution & Analytics (L6) ---------------------------------------------
+
+func (s *Store) InsertExecutionPlan(ctx context.Context, item *models.ExecutionPlan) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.InsertExecutionPlanTx(ctx, s.db, item)
+}
+
+func (s *Store) InsertExecutionPlanTx(ctx context.Context, tx *gorm.DB, item *models.ExecutionPlan) error {
+	if tx == nil || item == nil {
+		return nil
+	}
+	if err := tx.WithContext(ctx).Create(item).Error; err != nil {
+		return err
+	}
+	s.publishTx(ctx, tx, events.Event{Topic: events.TopicPlanStatus, ID: item.ID, Strategy: item.StrategyName, Status: item.Status})
+	return nil
+}
+
+func (s *Store) GetExecutionPlanByID(ctx context.Context, id uint64) (*models.ExecutionPlan, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	if id == 0 {
+		return nil, nil
+	}
+	var item models.ExecutionPlan
+	err := s.db.WithContext(ctx).Model(&models.ExecutionPlan{}).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) ListExecutionPlans(ctx context.Context, params repository.ListExecutionPlansParams) ([]models.ExecutionPlan, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.ExecutionPlan{})
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	if params.Source != nil && strings.TrimSpace(*params.Source) != "" {
+		query = query.Where("source = ?", strings.TrimSpace(*params.Source))
+	}
+	if params.Tenant != nil && strings.TrimSpace(*params.Tenant) != "" {
+		query = query.Where("tenant = ?", strings.TrimSpace(*params.Tenant))
+	}
+	if params.CampaignID != nil {
+		query = query.Where("campaign_id = ?", *params.CampaignID)
+	}
+	if params.OpportunityID != nil {
+		query = query.Where("opportunity_id = ?", *params.OpportunityID)
+	}
+	if params.TriggerState != nil && strings.TrimSpace(*params.TriggerState) != "" {
+		query = query.Where("trigger_state = ?", strings.TrimSpace(*params.TriggerState))
+	}
+	query = applyOrder(query, params.OrderBy, params.Asc, "created_at")
+	limit := normalizeLimit(params.Limit, 200)
+	offset := normalizeOffset(params.Offset)
+	var items []models.ExecutionPlan
+	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountExecutionPlans(ctx context.Context, params repository.ListExecutionPlansParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.ExecutionPlan{})
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	if params.Source != nil && strings.TrimSpace(*params.Source) != "" {
+		query = query.Where("source = ?", strings.TrimSpace(*params.Source))
+	}
+	if params.Tenant != nil && strings.TrimSpace(*params.Tenant) != "" {
+		query = query.Where("tenant = ?", strings.TrimSpace(*params.Tenant))
+	}
+	if params.CampaignID != nil {
+		query = query.Where("campaign_id = ?", *params.CampaignID)
+	}
+	if params.OpportunityID != nil {
+		query = query.Where("opportunity_id = ?", *params.OpportunityID)
+	}
+	if params.TriggerState != nil && strings.TrimSpace(*params.TriggerState) != "" {
+		query = query.Where("trigger_state = ?", strings.TrimSpace(*params.TriggerState))
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (s *Store) ListExecutionPlansByStatuses(ctx context.Context, statuses []string, limit int) ([]models.ExecutionPlan, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	statuses = cleanStrings(statuses)
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	limit = normalizeLimit(limit, 5000)
+	var items []models.ExecutionPlan
+	if err := s.db.WithContext(ctx).
+		Model(&models.ExecutionPlan{}).
+		Where("status IN ?", statuses).
+		Order("created_at desc").
+		Limit(limit).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) UpdateExecutionPlanSizing(ctx context.Context, id uint64, plannedSizeUSD, maxLossUSD decimal.Decimal, legs []byte) error {
+	if s == nil || s.db == nil || id == 0 {
+		return nil
+	}
+	err := s.db.WithContext(ctx).
+		Model(&models.ExecutionPlan{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"planned_size_usd": plannedSizeUSD,
+			"max_loss_usd":     maxLossUSD,
+			"legs":             legs,
+			"status":           "draft",
+			"preflight_result": []byte(`{}`),
+			"updated_at":       time.Now().UTC(),
+		}).
+		Error
+	if err == nil {
+		s.publishPlanStatus(ctx, id, "draft")
+	}
+	return err
+}
+
+func (s *Store) UpdateExecutionPlanStatus(ctx context.Context, id uint64, status string) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.UpdateExecutionPlanStatusTx(ctx, s.db, id, status)
+}
+
+func (s *Store) UpdateExecutionPlanStatusTx(ctx context.Context, tx *gorm.DB, id uint64, status string) error {
+	if tx == nil {
+		return nil
+	}
+	if id == 0 || strings.TrimSpace(status) == "" {
+		return nil
+	}
+	err := tx.WithContext(ctx).
+		Model(&models.ExecutionPlan{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": strings.TrimSpace(status), "updated_at": time.Now().UTC()}).
+		Error
+	if err == nil {
+		s.publishPlanStatusTx(ctx, tx, id, status)
+	}
+	return err
+}
+
+func (s *Store) UpdateExecutionPlanPreflight(ctx context.Context, id uint64, status string, preflightResult []byte, riskReport []byte) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	if id == 0 || strings.TrimSpace(status) == "" {
+		return nil
+	}
+	updates := map[string]any{
+		"status":           strings.TrimSpace(status),
+		"preflight_result": preflightResult,
+		"updated_at":       time.Now().UTC(),
+	}
+	if len(riskReport) > 0 {
+		updates["risk_report"] = riskReport
+	}
+	err := s.db.WithContext(ctx).
+		Model(&models.ExecutionPlan{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+	if err == nil {
+		s.publishPlanStatus(ctx, id, status)
+	}
+	return err
+}
+
+func (s *Store) UpdateExecutionPlanExecutedAt(ctx context.Context, id uint64, status string, executedAt *time.Time) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.UpdateExecutionPlanExecutedAtTx(ctx, s.db, id, status, executedAt)
+}
+
+func (s *Store) UpdateExecutionPlanExecutedAtTx(ctx context.Context, tx *gorm.DB, id uint64, status string, executedAt *time.Time) error {
+	if tx == nil {
+		return nil
+	}
+	if id == 0 || strings.TrimSpace(status) == "" {
+		return nil
+	}
+	updates := map[string]any{
+		"status":      strings.TrimSpace(status),
+		"executed_at": executedAt,
+		"updated_at":  time.Now().UTC(),
+	}
+	err := tx.WithContext(ctx).
+		Model(&models.ExecutionPlan{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+	if err == nil {
+		s.publishPlanStatusTx(ctx, tx, id, status)
+	}
+	return err
+}
+
+func (s *Store) UpdateExecutionPlanTrigger(ctx context.Context, item *models.ExecutionPlan) error {
+	if s == nil || s.db == nil || item == nil || item.ID == 0 {
+		return nil
+	}
+	updates := map[string]any{
+		"trigger_state":              strings.TrimSpace(item.TriggerState),
+		"trigger_at":                 item.TriggerAt,
+		"trigger_minutes_before_end": item.TriggerMinutesBeforeEnd,
+		"trigger_market_id":          item.TriggerMarketID,
+		"trigger_error":              item.TriggerError,
+		"updated_at":                 time.Now().UTC(),
+	}
+	return s.db.WithContext(ctx).
+		Model(&models.ExecutionPlan{}).
+		Where("id = ?", item.ID).
+		Updates(updates).Error
+}
+
+func (s *Store) ListDuePlanTriggers(ctx context.Context, now time.Time, limit int) ([]models.ExecutionPlan, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var items []models.ExecutionPlan
+	err := s.db.WithContext(ctx).
+		Model(&models.ExecutionPlan{}).
+		Where("trigger_state = ?", "scheduled").
+		Where("trigger_at <= ?", now.UTC()).
+		Where("status IN ?", []string{"draft", "preflight_pass"}).
+		Order("trigger_at asc").
+		Limit(normalizeLimit(limit, 100)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) CountExecutionPlansByStrategySince(ctx context.Context, strategyName string, since time.Time) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	strategyName = strings.TrimSpace(strategyName)
+	if strategyName == "" || since.IsZero() {
+		return 0, nil
+	}
+	query := s.db.WithContext(ctx).
+		Model(&models.ExecutionPlan{}).
+		Where("strategy_name = ?", strategyName).
+		Where("created_at >= ?", since.UTC())
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (s *Store) CountExecutionPlansSince(ctx context.Context, since time.Time, autoExecuted *bool) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	query := s.db.WithContext(ctx).
+		Model(&models.ExecutionPlan{}).
+		Where("created_at >= ?", since.UTC())
+	if autoExecuted != nil {
+		query = query.Where("auto_executed = ?", *autoExecuted)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (s *Store) InsertFill(ctx context.Context, item *models.Fill) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Create(item).Error; err != nil {
+		return err
+	}
+	s.publish(ctx, events.Event{Topic: events.TopicFillCreated, ID: item.ID, PlanID: item.PlanID})
+	return nil
+}
+
+func (s *Store) publishPlanStatus(ctx context.Context, id uint64, status string) {
+	s.publish(ctx, events.Event{Topic: events.TopicPlanStatus, ID: id, Status: strings.TrimSpace(status)})
+}
+
+func (s *Store) publishPlanStatusTx(ctx context.Context, tx *gorm.DB, id uint64, status string) {
+	s.publishTx(ctx, tx, events.Event{Topic: events.TopicPlanStatus, ID: id, Status: strings.TrimSpace(status)})
+}
+
+func (s *Store) ListRecordedExternalTradeIDs(ctx context.Context, ids []string) ([]string, error) {
+	if s == nil || s.db == nil || len(ids) == 0 {
+		return nil, nil
+	}
+	var out []string
+	err := s.db.WithContext(ctx).
+		Model(&models.Fill{}).
+		Where("external_trade_id IN ?", ids).
+		Pluck("external_trade_id", &out).Error
+	return out, err
+}
+
+func (s *Store) ListFillsChronological(ctx context.Context, tokenID string) ([]models.Fill, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.Fill{})
+	if tokenID = strings.TrimSpace(tokenID); tokenID != "" {
+		query = query.Where("token_id = ?", tokenID)
+	}
+	var items []models.Fill
+	if err := query.Order("filled_at asc, id asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountFillsBetween(ctx context.Context, since, until time.Time) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var n int64
+	err := s.db.WithContext(ctx).Model(&models.Fill{}).
+		Where("filled_at >= ? AND filled_at < ?", since.UTC(), until.UTC()).
+		Count(&n).Error
+	return n, err
+}
+
+func (s *Store) GetFillByID(ctx context.Context, id uint64) (*models.Fill, error) {
+	if s == nil || s.db == nil || id == 0 {
+		return nil, nil
+	}
+	var item models.Fill
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) ListFillsByPlanID(ctx context.Context, planID uint64) ([]models.Fill, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	if planID == 0 {
+		return nil, nil
+	}
+	var items []models.Fill
+	if err := s.db.WithContext(ctx).
+		Model(&models.Fill{}).
+		Where("plan_id = ?", planID).
+		Order("filled_at asc").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) UpsertPnLRecord(ctx context.Context, item *models.PnLRecord) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.UpsertPnLRecordTx(ctx, s.db, item)
+}
+
+func (s *Store) UpsertPnLRecordTx(ctx context.Context, tx *gorm.DB, item *models.PnLRecord) error {
+	if tx == nil || item == nil {
+		return nil
+	}
+	if item.PlanID == 0 {
+		return tx.WithContext(ctx).Create(item).Error
+	}
+	return tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "plan_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"strategy_name", "expected_edge", "realized_pnl", "realized_roi", "slippage_loss", "outcome", "failure_reason", "settled_at", "notes"}),
+	}).Create(item).Error
+}
+
+func (s *Store) GetPnLRecordByPlanID(ctx context.Context, planID uint64) (*models.PnLRecord, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	if planID == 0 {
+		return nil, nil
+	}
+	var item models.PnLRecord
+	err := s.db.WithContext(ctx).
+		Where("plan_id = ?", planID).
+		First(&item).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) SumRealizedPnLSince(ctx context.Context, since time.Time) (decimal.Decimal, error) {
+	if s == nil || s.db == nil {
+		return decimal.Zero, nil
+	}
+	if since.IsZero() {
+		return decimal.Zero, nil
+	}
+	var out float64
+	err := s.db.WithContext(ctx).
+		Table("pnl_records").
+		Select("COALESCE(SUM(COALESCE(realized_pnl,0)),0)").
+		Where("created_at >= ?", since.UTC()).
+		Scan(&out).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.NewFromFloat(out), nil
+}
+
+func (s *Store) SumRealizedPnLSinceForTenant(ctx context.Context, since time.Time, tenant string) (decimal.Decimal, error) {
+	if s == nil || s.db == nil {
+		return decimal.Zero, nil
+	}
+	if since.IsZero() {
+		return decimal.Zero, nil
+	}
+	tenant = strings.TrimSpace(tenant)
+	if tenant == "" {
+		return s.SumRealizedPnLSince(ctx, since)
+	}
+	var out float64
+	err := s.db.WithContext(ctx).
+		Table("pnl_records").
+		Joins("JOIN execution_plans ON execution_plans.id = pnl_records.plan_id").
+		Select("COALESCE(SUM(COALESCE(pnl_records.realized_pnl,0)),0)").
+		Where("pnl_records.created_at >= ?", since.UTC()).
+		Where("execution_plans.tenant = ?", tenant).
+		Scan(&out).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.NewFromFloat(out), nil
+}
+
+func (s *Store) UpsertExecutionRule(ctx context.Context, item *models.ExecutionRule) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	item.StrategyName = strings.TrimSpace(item.StrategyName)
+	if item.StrategyName == "" {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "strategy_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"auto_execute",
+			"min_confidence",
+			"min_edge_pct",
+			"stop_loss_pct",
+			"take_profit_pct",
+			"max_hold_hours",
+			"max_daily_trades",
+			"condition",
+			"updated_at",
+		}),
+	}).Create(item).Error
+}
+
+func (s *Store) GetExecutionRuleByStrategyName(ctx context.Context, strategyName string) (*models.ExecutionRule, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	strategyName = strings.TrimSpace(strategyName)
+	if strategyName == "" {
+		return nil, nil
+	}
+	var item models.ExecutionRule
+	err := s.db.WithContext(ctx).
+		Model(&models.ExecutionRule{}).
+		Where("strategy_name = ?", strategyName).
+		First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) ListExecutionRules(ctx context.Context) ([]models.ExecutionRule, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var items []models.ExecutionRule
+	if err := s.db.WithContext(ctx).
+		Model(&models.ExecutionRule{}).
+		Order("strategy_name asc").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) OpportunityConversionByStrategy(ctx context.Context, since time.Time, tenant string) ([]repository.StrategyConversion, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).
+		Table("opportunities AS o").
+		Joins("JOIN strategies AS st ON st.id = o.strategy_id").
+		Joins("LEFT JOIN execution_plans AS p ON p.opportunity_id = o.id").
+		Select(`st.name AS strategy_name,
+			COUNT(DISTINCT o.id) AS opportunities,
+			COUNT(DISTINCT p.opportunity_id) AS planned`).
+		Where("o.created_at >= ?", since.UTC()).
+		Group("st.name")
+	if tenant = strings.TrimSpace(tenant); tenant != "" {
+		query = query.Where("o.tenant = ?", tenant)
+	}
+	var rows []repository.StrategyConversion
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *Store) DeleteExecutionRuleByStrategyName(ctx context.Context, strategyName string) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	strategyName = strings.TrimSpace(strategyName)
+	if strategyName == "" {
+		return nil
+	}
+	return s.db.WithContext(ctx).
+		Where("strategy_name = ?", strategyName).
+		Delete(&models.ExecutionRule{}).Error
+}
+
+func (s *Store) InsertTradeJournal(ctx context.Context, item *models.TradeJournal) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) GetTradeJournalByPlanID(ctx context.Context, planID uint64) (*models.TradeJournal, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	if planID == 0 {
+		return nil, nil
+	}
+	var item models.TradeJournal
+	err := s.db.WithContext(ctx).
+		Model(&models.TradeJournal{}).
+		Where("execution_plan_id = ?", planID).
+		First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) UpdateTradeJournalExit(ctx context.Context, planID uint64, updates map[string]any) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	if planID == 0 || len(updates) == 0 {
+		return nil
+	}
+	updates["updated_at"] = time.Now().UTC()
+	return s.db.WithContext(ctx).
+		Model(&models.TradeJournal{}).
+		Where("execution_plan_id = ?", planID).
+		Updates(updates).Error
+}
+
+func (s *Store) UpdateTradeJournalNotes(ctx context.Context, planID uint64, notes string, tags []byte, reviewedAt *time.Time) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	if planID == 0 {
+		return nil
+	}
+	updates := map[string]any{
+		"notes":       strings.TrimSpace(notes),
+		"tags":        tags,
+		"reviewed_at": reviewedAt,
+		"updated_at":  time.Now().UTC(),
+	}
+	return s.db.WithContext(ctx).
+		Model(&models.TradeJournal{}).
+		Where("execution_plan_id = ?", planID).
+		Updates(updates).Error
+}
+
+func (s *Store) UpdateTradeJournalSnapshots(ctx context.Context, planID uint64, snapshotIDs []byte) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	if planID == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).
+		Model(&models.TradeJournal{}).
+		Where("execution_plan_id = ?", planID).
+		Updates(map[string]any{"snapshot_ids": snapshotIDs, "updated_at": time.Now().UTC()}).Error
+}
+
+func (s *Store) ListTradeJournals(ctx context.Context, params repository.ListTradeJournalParams) ([]models.TradeJournal, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := planTenantScope(s.db.WithContext(ctx).Model(&models.TradeJournal{}), "execution_plan_id", tenantParam(params.Tenant))
+	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
+		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+	}
+	if params.Outcome != nil && strings.TrimSpace(*params.Outcome) != "" {
+		query = query.Where("outcome = ?", strings.TrimSpace(*params.Outcome))
+	}
+	if params.Since != nil && !params.Since.IsZero() {
+		query = query.Where("created_at >= ?", params.Since.UTC())
+	}
+	if params.Until != nil && !params.Until.IsZero() {
+		query = query.Where("created_at <= ?", params.Until.UTC())
+	}
+	for _, tag := range cleanStrings(params.Tags) {
+		like := "%" + tag + "%"
+		query = query.Where("CAST(tags AS TEXT) LIKE ?", like)
+	}
+	query = applyOrder(query, params.OrderBy, params.Asc, "created_at")
+	limit := normalizeLimit(params.Limit, 200)
+	offset := normalizeOffset(params.Offset)
+	var items []models.TradeJournal
+	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountTradeJournals(ctx context.Context, params repository.ListTradeJournalParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	query := planTenantScope(s.db.WithContext(ctx).Model(&models.TradeJournal{}), "execution_plan_id", tenantParam(params.Tenant))
+	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
+		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+	}
+	if params.Outcome != nil && strings.TrimSpace(*params.Outcome) != "" {
+		query = query.Where("outcome = ?", strings.TrimSpace(*params.Outcome))
+	}
+	if params.Since != nil && !params.Since.IsZero() {
+		query = query.Where("created_at >= ?", params.Since.UTC())
+	}
+	if params.Until != nil && !params.Until.IsZero() {
+		query = query.Where("created_at <= ?", params.Until.UTC())
+	}
+	for _, tag := range cleanStrings(params.Tags) {
+		like := "%" + tag + "%"
+		query = query.Where("CAST(tags AS TEXT) LIKE ?", like)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (s *Store) UpsertSystemSetting(ctx context.Context, item *models.SystemSetting) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	item.Key = strings.TrimSpace(item.Key)
+	if item.Key == "" {
+		return nil
+	}
+	item.Tenant = rowTenant(item.Tenant)
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"value",
+			"description",
+			"updated_at",
+		}),
+	}).Create(item).Error
+}
+
+func (s *Store) UpsertSystemSettings(ctx context.Context, items []models.SystemSetting) error {
+	if s == nil || s.db == nil || len(items) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range items {
+			items[i].Key = strings.TrimSpace(items[i].Key)
+			if items[i].Key == "" {
+				continue
+			}
+			items[i].Tenant = rowTenant(items[i].Tenant)
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "tenant"}, {Name: "key"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"value",
+					"description",
+					"updated_at",
+				}),
+			}).Create(&items[i]).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) GetSystemSettingByKey(ctx context.Context, key string) (*models.SystemSetting, error) {
+	return s.GetTenantSystemSetting(ctx, "default", key)
+}
+
+func (s *Store) GetTenantSystemSetting(ctx context.Context, tenant, key string) (*models.SystemSetting, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, nil
+	}
+	var item models.SystemSetting
+	err := s.db.WithContext(ctx).Model(&models.SystemSetting{}).Where("tenant = ? AND key = ?", rowTenant(tenant), key).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) ListSystemSettings(ctx context.Context, params repository.ListSystemSettingsParams) ([]models.SystemSetting, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.SystemSetting{})
+	if params.Prefix != nil && strings.TrimSpace(*params.Prefix) != "" {
+		pattern := strings.TrimSpace(*params.Prefix) + "%"
+		query = query.Where("key LIKE ?", pattern)
+	}
+	if params.Tenant != nil && strings.TrimSpace(*params.Tenant) != "" {
+		query = query.Where("tenant IN ?", []string{strings.TrimSpace(*params.Tenant), "default"})
+	}
+	query = applyOrder(query, params.OrderBy, params.Asc, "key")
+	limit := normalizeLimit(params.Limit, 500)
+	offset := normalizeOffset(params.Offset)
+	var items []models.SystemSetting
+	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountSystemSettings(ctx context.Context, params repository.ListSystemSettingsParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.SystemSetting{})
+	if params.Prefix != nil && strings.TrimSpace(*params.Prefix) != "" {
+		pattern := strings.TrimSpace(*params.Prefix) + "%"
+		query = query.Where("key LIKE ?", pattern)
+	}
+	if params.Tenant != nil && strings.TrimSpace(*params.Tenant) != "" {
+		query = query.Where("tenant IN ?", []string{strings.TrimSpace(*params.Tenant), "default"})
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (s *Store) UpsertPosition(ctx context.Context, item *models.Position) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	item.TokenID = strings.TrimSpace(item.TokenID)
+	if item.TokenID == "" {
+		return nil
+	}
+	item.Tenant = rowTenant(item.Tenant)
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant"}, {Name: "token_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"market_id",
+			"event_id",
+			"direction",
+			"quantity",
+			"avg_entry_price",
+			"current_price",
+			"cost_basis",
+			"unrealized_pnl",
+			"realized_pnl",
+			"status",
+			"strategy_name",
+			"external_wallet",
+			"opened_at",
+			"closed_at",
+			"updated_at",
+		}),
+	}).Create(item).Error
+	if err != nil {
+		return err
+	}
+	if item.Status == "closed" && item.ID > 0 {
+		s.publish(ctx, events.Event{Topic: events.TopicPositionClosed, ID: item.ID, Strategy: item.StrategyName})
+	}
+	return nil
+}
+
+// rowTenant is the desk a tenant-keyed row (positions, system settings) is
+// stored under; empty is the default desk.
+func rowTenant(tenant string) string {
+	if t := strings.ToLower(strings.TrimSpace(tenant)); t != "" {
+		return t
+	}
+	return "default"
+}
+
+// tenantParam reads an optional desk filter; "" leaves a query unscoped.
+func tenantParam(tenant *string) string {
+	if tenant == nil {
+		return ""
+	}
+	return strings.TrimSpace(*tenant)
+}
+
+// planTenantScope keeps rows whose plan, referenced by column, belongs to
+// tenant. Fills, orders and the journal carry no tenant of their own.
+func planTenantScope(query *gorm.DB, column, tenant string) *gorm.DB {
+	if tenant = strings.TrimSpace(tenant); tenant == "" {
+		return query
+	}
+	return query.Where(column+" IN (SELECT id FROM execution_plans WHERE tenant = ?)", tenant)
+}
+
+// strategyTenantScope keeps rows whose strategy, named by column, belongs to
+// tenant.
+func strategyTenantScope(query *gorm.DB, column, tenant string) *gorm.DB {
+	if tenant = strings.TrimSpace(tenant); tenant == "" {
+		return query
+	}
+	return query.Where(column+" IN (SELECT name FROM strategies WHERE tenant = ?)", tenant)
+}
+
+func (s *Store) GetPositionByID(ctx context.Context, id uint64) (*models.Position, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	if id == 0 {
+		return nil, nil
+	}
+	var item models.Position
+	err := s.db.WithContext(ctx).Model(&models.Position{}).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) GetPositionByTokenID(ctx context.Context, tenant, tokenID string) (*models.Position, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	tokenID = strings.TrimSpace(tokenID)
+	if tokenID == "" {
+		return nil, nil
+	}
+	var item models.Position
+	err := s.db.WithContext(ctx).Model(&models.Position{}).Where("tenant = ? AND token_id = ?", rowTenant(tenant), tokenID).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) ListPositions(ctx context.Context, params repository.ListPositionsParams) ([]models.Position, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.Position{})
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	if params.Tenant != nil && strings.TrimSpace(*params.Tenant) != "" {
+		query = query.Where("tenant = ?", strings.TrimSpace(*params.Tenant))
+	}
+	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
+		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+	}
+	if params.MarketID != nil && strings.TrimSpace(*params.MarketID) != "" {
+		query = query.Where("market_id = ?", strings.TrimSpace(*params.MarketID))
+	}
+	if params.Source != nil && strings.TrimSpace(*params.Source) != "" {
+		query = query.Where("source = ?", strings.TrimSpace(*params.Source))
+	}
+	query = applyOrder(query, params.OrderBy, params.Asc, "opened_at")
+	limit := normalizeLimit(params.Limit, 200)
+	offset := normalizeOffset(params.Offset)
+	var items []models.Position
+	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountPositions(ctx context.Context, params repository.ListPositionsParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.Position{})
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	if params.Tenant != nil && strings.TrimSpace(*params.Tenant) != "" {
+		query = query.Where("tenant = ?", strings.TrimSpace(*params.Tenant))
+	}
+	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
+		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+	}
+	if params.MarketID != nil && strings.TrimSpace(*params.MarketID) != "" {
+		query = query.Where("market_id = ?", strings.TrimSpace(*params.MarketID))
+	}
+	if params.Source != nil && strings.TrimSpace(*params.Source) != "" {
+		query = query.Where("source = ?", strings.TrimSpace(*params.Source))
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (s *Store) ListOpenPositions(ctx context.Context) ([]models.Position, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var items []models.Position
+	if err := s.db.WithContext(ctx).Model(&models.Position{}).
+		Where("status = ?", "open").
+		Order("opened_at asc").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) ClosePosition(ctx context.Context, id uint64, realizedPnL decimal.Decimal, closedAt time.Time) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	if id == 0 {
+		return nil
+	}
+	if closedAt.IsZero() {
+		closedAt = time.Now().UTC()
+	}
+	err := s.db.WithContext(ctx).Model(&models.Position{}).Where("id = ?", id).Updates(map[string]any{
+		"status":         "closed",
+		"closed_at":      &closedAt,
+		"quantity":       decimal.Zero,
+		"cost_basis":     decimal.Zero,
+		"unrealized_pnl": decimal.Zero,
+		"realized_pnl":   realizedPnL,
+		"updated_at":     time.Now().UTC(),
+	}).Error
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.Event{Topic: events.TopicPositionClosed, ID: id})
+	return nil
+}
+
+func (s *Store) PositionsSummary(ctx context.Context) (repository.PositionsSummary, error) {
+	if s == nil || s.db == nil {
+		return repository.PositionsSummary{}, nil
+	}
+	var row struct {
+		TotalOpen      int64
+		TotalCostBasis float64
+		TotalMarketVal float64
+		UnrealizedPnL  float64
+		RealizedPnL    float64
+	}
+	err := s.db.WithContext(ctx).
+		Table("positions").
+		Select(`
+			COALESCE(SUM(CASE WHEN status = 'open' THEN 1 ELSE 0 END),0) AS total_open,
+			COALESCE(SUM(CASE WHEN status = 'open' THEN cost_basis ELSE 0 END),0) AS total_cost_basis,
+			COALESCE(SUM(CASE WHEN status = 'open' THEN (current_price * quantity) ELSE 0 END),0) AS total_market_val,
+			COALESCE(SUM(CASE WHEN status = 'open' THEN unrealized_pnl ELSE 0 END),0) AS unrealized_pnl,
+			COALESCE(SUM(realized_pnl),0) AS realized_pnl
+		`).
+		Scan(&row).Error
+	if err != nil {
+		return repository.PositionsSummary{}, err
+	}
+	return repository.PositionsSummary{
+		TotalOpen:      row.TotalOpen,
+		TotalCostBasis: row.TotalCostBasis,
+		TotalMarketVal: row.TotalMarketVal,
+		UnrealizedPnL:  row.UnrealizedPnL,
+		RealizedPnL:    row.RealizedPnL,
+		NetLiquidation: row.TotalMarketVal + row.RealizedPnL,
+	}, nil
+}
+
+func (s *Store) InsertPortfolioSnapshot(ctx context.Context, item *models.PortfolioSnapshot) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "snapshot_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"total_positions", "total_cost_basis", "total_market_val", "unrealized_pnl", "realized_pnl", "net_liquidation"}),
+	}).Create(item).Error
+}
+
+func (s *Store) ListPortfolioSnapshots(ctx context.Context, params repository.ListPortfolioSnapshotsParams) ([]models.PortfolioSnapshot, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.PortfolioSnapshot{})
+	if params.Since != nil && !params.Since.IsZero() {
+		query = query.Where("snapshot_at >= ?", params.Since.UTC())
+	}
+	if params.Until != nil && !params.Until.IsZero() {
+		query = query.Where("snapshot_at <= ?", params.Until.UTC())
+	}
+	limit := normalizeLimit(params.Limit, 500)
+	offset := normalizeOffset(params.Offset)
+	var items []models.PortfolioSnapshot
+	if err := query.Order("snapshot_at desc").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) InsertPortfolioAnomaly(ctx context.Context, item *models.PortfolioAnomaly) (bool, error) {
+	if s == nil || s.db == nil || item == nil {
+		return false, nil
+	}
+	res := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "snapshot_id"}, {Name: "kind"}},
+		DoNothing: true,
+	}).Create(item)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (s *Store) portfolioAnomaliesQuery(ctx context.Context, params repository.ListPortfolioAnomaliesParams) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.PortfolioAnomaly{})
+	if params.Kind != nil && strings.TrimSpace(*params.Kind) != "" {
+		query = query.Where("kind = ?", strings.TrimSpace(*params.Kind))
+	}
+	if params.Since != nil && !params.Since.IsZero() {
+		query = query.Where("snapshot_at >= ?", params.Since.UTC())
+	}
+	if params.Until != nil && !params.Until.IsZero() {
+		query = query.Where("snapshot_at <= ?", params.Until.UTC())
+	}
+	return query
+}
+
+func (s *Store) ListPortfolioAnomalies(ctx context.Context, params repository.ListPortfolioAnomaliesParams) ([]models.PortfolioAnomaly, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	limit := normalizeLimit(params.Limit, 500)
+	offset := normalizeOffset(params.Offset)
+	var items []models.PortfolioAnomaly
+	if err := s.portfolioAnomaliesQuery(ctx, params).Order("snapshot_at desc, id desc").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountPortfolioAnomalies(ctx context.Context, params repository.ListPortfolioAnomaliesParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var n int64
+	err := s.portfolioAnomaliesQuery(ctx, params).Count(&n).Error
+	return n, err
+}
+
+func (s *Store) InsertOrder(ctx context.Context, item *models.Order) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Create(item).Error; err != nil {
+		return err
+	}
+	if item.LineageID == 0 {
+		item.LineageID = item.ID
+		return s.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", item.ID).Update("lineage_id", item.ID).Error
+	}
+	return nil
+}
+
+func (s *Store) GetOrderByID(ctx context.Context, id uint64) (*models.Order, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	if id == 0 {
+		return nil, nil
+	}
+	var item models.Order
+	err := s.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Store) ListOrders(ctx context.Context, params repository.ListOrdersParams) ([]models.Order, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := planTenantScope(s.db.WithContext(ctx).Model(&models.Order{}), "plan_id", tenantParam(params.Tenant))
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	if params.PlanID != nil && *params.PlanID > 0 {
+		query = query.Where("plan_id = ?", *params.PlanID)
+	}
+	if params.TokenID != nil && strings.TrimSpace(*params.TokenID) != "" {
+		query = query.Where("token_id = ?", strings.TrimSpace(*params.TokenID))
+	}
+	if params.LineageID != nil && *params.LineageID > 0 {
+		query = query.Where("(lineage_id = ? OR id = ?)", *params.LineageID, *params.LineageID)
+	}
+	if params.MergedIntoID != nil && *params.MergedIntoID > 0 {
+		query = query.Where("merged_into_id = ?", *params.MergedIntoID)
+	}
+	query = applyOrder(query, params.OrderBy, params.Asc, "created_at")
+	limit := normalizeLimit(params.Limit, 200)
+	offset := normalizeOffset(params.Offset)
+	var items []models.Order
+	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountOpenOrders(ctx context.Context) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var total int64
+	err := s.db.WithContext(ctx).Model(&models.Order{}).
+		Where("status IN ?", models.OpenOrderStatuses()).
+		Count(&total).Error
+	return total, err
+}
+
+func (s *Store) CountOrders(ctx context.Context, params repository.ListOrdersParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	query := planTenantScope(s.db.WithContext(ctx).Model(&models.Order{}), "plan_id", tenantParam(params.Tenant))
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	if params.PlanID != nil && *params.PlanID > 0 {
+		query = query.Where("plan_id = ?", *params.PlanID)
+	}
+	if params.TokenID != nil && strings.TrimSpace(*params.TokenID) != "" {
+		query = query.Where("token_id = ?", strings.TrimSpace(*params.TokenID))
+	}
+	if params.LineageID != nil && *params.LineageID > 0 {
+		query = query.Where("(lineage_id = ? OR id = ?)", *params.LineageID, *params.LineageID)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (s *Store) UpdateOrderStatus(ctx context.Context, id uint64, status string, updates map[string]any) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	if id == 0 || strings.TrimSpace(status) == "" {
+		return nil
+	}
+	next := map[string]any{
+		"status":     strings.TrimSpace(status),
+		"updated_at": time.Now().UTC(),
+	}
+	for k, v := range updates {
+		next[k] = v
+	}
+	return s.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).Updates(next).Error
+}
+
+func (s *Store) AddOrderImprovementUSD(ctx context.Context, id uint64, usd decimal.Decimal) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	if id == 0 || usd.IsZero() {
+		return nil
+	}
+	return s.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).
+		Update("improvement_usd", gorm.Expr("improvement_usd + ?", usd)).Error
+}
+
+func (s *Store) UpsertStrategyDailyStats(ctx context.Context, item *models.StrategyDailyStats) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	item.StrategyName = strings.TrimSpace(item.StrategyName)
+	if item.StrategyName == "" || item.Date.IsZero() {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "strategy_name"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"trades_count",
+			"win_count",
+			"loss_count",
+			"pnl_usd",
+			"avg_edge_pct",
+			"avg_slippage_bps",
+			"avg_hold_hours",
+			"max_drawdown_usd",
+			"cumulative_pn_l",
+			"updated_at",
+		}),
+	}).Create(item).Error
+}
+
+func (s *Store) ListStrategyDailyStats(ctx context.Context, params repository.ListDailyStatsParams) ([]models.StrategyDailyStats, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := strategyTenantScope(s.db.WithContext(ctx).Model(&models.StrategyDailyStats{}), "strategy_name", tenantParam(params.Tenant))
+	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
+		query = query.Where("strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+	}
+	if params.Since != nil && !params.Since.IsZero() {
+		query = query.Where("date >= ?", params.Since.UTC())
+	}
+	if params.Until != nil && !params.Until.IsZero() {
+		query = query.Where("date <= ?", params.Until.UTC())
+	}
+	limit := normalizeLimit(params.Limit, 500)
+	offset := normalizeOffset(params.Offset)
+	var items []models.StrategyDailyStats
+	if err := query.Order("date desc, strategy_name asc").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) AttributionByStrategy(ctx context.Context, strategyName string, since, until, asOf *time.Time) (repository.AttributionResult, error) {
+	if s == nil || s.db == nil {
+		return repository.AttributionResult{}, nil
 	}
 	strategyName = strings.TrimSpace(strategyName)
 	if strategyName == "" {
 		return repository.AttributionResult{}, nil
 	}
-	query := s.db.WithContext(ctx).Table("pnl_records").Where("strategy_name = ?", strategyName)
+	query := s.pnlRecordsAsOf(ctx, asOf).Where("strategy_name = ?", strategyName)
 	if since != nil && !since.IsZero() {
 		query = query.Where("created_at >= ?", since.UTC())
 	}
@@ -1458,6 +4149,9 @@ func (s *Store) AttributionByStrategy(ctx context.Context, strategyName string,
 	if until != nil && !until.IsZero() {
 		feeQuery = feeQuery.Where("f.created_at <= ?", until.UTC())
 	}
+	if asOf != nil && !asOf.IsZero() {
+		feeQuery = feeQuery.Where("f.created_at <= ?", asOf.UTC())
+	}
 	var fee float64
 	if err := feeQuery.Scan(&fee).Error; err != nil {
 		return repository.AttributionResult{}, err
@@ -1472,15 +4166,15 @@ func (s *Store) AttributionByStrategy(ctx context.Context, strategyName string,
 	}, nil
 }
 
-func (s *Store) PortfolioDrawdown(ctx context.Context) (repository.DrawdownResult, error) {
+func (s *Store) PortfolioDrawdown(ctx context.Context, tenant string) (repository.DrawdownResult, error) {
 	if s == nil || s.db == nil {
 		return repository.DrawdownResult{}, nil
 	}
 	var rows []struct {
-		TS  *time.Time
+		TS  sqlTime
 		PnL float64
 	}
-	if err := s.db.WithContext(ctx).Table("pnl_records").
+	if err := planTenantScope(s.db.WithContext(ctx).Table("pnl_records"), "plan_id", tenant).
 		Select("COALESCE(settled_at, created_at) AS ts, COALESCE(realized_pnl,0) AS pnl").
 		Order("COALESCE(settled_at, created_at) asc").
 		Scan(&rows).Error; err != nil {
@@ -1501,8 +4195,8 @@ func (s *Store) PortfolioDrawdown(ctx context.Context) (repository.DrawdownResul
 		if cum > peak || peakTime == nil {
 			peak = cum
 			t := time.Now().UTC()
-			if r.TS != nil {
-				t = r.TS.UTC()
+			if r.TS.Valid {
+				t = r.TS.Time.UTC()
 			}
 			peakTime = &t
 		}
@@ -1511,8 +4205,8 @@ func (s *Store) PortfolioDrawdown(ctx context.Context) (repository.DrawdownResul
 			maxDD = dd
 			trough = cum
 			t := time.Now().UTC()
-			if r.TS != nil {
-				t = r.TS.UTC()
+			if r.TS.Valid {
+				t = r.TS.Time.UTC()
 			}
 			troughTime = &t
 		}
@@ -1538,11 +4232,11 @@ func (s *Store) PortfolioDrawdown(ctx context.Context) (repository.DrawdownResul
 	}, nil
 }
 
-func (s *Store) StrategyCorrelation(ctx context.Context, since, until *time.Time) ([]repository.CorrelationRow, error) {
+func (s *Store) StrategyCorrelation(ctx context.Context, since, until *time.Time, tenant string) ([]repository.CorrelationRow, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	query := s.db.WithContext(ctx).Table("strategy_daily_stats")
+	query := strategyTenantScope(s.db.WithContext(ctx).Table("strategy_daily_stats"), "strategy_name", tenant)
 	if since != nil && !since.IsZero() {
 		query = query.Where("date >= ?", since.UTC())
 	}
@@ -1583,20 +4277,18 @@ func (s *Store) StrategyCorrelation(ctx context.Context, since, until *time.Time
 	return out, nil
 }
 
-func (s *Store) PerformanceRatios(ctx context.Context, since, until *time.Time) (repository.RatiosResult, error) {
+func (s *Store) PerformanceRatios(ctx context.Context, since, until, asOf *time.Time, tenant string) (repository.RatiosResult, error) {
 	if s == nil || s.db == nil {
 		return repository.RatiosResult{}, nil
 	}
-	query := s.db.WithContext(ctx).Table("pnl_records")
+	query := planTenantScope(s.pnlRecordsAsOf(ctx, asOf), "plan_id", tenant)
 	if since != nil && !since.IsZero() {
 		query = query.Where("created_at >= ?", since.UTC())
 	}
 	if until != nil && !until.IsZero() {
 		query = query.Where("created_at <= ?", until.UTC())
 	}
-	var rows []struct {
-		PnL float64
-	}
+	var rows []pnlRow
 	if err := query.Select("COALESCE(realized_pnl,0) AS pnl").Scan(&rows).Error; err != nil {
 		return repository.RatiosResult{}, err
 	}
@@ -1606,76 +4298,134 @@ func (s *Store) PerformanceRatios(ctx context.Context, since, until *time.Time)
 	return calcRatios(rows), nil
 }
 
-func (s *Store) RebuildStrategyDailyStats(ctx context.Context, since, until *time.Time) (int, error) {
+func (s *Store) RebuildStrategyDailyStats(ctx context.Context, since, until *time.Time, cal *tradingday.Calendar) (int, error) {
 	if s == nil || s.db == nil {
 		return 0, nil
 	}
+	// Widen the window to whole trading days; rows are bucketed in Go
+	// because the boundary need not be UTC midnight.
 	query := s.db.WithContext(ctx).Table("pnl_records AS r")
+	var sinceDate, untilDate *time.Time
 	if since != nil && !since.IsZero() {
-		query = query.Where("COALESCE(r.settled_at, r.created_at) >= ?", since.UTC())
+		d := cal.Date(*since)
+		sinceDate = &d
+		query = query.Where("COALESCE(r.settled_at, r.created_at) >= ?", cal.DateStart(d))
 	}
 	if until != nil && !until.IsZero() {
-		query = query.Where("COALESCE(r.settled_at, r.created_at) <= ?", until.UTC())
-	}
-	var rows []struct {
-		StrategyName   string
-		Date           time.Time
-		TradesCount    int
-		WinCount       int
-		LossCount      int
-		PnLUSD         float64
-		AvgEdgePct     float64
-		AvgSlippageBps float64
-		AvgHoldHours   float64
+		d := cal.Date(*until)
+		untilDate = &d
+		query = query.Where("COALESCE(r.settled_at, r.created_at) < ?", cal.DateStart(d.AddDate(0, 0, 1)))
+	}
+	var records []struct {
+		StrategyName string
+		At           sqlTime
+		Outcome      string
+		Realized     float64
+		ExpectedEdge float64
+		SlippageLoss float64
+		HoldHours    *float64
 	}
 	err := query.
 		Select(`
 			r.strategy_name AS strategy_name,
-			DATE(COALESCE(r.settled_at, r.created_at)) AS date,
-			COUNT(*) AS trades_count,
-			COALESCE(SUM(CASE WHEN r.outcome = 'win' THEN 1 ELSE 0 END),0) AS win_count,
-			COALESCE(SUM(CASE WHEN r.outcome = 'loss' THEN 1 ELSE 0 END),0) AS loss_count,
-			COALESCE(SUM(COALESCE(r.realized_pnl,0)),0) AS pnl_usd,
-			COALESCE(AVG(COALESCE(r.expected_edge,0)),0) AS avg_edge_pct,
-			COALESCE(AVG(COALESCE(r.slippage_loss,0))*10000,0) AS avg_slippage_bps,
-			COALESCE(AVG(EXTRACT(EPOCH FROM (p.executed_at - p.created_at))/3600.0),0) AS avg_hold_hours
+			COALESCE(r.settled_at, r.created_at) AS at,
+			COALESCE(r.outcome, '') AS outcome,
+			COALESCE(r.realized_pnl,0) AS realized,
+			COALESCE(r.expected_edge,0) AS expected_edge,
+			COALESCE(r.slippage_loss,0) AS slippage_loss,
+			` + s.hoursBetween("p.executed_at", "p.created_at") + ` AS hold_hours
 		`).
 		Joins("LEFT JOIN execution_plans AS p ON p.id = r.plan_id").
-		Group("r.strategy_name, DATE(COALESCE(r.settled_at, r.created_at))").
-		Order("r.strategy_name asc, DATE(COALESCE(r.settled_at, r.created_at)) asc").
-		Scan(&rows).Error
+		Order("COALESCE(r.settled_at, r.created_at) asc").
+		Scan(&records).Error
 	if err != nil {
 		return 0, err
 	}
-	if len(rows) == 0 {
-		return 0, nil
+
+	type dayKey struct {
+		name string
+		date time.Time
 	}
-	cumByStrategy := map[string]float64{}
-	peakByStrategy := map[string]float64{}
-	updated := 0
-	for _, r := range rows {
+	type dayAgg struct {
+		trades, wins, losses, holds int
+		pnl, edge, slippage, hold   float64
+	}
+	days := map[dayKey]*dayAgg{}
+	keys := []dayKey{}
+	for _, r := range records {
 		name := strings.TrimSpace(r.StrategyName)
-		if name == "" {
+		if name == "" || !r.At.Valid {
 			continue
 		}
-		cum := cumByStrategy[name] + r.PnLUSD
-		cumByStrategy[name] = cum
-		peak := peakByStrategy[name]
+		key := dayKey{name: name, date: cal.Date(r.At.Time)}
+		agg := days[key]
+		if agg == nil {
+			agg = &dayAgg{}
+			days[key] = agg
+			keys = append(keys, key)
+		}
+		agg.trades++
+		switch r.Outcome {
+		case "win":
+			agg.wins++
+		case "loss":
+			agg.losses++
+		}
+		agg.pnl += r.Realized
+		agg.edge += r.ExpectedEdge
+		agg.slippage += r.SlippageLoss
+		if r.HoldHours != nil {
+			agg.hold += *r.HoldHours
+			agg.holds++
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].date.Before(keys[j].date)
+	})
+
+	// Rows from a previous boundary would otherwise linger next to the
+	// rebuilt ones.
+	del := s.db.WithContext(ctx).Where("1 = 1")
+	if sinceDate != nil {
+		del = del.Where("date >= ?", *sinceDate)
+	}
+	if untilDate != nil {
+		del = del.Where("date <= ?", *untilDate)
+	}
+	if err := del.Delete(&models.StrategyDailyStats{}).Error; err != nil {
+		return 0, err
+	}
+
+	cumByStrategy := map[string]float64{}
+	peakByStrategy := map[string]float64{}
+	updated := 0
+	for _, key := range keys {
+		r := days[key]
+		cum := cumByStrategy[key.name] + r.pnl
+		cumByStrategy[key.name] = cum
+		peak := peakByStrategy[key.name]
 		if cum > peak {
 			peak = cum
-			peakByStrategy[name] = peak
+			peakByStrategy[key.name] = peak
 		}
 		maxDD := peak - cum
+		avgHold := 0.0
+		if r.holds > 0 {
+			avgHold = r.hold / float64(r.holds)
+		}
 		item := &models.StrategyDailyStats{
-			StrategyName:   name,
-			Date:           r.Date.UTC(),
-			TradesCount:    r.TradesCount,
-			WinCount:       r.WinCount,
-			LossCount:      r.LossCount,
-			PnLUSD:         decimal.NewFromFloat(r.PnLUSD),
-			AvgEdgePct:     decimal.NewFromFloat(r.AvgEdgePct),
-			AvgSlippageBps: decimal.NewFromFloat(r.AvgSlippageBps),
-			AvgHoldHours:   decimal.NewFromFloat(r.AvgHoldHours),
+			StrategyName:   key.name,
+			Date:           key.date,
+			TradesCount:    r.trades,
+			WinCount:       r.wins,
+			LossCount:      r.losses,
+			PnLUSD:         decimal.NewFromFloat(r.pnl),
+			AvgEdgePct:     decimal.NewFromFloat(r.edge / float64(r.trades)),
+			AvgSlippageBps: decimal.NewFromFloat(r.slippage / float64(r.trades) * 10000),
+			AvgHoldHours:   decimal.NewFromFloat(avgHold),
 			MaxDrawdownUSD: decimal.NewFromFloat(maxDD),
 			CumulativePnL:  decimal.NewFromFloat(cum),
 			UpdatedAt:      time.Now().UTC(),
@@ -1723,7 +4473,13 @@ func correlationOfMaps(a, b map[string]float64) float64 {
 	return num / math.Sqrt(dx*dy)
 }
 
-func calcRatios(rows []struct{ PnL float64 }) repository.RatiosResult {
+// pnlRow is one realized PnL. The column is named explicitly because default
+// GORM naming turns "PnL" into "pn_l".
+type pnlRow struct {
+	PnL float64 `gorm:"column:pnl"`
+}
+
+func calcRatios(rows []pnlRow) repository.RatiosResult {
 	rets := make([]float64, 0, len(rows))
 	win := 0
 	loss := 0
@@ -1838,6 +4594,8 @@ func (s *Store) UpsertMarketSettlementHistory(ctx context.Context, item *models.
 			"category",
 			"labels",
 			"initial_yes_price",
+			"initial_yes_price_source",
+			"initial_yes_price_at",
 			"final_yes_price",
 			"settled_at",
 		}),
@@ -1849,17 +4607,7 @@ func (s *Store) ListMarketSettlementHistoryByMarketIDs(ctx context.Context, mark
 		return nil, nil
 	}
 	marketIDs = cleanStrings(marketIDs)
-	if len(marketIDs) == 0 {
-		return nil, nil
-	}
-	var items []models.MarketSettlementHistory
-	if err := s.db.WithContext(ctx).
-		Model(&models.MarketSettlementHistory{}).
-		Where("market_id IN ?", marketIDs).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	return findInChunks[models.MarketSettlementHistory](s.db.WithContext(ctx).Model(&models.MarketSettlementHistory{}), "market_id", marketIDs)
 }
 
 func (s *Store) ListRecentMarketSettlementHistory(ctx context.Context, since time.Time, limit int) ([]models.MarketSettlementHistory, error) {
@@ -1924,6 +4672,39 @@ func (s *Store) ListLabelNoRateStats(ctx context.Context, labels []string) ([]re
 	return out, nil
 }
 
+func (s *Store) ListCalibrationSamples(ctx context.Context, since, until *time.Time, limit int) ([]repository.CalibrationSampleRow, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	// Fitting needs the whole sample, so this bypasses the usual 500 row cap.
+	if limit <= 0 || limit > 20000 {
+		limit = 20000
+	}
+	query := s.db.WithContext(ctx).
+		Table("market_settlement_history AS h").
+		Select(`
+			h.market_id AS market_id,
+			h.outcome AS outcome,
+			h.initial_yes_price AS price,
+			m.external_created_at AS opened_at,
+			h.settled_at AS settled_at
+		`).
+		Joins("LEFT JOIN catalog_markets AS m ON m.id = h.market_id").
+		Where("h.initial_yes_price IS NOT NULL").
+		Where("h.outcome IN ?", []string{"YES", "NO"})
+	if since != nil {
+		query = query.Where("h.settled_at >= ?", since.UTC())
+	}
+	if until != nil {
+		query = query.Where("h.settled_at < ?", until.UTC())
+	}
+	var rows []repository.CalibrationSampleRow
+	if err := query.Order("h.settled_at desc").Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 func (s *Store) UpsertMarketReview(ctx context.Context, item *models.MarketReview) error {
 	if s == nil || s.db == nil || item == nil {
 		return nil
@@ -2099,20 +4880,44 @@ func (s *Store) UpdateMarketReviewNotes(ctx context.Context, id uint64, notes st
 	}).Error
 }
 
-func (s *Store) AnalyticsOverview(ctx context.Context) (repository.AnalyticsOverview, error) {
+// pnlRecordsAsOf returns pnl_records as they were known at asOf: rows created
+// later are dropped and settlements recorded later are masked back to pending.
+// A nil asOf returns the live table.
+func (s *Store) pnlRecordsAsOf(ctx context.Context, asOf *time.Time) *gorm.DB {
+	db := s.db.WithContext(ctx)
+	if asOf == nil || asOf.IsZero() {
+		return db.Table("pnl_records")
+	}
+	t := asOf.UTC()
+	sub := s.db.WithContext(ctx).Table("pnl_records").
+		Select(`
+			id, plan_id, strategy_name, expected_edge, slippage_loss, failure_reason, notes, created_at,
+			CASE WHEN settled_at <= ? THEN settled_at END AS settled_at,
+			CASE WHEN settled_at <= ? THEN realized_pnl END AS realized_pnl,
+			CASE WHEN settled_at <= ? THEN realized_roi END AS realized_roi,
+			CASE
+				WHEN settled_at <= ? THEN outcome
+				WHEN settled_at IS NULL AND outcome NOT IN ('win','loss','partial') THEN outcome
+				ELSE 'pending'
+			END AS outcome
+		`, t, t, t, t).
+		Where("created_at <= ?", t)
+	return db.Table("(?) AS pnl_records", sub)
+}
+
+func (s *Store) AnalyticsOverview(ctx context.Context, asOf *time.Time, tenant string) (repository.AnalyticsOverview, error) {
 	if s == nil || s.db == nil {
 		return repository.AnalyticsOverview{}, nil
 	}
 	var row struct {
 		TotalPlans   int64
-		TotalPnLUSD  float64
+		TotalPnLUSD  float64 `gorm:"column:total_pnl_usd"`
 		AvgROI       float64
 		WinCount     int64
 		LossCount    int64
 		PendingCount int64
 	}
-	err := s.db.WithContext(ctx).
-		Table("pnl_records").
+	err := planTenantScope(s.pnlRecordsAsOf(ctx, asOf), "plan_id", tenant).
 		Select(`
 			COUNT(*) AS total_plans,
 			COALESCE(SUM(COALESCE(realized_pnl,0)),0) AS total_pnl_usd,
@@ -2135,13 +4940,12 @@ func (s *Store) AnalyticsOverview(ctx context.Context) (repository.AnalyticsOver
 	}, nil
 }
 
-func (s *Store) AnalyticsByStrategy(ctx context.Context) ([]repository.StrategyAnalyticsRow, error) {
+func (s *Store) AnalyticsByStrategy(ctx context.Context, tenant string) ([]repository.StrategyAnalyticsRow, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
 	var rows []repository.StrategyAnalyticsRow
-	err := s.db.WithContext(ctx).
-		Table("pnl_records").
+	err := planTenantScope(s.db.WithContext(ctx).Table("pnl_records"), "plan_id", tenant).
 		Select(`
 			strategy_name AS strategy_name,
 			COUNT(*) AS plans,
@@ -2157,6 +4961,32 @@ func (s *Store) AnalyticsByStrategy(ctx context.Context) ([]repository.StrategyA
 	return rows, nil
 }
 
+func (s *Store) SignalQualityStats(ctx context.Context, since *time.Time) ([]repository.SignalQualityRow, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).
+		Table("opportunities AS o").
+		Select(`
+			o.signal_type AS signal_type,
+			COUNT(DISTINCT o.id) AS opportunities,
+			COALESCE(SUM(CASE WHEN p.outcome IN ('win','loss','partial') THEN 1 ELSE 0 END),0) AS settled,
+			COALESCE(SUM(CASE WHEN p.outcome IN ('win','loss','partial') AND p.realized_pnl > 0 THEN 1 ELSE 0 END),0) AS profitable,
+			COALESCE(SUM(CASE WHEN p.outcome IN ('win','loss','partial') THEN COALESCE(p.realized_pnl,0) ELSE 0 END),0) AS realized_usd
+		`).
+		Joins("LEFT JOIN execution_plans AS e ON e.opportunity_id = o.id").
+		Joins("LEFT JOIN pnl_records AS p ON p.plan_id = e.id").
+		Where("o.signal_type IS NOT NULL AND o.signal_type <> ''")
+	if since != nil && !since.IsZero() {
+		query = query.Where("o.created_at >= ?", since.UTC())
+	}
+	var rows []repository.SignalQualityRow
+	if err := query.Group("o.signal_type").Order("o.signal_type asc").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 func (s *Store) AnalyticsStrategyOutcomes(ctx context.Context) ([]repository.StrategyOutcomeRow, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
@@ -2180,13 +5010,12 @@ func (s *Store) AnalyticsStrategyOutcomes(ctx context.Context) ([]repository.Str
 	return rows, nil
 }
 
-func (s *Store) AnalyticsFailures(ctx context.Context) ([]repository.FailureAnalyticsRow, error) {
+func (s *Store) AnalyticsFailures(ctx context.Context, tenant string) ([]repository.FailureAnalyticsRow, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
 	var rows []repository.FailureAnalyticsRow
-	err := s.db.WithContext(ctx).
-		Table("pnl_records").
+	err := planTenantScope(s.db.WithContext(ctx).Table("pnl_records"), "plan_id", tenant).
 		Select("COALESCE(failure_reason,'') AS failure_reason, COUNT(*) AS count").
 		Where("failure_reason IS NOT NULL AND failure_reason <> ''").
 		Group("failure_reason").
@@ -2198,6 +5027,220 @@ func (s *Store) AnalyticsFailures(ctx context.Context) ([]repository.FailureAnal
 	return rows, nil
 }
 
+// pnlBreakdownSelect aggregates settled pnl_records (alias p) per bucket.
+const pnlBreakdownSelect = `
+	COUNT(*) AS trades,
+	COALESCE(SUM(CASE WHEN p.outcome = 'win' THEN 1 ELSE 0 END),0) AS wins,
+	COALESCE(SUM(CASE WHEN p.outcome = 'loss' THEN 1 ELSE 0 END),0) AS losses,
+	COALESCE(SUM(COALESCE(p.realized_pnl,0)),0) AS realized_usd,
+	COALESCE(AVG(COALESCE(p.realized_roi,0)),0) AS avg_roi`
+
+// pnlBreakdownBase selects settled pnl_records joined to their plans (alias e).
+// The entry time is the plan's execution time, or its creation when it never
+// fully executed.
+func (s *Store) pnlBreakdownBase(ctx context.Context, params repository.PnLBreakdownParams) *gorm.DB {
+	query := s.db.WithContext(ctx).
+		Table("pnl_records AS p").
+		Joins("JOIN execution_plans AS e ON e.id = p.plan_id").
+		Where("p.outcome IN ?", []string{"win", "loss", "partial"})
+	if tenant := tenantParam(params.Tenant); tenant != "" {
+		query = query.Where("e.tenant = ?", tenant)
+	}
+	if params.Since != nil && !params.Since.IsZero() {
+		query = query.Where("p.settled_at >= ?", params.Since.UTC())
+	}
+	if params.Until != nil && !params.Until.IsZero() {
+		query = query.Where("p.settled_at <= ?", params.Until.UTC())
+	}
+	if params.StrategyName != nil && strings.TrimSpace(*params.StrategyName) != "" {
+		query = query.Where("p.strategy_name = ?", strings.TrimSpace(*params.StrategyName))
+	}
+	return query
+}
+
+func withWinRate(row repository.PnLBreakdownRow) repository.PnLBreakdownRow {
+	if row.Trades > 0 {
+		row.WinRate = float64(row.Wins) / float64(row.Trades)
+	}
+	return row
+}
+
+// PnLBreakdownByLabel counts a trade once under every label of its market.
+func (s *Store) PnLBreakdownByLabel(ctx context.Context, params repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.pnlBreakdownBase(ctx, params).
+		Select("ml.label AS bucket," + pnlBreakdownSelect).
+		Joins("JOIN opportunities AS o ON o.id = e.opportunity_id").
+		Joins("JOIN market_labels AS ml ON ml.market_id = o.primary_market_id").
+		Group("ml.label").
+		Order("realized_usd desc")
+	var rows []repository.PnLBreakdownRow
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i] = withWinRate(rows[i])
+	}
+	return rows, nil
+}
+
+func (s *Store) PnLBreakdownByEntryHour(ctx context.Context, params repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	width := params.BucketHours
+	if width <= 0 || width > 12 {
+		width = 4
+	}
+	start := fmt.Sprintf("((%s / %d) * %d)", s.utcHour("COALESCE(e.executed_at, e.created_at)"), width, width)
+	var rows []struct {
+		BucketStart int
+		repository.PnLBreakdownRow
+	}
+	err := s.pnlBreakdownBase(ctx, params).
+		Select(start + " AS bucket_start," + pnlBreakdownSelect).
+		Group(start).
+		Order("bucket_start asc").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]repository.PnLBreakdownRow, 0, len(rows))
+	for _, r := range rows {
+		row := withWinRate(r.PnLBreakdownRow)
+		row.Bucket = fmt.Sprintf("%02d-%02d", r.BucketStart, r.BucketStart+width)
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// holdDurationBuckets are upper bounds in hours; the last bucket is open-ended.
+var holdDurationBuckets = []struct {
+	Label    string
+	MaxHours float64
+}{
+	{"<1h", 1},
+	{"1h-6h", 6},
+	{"6h-24h", 24},
+	{"1d-3d", 72},
+	{"3d-7d", 168},
+}
+
+func (s *Store) PnLBreakdownByHoldDuration(ctx context.Context, params repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	hours := s.hoursBetween("p.settled_at", "COALESCE(e.executed_at, e.created_at)")
+	var bucket strings.Builder
+	bucket.WriteString("CASE")
+	for i, b := range holdDurationBuckets {
+		fmt.Fprintf(&bucket, " WHEN %s < %g THEN %d", hours, b.MaxHours, i)
+	}
+	fmt.Fprintf(&bucket, " ELSE %d END", len(holdDurationBuckets))
+	var rows []struct {
+		BucketIndex int
+		repository.PnLBreakdownRow
+	}
+	err := s.pnlBreakdownBase(ctx, params).
+		Select(bucket.String() + " AS bucket_index," + pnlBreakdownSelect).
+		Where("p.settled_at IS NOT NULL").
+		Group(bucket.String()).
+		Order("bucket_index asc").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]repository.PnLBreakdownRow, 0, len(rows))
+	for _, r := range rows {
+		row := withWinRate(r.PnLBreakdownRow)
+		row.Bucket = ">=7d"
+		if r.BucketIndex < len(holdDurationBuckets) {
+			row.Bucket = holdDurationBuckets[r.BucketIndex].Label
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// bucketIndex returns a CASE expression numbering the bucket of column among
+// ascending bounds: 0 below the first bound, len(bounds) at or above the
+// last.
+func bucketIndex(column string, bounds []float64) string {
+	if len(bounds) == 0 {
+		return "0"
+	}
+	var b strings.Builder
+	b.WriteString("CASE")
+	for i, bound := range bounds {
+		fmt.Fprintf(&b, " WHEN %s < %g THEN %d", column, bound, i)
+	}
+	fmt.Fprintf(&b, " ELSE %d END", len(bounds))
+	return b.String()
+}
+
+// EdgeRealizationHeatmap counts a trade once under every label of its
+// market, and once as unlabeled when its market has none. Trades without a
+// realized ROI are left out.
+func (s *Store) EdgeRealizationHeatmap(ctx context.Context, params repository.EdgeHeatmapParams) ([]repository.EdgeHeatmapCell, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	label := "COALESCE(ml.label, 'unlabeled')"
+	edge := bucketIndex("p.expected_edge", params.EdgeBounds)
+	roi := bucketIndex("p.realized_roi", params.ROIBounds)
+	query := s.pnlBreakdownBase(ctx, repository.PnLBreakdownParams{Since: params.Since, Until: params.Until, Tenant: params.Tenant, StrategyName: params.StrategyName}).
+		Joins("LEFT JOIN opportunities AS o ON o.id = e.opportunity_id").
+		Joins("LEFT JOIN market_labels AS ml ON ml.market_id = o.primary_market_id").
+		Where("p.realized_roi IS NOT NULL")
+	if params.Label != nil && strings.TrimSpace(*params.Label) != "" {
+		query = query.Where(label+" = ?", strings.TrimSpace(*params.Label))
+	}
+	var rows []repository.EdgeHeatmapCell
+	err := query.
+		Select(fmt.Sprintf(`p.strategy_name AS strategy_name,
+			%s AS label,
+			%s AS edge_bucket,
+			%s AS roi_bucket,
+			COUNT(*) AS trades,
+			COALESCE(SUM(CASE WHEN p.outcome = 'win' THEN 1 ELSE 0 END),0) AS wins,
+			COALESCE(SUM(COALESCE(p.realized_pnl,0)),0) AS realized_usd,
+			COALESCE(AVG(p.expected_edge),0) AS avg_expected_edge,
+			COALESCE(AVG(p.realized_roi),0) AS avg_roi`, label, edge, roi)).
+		Group("p.strategy_name, " + label + ", " + edge + ", " + roi).
+		Order("strategy_name asc, label asc, edge_bucket asc, roi_bucket asc").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (s *Store) ListSettledTrades(ctx context.Context, params repository.SettledTradeParams) ([]repository.SettledTradeRow, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.pnlBreakdownBase(ctx, repository.PnLBreakdownParams{Since: params.Since, Until: params.Until, StrategyName: params.StrategyName}).
+		Joins("LEFT JOIN opportunities AS o ON o.id = e.opportunity_id").
+		Joins("LEFT JOIN catalog_markets AS m ON m.id = o.primary_market_id").
+		Where("p.settled_at IS NOT NULL").
+		Select(`p.plan_id AS plan_id,
+			p.strategy_name AS strategy_name,
+			COALESCE(o.primary_market_id, '') AS market_id,
+			m.volume AS market_volume,
+			e.planned_size_usd AS size_usd,
+			p.expected_edge AS expected_edge,
+			COALESCE(p.realized_pnl,0) AS realized_usd,
+			COALESCE(p.realized_roi,0) AS realized_roi,
+			p.outcome AS outcome,
+			p.settled_at AS settled_at`).
+		Order("p.settled_at asc, p.plan_id asc")
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
+	var rows []repository.SettledTradeRow
+	err := query.Scan(&rows).Error
+	return rows, err
+}
+
 func (s *Store) ListEvents(ctx context.Context, params repository.ListEventsParams) ([]models.Event, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
@@ -2213,7 +5256,7 @@ func (s *Store) ListEvents(ctx context.Context, params repository.ListEventsPara
 		query = query.Where("slug = ?", *params.Slug)
 	}
 	if params.Title != nil && *params.Title != "" {
-		query = query.Where("title ILIKE ?", "%"+*params.Title+"%")
+		query = query.Where(s.ilike("title"), "%"+*params.Title+"%")
 	}
 	query = applyOrder(query, params.OrderBy, params.Asc, "external_updated_at")
 	limit := normalizeLimit(params.Limit, 100)
@@ -2240,7 +5283,7 @@ func (s *Store) CountEvents(ctx context.Context, params repository.ListEventsPar
 		query = query.Where("slug = ?", *params.Slug)
 	}
 	if params.Title != nil && *params.Title != "" {
-		query = query.Where("title ILIKE ?", "%"+*params.Title+"%")
+		query = query.Where(s.ilike("title"), "%"+*params.Title+"%")
 	}
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -2267,7 +5310,7 @@ func (s *Store) ListMarkets(ctx context.Context, params repository.ListMarketsPa
 		query = query.Where("slug = ?", *params.Slug)
 	}
 	if params.Question != nil && *params.Question != "" {
-		query = query.Where("question ILIKE ?", "%"+*params.Question+"%")
+		query = query.Where(s.ilike("question"), "%"+*params.Question+"%")
 	}
 	query = applyOrder(query, params.OrderBy, params.Asc, "external_updated_at")
 	limit := normalizeLimit(params.Limit, 100)
@@ -2297,7 +5340,7 @@ func (s *Store) CountMarkets(ctx context.Context, params repository.ListMarketsP
 		query = query.Where("slug = ?", *params.Slug)
 	}
 	if params.Question != nil && *params.Question != "" {
-		query = query.Where("question ILIKE ?", "%"+*params.Question+"%")
+		query = query.Where(s.ilike("question"), "%"+*params.Question+"%")
 	}
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -2325,21 +5368,86 @@ func (s *Store) ListMarketIDsForStream(ctx context.Context, limit int) ([]string
 	return ids, nil
 }
 
+func (s *Store) ListMarketBaselines(ctx context.Context, marketIDs []string) ([]models.MarketBaseline, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	marketIDs = cleanStrings(marketIDs)
+	return findInChunks[models.MarketBaseline](s.db.WithContext(ctx).Model(&models.MarketBaseline{}), "market_id", marketIDs)
+}
+
+func (s *Store) UpsertMarketBaselines(ctx context.Context, items []models.MarketBaseline) error {
+	if s == nil || s.db == nil || len(items) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "market_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"liquidity", "volume_rate", "last_volume", "samples", "last_sampled_at", "liquidity_collapsed", "volume_spiking", "updated_at"}),
+	}).Create(&items).Error
+}
+
+func (s *Store) ListMarketsForOnchainCheck(ctx context.Context, limit int) ([]models.Market, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	limit = normalizeLimit(limit, 50)
+	var items []models.Market
+	err := s.db.WithContext(ctx).
+		Model(&models.Market{}).
+		Where("closed = ?", false).
+		Where("condition_id <> ''").
+		Where("onchain_resolved_at IS NULL").
+		Order("onchain_checked_at IS NOT NULL, onchain_checked_at asc, id asc").
+		Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) SaveOnchainCondition(ctx context.Context, marketID string, state repository.OnchainConditionState) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	marketID = strings.TrimSpace(marketID)
+	if marketID == "" {
+		return nil
+	}
+	checkedAt := state.CheckedAt.UTC()
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]any{
+			"onchain_outcome_slots": state.OutcomeSlots,
+			"onchain_checked_at":    checkedAt,
+		}
+		if len(state.Payouts) > 0 {
+			raw, err := json.Marshal(state.Payouts)
+			if err != nil {
+				return err
+			}
+			updates["onchain_payouts"] = datatypes.JSON(raw)
+			updates["onchain_resolved_at"] = gorm.Expr("COALESCE(onchain_resolved_at, ?)", checkedAt)
+		}
+		if err := tx.Model(&models.Market{}).Where("id = ?", marketID).Updates(updates).Error; err != nil {
+			return err
+		}
+		for i, payout := range state.Payouts {
+			if err := tx.Model(&models.Token{}).
+				Where("market_id = ? AND outcome_index = ?", marketID, i).
+				Update("onchain_payout", payout).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (s *Store) ListTokensByMarketIDs(ctx context.Context, marketIDs []string) ([]models.Token, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if len(marketIDs) == 0 {
-		return nil, nil
-	}
-	var items []models.Token
-	if err := s.db.WithContext(ctx).
-		Model(&models.Token{}).
-		Where("market_id IN ?", marketIDs).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	marketIDs = cleanStrings(marketIDs)
+	return findInChunks[models.Token](s.db.WithContext(ctx).Model(&models.Token{}), "market_id", marketIDs)
 }
 
 func (s *Store) ListTokensByIDs(ctx context.Context, tokenIDs []string) ([]models.Token, error) {
@@ -2347,68 +5455,47 @@ func (s *Store) ListTokensByIDs(ctx context.Context, tokenIDs []string) ([]model
 		return nil, nil
 	}
 	tokenIDs = cleanStrings(tokenIDs)
-	if len(tokenIDs) == 0 {
-		return nil, nil
-	}
-	var items []models.Token
-	if err := s.db.WithContext(ctx).
-		Model(&models.Token{}).
-		Where("id IN ?", tokenIDs).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	return findInChunks[models.Token](s.db.WithContext(ctx).Model(&models.Token{}), "id", tokenIDs)
 }
 
 func (s *Store) ListMarketDataHealthByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.MarketDataHealth, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if len(tokenIDs) == 0 {
-		return nil, nil
-	}
-	var items []models.MarketDataHealth
-	if err := s.db.WithContext(ctx).
-		Model(&models.MarketDataHealth{}).
-		Where("token_id IN ?", tokenIDs).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	tokenIDs = cleanStrings(tokenIDs)
+	return findInChunks[models.MarketDataHealth](s.db.WithContext(ctx).Model(&models.MarketDataHealth{}), "token_id", tokenIDs)
 }
 
 func (s *Store) ListOrderbookLatestByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.OrderbookLatest, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if len(tokenIDs) == 0 {
+	tokenIDs = cleanStrings(tokenIDs)
+	return findInChunks[models.OrderbookLatest](s.db.WithContext(ctx).Model(&models.OrderbookLatest{}), "token_id", tokenIDs)
+}
+
+func (s *Store) ListCatalogBooks(ctx context.Context, tokenIDs []string) ([]repository.CatalogBook, error) {
+	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	var items []models.OrderbookLatest
-	if err := s.db.WithContext(ctx).
-		Model(&models.OrderbookLatest{}).
-		Where("token_id IN ?", tokenIDs).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	tokenIDs = cleanStrings(tokenIDs)
+	query := s.db.WithContext(ctx).
+		Table("catalog_tokens AS t").
+		Select(`t.id AS token_id, t.market_id, t.outcome, t.outcome_index,
+			m.question, m.condition_id, m.tick_size, m.neg_risk, m.active, m.closed,
+			b.best_bid, b.best_ask, b.mid, b.bids_json, b.asks_json, b.snapshot_ts, b.source AS book_source,
+			m.catalog_epoch AS market_epoch, t.catalog_epoch AS token_epoch, b.catalog_epoch AS book_epoch`).
+		Joins("JOIN catalog_markets AS m ON m.id = t.market_id").
+		Joins("LEFT JOIN orderbook_latest AS b ON b.token_id = t.id")
+	return findInChunks[repository.CatalogBook](query, "t.id", tokenIDs)
 }
 
 func (s *Store) ListLastTradePricesByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.LastTradePrice, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if len(tokenIDs) == 0 {
-		return nil, nil
-	}
-	var items []models.LastTradePrice
-	if err := s.db.WithContext(ctx).
-		Model(&models.LastTradePrice{}).
-		Where("token_id IN ?", tokenIDs).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	tokenIDs = cleanStrings(tokenIDs)
+	return findInChunks[models.LastTradePrice](s.db.WithContext(ctx).Model(&models.LastTradePrice{}), "token_id", tokenIDs)
 }
 
 func (s *Store) ListMarketAggregates(ctx context.Context, limit int) ([]repository.EventAggregate, error) {
@@ -2421,7 +5508,7 @@ func (s *Store) ListMarketAggregates(ctx context.Context, limit int) ([]reposito
 		MarketCount   int
 		SumLiquidity  decimal.Decimal
 		SumVolume     decimal.Decimal
-		LatestUpdated *time.Time
+		LatestUpdated sqlTime
 	}
 	if err := s.db.WithContext(ctx).
 		Model(&models.Market{}).
@@ -2441,7 +5528,7 @@ func (s *Store) ListMarketAggregates(ctx context.Context, limit int) ([]reposito
 			MarketCount:   row.MarketCount,
 			SumLiquidity:  row.SumLiquidity,
 			SumVolume:     row.SumVolume,
-			LatestUpdated: row.LatestUpdated,
+			LatestUpdated: row.LatestUpdated.Ptr(),
 		})
 	}
 	return out, nil
@@ -2451,51 +5538,24 @@ func (s *Store) ListEventsByIDs(ctx context.Context, ids []string) ([]models.Eve
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if len(ids) == 0 {
-		return nil, nil
-	}
-	var items []models.Event
-	if err := s.db.WithContext(ctx).
-		Model(&models.Event{}).
-		Where("id IN ?", ids).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	ids = cleanStrings(ids)
+	return findInChunks[models.Event](s.db.WithContext(ctx).Model(&models.Event{}), "id", ids)
 }
 
 func (s *Store) FindMarketsByConditionIDs(ctx context.Context, conditionIDs []string) ([]models.Market, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if len(conditionIDs) == 0 {
-		return nil, nil
-	}
-	var items []models.Market
-	if err := s.db.WithContext(ctx).
-		Model(&models.Market{}).
-		Where("condition_id IN ?", conditionIDs).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	conditionIDs = cleanStrings(conditionIDs)
+	return findInChunks[models.Market](s.db.WithContext(ctx).Model(&models.Market{}), "condition_id", conditionIDs)
 }
 
 func (s *Store) FindMarketsBySlugs(ctx context.Context, slugs []string) ([]models.Market, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if len(slugs) == 0 {
-		return nil, nil
-	}
-	var items []models.Market
-	if err := s.db.WithContext(ctx).
-		Model(&models.Market{}).
-		Where("slug IN ?", slugs).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	slugs = cleanStrings(slugs)
+	return findInChunks[models.Market](s.db.WithContext(ctx).Model(&models.Market{}), "slug", slugs)
 }
 
 func (s *Store) GetMarketBySlug(ctx context.Context, slug string) (*models.Market, error) {
@@ -2561,34 +5621,16 @@ func (s *Store) ListMarketsByEventIDs(ctx context.Context, eventIDs []string) ([
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if len(eventIDs) == 0 {
-		return nil, nil
-	}
-	var items []models.Market
-	if err := s.db.WithContext(ctx).
-		Model(&models.Market{}).
-		Where("event_id IN ?", eventIDs).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	eventIDs = cleanStrings(eventIDs)
+	return findInChunks[models.Market](s.db.WithContext(ctx).Model(&models.Market{}), "event_id", eventIDs)
 }
 
 func (s *Store) ListMarketsByIDs(ctx context.Context, marketIDs []string) ([]models.Market, error) {
 	if s == nil || s.db == nil {
 		return nil, nil
 	}
-	if len(marketIDs) == 0 {
-		return nil, nil
-	}
-	var items []models.Market
-	if err := s.db.WithContext(ctx).
-		Model(&models.Market{}).
-		Where("id IN ?", marketIDs).
-		Find(&items).Error; err != nil {
-		return nil, err
-	}
-	return items, nil
+	marketIDs = cleanStrings(marketIDs)
+	return findInChunks[models.Market](s.db.WithContext(ctx).Model(&models.Market{}), "id", marketIDs)
 }
 
 func (s *Store) ListTokens(ctx context.Context, params repository.ListTokensParams) ([]models.Token, error) {
@@ -2683,6 +5725,7 @@ func (s *Store) UpsertMarketsTx(ctx context.Context, tx *gorm.DB, items []models
 			"external_updated_at",
 			"last_seen_at",
 			"raw_json",
+			"catalog_epoch",
 		}),
 	}), items, 200)
 }
@@ -2697,10 +5740,12 @@ func (s *Store) UpsertTokensTx(ctx context.Context, tx *gorm.DB, items []models.
 			"market_id",
 			"outcome",
 			"side",
+			"outcome_index",
 			"external_created_at",
 			"external_updated_at",
 			"last_seen_at",
 			"raw_json",
+			"catalog_epoch",
 		}),
 	}), items, 300)
 }
@@ -2726,85 +5771,359 @@ func (s *Store) UpsertTagsTx(ctx context.Context, tx *gorm.DB, items []models.Ta
 	if len(items) == 0 {
 		return nil
 	}
-	return createInBatches(tx.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "id"}},
-		DoUpdates: clause.AssignmentColumns([]string{
-			"label",
-			"slug",
-			"external_updated_at",
-			"last_seen_at",
-			"raw_json",
-		}),
-	}), items, 200)
+	return createInBatches(tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"label",
+			"slug",
+			"external_updated_at",
+			"last_seen_at",
+			"raw_json",
+		}),
+	}), items, 200)
+}
+
+func (s *Store) UpsertEventTagsTx(ctx context.Context, tx *gorm.DB, items []models.EventTag) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return createInBatches(tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "tag_id"}},
+		DoNothing: true,
+	}), items, 500)
+}
+
+func (s *Store) UpsertOrderbookLatest(ctx context.Context, item *models.OrderbookLatest) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "token_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"snapshot_ts",
+			"bids_json",
+			"asks_json",
+			"best_bid",
+			"best_ask",
+			"mid",
+			"source",
+			"data_age_seconds",
+			"updated_at",
+			"catalog_epoch",
+		}),
+	}).Create(item).Error
+}
+
+func (s *Store) UpsertMarketDataHealth(ctx context.Context, item *models.MarketDataHealth) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "token_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"ws_connected",
+			"last_ws_ts",
+			"last_rest_ts",
+			"data_age_seconds",
+			"stale",
+			"needs_resync",
+			"last_resync_ts",
+			"spread",
+			"spread_bps",
+			"price_jump_bps",
+			"last_book_change_ts",
+			"reason",
+			"updated_at",
+		}),
+	}).Create(item).Error
+}
+
+func (s *Store) UpsertLastTradePrice(ctx context.Context, item *models.LastTradePrice) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "token_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"price",
+			"trade_ts",
+			"source",
+			"updated_at",
+		}),
+	}).Create(item).Error
+}
+
+// UpsertPriceCandles merges candles into existing minutes: high/low widen,
+// open/close follow the earliest/latest point.
+func (s *Store) UpsertPriceCandles(ctx context.Context, items []models.PriceCandle) error {
+	if s == nil || s.db == nil || len(items) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			var existing models.PriceCandle
+			err := tx.Where("token_id = ? AND bucket_start = ?", item.TokenID, item.BucketStart.UTC()).Take(&existing).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				item.BucketStart = item.BucketStart.UTC()
+				if err := tx.Create(&item).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if item.OpenTS.Before(existing.OpenTS) {
+				existing.Open = item.Open
+				existing.OpenTS = item.OpenTS
+			}
+			if !item.CloseTS.Before(existing.CloseTS) {
+				existing.Close = item.Close
+				existing.CloseTS = item.CloseTS
+			}
+			if item.High > existing.High {
+				existing.High = item.High
+			}
+			if item.Low < existing.Low {
+				existing.Low = item.Low
+			}
+			existing.Points += item.Points
+			if existing.Source != item.Source {
+				existing.Source = "mixed"
+			}
+			if err := tx.Save(&existing).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) ListPriceCandles(ctx context.Context, tokenID string, since, until time.Time) ([]models.PriceCandle, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var items []models.PriceCandle
+	err := s.db.WithContext(ctx).
+		Where("token_id = ? AND bucket_start >= ? AND bucket_start < ?", strings.TrimSpace(tokenID), since.UTC(), until.UTC()).
+		Order("bucket_start asc").
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) InsertTrade(ctx context.Context, item *models.Trade) error {
+	if s == nil || s.db == nil || item == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Create(item).Error
+}
+
+func (s *Store) ListTrades(ctx context.Context, params repository.ListTradesParams) ([]models.Trade, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := applyTradeFilters(s.db.WithContext(ctx).Model(&models.Trade{}), params)
+	var items []models.Trade
+	err := query.Order("trade_ts desc").Order("id desc").
+		Limit(normalizeLimit(params.Limit, 100)).
+		Offset(normalizeOffset(params.Offset)).
+		Find(&items).Error
+	return items, err
+}
+
+func (s *Store) CountTrades(ctx context.Context, params repository.ListTradesParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var total int64
+	err := applyTradeFilters(s.db.WithContext(ctx).Model(&models.Trade{}), params).Count(&total).Error
+	return total, err
+}
+
+func applyTradeFilters(query *gorm.DB, params repository.ListTradesParams) *gorm.DB {
+	if len(params.TokenIDs) > 0 {
+		query = query.Where("token_id IN ?", params.TokenIDs)
+	}
+	if params.Side != nil && strings.TrimSpace(*params.Side) != "" {
+		query = query.Where("side = ?", strings.ToUpper(strings.TrimSpace(*params.Side)))
+	}
+	if params.Since != nil {
+		query = query.Where("trade_ts >= ?", params.Since.UTC())
+	}
+	if params.Until != nil {
+		query = query.Where("trade_ts < ?", params.Until.UTC())
+	}
+	return query
+}
+
+// TradeVolumeProfile aggregates in Go: rounding prices to a bucket is not
+// portable between Postgres numeric and SQLite.
+func (s *Store) TradeVolumeProfile(ctx context.Context, tokenID string, since time.Time, bucket float64) (repository.VolumeProfile, error) {
+	if bucket <= 0 {
+		bucket = 0.01
+	}
+	out := repository.VolumeProfile{TokenID: strings.TrimSpace(tokenID), Since: since.UTC(), Bucket: bucket, Levels: []repository.VolumeLevel{}}
+	if s == nil || s.db == nil {
+		return out, nil
+	}
+	var rows []struct {
+		Price float64
+		Size  float64
+		Side  string
+	}
+	err := s.db.WithContext(ctx).Model(&models.Trade{}).
+		Select("price, size, side").
+		Where("token_id = ? AND trade_ts >= ?", out.TokenID, out.Since).
+		Scan(&rows).Error
+	if err != nil {
+		return out, err
+	}
+	levels := map[int64]*repository.VolumeLevel{}
+	notional := 0.0
+	for _, r := range rows {
+		if r.Price <= 0 || r.Size <= 0 {
+			continue
+		}
+		idx := int64(math.Floor(r.Price/bucket + 1e-9))
+		lvl, ok := levels[idx]
+		if !ok {
+			lvl = &repository.VolumeLevel{Price: math.Round(float64(idx)*bucket*1e6) / 1e6}
+			levels[idx] = lvl
+		}
+		lvl.Trades++
+		lvl.Volume += r.Size
+		out.Trades++
+		out.Volume += r.Size
+		notional += r.Price * r.Size
+		switch strings.ToUpper(r.Side) {
+		case "BUY":
+			lvl.BuyVolume += r.Size
+			out.BuyVolume += r.Size
+		case "SELL":
+			lvl.SellVolume += r.Size
+			out.SellVolume += r.Size
+		}
+	}
+	if out.Volume > 0 {
+		out.VWAP = notional / out.Volume
+	}
+	best := -1.0
+	for _, lvl := range levels {
+		out.Levels = append(out.Levels, *lvl)
+		if lvl.Volume > best || (lvl.Volume == best && lvl.Price < out.POC) {
+			best = lvl.Volume
+			out.POC = lvl.Price
+		}
+	}
+	sort.Slice(out.Levels, func(i, j int) bool { return out.Levels[i].Price < out.Levels[j].Price })
+	return out, nil
+}
+
+func (s *Store) PruneTrades(ctx context.Context, before time.Time, maxPerToken int) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var total int64
+	if !before.IsZero() {
+		res := s.db.WithContext(ctx).Where("trade_ts < ?", before.UTC()).Delete(&models.Trade{})
+		if res.Error != nil {
+			return 0, res.Error
+		}
+		total += res.RowsAffected
+	}
+	if maxPerToken > 0 {
+		res := s.db.WithContext(ctx).Exec(`DELETE FROM trades WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY token_id ORDER BY trade_ts DESC, id DESC) AS rn FROM trades
+			) ranked WHERE rn > ?
+		)`, maxPerToken)
+		if res.Error != nil {
+			return total, res.Error
+		}
+		total += res.RowsAffected
+	}
+	return total, nil
+}
+
+func (s *Store) ListRawWSEventTimes(ctx context.Context, tokenID string, since, until time.Time) ([]time.Time, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var out []time.Time
+	err := s.db.WithContext(ctx).Model(&models.RawWSEvent{}).
+		Where("token_id = ? AND received_at >= ? AND received_at < ?", strings.TrimSpace(tokenID), since.UTC(), until.UTC()).
+		Order("received_at asc").
+		Pluck("received_at", &out).Error
+	return out, err
 }
 
-func (s *Store) UpsertEventTagsTx(ctx context.Context, tx *gorm.DB, items []models.EventTag) error {
-	if len(items) == 0 {
-		return nil
+func (s *Store) ListRawWSEventTokenIDs(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
 	}
-	return createInBatches(tx.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "event_id"}, {Name: "tag_id"}},
-		DoNothing: true,
-	}), items, 500)
+	limit = normalizeLimit(limit, 200)
+	var out []string
+	err := s.db.WithContext(ctx).Model(&models.RawWSEvent{}).
+		Where("token_id IS NOT NULL AND token_id <> '' AND received_at >= ?", since.UTC()).
+		Distinct("token_id").
+		Order("token_id asc").
+		Limit(limit).
+		Pluck("token_id", &out).Error
+	return out, err
 }
 
-func (s *Store) UpsertOrderbookLatest(ctx context.Context, item *models.OrderbookLatest) error {
-	if s == nil || s.db == nil || item == nil {
-		return nil
+func (s *Store) GetEarliestPriceCandle(ctx context.Context, tokenID string) (*models.PriceCandle, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
 	}
-	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "token_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{
-			"snapshot_ts",
-			"bids_json",
-			"asks_json",
-			"best_bid",
-			"best_ask",
-			"mid",
-			"source",
-			"data_age_seconds",
-			"updated_at",
-		}),
-	}).Create(item).Error
+	var item models.PriceCandle
+	err := s.db.WithContext(ctx).
+		Where("token_id = ?", strings.TrimSpace(tokenID)).
+		Order("bucket_start asc").
+		First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
 }
 
-func (s *Store) UpsertMarketDataHealth(ctx context.Context, item *models.MarketDataHealth) error {
-	if s == nil || s.db == nil || item == nil {
-		return nil
+func (s *Store) GetEarliestRawWSEvent(ctx context.Context, tokenID string, eventType string) (*models.RawWSEvent, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
 	}
-	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "token_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{
-			"ws_connected",
-			"last_ws_ts",
-			"last_rest_ts",
-			"data_age_seconds",
-			"stale",
-			"needs_resync",
-			"last_resync_ts",
-			"spread",
-			"spread_bps",
-			"price_jump_bps",
-			"last_book_change_ts",
-			"reason",
-			"updated_at",
-		}),
-	}).Create(item).Error
+	var item models.RawWSEvent
+	err := s.db.WithContext(ctx).
+		Where("token_id = ? AND event_type = ?", strings.TrimSpace(tokenID), strings.TrimSpace(eventType)).
+		Order("received_at asc").
+		First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
 }
 
-func (s *Store) UpsertLastTradePrice(ctx context.Context, item *models.LastTradePrice) error {
-	if s == nil || s.db == nil || item == nil {
-		return nil
+func (s *Store) GetEarliestRawRESTSnapshot(ctx context.Context, tokenID string, snapshotType string) (*models.RawRESTSnapshot, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
 	}
-	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "token_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{
-			"price",
-			"trade_ts",
-			"source",
-			"updated_at",
-		}),
-	}).Create(item).Error
+	var item models.RawRESTSnapshot
+	err := s.db.WithContext(ctx).
+		Where("token_id = ? AND snapshot_type = ?", strings.TrimSpace(tokenID), strings.TrimSpace(snapshotType)).
+		Order("fetched_at asc").
+		First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
 }
 
 func (s *Store) InsertRawWSEvent(ctx context.Context, item *models.RawWSEvent) error {
@@ -2864,6 +6183,278 @@ func (s *Store) ListSyncStates(ctx context.Context) ([]models.SyncState, error)
 	return states, nil
 }
 
+// --- Local write audit ------------------------------------------------------
+
+// auditChainLockKey serializes appends across processes sharing the database.
+const auditChainLockKey = 7_420_017
+
+// AppendAuditRecord locks the chain, hands the current tail to seal so the
+// caller can set Seq/PrevHash/Hash, and inserts the record.
+func (s *Store) AppendAuditRecord(ctx context.Context, item *models.AuditRecord, seal func(prev *models.AuditRecord, item *models.AuditRecord)) error {
+	if s == nil || s.db == nil || item == nil || seal == nil {
+		return nil
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// SQLite already serializes writers on its single connection.
+		if !isSQLite(tx) {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockKey).Error; err != nil {
+				return err
+			}
+		}
+		var prev models.AuditRecord
+		err := tx.Model(&models.AuditRecord{}).Order("seq desc").Limit(1).Take(&prev).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			seal(nil, item)
+		case err != nil:
+			return err
+		default:
+			seal(&prev, item)
+		}
+		return tx.Create(item).Error
+	})
+}
+
+func (s *Store) ListAuditRecords(ctx context.Context, params repository.ListAuditRecordsParams) ([]models.AuditRecord, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	limit := params.Limit
+	if limit <= 0 || limit > 5000 {
+		limit = 1000
+	}
+	query := s.db.WithContext(ctx).Model(&models.AuditRecord{}).Where("seq > ?", params.AfterSeq)
+	if params.Since != nil {
+		query = query.Where("created_at >= ?", params.Since.UTC())
+	}
+	if params.Until != nil {
+		query = query.Where("created_at < ?", params.Until.UTC())
+	}
+	var items []models.AuditRecord
+	if err := query.Order("seq asc").Limit(limit).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// --- Catalog data-quality quarantine ----------------------------------------
+
+func (s *Store) UpsertCatalogQuarantineTx(ctx context.Context, tx *gorm.DB, items []models.CatalogQuarantine) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return createInBatches(tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}, {Name: "rule"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"reason":       gorm.Expr("excluded.reason"),
+			"scope":        gorm.Expr("excluded.scope"),
+			"status":       gorm.Expr("excluded.status"),
+			"payload":      gorm.Expr("excluded.payload"),
+			"hits":         gorm.Expr("catalog_quarantine.hits + 1"),
+			"last_seen_at": gorm.Expr("excluded.last_seen_at"),
+			"released_at":  nil,
+			"updated_at":   gorm.Expr("excluded.updated_at"),
+		}),
+	}), items, 200)
+}
+
+func (s *Store) ReleaseCatalogQuarantineTx(ctx context.Context, tx *gorm.DB, entityType string, entityIDs []string, at time.Time) (int64, error) {
+	entityType = strings.TrimSpace(entityType)
+	entityIDs = cleanStrings(entityIDs)
+	if entityType == "" || len(entityIDs) == 0 {
+		return 0, nil
+	}
+	res := tx.WithContext(ctx).
+		Model(&models.CatalogQuarantine{}).
+		Where("entity_type = ?", entityType).
+		Where("entity_id IN ?", entityIDs).
+		Where("status = ?", "quarantined").
+		Updates(map[string]any{"status": "released", "released_at": at, "updated_at": at})
+	return res.RowsAffected, res.Error
+}
+
+func (s *Store) ReleaseCatalogQuarantineByIDsTx(ctx context.Context, tx *gorm.DB, ids []uint64, at time.Time) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	res := tx.WithContext(ctx).
+		Model(&models.CatalogQuarantine{}).
+		Where("id IN ?", ids).
+		Where("status = ?", "quarantined").
+		Updates(map[string]any{"status": "released", "released_at": at, "updated_at": at})
+	return res.RowsAffected, res.Error
+}
+
+func (s *Store) ListCatalogQuarantine(ctx context.Context, params repository.ListCatalogQuarantineParams) ([]models.CatalogQuarantine, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := applyCatalogQuarantineFilters(s.db.WithContext(ctx).Model(&models.CatalogQuarantine{}), params)
+	query = applyOrder(query, params.OrderBy, params.Asc, "last_seen_at")
+	limit := normalizeLimit(params.Limit, 100)
+	offset := normalizeOffset(params.Offset)
+	var items []models.CatalogQuarantine
+	if err := query.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountCatalogQuarantine(ctx context.Context, params repository.ListCatalogQuarantineParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	query := applyCatalogQuarantineFilters(s.db.WithContext(ctx).Model(&models.CatalogQuarantine{}), params)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (s *Store) CatalogQuarantineMetrics(ctx context.Context) ([]repository.CatalogQuarantineMetricRow, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	var rows []struct {
+		Rule       string
+		Status     string
+		Count      int64
+		Hits       int64
+		LastSeenAt sqlTime
+	}
+	if err := s.db.WithContext(ctx).
+		Model(&models.CatalogQuarantine{}).
+		Select("rule, status, COUNT(*) AS count, COALESCE(SUM(hits), 0) AS hits, MAX(last_seen_at) AS last_seen_at").
+		Group("rule, status").
+		Order("rule asc, status asc").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]repository.CatalogQuarantineMetricRow, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, repository.CatalogQuarantineMetricRow{
+			Rule:       row.Rule,
+			Status:     row.Status,
+			Count:      row.Count,
+			Hits:       row.Hits,
+			LastSeenAt: row.LastSeenAt.Ptr(),
+		})
+	}
+	return out, nil
+}
+
+func (s *Store) InsertMarketChangesTx(ctx context.Context, tx *gorm.DB, items []models.MarketChange) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return createInBatches(tx.WithContext(ctx), items, 200)
+}
+
+func (s *Store) ListMarketChanges(ctx context.Context, params repository.ListMarketChangesParams) ([]models.MarketChange, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := applyMarketChangeFilters(s.db.WithContext(ctx).Model(&models.MarketChange{}), params)
+	limit := normalizeLimit(params.Limit, 100)
+	offset := normalizeOffset(params.Offset)
+	var items []models.MarketChange
+	if err := query.Order("detected_at desc, id desc").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) CountMarketChanges(ctx context.Context, params repository.ListMarketChangesParams) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	var total int64
+	if err := applyMarketChangeFilters(s.db.WithContext(ctx).Model(&models.MarketChange{}), params).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func applyMarketChangeFilters(query *gorm.DB, params repository.ListMarketChangesParams) *gorm.DB {
+	if params.MarketID != nil && strings.TrimSpace(*params.MarketID) != "" {
+		query = query.Where("market_id = ?", strings.TrimSpace(*params.MarketID))
+	}
+	if params.Field != nil && strings.TrimSpace(*params.Field) != "" {
+		query = query.Where("field = ?", strings.TrimSpace(*params.Field))
+	}
+	if params.HeldOnly {
+		query = query.Where("held_position = ?", true)
+	}
+	if params.Since != nil && !params.Since.IsZero() {
+		query = query.Where("detected_at >= ?", params.Since.UTC())
+	}
+	if params.Until != nil && !params.Until.IsZero() {
+		query = query.Where("detected_at < ?", params.Until.UTC())
+	}
+	return query
+}
+
+func (s *Store) InsertCatalogChangesTx(ctx context.Context, tx *gorm.DB, items []models.CatalogChange) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return createInBatches(tx.WithContext(ctx), items, 200)
+}
+
+func (s *Store) ListCatalogChanges(ctx context.Context, params repository.ListCatalogChangesParams) ([]models.CatalogChange, error) {
+	if s == nil || s.db == nil {
+		return nil, nil
+	}
+	query := s.db.WithContext(ctx).Model(&models.CatalogChange{}).Where("id > ?", params.AfterID)
+	if params.Since != nil && !params.Since.IsZero() {
+		query = query.Where("changed_at > ?", params.Since.UTC())
+	}
+	if params.Entity != nil && strings.TrimSpace(*params.Entity) != "" {
+		query = query.Where("entity = ?", strings.TrimSpace(*params.Entity))
+	}
+	var items []models.CatalogChange
+	if err := query.Order("id asc").Limit(normalizeLimit(params.Limit, 500)).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) ListHeldMarketIDs(ctx context.Context, marketIDs []string) ([]string, error) {
+	marketIDs = cleanStrings(marketIDs)
+	if s == nil || s.db == nil || len(marketIDs) == 0 {
+		return nil, nil
+	}
+	var out []string
+	if err := s.db.WithContext(ctx).
+		Model(&models.Position{}).
+		Distinct("market_id").
+		Where("market_id IN ? AND status = ?", marketIDs, "open").
+		Pluck("market_id", &out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func applyCatalogQuarantineFilters(query *gorm.DB, params repository.ListCatalogQuarantineParams) *gorm.DB {
+	if len(params.IDs) > 0 {
+		query = query.Where("id IN ?", params.IDs)
+	}
+	if params.EntityType != nil && strings.TrimSpace(*params.EntityType) != "" {
+		query = query.Where("entity_type = ?", strings.TrimSpace(*params.EntityType))
+	}
+	if params.EntityID != nil && strings.TrimSpace(*params.EntityID) != "" {
+		query = query.Where("entity_id = ?", strings.TrimSpace(*params.EntityID))
+	}
+	if params.Rule != nil && strings.TrimSpace(*params.Rule) != "" {
+		query = query.Where("rule = ?", strings.TrimSpace(*params.Rule))
+	}
+	if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+		query = query.Where("status = ?", strings.TrimSpace(*params.Status))
+	}
+	return query
+}
+
 func applyOrder(query *gorm.DB, orderBy string, asc *bool, fallback string) *gorm.DB {
 	column := strings.TrimSpace(orderBy)
 	if column == "" {
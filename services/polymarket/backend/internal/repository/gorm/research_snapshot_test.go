@@ -0,0 +1,41 @@
+package gormrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestResearchSnapshotNameFilter(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.ResearchSnapshot{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	for _, name := range []string{"NBA Finals", "election night"} {
+		item := &models.ResearchSnapshot{Name: name, Query: datatypes.JSON(`{}`), Payload: datatypes.JSON(`{}`), Checksum: "x", TakenAt: time.Now().UTC()}
+		if err := store.InsertResearchSnapshot(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	name := "nba"
+	items, err := store.ListResearchSnapshots(ctx, repository.ListResearchSnapshotsParams{Name: &name, Limit: 10})
+	if err != nil || len(items) != 1 || items[0].Name != "NBA Finals" {
+		t.Fatalf("snapshots = %+v, %v", items, err)
+	}
+	if n, err := store.CountResearchSnapshots(ctx, repository.ListResearchSnapshotsParams{Name: &name}); err != nil || n != 1 {
+		t.Fatalf("count = %d, %v", n, err)
+	}
+}
@@ -0,0 +1,72 @@
+package gormrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestListSettledTrades(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.Strategy{}, &models.Market{}, &models.Opportunity{}, &models.ExecutionPlan{}, &models.PnLRecord{}, &models.CatalogChange{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	strat := &models.Strategy{Name: "arb", Params: datatypes.JSON(`{}`)}
+	if err := store.UpsertStrategy(ctx, strat); err != nil {
+		t.Fatal(err)
+	}
+	volume := decimal.NewFromInt(25000)
+	if err := conn.Gorm.Create(&models.Market{ID: "m1", EventID: "e1", Question: "q", ConditionID: "c1", Volume: &volume, LastSeenAt: now, RawJSON: datatypes.JSON(`{}`)}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	settle := func(market string, size float64, settledAt time.Time) {
+		t.Helper()
+		opp := &models.Opportunity{StrategyID: strat.ID, Status: "executed", Legs: datatypes.JSON(`[]`), PrimaryMarketID: &market}
+		if err := store.InsertOpportunity(ctx, opp); err != nil {
+			t.Fatal(err)
+		}
+		plan := &models.ExecutionPlan{OpportunityID: opp.ID, StrategyName: "arb", Status: "executed", Legs: datatypes.JSON(`[]`), PlannedSizeUSD: decimal.NewFromFloat(size)}
+		if err := store.InsertExecutionPlan(ctx, plan); err != nil {
+			t.Fatal(err)
+		}
+		roi := decimal.NewFromFloat(0.05)
+		pnl := roi.Mul(decimal.NewFromFloat(size))
+		rec := &models.PnLRecord{PlanID: plan.ID, StrategyName: "arb", ExpectedEdge: decimal.NewFromFloat(0.02), RealizedROI: &roi, RealizedPnL: &pnl, Outcome: "win", SettledAt: &settledAt}
+		if err := store.UpsertPnLRecord(ctx, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	settle("m2", 40, now)
+	settle("m1", 200, now.Add(-time.Hour))
+
+	rows, err := store.ListSettledTrades(ctx, repository.SettledTradeParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows=%+v", rows)
+	}
+	first, second := rows[0], rows[1]
+	if first.MarketID != "m1" || first.MarketVolume == nil || *first.MarketVolume != 25000 || first.SizeUSD != 200 || first.RealizedUSD != 10 || first.RealizedROI != 0.05 {
+		t.Fatalf("first=%+v", first)
+	}
+	if second.MarketID != "m2" || second.MarketVolume != nil || second.SizeUSD != 40 {
+		t.Fatalf("second=%+v", second)
+	}
+}
@@ -0,0 +1,81 @@
+package gormrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+)
+
+func TestStrategyChangeCohorts(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.Strategy{}, &models.StrategyChange{}, &models.Opportunity{}, &models.ExecutionPlan{}, &models.PnLRecord{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	strat := &models.Strategy{Name: "s", Params: datatypes.JSON(`{}`)}
+	if err := store.UpsertStrategy(ctx, strat); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	until := now.Add(time.Hour)
+
+	first := &models.StrategyChange{StrategyName: "s", OldParams: datatypes.JSON(`{}`), NewParams: datatypes.JSON(`{"a":1}`), HoldoutPct: 20, HoldoutUntil: &until}
+	second := &models.StrategyChange{StrategyName: "s", OldParams: datatypes.JSON(`{"a":1}`), NewParams: datatypes.JSON(`{"a":2}`), HoldoutPct: 20, HoldoutUntil: &until}
+	for _, c := range []*models.StrategyChange{first, second} {
+		if err := store.InsertStrategyChange(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The later change supersedes the first one's holdout.
+	active, err := store.ListActiveStrategyChanges(ctx, now)
+	if err != nil || len(active) != 1 || active[0].ID != second.ID {
+		t.Fatalf("active=%+v err=%v", active, err)
+	}
+	if active, _ := store.ListActiveStrategyChanges(ctx, until.Add(time.Second)); len(active) != 0 {
+		t.Fatalf("expired holdout still active: %+v", active)
+	}
+
+	settled := func(cohort string, pnl ...float64) {
+		id := second.ID
+		for _, v := range pnl {
+			opp := &models.Opportunity{StrategyID: strat.ID, Status: "executed", Legs: datatypes.JSON(`[]`), StrategyChangeID: &id, Cohort: cohort}
+			if err := store.InsertOpportunity(ctx, opp); err != nil {
+				t.Fatal(err)
+			}
+			plan := &models.ExecutionPlan{OpportunityID: opp.ID, StrategyName: "s", Status: "executed", Legs: datatypes.JSON(`[]`)}
+			if err := store.InsertExecutionPlan(ctx, plan); err != nil {
+				t.Fatal(err)
+			}
+			realized := decimal.NewFromFloat(v)
+			if err := store.UpsertPnLRecord(ctx, &models.PnLRecord{PlanID: plan.ID, StrategyName: "s", RealizedPnL: &realized, SettledAt: &now}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	settled(models.CohortControl, 1, -1)
+	settled(models.CohortTreatment, 2, 3, -1)
+
+	rows, err := store.StrategyChangeCohorts(ctx, second.ID)
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("rows=%+v err=%v", rows, err)
+	}
+	control, treatment := rows[0], rows[1]
+	if control.Cohort != models.CohortControl || control.Settled != 2 || control.Wins != 1 || control.SumPnL != 0 || control.SumPnLSq != 2 {
+		t.Fatalf("control=%+v", control)
+	}
+	if treatment.Opportunities != 3 || treatment.Plans != 3 || treatment.SumPnL != 4 || treatment.SumPnLSq != 14 {
+		t.Fatalf("treatment=%+v", treatment)
+	}
+}
@@ -0,0 +1,122 @@
+package gormrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestPositionsAndSettingsAreKeyedByTenant(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := db.AutoMigrate(conn); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, p := range []models.Position{
+		{TokenID: "t1", MarketID: "m1", Tenant: "desk-a", Direction: "YES", Quantity: decimal.NewFromInt(10), Status: "open", OpenedAt: now},
+		{TokenID: "t1", MarketID: "m1", Tenant: "desk-b", Direction: "YES", Quantity: decimal.NewFromInt(3), Status: "open", OpenedAt: now},
+	} {
+		p := p
+		if err := store.UpsertPosition(ctx, &p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, err := store.GetPositionByTokenID(ctx, "desk-a", "t1")
+	if err != nil || a == nil || !a.Quantity.Equal(decimal.NewFromInt(10)) {
+		t.Fatalf("desk-a position = %+v, %v", a, err)
+	}
+	b, err := store.GetPositionByTokenID(ctx, "desk-b", "t1")
+	if err != nil || b == nil || !b.Quantity.Equal(decimal.NewFromInt(3)) || b.ID == a.ID {
+		t.Fatalf("desk-b position = %+v, %v", b, err)
+	}
+	if p, _ := store.GetPositionByTokenID(ctx, "", "t1"); p != nil {
+		t.Fatalf("default desk sees %+v", p)
+	}
+
+	for _, s := range []models.SystemSetting{
+		{Key: "trading.limit", Value: datatypes.JSON(`"one"`)},
+		{Key: "trading.limit", Tenant: "desk-a", Value: datatypes.JSON(`"two"`)},
+	} {
+		s := s
+		if err := store.UpsertSystemSetting(ctx, &s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shared, err := store.GetSystemSettingByKey(ctx, "trading.limit")
+	if err != nil || shared == nil || string(shared.Value) != `"one"` {
+		t.Fatalf("shared setting = %+v, %v", shared, err)
+	}
+	own, err := store.GetTenantSystemSetting(ctx, "desk-a", "trading.limit")
+	if err != nil || own == nil || string(own.Value) != `"two"` {
+		t.Fatalf("desk-a setting = %+v, %v", own, err)
+	}
+}
+
+func TestPlanRowsAreScopedByPlanTenant(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := db.AutoMigrate(conn); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	plans := []models.ExecutionPlan{
+		{OpportunityID: 1, StrategyName: "s", Tenant: "default", PlannedSizeUSD: decimal.NewFromInt(10), MaxLossUSD: decimal.NewFromInt(10), Legs: datatypes.JSON(`[]`)},
+		{OpportunityID: 2, StrategyName: "s", Tenant: "desk-a", PlannedSizeUSD: decimal.NewFromInt(10), MaxLossUSD: decimal.NewFromInt(10), Legs: datatypes.JSON(`[]`)},
+	}
+	if err := conn.Gorm.Create(&plans).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i, plan := range plans {
+		pnl := decimal.NewFromInt(int64(i + 1))
+		if err := conn.Gorm.Create(&models.Order{PlanID: plan.ID, TokenID: "t", Side: "BUY", Price: decimal.RequireFromString("0.5"), SizeUSD: decimal.NewFromInt(10), Status: "filled", CreatedAt: now, UpdatedAt: now}).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.Gorm.Create(&models.TradeJournal{ExecutionPlanID: plan.ID, OpportunityID: plan.OpportunityID, StrategyName: "s"}).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.Gorm.Create(&models.PnLRecord{PlanID: plan.ID, StrategyName: "s", RealizedPnL: &pnl, Outcome: "win", SettledAt: &now}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	desk := "desk-a"
+	orders, err := store.ListOrders(ctx, repository.ListOrdersParams{Tenant: &desk})
+	if err != nil || len(orders) != 1 || orders[0].PlanID != plans[1].ID {
+		t.Fatalf("desk-a orders = %+v, %v", orders, err)
+	}
+	if n, _ := store.CountOrders(ctx, repository.ListOrdersParams{}); n != 2 {
+		t.Fatalf("unscoped order count = %d", n)
+	}
+	journals, err := store.ListTradeJournals(ctx, repository.ListTradeJournalParams{Tenant: &desk})
+	if err != nil || len(journals) != 1 || journals[0].ExecutionPlanID != plans[1].ID {
+		t.Fatalf("desk-a journal = %+v, %v", journals, err)
+	}
+	overview, err := store.AnalyticsOverview(ctx, nil, "default")
+	if err != nil || overview.TotalPlans != 1 || overview.WinCount != 1 {
+		t.Fatalf("default overview = %+v, %v", overview, err)
+	}
+	if all, _ := store.AnalyticsOverview(ctx, nil, ""); all.TotalPlans != 2 {
+		t.Fatalf("unscoped overview = %+v", all)
+	}
+}
@@ -0,0 +1,84 @@
+package gormrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestTradeWebhookDeliveryLog(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.TradeWebhook{}, &models.TradeWebhookDelivery{}); err != nil {
+		t.Fatal(err)
+	}
+	store := New(conn.Gorm)
+	ctx := context.Background()
+	hook := &models.TradeWebhook{Name: "risk", URL: "http://risk.local", Secret: "s", Enabled: true}
+	if err := store.InsertTradeWebhook(ctx, hook); err != nil {
+		t.Fatal(err)
+	}
+
+	insert := func() (*models.TradeWebhookDelivery, bool) {
+		item := &models.TradeWebhookDelivery{WebhookID: hook.ID, EventType: models.TradeEventOrderFilled, EventKey: "fill:7", Payload: datatypes.JSON(`{}`)}
+		var inserted bool
+		if err := store.InTx(ctx, func(tx *gorm.DB) error {
+			var err error
+			inserted, err = store.InsertTradeWebhookDeliveryTx(ctx, tx, item)
+			return err
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return item, inserted
+	}
+	first, ok := insert()
+	if !ok || first.ID == 0 {
+		t.Fatalf("first insert=%v id=%d", ok, first.ID)
+	}
+	// A second replica seeing the same fill queues nothing.
+	if _, ok := insert(); ok {
+		t.Fatal("duplicate event should not be queued twice")
+	}
+
+	now := time.Now().UTC()
+	if err := store.RecordTradeWebhookAttempt(ctx, first.ID, 503, "unexpected status 503", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordTradeWebhookAttempt(ctx, first.ID, 200, "", now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.GetTradeWebhookDelivery(ctx, first.ID)
+	if err != nil || got == nil {
+		t.Fatalf("delivery=%v err=%v", got, err)
+	}
+	if got.Status != models.TradeDeliveryDelivered || got.Attempts != 2 || got.HTTPStatus != 200 || got.LastError != "" || got.DeliveredAt == nil {
+		t.Fatalf("delivery=%+v", got)
+	}
+	h, err := store.GetTradeWebhookByID(ctx, hook.ID)
+	if err != nil || h == nil || h.LastStatus != 200 || h.ConsecutiveFailures != 0 || h.LastDeliveryAt == nil {
+		t.Fatalf("webhook=%+v err=%v", h, err)
+	}
+
+	failed := models.TradeDeliveryFailed
+	n, err := store.CountTradeWebhookDeliveries(ctx, repository.ListTradeWebhookDeliveriesParams{WebhookID: hook.ID, Status: &failed})
+	if err != nil || n != 0 {
+		t.Fatalf("failed=%d err=%v", n, err)
+	}
+	if err := store.DeleteTradeWebhook(ctx, hook.ID); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := store.CountTradeWebhookDeliveries(ctx, repository.ListTradeWebhookDeliveriesParams{WebhookID: hook.ID}); n != 0 {
+		t.Fatalf("deliveries left after delete=%d", n)
+	}
+}
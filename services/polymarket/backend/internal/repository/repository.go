@@ -8,6 +8,7 @@ import (
 	"gorm.io/gorm"
 
 	"polymarket/internal/models"
+	"polymarket/internal/tradingday"
 )
 
 type CatalogRepository interface {
@@ -23,6 +24,22 @@ type CatalogRepository interface {
 	UpsertLastTradePrice(ctx context.Context, item *models.LastTradePrice) error
 	InsertRawWSEvent(ctx context.Context, item *models.RawWSEvent) error
 	InsertRawRESTSnapshot(ctx context.Context, item *models.RawRESTSnapshot) error
+	UpsertPriceCandles(ctx context.Context, items []models.PriceCandle) error
+	ListPriceCandles(ctx context.Context, tokenID string, since, until time.Time) ([]models.PriceCandle, error)
+	InsertTrade(ctx context.Context, item *models.Trade) error
+	ListTrades(ctx context.Context, params ListTradesParams) ([]models.Trade, error)
+	CountTrades(ctx context.Context, params ListTradesParams) (int64, error)
+	// TradeVolumeProfile buckets a token's trades since into price levels
+	// bucket wide.
+	TradeVolumeProfile(ctx context.Context, tokenID string, since time.Time, bucket float64) (VolumeProfile, error)
+	// PruneTrades deletes trades before cutoff and, when maxPerToken > 0,
+	// all but each token's newest maxPerToken.
+	PruneTrades(ctx context.Context, before time.Time, maxPerToken int) (int64, error)
+	ListRawWSEventTimes(ctx context.Context, tokenID string, since, until time.Time) ([]time.Time, error)
+	ListRawWSEventTokenIDs(ctx context.Context, since time.Time, limit int) ([]string, error)
+	GetEarliestPriceCandle(ctx context.Context, tokenID string) (*models.PriceCandle, error)
+	GetEarliestRawWSEvent(ctx context.Context, tokenID string, eventType string) (*models.RawWSEvent, error)
+	GetEarliestRawRESTSnapshot(ctx context.Context, tokenID string, snapshotType string) (*models.RawRESTSnapshot, error)
 	FindMarketsByConditionIDs(ctx context.Context, conditionIDs []string) ([]models.Market, error)
 	FindMarketsBySlugs(ctx context.Context, slugs []string) ([]models.Market, error)
 	GetMarketBySlug(ctx context.Context, slug string) (*models.Market, error)
@@ -31,10 +48,21 @@ type CatalogRepository interface {
 	ListMarketsByEventIDs(ctx context.Context, eventIDs []string) ([]models.Market, error)
 	ListMarketsByIDs(ctx context.Context, marketIDs []string) ([]models.Market, error)
 	ListMarketIDsForStream(ctx context.Context, limit int) ([]string, error)
+	ListMarketBaselines(ctx context.Context, marketIDs []string) ([]models.MarketBaseline, error)
+	UpsertMarketBaselines(ctx context.Context, items []models.MarketBaseline) error
+	// ListMarketsForOnchainCheck lists open markets with a condition id
+	// not yet seen resolved onchain, least recently checked first.
+	ListMarketsForOnchainCheck(ctx context.Context, limit int) ([]models.Market, error)
+	// SaveOnchainCondition stores what the CTF reader saw for a market and
+	// copies reported payouts onto its tokens by outcome index.
+	SaveOnchainCondition(ctx context.Context, marketID string, state OnchainConditionState) error
 	ListTokensByMarketIDs(ctx context.Context, marketIDs []string) ([]models.Token, error)
 	ListTokensByIDs(ctx context.Context, tokenIDs []string) ([]models.Token, error)
 	ListMarketDataHealthByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.MarketDataHealth, error)
 	ListOrderbookLatestByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.OrderbookLatest, error)
+	// ListCatalogBooks reads book, token and market for each token in one
+	// statement so the three catalog epochs can be compared.
+	ListCatalogBooks(ctx context.Context, tokenIDs []string) ([]CatalogBook, error)
 	ListLastTradePricesByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.LastTradePrice, error)
 	ListMarketAggregates(ctx context.Context, limit int) ([]EventAggregate, error)
 	ListEventsByIDs(ctx context.Context, ids []string) ([]models.Event, error)
@@ -48,12 +76,35 @@ type CatalogRepository interface {
 	SaveSyncStateTx(ctx context.Context, tx *gorm.DB, state *models.SyncState) error
 	ListSyncStates(ctx context.Context) ([]models.SyncState, error)
 	ListActiveEventsEndingSoon(ctx context.Context, hoursToExpiry int, limit int) ([]models.Event, error)
+
+	// Data-quality quarantine
+	UpsertCatalogQuarantineTx(ctx context.Context, tx *gorm.DB, items []models.CatalogQuarantine) error
+	ReleaseCatalogQuarantineTx(ctx context.Context, tx *gorm.DB, entityType string, entityIDs []string, at time.Time) (int64, error)
+	ReleaseCatalogQuarantineByIDsTx(ctx context.Context, tx *gorm.DB, ids []uint64, at time.Time) (int64, error)
+	ListCatalogQuarantine(ctx context.Context, params ListCatalogQuarantineParams) ([]models.CatalogQuarantine, error)
+	CountCatalogQuarantine(ctx context.Context, params ListCatalogQuarantineParams) (int64, error)
+	CatalogQuarantineMetrics(ctx context.Context) ([]CatalogQuarantineMetricRow, error)
+
+	// Market changelog
+	InsertMarketChangesTx(ctx context.Context, tx *gorm.DB, items []models.MarketChange) error
+	ListMarketChanges(ctx context.Context, params ListMarketChangesParams) ([]models.MarketChange, error)
+	CountMarketChanges(ctx context.Context, params ListMarketChangesParams) (int64, error)
+	// ListHeldMarketIDs returns those of marketIDs with an open position.
+	ListHeldMarketIDs(ctx context.Context, marketIDs []string) ([]string, error)
+
+	// Catalog change journal
+	InsertCatalogChangesTx(ctx context.Context, tx *gorm.DB, items []models.CatalogChange) error
+	// ListCatalogChanges returns journal entries in ID order.
+	ListCatalogChanges(ctx context.Context, params ListCatalogChangesParams) ([]models.CatalogChange, error)
 }
 
 // Repository is the V2 unified repository expected by the strategy engine modules.
 // It intentionally embeds CatalogRepository to preserve existing L1-L3 usage.
 type Repository interface {
 	CatalogRepository
+	// WithTx returns a repository whose methods run in tx, for writes whose
+	// follow-ups must commit or roll back with them.
+	WithTx(tx *gorm.DB) Repository
 
 	// L4: signals
 	InsertSignal(ctx context.Context, item *models.Signal) error
@@ -64,9 +115,26 @@ type Repository interface {
 	UpsertSignalSource(ctx context.Context, item *models.SignalSource) error
 	ListSignalSources(ctx context.Context) ([]models.SignalSource, error)
 
+	// L4: tracked wallets (peer benchmark)
+	ListWalletPositions(ctx context.Context, wallet string) ([]models.WalletPosition, error)
+	UpsertWalletPosition(ctx context.Context, item *models.WalletPosition) error
+	DeleteWalletPosition(ctx context.Context, wallet string, tokenID string) error
+	InsertWalletPositionChange(ctx context.Context, item *models.WalletPositionChange) error
+	ListWalletPositionChanges(ctx context.Context, params ListWalletPositionChangesParams) ([]models.WalletPositionChange, error)
+	CountWalletPositionChanges(ctx context.Context, params ListWalletPositionChangesParams) (int64, error)
+
+	// Market data gaps (stream holes and their backfill state)
+	InsertMarketDataGap(ctx context.Context, item *models.MarketDataGap) (bool, error)
+	UpdateMarketDataGap(ctx context.Context, item *models.MarketDataGap) error
+	ListMarketDataGaps(ctx context.Context, params ListMarketDataGapsParams) ([]models.MarketDataGap, error)
+	CountMarketDataGaps(ctx context.Context, params ListMarketDataGapsParams) (int64, error)
+
 	// Existing hot data (helpers for collectors).
 	ListMarketDataHealthCandidates(ctx context.Context, limit int, minSpreadBps float64) ([]models.MarketDataHealth, error)
 	ListYesTokenJumpCandidates(ctx context.Context, limit int, minJumpBps float64, maxSpreadBps float64) ([]TokenJumpCandidate, error)
+	// MarketActivity counts tokens with market data updated since, how many
+	// of them jumped at least minJumpBps, and how many traded since.
+	MarketActivity(ctx context.Context, since time.Time, minJumpBps float64) (MarketActivity, error)
 
 	// Catalog helpers for labeler.
 	ListTagsByEventIDs(ctx context.Context, eventIDs []string) (map[string][]models.Tag, error)
@@ -78,6 +146,243 @@ type Repository interface {
 	SetStrategyEnabled(ctx context.Context, name string, enabled bool) error
 	UpdateStrategyParams(ctx context.Context, name string, params []byte) error
 	UpdateStrategyStats(ctx context.Context, name string, stats []byte) error
+	SetStrategyTenant(ctx context.Context, name string, tenant string) error
+	SetStrategyLaunchStage(ctx context.Context, name string, stage string) error
+
+	// Local write audit (hash chained, append-only)
+	AppendAuditRecord(ctx context.Context, item *models.AuditRecord, seal func(prev *models.AuditRecord, item *models.AuditRecord)) error
+	ListAuditRecords(ctx context.Context, params ListAuditRecordsParams) ([]models.AuditRecord, error)
+
+	// Idempotency keys for V2 writes
+	// ReserveIdempotencyKey inserts item unless its (tenant, key) is taken by
+	// an unexpired row, which is returned with false instead.
+	ReserveIdempotencyKey(ctx context.Context, item *models.IdempotencyKey) (*models.IdempotencyKey, bool, error)
+	// CompleteIdempotencyKey stores the response and keeps the key until
+	// expiresAt.
+	CompleteIdempotencyKey(ctx context.Context, id uint64, status int, contentType string, body string, expiresAt time.Time) error
+	// RenewIdempotencyKey moves a pending key's expiry to expiresAt while
+	// its request runs.
+	RenewIdempotencyKey(ctx context.Context, id uint64, expiresAt time.Time) error
+	DeleteIdempotencyKey(ctx context.Context, id uint64) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+
+	// Compliance overrides
+	InsertComplianceOverride(ctx context.Context, item *models.ComplianceOverride) error
+	GetComplianceOverrideByID(ctx context.Context, id uint64) (*models.ComplianceOverride, error)
+	ListComplianceOverrides(ctx context.Context, params ListComplianceOverridesParams) ([]models.ComplianceOverride, error)
+	CountComplianceOverrides(ctx context.Context, params ListComplianceOverridesParams) (int64, error)
+	// ListActiveComplianceOverrides returns the unrevoked, unexpired overrides
+	// for marketIDs at now.
+	ListActiveComplianceOverrides(ctx context.Context, marketIDs []string, now time.Time) ([]models.ComplianceOverride, error)
+	RevokeComplianceOverride(ctx context.Context, id uint64, reason string, at time.Time) error
+
+	// Campaigns
+	InsertCampaign(ctx context.Context, item *models.Campaign) error
+	UpdateCampaign(ctx context.Context, item *models.Campaign) error
+	GetCampaignByID(ctx context.Context, id uint64) (*models.Campaign, error)
+	ListCampaigns(ctx context.Context, params ListCampaignsParams) ([]models.Campaign, error)
+	CountCampaigns(ctx context.Context, params ListCampaignsParams) (int64, error)
+	// AssignPlansToCampaign and AssignOpportunitiesToCampaign set the
+	// campaign of the given rows; a nil campaignID removes them from theirs.
+	AssignPlansToCampaign(ctx context.Context, campaignID *uint64, planIDs []uint64) (int64, error)
+	AssignOpportunitiesToCampaign(ctx context.Context, campaignID *uint64, opportunityIDs []uint64) (int64, error)
+	CampaignStats(ctx context.Context, campaignID uint64) (CampaignStats, error)
+
+	// Liquidity reward epochs
+	GetRewardEpoch(ctx context.Context, epoch, marketID string) (*models.RewardEpoch, error)
+	// SaveRewardEpoch inserts item or, when ID is set, overwrites it.
+	SaveRewardEpoch(ctx context.Context, item *models.RewardEpoch) error
+	ListRewardEpochs(ctx context.Context, params ListRewardEpochsParams) ([]models.RewardEpoch, error)
+	CountRewardEpochs(ctx context.Context, params ListRewardEpochsParams) (int64, error)
+
+	// Trade tickets
+	InsertTradeTicket(ctx context.Context, item *models.TradeTicket) error
+	UpdateTradeTicket(ctx context.Context, item *models.TradeTicket) error
+	GetTradeTicketByID(ctx context.Context, id uint64) (*models.TradeTicket, error)
+	ListTradeTickets(ctx context.Context, params ListTradeTicketsParams) ([]models.TradeTicket, error)
+	CountTradeTickets(ctx context.Context, params ListTradeTicketsParams) (int64, error)
+
+	// Research snapshots (immutable)
+	InsertResearchSnapshot(ctx context.Context, item *models.ResearchSnapshot) error
+	GetResearchSnapshotByID(ctx context.Context, id uint64) (*models.ResearchSnapshot, error)
+	// ListResearchSnapshots omits Payload.
+	ListResearchSnapshots(ctx context.Context, params ListResearchSnapshotsParams) ([]models.ResearchSnapshot, error)
+	CountResearchSnapshots(ctx context.Context, params ListResearchSnapshotsParams) (int64, error)
+	// CountResearchSnapshotsByIDs counts the ids that exist and tenant may see.
+	CountResearchSnapshotsByIDs(ctx context.Context, ids []uint64, tenant *string) (int64, error)
+
+	// Analytics export jobs
+	InsertAnalyticsExport(ctx context.Context, item *models.AnalyticsExport) error
+	GetAnalyticsExportByID(ctx context.Context, id uint64) (*models.AnalyticsExport, error)
+	ListAnalyticsExports(ctx context.Context, params ListAnalyticsExportsParams) ([]models.AnalyticsExport, error)
+	CountAnalyticsExports(ctx context.Context, params ListAnalyticsExportsParams) (int64, error)
+	DeleteAnalyticsExport(ctx context.Context, id uint64) error
+	// RecordAnalyticsExportRun bumps the job's run count and keeps the
+	// watermark of the run.
+	RecordAnalyticsExportRun(ctx context.Context, id uint64, watermark string, at time.Time) error
+	// ListSettledTrades lists settled plans oldest first with their size
+	// and the volume of their primary market.
+	ListSettledTrades(ctx context.Context, params SettledTradeParams) ([]SettledTradeRow, error)
+
+	// Cash operations
+	InsertCashOperation(ctx context.Context, item *models.CashOperation) error
+	UpdateCashOperation(ctx context.Context, item *models.CashOperation) error
+	GetCashOperationByID(ctx context.Context, id uint64) (*models.CashOperation, error)
+	ListCashOperations(ctx context.Context, params ListCashOperationsParams) ([]models.CashOperation, error)
+	CountCashOperations(ctx context.Context, params ListCashOperationsParams) (int64, error)
+	// CashBalance sums the cash ledger of tenant (nil = all tenants).
+	CashBalance(ctx context.Context, tenant *string) (CashBalance, error)
+
+	// Data retention; table must be a key of RetentionColumns.
+	RetentionBacklog(ctx context.Context, table string, before time.Time) (RetentionBacklog, error)
+	// PurgeRetention deletes up to limit rows of table older than before.
+	PurgeRetention(ctx context.Context, table string, before time.Time, limit int) (int64, error)
+
+	// Execution cost forecasts
+	InsertCostForecast(ctx context.Context, item *models.CostForecast) error
+	GetCostForecastByPlanID(ctx context.Context, planID uint64) (*models.CostForecast, error)
+	// ListCostForecastOutcomes pairs forecasts of plans with fills with the
+	// fees and slippage those plans realized.
+	ListCostForecastOutcomes(ctx context.Context, params CostForecastOutcomeParams) ([]CostForecastOutcomeRow, error)
+
+	// Risk filter decision audit
+	InsertRiskDecisions(ctx context.Context, items []models.RiskDecision) error
+	ListRiskDecisions(ctx context.Context, params ListRiskDecisionsParams) ([]models.RiskDecision, error)
+	CountRiskDecisions(ctx context.Context, params ListRiskDecisionsParams) (int64, error)
+
+	// Market category regimes
+	// ListCategoryTokens returns the first-outcome tokens of up to limit open
+	// markets whose event carries the category as tag slug or label, by
+	// market volume.
+	ListCategoryTokens(ctx context.Context, category string, limit int) ([]models.Token, error)
+	CountCategorySettlements(ctx context.Context, category string, since time.Time) (int64, error)
+	InsertMarketRegimes(ctx context.Context, items []models.MarketRegime) error
+	// ListLatestMarketRegimes returns the newest regime of each category.
+	ListLatestMarketRegimes(ctx context.Context) ([]models.MarketRegime, error)
+	ListMarketRegimes(ctx context.Context, params ListMarketRegimesParams) ([]models.MarketRegime, error)
+	CountMarketRegimes(ctx context.Context, params ListMarketRegimesParams) (int64, error)
+
+	// Pipeline freshness SLOs
+	InsertSLOSamples(ctx context.Context, items []models.SLOSample) error
+	// SumSLOSamples totals the good and total items of slo's samples
+	// evaluated since.
+	SumSLOSamples(ctx context.Context, slo string, since time.Time) (good int64, total int64, err error)
+	ListSLOSamples(ctx context.Context, params ListSLOSamplesParams) ([]models.SLOSample, error)
+	CountSLOSamples(ctx context.Context, params ListSLOSamplesParams) (int64, error)
+	// CountClosedMarketSettlements counts the markets closed (by external
+	// update time) in [from, to) and how many of them have a settlement row.
+	CountClosedMarketSettlements(ctx context.Context, from, to time.Time) (closed int64, settled int64, err error)
+
+	// Transactional outbox
+	InsertOutboxMessagesTx(ctx context.Context, tx *gorm.DB, items []models.OutboxMessage) error
+	// ClaimOutboxMessages returns up to limit pending messages due at now,
+	// oldest first, and pushes their next attempt to now+lease so other
+	// dispatchers skip them while they are delivered.
+	ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error)
+	MarkOutboxMessageDelivered(ctx context.Context, id uint64, at time.Time) error
+	// MarkOutboxMessageFailed records a failed attempt; the message is
+	// retried at retryAt, or marked dead when retryAt is nil.
+	MarkOutboxMessageFailed(ctx context.Context, id uint64, errMsg string, retryAt *time.Time) error
+	// RetryOutboxMessage makes a dead message pending again with a fresh
+	// attempt count; false when id is not a dead message.
+	RetryOutboxMessage(ctx context.Context, id uint64, now time.Time) (bool, error)
+	ListOutboxMessages(ctx context.Context, params ListOutboxMessagesParams) ([]models.OutboxMessage, error)
+	CountOutboxMessages(ctx context.Context, params ListOutboxMessagesParams) (int64, error)
+	// CountOutboxMessagesByStatus counts messages per status.
+	CountOutboxMessagesByStatus(ctx context.Context) (map[string]int64, error)
+	DeleteDeliveredOutboxMessages(ctx context.Context, before time.Time) (int64, error)
+
+	// Executor write-ahead intents
+	InsertExecutorIntent(ctx context.Context, item *models.ExecutorIntent) error
+	// ResolveExecutorIntent moves a pending intent to status with updates;
+	// intents no longer pending are left alone.
+	ResolveExecutorIntent(ctx context.Context, id uint64, status string, updates map[string]any) error
+	ListExecutorIntents(ctx context.Context, params ListExecutorIntentsParams) ([]models.ExecutorIntent, error)
+	CountExecutorIntents(ctx context.Context, params ListExecutorIntentsParams) (int64, error)
+	// CountExecutorIntentsByStatus counts intents per status.
+	CountExecutorIntentsByStatus(ctx context.Context) (map[string]int64, error)
+
+	// Strategy changes and their holdout cohorts
+	InsertStrategyChange(ctx context.Context, item *models.StrategyChange) error
+	GetStrategyChange(ctx context.Context, id uint64) (*models.StrategyChange, error)
+	GetLatestStrategyChange(ctx context.Context, strategyName string) (*models.StrategyChange, error)
+	// ListActiveStrategyChanges returns each strategy's latest change when
+	// its holdout is still running at now.
+	ListActiveStrategyChanges(ctx context.Context, now time.Time) ([]models.StrategyChange, error)
+	// StrategyChangeCohorts sums the plans and settled PnL of the
+	// opportunities tagged with the change, per cohort.
+	StrategyChangeCohorts(ctx context.Context, changeID uint64) ([]CohortOutcome, error)
+
+	// Strategy capacity estimates
+	InsertStrategyCapacity(ctx context.Context, item *models.StrategyCapacity) error
+	GetLatestStrategyCapacity(ctx context.Context, strategyName string) (*models.StrategyCapacity, error)
+	// ListStrategyCapacities returns the strategy's estimates, newest first.
+	ListStrategyCapacities(ctx context.Context, strategyName string, limit int) ([]models.StrategyCapacity, error)
+
+	// Strategy backtest expectations and drift reports
+	GetStrategyExpectation(ctx context.Context, strategyName string) (*models.StrategyExpectation, error)
+	ListStrategyExpectations(ctx context.Context) ([]models.StrategyExpectation, error)
+	// SaveStrategyExpectation inserts item or, when ID is set, overwrites it.
+	SaveStrategyExpectation(ctx context.Context, item *models.StrategyExpectation) error
+	DeleteStrategyExpectation(ctx context.Context, strategyName string) error
+	InsertStrategyDriftReport(ctx context.Context, item *models.StrategyDriftReport) error
+	GetLatestStrategyDriftReport(ctx context.Context, strategyName string) (*models.StrategyDriftReport, error)
+	// ListStrategyDriftReports returns the strategy's reports, newest first.
+	ListStrategyDriftReports(ctx context.Context, strategyName string, limit int) ([]models.StrategyDriftReport, error)
+
+	// Execution rule tuning runs
+	InsertRuleTuning(ctx context.Context, item *models.RuleTuning) error
+	UpdateRuleTuning(ctx context.Context, item *models.RuleTuning) error
+	GetRuleTuning(ctx context.Context, id uint64) (*models.RuleTuning, error)
+	// ListRuleTunings returns the strategy's runs, newest first.
+	ListRuleTunings(ctx context.Context, strategyName string, limit int) ([]models.RuleTuning, error)
+
+	// Strategy param tuning runs
+	InsertStrategyParamTuning(ctx context.Context, item *models.StrategyParamTuning) error
+	UpdateStrategyParamTuning(ctx context.Context, item *models.StrategyParamTuning) error
+	GetStrategyParamTuning(ctx context.Context, id uint64) (*models.StrategyParamTuning, error)
+	// ListStrategyParamTunings returns the strategy's runs, newest first.
+	ListStrategyParamTunings(ctx context.Context, strategyName string, limit int) ([]models.StrategyParamTuning, error)
+
+	// Strategy turnover budgets
+	GetStrategyBudget(ctx context.Context, strategyName string) (*models.StrategyBudget, error)
+	// SaveStrategyBudget inserts item or, when ID is set, overwrites it.
+	SaveStrategyBudget(ctx context.Context, item *models.StrategyBudget) error
+	// StrategyTurnover sums the strategy's fills since the given time.
+	StrategyTurnover(ctx context.Context, strategyName string, since time.Time) (StrategyTurnover, error)
+
+	// Catalog webhooks
+	InsertCatalogWebhook(ctx context.Context, item *models.CatalogWebhook) error
+	UpdateCatalogWebhook(ctx context.Context, item *models.CatalogWebhook) error
+	GetCatalogWebhookByID(ctx context.Context, id uint64) (*models.CatalogWebhook, error)
+	ListCatalogWebhooks(ctx context.Context, params ListCatalogWebhooksParams) ([]models.CatalogWebhook, error)
+	DeleteCatalogWebhook(ctx context.Context, id uint64) error
+	// RecordCatalogWebhookDelivery stores a delivery outcome; an error
+	// message counts as a failure and an empty one resets the failure streak.
+	RecordCatalogWebhookDelivery(ctx context.Context, id uint64, status int, errMsg string, at time.Time) error
+
+	// Trade webhooks
+	InsertTradeWebhook(ctx context.Context, item *models.TradeWebhook) error
+	UpdateTradeWebhook(ctx context.Context, item *models.TradeWebhook) error
+	GetTradeWebhookByID(ctx context.Context, id uint64) (*models.TradeWebhook, error)
+	ListTradeWebhooks(ctx context.Context, params ListTradeWebhooksParams) ([]models.TradeWebhook, error)
+	// DeleteTradeWebhook deletes the webhook and its delivery log.
+	DeleteTradeWebhook(ctx context.Context, id uint64) error
+	// InsertTradeWebhookDeliveryTx queues item unless the webhook already
+	// has a delivery for its EventKey; false when it had.
+	InsertTradeWebhookDeliveryTx(ctx context.Context, tx *gorm.DB, item *models.TradeWebhookDelivery) (bool, error)
+	GetTradeWebhookDelivery(ctx context.Context, id uint64) (*models.TradeWebhookDelivery, error)
+	ListTradeWebhookDeliveries(ctx context.Context, params ListTradeWebhookDeliveriesParams) ([]models.TradeWebhookDelivery, error)
+	CountTradeWebhookDeliveries(ctx context.Context, params ListTradeWebhookDeliveriesParams) (int64, error)
+	// RecordTradeWebhookAttempt stores an attempt on the delivery and its
+	// webhook; an error message counts as a failure.
+	RecordTradeWebhookAttempt(ctx context.Context, deliveryID uint64, status int, errMsg string, at time.Time) error
+
+	// L5: strategy evaluation runs
+	InsertEvaluationRun(ctx context.Context, item *models.EvaluationRun) error
+	ListEvaluationRuns(ctx context.Context, params ListEvaluationRunsParams) ([]models.EvaluationRun, error)
+	CountEvaluationRuns(ctx context.Context, params ListEvaluationRunsParams) (int64, error)
+	DeleteEvaluationRunsBefore(ctx context.Context, before time.Time) (int64, error)
 
 	// L5: opportunities
 	InsertOpportunity(ctx context.Context, item *models.Opportunity) error
@@ -86,7 +391,9 @@ type Repository interface {
 	ListOpportunities(ctx context.Context, params ListOpportunitiesParams) ([]models.Opportunity, error)
 	CountOpportunities(ctx context.Context, params ListOpportunitiesParams) (int64, error)
 	UpdateOpportunityStatus(ctx context.Context, id uint64, status string) error
+	UpdateOpportunityStatusTx(ctx context.Context, tx *gorm.DB, id uint64, status string) error
 	ExpireDueOpportunities(ctx context.Context, now time.Time) (int64, error)
+	UpdateOpportunityDecay(ctx context.Context, id uint64, decay OpportunityDecay) error
 	CountActiveOpportunities(ctx context.Context) (int64, error)
 	ListOldestActiveOpportunityIDs(ctx context.Context, limit int) ([]uint64, error)
 	BulkUpdateOpportunityStatus(ctx context.Context, ids []uint64, status string) (int64, error)
@@ -94,28 +401,55 @@ type Repository interface {
 	// L5: labels
 	UpsertMarketLabel(ctx context.Context, item *models.MarketLabel) error
 	ListMarketLabels(ctx context.Context, params ListMarketLabelsParams) ([]models.MarketLabel, error)
+	ListMarketLabelsByMarketIDs(ctx context.Context, marketIDs []string) ([]models.MarketLabel, error)
 	DeleteMarketLabel(ctx context.Context, marketID string, label string) error
 
 	// L6: execution & analytics (MVP)
 	InsertExecutionPlan(ctx context.Context, item *models.ExecutionPlan) error
+	InsertExecutionPlanTx(ctx context.Context, tx *gorm.DB, item *models.ExecutionPlan) error
 	GetExecutionPlanByID(ctx context.Context, id uint64) (*models.ExecutionPlan, error)
 	ListExecutionPlans(ctx context.Context, params ListExecutionPlansParams) ([]models.ExecutionPlan, error)
 	CountExecutionPlans(ctx context.Context, params ListExecutionPlansParams) (int64, error)
 	ListExecutionPlansByStatuses(ctx context.Context, statuses []string, limit int) ([]models.ExecutionPlan, error)
 	UpdateExecutionPlanStatus(ctx context.Context, id uint64, status string) error
-	UpdateExecutionPlanPreflight(ctx context.Context, id uint64, status string, preflightResult []byte) error
+	UpdateExecutionPlanStatusTx(ctx context.Context, tx *gorm.DB, id uint64, status string) error
+	// UpdateExecutionPlanSizing resizes a plan and returns it to draft.
+	UpdateExecutionPlanSizing(ctx context.Context, id uint64, plannedSizeUSD, maxLossUSD decimal.Decimal, legs []byte) error
+	UpdateExecutionPlanPreflight(ctx context.Context, id uint64, status string, preflightResult []byte, riskReport []byte) error
 	UpdateExecutionPlanExecutedAt(ctx context.Context, id uint64, status string, executedAt *time.Time) error
+	UpdateExecutionPlanExecutedAtTx(ctx context.Context, tx *gorm.DB, id uint64, status string, executedAt *time.Time) error
+	// UpdateExecutionPlanTrigger writes the plan's trigger columns.
+	UpdateExecutionPlanTrigger(ctx context.Context, item *models.ExecutionPlan) error
+	// ListDuePlanTriggers returns draft and preflight_pass plans whose
+	// scheduled trigger is at or before now, earliest first.
+	ListDuePlanTriggers(ctx context.Context, now time.Time, limit int) ([]models.ExecutionPlan, error)
 	CountExecutionPlansByStrategySince(ctx context.Context, strategyName string, since time.Time) (int64, error)
+	// CountExecutionPlansSince counts plans created since the given time;
+	// autoExecuted narrows to (non-)auto-executed plans when set.
+	CountExecutionPlansSince(ctx context.Context, since time.Time, autoExecuted *bool) (int64, error)
 	InsertFill(ctx context.Context, item *models.Fill) error
+	GetFillByID(ctx context.Context, id uint64) (*models.Fill, error)
 	ListFillsByPlanID(ctx context.Context, planID uint64) ([]models.Fill, error)
+	// ListRecordedExternalTradeIDs returns those of ids already on a fill.
+	ListRecordedExternalTradeIDs(ctx context.Context, ids []string) ([]string, error)
+	// ListFillsChronological returns every fill (optionally for one token) in replay order.
+	ListFillsChronological(ctx context.Context, tokenID string) ([]models.Fill, error)
+	// CountFillsBetween counts fills filled in [since, until).
+	CountFillsBetween(ctx context.Context, since, until time.Time) (int64, error)
 	UpsertPnLRecord(ctx context.Context, item *models.PnLRecord) error
+	UpsertPnLRecordTx(ctx context.Context, tx *gorm.DB, item *models.PnLRecord) error
 	GetPnLRecordByPlanID(ctx context.Context, planID uint64) (*models.PnLRecord, error)
 	SumRealizedPnLSince(ctx context.Context, since time.Time) (decimal.Decimal, error)
+	SumRealizedPnLSinceForTenant(ctx context.Context, since time.Time, tenant string) (decimal.Decimal, error)
 
 	// Automation rules (L7)
 	UpsertExecutionRule(ctx context.Context, item *models.ExecutionRule) error
 	GetExecutionRuleByStrategyName(ctx context.Context, strategyName string) (*models.ExecutionRule, error)
 	ListExecutionRules(ctx context.Context) ([]models.ExecutionRule, error)
+	// OpportunityConversionByStrategy counts opportunities created since and
+	// how many of them got an execution plan, per strategy. An empty tenant
+	// covers all desks.
+	OpportunityConversionByStrategy(ctx context.Context, since time.Time, tenant string) ([]StrategyConversion, error)
 	DeleteExecutionRuleByStrategyName(ctx context.Context, strategyName string) error
 
 	// Trade journal (L7)
@@ -123,19 +457,30 @@ type Repository interface {
 	GetTradeJournalByPlanID(ctx context.Context, planID uint64) (*models.TradeJournal, error)
 	UpdateTradeJournalExit(ctx context.Context, planID uint64, updates map[string]any) error
 	UpdateTradeJournalNotes(ctx context.Context, planID uint64, notes string, tags []byte, reviewedAt *time.Time) error
+	UpdateTradeJournalSnapshots(ctx context.Context, planID uint64, snapshotIDs []byte) error
 	ListTradeJournals(ctx context.Context, params ListTradeJournalParams) ([]models.TradeJournal, error)
 	CountTradeJournals(ctx context.Context, params ListTradeJournalParams) (int64, error)
 
 	// System settings (L8)
+	// UpsertSystemSetting writes the item's tenant row of its key; an empty
+	// tenant is the shared default row.
 	UpsertSystemSetting(ctx context.Context, item *models.SystemSetting) error
+	// UpsertSystemSettings writes items in one transaction: all or none.
+	UpsertSystemSettings(ctx context.Context, items []models.SystemSetting) error
+	// GetSystemSettingByKey returns the shared row owned by the default tenant.
 	GetSystemSettingByKey(ctx context.Context, key string) (*models.SystemSetting, error)
+	// GetTenantSystemSetting returns tenant's own row of key, without falling
+	// back to the shared row.
+	GetTenantSystemSetting(ctx context.Context, tenant, key string) (*models.SystemSetting, error)
 	ListSystemSettings(ctx context.Context, params ListSystemSettingsParams) ([]models.SystemSetting, error)
 	CountSystemSettings(ctx context.Context, params ListSystemSettingsParams) (int64, error)
 
 	// Positions & portfolio (L8)
 	UpsertPosition(ctx context.Context, item *models.Position) error
 	GetPositionByID(ctx context.Context, id uint64) (*models.Position, error)
-	GetPositionByTokenID(ctx context.Context, tokenID string) (*models.Position, error)
+	// GetPositionByTokenID returns tenant's position in the token; an empty
+	// tenant means the default desk.
+	GetPositionByTokenID(ctx context.Context, tenant, tokenID string) (*models.Position, error)
 	ListPositions(ctx context.Context, params ListPositionsParams) ([]models.Position, error)
 	CountPositions(ctx context.Context, params ListPositionsParams) (int64, error)
 	ListOpenPositions(ctx context.Context) ([]models.Position, error)
@@ -144,28 +489,43 @@ type Repository interface {
 
 	InsertPortfolioSnapshot(ctx context.Context, item *models.PortfolioSnapshot) error
 	ListPortfolioSnapshots(ctx context.Context, params ListPortfolioSnapshotsParams) ([]models.PortfolioSnapshot, error)
+	// InsertPortfolioAnomaly stores a flag unless the snapshot already has
+	// one of its kind, and reports whether it was stored.
+	InsertPortfolioAnomaly(ctx context.Context, item *models.PortfolioAnomaly) (bool, error)
+	ListPortfolioAnomalies(ctx context.Context, params ListPortfolioAnomaliesParams) ([]models.PortfolioAnomaly, error)
+	CountPortfolioAnomalies(ctx context.Context, params ListPortfolioAnomaliesParams) (int64, error)
 
 	// Orders (L8)
 	InsertOrder(ctx context.Context, item *models.Order) error
 	GetOrderByID(ctx context.Context, id uint64) (*models.Order, error)
 	ListOrders(ctx context.Context, params ListOrdersParams) ([]models.Order, error)
 	CountOrders(ctx context.Context, params ListOrdersParams) (int64, error)
+	// CountOpenOrders counts orders that are pending, submitted or partially filled.
+	CountOpenOrders(ctx context.Context) (int64, error)
 	UpdateOrderStatus(ctx context.Context, id uint64, status string, updates map[string]any) error
+	// AddOrderImprovementUSD adds price improvement captured by a fill.
+	AddOrderImprovementUSD(ctx context.Context, id uint64, usd decimal.Decimal) error
 
 	// Strategy deep analytics (L9)
 	UpsertStrategyDailyStats(ctx context.Context, item *models.StrategyDailyStats) error
 	ListStrategyDailyStats(ctx context.Context, params ListDailyStatsParams) ([]models.StrategyDailyStats, error)
-	AttributionByStrategy(ctx context.Context, strategyName string, since, until *time.Time) (AttributionResult, error)
-	PortfolioDrawdown(ctx context.Context) (DrawdownResult, error)
-	StrategyCorrelation(ctx context.Context, since, until *time.Time) ([]CorrelationRow, error)
-	PerformanceRatios(ctx context.Context, since, until *time.Time) (RatiosResult, error)
-	RebuildStrategyDailyStats(ctx context.Context, since, until *time.Time) (int, error)
+	// asOf restricts analytics to pnl data known at that time (nil = now).
+	// The portfolio queries take the desk whose plans they cover; an empty
+	// tenant covers all desks.
+	AttributionByStrategy(ctx context.Context, strategyName string, since, until, asOf *time.Time) (AttributionResult, error)
+	PortfolioDrawdown(ctx context.Context, tenant string) (DrawdownResult, error)
+	StrategyCorrelation(ctx context.Context, since, until *time.Time, tenant string) ([]CorrelationRow, error)
+	PerformanceRatios(ctx context.Context, since, until, asOf *time.Time, tenant string) (RatiosResult, error)
+	// RebuildStrategyDailyStats recomputes the daily rows for the trading days
+	// of cal overlapping [since, until], replacing any rows stored there.
+	RebuildStrategyDailyStats(ctx context.Context, since, until *time.Time, cal *tradingday.Calendar) (int, error)
 
 	// Settlement history (L6 support for systematic strategies)
 	UpsertMarketSettlementHistory(ctx context.Context, item *models.MarketSettlementHistory) error
 	ListMarketSettlementHistoryByMarketIDs(ctx context.Context, marketIDs []string) ([]models.MarketSettlementHistory, error)
 	ListRecentMarketSettlementHistory(ctx context.Context, since time.Time, limit int) ([]models.MarketSettlementHistory, error)
 	ListLabelNoRateStats(ctx context.Context, labels []string) ([]LabelNoRateRow, error)
+	ListCalibrationSamples(ctx context.Context, since, until *time.Time, limit int) ([]CalibrationSampleRow, error)
 
 	// Market review (L9)
 	UpsertMarketReview(ctx context.Context, item *models.MarketReview) error
@@ -176,11 +536,22 @@ type Repository interface {
 	LabelPerformance(ctx context.Context) ([]LabelPerformanceRow, error)
 	UpdateMarketReviewNotes(ctx context.Context, id uint64, notes string, lessonTags []byte) error
 
-	// Analytics queries (L6)
-	AnalyticsOverview(ctx context.Context) (AnalyticsOverview, error)
-	AnalyticsByStrategy(ctx context.Context) ([]StrategyAnalyticsRow, error)
+	// Analytics queries (L6). An empty tenant covers all desks.
+	AnalyticsOverview(ctx context.Context, asOf *time.Time, tenant string) (AnalyticsOverview, error)
+	AnalyticsByStrategy(ctx context.Context, tenant string) ([]StrategyAnalyticsRow, error)
 	AnalyticsStrategyOutcomes(ctx context.Context) ([]StrategyOutcomeRow, error)
-	AnalyticsFailures(ctx context.Context) ([]FailureAnalyticsRow, error)
+	AnalyticsFailures(ctx context.Context, tenant string) ([]FailureAnalyticsRow, error)
+	// SignalQualityStats joins opportunities -> plans -> pnl per triggering signal type.
+	SignalQualityStats(ctx context.Context, since *time.Time) ([]SignalQualityRow, error)
+	// PnL breakdowns pivot settled pnl_records by the market label of the
+	// plan's opportunity, the UTC hour the plan was entered, and how long it
+	// was held until settlement.
+	PnLBreakdownByLabel(ctx context.Context, params PnLBreakdownParams) ([]PnLBreakdownRow, error)
+	PnLBreakdownByEntryHour(ctx context.Context, params PnLBreakdownParams) ([]PnLBreakdownRow, error)
+	PnLBreakdownByHoldDuration(ctx context.Context, params PnLBreakdownParams) ([]PnLBreakdownRow, error)
+	// EdgeRealizationHeatmap counts settled pnl_records per strategy,
+	// market label, expected edge bucket and realized ROI bucket.
+	EdgeRealizationHeatmap(ctx context.Context, params EdgeHeatmapParams) ([]EdgeHeatmapCell, error)
 
 	// Pipeline observability (L10)
 	CountOrderbookLatest(ctx context.Context, freshWindow time.Duration) (total int64, fresh int64, err error)
@@ -196,6 +567,14 @@ type TokenJumpCandidate struct {
 	UpdatedAt    time.Time
 }
 
+// MarketActivity is a coarse volatility and volume sample of the tracked
+// tokens.
+type MarketActivity struct {
+	Tokens  int64
+	Jumping int64
+	Traded  int64
+}
+
 type EventAggregate struct {
 	EventID       string
 	MarketCount   int
@@ -237,6 +616,101 @@ type ListTokensParams struct {
 	Asc      *bool
 }
 
+type ListCatalogQuarantineParams struct {
+	Limit      int
+	Offset     int
+	IDs        []uint64
+	EntityType *string
+	EntityID   *string
+	Rule       *string
+	Status     *string
+	OrderBy    string
+	Asc        *bool
+}
+
+// ListResearchSnapshotsParams filters snapshots; Name matches a substring.
+type ListResearchSnapshotsParams struct {
+	Limit  int
+	Offset int
+	Tenant *string
+	Name   *string
+}
+
+// ListAnalyticsExportsParams filters export jobs.
+type ListAnalyticsExportsParams struct {
+	Limit  int
+	Offset int
+	Tenant *string
+}
+
+// SettledTradeParams filters settled trades on settlement time and
+// strategy. Limit 0 returns every match.
+// OnchainConditionState is a market's CTF condition as read from Polygon.
+// Payouts holds each outcome slot's share of the payout and stays empty
+// until the condition is reported.
+type OnchainConditionState struct {
+	OutcomeSlots int
+	Payouts      []decimal.Decimal
+	CheckedAt    time.Time
+}
+
+type SettledTradeParams struct {
+	Since        *time.Time
+	Until        *time.Time
+	StrategyName *string
+	Limit        int
+}
+
+// SettledTradeRow is one settled plan. MarketID is empty when the plan has
+// no primary market, MarketVolume nil when the market is not in the catalog.
+type SettledTradeRow struct {
+	PlanID       uint64
+	StrategyName string
+	MarketID     string
+	MarketVolume *float64
+	SizeUSD      float64
+	ExpectedEdge float64
+	RealizedUSD  float64
+	RealizedROI  float64
+	Outcome      string
+	SettledAt    time.Time
+}
+
+type ListCashOperationsParams struct {
+	Limit  int
+	Offset int
+	Tenant *string
+	Status *string
+	Kind   *string
+}
+
+// ListCatalogChangesParams selects journal entries after AfterID and, when
+// set, changed after Since.
+type ListCatalogChangesParams struct {
+	Limit   int
+	AfterID uint64
+	Since   *time.Time
+	Entity  *string
+}
+
+type ListMarketChangesParams struct {
+	Limit    int
+	Offset   int
+	MarketID *string
+	Field    *string
+	HeldOnly bool
+	Since    *time.Time
+	Until    *time.Time
+}
+
+type CatalogQuarantineMetricRow struct {
+	Rule       string
+	Status     string
+	Count      int64
+	Hits       int64
+	LastSeenAt *time.Time
+}
+
 type ListSignalsParams struct {
 	Limit   int
 	Offset  int
@@ -247,16 +721,177 @@ type ListSignalsParams struct {
 	Asc     *bool
 }
 
+type ListWalletPositionChangesParams struct {
+	Limit       int
+	Offset      int
+	Wallet      *string
+	MarketID    *string
+	Since       *time.Time
+	SignalsOnly bool
+}
+
+type ListMarketDataGapsParams struct {
+	Limit       int
+	Offset      int
+	TokenID     *string
+	Status      *string
+	Since       *time.Time
+	MaxAttempts int
+	// OldestFirst orders by gap start ascending (backfill queue order).
+	OldestFirst bool
+}
+
+// ListAuditRecordsParams pages audit records in chain order (seq asc).
+type ListTradesParams struct {
+	Limit    int
+	Offset   int
+	TokenIDs []string
+	Side     *string
+	Since    *time.Time
+	Until    *time.Time
+}
+
+// VolumeProfile is traded size by price level. POC (point of control) is the
+// level with the most volume; VWAP covers all trades in the window.
+type VolumeProfile struct {
+	TokenID    string        `json:"token_id"`
+	Since      time.Time     `json:"since"`
+	Bucket     float64       `json:"bucket"`
+	Trades     int           `json:"trades"`
+	Volume     float64       `json:"volume"`
+	BuyVolume  float64       `json:"buy_volume"`
+	SellVolume float64       `json:"sell_volume"`
+	VWAP       float64       `json:"vwap"`
+	POC        float64       `json:"poc"`
+	Levels     []VolumeLevel `json:"levels"`
+}
+
+type VolumeLevel struct {
+	Price      float64 `json:"price"`
+	Trades     int     `json:"trades"`
+	Volume     float64 `json:"volume"`
+	BuyVolume  float64 `json:"buy_volume"`
+	SellVolume float64 `json:"sell_volume"`
+}
+
+// ListComplianceOverridesParams filters overrides; ActiveAt keeps those in
+// force at that time.
+type ListComplianceOverridesParams struct {
+	Limit    int
+	Offset   int
+	MarketID *string
+	ActiveAt *time.Time
+}
+
+type ListCampaignsParams struct {
+	Limit  int
+	Offset int
+	Tenant *string
+	Status *string
+}
+
+type ListTradeTicketsParams struct {
+	Limit         int
+	Offset        int
+	Tenant        *string
+	State         *string
+	OpportunityID *uint64
+}
+
+// ListRewardEpochsParams filters reward epochs; Since and Until are
+// inclusive epoch dates (YYYY-MM-DD).
+type ListRewardEpochsParams struct {
+	Limit    int
+	Offset   int
+	MarketID *string
+	Since    *string
+	Until    *string
+}
+
+// CampaignStats aggregates a campaign's plans. Live plans are those not
+// cancelled, failed or failed at preflight; CommittedUSD is their planned
+// size. Pending plans have not started executing.
+type CampaignStats struct {
+	Plans          int64
+	LivePlans      int64
+	PendingPlans   int64
+	SettledPlans   int64
+	Opportunities  int64
+	CommittedUSD   float64
+	RealizedPnLUSD float64
+}
+
+// ListCatalogWebhooksParams filters webhooks; a nil Tenant spans all desks.
+type ListCatalogWebhooksParams struct {
+	Tenant      *string
+	EnabledOnly bool
+}
+
+// ListTradeWebhooksParams filters trade webhooks; a nil Tenant spans all
+// desks.
+type ListTradeWebhooksParams struct {
+	Tenant      *string
+	EnabledOnly bool
+}
+
+// ListTradeWebhookDeliveriesParams filters a webhook's delivery log, newest
+// first.
+type ListTradeWebhookDeliveriesParams struct {
+	Limit     int
+	Offset    int
+	WebhookID uint64
+	Status    *string
+	EventType *string
+}
+
+type ListAuditRecordsParams struct {
+	Limit    int
+	AfterSeq uint64
+	Since    *time.Time
+	Until    *time.Time
+}
+
+type ListEvaluationRunsParams struct {
+	Limit        int
+	Offset       int
+	StrategyName *string
+	Since        *time.Time
+	Until        *time.Time
+	ErrorsOnly   bool
+	OrderBy      string
+	Asc          *bool
+}
+
+// OpportunityDecay is the decay state the opportunity manager writes back on
+// each re-score of an active opportunity.
+type OpportunityDecay struct {
+	DecayedEdgePct    decimal.Decimal
+	DecayedConfidence float64
+	ExpiresAt         *time.Time
+	AnchorAt          *time.Time
+}
+
+// StrategyConversion is how many of a strategy's opportunities became plans.
+type StrategyConversion struct {
+	StrategyName  string
+	Opportunities int64
+	Planned       int64
+}
+
 type ListOpportunitiesParams struct {
 	Limit         int
 	Offset        int
 	Status        *string
 	StrategyName  *string
 	Category      *string
+	Tenant        *string
 	MinEdgePct    *decimal.Decimal
 	MinConfidence *float64
-	OrderBy       string
-	Asc           *bool
+	// Since and Until bound created_at (until exclusive).
+	Since   *time.Time
+	Until   *time.Time
+	OrderBy string
+	Asc     *bool
 }
 
 type ListMarketLabelsParams struct {
@@ -270,16 +905,24 @@ type ListMarketLabelsParams struct {
 }
 
 type ListExecutionPlansParams struct {
-	Limit   int
-	Offset  int
-	Status  *string
-	OrderBy string
-	Asc     *bool
+	Limit      int
+	Offset     int
+	Status     *string
+	Source     *string
+	Tenant     *string
+	CampaignID *uint64
+	// OpportunityID lists the plans created from one opportunity.
+	OpportunityID *uint64
+	// TriggerState filters on the time trigger (scheduled|fired|failed).
+	TriggerState *string
+	OrderBy      string
+	Asc          *bool
 }
 
 type ListTradeJournalParams struct {
 	Limit        int
 	Offset       int
+	Tenant       *string
 	StrategyName *string
 	Outcome      *string
 	Since        *time.Time
@@ -290,9 +933,11 @@ type ListTradeJournalParams struct {
 }
 
 type ListSystemSettingsParams struct {
-	Limit   int
-	Offset  int
-	Prefix  *string
+	Limit  int
+	Offset int
+	Prefix *string
+	// Tenant limits results to the tenant's own rows plus shared default rows.
+	Tenant  *string
 	OrderBy string
 	Asc     *bool
 }
@@ -303,6 +948,8 @@ type ListPositionsParams struct {
 	Status       *string
 	StrategyName *string
 	MarketID     *string
+	Tenant       *string
+	Source       *string
 	OrderBy      string
 	Asc          *bool
 }
@@ -314,6 +961,14 @@ type ListPortfolioSnapshotsParams struct {
 	Until  *time.Time
 }
 
+type ListPortfolioAnomaliesParams struct {
+	Limit  int
+	Offset int
+	Kind   *string
+	Since  *time.Time
+	Until  *time.Time
+}
+
 type PositionsSummary struct {
 	TotalOpen      int64
 	TotalCostBasis float64
@@ -324,23 +979,152 @@ type PositionsSummary struct {
 }
 
 type ListOrdersParams struct {
-	Limit   int
-	Offset  int
-	Status  *string
-	PlanID  *uint64
-	TokenID *string
-	OrderBy string
-	Asc     *bool
+	Limit     int
+	Offset    int
+	Tenant    *string
+	Status    *string
+	PlanID    *uint64
+	TokenID   *string
+	LineageID *uint64
+	// MergedIntoID lists the orders submitted as one book order with it.
+	MergedIntoID *uint64
+	OrderBy      string
+	Asc          *bool
 }
 
 type ListDailyStatsParams struct {
 	Limit        int
 	Offset       int
+	Tenant       *string
 	StrategyName *string
 	Since        *time.Time
 	Until        *time.Time
 }
 
+// StrategyTurnover is the gross traded notional and fees of a strategy's fills.
+type StrategyTurnover struct {
+	NotionalUSD decimal.Decimal `json:"notional_usd"`
+	FeesUSD     decimal.Decimal `json:"fees_usd"`
+	Fills       int64           `json:"fills"`
+}
+
+// ListRiskDecisionsParams filters risk decisions. OpportunityKeys matches
+// any of the keys; OpportunityID matches decisions recorded with that id.
+// When both are set a decision matching either is returned.
+type ListRiskDecisionsParams struct {
+	Limit           int
+	Offset          int
+	OpportunityID   *uint64
+	OpportunityKeys []string
+	StrategyID      *uint64
+	Tenant          *string
+	Verdict         *string
+	RejectedBy      *string
+	Since           *time.Time
+	Until           *time.Time
+}
+
+// ListMarketRegimesParams filters regime history on computation time.
+type ListMarketRegimesParams struct {
+	Limit    int
+	Offset   int
+	Category *string
+	Regime   *string
+	Since    *time.Time
+	Until    *time.Time
+}
+
+// ListOutboxMessagesParams filters outbox messages, newest first.
+type ListOutboxMessagesParams struct {
+	Limit  int
+	Offset int
+	Status *string
+	Kind   *string
+}
+
+// ListExecutorIntentsParams filters executor intents, newest first.
+type ListExecutorIntentsParams struct {
+	Limit   int
+	Offset  int
+	Tenant  *string
+	Status  *string
+	Action  *string
+	OrderID *uint64
+	PlanID  *uint64
+}
+
+// CohortOutcome is one cohort of a strategy change: its opportunities, the
+// plans made from them and the PnL of those settled. SumPnLSq lets callers
+// derive the variance without the individual records.
+type CohortOutcome struct {
+	Cohort        string
+	Opportunities int64
+	Plans         int64
+	Settled       int64
+	Wins          int64
+	SumPnL        float64
+	SumPnLSq      float64
+}
+
+// ListSLOSamplesParams filters SLO samples on evaluation time.
+type ListSLOSamplesParams struct {
+	Limit  int
+	Offset int
+	SLO    *string
+	Since  *time.Time
+	Until  *time.Time
+}
+
+// CostForecastOutcomeParams filters forecasts on their creation time, desk
+// and strategy.
+type CostForecastOutcomeParams struct {
+	Since        *time.Time
+	Until        *time.Time
+	Tenant       *string
+	StrategyName *string
+}
+
+// CostForecastOutcomeRow is a forecast with the plan's realized costs: fees
+// summed over its fills and the slippage loss on its PnL record. FilledUSD is
+// the notional of the fills.
+type CostForecastOutcomeRow struct {
+	models.CostForecast
+	FilledUSD           decimal.Decimal
+	RealizedFeesUSD     decimal.Decimal
+	RealizedSlippageUSD decimal.Decimal
+	Fills               int64
+}
+
+// CashBalance totals the cash ledger: completed and reconciled deposits and
+// withdrawals, plus approved withdrawals not yet completed.
+type CashBalance struct {
+	DepositsUSD    decimal.Decimal `json:"deposits_usd"`
+	WithdrawalsUSD decimal.Decimal `json:"withdrawals_usd"`
+	ReservedUSD    decimal.Decimal `json:"reserved_usd"`
+	// Entries counts the completed and reconciled operations.
+	Entries int64 `json:"entries"`
+}
+
+// RetentionColumns maps each table a retention policy may purge to the
+// timestamp column its rows age by.
+var RetentionColumns = map[string]string{
+	"raw_ws_events":           "received_at",
+	"raw_rest_snapshots":      "fetched_at",
+	"signals":                 "created_at",
+	"price_candles":           "bucket_start",
+	"catalog_market_changes":  "detected_at",
+	"catalog_changes":         "changed_at",
+	"wallet_position_changes": "observed_at",
+	"risk_decisions":          "created_at",
+	"slo_samples":             "evaluated_at",
+}
+
+// RetentionBacklog is what purging one table before a cutoff would delete.
+type RetentionBacklog struct {
+	Rows   int64      `json:"rows"`
+	Oldest *time.Time `json:"oldest,omitempty"`
+}
+
 type AttributionResult struct {
 	EdgeContribution float64
 	SlippageCost     float64
@@ -419,6 +1203,65 @@ type StrategyAnalyticsRow struct {
 	AvgROI       float64
 }
 
+// SignalQualityRow counts opportunities per triggering signal type and how
+// the plans executed from them settled.
+type SignalQualityRow struct {
+	SignalType    string
+	Opportunities int64
+	Settled       int64
+	Profitable    int64
+	RealizedUSD   float64
+}
+
+// PnLBreakdownParams filters breakdowns on settlement time, desk and strategy.
+// BucketHours sizes the entry time-of-day buckets (1-12, default 4).
+type PnLBreakdownParams struct {
+	Since        *time.Time
+	Until        *time.Time
+	Tenant       *string
+	StrategyName *string
+	BucketHours  int
+}
+
+type PnLBreakdownRow struct {
+	Bucket      string
+	Trades      int64
+	Wins        int64
+	Losses      int64
+	RealizedUSD float64
+	AvgROI      float64
+	WinRate     float64
+}
+
+// EdgeHeatmapParams filters the edge realization heatmap on settlement
+// time, desk, strategy and market label. EdgeBounds and ROIBounds are
+// ascending bucket boundaries: n bounds make n+1 buckets, the first and last
+// open.
+type EdgeHeatmapParams struct {
+	Since        *time.Time
+	Until        *time.Time
+	Tenant       *string
+	StrategyName *string
+	Label        *string
+	EdgeBounds   []float64
+	ROIBounds    []float64
+}
+
+// EdgeHeatmapCell aggregates the settled trades of one strategy and label
+// whose expected edge falls in bucket EdgeBucket and realized ROI in
+// ROIBucket. Trades on unlabeled markets have the label "unlabeled".
+type EdgeHeatmapCell struct {
+	StrategyName    string
+	Label           string
+	EdgeBucket      int
+	ROIBucket       int
+	Trades          int64
+	Wins            int64
+	RealizedUSD     float64
+	AvgExpectedEdge float64
+	AvgROI          float64
+}
+
 type StrategyOutcomeRow struct {
 	StrategyName string
 	WinCount     int64
@@ -438,3 +1281,13 @@ type LabelNoRateRow struct {
 	NoCount int64
 	NoRate  float64
 }
+
+// CalibrationSampleRow pairs a settled market's initial YES price with the
+// time it had left to run (catalog created_at -> settled_at).
+type CalibrationSampleRow struct {
+	MarketID  string
+	Outcome   string
+	Price     decimal.Decimal
+	OpenedAt  *time.Time
+	SettledAt time.Time
+}
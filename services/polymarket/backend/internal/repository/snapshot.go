@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"polymarket/internal/models"
+)
+
+// TokenSnapshot is a token with its latest book, data health and last trade.
+// Nil fields mean no row exists yet.
+type TokenSnapshot struct {
+	Token     models.Token             `json:"token"`
+	Orderbook *models.OrderbookLatest  `json:"orderbook"`
+	Health    *models.MarketDataHealth `json:"health"`
+	LastTrade *models.LastTradePrice   `json:"last_trade"`
+}
+
+// LoadTokenSnapshots fetches tokens with their books, health and last trades
+// in one call, preserving the order of tokenIDs and skipping unknown tokens.
+// It goes through repo, so a book cache in front of the store is honoured;
+// the store chunks each lookup, so tokenIDs may be arbitrarily long.
+func LoadTokenSnapshots(ctx context.Context, repo CatalogRepository, tokenIDs []string) ([]TokenSnapshot, error) {
+	ids := make([]string, 0, len(tokenIDs))
+	seen := map[string]bool{}
+	for _, id := range tokenIDs {
+		if id = strings.TrimSpace(id); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if repo == nil || len(ids) == 0 {
+		return []TokenSnapshot{}, nil
+	}
+	tokens, err := repo.ListTokensByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	books, err := repo.ListOrderbookLatestByTokenIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	health, err := repo.ListMarketDataHealthByTokenIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	trades, err := repo.ListLastTradePricesByTokenIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenByID := make(map[string]models.Token, len(tokens))
+	for _, t := range tokens {
+		tokenByID[t.ID] = t
+	}
+	bookByID := make(map[string]*models.OrderbookLatest, len(books))
+	for i := range books {
+		bookByID[books[i].TokenID] = &books[i]
+	}
+	healthByID := make(map[string]*models.MarketDataHealth, len(health))
+	for i := range health {
+		healthByID[health[i].TokenID] = &health[i]
+	}
+	tradeByID := make(map[string]*models.LastTradePrice, len(trades))
+	for i := range trades {
+		tradeByID[trades[i].TokenID] = &trades[i]
+	}
+	out := make([]TokenSnapshot, 0, len(ids))
+	for _, id := range ids {
+		token, ok := tokenByID[id]
+		if !ok {
+			continue
+		}
+		out = append(out, TokenSnapshot{
+			Token:     token,
+			Orderbook: bookByID[id],
+			Health:    healthByID[id],
+			LastTrade: tradeByID[id],
+		})
+	}
+	return out, nil
+}
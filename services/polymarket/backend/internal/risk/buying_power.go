@@ -0,0 +1,72 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// BuyingPower is the cash left to commit to new plans: the cash ledger
+// balance less open plan exposure and withdrawals approved but not yet paid.
+type BuyingPower struct {
+	Ledger       repository.CashBalance `json:"ledger"`
+	CashUSD      decimal.Decimal        `json:"cash_usd"`
+	ExposureUSD  decimal.Decimal        `json:"exposure_usd"`
+	AvailableUSD decimal.Decimal        `json:"available_usd"`
+}
+
+func newBuyingPower(ledger repository.CashBalance, exposure decimal.Decimal) BuyingPower {
+	cash := ledger.DepositsUSD.Sub(ledger.WithdrawalsUSD)
+	available := cash.Sub(ledger.ReservedUSD).Sub(exposure)
+	if available.IsNegative() {
+		available = decimal.Zero
+	}
+	return BuyingPower{Ledger: ledger, CashUSD: cash, ExposureUSD: exposure, AvailableUSD: available}
+}
+
+// BuyingPower reports the manager's desk (all desks when unscoped).
+func (m *Manager) BuyingPower(ctx context.Context) (BuyingPower, error) {
+	if m == nil || m.Repo == nil {
+		return BuyingPower{}, nil
+	}
+	ledger, err := m.Repo.CashBalance(ctx, m.tenantFilter())
+	if err != nil {
+		return BuyingPower{}, err
+	}
+	return newBuyingPower(ledger, m.exposures(ctx, time.Now().UTC()).Total), nil
+}
+
+func (m *Manager) tenantFilter() *string {
+	if m.Tenant == "" {
+		return nil
+	}
+	tenant := m.Tenant
+	return &tenant
+}
+
+// buyingPowerCheck compares plan with buying power, leaving the plan's own
+// size out of the exposure. ok is false while the cash ledger is empty.
+func (m *Manager) buyingPowerCheck(ctx context.Context, plan models.ExecutionPlan, now time.Time) (PreflightCheck, bool) {
+	ledger, err := m.Repo.CashBalance(ctx, m.tenantFilter())
+	if err != nil {
+		return PreflightCheck{Name: "buying_power", Status: "warn", Msg: "cash ledger unavailable: " + err.Error()}, true
+	}
+	if ledger.Entries == 0 {
+		return PreflightCheck{}, false
+	}
+	exposure := m.exposures(ctx, now).Total
+	switch plan.Status {
+	case "draft", "preflight_pass", "executing", "partial":
+		exposure = exposure.Sub(plan.PlannedSizeUSD)
+	}
+	bp := newBuyingPower(ledger, exposure)
+	if plan.PlannedSizeUSD.GreaterThan(bp.AvailableUSD) {
+		return PreflightCheck{Name: "buying_power", Status: "fail", Value: plan.PlannedSizeUSD.StringFixed(2), Msg: fmt.Sprintf("planned_size_usd exceeds buying power %s", bp.AvailableUSD.StringFixed(2))}, true
+	}
+	return PreflightCheck{Name: "buying_power", Status: "pass", Value: bp.AvailableUSD.StringFixed(2)}, true
+}
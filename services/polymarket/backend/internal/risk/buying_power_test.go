@@ -0,0 +1,25 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/repository"
+)
+
+func TestNewBuyingPower(t *testing.T) {
+	ledger := repository.CashBalance{
+		DepositsUSD:    decimal.NewFromInt(1000),
+		WithdrawalsUSD: decimal.NewFromInt(200),
+		ReservedUSD:    decimal.NewFromInt(100),
+		Entries:        3,
+	}
+	bp := newBuyingPower(ledger, decimal.NewFromInt(300))
+	if !bp.CashUSD.Equal(decimal.NewFromInt(800)) || !bp.AvailableUSD.Equal(decimal.NewFromInt(400)) {
+		t.Fatalf("cash=%s available=%s", bp.CashUSD, bp.AvailableUSD)
+	}
+	if bp := newBuyingPower(ledger, decimal.NewFromInt(900)); !bp.AvailableUSD.IsZero() {
+		t.Fatalf("available=%s want 0", bp.AvailableUSD)
+	}
+}
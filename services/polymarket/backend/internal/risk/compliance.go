@@ -0,0 +1,104 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/compliance"
+	"polymarket/internal/models"
+)
+
+// complianceResult checks every market of the batch at once. A failed lookup
+// is returned so the filter can fail closed.
+func (m *Manager) complianceResult(opps []models.Opportunity, now time.Time) (compliance.Result, error) {
+	if m.Compliance == nil {
+		return compliance.Result{}, nil
+	}
+	var ids []string
+	for _, opp := range opps {
+		ids = append(ids, compliance.OpportunityMarketIDs(opp)...)
+	}
+	res, err := m.Compliance.Check(context.Background(), ids, now)
+	if err != nil && m.Logger != nil {
+		m.Logger.Warn("risk: compliance check failed", zap.Error(err))
+	}
+	return res, err
+}
+
+// rejectCompliance returns the opportunity's restricted markets. When the
+// lookup failed every market counts as restricted.
+func (m *Manager) rejectCompliance(res compliance.Result, lookupErr error, opp models.Opportunity) []string {
+	if m.Compliance == nil {
+		return nil
+	}
+	ids := compliance.OpportunityMarketIDs(opp)
+	if lookupErr != nil {
+		return ids
+	}
+	var out []string
+	for _, id := range ids {
+		if res.IsBlocked(id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// PlanCompliance checks the markets of the plan's legs. It is the hard gate
+// behind the compliance preflight check and applies whether or not the risk
+// config requires a preflight pass.
+func (m *Manager) PlanCompliance(ctx context.Context, plan models.ExecutionPlan, now time.Time) (compliance.Result, error) {
+	if m == nil || m.Compliance == nil || m.Repo == nil {
+		return compliance.Result{}, nil
+	}
+	var legs []planLeg
+	_ = json.Unmarshal(plan.Legs, &legs)
+	tokenIDs := make([]string, 0, len(legs))
+	for _, leg := range legs {
+		if id := strings.TrimSpace(leg.TokenID); id != "" {
+			tokenIDs = append(tokenIDs, id)
+		}
+	}
+	if len(tokenIDs) == 0 {
+		return compliance.Result{}, nil
+	}
+	tokens, err := m.Repo.ListTokensByIDs(ctx, tokenIDs)
+	if err != nil {
+		return compliance.Result{}, err
+	}
+	marketIDs := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		marketIDs = append(marketIDs, t.MarketID)
+	}
+	return m.Compliance.Check(ctx, marketIDs, now)
+}
+
+// complianceCheck is the preflight form of PlanCompliance: fail for an
+// uncovered violation or a failed lookup, warn when only overrides let the
+// plan through.
+func (m *Manager) complianceCheck(ctx context.Context, plan models.ExecutionPlan, now time.Time) (PreflightCheck, bool) {
+	if m.Compliance == nil {
+		return PreflightCheck{}, false
+	}
+	res, err := m.PlanCompliance(ctx, plan, now)
+	if err != nil {
+		return PreflightCheck{Name: "compliance", Status: "fail", Msg: "compliance lookup failed: " + err.Error()}, true
+	}
+	if blocked := res.Blocked(); len(blocked) > 0 {
+		return PreflightCheck{
+			Name:   "compliance",
+			Status: "fail",
+			Value:  res.Violations,
+			Msg:    fmt.Sprintf("restricted markets: %s", strings.Join(blocked, ",")),
+		}, true
+	}
+	if len(res.Violations) > 0 {
+		return PreflightCheck{Name: "compliance", Status: "warn", Value: res.Violations, Msg: "admin override applied"}, true
+	}
+	return PreflightCheck{Name: "compliance", Status: "pass"}, true
+}
@@ -0,0 +1,104 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+)
+
+// Verdicts of a risk decision.
+const (
+	VerdictPass   = "pass"
+	VerdictReject = "reject"
+)
+
+// RiskCheck is one check Filter evaluated on an opportunity. Value is what
+// was measured and Threshold the limit it was held to; checks that are
+// disabled in the config are not evaluated and not listed.
+type RiskCheck struct {
+	Name      string  `json:"name"`
+	Passed    bool    `json:"passed"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Detail    string  `json:"detail,omitempty"`
+}
+
+// OpportunityKey is the key risk decisions are stored under: the strategy
+// plus the event or market the opportunity manager deduplicates on.
+func OpportunityKey(opp models.Opportunity) string {
+	return opportunityKey(opp)
+}
+
+// decisionLog collects one Filter batch's sampled decisions.
+type decisionLog struct {
+	m     *Manager
+	items []models.RiskDecision
+}
+
+// decisionLog returns nil when the audit is off, which records nothing.
+func (m *Manager) decisionLog() *decisionLog {
+	if m == nil || m.Repo == nil || !m.Config.DecisionAudit.Enabled {
+		return nil
+	}
+	return &decisionLog{m: m}
+}
+
+// add samples the decision on opp. rejectedBy is empty when opp passed.
+func (l *decisionLog) add(opp models.Opportunity, checks []RiskCheck, rejectedBy string) {
+	if l == nil {
+		return
+	}
+	verdict, rate := VerdictPass, l.m.Config.DecisionAudit.PassSampleRate
+	if rejectedBy != "" {
+		verdict, rate = VerdictReject, l.m.Config.DecisionAudit.RejectSampleRate
+	}
+	if rate <= 0 || (rate < 1 && l.m.sample() >= rate) {
+		return
+	}
+	if checks == nil {
+		checks = []RiskCheck{}
+	}
+	raw, _ := json.Marshal(checks)
+	item := models.RiskDecision{
+		OpportunityKey: opportunityKey(opp),
+		StrategyID:     opp.StrategyID,
+		Tenant:         strings.TrimSpace(opp.Tenant),
+		Verdict:        verdict,
+		RejectedBy:     rejectedBy,
+		Checks:         raw,
+		Warnings:       opp.Warnings,
+		EdgePct:        opp.EdgePct,
+		MaxSize:        opp.MaxSize,
+		SampleRate:     min(rate, 1),
+	}
+	if opp.ID != 0 {
+		id := opp.ID
+		item.OpportunityID = &id
+	}
+	if item.Tenant == "" {
+		item.Tenant = "default"
+	}
+	l.items = append(l.items, item)
+}
+
+// flush stores the batch. Failures are logged: the audit never blocks Filter.
+func (l *decisionLog) flush(ctx context.Context) {
+	if l == nil || len(l.items) == 0 {
+		return
+	}
+	if err := l.m.Repo.InsertRiskDecisions(ctx, l.items); err != nil && l.m.Logger != nil {
+		l.m.Logger.Warn("risk: record decisions failed", zap.Int("decisions", len(l.items)), zap.Error(err))
+	}
+}
+
+func (m *Manager) sample() float64 {
+	if m.sampleFn != nil {
+		return m.sampleFn()
+	}
+	return rand.Float64()
+}
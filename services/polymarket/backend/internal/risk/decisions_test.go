@@ -0,0 +1,80 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type decisionRepo struct {
+	repository.Repository
+	items []models.RiskDecision
+}
+
+func (r *decisionRepo) InsertRiskDecisions(ctx context.Context, items []models.RiskDecision) error {
+	r.items = append(r.items, items...)
+	return nil
+}
+
+func TestDecisionLog_SamplesByVerdict(t *testing.T) {
+	repo := &decisionRepo{}
+	draws := []float64{0.3, 0.7}
+	m := &Manager{
+		Repo: repo,
+		Config: config.RiskConfig{DecisionAudit: config.RiskDecisionAuditConfig{
+			Enabled: true, RejectSampleRate: 1, PassSampleRate: 0.5,
+		}},
+		sampleFn: func() float64 { v := draws[0]; draws = draws[1:]; return v },
+	}
+	market := "m1"
+	opp := models.Opportunity{ID: 7, StrategyID: 3, PrimaryMarketID: &market}
+	log := m.decisionLog()
+	checks := []RiskCheck{{Name: "exposure", Value: 120, Threshold: 100, Detail: "total"}}
+	log.add(opp, checks, "exposure")
+	log.add(opp, nil, "") // kept: 0.3 < 0.5
+	log.add(opp, nil, "") // dropped: 0.7 >= 0.5
+	log.flush(context.Background())
+
+	if len(repo.items) != 2 {
+		t.Fatalf("decisions=%d want 2", len(repo.items))
+	}
+	rej := repo.items[0]
+	if rej.Verdict != VerdictReject || rej.RejectedBy != "exposure" || rej.OpportunityKey != "3:m:m1" || rej.OpportunityID == nil || *rej.OpportunityID != 7 || rej.Tenant != "default" {
+		t.Fatalf("reject=%+v", rej)
+	}
+	var got []RiskCheck
+	if err := json.Unmarshal(rej.Checks, &got); err != nil || len(got) != 1 || got[0].Threshold != 100 {
+		t.Fatalf("checks=%s err=%v", rej.Checks, err)
+	}
+	if pass := repo.items[1]; pass.Verdict != VerdictPass || pass.SampleRate != 0.5 || string(pass.Checks) != "[]" {
+		t.Fatalf("pass=%+v", pass)
+	}
+	if (&Manager{Repo: repo}).decisionLog() != nil {
+		t.Fatalf("disabled audit should not log")
+	}
+}
+
+func TestCheckExposure_ReportsBreachedLimit(t *testing.T) {
+	m := &Manager{Config: config.RiskConfig{MaxTotalExposureUSD: 1000, MaxPerMarketUSD: 100}}
+	market := "m1"
+	exp := exposureSnapshot{
+		Total:    decimal.NewFromInt(200),
+		ByMarket: map[string]decimal.Decimal{"m1": decimal.NewFromInt(80)},
+	}
+	opp := models.Opportunity{PrimaryMarketID: &market, MaxSize: decimal.NewFromInt(30)}
+	check, breached := m.checkExposure(exp, nil, opp)
+	if !breached || check.Detail != "market:m1" || check.Value != 110 || check.Threshold != 100 {
+		t.Fatalf("check=%+v breached=%v", check, breached)
+	}
+	opp.MaxSize = decimal.NewFromInt(10)
+	check, breached = m.checkExposure(exp, nil, opp)
+	if breached || check.Detail != "total" || check.Value != 210 || check.Threshold != 1000 {
+		t.Fatalf("check=%+v breached=%v", check, breached)
+	}
+}
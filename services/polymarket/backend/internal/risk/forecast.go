@@ -0,0 +1,257 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// ExposureForecast is plan exposure plus the probability-weighted size of
+// active opportunities that have no plan yet.
+type ExposureForecast struct {
+	PlannedUSD          decimal.Decimal            `json:"planned_usd"`
+	PendingUSD          decimal.Decimal            `json:"pending_usd"`
+	ProjectedUSD        decimal.Decimal            `json:"projected_usd"`
+	ByStrategy          map[string]decimal.Decimal `json:"by_strategy"`
+	ByMarket            map[string]decimal.Decimal `json:"by_market"`
+	Probabilities       map[string]float64         `json:"probabilities"`
+	ActiveOpportunities int                        `json:"active_opportunities"`
+}
+
+// pendingEntry is one active opportunity's contribution to projected exposure.
+type pendingEntry struct {
+	Strategy string
+	Markets  []string
+	Size     decimal.Decimal
+}
+
+type exposureProjection struct {
+	Snapshot      exposureSnapshot
+	Pending       map[string]pendingEntry
+	Probabilities map[string]float64
+}
+
+// Forecast reports projected exposure for the manager's desk.
+func (m *Manager) Forecast(ctx context.Context) ExposureForecast {
+	if m == nil || m.Repo == nil {
+		return ExposureForecast{ByStrategy: map[string]decimal.Decimal{}, ByMarket: map[string]decimal.Decimal{}, Probabilities: map[string]float64{}}
+	}
+	now := time.Now().UTC()
+	planned := m.exposures(ctx, now)
+	proj := m.projection(ctx, now, planned, m.strategyMap())
+	return ExposureForecast{
+		PlannedUSD:          planned.Total,
+		ProjectedUSD:        proj.Snapshot.Total,
+		PendingUSD:          proj.Snapshot.Total.Sub(planned.Total),
+		ByStrategy:          proj.Snapshot.ByStrategy,
+		ByMarket:            proj.Snapshot.ByMarket,
+		Probabilities:       proj.Probabilities,
+		ActiveOpportunities: len(proj.Pending),
+	}
+}
+
+// projection adds pending opportunities to the plan exposure. It is cached
+// alongside the plan exposure snapshot.
+func (m *Manager) projection(ctx context.Context, now time.Time, planned exposureSnapshot, stratByID map[uint64]string) exposureProjection {
+	m.mu.Lock()
+	if !m.lastProjectionAt.IsZero() && now.Sub(m.lastProjectionAt) < 10*time.Second {
+		c := m.projectionCache
+		m.mu.Unlock()
+		return c
+	}
+	m.mu.Unlock()
+
+	out := exposureProjection{
+		Snapshot:      planned.clone(),
+		Pending:       map[string]pendingEntry{},
+		Probabilities: m.executionProbabilities(ctx, now),
+	}
+	active := "active"
+	params := repository.ListOpportunitiesParams{Status: &active, Limit: 500}
+	if m.Tenant != "" {
+		params.Tenant = &m.Tenant
+	}
+	for offset := 0; ; offset += 500 {
+		params.Offset = offset
+		items, err := m.Repo.ListOpportunities(ctx, params)
+		if err != nil {
+			break
+		}
+		for _, opp := range items {
+			if opp.Shadow {
+				continue
+			}
+			entry := pendingFor(opp, stratByID, out.Probabilities)
+			out.Pending[opportunityKey(opp)] = entry
+			out.Snapshot.add(entry, 1)
+		}
+		if len(items) < 500 {
+			break
+		}
+	}
+
+	m.mu.Lock()
+	m.lastProjectionAt = now
+	m.projectionCache = out
+	m.mu.Unlock()
+	return out
+}
+
+// rejectProjected checks opp against projected exposure. An opportunity that
+// is already pending (a re-emit) is checked without its own earlier weight.
+// Accepted opportunities join the projection so the rest of the batch sees
+// them.
+func (m *Manager) rejectProjected(proj *exposureProjection, stratByID map[uint64]string, opp models.Opportunity) bool {
+	_, breached := m.checkProjected(proj, stratByID, opp)
+	return breached
+}
+
+// checkProjected is rejectProjected plus the exposure check it evaluated.
+func (m *Manager) checkProjected(proj *exposureProjection, stratByID map[uint64]string, opp models.Opportunity) (RiskCheck, bool) {
+	key := opportunityKey(opp)
+	prev, pending := proj.Pending[key]
+	if pending {
+		proj.Snapshot.add(prev, -1)
+	}
+	check, breached := m.checkExposure(proj.Snapshot, stratByID, opp)
+	if breached {
+		if pending {
+			proj.Snapshot.add(prev, 1)
+		}
+		return check, true
+	}
+	entry := pendingFor(opp, stratByID, proj.Probabilities)
+	proj.Snapshot.add(entry, 1)
+	proj.Pending[key] = entry
+	return check, false
+}
+
+// executionProbabilities estimates, per strategy, the chance an opportunity
+// becomes a plan.
+func (m *Manager) executionProbabilities(ctx context.Context, now time.Time) map[string]float64 {
+	cfg := m.Config.Forecast
+	lookback := cfg.Lookback
+	if lookback <= 0 {
+		lookback = 7 * 24 * time.Hour
+	}
+	auto := map[string]bool{}
+	if rules, err := m.Repo.ListExecutionRules(ctx); err == nil {
+		for _, r := range rules {
+			auto[r.StrategyName] = r.AutoExecute
+		}
+	}
+	conv := map[string]repository.StrategyConversion{}
+	if rows, err := m.Repo.OpportunityConversionByStrategy(ctx, now.Add(-lookback), m.Tenant); err == nil {
+		for _, r := range rows {
+			conv[r.StrategyName] = r
+		}
+	}
+	out := map[string]float64{}
+	for name := range m.strategyMapNames() {
+		out[name] = executionProbability(cfg, auto[name], conv[name])
+	}
+	return out
+}
+
+// executionProbability blends the observed plan rate with a prior worth
+// MinSamples opportunities, so new strategies start at the prior and settle
+// on their own history.
+func executionProbability(cfg config.ExposureForecastConfig, autoExecute bool, conv repository.StrategyConversion) float64 {
+	prior := cfg.ManualPrior
+	if autoExecute {
+		prior = cfg.AutoExecutePrior
+	}
+	weight := float64(cfg.MinSamples)
+	if weight < 0 {
+		weight = 0
+	}
+	n := float64(conv.Opportunities)
+	if n+weight == 0 {
+		return clamp01(prior)
+	}
+	return clamp01((float64(conv.Planned) + prior*weight) / (n + weight))
+}
+
+func pendingFor(opp models.Opportunity, stratByID map[uint64]string, probs map[string]float64) pendingEntry {
+	name := stratByID[opp.StrategyID]
+	if name == "" {
+		name = opp.Strategy.Name
+	}
+	p, ok := probs[name]
+	if !ok {
+		p = 1
+	}
+	return pendingEntry{
+		Strategy: name,
+		Markets:  oppMarketIDs(opp),
+		Size:     opp.MaxSize.Mul(decimal.NewFromFloat(p)),
+	}
+}
+
+// opportunityKey matches how the opportunity manager deduplicates active
+// opportunities, so a re-emitted opportunity replaces its own pending weight.
+func opportunityKey(opp models.Opportunity) string {
+	if opp.EventID != nil && strings.TrimSpace(*opp.EventID) != "" {
+		return fmt.Sprintf("%d:e:%s", opp.StrategyID, strings.TrimSpace(*opp.EventID))
+	}
+	if opp.PrimaryMarketID != nil {
+		return fmt.Sprintf("%d:m:%s", opp.StrategyID, strings.TrimSpace(*opp.PrimaryMarketID))
+	}
+	return fmt.Sprintf("%d:id:%d", opp.StrategyID, opp.ID)
+}
+
+func (m *Manager) strategyMapNames() map[string]struct{} {
+	out := map[string]struct{}{}
+	for _, name := range m.strategyMap() {
+		out[name] = struct{}{}
+	}
+	return out
+}
+
+func (s exposureSnapshot) clone() exposureSnapshot {
+	out := exposureSnapshot{
+		Total:      s.Total,
+		ByStrategy: make(map[string]decimal.Decimal, len(s.ByStrategy)),
+		ByMarket:   make(map[string]decimal.Decimal, len(s.ByMarket)),
+	}
+	for k, v := range s.ByStrategy {
+		out.ByStrategy[k] = v
+	}
+	for k, v := range s.ByMarket {
+		out.ByMarket[k] = v
+	}
+	return out
+}
+
+// add applies sign * entry to the snapshot in place.
+func (s *exposureSnapshot) add(e pendingEntry, sign int64) {
+	size := e.Size.Mul(decimal.NewFromInt(sign))
+	s.Total = s.Total.Add(size)
+	if e.Strategy != "" {
+		s.ByStrategy[e.Strategy] = s.ByStrategy[e.Strategy].Add(size)
+	}
+	if len(e.Markets) == 0 {
+		return
+	}
+	share := size.Div(decimal.NewFromInt(int64(len(e.Markets))))
+	for _, mid := range e.Markets {
+		s.ByMarket[mid] = s.ByMarket[mid].Add(share)
+	}
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,62 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestExecutionProbability_BlendsPriorWithHistory(t *testing.T) {
+	cfg := config.ExposureForecastConfig{MinSamples: 20, AutoExecutePrior: 0.6, ManualPrior: 0.05}
+	if got := executionProbability(cfg, true, repository.StrategyConversion{}); got != 0.6 {
+		t.Fatalf("no history auto=%v", got)
+	}
+	if got := executionProbability(cfg, false, repository.StrategyConversion{}); got != 0.05 {
+		t.Fatalf("no history manual=%v", got)
+	}
+	// 80 opportunities, 20 planned: (20 + 0.05*20) / 100.
+	got := executionProbability(cfg, false, repository.StrategyConversion{Opportunities: 80, Planned: 20})
+	if got < 0.2099 || got > 0.2101 {
+		t.Fatalf("blended=%v want 0.21", got)
+	}
+}
+
+func TestRejectProjected_AccumulatesBatch(t *testing.T) {
+	m := &Manager{Config: config.RiskConfig{MaxTotalExposureUSD: 100}}
+	market := "m1"
+	other := "m2"
+	proj := exposureProjection{
+		Snapshot: exposureSnapshot{
+			Total:      decimal.NewFromInt(40),
+			ByStrategy: map[string]decimal.Decimal{},
+			ByMarket:   map[string]decimal.Decimal{},
+		},
+		Pending:       map[string]pendingEntry{},
+		Probabilities: map[string]float64{"s": 0.5},
+	}
+	stratByID := map[uint64]string{1: "s"}
+	opp := models.Opportunity{StrategyID: 1, PrimaryMarketID: &market, MaxSize: decimal.NewFromInt(50)}
+
+	if m.rejectProjected(&proj, stratByID, opp) {
+		t.Fatalf("first opportunity rejected")
+	}
+	if !proj.Snapshot.Total.Equal(decimal.NewFromInt(65)) {
+		t.Fatalf("projected=%s want 65", proj.Snapshot.Total)
+	}
+	// Re-emitting the same opportunity replaces its own weight.
+	if m.rejectProjected(&proj, stratByID, opp) || !proj.Snapshot.Total.Equal(decimal.NewFromInt(65)) {
+		t.Fatalf("re-emit projected=%s", proj.Snapshot.Total)
+	}
+	// A second opportunity would fit plan exposure alone but not the batch.
+	next := models.Opportunity{StrategyID: 1, PrimaryMarketID: &other, MaxSize: decimal.NewFromInt(50)}
+	if !m.rejectProjected(&proj, stratByID, next) {
+		t.Fatalf("batch overshoot accepted")
+	}
+	if !proj.Snapshot.Total.Equal(decimal.NewFromInt(65)) {
+		t.Fatalf("rejected opportunity changed projection: %s", proj.Snapshot.Total)
+	}
+}
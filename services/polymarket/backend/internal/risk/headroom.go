@@ -0,0 +1,36 @@
+package risk
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Headroom is what MaxTotalExposureUSD leaves after open plans on the
+// manager's desk, floored at zero. ok is false when no total limit is set.
+func (m *Manager) Headroom(ctx context.Context) (decimal.Decimal, bool) {
+	if m == nil || m.Config.MaxTotalExposureUSD <= 0 {
+		return decimal.Zero, false
+	}
+	exp := exposureSnapshot{Total: decimal.Zero}
+	if m.Repo != nil {
+		exp = m.exposures(ctx, time.Now().UTC())
+	}
+	left := decimal.NewFromFloat(m.Config.MaxTotalExposureUSD).Sub(exp.Total)
+	if left.IsNegative() {
+		left = decimal.Zero
+	}
+	return left, true
+}
+
+// InvalidateExposure drops the cached exposure snapshot so the next check
+// sees plans cancelled since.
+func (m *Manager) InvalidateExposure() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.lastExposureAt = time.Time{}
+	m.mu.Unlock()
+}
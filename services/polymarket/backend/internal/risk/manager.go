@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"strings"
 	"sync"
 	"time"
@@ -13,9 +14,12 @@ import (
 	"gorm.io/datatypes"
 
 	polymarketclob "polymarket/internal/client/polymarket/clob"
+	"polymarket/internal/compliance"
 	"polymarket/internal/config"
 	"polymarket/internal/models"
+	"polymarket/internal/regime"
 	"polymarket/internal/repository"
+	"polymarket/internal/tradingday"
 )
 
 type Manager struct {
@@ -28,36 +32,142 @@ type Manager struct {
 	lastExposureAt time.Time
 	exposureCache  exposureSnapshot
 
+	lastProjectionAt time.Time
+	projectionCache  exposureProjection
+
 	lastDailyPnLAt time.Time
 	dailyPnLCache  decimal.Decimal
 
 	lastStrategyMapAt time.Time
 	strategyNameByID  map[uint64]string
+
+	lastVaRAt time.Time
+	varCache  float64
+
+	// rateBreached remembers which rate guards are tripped so a breach is
+	// reported once rather than on every check.
+	rateBreached map[string]bool
+
+	// Calibration feeds Monte Carlo VaR; nil uses raw prices.
+	Calibration ProbabilityModel
+
+	// Compliance rejects opportunities and fails preflight for restricted
+	// markets; nil restricts nothing.
+	Compliance *compliance.Checker
+
+	// Regimes tightens limits per market category regime; nil adjusts
+	// nothing.
+	Regimes *regime.Detector
+
+	// Tenant scopes exposure and daily loss to one desk. Empty means all desks.
+	Tenant string
+
+	// Calendar sets when the daily loss window starts; nil is UTC midnight.
+	Calendar *tradingday.Calendar
+
+	tenantsMu sync.Mutex
+	tenants   map[string]*Manager
+
+	// sampleFn draws decision audit samples; nil uses math/rand.
+	sampleFn func() float64
 }
 
 // Filter applies cheap, deterministic checks. It does not mutate inputs.
 func (m *Manager) Filter(opps []models.Opportunity) []models.Opportunity {
+	out, _ := m.filter(opps)
+	return out
+}
+
+// filter is Filter plus the number of rejected opportunities per reason.
+func (m *Manager) filter(opps []models.Opportunity) ([]models.Opportunity, map[string]int) {
 	if len(opps) == 0 {
-		return nil
+		return nil, nil
 	}
 	if m == nil || m.Repo == nil {
-		return opps
+		return opps, nil
+	}
+	now := opps[0].CreatedAt
+	if now.IsZero() {
+		now = time.Now().UTC()
 	}
-	exp := m.exposures(context.Background(), opps[0].CreatedAt)
+	exp := m.exposures(context.Background(), now)
 	stratMap := m.strategyMap()
+	// The projection is copied because accepted opportunities are added to it
+	// as the batch is walked.
+	forecast := m.Config.Forecast.Enabled
+	var proj exposureProjection
+	if forecast {
+		cached := m.projection(context.Background(), now, exp, stratMap)
+		proj = exposureProjection{
+			Snapshot:      cached.Snapshot.clone(),
+			Pending:       maps.Clone(cached.Pending),
+			Probabilities: cached.Probabilities,
+		}
+	}
 	dailyLoss := m.dailyPnL()
+	varUSD, varOK := 0.0, false
+	if m.Config.VaR.MaxVaRUSD > 0 {
+		varUSD, varOK = m.currentVaR()
+	}
+	restricted, complianceErr := m.complianceResult(opps, now)
+	regimes := m.regimeResult(opps, now)
+	audit := m.decisionLog()
+	defer audit.flush(context.Background())
 	out := make([]models.Opportunity, 0, len(opps))
+	rejects := map[string]int{}
 	filtered := 0
 	for _, opp := range opps {
-		if m.rejectStale(opp) {
+		var checks []RiskCheck
+		reject := func(reason string) {
+			filtered++
+			rejects[reason]++
+			audit.add(opp, checks, reason)
+		}
+		if m.Compliance != nil {
+			blocked := m.rejectCompliance(restricted, complianceErr, opp)
+			check := RiskCheck{Name: "compliance", Passed: len(blocked) == 0, Value: float64(len(blocked)), Detail: strings.Join(blocked, ",")}
+			if complianceErr != nil {
+				check.Detail = complianceErr.Error()
+			}
+			checks = append(checks, check)
+			if len(blocked) > 0 {
+				reject("compliance")
+				if m.Logger != nil {
+					m.Logger.Debug("risk: reject compliance",
+						zap.Strings("market_ids", blocked),
+						zap.String("reasoning", opp.Reasoning),
+					)
+				}
+				continue
+			}
+		}
+		if check, adjusted, rejected, ok := m.checkRegime(regimes, stratMap, opp); ok {
+			checks = append(checks, check)
+			if rejected {
+				reject("regime")
+				if m.Logger != nil {
+					m.Logger.Debug("risk: reject regime",
+						zap.String("regime", check.Detail),
+						zap.Float64("edge_pct", check.Value),
+						zap.Float64("min_edge_pct", check.Threshold),
+						zap.String("reasoning", opp.Reasoning),
+					)
+				}
+				continue
+			}
+			opp = adjusted
+		}
+		if m.Config.MinDataFreshnessMs > 0 {
+			stale := m.rejectStale(opp)
 			action := strings.ToLower(strings.TrimSpace(m.Config.StaleDataAction))
 			if action == "" {
 				action = "block"
 			}
-			if action == "warn" {
+			checks = append(checks, RiskCheck{Name: "stale_data", Passed: !stale, Value: float64(opp.DataAgeMs), Threshold: float64(m.Config.MinDataFreshnessMs), Detail: action})
+			if stale && action == "warn" {
 				opp = appendOppWarning(opp, "stale_data")
-			} else {
-				filtered++
+			} else if stale {
+				reject("stale_data")
 				if m.Logger != nil {
 					m.Logger.Debug("risk: reject stale",
 						zap.Int("data_age_ms", opp.DataAgeMs),
@@ -68,28 +178,73 @@ func (m *Manager) Filter(opps []models.Opportunity) []models.Opportunity {
 				continue
 			}
 		}
-		if m.rejectDailyLoss(dailyLoss) {
-			filtered++
-			if m.Logger != nil {
-				m.Logger.Debug("risk: reject daily loss",
-					zap.String("daily_pnl", dailyLoss.StringFixed(2)),
-					zap.Float64("limit_usd", m.Config.MaxDailyLossUSD),
-					zap.String("reasoning", opp.Reasoning),
-				)
+		if m.Config.MaxDailyLossUSD > 0 {
+			lossHit := m.rejectDailyLoss(dailyLoss)
+			checks = append(checks, RiskCheck{Name: "daily_loss", Passed: !lossHit, Value: dailyLoss.InexactFloat64(), Threshold: -m.Config.MaxDailyLossUSD})
+			if lossHit {
+				reject("daily_loss")
+				if m.Logger != nil {
+					m.Logger.Debug("risk: reject daily loss",
+						zap.String("daily_pnl", dailyLoss.StringFixed(2)),
+						zap.Float64("limit_usd", m.Config.MaxDailyLossUSD),
+						zap.String("reasoning", opp.Reasoning),
+					)
+				}
+				continue
 			}
-			continue
 		}
-		if m.rejectExposure(exp, stratMap, opp) {
-			filtered++
-			if m.Logger != nil {
-				m.Logger.Debug("risk: reject exposure",
-					zap.String("total_exposure", exp.Total.StringFixed(2)),
-					zap.Float64("max_total_usd", m.Config.MaxTotalExposureUSD),
-					zap.String("reasoning", opp.Reasoning),
-				)
+		if m.Config.VaR.MaxVaRUSD > 0 {
+			varHit := m.rejectVaR(varUSD, varOK)
+			check := RiskCheck{Name: "var", Passed: !varHit, Value: varUSD, Threshold: m.Config.VaR.MaxVaRUSD}
+			if !varOK {
+				check.Detail = "unavailable"
+			}
+			checks = append(checks, check)
+			if varHit {
+				reject("var")
+				if m.Logger != nil {
+					m.Logger.Debug("risk: reject var",
+						zap.Float64("var_usd", varUSD),
+						zap.Float64("max_var_usd", m.Config.VaR.MaxVaRUSD),
+						zap.String("reasoning", opp.Reasoning),
+					)
+				}
+				continue
+			}
+		}
+		if m.Config.MaxTotalExposureUSD > 0 || m.Config.MaxPerStrategyUSD > 0 || m.Config.MaxPerMarketUSD > 0 {
+			check, breached := m.checkExposure(exp, stratMap, opp)
+			check.Name, check.Passed = "exposure", !breached
+			checks = append(checks, check)
+			if breached {
+				reject("exposure")
+				if m.Logger != nil {
+					m.Logger.Debug("risk: reject exposure",
+						zap.String("total_exposure", exp.Total.StringFixed(2)),
+						zap.Float64("max_total_usd", m.Config.MaxTotalExposureUSD),
+						zap.String("reasoning", opp.Reasoning),
+					)
+				}
+				continue
 			}
-			continue
 		}
+		if forecast {
+			check, breached := m.checkProjected(&proj, stratMap, opp)
+			check.Name, check.Passed = "projected_exposure", !breached
+			checks = append(checks, check)
+			if breached {
+				reject("projected_exposure")
+				if m.Logger != nil {
+					m.Logger.Debug("risk: reject projected exposure",
+						zap.String("projected_exposure", proj.Snapshot.Total.StringFixed(2)),
+						zap.Float64("max_total_usd", m.Config.MaxTotalExposureUSD),
+						zap.String("reasoning", opp.Reasoning),
+					)
+				}
+				continue
+			}
+		}
+		audit.add(opp, checks, "")
 		out = append(out, opp)
 	}
 	if m.Logger != nil && (filtered > 0 || len(opps) > 0) {
@@ -99,7 +254,7 @@ func (m *Manager) Filter(opps []models.Opportunity) []models.Opportunity {
 			zap.Int("passed", len(out)),
 		)
 	}
-	return out
+	return out, rejects
 }
 
 func appendOppWarning(opp models.Opportunity, warning string) models.Opportunity {
@@ -163,6 +318,9 @@ func (m *Manager) exposures(ctx context.Context, now time.Time) exposureSnapshot
 		ByMarket:   map[string]decimal.Decimal{},
 	}
 	for _, p := range plans {
+		if m.Tenant != "" && p.Tenant != m.Tenant {
+			continue
+		}
 		out.Total = out.Total.Add(p.PlannedSizeUSD)
 		if strings.TrimSpace(p.StrategyName) != "" {
 			out.ByStrategy[p.StrategyName] = out.ByStrategy[p.StrategyName].Add(p.PlannedSizeUSD)
@@ -194,8 +352,8 @@ func (m *Manager) dailyPnL() decimal.Decimal {
 	}
 	m.mu.Unlock()
 
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-	sum, err := m.Repo.SumRealizedPnLSince(context.Background(), dayStart)
+	dayStart := m.Calendar.Start(now)
+	sum, err := m.Repo.SumRealizedPnLSinceForTenant(context.Background(), dayStart, m.Tenant)
 	if err != nil {
 		return decimal.Zero
 	}
@@ -246,23 +404,36 @@ func (m *Manager) rejectDailyLoss(dayPnL decimal.Decimal) bool {
 }
 
 func (m *Manager) rejectExposure(exp exposureSnapshot, stratByID map[uint64]string, opp models.Opportunity) bool {
+	_, breached := m.checkExposure(exp, stratByID, opp)
+	return breached
+}
+
+// checkExposure reports the first exposure limit opp would breach: total,
+// then strategy, then each market. When none is breached the check shows the
+// total exposure with opp against the total limit.
+func (m *Manager) checkExposure(exp exposureSnapshot, stratByID map[uint64]string, opp models.Opportunity) (RiskCheck, bool) {
+	total := exp.Total.Add(opp.MaxSize)
+	check := RiskCheck{Passed: true, Value: total.InexactFloat64(), Detail: "total"}
 	if m == nil {
-		return false
+		return check, false
+	}
+	check.Threshold = m.Config.MaxTotalExposureUSD
+	breach := func(detail string, value decimal.Decimal, limit float64) (RiskCheck, bool) {
+		return RiskCheck{Value: value.InexactFloat64(), Threshold: limit, Detail: detail}, true
 	}
 	// Total exposure.
 	if m.Config.MaxTotalExposureUSD > 0 {
-		limit := decimal.NewFromFloat(m.Config.MaxTotalExposureUSD)
-		if exp.Total.Add(opp.MaxSize).GreaterThan(limit) {
-			return true
+		if total.GreaterThan(decimal.NewFromFloat(m.Config.MaxTotalExposureUSD)) {
+			return breach("total", total, m.Config.MaxTotalExposureUSD)
 		}
 	}
 	// Strategy exposure (requires StrategyID).
 	if m.Config.MaxPerStrategyUSD > 0 && opp.StrategyID != 0 {
 		name := stratByID[opp.StrategyID]
 		if strings.TrimSpace(name) != "" {
-			limit := decimal.NewFromFloat(m.Config.MaxPerStrategyUSD)
-			if exp.ByStrategy[name].Add(opp.MaxSize).GreaterThan(limit) {
-				return true
+			after := exp.ByStrategy[name].Add(opp.MaxSize)
+			if after.GreaterThan(decimal.NewFromFloat(m.Config.MaxPerStrategyUSD)) {
+				return breach("strategy:"+name, after, m.Config.MaxPerStrategyUSD)
 			}
 		}
 	}
@@ -273,13 +444,13 @@ func (m *Manager) rejectExposure(exp exposureSnapshot, stratByID map[uint64]stri
 		if len(marketIDs) > 0 {
 			share := opp.MaxSize.Div(decimal.NewFromInt(int64(len(marketIDs))))
 			for _, mid := range marketIDs {
-				if exp.ByMarket[mid].Add(share).GreaterThan(limit) {
-					return true
+				if after := exp.ByMarket[mid].Add(share); after.GreaterThan(limit) {
+					return breach("market:"+mid, after, m.Config.MaxPerMarketUSD)
 				}
 			}
 		}
 	}
-	return false
+	return check, false
 }
 
 type legMarket struct {
@@ -362,6 +533,9 @@ func (m *Manager) SuggestPlanSizing(ctx context.Context, opp models.Opportunity,
 	if m == nil {
 		return planned, planned, nil, nil
 	}
+	if scoped := m.ForTenant(opp.Tenant); scoped != m {
+		return scoped.SuggestPlanSizing(ctx, opp, strategyName)
+	}
 	k := m.defaultKellyFraction()
 	if k != nil {
 		kelly = k
@@ -485,6 +659,9 @@ func nowUTC() time.Time { return time.Now().UTC() }
 type PreflightResult struct {
 	Passed bool             `json:"passed"`
 	Checks []PreflightCheck `json:"checks"`
+
+	// legs feeds the slippage section of the risk report.
+	legs []LegSlippage
 }
 
 type PreflightCheck struct {
@@ -512,9 +689,11 @@ func (m *Manager) PreflightPlan(ctx context.Context, planID uint64) (*PreflightR
 	if plan == nil {
 		return nil, nil
 	}
-	result, status := m.preflight(ctx, *plan)
+	scoped := m.ForTenant(plan.Tenant)
+	result, status := scoped.preflight(ctx, *plan)
 	raw, _ := json.Marshal(result)
-	_ = m.Repo.UpdateExecutionPlanPreflight(ctx, planID, status, raw)
+	report, _ := json.Marshal(scoped.buildRiskReport(ctx, *plan, result, time.Now().UTC()))
+	_ = m.Repo.UpdateExecutionPlanPreflight(ctx, planID, status, raw, report)
 	return &result, nil
 }
 
@@ -544,6 +723,19 @@ func (m *Manager) preflight(ctx context.Context, plan models.ExecutionPlan) (Pre
 		return res, "preflight_fail"
 	}
 
+	if check, ok := m.complianceCheck(ctx, plan, now); ok {
+		if check.Status == "fail" {
+			res.Passed = false
+		}
+		res.Checks = append(res.Checks, check)
+	}
+	if check, ok := m.onchainResolutionCheck(ctx, tokenIDs); ok {
+		if check.Status == "fail" {
+			res.Passed = false
+		}
+		res.Checks = append(res.Checks, check)
+	}
+
 	healthRows, _ := m.Repo.ListMarketDataHealthByTokenIDs(ctx, tokenIDs)
 	bookRows, _ := m.Repo.ListOrderbookLatestByTokenIDs(ctx, tokenIDs)
 	healthByID := map[string]models.MarketDataHealth{}
@@ -640,6 +832,15 @@ func (m *Manager) preflight(ctx context.Context, plan models.ExecutionPlan) (Pre
 		}
 	}
 
+	if m.Config.BuyingPowerCheck {
+		if check, ok := m.buyingPowerCheck(ctx, plan, now); ok {
+			if check.Status == "fail" {
+				res.Passed = false
+			}
+			res.Checks = append(res.Checks, check)
+		}
+	}
+
 	// Edge/slippage re-check from latest books: ensure current best ask doesn't drift beyond tolerance from leg targets.
 	maxSlippage := 0.0
 	failedSlippage := false
@@ -664,6 +865,14 @@ func (m *Manager) preflight(ctx context.Context, plan models.ExecutionPlan) (Pre
 		if sl < 0 {
 			sl = 0
 		}
+		legReport := LegSlippage{
+			TokenID:     tokenID,
+			TargetPrice: *target,
+			BestAsk:     bestAsk.InexactFloat64(),
+			SlippagePct: sl,
+			Tolerance:   slippageTol,
+			SizeUSD:     leg.SizeUSD,
+		}
 		if sl > maxSlippage {
 			maxSlippage = sl
 		}
@@ -680,6 +889,12 @@ func (m *Manager) preflight(ctx context.Context, plan models.ExecutionPlan) (Pre
 		// Thin-book warning: if size_usd is present, check that the top ask can cover it.
 		if leg.SizeUSD != nil && *leg.SizeUSD > 0 && bestAsk.GreaterThan(decimal.Zero) {
 			needShares := decimal.NewFromFloat(*leg.SizeUSD).Div(bestAsk)
+			need := needShares.InexactFloat64()
+			legReport.NeedShares = &need
+			if bestAskSize.GreaterThan(decimal.Zero) {
+				top := bestAskSize.InexactFloat64()
+				legReport.TopAskSize = &top
+			}
 			if bestAskSize.GreaterThan(decimal.Zero) && needShares.GreaterThan(bestAskSize) {
 				res.Checks = append(res.Checks, PreflightCheck{
 					Name:   "thin_book",
@@ -689,6 +904,7 @@ func (m *Manager) preflight(ctx context.Context, plan models.ExecutionPlan) (Pre
 				})
 			}
 		}
+		res.legs = append(res.legs, legReport)
 	}
 	if !failedSlippage {
 		res.Checks = append(res.Checks, PreflightCheck{Name: "edge_recheck", Status: "pass", Value: fmt.Sprintf("%.4f", maxSlippage)})
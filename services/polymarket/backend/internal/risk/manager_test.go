@@ -6,6 +6,7 @@ import (
 	"github.com/shopspring/decimal"
 
 	"polymarket/internal/config"
+	"polymarket/internal/models"
 )
 
 func TestLimitPlannedSize_TotalExposureCap(t *testing.T) {
@@ -78,3 +79,42 @@ func TestLimitPlannedSize_MarketCap_MultiMarket(t *testing.T) {
 		t.Fatalf("warnings=%v want contains market_exposure_cap", warnings)
 	}
 }
+
+func TestTenantRiskConfig_OverridesNonZeroLimits(t *testing.T) {
+	base := config.RiskConfig{
+		MaxTotalExposureUSD: 1000,
+		MaxPerMarketUSD:     100,
+		Tenants: map[string]config.TenantRiskLimits{
+			"desk-a": {MaxTotalExposureUSD: 250},
+		},
+	}
+	got := tenantRiskConfig(base, "desk-a")
+	if got.MaxTotalExposureUSD != 250 || got.MaxPerMarketUSD != 100 {
+		t.Fatalf("got total=%v market=%v want total=250 market=100", got.MaxTotalExposureUSD, got.MaxPerMarketUSD)
+	}
+	if other := tenantRiskConfig(base, "desk-b"); other.MaxTotalExposureUSD != 1000 {
+		t.Fatalf("desk-b total=%v want=1000", other.MaxTotalExposureUSD)
+	}
+}
+
+func TestForTenant_CachesScopedManager(t *testing.T) {
+	m := &Manager{}
+	a := m.ForTenant("Desk-A")
+	if a == m || a.Tenant != "desk-a" {
+		t.Fatalf("expected scoped manager for desk-a, got tenant=%q", a.Tenant)
+	}
+	if m.ForTenant("desk-a") != a {
+		t.Fatalf("expected cached manager")
+	}
+	if m.ForTenant("") != m {
+		t.Fatalf("expected root manager for empty tenant")
+	}
+}
+
+func TestFilter_NoRepoPassesThroughWithoutRejects(t *testing.T) {
+	m := &Manager{}
+	out, rejects := m.FilterForTenantWithReasons("desk-a", []models.Opportunity{{ID: 1}})
+	if len(out) != 1 || len(rejects) != 0 {
+		t.Fatalf("out=%d rejects=%v", len(out), rejects)
+	}
+}
@@ -0,0 +1,49 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// onchainResolutionCheck fails a plan trading a market whose CTF condition
+// has been reported onchain, even while Gamma still lists it open. It is
+// skipped until some market of the plan has been checked onchain.
+func (m *Manager) onchainResolutionCheck(ctx context.Context, tokenIDs []string) (PreflightCheck, bool) {
+	tokens, err := m.Repo.ListTokensByIDs(ctx, tokenIDs)
+	if err != nil || len(tokens) == 0 {
+		return PreflightCheck{}, false
+	}
+	marketIDs := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		marketIDs = append(marketIDs, t.MarketID)
+	}
+	markets, err := m.Repo.ListMarketsByIDs(ctx, marketIDs)
+	if err != nil {
+		return PreflightCheck{}, false
+	}
+	checked := false
+	var resolved []string
+	for _, mk := range markets {
+		if mk.OnchainCheckedAt != nil {
+			checked = true
+		}
+		if mk.OnchainResolvedAt != nil {
+			resolved = append(resolved, mk.ID)
+		}
+	}
+	if !checked {
+		return PreflightCheck{}, false
+	}
+	if len(resolved) > 0 {
+		sort.Strings(resolved)
+		return PreflightCheck{
+			Name:   "onchain_resolution",
+			Status: "fail",
+			Value:  resolved,
+			Msg:    fmt.Sprintf("markets resolved onchain: %s", strings.Join(resolved, ",")),
+		}, true
+	}
+	return PreflightCheck{Name: "onchain_resolution", Status: "pass"}, true
+}
@@ -0,0 +1,135 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/paas"
+)
+
+// Global rate guards. They count across all desks.
+const (
+	LimitPlansPerHour          = "plans_per_hour"
+	LimitOpenOrders            = "open_orders"
+	LimitAutoExecutionsPerHour = "auto_executions_per_hour"
+)
+
+// RateLimitUsage is the current budget of one rate guard. Limit 0 means the
+// guard is disabled and Remaining is nil.
+type RateLimitUsage struct {
+	Name      string `json:"name"`
+	Limit     int    `json:"limit"`
+	Used      int64  `json:"used"`
+	Remaining *int64 `json:"remaining"`
+	Window    string `json:"window,omitempty"`
+	Breached  bool   `json:"breached"`
+}
+
+// RateLimitError is returned when an action would exceed a rate guard.
+type RateLimitError struct {
+	Usage  RateLimitUsage
+	Adding int
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("risk limit %s reached: used %d of %d", e.Usage.Name, e.Usage.Used, e.Usage.Limit)
+}
+
+// RateLimits reports every rate guard with its remaining budget.
+func (m *Manager) RateLimits(ctx context.Context) ([]RateLimitUsage, error) {
+	names := []string{LimitPlansPerHour, LimitAutoExecutionsPerHour, LimitOpenOrders}
+	out := make([]RateLimitUsage, 0, len(names))
+	for _, name := range names {
+		u, err := m.rateLimitUsage(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+// CheckRateLimit returns a *RateLimitError when adding more of name would
+// exceed its limit. The first breach of a guard is logged and reported to the
+// PaaS; the notification re-arms once the guard has budget again.
+func (m *Manager) CheckRateLimit(ctx context.Context, name string, adding int) error {
+	if m == nil || m.Repo == nil {
+		return nil
+	}
+	u, err := m.rateLimitUsage(ctx, name)
+	if err != nil {
+		return err
+	}
+	if adding < 1 {
+		adding = 1
+	}
+	exceeded := u.Limit > 0 && u.Used+int64(adding) > int64(u.Limit)
+	m.notifyRateLimit(ctx, u, exceeded)
+	if exceeded {
+		return &RateLimitError{Usage: u, Adding: adding}
+	}
+	return nil
+}
+
+func (m *Manager) rateLimitUsage(ctx context.Context, name string) (RateLimitUsage, error) {
+	u := RateLimitUsage{Name: name}
+	if m == nil || m.Repo == nil {
+		return u, nil
+	}
+	hourAgo := time.Now().UTC().Add(-time.Hour)
+	var err error
+	switch name {
+	case LimitPlansPerHour:
+		u.Limit, u.Window = m.Config.MaxPlansPerHour, "1h"
+		u.Used, err = m.Repo.CountExecutionPlansSince(ctx, hourAgo, nil)
+	case LimitAutoExecutionsPerHour:
+		u.Limit, u.Window = m.Config.MaxAutoExecutionsPerHour, "1h"
+		u.Used, err = m.Repo.CountExecutionPlansSince(ctx, hourAgo, boolPtr(true))
+	case LimitOpenOrders:
+		u.Limit = m.Config.MaxOpenOrders
+		u.Used, err = m.Repo.CountOpenOrders(ctx)
+	default:
+		return u, fmt.Errorf("unknown risk limit %q", name)
+	}
+	if err != nil {
+		return u, err
+	}
+	if u.Limit > 0 {
+		remaining := int64(u.Limit) - u.Used
+		if remaining < 0 {
+			remaining = 0
+		}
+		u.Remaining = &remaining
+		u.Breached = remaining == 0
+	}
+	return u, nil
+}
+
+func (m *Manager) notifyRateLimit(ctx context.Context, u RateLimitUsage, exceeded bool) {
+	m.mu.Lock()
+	if m.rateBreached == nil {
+		m.rateBreached = map[string]bool{}
+	}
+	was := m.rateBreached[u.Name]
+	m.rateBreached[u.Name] = exceeded
+	m.mu.Unlock()
+	if !exceeded || was {
+		return
+	}
+	if m.Logger != nil {
+		m.Logger.Warn("risk: rate limit breached",
+			zap.String("limit", u.Name),
+			zap.Int64("used", u.Used),
+			zap.Int("max", u.Limit),
+		)
+	}
+	paas.LogBestEffortCtx(ctx, "polymarket_risk_limit_breached", "warn", map[string]any{
+		"limit":  u.Name,
+		"used":   u.Used,
+		"max":    u.Limit,
+		"window": u.Window,
+	})
+}
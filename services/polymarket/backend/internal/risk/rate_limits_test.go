@@ -0,0 +1,60 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/repository"
+)
+
+// countRepo answers only the counts used by the rate guards.
+type countRepo struct {
+	repository.Repository
+	plans, autoPlans, openOrders int64
+}
+
+func (r *countRepo) CountExecutionPlansSince(ctx context.Context, since time.Time, autoExecuted *bool) (int64, error) {
+	if autoExecuted != nil && *autoExecuted {
+		return r.autoPlans, nil
+	}
+	return r.plans, nil
+}
+
+func (r *countRepo) CountOpenOrders(ctx context.Context) (int64, error) { return r.openOrders, nil }
+
+func TestCheckRateLimit(t *testing.T) {
+	repo := &countRepo{plans: 9, autoPlans: 2, openOrders: 8}
+	m := &Manager{Repo: repo, Config: config.RiskConfig{MaxPlansPerHour: 10, MaxOpenOrders: 10}}
+	ctx := context.Background()
+
+	if err := m.CheckRateLimit(ctx, LimitPlansPerHour, 1); err != nil {
+		t.Fatalf("plans within budget: %v", err)
+	}
+	var limitErr *RateLimitError
+	if err := m.CheckRateLimit(ctx, LimitOpenOrders, 3); !errors.As(err, &limitErr) || limitErr.Usage.Name != LimitOpenOrders {
+		t.Fatalf("open orders err=%v", err)
+	}
+	if err := m.CheckRateLimit(ctx, LimitAutoExecutionsPerHour, 100); err != nil {
+		t.Fatalf("disabled guard blocked: %v", err)
+	}
+
+	limits, err := m.RateLimits(ctx)
+	if err != nil {
+		t.Fatalf("limits: %v", err)
+	}
+	for _, u := range limits {
+		switch u.Name {
+		case LimitPlansPerHour:
+			if u.Remaining == nil || *u.Remaining != 1 || u.Breached {
+				t.Fatalf("plans=%+v", u)
+			}
+		case LimitAutoExecutionsPerHour:
+			if u.Remaining != nil || u.Used != 2 {
+				t.Fatalf("auto=%+v", u)
+			}
+		}
+	}
+}
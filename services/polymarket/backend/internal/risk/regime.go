@@ -0,0 +1,71 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/compliance"
+	"polymarket/internal/models"
+)
+
+// regimeResult looks up the regime of every market of the batch at once.
+// Regimes only tighten limits, so a failed lookup is logged and the batch
+// is filtered without them.
+func (m *Manager) regimeResult(opps []models.Opportunity, now time.Time) map[string]models.MarketRegime {
+	if m.Regimes == nil {
+		return nil
+	}
+	var ids []string
+	for _, opp := range opps {
+		ids = append(ids, compliance.OpportunityMarketIDs(opp)...)
+	}
+	res, err := m.Regimes.ForMarkets(context.Background(), ids, now)
+	if err != nil {
+		if m.Logger != nil {
+			m.Logger.Warn("risk: regime lookup failed", zap.Error(err))
+		}
+		return nil
+	}
+	return res
+}
+
+// checkRegime applies the regime adjustment of the opportunity's first
+// market with a known regime. ok is false when no adjustment applies. The
+// returned opportunity has its max size scaled when the adjustment says so.
+func (m *Manager) checkRegime(regimes map[string]models.MarketRegime, stratByID map[uint64]string, opp models.Opportunity) (check RiskCheck, out models.Opportunity, reject, ok bool) {
+	out = opp
+	var current models.MarketRegime
+	for _, id := range compliance.OpportunityMarketIDs(opp) {
+		if r, found := regimes[id]; found {
+			current = r
+			break
+		}
+	}
+	if current.Regime == "" {
+		return RiskCheck{}, out, false, false
+	}
+	adj, found := m.Regimes.Adjustment(stratByID[opp.StrategyID], current.Regime)
+	if !found {
+		return RiskCheck{}, out, false, false
+	}
+	edge := opp.CurrentEdgePct().InexactFloat64()
+	check = RiskCheck{
+		Name:      "regime",
+		Value:     edge,
+		Threshold: adj.MinEdgePct,
+		Detail:    fmt.Sprintf("%s:%s", current.Category, current.Regime),
+	}
+	if adj.Block || (adj.MinEdgePct > 0 && edge < adj.MinEdgePct) {
+		return check, out, true, true
+	}
+	check.Passed = true
+	if adj.SizeMultiplier > 0 && adj.SizeMultiplier != 1 {
+		out = appendOppWarning(out, "regime_"+current.Regime)
+		out.MaxSize = opp.MaxSize.Mul(decimal.NewFromFloat(adj.SizeMultiplier))
+	}
+	return check, out, false, true
+}
@@ -0,0 +1,39 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/regime"
+)
+
+func TestCheckRegime_MinEdgeAndSizing(t *testing.T) {
+	m := &Manager{Regimes: &regime.Detector{Config: config.RegimeConfig{
+		Adjustments: map[string]config.RegimeAdjustment{regime.NewsDriven: {MinEdgePct: 0.03, SizeMultiplier: 0.5}},
+	}}}
+	market := "m1"
+	regimes := map[string]models.MarketRegime{"m1": {Category: "politics", Regime: regime.NewsDriven}}
+	opp := models.Opportunity{StrategyID: 1, PrimaryMarketID: &market, EdgePct: decimal.NewFromFloat(0.02), MaxSize: decimal.NewFromInt(100)}
+
+	check, _, rejected, ok := m.checkRegime(regimes, nil, opp)
+	if !ok || !rejected || check.Detail != "politics:news_driven" || check.Threshold != 0.03 {
+		t.Fatalf("check=%+v rejected=%v ok=%v", check, rejected, ok)
+	}
+
+	opp.EdgePct = decimal.NewFromFloat(0.05)
+	check, out, rejected, ok := m.checkRegime(regimes, nil, opp)
+	if !ok || rejected || !check.Passed || !out.MaxSize.Equal(decimal.NewFromInt(50)) || string(out.Warnings) != `["regime_news_driven"]` {
+		t.Fatalf("check=%+v out=%+v", check, out)
+	}
+	if !opp.MaxSize.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("input mutated")
+	}
+
+	regimes["m1"] = models.MarketRegime{Category: "politics", Regime: regime.Choppy}
+	if _, _, _, ok := m.checkRegime(regimes, nil, opp); ok {
+		t.Fatalf("choppy has no adjustment")
+	}
+}
@@ -0,0 +1,239 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+// RiskReport summarizes a plan's preflight for audit and approval review. It
+// is stored on the plan with a rendered markdown copy.
+type RiskReport struct {
+	PlanID       uint64          `json:"plan_id"`
+	StrategyName string          `json:"strategy_name"`
+	Tenant       string          `json:"tenant"`
+	GeneratedAt  time.Time       `json:"generated_at"`
+	Passed       bool            `json:"passed"`
+	Exposure     ExposureDeltas  `json:"exposure"`
+	Sizing       SizingRationale `json:"sizing"`
+	Slippage     []LegSlippage   `json:"slippage"`
+	Warnings     []string        `json:"warnings"`
+	Markdown     string          `json:"markdown"`
+}
+
+// ExposureDelta is exposure without and with the plan, against its limit
+// (zero when the limit is off).
+type ExposureDelta struct {
+	Scope  string          `json:"scope"`
+	Key    string          `json:"key,omitempty"`
+	Before decimal.Decimal `json:"before"`
+	After  decimal.Decimal `json:"after"`
+	Limit  decimal.Decimal `json:"limit"`
+}
+
+type ExposureDeltas struct {
+	Total    ExposureDelta   `json:"total"`
+	Strategy ExposureDelta   `json:"strategy"`
+	Markets  []ExposureDelta `json:"markets"`
+}
+
+type SizingRationale struct {
+	RequestedUSD   decimal.Decimal  `json:"requested_usd"`
+	PlannedSizeUSD decimal.Decimal  `json:"planned_size_usd"`
+	MaxLossUSD     decimal.Decimal  `json:"max_loss_usd"`
+	KellyFraction  *float64         `json:"kelly_fraction,omitempty"`
+	KellyCapUSD    *decimal.Decimal `json:"kelly_cap_usd,omitempty"`
+	Confidence     *float64         `json:"confidence,omitempty"`
+	EdgePct        *decimal.Decimal `json:"edge_pct,omitempty"`
+	EdgeUSD        *decimal.Decimal `json:"edge_usd,omitempty"`
+	Notes          []string         `json:"notes"`
+}
+
+// LegSlippage is the drift of a leg's best ask from its target price.
+type LegSlippage struct {
+	TokenID     string   `json:"token_id"`
+	TargetPrice float64  `json:"target_price"`
+	BestAsk     float64  `json:"best_ask"`
+	SlippagePct float64  `json:"slippage_pct"`
+	Tolerance   float64  `json:"tolerance"`
+	SizeUSD     *float64 `json:"size_usd,omitempty"`
+	NeedShares  *float64 `json:"need_shares,omitempty"`
+	TopAskSize  *float64 `json:"top_ask_size,omitempty"`
+}
+
+// buildRiskReport assembles the report for a preflighted plan.
+func (m *Manager) buildRiskReport(ctx context.Context, plan models.ExecutionPlan, res PreflightResult, now time.Time) RiskReport {
+	rep := RiskReport{
+		PlanID:       plan.ID,
+		StrategyName: plan.StrategyName,
+		Tenant:       plan.Tenant,
+		GeneratedAt:  now,
+		Passed:       res.Passed,
+		Slippage:     res.legs,
+		Warnings:     []string{},
+	}
+	if rep.Slippage == nil {
+		rep.Slippage = []LegSlippage{}
+	}
+
+	exp := m.exposures(ctx, now)
+	before := exp.clone()
+	if planCountsTowardExposure(plan.Status) {
+		before.add(planEntry(plan), -1)
+	}
+	after := before.clone()
+	after.add(planEntry(plan), 1)
+
+	rep.Exposure.Total = ExposureDelta{Scope: "total", Before: before.Total, After: after.Total, Limit: decimal.NewFromFloat(m.Config.MaxTotalExposureUSD)}
+	rep.Exposure.Strategy = ExposureDelta{
+		Scope:  "strategy",
+		Key:    plan.StrategyName,
+		Before: before.ByStrategy[plan.StrategyName],
+		After:  after.ByStrategy[plan.StrategyName],
+		Limit:  decimal.NewFromFloat(m.Config.MaxPerStrategyUSD),
+	}
+	rep.Exposure.Markets = []ExposureDelta{}
+	for _, mid := range planMarketIDs(plan.Legs) {
+		rep.Exposure.Markets = append(rep.Exposure.Markets, ExposureDelta{
+			Scope:  "market",
+			Key:    mid,
+			Before: before.ByMarket[mid],
+			After:  after.ByMarket[mid],
+			Limit:  decimal.NewFromFloat(m.Config.MaxPerMarketUSD),
+		})
+	}
+
+	rep.Sizing = m.sizingRationale(ctx, plan, before)
+
+	for _, chk := range res.Checks {
+		if chk.Status != "warn" && chk.Status != "fail" {
+			continue
+		}
+		w := chk.Status + ": " + chk.Name
+		if chk.Msg != "" {
+			w += " (" + chk.Msg + ")"
+		}
+		rep.Warnings = append(rep.Warnings, w)
+	}
+	for _, d := range append([]ExposureDelta{rep.Exposure.Total, rep.Exposure.Strategy}, rep.Exposure.Markets...) {
+		if d.Limit.GreaterThan(decimal.Zero) && d.After.GreaterThan(d.Limit) {
+			rep.Warnings = append(rep.Warnings, fmt.Sprintf("limit: %s exposure %s over %s", d.label(), d.After.StringFixed(2), d.Limit.StringFixed(2)))
+		}
+	}
+
+	rep.Markdown = rep.render()
+	return rep
+}
+
+// sizingRationale explains the planned size from the opportunity's size, the
+// Kelly cap and the remaining capacity before this plan.
+func (m *Manager) sizingRationale(ctx context.Context, plan models.ExecutionPlan, before exposureSnapshot) SizingRationale {
+	out := SizingRationale{
+		RequestedUSD:   plan.PlannedSizeUSD,
+		PlannedSizeUSD: plan.PlannedSizeUSD,
+		MaxLossUSD:     plan.MaxLossUSD,
+		KellyFraction:  plan.KellyFraction,
+		Notes:          []string{},
+	}
+	if plan.OpportunityID != 0 {
+		if opp, err := m.Repo.GetOpportunityByID(ctx, plan.OpportunityID); err == nil && opp != nil {
+			out.RequestedUSD = opp.MaxSize
+			conf := opp.Confidence
+			out.Confidence = &conf
+			edgePct, edgeUSD := opp.EdgePct, opp.EdgeUSD
+			out.EdgePct = &edgePct
+			out.EdgeUSD = &edgeUSD
+			out.Notes = append(out.Notes, fmt.Sprintf("opportunity %d offered up to $%s", opp.ID, opp.MaxSize.StringFixed(2)))
+		}
+	} else {
+		out.Notes = append(out.Notes, "manual plan; size set by the operator")
+	}
+	if plan.KellyFraction != nil && m.Config.MaxTotalExposureUSD > 0 {
+		kellyCap := decimal.NewFromFloat(m.Config.MaxTotalExposureUSD).Mul(decimal.NewFromFloat(*plan.KellyFraction))
+		out.KellyCapUSD = &kellyCap
+		out.Notes = append(out.Notes, fmt.Sprintf("kelly %.4f of $%.2f capital caps size at $%s", *plan.KellyFraction, m.Config.MaxTotalExposureUSD, kellyCap.StringFixed(2)))
+	}
+	_, caps := limitPlannedSize(m.Config, before, plan.StrategyName, planMarketIDs(plan.Legs), out.RequestedUSD)
+	for _, c := range caps {
+		out.Notes = append(out.Notes, "capped by "+c)
+	}
+	if plan.PlannedSizeUSD.LessThan(out.RequestedUSD) {
+		out.Notes = append(out.Notes, fmt.Sprintf("planned $%s of $%s requested", plan.PlannedSizeUSD.StringFixed(2), out.RequestedUSD.StringFixed(2)))
+	}
+	return out
+}
+
+func (r RiskReport) render() string {
+	var b strings.Builder
+	verdict := "PASS"
+	if !r.Passed {
+		verdict = "FAIL"
+	}
+	fmt.Fprintf(&b, "# Risk report: plan %d (%s)\n\n", r.PlanID, verdict)
+	fmt.Fprintf(&b, "- Strategy: %s\n- Tenant: %s\n- Generated: %s\n\n", r.StrategyName, r.Tenant, r.GeneratedAt.Format(time.RFC3339))
+
+	b.WriteString("## Exposure\n\n| Scope | Before | After | Limit |\n|---|---:|---:|---:|\n")
+	for _, d := range append([]ExposureDelta{r.Exposure.Total, r.Exposure.Strategy}, r.Exposure.Markets...) {
+		limit := "-"
+		if d.Limit.GreaterThan(decimal.Zero) {
+			limit = d.Limit.StringFixed(2)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", d.label(), d.Before.StringFixed(2), d.After.StringFixed(2), limit)
+	}
+
+	b.WriteString("\n## Sizing\n\n")
+	fmt.Fprintf(&b, "- Planned size: $%s (max loss $%s)\n", r.Sizing.PlannedSizeUSD.StringFixed(2), r.Sizing.MaxLossUSD.StringFixed(2))
+	if r.Sizing.Confidence != nil {
+		fmt.Fprintf(&b, "- Confidence: %.2f\n", *r.Sizing.Confidence)
+	}
+	if r.Sizing.EdgePct != nil && r.Sizing.EdgeUSD != nil {
+		fmt.Fprintf(&b, "- Edge: %s%% ($%s)\n", r.Sizing.EdgePct.Mul(decimal.NewFromInt(100)).StringFixed(2), r.Sizing.EdgeUSD.StringFixed(2))
+	}
+	for _, n := range r.Sizing.Notes {
+		fmt.Fprintf(&b, "- %s\n", n)
+	}
+
+	b.WriteString("\n## Slippage\n\n")
+	if len(r.Slippage) == 0 {
+		b.WriteString("No book data for the plan legs.\n")
+	} else {
+		b.WriteString("| Token | Target | Best ask | Slippage | Tolerance |\n|---|---:|---:|---:|---:|\n")
+		for _, l := range r.Slippage {
+			fmt.Fprintf(&b, "| %s | %.4f | %.4f | %.2f%% | %.2f%% |\n", l.TokenID, l.TargetPrice, l.BestAsk, l.SlippagePct*100, l.Tolerance*100)
+		}
+	}
+
+	b.WriteString("\n## Warnings\n\n")
+	if len(r.Warnings) == 0 {
+		b.WriteString("None.\n")
+	}
+	for _, w := range r.Warnings {
+		fmt.Fprintf(&b, "- %s\n", w)
+	}
+	return b.String()
+}
+
+func (d ExposureDelta) label() string {
+	if d.Key == "" {
+		return d.Scope
+	}
+	return d.Scope + " " + d.Key
+}
+
+// planCountsTowardExposure mirrors the statuses exposures() sums.
+func planCountsTowardExposure(status string) bool {
+	switch status {
+	case "draft", "preflight_pass", "executing", "partial":
+		return true
+	}
+	return false
+}
+
+func planEntry(plan models.ExecutionPlan) pendingEntry {
+	return pendingEntry{Strategy: plan.StrategyName, Markets: planMarketIDs(plan.Legs), Size: plan.PlannedSizeUSD}
+}
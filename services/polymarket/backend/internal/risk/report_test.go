@@ -0,0 +1,84 @@
+package risk
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type reportRepo struct {
+	repository.Repository
+	plans []models.ExecutionPlan
+	opp   *models.Opportunity
+}
+
+func (r *reportRepo) ListExecutionPlansByStatuses(_ context.Context, _ []string, _ int) ([]models.ExecutionPlan, error) {
+	return r.plans, nil
+}
+
+func (r *reportRepo) GetOpportunityByID(_ context.Context, _ uint64) (*models.Opportunity, error) {
+	return r.opp, nil
+}
+
+func TestBuildRiskReport_ExposureDeltasAndSizing(t *testing.T) {
+	kelly := 0.05
+	plan := models.ExecutionPlan{
+		ID:             7,
+		OpportunityID:  3,
+		Status:         "draft",
+		StrategyName:   "arb_sum",
+		PlannedSizeUSD: decimal.NewFromInt(100),
+		MaxLossUSD:     decimal.NewFromInt(100),
+		KellyFraction:  &kelly,
+		Legs:           datatypes.JSON(`[{"market_id":"m1","token_id":"t1"}]`),
+	}
+	other := models.ExecutionPlan{
+		ID:             8,
+		Status:         "executing",
+		StrategyName:   "arb_sum",
+		PlannedSizeUSD: decimal.NewFromInt(450),
+		Legs:           datatypes.JSON(`[{"market_id":"m1","token_id":"t1"}]`),
+	}
+	repo := &reportRepo{
+		plans: []models.ExecutionPlan{plan, other},
+		opp:   &models.Opportunity{ID: 3, MaxSize: decimal.NewFromInt(300), Confidence: 0.9, EdgePct: decimal.RequireFromString("0.04")},
+	}
+	m := &Manager{Repo: repo, Config: config.RiskConfig{MaxTotalExposureUSD: 2000, MaxPerMarketUSD: 500}}
+	res := PreflightResult{
+		Passed: true,
+		Checks: []PreflightCheck{{Name: "spread", Status: "warn", Msg: "t1"}},
+		legs:   []LegSlippage{{TokenID: "t1", TargetPrice: 0.5, BestAsk: 0.505, SlippagePct: 0.01, Tolerance: 0.02}},
+	}
+
+	rep := m.buildRiskReport(context.Background(), plan, res, time.Now().UTC())
+	if !rep.Exposure.Total.Before.Equal(decimal.NewFromInt(450)) || !rep.Exposure.Total.After.Equal(decimal.NewFromInt(550)) {
+		t.Fatalf("total delta %+v", rep.Exposure.Total)
+	}
+	if len(rep.Exposure.Markets) != 1 || !rep.Exposure.Markets[0].After.Equal(decimal.NewFromInt(550)) {
+		t.Fatalf("market delta %+v", rep.Exposure.Markets)
+	}
+	if !rep.Sizing.RequestedUSD.Equal(decimal.NewFromInt(300)) || rep.Sizing.KellyCapUSD == nil || !rep.Sizing.KellyCapUSD.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("sizing %+v", rep.Sizing)
+	}
+	joined := strings.Join(rep.Sizing.Notes, "; ")
+	if !strings.Contains(joined, "capped by market_exposure_cap") {
+		t.Fatalf("notes %q", joined)
+	}
+	// The spread warning and the market limit breach are both reported.
+	if len(rep.Warnings) != 2 || !strings.HasPrefix(rep.Warnings[1], "limit: market m1") {
+		t.Fatalf("warnings %v", rep.Warnings)
+	}
+	for _, want := range []string{"# Risk report: plan 7 (PASS)", "| market m1 | 450.00 | 550.00 | 500.00 |", "| t1 | 0.5000 | 0.5050 | 1.00% | 2.00% |"} {
+		if !strings.Contains(rep.Markdown, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, rep.Markdown)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package risk
+
+import (
+	"strings"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+)
+
+// ForTenant returns the risk manager for one desk. Managers are created lazily,
+// share the repository, and keep their own exposure caches so one desk cannot
+// consume another desk's limits.
+func (m *Manager) ForTenant(tenant string) *Manager {
+	tenant = strings.ToLower(strings.TrimSpace(tenant))
+	if m == nil || tenant == "" || tenant == m.Tenant {
+		return m
+	}
+	m.tenantsMu.Lock()
+	defer m.tenantsMu.Unlock()
+	if m.tenants == nil {
+		m.tenants = map[string]*Manager{}
+	}
+	if scoped, ok := m.tenants[tenant]; ok {
+		return scoped
+	}
+	scoped := &Manager{
+		Config: tenantRiskConfig(m.Config, tenant),
+		Repo:   m.Repo,
+		Logger: m.Logger,
+		Tenant: tenant,
+
+		Calibration: m.Calibration,
+		Compliance:  m.Compliance,
+		Regimes:     m.Regimes,
+		Calendar:    m.Calendar,
+	}
+	m.tenants[tenant] = scoped
+	return scoped
+}
+
+// FilterForTenant applies Filter with the desk's limits and exposure.
+func (m *Manager) FilterForTenant(tenant string, opps []models.Opportunity) []models.Opportunity {
+	return m.ForTenant(tenant).Filter(opps)
+}
+
+// FilterForTenantWithReasons is FilterForTenant plus rejected counts by reason
+// (stale_data, daily_loss, var, exposure) for evaluation run records.
+func (m *Manager) FilterForTenantWithReasons(tenant string, opps []models.Opportunity) ([]models.Opportunity, map[string]int) {
+	return m.ForTenant(tenant).filter(opps)
+}
+
+func tenantRiskConfig(base config.RiskConfig, tenant string) config.RiskConfig {
+	out := base
+	limits, ok := base.Tenants[tenant]
+	if !ok {
+		return out
+	}
+	if limits.MaxTotalExposureUSD > 0 {
+		out.MaxTotalExposureUSD = limits.MaxTotalExposureUSD
+	}
+	if limits.MaxPerMarketUSD > 0 {
+		out.MaxPerMarketUSD = limits.MaxPerMarketUSD
+	}
+	if limits.MaxPerStrategyUSD > 0 {
+		out.MaxPerStrategyUSD = limits.MaxPerStrategyUSD
+	}
+	if limits.MaxDailyLossUSD > 0 {
+		out.MaxDailyLossUSD = limits.MaxDailyLossUSD
+	}
+	return out
+}
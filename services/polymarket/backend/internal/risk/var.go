@@ -0,0 +1,309 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+const (
+	VaRMethodHistorical = "historical"
+	VaRMethodMonteCarlo = "monte_carlo"
+
+	// Fewer daily returns than this makes historical VaR meaningless, so the
+	// computation falls back to Monte Carlo.
+	minHistoricalVaRSamples = 10
+	varCacheTTL             = 5 * time.Minute
+)
+
+// ProbabilityModel converts a YES price into a settlement probability.
+// service.CalibrationService implements it.
+type ProbabilityModel interface {
+	Probability(ctx context.Context, price float64, tte time.Duration) float64
+}
+
+// VaRResult is a loss estimate in USD; both figures are positive losses.
+type VaRResult struct {
+	Method               string    `json:"method"`
+	Confidence           float64   `json:"confidence"`
+	HorizonDays          int       `json:"horizon_days"`
+	VaRUSD               float64   `json:"var_usd"`
+	ExpectedShortfallUSD float64   `json:"expected_shortfall_usd"`
+	ExposureUSD          float64   `json:"exposure_usd"`
+	Samples              int       `json:"samples"`
+	Positions            int       `json:"positions"`
+	Warnings             []string  `json:"warnings,omitempty"`
+	ComputedAt           time.Time `json:"computed_at"`
+}
+
+// VaROptions overrides the configured method and confidence for one request.
+type VaROptions struct {
+	Method     string
+	Confidence float64
+}
+
+// LossQuantiles returns VaR and expected shortfall at confidence for a P&L
+// series (gains positive). Both are reported as non-negative losses.
+func LossQuantiles(pnl []float64, confidence float64) (float64, float64) {
+	if len(pnl) == 0 {
+		return 0, 0
+	}
+	losses := make([]float64, len(pnl))
+	for i, v := range pnl {
+		losses[i] = -v
+	}
+	sort.Float64s(losses)
+	idx := int(math.Ceil(confidence*float64(len(losses)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(losses) {
+		idx = len(losses) - 1
+	}
+	varLoss := losses[idx]
+	tail := losses[idx:]
+	sum := 0.0
+	for _, v := range tail {
+		sum += v
+	}
+	es := sum / float64(len(tail))
+	return math.Max(varLoss, 0), math.Max(es, 0)
+}
+
+// DailyPortfolioReturns turns snapshots (any order) into daily returns on the
+// capital deployed at the previous close: the change in total PnL divided by
+// the previous day's cost basis. Days without deployed capital are skipped.
+func DailyPortfolioReturns(snapshots []models.PortfolioSnapshot) []float64 {
+	if len(snapshots) < 2 {
+		return nil
+	}
+	items := append([]models.PortfolioSnapshot(nil), snapshots...)
+	sort.Slice(items, func(i, j int) bool { return items[i].SnapshotAt.Before(items[j].SnapshotAt) })
+	var closes []models.PortfolioSnapshot
+	for _, snap := range items {
+		day := snap.SnapshotAt.UTC().Truncate(24 * time.Hour)
+		if n := len(closes); n > 0 && closes[n-1].SnapshotAt.UTC().Truncate(24*time.Hour).Equal(day) {
+			closes[n-1] = snap
+			continue
+		}
+		closes = append(closes, snap)
+	}
+	out := make([]float64, 0, len(closes))
+	for i := 1; i < len(closes); i++ {
+		prev, cur := closes[i-1], closes[i]
+		base := prev.TotalCostBasis.InexactFloat64()
+		if base <= 0 {
+			continue
+		}
+		prevPnL := prev.RealizedPnL.Add(prev.UnrealizedPnL).InexactFloat64()
+		curPnL := cur.RealizedPnL.Add(cur.UnrealizedPnL).InexactFloat64()
+		out = append(out, (curPnL-prevPnL)/base)
+	}
+	return out
+}
+
+type varMarket struct {
+	probYes float64
+	weight  float64
+	// payoff P&L when YES / NO settles, summed over the market's positions.
+	pnlYes float64
+	pnlNo  float64
+}
+
+// SimulateSettlementPnL draws portfolio P&L at settlement. Positions in the
+// same binary market share one draw, so YES and NO holdings offset each
+// other. Each outcome of a non-binary market is drawn as its own YES/NO
+// market, which ignores that only one of them can win.
+func SimulateSettlementPnL(ctx context.Context, positions []models.Position, probs ProbabilityModel, sims int, seed int64) []float64 {
+	markets := map[string]*varMarket{}
+	order := []string{}
+	for _, p := range positions {
+		qty := p.Quantity.InexactFloat64()
+		price := p.CurrentPrice.InexactFloat64()
+		if qty == 0 || price <= 0 || price >= 1 {
+			continue
+		}
+		yesPrice := price
+		if strings.EqualFold(p.Direction, "NO") {
+			yesPrice = 1 - price
+		}
+		probYes := yesPrice
+		if probs != nil {
+			probYes = probs.Probability(ctx, yesPrice, 0)
+		}
+		key := p.MarketID
+		if key == "" {
+			key = p.TokenID
+		}
+		if code := models.OutcomeCode(p.Direction); code != "" && code != models.OutcomeYes && code != models.OutcomeNo {
+			key += ":" + code
+		}
+		m := markets[key]
+		if m == nil {
+			m = &varMarket{}
+			markets[key] = m
+			order = append(order, key)
+		}
+		w := math.Abs(qty)
+		m.probYes = (m.probYes*m.weight + probYes*w) / (m.weight + w)
+		m.weight += w
+		if strings.EqualFold(p.Direction, "NO") {
+			m.pnlYes += qty * (0 - price)
+			m.pnlNo += qty * (1 - price)
+		} else {
+			m.pnlYes += qty * (1 - price)
+			m.pnlNo += qty * (0 - price)
+		}
+	}
+	if len(order) == 0 || sims <= 0 {
+		return nil
+	}
+	rng := rand.New(rand.NewSource(seed))
+	out := make([]float64, sims)
+	for i := range out {
+		total := 0.0
+		for _, key := range order {
+			m := markets[key]
+			if rng.Float64() < m.probYes {
+				total += m.pnlYes
+			} else {
+				total += m.pnlNo
+			}
+		}
+		out[i] = total
+	}
+	return out
+}
+
+// ComputeVaR estimates 1-day VaR and expected shortfall for the manager's desk.
+// Historical VaR scales daily portfolio returns by current exposure; Monte Carlo
+// settles open positions at calibrated probabilities (a loss-to-settlement bound).
+func (m *Manager) ComputeVaR(ctx context.Context, opts VaROptions) (VaRResult, error) {
+	if m == nil || m.Repo == nil {
+		return VaRResult{}, fmt.Errorf("repo unavailable")
+	}
+	cfg := m.Config.VaR
+	method := strings.ToLower(strings.TrimSpace(opts.Method))
+	if method == "" {
+		method = strings.ToLower(strings.TrimSpace(cfg.Method))
+	}
+	if method == "" {
+		method = VaRMethodHistorical
+	}
+	if method != VaRMethodHistorical && method != VaRMethodMonteCarlo {
+		return VaRResult{}, fmt.Errorf("unknown var method: %s", method)
+	}
+	confidence := opts.Confidence
+	if confidence <= 0 || confidence >= 1 {
+		confidence = cfg.Confidence
+	}
+	if confidence <= 0 || confidence >= 1 {
+		confidence = 0.95
+	}
+	now := time.Now().UTC()
+	res := VaRResult{Method: method, Confidence: confidence, HorizonDays: 1, ComputedAt: now}
+
+	positions, err := m.Repo.ListOpenPositions(ctx)
+	if err != nil {
+		return res, err
+	}
+	open := positions[:0]
+	for _, p := range positions {
+		if m.Tenant != "" && p.Tenant != m.Tenant {
+			continue
+		}
+		open = append(open, p)
+		res.ExposureUSD += math.Abs(p.Quantity.Mul(p.CurrentPrice).InexactFloat64())
+	}
+	res.Positions = len(open)
+
+	if method == VaRMethodHistorical {
+		returns, err := m.dailyReturns(ctx, now, cfg.LookbackDays)
+		if err != nil {
+			return res, err
+		}
+		if m.Tenant != "" {
+			res.Warnings = append(res.Warnings, "historical returns are portfolio-wide; exposure is the desk's")
+		}
+		if len(returns) >= minHistoricalVaRSamples {
+			pnl := make([]float64, len(returns))
+			for i, r := range returns {
+				pnl[i] = r * res.ExposureUSD
+			}
+			res.Samples = len(pnl)
+			res.VaRUSD, res.ExpectedShortfallUSD = LossQuantiles(pnl, confidence)
+			return res, nil
+		}
+		res.Warnings = append(res.Warnings, fmt.Sprintf("only %d daily returns; fell back to monte_carlo", len(returns)))
+		res.Method = VaRMethodMonteCarlo
+	}
+
+	sims := cfg.Simulations
+	if sims <= 0 {
+		sims = 10000
+	}
+	// Fixed seed keeps repeated calls (and the Filter gate) stable.
+	pnl := SimulateSettlementPnL(ctx, open, m.Calibration, sims, 1)
+	res.Samples = len(pnl)
+	res.VaRUSD, res.ExpectedShortfallUSD = LossQuantiles(pnl, confidence)
+	return res, nil
+}
+
+func (m *Manager) dailyReturns(ctx context.Context, now time.Time, lookbackDays int) ([]float64, error) {
+	if lookbackDays <= 0 {
+		lookbackDays = 90
+	}
+	since := now.AddDate(0, 0, -lookbackDays)
+	var snaps []models.PortfolioSnapshot
+	const pageSize = 500
+	for offset := 0; offset < 50*pageSize; offset += pageSize {
+		page, err := m.Repo.ListPortfolioSnapshots(ctx, repository.ListPortfolioSnapshotsParams{
+			Limit:  pageSize,
+			Offset: offset,
+			Since:  &since,
+		})
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, page...)
+		if len(page) < pageSize {
+			break
+		}
+	}
+	return DailyPortfolioReturns(snaps), nil
+}
+
+// currentVaR is the cached configured VaR used by the Filter gate.
+func (m *Manager) currentVaR() (float64, bool) {
+	now := time.Now().UTC()
+	m.mu.Lock()
+	if !m.lastVaRAt.IsZero() && now.Sub(m.lastVaRAt) < varCacheTTL {
+		v := m.varCache
+		m.mu.Unlock()
+		return v, true
+	}
+	m.mu.Unlock()
+	res, err := m.ComputeVaR(context.Background(), VaROptions{})
+	if err != nil {
+		return 0, false
+	}
+	m.mu.Lock()
+	m.lastVaRAt = now
+	m.varCache = res.VaRUSD
+	m.mu.Unlock()
+	return res.VaRUSD, true
+}
+
+func (m *Manager) rejectVaR(varUSD float64, ok bool) bool {
+	if m == nil || !ok || m.Config.VaR.MaxVaRUSD <= 0 {
+		return false
+	}
+	return varUSD >= m.Config.VaR.MaxVaRUSD
+}
@@ -0,0 +1,58 @@
+package risk
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestLossQuantiles_VaRAndShortfall(t *testing.T) {
+	pnl := make([]float64, 0, 100)
+	for i := 1; i <= 100; i++ {
+		pnl = append(pnl, -float64(i))
+	}
+	v, es := LossQuantiles(pnl, 0.95)
+	if v != 95 {
+		t.Fatalf("var=%v want=95", v)
+	}
+	if math.Abs(es-97.5) > 1e-9 {
+		t.Fatalf("es=%v want=97.5", es)
+	}
+}
+
+func TestDailyPortfolioReturns_UsesLastSnapshotPerDay(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	snap := func(at time.Time, cost, pnl int64) models.PortfolioSnapshot {
+		return models.PortfolioSnapshot{
+			SnapshotAt:     at,
+			TotalCostBasis: decimal.NewFromInt(cost),
+			UnrealizedPnL:  decimal.NewFromInt(pnl),
+		}
+	}
+	returns := DailyPortfolioReturns([]models.PortfolioSnapshot{
+		snap(day.Add(48*time.Hour), 100, 5),
+		snap(day.Add(1*time.Hour), 1000, 99),
+		snap(day.Add(23*time.Hour), 100, 0),
+		snap(day.Add(24*time.Hour), 100, -10),
+	})
+	if len(returns) != 2 || returns[0] != -0.1 || returns[1] != 0.15 {
+		t.Fatalf("returns=%v want=[-0.1 0.15]", returns)
+	}
+}
+
+func TestSimulateSettlementPnL_HedgedMarketHasNoLoss(t *testing.T) {
+	positions := []models.Position{
+		{MarketID: "m1", TokenID: "yes", Direction: "YES", Quantity: decimal.NewFromInt(10), CurrentPrice: decimal.NewFromFloat(0.4)},
+		{MarketID: "m1", TokenID: "no", Direction: "NO", Quantity: decimal.NewFromInt(10), CurrentPrice: decimal.NewFromFloat(0.6)},
+	}
+	pnl := SimulateSettlementPnL(context.Background(), positions, nil, 1000, 1)
+	v, _ := LossQuantiles(pnl, 0.99)
+	if v > 1e-9 {
+		t.Fatalf("var=%v want=0 for YES+NO hedge", v)
+	}
+}
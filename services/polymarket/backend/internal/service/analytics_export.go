@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// maxAnalyticsExportTrades bounds the settled trades one export reads.
+const maxAnalyticsExportTrades = 50000
+
+// ExportMasking transforms an export before it is shared outside. With
+// NormalizeSizes dollar amounts are dropped and only percent returns kept.
+// Trades on markets with less than MinMarketVolumeUSD of volume, or none
+// known, lose their market id. Watermark stamps every run with its own id.
+type ExportMasking struct {
+	NormalizeSizes     bool    `json:"normalize_sizes"`
+	MinMarketVolumeUSD float64 `json:"min_market_volume_usd"`
+	Watermark          bool    `json:"watermark"`
+}
+
+// ParseExportMasking reads a job's stored masking; empty means none.
+func ParseExportMasking(raw []byte) (ExportMasking, error) {
+	var m ExportMasking
+	if len(raw) == 0 {
+		return m, nil
+	}
+	err := json.Unmarshal(raw, &m)
+	return m, err
+}
+
+// ExportTrade is one settled trade of a trades export.
+type ExportTrade struct {
+	SettledAt       time.Time `json:"settled_at"`
+	StrategyName    string    `json:"strategy_name"`
+	MarketID        string    `json:"market_id,omitempty"`
+	Outcome         string    `json:"outcome"`
+	ExpectedEdgePct float64   `json:"expected_edge_pct"`
+	ReturnPct       float64   `json:"return_pct"`
+	SizeUSD         *float64  `json:"size_usd,omitempty"`
+	PnLUSD          *float64  `json:"pnl_usd,omitempty"`
+}
+
+// ExportDay sums one strategy's trades settled on one UTC day.
+type ExportDay struct {
+	Date         string   `json:"date"`
+	StrategyName string   `json:"strategy_name"`
+	Trades       int      `json:"trades"`
+	Wins         int      `json:"wins"`
+	ReturnPct    float64  `json:"return_pct"`
+	SizeUSD      *float64 `json:"size_usd,omitempty"`
+	PnLUSD       *float64 `json:"pnl_usd,omitempty"`
+}
+
+// AnalyticsExportResult is the payload of one export run. Rows holds
+// ExportTrade or ExportDay values depending on the dataset.
+type AnalyticsExportResult struct {
+	ExportID      uint64        `json:"export_id"`
+	Name          string        `json:"name"`
+	Dataset       string        `json:"dataset"`
+	Masking       ExportMasking `json:"masking"`
+	GeneratedAt   time.Time     `json:"generated_at"`
+	Watermark     string        `json:"watermark,omitempty"`
+	MaskedMarkets int           `json:"masked_markets"`
+	Rows          any           `json:"rows"`
+}
+
+// AnalyticsExportService runs saved export jobs over settled trades.
+type AnalyticsExportService struct {
+	Repo repository.Repository
+}
+
+// Run builds the job's dataset, applies its masking and records the run.
+func (s *AnalyticsExportService) Run(ctx context.Context, job models.AnalyticsExport, now time.Time) (*AnalyticsExportResult, error) {
+	if s == nil || s.Repo == nil {
+		return nil, fmt.Errorf("analytics export unavailable")
+	}
+	masking, err := ParseExportMasking(job.Masking)
+	if err != nil {
+		return nil, fmt.Errorf("invalid masking: %w", err)
+	}
+	rows, err := s.Repo.ListSettledTrades(ctx, repository.SettledTradeParams{
+		Since:        job.Since,
+		Until:        job.Until,
+		StrategyName: job.StrategyName,
+		Limit:        maxAnalyticsExportTrades,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := &AnalyticsExportResult{
+		ExportID:    job.ID,
+		Name:        job.Name,
+		Dataset:     job.Dataset,
+		Masking:     masking,
+		GeneratedAt: now.UTC(),
+	}
+	if job.Dataset == models.AnalyticsExportDaily {
+		out.Rows = exportDays(rows, masking)
+	} else {
+		trades := make([]ExportTrade, 0, len(rows))
+		for _, row := range rows {
+			t := maskTrade(row, masking)
+			if t.MarketID == "" && row.MarketID != "" {
+				out.MaskedMarkets++
+			}
+			trades = append(trades, t)
+		}
+		out.Rows = trades
+	}
+	if masking.Watermark {
+		out.Watermark = exportWatermark(job.ID, job.RunCount+1, out.GeneratedAt)
+	}
+	if err := s.Repo.RecordAnalyticsExportRun(ctx, job.ID, out.Watermark, out.GeneratedAt); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func maskTrade(row repository.SettledTradeRow, m ExportMasking) ExportTrade {
+	t := ExportTrade{
+		SettledAt:       row.SettledAt.UTC(),
+		StrategyName:    row.StrategyName,
+		MarketID:        row.MarketID,
+		Outcome:         row.Outcome,
+		ExpectedEdgePct: row.ExpectedEdge * 100,
+		ReturnPct:       row.RealizedROI * 100,
+	}
+	if m.MinMarketVolumeUSD > 0 && (row.MarketVolume == nil || *row.MarketVolume < m.MinMarketVolumeUSD) {
+		t.MarketID = ""
+	}
+	if !m.NormalizeSizes {
+		size, pnl := row.SizeUSD, row.RealizedUSD
+		t.SizeUSD, t.PnLUSD = &size, &pnl
+	}
+	return t
+}
+
+// exportDays sums trades per strategy and settlement day. The return is
+// the day's PnL over the size it traded.
+func exportDays(rows []repository.SettledTradeRow, m ExportMasking) []ExportDay {
+	type key struct{ date, strategy string }
+	type sums struct {
+		trades, wins int
+		size, pnl    float64
+	}
+	byDay := map[key]*sums{}
+	for _, row := range rows {
+		k := key{row.SettledAt.UTC().Format("2006-01-02"), row.StrategyName}
+		agg := byDay[k]
+		if agg == nil {
+			agg = &sums{}
+			byDay[k] = agg
+		}
+		agg.trades++
+		if row.Outcome == "win" {
+			agg.wins++
+		}
+		agg.size += row.SizeUSD
+		agg.pnl += row.RealizedUSD
+	}
+	out := make([]ExportDay, 0, len(byDay))
+	for k, agg := range byDay {
+		d := ExportDay{Date: k.date, StrategyName: k.strategy, Trades: agg.trades, Wins: agg.wins}
+		if agg.size > 0 {
+			d.ReturnPct = agg.pnl / agg.size * 100
+		}
+		if !m.NormalizeSizes {
+			size, pnl := agg.size, agg.pnl
+			d.SizeUSD, d.PnLUSD = &size, &pnl
+		}
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Date != out[j].Date {
+			return out[i].Date < out[j].Date
+		}
+		return out[i].StrategyName < out[j].StrategyName
+	})
+	return out
+}
+
+// exportWatermark names the job and run in clear, so a shared file can be
+// traced back, plus a digest that differs on every run.
+func exportWatermark(jobID uint64, run int, at time.Time) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%d:%d", jobID, run, at.UnixNano())))
+	return fmt.Sprintf("pmx-%d-%d-%s", jobID, run, hex.EncodeToString(sum[:6]))
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type exportRepo struct {
+	repository.Repository
+	rows      []repository.SettledTradeRow
+	watermark string
+	runs      int
+}
+
+func (r *exportRepo) ListSettledTrades(context.Context, repository.SettledTradeParams) ([]repository.SettledTradeRow, error) {
+	return r.rows, nil
+}
+
+func (r *exportRepo) RecordAnalyticsExportRun(_ context.Context, _ uint64, watermark string, _ time.Time) error {
+	r.runs++
+	r.watermark = watermark
+	return nil
+}
+
+func TestAnalyticsExportMasking(t *testing.T) {
+	day := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	big, small := 500000.0, 800.0
+	repo := &exportRepo{rows: []repository.SettledTradeRow{
+		{PlanID: 1, StrategyName: "arb", MarketID: "m-big", MarketVolume: &big, SizeUSD: 100, ExpectedEdge: 0.03, RealizedUSD: 4, RealizedROI: 0.04, Outcome: "win", SettledAt: day},
+		{PlanID: 2, StrategyName: "arb", MarketID: "m-small", MarketVolume: &small, SizeUSD: 300, ExpectedEdge: 0.02, RealizedUSD: -12, RealizedROI: -0.04, Outcome: "loss", SettledAt: day.Add(time.Hour)},
+		{PlanID: 3, StrategyName: "arb", MarketID: "m-unknown", SizeUSD: 100, RealizedUSD: 2, RealizedROI: 0.02, Outcome: "win", SettledAt: day.Add(2 * time.Hour)},
+	}}
+	svc := &AnalyticsExportService{Repo: repo}
+	job := models.AnalyticsExport{
+		ID:       7,
+		Name:     "fund deck",
+		Dataset:  models.AnalyticsExportTrades,
+		RunCount: 2,
+		Masking:  datatypes.JSON(`{"normalize_sizes":true,"min_market_volume_usd":10000,"watermark":true}`),
+	}
+
+	res, err := svc.Run(context.Background(), job, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trades := res.Rows.([]ExportTrade)
+	if len(trades) != 3 || res.MaskedMarkets != 2 {
+		t.Fatalf("trades=%+v masked=%d", trades, res.MaskedMarkets)
+	}
+	if trades[0].MarketID != "m-big" || trades[1].MarketID != "" || trades[2].MarketID != "" {
+		t.Fatalf("market ids=%q %q %q", trades[0].MarketID, trades[1].MarketID, trades[2].MarketID)
+	}
+	for _, tr := range trades {
+		if tr.SizeUSD != nil || tr.PnLUSD != nil {
+			t.Fatalf("sizes not stripped: %+v", tr)
+		}
+	}
+	if trades[0].ReturnPct != 4 || trades[0].ExpectedEdgePct != 3 {
+		t.Fatalf("percents=%+v", trades[0])
+	}
+	if !strings.HasPrefix(res.Watermark, "pmx-7-3-") || repo.watermark != res.Watermark || repo.runs != 1 {
+		t.Fatalf("watermark=%q recorded=%q runs=%d", res.Watermark, repo.watermark, repo.runs)
+	}
+
+	job.Dataset = models.AnalyticsExportDaily
+	job.Masking = datatypes.JSON(`{}`)
+	res, err = svc.Run(context.Background(), job, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	days := res.Rows.([]ExportDay)
+	if len(days) != 1 || days[0].Trades != 3 || days[0].Wins != 2 || res.Watermark != "" {
+		t.Fatalf("days=%+v watermark=%q", days, res.Watermark)
+	}
+	if days[0].SizeUSD == nil || *days[0].SizeUSD != 500 || *days[0].PnLUSD != -6 || days[0].ReturnPct != -1.2 {
+		t.Fatalf("day=%+v", days[0])
+	}
+}
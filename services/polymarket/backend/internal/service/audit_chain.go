@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// AuditGenesisHash is the PrevHash of the first record in the chain.
+var AuditGenesisHash = strings.Repeat("0", 64)
+
+// AuditChainService appends write requests to the hash-chained audit table.
+// It implements paas.AuditSink.
+type AuditChainService struct {
+	Repo repository.Repository
+}
+
+var _ paas.AuditSink = (*AuditChainService)(nil)
+
+func (s *AuditChainService) AppendAudit(ctx context.Context, entry paas.AuditEntry) error {
+	if s == nil || s.Repo == nil {
+		return nil
+	}
+	item := &models.AuditRecord{
+		Agent:      strings.TrimSpace(entry.Agent),
+		Method:     strings.ToUpper(strings.TrimSpace(entry.Method)),
+		Path:       entry.Path,
+		Status:     entry.Status,
+		DurationMs: entry.Duration.Milliseconds(),
+		Project:    strings.TrimSpace(entry.Project),
+		Role:       strings.TrimSpace(entry.Role),
+		// Postgres keeps microseconds; truncate so the stored value hashes the same.
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	return s.Repo.AppendAuditRecord(ctx, item, func(prev *models.AuditRecord, item *models.AuditRecord) {
+		item.Seq = 1
+		item.PrevHash = AuditGenesisHash
+		if prev != nil {
+			item.Seq = prev.Seq + 1
+			item.PrevHash = prev.Hash
+		}
+		item.Hash = AuditRecordHash(*item)
+	})
+}
+
+// AuditRecordHash is sha256 over the newline-joined record fields. The CLI
+// verifier (easyweb3 api polymarket audit-verify) recomputes the same value,
+// so the field order and formatting must not change.
+func AuditRecordHash(rec models.AuditRecord) string {
+	payload := strings.Join([]string{
+		strconv.FormatUint(rec.Seq, 10),
+		rec.CreatedAt.UTC().Format(time.RFC3339Nano),
+		rec.Agent,
+		rec.Method,
+		rec.Path,
+		strconv.Itoa(rec.Status),
+		strconv.FormatInt(rec.DurationMs, 10),
+		rec.Project,
+		rec.Role,
+		rec.PrevHash,
+	}, "\n")
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditVerifyResult summarizes a chain verification.
+type AuditVerifyResult struct {
+	Checked  int    `json:"checked"`
+	Valid    bool   `json:"valid"`
+	LastSeq  uint64 `json:"last_seq"`
+	LastHash string `json:"last_hash"`
+	BrokenAt uint64 `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// VerifyAuditChain checks records (in seq order) against prevHash/prevSeq of
+// the record before them; pass AuditGenesisHash and 0 to start at the head.
+func VerifyAuditChain(records []models.AuditRecord, prevSeq uint64, prevHash string) AuditVerifyResult {
+	res := AuditVerifyResult{Valid: true, LastSeq: prevSeq, LastHash: prevHash}
+	for _, rec := range records {
+		res.Checked++
+		switch {
+		case rec.Seq != res.LastSeq+1:
+			res.Reason = fmt.Sprintf("expected seq %d, got %d", res.LastSeq+1, rec.Seq)
+		case rec.PrevHash != res.LastHash:
+			res.Reason = "prev_hash does not match previous record"
+		case AuditRecordHash(rec) != rec.Hash:
+			res.Reason = "hash does not match record contents"
+		}
+		if res.Reason != "" {
+			res.Valid = false
+			res.BrokenAt = rec.Seq
+			return res
+		}
+		res.LastSeq = rec.Seq
+		res.LastHash = rec.Hash
+	}
+	return res
+}
+
+// Verify walks the whole stored chain page by page.
+func (s *AuditChainService) Verify(ctx context.Context) (AuditVerifyResult, error) {
+	res := AuditVerifyResult{Valid: true, LastHash: AuditGenesisHash}
+	if s == nil || s.Repo == nil {
+		return res, fmt.Errorf("repo unavailable")
+	}
+	checked := 0
+	for {
+		page, err := s.Repo.ListAuditRecords(ctx, repository.ListAuditRecordsParams{Limit: 1000, AfterSeq: res.LastSeq})
+		if err != nil {
+			return res, err
+		}
+		if len(page) == 0 {
+			res.Checked = checked
+			return res, nil
+		}
+		res = VerifyAuditChain(page, res.LastSeq, res.LastHash)
+		checked += res.Checked
+		if !res.Valid {
+			res.Checked = checked
+			return res, nil
+		}
+	}
+}
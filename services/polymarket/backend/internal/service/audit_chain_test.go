@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"polymarket/internal/models"
+)
+
+func buildAuditChain(n int) []models.AuditRecord {
+	out := make([]models.AuditRecord, 0, n)
+	prev := AuditGenesisHash
+	for i := 1; i <= n; i++ {
+		rec := models.AuditRecord{
+			Seq:       uint64(i),
+			Agent:     "polymarket-service",
+			Method:    "POST",
+			Path:      "/api/v2/executions",
+			Status:    200,
+			Project:   "desk-a",
+			Role:      "agent",
+			PrevHash:  prev,
+			CreatedAt: time.Date(2026, 3, 1, 12, 0, i, 123456000, time.UTC),
+		}
+		rec.Hash = AuditRecordHash(rec)
+		prev = rec.Hash
+		out = append(out, rec)
+	}
+	return out
+}
+
+func TestVerifyAuditChain_Valid(t *testing.T) {
+	res := VerifyAuditChain(buildAuditChain(3), 0, AuditGenesisHash)
+	if !res.Valid || res.Checked != 3 || res.LastSeq != 3 {
+		t.Fatalf("res=%+v", res)
+	}
+}
+
+func TestVerifyAuditChain_DetectsTampering(t *testing.T) {
+	chain := buildAuditChain(3)
+	chain[1].Status = 500
+	res := VerifyAuditChain(chain, 0, AuditGenesisHash)
+	if res.Valid || res.BrokenAt != 2 {
+		t.Fatalf("res=%+v want broken at 2", res)
+	}
+
+	chain = buildAuditChain(3)
+	res = VerifyAuditChain([]models.AuditRecord{chain[0], chain[2]}, 0, AuditGenesisHash)
+	if res.Valid || res.BrokenAt != 3 {
+		t.Fatalf("res=%+v want broken at 3 after deletion", res)
+	}
+}
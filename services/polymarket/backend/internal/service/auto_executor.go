@@ -3,7 +3,10 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -11,9 +14,11 @@ import (
 	"gorm.io/datatypes"
 
 	"polymarket/internal/config"
+	"polymarket/internal/events"
 	"polymarket/internal/models"
 	"polymarket/internal/repository"
 	"polymarket/internal/risk"
+	"polymarket/internal/tradingday"
 )
 
 type AutoExecutorService struct {
@@ -24,6 +29,21 @@ type AutoExecutorService struct {
 	Flags  *SystemSettingsService
 	// Executor unifies dry-run/live order submission path.
 	Executor *CLOBExecutor
+	// Campaigns gates opportunities that belong to a campaign.
+	Campaigns *CampaignService
+	// Calendar sets when MaxDailyTrades resets; nil is UTC midnight.
+	Calendar *tradingday.Calendar
+	// Budgets pauses strategies that spent their turnover or fee budget.
+	Budgets *StrategyBudgetService
+	// Costs records the execution cost forecast of auto plans.
+	Costs *CostForecaster
+	// Wake, when set, triggers a scan on each new opportunity event besides
+	// the ScanInterval poll.
+	Wake <-chan events.Event
+
+	queueMu  sync.Mutex
+	queuedAt map[uint64]time.Time
+	queue    AutoExecutorQueue
 }
 
 func (s *AutoExecutorService) Run(ctx context.Context) error {
@@ -45,6 +65,8 @@ func (s *AutoExecutorService) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
+		case <-s.Wake:
+			events.Drain(s.Wake)
 		}
 	}
 }
@@ -71,11 +93,20 @@ func (s *AutoExecutorService) scanOnce(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	for _, opp := range opps {
+	for _, opp := range s.buildQueue(opps, time.Now().UTC()) {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
-		if err := s.processOpportunity(ctx, opp); err != nil && s.Logger != nil {
+		err := s.processOpportunity(ctx, opp)
+		var limitErr *risk.RateLimitError
+		if errors.As(err, &limitErr) {
+			// Global budget exhausted: the remaining opportunities would hit it too.
+			if s.Logger != nil {
+				s.Logger.Debug("auto executor paused by risk limit", zap.String("limit", limitErr.Usage.Name))
+			}
+			return nil
+		}
+		if err != nil && s.Logger != nil {
 			s.Logger.Warn("auto executor skipped opportunity", zap.Uint64("opportunity_id", opp.ID), zap.Error(err))
 		}
 	}
@@ -84,7 +115,7 @@ func (s *AutoExecutorService) scanOnce(ctx context.Context) error {
 
 func (s *AutoExecutorService) processOpportunity(ctx context.Context, opp models.Opportunity) error {
 	strategyName := strings.TrimSpace(opp.Strategy.Name)
-	if strategyName == "" {
+	if strategyName == "" || opp.Shadow {
 		return nil
 	}
 	rule, err := s.Repo.GetExecutionRuleByStrategyName(ctx, strategyName)
@@ -99,7 +130,7 @@ func (s *AutoExecutorService) processOpportunity(ctx context.Context, opp models
 			minConfidence = 0.8
 		}
 	}
-	if opp.Confidence < minConfidence {
+	if opp.CurrentConfidence() < minConfidence {
 		return nil
 	}
 
@@ -110,13 +141,37 @@ func (s *AutoExecutorService) processOpportunity(ctx context.Context, opp models
 			minEdge = decimal.NewFromFloat(0.05)
 		}
 	}
-	if opp.EdgePct.LessThan(minEdge) {
+	if opp.CurrentEdgePct().LessThan(minEdge) {
 		return nil
 	}
 
+	if cond := strings.TrimSpace(rule.Condition); cond != "" {
+		prog, err := CompileOpportunityCondition(cond)
+		if err != nil {
+			return fmt.Errorf("execution rule condition: %w", err)
+		}
+		env, err := OpportunityConditionEnv(ctx, s.Repo, opp, prog)
+		if err != nil {
+			return err
+		}
+		ok, err := prog.Eval(env)
+		if err != nil {
+			return fmt.Errorf("execution rule condition: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	if s.Budgets != nil {
+		paused, err := s.Budgets.Check(ctx, strategyName, time.Now().UTC())
+		if err != nil || paused {
+			return err
+		}
+	}
+
 	if rule.MaxDailyTrades > 0 {
-		now := time.Now().UTC()
-		dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		dayStart := s.Calendar.Start(time.Now().UTC())
 		count, err := s.Repo.CountExecutionPlansByStrategySince(ctx, strategyName, dayStart)
 		if err != nil {
 			return err
@@ -126,6 +181,29 @@ func (s *AutoExecutorService) processOpportunity(ctx context.Context, opp models
 		}
 	}
 
+	if s.Risk != nil {
+		legs := 1
+		var rawLegs []json.RawMessage
+		if json.Unmarshal(opp.Legs, &rawLegs) == nil && len(rawLegs) > 0 {
+			legs = len(rawLegs)
+		}
+		if err := s.Risk.CheckRateLimit(ctx, risk.LimitPlansPerHour, 1); err != nil {
+			return err
+		}
+		if err := s.Risk.CheckRateLimit(ctx, risk.LimitAutoExecutionsPerHour, 1); err != nil {
+			return err
+		}
+		if err := s.Risk.CheckRateLimit(ctx, risk.LimitOpenOrders, legs); err != nil {
+			return err
+		}
+	}
+
+	if s.Config.Preemption && s.Risk != nil {
+		if err := s.preemptFor(ctx, opp); err != nil {
+			return err
+		}
+	}
+
 	plannedSize := opp.MaxSize
 	maxLoss := plannedSize
 	var kelly *float64
@@ -139,10 +217,23 @@ func (s *AutoExecutorService) processOpportunity(ctx context.Context, opp models
 		return nil
 	}
 
+	if opp.CampaignID != nil {
+		if _, err := s.Campaigns.Admit(ctx, *opp.CampaignID, opp.Tenant, plannedSize, time.Now().UTC()); err != nil {
+			if _, blocked := IsCampaignBlocked(err); blocked {
+				return nil
+			}
+			return err
+		}
+	}
+
 	plan := &models.ExecutionPlan{
 		OpportunityID:   opp.ID,
+		Source:          "opportunity",
+		AutoExecuted:    true,
+		CampaignID:      opp.CampaignID,
 		Status:          "draft",
 		StrategyName:    strategyName,
+		Tenant:          opp.Tenant,
 		PlannedSizeUSD:  plannedSize,
 		MaxLossUSD:      maxLoss,
 		KellyFraction:   kelly,
@@ -163,6 +254,11 @@ func (s *AutoExecutorService) processOpportunity(ctx context.Context, opp models
 		Outcome:      "pending",
 		CreatedAt:    time.Now().UTC(),
 	})
+	if s.Costs != nil {
+		if fc, err := s.Costs.Forecast(ctx, opp, plannedSize); err == nil {
+			_ = s.Costs.Record(ctx, plan, fc)
+		}
+	}
 
 	if s.Risk != nil {
 		preflight, err := s.Risk.PreflightPlan(ctx, plan.ID)
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+)
+
+// AutoQueueItem is one active opportunity waiting in the auto executor.
+type AutoQueueItem struct {
+	Position      int             `json:"position"`
+	OpportunityID uint64          `json:"opportunity_id"`
+	Strategy      string          `json:"strategy"`
+	Tenant        string          `json:"tenant,omitempty"`
+	EdgePct       float64         `json:"edge_pct"`
+	Confidence    float64         `json:"confidence"`
+	Priority      float64         `json:"priority"`
+	MaxSizeUSD    decimal.Decimal `json:"max_size_usd"`
+	QueuedAt      time.Time       `json:"queued_at"`
+	WaitSeconds   float64         `json:"wait_seconds"`
+	// Starved items waited past starvation_after and go first.
+	Starved bool `json:"starved"`
+}
+
+// AutoPreemption records a pending plan cancelled to make room for a
+// higher-priority opportunity. Its opportunity goes back to the queue.
+type AutoPreemption struct {
+	PlanID         uint64          `json:"plan_id"`
+	OpportunityID  uint64          `json:"opportunity_id"`
+	Strategy       string          `json:"strategy"`
+	Priority       float64         `json:"priority"`
+	PlannedSizeUSD decimal.Decimal `json:"planned_size_usd"`
+	PreemptedBy    uint64          `json:"preempted_by"`
+	ByPriority     float64         `json:"by_priority"`
+	At             time.Time       `json:"at"`
+}
+
+// AutoExecutorQueue is the queue as of the last scan.
+type AutoExecutorQueue struct {
+	ScannedAt              *time.Time       `json:"scanned_at"`
+	StarvationAfterSeconds float64          `json:"starvation_after_seconds"`
+	Preemption             bool             `json:"preemption"`
+	Items                  []AutoQueueItem  `json:"items"`
+	RecentPreemptions      []AutoPreemption `json:"recent_preemptions"`
+}
+
+// maxRecentPreemptions bounds the preemption history kept for the queue
+// view.
+const maxRecentPreemptions = 50
+
+// autoPriority orders the queue: decayed edge times decayed confidence.
+func autoPriority(opp models.Opportunity) float64 {
+	edge, _ := opp.CurrentEdgePct().Float64()
+	return edge * opp.CurrentConfidence()
+}
+
+// buildQueue turns the active opportunities into the processing order and
+// publishes it for Queue. Wait time counts from the first scan that saw
+// the opportunity; ones no longer active are forgotten.
+func (s *AutoExecutorService) buildQueue(opps []models.Opportunity, now time.Time) []models.Opportunity {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	seen := make(map[uint64]time.Time, len(opps))
+	items := make([]AutoQueueItem, 0, len(opps))
+	byID := make(map[uint64]models.Opportunity, len(opps))
+	for _, opp := range opps {
+		queuedAt, ok := s.queuedAt[opp.ID]
+		if !ok {
+			queuedAt = now
+		}
+		seen[opp.ID] = queuedAt
+		byID[opp.ID] = opp
+		edge, _ := opp.CurrentEdgePct().Float64()
+		wait := now.Sub(queuedAt)
+		items = append(items, AutoQueueItem{
+			OpportunityID: opp.ID,
+			Strategy:      strings.TrimSpace(opp.Strategy.Name),
+			Tenant:        opp.Tenant,
+			EdgePct:       edge,
+			Confidence:    opp.CurrentConfidence(),
+			Priority:      autoPriority(opp),
+			MaxSizeUSD:    opp.MaxSize,
+			QueuedAt:      queuedAt,
+			WaitSeconds:   wait.Seconds(),
+			Starved:       s.Config.StarvationAfter > 0 && wait >= s.Config.StarvationAfter,
+		})
+	}
+	s.queuedAt = seen
+	orderAutoQueue(items)
+
+	ordered := make([]models.Opportunity, 0, len(items))
+	for i := range items {
+		items[i].Position = i + 1
+		ordered = append(ordered, byID[items[i].OpportunityID])
+	}
+	scanned := now
+	s.queue.ScannedAt = &scanned
+	s.queue.StarvationAfterSeconds = s.Config.StarvationAfter.Seconds()
+	s.queue.Preemption = s.Config.Preemption
+	s.queue.Items = items
+	return ordered
+}
+
+// orderAutoQueue puts starved items first, longest wait first, then the
+// rest by priority. Ties go to the earlier arrival.
+func orderAutoQueue(items []AutoQueueItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Starved != b.Starved {
+			return a.Starved
+		}
+		if !a.Starved && a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if !a.QueuedAt.Equal(b.QueuedAt) {
+			return a.QueuedAt.Before(b.QueuedAt)
+		}
+		return a.OpportunityID < b.OpportunityID
+	})
+}
+
+// Queue returns the queue as of the last scan with the recent preemptions.
+func (s *AutoExecutorService) Queue() AutoExecutorQueue {
+	if s == nil {
+		return AutoExecutorQueue{Items: []AutoQueueItem{}, RecentPreemptions: []AutoPreemption{}}
+	}
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	out := s.queue
+	out.StarvationAfterSeconds = s.Config.StarvationAfter.Seconds()
+	out.Preemption = s.Config.Preemption
+	out.Items = append([]AutoQueueItem{}, s.queue.Items...)
+	out.RecentPreemptions = append([]AutoPreemption{}, s.queue.RecentPreemptions...)
+	return out
+}
+
+// preemptFor makes room for opp when the total exposure limit leaves less
+// than its max size: pending auto plans of lower priority on the same desk
+// are cancelled, lowest first, until the room covers it. Their
+// opportunities are reactivated so they queue again.
+func (s *AutoExecutorService) preemptFor(ctx context.Context, opp models.Opportunity) error {
+	mgr := s.Risk.ForTenant(opp.Tenant)
+	room, limited := mgr.Headroom(ctx)
+	if !limited || room.GreaterThanOrEqual(opp.MaxSize) {
+		return nil
+	}
+	plans, err := s.Repo.ListExecutionPlansByStatuses(ctx, []string{"draft", "preflight_pass"}, 500)
+	if err != nil {
+		return err
+	}
+	prio := autoPriority(opp)
+	type candidate struct {
+		plan     models.ExecutionPlan
+		priority float64
+		active   bool
+	}
+	var cands []candidate
+	for _, p := range plans {
+		if !p.AutoExecuted || p.OpportunityID == 0 || p.OpportunityID == opp.ID {
+			continue
+		}
+		if mgr.Tenant != "" && p.Tenant != mgr.Tenant {
+			continue
+		}
+		c := candidate{plan: p}
+		if held, err := s.Repo.GetOpportunityByID(ctx, p.OpportunityID); err == nil && held != nil {
+			c.priority = autoPriority(*held)
+			c.active = held.Status == "executing"
+		}
+		if c.priority < prio {
+			cands = append(cands, c)
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].priority < cands[j].priority })
+
+	now := time.Now().UTC()
+	var done []AutoPreemption
+	for _, c := range cands {
+		if room.GreaterThanOrEqual(opp.MaxSize) {
+			break
+		}
+		if err := s.Repo.UpdateExecutionPlanStatus(ctx, c.plan.ID, "cancelled"); err != nil {
+			return err
+		}
+		if c.active {
+			_ = s.Repo.UpdateOpportunityStatus(ctx, c.plan.OpportunityID, "active")
+		}
+		room = room.Add(c.plan.PlannedSizeUSD)
+		done = append(done, AutoPreemption{
+			PlanID:         c.plan.ID,
+			OpportunityID:  c.plan.OpportunityID,
+			Strategy:       c.plan.StrategyName,
+			Priority:       c.priority,
+			PlannedSizeUSD: c.plan.PlannedSizeUSD,
+			PreemptedBy:    opp.ID,
+			ByPriority:     prio,
+			At:             now,
+		})
+		if s.Logger != nil {
+			s.Logger.Info("auto executor preempted pending plan",
+				zap.Uint64("plan_id", c.plan.ID),
+				zap.Uint64("opportunity_id", opp.ID),
+				zap.Float64("priority", c.priority),
+				zap.Float64("by_priority", prio),
+			)
+		}
+	}
+	if len(done) == 0 {
+		return nil
+	}
+	mgr.InvalidateExposure()
+	if mgr != s.Risk {
+		s.Risk.InvalidateExposure()
+	}
+	s.queueMu.Lock()
+	hist := append(s.queue.RecentPreemptions, done...)
+	if len(hist) > maxRecentPreemptions {
+		hist = hist[len(hist)-maxRecentPreemptions:]
+	}
+	s.queue.RecentPreemptions = hist
+	s.queueMu.Unlock()
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/risk"
+)
+
+type queueRepo struct {
+	repository.Repository
+	plans map[uint64]*models.ExecutionPlan
+	opps  map[uint64]*models.Opportunity
+}
+
+func (r *queueRepo) ListExecutionPlansByStatuses(ctx context.Context, statuses []string, limit int) ([]models.ExecutionPlan, error) {
+	var out []models.ExecutionPlan
+	for _, p := range r.plans {
+		for _, st := range statuses {
+			if p.Status == st {
+				out = append(out, *p)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (r *queueRepo) GetOpportunityByID(ctx context.Context, id uint64) (*models.Opportunity, error) {
+	return r.opps[id], nil
+}
+
+func (r *queueRepo) UpdateExecutionPlanStatus(ctx context.Context, id uint64, status string) error {
+	r.plans[id].Status = status
+	return nil
+}
+
+func (r *queueRepo) UpdateOpportunityStatus(ctx context.Context, id uint64, status string) error {
+	r.opps[id].Status = status
+	return nil
+}
+
+func queueOpp(id uint64, edge, confidence float64) models.Opportunity {
+	return models.Opportunity{ID: id, EdgePct: decimal.NewFromFloat(edge), Confidence: confidence, MaxSize: decimal.NewFromInt(40), Status: "active"}
+}
+
+func TestAutoQueueOrdersByPriorityWithStarvation(t *testing.T) {
+	s := &AutoExecutorService{Config: config.AutoExecutorConfig{StarvationAfter: 5 * time.Minute}}
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	low := queueOpp(1, 0.05, 0.9)
+	high := queueOpp(2, 0.10, 0.9)
+	mid := queueOpp(3, 0.10, 0.6)
+	order := s.buildQueue([]models.Opportunity{low, high, mid}, t0)
+	if got := [3]uint64{order[0].ID, order[1].ID, order[2].ID}; got != [3]uint64{2, 3, 1} {
+		t.Fatalf("order = %v, want [2 3 1]", got)
+	}
+
+	// A newcomer outranks everyone, but the low item has now waited past
+	// starvation_after and goes first.
+	top := queueOpp(4, 0.20, 0.9)
+	order = s.buildQueue([]models.Opportunity{low, high, mid, top}, t0.Add(6*time.Minute))
+	want := []uint64{1, 2, 3, 4}
+	for i := range want {
+		if order[i].ID != want[i] {
+			t.Fatalf("starved order[%d] = %d, want %v", i, order[i].ID, want)
+		}
+	}
+	q := s.Queue()
+	if !q.Items[0].Starved || q.Items[0].Position != 1 || q.Items[3].Starved {
+		t.Fatalf("queue = %+v", q.Items)
+	}
+
+	// Leaving the queue resets the wait.
+	s.buildQueue([]models.Opportunity{high}, t0.Add(7*time.Minute))
+	s.buildQueue([]models.Opportunity{low, high}, t0.Add(8*time.Minute))
+	if q := s.Queue(); q.Items[0].OpportunityID != 2 || q.Items[1].Starved {
+		t.Fatalf("requeued = %+v", q.Items)
+	}
+}
+
+func TestAutoExecutorPreemptsLowerPriorityPlans(t *testing.T) {
+	held := map[uint64]*models.Opportunity{}
+	for _, o := range []models.Opportunity{queueOpp(10, 0.02, 0.9), queueOpp(11, 0.04, 0.9), queueOpp(12, 0.30, 0.9)} {
+		o := o
+		o.Status = "executing"
+		held[o.ID] = &o
+	}
+	repo := &queueRepo{
+		opps: held,
+		plans: map[uint64]*models.ExecutionPlan{
+			1: {ID: 1, OpportunityID: 10, Status: "draft", AutoExecuted: true, PlannedSizeUSD: decimal.NewFromInt(30)},
+			2: {ID: 2, OpportunityID: 11, Status: "preflight_pass", AutoExecuted: true, PlannedSizeUSD: decimal.NewFromInt(30)},
+			3: {ID: 3, OpportunityID: 12, Status: "draft", AutoExecuted: true, PlannedSizeUSD: decimal.NewFromInt(30)},
+			4: {ID: 4, OpportunityID: 0, Status: "draft", Source: "manual", PlannedSizeUSD: decimal.NewFromInt(5)},
+		},
+	}
+	mgr := &risk.Manager{Config: config.RiskConfig{MaxTotalExposureUSD: 100}, Repo: repo}
+	s := &AutoExecutorService{Repo: repo, Risk: mgr, Config: config.AutoExecutorConfig{Preemption: true}}
+
+	// 95 of 100 is taken; the incoming opportunity wants 40, so the two
+	// lower-priority auto plans go, lowest first, and the stronger one and
+	// the manual plan stay.
+	if err := s.preemptFor(context.Background(), queueOpp(20, 0.10, 0.9)); err != nil {
+		t.Fatal(err)
+	}
+	if repo.plans[1].Status != "cancelled" || repo.plans[2].Status != "cancelled" {
+		t.Fatalf("plans 1,2 = %s,%s, want cancelled", repo.plans[1].Status, repo.plans[2].Status)
+	}
+	if repo.plans[3].Status != "draft" || repo.plans[4].Status != "draft" {
+		t.Fatalf("plans 3,4 = %s,%s, want draft", repo.plans[3].Status, repo.plans[4].Status)
+	}
+	if held[10].Status != "active" || held[11].Status != "active" {
+		t.Fatalf("preempted opportunities not requeued: %s %s", held[10].Status, held[11].Status)
+	}
+	if room, _ := mgr.Headroom(context.Background()); !room.Equal(decimal.NewFromInt(65)) {
+		t.Fatalf("headroom = %s, want 65", room)
+	}
+	q := s.Queue()
+	if len(q.RecentPreemptions) != 2 || q.RecentPreemptions[0].PlanID != 1 || q.RecentPreemptions[0].PreemptedBy != 20 {
+		t.Fatalf("preemptions = %+v", q.RecentPreemptions)
+	}
+
+	// Enough room: nothing more is cancelled.
+	if err := s.preemptFor(context.Background(), queueOpp(21, 0.50, 0.9)); err != nil {
+		t.Fatal(err)
+	}
+	if repo.plans[3].Status != "draft" {
+		t.Fatal("preempted with room to spare")
+	}
+}
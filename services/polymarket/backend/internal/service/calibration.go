@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"polymarket/internal/repository"
+)
+
+const (
+	calibrationPriceBins   = 10
+	calibrationPriorWeight = 10.0
+	calibrationAllHorizons = "all"
+)
+
+// calibrationHorizons buckets time-to-expiry. The last bucket is open-ended.
+var calibrationHorizons = []struct {
+	Name string
+	Max  time.Duration
+}{
+	{Name: "lt_1d", Max: 24 * time.Hour},
+	{Name: "1d_7d", Max: 7 * 24 * time.Hour},
+	{Name: "7d_30d", Max: 30 * 24 * time.Hour},
+	{Name: "gt_30d"},
+}
+
+// CalibrationBin is one point of the reliability diagram.
+type CalibrationBin struct {
+	Horizon     string  `json:"horizon"`
+	PriceLow    float64 `json:"price_low"`
+	PriceHigh   float64 `json:"price_high"`
+	Count       int     `json:"count"`
+	MeanPrice   float64 `json:"mean_price"`
+	ObservedYes float64 `json:"observed_yes"`
+	Calibrated  float64 `json:"calibrated"`
+}
+
+// CalibrationModel maps (price, time-to-expiry) to a settlement probability.
+// Each horizon bin is shrunk towards the all-horizon bin, which is shrunk
+// towards the market price, so sparse bins stay close to the raw price.
+type CalibrationModel struct {
+	Samples    int              `json:"samples"`
+	BrierScore float64          `json:"brier_score"`
+	FittedAt   time.Time        `json:"fitted_at"`
+	Bins       []CalibrationBin `json:"bins"`
+
+	cells map[string]*calibrationCell
+}
+
+type calibrationCell struct {
+	count    int
+	yes      int
+	priceSum float64
+}
+
+func calibrationHorizon(tte time.Duration) string {
+	if tte <= 0 {
+		return ""
+	}
+	for _, h := range calibrationHorizons {
+		if h.Max == 0 || tte < h.Max {
+			return h.Name
+		}
+	}
+	return ""
+}
+
+func calibrationPriceBin(price float64) int {
+	idx := int(price * calibrationPriceBins)
+	if idx < 0 {
+		return 0
+	}
+	if idx >= calibrationPriceBins {
+		return calibrationPriceBins - 1
+	}
+	return idx
+}
+
+func calibrationCellKey(horizon string, bin int) string {
+	return fmt.Sprintf("%s|%d", horizon, bin)
+}
+
+func clampProbability(p float64) float64 {
+	if math.IsNaN(p) || p < 0 {
+		return 0
+	}
+	if p > 1 {
+		return 1
+	}
+	return p
+}
+
+// FitCalibration builds a calibration model from settled markets.
+func FitCalibration(samples []repository.CalibrationSampleRow, now time.Time) *CalibrationModel {
+	model := &CalibrationModel{FittedAt: now.UTC(), cells: map[string]*calibrationCell{}}
+	add := func(horizon string, bin int, price float64, yes bool) {
+		key := calibrationCellKey(horizon, bin)
+		cell := model.cells[key]
+		if cell == nil {
+			cell = &calibrationCell{}
+			model.cells[key] = cell
+		}
+		cell.count++
+		cell.priceSum += price
+		if yes {
+			cell.yes++
+		}
+	}
+	var brier float64
+	for _, s := range samples {
+		outcome := strings.ToUpper(strings.TrimSpace(s.Outcome))
+		if outcome != "YES" && outcome != "NO" {
+			continue
+		}
+		price := clampProbability(s.Price.InexactFloat64())
+		yes := outcome == "YES"
+		bin := calibrationPriceBin(price)
+		add(calibrationAllHorizons, bin, price, yes)
+		if s.OpenedAt != nil {
+			if horizon := calibrationHorizon(s.SettledAt.Sub(*s.OpenedAt)); horizon != "" {
+				add(horizon, bin, price, yes)
+			}
+		}
+		actual := 0.0
+		if yes {
+			actual = 1
+		}
+		brier += (price - actual) * (price - actual)
+		model.Samples++
+	}
+	if model.Samples > 0 {
+		model.BrierScore = brier / float64(model.Samples)
+	}
+
+	horizons := []string{calibrationAllHorizons}
+	for _, h := range calibrationHorizons {
+		horizons = append(horizons, h.Name)
+	}
+	for _, horizon := range horizons {
+		for bin := 0; bin < calibrationPriceBins; bin++ {
+			cell := model.cells[calibrationCellKey(horizon, bin)]
+			if cell == nil || cell.count == 0 {
+				continue
+			}
+			mean := cell.priceSum / float64(cell.count)
+			model.Bins = append(model.Bins, CalibrationBin{
+				Horizon:     horizon,
+				PriceLow:    float64(bin) / calibrationPriceBins,
+				PriceHigh:   float64(bin+1) / calibrationPriceBins,
+				Count:       cell.count,
+				MeanPrice:   mean,
+				ObservedYes: float64(cell.yes) / float64(cell.count),
+				Calibrated:  model.probability(mean, horizon),
+			})
+		}
+	}
+	return model
+}
+
+// Probability converts a YES price into a calibrated YES probability. tte <= 0
+// means the expiry is unknown and only the all-horizon curve is used.
+func (m *CalibrationModel) Probability(price float64, tte time.Duration) float64 {
+	return m.probability(price, calibrationHorizon(tte))
+}
+
+func (m *CalibrationModel) probability(price float64, horizon string) float64 {
+	price = clampProbability(price)
+	if m == nil || len(m.cells) == 0 {
+		return price
+	}
+	bin := calibrationPriceBin(price)
+	p := shrinkProbability(m.cells[calibrationCellKey(calibrationAllHorizons, bin)], price)
+	if horizon != "" && horizon != calibrationAllHorizons {
+		p = shrinkProbability(m.cells[calibrationCellKey(horizon, bin)], p)
+	}
+	return clampProbability(p)
+}
+
+func shrinkProbability(cell *calibrationCell, prior float64) float64 {
+	if cell == nil || cell.count == 0 {
+		return prior
+	}
+	return (float64(cell.yes) + calibrationPriorWeight*prior) / (float64(cell.count) + calibrationPriorWeight)
+}
+
+// BinsForHorizon returns reliability diagram points for one horizon.
+func (m *CalibrationModel) BinsForHorizon(horizon string) []CalibrationBin {
+	if m == nil {
+		return nil
+	}
+	horizon = strings.TrimSpace(horizon)
+	if horizon == "" {
+		horizon = calibrationAllHorizons
+	}
+	out := make([]CalibrationBin, 0, calibrationPriceBins)
+	for _, b := range m.Bins {
+		if b.Horizon == horizon {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// CalibrationService caches a model fitted from market_settlement_history.
+type CalibrationService struct {
+	Repo repository.Repository
+	TTL  time.Duration
+
+	mu    sync.Mutex
+	model *CalibrationModel
+}
+
+func (s *CalibrationService) Fit(ctx context.Context, since, until *time.Time) (*CalibrationModel, error) {
+	if s == nil || s.Repo == nil {
+		return nil, fmt.Errorf("repo unavailable")
+	}
+	samples, err := s.Repo.ListCalibrationSamples(ctx, since, until, 0)
+	if err != nil {
+		return nil, err
+	}
+	return FitCalibration(samples, time.Now()), nil
+}
+
+// Model returns the cached model, refitting it once TTL has passed.
+func (s *CalibrationService) Model(ctx context.Context) (*CalibrationModel, error) {
+	if s == nil {
+		return nil, nil
+	}
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.model != nil && time.Since(s.model.FittedAt) < ttl {
+		return s.model, nil
+	}
+	model, err := s.Fit(ctx, nil, nil)
+	if err != nil {
+		if s.model != nil {
+			return s.model, nil
+		}
+		return nil, err
+	}
+	s.model = model
+	return model, nil
+}
+
+// Probability is the entry point for strategies. It falls back to the raw
+// price when no model is available.
+func (s *CalibrationService) Probability(ctx context.Context, price float64, tte time.Duration) float64 {
+	model, err := s.Model(ctx)
+	if err != nil || model == nil {
+		return clampProbability(price)
+	}
+	return model.Probability(price, tte)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/repository"
+)
+
+func calibrationSamples(price float64, yes, no int, tte time.Duration) []repository.CalibrationSampleRow {
+	settled := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	opened := settled.Add(-tte)
+	out := make([]repository.CalibrationSampleRow, 0, yes+no)
+	for i := 0; i < yes+no; i++ {
+		outcome := "NO"
+		if i < yes {
+			outcome = "YES"
+		}
+		out = append(out, repository.CalibrationSampleRow{
+			Outcome:   outcome,
+			Price:     decimal.NewFromFloat(price),
+			OpenedAt:  &opened,
+			SettledAt: settled,
+		})
+	}
+	return out
+}
+
+func TestFitCalibration_ShrinksTowardsObservedRate(t *testing.T) {
+	// Markets priced at 0.85 settled YES only half the time.
+	model := FitCalibration(calibrationSamples(0.85, 50, 50, 48*time.Hour), time.Now())
+	if model.Samples != 100 {
+		t.Fatalf("samples=%d want=100", model.Samples)
+	}
+	p := model.Probability(0.85, 48*time.Hour)
+	if p >= 0.6 || p <= 0.5 {
+		t.Fatalf("p=%v want between 0.5 and 0.6", p)
+	}
+	bins := model.BinsForHorizon("1d_7d")
+	if len(bins) != 1 || bins[0].Count != 100 || math.Abs(bins[0].ObservedYes-0.5) > 1e-9 {
+		t.Fatalf("bins=%+v", bins)
+	}
+}
+
+func TestFitCalibration_EmptyBinFallsBackToPrice(t *testing.T) {
+	model := FitCalibration(calibrationSamples(0.85, 5, 5, 48*time.Hour), time.Now())
+	if p := model.Probability(0.15, 0); p != 0.15 {
+		t.Fatalf("p=%v want=0.15", p)
+	}
+	var empty *CalibrationModel
+	if p := empty.Probability(1.5, time.Hour); p != 1 {
+		t.Fatalf("p=%v want=1", p)
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// Campaign halt and block reasons.
+const (
+	CampaignReasonBudget     = "budget_exhausted"
+	CampaignReasonLossLimit  = "loss_limit"
+	CampaignReasonManual     = "manual"
+	CampaignReasonNotFound   = "not_found"
+	CampaignReasonNotStarted = "not_started"
+	CampaignReasonEnded      = "ended"
+	CampaignReasonHalted     = "halted"
+	CampaignReasonOverBudget = "over_budget"
+)
+
+// CampaignBlockedError is returned when a campaign does not admit a plan or
+// an execution.
+type CampaignBlockedError struct {
+	CampaignID   uint64
+	Reason       string
+	RemainingUSD *float64
+}
+
+func (e *CampaignBlockedError) Error() string {
+	return fmt.Sprintf("campaign %d blocked: %s", e.CampaignID, e.Reason)
+}
+
+// CampaignService enforces campaign budgets and loss limits. Run halts
+// campaigns whose live plans use the whole budget or whose realized loss
+// reaches the limit, and ends campaigns past EndsAt. A budget halt only
+// stops new plans and lifts itself when plans are cancelled; a loss or
+// manual halt also blocks the campaign's pending plans from executing.
+type CampaignService struct {
+	Repo     repository.Repository
+	Logger   *zap.Logger
+	Interval time.Duration
+}
+
+// CampaignReport is a campaign with its aggregates and limit usage.
+type CampaignReport struct {
+	Campaign           models.Campaign          `json:"campaign"`
+	Stats              repository.CampaignStats `json:"stats"`
+	LossUSD            float64                  `json:"loss_usd"`
+	RemainingBudgetUSD *float64                 `json:"remaining_budget_usd,omitempty"`
+	BudgetUsedPct      *float64                 `json:"budget_used_pct,omitempty"`
+	LossLimitUsedPct   *float64                 `json:"loss_limit_used_pct,omitempty"`
+}
+
+func (s *CampaignService) Run(ctx context.Context) error {
+	if s == nil || s.Repo == nil {
+		return nil
+	}
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.enforceAll(ctx); err != nil && s.Logger != nil {
+			s.Logger.Warn("campaign enforcement failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *CampaignService) enforceAll(ctx context.Context) error {
+	for _, status := range []string{models.CampaignStatusActive, models.CampaignStatusHalted} {
+		status := status
+		items, err := s.Repo.ListCampaigns(ctx, repository.ListCampaignsParams{Limit: 500, Status: &status})
+		if err != nil {
+			return err
+		}
+		for i := range items {
+			if _, err := s.Enforce(ctx, &items[i], time.Now().UTC()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Report computes the campaign's aggregates without changing it.
+func (s *CampaignService) Report(ctx context.Context, c models.Campaign) (CampaignReport, error) {
+	stats, err := s.Repo.CampaignStats(ctx, c.ID)
+	if err != nil {
+		return CampaignReport{}, err
+	}
+	rep := CampaignReport{Campaign: c, Stats: stats}
+	if stats.RealizedPnLUSD < 0 {
+		rep.LossUSD = -stats.RealizedPnLUSD
+	}
+	if budget := c.BudgetUSD.InexactFloat64(); budget > 0 {
+		remaining := budget - stats.CommittedUSD
+		if remaining < 0 {
+			remaining = 0
+		}
+		used := stats.CommittedUSD / budget * 100
+		rep.RemainingBudgetUSD = &remaining
+		rep.BudgetUsedPct = &used
+	}
+	if limit := c.MaxLossUSD.InexactFloat64(); limit > 0 {
+		used := rep.LossUSD / limit * 100
+		rep.LossLimitUsedPct = &used
+	}
+	return rep, nil
+}
+
+// Enforce applies the date window and limits to c, saving any status
+// change, and returns the resulting report.
+func (s *CampaignService) Enforce(ctx context.Context, c *models.Campaign, now time.Time) (CampaignReport, error) {
+	rep, err := s.Report(ctx, *c)
+	if err != nil {
+		return rep, err
+	}
+	if c.Status == models.CampaignStatusEnded {
+		return rep, nil
+	}
+	status, reason := c.Status, c.HaltReason
+	switch {
+	case c.EndsAt != nil && !now.Before(*c.EndsAt):
+		status, reason = models.CampaignStatusEnded, ""
+	case c.HaltReason == CampaignReasonManual || c.HaltReason == CampaignReasonLossLimit:
+		// Lifted only by an explicit resume.
+	case c.MaxLossUSD.IsPositive() && rep.LossUSD >= c.MaxLossUSD.InexactFloat64():
+		status, reason = models.CampaignStatusHalted, CampaignReasonLossLimit
+	case c.BudgetUSD.IsPositive() && rep.Stats.CommittedUSD >= c.BudgetUSD.InexactFloat64():
+		status, reason = models.CampaignStatusHalted, CampaignReasonBudget
+	case c.HaltReason == CampaignReasonBudget:
+		status, reason = models.CampaignStatusActive, ""
+	}
+	if status == c.Status && reason == c.HaltReason {
+		return rep, nil
+	}
+	if status == models.CampaignStatusHalted {
+		c.HaltedAt = &now
+	} else {
+		c.HaltedAt = nil
+	}
+	c.Status, c.HaltReason = status, reason
+	if err := s.Repo.UpdateCampaign(ctx, c); err != nil {
+		return rep, err
+	}
+	rep.Campaign = *c
+	if s.Logger != nil {
+		s.Logger.Warn("campaign status changed",
+			zap.Uint64("campaign_id", c.ID),
+			zap.String("status", status),
+			zap.String("reason", reason),
+			zap.Float64("committed_usd", rep.Stats.CommittedUSD),
+			zap.Float64("loss_usd", rep.LossUSD),
+		)
+	}
+	return rep, nil
+}
+
+// Admit checks that a new plan of sizeUSD fits campaignID for tenant ("" for
+// any desk). It returns a *CampaignBlockedError when it does not.
+func (s *CampaignService) Admit(ctx context.Context, campaignID uint64, tenant string, sizeUSD decimal.Decimal, now time.Time) (*models.Campaign, error) {
+	c, err := s.load(ctx, campaignID, tenant)
+	if err != nil {
+		return nil, err
+	}
+	rep, err := s.Enforce(ctx, c, now)
+	if err != nil {
+		return nil, err
+	}
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return nil, &CampaignBlockedError{CampaignID: c.ID, Reason: CampaignReasonNotStarted}
+	}
+	switch c.Status {
+	case models.CampaignStatusEnded:
+		return nil, &CampaignBlockedError{CampaignID: c.ID, Reason: CampaignReasonEnded}
+	case models.CampaignStatusHalted:
+		return nil, &CampaignBlockedError{CampaignID: c.ID, Reason: CampaignReasonHalted + ":" + c.HaltReason, RemainingUSD: rep.RemainingBudgetUSD}
+	}
+	if rep.RemainingBudgetUSD != nil && sizeUSD.InexactFloat64() > *rep.RemainingBudgetUSD {
+		return nil, &CampaignBlockedError{CampaignID: c.ID, Reason: CampaignReasonOverBudget, RemainingUSD: rep.RemainingBudgetUSD}
+	}
+	return c, nil
+}
+
+// CheckExecutable blocks execution of a campaign's plans while the campaign
+// has ended or is halted for any reason but its budget.
+func (s *CampaignService) CheckExecutable(ctx context.Context, campaignID uint64, now time.Time) error {
+	c, err := s.load(ctx, campaignID, "")
+	if err != nil {
+		return err
+	}
+	if _, err := s.Enforce(ctx, c, now); err != nil {
+		return err
+	}
+	switch {
+	case c.Status == models.CampaignStatusEnded:
+		return &CampaignBlockedError{CampaignID: c.ID, Reason: CampaignReasonEnded}
+	case c.Status == models.CampaignStatusHalted && c.HaltReason != CampaignReasonBudget:
+		return &CampaignBlockedError{CampaignID: c.ID, Reason: CampaignReasonHalted + ":" + c.HaltReason}
+	}
+	return nil
+}
+
+func (s *CampaignService) load(ctx context.Context, campaignID uint64, tenant string) (*models.Campaign, error) {
+	if s == nil || s.Repo == nil {
+		return nil, fmt.Errorf("campaigns unavailable")
+	}
+	c, err := s.Repo.GetCampaignByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil || (tenant != "" && !strings.EqualFold(c.Tenant, tenant)) {
+		return nil, &CampaignBlockedError{CampaignID: campaignID, Reason: CampaignReasonNotFound}
+	}
+	return c, nil
+}
+
+// IsCampaignBlocked unwraps a *CampaignBlockedError.
+func IsCampaignBlocked(err error) (*CampaignBlockedError, bool) {
+	var blocked *CampaignBlockedError
+	if errors.As(err, &blocked) {
+		return blocked, true
+	}
+	return nil, false
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type campaignRepo struct {
+	repository.Repository
+	campaign models.Campaign
+	stats    repository.CampaignStats
+	updates  int
+}
+
+func (r *campaignRepo) GetCampaignByID(ctx context.Context, id uint64) (*models.Campaign, error) {
+	if id != r.campaign.ID {
+		return nil, nil
+	}
+	c := r.campaign
+	return &c, nil
+}
+
+func (r *campaignRepo) CampaignStats(ctx context.Context, id uint64) (repository.CampaignStats, error) {
+	return r.stats, nil
+}
+
+func (r *campaignRepo) UpdateCampaign(ctx context.Context, item *models.Campaign) error {
+	r.campaign = *item
+	r.updates++
+	return nil
+}
+
+func TestCampaignService_AdmitBudget(t *testing.T) {
+	repo := &campaignRepo{
+		campaign: models.Campaign{ID: 7, Tenant: "a", Status: models.CampaignStatusActive, BudgetUSD: decimal.NewFromInt(1000)},
+		stats:    repository.CampaignStats{CommittedUSD: 800},
+	}
+	svc := &CampaignService{Repo: repo}
+	now := time.Now().UTC()
+
+	if _, err := svc.Admit(context.Background(), 7, "a", decimal.NewFromInt(150), now); err != nil {
+		t.Fatalf("admit within budget: %v", err)
+	}
+	_, err := svc.Admit(context.Background(), 7, "a", decimal.NewFromInt(300), now)
+	if blocked, ok := IsCampaignBlocked(err); !ok || blocked.Reason != CampaignReasonOverBudget || *blocked.RemainingUSD != 200 {
+		t.Fatalf("over budget: err=%v", err)
+	}
+	_, err = svc.Admit(context.Background(), 7, "b", decimal.NewFromInt(1), now)
+	if blocked, ok := IsCampaignBlocked(err); !ok || blocked.Reason != CampaignReasonNotFound {
+		t.Fatalf("other desk: err=%v", err)
+	}
+
+	// A fully committed budget halts new plans but not pending executions,
+	// and the halt lifts once plans are cancelled.
+	repo.stats.CommittedUSD = 1000
+	if _, ok := IsCampaignBlocked(mustErr(svc.Admit(context.Background(), 7, "a", decimal.NewFromInt(1), now))); !ok {
+		t.Fatal("exhausted budget admitted a plan")
+	}
+	if repo.campaign.Status != models.CampaignStatusHalted || repo.campaign.HaltReason != CampaignReasonBudget {
+		t.Fatalf("campaign = %+v", repo.campaign)
+	}
+	if err := svc.CheckExecutable(context.Background(), 7, now); err != nil {
+		t.Fatalf("budget halt blocked execution: %v", err)
+	}
+	repo.stats.CommittedUSD = 400
+	if _, err := svc.Admit(context.Background(), 7, "a", decimal.NewFromInt(100), now); err != nil {
+		t.Fatalf("budget halt did not lift: %v", err)
+	}
+}
+
+func TestCampaignService_LossLimitAndEnd(t *testing.T) {
+	end := time.Now().UTC().Add(time.Hour)
+	repo := &campaignRepo{
+		campaign: models.Campaign{ID: 3, Status: models.CampaignStatusActive, MaxLossUSD: decimal.NewFromInt(100), EndsAt: &end},
+		stats:    repository.CampaignStats{RealizedPnLUSD: -120},
+	}
+	svc := &CampaignService{Repo: repo}
+	now := time.Now().UTC()
+
+	err := svc.CheckExecutable(context.Background(), 3, now)
+	if blocked, ok := IsCampaignBlocked(err); !ok || blocked.Reason != CampaignReasonHalted+":"+CampaignReasonLossLimit {
+		t.Fatalf("loss limit: err=%v", err)
+	}
+	// A loss halt sticks after PnL recovers.
+	repo.stats.RealizedPnLUSD = 50
+	if err := svc.CheckExecutable(context.Background(), 3, now); err == nil {
+		t.Fatal("loss halt lifted without a resume")
+	}
+	if err := svc.CheckExecutable(context.Background(), 3, end); err == nil || repo.campaign.Status != models.CampaignStatusEnded {
+		t.Fatalf("past EndsAt: err=%v campaign=%+v", err, repo.campaign)
+	}
+}
+
+func mustErr(_ *models.Campaign, err error) error {
+	return err
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// Actions of the cash operation workflow.
+const (
+	CashOpActionApprove  = "approve"
+	CashOpActionReject   = "reject"
+	CashOpActionCancel   = "cancel"
+	CashOpActionComplete = "complete"
+)
+
+// CashOpTransition returns the status action moves an operation in status
+// to, or false when the action is not allowed in that status.
+func CashOpTransition(status, action string) (string, bool) {
+	switch action {
+	case CashOpActionApprove:
+		if status == models.CashOpRequested {
+			return models.CashOpApproved, true
+		}
+	case CashOpActionReject:
+		if status == models.CashOpRequested {
+			return models.CashOpRejected, true
+		}
+	case CashOpActionCancel:
+		if status == models.CashOpRequested || status == models.CashOpApproved {
+			return models.CashOpCancelled, true
+		}
+	case CashOpActionComplete:
+		if status == models.CashOpApproved {
+			return models.CashOpCompleted, true
+		}
+	}
+	return "", false
+}
+
+// CashOpActions lists the actions allowed in status.
+func CashOpActions(status string) []string {
+	out := []string{}
+	for _, a := range []string{CashOpActionApprove, CashOpActionReject, CashOpActionComplete, CashOpActionCancel} {
+		if _, ok := CashOpTransition(status, a); ok {
+			out = append(out, a)
+		}
+	}
+	if status == models.CashOpCompleted {
+		out = append(out, "reconcile")
+	}
+	return out
+}
+
+// OnchainTransfer is one transfer of the collateral token.
+type OnchainTransfer struct {
+	Hash      string          `json:"hash"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	AmountUSD decimal.Decimal `json:"amount_usd"`
+	At        time.Time       `json:"at"`
+}
+
+// TransferSource lists the recent collateral transfers in and out of a
+// wallet.
+type TransferSource interface {
+	Name() string
+	Transfers(ctx context.Context, wallet string) ([]OnchainTransfer, error)
+}
+
+// EtherscanTransferSource reads ERC-20 transfers of one token contract from
+// an Etherscan-compatible tokentx API such as Polygonscan.
+type EtherscanTransferSource struct {
+	Endpoint string
+	APIKey   string
+	Contract string
+	HTTP     *http.Client
+}
+
+func (s *EtherscanTransferSource) Name() string { return "etherscan" }
+
+func (s *EtherscanTransferSource) Transfers(ctx context.Context, wallet string) ([]OnchainTransfer, error) {
+	q := url.Values{}
+	q.Set("module", "account")
+	q.Set("action", "tokentx")
+	q.Set("contractaddress", strings.TrimSpace(s.Contract))
+	q.Set("address", wallet)
+	q.Set("page", "1")
+	q.Set("offset", "500")
+	q.Set("sort", "desc")
+	if key := strings.TrimSpace(s.APIKey); key != "" {
+		q.Set("apikey", key)
+	}
+	endpoint := strings.TrimSpace(s.Endpoint)
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	client := s.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+sep+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("http %d", resp.StatusCode)
+	}
+	var body tokenTxResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body.transfers()
+}
+
+type tokenTxResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// transfers converts a tokentx response. An empty history comes back as
+// status 0 with "No transactions found".
+func (r tokenTxResponse) transfers() ([]OnchainTransfer, error) {
+	var rows []struct {
+		Hash         string `json:"hash"`
+		From         string `json:"from"`
+		To           string `json:"to"`
+		Value        string `json:"value"`
+		TokenDecimal string `json:"tokenDecimal"`
+		TimeStamp    string `json:"timeStamp"`
+	}
+	if err := json.Unmarshal(r.Result, &rows); err != nil {
+		if r.Status == "0" && strings.Contains(strings.ToLower(r.Message), "no transactions") {
+			return nil, nil
+		}
+		var msg string
+		_ = json.Unmarshal(r.Result, &msg)
+		return nil, fmt.Errorf("tokentx: %s %s", r.Message, msg)
+	}
+	out := make([]OnchainTransfer, 0, len(rows))
+	for _, row := range rows {
+		value, err := decimal.NewFromString(row.Value)
+		if err != nil {
+			continue
+		}
+		decimals, err := decimal.NewFromString(row.TokenDecimal)
+		if err != nil {
+			decimals = decimal.NewFromInt(6)
+		}
+		ts, _ := decimal.NewFromString(row.TimeStamp)
+		out = append(out, OnchainTransfer{
+			Hash:      strings.ToLower(row.Hash),
+			From:      strings.ToLower(row.From),
+			To:        strings.ToLower(row.To),
+			AmountUSD: value.Shift(-int32(decimals.IntPart())),
+			At:        time.Unix(ts.IntPart(), 0).UTC(),
+		})
+	}
+	return out, nil
+}
+
+// CashOpsService reconciles completed cash operations against onchain
+// transfers of the trading wallet.
+type CashOpsService struct {
+	Repo   repository.Repository
+	Source TransferSource
+	Config config.CashOpsConfig
+	Logger *zap.Logger
+}
+
+// CashReconcileReport summarizes one reconciliation pass.
+type CashReconcileReport struct {
+	Checked    int      `json:"checked"`
+	Reconciled int      `json:"reconciled"`
+	Unmatched  int      `json:"unmatched"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// ErrNoTransferSource is returned when reconciliation is not configured.
+var ErrNoTransferSource = errors.New("no onchain transfer source configured")
+
+// matchTransfer checks op against the wallet's transfers: the transfer with
+// op's tx hash must move the token in op's direction for op's amount within
+// tolerance. It returns the matched transfer or why there is none.
+func matchTransfer(op models.CashOperation, wallet string, transfers []OnchainTransfer, tolerance decimal.Decimal) (*OnchainTransfer, string) {
+	hash := strings.ToLower(strings.TrimSpace(op.TxHash))
+	if hash == "" {
+		return nil, "tx_hash missing"
+	}
+	wallet = strings.ToLower(strings.TrimSpace(wallet))
+	var found bool
+	for i := range transfers {
+		t := transfers[i]
+		if t.Hash != hash {
+			continue
+		}
+		found = true
+		if op.Kind == models.CashOpDeposit && t.To != wallet {
+			continue
+		}
+		if op.Kind == models.CashOpWithdrawal && t.From != wallet {
+			continue
+		}
+		if t.AmountUSD.Sub(op.AmountUSD).Abs().GreaterThan(tolerance) {
+			return nil, fmt.Sprintf("onchain amount %s differs from %s", t.AmountUSD.StringFixed(2), op.AmountUSD.StringFixed(2))
+		}
+		return &t, ""
+	}
+	if found {
+		return nil, "transfer direction does not match " + op.Kind
+	}
+	return nil, "transfer not found"
+}
+
+func (s *CashOpsService) wallet(op models.CashOperation) string {
+	if w := strings.TrimSpace(op.Wallet); w != "" {
+		return w
+	}
+	return strings.TrimSpace(s.Config.Wallet)
+}
+
+// Reconcile checks the given completed operations, or all completed ones
+// when ops is nil, and marks the matched ones reconciled.
+func (s *CashOpsService) Reconcile(ctx context.Context, ops []models.CashOperation, now time.Time) (CashReconcileReport, error) {
+	var report CashReconcileReport
+	if s == nil || s.Repo == nil {
+		return report, nil
+	}
+	if s.Source == nil {
+		return report, ErrNoTransferSource
+	}
+	if ops == nil {
+		status := models.CashOpCompleted
+		rows, err := s.Repo.ListCashOperations(ctx, repository.ListCashOperationsParams{Status: &status, Limit: 500})
+		if err != nil {
+			return report, err
+		}
+		ops = rows
+	}
+	tolerance := decimal.NewFromFloat(s.Config.ToleranceUSD)
+	byWallet := map[string][]OnchainTransfer{}
+	for i := range ops {
+		op := ops[i]
+		if op.Status != models.CashOpCompleted {
+			continue
+		}
+		wallet := s.wallet(op)
+		if wallet == "" {
+			report.Errors = append(report.Errors, fmt.Sprintf("cash op %d: no wallet", op.ID))
+			continue
+		}
+		key := strings.ToLower(wallet)
+		transfers, ok := byWallet[key]
+		if !ok {
+			rows, err := s.Source.Transfers(ctx, wallet)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", wallet, err))
+				continue
+			}
+			transfers = rows
+			byWallet[key] = rows
+		}
+		report.Checked++
+		matched, reason := matchTransfer(op, wallet, transfers, tolerance)
+		if matched == nil {
+			report.Unmatched++
+			// A known mismatch is stored and notified once.
+			if reason == op.ReconcileError {
+				continue
+			}
+			op.ReconcileError = reason
+		} else {
+			amount := matched.AmountUSD
+			op.Status = models.CashOpReconciled
+			op.OnchainAmountUSD = &amount
+			op.ReconcileError = ""
+			op.ReconciledAt = &now
+		}
+		if err := s.Repo.UpdateCashOperation(ctx, &op); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("cash op %d: %v", op.ID, err))
+			continue
+		}
+		ops[i] = op
+		if matched != nil {
+			report.Reconciled++
+			s.notify(ctx, "polymarket_cash_op_reconciled", "info", op)
+		} else {
+			s.notify(ctx, "polymarket_cash_op_unreconciled", "warn", op)
+		}
+	}
+	return report, nil
+}
+
+// Run reconciles completed operations every ReconcileInterval; it does
+// nothing without a transfer source.
+func (s *CashOpsService) Run(ctx context.Context) error {
+	if s == nil || s.Repo == nil || s.Source == nil {
+		return nil
+	}
+	interval := s.Config.ReconcileInterval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			report, err := s.Reconcile(ctx, nil, time.Now().UTC())
+			if err != nil && s.Logger != nil {
+				s.Logger.Warn("cash ops reconcile failed", zap.Error(err))
+			}
+			if len(report.Errors) > 0 && s.Logger != nil {
+				s.Logger.Warn("cash ops reconcile errors", zap.Strings("errors", report.Errors))
+			}
+		}
+	}
+}
+
+func (s *CashOpsService) notify(ctx context.Context, action, level string, op models.CashOperation) {
+	fields := map[string]any{
+		"cash_op_id": op.ID,
+		"kind":       op.Kind,
+		"amount_usd": op.AmountUSD.StringFixed(2),
+		"tx_hash":    op.TxHash,
+		"status":     op.Status,
+	}
+	if op.ReconcileError != "" {
+		fields["reason"] = op.ReconcileError
+	}
+	paas.LogBestEffortCtx(ctx, action, level, fields)
+}
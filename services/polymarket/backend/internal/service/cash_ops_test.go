@@ -0,0 +1,85 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestCashOpTransition(t *testing.T) {
+	cases := []struct {
+		status, action, want string
+		ok                   bool
+	}{
+		{models.CashOpRequested, CashOpActionApprove, models.CashOpApproved, true},
+		{models.CashOpRequested, CashOpActionReject, models.CashOpRejected, true},
+		{models.CashOpRequested, CashOpActionComplete, "", false},
+		{models.CashOpApproved, CashOpActionComplete, models.CashOpCompleted, true},
+		{models.CashOpApproved, CashOpActionCancel, models.CashOpCancelled, true},
+		{models.CashOpApproved, CashOpActionReject, "", false},
+		{models.CashOpCompleted, CashOpActionCancel, "", false},
+		{models.CashOpRejected, CashOpActionApprove, "", false},
+	}
+	for _, tc := range cases {
+		got, ok := CashOpTransition(tc.status, tc.action)
+		if got != tc.want || ok != tc.ok {
+			t.Fatalf("%s+%s = %q,%v want %q,%v", tc.status, tc.action, got, ok, tc.want, tc.ok)
+		}
+	}
+	if got := CashOpActions(models.CashOpCompleted); len(got) != 1 || got[0] != "reconcile" {
+		t.Fatalf("completed actions=%v", got)
+	}
+}
+
+func TestMatchTransfer(t *testing.T) {
+	wallet := "0xABC"
+	transfers := []OnchainTransfer{
+		{Hash: "0x01", From: "0xfeed", To: "0xabc", AmountUSD: decimal.NewFromInt(100)},
+		{Hash: "0x02", From: "0xabc", To: "0xfeed", AmountUSD: decimal.NewFromFloat(49.5)},
+	}
+	tol := decimal.NewFromFloat(0.01)
+	dep := models.CashOperation{Kind: models.CashOpDeposit, AmountUSD: decimal.NewFromInt(100), TxHash: "0x01"}
+	if m, reason := matchTransfer(dep, wallet, transfers, tol); m == nil {
+		t.Fatalf("deposit not matched: %s", reason)
+	}
+	// A deposit naming an outgoing transfer does not match.
+	dep.TxHash = "0x02"
+	if m, reason := matchTransfer(dep, wallet, transfers, tol); m != nil || reason != "transfer direction does not match deposit" {
+		t.Fatalf("matched=%v reason=%q", m, reason)
+	}
+	wd := models.CashOperation{Kind: models.CashOpWithdrawal, AmountUSD: decimal.NewFromInt(50), TxHash: "0X02"}
+	if m, reason := matchTransfer(wd, wallet, transfers, tol); m != nil || reason != "onchain amount 49.50 differs from 50.00" {
+		t.Fatalf("matched=%v reason=%q", m, reason)
+	}
+	wd.TxHash = "0x03"
+	if _, reason := matchTransfer(wd, wallet, transfers, tol); reason != "transfer not found" {
+		t.Fatalf("reason=%q", reason)
+	}
+}
+
+func TestTokenTxTransfers(t *testing.T) {
+	var body tokenTxResponse
+	raw := `{"status":"1","message":"OK","result":[{"hash":"0xAA","from":"0xF","to":"0xT","value":"12500000","tokenDecimal":"6","timeStamp":"1700000000"}]}`
+	if err := json.Unmarshal([]byte(raw), &body); err != nil {
+		t.Fatal(err)
+	}
+	got, err := body.transfers()
+	if err != nil || len(got) != 1 {
+		t.Fatalf("transfers=%v err=%v", got, err)
+	}
+	if got[0].Hash != "0xaa" || !got[0].AmountUSD.Equal(decimal.NewFromFloat(12.5)) || got[0].At.Unix() != 1700000000 {
+		t.Fatalf("transfer=%+v", got[0])
+	}
+
+	empty := tokenTxResponse{Status: "0", Message: "No transactions found", Result: json.RawMessage(`[]`)}
+	if got, err := empty.transfers(); err != nil || len(got) != 0 {
+		t.Fatalf("empty=%v err=%v", got, err)
+	}
+	failed := tokenTxResponse{Status: "0", Message: "NOTOK", Result: json.RawMessage(`"Invalid API Key"`)}
+	if _, err := failed.transfers(); err == nil {
+		t.Fatal("expected error")
+	}
+}
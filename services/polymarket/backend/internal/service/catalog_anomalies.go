@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// Catalog anomaly signal types.
+const (
+	SignalLiquidityCollapse = "liquidity_collapse"
+	SignalVolumeSpike       = "volume_spike"
+)
+
+// minBaselineGap skips a market seen again within one sync run, e.g. by the
+// events and markets scopes, so its volume rate is not taken over seconds.
+const minBaselineGap = time.Minute
+
+// CatalogAnomalyMonitor compares synced markets with their trailing
+// liquidity and volume baselines and signals sudden changes.
+type CatalogAnomalyMonitor struct {
+	Repo   repository.Repository
+	Config config.CatalogAnomalyConfig
+	Logger *zap.Logger
+	// Notify routes anomaly alerts to the notification dispatcher; nil
+	// broadcasts through the platform client in ctx.
+	Notify func(ctx context.Context, event, message string) error
+
+	mu     sync.Mutex
+	ingest func(ctx context.Context, sig models.Signal) bool
+}
+
+// SetIngest hands later signals to the signal hub. Until it is set, as when
+// the strategy engine is off, signals are stored directly.
+func (m *CatalogAnomalyMonitor) SetIngest(fn func(ctx context.Context, sig models.Signal) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ingest = fn
+}
+
+// catalogAnomaly is one market's reading outside its baseline. New is false
+// while the same anomaly persists from the previous sync.
+type catalogAnomaly struct {
+	Type     string
+	Current  float64
+	Baseline float64
+	Strength float64
+	New      bool
+}
+
+// Observe folds a synced page into the markets' baselines, emits a signal
+// per anomaly and notifies the ones newly seen. It returns the number of
+// signals emitted.
+func (m *CatalogAnomalyMonitor) Observe(ctx context.Context, markets []models.Market, now time.Time) (int, error) {
+	if m == nil || m.Repo == nil || !m.Config.Enabled || len(markets) == 0 {
+		return 0, nil
+	}
+	ids := make([]string, 0, len(markets))
+	for _, mk := range markets {
+		if !mk.Closed && (mk.Liquidity != nil || mk.Volume != nil) {
+			ids = append(ids, mk.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	stored, err := m.Repo.ListMarketBaselines(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	baselines := make(map[string]models.MarketBaseline, len(stored))
+	for _, b := range stored {
+		baselines[b.MarketID] = b
+	}
+
+	type found struct {
+		market    models.Market
+		anomalies []catalogAnomaly
+	}
+	var hits []found
+	updated := make([]models.MarketBaseline, 0, len(ids))
+	seen := map[string]bool{}
+	for _, mk := range markets {
+		if mk.Closed || (mk.Liquidity == nil && mk.Volume == nil) || seen[mk.ID] {
+			continue
+		}
+		seen[mk.ID] = true
+		b, ok := baselines[mk.ID]
+		if !ok {
+			b = models.MarketBaseline{MarketID: mk.ID}
+		} else if now.Sub(b.LastSampledAt) < minBaselineGap {
+			continue
+		}
+		var liquidity, volume *float64
+		if mk.Liquidity != nil {
+			v := mk.Liquidity.InexactFloat64()
+			liquidity = &v
+		}
+		if mk.Volume != nil {
+			v := mk.Volume.InexactFloat64()
+			volume = &v
+		}
+		if anomalies := observeBaseline(&b, liquidity, volume, now, m.Config); len(anomalies) > 0 {
+			hits = append(hits, found{market: mk, anomalies: anomalies})
+		}
+		updated = append(updated, b)
+	}
+	if err := m.Repo.UpsertMarketBaselines(ctx, updated); err != nil {
+		return 0, err
+	}
+	if len(hits) == 0 {
+		return 0, nil
+	}
+
+	hitIDs := make([]string, 0, len(hits))
+	for _, h := range hits {
+		hitIDs = append(hitIDs, h.market.ID)
+	}
+	heldIDs, err := m.Repo.ListHeldMarketIDs(ctx, hitIDs)
+	if err != nil {
+		return 0, err
+	}
+	held := make(map[string]bool, len(heldIDs))
+	for _, id := range heldIDs {
+		held[id] = true
+	}
+	n := 0
+	for _, h := range hits {
+		for _, a := range h.anomalies {
+			m.emit(ctx, h.market, a, held[h.market.ID], now)
+			n++
+			if a.New && (held[h.market.ID] || m.Config.NotifyUnheld) {
+				m.warnAnomaly(ctx, h.market, a, held[h.market.ID])
+			}
+		}
+	}
+	return n, nil
+}
+
+// observeBaseline checks one market's sync against b and then folds the
+// sync into b. Volume is Gamma's cumulative volume, so its rate is the
+// growth since the previous sync per hour.
+func observeBaseline(b *models.MarketBaseline, liquidity, volume *float64, now time.Time, cfg config.CatalogAnomalyConfig) []catalogAnomaly {
+	var out []catalogAnomaly
+	warm := b.Samples >= cfg.MinSamples
+	alpha := cfg.Alpha
+	if alpha <= 0 || alpha > 1 {
+		alpha = 0.3
+	}
+	if liquidity != nil {
+		cur := *liquidity
+		collapsed := warm && b.Liquidity >= cfg.MinBaselineLiquidityUSD && b.Liquidity > 0 && cur < b.Liquidity*(1-cfg.LiquidityDropPct)
+		if collapsed {
+			out = append(out, catalogAnomaly{Type: SignalLiquidityCollapse, Current: cur, Baseline: b.Liquidity, Strength: 1 - cur/b.Liquidity, New: !b.LiquidityCollapsed})
+		}
+		b.LiquidityCollapsed = collapsed
+		if b.Samples == 0 {
+			b.Liquidity = cur
+		} else {
+			b.Liquidity = alpha*cur + (1-alpha)*b.Liquidity
+		}
+	}
+	if volume != nil {
+		if hours := now.Sub(b.LastSampledAt).Hours(); b.Samples > 0 && hours > 0 {
+			rate := (*volume - b.LastVolume) / hours
+			if rate < 0 {
+				rate = 0
+			}
+			spiking := warm && b.VolumeRate > 0 && rate >= cfg.MinVolumeRateUSD && rate >= b.VolumeRate*cfg.VolumeSpikeMultiple
+			if spiking {
+				out = append(out, catalogAnomaly{Type: SignalVolumeSpike, Current: rate, Baseline: b.VolumeRate, Strength: rate / b.VolumeRate, New: !b.VolumeSpiking})
+			}
+			b.VolumeSpiking = spiking
+			if b.Samples == 1 {
+				b.VolumeRate = rate
+			} else {
+				b.VolumeRate = alpha*rate + (1-alpha)*b.VolumeRate
+			}
+		}
+		b.LastVolume = *volume
+	}
+	b.Samples++
+	b.LastSampledAt = now
+	return out
+}
+
+func (m *CatalogAnomalyMonitor) emit(ctx context.Context, mk models.Market, a catalogAnomaly, held bool, now time.Time) {
+	direction := "DOWN"
+	if a.Type == SignalVolumeSpike {
+		direction = "UP"
+	}
+	marketID, eventID := mk.ID, mk.EventID
+	sig := models.Signal{
+		SignalType: a.Type,
+		Source:     "catalog_sync",
+		MarketID:   &marketID,
+		EventID:    &eventID,
+		Strength:   a.Strength,
+		Direction:  direction,
+		Payload: mustJSON(map[string]any{
+			"current":  a.Current,
+			"baseline": a.Baseline,
+			"held":     held,
+			"question": mk.Question,
+		}),
+		CreatedAt: now,
+	}
+	if m.Config.SignalTTL > 0 {
+		expires := now.Add(m.Config.SignalTTL)
+		sig.ExpiresAt = &expires
+	}
+	m.mu.Lock()
+	ingest := m.ingest
+	m.mu.Unlock()
+	if ingest != nil {
+		ingest(ctx, sig)
+		return
+	}
+	if err := m.Repo.InsertSignal(ctx, &sig); err != nil && m.Logger != nil {
+		m.Logger.Warn("catalog anomaly signal insert failed", zap.String("market_id", mk.ID), zap.Error(err))
+	}
+}
+
+func (m *CatalogAnomalyMonitor) warnAnomaly(ctx context.Context, mk models.Market, a catalogAnomaly, held bool) {
+	if m.Logger != nil {
+		m.Logger.Warn("catalog market anomaly",
+			zap.String("type", a.Type),
+			zap.String("market_id", mk.ID),
+			zap.Float64("current", a.Current),
+			zap.Float64("baseline", a.Baseline),
+			zap.Bool("held", held),
+		)
+	}
+	paas.LogBestEffortCtx(ctx, "polymarket_catalog_anomaly", "warn", map[string]any{
+		"type":      a.Type,
+		"market_id": mk.ID,
+		"current":   a.Current,
+		"baseline":  a.Baseline,
+		"held":      held,
+	})
+	notify := m.Notify
+	if notify == nil {
+		notify = paas.BroadcastCtx
+	}
+	var message string
+	if a.Type == SignalLiquidityCollapse {
+		message = fmt.Sprintf("[warning] polymarket market %s liquidity collapsed to $%.0f from a $%.0f baseline", mk.ID, a.Current, a.Baseline)
+	} else {
+		message = fmt.Sprintf("[warning] polymarket market %s volume spiking at $%.0f/h, %.1fx its $%.0f/h baseline", mk.ID, a.Current, a.Strength, a.Baseline)
+	}
+	if held {
+		message += " (held position)"
+	}
+	message += ": " + mk.Question
+	if err := notify(ctx, m.Config.NotifyEvent, message); err != nil && m.Logger != nil {
+		m.Logger.Warn("catalog anomaly notify failed", zap.String("market_id", mk.ID), zap.Error(err))
+	}
+}
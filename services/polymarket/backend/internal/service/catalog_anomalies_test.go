@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type anomalyRepo struct {
+	repository.Repository
+	baselines map[string]models.MarketBaseline
+	held      []string
+	signals   []models.Signal
+}
+
+func (r *anomalyRepo) ListMarketBaselines(_ context.Context, ids []string) ([]models.MarketBaseline, error) {
+	var out []models.MarketBaseline
+	for _, id := range ids {
+		if b, ok := r.baselines[id]; ok {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (r *anomalyRepo) UpsertMarketBaselines(_ context.Context, items []models.MarketBaseline) error {
+	for _, b := range items {
+		r.baselines[b.MarketID] = b
+	}
+	return nil
+}
+
+func (r *anomalyRepo) ListHeldMarketIDs(context.Context, []string) ([]string, error) {
+	return r.held, nil
+}
+
+func (r *anomalyRepo) InsertSignal(_ context.Context, item *models.Signal) error {
+	r.signals = append(r.signals, *item)
+	return nil
+}
+
+func TestCatalogAnomalyMonitorObserve(t *testing.T) {
+	cfg := config.CatalogAnomalyConfig{
+		Enabled:                 true,
+		Alpha:                   0.5,
+		MinSamples:              3,
+		LiquidityDropPct:        0.5,
+		MinBaselineLiquidityUSD: 1000,
+		VolumeSpikeMultiple:     4,
+		MinVolumeRateUSD:        100,
+		SignalTTL:               time.Hour,
+		NotifyEvent:             "polymarket.catalog_anomaly",
+	}
+	repo := &anomalyRepo{baselines: map[string]models.MarketBaseline{}, held: []string{"m1"}}
+	var notes []string
+	mon := &CatalogAnomalyMonitor{Repo: repo, Config: cfg, Notify: func(_ context.Context, event, msg string) error {
+		notes = append(notes, msg)
+		return nil
+	}}
+	ctx := context.Background()
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	market := func(liquidity, volume int64) []models.Market {
+		l, v := decimal.NewFromInt(liquidity), decimal.NewFromInt(volume)
+		return []models.Market{{ID: "m1", EventID: "e1", Question: "q", Liquidity: &l, Volume: &v}}
+	}
+
+	// Four hourly syncs of steady 10k liquidity and 50/h volume warm up.
+	for i := 0; i < 4; i++ {
+		n, err := mon.Observe(ctx, market(10000, int64(i)*50), start.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 0 {
+			t.Fatalf("sync %d: %d anomalies during warm up", i, n)
+		}
+	}
+
+	// Liquidity drops to 2k while volume jumps by 1000 in an hour.
+	n, err := mon.Observe(ctx, market(2000, 1150), start.Add(4*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(repo.signals) != 2 {
+		t.Fatalf("n=%d signals=%d want liquidity_collapse and volume_spike", n, len(repo.signals))
+	}
+	types := map[string]models.Signal{}
+	for _, s := range repo.signals {
+		types[s.SignalType] = s
+	}
+	if s, ok := types[SignalLiquidityCollapse]; !ok || s.Strength < 0.79 || s.ExpiresAt == nil {
+		t.Fatalf("liquidity signal=%+v", s)
+	}
+	if s, ok := types[SignalVolumeSpike]; !ok || s.Strength < 19 {
+		t.Fatalf("volume signal=%+v", s)
+	}
+	if len(notes) != 2 || !strings.Contains(notes[0], "held position") {
+		t.Fatalf("notes=%v want two held-market alerts", notes)
+	}
+
+	// A sync seconds later is skipped; a persisting collapse signals again
+	// without a second alert.
+	if n, _ := mon.Observe(ctx, market(2000, 1150), start.Add(4*time.Hour+time.Second)); n != 0 {
+		t.Fatalf("resampled within a minute: %d", n)
+	}
+	n, err = mon.Observe(ctx, market(1000, 1200), start.Add(5*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || len(notes) != 2 {
+		t.Fatalf("n=%d notes=%d want one ongoing collapse signal and no new alert", n, len(notes))
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+)
+
+const (
+	MarketChangeQuestion         = "question"
+	MarketChangeDescription      = "description"
+	MarketChangeResolutionSource = "resolution_source"
+	MarketChangeEndDate          = "end_date"
+)
+
+// marketChangeFields maps a changelog field to its key in the Gamma market
+// raw_json. Description carries the resolution criteria.
+var marketChangeFields = []struct {
+	Field string
+	Key   string
+	Time  bool
+}{
+	{Field: MarketChangeQuestion, Key: "question"},
+	{Field: MarketChangeDescription, Key: "description"},
+	{Field: MarketChangeResolutionSource, Key: "resolutionSource"},
+	{Field: MarketChangeEndDate, Key: "endDate", Time: true},
+}
+
+// diffMarketRaw compares two raw_json snapshots of a market and returns the
+// semantic edits. Case and whitespace changes are ignored and end dates are
+// compared as instants. A field absent from the stored snapshot is skipped:
+// older rows may predate it.
+func diffMarketRaw(marketID string, prev, next []byte, scope string, now time.Time) []models.MarketChange {
+	var before, after map[string]json.RawMessage
+	if json.Unmarshal(prev, &before) != nil || json.Unmarshal(next, &after) != nil {
+		return nil
+	}
+	var out []models.MarketChange
+	for _, f := range marketChangeFields {
+		oldRaw, ok := before[f.Key]
+		if !ok {
+			continue
+		}
+		oldVal, newVal := rawString(oldRaw), rawString(after[f.Key])
+		if f.Time {
+			if sameInstant(oldVal, newVal) {
+				continue
+			}
+		} else if normalizeText(oldVal) == normalizeText(newVal) {
+			continue
+		}
+		out = append(out, models.MarketChange{
+			MarketID:   marketID,
+			Field:      f.Field,
+			OldValue:   oldVal,
+			NewValue:   newVal,
+			Scope:      scope,
+			DetectedAt: now,
+		})
+	}
+	return out
+}
+
+func rawString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
+
+func normalizeText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func sameInstant(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ta, errA := time.Parse(time.RFC3339, strings.TrimSpace(a))
+	tb, errB := time.Parse(time.RFC3339, strings.TrimSpace(b))
+	if errA != nil || errB != nil {
+		return normalizeText(a) == normalizeText(b)
+	}
+	return ta.Equal(tb)
+}
+
+// detectMarketChanges diffs incoming markets against their stored rows and
+// flags changes to markets we hold. It runs before the upsert overwrites
+// raw_json.
+func (s *CatalogSyncService) detectMarketChanges(ctx context.Context, scope string, markets []models.Market, now time.Time) ([]models.MarketChange, error) {
+	if len(markets) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(markets))
+	for _, m := range markets {
+		ids = append(ids, m.ID)
+	}
+	stored, err := s.Store.ListMarketsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	prev := make(map[string]models.Market, len(stored))
+	for _, m := range stored {
+		prev[m.ID] = m
+	}
+	var changes []models.MarketChange
+	changed := map[string]struct{}{}
+	for _, m := range markets {
+		old, ok := prev[m.ID]
+		if !ok {
+			continue
+		}
+		diff := diffMarketRaw(m.ID, old.RawJSON, m.RawJSON, scope, now)
+		if len(diff) > 0 {
+			changed[m.ID] = struct{}{}
+			changes = append(changes, diff...)
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	changedIDs := make([]string, 0, len(changed))
+	for id := range changed {
+		changedIDs = append(changedIDs, id)
+	}
+	held, err := s.Store.ListHeldMarketIDs(ctx, changedIDs)
+	if err != nil {
+		return nil, err
+	}
+	heldSet := make(map[string]struct{}, len(held))
+	for _, id := range held {
+		heldSet[id] = struct{}{}
+	}
+	for i := range changes {
+		_, changes[i].HeldPosition = heldSet[changes[i].MarketID]
+	}
+	return changes, nil
+}
+
+// logMarketChanges warns once per held market whose terms changed; other
+// edits are only recorded.
+func (s *CatalogSyncService) logMarketChanges(scope string, changes []models.MarketChange) {
+	if s.Logger == nil || len(changes) == 0 {
+		return
+	}
+	fields := map[string][]string{}
+	for _, c := range changes {
+		if c.HeldPosition {
+			fields[c.MarketID] = append(fields[c.MarketID], c.Field)
+		}
+	}
+	for marketID, changed := range fields {
+		s.Logger.Warn("held market terms changed",
+			zap.String("scope", scope),
+			zap.String("market_id", marketID),
+			zap.Strings("fields", changed),
+		)
+	}
+	s.Logger.Info("catalog market changes recorded",
+		zap.String("scope", scope),
+		zap.Int("count", len(changes)),
+		zap.Int("held_markets", len(fields)),
+	)
+}
+
+func countHeldMarkets(changes []models.MarketChange) int {
+	seen := map[string]struct{}{}
+	for _, c := range changes {
+		if c.HeldPosition {
+			seen[c.MarketID] = struct{}{}
+		}
+	}
+	return len(seen)
+}
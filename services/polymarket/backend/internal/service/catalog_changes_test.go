@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffMarketRaw(t *testing.T) {
+	now := time.Now().UTC()
+	prev := []byte(`{"question":"Will X happen by June 30?","description":"Resolves YES if X.","endDate":"2026-06-30T12:00:00Z"}`)
+
+	// Whitespace, case and time-zone-only edits are not semantic.
+	same := []byte(`{"question":"will  X happen by June 30? ","description":"Resolves YES if X.","endDate":"2026-06-30T14:00:00+02:00","resolutionSource":"https://x"}`)
+	if got := diffMarketRaw("m1", prev, same, "events", now); len(got) != 0 {
+		t.Fatalf("expected no changes, got %+v", got)
+	}
+
+	next := []byte(`{"question":"Will X happen by July 31?","description":"Resolves YES if X or Y.","endDate":"2026-07-31T12:00:00Z"}`)
+	got := diffMarketRaw("m1", prev, next, "events", now)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", got)
+	}
+	want := []string{MarketChangeQuestion, MarketChangeDescription, MarketChangeEndDate}
+	for i, c := range got {
+		if c.Field != want[i] || c.MarketID != "m1" || c.OldValue == c.NewValue {
+			t.Fatalf("change %d: %+v", i, c)
+		}
+	}
+	if got[2].OldValue != "2026-06-30T12:00:00Z" || got[2].NewValue != "2026-07-31T12:00:00Z" {
+		t.Fatalf("end date change: %+v", got[2])
+	}
+}
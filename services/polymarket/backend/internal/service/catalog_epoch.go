@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"polymarket/internal/models"
+)
+
+// stampCatalogEpochs sets CatalogEpoch on the incoming markets and their
+// tokens before the upsert. A market keeps its stored epoch unless it is
+// new, its tradable metadata or token set changed, or a semantic edit was
+// detected; then it and all its tokens move to the epoch of this sync, and
+// books written before are reported stale until rewritten.
+func (s *CatalogSyncService) stampCatalogEpochs(ctx context.Context, markets []models.Market, tokens []models.Token, changes []models.MarketChange, now time.Time) error {
+	if len(markets) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(markets))
+	for _, m := range markets {
+		ids = append(ids, m.ID)
+	}
+	storedMarkets, err := s.Store.ListMarketsByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	storedTokens, err := s.Store.ListTokensByMarketIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	edited := make(map[string]bool, len(changes))
+	for _, c := range changes {
+		edited[c.MarketID] = true
+	}
+	assignCatalogEpochs(markets, tokens, storedMarkets, storedTokens, edited, now.UnixMilli())
+	return nil
+}
+
+// assignCatalogEpochs is the pure part of stampCatalogEpochs; epoch is the
+// value a changed market moves to.
+func assignCatalogEpochs(markets []models.Market, tokens []models.Token, storedMarkets []models.Market, storedTokens []models.Token, edited map[string]bool, epoch int64) {
+	prev := make(map[string]models.Market, len(storedMarkets))
+	for _, m := range storedMarkets {
+		prev[m.ID] = m
+	}
+	prevTokens := map[string][]models.Token{}
+	for _, t := range storedTokens {
+		prevTokens[t.MarketID] = append(prevTokens[t.MarketID], t)
+	}
+	nextTokens := map[string][]models.Token{}
+	for _, t := range tokens {
+		nextTokens[t.MarketID] = append(nextTokens[t.MarketID], t)
+	}
+	epochs := make(map[string]int64, len(markets))
+	for i := range markets {
+		m := &markets[i]
+		old, ok := prev[m.ID]
+		if ok && old.CatalogEpoch != 0 && !edited[m.ID] &&
+			catalogFingerprint(old, prevTokens[m.ID]) == catalogFingerprint(*m, nextTokens[m.ID]) {
+			m.CatalogEpoch = old.CatalogEpoch
+		} else {
+			m.CatalogEpoch = epoch
+		}
+		epochs[m.ID] = m.CatalogEpoch
+	}
+	for i := range tokens {
+		tokens[i].CatalogEpoch = epochs[tokens[i].MarketID]
+	}
+}
+
+// catalogFingerprint covers what a strategy trades on: identity, tick size,
+// tradability and the outcome tokens. Prices and volume are left out so
+// routine syncs keep the epoch.
+func catalogFingerprint(m models.Market, tokens []models.Token) string {
+	negRisk := "-"
+	if m.NegRisk != nil {
+		negRisk = fmt.Sprint(*m.NegRisk)
+	}
+	parts := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		parts = append(parts, fmt.Sprintf("%s:%s:%d", t.ID, t.Outcome, t.OutcomeIndex))
+	}
+	sort.Strings(parts)
+	return strings.Join([]string{
+		m.ConditionID,
+		m.TickSize.String(),
+		negRisk,
+		fmt.Sprint(m.Active),
+		fmt.Sprint(m.Closed),
+		strings.Join(parts, ","),
+	}, "|")
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestAssignCatalogEpochs(t *testing.T) {
+	market := func(id string, epoch int64) models.Market {
+		return models.Market{ID: id, ConditionID: "c-" + id, TickSize: decimal.RequireFromString("0.01"), Active: true, CatalogEpoch: epoch}
+	}
+	token := func(id, marketID, outcome string, idx int) models.Token {
+		return models.Token{ID: id, MarketID: marketID, Outcome: outcome, OutcomeIndex: idx}
+	}
+	stored := []models.Market{market("same", 100), market("tokens", 100), market("edited", 100), market("legacy", 0), market("closed", 100)}
+	storedTokens := []models.Token{
+		token("s1", "same", "Yes", 0), token("s2", "same", "No", 1),
+		token("t1", "tokens", "Yes", 0), token("t2", "tokens", "No", 1),
+		token("e1", "edited", "Yes", 0),
+		token("c1", "closed", "Yes", 0),
+	}
+
+	same := market("same", 0)
+	vol := decimal.NewFromInt(5000)
+	same.Volume = &vol
+	same.TickSize = decimal.RequireFromString("0.0100000000")
+	closed := market("closed", 0)
+	closed.Closed = true
+	markets := []models.Market{same, market("tokens", 0), market("edited", 0), market("legacy", 0), closed, market("new", 0)}
+	tokens := []models.Token{
+		token("s2", "same", "No", 1), token("s1", "same", "Yes", 0),
+		token("t1", "tokens", "Yes", 0), token("t3", "tokens", "No", 1),
+		token("e1", "edited", "Yes", 0),
+		token("c1", "closed", "Yes", 0),
+		token("n1", "new", "Yes", 0),
+	}
+	assignCatalogEpochs(markets, tokens, stored, storedTokens, map[string]bool{"edited": true}, 200)
+
+	want := map[string]int64{"same": 100, "tokens": 200, "edited": 200, "legacy": 200, "closed": 200, "new": 200}
+	for _, m := range markets {
+		if m.CatalogEpoch != want[m.ID] {
+			t.Errorf("market %s epoch = %d, want %d", m.ID, m.CatalogEpoch, want[m.ID])
+		}
+	}
+	for _, tok := range tokens {
+		if tok.CatalogEpoch != want[tok.MarketID] {
+			t.Errorf("token %s epoch = %d, want %d", tok.ID, tok.CatalogEpoch, want[tok.MarketID])
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"polymarket/internal/models"
+)
+
+// catalogJournal diffs a sync page against the stored rows and returns the
+// journal entries for events and markets that were created, closed or had a
+// state field change. changes are the page's semantic market edits, which
+// count as updates. It runs before the page is upserted.
+func (s *CatalogSyncService) catalogJournal(ctx context.Context, events []models.Event, markets []models.Market, changes []models.MarketChange, now time.Time) ([]models.CatalogChange, error) {
+	var out []models.CatalogChange
+	if len(events) > 0 {
+		ids := make([]string, 0, len(events))
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		stored, err := s.Store.ListEventsByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		prev := make(map[string]models.Event, len(stored))
+		for _, e := range stored {
+			prev[e.ID] = e
+		}
+		for _, e := range events {
+			old, ok := prev[e.ID]
+			var fields []string
+			if ok {
+				fields = eventStateChanges(old, e)
+			}
+			if c, ok := journalEntry("event", e.ID, ok, old.Closed, e.Closed, fields, now); ok {
+				out = append(out, c)
+			}
+		}
+	}
+	if len(markets) > 0 {
+		ids := make([]string, 0, len(markets))
+		for _, m := range markets {
+			ids = append(ids, m.ID)
+		}
+		stored, err := s.Store.ListMarketsByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		prev := make(map[string]models.Market, len(stored))
+		for _, m := range stored {
+			prev[m.ID] = m
+		}
+		semantic := map[string][]string{}
+		for _, c := range changes {
+			semantic[c.MarketID] = append(semantic[c.MarketID], c.Field)
+		}
+		for _, m := range markets {
+			old, ok := prev[m.ID]
+			var fields []string
+			if ok {
+				fields = append(marketStateChanges(old, m), semantic[m.ID]...)
+			}
+			if c, ok := journalEntry("market", m.ID, ok, old.Closed, m.Closed, fields, now); ok {
+				out = append(out, c)
+			}
+		}
+	}
+	return out, nil
+}
+
+// journalEntry picks the kind of change for one row, if any.
+func journalEntry(entity, id string, known, wasClosed, closed bool, fields []string, now time.Time) (models.CatalogChange, bool) {
+	c := models.CatalogChange{Entity: entity, EntityID: id, ChangedAt: now}
+	switch {
+	case !known:
+		c.Kind = models.CatalogChangeCreated
+	case closed && !wasClosed:
+		c.Kind = models.CatalogChangeClosed
+	case len(fields) > 0:
+		c.Kind = models.CatalogChangeUpdated
+		c.Fields = strings.Join(dedupeSorted(fields), ",")
+	default:
+		return c, false
+	}
+	return c, true
+}
+
+func eventStateChanges(old, next models.Event) []string {
+	var fields []string
+	if normalizeText(old.Title) != normalizeText(next.Title) {
+		fields = append(fields, "title")
+	}
+	if old.Active != next.Active {
+		fields = append(fields, "active")
+	}
+	if old.Closed != next.Closed {
+		fields = append(fields, "closed")
+	}
+	if !sameTimePtr(old.EndTime, next.EndTime) {
+		fields = append(fields, "end_time")
+	}
+	return fields
+}
+
+func marketStateChanges(old, next models.Market) []string {
+	var fields []string
+	if old.Active != next.Active {
+		fields = append(fields, "active")
+	}
+	if old.Closed != next.Closed {
+		fields = append(fields, "closed")
+	}
+	if derefString(old.Status) != derefString(next.Status) {
+		fields = append(fields, "status")
+	}
+	if !old.TickSize.Equal(next.TickSize) {
+		fields = append(fields, "tick_size")
+	}
+	return fields
+}
+
+func sameTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func dedupeSorted(in []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// CatalogChangeRecord is the compact form the catalog diff endpoint serves:
+// one record per entity (or per market label) with its latest kind.
+type CatalogChangeRecord struct {
+	Entity    string    `json:"entity"`
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Fields    []string  `json:"fields,omitempty"`
+	Label     string    `json:"label,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// CompactCatalogChanges folds journal entries, oldest first, into one record
+// per entity. A creation stays a creation and a closure a closure; updates
+// merge their fields. Label entries fold per market and label, last wins.
+func CompactCatalogChanges(items []models.CatalogChange) []CatalogChangeRecord {
+	out := []CatalogChangeRecord{}
+	index := map[string]int{}
+	for _, it := range items {
+		key := it.Entity + "|" + it.EntityID
+		if it.Entity == "label" {
+			key += "|" + it.Fields
+		}
+		i, ok := index[key]
+		if !ok {
+			rec := CatalogChangeRecord{Entity: it.Entity, ID: it.EntityID, Kind: it.Kind, ChangedAt: it.ChangedAt}
+			if it.Entity == "label" {
+				rec.Label = it.Fields
+			} else if it.Fields != "" {
+				rec.Fields = strings.Split(it.Fields, ",")
+			}
+			index[key] = len(out)
+			out = append(out, rec)
+			continue
+		}
+		rec := &out[i]
+		rec.ChangedAt = it.ChangedAt
+		switch {
+		case it.Entity == "label":
+			rec.Kind = it.Kind
+		case rec.Kind == models.CatalogChangeCreated:
+		case it.Kind == models.CatalogChangeClosed || it.Kind == models.CatalogChangeCreated:
+			rec.Kind = it.Kind
+			rec.Fields = nil
+		case rec.Kind == models.CatalogChangeUpdated && it.Fields != "":
+			rec.Fields = dedupeSorted(append(rec.Fields, strings.Split(it.Fields, ",")...))
+		}
+	}
+	return out
+}
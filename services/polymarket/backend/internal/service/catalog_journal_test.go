@@ -0,0 +1,50 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"polymarket/internal/models"
+)
+
+func TestJournalEntry(t *testing.T) {
+	now := time.Now().UTC()
+	if c, ok := journalEntry("market", "m1", false, false, false, nil, now); !ok || c.Kind != models.CatalogChangeCreated {
+		t.Fatalf("new row: %+v ok=%v", c, ok)
+	}
+	if c, _ := journalEntry("market", "m1", true, false, true, []string{"closed"}, now); c.Kind != models.CatalogChangeClosed {
+		t.Fatalf("closing row: %+v", c)
+	}
+	if c, _ := journalEntry("event", "e1", true, false, false, []string{"title", "active", "title"}, now); c.Kind != models.CatalogChangeUpdated || c.Fields != "active,title" {
+		t.Fatalf("updated row: %+v", c)
+	}
+	if _, ok := journalEntry("event", "e1", true, true, true, nil, now); ok {
+		t.Fatalf("unchanged row journaled")
+	}
+}
+
+func TestCompactCatalogChanges(t *testing.T) {
+	at := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	items := []models.CatalogChange{
+		{ID: 1, Entity: "market", EntityID: "m1", Kind: models.CatalogChangeUpdated, Fields: "status", ChangedAt: at},
+		{ID: 2, Entity: "market", EntityID: "m2", Kind: models.CatalogChangeCreated, ChangedAt: at},
+		{ID: 3, Entity: "market", EntityID: "m1", Kind: models.CatalogChangeUpdated, Fields: "active,question", ChangedAt: at.Add(time.Minute)},
+		{ID: 4, Entity: "market", EntityID: "m2", Kind: models.CatalogChangeUpdated, Fields: "status", ChangedAt: at.Add(time.Minute)},
+		{ID: 5, Entity: "label", EntityID: "m1", Kind: models.CatalogChangeLabeled, Fields: "safe_no", ChangedAt: at},
+		{ID: 6, Entity: "label", EntityID: "m1", Kind: models.CatalogChangeUnlabeled, Fields: "safe_no", ChangedAt: at.Add(time.Minute)},
+	}
+	got := CompactCatalogChanges(items)
+	if len(got) != 3 {
+		t.Fatalf("records=%+v", got)
+	}
+	if got[0].ID != "m1" || !reflect.DeepEqual(got[0].Fields, []string{"active", "question", "status"}) || !got[0].ChangedAt.Equal(at.Add(time.Minute)) {
+		t.Fatalf("merged update=%+v", got[0])
+	}
+	if got[1].Kind != models.CatalogChangeCreated || got[1].Fields != nil {
+		t.Fatalf("created stays created: %+v", got[1])
+	}
+	if got[2].Kind != models.CatalogChangeUnlabeled || got[2].Label != "safe_no" {
+		t.Fatalf("label=%+v", got[2])
+	}
+}
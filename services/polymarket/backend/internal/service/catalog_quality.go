@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+const (
+	QualityRuleMissingConditionID   = "missing_condition_id"
+	QualityRuleZeroTickSize         = "zero_tick_size"
+	QualityRuleDuplicateConditionID = "duplicate_condition_id"
+	QualityRuleDuplicateSlug        = "duplicate_slug"
+
+	QuarantineStatusQuarantined = "quarantined"
+	QuarantineStatusReleased    = "released"
+
+	quarantineEntityMarket = "market"
+)
+
+type marketRowRule struct {
+	Name  string
+	Check func(market models.Market) string
+}
+
+// marketRowRules are evaluated per row before duplicate detection and can be
+// disabled by name. Duplicate rules are always enforced because
+// catalog_markets.slug is unique and condition ids must map to one market.
+var marketRowRules = []marketRowRule{
+	{
+		Name: QualityRuleMissingConditionID,
+		Check: func(market models.Market) string {
+			if strings.TrimSpace(market.ConditionID) == "" {
+				return "condition_id is empty"
+			}
+			return ""
+		},
+	},
+	{
+		Name: QualityRuleZeroTickSize,
+		Check: func(market models.Market) string {
+			if !market.TickSize.IsPositive() {
+				return fmt.Sprintf("tick size %s is not positive", market.TickSize.String())
+			}
+			return ""
+		},
+	},
+}
+
+type qualityViolation struct {
+	Market models.Market
+	Tokens []models.Token
+	Rule   string
+	Reason string
+}
+
+type quarantinePayload struct {
+	Market models.Market  `json:"market"`
+	Tokens []models.Token `json:"tokens"`
+}
+
+type QuarantineReprocessOptions struct {
+	IDs   []uint64
+	Rule  *string
+	Limit int
+}
+
+type QuarantineReprocessResult struct {
+	Scanned           int            `json:"scanned"`
+	Released          int64          `json:"released"`
+	StillQuarantined  int            `json:"still_quarantined"`
+	Markets           int            `json:"markets"`
+	Tokens            int            `json:"tokens"`
+	QualityViolations map[string]int `json:"quality_violations,omitempty"`
+}
+
+// checkMarketRow returns the first failing row-level rule and its reason.
+func checkMarketRow(market models.Market, disabled []string) (string, string) {
+	for _, rule := range marketRowRules {
+		if containsFold(disabled, rule.Name) {
+			continue
+		}
+		if reason := rule.Check(market); reason != "" {
+			return rule.Name, reason
+		}
+	}
+	return "", ""
+}
+
+func containsFold(items []string, value string) bool {
+	for _, item := range items {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func countViolations(violations []qualityViolation) map[string]int {
+	if len(violations) == 0 {
+		return nil
+	}
+	out := map[string]int{}
+	for _, v := range violations {
+		out[v.Rule]++
+	}
+	return out
+}
+
+func mergeViolationCounts(dst map[string]int, src map[string]int) map[string]int {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = map[string]int{}
+	}
+	for k, v := range src {
+		dst[k] += v
+	}
+	return dst
+}
+
+func quarantineRows(scope string, violations []qualityViolation, now time.Time) []models.CatalogQuarantine {
+	rows := make([]models.CatalogQuarantine, 0, len(violations))
+	for _, v := range violations {
+		rows = append(rows, models.CatalogQuarantine{
+			EntityType:  quarantineEntityMarket,
+			EntityID:    v.Market.ID,
+			Rule:        v.Rule,
+			Reason:      v.Reason,
+			Scope:       scope,
+			Status:      QuarantineStatusQuarantined,
+			Payload:     mustJSON(quarantinePayload{Market: v.Market, Tokens: v.Tokens}),
+			Hits:        1,
+			FirstSeenAt: now,
+			LastSeenAt:  now,
+		})
+	}
+	return rows
+}
+
+// saveQualityResultTx quarantines offending rows and releases earlier
+// quarantine entries for markets that passed every rule in this batch.
+func (s *CatalogSyncService) saveQualityResultTx(ctx context.Context, tx *gorm.DB, scope string, markets []models.Market, violations []qualityViolation, now time.Time) (int64, error) {
+	marketIDs := make([]string, 0, len(markets))
+	for _, market := range markets {
+		marketIDs = append(marketIDs, market.ID)
+	}
+	released, err := s.Store.ReleaseCatalogQuarantineTx(ctx, tx, quarantineEntityMarket, marketIDs, now)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.Store.UpsertCatalogQuarantineTx(ctx, tx, quarantineRows(scope, violations, now)); err != nil {
+		return 0, err
+	}
+	return released, nil
+}
+
+func (s *CatalogSyncService) logViolations(scope string, violations []qualityViolation) {
+	if s.Logger == nil || len(violations) == 0 {
+		return
+	}
+	s.Logger.Warn("catalog rows quarantined",
+		zap.String("scope", scope),
+		zap.Int("count", len(violations)),
+		zap.Any("rules", countViolations(violations)),
+	)
+}
+
+// ReprocessQuarantine re-evaluates quarantined rows against the current rules
+// and upserts the ones that now pass.
+func (s *CatalogSyncService) ReprocessQuarantine(ctx context.Context, opts QuarantineReprocessOptions) (QuarantineReprocessResult, error) {
+	result := QuarantineReprocessResult{}
+	if s.Store == nil {
+		return result, fmt.Errorf("store is nil")
+	}
+	entityType := quarantineEntityMarket
+	status := QuarantineStatusQuarantined
+	asc := true
+	rows, err := s.Store.ListCatalogQuarantine(ctx, repository.ListCatalogQuarantineParams{
+		Limit:      opts.Limit,
+		IDs:        opts.IDs,
+		EntityType: &entityType,
+		Rule:       opts.Rule,
+		Status:     &status,
+		OrderBy:    "id",
+		Asc:        &asc,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Scanned = len(rows)
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	markets := make([]models.Market, 0, len(rows))
+	tokens := make([]models.Token, 0)
+	decoded := make([]models.CatalogQuarantine, 0, len(rows))
+	seen := map[string]struct{}{}
+	for _, row := range rows {
+		var payload quarantinePayload
+		if err := json.Unmarshal(row.Payload, &payload); err != nil || payload.Market.ID == "" {
+			continue
+		}
+		decoded = append(decoded, row)
+		if _, ok := seen[payload.Market.ID]; ok {
+			continue
+		}
+		seen[payload.Market.ID] = struct{}{}
+		markets = append(markets, payload.Market)
+		tokens = append(tokens, payload.Tokens...)
+	}
+
+	passed, passedTokens, violations, err := s.filterMarketsAndTokens(ctx, markets, tokens)
+	if err != nil {
+		return result, err
+	}
+	failing := map[string]struct{}{}
+	for _, v := range violations {
+		failing[v.Market.ID+"|"+v.Rule] = struct{}{}
+	}
+	releaseIDs := make([]uint64, 0, len(decoded))
+	for _, row := range decoded {
+		if _, ok := failing[row.EntityID+"|"+row.Rule]; !ok {
+			releaseIDs = append(releaseIDs, row.ID)
+		}
+	}
+
+	now := time.Now().UTC()
+	if err := s.stampCatalogEpochs(ctx, passed, passedTokens, nil, now); err != nil {
+		return result, err
+	}
+	err = s.Store.InTx(ctx, func(tx *gorm.DB) error {
+		if err := s.Store.UpsertMarketsTx(ctx, tx, passed); err != nil {
+			return err
+		}
+		if err := s.Store.UpsertTokensTx(ctx, tx, passedTokens); err != nil {
+			return err
+		}
+		released, err := s.Store.ReleaseCatalogQuarantineByIDsTx(ctx, tx, releaseIDs, now)
+		if err != nil {
+			return err
+		}
+		more, err := s.saveQualityResultTx(ctx, tx, "reprocess", passed, violations, now)
+		if err != nil {
+			return err
+		}
+		result.Released = released + more
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Markets = len(passed)
+	result.Tokens = len(passedTokens)
+	result.StillQuarantined = len(violations)
+	result.QualityViolations = countViolations(violations)
+	return result, nil
+}
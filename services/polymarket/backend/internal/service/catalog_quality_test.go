@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestCheckMarketRow_MissingConditionID(t *testing.T) {
+	m := models.Market{ID: "1", TickSize: decimal.RequireFromString("0.01")}
+	rule, reason := checkMarketRow(m, nil)
+	if rule != QualityRuleMissingConditionID {
+		t.Fatalf("rule=%q want %q", rule, QualityRuleMissingConditionID)
+	}
+	if reason == "" {
+		t.Fatalf("expected reason")
+	}
+}
+
+func TestCheckMarketRow_ZeroTickSize(t *testing.T) {
+	m := models.Market{ID: "1", ConditionID: "0xabc"}
+	rule, _ := checkMarketRow(m, nil)
+	if rule != QualityRuleZeroTickSize {
+		t.Fatalf("rule=%q want %q", rule, QualityRuleZeroTickSize)
+	}
+}
+
+func TestCheckMarketRow_DisabledRule(t *testing.T) {
+	m := models.Market{ID: "1", ConditionID: "0xabc"}
+	rule, _ := checkMarketRow(m, []string{"ZERO_TICK_SIZE"})
+	if rule != "" {
+		t.Fatalf("rule=%q want empty", rule)
+	}
+}
@@ -18,6 +18,7 @@ import (
 
 	"polymarket/internal/client/polymarket/clob"
 	polymarketgamma "polymarket/internal/client/polymarket/gamma"
+	"polymarket/internal/config"
 	"polymarket/internal/models"
 	"polymarket/internal/repository"
 )
@@ -27,14 +28,31 @@ type CatalogSyncService struct {
 	Gamma  *polymarketgamma.Client
 	Clob   *clob.Client
 	Logger *zap.Logger
+
+	// DisabledQualityRules lists row-level data-quality rules to skip.
+	DisabledQualityRules []string
+	// Webhooks, when set, is told about events and markets first seen.
+	Webhooks *CatalogWebhookService
+	// Profiles are the named tag scopes SyncOptions.Profile refers to.
+	Profiles map[string]config.CatalogSyncProfile
+	// Anomalies, when set, checks synced markets against their liquidity
+	// and volume baselines.
+	Anomalies *CatalogAnomalyMonitor
 }
 
 type SyncOptions struct {
-	Scope             string
-	Limit             int
-	MaxPages          int
-	Resume            bool
-	TagID             *int
+	Scope    string
+	Limit    int
+	MaxPages int
+	Resume   bool
+	TagID    *int
+	// TagIDs adds include tags to TagID: the events sync runs once per tag.
+	// Events carrying any ExcludeTagIDs are skipped.
+	TagIDs        []int
+	ExcludeTagIDs []int
+	// Profile names the tag scope; its events cursor is kept apart from the
+	// default one. Resolve it with ApplyProfile.
+	Profile           string
 	Closed            *bool
 	BookMaxAssets     int
 	BookBatchSize     int
@@ -54,6 +72,21 @@ type SyncResult struct {
 	BookErrors int    `json:"book_errors"`
 	NextOffset int    `json:"next_offset"`
 	Done       bool   `json:"done"`
+
+	Quarantined       int            `json:"quarantined"`
+	QualityViolations map[string]int `json:"quality_violations,omitempty"`
+
+	// MarketChanges counts semantic edits to stored markets; HeldMarketChanges
+	// counts the edited markets we hold a position in.
+	MarketChanges     int `json:"market_changes"`
+	HeldMarketChanges int `json:"held_market_changes"`
+
+	// Anomalies counts liquidity_collapse and volume_spike signals emitted.
+	Anomalies int `json:"anomalies"`
+
+	// BatchErrors lists the batches rolled back to their savepoint; their
+	// markets are quarantined as write_failed and left out of the counts.
+	BatchErrors []SyncBatchError `json:"batch_errors,omitempty"`
 }
 
 func (s *CatalogSyncService) Sync(ctx context.Context, opts SyncOptions) (SyncResult, error) {
@@ -82,6 +115,12 @@ func (s *CatalogSyncService) Sync(ctx context.Context, opts SyncOptions) (SyncRe
 		result.Series += res.Series
 		result.Tags += res.Tags
 		result.EventTags += res.EventTags
+		result.Quarantined += res.Quarantined
+		result.QualityViolations = mergeViolationCounts(result.QualityViolations, res.QualityViolations)
+		result.MarketChanges += res.MarketChanges
+		result.HeldMarketChanges += res.HeldMarketChanges
+		result.Anomalies += res.Anomalies
+		result.BatchErrors = append(result.BatchErrors, res.BatchErrors...)
 		result.Pages += res.Pages
 		result.NextOffset = res.NextOffset
 		result.Done = res.Done
@@ -110,98 +149,281 @@ func (s *CatalogSyncService) syncEvents(ctx context.Context, opts SyncOptions) (
 	if s.Gamma == nil {
 		return SyncResult{}, fmt.Errorf("gamma client is nil")
 	}
-	limit := normalizeLimit(opts.Limit)
-	maxPages := normalizeMaxPages(opts.MaxPages)
-	offset := 0
+	stateScope := SyncStateScope("events", opts.Profile)
+	tags := syncIncludeTags(opts)
+	offsets := map[int]int{}
 	if opts.Resume {
-		state, err := s.Store.GetSyncState(ctx, "events")
+		state, err := s.Store.GetSyncState(ctx, stateScope)
 		if err != nil {
 			return SyncResult{}, err
 		}
 		if state != nil && state.Cursor != nil {
-			if parsed, err := strconv.Atoi(*state.Cursor); err == nil {
-				offset = parsed
-			}
+			offsets = parseTagCursor(*state.Cursor, tags)
 		}
 	}
 
-	now := time.Now().UTC()
-	result := SyncResult{Scope: "events"}
-	for page := 0; page < maxPages; page++ {
-		params := &polymarketgamma.GetEventsParams{
-			Limit:  limit,
-			Offset: offset,
-			TagID:  opts.TagID,
-			Closed: opts.Closed,
+	result := SyncResult{Scope: "events", Done: true}
+	budget := normalizeMaxPages(opts.MaxPages)
+	for _, tag := range tags {
+		if budget <= 0 {
+			result.Done = false
+			break
 		}
-		events, err := s.Gamma.GetEvents(ctx, params)
+		done, err := s.syncEventPages(ctx, opts, stateScope, tags, tag, offsets, &budget, &result)
 		if err != nil {
-			s.writeSyncError(ctx, "events", err)
 			return result, err
 		}
-		if len(events) == 0 {
-			result.Done = true
-			break
+		if !done {
+			result.Done = false
 		}
+	}
+	return result, nil
+}
 
-		series, tags, eventTags, markets, tokens, eventsOut := mapEventsPayload(events, now)
-		markets, tokens, err = s.filterMarketsAndTokens(ctx, markets, tokens)
+// syncEventPages pages through the events of one include tag (0 = no tag
+// filter) from offsets[tag], spending the shared page budget. It reports
+// whether the tag reached its last page.
+func (s *CatalogSyncService) syncEventPages(ctx context.Context, opts SyncOptions, stateScope string, tags []int, tag int, offsets map[int]int, budget *int, result *SyncResult) (bool, error) {
+	limit := normalizeLimit(opts.Limit)
+	offset := offsets[tag]
+	var tagID *int
+	if tag > 0 {
+		tagID = &tag
+	}
+	excluded := map[string]bool{}
+	for _, id := range opts.ExcludeTagIDs {
+		excluded[strconv.Itoa(id)] = true
+	}
+
+	now := time.Now().UTC()
+	for *budget > 0 {
+		*budget--
+		params := &polymarketgamma.GetEventsParams{
+			Limit:        limit,
+			Offset:       offset,
+			TagID:        tagID,
+			ExcludeTagID: opts.ExcludeTagIDs,
+			Closed:       opts.Closed,
+		}
+		fetched, err := s.Gamma.GetEvents(ctx, params)
 		if err != nil {
-			s.writeSyncError(ctx, "events", err)
-			return result, err
+			s.writeSyncError(ctx, stateScope, err)
+			return false, err
 		}
-		nextOffset := offset + len(events)
+		if len(fetched) == 0 {
+			return true, nil
+		}
+		// Gamma applies exclude_tag_id too; filter again in case it does not.
+		events := excludeTaggedEvents(fetched, excluded)
+		series, pageTagRows, eventTags, markets, tokens, eventsOut := mapEventsPayload(events, now)
+		markets, tokens, violations, err := s.filterMarketsAndTokens(ctx, markets, tokens)
+		if err != nil {
+			s.writeSyncError(ctx, stateScope, err)
+			return false, err
+		}
+		s.logViolations("events", violations)
+		changes, err := s.detectMarketChanges(ctx, "events", markets, now)
+		if err != nil {
+			s.writeSyncError(ctx, stateScope, err)
+			return false, err
+		}
+		if err := s.stampCatalogEpochs(ctx, markets, tokens, changes, now); err != nil {
+			s.writeSyncError(ctx, stateScope, err)
+			return false, err
+		}
+		newEvents, newMarkets, err := s.newCatalogEntities(ctx, eventsOut, markets)
+		if err != nil {
+			s.writeSyncError(ctx, stateScope, err)
+			return false, err
+		}
+		journal, err := s.catalogJournal(ctx, eventsOut, markets, changes, now)
+		if err != nil {
+			s.writeSyncError(ctx, stateScope, err)
+			return false, err
+		}
+		nextOffset := offset + len(fetched)
+		offsets[tag] = nextOffset
 
+		var batchErrors []SyncBatchError
+		droppedEvents := 0
 		err = s.Store.InTx(ctx, func(tx *gorm.DB) error {
 			if err := s.Store.UpsertSeriesTx(ctx, tx, series); err != nil {
 				return err
 			}
-			if err := s.Store.UpsertTagsTx(ctx, tx, tags); err != nil {
+			if err := s.Store.UpsertTagsTx(ctx, tx, pageTagRows); err != nil {
 				return err
 			}
-			if err := s.Store.UpsertEventsTx(ctx, tx, eventsOut); err != nil {
+			failed, errs, err := s.writeBatchesTx(ctx, tx, eventBatches(eventsOut, markets, tokens, eventTags))
+			if err != nil {
 				return err
 			}
-			if err := s.Store.UpsertMarketsTx(ctx, tx, markets); err != nil {
+			if len(failed) > 0 {
+				rows := newFailedBatchRows(failed)
+				droppedEvents = len(rows.events)
+				eventsOut = rows.keepEvents(eventsOut)
+				markets, tokens, eventTags = rows.keepMarkets(markets), rows.keepTokens(tokens), rows.keepEventTags(eventTags)
+				changes, journal = rows.keepChanges(changes), rows.keepJournal(journal)
+				newEvents, newMarkets = rows.keepEvents(newEvents), rows.keepMarkets(newMarkets)
+				var writeViolations []qualityViolation
+				writeViolations, batchErrors = batchFailures("events", offset, failed, errs)
+				violations = append(violations, writeViolations...)
+			}
+			if _, err := s.saveQualityResultTx(ctx, tx, "events", markets, violations, now); err != nil {
 				return err
 			}
-			if err := s.Store.UpsertTokensTx(ctx, tx, tokens); err != nil {
+			if err := s.Store.InsertMarketChangesTx(ctx, tx, changes); err != nil {
 				return err
 			}
-			if err := s.Store.UpsertEventTagsTx(ctx, tx, eventTags); err != nil {
+			if err := s.Store.InsertCatalogChangesTx(ctx, tx, journal); err != nil {
 				return err
 			}
 			state := &models.SyncState{
-				Scope:         "events",
-				Cursor:        strPtr(strconv.Itoa(nextOffset)),
+				Scope:         stateScope,
+				Cursor:        strPtr(formatTagCursor(tags, offsets)),
 				LastAttemptAt: &now,
 				LastSuccessAt: &now,
 				LastError:     nil,
-				StatsJSON:     statsJSON(map[string]int{"events": len(events), "markets": len(markets), "tokens": len(tokens), "tags": len(tags), "series": len(series)}),
+				StatsJSON:     statsJSON(map[string]int{"events": len(events) - droppedEvents, "markets": len(markets), "tokens": len(tokens), "tags": len(pageTagRows), "series": len(series), "quarantined": len(violations), "batch_errors": len(batchErrors)}),
 			}
 			return s.Store.SaveSyncStateTx(ctx, tx, state)
 		})
 		if err != nil {
-			s.writeSyncError(ctx, "events", err)
-			return result, err
+			s.writeSyncError(ctx, stateScope, err)
+			return false, err
 		}
+		s.logBatchErrors(batchErrors)
+		s.logMarketChanges("events", changes)
+		s.Webhooks.PublishNew(ctx, newEvents, newMarkets, pageTags(pageTagRows, eventTags))
+		result.Anomalies += s.observeAnomalies(ctx, "events", markets, now)
 
 		result.Pages++
-		result.Events += len(events)
+		result.Events += len(events) - droppedEvents
 		result.Markets += len(markets)
 		result.Tokens += len(tokens)
 		result.Series += len(series)
-		result.Tags += len(tags)
+		result.Tags += len(pageTagRows)
 		result.EventTags += len(eventTags)
+		result.Quarantined += len(violations)
+		result.QualityViolations = mergeViolationCounts(result.QualityViolations, countViolations(violations))
+		result.MarketChanges += len(changes)
+		result.HeldMarketChanges += countHeldMarkets(changes)
+		result.BatchErrors = append(result.BatchErrors, batchErrors...)
 		result.NextOffset = nextOffset
 
 		offset = nextOffset
-		if len(events) < limit {
-			result.Done = true
-			break
+		if len(fetched) < limit {
+			return true, nil
 		}
 	}
-	return result, nil
+	return false, nil
+}
+
+// SyncStateScope is the sync state row of scope under profile.
+func SyncStateScope(scope, profile string) string {
+	if profile = strings.TrimSpace(profile); profile != "" {
+		return scope + "@" + profile
+	}
+	return scope
+}
+
+// syncIncludeTags lists the include tags of opts, deduplicated in order; a
+// single 0 means no tag filter.
+func syncIncludeTags(opts SyncOptions) []int {
+	var out []int
+	seen := map[int]bool{}
+	add := func(id int) {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	if opts.TagID != nil {
+		add(*opts.TagID)
+	}
+	for _, id := range opts.TagIDs {
+		add(id)
+	}
+	if len(out) == 0 {
+		return []int{0}
+	}
+	return out
+}
+
+// formatTagCursor keeps a plain offset for a single tag, as before profiles,
+// and "tag:offset,..." pairs for several.
+func formatTagCursor(tags []int, offsets map[int]int) string {
+	if len(tags) == 1 {
+		return strconv.Itoa(offsets[tags[0]])
+	}
+	parts := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		parts = append(parts, strconv.Itoa(tag)+":"+strconv.Itoa(offsets[tag]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseTagCursor reads a formatTagCursor cursor. Tags missing from it, e.g.
+// added to the profile since, start at 0.
+func parseTagCursor(cursor string, tags []int) map[int]int {
+	out := map[int]int{}
+	cursor = strings.TrimSpace(cursor)
+	if !strings.Contains(cursor, ":") {
+		if n, err := strconv.Atoi(cursor); err == nil && len(tags) == 1 {
+			out[tags[0]] = n
+		}
+		return out
+	}
+	for _, part := range strings.Split(cursor, ",") {
+		tag, offset, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+		t, err1 := strconv.Atoi(strings.TrimSpace(tag))
+		n, err2 := strconv.Atoi(strings.TrimSpace(offset))
+		if err1 == nil && err2 == nil {
+			out[t] = n
+		}
+	}
+	return out
+}
+
+// excludeTaggedEvents drops events carrying one of the excluded tag IDs.
+func excludeTaggedEvents(items []polymarketgamma.Event, excluded map[string]bool) []polymarketgamma.Event {
+	if len(excluded) == 0 {
+		return items
+	}
+	out := make([]polymarketgamma.Event, 0, len(items))
+	for _, evt := range items {
+		skip := false
+		for _, tag := range evt.Tags {
+			if excluded[tag.ID] {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			out = append(out, evt)
+		}
+	}
+	return out
+}
+
+// ApplyProfile resolves the named profile into opts. An empty name leaves
+// opts unchanged.
+func (s *CatalogSyncService) ApplyProfile(opts *SyncOptions, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil
+	}
+	profile, ok := s.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown sync profile: %s", name)
+	}
+	opts.Profile = name
+	opts.TagID = nil
+	opts.TagIDs = append([]int(nil), profile.TagIDs...)
+	opts.ExcludeTagIDs = append([]int(nil), profile.ExcludeTagIDs...)
+	return nil
 }
 
 func (s *CatalogSyncService) syncSeries(ctx context.Context, opts SyncOptions) (SyncResult, error) {
@@ -435,18 +657,55 @@ func (s *CatalogSyncService) syncMarkets(ctx context.Context, opts SyncOptions)
 			markets = append(markets, market)
 			tokens = append(tokens, buildTokensFromMarket(item, market.ExternalUpdatedAt, now)...)
 		}
-		markets, tokens, err = s.filterMarketsAndTokens(ctx, markets, tokens)
+		markets, tokens, violations, err := s.filterMarketsAndTokens(ctx, markets, tokens)
+		if err != nil {
+			s.writeSyncError(ctx, "markets", err)
+			return result, err
+		}
+		s.logViolations("markets", violations)
+		changes, err := s.detectMarketChanges(ctx, "markets", markets, now)
+		if err != nil {
+			s.writeSyncError(ctx, "markets", err)
+			return result, err
+		}
+		if err := s.stampCatalogEpochs(ctx, markets, tokens, changes, now); err != nil {
+			s.writeSyncError(ctx, "markets", err)
+			return result, err
+		}
+		newEvents, newMarkets, err := s.newCatalogEntities(ctx, nil, markets)
+		if err != nil {
+			s.writeSyncError(ctx, "markets", err)
+			return result, err
+		}
+		journal, err := s.catalogJournal(ctx, nil, markets, changes, now)
 		if err != nil {
 			s.writeSyncError(ctx, "markets", err)
 			return result, err
 		}
 		nextOffset := offset + len(items)
 
+		var batchErrors []SyncBatchError
 		err = s.Store.InTx(ctx, func(tx *gorm.DB) error {
-			if err := s.Store.UpsertMarketsTx(ctx, tx, markets); err != nil {
+			failed, errs, err := s.writeBatchesTx(ctx, tx, marketBatches(markets, tokens))
+			if err != nil {
 				return err
 			}
-			if err := s.Store.UpsertTokensTx(ctx, tx, tokens); err != nil {
+			if len(failed) > 0 {
+				rows := newFailedBatchRows(failed)
+				markets, tokens = rows.keepMarkets(markets), rows.keepTokens(tokens)
+				changes, journal = rows.keepChanges(changes), rows.keepJournal(journal)
+				newMarkets = rows.keepMarkets(newMarkets)
+				var writeViolations []qualityViolation
+				writeViolations, batchErrors = batchFailures("markets", offset, failed, errs)
+				violations = append(violations, writeViolations...)
+			}
+			if _, err := s.saveQualityResultTx(ctx, tx, "markets", markets, violations, now); err != nil {
+				return err
+			}
+			if err := s.Store.InsertMarketChangesTx(ctx, tx, changes); err != nil {
+				return err
+			}
+			if err := s.Store.InsertCatalogChangesTx(ctx, tx, journal); err != nil {
 				return err
 			}
 			state := &models.SyncState{
@@ -455,7 +714,7 @@ func (s *CatalogSyncService) syncMarkets(ctx context.Context, opts SyncOptions)
 				LastAttemptAt: &now,
 				LastSuccessAt: &now,
 				LastError:     nil,
-				StatsJSON:     statsJSON(map[string]int{"markets": len(markets), "tokens": len(tokens)}),
+				StatsJSON:     statsJSON(map[string]int{"markets": len(markets), "tokens": len(tokens), "quarantined": len(violations), "batch_errors": len(batchErrors)}),
 			}
 			return s.Store.SaveSyncStateTx(ctx, tx, state)
 		})
@@ -463,10 +722,19 @@ func (s *CatalogSyncService) syncMarkets(ctx context.Context, opts SyncOptions)
 			s.writeSyncError(ctx, "markets", err)
 			return result, err
 		}
+		s.logBatchErrors(batchErrors)
+		s.logMarketChanges("markets", changes)
+		s.Webhooks.PublishNew(ctx, newEvents, newMarkets, nil)
+		result.Anomalies += s.observeAnomalies(ctx, "markets", markets, now)
 
 		result.Pages++
 		result.Markets += len(markets)
 		result.Tokens += len(tokens)
+		result.Quarantined += len(violations)
+		result.QualityViolations = mergeViolationCounts(result.QualityViolations, countViolations(violations))
+		result.MarketChanges += len(changes)
+		result.HeldMarketChanges += countHeldMarkets(changes)
+		result.BatchErrors = append(result.BatchErrors, batchErrors...)
 		result.NextOffset = nextOffset
 		offset = nextOffset
 		if len(items) < limit {
@@ -477,6 +745,16 @@ func (s *CatalogSyncService) syncMarkets(ctx context.Context, opts SyncOptions)
 	return result, nil
 }
 
+// observeAnomalies runs the anomaly monitor over a committed page. A failure
+// is logged and does not fail the sync.
+func (s *CatalogSyncService) observeAnomalies(ctx context.Context, scope string, markets []models.Market, now time.Time) int {
+	n, err := s.Anomalies.Observe(ctx, markets, now)
+	if err != nil && s.Logger != nil {
+		s.Logger.Warn("catalog anomaly check failed", zap.String("scope", scope), zap.Error(err))
+	}
+	return n
+}
+
 func (s *CatalogSyncService) writeSyncError(ctx context.Context, scope string, err error) {
 	if s.Logger != nil {
 		s.Logger.Warn("catalog sync failed", zap.String("scope", scope), zap.Error(err))
@@ -518,6 +796,10 @@ func (s *CatalogSyncService) resyncBooks(ctx context.Context, opts SyncOptions)
 		return bookResyncResult{}, err
 	}
 	assetIDs := uniqueTokenIDs(tokens, maxAssets)
+	epochs := make(map[string]int64, len(tokens))
+	for _, t := range tokens {
+		epochs[t.ID] = t.CatalogEpoch
+	}
 	result := bookResyncResult{}
 	for i := 0; i < len(assetIDs); i += batchSize {
 		end := i + batchSize
@@ -528,7 +810,7 @@ func (s *CatalogSyncService) resyncBooks(ctx context.Context, opts SyncOptions)
 			if tokenID == "" {
 				continue
 			}
-			if err := s.resyncToken(ctx, tokenID); err != nil {
+			if err := s.resyncToken(ctx, tokenID, epochs[tokenID]); err != nil {
 				result.Errors++
 				if s.Logger != nil && !isBookNotFound(err) {
 					s.Logger.Warn("book resync failed", zap.String("token_id", tokenID), zap.Error(err))
@@ -548,7 +830,9 @@ func (s *CatalogSyncService) resyncBooks(ctx context.Context, opts SyncOptions)
 	return result, nil
 }
 
-func (s *CatalogSyncService) resyncToken(ctx context.Context, tokenID string) error {
+// resyncToken refetches one book over REST and stamps it with epoch, the
+// token's catalog epoch when the resync listed it.
+func (s *CatalogSyncService) resyncToken(ctx context.Context, tokenID string, epoch int64) error {
 	raw, book, err := s.getBookWithRetry(ctx, tokenID, 2)
 	if err != nil {
 		return err
@@ -574,6 +858,7 @@ func (s *CatalogSyncService) resyncToken(ctx context.Context, tokenID string) er
 		Source:         strPtr("rest"),
 		DataAgeSeconds: 0,
 		UpdatedAt:      now,
+		CatalogEpoch:   epoch,
 	}); err != nil {
 		return err
 	}
@@ -790,40 +1075,64 @@ func mapEventsPayload(events []polymarketgamma.Event, now time.Time) ([]models.S
 	return series, tags, eventTags, markets, tokens, eventsOut
 }
 
-func (s *CatalogSyncService) filterMarketsAndTokens(ctx context.Context, markets []models.Market, tokens []models.Token) ([]models.Market, []models.Token, error) {
+// filterMarketsAndTokens applies data-quality rules and returns the rows that
+// passed along with the violations to quarantine.
+func (s *CatalogSyncService) filterMarketsAndTokens(ctx context.Context, markets []models.Market, tokens []models.Token) ([]models.Market, []models.Token, []qualityViolation, error) {
 	if len(markets) == 0 {
-		return markets, tokens, nil
+		return markets, tokens, nil, nil
 	}
+	tokensByMarket := map[string][]models.Token{}
+	for _, token := range tokens {
+		tokensByMarket[token.MarketID] = append(tokensByMarket[token.MarketID], token)
+	}
+	violations := make([]qualityViolation, 0)
+	quarantine := func(market models.Market, rule, reason string) {
+		violations = append(violations, qualityViolation{
+			Market: market,
+			Tokens: tokensByMarket[market.ID],
+			Rule:   rule,
+			Reason: reason,
+		})
+	}
+
 	seenCondition := map[string]string{}
 	seenSlug := map[string]string{}
 	filtered := make([]models.Market, 0, len(markets))
 	conditionIDs := make([]string, 0)
 	slugs := make([]string, 0)
 	for _, market := range markets {
+		if rule, reason := checkMarketRow(market, s.DisabledQualityRules); rule != "" {
+			quarantine(market, rule, reason)
+			continue
+		}
 		if market.ConditionID != "" {
-			if _, exists := seenCondition[market.ConditionID]; exists {
+			if otherID, exists := seenCondition[market.ConditionID]; exists {
+				quarantine(market, QualityRuleDuplicateConditionID, fmt.Sprintf("condition_id %s already used by market %s in this batch", market.ConditionID, otherID))
 				continue
 			}
-			seenCondition[market.ConditionID] = market.ID
-			conditionIDs = append(conditionIDs, market.ConditionID)
 		}
 		if market.Slug != nil && *market.Slug != "" {
-			if _, exists := seenSlug[*market.Slug]; exists {
+			if otherID, exists := seenSlug[*market.Slug]; exists {
+				quarantine(market, QualityRuleDuplicateSlug, fmt.Sprintf("slug %s already used by market %s in this batch", *market.Slug, otherID))
 				continue
 			}
 			seenSlug[*market.Slug] = market.ID
 			slugs = append(slugs, *market.Slug)
 		}
+		if market.ConditionID != "" {
+			seenCondition[market.ConditionID] = market.ID
+			conditionIDs = append(conditionIDs, market.ConditionID)
+		}
 		filtered = append(filtered, market)
 	}
 
 	existingByCondition, err := s.fetchMarketsByConditionIDs(ctx, conditionIDs)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	existingBySlug, err := s.fetchMarketsBySlugs(ctx, slugs)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	finalMarkets := make([]models.Market, 0, len(filtered))
@@ -831,11 +1140,13 @@ func (s *CatalogSyncService) filterMarketsAndTokens(ctx context.Context, markets
 	for _, market := range filtered {
 		if market.ConditionID != "" {
 			if existingID, ok := existingByCondition[market.ConditionID]; ok && existingID != market.ID {
+				quarantine(market, QualityRuleDuplicateConditionID, fmt.Sprintf("condition_id %s already stored for market %s", market.ConditionID, existingID))
 				continue
 			}
 		}
 		if market.Slug != nil && *market.Slug != "" {
 			if existingID, ok := existingBySlug[*market.Slug]; ok && existingID != market.ID {
+				quarantine(market, QualityRuleDuplicateSlug, fmt.Sprintf("slug %s already stored for market %s", *market.Slug, existingID))
 				continue
 			}
 		}
@@ -844,7 +1155,7 @@ func (s *CatalogSyncService) filterMarketsAndTokens(ctx context.Context, markets
 	}
 
 	if len(allowedMarketIDs) == 0 {
-		return finalMarkets, nil, nil
+		return finalMarkets, nil, violations, nil
 	}
 	filteredTokens := make([]models.Token, 0, len(tokens))
 	for _, token := range tokens {
@@ -852,7 +1163,7 @@ func (s *CatalogSyncService) filterMarketsAndTokens(ctx context.Context, markets
 			filteredTokens = append(filteredTokens, token)
 		}
 	}
-	return finalMarkets, filteredTokens, nil
+	return finalMarkets, filteredTokens, violations, nil
 }
 
 func (s *CatalogSyncService) fetchMarketsByConditionIDs(ctx context.Context, conditionIDs []string) (map[string]string, error) {
@@ -935,6 +1246,7 @@ func buildTokensFromMarket(m *polymarketgamma.Market, updatedAt *time.Time, now
 			MarketID:          m.ID,
 			Outcome:           outcome,
 			Side:              normalizeSide(outcome),
+			OutcomeIndex:      i,
 			ExternalUpdatedAt: updatedAt,
 			LastSeenAt:        now,
 			RawJSON:           mustJSON(raw),
@@ -965,15 +1277,10 @@ func parseTokenIDs(raw string) []string {
 	return out
 }
 
+// normalizeSide returns the lower-cased outcome code: yes and no for binary
+// markets, e.g. over_2_5 for other outcomes.
 func normalizeSide(outcome string) *string {
-	switch strings.ToLower(strings.TrimSpace(outcome)) {
-	case "yes":
-		return strPtr("yes")
-	case "no":
-		return strPtr("no")
-	default:
-		return nil
-	}
+	return strPtr(strings.ToLower(models.OutcomeCode(outcome)))
 }
 
 func normalizeLimit(limit int) int {
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+)
+
+// QualityRuleWriteFailed quarantines a market whose batch the database
+// rejected during sync.
+const QualityRuleWriteFailed = "write_failed"
+
+// SyncBatchError is a batch of a sync page that failed to write and was
+// rolled back to its savepoint while the rest of the page committed.
+// EventID is empty for batches of the markets scope.
+type SyncBatchError struct {
+	Scope     string   `json:"scope"`
+	Offset    int      `json:"offset"`
+	EventID   string   `json:"event_id,omitempty"`
+	MarketIDs []string `json:"market_ids"`
+	Error     string   `json:"error"`
+}
+
+// catalogBatch is the rows written under one savepoint: an event with its
+// markets, tokens and tags, or a single market and its tokens.
+type catalogBatch struct {
+	EventID   string
+	Events    []models.Event
+	Markets   []models.Market
+	Tokens    []models.Token
+	EventTags []models.EventTag
+}
+
+func (b catalogBatch) marketIDs() []string {
+	out := make([]string, 0, len(b.Markets))
+	for _, m := range b.Markets {
+		out = append(out, m.ID)
+	}
+	return out
+}
+
+// eventBatches groups a page by event. Markets whose event is not on the
+// page, which mapEventsPayload does not produce, get a batch of their own.
+func eventBatches(events []models.Event, markets []models.Market, tokens []models.Token, eventTags []models.EventTag) []catalogBatch {
+	index := map[string]int{}
+	out := make([]catalogBatch, 0, len(events))
+	for _, e := range events {
+		index[e.ID] = len(out)
+		out = append(out, catalogBatch{EventID: e.ID, Events: []models.Event{e}})
+	}
+	marketBatch := map[string]int{}
+	for _, m := range markets {
+		i, ok := index[m.EventID]
+		if !ok {
+			i = len(out)
+			out = append(out, catalogBatch{})
+		}
+		out[i].Markets = append(out[i].Markets, m)
+		marketBatch[m.ID] = i
+	}
+	for _, t := range tokens {
+		if i, ok := marketBatch[t.MarketID]; ok {
+			out[i].Tokens = append(out[i].Tokens, t)
+		}
+	}
+	for _, et := range eventTags {
+		if i, ok := index[et.EventID]; ok {
+			out[i].EventTags = append(out[i].EventTags, et)
+		}
+	}
+	return out
+}
+
+// marketBatches puts each market of a markets scope page in its own batch.
+func marketBatches(markets []models.Market, tokens []models.Token) []catalogBatch {
+	return eventBatches(nil, markets, tokens, nil)
+}
+
+func (s *CatalogSyncService) writeBatchTx(ctx context.Context, tx *gorm.DB, b catalogBatch) error {
+	if err := s.Store.UpsertEventsTx(ctx, tx, b.Events); err != nil {
+		return err
+	}
+	if err := s.Store.UpsertMarketsTx(ctx, tx, b.Markets); err != nil {
+		return err
+	}
+	if err := s.Store.UpsertTokensTx(ctx, tx, b.Tokens); err != nil {
+		return err
+	}
+	return s.Store.UpsertEventTagsTx(ctx, tx, b.EventTags)
+}
+
+// writeBatchesTx writes a page's batches. The page is first written whole
+// under one savepoint; if that fails each batch is retried under its own
+// savepoint so a bad row only rolls back its batch. It returns the batches
+// that failed, or an error when every batch did, since then the fault is
+// not in the rows.
+func (s *CatalogSyncService) writeBatchesTx(ctx context.Context, tx *gorm.DB, batches []catalogBatch) ([]catalogBatch, []error, error) {
+	if len(batches) == 0 {
+		return nil, nil, nil
+	}
+	if err := tx.SavePoint("catalog_page").Error; err != nil {
+		return nil, nil, err
+	}
+	pageErr := func() error {
+		for _, b := range batches {
+			if err := s.writeBatchTx(ctx, tx, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if pageErr == nil {
+		return nil, nil, nil
+	}
+	if err := tx.RollbackTo("catalog_page").Error; err != nil {
+		return nil, nil, err
+	}
+	var failed []catalogBatch
+	var errs []error
+	for i, b := range batches {
+		name := fmt.Sprintf("catalog_batch_%d", i)
+		if err := tx.SavePoint(name).Error; err != nil {
+			return nil, nil, err
+		}
+		if err := s.writeBatchTx(ctx, tx, b); err != nil {
+			if rbErr := tx.RollbackTo(name).Error; rbErr != nil {
+				return nil, nil, rbErr
+			}
+			failed = append(failed, b)
+			errs = append(errs, err)
+		}
+	}
+	if len(failed) == len(batches) {
+		return nil, nil, pageErr
+	}
+	return failed, errs, nil
+}
+
+// failedBatchRows are the ids written by failed batches, dropped from what
+// the page journals, reports and releases from quarantine.
+type failedBatchRows struct {
+	events  map[string]bool
+	markets map[string]bool
+}
+
+func newFailedBatchRows(failed []catalogBatch) failedBatchRows {
+	out := failedBatchRows{events: map[string]bool{}, markets: map[string]bool{}}
+	for _, b := range failed {
+		for _, e := range b.Events {
+			out.events[e.ID] = true
+		}
+		for _, m := range b.Markets {
+			out.markets[m.ID] = true
+		}
+	}
+	return out
+}
+
+func (f failedBatchRows) empty() bool {
+	return len(f.events) == 0 && len(f.markets) == 0
+}
+
+func (f failedBatchRows) keepEvents(items []models.Event) []models.Event {
+	if len(f.events) == 0 {
+		return items
+	}
+	out := make([]models.Event, 0, len(items))
+	for _, e := range items {
+		if !f.events[e.ID] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (f failedBatchRows) keepMarkets(items []models.Market) []models.Market {
+	if len(f.markets) == 0 {
+		return items
+	}
+	out := make([]models.Market, 0, len(items))
+	for _, m := range items {
+		if !f.markets[m.ID] {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func (f failedBatchRows) keepTokens(items []models.Token) []models.Token {
+	if len(f.markets) == 0 {
+		return items
+	}
+	out := make([]models.Token, 0, len(items))
+	for _, t := range items {
+		if !f.markets[t.MarketID] {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func (f failedBatchRows) keepEventTags(items []models.EventTag) []models.EventTag {
+	if len(f.events) == 0 {
+		return items
+	}
+	out := make([]models.EventTag, 0, len(items))
+	for _, et := range items {
+		if !f.events[et.EventID] {
+			out = append(out, et)
+		}
+	}
+	return out
+}
+
+func (f failedBatchRows) keepChanges(items []models.MarketChange) []models.MarketChange {
+	if len(f.markets) == 0 {
+		return items
+	}
+	out := make([]models.MarketChange, 0, len(items))
+	for _, c := range items {
+		if !f.markets[c.MarketID] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (f failedBatchRows) keepJournal(items []models.CatalogChange) []models.CatalogChange {
+	if f.empty() {
+		return items
+	}
+	out := make([]models.CatalogChange, 0, len(items))
+	for _, c := range items {
+		if (c.Entity == "event" && f.events[c.EntityID]) || (c.Entity == "market" && f.markets[c.EntityID]) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// batchFailures quarantines the markets of failed batches under
+// QualityRuleWriteFailed and describes each batch for the SyncResult.
+func batchFailures(scope string, offset int, failed []catalogBatch, errs []error) ([]qualityViolation, []SyncBatchError) {
+	var violations []qualityViolation
+	reports := make([]SyncBatchError, 0, len(failed))
+	for i, b := range failed {
+		reason := errs[i].Error()
+		reports = append(reports, SyncBatchError{Scope: scope, Offset: offset, EventID: b.EventID, MarketIDs: b.marketIDs(), Error: reason})
+		for _, m := range b.Markets {
+			var tokens []models.Token
+			for _, t := range b.Tokens {
+				if t.MarketID == m.ID {
+					tokens = append(tokens, t)
+				}
+			}
+			violations = append(violations, qualityViolation{Market: m, Tokens: tokens, Rule: QualityRuleWriteFailed, Reason: reason})
+		}
+	}
+	return violations, reports
+}
+
+func (s *CatalogSyncService) logBatchErrors(items []SyncBatchError) {
+	if s.Logger == nil {
+		return
+	}
+	for _, b := range items {
+		s.Logger.Warn("catalog sync batch rolled back",
+			zap.String("scope", b.Scope),
+			zap.Int("offset", b.Offset),
+			zap.String("event_id", b.EventID),
+			zap.Strings("market_ids", b.MarketIDs),
+			zap.String("error", b.Error),
+		)
+	}
+}
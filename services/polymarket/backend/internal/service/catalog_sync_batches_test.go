@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	gormrepository "polymarket/internal/repository/gorm"
+)
+
+func TestWriteBatchesTxIsolatesBadBatch(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.Event{}, &models.Market{}, &models.Token{}, &models.EventTag{}); err != nil {
+		t.Fatal(err)
+	}
+	store := gormrepository.New(conn.Gorm)
+	svc := &CatalogSyncService{Store: store}
+	ctx := context.Background()
+	now := time.Now().UTC()
+	raw := datatypes.JSON(`{}`)
+
+	events := []models.Event{
+		{ID: "e1", Slug: "e1", Title: "good", LastSeenAt: now, RawJSON: raw},
+		{ID: "e2", Slug: "e2", Title: "bad", LastSeenAt: now, RawJSON: raw},
+	}
+	markets := []models.Market{
+		{ID: "m1", EventID: "e1", Question: "q1", ConditionID: "c1", TickSize: decimal.RequireFromString("0.01"), LastSeenAt: now, RawJSON: raw},
+		// A nil raw_json violates NOT NULL and fails e2's batch.
+		{ID: "m2", EventID: "e2", Question: "q2", ConditionID: "c2", TickSize: decimal.RequireFromString("0.01"), LastSeenAt: now},
+	}
+	tokens := []models.Token{
+		{ID: "t1", MarketID: "m1", Outcome: "Yes", LastSeenAt: now, RawJSON: raw},
+		{ID: "t2", MarketID: "m2", Outcome: "Yes", LastSeenAt: now, RawJSON: raw},
+	}
+	eventTags := []models.EventTag{{EventID: "e1", TagID: "1"}, {EventID: "e2", TagID: "1"}}
+
+	var failed []catalogBatch
+	var errs []error
+	err = store.InTx(ctx, func(tx *gorm.DB) error {
+		var err error
+		failed, errs, err = svc.writeBatchesTx(ctx, tx, eventBatches(events, markets, tokens, eventTags))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0].EventID != "e2" || len(errs) != 1 {
+		t.Fatalf("failed=%+v want e2's batch", failed)
+	}
+	violations, reports := batchFailures("events", 100, failed, errs)
+	if len(violations) != 1 || violations[0].Market.ID != "m2" || violations[0].Rule != QualityRuleWriteFailed || len(violations[0].Tokens) != 1 {
+		t.Fatalf("violations=%+v", violations)
+	}
+	if len(reports) != 1 || reports[0].Offset != 100 || reports[0].MarketIDs[0] != "m2" || reports[0].Error == "" {
+		t.Fatalf("reports=%+v", reports)
+	}
+
+	count := func(model any) int64 {
+		var n int64
+		if err := conn.Gorm.Model(model).Count(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if count(&models.Event{}) != 1 || count(&models.Market{}) != 1 || count(&models.Token{}) != 1 || count(&models.EventTag{}) != 1 {
+		t.Fatalf("want only e1's batch committed")
+	}
+
+	rows := newFailedBatchRows(failed)
+	if got := rows.keepTokens(tokens); len(got) != 1 || got[0].ID != "t1" {
+		t.Fatalf("kept tokens=%+v", got)
+	}
+	journal := rows.keepJournal([]models.CatalogChange{{Entity: "event", EntityID: "e2"}, {Entity: "market", EntityID: "m1"}, {Entity: "label", EntityID: "m2"}})
+	if len(journal) != 2 || journal[0].EntityID != "m1" {
+		t.Fatalf("kept journal=%+v", journal)
+	}
+
+	// A page where every batch fails is a fault outside the rows.
+	err = store.InTx(ctx, func(tx *gorm.DB) error {
+		_, _, err := svc.writeBatchesTx(ctx, tx, marketBatches(markets[1:], tokens[1:]))
+		return err
+	})
+	if err == nil {
+		t.Fatal("all batches failing should fail the page")
+	}
+}
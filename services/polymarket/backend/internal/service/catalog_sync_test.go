@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	polymarketgamma "polymarket/internal/client/polymarket/gamma"
+	"polymarket/internal/config"
+)
+
+func TestTagCursorRoundTrip(t *testing.T) {
+	single := []int{0}
+	if got := formatTagCursor(single, map[int]int{0: 300}); got != "300" {
+		t.Fatalf("single cursor=%q", got)
+	}
+	if got := parseTagCursor("300", single); got[0] != 300 {
+		t.Fatalf("single parse=%v", got)
+	}
+
+	tags := []int{2, 7, 9}
+	cursor := formatTagCursor(tags, map[int]int{2: 100, 7: 50})
+	if cursor != "2:100,7:50,9:0" {
+		t.Fatalf("cursor=%q", cursor)
+	}
+	// A tag added to the profile since starts at 0; a dropped one is ignored.
+	got := parseTagCursor("2:100,5:40", []int{2, 11})
+	if got[2] != 100 || got[11] != 0 {
+		t.Fatalf("parse=%v", got)
+	}
+	// A pre-profile plain offset does not apply to several tags.
+	if got := parseTagCursor("300", tags); len(got) != 0 {
+		t.Fatalf("plain offset on several tags=%v", got)
+	}
+}
+
+func TestSyncIncludeTagsAndExclude(t *testing.T) {
+	if got := syncIncludeTags(SyncOptions{}); len(got) != 1 || got[0] != 0 {
+		t.Fatalf("no tags=%v", got)
+	}
+	tag := 7
+	got := syncIncludeTags(SyncOptions{TagID: &tag, TagIDs: []int{3, 7, 0, 3}})
+	if len(got) != 2 || got[0] != 7 || got[1] != 3 {
+		t.Fatalf("tags=%v", got)
+	}
+
+	items := []polymarketgamma.Event{
+		{ID: "a", Tags: []polymarketgamma.Tag{{ID: "1"}}},
+		{ID: "b", Tags: []polymarketgamma.Tag{{ID: "1"}, {ID: "4"}}},
+		{ID: "c"},
+	}
+	kept := excludeTaggedEvents(items, map[string]bool{"4": true})
+	if len(kept) != 2 || kept[0].ID != "a" || kept[1].ID != "c" {
+		t.Fatalf("kept=%+v", kept)
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	svc := &CatalogSyncService{Profiles: map[string]config.CatalogSyncProfile{
+		"sports": {TagIDs: []int{1, 2}, ExcludeTagIDs: []int{9}},
+	}}
+	tag := 5
+	opts := SyncOptions{TagID: &tag}
+	if err := svc.ApplyProfile(&opts, " Sports "); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if opts.Profile != "sports" || opts.TagID != nil || len(opts.TagIDs) != 2 || len(opts.ExcludeTagIDs) != 1 {
+		t.Fatalf("opts=%+v", opts)
+	}
+	if err := svc.ApplyProfile(&opts, "nope"); err == nil {
+		t.Fatalf("expected unknown profile error")
+	}
+	if SyncStateScope("events", opts.Profile) != "events@sports" || SyncStateScope("events", "") != "events" {
+		t.Fatalf("unexpected state scope")
+	}
+}
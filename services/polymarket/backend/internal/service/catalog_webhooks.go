@@ -0,0 +1,541 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+const (
+	CatalogEntityEvent  = "event"
+	CatalogEntityMarket = "market"
+
+	// CatalogWebhookSecretKey is the setting key secrets are protected under;
+	// it names a secret so ProtectSettingValue encrypts it.
+	CatalogWebhookSecretKey = "catalog_webhook.secret"
+
+	catalogWebhookPayloadType = "catalog.new_entities"
+	catalogWebhookCacheTTL    = 30 * time.Second
+)
+
+// CatalogWebhookService delivers new catalog events and markets to the
+// subscribed endpoints. Catalog sync calls PublishNew after each committed
+// page; deliveries are queued and sent by Run, so a slow endpoint never
+// holds up a sync. With the outbox enabled they are recorded there instead
+// and retried by its dispatcher until delivered.
+type CatalogWebhookService struct {
+	Repo   repository.Repository
+	Config config.CatalogWebhooksConfig
+	Client *http.Client
+	Logger *zap.Logger
+	Outbox *OutboxDispatcher
+
+	once  sync.Once
+	queue chan catalogDelivery
+
+	mu       sync.Mutex
+	cached   []models.CatalogWebhook
+	cachedAt time.Time
+}
+
+// CatalogWebhookPayload is the JSON body of a delivery.
+type CatalogWebhookPayload struct {
+	Type      string               `json:"type"`
+	WebhookID uint64               `json:"webhook_id"`
+	SentAt    time.Time            `json:"sent_at"`
+	Events    []CatalogWebhookItem `json:"events"`
+	Markets   []CatalogWebhookItem `json:"markets"`
+}
+
+// CatalogWebhookItem describes one new entity. Liquidity is the market's, or
+// for an event the sum over its markets in the same sync page.
+type CatalogWebhookItem struct {
+	ID           string   `json:"id"`
+	EventID      string   `json:"event_id,omitempty"`
+	Slug         string   `json:"slug,omitempty"`
+	Title        string   `json:"title"`
+	Tags         []string `json:"tags,omitempty"`
+	LiquidityUSD float64  `json:"liquidity_usd"`
+}
+
+// CatalogDeliveryResult is the outcome of one delivery attempt.
+type CatalogDeliveryResult struct {
+	DeliveryID string `json:"delivery_id"`
+	Status     int    `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+type catalogDelivery struct {
+	hook    models.CatalogWebhook
+	payload CatalogWebhookPayload
+}
+
+func (s *CatalogWebhookService) init() {
+	s.once.Do(func() {
+		size := s.Config.QueueSize
+		if size <= 0 {
+			size = 256
+		}
+		s.queue = make(chan catalogDelivery, size)
+	})
+}
+
+// Wants reports whether any enabled subscription exists, so sync can skip
+// the new-entity lookups when nobody listens.
+func (s *CatalogWebhookService) Wants(ctx context.Context) bool {
+	if s == nil || !s.Config.Enabled || s.Repo == nil {
+		return false
+	}
+	hooks, err := s.subscriptions(ctx)
+	return err == nil && len(hooks) > 0
+}
+
+// Invalidate drops the cached subscription list after an edit.
+func (s *CatalogWebhookService) Invalidate() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.cached = nil
+	s.cachedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *CatalogWebhookService) subscriptions(ctx context.Context) ([]models.CatalogWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.cachedAt.IsZero() && time.Since(s.cachedAt) < catalogWebhookCacheTTL {
+		return s.cached, nil
+	}
+	hooks, err := s.Repo.ListCatalogWebhooks(ctx, repository.ListCatalogWebhooksParams{EnabledOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	s.cached = hooks
+	s.cachedAt = time.Now()
+	return hooks, nil
+}
+
+// PublishNew matches new entities against every enabled subscription and
+// queues one delivery per matching webhook. tags maps event IDs to the tags
+// seen in the sync page; events missing from it are looked up.
+func (s *CatalogWebhookService) PublishNew(ctx context.Context, events []models.Event, markets []models.Market, tags map[string][]models.Tag) {
+	if s == nil || !s.Config.Enabled || (len(events) == 0 && len(markets) == 0) {
+		return
+	}
+	hooks, err := s.subscriptions(ctx)
+	if err != nil {
+		s.warn("catalog webhooks: list subscriptions", zap.Error(err))
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+	tags = s.completeTags(ctx, events, markets, tags)
+
+	eventLiquidity := map[string]float64{}
+	marketItems := make([]CatalogWebhookItem, 0, len(markets))
+	for _, m := range markets {
+		liq := 0.0
+		if m.Liquidity != nil {
+			liq = m.Liquidity.InexactFloat64()
+		}
+		eventLiquidity[m.EventID] += liq
+		item := CatalogWebhookItem{ID: m.ID, EventID: m.EventID, Title: m.Question, Tags: tagNames(tags[m.EventID]), LiquidityUSD: liq}
+		if m.Slug != nil {
+			item.Slug = *m.Slug
+		}
+		marketItems = append(marketItems, item)
+	}
+	eventItems := make([]CatalogWebhookItem, 0, len(events))
+	for _, e := range events {
+		eventItems = append(eventItems, CatalogWebhookItem{ID: e.ID, Slug: e.Slug, Title: e.Title, Tags: tagNames(tags[e.ID]), LiquidityUSD: eventLiquidity[e.ID]})
+	}
+
+	s.init()
+	now := time.Now().UTC()
+	var outbox []models.OutboxMessage
+	for _, hook := range hooks {
+		payload := CatalogWebhookPayload{Type: catalogWebhookPayloadType, WebhookID: hook.ID, SentAt: now}
+		if wantsEntity(hook, CatalogEntityEvent) {
+			payload.Events = matchCatalogItems(hook, eventItems)
+		}
+		if wantsEntity(hook, CatalogEntityMarket) {
+			payload.Markets = matchCatalogItems(hook, marketItems)
+		}
+		if len(payload.Events) == 0 && len(payload.Markets) == 0 {
+			continue
+		}
+		if s.Outbox.Enabled() {
+			outbox = append(outbox, NewOutboxCatalogWebhook(hook.ID, payload))
+			continue
+		}
+		select {
+		case s.queue <- catalogDelivery{hook: hook, payload: payload}:
+		default:
+			s.warn("catalog webhooks: queue full, delivery dropped", zap.Uint64("webhook_id", hook.ID))
+		}
+	}
+	if len(outbox) > 0 {
+		if err := s.Outbox.Enqueue(ctx, outbox); err != nil {
+			s.warn("catalog webhooks: enqueue deliveries", zap.Int("deliveries", len(outbox)), zap.Error(err))
+		}
+	}
+}
+
+// OutboxCatalogWebhookPayload is the body of a catalog webhook outbox
+// message.
+type OutboxCatalogWebhookPayload struct {
+	WebhookID uint64                `json:"webhook_id"`
+	Payload   CatalogWebhookPayload `json:"payload"`
+}
+
+func NewOutboxCatalogWebhook(webhookID uint64, payload CatalogWebhookPayload) models.OutboxMessage {
+	return newOutboxMessage(models.OutboxKindCatalogWebhook, OutboxCatalogWebhookPayload{WebhookID: webhookID, Payload: payload})
+}
+
+// DeliverQueued sends an outbox delivery once. A delivery whose webhook was
+// deleted or disabled is dropped.
+func (s *CatalogWebhookService) DeliverQueued(ctx context.Context, webhookID uint64, payload CatalogWebhookPayload) error {
+	if s == nil || s.Repo == nil {
+		return errors.New("catalog webhooks unavailable")
+	}
+	hook, err := s.Repo.GetCatalogWebhookByID(ctx, webhookID)
+	if err != nil || hook == nil || !hook.Enabled {
+		return err
+	}
+	if res := s.Deliver(ctx, *hook, payload); res.Error != "" {
+		return errors.New(res.Error)
+	}
+	return nil
+}
+
+func (s *CatalogWebhookService) completeTags(ctx context.Context, events []models.Event, markets []models.Market, tags map[string][]models.Tag) map[string][]models.Tag {
+	out := make(map[string][]models.Tag, len(tags))
+	for k, v := range tags {
+		out[k] = v
+	}
+	var missing []string
+	seen := map[string]bool{}
+	add := func(id string) {
+		if id == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+		if _, ok := out[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	for _, e := range events {
+		add(e.ID)
+	}
+	for _, m := range markets {
+		add(m.EventID)
+	}
+	if len(missing) == 0 || s.Repo == nil {
+		return out
+	}
+	found, err := s.Repo.ListTagsByEventIDs(ctx, missing)
+	if err != nil {
+		s.warn("catalog webhooks: list tags", zap.Error(err))
+		return out
+	}
+	for k, v := range found {
+		out[k] = v
+	}
+	return out
+}
+
+// Run sends queued deliveries until ctx is done.
+func (s *CatalogWebhookService) Run(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	s.init()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-s.queue:
+			s.deliverWithRetry(ctx, d)
+		}
+	}
+}
+
+func (s *CatalogWebhookService) deliverWithRetry(ctx context.Context, d catalogDelivery) {
+	attempts := s.Config.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	backoff := s.Config.RetryBackoff
+	var res CatalogDeliveryResult
+	for i := 0; i < attempts; i++ {
+		if i > 0 && backoff > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff * time.Duration(i)):
+			}
+		}
+		res = s.Deliver(ctx, d.hook, d.payload)
+		if res.Error == "" {
+			return
+		}
+	}
+	s.warn("catalog webhooks: delivery failed",
+		zap.Uint64("webhook_id", d.hook.ID),
+		zap.Int("status", res.Status),
+		zap.String("error", res.Error),
+	)
+}
+
+// Deliver posts payload to the webhook once and records the outcome. Any
+// non-2xx status is a failure.
+func (s *CatalogWebhookService) Deliver(ctx context.Context, hook models.CatalogWebhook, payload CatalogWebhookPayload) CatalogDeliveryResult {
+	res := CatalogDeliveryResult{DeliveryID: newDeliveryID()}
+	res.Status, res.Error = s.post(ctx, hook, payload, res.DeliveryID)
+	if s.Repo != nil {
+		if err := s.Repo.RecordCatalogWebhookDelivery(ctx, hook.ID, res.Status, res.Error, time.Now().UTC()); err != nil {
+			s.warn("catalog webhooks: record delivery", zap.Error(err))
+		}
+	}
+	return res
+}
+
+func (s *CatalogWebhookService) post(ctx context.Context, hook models.CatalogWebhook, payload CatalogWebhookPayload, deliveryID string) (int, string) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err.Error()
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	secret := string(RevealSettingValue(CatalogWebhookSecretKey, []byte(hook.Secret)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Catalog-Webhook-Id", strconv.FormatUint(hook.ID, 10))
+	req.Header.Set("X-Catalog-Delivery", deliveryID)
+	req.Header.Set("X-Catalog-Timestamp", ts)
+	req.Header.Set("X-Catalog-Signature", SignCatalogWebhook(secret, ts, body))
+
+	client := s.Client
+	if client == nil {
+		timeout := s.Config.Timeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, ""
+}
+
+// SignCatalogWebhook returns the X-Catalog-Signature value: the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed by the webhook secret.
+// Receivers should recompute it and reject stale timestamps.
+func SignCatalogWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewCatalogWebhookSecret returns a random 32-byte hex secret.
+func NewCatalogWebhookSecret() string {
+	buf := make([]byte, 32)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func newDeliveryID() string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func wantsEntity(hook models.CatalogWebhook, entity string) bool {
+	types := decodeStringList(hook.EntityTypes)
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if strings.EqualFold(strings.TrimSpace(t), entity) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCatalogItems applies the tag, keyword and liquidity filters. Each
+// filter is skipped when empty; keywords and tags match any one entry.
+func matchCatalogItems(hook models.CatalogWebhook, items []CatalogWebhookItem) []CatalogWebhookItem {
+	tags := lowerList(decodeStringList(hook.Tags))
+	keywords := lowerList(decodeStringList(hook.Keywords))
+	var out []CatalogWebhookItem
+	for _, item := range items {
+		if item.LiquidityUSD < hook.MinLiquidityUSD {
+			continue
+		}
+		if len(keywords) > 0 && !containsAny(strings.ToLower(item.Title), keywords) {
+			continue
+		}
+		if len(tags) > 0 && !intersects(lowerList(item.Tags), tags) {
+			continue
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// tagNames lists each tag's slug and label so filters can use either.
+func tagNames(tags []models.Tag) []string {
+	out := make([]string, 0, len(tags)*2)
+	for _, t := range tags {
+		if t.Slug != "" {
+			out = append(out, t.Slug)
+		}
+		if t.Label != "" && !strings.EqualFold(t.Label, t.Slug) {
+			out = append(out, t.Label)
+		}
+	}
+	return out
+}
+
+func decodeStringList(raw []byte) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var out []string
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+func lowerList(items []string) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if v := strings.ToLower(strings.TrimSpace(item)); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func containsAny(text string, needles []string) bool {
+	for _, n := range needles {
+		if strings.Contains(text, n) {
+			return true
+		}
+	}
+	return false
+}
+
+func intersects(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *CatalogWebhookService) warn(msg string, fields ...zap.Field) {
+	if s.Logger != nil {
+		s.Logger.Warn(msg, fields...)
+	}
+}
+
+// newCatalogEntities returns the events and markets not stored yet. It runs
+// before the upsert and only when a webhook listens.
+func (s *CatalogSyncService) newCatalogEntities(ctx context.Context, events []models.Event, markets []models.Market) ([]models.Event, []models.Market, error) {
+	if !s.Webhooks.Wants(ctx) {
+		return nil, nil, nil
+	}
+	var newEvents []models.Event
+	if len(events) > 0 {
+		ids := make([]string, 0, len(events))
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		stored, err := s.Store.ListEventsByIDs(ctx, ids)
+		if err != nil {
+			return nil, nil, err
+		}
+		known := make(map[string]bool, len(stored))
+		for _, e := range stored {
+			known[e.ID] = true
+		}
+		for _, e := range events {
+			if !known[e.ID] {
+				newEvents = append(newEvents, e)
+			}
+		}
+	}
+	var newMarkets []models.Market
+	if len(markets) > 0 {
+		ids := make([]string, 0, len(markets))
+		for _, m := range markets {
+			ids = append(ids, m.ID)
+		}
+		stored, err := s.Store.ListMarketsByIDs(ctx, ids)
+		if err != nil {
+			return nil, nil, err
+		}
+		known := make(map[string]bool, len(stored))
+		for _, m := range stored {
+			known[m.ID] = true
+		}
+		for _, m := range markets {
+			if !known[m.ID] {
+				newMarkets = append(newMarkets, m)
+			}
+		}
+	}
+	return newEvents, newMarkets, nil
+}
+
+// pageTags groups a sync page's tags by event.
+func pageTags(tags []models.Tag, eventTags []models.EventTag) map[string][]models.Tag {
+	byID := make(map[string]models.Tag, len(tags))
+	for _, t := range tags {
+		byID[t.ID] = t
+	}
+	out := make(map[string][]models.Tag)
+	for _, et := range eventTags {
+		if t, ok := byID[et.TagID]; ok {
+			out[et.EventID] = append(out[et.EventID], t)
+		}
+	}
+	return out
+}
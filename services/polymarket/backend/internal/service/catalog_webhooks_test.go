@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type webhookRepo struct {
+	repository.Repository
+	hooks    []models.CatalogWebhook
+	statuses []int
+}
+
+func (r *webhookRepo) ListCatalogWebhooks(ctx context.Context, params repository.ListCatalogWebhooksParams) ([]models.CatalogWebhook, error) {
+	return r.hooks, nil
+}
+
+func (r *webhookRepo) ListTagsByEventIDs(ctx context.Context, eventIDs []string) (map[string][]models.Tag, error) {
+	return map[string][]models.Tag{"e2": {{Slug: "nba", Label: "NBA"}}}, nil
+}
+
+func (r *webhookRepo) RecordCatalogWebhookDelivery(ctx context.Context, id uint64, status int, errMsg string, at time.Time) error {
+	r.statuses = append(r.statuses, status)
+	return nil
+}
+
+func TestCatalogWebhookService_FiltersAndSigns(t *testing.T) {
+	type received struct {
+		sig, ts string
+		body    []byte
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{sig: r.Header.Get("X-Catalog-Signature"), ts: r.Header.Get("X-Catalog-Timestamp"), body: body}
+	}))
+	defer srv.Close()
+
+	repo := &webhookRepo{hooks: []models.CatalogWebhook{{
+		ID:              1,
+		URL:             srv.URL,
+		Secret:          "s3cret",
+		EntityTypes:     datatypes.JSON(`["market"]`),
+		Tags:            datatypes.JSON(`["NBA"]`),
+		Keywords:        datatypes.JSON(`["lakers"]`),
+		MinLiquidityUSD: 1000,
+		Enabled:         true,
+	}}}
+	svc := &CatalogWebhookService{Repo: repo, Config: config.CatalogWebhooksConfig{Enabled: true, MaxAttempts: 1}}
+	liq := func(v float64) *decimal.Decimal { d := decimal.NewFromFloat(v); return &d }
+	events := []models.Event{{ID: "e1", Title: "Lakers season"}, {ID: "e2", Title: "Lakers season"}}
+	markets := []models.Market{
+		{ID: "m1", EventID: "e1", Question: "Lakers win?", Liquidity: liq(5000)}, // no NBA tag
+		{ID: "m2", EventID: "e2", Question: "Lakers win?", Liquidity: liq(500)},  // below floor
+		{ID: "m3", EventID: "e2", Question: "LAKERS win title?", Liquidity: liq(2000)},
+		{ID: "m4", EventID: "e2", Question: "Celtics win?", Liquidity: liq(9000)}, // no keyword
+	}
+	if !svc.Wants(context.Background()) {
+		t.Fatal("Wants = false with an enabled subscription")
+	}
+	svc.PublishNew(context.Background(), events, markets, map[string][]models.Tag{"e1": nil})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Run(ctx)
+	var r received
+	select {
+	case r = <-got:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery")
+	}
+	if want := SignCatalogWebhook("s3cret", r.ts, r.body); r.sig != want {
+		t.Fatalf("signature = %q, want %q", r.sig, want)
+	}
+	var payload CatalogWebhookPayload
+	if err := json.Unmarshal(r.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(payload.Events) != 0 || len(payload.Markets) != 1 || payload.Markets[0].ID != "m3" {
+		t.Fatalf("payload = %+v", payload)
+	}
+}
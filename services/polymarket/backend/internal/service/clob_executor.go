@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -23,6 +24,17 @@ type ExecutorConfig struct {
 	Mode                 string
 	MaxOrderSizeUSD      decimal.Decimal
 	SlippageToleranceBps int
+	// MergeChildOrders folds same-side, same-price legs of a plan on one
+	// token into a single order, and sends live taker orders of different
+	// plans that wait on the same market together as one book order.
+	MergeChildOrders bool
+	// Maker holds the defaults for plans priced in maker mode.
+	Maker MakerConfig
+	// Repricer moves resting orders back toward the book when it moves in
+	// our favor.
+	Repricer RepricerConfig
+	// Dependencies holds the defaults for legs that wait on other legs.
+	Dependencies LegDependencyConfig
 }
 
 type SubmitResult struct {
@@ -39,10 +51,22 @@ type CLOBExecutor struct {
 	Config       ExecutorConfig
 	PositionSync *PositionSyncService
 	Client       *polymarketclob.Client
+	// Throttle, when set, serializes live submissions per market.
+	Throttle *MarketThrottle
+	// Settings, when set, serves mode and broker settings from memory.
+	Settings *SettingsCache
+	// Notify routes operator alerts, such as orders held for review, to the
+	// notification dispatcher; nil broadcasts through the platform client in
+	// ctx.
+	Notify func(ctx context.Context, event, message string) error
+
+	keysMu     sync.Mutex
+	marketKeys map[string]string
 }
 
 type orderLeg struct {
 	TokenID        string   `json:"token_id"`
+	MarketID       string   `json:"market_id"`
 	Direction      string   `json:"direction"`
 	TargetPrice    *float64 `json:"target_price"`
 	CurrentBestAsk *float64 `json:"current_best_ask"`
@@ -55,6 +79,8 @@ type orderLeg struct {
 	OrderType      string   `json:"order_type"`
 	Owner          string   `json:"owner"`
 	PostOnly       *bool    `json:"post_only"`
+	// DependsOn holds the leg back until other legs of the plan fill.
+	DependsOn *legDependency `json:"depends_on"`
 }
 
 func (e *CLOBExecutor) SubmitPlan(ctx context.Context, planID uint64) (*SubmitResult, error) {
@@ -89,19 +115,23 @@ func (e *CLOBExecutor) SubmitPlan(ctx context.Context, planID uint64) (*SubmitRe
 		return nil, fmt.Errorf("plan has no legs")
 	}
 
-	orderIDs := make([]uint64, 0, len(legs))
+	pricingMode, makerCfg, slippage := planPricing(plan.Params, e.Config.Maker)
+	books := map[string]makerBook{}
+	if pricingMode == PricingModeMaker {
+		books, err = e.latestBooks(ctx, legs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	perLeg := plan.PlannedSizeUSD.Div(decimal.NewFromInt(int64(len(legs))))
+	children := make([]childOrder, 0, len(legs))
 	for _, leg := range legs {
 		tokenID := strings.TrimSpace(leg.TokenID)
 		if tokenID == "" {
 			continue
 		}
-		price := decimal.NewFromFloat(0.5)
-		if leg.TargetPrice != nil && *leg.TargetPrice > 0 {
-			price = decimal.NewFromFloat(*leg.TargetPrice)
-		} else if leg.CurrentBestAsk != nil && *leg.CurrentBestAsk > 0 {
-			price = decimal.NewFromFloat(*leg.CurrentBestAsk)
-		}
+		price := legTakerPrice(leg)
 		sizeUSD := perLeg
 		if leg.SizeUSD != nil && *leg.SizeUSD > 0 {
 			sizeUSD = decimal.NewFromFloat(*leg.SizeUSD)
@@ -109,20 +139,55 @@ func (e *CLOBExecutor) SubmitPlan(ctx context.Context, planID uint64) (*SubmitRe
 		if e.Config.MaxOrderSizeUSD.GreaterThan(decimal.Zero) && sizeUSD.GreaterThan(e.Config.MaxOrderSizeUSD) {
 			sizeUSD = e.Config.MaxOrderSizeUSD
 		}
+		side := strings.ToUpper(strings.TrimSpace(leg.Direction))
+		if side == "" {
+			side = "BUY_YES"
+		}
+		mode := PricingModeTaker
+		if book, ok := books[tokenID]; ok && leg.SignedOrder == nil {
+			// Pre-signed legs commit to their price and stay taker.
+			limit := makerLimit(side, price.InexactFloat64(), slippage)
+			if p, ok := makerEntryPrice(side, book, limit, makerCfg); ok {
+				price, mode = decimal.NewFromFloat(p), PricingModeMaker
+				if leg.PostOnly == nil {
+					leg.PostOnly = boolPtrExecutor(true)
+				}
+			}
+		}
+		children = append(children, childOrder{Leg: leg, TokenID: tokenID, Side: side, Price: price, SizeUSD: sizeUSD, PricingMode: mode})
+	}
+	if e.Config.MergeChildOrders {
+		children = mergeChildOrders(children, e.Config.MaxOrderSizeUSD)
+	}
+	if err := validateLegDependencies(children); err != nil {
+		return nil, err
+	}
+	if e.Risk != nil {
+		if err := e.Risk.CheckRateLimit(ctx, risk.LimitOpenOrders, len(children)); err != nil {
+			return nil, err
+		}
+	}
+
+	orderIDs := make([]uint64, 0, len(children))
+	for _, child := range children {
+		leg, tokenID, price, sizeUSD := child.Leg, child.TokenID, child.Price, child.SizeUSD
+		status := "pending"
+		if mode != "dry-run" && leg.DependsOn.active() {
+			// Dry runs fill every leg at once, so only live legs wait.
+			status = OrderStatusHeld
+		}
 		order := &models.Order{
-			PlanID:    plan.ID,
-			TokenID:   tokenID,
-			Side:      strings.ToUpper(strings.TrimSpace(leg.Direction)),
-			OrderType: "limit",
-			Price:     price,
-			SizeUSD:   sizeUSD,
-			FilledUSD: decimal.Zero,
-			Status:    "pending",
-			CreatedAt: time.Now().UTC(),
-			UpdatedAt: time.Now().UTC(),
-		}
-		if order.Side == "" {
-			order.Side = "BUY_YES"
+			PlanID:      plan.ID,
+			TokenID:     tokenID,
+			Side:        child.Side,
+			OrderType:   "limit",
+			PricingMode: child.PricingMode,
+			Price:       price,
+			SizeUSD:     sizeUSD,
+			FilledUSD:   decimal.Zero,
+			Status:      status,
+			CreatedAt:   time.Now().UTC(),
+			UpdatedAt:   time.Now().UTC(),
 		}
 		if err := e.Repo.InsertOrder(ctx, order); err != nil {
 			return nil, err
@@ -140,21 +205,22 @@ func (e *CLOBExecutor) SubmitPlan(ctx context.Context, planID uint64) (*SubmitRe
 				fillSize = sizeUSD.Div(price)
 			}
 			fill := &models.Fill{
-				PlanID:     plan.ID,
-				TokenID:    tokenID,
-				Direction:  order.Side,
-				FilledSize: fillSize,
-				AvgPrice:   price,
-				Fee:        decimal.Zero,
-				FilledAt:   now,
-				CreatedAt:  now,
+				PlanID:         plan.ID,
+				TokenID:        tokenID,
+				Direction:      order.Side,
+				OrderLineageID: order.Lineage(),
+				FilledSize:     fillSize,
+				AvgPrice:       price,
+				Fee:            decimal.Zero,
+				FilledAt:       now,
+				CreatedAt:      now,
 			}
 			_ = e.Repo.InsertFill(ctx, fill)
 			if e.PositionSync != nil {
 				_ = e.PositionSync.SyncFromFill(ctx, *fill)
 			}
-		} else {
-			status, updates, err := e.submitLiveOrder(ctx, *plan, *order, leg)
+		} else if status != OrderStatusHeld {
+			status, updates, err := e.submitThrottled(ctx, *plan, *order, leg)
 			if err != nil {
 				_ = e.Repo.UpdateOrderStatus(ctx, order.ID, "failed", map[string]any{
 					"failure_reason": err.Error(),
@@ -175,10 +241,14 @@ func (e *CLOBExecutor) SubmitPlan(ctx context.Context, planID uint64) (*SubmitRe
 	if mode == "dry-run" {
 		now := time.Now().UTC()
 		_ = e.Repo.UpdateExecutionPlanExecutedAt(ctx, plan.ID, "executed", &now)
-		_ = e.Repo.UpdateOpportunityStatus(ctx, plan.OpportunityID, "executed")
+		if plan.OpportunityID > 0 {
+			_ = e.Repo.UpdateOpportunityStatus(ctx, plan.OpportunityID, "executed")
+		}
 	} else {
 		_ = e.Repo.UpdateExecutionPlanStatus(ctx, plan.ID, "executing")
-		_ = e.Repo.UpdateOpportunityStatus(ctx, plan.OpportunityID, "executing")
+		if plan.OpportunityID > 0 {
+			_ = e.Repo.UpdateOpportunityStatus(ctx, plan.OpportunityID, "executing")
+		}
 	}
 
 	return &SubmitResult{
@@ -189,6 +259,159 @@ func (e *CLOBExecutor) SubmitPlan(ctx context.Context, planID uint64) (*SubmitRe
 	}, nil
 }
 
+// submitThrottled submits a live order once the order's market is free. The
+// wait is bounded by ctx; a cancelled wait fails the order like a submit error.
+// With MergeChildOrders, taker orders of any plan waiting on the market at the
+// same token, side and price go to the book as one order: the first to get
+// the market submits for all of them, and each gets its share of the result.
+func (e *CLOBExecutor) submitThrottled(ctx context.Context, plan models.ExecutionPlan, order models.Order, leg orderLeg) (string, map[string]any, error) {
+	var w *MergeWaiter
+	if e.mergeable(order, leg) {
+		w = NewMergeWaiter(orderMergeKey(order), order.ID, order.SizeUSD)
+	}
+	release, followers, merged, err := e.Throttle.AcquireMerge(ctx, e.marketKey(ctx, leg.MarketID, order.TokenID), w, e.Config.MaxOrderSizeUSD)
+	if err != nil {
+		return "", nil, fmt.Errorf("market throttle: %w", err)
+	}
+	if merged != nil {
+		if merged.Err != nil {
+			return "", nil, merged.Err
+		}
+		return merged.Status, mergedShare(merged.Updates, order.SizeUSD, merged.TotalUSD, merged.LeaderID), nil
+	}
+	defer release()
+	if len(followers) == 0 {
+		return e.submitLiveOrder(ctx, plan, order, leg)
+	}
+	total := order.SizeUSD
+	for _, f := range followers {
+		total = total.Add(f.SizeUSD)
+	}
+	combined := order
+	combined.SizeUSD = total
+	if leg.SizeUSD != nil {
+		v := total.InexactFloat64()
+		leg.SizeUSD = &v
+	}
+	status, updates, err := e.submitLiveOrder(ctx, plan, combined, leg)
+	res := MergeResult{LeaderID: order.ID, TotalUSD: total, Status: status, Updates: updates, Err: err}
+	for _, f := range followers {
+		f.Finish(res)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if e.Logger != nil {
+		e.Logger.Info("merged live orders", zap.Uint64("order_id", order.ID), zap.Int("orders", len(followers)+1), zap.String("size_usd", total.String()))
+	}
+	return status, mergedShare(updates, order.SizeUSD, total, order.ID), nil
+}
+
+// mergeable reports whether order may share a book order with other plans'.
+// Pre-signed orders commit to their size, and maker orders are stepped one
+// by one, so neither is merged.
+func (e *CLOBExecutor) mergeable(order models.Order, leg orderLeg) bool {
+	if !e.Config.MergeChildOrders || e.Throttle == nil || leg.SignedOrder != nil {
+		return false
+	}
+	return order.PricingMode == "" || order.PricingMode == PricingModeTaker
+}
+
+func orderMergeKey(o models.Order) string {
+	return strings.Join([]string{o.TokenID, o.Side, o.OrderType, o.Price.String()}, "|")
+}
+
+// mergedShare scales the fill of a merged book order of totalUSD down to a
+// member of sizeUSD and records the merge on the member.
+func mergedShare(updates map[string]any, sizeUSD, totalUSD decimal.Decimal, leaderID uint64) map[string]any {
+	out := make(map[string]any, len(updates)+1)
+	for k, v := range updates {
+		out[k] = v
+	}
+	if filled, ok := out["filled_usd"].(decimal.Decimal); ok && totalUSD.IsPositive() {
+		out["filled_usd"] = filled.Mul(sizeUSD).Div(totalUSD).Round(10)
+	}
+	out["merged_into_id"] = leaderID
+	return out
+}
+
+// mergedGroup returns the orders that share order's book order, order
+// included, and their total size.
+func (e *CLOBExecutor) mergedGroup(ctx context.Context, order models.Order) ([]models.Order, decimal.Decimal, error) {
+	if order.MergedIntoID == nil {
+		return []models.Order{order}, order.SizeUSD, nil
+	}
+	items, err := e.Repo.ListOrders(ctx, repository.ListOrdersParams{MergedIntoID: order.MergedIntoID, Limit: 500})
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+	if len(items) == 0 {
+		items = []models.Order{order}
+	}
+	total := decimal.Zero
+	for _, it := range items {
+		total = total.Add(it.SizeUSD)
+	}
+	return items, total, nil
+}
+
+// latestBooks loads the latest book of every leg token.
+func (e *CLOBExecutor) latestBooks(ctx context.Context, legs []orderLeg) (map[string]makerBook, error) {
+	tokenIDs := make([]string, 0, len(legs))
+	for _, leg := range legs {
+		if id := strings.TrimSpace(leg.TokenID); id != "" {
+			tokenIDs = append(tokenIDs, id)
+		}
+	}
+	rows, err := e.Repo.ListOrderbookLatestByTokenIDs(ctx, tokenIDs)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]makerBook, len(rows))
+	for _, r := range rows {
+		out[r.TokenID] = makerBookFromLatest(r)
+	}
+	return out, nil
+}
+
+// marketKey maps a token to its market so both outcomes of a market share one
+// throttle slot. The leg's market is used when it has one; otherwise the
+// token is looked up once and cached. Unknown tokens are throttled on their
+// own.
+func (e *CLOBExecutor) marketKey(ctx context.Context, marketID, tokenID string) string {
+	if e.Throttle == nil {
+		return ""
+	}
+	if marketID = strings.TrimSpace(marketID); marketID != "" {
+		return marketID
+	}
+	e.keysMu.Lock()
+	key, ok := e.marketKeys[tokenID]
+	e.keysMu.Unlock()
+	if ok {
+		return key
+	}
+	tokens, err := e.Repo.ListTokensByIDs(ctx, []string{tokenID})
+	if err != nil {
+		return tokenID
+	}
+	key = tokenID
+	if len(tokens) > 0 && strings.TrimSpace(tokens[0].MarketID) != "" {
+		key = tokens[0].MarketID
+	}
+	e.keysMu.Lock()
+	if e.marketKeys == nil || len(e.marketKeys) >= maxCachedMarketKeys {
+		e.marketKeys = map[string]string{}
+	}
+	e.marketKeys[tokenID] = key
+	e.keysMu.Unlock()
+	return key
+}
+
+// maxCachedMarketKeys bounds the token to market cache; it starts over when
+// full.
+const maxCachedMarketKeys = 10000
+
 func (e *CLOBExecutor) PollOrders(ctx context.Context) error {
 	if e == nil || e.Repo == nil {
 		return nil
@@ -203,6 +426,10 @@ func (e *CLOBExecutor) PollOrders(ctx context.Context) error {
 			if strings.TrimSpace(order.ClobOrderID) == "" {
 				continue
 			}
+			if order.MergedIntoID != nil && *order.MergedIntoID != order.ID {
+				// Synced with the order that submitted it.
+				continue
+			}
 			status, updates, err := e.fetchLiveOrder(ctx, order.ClobOrderID)
 			if err != nil {
 				if e.Logger != nil {
@@ -210,18 +437,44 @@ func (e *CLOBExecutor) PollOrders(ctx context.Context) error {
 				}
 				continue
 			}
-			if err := e.Repo.UpdateOrderStatus(ctx, order.ID, status, updates); err != nil {
-				continue
-			}
-			if status == "filled" || status == "partial" {
-				_ = e.applyOrderFillDelta(ctx, order, updates)
-			}
-			_ = e.reconcilePlanStatus(ctx, order.PlanID)
+			e.applyLiveStatus(ctx, order, status, updates)
+		}
+		if err := e.releaseHeldOrders(ctx); err != nil && e.Logger != nil {
+			e.Logger.Warn("held order release pass failed", zap.Error(err))
+		}
+		if err := e.stepMakerOrders(ctx); err != nil && e.Logger != nil {
+			e.Logger.Warn("maker reprice pass failed", zap.Error(err))
+		}
+		if err := e.improveRestingOrders(ctx); err != nil && e.Logger != nil {
+			e.Logger.Warn("price improvement pass failed", zap.Error(err))
 		}
 	}
 	return nil
 }
 
+// applyLiveStatus records a venue status for order and, when it was merged,
+// for every order sharing its book order, each with its share of the fill.
+func (e *CLOBExecutor) applyLiveStatus(ctx context.Context, order models.Order, status string, updates map[string]any) error {
+	group, total, err := e.mergedGroup(ctx, order)
+	if err != nil {
+		return err
+	}
+	for _, member := range group {
+		u := updates
+		if member.MergedIntoID != nil {
+			u = mergedShare(updates, member.SizeUSD, total, *member.MergedIntoID)
+		}
+		if err := e.Repo.UpdateOrderStatus(ctx, member.ID, status, u); err != nil {
+			return err
+		}
+		if status == "filled" || status == "partial" {
+			_ = e.applyOrderFillDelta(ctx, member, u)
+		}
+		_ = e.reconcilePlanStatus(ctx, member.PlanID)
+	}
+	return nil
+}
+
 func (e *CLOBExecutor) CancelOrder(ctx context.Context, orderID uint64) error {
 	if e == nil || e.Repo == nil || orderID == 0 {
 		return nil
@@ -233,12 +486,13 @@ func (e *CLOBExecutor) CancelOrder(ctx context.Context, orderID uint64) error {
 	if order == nil {
 		return nil
 	}
-	switch order.Status {
-	case "submitted", "partial", "pending":
+	switch {
+	case models.IsOpenOrderStatus(order.Status):
 		if e.resolveMode(ctx) == "live" && strings.TrimSpace(order.ClobOrderID) != "" {
-			status, updates, err := e.cancelLiveOrder(ctx, order.ClobOrderID)
+			status, updates, err := e.cancelLiveOrder(ctx, *order)
 			if err == nil {
-				return e.Repo.UpdateOrderStatus(ctx, orderID, status, updates)
+				// A merged book order is gone for every plan in it.
+				return e.applyLiveStatus(ctx, *order, status, updates)
 			}
 			if e.Logger != nil {
 				e.Logger.Warn("cancel live order failed, fallback local cancel", zap.Uint64("order_id", orderID), zap.Error(err))
@@ -264,16 +518,8 @@ func parseOrderLegs(raw []byte) ([]orderLeg, error) {
 
 func (e *CLOBExecutor) resolveMode(ctx context.Context) string {
 	mode := strings.ToLower(strings.TrimSpace(e.Config.Mode))
-	if e != nil && e.Repo != nil {
-		if row, err := e.Repo.GetSystemSettingByKey(ctx, "trading.executor_mode"); err == nil && row != nil && len(row.Value) > 0 {
-			var v string
-			if err := json.Unmarshal(row.Value, &v); err == nil {
-				v = strings.ToLower(strings.TrimSpace(v))
-				if v == "dry-run" || v == "live" {
-					mode = v
-				}
-			}
-		}
+	if v := strings.ToLower(e.settingString(ctx, "trading.executor_mode")); v == "dry-run" || v == "live" {
+		mode = v
 	}
 	if mode == "" {
 		return "dry-run"
@@ -281,11 +527,35 @@ func (e *CLOBExecutor) resolveMode(ctx context.Context) string {
 	return mode
 }
 
+// settingString reads a string setting, decrypting sensitive keys, from the
+// settings cache when configured and the repository otherwise.
+func (e *CLOBExecutor) settingString(ctx context.Context, key string) string {
+	if e == nil {
+		return ""
+	}
+	if e.Settings != nil {
+		return e.Settings.String(ctx, key)
+	}
+	if e.Repo == nil {
+		return ""
+	}
+	row, err := e.Repo.GetSystemSettingByKey(ctx, key)
+	if err != nil || row == nil || len(row.Value) == 0 {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(RevealSettingValue(key, row.Value), &s) == nil {
+		return strings.TrimSpace(s)
+	}
+	return ""
+}
+
 type liveBrokerConfig struct {
 	BaseURL          string
 	SubmitPath       string
 	StatusPath       string
 	CancelPath       string
+	AmendPath        string
 	AuthMode         string
 	APIKey           string
 	APIKeyHeader     string
@@ -317,16 +587,7 @@ func (e *CLOBExecutor) loadLiveBrokerConfig(ctx context.Context) liveBrokerConfi
 		return cfg
 	}
 	read := func(key string) string {
-		row, err := e.Repo.GetSystemSettingByKey(ctx, key)
-		if err != nil || row == nil || len(row.Value) == 0 {
-			return ""
-		}
-		raw := RevealSettingValue(key, row.Value)
-		var s string
-		if json.Unmarshal(raw, &s) == nil {
-			return strings.TrimSpace(s)
-		}
-		return ""
+		return e.settingString(ctx, key)
 	}
 	if v := read("trading.live.base_url"); v != "" {
 		cfg.BaseURL = v
@@ -340,6 +601,9 @@ func (e *CLOBExecutor) loadLiveBrokerConfig(ctx context.Context) liveBrokerConfi
 	if v := read("trading.live.cancel_path"); v != "" {
 		cfg.CancelPath = v
 	}
+	if v := read("trading.live.amend_path"); v != "" {
+		cfg.AmendPath = v
+	}
 	if v := strings.ToLower(read("trading.live.auth_mode")); v != "" {
 		cfg.AuthMode = v
 	}
@@ -402,6 +666,8 @@ func (e *CLOBExecutor) loadLiveBrokerConfig(ctx context.Context) liveBrokerConfi
 func (e *CLOBExecutor) buildLiveClient(ctx context.Context) (*polymarketclob.Client, liveBrokerConfig, error) {
 	cfg := e.loadLiveBrokerConfig(ctx)
 	client := e.Client
+	// An explicit trading.live.base_url pins the host, bypassing endpoint
+	// selection.
 	if strings.TrimSpace(cfg.BaseURL) != "" {
 		client = polymarketclob.NewClient(&http.Client{Timeout: 15 * time.Second}, cfg.BaseURL)
 	}
@@ -457,6 +723,18 @@ func (e *CLOBExecutor) submitLiveOrder(ctx context.Context, plan models.Executio
 			leg.PostOnly = postOnly
 		}
 	}
+	intent, err := e.beginIntent(ctx, models.ExecutorIntentSubmit, order, map[string]any{
+		"token_id":        order.TokenID,
+		"side":            order.Side,
+		"price":           order.Price.String(),
+		"size_usd":        order.SizeUSD.String(),
+		"client_order_id": strconv.FormatUint(order.ID, 10),
+		"signed":          leg.SignedOrder != nil,
+		"post_only":       leg.PostOnly,
+	})
+	if err != nil {
+		return "", nil, err
+	}
 	var resp *polymarketclob.TradingOrder
 	if leg.SignedOrder != nil {
 		submitPath := strings.TrimSpace(cfg.SubmitPath)
@@ -487,10 +765,12 @@ func (e *CLOBExecutor) submitLiveOrder(ctx context.Context, plan models.Executio
 			SizeUSD:       order.SizeUSD.InexactFloat64(),
 			ClientOrderID: strconv.FormatUint(order.ID, 10),
 			PlanID:        plan.ID,
+			PostOnly:      leg.PostOnly,
 		}
 		resp, err = client.PlaceOrder(ctx, cfg.SubmitPath, req, auth)
 	}
 	if err != nil {
+		e.resolveIntent(ctx, intent, "", nil, err)
 		return "", nil, err
 	}
 	now := time.Now().UTC()
@@ -514,6 +794,7 @@ func (e *CLOBExecutor) submitLiveOrder(ctx context.Context, plan models.Executio
 	if strings.TrimSpace(resp.Failure) != "" {
 		updates["failure_reason"] = strings.TrimSpace(resp.Failure)
 	}
+	e.resolveIntent(ctx, intent, status, updates, nil)
 	return status, updates, nil
 }
 
@@ -555,12 +836,16 @@ func (e *CLOBExecutor) fetchLiveOrder(ctx context.Context, clobOrderID string) (
 	return status, updates, nil
 }
 
-func (e *CLOBExecutor) cancelLiveOrder(ctx context.Context, clobOrderID string) (string, map[string]any, error) {
+func (e *CLOBExecutor) cancelLiveOrder(ctx context.Context, order models.Order) (string, map[string]any, error) {
 	client, cfg, err := e.buildLiveClient(ctx)
 	if err != nil {
 		return "", nil, err
 	}
-	resp, err := client.CancelOrder(ctx, cfg.CancelPath, clobOrderID, polymarketclob.TradingAuth{
+	intent, err := e.beginIntent(ctx, models.ExecutorIntentCancel, order, map[string]any{"clob_order_id": order.ClobOrderID})
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := client.CancelOrder(ctx, cfg.CancelPath, order.ClobOrderID, polymarketclob.TradingAuth{
 		APIKeyHeader:     cfg.APIKeyHeader,
 		APIKey:           cfg.APIKey,
 		BearerToken:      cfg.BearerToken,
@@ -574,6 +859,7 @@ func (e *CLOBExecutor) cancelLiveOrder(ctx context.Context, clobOrderID string)
 		AddressHeader:    cfg.AddressHeader,
 	})
 	if err != nil {
+		e.resolveIntent(ctx, intent, "", nil, err)
 		return "", nil, err
 	}
 	status := normalizeLiveStatus(resp.Status)
@@ -582,6 +868,7 @@ func (e *CLOBExecutor) cancelLiveOrder(ctx context.Context, clobOrderID string)
 	}
 	now := time.Now().UTC()
 	updates := map[string]any{"cancelled_at": timeOrPtr(resp.CancelledAt, &now)}
+	e.resolveIntent(ctx, intent, status, updates, nil)
 	return status, updates, nil
 }
 
@@ -622,14 +909,15 @@ func (e *CLOBExecutor) applyOrderFillDelta(ctx context.Context, order models.Ord
 	}
 	deltaSize := deltaUSD.Div(price)
 	fill := &models.Fill{
-		PlanID:     order.PlanID,
-		TokenID:    order.TokenID,
-		Direction:  order.Side,
-		FilledSize: deltaSize,
-		AvgPrice:   price,
-		Fee:        decimal.Zero,
-		FilledAt:   time.Now().UTC(),
-		CreatedAt:  time.Now().UTC(),
+		PlanID:         order.PlanID,
+		TokenID:        order.TokenID,
+		Direction:      order.Side,
+		OrderLineageID: order.Lineage(),
+		FilledSize:     deltaSize,
+		AvgPrice:       price,
+		Fee:            decimal.Zero,
+		FilledAt:       time.Now().UTC(),
+		CreatedAt:      time.Now().UTC(),
 	}
 	if err := e.Repo.InsertFill(ctx, fill); err != nil {
 		return err
@@ -637,6 +925,9 @@ func (e *CLOBExecutor) applyOrderFillDelta(ctx context.Context, order models.Ord
 	if e.PositionSync != nil {
 		_ = e.PositionSync.SyncFromFill(ctx, *fill)
 	}
+	if order.PriceImprovement.IsPositive() {
+		_ = e.Repo.AddOrderImprovementUSD(ctx, order.ID, deltaSize.Mul(order.PriceImprovement))
+	}
 	return nil
 }
 
@@ -731,6 +1022,17 @@ func (e *CLOBExecutor) reconcilePlanStatus(ctx context.Context, planID uint64) e
 	if err != nil || len(orders) == 0 {
 		return err
 	}
+	// Replaced orders are superseded by a later row of the same lineage.
+	live := orders[:0]
+	for _, o := range orders {
+		if !strings.EqualFold(strings.TrimSpace(o.Status), "replaced") {
+			live = append(live, o)
+		}
+	}
+	orders = live
+	if len(orders) == 0 {
+		return nil
+	}
 	total := len(orders)
 	filled := 0
 	partial := 0
@@ -741,10 +1043,10 @@ func (e *CLOBExecutor) reconcilePlanStatus(ctx context.Context, planID uint64) e
 		switch strings.ToLower(strings.TrimSpace(o.Status)) {
 		case "filled":
 			filled++
-		case "partial":
+		case models.OrderStatusPartial:
 			partial++
 			open++
-		case "submitted", "pending":
+		case models.OrderStatusSubmitted, models.OrderStatusPending, models.OrderStatusHeld, models.OrderStatusNeedsReview:
 			open++
 		case "failed":
 			failed++
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	polymarketclob "polymarket/internal/client/polymarket/clob"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// Amend errors. ErrAmendInvalid wraps a request the order cannot take;
+// ErrOrderNotAmendable an order that is no longer working.
+var (
+	ErrAmendInvalid      = errors.New("invalid amend")
+	ErrOrderNotAmendable = errors.New("order is not amendable")
+)
+
+// AmendOrderRequest changes price and/or size of a working order. SizeUSD is
+// the total size of the logical order, including any amount already filled.
+type AmendOrderRequest struct {
+	Price   *decimal.Decimal
+	SizeUSD *decimal.Decimal
+	// PricingMode switches the order between maker and taker pricing; empty
+	// keeps the current mode.
+	PricingMode string
+}
+
+type AmendResult struct {
+	// Order is the working row after the amend: the same row when amended in
+	// place, the replacement row after cancel-and-replace.
+	Order           *models.Order `json:"order"`
+	LineageID       uint64        `json:"lineage_id"`
+	Method          string        `json:"method"`
+	ReplacedOrderID *uint64       `json:"replaced_order_id,omitempty"`
+}
+
+// AmendOrder modifies a working order. In live mode it uses the venue's amend
+// endpoint when trading.live.amend_path is configured, otherwise (or when the
+// venue rejects the amend) it cancels and replaces the order. The replacement
+// keeps the original lineage id so fills stay attached to one logical order.
+func (e *CLOBExecutor) AmendOrder(ctx context.Context, orderID uint64, req AmendOrderRequest) (*AmendResult, error) {
+	if e == nil || e.Repo == nil || orderID == 0 {
+		return nil, nil
+	}
+	order, err := e.Repo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, nil
+	}
+	if !models.IsOpenOrderStatus(order.Status) {
+		return nil, fmt.Errorf("%w: status %s", ErrOrderNotAmendable, order.Status)
+	}
+	if order.MergedIntoID != nil {
+		return nil, fmt.Errorf("%w: merged with other plans' orders into one book order", ErrOrderNotAmendable)
+	}
+	price, sizeUSD, err := resolveAmend(*order, req)
+	if err != nil {
+		return nil, err
+	}
+	if sizeUSD.GreaterThan(order.SizeUSD) {
+		if err := e.checkAmendSize(ctx, *order, sizeUSD); err != nil {
+			return nil, err
+		}
+	}
+	mode := order.PricingMode
+	if req.PricingMode != "" {
+		mode = req.PricingMode
+	}
+	if mode == "" {
+		mode = PricingModeTaker
+	}
+
+	if e.resolveMode(ctx) != "live" || strings.TrimSpace(order.ClobOrderID) == "" {
+		// Not resting on the venue: amend the local row.
+		if err := e.Repo.UpdateOrderStatus(ctx, order.ID, order.Status, map[string]any{
+			"price":        price,
+			"size_usd":     sizeUSD,
+			"pricing_mode": mode,
+		}); err != nil {
+			return nil, err
+		}
+		return e.amendResult(ctx, order.ID, "local", nil)
+	}
+
+	release, err := e.Throttle.Acquire(ctx, e.marketKey(ctx, "", order.TokenID))
+	if err != nil {
+		return nil, fmt.Errorf("market throttle: %w", err)
+	}
+	defer release()
+	cfg := e.loadLiveBrokerConfig(ctx)
+	if strings.TrimSpace(cfg.AmendPath) != "" {
+		status, updates, err := e.amendLiveOrder(ctx, *order, price, sizeUSD)
+		if err == nil {
+			updates["price"] = price
+			updates["size_usd"] = sizeUSD
+			updates["pricing_mode"] = mode
+			if err := e.Repo.UpdateOrderStatus(ctx, order.ID, status, updates); err != nil {
+				return nil, err
+			}
+			if status == "filled" || status == "partial" {
+				_ = e.applyOrderFillDelta(ctx, *order, updates)
+			}
+			_ = e.reconcilePlanStatus(ctx, order.PlanID)
+			return e.amendResult(ctx, order.ID, "amend", nil)
+		}
+		if e.Logger != nil {
+			e.Logger.Warn("amend live order failed, fallback cancel-and-replace", zap.Uint64("order_id", order.ID), zap.Error(err))
+		}
+	}
+	order.PricingMode = mode
+	return e.replaceOrder(ctx, *order, price, sizeUSD)
+}
+
+// replaceOrder cancels a live order and submits a replacement for the unfilled
+// remainder. The replacement is only created once the cancel is confirmed and
+// takes old's pricing mode.
+func (e *CLOBExecutor) replaceOrder(ctx context.Context, old models.Order, price, sizeUSD decimal.Decimal) (*AmendResult, error) {
+	_, updates, err := e.cancelLiveOrder(ctx, old)
+	if err != nil {
+		return nil, fmt.Errorf("cancel for replace: %w", err)
+	}
+	// Fills that landed before the cancel stay on the old row.
+	filledUSD := old.FilledUSD
+	finalStatus, fillUpdates, err := e.fetchLiveOrder(ctx, old.ClobOrderID)
+	if err == nil {
+		if v, ok := fillUpdates["filled_usd"].(decimal.Decimal); ok {
+			updates["filled_usd"] = v
+			filledUSD = v
+		}
+		if v, ok := fillUpdates["filled_at"]; ok {
+			updates["filled_at"] = v
+		}
+	}
+	_ = e.applyOrderFillDelta(ctx, old, updates)
+	if finalStatus == "filled" || filledUSD.GreaterThanOrEqual(sizeUSD) {
+		_ = e.Repo.UpdateOrderStatus(ctx, old.ID, "filled", updates)
+		_ = e.reconcilePlanStatus(ctx, old.PlanID)
+		return nil, fmt.Errorf("%w: order %d filled before it could be replaced", ErrOrderNotAmendable, old.ID)
+	}
+	if err := e.Repo.UpdateOrderStatus(ctx, old.ID, "replaced", updates); err != nil {
+		return nil, err
+	}
+
+	oldID := old.ID
+	now := time.Now().UTC()
+	next := &models.Order{
+		PlanID:           old.PlanID,
+		TokenID:          old.TokenID,
+		LineageID:        old.Lineage(),
+		ReplacesOrderID:  &oldID,
+		Side:             old.Side,
+		OrderType:        old.OrderType,
+		PricingMode:      old.PricingMode,
+		MakerSteps:       old.MakerSteps,
+		PriceImprovement: old.PriceImprovement,
+		Price:            price,
+		SizeUSD:          sizeUSD.Sub(filledUSD),
+		FilledUSD:        decimal.Zero,
+		Status:           "pending",
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := e.Repo.InsertOrder(ctx, next); err != nil {
+		return nil, err
+	}
+	plan, err := e.Repo.GetExecutionPlanByID(ctx, old.PlanID)
+	if err != nil || plan == nil {
+		_ = e.Repo.UpdateOrderStatus(ctx, next.ID, "failed", map[string]any{"failure_reason": "plan not found"})
+		_ = e.reconcilePlanStatus(ctx, old.PlanID)
+		if err == nil {
+			err = fmt.Errorf("plan %d not found", old.PlanID)
+		}
+		return nil, err
+	}
+	status, submitUpdates, err := e.submitLiveOrder(ctx, *plan, *next, replacementLeg(plan.Legs, *next))
+	if err != nil {
+		_ = e.Repo.UpdateOrderStatus(ctx, next.ID, "failed", map[string]any{"failure_reason": err.Error()})
+		_ = e.reconcilePlanStatus(ctx, old.PlanID)
+		return nil, fmt.Errorf("submit replacement: %w", err)
+	}
+	_ = e.Repo.UpdateOrderStatus(ctx, next.ID, status, submitUpdates)
+	if status == "filled" || status == "partial" {
+		_ = e.applyOrderFillDelta(ctx, *next, submitUpdates)
+	}
+	_ = e.reconcilePlanStatus(ctx, old.PlanID)
+	return e.amendResult(ctx, next.ID, "replace", &oldID)
+}
+
+func (e *CLOBExecutor) amendLiveOrder(ctx context.Context, order models.Order, price, sizeUSD decimal.Decimal) (string, map[string]any, error) {
+	client, cfg, err := e.buildLiveClient(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	clobOrderID := order.ClobOrderID
+	intent, err := e.beginIntent(ctx, models.ExecutorIntentAmend, order, map[string]any{
+		"clob_order_id": clobOrderID,
+		"price":         price.String(),
+		"size_usd":      sizeUSD.String(),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	p := price.InexactFloat64()
+	size := sizeUSD.InexactFloat64()
+	resp, err := client.AmendOrder(ctx, cfg.AmendPath, clobOrderID, polymarketclob.AmendOrderRequest{
+		Price:   &p,
+		SizeUSD: &size,
+	}, polymarketclob.TradingAuth{
+		APIKeyHeader:     cfg.APIKeyHeader,
+		APIKey:           cfg.APIKey,
+		BearerToken:      cfg.BearerToken,
+		APISecret:        cfg.APISecret,
+		SignRequests:     cfg.AuthMode == "hmac" || cfg.AuthMode == "polymarket_l2" || cfg.AuthMode == "polymarket_l2_signer" || cfg.AuthMode == "polymarket_l2_local",
+		TimestampHeader:  cfg.TimestampHeader,
+		SignatureHeader:  cfg.SignatureHeader,
+		Passphrase:       cfg.Passphrase,
+		PassphraseHeader: cfg.PassphraseHeader,
+		Address:          cfg.Address,
+		AddressHeader:    cfg.AddressHeader,
+	})
+	if err != nil {
+		e.resolveIntent(ctx, intent, "", nil, err)
+		return "", nil, err
+	}
+	status := normalizeLiveStatus(resp.Status)
+	if status == "" {
+		status = "submitted"
+	}
+	updates := map[string]any{}
+	if resp.OrderID != clobOrderID {
+		updates["clob_order_id"] = resp.OrderID
+	}
+	if resp.FilledUSD > 0 {
+		updates["filled_usd"] = decimal.NewFromFloat(resp.FilledUSD)
+	}
+	if resp.FilledAt != nil {
+		updates["filled_at"] = resp.FilledAt
+	}
+	e.resolveIntent(ctx, intent, status, updates, nil)
+	return status, updates, nil
+}
+
+func (e *CLOBExecutor) amendResult(ctx context.Context, orderID uint64, method string, replaced *uint64) (*AmendResult, error) {
+	order, err := e.Repo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	out := &AmendResult{Order: order, Method: method, ReplacedOrderID: replaced}
+	if order != nil {
+		out.LineageID = order.Lineage()
+	}
+	return out, nil
+}
+
+// resolveAmend merges the request into the order and validates the result.
+func resolveAmend(order models.Order, req AmendOrderRequest) (decimal.Decimal, decimal.Decimal, error) {
+	if req.Price == nil && req.SizeUSD == nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%w: price or size_usd is required", ErrAmendInvalid)
+	}
+	price := order.Price
+	if req.Price != nil {
+		price = *req.Price
+	}
+	sizeUSD := order.SizeUSD
+	if req.SizeUSD != nil {
+		sizeUSD = *req.SizeUSD
+	}
+	if price.LessThanOrEqual(decimal.Zero) || price.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%w: price must be between 0 and 1", ErrAmendInvalid)
+	}
+	if sizeUSD.LessThanOrEqual(order.FilledUSD) {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%w: size_usd must exceed filled_usd %s", ErrAmendInvalid, order.FilledUSD.String())
+	}
+	return price, sizeUSD, nil
+}
+
+// checkAmendSize holds a raised order to the limits SubmitPlan sized it by:
+// the per-order cap and what the plan's other orders leave of its planned
+// size. Orders that ended keep only what they filled.
+func (e *CLOBExecutor) checkAmendSize(ctx context.Context, order models.Order, sizeUSD decimal.Decimal) error {
+	if max := e.Config.MaxOrderSizeUSD; max.IsPositive() && sizeUSD.GreaterThan(max) {
+		return fmt.Errorf("%w: size_usd exceeds max order size %s", ErrAmendInvalid, max.String())
+	}
+	plan, err := e.Repo.GetExecutionPlanByID(ctx, order.PlanID)
+	if err != nil || plan == nil {
+		return err
+	}
+	planID := plan.ID
+	orders, err := e.Repo.ListOrders(ctx, repository.ListOrdersParams{Limit: 1000, PlanID: &planID})
+	if err != nil {
+		return err
+	}
+	remaining := plan.PlannedSizeUSD
+	for _, o := range orders {
+		if o.ID == order.ID {
+			continue
+		}
+		switch o.Status {
+		case "replaced":
+		case "filled", "cancelled", "failed":
+			remaining = remaining.Sub(o.FilledUSD)
+		default:
+			remaining = remaining.Sub(o.SizeUSD)
+		}
+	}
+	if sizeUSD.GreaterThan(remaining) {
+		return fmt.Errorf("%w: size_usd exceeds the plan's remaining size %s", ErrAmendInvalid, remaining.StringFixed(2))
+	}
+	return nil
+}
+
+// replacementLeg picks the plan leg for the order's token. Pre-signed payloads
+// are dropped since they commit to the old price and size, and maker orders
+// are posted post-only.
+func replacementLeg(raw []byte, order models.Order) orderLeg {
+	leg := orderLeg{TokenID: order.TokenID, Direction: order.Side}
+	legs, _ := parseOrderLegs(raw)
+	for _, l := range legs {
+		if strings.TrimSpace(l.TokenID) == order.TokenID {
+			leg = l
+			break
+		}
+	}
+	leg.SignedOrder = nil
+	leg.UnsignedOrder = nil
+	leg.SigningHash = ""
+	if order.PricingMode == PricingModeMaker {
+		leg.PostOnly = boolPtrExecutor(true)
+	}
+	return leg
+}
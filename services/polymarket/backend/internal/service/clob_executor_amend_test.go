@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type amendRepo struct {
+	intentRepo
+	plan *models.ExecutionPlan
+}
+
+func (r *amendRepo) GetExecutionPlanByID(context.Context, uint64) (*models.ExecutionPlan, error) {
+	return r.plan, nil
+}
+
+func (r *amendRepo) ListOrders(context.Context, repository.ListOrdersParams) ([]models.Order, error) {
+	var out []models.Order
+	for _, o := range r.orders {
+		out = append(out, *o)
+	}
+	return out, nil
+}
+
+func TestResolveAmend(t *testing.T) {
+	order := models.Order{
+		Price:     decimal.NewFromFloat(0.40),
+		SizeUSD:   decimal.NewFromInt(100),
+		FilledUSD: decimal.NewFromInt(30),
+	}
+	price := decimal.NewFromFloat(0.42)
+	gotPrice, gotSize, err := resolveAmend(order, AmendOrderRequest{Price: &price})
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if !gotPrice.Equal(price) || !gotSize.Equal(order.SizeUSD) {
+		t.Fatalf("price=%s size=%s", gotPrice, gotSize)
+	}
+
+	if _, _, err := resolveAmend(order, AmendOrderRequest{}); !errors.Is(err, ErrAmendInvalid) {
+		t.Fatalf("expected error for empty request")
+	}
+	size := decimal.NewFromInt(30)
+	if _, _, err := resolveAmend(order, AmendOrderRequest{SizeUSD: &size}); err == nil {
+		t.Fatalf("expected error when size does not exceed filled")
+	}
+	bad := decimal.NewFromInt(1)
+	if _, _, err := resolveAmend(order, AmendOrderRequest{Price: &bad}); err == nil {
+		t.Fatalf("expected error for price >= 1")
+	}
+}
+
+func TestReplacementLeg_DropsSignedPayload(t *testing.T) {
+	raw := []byte(`[{"token_id":"t1","direction":"BUY_YES","order_type":"GTC","signed_order":{"salt":"1"},"signing_hash":"0xabc"}]`)
+	leg := replacementLeg(raw, models.Order{TokenID: "t1", Side: "BUY_YES"})
+	if leg.SignedOrder != nil || leg.SigningHash != "" {
+		t.Fatalf("signed payload kept: %+v", leg)
+	}
+	if leg.OrderType != "GTC" {
+		t.Fatalf("order_type=%q want GTC", leg.OrderType)
+	}
+}
+
+func TestAmendOrder_HoldsRaisedSizeToPlanAndOrderCaps(t *testing.T) {
+	repo := &amendRepo{
+		intentRepo: intentRepo{orders: map[uint64]*models.Order{
+			1: {ID: 1, PlanID: 7, Status: "submitted", Price: decimal.NewFromFloat(0.4), SizeUSD: decimal.NewFromInt(40)},
+			2: {ID: 2, PlanID: 7, Status: "submitted", Price: decimal.NewFromFloat(0.4), SizeUSD: decimal.NewFromInt(40)},
+			3: {ID: 3, PlanID: 7, Status: "cancelled", SizeUSD: decimal.NewFromInt(20), FilledUSD: decimal.NewFromInt(5)},
+			4: {ID: 4, PlanID: 7, Status: "filled", SizeUSD: decimal.NewFromInt(10), FilledUSD: decimal.NewFromInt(10)},
+		}},
+		plan: &models.ExecutionPlan{ID: 7, PlannedSizeUSD: decimal.NewFromInt(100)},
+	}
+	e := &CLOBExecutor{Repo: repo, Config: ExecutorConfig{Mode: "dry-run", MaxOrderSizeUSD: decimal.NewFromInt(50)}}
+	ctx := context.Background()
+	amend := func(id uint64, size int64) error {
+		v := decimal.NewFromInt(size)
+		_, err := e.AmendOrder(ctx, id, AmendOrderRequest{SizeUSD: &v})
+		return err
+	}
+
+	// 100 planned - 40 working - 5 filled before cancel - 10 filled = 45.
+	if err := amend(1, 45); err != nil {
+		t.Fatalf("amend within plan: %v", err)
+	}
+	if err := amend(1, 46); !errors.Is(err, ErrAmendInvalid) {
+		t.Fatalf("amend past plan err = %v", err)
+	}
+	repo.plan.PlannedSizeUSD = decimal.NewFromInt(1000)
+	if err := amend(1, 51); !errors.Is(err, ErrAmendInvalid) {
+		t.Fatalf("amend past max order size err = %v", err)
+	}
+	if err := amend(4, 20); !errors.Is(err, ErrOrderNotAmendable) {
+		t.Fatalf("amend filled order err = %v", err)
+	}
+}
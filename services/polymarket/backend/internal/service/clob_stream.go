@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -20,13 +21,85 @@ type CLOBStreamService struct {
 	Repo       repository.CatalogRepository
 	Logger     *zap.Logger
 	lastPrices map[string]float64
+
+	stream atomic.Pointer[clob.MarketStream]
+	// epochs maps the subscribed tokens to the catalog epoch they were
+	// listed at; books are stamped with it. Empty for fixed AssetIDs.
+	epochs atomic.Pointer[map[string]int64]
+
+	// Subscriptions, when set, adds pinned tokens to the top-market snapshot
+	// and caps the subscribed set.
+	Subscriptions *StreamSubscriptions
+	// Positions, when set with Subscriptions, pins open position tokens.
+	Positions interface {
+		ListOpenPositions(ctx context.Context) ([]models.Position, error)
+	}
 }
 
 type CLOBStreamOptions struct {
-	URL             string
-	AssetIDs        []string
-	RefreshInterval time.Duration
-	MaxAssets       int
+	URL               string
+	AssetIDs          []string
+	RefreshInterval   time.Duration
+	MaxAssets         int
+	HeartbeatInterval time.Duration
+	PingTimeout       time.Duration
+	StallTimeout      time.Duration
+	BackoffMin        time.Duration
+	BackoffMax        time.Duration
+	// Endpoints, when set, picks the websocket endpoint per connect.
+	Endpoints *clob.EndpointPool
+	// FaultHook is passed to the market stream for fault injection.
+	FaultHook func(context.Context) error
+}
+
+// Stats reports connection supervision of the running stream; ok is false
+// before RunMarketStream has started.
+func (s *CLOBStreamService) Stats() (clob.MarketStreamStats, bool) {
+	if s == nil {
+		return clob.MarketStreamStats{}, false
+	}
+	stream := s.stream.Load()
+	if stream == nil {
+		return clob.MarketStreamStats{}, false
+	}
+	return stream.Stats(), true
+}
+
+// Pin subscribes source's tokens now rather than at the next refresh.
+func (s *CLOBStreamService) Pin(source string, tokenIDs []string) int {
+	if s == nil || s.Subscriptions == nil {
+		return 0
+	}
+	n := s.Subscriptions.Pin(source, tokenIDs, time.Now().UTC())
+	s.stream.Load().Resync()
+	return n
+}
+
+// Unpin drops source's pins on tokenIDs (all when empty); tokens nothing
+// else wants are unsubscribed now.
+func (s *CLOBStreamService) Unpin(source string, tokenIDs []string) int {
+	if s == nil || s.Subscriptions == nil {
+		return 0
+	}
+	n := s.Subscriptions.Unpin(source, tokenIDs)
+	s.stream.Load().Resync()
+	return n
+}
+
+// resolveAssetIDs merges snapshot with the pins. A nil snapshot (failed
+// refresh) keeps the previous one.
+func (s *CLOBStreamService) resolveAssetIDs(ctx context.Context, snapshot []string) []string {
+	now := time.Now().UTC()
+	if s.Positions != nil {
+		if positions, err := s.Positions.ListOpenPositions(ctx); err == nil {
+			ids := make([]string, 0, len(positions))
+			for _, p := range positions {
+				ids = append(ids, p.TokenID)
+			}
+			s.Subscriptions.ReplacePins(StreamSourcePositions, ids, now)
+		}
+	}
+	return s.Subscriptions.Resolve(snapshot, now)
 }
 
 func (s *CLOBStreamService) RunMarketStream(ctx context.Context, opts CLOBStreamOptions) error {
@@ -47,19 +120,37 @@ func (s *CLOBStreamService) RunMarketStream(ctx context.Context, opts CLOBStream
 			if err != nil && s.Logger != nil {
 				s.Logger.Warn("fetch stream asset ids failed", zap.Error(err))
 			}
+			if s.Subscriptions != nil {
+				if err != nil {
+					ids = nil
+				}
+				ids, err = s.resolveAssetIDs(ctx, ids), nil
+			}
 			if s.Logger != nil {
 				s.Logger.Info("stream asset ids refreshed", zap.Int("count", len(ids)))
 			}
 			return ids, err
 		}
+	} else if s.Subscriptions != nil {
+		provider = func(ctx context.Context) ([]string, error) {
+			return s.resolveAssetIDs(ctx, opts.AssetIDs), nil
+		}
 	}
 	stream := clob.NewMarketStream(clob.MarketStreamOptions{
-		URL:             opts.URL,
-		AssetIDs:        opts.AssetIDs,
-		AssetIDProvider: provider,
-		RefreshInterval: opts.RefreshInterval,
-		Logger:          s.Logger,
+		URL:               opts.URL,
+		AssetIDs:          opts.AssetIDs,
+		AssetIDProvider:   provider,
+		RefreshInterval:   opts.RefreshInterval,
+		HeartbeatInterval: opts.HeartbeatInterval,
+		PingTimeout:       opts.PingTimeout,
+		StallTimeout:      opts.StallTimeout,
+		BackoffMin:        opts.BackoffMin,
+		BackoffMax:        opts.BackoffMax,
+		Logger:            s.Logger,
+		Endpoints:         opts.Endpoints,
+		FaultHook:         opts.FaultHook,
 	})
+	s.stream.Store(stream)
 	return stream.Run(ctx, func(env clob.MarketEnvelope, raw []byte) {
 		s.handleMarketMessage(ctx, env, raw)
 	})
@@ -74,6 +165,9 @@ func (s *CLOBStreamService) handleMarketMessage(ctx context.Context, env clob.Ma
 	if tokenID == "" {
 		tokenID = extractTokenID(raw)
 	}
+	if s.Subscriptions != nil && tokenID != "" {
+		s.Subscriptions.Touch(tokenID, now)
+	}
 
 	_ = s.Repo.InsertRawWSEvent(ctx, &models.RawWSEvent{
 		TokenID:    strPtr(tokenID),
@@ -118,6 +212,7 @@ func (s *CLOBStreamService) fetchStreamAssetIDs(ctx context.Context, maxAssets i
 	}
 	seen := map[string]struct{}{}
 	out := make([]string, 0, len(tokens))
+	epochs := make(map[string]int64, len(tokens))
 	for _, token := range tokens {
 		if token.ID == "" {
 			continue
@@ -127,10 +222,12 @@ func (s *CLOBStreamService) fetchStreamAssetIDs(ctx context.Context, maxAssets i
 		}
 		seen[token.ID] = struct{}{}
 		out = append(out, token.ID)
+		epochs[token.ID] = token.CatalogEpoch
 		if len(out) >= maxAssets {
 			break
 		}
 	}
+	s.epochs.Store(&epochs)
 	return out, nil
 }
 
@@ -160,6 +257,7 @@ func (s *CLOBStreamService) handleBook(ctx context.Context, tokenID string, env
 		Source:         strPtr("ws"),
 		DataAgeSeconds: 0,
 		UpdatedAt:      time.Now().UTC(),
+		CatalogEpoch:   s.tokenEpoch(tokenID),
 	}
 	if err := s.Repo.UpsertOrderbookLatest(ctx, item); err != nil {
 		return err
@@ -167,6 +265,13 @@ func (s *CLOBStreamService) handleBook(ctx context.Context, tokenID string, env
 	return s.updateHealthWithBook(ctx, tokenID, time.Now().UTC(), "book", &snapshotTS, bestBid, bestAsk, mid)
 }
 
+func (s *CLOBStreamService) tokenEpoch(tokenID string) int64 {
+	if epochs := s.epochs.Load(); epochs != nil {
+		return (*epochs)[tokenID]
+	}
+	return 0
+}
+
 func (s *CLOBStreamService) updateHealth(ctx context.Context, tokenID string, now time.Time, reason string, lastWSTS *time.Time) error {
 	if tokenID == "" {
 		return nil
@@ -227,6 +332,32 @@ func (s *CLOBStreamService) handleLastTradePrice(ctx context.Context, tokenID st
 	if err := s.Repo.UpsertLastTradePrice(ctx, item); err != nil {
 		return err
 	}
+	trade := parseTradePrint(raw)
+	trade.TokenID = tokenID
+	trade.Price = price
+	trade.TradeTS = tradeTS.UTC()
+	if trade.TradeTS.IsZero() {
+		trade.TradeTS = item.UpdatedAt
+	}
+	if err := s.Repo.InsertTrade(ctx, &trade); err != nil && s.Logger != nil {
+		s.Logger.Warn("insert trade failed", zap.String("token_id", tokenID), zap.Error(err))
+	}
+	candleTS := tradeTS
+	if candleTS.IsZero() {
+		candleTS = item.UpdatedAt
+	}
+	_ = s.Repo.UpsertPriceCandles(ctx, []models.PriceCandle{{
+		TokenID:     tokenID,
+		BucketStart: candleTS.UTC().Truncate(time.Minute),
+		Open:        price,
+		High:        price,
+		Low:         price,
+		Close:       price,
+		OpenTS:      candleTS.UTC(),
+		CloseTS:     candleTS.UTC(),
+		Points:      1,
+		Source:      "ws",
+	}})
 	prev, _ := s.lastTradePrice(tokenID)
 	jumpBps := computePriceJumpBps(prev, price)
 	s.setLastTradePrice(tokenID, price)
@@ -439,6 +570,36 @@ func parseLastTradePrice(raw []byte) float64 {
 	return 0
 }
 
+// parseTradePrint reads size, side, fee and market from a last_trade_price
+// event, looking in "data" when the fields are not at the top level.
+func parseTradePrint(raw []byte) models.Trade {
+	out := models.Trade{Source: "ws"}
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return out
+	}
+	if data := root["data"]; len(data) > 0 && len(firstRaw(root, "size", "side")) == 0 {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err == nil {
+			root = obj
+		}
+	}
+	out.Size = parseFloat(firstRaw(root, "size", "amount"))
+	var side string
+	if err := json.Unmarshal(firstRaw(root, "side"), &side); err == nil {
+		out.Side = strings.ToUpper(strings.TrimSpace(side))
+	}
+	if fee := firstRaw(root, "fee_rate_bps", "feeRateBps"); len(fee) > 0 {
+		v := parseFloat(fee)
+		out.FeeRateBps = &v
+	}
+	var market string
+	if err := json.Unmarshal(firstRaw(root, "market", "condition_id"), &market); err == nil && strings.TrimSpace(market) != "" {
+		out.Market = strPtr(strings.TrimSpace(market))
+	}
+	return out
+}
+
 func extractTokenID(raw []byte) string {
 	var obj map[string]json.RawMessage
 	if err := json.Unmarshal(raw, &obj); err != nil {
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StreamSourcePositions pins the tokens of open positions.
+const StreamSourcePositions = "positions"
+
+// StreamSubscriptions decides which tokens the CLOB stream subscribes to: the
+// top-market snapshot plus tokens pinned by a source such as a strategy, a
+// watchlist or open positions. Pinned tokens go first. When the set exceeds
+// Cap the least recently active tokens are evicted; a pin also lapses once
+// its token has been idle (no stream message, no re-pin) for IdleTTL.
+type StreamSubscriptions struct {
+	// Cap bounds the subscribed set; 0 is unbounded.
+	Cap int
+	// IdleTTL expires idle pins; 0 keeps pins until removed.
+	IdleTTL time.Duration
+
+	mu         sync.Mutex
+	entries    map[string]*streamSub
+	snapshot   []string
+	subscribed map[string]struct{}
+	evictions  int64
+	expired    int64
+}
+
+type streamSub struct {
+	// pins maps the pinning source to when it last pinned the token.
+	pins       map[string]time.Time
+	lastActive time.Time
+	inSnapshot bool
+}
+
+// StreamSubscription is one wanted token.
+type StreamSubscription struct {
+	TokenID    string    `json:"token_id"`
+	Sources    []string  `json:"sources"`
+	Snapshot   bool      `json:"snapshot"`
+	Subscribed bool      `json:"subscribed"`
+	LastActive time.Time `json:"last_active"`
+}
+
+// StreamSubscriptionState summarizes the subscription set for stream health.
+type StreamSubscriptionState struct {
+	Cap         int            `json:"cap"`
+	Wanted      int            `json:"wanted"`
+	Subscribed  int            `json:"subscribed"`
+	Pinned      int            `json:"pinned"`
+	BySource    map[string]int `json:"by_source"`
+	Evictions   int64          `json:"evictions"`
+	ExpiredPins int64          `json:"expired_pins"`
+}
+
+func (s *StreamSubscriptions) entry(tokenID string, now time.Time) *streamSub {
+	if s.entries == nil {
+		s.entries = map[string]*streamSub{}
+	}
+	e := s.entries[tokenID]
+	if e == nil {
+		e = &streamSub{pins: map[string]time.Time{}, lastActive: now}
+		s.entries[tokenID] = e
+	}
+	return e
+}
+
+// Pin adds source's pin on tokenIDs and reports how many tokens it names.
+func (s *StreamSubscriptions) Pin(source string, tokenIDs []string, now time.Time) int {
+	source = strings.TrimSpace(source)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, id := range tokenIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || source == "" {
+			continue
+		}
+		e := s.entry(id, now)
+		e.pins[source] = now
+		e.lastActive = now
+		n++
+	}
+	return n
+}
+
+// Unpin removes source's pins on tokenIDs, or all of its pins when tokenIDs
+// is empty, and reports how many were removed.
+func (s *StreamSubscriptions) Unpin(source string, tokenIDs []string) int {
+	source = strings.TrimSpace(source)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	only := setFromIDs(tokenIDs)
+	n := 0
+	for id, e := range s.entries {
+		if _, ok := e.pins[source]; !ok {
+			continue
+		}
+		if len(only) > 0 {
+			if _, ok := only[id]; !ok {
+				continue
+			}
+		}
+		delete(e.pins, source)
+		n++
+	}
+	return n
+}
+
+// ReplacePins makes tokenIDs the only tokens source pins, renewing each pin.
+func (s *StreamSubscriptions) ReplacePins(source string, tokenIDs []string, now time.Time) {
+	source = strings.TrimSpace(source)
+	keep := setFromIDs(tokenIDs)
+	s.mu.Lock()
+	for id, e := range s.entries {
+		if _, ok := keep[id]; !ok {
+			delete(e.pins, source)
+		}
+	}
+	for id := range keep {
+		s.entry(id, now).pins[source] = now
+	}
+	s.mu.Unlock()
+}
+
+// Touch records stream activity on tokenID.
+func (s *StreamSubscriptions) Touch(tokenID string, now time.Time) {
+	s.mu.Lock()
+	if e := s.entries[tokenID]; e != nil {
+		e.lastActive = now
+	}
+	s.mu.Unlock()
+}
+
+// Resolve merges the top-market snapshot (nil keeps the previous one) with
+// the pins and returns the tokens to subscribe, pinned first and each group
+// most recently active first, truncated to Cap.
+func (s *StreamSubscriptions) Resolve(snapshot []string, now time.Time) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if snapshot != nil {
+		s.snapshot = append([]string(nil), snapshot...)
+	}
+	for _, e := range s.entries {
+		e.inSnapshot = false
+	}
+	for _, id := range s.snapshot {
+		if id = strings.TrimSpace(id); id != "" {
+			s.entry(id, now).inSnapshot = true
+		}
+	}
+	for id, e := range s.entries {
+		if s.IdleTTL > 0 {
+			for source, at := range e.pins {
+				if now.Sub(laterOf(at, e.lastActive)) >= s.IdleTTL {
+					delete(e.pins, source)
+					s.expired++
+				}
+			}
+		}
+		if len(e.pins) == 0 && !e.inSnapshot {
+			delete(s.entries, id)
+		}
+	}
+	ids := make([]string, 0, len(s.entries))
+	for id := range s.entries {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := s.entries[ids[i]], s.entries[ids[j]]
+		if pa, pb := len(a.pins) > 0, len(b.pins) > 0; pa != pb {
+			return pa
+		}
+		if !a.lastActive.Equal(b.lastActive) {
+			return a.lastActive.After(b.lastActive)
+		}
+		return ids[i] < ids[j]
+	})
+	if s.Cap > 0 && len(ids) > s.Cap {
+		for _, id := range ids[s.Cap:] {
+			if _, was := s.subscribed[id]; was {
+				s.evictions++
+			}
+		}
+		ids = ids[:s.Cap]
+	}
+	s.subscribed = setFromIDs(ids)
+	return ids
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// State summarizes the set as of the last Resolve.
+func (s *StreamSubscriptions) State() StreamSubscriptionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := StreamSubscriptionState{
+		Cap:         s.Cap,
+		Wanted:      len(s.entries),
+		Subscribed:  len(s.subscribed),
+		BySource:    map[string]int{},
+		Evictions:   s.evictions,
+		ExpiredPins: s.expired,
+	}
+	for _, e := range s.entries {
+		if len(e.pins) > 0 {
+			out.Pinned++
+		}
+		for source := range e.pins {
+			out.BySource[source]++
+		}
+	}
+	return out
+}
+
+// List returns the wanted tokens, optionally only those source pins.
+func (s *StreamSubscriptions) List(source string) []StreamSubscription {
+	source = strings.TrimSpace(source)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]StreamSubscription, 0, len(s.entries))
+	for id, e := range s.entries {
+		if _, ok := e.pins[source]; source != "" && !ok {
+			continue
+		}
+		sources := make([]string, 0, len(e.pins))
+		for src := range e.pins {
+			sources = append(sources, src)
+		}
+		sort.Strings(sources)
+		_, subscribed := s.subscribed[id]
+		out = append(out, StreamSubscription{
+			TokenID:    id,
+			Sources:    sources,
+			Snapshot:   e.inSnapshot,
+			Subscribed: subscribed,
+			LastActive: e.lastActive,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TokenID < out[j].TokenID })
+	return out
+}
+
+func setFromIDs(ids []string) map[string]struct{} {
+	out := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if id = strings.TrimSpace(id); id != "" {
+			out[id] = struct{}{}
+		}
+	}
+	return out
+}
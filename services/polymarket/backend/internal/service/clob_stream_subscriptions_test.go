@@ -0,0 +1,50 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStreamSubscriptions_PinsFirstAndLRUCap(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	subs := &StreamSubscriptions{Cap: 3}
+	if got := subs.Resolve([]string{"a", "b", "c"}, t0); len(got) != 3 {
+		t.Fatalf("snapshot=%v", got)
+	}
+	subs.Touch("a", t0.Add(2*time.Minute))
+	subs.Touch("b", t0.Add(time.Minute))
+	subs.Pin("strategy:arb_sum", []string{"p"}, t0.Add(3*time.Minute))
+
+	// The pin goes first and the least recently active snapshot token, c, is evicted.
+	got := subs.Resolve(nil, t0.Add(3*time.Minute))
+	if want := []string{"p", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	st := subs.State()
+	if st.Evictions != 1 || st.Pinned != 1 || st.BySource["strategy:arb_sum"] != 1 || st.Wanted != 4 {
+		t.Fatalf("state=%+v", st)
+	}
+
+	subs.Unpin("strategy:arb_sum", nil)
+	if got := subs.Resolve([]string{"a", "b"}, t0.Add(4*time.Minute)); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("after unpin got %v", got)
+	}
+}
+
+func TestStreamSubscriptions_IdlePinsExpire(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	subs := &StreamSubscriptions{IdleTTL: time.Hour}
+	subs.Pin("watchlist:btc", []string{"quiet", "busy"}, t0)
+	subs.ReplacePins(StreamSourcePositions, []string{"pos"}, t0)
+	subs.Touch("busy", t0.Add(50*time.Minute))
+	subs.ReplacePins(StreamSourcePositions, []string{"pos"}, t0.Add(61*time.Minute))
+
+	got := subs.Resolve(nil, t0.Add(61*time.Minute))
+	if want := []string{"busy", "pos"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	if st := subs.State(); st.ExpiredPins != 1 {
+		t.Fatalf("state=%+v", st)
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+import "testing"
+
+func TestParseTradePrint(t *testing.T) {
+	raw := []byte(`{"asset_id":"t1","event_type":"last_trade_price","fee_rate_bps":"0","market":"0xabc","price":"0.456","side":"buy","size":"219.5","timestamp":"1750428146322"}`)
+	got := parseTradePrint(raw)
+	if got.Size != 219.5 || got.Side != "BUY" || got.Market == nil || *got.Market != "0xabc" || got.FeeRateBps == nil || *got.FeeRateBps != 0 || got.Source != "ws" {
+		t.Fatalf("unexpected print: %+v", got)
+	}
+
+	nested := parseTradePrint([]byte(`{"event_type":"last_trade_price","data":{"price":0.5,"size":10,"side":"SELL"}}`))
+	if nested.Size != 10 || nested.Side != "SELL" || nested.Market != nil {
+		t.Fatalf("unexpected nested print: %+v", nested)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"polymarket/internal/expr"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// ConditionVar documents one variable of a condition scope.
+type ConditionVar struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	kind        expr.Kind
+}
+
+// opportunityConditionVars are the variables of execution rule conditions.
+// Edge and confidence are the decayed values the auto executor compares.
+var opportunityConditionVars = []ConditionVar{
+	{Name: "confidence", kind: expr.Number, Description: "current (decayed) confidence, 0-1"},
+	{Name: "edge_pct", kind: expr.Number, Description: "current (decayed) edge as stored, e.g. 0.05 for 5%"},
+	{Name: "edge_usd", kind: expr.Number, Description: "expected edge in USD"},
+	{Name: "max_size", kind: expr.Number, Description: "maximum size in USD suggested by the strategy"},
+	{Name: "risk_score", kind: expr.Number, Description: "strategy risk score"},
+	{Name: "data_age_ms", kind: expr.Number, Description: "age of the market data behind the opportunity"},
+	{Name: "legs", kind: expr.Number, Description: "number of legs"},
+	{Name: "strategy", kind: expr.String, Description: "strategy name"},
+	{Name: "signal_type", kind: expr.String, Description: "signal type that triggered the evaluation"},
+	{Name: "label", kind: expr.List, Description: "market labels of the opportunity's markets"},
+}
+
+// OpportunityConditionVars lists the variables of opportunity conditions.
+func OpportunityConditionVars() []ConditionVar {
+	out := append([]ConditionVar(nil), opportunityConditionVars...)
+	for i := range out {
+		out[i].Type = out[i].kind.String()
+	}
+	return out
+}
+
+// CompileOpportunityCondition compiles an opportunity condition.
+func CompileOpportunityCondition(src string) (*expr.Program, error) {
+	vars := make(map[string]expr.Kind, len(opportunityConditionVars))
+	for _, v := range opportunityConditionVars {
+		vars[v.Name] = v.kind
+	}
+	return expr.Compile(src, vars)
+}
+
+// OpportunityConditionEnv builds the variables of opp for prog. Market
+// labels are only loaded when prog reads them; a nil prog loads everything.
+func OpportunityConditionEnv(ctx context.Context, repo repository.Repository, opp models.Opportunity, prog *expr.Program) (map[string]any, error) {
+	legs := 0
+	var rawLegs []json.RawMessage
+	if json.Unmarshal(opp.Legs, &rawLegs) == nil {
+		legs = len(rawLegs)
+	}
+	env := map[string]any{
+		"confidence":  opp.CurrentConfidence(),
+		"edge_pct":    opp.CurrentEdgePct().InexactFloat64(),
+		"edge_usd":    opp.EdgeUSD.InexactFloat64(),
+		"max_size":    opp.MaxSize.InexactFloat64(),
+		"risk_score":  opp.RiskScore,
+		"data_age_ms": opp.DataAgeMs,
+		"legs":        legs,
+		"strategy":    opp.Strategy.Name,
+		"signal_type": opp.SignalType,
+		"label":       []string{},
+	}
+	if prog != nil && !prog.Uses("label") {
+		return env, nil
+	}
+	var marketIDs []string
+	_ = json.Unmarshal(opp.MarketIDs, &marketIDs)
+	if opp.PrimaryMarketID != nil && *opp.PrimaryMarketID != "" {
+		marketIDs = append(marketIDs, *opp.PrimaryMarketID)
+	}
+	if len(marketIDs) == 0 || repo == nil {
+		return env, nil
+	}
+	rows, err := repo.ListMarketLabelsByMarketIDs(ctx, marketIDs)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	labels := []string{}
+	for _, row := range rows {
+		if !seen[row.Label] {
+			seen[row.Label] = true
+			labels = append(labels, row.Label)
+		}
+	}
+	sort.Strings(labels)
+	env["label"] = labels
+	return env, nil
+}
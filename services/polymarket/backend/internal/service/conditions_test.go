@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+)
+
+func TestOpportunityCondition_UsesDecayedValues(t *testing.T) {
+	decayed := 0.6
+	opp := models.Opportunity{
+		Strategy:          models.Strategy{Name: "arb_sum"},
+		EdgePct:           decimal.NewFromFloat(0.08),
+		Confidence:        0.9,
+		DecayedConfidence: &decayed,
+		Legs:              datatypes.JSON(`[{"token_id":"a"},{"token_id":"b"}]`),
+	}
+	prog, err := CompileOpportunityCondition(`confidence > 0.7 || (edge_pct > 0.05 && legs == 2 && strategy == "arb_sum")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := OpportunityConditionEnv(context.Background(), nil, opp, prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := prog.Eval(env); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v env=%v", ok, err, env)
+	}
+	if env["confidence"] != 0.6 {
+		t.Fatalf("confidence=%v want decayed 0.6", env["confidence"])
+	}
+	if _, err := CompileOpportunityCondition(`category == "sports"`); err == nil {
+		t.Fatalf("unknown variable must not compile")
+	}
+}
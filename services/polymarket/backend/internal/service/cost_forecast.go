@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// CostForecaster forecasts the all-in execution cost of an opportunity at a
+// planned size and scores past forecasts against realized fills.
+type CostForecaster struct {
+	Repo   repository.Repository
+	Config config.ExecutionCostConfig
+}
+
+// ExecutionCostForecast is gross edge less the expected slippage, fees and
+// adverse-selection buffer at SizeUSD.
+type ExecutionCostForecast struct {
+	SizeUSD             decimal.Decimal   `json:"size_usd"`
+	GrossEdgeUSD        decimal.Decimal   `json:"gross_edge_usd"`
+	SlippageUSD         decimal.Decimal   `json:"slippage_usd"`
+	FeesUSD             decimal.Decimal   `json:"fees_usd"`
+	AdverseSelectionUSD decimal.Decimal   `json:"adverse_selection_usd"`
+	TotalCostUSD        decimal.Decimal   `json:"total_cost_usd"`
+	NetEdgeUSD          decimal.Decimal   `json:"net_edge_usd"`
+	NetEdgePct          decimal.Decimal   `json:"net_edge_pct"`
+	Legs                []LegCostForecast `json:"legs"`
+}
+
+// LegCostForecast is the book walk of one leg. UnknownDepth is set when the
+// book had no levels, or too few for the leg, and the flat fallback applied.
+type LegCostForecast struct {
+	TokenID        string  `json:"token_id"`
+	SizeUSD        float64 `json:"size_usd"`
+	ReferencePrice float64 `json:"reference_price"`
+	AvgPrice       float64 `json:"avg_price"`
+	SlippageUSD    float64 `json:"slippage_usd"`
+	UnknownDepth   bool    `json:"unknown_depth"`
+}
+
+// Forecast prices opp at sizeUSD from the latest books.
+func (f *CostForecaster) Forecast(ctx context.Context, opp models.Opportunity, sizeUSD decimal.Decimal) (*ExecutionCostForecast, error) {
+	books, err := f.legBooks(ctx, opp)
+	if err != nil {
+		return nil, err
+	}
+	return f.forecast(opp, sizeUSD, books), nil
+}
+
+// legBooks loads the latest books of opp's legs by token.
+func (f *CostForecaster) legBooks(ctx context.Context, opp models.Opportunity) (map[string]models.OrderbookLatest, error) {
+	var legs []orderLeg
+	if len(opp.Legs) > 0 {
+		_ = json.Unmarshal(opp.Legs, &legs)
+	}
+	tokenIDs := make([]string, 0, len(legs))
+	for _, leg := range legs {
+		if id := strings.TrimSpace(leg.TokenID); id != "" {
+			tokenIDs = append(tokenIDs, id)
+		}
+	}
+	books := map[string]models.OrderbookLatest{}
+	if len(tokenIDs) > 0 {
+		rows, err := f.Repo.ListOrderbookLatestByTokenIDs(ctx, tokenIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range rows {
+			books[b.TokenID] = b
+		}
+	}
+	return books, nil
+}
+
+// forecast prices opp at sizeUSD against books.
+func (f *CostForecaster) forecast(opp models.Opportunity, sizeUSD decimal.Decimal, books map[string]models.OrderbookLatest) *ExecutionCostForecast {
+	var legs []orderLeg
+	if raw := addPlanLegSizing(opp.Legs, sizeUSD); len(raw) > 0 {
+		_ = json.Unmarshal(raw, &legs)
+	}
+	out := &ExecutionCostForecast{
+		SizeUSD:      sizeUSD,
+		GrossEdgeUSD: opp.CurrentEdgePct().Mul(sizeUSD),
+		Legs:         make([]LegCostForecast, 0, len(legs)),
+	}
+	slippage := 0.0
+	for _, leg := range legs {
+		lf := f.forecastLeg(leg, books[strings.TrimSpace(leg.TokenID)])
+		slippage += lf.SlippageUSD
+		out.Legs = append(out.Legs, lf)
+	}
+	bps := decimal.NewFromInt(10000)
+	out.SlippageUSD = decimal.NewFromFloat(slippage)
+	out.FeesUSD = sizeUSD.Mul(decimal.NewFromFloat(f.Config.FeeBps)).Div(bps)
+	out.AdverseSelectionUSD = sizeUSD.Mul(decimal.NewFromFloat(f.Config.AdverseSelectionBps)).Div(bps)
+	out.TotalCostUSD = out.SlippageUSD.Add(out.FeesUSD).Add(out.AdverseSelectionUSD)
+	out.NetEdgeUSD = out.GrossEdgeUSD.Sub(out.TotalCostUSD)
+	if sizeUSD.IsPositive() {
+		out.NetEdgePct = out.NetEdgeUSD.Div(sizeUSD)
+	}
+	return out
+}
+
+func (f *CostForecaster) forecastLeg(leg orderLeg, book models.OrderbookLatest) LegCostForecast {
+	out := LegCostForecast{TokenID: strings.TrimSpace(leg.TokenID)}
+	if leg.SizeUSD != nil {
+		out.SizeUSD = *leg.SizeUSD
+	}
+	mb := makerBookFromLatest(book)
+	levels, touch := mb.Asks, mb.BestAsk
+	if isSellSide(leg.Direction) {
+		levels, touch = mb.Bids, mb.BestBid
+	}
+	ref := touch
+	if leg.TargetPrice != nil && *leg.TargetPrice > 0 {
+		ref = *leg.TargetPrice
+	}
+	walk := walkBook(leg.Direction, levels, out.SizeUSD)
+	out.ReferencePrice = ref
+	out.AvgPrice = walk.AvgPrice
+	out.UnknownDepth = walk.Remaining > 0
+	if walk.Filled > 0 && ref > 0 {
+		out.SlippageUSD = legSlippage(leg.Direction, walk.Filled, walk.AvgPrice, ref)
+	}
+	if walk.Remaining > 0 {
+		out.SlippageUSD += walk.Remaining * f.Config.UnknownDepthSlippagePct
+	}
+	return out
+}
+
+// bookWalk is the result of taking sizeUSD of notional from a book. Filled
+// and Remaining are notional; Remaining is what the levels could not absorb.
+type bookWalk struct {
+	Filled    float64
+	Remaining float64
+	AvgPrice  float64
+}
+
+// walkBook consumes levels best price first: ascending asks for buys,
+// descending bids for sells.
+func walkBook(side string, levels []priceLevel, sizeUSD float64) bookWalk {
+	sorted := make([]priceLevel, 0, len(levels))
+	for _, l := range levels {
+		if l.Price > 0 && l.Size > 0 {
+			sorted = append(sorted, l)
+		}
+	}
+	sell := isSellSide(side)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sell {
+			return sorted[i].Price > sorted[j].Price
+		}
+		return sorted[i].Price < sorted[j].Price
+	})
+	remaining := math.Max(sizeUSD, 0)
+	shares, filled := 0.0, 0.0
+	for _, l := range sorted {
+		if remaining <= 0 {
+			break
+		}
+		take := math.Min(remaining, l.Price*l.Size)
+		shares += take / l.Price
+		filled += take
+		remaining -= take
+	}
+	out := bookWalk{Filled: filled, Remaining: remaining}
+	if shares > 0 {
+		out.AvgPrice = filled / shares
+	}
+	return out
+}
+
+// legSlippage is the notional lost against ref: paying above it when buying,
+// receiving below it when selling. Price improvement is not credited.
+func legSlippage(side string, notional, avg, ref float64) float64 {
+	if avg <= 0 || ref <= 0 {
+		return 0
+	}
+	var loss float64
+	if isSellSide(side) {
+		loss = notional * (ref - avg) / avg
+	} else {
+		loss = notional * (1 - ref/avg)
+	}
+	return math.Max(loss, 0)
+}
+
+// Record persists fc as the forecast of plan.
+func (f *CostForecaster) Record(ctx context.Context, plan *models.ExecutionPlan, fc *ExecutionCostForecast) error {
+	if f == nil || plan == nil || fc == nil {
+		return nil
+	}
+	unknown := 0
+	for _, l := range fc.Legs {
+		if l.UnknownDepth {
+			unknown++
+		}
+	}
+	return f.Repo.InsertCostForecast(ctx, &models.CostForecast{
+		PlanID:              plan.ID,
+		OpportunityID:       plan.OpportunityID,
+		StrategyName:        plan.StrategyName,
+		SizeUSD:             fc.SizeUSD,
+		GrossEdgeUSD:        fc.GrossEdgeUSD,
+		SlippageUSD:         fc.SlippageUSD,
+		FeesUSD:             fc.FeesUSD,
+		AdverseSelectionUSD: fc.AdverseSelectionUSD,
+		NetEdgeUSD:          fc.NetEdgeUSD,
+		UnknownDepthLegs:    unknown,
+	})
+}
+
+// CostForecastAccuracy scores forecast slippage and fees against what plans
+// realized. Forecasts are scaled to the filled share of the planned size, and
+// errors are realized minus forecast, so a positive bias means the model
+// underestimates cost. The adverse-selection buffer is reported but not
+// scored: it has no realized counterpart.
+type CostForecastAccuracy struct {
+	Strategy               string  `json:"strategy"`
+	Plans                  int     `json:"plans"`
+	ForecastSlippageUSD    float64 `json:"forecast_slippage_usd"`
+	RealizedSlippageUSD    float64 `json:"realized_slippage_usd"`
+	ForecastFeesUSD        float64 `json:"forecast_fees_usd"`
+	RealizedFeesUSD        float64 `json:"realized_fees_usd"`
+	AdverseSelectionUSD    float64 `json:"adverse_selection_usd"`
+	BiasUSD                float64 `json:"bias_usd"`
+	MeanAbsErrorUSD        float64 `json:"mean_abs_error_usd"`
+	RealizedToForecastCost float64 `json:"realized_to_forecast_cost"`
+	// UnknownDepthPlans counts forecasts that used the flat slippage fallback.
+	UnknownDepthPlans int `json:"unknown_depth_plans"`
+}
+
+// Accuracy scores forecasts of plans with fills, overall (Strategy "all")
+// first and then per strategy.
+func (f *CostForecaster) Accuracy(ctx context.Context, params repository.CostForecastOutcomeParams) ([]CostForecastAccuracy, error) {
+	rows, err := f.Repo.ListCostForecastOutcomes(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return scoreCostForecasts(rows), nil
+}
+
+func scoreCostForecasts(rows []repository.CostForecastOutcomeRow) []CostForecastAccuracy {
+	accs := []CostForecastAccuracy{{Strategy: "all"}}
+	index := map[string]int{}
+	for _, r := range rows {
+		i, ok := index[r.StrategyName]
+		if !ok {
+			i = len(accs)
+			index[r.StrategyName] = i
+			accs = append(accs, CostForecastAccuracy{Strategy: r.StrategyName})
+		}
+		scale := 1.0
+		if r.SizeUSD.IsPositive() {
+			scale = math.Min(1, r.FilledUSD.Div(r.SizeUSD).InexactFloat64())
+		}
+		slippage := r.SlippageUSD.InexactFloat64() * scale
+		fees := r.FeesUSD.InexactFloat64() * scale
+		realizedSlippage := r.RealizedSlippageUSD.InexactFloat64()
+		realizedFees := r.RealizedFeesUSD.InexactFloat64()
+		diff := realizedSlippage + realizedFees - slippage - fees
+		for _, a := range []*CostForecastAccuracy{&accs[0], &accs[i]} {
+			a.Plans++
+			a.ForecastSlippageUSD += slippage
+			a.RealizedSlippageUSD += realizedSlippage
+			a.ForecastFeesUSD += fees
+			a.RealizedFeesUSD += realizedFees
+			a.AdverseSelectionUSD += r.AdverseSelectionUSD.InexactFloat64() * scale
+			a.BiasUSD += diff
+			a.MeanAbsErrorUSD += math.Abs(diff)
+			if r.UnknownDepthLegs > 0 {
+				a.UnknownDepthPlans++
+			}
+		}
+	}
+	for i := range accs {
+		a := &accs[i]
+		if a.Plans > 0 {
+			a.BiasUSD /= float64(a.Plans)
+			a.MeanAbsErrorUSD /= float64(a.Plans)
+		}
+		if forecast := a.ForecastSlippageUSD + a.ForecastFeesUSD; forecast > 0 {
+			a.RealizedToForecastCost = (a.RealizedSlippageUSD + a.RealizedFeesUSD) / forecast
+		}
+	}
+	sort.SliceStable(accs[1:], func(i, j int) bool { return accs[1+i].Strategy < accs[1+j].Strategy })
+	return accs
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestWalkBook(t *testing.T) {
+	// Unsorted asks: 50 shares at 0.40 then 100 at 0.50.
+	asks := []priceLevel{{Price: 0.50, Size: 100}, {Price: 0.40, Size: 50}}
+	w := walkBook("BUY_YES", asks, 45)
+	// 20 USD at 0.40 buys 50 shares, 25 USD at 0.50 buys 50 more.
+	if w.Filled != 45 || w.Remaining != 0 || math.Abs(w.AvgPrice-0.45) > 1e-9 {
+		t.Fatalf("walk=%+v", w)
+	}
+	if w := walkBook("BUY_YES", asks, 100); math.Abs(w.Remaining-30) > 1e-9 {
+		t.Fatalf("exhausted walk=%+v", w)
+	}
+	bids := []priceLevel{{Price: 0.30, Size: 100}, {Price: 0.35, Size: 100}}
+	if w := walkBook("SELL_YES", bids, 35); w.Filled != 35 || math.Abs(w.AvgPrice-0.35) > 1e-9 {
+		t.Fatalf("sell walk=%+v", w)
+	}
+}
+
+func TestForecastLeg_SlippageAndFallback(t *testing.T) {
+	f := &CostForecaster{Config: config.ExecutionCostConfig{UnknownDepthSlippagePct: 0.01}}
+	ask := 0.40
+	size := 45.0
+	book := models.OrderbookLatest{BestAsk: &ask, AsksJSON: []byte(`[{"price":"0.40","size":"50"},{"price":"0.50","size":"100"}]`)}
+	lf := f.forecastLeg(orderLeg{TokenID: "t1", Direction: "BUY_YES", SizeUSD: &size}, book)
+	// Paying 0.45 on average against a 0.40 touch loses 45*(1-0.40/0.45) = 5.
+	if lf.UnknownDepth || math.Abs(lf.SlippageUSD-5) > 1e-9 {
+		t.Fatalf("leg=%+v", lf)
+	}
+	empty := f.forecastLeg(orderLeg{TokenID: "t2", Direction: "BUY_YES", SizeUSD: &size}, models.OrderbookLatest{})
+	if !empty.UnknownDepth || math.Abs(empty.SlippageUSD-0.45) > 1e-9 {
+		t.Fatalf("fallback leg=%+v", empty)
+	}
+}
+
+func TestScoreCostForecasts(t *testing.T) {
+	d := decimal.RequireFromString
+	rows := []repository.CostForecastOutcomeRow{
+		{
+			CostForecast:        models.CostForecast{StrategyName: "b", SizeUSD: d("100"), SlippageUSD: d("2"), FeesUSD: d("1")},
+			FilledUSD:           d("50"),
+			RealizedSlippageUSD: d("2"),
+			RealizedFeesUSD:     d("0.5"),
+		},
+		{
+			CostForecast:        models.CostForecast{StrategyName: "a", SizeUSD: d("100"), SlippageUSD: d("1"), FeesUSD: d("0"), UnknownDepthLegs: 1},
+			FilledUSD:           d("100"),
+			RealizedSlippageUSD: d("0"),
+			RealizedFeesUSD:     d("0"),
+		},
+	}
+	got := scoreCostForecasts(rows)
+	if len(got) != 3 || got[0].Strategy != "all" || got[1].Strategy != "a" || got[2].Strategy != "b" {
+		t.Fatalf("order=%+v", got)
+	}
+	// b half filled: forecast 1.5, realized 2.5.
+	if b := got[2]; math.Abs(b.BiasUSD-1) > 1e-9 || math.Abs(b.ForecastSlippageUSD-1) > 1e-9 {
+		t.Fatalf("b=%+v", b)
+	}
+	all := got[0]
+	if all.Plans != 2 || all.UnknownDepthPlans != 1 || math.Abs(all.BiasUSD) > 1e-9 || math.Abs(all.MeanAbsErrorUSD-1) > 1e-9 {
+		t.Fatalf("all=%+v", all)
+	}
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/signal"
+)
+
+// minDeltaYears floors time to deadline at one hour; closer in, a digital's
+// delta spikes around the strike and stops meaning anything for a hedge.
+const minDeltaYears = 1.0 / (365 * 24)
+
+// CryptoDeltaService aggregates the spot delta of open positions in crypto
+// threshold markets. Each market is mapped to its asset and strike with the
+// crypto symbol mapping and priced as a cash-or-nothing digital under
+// lognormal spot, so YES on "above K" is long the asset and YES on "below K"
+// is short it. Touch markets ("reach", "hit") are priced the same way, which
+// understates their delta.
+type CryptoDeltaService struct {
+	Repo    repository.Repository
+	Config  config.CryptoDeltaConfig
+	Symbols config.CryptoSymbolConfig
+	HTTP    *http.Client
+	Logger  *zap.Logger
+
+	mu     sync.Mutex
+	prices map[string]cachedSpot
+}
+
+type cachedSpot struct {
+	price float64
+	at    time.Time
+}
+
+// CryptoDeltaReport is the net delta per underlying asset.
+type CryptoDeltaReport struct {
+	Tenant     string                  `json:"tenant,omitempty"`
+	Assets     []CryptoAssetDelta      `json:"assets"`
+	Hedges     []CryptoHedgeSuggestion `json:"hedges,omitempty"`
+	Skipped    int                     `json:"skipped"`
+	Warnings   []string                `json:"warnings,omitempty"`
+	ComputedAt time.Time               `json:"computed_at"`
+}
+
+// CryptoAssetDelta sums the position deltas of one asset. Delta is in units
+// of the asset; DeltaUSD is Delta at the current spot.
+type CryptoAssetDelta struct {
+	Asset      string                `json:"asset"`
+	Symbol     string                `json:"symbol"`
+	Spot       float64               `json:"spot"`
+	Volatility float64               `json:"volatility"`
+	Delta      float64               `json:"delta"`
+	DeltaUSD   float64               `json:"delta_usd"`
+	Positions  []CryptoPositionDelta `json:"positions"`
+}
+
+type CryptoPositionDelta struct {
+	PositionID uint64     `json:"position_id"`
+	MarketID   string     `json:"market_id"`
+	Question   string     `json:"question"`
+	Direction  string     `json:"direction"`
+	Quantity   float64    `json:"quantity"`
+	Strike     float64    `json:"strike"`
+	Comparator string     `json:"comparator"`
+	Deadline   *time.Time `json:"deadline,omitempty"`
+	// FairPrice is the model price of the held side.
+	FairPrice float64 `json:"fair_price"`
+	Delta     float64 `json:"delta"`
+	DeltaUSD  float64 `json:"delta_usd"`
+}
+
+// CryptoHedgeSuggestion offsets an asset's net delta on the hedge venue.
+// Side is SELL for a net long delta and BUY for a net short one.
+type CryptoHedgeSuggestion struct {
+	Asset       string  `json:"asset"`
+	Venue       string  `json:"venue"`
+	Symbol      string  `json:"symbol"`
+	Side        string  `json:"side"`
+	Quantity    float64 `json:"quantity"`
+	NotionalUSD float64 `json:"notional_usd"`
+	// Residual is the delta left after the hedge, in asset units.
+	Residual float64 `json:"residual_delta"`
+	Capped   bool    `json:"capped,omitempty"`
+}
+
+// Compute builds the report for tenant ("" = all desks). Hedge suggestions
+// are added when withHedges is set and hedging is configured.
+func (s *CryptoDeltaService) Compute(ctx context.Context, tenant string, withHedges bool) (CryptoDeltaReport, error) {
+	now := time.Now().UTC()
+	rep := CryptoDeltaReport{Tenant: tenant, Assets: []CryptoAssetDelta{}, ComputedAt: now}
+	if s == nil || s.Repo == nil {
+		return rep, fmt.Errorf("repo unavailable")
+	}
+	positions, err := s.Repo.ListOpenPositions(ctx)
+	if err != nil {
+		return rep, err
+	}
+	marketIDs := make([]string, 0, len(positions))
+	open := positions[:0]
+	for _, p := range positions {
+		if tenant != "" && p.Tenant != tenant {
+			continue
+		}
+		open = append(open, p)
+		marketIDs = append(marketIDs, p.MarketID)
+	}
+	if len(open) == 0 {
+		return rep, nil
+	}
+	markets, err := s.Repo.ListMarketsByIDs(ctx, marketIDs)
+	if err != nil {
+		return rep, err
+	}
+	mappings := map[string]signal.CryptoMarketMapping{}
+	questions := map[string]string{}
+	eventIDs := []string{}
+	for _, m := range markets {
+		mapping, ok := signal.MapCryptoMarket(m, s.Symbols)
+		if !ok {
+			continue
+		}
+		mappings[m.ID] = mapping
+		questions[m.ID] = m.Question
+		eventIDs = append(eventIDs, m.EventID)
+	}
+	if len(mappings) == 0 {
+		return rep, nil
+	}
+	events, err := s.Repo.ListEventsByIDs(ctx, eventIDs)
+	if err != nil {
+		return rep, err
+	}
+	deadlines := map[string]*time.Time{}
+	for _, e := range events {
+		deadlines[e.ID] = e.EndTime
+	}
+
+	byAsset := map[string]*CryptoAssetDelta{}
+	failed := map[string]bool{}
+	for _, p := range open {
+		mapping, ok := mappings[p.MarketID]
+		if !ok {
+			continue
+		}
+		side := strings.ToUpper(strings.TrimSpace(p.Direction))
+		if side != models.OutcomeYes && side != models.OutcomeNo {
+			rep.Skipped++
+			continue
+		}
+		agg := byAsset[mapping.Asset]
+		if agg == nil {
+			if failed[mapping.Symbol] {
+				rep.Skipped++
+				continue
+			}
+			spot, err := s.spot(ctx, mapping.Symbol, now)
+			if err != nil {
+				failed[mapping.Symbol] = true
+				rep.Skipped++
+				rep.Warnings = append(rep.Warnings, fmt.Sprintf("%s spot unavailable: %v", mapping.Symbol, err))
+				continue
+			}
+			agg = &CryptoAssetDelta{Asset: mapping.Asset, Symbol: mapping.Symbol, Spot: spot, Volatility: s.volatility(mapping.Asset)}
+			byAsset[mapping.Asset] = agg
+		}
+		deadline := deadlines[mapping.EventID]
+		years := minDeltaYears
+		if deadline != nil {
+			years = math.Max(deadline.Sub(now).Hours()/(365*24), minDeltaYears)
+		} else {
+			rep.Warnings = appendOnce(rep.Warnings, "markets without a deadline are priced one hour out")
+		}
+		price, dPdS := DigitalDelta(agg.Spot, mapping.Strike, agg.Volatility, years, mapping.Comparator == "below")
+		qty := p.Quantity.InexactFloat64()
+		if side == models.OutcomeNo {
+			price, dPdS = 1-price, -dPdS
+		}
+		pd := CryptoPositionDelta{
+			PositionID: p.ID,
+			MarketID:   p.MarketID,
+			Question:   questions[p.MarketID],
+			Direction:  side,
+			Quantity:   qty,
+			Strike:     mapping.Strike,
+			Comparator: mapping.Comparator,
+			Deadline:   deadline,
+			FairPrice:  price,
+			Delta:      qty * dPdS,
+		}
+		pd.DeltaUSD = pd.Delta * agg.Spot
+		agg.Positions = append(agg.Positions, pd)
+		agg.Delta += pd.Delta
+		agg.DeltaUSD += pd.DeltaUSD
+	}
+
+	for _, agg := range byAsset {
+		rep.Assets = append(rep.Assets, *agg)
+	}
+	sort.Slice(rep.Assets, func(i, j int) bool {
+		return math.Abs(rep.Assets[i].DeltaUSD) > math.Abs(rep.Assets[j].DeltaUSD)
+	})
+	if withHedges && s.Config.Hedge.Enabled {
+		for _, a := range rep.Assets {
+			if h, ok := s.hedge(a); ok {
+				rep.Hedges = append(rep.Hedges, h)
+			}
+		}
+	}
+	return rep, nil
+}
+
+// DigitalDelta returns the price of a cash-or-nothing digital paying 1 when
+// spot ends above strike (below when put is set) and its derivative in spot,
+// with zero rates: price = N(d2), delta = φ(d2) / (S·σ·√T).
+func DigitalDelta(spot, strike, vol, years float64, put bool) (float64, float64) {
+	if spot <= 0 || strike <= 0 || vol <= 0 || years <= 0 {
+		return 0, 0
+	}
+	sd := vol * math.Sqrt(years)
+	d2 := (math.Log(spot/strike) - sd*sd/2) / sd
+	price := normCDF(d2)
+	delta := math.Exp(-d2*d2/2) / math.Sqrt(2*math.Pi) / (spot * sd)
+	if put {
+		return 1 - price, -delta
+	}
+	return price, delta
+}
+
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func (s *CryptoDeltaService) hedge(a CryptoAssetDelta) (CryptoHedgeSuggestion, bool) {
+	cfg := s.Config.Hedge
+	if a.Spot <= 0 || math.Abs(a.DeltaUSD) < cfg.MinNotionalUSD {
+		return CryptoHedgeSuggestion{}, false
+	}
+	qty := math.Abs(a.Delta)
+	capped := false
+	if cfg.MaxNotionalUSD > 0 && qty*a.Spot > cfg.MaxNotionalUSD {
+		qty = cfg.MaxNotionalUSD / a.Spot
+		capped = true
+	}
+	if step := lookupAsset(cfg.QtyStep, a.Asset); step > 0 {
+		qty = math.Floor(qty/step) * step
+	}
+	if qty <= 0 {
+		return CryptoHedgeSuggestion{}, false
+	}
+	template := strings.TrimSpace(cfg.SymbolTemplate)
+	if template == "" {
+		template = "%sUSDT"
+	}
+	h := CryptoHedgeSuggestion{
+		Asset:       a.Asset,
+		Venue:       cfg.Venue,
+		Symbol:      fmt.Sprintf(template, a.Asset),
+		Side:        "SELL",
+		Quantity:    qty,
+		NotionalUSD: qty * a.Spot,
+		Residual:    a.Delta - qty,
+		Capped:      capped,
+	}
+	if a.Delta < 0 {
+		h.Side = "BUY"
+		h.Residual = a.Delta + qty
+	}
+	return h, true
+}
+
+func (s *CryptoDeltaService) volatility(asset string) float64 {
+	if v := lookupAsset(s.Config.Volatility, asset); v > 0 {
+		return v
+	}
+	if s.Config.DefaultVolatility > 0 {
+		return s.Config.DefaultVolatility
+	}
+	return 0.6
+}
+
+// lookupAsset reads a per-asset setting; config loading lowercases map keys.
+func lookupAsset(values map[string]float64, asset string) float64 {
+	for k, v := range values {
+		if strings.EqualFold(strings.TrimSpace(k), asset) {
+			return v
+		}
+	}
+	return 0
+}
+
+func (s *CryptoDeltaService) spot(ctx context.Context, symbol string, now time.Time) (float64, error) {
+	ttl := s.Config.PriceCacheTTL
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	s.mu.Lock()
+	cached, ok := s.prices[symbol]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.at) < ttl {
+		return cached.price, nil
+	}
+	client := s.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	price, err := signal.FetchTickerPrice(ctx, client, signal.CryptoPriceEndpoint(s.Symbols, symbol))
+	if err != nil {
+		if ok {
+			s.warn("crypto delta: spot refresh failed, using cached price", zap.String("symbol", symbol), zap.Error(err))
+			return cached.price, nil
+		}
+		return 0, err
+	}
+	s.mu.Lock()
+	if s.prices == nil {
+		s.prices = map[string]cachedSpot{}
+	}
+	s.prices[symbol] = cachedSpot{price: price, at: now}
+	s.mu.Unlock()
+	return price, nil
+}
+
+func appendOnce(items []string, item string) []string {
+	for _, v := range items {
+		if v == item {
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+func (s *CryptoDeltaService) warn(msg string, fields ...zap.Field) {
+	if s.Logger != nil {
+		s.Logger.Warn(msg, fields...)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestDigitalDelta(t *testing.T) {
+	price, delta := DigitalDelta(100000, 100000, 0.6, 30.0/365, false)
+	if math.Abs(price-0.5) > 0.05 || delta <= 0 {
+		t.Fatalf("ATM call digital: price=%v delta=%v", price, delta)
+	}
+	// Finite difference agrees with the closed form.
+	up, _ := DigitalDelta(100100, 100000, 0.6, 30.0/365, false)
+	if fd := (up - price) / 100; math.Abs(fd-delta)/delta > 0.01 {
+		t.Fatalf("delta=%v finite difference=%v", delta, fd)
+	}
+	putPrice, putDelta := DigitalDelta(100000, 100000, 0.6, 30.0/365, true)
+	if math.Abs(putPrice+price-1) > 1e-9 || putDelta != -delta {
+		t.Fatalf("put digital: price=%v delta=%v", putPrice, putDelta)
+	}
+}
+
+type deltaRepo struct {
+	repository.Repository
+	positions []models.Position
+	markets   []models.Market
+	events    []models.Event
+}
+
+func (r *deltaRepo) ListOpenPositions(ctx context.Context) ([]models.Position, error) {
+	return r.positions, nil
+}
+
+func (r *deltaRepo) ListMarketsByIDs(ctx context.Context, ids []string) ([]models.Market, error) {
+	return r.markets, nil
+}
+
+func (r *deltaRepo) ListEventsByIDs(ctx context.Context, ids []string) ([]models.Event, error) {
+	return r.events, nil
+}
+
+func TestCryptoDeltaService_NetsAndHedges(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"symbol":"BTCUSDT","price":"100000"}`))
+	}))
+	defer srv.Close()
+
+	end := time.Now().Add(30 * 24 * time.Hour)
+	repo := &deltaRepo{
+		positions: []models.Position{
+			{ID: 1, MarketID: "above", Tenant: "a", Direction: "YES", Quantity: decimal.NewFromInt(5000)},
+			{ID: 2, MarketID: "below", Tenant: "a", Direction: "YES", Quantity: decimal.NewFromInt(1000)},
+			{ID: 3, MarketID: "sports", Tenant: "a", Direction: "YES", Quantity: decimal.NewFromInt(100)},
+			{ID: 4, MarketID: "above", Tenant: "b", Direction: "NO", Quantity: decimal.NewFromInt(9000)},
+		},
+		markets: []models.Market{
+			{ID: "above", EventID: "e1", Question: "Will Bitcoin be above $100k on June 30?"},
+			{ID: "below", EventID: "e1", Question: "Will Bitcoin dip to $90,000 in June?"},
+			{ID: "sports", EventID: "e2", Question: "Will the Lakers win?"},
+		},
+		events: []models.Event{{ID: "e1", EndTime: &end}},
+	}
+	svc := &CryptoDeltaService{
+		Repo:    repo,
+		Symbols: config.CryptoSymbolConfig{EndpointTemplate: srv.URL + "?symbol=%s"},
+		Config: config.CryptoDeltaConfig{
+			Volatility: map[string]float64{"btc": 0.6},
+			Hedge:      config.CryptoHedgeConfig{Enabled: true, Venue: "binance_perp", MinNotionalUSD: 100, QtyStep: map[string]float64{"btc": 0.001}},
+		},
+	}
+	rep, err := svc.Compute(context.Background(), "a", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Assets) != 1 || rep.Assets[0].Asset != "BTC" || len(rep.Assets[0].Positions) != 2 {
+		t.Fatalf("assets = %+v", rep.Assets)
+	}
+	a := rep.Assets[0]
+	if a.Positions[0].Delta <= 0 || a.Positions[1].Delta >= 0 || a.Delta <= 0 {
+		t.Fatalf("expected long above, short below, net long: %+v", a)
+	}
+	if len(rep.Hedges) != 1 {
+		t.Fatalf("hedges = %+v", rep.Hedges)
+	}
+	h := rep.Hedges[0]
+	if h.Side != "SELL" || h.Symbol != "BTCUSDT" || h.Quantity > a.Delta || a.Delta-h.Quantity >= 0.001 {
+		t.Fatalf("hedge = %+v for delta %v", h, a.Delta)
+	}
+}
@@ -2,17 +2,30 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
 
+	"polymarket/internal/governor"
+	"polymarket/internal/models"
 	"polymarket/internal/repository"
+	"polymarket/internal/tradingday"
 )
 
+// settingTradingDay records the trading-day boundary the stored daily stats
+// were built with.
+const settingTradingDay = "analytics.trading_day"
+
 type DailyStatsService struct {
 	Repo   repository.Repository
 	Logger *zap.Logger
 	Flags  *SystemSettingsService
+	// Governor, when set, admits each scheduled rebuild as a rebuild job.
+	Governor *governor.Governor
+	// Calendar buckets records into trading days (nil = UTC midnight).
+	Calendar *tradingday.Calendar
 }
 
 func (s *DailyStatsService) Run(ctx context.Context, interval time.Duration) error {
@@ -25,7 +38,7 @@ func (s *DailyStatsService) Run(ctx context.Context, interval time.Duration) err
 	t := time.NewTicker(interval)
 	defer t.Stop()
 	for {
-		if err := s.RunOnce(ctx); err != nil && s.Logger != nil {
+		if err := s.Governor.Run(ctx, governor.ClassRebuild, func(ctx context.Context) error { return s.RunOnce(ctx) }); err != nil && s.Logger != nil {
 			s.Logger.Warn("daily stats run failed", zap.Error(err))
 		}
 		select {
@@ -43,8 +56,58 @@ func (s *DailyStatsService) RunOnce(ctx context.Context) error {
 	if s.Flags != nil && !s.Flags.IsEnabled(ctx, FeatureDailyStats, true) {
 		return nil
 	}
+	backfilled, err := s.backfillOnBoundaryChange(ctx)
+	if err != nil || backfilled {
+		return err
+	}
 	now := time.Now().UTC()
 	since := now.Add(-30 * 24 * time.Hour)
-	_, err := s.Repo.RebuildStrategyDailyStats(ctx, &since, nil)
+	_, err = s.Repo.RebuildStrategyDailyStats(ctx, &since, nil, s.Calendar)
 	return err
 }
+
+// backfillOnBoundaryChange rebuilds all history when the trading-day
+// boundary differs from the one the stored rows were built with.
+func (s *DailyStatsService) backfillOnBoundaryChange(ctx context.Context) (bool, error) {
+	current := s.Calendar.String()
+	row, err := s.Repo.GetSystemSettingByKey(ctx, settingTradingDay)
+	if err != nil {
+		return false, err
+	}
+	var stored string
+	if row != nil {
+		_ = json.Unmarshal(row.Value, &stored)
+	}
+	// A fresh install built under UTC midnight before the setting existed.
+	if stored == "" {
+		stored = (*tradingday.Calendar)(nil).String()
+	}
+	if stored == current && row != nil {
+		return false, nil
+	}
+	backfilled := false
+	if stored != current {
+		n, err := s.Repo.RebuildStrategyDailyStats(ctx, nil, nil, s.Calendar)
+		if err != nil {
+			return false, err
+		}
+		backfilled = true
+		if s.Logger != nil {
+			s.Logger.Info("daily stats rebuilt for new trading day boundary",
+				zap.String("from", stored), zap.String("to", current), zap.Int("rows", n))
+		}
+	}
+	raw, _ := json.Marshal(current)
+	item := &models.SystemSetting{
+		Key:         settingTradingDay,
+		Value:       datatypes.JSON(raw),
+		Description: "trading day boundary of strategy_daily_stats",
+		UpdatedAt:   time.Now().UTC(),
+	}
+	if row != nil {
+		item.CreatedAt = row.CreatedAt
+	} else {
+		item.CreatedAt = item.UpdatedAt
+	}
+	return backfilled, s.Repo.UpsertSystemSetting(ctx, item)
+}
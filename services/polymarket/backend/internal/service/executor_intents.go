@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// intentReconcileLimit bounds how many pending intents one reconcile pass
+// looks at.
+const intentReconcileLimit = 500
+
+// OrderStatusNeedsReview marks an order whose submit was interrupted before
+// the venue returned an order id. The venue cannot be queried by our client
+// order id, so the order may or may not be resting; an operator checks and
+// cancels it or records its fills.
+const OrderStatusNeedsReview = models.OrderStatusNeedsReview
+
+// NotifyEventOrderNeedsReview is the notification event sent when an order
+// is held for review.
+const NotifyEventOrderNeedsReview = "polymarket.order_needs_review"
+
+// IntentReconcileResult counts what a reconcile pass did with the pending
+// intents it found.
+type IntentReconcileResult struct {
+	Checked    int `json:"checked"`
+	Reconciled int `json:"reconciled"`
+	Orphaned   int `json:"orphaned"`
+	// NeedsReview orders were moved to needs_review; operators are notified.
+	NeedsReview []uint64 `json:"needs_review,omitempty"`
+	// Failed intents could not be looked up and stay pending.
+	Failed int `json:"failed"`
+}
+
+// beginIntent records that action is about to be sent to the venue for
+// order. The request must not be sent when the record cannot be written.
+func (e *CLOBExecutor) beginIntent(ctx context.Context, action string, order models.Order, request map[string]any) (*models.ExecutorIntent, error) {
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	intent := &models.ExecutorIntent{
+		Action:      action,
+		OrderID:     order.ID,
+		PlanID:      order.PlanID,
+		TokenID:     order.TokenID,
+		ClobOrderID: strings.TrimSpace(order.ClobOrderID),
+		Request:     datatypes.JSON(raw),
+		Status:      models.ExecutorIntentPending,
+	}
+	if err := e.Repo.InsertExecutorIntent(ctx, intent); err != nil {
+		return nil, fmt.Errorf("record %s intent: %w", action, err)
+	}
+	return intent, nil
+}
+
+// resolveIntent records the venue's answer to intent: the order status and
+// updates it produced, or the error it failed with.
+func (e *CLOBExecutor) resolveIntent(ctx context.Context, intent *models.ExecutorIntent, status string, updates map[string]any, sendErr error) {
+	if intent == nil {
+		return
+	}
+	next := models.ExecutorIntentResolved
+	values := map[string]any{"outcome": status}
+	if sendErr != nil {
+		next = models.ExecutorIntentFailed
+		values = map[string]any{"error": sendErr.Error()}
+	} else {
+		if raw, err := json.Marshal(updates); err == nil {
+			values["response"] = datatypes.JSON(raw)
+		}
+		if id, ok := updates["clob_order_id"].(string); ok && strings.TrimSpace(id) != "" {
+			values["clob_order_id"] = strings.TrimSpace(id)
+		}
+	}
+	if err := e.Repo.ResolveExecutorIntent(ctx, intent.ID, next, values); err != nil && e.Logger != nil {
+		e.Logger.Warn("resolve executor intent failed", zap.Uint64("intent_id", intent.ID), zap.Error(err))
+	}
+}
+
+// ReconcileIntents settles the intents left pending by a crash between a
+// venue request and its response. Orders the venue acknowledged are
+// refreshed from its view of them; a submit that never got an order id
+// cannot be looked up, so its order is moved to needs_review and the intent
+// orphaned, and operators are notified to check it on the venue. Intents
+// whose order cannot be fetched stay pending for the next pass.
+func (e *CLOBExecutor) ReconcileIntents(ctx context.Context) (*IntentReconcileResult, error) {
+	out := &IntentReconcileResult{}
+	if e == nil || e.Repo == nil {
+		return out, nil
+	}
+	pending := models.ExecutorIntentPending
+	intents, err := e.Repo.ListExecutorIntents(ctx, repository.ListExecutorIntentsParams{Limit: intentReconcileLimit, Status: &pending})
+	if err != nil {
+		return out, err
+	}
+	for _, intent := range intents {
+		out.Checked++
+		order, err := e.Repo.GetOrderByID(ctx, intent.OrderID)
+		if err != nil {
+			return out, err
+		}
+		if order == nil {
+			e.orphanIntent(ctx, intent, "order not found")
+			out.Orphaned++
+			continue
+		}
+		clobOrderID := strings.TrimSpace(order.ClobOrderID)
+		if clobOrderID == "" {
+			clobOrderID = intent.ClobOrderID
+		}
+		if clobOrderID == "" {
+			reason := fmt.Sprintf("%s interrupted before the venue acknowledged it", intent.Action)
+			if order.Status == "pending" {
+				_ = e.Repo.UpdateOrderStatus(ctx, order.ID, OrderStatusNeedsReview, map[string]any{"failure_reason": reason + "; check the venue for client order " + fmt.Sprint(order.ID)})
+				_ = e.reconcilePlanStatus(ctx, order.PlanID)
+				out.NeedsReview = append(out.NeedsReview, order.ID)
+			}
+			e.orphanIntent(ctx, intent, reason)
+			out.Orphaned++
+			continue
+		}
+		status, updates, err := e.fetchLiveOrder(ctx, clobOrderID)
+		if err != nil {
+			if e.Logger != nil {
+				e.Logger.Warn("reconcile executor intent failed", zap.Uint64("intent_id", intent.ID), zap.Uint64("order_id", order.ID), zap.Error(err))
+			}
+			out.Failed++
+			continue
+		}
+		if status == "" {
+			status = order.Status
+		}
+		if strings.TrimSpace(order.ClobOrderID) == "" {
+			updates["clob_order_id"] = clobOrderID
+		}
+		if err := e.Repo.UpdateOrderStatus(ctx, order.ID, status, updates); err != nil {
+			return out, err
+		}
+		if status == "filled" || status == "partial" {
+			_ = e.applyOrderFillDelta(ctx, *order, updates)
+		}
+		_ = e.reconcilePlanStatus(ctx, order.PlanID)
+		values := map[string]any{"outcome": status, "clob_order_id": clobOrderID}
+		if raw, err := json.Marshal(updates); err == nil {
+			values["response"] = datatypes.JSON(raw)
+		}
+		if err := e.Repo.ResolveExecutorIntent(ctx, intent.ID, models.ExecutorIntentReconciled, values); err != nil {
+			return out, err
+		}
+		out.Reconciled++
+	}
+	if out.Checked > 0 {
+		paas.LogBestEffortCtx(ctx, "polymarket_executor_intents_reconciled", "info", map[string]any{
+			"checked":      out.Checked,
+			"reconciled":   out.Reconciled,
+			"orphaned":     out.Orphaned,
+			"failed":       out.Failed,
+			"needs_review": out.NeedsReview,
+		})
+	}
+	e.notifyNeedsReview(ctx, out.NeedsReview)
+	return out, nil
+}
+
+// notifyNeedsReview alerts operators to orders held for review, which stay
+// open until someone checks the venue and cancels them or records fills.
+func (e *CLOBExecutor) notifyNeedsReview(ctx context.Context, orderIDs []uint64) {
+	if len(orderIDs) == 0 {
+		return
+	}
+	notify := e.Notify
+	if notify == nil {
+		notify = paas.BroadcastCtx
+	}
+	message := fmt.Sprintf("[warning] polymarket orders %v need review: their submit was interrupted before the venue acknowledged it. Check the venue by client order id, then cancel them or record their fills.", orderIDs)
+	if err := notify(ctx, NotifyEventOrderNeedsReview, message); err != nil && e.Logger != nil {
+		e.Logger.Warn("needs review notify failed", zap.Uint64s("order_ids", orderIDs), zap.Error(err))
+	}
+}
+
+func (e *CLOBExecutor) orphanIntent(ctx context.Context, intent models.ExecutorIntent, reason string) {
+	if e.Logger != nil {
+		e.Logger.Warn("executor intent orphaned",
+			zap.Uint64("intent_id", intent.ID),
+			zap.String("action", intent.Action),
+			zap.Uint64("order_id", intent.OrderID),
+			zap.String("reason", reason),
+		)
+	}
+	if err := e.Repo.ResolveExecutorIntent(ctx, intent.ID, models.ExecutorIntentOrphaned, map[string]any{"error": reason}); err != nil && e.Logger != nil {
+		e.Logger.Warn("resolve executor intent failed", zap.Uint64("intent_id", intent.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	polymarketclob "polymarket/internal/client/polymarket/clob"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type intentRepo struct {
+	repository.Repository
+	orders  map[uint64]*models.Order
+	intents []*models.ExecutorIntent
+}
+
+func (r *intentRepo) GetSystemSettingByKey(context.Context, string) (*models.SystemSetting, error) {
+	return nil, nil
+}
+
+func (r *intentRepo) GetOrderByID(_ context.Context, id uint64) (*models.Order, error) {
+	if o, ok := r.orders[id]; ok {
+		cp := *o
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (r *intentRepo) UpdateOrderStatus(_ context.Context, id uint64, status string, updates map[string]any) error {
+	if o, ok := r.orders[id]; ok {
+		o.Status = status
+		if v, ok := updates["clob_order_id"].(string); ok {
+			o.ClobOrderID = v
+		}
+	}
+	return nil
+}
+
+func (r *intentRepo) ListOrders(context.Context, repository.ListOrdersParams) ([]models.Order, error) {
+	return nil, nil
+}
+
+func (r *intentRepo) InsertExecutorIntent(_ context.Context, item *models.ExecutorIntent) error {
+	item.ID = uint64(len(r.intents) + 1)
+	r.intents = append(r.intents, item)
+	return nil
+}
+
+func (r *intentRepo) ResolveExecutorIntent(_ context.Context, id uint64, status string, updates map[string]any) error {
+	for _, it := range r.intents {
+		if it.ID != id || it.Status != models.ExecutorIntentPending {
+			continue
+		}
+		it.Status = status
+		if v, ok := updates["outcome"].(string); ok {
+			it.Outcome = v
+		}
+		if v, ok := updates["error"].(string); ok {
+			it.Error = v
+		}
+		if v, ok := updates["clob_order_id"].(string); ok {
+			it.ClobOrderID = v
+		}
+	}
+	return nil
+}
+
+func (r *intentRepo) ListExecutorIntents(_ context.Context, params repository.ListExecutorIntentsParams) ([]models.ExecutorIntent, error) {
+	var out []models.ExecutorIntent
+	for _, it := range r.intents {
+		if params.Status == nil || it.Status == *params.Status {
+			out = append(out, *it)
+		}
+	}
+	return out, nil
+}
+
+func intentVenue(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/cancel"):
+			_, _ = w.Write([]byte(`{"order_id":"c1","status":"canceled"}`))
+		case strings.HasPrefix(req.URL.Path, "/orders/c2"):
+			_, _ = w.Write([]byte(`{"order_id":"c2","status":"open"}`))
+		default:
+			http.Error(w, "down", http.StatusServiceUnavailable)
+		}
+	}))
+}
+
+func TestCancelOrder_RecordsIntent(t *testing.T) {
+	srv := intentVenue(t)
+	defer srv.Close()
+	repo := &intentRepo{orders: map[uint64]*models.Order{
+		1: {ID: 1, PlanID: 7, TokenID: "t1", ClobOrderID: "c1", Status: "submitted", Price: decimal.NewFromFloat(0.4)},
+	}}
+	e := &CLOBExecutor{Repo: repo, Client: polymarketclob.NewClient(srv.Client(), srv.URL), Config: ExecutorConfig{Mode: "live"}}
+
+	if err := e.CancelOrder(context.Background(), 1); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if len(repo.intents) != 1 {
+		t.Fatalf("intents=%d", len(repo.intents))
+	}
+	it := repo.intents[0]
+	if it.Action != models.ExecutorIntentCancel || it.Status != models.ExecutorIntentResolved || it.Outcome != "cancelled" || it.OrderID != 1 || it.PlanID != 7 {
+		t.Fatalf("intent=%+v", it)
+	}
+}
+
+func TestReconcileIntents(t *testing.T) {
+	srv := intentVenue(t)
+	defer srv.Close()
+	repo := &intentRepo{
+		orders: map[uint64]*models.Order{
+			// Submit cut off before the venue answered.
+			1: {ID: 1, PlanID: 7, TokenID: "t1", Status: "pending"},
+			// Amend cut off after the order was resting.
+			2: {ID: 2, PlanID: 7, TokenID: "t2", ClobOrderID: "c2", Status: "submitted"},
+			// Cancel whose order the venue cannot be asked about.
+			3: {ID: 3, PlanID: 7, TokenID: "t3", ClobOrderID: "c3", Status: "submitted"},
+		},
+		intents: []*models.ExecutorIntent{
+			{ID: 1, Action: models.ExecutorIntentSubmit, OrderID: 1, Status: models.ExecutorIntentPending},
+			{ID: 2, Action: models.ExecutorIntentAmend, OrderID: 2, Status: models.ExecutorIntentPending},
+			{ID: 3, Action: models.ExecutorIntentCancel, OrderID: 3, Status: models.ExecutorIntentPending},
+			{ID: 4, Action: models.ExecutorIntentSubmit, OrderID: 9, Status: models.ExecutorIntentResolved},
+		},
+	}
+	var notified []string
+	e := &CLOBExecutor{Repo: repo, Client: polymarketclob.NewClient(srv.Client(), srv.URL), Config: ExecutorConfig{Mode: "live"},
+		Notify: func(ctx context.Context, event, message string) error {
+			notified = append(notified, event)
+			return nil
+		},
+	}
+
+	res, err := e.ReconcileIntents(context.Background())
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if res.Checked != 3 || res.Reconciled != 1 || res.Orphaned != 1 || res.Failed != 1 || len(res.NeedsReview) != 1 || res.NeedsReview[0] != 1 {
+		t.Fatalf("result=%+v", res)
+	}
+	if repo.intents[0].Status != models.ExecutorIntentOrphaned || repo.orders[1].Status != OrderStatusNeedsReview {
+		t.Fatalf("submit intent=%+v order=%+v", repo.intents[0], repo.orders[1])
+	}
+	if len(notified) != 1 || notified[0] != NotifyEventOrderNeedsReview {
+		t.Fatalf("notified=%v", notified)
+	}
+	if repo.intents[1].Status != models.ExecutorIntentReconciled || repo.intents[1].Outcome != "submitted" {
+		t.Fatalf("amend intent=%+v", repo.intents[1])
+	}
+	if repo.intents[2].Status != models.ExecutorIntentPending || repo.orders[3].Status != "submitted" {
+		t.Fatalf("cancel intent=%+v order=%+v", repo.intents[2], repo.orders[3])
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+// FillImportRow is one fill of a manual or offline trade, as uploaded. CSV
+// uploads name these fields in a header row; other columns are ignored.
+type FillImportRow struct {
+	ExternalTradeID string `json:"external_trade_id"`
+	TokenID         string `json:"token_id"`
+	Direction       string `json:"direction"`
+	FilledSize      string `json:"filled_size"`
+	AvgPrice        string `json:"avg_price"`
+	Fee             string `json:"fee"`
+	Slippage        string `json:"slippage"`
+	FilledAt        string `json:"filled_at"`
+}
+
+// FillImportError is a rejected row; Row counts from 1, excluding a header.
+type FillImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+var fillImportColumns = []string{"external_trade_id", "token_id", "direction", "filled_size", "avg_price", "fee", "slippage", "filled_at"}
+
+// ParseFillImportCSV reads rows from CSV with a header row. The header must
+// name at least external_trade_id, token_id, direction, filled_size and
+// avg_price.
+func ParseFillImportCSV(r io.Reader) ([]FillImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range fillImportColumns[:5] {
+		if _, ok := index[required]; !ok {
+			return nil, fmt.Errorf("missing column %s", required)
+		}
+	}
+	var out []FillImportRow
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := index[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		out = append(out, FillImportRow{
+			ExternalTradeID: field("external_trade_id"),
+			TokenID:         field("token_id"),
+			Direction:       field("direction"),
+			FilledSize:      field("filled_size"),
+			AvgPrice:        field("avg_price"),
+			Fee:             field("fee"),
+			Slippage:        field("slippage"),
+			FilledAt:        field("filled_at"),
+		})
+	}
+}
+
+// ParseFillImportJSON reads rows from a JSON array or {"fills": [...]}.
+func ParseFillImportJSON(raw []byte) ([]FillImportRow, error) {
+	var rows []FillImportRow
+	if err := json.Unmarshal(raw, &rows); err == nil {
+		return rows, nil
+	}
+	var wrapped struct {
+		Fills []FillImportRow `json:"fills"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err != nil {
+		return nil, err
+	}
+	return wrapped.Fills, nil
+}
+
+// ValidateFillImport turns rows into fills of plan. Unlike the single-fill
+// endpoint it rejects what it cannot parse rather than defaulting it: a row
+// needs a trade id unique within the batch, a token of one of the plan's
+// legs, a positive size, a price in (0, 1], a non-negative fee and, when
+// given, an RFC3339 filled_at (otherwise now). Fills are returned only when
+// every row is valid.
+func ValidateFillImport(plan models.ExecutionPlan, rows []FillImportRow, now time.Time) ([]models.Fill, []FillImportError) {
+	var legs []struct {
+		TokenID string `json:"token_id"`
+	}
+	_ = json.Unmarshal(plan.Legs, &legs)
+	tokens := map[string]struct{}{}
+	for _, leg := range legs {
+		if id := strings.TrimSpace(leg.TokenID); id != "" {
+			tokens[id] = struct{}{}
+		}
+	}
+	seen := map[string]int{}
+	var fills []models.Fill
+	var errs []FillImportError
+	for i, r := range rows {
+		n := i + 1
+		bad := func(field, msg string) {
+			errs = append(errs, FillImportError{Row: n, Field: field, Message: msg})
+		}
+		tradeID := strings.TrimSpace(r.ExternalTradeID)
+		if tradeID == "" {
+			bad("external_trade_id", "required")
+		} else if first, dup := seen[tradeID]; dup {
+			bad("external_trade_id", fmt.Sprintf("duplicates row %d", first))
+		} else {
+			seen[tradeID] = n
+		}
+		tokenID := strings.TrimSpace(r.TokenID)
+		if _, ok := tokens[tokenID]; !ok {
+			bad("token_id", "not a leg of the plan")
+		}
+		direction := strings.ToUpper(strings.TrimSpace(r.Direction))
+		if direction == "" {
+			bad("direction", "required")
+		}
+		size, err := decimal.NewFromString(strings.TrimSpace(r.FilledSize))
+		if err != nil || !size.IsPositive() {
+			bad("filled_size", "must be a positive number")
+		}
+		price, err := decimal.NewFromString(strings.TrimSpace(r.AvgPrice))
+		if err != nil || !price.IsPositive() || price.GreaterThan(decimal.NewFromInt(1)) {
+			bad("avg_price", "must be in (0, 1]")
+		}
+		fee := decimal.Zero
+		if v := strings.TrimSpace(r.Fee); v != "" {
+			if fee, err = decimal.NewFromString(v); err != nil || fee.IsNegative() {
+				bad("fee", "must be a non-negative number")
+			}
+		}
+		var slippage *decimal.Decimal
+		if v := strings.TrimSpace(r.Slippage); v != "" {
+			s, err := decimal.NewFromString(v)
+			if err != nil {
+				bad("slippage", "must be a number")
+			}
+			slippage = &s
+		}
+		filledAt := now.UTC()
+		if v := strings.TrimSpace(r.FilledAt); v != "" {
+			ts, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				bad("filled_at", "must be RFC3339")
+			}
+			filledAt = ts.UTC()
+		}
+		if len(errs) > 0 {
+			continue
+		}
+		fills = append(fills, models.Fill{
+			PlanID:          plan.ID,
+			TokenID:         tokenID,
+			Direction:       direction,
+			ExternalTradeID: &tradeID,
+			FilledSize:      size,
+			AvgPrice:        price,
+			Fee:             fee,
+			Slippage:        slippage,
+			FilledAt:        filledAt,
+			CreatedAt:       now.UTC(),
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return fills, nil
+}
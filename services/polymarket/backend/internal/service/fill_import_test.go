@@ -0,0 +1,54 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"polymarket/internal/models"
+)
+
+func TestParseFillImportCSV(t *testing.T) {
+	raw := "External_Trade_ID,token_id,direction,filled_size,avg_price,venue\n" +
+		"x1, t1,buy_yes,10,0.42,otc\n"
+	rows, err := ParseFillImportCSV(strings.NewReader(raw))
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("rows=%+v err=%v", rows, err)
+	}
+	if r := rows[0]; r.ExternalTradeID != "x1" || r.TokenID != "t1" || r.AvgPrice != "0.42" || r.Fee != "" {
+		t.Fatalf("row=%+v", r)
+	}
+	if _, err := ParseFillImportCSV(strings.NewReader("token_id,direction\n")); err == nil {
+		t.Fatalf("expected missing column error")
+	}
+}
+
+func TestValidateFillImport(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	plan := models.ExecutionPlan{ID: 4, Legs: []byte(`[{"token_id":"t1"},{"token_id":"t2"}]`)}
+	rows := []FillImportRow{
+		{ExternalTradeID: "x1", TokenID: "t1", Direction: "buy_yes", FilledSize: "10", AvgPrice: "0.42", FilledAt: "2026-03-01T10:00:00Z"},
+		{ExternalTradeID: "x2", TokenID: "t2", Direction: "BUY_NO", FilledSize: "10", AvgPrice: "0.55", Fee: "0.1"},
+	}
+	fills, errs := ValidateFillImport(plan, rows, now)
+	if len(errs) != 0 || len(fills) != 2 {
+		t.Fatalf("fills=%d errs=%+v", len(fills), errs)
+	}
+	if f := fills[0]; f.PlanID != 4 || f.Direction != "BUY_YES" || *f.ExternalTradeID != "x1" || f.FilledAt.Hour() != 10 {
+		t.Fatalf("fill=%+v", f)
+	}
+	if !fills[1].FilledAt.Equal(now) {
+		t.Fatalf("filled_at=%v want now", fills[1].FilledAt)
+	}
+
+	rows = append(rows,
+		FillImportRow{ExternalTradeID: "x1", TokenID: "t3", Direction: "BUY_YES", FilledSize: "0", AvgPrice: "1.5"},
+	)
+	fills, errs = ValidateFillImport(plan, rows, now)
+	if fills != nil || len(errs) != 4 {
+		t.Fatalf("fills=%v errs=%+v", fills, errs)
+	}
+	if errs[0].Row != 3 || errs[0].Message != "duplicates row 1" {
+		t.Fatalf("errs[0]=%+v", errs[0])
+	}
+}
@@ -30,12 +30,17 @@ func (s *JournalService) CaptureEntry(ctx context.Context, planID uint64) error
 	if err != nil || plan == nil {
 		return err
 	}
-	opp, err := s.Repo.GetOpportunityByID(ctx, plan.OpportunityID)
-	if err != nil || opp == nil {
-		return err
+	// Manual plans have no opportunity; the trader's note is the reasoning.
+	reasoning := manualPlanNote(plan.Params)
+	var ids []uint64
+	if plan.OpportunityID > 0 {
+		opp, err := s.Repo.GetOpportunityByID(ctx, plan.OpportunityID)
+		if err != nil || opp == nil {
+			return err
+		}
+		reasoning = opp.Reasoning
+		ids = parseSignalIDs(opp.SignalIDs)
 	}
-
-	ids := parseSignalIDs(opp.SignalIDs)
 	signalSnapshot := map[string]any{"signal_ids": ids}
 	if len(ids) > 0 {
 		items, _ := s.Repo.ListSignals(ctx, repository.ListSignalsParams{
@@ -75,6 +80,7 @@ func (s *JournalService) CaptureEntry(ctx context.Context, planID uint64) error
 		"plan_id":      plan.ID,
 		"status":       plan.Status,
 		"strategy":     plan.StrategyName,
+		"source":       plan.Source,
 		"opportunity":  plan.OpportunityID,
 		"created_at":   plan.CreatedAt,
 		"planned_legs": json.RawMessage(plan.Legs),
@@ -92,7 +98,7 @@ func (s *JournalService) CaptureEntry(ctx context.Context, planID uint64) error
 		ExecutionPlanID: plan.ID,
 		OpportunityID:   plan.OpportunityID,
 		StrategyName:    plan.StrategyName,
-		EntryReasoning:  opp.Reasoning,
+		EntryReasoning:  reasoning,
 		SignalSnapshot:  datatypes.JSON(signalRaw),
 		MarketSnapshot:  datatypes.JSON(marketRaw),
 		EntryParams:     plan.Params,
@@ -176,6 +182,19 @@ func parseSignalIDs(raw []byte) []uint64 {
 	return out
 }
 
+func manualPlanNote(paramsJSON []byte) string {
+	var params struct {
+		Note string `json:"note"`
+	}
+	if len(paramsJSON) > 0 {
+		_ = json.Unmarshal(paramsJSON, &params)
+	}
+	if note := strings.TrimSpace(params.Note); note != "" {
+		return note
+	}
+	return "manual plan"
+}
+
 type journalLeg struct {
 	TokenID string `json:"token_id"`
 }
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// OrderStatusHeld marks a live order kept back until the legs it depends on
+// have filled; it has not been sent to the venue.
+const OrderStatusHeld = models.OrderStatusHeld
+
+// Leg dependency states. A held leg is waiting on its prerequisites, ready
+// once they reach the fill threshold, blocked when one of them ended short
+// of it, and timed out when its deadline passed first. A leg no longer held
+// is released.
+const (
+	LegDependencyWaiting  = "waiting"
+	LegDependencyReady    = "ready"
+	LegDependencyBlocked  = "blocked"
+	LegDependencyTimedOut = "timed_out"
+	LegDependencyReleased = "released"
+)
+
+// LegDependencyConfig holds the defaults for legs that leave out their
+// timeout or timeout action. OnTimeout is "submit" or "cancel".
+type LegDependencyConfig struct {
+	Timeout   time.Duration
+	OnTimeout string
+}
+
+// legDependency in a plan leg holds the leg back until the legs on the
+// tokens in After have filled MinFillPct (0-1, default 1) of their size.
+// After TimeoutSec (0 uses the configured default) it is submitted or
+// cancelled per OnTimeout.
+type legDependency struct {
+	After      []string `json:"after"`
+	MinFillPct float64  `json:"min_fill_pct"`
+	TimeoutSec int      `json:"timeout_sec"`
+	OnTimeout  string   `json:"on_timeout"`
+}
+
+func (d *legDependency) active() bool {
+	return d != nil && len(d.After) > 0
+}
+
+func (d *legDependency) minFill() float64 {
+	if d.MinFillPct <= 0 || d.MinFillPct > 1 {
+		return 1
+	}
+	return d.MinFillPct
+}
+
+func (d *legDependency) timeout(cfg LegDependencyConfig) time.Duration {
+	if d.TimeoutSec > 0 {
+		return time.Duration(d.TimeoutSec) * time.Second
+	}
+	return cfg.Timeout
+}
+
+func (d *legDependency) onTimeout(cfg LegDependencyConfig) string {
+	v := strings.ToLower(strings.TrimSpace(d.OnTimeout))
+	if v == "" {
+		v = strings.ToLower(strings.TrimSpace(cfg.OnTimeout))
+	}
+	if v == "submit" {
+		return "submit"
+	}
+	return "cancel"
+}
+
+// PrerequisiteStatus is how far one prerequisite leg has filled.
+type PrerequisiteStatus struct {
+	TokenID   string  `json:"token_id"`
+	FilledPct float64 `json:"filled_pct"`
+	// Done is set once none of the leg's orders can fill further.
+	Done bool `json:"done"`
+}
+
+// LegDependencyStatus is the state of one dependent leg of a plan.
+type LegDependencyStatus struct {
+	TokenID     string               `json:"token_id"`
+	OrderID     uint64               `json:"order_id,omitempty"`
+	OrderStatus string               `json:"order_status,omitempty"`
+	State       string               `json:"state"`
+	MinFillPct  float64              `json:"min_fill_pct"`
+	OnTimeout   string               `json:"on_timeout"`
+	Deadline    *time.Time           `json:"deadline,omitempty"`
+	After       []PrerequisiteStatus `json:"after"`
+}
+
+// validateLegDependencies rejects dependencies on tokens that no leg of the
+// plan trades and dependency cycles, which could only ever time out.
+func validateLegDependencies(children []childOrder) error {
+	after := map[string][]string{}
+	for _, c := range children {
+		after[c.TokenID] = append(after[c.TokenID], dependencyTokens(c.Leg.DependsOn)...)
+	}
+	for token, deps := range after {
+		for _, dep := range deps {
+			if _, ok := after[dep]; !ok {
+				return fmt.Errorf("leg %s depends on %s, which is not a leg of the plan", token, dep)
+			}
+		}
+	}
+	state := map[string]int{}
+	var visit func(string) bool
+	visit = func(token string) bool {
+		switch state[token] {
+		case 1:
+			return false
+		case 2:
+			return true
+		}
+		state[token] = 1
+		for _, dep := range after[token] {
+			if !visit(dep) {
+				return false
+			}
+		}
+		state[token] = 2
+		return true
+	}
+	for token := range after {
+		if !visit(token) {
+			return fmt.Errorf("leg dependencies of %s form a cycle", token)
+		}
+	}
+	return nil
+}
+
+func dependencyTokens(d *legDependency) []string {
+	if !d.active() {
+		return nil
+	}
+	out := make([]string, 0, len(d.After))
+	for _, t := range d.After {
+		if t = strings.TrimSpace(t); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// prerequisiteStatus sums the plan's orders on token. Replaced rows only
+// count their fills, since their replacement carries the rest of the size.
+func prerequisiteStatus(token string, orders []models.Order) (PrerequisiteStatus, bool) {
+	out := PrerequisiteStatus{TokenID: token, Done: true}
+	filled, size := decimal.Zero, decimal.Zero
+	found := false
+	for _, o := range orders {
+		if o.TokenID != token {
+			continue
+		}
+		found = true
+		filled = filled.Add(o.FilledUSD)
+		switch strings.ToLower(strings.TrimSpace(o.Status)) {
+		case "replaced":
+			size = size.Add(o.FilledUSD)
+		case "filled", "failed", "cancelled":
+			size = size.Add(o.SizeUSD)
+		default:
+			size = size.Add(o.SizeUSD)
+			out.Done = false
+		}
+	}
+	if size.IsPositive() {
+		out.FilledPct = filled.Div(size).InexactFloat64()
+	}
+	return out, found
+}
+
+// legDependencyState decides what to do with a held order whose leg has dep.
+func legDependencyState(dep *legDependency, order models.Order, siblings []models.Order, now time.Time, cfg LegDependencyConfig) LegDependencyStatus {
+	out := LegDependencyStatus{
+		TokenID:     order.TokenID,
+		OrderID:     order.ID,
+		OrderStatus: order.Status,
+		MinFillPct:  dep.minFill(),
+		OnTimeout:   dep.onTimeout(cfg),
+		State:       LegDependencyReady,
+		After:       []PrerequisiteStatus{},
+	}
+	if d := dep.timeout(cfg); d > 0 && !order.CreatedAt.IsZero() {
+		deadline := order.CreatedAt.Add(d)
+		out.Deadline = &deadline
+	}
+	blocked := false
+	for _, token := range dependencyTokens(dep) {
+		st, found := prerequisiteStatus(token, siblings)
+		out.After = append(out.After, st)
+		if st.FilledPct+1e-9 >= out.MinFillPct {
+			continue
+		}
+		if !found || st.Done {
+			blocked = true
+		}
+		if out.State == LegDependencyReady {
+			out.State = LegDependencyWaiting
+		}
+	}
+	switch {
+	case order.Status != OrderStatusHeld:
+		out.State = LegDependencyReleased
+	case blocked:
+		out.State = LegDependencyBlocked
+	case out.State == LegDependencyWaiting && out.Deadline != nil && !now.Before(*out.Deadline):
+		out.State = LegDependencyTimedOut
+	}
+	return out
+}
+
+// PlanLegDependencies reports the state of every dependent leg of plan,
+// given its orders. Plans without dependencies return nothing.
+func PlanLegDependencies(plan models.ExecutionPlan, orders []models.Order, now time.Time, cfg LegDependencyConfig) []LegDependencyStatus {
+	legs, _ := parseOrderLegs(plan.Legs)
+	var out []LegDependencyStatus
+	for _, leg := range legs {
+		if !leg.DependsOn.active() {
+			continue
+		}
+		token := strings.TrimSpace(leg.TokenID)
+		order := models.Order{TokenID: token}
+		for _, o := range orders {
+			if o.TokenID == token && !strings.EqualFold(strings.TrimSpace(o.Status), "replaced") {
+				order = o
+			}
+		}
+		out = append(out, legDependencyState(leg.DependsOn, order, orders, now, cfg))
+	}
+	return out
+}
+
+// releaseHeldOrders submits held orders whose prerequisites have filled,
+// and resolves those that are blocked or timed out.
+func (e *CLOBExecutor) releaseHeldOrders(ctx context.Context) error {
+	held := OrderStatusHeld
+	orders, err := e.Repo.ListOrders(ctx, repository.ListOrdersParams{Limit: 500, Status: &held, OrderBy: "created_at", Asc: boolPtrExecutor(true)})
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, order := range orders {
+		plan, err := e.Repo.GetExecutionPlanByID(ctx, order.PlanID)
+		if err != nil {
+			return err
+		}
+		if plan == nil {
+			continue
+		}
+		leg := planLeg(plan.Legs, order)
+		if !leg.DependsOn.active() {
+			e.releaseHeldOrder(ctx, *plan, order, leg)
+			continue
+		}
+		planID := plan.ID
+		siblings, err := e.Repo.ListOrders(ctx, repository.ListOrdersParams{Limit: 1000, PlanID: &planID})
+		if err != nil {
+			return err
+		}
+		st := legDependencyState(leg.DependsOn, order, siblings, now, e.Config.Dependencies)
+		switch st.State {
+		case LegDependencyReady:
+			e.releaseHeldOrder(ctx, *plan, order, leg)
+		case LegDependencyBlocked:
+			e.dropHeldOrder(ctx, order, "prerequisite leg ended below its fill threshold")
+		case LegDependencyTimedOut:
+			if st.OnTimeout == "submit" {
+				e.releaseHeldOrder(ctx, *plan, order, leg)
+			} else {
+				e.dropHeldOrder(ctx, order, "timed out waiting for prerequisite legs")
+			}
+		}
+	}
+	return nil
+}
+
+func (e *CLOBExecutor) releaseHeldOrder(ctx context.Context, plan models.ExecutionPlan, order models.Order, leg orderLeg) {
+	status, updates, err := e.submitThrottled(ctx, plan, order, leg)
+	if err != nil {
+		_ = e.Repo.UpdateOrderStatus(ctx, order.ID, "failed", map[string]any{"failure_reason": err.Error()})
+		if e.Logger != nil {
+			e.Logger.Warn("held order submit failed", zap.Uint64("order_id", order.ID), zap.Error(err))
+		}
+	} else {
+		_ = e.Repo.UpdateOrderStatus(ctx, order.ID, status, updates)
+		if status == "filled" || status == "partial" {
+			_ = e.applyOrderFillDelta(ctx, order, updates)
+		}
+	}
+	_ = e.reconcilePlanStatus(ctx, order.PlanID)
+}
+
+func (e *CLOBExecutor) dropHeldOrder(ctx context.Context, order models.Order, reason string) {
+	now := time.Now().UTC()
+	_ = e.Repo.UpdateOrderStatus(ctx, order.ID, "cancelled", map[string]any{"cancelled_at": &now, "failure_reason": reason})
+	if e.Logger != nil {
+		e.Logger.Info("held order cancelled", zap.Uint64("order_id", order.ID), zap.String("reason", reason))
+	}
+	_ = e.reconcilePlanStatus(ctx, order.PlanID)
+}
+
+// planLeg picks the plan leg for the order's token, keeping any pre-signed
+// payload since a held order is submitted at its original price and size.
+// Maker orders are posted post-only, as when the plan was submitted.
+func planLeg(raw []byte, order models.Order) orderLeg {
+	leg := orderLeg{TokenID: order.TokenID, Direction: order.Side}
+	legs, _ := parseOrderLegs(raw)
+	for _, l := range legs {
+		if strings.TrimSpace(l.TokenID) == order.TokenID {
+			leg = l
+			break
+		}
+	}
+	if order.PricingMode == PricingModeMaker && leg.PostOnly == nil {
+		leg.PostOnly = boolPtrExecutor(true)
+	}
+	return leg
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestValidateLegDependencies(t *testing.T) {
+	dep := func(after ...string) *legDependency { return &legDependency{After: after} }
+	ok := []childOrder{
+		{TokenID: "a"},
+		{TokenID: "b", Leg: orderLeg{DependsOn: dep("a")}},
+		{TokenID: "c", Leg: orderLeg{DependsOn: dep("a", "b")}},
+	}
+	if err := validateLegDependencies(ok); err != nil {
+		t.Fatalf("valid plan: %v", err)
+	}
+	unknown := []childOrder{{TokenID: "a", Leg: orderLeg{DependsOn: dep("z")}}}
+	if err := validateLegDependencies(unknown); err == nil {
+		t.Fatal("expected unknown leg error")
+	}
+	cycle := []childOrder{
+		{TokenID: "a", Leg: orderLeg{DependsOn: dep("b")}},
+		{TokenID: "b", Leg: orderLeg{DependsOn: dep("a")}},
+	}
+	if err := validateLegDependencies(cycle); err == nil {
+		t.Fatal("expected cycle error")
+	}
+}
+
+func TestLegDependencyState(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := LegDependencyConfig{Timeout: 10 * time.Minute, OnTimeout: "cancel"}
+	held := models.Order{ID: 2, TokenID: "b", Status: OrderStatusHeld, CreatedAt: start}
+	prereq := func(status string, size, filled float64) models.Order {
+		return models.Order{ID: 1, TokenID: "a", Status: status, SizeUSD: decimal.NewFromFloat(size), FilledUSD: decimal.NewFromFloat(filled)}
+	}
+	cases := []struct {
+		name   string
+		dep    legDependency
+		prereq models.Order
+		now    time.Time
+		want   string
+	}{
+		{"waiting", legDependency{After: []string{"a"}}, prereq("partial", 10, 4), start.Add(time.Minute), LegDependencyWaiting},
+		{"ready at threshold", legDependency{After: []string{"a"}, MinFillPct: 0.4}, prereq("partial", 10, 4), start.Add(time.Minute), LegDependencyReady},
+		{"ready when filled", legDependency{After: []string{"a"}}, prereq("filled", 10, 10), start.Add(time.Minute), LegDependencyReady},
+		{"blocked when short", legDependency{After: []string{"a"}}, prereq("cancelled", 10, 4), start.Add(time.Minute), LegDependencyBlocked},
+		{"timed out", legDependency{After: []string{"a"}}, prereq("submitted", 10, 0), start.Add(11 * time.Minute), LegDependencyTimedOut},
+		{"leg timeout", legDependency{After: []string{"a"}, TimeoutSec: 30, OnTimeout: "submit"}, prereq("submitted", 10, 0), start.Add(time.Minute), LegDependencyTimedOut},
+	}
+	for _, tc := range cases {
+		st := legDependencyState(&tc.dep, held, []models.Order{tc.prereq, held}, tc.now, cfg)
+		if st.State != tc.want {
+			t.Fatalf("%s: state=%s want %s (%+v)", tc.name, st.State, tc.want, st)
+		}
+	}
+
+	dep := legDependency{After: []string{"a"}, TimeoutSec: 30, OnTimeout: "submit"}
+	if st := legDependencyState(&dep, held, nil, start, cfg); st.OnTimeout != "submit" {
+		t.Fatalf("on_timeout=%s", st.OnTimeout)
+	}
+	released := held
+	released.Status = "submitted"
+	if st := legDependencyState(&dep, released, []models.Order{prereq("filled", 10, 10)}, start, cfg); st.State != LegDependencyReleased {
+		t.Fatalf("released state=%s", st.State)
+	}
+}
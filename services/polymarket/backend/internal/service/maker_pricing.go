@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+)
+
+// Pricing modes of plan orders. Taker orders are priced at the plan target or
+// best ask; maker orders rest inside the spread and step toward the far touch.
+const (
+	PricingModeTaker = "taker"
+	PricingModeMaker = "maker"
+)
+
+// MakerConfig tunes maker pricing. A plan opts in with params
+// {"pricing_mode":"maker"} and may override each field with
+// maker_improvement, maker_step_size, maker_step_interval_seconds and
+// maker_taker_after_seconds.
+type MakerConfig struct {
+	// Improvement is how far inside the near touch a maker order is posted.
+	Improvement float64
+	// StepSize is how far each reprice moves toward the far touch.
+	StepSize     float64
+	StepInterval time.Duration
+	// TakerAfter crosses the spread once the order has rested this long;
+	// 0 keeps the order passive until cancelled.
+	TakerAfter time.Duration
+	TickSize   float64
+}
+
+type makerPlanParams struct {
+	PricingMode         string   `json:"pricing_mode"`
+	Improvement         *float64 `json:"maker_improvement"`
+	StepSize            *float64 `json:"maker_step_size"`
+	StepIntervalSeconds *float64 `json:"maker_step_interval_seconds"`
+	TakerAfterSeconds   *float64 `json:"maker_taker_after_seconds"`
+	SlippageTolerance   *float64 `json:"slippage_tolerance"`
+}
+
+// planPricing reads the pricing mode of a plan, its maker settings and the
+// slippage tolerance that bounds how far maker orders may chase the book.
+func planPricing(raw []byte, base MakerConfig) (string, MakerConfig, float64) {
+	var p makerPlanParams
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &p)
+	}
+	cfg := base
+	if p.Improvement != nil && *p.Improvement >= 0 {
+		cfg.Improvement = *p.Improvement
+	}
+	if p.StepSize != nil && *p.StepSize > 0 {
+		cfg.StepSize = *p.StepSize
+	}
+	if p.StepIntervalSeconds != nil && *p.StepIntervalSeconds > 0 {
+		cfg.StepInterval = time.Duration(*p.StepIntervalSeconds * float64(time.Second))
+	}
+	if p.TakerAfterSeconds != nil && *p.TakerAfterSeconds >= 0 {
+		cfg.TakerAfter = time.Duration(*p.TakerAfterSeconds * float64(time.Second))
+	}
+	if cfg.TickSize <= 0 {
+		cfg.TickSize = 0.01
+	}
+	if cfg.StepSize <= 0 {
+		cfg.StepSize = cfg.TickSize
+	}
+	slippage := 0.02
+	if p.SlippageTolerance != nil && *p.SlippageTolerance >= 0 {
+		slippage = *p.SlippageTolerance
+	}
+	mode := PricingModeTaker
+	if strings.EqualFold(strings.TrimSpace(p.PricingMode), PricingModeMaker) {
+		mode = PricingModeMaker
+	}
+	return mode, cfg, slippage
+}
+
+// makerBook is the part of the latest book maker pricing looks at.
+type makerBook struct {
+	BestBid float64
+	BestAsk float64
+	Bids    []priceLevel
+	Asks    []priceLevel
+}
+
+func makerBookFromLatest(row models.OrderbookLatest) makerBook {
+	b := makerBook{
+		Bids: parseLevels(json.RawMessage(row.BidsJSON)),
+		Asks: parseLevels(json.RawMessage(row.AsksJSON)),
+	}
+	if row.BestBid != nil {
+		b.BestBid = *row.BestBid
+	}
+	if row.BestAsk != nil {
+		b.BestAsk = *row.BestAsk
+	}
+	return b
+}
+
+func (b makerBook) twoSided() bool {
+	return b.BestBid > 0 && b.BestAsk > 0 && b.BestBid < b.BestAsk
+}
+
+func isSellSide(side string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(side)), "SELL")
+}
+
+// makerLimit is the worst price a maker order may reach: the taker price moved
+// by the plan's slippage tolerance.
+func makerLimit(side string, takerPrice, slippage float64) float64 {
+	if isSellSide(side) {
+		return takerPrice * (1 - slippage)
+	}
+	return takerPrice * (1 + slippage)
+}
+
+// makerEntryPrice posts Improvement inside the near touch without crossing the
+// spread. It reports false for a one-sided book, where taker pricing is used.
+func makerEntryPrice(side string, book makerBook, limit float64, cfg MakerConfig) (float64, bool) {
+	if !book.twoSided() {
+		return 0, false
+	}
+	if isSellSide(side) {
+		p := roundToTick(book.BestAsk-cfg.Improvement, cfg.TickSize)
+		if p <= book.BestBid {
+			p = roundToTick(book.BestBid+cfg.TickSize, cfg.TickSize)
+		}
+		if p >= book.BestAsk {
+			p = book.BestAsk
+		}
+		return math.Max(p, limit), true
+	}
+	p := roundToTick(book.BestBid+cfg.Improvement, cfg.TickSize)
+	if p >= book.BestAsk {
+		p = roundToTick(book.BestAsk-cfg.TickSize, cfg.TickSize)
+	}
+	if p <= book.BestBid {
+		p = book.BestBid
+	}
+	return math.Min(p, limit), true
+}
+
+// queueAhead returns the resting size, in shares, that fills before an order
+// at price: better levels plus the rest of its own level, which it joined last.
+func queueAhead(side string, price float64, book makerBook, ownShares float64) float64 {
+	levels, better := book.Bids, func(p float64) bool { return p > price }
+	if isSellSide(side) {
+		levels, better = book.Asks, func(p float64) bool { return p < price }
+	}
+	ahead := 0.0
+	for _, l := range levels {
+		switch {
+		case samePrice(l.Price, price):
+			ahead += math.Max(l.Size-ownShares, 0)
+		case better(l.Price):
+			ahead += l.Size
+		}
+	}
+	return ahead
+}
+
+// makerAction is a reprice decided for a resting maker order.
+type makerAction struct {
+	Price  float64
+	Taker  bool
+	Reason string
+}
+
+// nextMakerPrice decides whether a maker order moves. Past TakerAfter it
+// crosses to the far touch. Otherwise, once StepInterval has passed since the
+// last reprice, it steps toward the far touch unless it already leads the
+// queue; an order outbid by others jumps back in front. Prices never pass
+// limit, and a step that would reach the far touch becomes the taker fallback.
+func nextMakerPrice(side string, current float64, book makerBook, ahead, limit float64, cfg MakerConfig, sinceReprice, resting time.Duration) (makerAction, bool) {
+	if !book.twoSided() {
+		return makerAction{}, false
+	}
+	sell := isSellSide(side)
+	near, far, dir := book.BestBid, book.BestAsk, 1.0
+	if sell {
+		near, far, dir = book.BestAsk, book.BestBid, -1.0
+	}
+	clamp := func(p float64) float64 {
+		if sell {
+			return math.Max(p, limit)
+		}
+		return math.Min(p, limit)
+	}
+	moves := func(p float64) bool { return (p-current)*dir > 1e-9 }
+
+	if cfg.TakerAfter > 0 && resting >= cfg.TakerAfter {
+		p := clamp(far)
+		if !moves(p) {
+			return makerAction{}, false
+		}
+		return makerAction{Price: p, Taker: samePrice(p, far), Reason: "taker_fallback"}, true
+	}
+	if sinceReprice < cfg.StepInterval {
+		return makerAction{}, false
+	}
+	outbid := (near-current)*dir > 1e-9
+	if !outbid && ahead <= 0 {
+		return makerAction{}, false
+	}
+	p, reason := roundToTick(current+dir*cfg.StepSize, cfg.TickSize), "step"
+	if outbid {
+		if jump := roundToTick(near+dir*cfg.Improvement, cfg.TickSize); (jump-p)*dir > 0 {
+			p, reason = jump, "outbid"
+		}
+	}
+	if (p-far)*dir >= -1e-9 {
+		p = clamp(far)
+		if !moves(p) {
+			return makerAction{}, false
+		}
+		return makerAction{Price: p, Taker: samePrice(p, far), Reason: "taker_fallback"}, true
+	}
+	p = clamp(p)
+	if !moves(p) {
+		return makerAction{}, false
+	}
+	return makerAction{Price: p, Reason: reason}, true
+}
+
+// stepMakerOrders reprices resting maker orders against the latest books.
+// Orders that hold record their queue position for the next poll.
+func (e *CLOBExecutor) stepMakerOrders(ctx context.Context) error {
+	candidates, err := e.listLiveSyncCandidates(ctx)
+	if err != nil {
+		return err
+	}
+	orders := make([]models.Order, 0, len(candidates))
+	tokenIDs := make([]string, 0, len(candidates))
+	for _, o := range candidates {
+		if o.PricingMode == PricingModeMaker && strings.TrimSpace(o.ClobOrderID) != "" {
+			orders = append(orders, o)
+			tokenIDs = append(tokenIDs, o.TokenID)
+		}
+	}
+	if len(orders) == 0 {
+		return nil
+	}
+	rows, err := e.Repo.ListOrderbookLatestByTokenIDs(ctx, tokenIDs)
+	if err != nil {
+		return err
+	}
+	books := make(map[string]makerBook, len(rows))
+	for _, r := range rows {
+		books[r.TokenID] = makerBookFromLatest(r)
+	}
+	plans := map[uint64]*models.ExecutionPlan{}
+	now := time.Now().UTC()
+	for _, order := range orders {
+		book, ok := books[order.TokenID]
+		if !ok || !order.Price.IsPositive() {
+			continue
+		}
+		plan, ok := plans[order.PlanID]
+		if !ok {
+			plan, err = e.Repo.GetExecutionPlanByID(ctx, order.PlanID)
+			if err != nil {
+				return err
+			}
+			plans[order.PlanID] = plan
+		}
+		if plan == nil {
+			continue
+		}
+		_, cfg, slippage := planPricing(plan.Params, e.Config.Maker)
+		limit := makerLimit(order.Side, legTakerPrice(replacementLeg(plan.Legs, order)).InexactFloat64(), slippage)
+		current := order.Price.InexactFloat64()
+		ownShares := order.SizeUSD.Sub(order.FilledUSD).Div(order.Price).InexactFloat64()
+		ahead := queueAhead(order.Side, current, book, ownShares)
+
+		act, ok := nextMakerPrice(order.Side, current, book, ahead, limit, cfg,
+			now.Sub(makerRepricedAt(order)), now.Sub(e.lineageStart(ctx, order)))
+		if !ok {
+			_ = e.Repo.UpdateOrderStatus(ctx, order.ID, order.Status, map[string]any{"queue_ahead": ahead})
+			continue
+		}
+		price := decimal.NewFromFloat(act.Price)
+		mode := PricingModeMaker
+		if act.Taker {
+			mode = PricingModeTaker
+		}
+		res, err := e.AmendOrder(ctx, order.ID, AmendOrderRequest{Price: &price, PricingMode: mode})
+		if err != nil {
+			if e.Logger != nil {
+				e.Logger.Warn("maker reprice failed", zap.Uint64("order_id", order.ID), zap.String("reason", act.Reason), zap.Error(err))
+			}
+			continue
+		}
+		if res == nil || res.Order == nil {
+			continue
+		}
+		_ = e.Repo.UpdateOrderStatus(ctx, res.Order.ID, res.Order.Status, map[string]any{
+			"maker_steps": order.MakerSteps + 1,
+			"repriced_at": &now,
+			"queue_ahead": nil,
+		})
+		if e.Logger != nil {
+			e.Logger.Info("maker order repriced",
+				zap.Uint64("order_id", res.Order.ID),
+				zap.String("reason", act.Reason),
+				zap.Float64("from", current),
+				zap.Float64("to", act.Price),
+				zap.Float64("queue_ahead", ahead),
+			)
+		}
+	}
+	return nil
+}
+
+// lineageStart is when the first order of the lineage was created, so the
+// taker fallback timer survives cancel-and-replace reprices.
+func (e *CLOBExecutor) lineageStart(ctx context.Context, order models.Order) time.Time {
+	if order.Lineage() != order.ID {
+		if first, err := e.Repo.GetOrderByID(ctx, order.Lineage()); err == nil && first != nil {
+			return first.CreatedAt
+		}
+	}
+	return order.CreatedAt
+}
+
+func makerRepricedAt(order models.Order) time.Time {
+	switch {
+	case order.RepricedAt != nil:
+		return *order.RepricedAt
+	case order.SubmittedAt != nil:
+		return *order.SubmittedAt
+	default:
+		return order.CreatedAt
+	}
+}
+
+// legTakerPrice is the price a taker order for the leg is posted at.
+func legTakerPrice(leg orderLeg) decimal.Decimal {
+	if leg.TargetPrice != nil && *leg.TargetPrice > 0 {
+		return decimal.NewFromFloat(*leg.TargetPrice)
+	}
+	if leg.CurrentBestAsk != nil && *leg.CurrentBestAsk > 0 {
+		return decimal.NewFromFloat(*leg.CurrentBestAsk)
+	}
+	return decimal.NewFromFloat(0.5)
+}
+
+func roundToTick(p, tick float64) float64 {
+	if tick <= 0 {
+		return p
+	}
+	// Trim float noise so prices store as clean decimals.
+	return math.Round(math.Round(p/tick)*tick*1e6) / 1e6
+}
+
+func samePrice(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanPricing_ParamsOverrideDefaults(t *testing.T) {
+	base := MakerConfig{Improvement: 0.01, StepSize: 0.01, StepInterval: 30 * time.Second, TakerAfter: 5 * time.Minute}
+	mode, cfg, slip := planPricing([]byte(`{"pricing_mode":"Maker","maker_improvement":0.02,"maker_step_interval_seconds":10,"slippage_tolerance":0.05}`), base)
+	if mode != PricingModeMaker || cfg.Improvement != 0.02 || cfg.StepInterval != 10*time.Second || cfg.TakerAfter != 5*time.Minute || slip != 0.05 {
+		t.Fatalf("mode=%s cfg=%+v slip=%v", mode, cfg, slip)
+	}
+	if mode, _, _ := planPricing([]byte(`{"limit_vs_market":"limit"}`), base); mode != PricingModeTaker {
+		t.Fatalf("mode=%s want taker", mode)
+	}
+}
+
+func TestMakerEntryPrice(t *testing.T) {
+	cfg := MakerConfig{Improvement: 0.01, TickSize: 0.01}
+	book := makerBook{BestBid: 0.40, BestAsk: 0.45}
+	if p, ok := makerEntryPrice("BUY_YES", book, 0.46, cfg); !ok || !samePrice(p, 0.41) {
+		t.Fatalf("buy p=%v ok=%v", p, ok)
+	}
+	if p, _ := makerEntryPrice("SELL_YES", book, 0.30, cfg); !samePrice(p, 0.44) {
+		t.Fatalf("sell p=%v", p)
+	}
+	// A one-tick spread joins the bid rather than crossing.
+	if p, _ := makerEntryPrice("BUY_YES", makerBook{BestBid: 0.40, BestAsk: 0.41}, 0.46, cfg); !samePrice(p, 0.40) {
+		t.Fatalf("tight p=%v", p)
+	}
+	if _, ok := makerEntryPrice("BUY_YES", makerBook{BestBid: 0.40}, 0.46, cfg); ok {
+		t.Fatalf("one-sided book must fall back to taker")
+	}
+}
+
+func TestQueueAhead(t *testing.T) {
+	book := makerBook{Bids: []priceLevel{{Price: 0.42, Size: 50}, {Price: 0.41, Size: 120}, {Price: 0.40, Size: 300}}}
+	if got := queueAhead("BUY_YES", 0.41, book, 100); got != 70 {
+		t.Fatalf("ahead=%v want 70", got)
+	}
+}
+
+func TestNextMakerPrice(t *testing.T) {
+	cfg := MakerConfig{Improvement: 0.01, StepSize: 0.01, StepInterval: 30 * time.Second, TakerAfter: 5 * time.Minute, TickSize: 0.01}
+	book := makerBook{BestBid: 0.41, BestAsk: 0.45}
+
+	if _, ok := nextMakerPrice("BUY_YES", 0.41, book, 10, 0.46, cfg, 10*time.Second, time.Minute); ok {
+		t.Fatalf("stepped before the step interval")
+	}
+	if _, ok := nextMakerPrice("BUY_YES", 0.41, book, 0, 0.46, cfg, time.Minute, time.Minute); ok {
+		t.Fatalf("stepped while leading the queue")
+	}
+	act, ok := nextMakerPrice("BUY_YES", 0.41, book, 10, 0.46, cfg, time.Minute, time.Minute)
+	if !ok || act.Taker || !samePrice(act.Price, 0.42) {
+		t.Fatalf("step=%+v ok=%v", act, ok)
+	}
+	act, _ = nextMakerPrice("BUY_YES", 0.40, makerBook{BestBid: 0.42, BestAsk: 0.45}, 0, 0.46, cfg, time.Minute, time.Minute)
+	if act.Reason != "outbid" || !samePrice(act.Price, 0.43) {
+		t.Fatalf("outbid=%+v", act)
+	}
+	act, _ = nextMakerPrice("BUY_YES", 0.44, book, 10, 0.46, cfg, time.Minute, time.Minute)
+	if !act.Taker || !samePrice(act.Price, 0.45) {
+		t.Fatalf("step into ask=%+v", act)
+	}
+	act, _ = nextMakerPrice("BUY_YES", 0.41, book, 0, 0.46, cfg, 0, 6*time.Minute)
+	if !act.Taker || act.Reason != "taker_fallback" {
+		t.Fatalf("fallback=%+v", act)
+	}
+	// The fallback never pays past the slippage limit.
+	act, _ = nextMakerPrice("BUY_YES", 0.41, book, 0, 0.43, cfg, 0, 6*time.Minute)
+	if act.Taker || !samePrice(act.Price, 0.43) {
+		t.Fatalf("capped fallback=%+v", act)
+	}
+	act, _ = nextMakerPrice("SELL_YES", 0.44, book, 10, 0.30, cfg, time.Minute, time.Minute)
+	if !samePrice(act.Price, 0.43) {
+		t.Fatalf("sell step=%+v", act)
+	}
+}
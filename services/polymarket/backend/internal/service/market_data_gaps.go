@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/client/polymarket/clob"
+	"polymarket/internal/config"
+	"polymarket/internal/governor"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// UpdateGap is a silence between two received updates of one token.
+type UpdateGap struct {
+	Start    time.Time
+	End      time.Time
+	Expected time.Duration
+}
+
+// DetectUpdateGaps finds silences between consecutive update times that exceed
+// both minGap and factor times the median interval. Tokens with fewer than
+// minSamples updates have no reliable cadence and yield no gaps.
+func DetectUpdateGaps(times []time.Time, minGap time.Duration, factor float64, minSamples int) []UpdateGap {
+	if minSamples < 2 {
+		minSamples = 2
+	}
+	if len(times) < minSamples {
+		return nil
+	}
+	sorted := append([]time.Time(nil), times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	intervals := make([]time.Duration, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		intervals = append(intervals, sorted[i].Sub(sorted[i-1]))
+	}
+	ordered := append([]time.Duration(nil), intervals...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i] < ordered[j] })
+	expected := ordered[len(ordered)/2]
+	if factor <= 1 {
+		factor = 5
+	}
+	threshold := time.Duration(float64(expected) * factor)
+	if threshold < minGap {
+		threshold = minGap
+	}
+	var out []UpdateGap
+	for i, d := range intervals {
+		if d > threshold {
+			out = append(out, UpdateGap{Start: sorted[i], End: sorted[i+1], Expected: expected})
+		}
+	}
+	return out
+}
+
+// PriceCandlesFromPoints buckets REST price history into one-minute candles.
+func PriceCandlesFromPoints(tokenID string, points []clob.PricePoint, source string) []models.PriceCandle {
+	byBucket := map[time.Time]*models.PriceCandle{}
+	for _, p := range points {
+		price, _ := p.Price.Float64()
+		if price <= 0 || p.TS.IsZero() {
+			continue
+		}
+		ts := p.TS.UTC()
+		bucket := ts.Truncate(time.Minute)
+		c := byBucket[bucket]
+		if c == nil {
+			byBucket[bucket] = &models.PriceCandle{
+				TokenID: tokenID, BucketStart: bucket,
+				Open: price, High: price, Low: price, Close: price,
+				OpenTS: ts, CloseTS: ts, Points: 1, Source: source,
+			}
+			continue
+		}
+		mergePriceCandlePoint(c, ts, price)
+	}
+	out := make([]models.PriceCandle, 0, len(byBucket))
+	for _, c := range byBucket {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].BucketStart.Before(out[j].BucketStart) })
+	return out
+}
+
+func mergePriceCandlePoint(c *models.PriceCandle, ts time.Time, price float64) {
+	if ts.Before(c.OpenTS) {
+		c.Open, c.OpenTS = price, ts
+	}
+	if !ts.Before(c.CloseTS) {
+		c.Close, c.CloseTS = price, ts
+	}
+	if price > c.High {
+		c.High = price
+	}
+	if price < c.Low {
+		c.Low = price
+	}
+	c.Points++
+}
+
+// MarketDataGapService detects holes in the CLOB stream and patches the candle
+// store from the REST price history.
+type MarketDataGapService struct {
+	Repo   repository.Repository
+	Clob   *clob.Client
+	Config config.MarketDataGapsConfig
+	Logger *zap.Logger
+	Flags  *SystemSettingsService
+	// Governor, when set, admits each scheduled scan as a backfill job.
+	Governor *governor.Governor
+}
+
+type MarketDataGapRunResult struct {
+	TokensScanned int `json:"tokens_scanned"`
+	GapsDetected  int `json:"gaps_detected"`
+	Backfilled    int `json:"backfilled"`
+	Failed        int `json:"failed"`
+}
+
+func (s *MarketDataGapService) Run(ctx context.Context) error {
+	if s == nil || s.Repo == nil {
+		return nil
+	}
+	interval := s.Config.ScanInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if s.Flags != nil && !s.Flags.IsEnabled(ctx, FeatureMarketDataBackfill, true) {
+				continue
+			}
+			err := s.Governor.Run(ctx, governor.ClassBackfill, func(ctx context.Context) error {
+				_, err := s.RunOnce(ctx)
+				return err
+			})
+			if err != nil {
+				s.logWarn("market data gap scan failed", err)
+			}
+		}
+	}
+}
+
+func (s *MarketDataGapService) RunOnce(ctx context.Context) (MarketDataGapRunResult, error) {
+	var out MarketDataGapRunResult
+	if s == nil || s.Repo == nil {
+		return out, nil
+	}
+	scanned, detected, err := s.Detect(ctx, time.Now().UTC())
+	out.TokensScanned, out.GapsDetected = scanned, detected
+	if err != nil {
+		return out, err
+	}
+	out.Backfilled, out.Failed, err = s.Backfill(ctx)
+	return out, err
+}
+
+// Detect scans the received WS update times of recently streamed tokens and
+// records new gaps. It returns the tokens scanned and gaps newly stored.
+func (s *MarketDataGapService) Detect(ctx context.Context, now time.Time) (int, int, error) {
+	lookback := s.Config.Lookback
+	if lookback <= 0 {
+		lookback = 6 * time.Hour
+	}
+	since := now.Add(-lookback)
+	tokenIDs, err := s.Repo.ListRawWSEventTokenIDs(ctx, since, s.Config.MaxTokens)
+	if err != nil {
+		return 0, 0, err
+	}
+	minSamples := s.Config.MinSamples
+	if minSamples <= 0 {
+		minSamples = 20
+	}
+	minGap := s.Config.MinGap
+	if minGap <= 0 {
+		minGap = 2 * time.Minute
+	}
+	detected := 0
+	for _, tokenID := range tokenIDs {
+		if err := governor.Yield(ctx); err != nil {
+			return len(tokenIDs), detected, err
+		}
+		times, err := s.Repo.ListRawWSEventTimes(ctx, tokenID, since, now)
+		if err != nil {
+			return len(tokenIDs), detected, err
+		}
+		for _, gap := range DetectUpdateGaps(times, minGap, s.Config.GapFactor, minSamples) {
+			created, err := s.Repo.InsertMarketDataGap(ctx, &models.MarketDataGap{
+				TokenID:         tokenID,
+				Start:           gap.Start.UTC(),
+				End:             gap.End.UTC(),
+				ExpectedSeconds: gap.Expected.Seconds(),
+				Status:          "open",
+			})
+			if err != nil {
+				return len(tokenIDs), detected, err
+			}
+			if created {
+				detected++
+			}
+		}
+	}
+	return len(tokenIDs), detected, nil
+}
+
+// Backfill fetches the REST price history for open gaps, oldest first, and
+// merges it into the candle store.
+func (s *MarketDataGapService) Backfill(ctx context.Context) (int, int, error) {
+	if s.Clob == nil {
+		return 0, 0, nil
+	}
+	maxAttempts := s.Config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	status := "open"
+	gaps, err := s.Repo.ListMarketDataGaps(ctx, repository.ListMarketDataGapsParams{
+		Limit:       s.Config.BackfillBatch,
+		Status:      &status,
+		MaxAttempts: maxAttempts,
+		OldestFirst: true,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	backfilled, failed := 0, 0
+	for i := range gaps {
+		if err := governor.Yield(ctx); err != nil {
+			return backfilled, failed, err
+		}
+		if err := s.BackfillGap(ctx, &gaps[i], maxAttempts); err != nil {
+			failed++
+			s.logWarn("market data gap backfill failed", err, zap.String("token_id", gaps[i].TokenID), zap.Uint64("gap_id", gaps[i].ID))
+			continue
+		}
+		backfilled++
+	}
+	return backfilled, failed, nil
+}
+
+// BackfillGap patches one gap. Failures are recorded on the gap and it is
+// marked failed once maxAttempts is reached.
+func (s *MarketDataGapService) BackfillGap(ctx context.Context, gap *models.MarketDataGap, maxAttempts int) error {
+	gap.Attempts++
+	startTs := gap.Start.Unix()
+	endTs := gap.End.Unix()
+	points, err := s.Clob.GetPriceHistory(ctx, gap.TokenID, "", &startTs, &endTs)
+	if err == nil {
+		inGap := points[:0]
+		for _, p := range points {
+			if !p.TS.Before(gap.Start) && !p.TS.After(gap.End) {
+				inGap = append(inGap, p)
+			}
+		}
+		err = s.Repo.UpsertPriceCandles(ctx, PriceCandlesFromPoints(gap.TokenID, inGap, "rest_backfill"))
+		if err == nil {
+			now := time.Now().UTC()
+			gap.Status = "backfilled"
+			gap.BackfilledPoints = len(inGap)
+			gap.BackfilledAt = &now
+			gap.LastError = ""
+			return s.Repo.UpdateMarketDataGap(ctx, gap)
+		}
+	}
+	gap.LastError = strings.TrimSpace(err.Error())
+	if gap.Attempts >= maxAttempts {
+		gap.Status = "failed"
+	}
+	if uerr := s.Repo.UpdateMarketDataGap(ctx, gap); uerr != nil {
+		return uerr
+	}
+	return err
+}
+
+func (s *MarketDataGapService) logWarn(msg string, err error, fields ...zap.Field) {
+	if s == nil || s.Logger == nil {
+		return
+	}
+	s.Logger.Warn(msg, append(fields, zap.Error(err))...)
+}
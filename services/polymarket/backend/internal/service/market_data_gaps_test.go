@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/client/polymarket/clob"
+)
+
+func TestDetectUpdateGaps(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var times []time.Time
+	for i := 0; i < 30; i++ {
+		times = append(times, base.Add(time.Duration(i)*10*time.Second))
+	}
+	// 20 minute disconnect, then the stream resumes.
+	resume := times[len(times)-1].Add(20 * time.Minute)
+	for i := 0; i < 30; i++ {
+		times = append(times, resume.Add(time.Duration(i)*10*time.Second))
+	}
+
+	gaps := DetectUpdateGaps(times, 2*time.Minute, 5, 20)
+	if len(gaps) != 1 {
+		t.Fatalf("gaps=%+v", gaps)
+	}
+	if !gaps[0].End.Equal(resume) || gaps[0].Expected != 10*time.Second {
+		t.Fatalf("gap=%+v", gaps[0])
+	}
+	if got := DetectUpdateGaps(times[:10], 2*time.Minute, 5, 20); got != nil {
+		t.Fatalf("expected no gaps below min samples, got %+v", got)
+	}
+}
+
+func TestPriceCandlesFromPoints(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	points := []clob.PricePoint{
+		{TS: base.Add(40 * time.Second), Price: decimal.RequireFromString("0.52")},
+		{TS: base.Add(5 * time.Second), Price: decimal.RequireFromString("0.50")},
+		{TS: base.Add(20 * time.Second), Price: decimal.RequireFromString("0.55")},
+		{TS: base.Add(70 * time.Second), Price: decimal.RequireFromString("0.60")},
+	}
+	candles := PriceCandlesFromPoints("tok", points, "rest_backfill")
+	if len(candles) != 2 {
+		t.Fatalf("candles=%+v", candles)
+	}
+	c := candles[0]
+	if c.Open != 0.50 || c.Close != 0.52 || c.High != 0.55 || c.Low != 0.50 || c.Points != 3 {
+		t.Fatalf("first=%+v", c)
+	}
+	if !candles[1].BucketStart.Equal(base.Add(time.Minute)) || candles[1].Points != 1 {
+		t.Fatalf("second=%+v", candles[1])
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MarketThrottle serializes order submissions per market and spaces them by
+// MinInterval, so plans that target the same market at the same time do not
+// race each other on the book. Orders waiting on a market can also be merged:
+// whichever gets the market first submits the others with it (see
+// AcquireMerge). A nil throttle does not throttle.
+type MarketThrottle struct {
+	MinInterval time.Duration
+
+	mu        sync.Mutex
+	slots     map[string]*marketSlot
+	lastPrune time.Time
+}
+
+type marketSlot struct {
+	// lock holds one token while a submission owns the market.
+	lock chan struct{}
+	last time.Time
+	// refs counts the callers holding or waiting on the slot; idle slots
+	// are pruned.
+	refs    int
+	waiting []*MergeWaiter
+}
+
+// MarketThrottleStats is exposed for health endpoints.
+type MarketThrottleStats struct {
+	Markets     int    `json:"markets"`
+	Busy        int    `json:"busy"`
+	MinInterval string `json:"min_interval"`
+}
+
+// marketSlotIdleAfter is how long an unused slot is kept beyond MinInterval.
+const marketSlotIdleAfter = time.Minute
+
+// MergeWaiter is an order waiting on a market that another waiter with the
+// same Key may submit on its behalf. The submitter claims it, then calls
+// Finish with the outcome of the shared submission.
+type MergeWaiter struct {
+	Key     string
+	OrderID uint64
+	SizeUSD decimal.Decimal
+
+	claimed chan struct{}
+	done    chan struct{}
+	result  MergeResult
+}
+
+// MergeResult is the outcome a claimed waiter receives. LeaderID is the order
+// that submitted it and TotalUSD the size of the shared submission.
+type MergeResult struct {
+	LeaderID uint64
+	TotalUSD decimal.Decimal
+	Status   string
+	Updates  map[string]any
+	Err      error
+}
+
+func NewMergeWaiter(key string, orderID uint64, sizeUSD decimal.Decimal) *MergeWaiter {
+	return &MergeWaiter{Key: key, OrderID: orderID, SizeUSD: sizeUSD, claimed: make(chan struct{}), done: make(chan struct{})}
+}
+
+// Finish hands a claimed waiter its result.
+func (w *MergeWaiter) Finish(res MergeResult) {
+	w.result = res
+	close(w.done)
+}
+
+func NewMarketThrottle(minInterval time.Duration) *MarketThrottle {
+	return &MarketThrottle{MinInterval: minInterval, slots: map[string]*marketSlot{}}
+}
+
+// Acquire waits until the market is free and MinInterval has passed since its
+// last submission. The returned release must be called once the submission is
+// done; it records the submission time.
+func (t *MarketThrottle) Acquire(ctx context.Context, market string) (func(), error) {
+	release, _, _, err := t.AcquireMerge(ctx, market, nil, decimal.Zero)
+	return release, err
+}
+
+// AcquireMerge is Acquire for an order that other waiters on the market may
+// submit: when one of them gets the market first and claims w, AcquireMerge
+// returns w's result instead of the market. Otherwise it returns release and
+// the waiters with w's key it claimed, up to maxUSD in total (0 is
+// unlimited), which the caller must Finish. A nil w is never merged.
+func (t *MarketThrottle) AcquireMerge(ctx context.Context, market string, w *MergeWaiter, maxUSD decimal.Decimal) (func(), []*MergeWaiter, *MergeResult, error) {
+	market = strings.TrimSpace(market)
+	if t == nil || market == "" {
+		return func() {}, nil, nil, nil
+	}
+	slot := t.slot(market, w)
+	claimedBy := func() *MergeResult {
+		<-w.done
+		res := w.result
+		return &res
+	}
+	var claimedCh <-chan struct{}
+	if w != nil {
+		claimedCh = w.claimed
+	}
+	select {
+	case slot.lock <- struct{}{}:
+	case <-claimedCh:
+		t.unref(slot, nil)
+		return nil, nil, claimedBy(), nil
+	case <-ctx.Done():
+		if t.unref(slot, w) {
+			return nil, nil, claimedBy(), nil
+		}
+		return nil, nil, nil, ctx.Err()
+	}
+	if w != nil && isClosed(w.claimed) {
+		// Claimed by the previous holder just before it let go.
+		<-slot.lock
+		t.unref(slot, nil)
+		return nil, nil, claimedBy(), nil
+	}
+	t.mu.Lock()
+	wait := time.Until(slot.last.Add(t.MinInterval))
+	t.mu.Unlock()
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			<-slot.lock
+			t.unref(slot, w)
+			return nil, nil, nil, ctx.Err()
+		}
+	}
+	followers := t.claim(slot, w, maxUSD)
+	release := func() {
+		t.mu.Lock()
+		slot.last = time.Now()
+		t.mu.Unlock()
+		<-slot.lock
+		t.unref(slot, nil)
+	}
+	return release, followers, nil, nil
+}
+
+// claim removes w from the slot's waiters and claims the others with its key.
+func (t *MarketThrottle) claim(slot *marketSlot, w *MergeWaiter, maxUSD decimal.Decimal) []*MergeWaiter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if w == nil {
+		return nil
+	}
+	slot.waiting = removeWaiter(slot.waiting, w)
+	total := w.SizeUSD
+	var out []*MergeWaiter
+	rest := slot.waiting[:0]
+	for _, o := range slot.waiting {
+		sum := total.Add(o.SizeUSD)
+		if o.Key != w.Key || (maxUSD.IsPositive() && sum.GreaterThan(maxUSD)) {
+			rest = append(rest, o)
+			continue
+		}
+		total = sum
+		close(o.claimed)
+		out = append(out, o)
+	}
+	slot.waiting = rest
+	return out
+}
+
+func (t *MarketThrottle) slot(market string, w *MergeWaiter) *marketSlot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.slots == nil {
+		t.slots = map[string]*marketSlot{}
+	}
+	t.pruneLocked(time.Now())
+	s := t.slots[market]
+	if s == nil {
+		s = &marketSlot{lock: make(chan struct{}, 1)}
+		t.slots[market] = s
+	}
+	s.refs++
+	if w != nil {
+		s.waiting = append(s.waiting, w)
+	}
+	return s
+}
+
+// unref drops a caller from the slot and, if w is still waiting, withdraws
+// it. It reports whether w had been claimed and so must take its result.
+func (t *MarketThrottle) unref(slot *marketSlot, w *MergeWaiter) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	slot.refs--
+	if w == nil {
+		return false
+	}
+	if isClosed(w.claimed) {
+		return true
+	}
+	slot.waiting = removeWaiter(slot.waiting, w)
+	return false
+}
+
+// pruneLocked drops slots nobody holds or waits on whose last submission no
+// longer spaces the next one, at most once a minute.
+func (t *MarketThrottle) pruneLocked(now time.Time) {
+	if now.Sub(t.lastPrune) < marketSlotIdleAfter {
+		return
+	}
+	t.lastPrune = now
+	for market, s := range t.slots {
+		if s.refs == 0 && now.Sub(s.last) >= t.MinInterval+marketSlotIdleAfter {
+			delete(t.slots, market)
+		}
+	}
+}
+
+func removeWaiter(items []*MergeWaiter, w *MergeWaiter) []*MergeWaiter {
+	for i, it := range items {
+		if it == w {
+			return append(items[:i], items[i+1:]...)
+		}
+	}
+	return items
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *MarketThrottle) Stats() MarketThrottleStats {
+	if t == nil {
+		return MarketThrottleStats{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := MarketThrottleStats{Markets: len(t.slots), MinInterval: t.MinInterval.String()}
+	for _, s := range t.slots {
+		if len(s.lock) > 0 {
+			out.Busy++
+		}
+	}
+	return out
+}
+
+// childOrder is one order a plan leg resolves to before it is stored.
+type childOrder struct {
+	Leg     orderLeg
+	TokenID string
+	Side    string
+	Price   decimal.Decimal
+	SizeUSD decimal.Decimal
+	// PricingMode is PricingModeTaker or PricingModeMaker.
+	PricingMode string
+}
+
+// mergeChildOrders folds same-token, same-side, same-price children into one
+// order so a plan does not queue against itself. Pre-signed legs commit to
+// their size and are never merged, nor are legs that wait on other legs or a
+// merge that would exceed maxSize.
+func mergeChildOrders(children []childOrder, maxSize decimal.Decimal) []childOrder {
+	out := make([]childOrder, 0, len(children))
+	for _, c := range children {
+		merged := false
+		if c.Leg.SignedOrder == nil && !c.Leg.DependsOn.active() {
+			for i := range out {
+				o := &out[i]
+				if o.Leg.SignedOrder != nil || o.Leg.DependsOn.active() || o.TokenID != c.TokenID || o.Side != c.Side || o.PricingMode != c.PricingMode || !o.Price.Equal(c.Price) {
+					continue
+				}
+				sum := o.SizeUSD.Add(c.SizeUSD)
+				if maxSize.GreaterThan(decimal.Zero) && sum.GreaterThan(maxSize) {
+					continue
+				}
+				o.SizeUSD = sum
+				if o.Leg.SizeUSD != nil {
+					v := sum.InexactFloat64()
+					o.Leg.SizeUSD = &v
+				}
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			out = append(out, c)
+		}
+	}
+	return out
+}
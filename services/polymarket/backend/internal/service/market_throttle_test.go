@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMarketThrottle_SerializesAndSpacesSubmissions(t *testing.T) {
+	th := NewMarketThrottle(30 * time.Millisecond)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var active, maxActive int
+	var starts []time.Time
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := th.Acquire(ctx, "m1")
+			if err != nil {
+				t.Errorf("acquire: %v", err)
+				return
+			}
+			mu.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			starts = append(starts, time.Now())
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			active--
+			mu.Unlock()
+			release()
+		}()
+	}
+	wg.Wait()
+	if maxActive != 1 {
+		t.Fatalf("max concurrent=%d", maxActive)
+	}
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap < 30*time.Millisecond {
+			t.Fatalf("submissions %d and %d only %s apart", i-1, i, gap)
+		}
+	}
+
+	// Other markets are not held up.
+	release, err := th.Acquire(ctx, "m2")
+	if err != nil {
+		t.Fatalf("acquire m2: %v", err)
+	}
+	release()
+}
+
+func TestMarketThrottle_AcquireHonorsContext(t *testing.T) {
+	th := NewMarketThrottle(0)
+	release, _ := th.Acquire(context.Background(), "m1")
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := th.Acquire(ctx, "m1"); err == nil {
+		t.Fatalf("expected timeout while market is busy")
+	}
+}
+
+func TestMergeChildOrders(t *testing.T) {
+	p := decimal.NewFromFloat(0.4)
+	children := []childOrder{
+		{TokenID: "t1", Side: "BUY_YES", Price: p, SizeUSD: decimal.NewFromInt(10)},
+		{TokenID: "t1", Side: "BUY_YES", Price: p, SizeUSD: decimal.NewFromInt(15)},
+		{TokenID: "t1", Side: "SELL_YES", Price: p, SizeUSD: decimal.NewFromInt(5)},
+		{TokenID: "t1", Side: "BUY_YES", Price: p, SizeUSD: decimal.NewFromInt(20), Leg: orderLeg{SignedOrder: map[string]any{"salt": "1"}}},
+	}
+	got := mergeChildOrders(children, decimal.Zero)
+	if len(got) != 3 || !got[0].SizeUSD.Equal(decimal.NewFromInt(25)) {
+		t.Fatalf("merged=%+v", got)
+	}
+
+	capped := mergeChildOrders(children[:2], decimal.NewFromInt(20))
+	if len(capped) != 2 {
+		t.Fatalf("merge over max size: %+v", capped)
+	}
+}
+
+func TestMarketThrottle_MergesWaitersAcrossOrders(t *testing.T) {
+	th := NewMarketThrottle(0)
+	ctx := context.Background()
+	hold, _ := th.Acquire(ctx, "m1")
+
+	type outcome struct {
+		followers []*MergeWaiter
+		merged    *MergeResult
+		release   func()
+	}
+	waiters := []*MergeWaiter{
+		NewMergeWaiter("t1|BUY", 1, decimal.NewFromInt(10)),
+		NewMergeWaiter("t1|BUY", 2, decimal.NewFromInt(30)),
+		NewMergeWaiter("t1|SELL", 3, decimal.NewFromInt(5)),
+	}
+	results := make([]outcome, len(waiters))
+	var wg sync.WaitGroup
+	for i, w := range waiters {
+		wg.Add(1)
+		go func(i int, w *MergeWaiter) {
+			defer wg.Done()
+			release, followers, merged, err := th.AcquireMerge(ctx, "m1", w, decimal.Zero)
+			if err != nil {
+				t.Errorf("acquire %d: %v", i, err)
+				return
+			}
+			results[i] = outcome{followers: followers, merged: merged, release: release}
+			for _, f := range followers {
+				f.Finish(MergeResult{LeaderID: w.OrderID, TotalUSD: decimal.NewFromInt(40), Status: "submitted"})
+			}
+			if release != nil {
+				release()
+			}
+		}(i, w)
+	}
+	for {
+		th.mu.Lock()
+		n := len(th.slots["m1"].waiting)
+		th.mu.Unlock()
+		if n == len(waiters) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	hold()
+	wg.Wait()
+
+	leader, follower := results[0], results[1]
+	if leader.merged != nil {
+		leader, follower = follower, leader
+	}
+	if len(leader.followers) != 1 || follower.merged == nil || follower.merged.Status != "submitted" {
+		t.Fatalf("leader=%+v follower=%+v", leader, follower)
+	}
+	if results[2].merged != nil || len(results[2].followers) != 0 {
+		t.Fatalf("other side merged: %+v", results[2])
+	}
+}
+
+func TestMarketThrottle_PrunesIdleSlots(t *testing.T) {
+	th := NewMarketThrottle(0)
+	ctx := context.Background()
+	release, _ := th.Acquire(ctx, "m1")
+	release()
+	held, _ := th.Acquire(ctx, "m2")
+	defer held()
+
+	th.mu.Lock()
+	th.lastPrune = time.Time{}
+	th.slots["m1"].last = time.Now().Add(-2 * marketSlotIdleAfter)
+	th.slots["m2"].last = time.Now().Add(-2 * marketSlotIdleAfter)
+	th.mu.Unlock()
+
+	release, _ = th.Acquire(ctx, "m3")
+	release()
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	if _, ok := th.slots["m1"]; ok {
+		t.Fatalf("idle slot kept")
+	}
+	if _, ok := th.slots["m2"]; !ok {
+		t.Fatalf("held slot pruned")
+	}
+}
+
+func TestMergedShare(t *testing.T) {
+	updates := map[string]any{"clob_order_id": "0xabc", "filled_usd": decimal.NewFromInt(30)}
+	got := mergedShare(updates, decimal.NewFromInt(10), decimal.NewFromInt(40), 7)
+	if !got["filled_usd"].(decimal.Decimal).Equal(decimal.NewFromFloat(7.5)) || got["merged_into_id"] != uint64(7) || got["clob_order_id"] != "0xabc" {
+		t.Fatalf("share=%v", got)
+	}
+	if !updates["filled_usd"].(decimal.Decimal).Equal(decimal.NewFromInt(30)) {
+		t.Fatalf("updates modified: %v", updates)
+	}
+}
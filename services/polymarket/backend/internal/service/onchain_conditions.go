@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/client/polygon"
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// ConditionReader reads a CTF condition; *polygon.CTFReader in production.
+type ConditionReader interface {
+	Condition(ctx context.Context, conditionID string) (*polygon.Condition, error)
+}
+
+// OnchainConditionService mirrors the CTF condition state of open catalog
+// markets so resolutions are seen onchain, before Gamma closes the market.
+type OnchainConditionService struct {
+	Repo   repository.Repository
+	Reader ConditionReader
+	Config config.OnchainConfig
+	Logger *zap.Logger
+	// Notify routes resolution alerts to the notification dispatcher; nil
+	// broadcasts through the platform client in ctx.
+	Notify func(ctx context.Context, event, message string) error
+}
+
+// OnchainSyncResult counts one pass over markets.
+type OnchainSyncResult struct {
+	Checked  int      `json:"checked"`
+	Resolved []string `json:"resolved"`
+	Failed   int      `json:"failed"`
+}
+
+// RunOnce checks the next batch of open markets.
+func (s *OnchainConditionService) RunOnce(ctx context.Context, now time.Time) (OnchainSyncResult, error) {
+	if s == nil || s.Repo == nil || s.Reader == nil || !s.Config.Enabled {
+		return OnchainSyncResult{}, nil
+	}
+	markets, err := s.Repo.ListMarketsForOnchainCheck(ctx, s.Config.BatchSize)
+	if err != nil {
+		return OnchainSyncResult{}, err
+	}
+	return s.Check(ctx, markets, now)
+}
+
+// Check reads and stores the condition of each market. A failed read is
+// counted and skipped so one bad condition does not stall the batch.
+func (s *OnchainConditionService) Check(ctx context.Context, markets []models.Market, now time.Time) (OnchainSyncResult, error) {
+	out := OnchainSyncResult{Resolved: []string{}}
+	if s == nil || s.Repo == nil || s.Reader == nil {
+		return out, errors.New("onchain reader unavailable")
+	}
+	for _, m := range markets {
+		if strings.TrimSpace(m.ConditionID) == "" {
+			continue
+		}
+		cond, err := s.Reader.Condition(ctx, m.ConditionID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return out, ctx.Err()
+			}
+			out.Failed++
+			if s.Logger != nil {
+				s.Logger.Warn("onchain condition read failed", zap.String("market_id", m.ID), zap.Error(err))
+			}
+			continue
+		}
+		state := repository.OnchainConditionState{OutcomeSlots: cond.OutcomeSlotCount, CheckedAt: now.UTC()}
+		if cond.Resolved() {
+			state.Payouts = conditionPayouts(cond)
+		}
+		if err := s.Repo.SaveOnchainCondition(ctx, m.ID, state); err != nil {
+			return out, err
+		}
+		out.Checked++
+		if len(state.Payouts) > 0 && m.OnchainResolvedAt == nil {
+			out.Resolved = append(out.Resolved, m.ID)
+			if !m.Closed {
+				s.warnResolved(ctx, m, state.Payouts)
+			}
+		}
+	}
+	return out, nil
+}
+
+// conditionPayouts is each slot's numerator over the denominator.
+func conditionPayouts(cond *polygon.Condition) []decimal.Decimal {
+	den := decimal.NewFromBigInt(cond.PayoutDenominator, 0)
+	out := make([]decimal.Decimal, 0, len(cond.PayoutNumerators))
+	for _, num := range cond.PayoutNumerators {
+		out = append(out, decimal.NewFromBigInt(num, 0).DivRound(den, 10))
+	}
+	return out
+}
+
+// warnResolved reports a market resolved onchain that Gamma has not closed.
+func (s *OnchainConditionService) warnResolved(ctx context.Context, m models.Market, payouts []decimal.Decimal) {
+	shares := make([]string, 0, len(payouts))
+	for _, p := range payouts {
+		shares = append(shares, p.String())
+	}
+	if s.Logger != nil {
+		s.Logger.Info("market resolved onchain",
+			zap.String("market_id", m.ID),
+			zap.String("condition_id", m.ConditionID),
+			zap.Strings("payouts", shares),
+		)
+	}
+	paas.LogBestEffortCtx(ctx, "polymarket_onchain_resolution", "info", map[string]any{
+		"market_id":    m.ID,
+		"condition_id": m.ConditionID,
+		"payouts":      shares,
+	})
+	notify := s.Notify
+	if notify == nil {
+		notify = paas.BroadcastCtx
+	}
+	message := fmt.Sprintf("[info] polymarket market %s resolved onchain with payouts [%s] while Gamma still lists it open: %s",
+		m.ID, strings.Join(shares, ", "), m.Question)
+	if err := notify(ctx, s.Config.NotifyEvent, message); err != nil && s.Logger != nil {
+		s.Logger.Warn("onchain resolution notify failed", zap.String("market_id", m.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/risk"
+)
+
+// CreateOpportunityPlan inserts a draft plan for opp sized by the risk
+// manager, or at sizeUSD when set, moves the opportunity into execution and
+// seeds its PnL record. All three commit with the creation log through
+// outbox. With costs set, the execution cost forecast is recorded for the plan and a warning added when it eats the whole edge.
+// Campaign admission errors are returned as is; see IsCampaignBlocked.
+func CreateOpportunityPlan(ctx context.Context, repo repository.Repository, riskMgr *risk.Manager, campaigns *CampaignService, costs *CostForecaster, outbox *OutboxDispatcher, opp models.Opportunity, sizeUSD *decimal.Decimal) (*models.ExecutionPlan, []string, error) {
+	stratName := ""
+	if opp.Strategy.Name != "" {
+		stratName = opp.Strategy.Name
+	}
+	if stratName == "" {
+		stratName = "unknown"
+	}
+
+	plannedSize := opp.MaxSize
+	maxLoss := plannedSize
+	var kellyFraction *float64
+	warnings := []string{}
+	if riskMgr != nil {
+		ps, ml, kf, ws := riskMgr.SuggestPlanSizing(ctx, opp, stratName)
+		plannedSize = ps
+		maxLoss = ml
+		kellyFraction = kf
+		warnings = append(warnings, ws...)
+	}
+	if sizeUSD != nil {
+		maxLoss = ScaleMaxLoss(maxLoss, plannedSize, *sizeUSD)
+		plannedSize = *sizeUSD
+	}
+
+	if opp.CampaignID != nil {
+		if _, err := campaigns.Admit(ctx, *opp.CampaignID, opp.Tenant, plannedSize, time.Now().UTC()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	plan := &models.ExecutionPlan{
+		OpportunityID:   opp.ID,
+		Source:          "opportunity",
+		Tenant:          opp.Tenant,
+		CampaignID:      opp.CampaignID,
+		Status:          "draft",
+		StrategyName:    stratName,
+		PlannedSizeUSD:  plannedSize,
+		MaxLossUSD:      maxLoss,
+		KellyFraction:   kellyFraction,
+		Params:          datatypes.JSON([]byte(`{"slippage_tolerance":0.02,"execution_order":"sequential","limit_vs_market":"limit","time_limit_seconds":300}`)),
+		PreflightResult: datatypes.JSON([]byte(`{}`)),
+		Legs:            addPlanLegSizing(opp.Legs, plannedSize),
+		CreatedAt:       time.Now().UTC(),
+		UpdatedAt:       time.Now().UTC(),
+	}
+	// If opportunity legs are missing, keep plan invalid but insertable.
+	if len(plan.Legs) == 0 {
+		legsJSON, _ := json.Marshal([]any{})
+		plan.Legs = datatypes.JSON(legsJSON)
+	}
+
+	var fc *ExecutionCostForecast
+	if costs != nil {
+		if f, err := costs.Forecast(ctx, opp, plannedSize); err == nil {
+			fc = f
+			if !fc.NetEdgeUSD.IsPositive() {
+				warnings = append(warnings, "forecast execution cost exceeds gross edge")
+			}
+		}
+	}
+
+	// The plan, the opportunity's move into execution, its PnL seed and the
+	// log commit together so a crash cannot leave a plan for an opportunity
+	// still marked new, or a plan that was never announced.
+	err := WriteWithOutbox(ctx, repo, outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		if err := repo.InsertExecutionPlanTx(ctx, tx, plan); err != nil {
+			return nil, err
+		}
+		if opp.ID != 0 {
+			if err := repo.UpdateOpportunityStatusTx(ctx, tx, opp.ID, "executing"); err != nil {
+				return nil, err
+			}
+		}
+		// Seed a PnL record so analytics can show "planned" stats even before settlement.
+		if err := repo.UpsertPnLRecordTx(ctx, tx, &models.PnLRecord{
+			PlanID:       plan.ID,
+			StrategyName: plan.StrategyName,
+			ExpectedEdge: opp.EdgePct,
+			Outcome:      "pending",
+			CreatedAt:    time.Now().UTC(),
+		}); err != nil {
+			return nil, err
+		}
+		return []models.OutboxMessage{NewOutboxPaasLog(ctx, "polymarket_execution_plan_created", "info", map[string]any{
+			"opportunity_id":   opp.ID,
+			"plan_id":          plan.ID,
+			"strategy":         plan.StrategyName,
+			"planned_size_usd": plan.PlannedSizeUSD.String(),
+			"max_loss_usd":     plan.MaxLossUSD.String(),
+			"warnings":         warnings,
+		})}, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if fc != nil {
+		_ = costs.Record(ctx, plan, fc)
+	}
+	return plan, warnings, nil
+}
+
+// ScaleMaxLoss keeps the max loss proportional to the planned size when a
+// trader overrides it; without a suggestion the whole size is at risk.
+func ScaleMaxLoss(maxLoss, plannedSize, sizeUSD decimal.Decimal) decimal.Decimal {
+	if plannedSize.LessThanOrEqual(decimal.Zero) {
+		return sizeUSD
+	}
+	return maxLoss.Mul(sizeUSD).Div(plannedSize)
+}
+
+func addPlanLegSizing(legsJSON []byte, plannedSizeUSD decimal.Decimal) datatypes.JSON {
+	if len(legsJSON) == 0 {
+		return datatypes.JSON(legsJSON)
+	}
+	var legs []map[string]any
+	if err := json.Unmarshal(legsJSON, &legs); err != nil {
+		return datatypes.JSON(legsJSON)
+	}
+	if len(legs) == 0 {
+		return datatypes.JSON(legsJSON)
+	}
+	perLeg := plannedSizeUSD.Div(decimal.NewFromInt(int64(len(legs))))
+	perLegF, _ := perLeg.Float64()
+	for i := range legs {
+		// Add sizing hints for the UI and future execution engine.
+		if _, ok := legs[i]["size_usd"]; !ok {
+			legs[i]["size_usd"] = perLegF
+		}
+		if _, ok := legs[i]["priority"]; !ok {
+			legs[i]["priority"] = i + 1
+		}
+	}
+	raw, err := json.Marshal(legs)
+	if err != nil {
+		return datatypes.JSON(legsJSON)
+	}
+	return datatypes.JSON(raw)
+}
+
+// PreflightPlan runs the risk preflight on a plan and, when it fails,
+// journals the failure reason on the plan's PnL record for analytics. A nil
+// result means the plan does not exist. The run is logged through outbox.
+func PreflightPlan(ctx context.Context, repo repository.Repository, riskMgr *risk.Manager, outbox *OutboxDispatcher, planID uint64) (*risk.PreflightResult, error) {
+	result, err := riskMgr.PreflightPlan(ctx, planID)
+	if err != nil || result == nil {
+		return result, err
+	}
+	if !result.Passed {
+		recordPreflightFailure(ctx, repo, planID, *result)
+	}
+	outbox.Log(ctx, "polymarket_execution_preflight", "info", map[string]any{
+		"plan_id": planID,
+		"passed":  result.Passed,
+		"checks":  len(result.Checks),
+	})
+	return result, nil
+}
+
+// recordPreflightFailure is best effort.
+func recordPreflightFailure(ctx context.Context, repo repository.Repository, planID uint64, result risk.PreflightResult) {
+	reason := preflightFailureReason(result)
+	if reason == "" {
+		return
+	}
+	plan, _ := repo.GetExecutionPlanByID(ctx, planID)
+	if plan == nil {
+		return
+	}
+	rec, _ := repo.GetPnLRecordByPlanID(ctx, planID)
+	if rec == nil {
+		rec = &models.PnLRecord{
+			PlanID:       planID,
+			StrategyName: plan.StrategyName,
+			ExpectedEdge: decimal.Zero,
+			Outcome:      "pending",
+			CreatedAt:    time.Now().UTC(),
+		}
+	}
+	rec.FailureReason = &reason
+	if strings.TrimSpace(rec.Outcome) == "" {
+		rec.Outcome = "pending"
+	}
+	_ = repo.UpsertPnLRecord(ctx, rec)
+}
+
+func preflightFailureReason(res risk.PreflightResult) string {
+	for _, chk := range res.Checks {
+		if chk.Status != "fail" {
+			continue
+		}
+		switch chk.Name {
+		case "data_freshness":
+			return "latency"
+		case "edge_recheck":
+			return "price_jump"
+		case "capital_limit":
+			return "rule_mismatch"
+		default:
+			// keep looking
+		}
+	}
+	return ""
+}
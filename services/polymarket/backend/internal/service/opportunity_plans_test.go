@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	gormrepository "polymarket/internal/repository/gorm"
+)
+
+func TestScaleMaxLoss(t *testing.T) {
+	if got := ScaleMaxLoss(decimal.NewFromInt(40), decimal.NewFromInt(100), decimal.NewFromInt(50)); !got.Equal(decimal.NewFromInt(20)) {
+		t.Fatalf("max loss=%s want 20", got)
+	}
+	if got := ScaleMaxLoss(decimal.Zero, decimal.Zero, decimal.NewFromInt(50)); !got.Equal(decimal.NewFromInt(50)) {
+		t.Fatalf("max loss=%s want 50", got)
+	}
+}
+
+func TestCreateOpportunityPlanCommitsStatusAndLogWithPlan(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.Strategy{}, &models.Opportunity{}, &models.ExecutionPlan{}, &models.OutboxMessage{}); err != nil {
+		t.Fatal(err)
+	}
+	store := gormrepository.New(conn.Gorm)
+	ctx := context.Background()
+	outbox := &OutboxDispatcher{Repo: store, Config: config.OutboxConfig{Enabled: true, MaxAttempts: 3}}
+
+	strat := &models.Strategy{Name: "s", Params: datatypes.JSON(`{}`)}
+	if err := store.UpsertStrategy(ctx, strat); err != nil {
+		t.Fatal(err)
+	}
+	opp := &models.Opportunity{StrategyID: strat.ID, Status: "active", MaxSize: decimal.NewFromInt(50), Legs: datatypes.JSON(`[]`)}
+	if err := store.InsertOpportunity(ctx, opp); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the PnL table the seed fails and nothing else commits.
+	if _, _, err := CreateOpportunityPlan(ctx, store, nil, nil, nil, outbox, *opp, nil); err == nil {
+		t.Fatal("expected the PnL seed to fail")
+	}
+	if got, _ := store.GetOpportunityByID(ctx, opp.ID); got == nil || got.Status != "active" {
+		t.Fatalf("opportunity = %+v, want still active", got)
+	}
+	if n, _ := store.CountExecutionPlans(ctx, repository.ListExecutionPlansParams{}); n != 0 {
+		t.Fatalf("plans = %d, want 0", n)
+	}
+	if msgs, _ := store.ListOutboxMessages(ctx, repository.ListOutboxMessagesParams{Limit: 10}); len(msgs) != 0 {
+		t.Fatalf("outbox = %+v, want empty", msgs)
+	}
+
+	if err := conn.Gorm.AutoMigrate(&models.PnLRecord{}); err != nil {
+		t.Fatal(err)
+	}
+	plan, _, err := CreateOpportunityPlan(ctx, store, nil, nil, nil, outbox, *opp, nil)
+	if err != nil || plan == nil {
+		t.Fatalf("plan=%v err=%v", plan, err)
+	}
+	if got, _ := store.GetOpportunityByID(ctx, opp.ID); got == nil || got.Status != "executing" {
+		t.Fatalf("opportunity = %+v, want executing", got)
+	}
+	if rec, _ := store.GetPnLRecordByPlanID(ctx, plan.ID); rec == nil || rec.Outcome != "pending" {
+		t.Fatalf("pnl record = %+v", rec)
+	}
+	msgs, err := store.ListOutboxMessages(ctx, repository.ListOutboxMessagesParams{Limit: 10})
+	if err != nil || len(msgs) != 1 || msgs[0].Kind != models.OutboxKindPaasLog {
+		t.Fatalf("outbox = %+v, %v", msgs, err)
+	}
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/config"
+	"polymarket/internal/events"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// OutboxNotifyPayload is the body of a notify message: a platform
+// broadcast to the channels subscribed to Event.
+type OutboxNotifyPayload struct {
+	Event   string `json:"event"`
+	Message string `json:"message"`
+}
+
+// OutboxWebhookPayload is the body of a webhook message: Body is POSTed to
+// URL as JSON.
+type OutboxWebhookPayload struct {
+	URL  string          `json:"url"`
+	Body json.RawMessage `json:"body"`
+}
+
+// NewOutboxPaasLog builds a PaaS log message correlated with the request in
+// ctx, as paas.LogBestEffortCtx would send it.
+func NewOutboxPaasLog(ctx context.Context, action, level string, details map[string]any) models.OutboxMessage {
+	return newOutboxMessage(models.OutboxKindPaasLog, paas.CreateLogRequest{
+		Agent:         "polymarket-service",
+		Action:        action,
+		Level:         level,
+		Details:       details,
+		Metadata:      map[string]any{},
+		CorrelationID: paas.RequestIDFromContext(ctx),
+	})
+}
+
+func NewOutboxNotify(event, message string) models.OutboxMessage {
+	return newOutboxMessage(models.OutboxKindNotify, OutboxNotifyPayload{Event: event, Message: message})
+}
+
+func NewOutboxWebhook(url string, body any) (models.OutboxMessage, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return models.OutboxMessage{}, err
+	}
+	return newOutboxMessage(models.OutboxKindWebhook, OutboxWebhookPayload{URL: url, Body: raw}), nil
+}
+
+func newOutboxMessage(kind string, payload any) models.OutboxMessage {
+	raw, _ := json.Marshal(payload)
+	return models.OutboxMessage{Kind: kind, Payload: datatypes.JSON(raw), Status: models.OutboxStatusPending}
+}
+
+// OutboxDispatcher delivers outbox messages. Delivery is at least once: a
+// dispatcher that dies mid-delivery leaves the message to be claimed again
+// when its lease runs out.
+type OutboxDispatcher struct {
+	Repo   repository.Repository
+	Config config.OutboxConfig
+	// Paas sends PaaS logs and notifications; without it they go through
+	// the client in the context, if any.
+	Paas   *paas.Client
+	Client *http.Client
+	Logger *zap.Logger
+	// Wake, when set, triggers a pass as soon as messages are enqueued.
+	Wake <-chan events.Event
+
+	// TradeWebhooks sends trade webhook deliveries.
+	TradeWebhooks *TradeWebhookService
+	// CatalogWebhooks sends catalog webhook deliveries.
+	CatalogWebhooks *CatalogWebhookService
+
+	lastPurge time.Time
+}
+
+// Enabled reports whether handlers should record side effects in the outbox
+// rather than send them after the write.
+func (d *OutboxDispatcher) Enabled() bool {
+	return d != nil && d.Config.Enabled && d.Repo != nil
+}
+
+// Notify broadcasts message to the channels subscribed to event. With the
+// outbox enabled the broadcast is recorded and retried until delivered;
+// otherwise it is sent inline, and dropped without a PaaS client, as
+// paas.BroadcastCtx does. It works on a nil dispatcher.
+func (d *OutboxDispatcher) Notify(ctx context.Context, event, message string) error {
+	if d.Enabled() {
+		return d.Enqueue(ctx, []models.OutboxMessage{NewOutboxNotify(event, message)})
+	}
+	p := d.paasClient(ctx)
+	if p == nil {
+		return nil
+	}
+	return p.Broadcast(ctx, event, message)
+}
+
+// Enqueue records items for delivery on their own, for side effects that
+// do not follow a database write.
+func (d *OutboxDispatcher) Enqueue(ctx context.Context, items []models.OutboxMessage) error {
+	if !d.Enabled() {
+		return errors.New("outbox disabled")
+	}
+	return d.Repo.InTx(ctx, func(tx *gorm.DB) error {
+		return d.Repo.InsertOutboxMessagesTx(ctx, tx, items)
+	})
+}
+
+// Log records a PaaS log on its own, for events that do not follow a
+// database write. It falls back to a best-effort send when the outbox is off
+// or the record cannot be written. It works on a nil dispatcher.
+func (d *OutboxDispatcher) Log(ctx context.Context, action, level string, details map[string]any) {
+	msg := NewOutboxPaasLog(ctx, action, level, details)
+	if d.Enabled() {
+		err := d.Enqueue(ctx, []models.OutboxMessage{msg})
+		if err == nil {
+			return
+		}
+		d.warn("outbox log enqueue failed", msg, err)
+	}
+	d.SendBestEffort(ctx, msg)
+}
+
+// WriteWithOutbox runs write in a transaction that also records the
+// messages it returns, so the side effects are delivered exactly when the
+// write commits. With the outbox off they are sent best-effort after the
+// commit instead. It works on a nil dispatcher.
+func WriteWithOutbox(ctx context.Context, repo repository.Repository, outbox *OutboxDispatcher, write func(tx *gorm.DB) ([]models.OutboxMessage, error)) error {
+	var msgs []models.OutboxMessage
+	err := repo.InTx(ctx, func(tx *gorm.DB) error {
+		var err error
+		if msgs, err = write(tx); err != nil {
+			return err
+		}
+		if outbox.Enabled() {
+			return repo.InsertOutboxMessagesTx(ctx, tx, msgs)
+		}
+		return nil
+	})
+	if err != nil || outbox.Enabled() {
+		return err
+	}
+	for _, msg := range msgs {
+		outbox.SendBestEffort(ctx, msg)
+	}
+	return nil
+}
+
+// Run dispatches due messages every Interval, or on Wake, until ctx is done.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	if !d.Enabled() {
+		return
+	}
+	interval := d.Config.Interval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DispatchOnce(ctx, time.Now().UTC()); err != nil && ctx.Err() == nil && d.Logger != nil {
+			d.Logger.Warn("outbox dispatch failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.Wake:
+			events.Drain(d.Wake)
+		}
+	}
+}
+
+// outboxMaxBatches bounds the batches delivered per pass so a backlog does
+// not hold the dispatcher past its next tick indefinitely.
+const outboxMaxBatches = 20
+
+// DispatchOnce claims and delivers due messages, batch by batch, until none
+// are due. It returns how many were delivered.
+func (d *OutboxDispatcher) DispatchOnce(ctx context.Context, now time.Time) (int, error) {
+	if !d.Enabled() {
+		return 0, nil
+	}
+	batch := d.Config.BatchSize
+	if batch <= 0 {
+		batch = 50
+	}
+	lease := d.Config.Lease
+	if lease <= 0 {
+		lease = time.Minute
+	}
+	delivered := 0
+	for i := 0; i < outboxMaxBatches; i++ {
+		items, err := d.Repo.ClaimOutboxMessages(ctx, now, lease, batch)
+		if err != nil {
+			return delivered, err
+		}
+		for _, msg := range items {
+			if d.deliverClaimed(ctx, msg, now) {
+				delivered++
+			}
+		}
+		if len(items) < batch {
+			break
+		}
+	}
+	d.purge(ctx, now)
+	return delivered, nil
+}
+
+func (d *OutboxDispatcher) deliverClaimed(ctx context.Context, msg models.OutboxMessage, now time.Time) bool {
+	err := d.Deliver(ctx, msg)
+	if err == nil {
+		if markErr := d.Repo.MarkOutboxMessageDelivered(ctx, msg.ID, time.Now().UTC()); markErr != nil {
+			d.warn("outbox mark delivered", msg, markErr)
+		}
+		return true
+	}
+	var retryAt *time.Time
+	maxAttempts := d.Config.MaxAttempts
+	if maxAttempts <= 0 || msg.Attempts < maxAttempts {
+		at := now.Add(d.backoff(msg.Attempts))
+		retryAt = &at
+	} else {
+		d.warn("outbox message dead", msg, err)
+	}
+	if markErr := d.Repo.MarkOutboxMessageFailed(ctx, msg.ID, err.Error(), retryAt); markErr != nil {
+		d.warn("outbox mark failed", msg, markErr)
+	}
+	return false
+}
+
+// backoff doubles RetryBackoff per attempt made, up to MaxBackoff.
+func (d *OutboxDispatcher) backoff(attempts int) time.Duration {
+	base := d.Config.RetryBackoff
+	if base <= 0 {
+		base = 5 * time.Second
+	}
+	max := d.Config.MaxBackoff
+	if max <= 0 {
+		max = 10 * time.Minute
+	}
+	wait := base
+	for i := 1; i < attempts && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return wait
+}
+
+// purge deletes delivered messages past Retention, at most hourly.
+func (d *OutboxDispatcher) purge(ctx context.Context, now time.Time) {
+	if d.Config.Retention <= 0 || now.Sub(d.lastPurge) < time.Hour {
+		return
+	}
+	d.lastPurge = now
+	if n, err := d.Repo.DeleteDeliveredOutboxMessages(ctx, now.Add(-d.Config.Retention)); err != nil {
+		if d.Logger != nil {
+			d.Logger.Warn("outbox purge failed", zap.Error(err))
+		}
+	} else if n > 0 && d.Logger != nil {
+		d.Logger.Info("outbox purged", zap.Int64("deleted", n))
+	}
+}
+
+// SendBestEffort sends msg without recording it, as handlers did before
+// the outbox: PaaS logs go through the client's log buffer, the rest are
+// delivered inline and errors are dropped. It works on a nil dispatcher.
+func (d *OutboxDispatcher) SendBestEffort(ctx context.Context, msg models.OutboxMessage) {
+	if msg.Kind == models.OutboxKindPaasLog {
+		var req paas.CreateLogRequest
+		if err := json.Unmarshal(msg.Payload, &req); err == nil {
+			d.paasClient(ctx).Log(req)
+		}
+		return
+	}
+	_ = d.Deliver(ctx, msg)
+}
+
+// Deliver sends one message; it works on a nil dispatcher.
+func (d *OutboxDispatcher) Deliver(ctx context.Context, msg models.OutboxMessage) error {
+	switch msg.Kind {
+	case models.OutboxKindPaasLog:
+		var req paas.CreateLogRequest
+		if err := json.Unmarshal(msg.Payload, &req); err != nil {
+			return err
+		}
+		p := d.paasClient(ctx)
+		if p == nil {
+			return errors.New("paas client unavailable")
+		}
+		return p.CreateLog(ctx, req)
+	case models.OutboxKindNotify:
+		var body OutboxNotifyPayload
+		if err := json.Unmarshal(msg.Payload, &body); err != nil {
+			return err
+		}
+		return d.paasClient(ctx).Broadcast(ctx, body.Event, body.Message)
+	case models.OutboxKindWebhook:
+		var body OutboxWebhookPayload
+		if err := json.Unmarshal(msg.Payload, &body); err != nil {
+			return err
+		}
+		return d.postWebhook(ctx, msg.ID, body)
+	case models.OutboxKindTradeWebhook:
+		var body OutboxTradeWebhookPayload
+		if err := json.Unmarshal(msg.Payload, &body); err != nil {
+			return err
+		}
+		if d == nil || d.TradeWebhooks == nil {
+			return errors.New("trade webhooks unavailable")
+		}
+		return d.TradeWebhooks.DeliverQueued(ctx, body.DeliveryID)
+	case models.OutboxKindCatalogWebhook:
+		var body OutboxCatalogWebhookPayload
+		if err := json.Unmarshal(msg.Payload, &body); err != nil {
+			return err
+		}
+		if d == nil || d.CatalogWebhooks == nil {
+			return errors.New("catalog webhooks unavailable")
+		}
+		return d.CatalogWebhooks.DeliverQueued(ctx, body.WebhookID, body.Payload)
+	default:
+		return fmt.Errorf("unknown outbox kind %q", msg.Kind)
+	}
+}
+
+func (d *OutboxDispatcher) paasClient(ctx context.Context) *paas.Client {
+	if d != nil && d.Paas != nil {
+		return d.Paas
+	}
+	return paas.ClientFromContext(ctx)
+}
+
+func (d *OutboxDispatcher) postWebhook(ctx context.Context, id uint64, body OutboxWebhookPayload) error {
+	if body.URL == "" {
+		return errors.New("webhook url is empty")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, body.URL, bytes.NewReader(body.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Receivers dedupe redeliveries on the message ID.
+	req.Header.Set("X-Outbox-Message-Id", strconv.FormatUint(id, 10))
+	client := http.DefaultClient
+	if d != nil {
+		if secret := d.Config.WebhookSecret; secret != "" {
+			ts := strconv.FormatInt(time.Now().Unix(), 10)
+			req.Header.Set("X-Outbox-Timestamp", ts)
+			req.Header.Set("X-Outbox-Signature", SignCatalogWebhook(secret, ts, body.Body))
+		}
+		client = d.Client
+		if client == nil {
+			timeout := d.Config.WebhookTimeout
+			if timeout <= 0 {
+				timeout = 10 * time.Second
+			}
+			client = &http.Client{Timeout: timeout}
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *OutboxDispatcher) warn(msg string, item models.OutboxMessage, err error) {
+	if d.Logger != nil {
+		d.Logger.Warn(msg, zap.Uint64("id", item.ID), zap.String("kind", item.Kind), zap.Int("attempts", item.Attempts), zap.Error(err))
+	}
+}
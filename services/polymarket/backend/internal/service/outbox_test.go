@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	gormrepository "polymarket/internal/repository/gorm"
+)
+
+type outboxRepo struct {
+	repository.Repository
+	due       []models.OutboxMessage
+	delivered []uint64
+	failed    map[uint64]*time.Time
+}
+
+func (r *outboxRepo) ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
+	out := r.due
+	r.due = nil
+	return out, nil
+}
+
+func (r *outboxRepo) MarkOutboxMessageDelivered(ctx context.Context, id uint64, at time.Time) error {
+	r.delivered = append(r.delivered, id)
+	return nil
+}
+
+func (r *outboxRepo) MarkOutboxMessageFailed(ctx context.Context, id uint64, errMsg string, retryAt *time.Time) error {
+	r.failed[id] = retryAt
+	return nil
+}
+
+func TestOutboxDispatcherRetriesThenDies(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		got = append(got, r.Header.Get("X-Outbox-Message-Id")+":"+body["plan"])
+		if body["plan"] == "bad" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	ok, _ := NewOutboxWebhook(srv.URL, map[string]string{"plan": "good"})
+	ok.ID, ok.Attempts = 1, 1
+	retry, _ := NewOutboxWebhook(srv.URL, map[string]string{"plan": "bad"})
+	retry.ID, retry.Attempts = 2, 3
+	dead := retry
+	dead.ID, dead.Attempts = 3, 5
+	repo := &outboxRepo{due: []models.OutboxMessage{ok, retry, dead}, failed: map[uint64]*time.Time{}}
+	d := &OutboxDispatcher{Repo: repo, Config: config.OutboxConfig{
+		Enabled: true, MaxAttempts: 5, RetryBackoff: time.Second, MaxBackoff: time.Minute,
+	}}
+
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	n, err := d.DispatchOnce(context.Background(), now)
+	if err != nil || n != 1 {
+		t.Fatalf("delivered=%d err=%v", n, err)
+	}
+	if len(got) != 3 || got[0] != "1:good" {
+		t.Fatalf("posts=%v", got)
+	}
+	if len(repo.delivered) != 1 || repo.delivered[0] != 1 {
+		t.Fatalf("delivered=%v", repo.delivered)
+	}
+	// Third attempt failed: the next waits 1s doubled twice.
+	if at := repo.failed[2]; at == nil || !at.Equal(now.Add(4*time.Second)) {
+		t.Fatalf("retry at=%v", at)
+	}
+	if at, ok := repo.failed[3]; !ok || at != nil {
+		t.Fatalf("message out of attempts should be dead, retry at=%v", at)
+	}
+}
+
+func TestOutboxBackoffCapped(t *testing.T) {
+	d := &OutboxDispatcher{Config: config.OutboxConfig{RetryBackoff: 5 * time.Second, MaxBackoff: time.Minute}}
+	for attempts, want := range map[int]time.Duration{1: 5 * time.Second, 2: 10 * time.Second, 4: 40 * time.Second, 9: time.Minute} {
+		if got := d.backoff(attempts); got != want {
+			t.Fatalf("backoff(%d)=%v want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestOutboxCarriesNotificationsAndCatalogWebhooks(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.OutboxMessage{}, &models.CatalogWebhook{}); err != nil {
+		t.Fatal(err)
+	}
+	store := gormrepository.New(conn.Gorm)
+	ctx := context.Background()
+
+	var sigs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sigs = append(sigs, r.Header.Get("X-Catalog-Signature"))
+	}))
+	defer srv.Close()
+	hook := models.CatalogWebhook{Name: "all", URL: srv.URL, Secret: "s3cret", Enabled: true}
+	if err := store.InsertCatalogWebhook(ctx, &hook); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &OutboxDispatcher{Repo: store, Config: config.OutboxConfig{Enabled: true, MaxAttempts: 3}}
+	d.CatalogWebhooks = &CatalogWebhookService{Repo: store, Config: config.CatalogWebhooksConfig{Enabled: true}, Outbox: d}
+	if err := d.Notify(ctx, "safe_mode", "entered"); err != nil {
+		t.Fatal(err)
+	}
+	d.CatalogWebhooks.PublishNew(ctx, nil, []models.Market{{ID: "m1", EventID: "e1", Question: "Will it rain?"}}, map[string][]models.Tag{"e1": nil})
+
+	queued, err := store.ListOutboxMessages(ctx, repository.ListOutboxMessagesParams{Limit: 10})
+	if err != nil || len(queued) != 2 {
+		t.Fatalf("queued = %+v, %v", queued, err)
+	}
+	// The notification fails without a PaaS client and stays pending for a
+	// retry; the webhook is delivered.
+	n, err := d.DispatchOnce(ctx, time.Now().UTC())
+	if err != nil || n != 1 {
+		t.Fatalf("delivered=%d err=%v", n, err)
+	}
+	if len(sigs) != 1 || sigs[0] == "" {
+		t.Fatalf("catalog posts = %v", sigs)
+	}
+	pending := models.OutboxStatusPending
+	left, err := store.ListOutboxMessages(ctx, repository.ListOutboxMessagesParams{Status: &pending, Limit: 10})
+	if err != nil || len(left) != 1 || left[0].Kind != models.OutboxKindNotify {
+		t.Fatalf("pending = %+v, %v", left, err)
+	}
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/risk"
+)
+
+// Plan trigger states.
+const (
+	PlanTriggerScheduled = "scheduled"
+	PlanTriggerFired     = "fired"
+	PlanTriggerFailed    = "failed"
+)
+
+var (
+	ErrPlanTriggerInvalid = errors.New("invalid plan trigger")
+	ErrPlanNotSchedulable = errors.New("only draft and preflight_pass plans can be scheduled")
+)
+
+// PlanTriggerSpec is when a plan should execute: at an absolute time, or
+// MinutesBeforeEnd before the end of MarketID's event. MarketID defaults to
+// the market of the plan's first leg.
+type PlanTriggerSpec struct {
+	At               *time.Time `json:"at"`
+	MinutesBeforeEnd *int       `json:"minutes_before_end"`
+	MarketID         string     `json:"market_id"`
+}
+
+// PlanTriggerService runs time-triggered plans, such as market-on-close
+// orders, through the standard preflight and submit flow once their trigger
+// time passes. RunDue is driven by the cron runner.
+type PlanTriggerService struct {
+	Repo     repository.Repository
+	Risk     *risk.Manager
+	Executor *CLOBExecutor
+	Logger   *zap.Logger
+	// MaxDelay fails a trigger found more than MaxDelay late instead of
+	// firing it; 0 always fires.
+	MaxDelay time.Duration
+
+	// mu keeps overlapping cron runs from firing a plan twice.
+	mu sync.Mutex
+}
+
+// Schedule stores spec on the plan. It returns nil when the plan does not
+// exist.
+func (s *PlanTriggerService) Schedule(ctx context.Context, planID uint64, spec PlanTriggerSpec, now time.Time) (*models.ExecutionPlan, error) {
+	if (spec.At == nil) == (spec.MinutesBeforeEnd == nil) {
+		return nil, fmt.Errorf("%w: set exactly one of at or minutes_before_end", ErrPlanTriggerInvalid)
+	}
+	if spec.MinutesBeforeEnd != nil && *spec.MinutesBeforeEnd <= 0 {
+		return nil, fmt.Errorf("%w: minutes_before_end must be positive", ErrPlanTriggerInvalid)
+	}
+	plan, err := s.Repo.GetExecutionPlanByID(ctx, planID)
+	if err != nil || plan == nil {
+		return nil, err
+	}
+	if plan.Status != "draft" && plan.Status != "preflight_pass" {
+		return nil, ErrPlanNotSchedulable
+	}
+	plan.TriggerMinutesBeforeEnd = spec.MinutesBeforeEnd
+	plan.TriggerMarketID = nil
+	if spec.MinutesBeforeEnd != nil {
+		marketID := strings.TrimSpace(spec.MarketID)
+		if marketID == "" {
+			marketID = s.planMarketID(ctx, *plan)
+		}
+		if marketID == "" {
+			return nil, fmt.Errorf("%w: plan has no market to anchor the trigger on", ErrPlanTriggerInvalid)
+		}
+		plan.TriggerMarketID = &marketID
+	}
+	at, err := s.triggerTime(ctx, *plan, spec.At)
+	if err != nil {
+		return nil, err
+	}
+	if !at.After(now) {
+		return nil, fmt.Errorf("%w: trigger time %s has already passed", ErrPlanTriggerInvalid, at.Format(time.RFC3339))
+	}
+	plan.TriggerState = PlanTriggerScheduled
+	plan.TriggerAt = &at
+	plan.TriggerError = ""
+	if err := s.Repo.UpdateExecutionPlanTrigger(ctx, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Unschedule clears a scheduled trigger and reports whether there was one.
+func (s *PlanTriggerService) Unschedule(ctx context.Context, planID uint64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	plan, err := s.Repo.GetExecutionPlanByID(ctx, planID)
+	if err != nil || plan == nil || plan.TriggerState != PlanTriggerScheduled {
+		return false, err
+	}
+	plan.TriggerState = ""
+	plan.TriggerAt = nil
+	plan.TriggerMinutesBeforeEnd = nil
+	plan.TriggerMarketID = nil
+	plan.TriggerError = ""
+	return true, s.Repo.UpdateExecutionPlanTrigger(ctx, plan)
+}
+
+// RunDue fires the plans whose trigger time has passed and returns how many
+// were submitted. Triggers anchored on an event end are re-resolved first, so
+// a moved end time reschedules rather than fires them.
+func (s *PlanTriggerService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	if s == nil || s.Repo == nil {
+		return 0, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	plans, err := s.Repo.ListDuePlanTriggers(ctx, now, 100)
+	if err != nil {
+		return 0, err
+	}
+	fired := 0
+	for i := range plans {
+		plan := &plans[i]
+		if plan.TriggerMinutesBeforeEnd != nil {
+			at, err := s.triggerTime(ctx, *plan, nil)
+			if err != nil {
+				s.fail(ctx, plan, err.Error())
+				continue
+			}
+			plan.TriggerAt = &at
+			if at.After(now) {
+				if err := s.Repo.UpdateExecutionPlanTrigger(ctx, plan); err != nil {
+					return fired, err
+				}
+				continue
+			}
+		}
+		if late := now.Sub(*plan.TriggerAt); s.MaxDelay > 0 && late > s.MaxDelay {
+			s.fail(ctx, plan, fmt.Sprintf("missed by %s", late.Round(time.Second)))
+			continue
+		}
+		if err := s.fire(ctx, plan); err != nil {
+			s.fail(ctx, plan, err.Error())
+			continue
+		}
+		fired++
+	}
+	return fired, nil
+}
+
+// fire marks the trigger fired before submitting, so a failed submit is not
+// retried on the next run, then preflights a draft plan and submits it.
+func (s *PlanTriggerService) fire(ctx context.Context, plan *models.ExecutionPlan) error {
+	plan.TriggerState = PlanTriggerFired
+	plan.TriggerError = ""
+	if err := s.Repo.UpdateExecutionPlanTrigger(ctx, plan); err != nil {
+		return err
+	}
+	if plan.Status == "draft" && s.Risk != nil {
+		res, err := s.Risk.PreflightPlan(ctx, plan.ID)
+		if err != nil {
+			return err
+		}
+		if res == nil || !res.Passed {
+			return fmt.Errorf("preflight failed")
+		}
+	}
+	if s.Executor == nil {
+		return fmt.Errorf("executor unavailable")
+	}
+	out, err := s.Executor.SubmitPlan(ctx, plan.ID)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return fmt.Errorf("plan not found")
+	}
+	if s.Logger != nil {
+		s.Logger.Info("plan trigger fired",
+			zap.Uint64("plan_id", plan.ID),
+			zap.Timep("trigger_at", plan.TriggerAt),
+			zap.String("mode", out.Mode),
+		)
+	}
+	return nil
+}
+
+func (s *PlanTriggerService) fail(ctx context.Context, plan *models.ExecutionPlan, reason string) {
+	plan.TriggerState = PlanTriggerFailed
+	plan.TriggerError = reason
+	_ = s.Repo.UpdateExecutionPlanTrigger(ctx, plan)
+	if s.Logger != nil {
+		s.Logger.Warn("plan trigger failed", zap.Uint64("plan_id", plan.ID), zap.String("reason", reason))
+	}
+}
+
+// triggerTime is at for absolute triggers, otherwise the plan's minutes
+// before the end of its anchor market's event.
+func (s *PlanTriggerService) triggerTime(ctx context.Context, plan models.ExecutionPlan, at *time.Time) (time.Time, error) {
+	if plan.TriggerMinutesBeforeEnd == nil {
+		if at == nil {
+			at = plan.TriggerAt
+		}
+		if at == nil {
+			return time.Time{}, fmt.Errorf("%w: no trigger time", ErrPlanTriggerInvalid)
+		}
+		return at.UTC(), nil
+	}
+	if plan.TriggerMarketID == nil {
+		return time.Time{}, fmt.Errorf("%w: no anchor market", ErrPlanTriggerInvalid)
+	}
+	end, err := s.marketEnd(ctx, *plan.TriggerMarketID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return end.UTC().Add(-time.Duration(*plan.TriggerMinutesBeforeEnd) * time.Minute), nil
+}
+
+func (s *PlanTriggerService) marketEnd(ctx context.Context, marketID string) (time.Time, error) {
+	markets, err := s.Repo.ListMarketsByIDs(ctx, []string{marketID})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(markets) == 0 {
+		return time.Time{}, fmt.Errorf("%w: market %s not found", ErrPlanTriggerInvalid, marketID)
+	}
+	events, err := s.Repo.ListEventsByIDs(ctx, []string{markets[0].EventID})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(events) == 0 || events[0].EndTime == nil || events[0].EndTime.IsZero() {
+		return time.Time{}, fmt.Errorf("%w: market %s has no event end time", ErrPlanTriggerInvalid, marketID)
+	}
+	return *events[0].EndTime, nil
+}
+
+// planMarketID is the market of the plan's first leg, by its market_id or
+// else its token.
+func (s *PlanTriggerService) planMarketID(ctx context.Context, plan models.ExecutionPlan) string {
+	var legs []struct {
+		MarketID string `json:"market_id"`
+		TokenID  string `json:"token_id"`
+	}
+	if json.Unmarshal(plan.Legs, &legs) != nil || len(legs) == 0 {
+		return ""
+	}
+	if id := strings.TrimSpace(legs[0].MarketID); id != "" {
+		return id
+	}
+	tokenID := strings.TrimSpace(legs[0].TokenID)
+	if tokenID == "" {
+		return ""
+	}
+	tokens, err := s.Repo.ListTokensByIDs(ctx, []string{tokenID})
+	if err != nil || len(tokens) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(tokens[0].MarketID)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type triggerRepo struct {
+	repository.Repository
+	plans map[uint64]models.ExecutionPlan
+	end   time.Time
+}
+
+func (r *triggerRepo) GetExecutionPlanByID(ctx context.Context, id uint64) (*models.ExecutionPlan, error) {
+	p, ok := r.plans[id]
+	if !ok {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+func (r *triggerRepo) UpdateExecutionPlanTrigger(ctx context.Context, item *models.ExecutionPlan) error {
+	r.plans[item.ID] = *item
+	return nil
+}
+
+func (r *triggerRepo) ListDuePlanTriggers(ctx context.Context, now time.Time, limit int) ([]models.ExecutionPlan, error) {
+	var out []models.ExecutionPlan
+	for _, p := range r.plans {
+		if p.TriggerState == PlanTriggerScheduled && !p.TriggerAt.After(now) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (r *triggerRepo) ListMarketsByIDs(ctx context.Context, ids []string) ([]models.Market, error) {
+	return []models.Market{{ID: ids[0], EventID: "e1"}}, nil
+}
+
+func (r *triggerRepo) ListEventsByIDs(ctx context.Context, ids []string) ([]models.Event, error) {
+	end := r.end
+	return []models.Event{{ID: ids[0], EndTime: &end}}, nil
+}
+
+func TestPlanTriggers_ScheduleBeforeEnd(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &triggerRepo{
+		plans: map[uint64]models.ExecutionPlan{1: {ID: 1, Status: "draft", Legs: []byte(`[{"market_id":"m1","token_id":"t1"}]`)}},
+		end:   t0.Add(2 * time.Hour),
+	}
+	svc := &PlanTriggerService{Repo: repo}
+	ctx := context.Background()
+
+	if _, err := svc.Schedule(ctx, 1, PlanTriggerSpec{}, t0); !errors.Is(err, ErrPlanTriggerInvalid) {
+		t.Fatalf("err=%v want invalid", err)
+	}
+	minutes := 30
+	plan, err := svc.Schedule(ctx, 1, PlanTriggerSpec{MinutesBeforeEnd: &minutes}, t0)
+	if err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	if plan.TriggerState != PlanTriggerScheduled || *plan.TriggerMarketID != "m1" || !plan.TriggerAt.Equal(t0.Add(90*time.Minute)) {
+		t.Fatalf("plan=%+v at=%v", plan, plan.TriggerAt)
+	}
+
+	// The event end moved out an hour: the due trigger is rescheduled, not fired.
+	repo.end = t0.Add(3 * time.Hour)
+	if n, err := svc.RunDue(ctx, t0.Add(95*time.Minute)); err != nil || n != 0 {
+		t.Fatalf("run n=%d err=%v", n, err)
+	}
+	if got := repo.plans[1]; got.TriggerState != PlanTriggerScheduled || !got.TriggerAt.Equal(t0.Add(150*time.Minute)) {
+		t.Fatalf("rescheduled=%+v", got)
+	}
+
+	late := 45
+	if _, err := svc.Schedule(ctx, 1, PlanTriggerSpec{MinutesBeforeEnd: &late}, t0.Add(170*time.Minute)); !errors.Is(err, ErrPlanTriggerInvalid) {
+		t.Fatalf("err=%v want passed trigger rejected", err)
+	}
+}
+
+func TestPlanTriggers_MissedTriggerFails(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &triggerRepo{plans: map[uint64]models.ExecutionPlan{2: {ID: 2, Status: "preflight_pass"}}}
+	svc := &PlanTriggerService{Repo: repo, MaxDelay: 5 * time.Minute}
+	ctx := context.Background()
+
+	at := t0.Add(time.Minute)
+	if _, err := svc.Schedule(ctx, 2, PlanTriggerSpec{At: &at}, t0); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	if n, _ := svc.RunDue(ctx, t0.Add(time.Hour)); n != 0 {
+		t.Fatalf("fired %d missed triggers", n)
+	}
+	if got := repo.plans[2]; got.TriggerState != PlanTriggerFailed || got.TriggerError != "missed by 59m0s" {
+		t.Fatalf("plan=%+v", got)
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// Sources of the price an open quantity is valued at.
+const (
+	MarkSettlement = "settlement"
+	MarkBook       = "book"
+	MarkLastFill   = "last_fill"
+)
+
+// TokenPnL is the recalculated PnL of one token of a plan. Fills are
+// replayed at average cost; an open quantity is paid out at settlement or
+// marked at the latest book mid.
+type TokenPnL struct {
+	TokenID       string           `json:"token_id"`
+	MarketID      string           `json:"market_id,omitempty"`
+	Outcome       string           `json:"outcome,omitempty"`
+	Fills         int              `json:"fills"`
+	BoughtSize    decimal.Decimal  `json:"bought_size"`
+	SoldSize      decimal.Decimal  `json:"sold_size"`
+	OpenSize      decimal.Decimal  `json:"open_size"`
+	AvgEntryPrice decimal.Decimal  `json:"avg_entry_price"`
+	CostUSD       decimal.Decimal  `json:"cost_usd"`
+	FeesUSD       decimal.Decimal  `json:"fees_usd"`
+	RealizedPnL   decimal.Decimal  `json:"realized_pnl"`
+	UnrealizedPnL decimal.Decimal  `json:"unrealized_pnl"`
+	MarkPrice     *decimal.Decimal `json:"mark_price,omitempty"`
+	MarkSource    string           `json:"mark_source,omitempty"`
+}
+
+// PnLDiscrepancy is a field where the stored PnL record and the
+// recalculation disagree. Stored is nil when the record has no value.
+type PnLDiscrepancy struct {
+	Field        string  `json:"field"`
+	Stored       *string `json:"stored"`
+	Recalculated string  `json:"recalculated"`
+}
+
+// PlanPnLRecalc is a plan's PnL recomputed from its fills. Settled is true
+// when nothing is left open after settlement payouts.
+type PlanPnLRecalc struct {
+	PlanID        uint64            `json:"plan_id"`
+	Tokens        []TokenPnL        `json:"tokens"`
+	CostUSD       decimal.Decimal   `json:"cost_usd"`
+	FeesUSD       decimal.Decimal   `json:"fees_usd"`
+	RealizedPnL   decimal.Decimal   `json:"realized_pnl"`
+	UnrealizedPnL decimal.Decimal   `json:"unrealized_pnl"`
+	RealizedROI   *decimal.Decimal  `json:"realized_roi,omitempty"`
+	Settled       bool              `json:"settled"`
+	Outcome       string            `json:"outcome"`
+	Stored        *models.PnLRecord `json:"stored,omitempty"`
+	Discrepancies []PnLDiscrepancy  `json:"discrepancies"`
+}
+
+// ErrNoFills is returned when a plan has nothing to recalculate.
+var ErrNoFills = errors.New("no fills for plan")
+
+// PnLRecalcService recomputes plan PnL from first principles, e.g. after a
+// fee model or settlement fix, and can overwrite the stored record.
+type PnLRecalcService struct {
+	Repo repository.Repository
+}
+
+// Recalculate replays the plan's fills. overrides map market_id to a
+// winning outcome and take precedence over the settlement history.
+func (s *PnLRecalcService) Recalculate(ctx context.Context, planID uint64, overrides map[string]string) (*PlanPnLRecalc, error) {
+	fills, err := s.Repo.ListFillsByPlanID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if len(fills) == 0 {
+		return nil, ErrNoFills
+	}
+	tokenIDs := make([]string, 0, len(fills))
+	for _, f := range fills {
+		if id := strings.TrimSpace(f.TokenID); id != "" {
+			tokenIDs = append(tokenIDs, id)
+		}
+	}
+	tokens, err := s.Repo.ListTokensByIDs(ctx, tokenIDs)
+	if err != nil {
+		return nil, err
+	}
+	marketIDs := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		marketIDs = append(marketIDs, t.MarketID)
+	}
+	outcomes := map[string]string{}
+	settlements, err := s.Repo.ListMarketSettlementHistoryByMarketIDs(ctx, marketIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range settlements {
+		if val := models.OutcomeCode(r.Outcome); val != "" {
+			outcomes[strings.TrimSpace(r.MarketID)] = val
+		}
+	}
+	for k, v := range overrides {
+		if mid, val := strings.TrimSpace(k), models.OutcomeCode(v); mid != "" && val != "" {
+			outcomes[mid] = val
+		}
+	}
+	books, err := s.Repo.ListOrderbookLatestByTokenIDs(ctx, tokenIDs)
+	if err != nil {
+		return nil, err
+	}
+	marks := map[string]decimal.Decimal{}
+	for _, b := range books {
+		switch {
+		case b.Mid != nil:
+			marks[b.TokenID] = decimal.NewFromFloat(*b.Mid)
+		case b.BestBid != nil:
+			marks[b.TokenID] = decimal.NewFromFloat(*b.BestBid)
+		}
+	}
+	out := recalcPlanPnL(planID, fills, tokens, outcomes, marks)
+	stored, err := s.Repo.GetPnLRecordByPlanID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	out.Stored = stored
+	out.Discrepancies = pnlDiscrepancies(stored, out)
+	return &out, nil
+}
+
+// Apply writes the recalculation into the plan's PnL record, noting reason.
+// The outcome is only set once the plan is settled.
+func (s *PnLRecalcService) Apply(ctx context.Context, plan models.ExecutionPlan, r *PlanPnLRecalc, reason string, now time.Time) (*models.PnLRecord, error) {
+	rec := r.Stored
+	if rec == nil {
+		rec = &models.PnLRecord{
+			PlanID:       plan.ID,
+			StrategyName: plan.StrategyName,
+			ExpectedEdge: decimal.Zero,
+			Outcome:      "pending",
+			CreatedAt:    now,
+		}
+	} else {
+		copied := *rec
+		rec = &copied
+	}
+	realized := r.RealizedPnL
+	rec.RealizedPnL = &realized
+	rec.RealizedROI = r.RealizedROI
+	if r.Settled {
+		rec.Outcome = r.Outcome
+	}
+	note := "pnl recalculated " + now.Format(time.RFC3339) + ": " + reason
+	if rec.Notes != nil && strings.TrimSpace(*rec.Notes) != "" {
+		note = *rec.Notes + "\n" + note
+	}
+	rec.Notes = &note
+	if err := s.Repo.UpsertPnLRecord(ctx, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func recalcPlanPnL(planID uint64, fills []models.Fill, tokens []models.Token, outcomes map[string]string, marks map[string]decimal.Decimal) PlanPnLRecalc {
+	tokenByID := make(map[string]models.Token, len(tokens))
+	for _, t := range tokens {
+		tokenByID[t.ID] = t
+	}
+	sorted := append([]models.Fill(nil), fills...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].FilledAt.Before(sorted[j].FilledAt) })
+	order, byToken := groupFillsByToken(sorted)
+
+	out := PlanPnLRecalc{PlanID: planID, Tokens: make([]TokenPnL, 0, len(order)), Settled: true}
+	for _, tid := range order {
+		tokenFills := byToken[tid]
+		tok := tokenByID[tid]
+		// Like settle, the fill direction names the outcome held.
+		row := TokenPnL{TokenID: tid, MarketID: strings.TrimSpace(tok.MarketID), Outcome: normalizePositionDirection(tokenFills[0].Direction), Fills: len(tokenFills)}
+		if row.Outcome == "" {
+			row.Outcome = models.OutcomeCode(tok.Outcome)
+		}
+		pos := &models.Position{}
+		for _, f := range tokenFills {
+			if fillSideSign(f.Direction) < 0 {
+				row.SoldSize = row.SoldSize.Add(f.FilledSize)
+			} else {
+				row.BoughtSize = row.BoughtSize.Add(f.FilledSize)
+				row.CostUSD = row.CostUSD.Add(f.AvgPrice.Mul(f.FilledSize)).Add(f.Fee)
+			}
+			row.FeesUSD = row.FeesUSD.Add(f.Fee)
+			applyFillToPosition(pos, f, f.FilledAt)
+		}
+		row.OpenSize = pos.Quantity
+		row.AvgEntryPrice = pos.AvgEntryPrice
+		row.RealizedPnL = pos.RealizedPnL
+		if pos.Quantity.IsPositive() {
+			winner, settled := outcomes[row.MarketID]
+			var mark decimal.Decimal
+			switch {
+			case settled:
+				mark = decimal.Zero
+				if winner == row.Outcome {
+					mark = decimal.NewFromInt(1)
+				}
+				row.MarkSource = MarkSettlement
+			default:
+				out.Settled = false
+				if m, ok := marks[tid]; ok {
+					mark = m
+					row.MarkSource = MarkBook
+				} else {
+					mark = tokenFills[len(tokenFills)-1].AvgPrice
+					row.MarkSource = MarkLastFill
+				}
+			}
+			row.MarkPrice = &mark
+			value := mark.Sub(pos.AvgEntryPrice).Mul(pos.Quantity)
+			if settled {
+				row.RealizedPnL = row.RealizedPnL.Add(value)
+				row.OpenSize = decimal.Zero
+			} else {
+				row.UnrealizedPnL = value
+			}
+		}
+		out.CostUSD = out.CostUSD.Add(row.CostUSD)
+		out.FeesUSD = out.FeesUSD.Add(row.FeesUSD)
+		out.RealizedPnL = out.RealizedPnL.Add(row.RealizedPnL)
+		out.UnrealizedPnL = out.UnrealizedPnL.Add(row.UnrealizedPnL)
+		out.Tokens = append(out.Tokens, row)
+	}
+	if out.CostUSD.IsPositive() {
+		roi := out.RealizedPnL.Div(out.CostUSD)
+		out.RealizedROI = &roi
+	}
+	out.Outcome = "pending"
+	if out.Settled {
+		switch {
+		case out.RealizedPnL.IsPositive():
+			out.Outcome = "win"
+		case out.RealizedPnL.IsNegative():
+			out.Outcome = "loss"
+		default:
+			out.Outcome = "partial"
+		}
+	}
+	return out
+}
+
+// pnlDiscrepancies compares at 6 decimal places so numeric round-trips do
+// not show up as drift.
+func pnlDiscrepancies(stored *models.PnLRecord, r PlanPnLRecalc) []PnLDiscrepancy {
+	out := []PnLDiscrepancy{}
+	const places = 6
+	compare := func(field string, have *decimal.Decimal, want *decimal.Decimal) {
+		switch {
+		case have == nil && want == nil:
+			return
+		case have == nil:
+			if want.Round(places).IsZero() {
+				return
+			}
+			out = append(out, PnLDiscrepancy{Field: field, Recalculated: want.String()})
+		case want == nil:
+			v := have.String()
+			out = append(out, PnLDiscrepancy{Field: field, Stored: &v})
+		case !have.Round(places).Equal(want.Round(places)):
+			v := have.String()
+			out = append(out, PnLDiscrepancy{Field: field, Stored: &v, Recalculated: want.String()})
+		}
+	}
+	var have, haveROI *decimal.Decimal
+	if stored != nil {
+		have, haveROI = stored.RealizedPnL, stored.RealizedROI
+	}
+	realized := r.RealizedPnL
+	compare("realized_pnl", have, &realized)
+	compare("realized_roi", haveROI, r.RealizedROI)
+	if r.Settled && (stored == nil || stored.Outcome != r.Outcome) {
+		d := PnLDiscrepancy{Field: "outcome", Recalculated: r.Outcome}
+		if stored != nil {
+			v := stored.Outcome
+			d.Stored = &v
+		}
+		out = append(out, d)
+	}
+	return out
+}
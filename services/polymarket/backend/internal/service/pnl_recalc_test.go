@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestRecalcPlanPnL_SettledWithPartialExit(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	fills := []models.Fill{
+		{TokenID: "yes", Direction: "BUY_YES", FilledSize: decimal.NewFromInt(10), AvgPrice: decimal.RequireFromString("0.40"), Fee: decimal.RequireFromString("0.1"), FilledAt: t0},
+		{TokenID: "yes", Direction: "SELL_YES", FilledSize: decimal.NewFromInt(5), AvgPrice: decimal.RequireFromString("0.70"), FilledAt: t0.Add(time.Hour)},
+	}
+	tokens := []models.Token{{ID: "yes", MarketID: "m1", Outcome: "Yes"}}
+	got := recalcPlanPnL(7, fills, tokens, map[string]string{"m1": "YES"}, nil)
+
+	// Cost 4.10, sale 3.50, 5 shares paid out at 1.
+	if !got.RealizedPnL.Equal(decimal.RequireFromString("4.4")) {
+		t.Fatalf("realized=%s want 4.4", got.RealizedPnL)
+	}
+	if !got.Settled || got.Outcome != "win" || !got.UnrealizedPnL.IsZero() {
+		t.Fatalf("settled=%v outcome=%s unrealized=%s", got.Settled, got.Outcome, got.UnrealizedPnL)
+	}
+	if got.RealizedROI == nil || !got.RealizedROI.Round(4).Equal(decimal.RequireFromString("1.0732")) {
+		t.Fatalf("roi=%v", got.RealizedROI)
+	}
+	if row := got.Tokens[0]; row.MarkSource != MarkSettlement || !row.OpenSize.IsZero() {
+		t.Fatalf("row=%+v", row)
+	}
+
+	// The old settle ignored the sell and booked all 10 shares at payout.
+	stale := decimal.RequireFromString("5.9")
+	stored := &models.PnLRecord{PlanID: 7, RealizedPnL: &stale, Outcome: "win"}
+	diffs := pnlDiscrepancies(stored, got)
+	if len(diffs) != 2 || diffs[0].Field != "realized_pnl" || diffs[1].Field != "realized_roi" {
+		t.Fatalf("diffs=%+v", diffs)
+	}
+	if *diffs[0].Stored != "5.9" || diffs[0].Recalculated != "4.4" {
+		t.Fatalf("realized diff=%+v", diffs[0])
+	}
+
+	exact := got.RealizedPnL.Add(decimal.RequireFromString("0.0000001"))
+	stored = &models.PnLRecord{PlanID: 7, RealizedPnL: &exact, RealizedROI: got.RealizedROI, Outcome: "win"}
+	if diffs := pnlDiscrepancies(stored, got); len(diffs) != 0 {
+		t.Fatalf("expected no drift, got %+v", diffs)
+	}
+}
+
+func TestRecalcPlanPnL_OpenMarkedToBook(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	fills := []models.Fill{
+		{TokenID: "no", Direction: "BUY_NO", FilledSize: decimal.NewFromInt(10), AvgPrice: decimal.RequireFromString("0.30"), FilledAt: t0},
+		{TokenID: "other", Direction: "BUY_YES", FilledSize: decimal.NewFromInt(4), AvgPrice: decimal.RequireFromString("0.50"), FilledAt: t0},
+	}
+	tokens := []models.Token{{ID: "no", MarketID: "m2", Outcome: "No"}, {ID: "other", MarketID: "m3", Outcome: "Yes"}}
+	marks := map[string]decimal.Decimal{"no": decimal.RequireFromString("0.25")}
+	got := recalcPlanPnL(8, fills, tokens, nil, marks)
+
+	if got.Settled || got.Outcome != "pending" {
+		t.Fatalf("settled=%v outcome=%s", got.Settled, got.Outcome)
+	}
+	if !got.RealizedPnL.IsZero() || !got.UnrealizedPnL.Equal(decimal.RequireFromString("-0.5")) {
+		t.Fatalf("realized=%s unrealized=%s", got.RealizedPnL, got.UnrealizedPnL)
+	}
+	if got.Tokens[0].MarkSource != MarkBook || got.Tokens[1].MarkSource != MarkLastFill {
+		t.Fatalf("mark sources %s %s", got.Tokens[0].MarkSource, got.Tokens[1].MarkSource)
+	}
+	if diffs := pnlDiscrepancies(nil, got); len(diffs) != 0 {
+		t.Fatalf("pending plan without record should not drift: %+v", diffs)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// PortfolioAnomalyService diffs consecutive portfolio snapshots and flags
+// cost basis jumps, PnL swings and position count changes the fills in
+// between do not explain.
+type PortfolioAnomalyService struct {
+	Repo   repository.Repository
+	Config config.PortfolioDiffConfig
+	Logger *zap.Logger
+	// Notify routes flags to the notification dispatcher; nil broadcasts
+	// through the platform client in ctx.
+	Notify func(ctx context.Context, event, message string) error
+}
+
+// Check compares the latest snapshot with the one before, stores its flags
+// and notifies the ones not stored before. It returns the new flags.
+func (s *PortfolioAnomalyService) Check(ctx context.Context) ([]models.PortfolioAnomaly, error) {
+	if s == nil || s.Repo == nil || !s.Config.Enabled {
+		return nil, nil
+	}
+	snaps, err := s.Repo.ListPortfolioSnapshots(ctx, repository.ListPortfolioSnapshotsParams{Limit: 2})
+	if err != nil || len(snaps) < 2 {
+		return nil, err
+	}
+	curr, prev := snaps[0], snaps[1]
+	// Snapshots are keyed by hour but taken anywhere in it, so fills are
+	// counted over both hours and everything between.
+	fills, err := s.Repo.CountFillsBetween(ctx, prev.SnapshotAt, curr.SnapshotAt.Add(time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	var created []models.PortfolioAnomaly
+	for _, item := range DiffPortfolioSnapshots(prev, curr, fills, s.Config) {
+		inserted, err := s.Repo.InsertPortfolioAnomaly(ctx, &item)
+		if err != nil {
+			return created, err
+		}
+		if inserted {
+			created = append(created, item)
+			s.notify(ctx, item)
+		}
+	}
+	return created, nil
+}
+
+// DiffPortfolioSnapshots flags the changes from prev to curr that cross
+// cfg's thresholds; fills is the number of fills between the two.
+func DiffPortfolioSnapshots(prev, curr models.PortfolioSnapshot, fills int64, cfg config.PortfolioDiffConfig) []models.PortfolioAnomaly {
+	var out []models.PortfolioAnomaly
+	flag := func(kind string, previous, current, threshold float64, message string) {
+		out = append(out, models.PortfolioAnomaly{
+			SnapshotID:     curr.ID,
+			PrevSnapshotID: prev.ID,
+			SnapshotAt:     curr.SnapshotAt,
+			Kind:           kind,
+			Previous:       previous,
+			Current:        current,
+			Change:         current - previous,
+			Threshold:      threshold,
+			Fills:          fills,
+			Message:        message,
+		})
+	}
+	span := fmt.Sprintf("%s -> %s", prev.SnapshotAt.UTC().Format(time.RFC3339), curr.SnapshotAt.UTC().Format(time.RFC3339))
+
+	prevCost, currCost := prev.TotalCostBasis.InexactFloat64(), curr.TotalCostBasis.InexactFloat64()
+	if delta := math.Abs(currCost - prevCost); cfg.CostBasisJumpPct > 0 && delta > 0 && delta >= cfg.MinChangeUSD {
+		// From a flat book any cost basis is a full jump.
+		pct := 100.0
+		if prevCost != 0 {
+			pct = delta / math.Abs(prevCost) * 100
+		}
+		if pct >= cfg.CostBasisJumpPct {
+			flag(models.PortfolioAnomalyCostBasisJump, prevCost, currCost, cfg.CostBasisJumpPct,
+				fmt.Sprintf("cost basis %.2f -> %.2f (%.1f%% move) over %s with %d fills", prevCost, currCost, pct, span, fills))
+		}
+	}
+
+	prevPnL := prev.UnrealizedPnL.Add(prev.RealizedPnL).InexactFloat64()
+	currPnL := curr.UnrealizedPnL.Add(curr.RealizedPnL).InexactFloat64()
+	if delta := math.Abs(currPnL - prevPnL); cfg.PnLSwingUSD > 0 && delta >= cfg.PnLSwingUSD && delta >= cfg.MinChangeUSD {
+		flag(models.PortfolioAnomalyPnLSwing, prevPnL, currPnL, cfg.PnLSwingUSD,
+			fmt.Sprintf("total pnl %.2f -> %.2f (%+.2f) over %s", prevPnL, currPnL, currPnL-prevPnL, span))
+	}
+
+	// A fill opens or closes at most one position, so a bigger count change
+	// came from somewhere else: an import, a rebuild or a manual edit.
+	if delta := curr.TotalPositions - prev.TotalPositions; int64(absInt(delta)) > fills {
+		flag(models.PortfolioAnomalyPositionCount, float64(prev.TotalPositions), float64(curr.TotalPositions), float64(fills),
+			fmt.Sprintf("open positions %d -> %d over %s with only %d fills", prev.TotalPositions, curr.TotalPositions, span, fills))
+	}
+	return out
+}
+
+func (s *PortfolioAnomalyService) notify(ctx context.Context, item models.PortfolioAnomaly) {
+	if s.Logger != nil {
+		s.Logger.Warn("portfolio anomaly",
+			zap.String("kind", item.Kind),
+			zap.Uint64("snapshot_id", item.SnapshotID),
+			zap.Float64("change", item.Change),
+		)
+	}
+	paas.LogBestEffortCtx(ctx, "polymarket_portfolio_anomaly", "warn", map[string]any{
+		"kind":        item.Kind,
+		"snapshot_id": item.SnapshotID,
+		"snapshot_at": item.SnapshotAt,
+		"previous":    item.Previous,
+		"current":     item.Current,
+		"fills":       item.Fills,
+	})
+	notify := s.Notify
+	if notify == nil {
+		notify = paas.BroadcastCtx
+	}
+	message := "[warning] polymarket portfolio anomaly " + item.Kind + ": " + item.Message
+	if err := notify(ctx, s.Config.NotifyEvent, message); err != nil && s.Logger != nil {
+		s.Logger.Warn("portfolio anomaly notify failed", zap.String("kind", item.Kind), zap.Error(err))
+	}
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type portfolioAnomalyRepo struct {
+	repository.Repository
+	snaps  []models.PortfolioSnapshot
+	fills  int64
+	stored map[string]bool
+}
+
+func (r *portfolioAnomalyRepo) ListPortfolioSnapshots(ctx context.Context, params repository.ListPortfolioSnapshotsParams) ([]models.PortfolioSnapshot, error) {
+	return r.snaps, nil
+}
+
+func (r *portfolioAnomalyRepo) CountFillsBetween(ctx context.Context, since, until time.Time) (int64, error) {
+	return r.fills, nil
+}
+
+func (r *portfolioAnomalyRepo) InsertPortfolioAnomaly(ctx context.Context, item *models.PortfolioAnomaly) (bool, error) {
+	key := item.Kind
+	if r.stored[key] {
+		return false, nil
+	}
+	r.stored[key] = true
+	return true, nil
+}
+
+func portfolioSnap(id uint64, at time.Time, positions int, cost, unrealized, realized float64) models.PortfolioSnapshot {
+	return models.PortfolioSnapshot{
+		ID:             id,
+		SnapshotAt:     at,
+		TotalPositions: positions,
+		TotalCostBasis: decimal.NewFromFloat(cost),
+		UnrealizedPnL:  decimal.NewFromFloat(unrealized),
+		RealizedPnL:    decimal.NewFromFloat(realized),
+	}
+}
+
+func TestDiffPortfolioSnapshots(t *testing.T) {
+	cfg := config.PortfolioDiffConfig{Enabled: true, CostBasisJumpPct: 50, PnLSwingUSD: 500, MinChangeUSD: 50}
+	at := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	prev := portfolioSnap(1, at, 4, 1000, 20, 0)
+
+	kinds := func(items []models.PortfolioAnomaly) string {
+		out := make([]string, 0, len(items))
+		for _, it := range items {
+			out = append(out, it.Kind)
+		}
+		return strings.Join(out, ",")
+	}
+	cases := []struct {
+		name  string
+		curr  models.PortfolioSnapshot
+		fills int64
+		want  string
+	}{
+		{"quiet hour", portfolioSnap(2, at.Add(time.Hour), 4, 1100, 60, 0), 0, ""},
+		{"cost basis jump", portfolioSnap(2, at.Add(time.Hour), 5, 1600, 20, 0), 1, "cost_basis_jump"},
+		{"small base ignored", portfolioSnap(2, at.Add(time.Hour), 4, 1040, 20, 0), 0, ""},
+		{"pnl swing", portfolioSnap(2, at.Add(time.Hour), 4, 1000, -300, -200), 0, "pnl_swing"},
+		{"positions without fills", portfolioSnap(2, at.Add(time.Hour), 7, 1000, 20, 0), 2, "position_count"},
+	}
+	for _, tc := range cases {
+		if got := kinds(DiffPortfolioSnapshots(prev, tc.curr, tc.fills, cfg)); got != tc.want {
+			t.Fatalf("%s: flags=%q want %q", tc.name, got, tc.want)
+		}
+	}
+
+	// A flat book opening is a full jump, once it clears the floor.
+	flat := portfolioSnap(1, at, 0, 0, 0, 0)
+	if got := kinds(DiffPortfolioSnapshots(flat, portfolioSnap(2, at.Add(time.Hour), 1, 200, 0, 0), 1, cfg)); got != "cost_basis_jump" {
+		t.Fatalf("from flat flags=%q", got)
+	}
+}
+
+func TestPortfolioAnomalyCheckNotifiesOnce(t *testing.T) {
+	at := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	repo := &portfolioAnomalyRepo{
+		snaps:  []models.PortfolioSnapshot{portfolioSnap(2, at.Add(time.Hour), 9, 1000, 0, 0), portfolioSnap(1, at, 3, 1000, 0, 0)},
+		stored: map[string]bool{},
+	}
+	var sent []string
+	svc := &PortfolioAnomalyService{
+		Repo:   repo,
+		Config: config.PortfolioDiffConfig{Enabled: true, CostBasisJumpPct: 50, PnLSwingUSD: 500, NotifyEvent: "portfolio"},
+		Notify: func(ctx context.Context, event, message string) error {
+			sent = append(sent, event+":"+message)
+			return nil
+		},
+	}
+	items, err := svc.Check(context.Background())
+	if err != nil || len(items) != 1 || items[0].Kind != models.PortfolioAnomalyPositionCount || items[0].SnapshotID != 2 || items[0].PrevSnapshotID != 1 {
+		t.Fatalf("items=%+v err=%v", items, err)
+	}
+	if len(sent) != 1 || !strings.HasPrefix(sent[0], "portfolio:") || !strings.Contains(sent[0], "3 -> 9") {
+		t.Fatalf("sent=%v", sent)
+	}
+	// The next run, or another replica, finds the flag already stored.
+	if items, err := svc.Check(context.Background()); err != nil || len(items) != 0 || len(sent) != 1 {
+		t.Fatalf("second check items=%d err=%v sent=%d", len(items), err, len(sent))
+	}
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// Values of models.Position.Source.
+const (
+	PositionSourceSystem   = "system"
+	PositionSourceExternal = "external"
+)
+
+// externalStrategyName tags imported positions, which no strategy opened.
+const externalStrategyName = "external"
+
+// ExternalPosition is one wallet holding reported by an import adapter.
+type ExternalPosition struct {
+	TokenID     string  `json:"token_id"`
+	ConditionID string  `json:"condition_id,omitempty"`
+	Outcome     string  `json:"outcome,omitempty"`
+	Title       string  `json:"title,omitempty"`
+	Size        float64 `json:"size"`
+	AvgPrice    float64 `json:"avg_price"`
+	CurPrice    float64 `json:"cur_price,omitempty"`
+	RealizedPnL float64 `json:"realized_pnl,omitempty"`
+}
+
+// PositionImportAdapter reads the current holdings of a wallet. A holding
+// missing from the result is treated as closed.
+type PositionImportAdapter interface {
+	Name() string
+	FetchPositions(ctx context.Context, wallet string) ([]ExternalPosition, error)
+}
+
+// DataAPIPositionAdapter reads holdings from Polymarket's public data-api
+// positions endpoint.
+type DataAPIPositionAdapter struct {
+	Endpoint string
+	HTTP     *http.Client
+}
+
+func (a *DataAPIPositionAdapter) Name() string { return "data_api" }
+
+func (a *DataAPIPositionAdapter) FetchPositions(ctx context.Context, wallet string) ([]ExternalPosition, error) {
+	endpoint := strings.TrimSpace(a.Endpoint)
+	if endpoint == "" {
+		endpoint = "https://data-api.polymarket.com/positions"
+	}
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	endpoint += sep + "user=" + url.QueryEscape(wallet) + "&sizeThreshold=0&limit=500"
+	client := a.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("http %d", resp.StatusCode)
+	}
+	var items []struct {
+		Asset       string  `json:"asset"`
+		ConditionID string  `json:"conditionId"`
+		Size        float64 `json:"size"`
+		AvgPrice    float64 `json:"avgPrice"`
+		CurPrice    float64 `json:"curPrice"`
+		RealizedPnL float64 `json:"realizedPnl"`
+		Title       string  `json:"title"`
+		Outcome     string  `json:"outcome"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, err
+	}
+	out := make([]ExternalPosition, 0, len(items))
+	for _, it := range items {
+		out = append(out, ExternalPosition{
+			TokenID:     it.Asset,
+			ConditionID: it.ConditionID,
+			Outcome:     it.Outcome,
+			Title:       it.Title,
+			Size:        it.Size,
+			AvgPrice:    it.AvgPrice,
+			CurPrice:    it.CurPrice,
+			RealizedPnL: it.RealizedPnL,
+		})
+	}
+	return out, nil
+}
+
+// StaticPositionAdapter serves holdings supplied by the caller, e.g. a
+// position list posted to the import endpoint.
+type StaticPositionAdapter []ExternalPosition
+
+func (StaticPositionAdapter) Name() string { return "payload" }
+
+func (a StaticPositionAdapter) FetchPositions(context.Context, string) ([]ExternalPosition, error) {
+	return a, nil
+}
+
+// PositionImportReport summarizes one wallet import.
+type PositionImportReport struct {
+	Wallet  string `json:"wallet"`
+	Adapter string `json:"adapter"`
+	Fetched int    `json:"fetched"`
+	Created int    `json:"created"`
+	Updated int    `json:"updated"`
+	Closed  int    `json:"closed"`
+	// SkippedTokens are held by positions built from our own fills; those
+	// stay owned by the fills ledger.
+	SkippedTokens []string `json:"skipped_tokens,omitempty"`
+}
+
+// ExternalPositionService imports wallet holdings acquired outside this system
+// into positions with Source=external, and reconciles them periodically.
+type ExternalPositionService struct {
+	Repo    repository.Repository
+	Logger  *zap.Logger
+	Flags   *SystemSettingsService
+	Adapter PositionImportAdapter
+	Config  config.PositionImportConfig
+
+	mu      sync.Mutex
+	lastRun *time.Time
+	lastErr string
+	reports map[string]PositionImportReport
+}
+
+func (s *ExternalPositionService) Run(ctx context.Context) error {
+	if s == nil || s.Repo == nil || s.Adapter == nil {
+		return nil
+	}
+	interval := s.Config.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	s.reconcileIfEnabled(ctx)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			s.reconcileIfEnabled(ctx)
+		}
+	}
+}
+
+func (s *ExternalPositionService) reconcileIfEnabled(ctx context.Context) {
+	if s.Flags != nil && !s.Flags.IsEnabled(ctx, FeaturePositionImport, false) {
+		return
+	}
+	for _, wallet := range s.Config.Wallets {
+		wallet = strings.TrimSpace(wallet)
+		if wallet == "" {
+			continue
+		}
+		if _, err := s.Import(ctx, wallet, nil); err != nil && s.Logger != nil {
+			s.Logger.Warn("external position reconcile failed", zap.String("wallet", wallet), zap.Error(err))
+		}
+	}
+}
+
+// Import reconciles the external positions of wallet with the adapter's
+// holdings; a nil adapter uses the service adapter. Imported rows are created
+// or updated, external rows the wallet no longer holds are closed, and tokens
+// already tracked from our own fills are skipped.
+func (s *ExternalPositionService) Import(ctx context.Context, wallet string, adapter PositionImportAdapter) (*PositionImportReport, error) {
+	if s == nil || s.Repo == nil {
+		return nil, nil
+	}
+	wallet = strings.ToLower(strings.TrimSpace(wallet))
+	if wallet == "" {
+		return nil, fmt.Errorf("wallet is required")
+	}
+	if adapter == nil {
+		adapter = s.Adapter
+	}
+	if adapter == nil {
+		return nil, fmt.Errorf("no position import adapter configured")
+	}
+	report, err := s.importWallet(ctx, wallet, adapter)
+	s.record(wallet, report, err)
+	return report, err
+}
+
+func (s *ExternalPositionService) importWallet(ctx context.Context, wallet string, adapter PositionImportAdapter) (*PositionImportReport, error) {
+	fetched, err := adapter.FetchPositions(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", adapter.Name(), err)
+	}
+	report := &PositionImportReport{Wallet: wallet, Adapter: adapter.Name(), Fetched: len(fetched)}
+	markets := s.marketsByCondition(ctx, fetched)
+	now := time.Now().UTC()
+
+	seen := map[string]struct{}{}
+	for _, ext := range fetched {
+		tokenID := strings.TrimSpace(ext.TokenID)
+		if tokenID == "" || ext.Size <= 0 {
+			continue
+		}
+		seen[tokenID] = struct{}{}
+		existing, err := s.Repo.GetPositionByTokenID(ctx, s.tenant(), tokenID)
+		if err != nil {
+			return report, err
+		}
+		if existing != nil && existing.Source != PositionSourceExternal {
+			report.SkippedTokens = append(report.SkippedTokens, tokenID)
+			continue
+		}
+		pos := externalPositionRow(existing, ext, wallet, markets, now)
+		if pos.Tenant == "" {
+			pos.Tenant = s.tenant()
+		}
+		if err := s.Repo.UpsertPosition(ctx, pos); err != nil {
+			return report, err
+		}
+		if existing == nil {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+	}
+
+	// External rows of this wallet missing from the holdings were sold or
+	// redeemed outside the system.
+	source, status := PositionSourceExternal, "open"
+	open, err := s.Repo.ListPositions(ctx, repository.ListPositionsParams{Limit: 1000, Source: &source, Status: &status})
+	if err != nil {
+		return report, err
+	}
+	for _, pos := range open {
+		if pos.ExternalWallet != wallet {
+			continue
+		}
+		if _, ok := seen[pos.TokenID]; ok {
+			continue
+		}
+		if err := s.Repo.ClosePosition(ctx, pos.ID, pos.RealizedPnL, now); err != nil {
+			return report, err
+		}
+		report.Closed++
+	}
+	return report, nil
+}
+
+// externalPositionRow maps a holding onto its position row. The adapter's
+// size and average price replace ours: the wallet is the source of truth.
+func externalPositionRow(existing *models.Position, ext ExternalPosition, wallet string, markets map[string]string, now time.Time) *models.Position {
+	pos := &models.Position{
+		TokenID:      strings.TrimSpace(ext.TokenID),
+		Source:       PositionSourceExternal,
+		StrategyName: externalStrategyName,
+		OpenedAt:     now,
+		CreatedAt:    now,
+	}
+	if existing != nil {
+		pos = existing
+	}
+	if pos.Status != "open" {
+		pos.Status = "open"
+		pos.OpenedAt = now
+		pos.ClosedAt = nil
+	}
+	marketID := markets[strings.TrimSpace(ext.ConditionID)]
+	if marketID == "" {
+		marketID = pos.MarketID
+	}
+	if marketID == "" {
+		marketID = strings.TrimSpace(ext.ConditionID)
+	}
+	pos.MarketID = marketID
+	pos.ExternalWallet = wallet
+	pos.Direction = externalDirection(ext.Outcome)
+
+	size := decimal.NewFromFloat(ext.Size)
+	avg := decimal.NewFromFloat(ext.AvgPrice)
+	cur := avg
+	if ext.CurPrice > 0 {
+		cur = decimal.NewFromFloat(ext.CurPrice)
+	}
+	pos.Quantity = size
+	pos.AvgEntryPrice = avg
+	pos.CurrentPrice = cur
+	pos.CostBasis = size.Mul(avg)
+	pos.UnrealizedPnL = cur.Sub(avg).Mul(size)
+	pos.RealizedPnL = decimal.NewFromFloat(ext.RealizedPnL)
+	pos.UpdatedAt = now
+	return pos
+}
+
+// externalDirection maps a holding's outcome to a position direction: its
+// outcome code, so non-binary holdings keep their outcome. Unlabelled
+// holdings are recorded as YES.
+func externalDirection(outcome string) string {
+	if code := models.OutcomeCode(outcome); code != "" {
+		return code
+	}
+	return models.OutcomeYes
+}
+
+func (s *ExternalPositionService) marketsByCondition(ctx context.Context, items []ExternalPosition) map[string]string {
+	ids := make([]string, 0, len(items))
+	for _, it := range items {
+		if id := strings.TrimSpace(it.ConditionID); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	out := map[string]string{}
+	if len(ids) == 0 {
+		return out
+	}
+	if markets, err := s.Repo.FindMarketsByConditionIDs(ctx, ids); err == nil {
+		for _, m := range markets {
+			out[m.ConditionID] = m.ID
+		}
+	}
+	return out
+}
+
+func (s *ExternalPositionService) tenant() string {
+	if v := strings.TrimSpace(s.Config.Tenant); v != "" {
+		return v
+	}
+	return "default"
+}
+
+func (s *ExternalPositionService) record(wallet string, report *PositionImportReport, err error) {
+	now := time.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRun = &now
+	s.lastErr = ""
+	if err != nil {
+		s.lastErr = err.Error()
+	}
+	if report != nil {
+		if s.reports == nil {
+			s.reports = map[string]PositionImportReport{}
+		}
+		s.reports[wallet] = *report
+	}
+}
+
+// PositionImportStatus is exposed on the import status endpoint.
+type PositionImportStatus struct {
+	Adapter   string                 `json:"adapter"`
+	Wallets   []string               `json:"wallets"`
+	Interval  string                 `json:"interval"`
+	LastRunAt *time.Time             `json:"last_run_at,omitempty"`
+	LastError string                 `json:"last_error,omitempty"`
+	Reports   []PositionImportReport `json:"reports"`
+}
+
+func (s *ExternalPositionService) Status() PositionImportStatus {
+	out := PositionImportStatus{Wallets: []string{}, Reports: []PositionImportReport{}}
+	if s == nil {
+		return out
+	}
+	if s.Adapter != nil {
+		out.Adapter = s.Adapter.Name()
+	}
+	for _, w := range s.Config.Wallets {
+		if w = strings.TrimSpace(w); w != "" {
+			out.Wallets = append(out.Wallets, w)
+		}
+	}
+	out.Interval = s.Config.Interval.String()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out.LastRunAt = s.lastRun
+	out.LastError = s.lastErr
+	for _, r := range s.reports {
+		out.Reports = append(out.Reports, r)
+	}
+	sort.Slice(out.Reports, func(i, j int) bool { return out.Reports[i].Wallet < out.Reports[j].Wallet })
+	return out
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestDataAPIPositionAdapter_FetchPositions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("user") != "0xabc" {
+			t.Errorf("user=%q", r.URL.Query().Get("user"))
+		}
+		_, _ = w.Write([]byte(`[{"asset":"t1","conditionId":"c1","size":100,"avgPrice":0.4,"curPrice":0.55,"realizedPnl":3,"outcome":"No"}]`))
+	}))
+	defer srv.Close()
+
+	got, err := (&DataAPIPositionAdapter{Endpoint: srv.URL}).FetchPositions(context.Background(), "0xabc")
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if len(got) != 1 || got[0].TokenID != "t1" || got[0].Size != 100 || got[0].RealizedPnL != 3 {
+		t.Fatalf("got=%+v", got)
+	}
+}
+
+func TestExternalPositionRow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	ext := ExternalPosition{TokenID: "t1", ConditionID: "c1", Outcome: "No", Size: 100, AvgPrice: 0.4, CurPrice: 0.55}
+	pos := externalPositionRow(nil, ext, "0xabc", map[string]string{"c1": "m1"}, now)
+	if pos.Source != PositionSourceExternal || pos.MarketID != "m1" || pos.Direction != "NO" || pos.Status != "open" {
+		t.Fatalf("pos=%+v", pos)
+	}
+	if !pos.CostBasis.Equal(decimal.NewFromInt(40)) || !pos.UnrealizedPnL.Equal(decimal.NewFromInt(15)) {
+		t.Fatalf("cost=%s upnl=%s", pos.CostBasis, pos.UnrealizedPnL)
+	}
+
+	// A closed external row reopens when the wallet holds the token again.
+	closedAt := now.Add(-time.Hour)
+	existing := &models.Position{ID: 7, TokenID: "t1", MarketID: "m1", Source: PositionSourceExternal, Status: "closed", ClosedAt: &closedAt}
+	pos = externalPositionRow(existing, ext, "0xabc", nil, now)
+	if pos.ID != 7 || pos.Status != "open" || pos.ClosedAt != nil || !pos.OpenedAt.Equal(now) || pos.MarketID != "m1" {
+		t.Fatalf("reopened=%+v", pos)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 
+	"polymarket/internal/events"
 	"polymarket/internal/models"
 	"polymarket/internal/repository"
 )
@@ -16,6 +17,9 @@ type PositionManager struct {
 	Repo   repository.Repository
 	Logger *zap.Logger
 	Flags  *SystemSettingsService
+	// Wake, when set, triggers a run on fill and plan status events besides
+	// the interval poll.
+	Wake <-chan events.Event
 }
 
 func (m *PositionManager) Run(ctx context.Context, interval time.Duration) error {
@@ -36,6 +40,8 @@ func (m *PositionManager) Run(ctx context.Context, interval time.Duration) error
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-t.C:
+		case <-m.Wake:
+			events.Drain(m.Wake)
 		}
 	}
 }
@@ -132,11 +138,10 @@ func (m *PositionManager) RunOnce(ctx context.Context) error {
 	return nil
 }
 
+// closeSideByDirection sells the position's outcome: SELL_NO, SELL_LAKERS.
 func closeSideByDirection(direction string) string {
-	switch strings.ToUpper(strings.TrimSpace(direction)) {
-	case "NO":
-		return "SELL_NO"
-	default:
+	if models.OutcomeCode(direction) == "" {
 		return "SELL_YES"
 	}
+	return models.Direction("SELL", direction)
 }
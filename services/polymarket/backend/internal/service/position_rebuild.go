@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+)
+
+// PositionState is the part of a position that can be derived from fills.
+type PositionState struct {
+	Quantity      decimal.Decimal `json:"quantity"`
+	AvgEntryPrice decimal.Decimal `json:"avg_entry_price"`
+	CostBasis     decimal.Decimal `json:"cost_basis"`
+	RealizedPnL   decimal.Decimal `json:"realized_pnl"`
+	Status        string          `json:"status"`
+}
+
+type PositionRebuildEntry struct {
+	TokenID    string         `json:"token_id"`
+	Tenant     string         `json:"tenant"`
+	PositionID *uint64        `json:"position_id,omitempty"`
+	Fills      int            `json:"fills"`
+	Before     *PositionState `json:"before,omitempty"`
+	After      *PositionState `json:"after,omitempty"`
+	Changed    bool           `json:"changed"`
+	Note       string         `json:"note,omitempty"`
+}
+
+type PositionRebuildReport struct {
+	TokenID string                 `json:"token_id,omitempty"`
+	Applied bool                   `json:"applied"`
+	Scanned int                    `json:"scanned"`
+	Changed int                    `json:"changed"`
+	Entries []PositionRebuildEntry `json:"entries"`
+}
+
+// RebuildFromFills replays the fills ledger in filled_at order and compares the
+// result with the stored positions. Fills are grouped by the tenant of their
+// plan, since each desk holds its own position in a token. Only positions
+// whose rebuilt state differs are listed unless tokenID is set. With
+// apply=false nothing is written; with apply=true the differing rows are
+// overwritten. Positions without any fills are reported but left untouched.
+// A request scoped to a tenant only rebuilds that tenant's positions.
+func (s *PositionSyncService) RebuildFromFills(ctx context.Context, tokenID string, apply bool) (*PositionRebuildReport, error) {
+	if s == nil || s.Repo == nil {
+		return nil, nil
+	}
+	tokenID = strings.TrimSpace(tokenID)
+	fills, err := s.Repo.ListFillsChronological(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	scope := paas.TenantFromContext(ctx)
+	byTenant, tenants, err := s.fillsByTenant(ctx, fills)
+	if err != nil {
+		return nil, err
+	}
+	report := &PositionRebuildReport{TokenID: tokenID, Applied: apply, Entries: []PositionRebuildEntry{}}
+
+	if tokenID != "" && len(fills) == 0 {
+		tenant := scope
+		if tenant == "" {
+			tenant = paas.DefaultTenant
+		}
+		pos, err := s.Repo.GetPositionByTokenID(ctx, tenant, tokenID)
+		if err != nil {
+			return nil, err
+		}
+		entry := PositionRebuildEntry{TokenID: tokenID, Tenant: tenant, Note: "no fills"}
+		if pos != nil {
+			entry.PositionID = &pos.ID
+			entry.Before = positionState(*pos)
+		}
+		report.Entries = append(report.Entries, entry)
+		return report, nil
+	}
+
+	for _, tenant := range tenants {
+		if scope != "" && tenant != scope {
+			continue
+		}
+		order, byToken := groupFillsByToken(byTenant[tenant])
+		for _, tid := range order {
+			report.Scanned++
+			tokenFills := byToken[tid]
+			existing, err := s.Repo.GetPositionByTokenID(ctx, tenant, tid)
+			if err != nil {
+				return nil, err
+			}
+			rebuilt := replayFills(existing, tokenFills)
+			entry := PositionRebuildEntry{TokenID: tid, Tenant: tenant, Fills: len(tokenFills), After: positionState(*rebuilt)}
+			if existing != nil {
+				entry.PositionID = &existing.ID
+				entry.Before = positionState(*existing)
+			}
+			entry.Changed = entry.Before == nil || !entry.Before.equal(*entry.After)
+			if !entry.Changed {
+				if tokenID != "" {
+					report.Entries = append(report.Entries, entry)
+				}
+				continue
+			}
+			report.Changed++
+			if apply {
+				if existing == nil {
+					if err := s.fillPositionRefs(ctx, rebuilt, tokenFills[0]); err != nil {
+						entry.Note = err.Error()
+						report.Entries = append(report.Entries, entry)
+						continue
+					}
+				}
+				rebuilt.Tenant = tenant
+				rebuilt.UpdatedAt = time.Now().UTC()
+				if err := s.Repo.UpsertPosition(ctx, rebuilt); err != nil {
+					return nil, err
+				}
+				if s.Logger != nil {
+					s.Logger.Info("position rebuilt from fills", zap.String("tenant", tenant), zap.String("token_id", tid), zap.Int("fills", len(tokenFills)))
+				}
+			}
+			report.Entries = append(report.Entries, entry)
+		}
+	}
+	return report, nil
+}
+
+// fillsByTenant splits fills by the tenant of their plan, keeping fill
+// order, and returns the tenants in first-seen order.
+func (s *PositionSyncService) fillsByTenant(ctx context.Context, fills []models.Fill) (map[string][]models.Fill, []string, error) {
+	planTenant := map[uint64]string{}
+	byTenant := map[string][]models.Fill{}
+	var tenants []string
+	for _, f := range fills {
+		tenant, ok := planTenant[f.PlanID]
+		if !ok {
+			plan, err := s.Repo.GetExecutionPlanByID(ctx, f.PlanID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if plan != nil {
+				tenant = paas.NormalizeTenant(plan.Tenant)
+			}
+			if tenant == "" {
+				tenant = paas.DefaultTenant
+			}
+			planTenant[f.PlanID] = tenant
+		}
+		if _, ok := byTenant[tenant]; !ok {
+			tenants = append(tenants, tenant)
+		}
+		byTenant[tenant] = append(byTenant[tenant], f)
+	}
+	return byTenant, tenants, nil
+}
+
+// fillPositionRefs sets market, tenant and strategy on a position that only
+// exists in the fills ledger, using the plan behind its first fill.
+func (s *PositionSyncService) fillPositionRefs(ctx context.Context, pos *models.Position, first models.Fill) error {
+	tokens, err := s.Repo.ListTokensByIDs(ctx, []string{pos.TokenID})
+	if err != nil {
+		return err
+	}
+	if len(tokens) > 0 {
+		pos.MarketID = strings.TrimSpace(tokens[0].MarketID)
+	}
+	plan, err := s.Repo.GetExecutionPlanByID(ctx, first.PlanID)
+	if err != nil {
+		return err
+	}
+	if plan != nil {
+		pos.Tenant = plan.Tenant
+		pos.StrategyName = plan.StrategyName
+		if opp, _ := s.Repo.GetOpportunityByID(ctx, plan.OpportunityID); opp != nil && opp.EventID != nil {
+			pos.EventID = strings.TrimSpace(*opp.EventID)
+		}
+	}
+	if strings.TrimSpace(pos.Tenant) == "" {
+		pos.Tenant = "default"
+	}
+	pos.CreatedAt = time.Now().UTC()
+	return nil
+}
+
+// replayFills rebuilds a position from scratch. Descriptive fields (market,
+// tenant, strategy, current price) are kept from existing when present.
+func replayFills(existing *models.Position, fills []models.Fill) *models.Position {
+	pos := &models.Position{}
+	if existing != nil {
+		*pos = *existing
+	}
+	if len(fills) == 0 {
+		return pos
+	}
+	first := fills[0]
+	pos.TokenID = strings.TrimSpace(first.TokenID)
+	if existing == nil {
+		pos.CurrentPrice = fills[len(fills)-1].AvgPrice
+		pos.Direction = normalizePositionDirection(first.Direction)
+		if pos.Direction == "" {
+			pos.Direction = models.OutcomeYes
+		}
+	}
+	pos.Quantity = decimal.Zero
+	pos.AvgEntryPrice = decimal.Zero
+	pos.CostBasis = decimal.Zero
+	pos.RealizedPnL = decimal.Zero
+	pos.UnrealizedPnL = decimal.Zero
+	pos.Status = "open"
+	pos.ClosedAt = nil
+	pos.OpenedAt = first.FilledAt
+	for _, f := range fills {
+		applyFillToPosition(pos, f, f.FilledAt)
+	}
+	return pos
+}
+
+func groupFillsByToken(fills []models.Fill) ([]string, map[string][]models.Fill) {
+	order := []string{}
+	byToken := map[string][]models.Fill{}
+	for _, f := range fills {
+		tid := strings.TrimSpace(f.TokenID)
+		if tid == "" {
+			continue
+		}
+		if _, ok := byToken[tid]; !ok {
+			order = append(order, tid)
+		}
+		byToken[tid] = append(byToken[tid], f)
+	}
+	return order, byToken
+}
+
+func positionState(pos models.Position) *PositionState {
+	return &PositionState{
+		Quantity:      pos.Quantity,
+		AvgEntryPrice: pos.AvgEntryPrice,
+		CostBasis:     pos.CostBasis,
+		RealizedPnL:   pos.RealizedPnL,
+		Status:        pos.Status,
+	}
+}
+
+// equal compares at the column precision so numeric round-trips through the
+// database do not show up as drift.
+func (p PositionState) equal(o PositionState) bool {
+	const places = 8
+	return p.Status == o.Status &&
+		p.Quantity.Round(places).Equal(o.Quantity.Round(places)) &&
+		p.AvgEntryPrice.Round(places).Equal(o.AvgEntryPrice.Round(places)) &&
+		p.CostBasis.Round(places).Equal(o.CostBasis.Round(places)) &&
+		p.RealizedPnL.Round(places).Equal(o.RealizedPnL.Round(places))
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestReplayFills_BuySellCycle(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	fills := []models.Fill{
+		{TokenID: "tok", Direction: "BUY_YES", FilledSize: decimal.NewFromInt(10), AvgPrice: decimal.RequireFromString("0.40"), FilledAt: t0},
+		{TokenID: "tok", Direction: "BUY_YES", FilledSize: decimal.NewFromInt(10), AvgPrice: decimal.RequireFromString("0.60"), FilledAt: t0.Add(time.Hour)},
+		{TokenID: "tok", Direction: "SELL_YES", FilledSize: decimal.NewFromInt(5), AvgPrice: decimal.RequireFromString("0.70"), FilledAt: t0.Add(2 * time.Hour)},
+	}
+	existing := &models.Position{
+		TokenID:      "tok",
+		Quantity:     decimal.NewFromInt(99),
+		RealizedPnL:  decimal.NewFromInt(7),
+		CurrentPrice: decimal.RequireFromString("0.65"),
+		Status:       "open",
+	}
+	pos := replayFills(existing, fills)
+	if !pos.Quantity.Equal(decimal.NewFromInt(15)) {
+		t.Fatalf("quantity=%s want 15", pos.Quantity)
+	}
+	if !pos.AvgEntryPrice.Equal(decimal.RequireFromString("0.5")) {
+		t.Fatalf("avg=%s want 0.5", pos.AvgEntryPrice)
+	}
+	if !pos.RealizedPnL.Equal(decimal.RequireFromString("1")) {
+		t.Fatalf("realized=%s want 1", pos.RealizedPnL)
+	}
+	if !pos.CurrentPrice.Equal(decimal.RequireFromString("0.65")) {
+		t.Fatalf("current price not preserved: %s", pos.CurrentPrice)
+	}
+	if !pos.OpenedAt.Equal(t0) || pos.Status != "open" {
+		t.Fatalf("opened_at=%s status=%s", pos.OpenedAt, pos.Status)
+	}
+	if positionState(*existing).equal(*positionState(*pos)) {
+		t.Fatalf("expected drift against stored row")
+	}
+}
+
+func TestReplayFills_Closed(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	fills := []models.Fill{
+		{TokenID: "tok", Direction: "BUY_NO", FilledSize: decimal.NewFromInt(4), AvgPrice: decimal.RequireFromString("0.25"), FilledAt: t0},
+		{TokenID: "tok", Direction: "SELL_NO", FilledSize: decimal.NewFromInt(4), AvgPrice: decimal.RequireFromString("0.50"), FilledAt: t0.Add(time.Hour)},
+	}
+	pos := replayFills(nil, fills)
+	if pos.Status != "closed" || pos.ClosedAt == nil || !pos.ClosedAt.Equal(t0.Add(time.Hour)) {
+		t.Fatalf("status=%s closed_at=%v", pos.Status, pos.ClosedAt)
+	}
+	if pos.Direction != "NO" {
+		t.Fatalf("direction=%s want NO", pos.Direction)
+	}
+	if !pos.RealizedPnL.Equal(decimal.NewFromInt(1)) || !pos.Quantity.IsZero() {
+		t.Fatalf("realized=%s qty=%s", pos.RealizedPnL, pos.Quantity)
+	}
+}
+
+func TestReplayFills_NonBinaryOutcome(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	fills := []models.Fill{
+		{TokenID: "tok", Direction: "BUY_OVER_2_5", FilledSize: decimal.NewFromInt(10), AvgPrice: decimal.RequireFromString("0.30"), FilledAt: t0},
+		{TokenID: "tok", Direction: "SELL_OVER_2_5", FilledSize: decimal.NewFromInt(4), AvgPrice: decimal.RequireFromString("0.55"), FilledAt: t0.Add(time.Hour)},
+	}
+	pos := replayFills(nil, fills)
+	if pos.Direction != "OVER_2_5" {
+		t.Fatalf("direction=%s want OVER_2_5", pos.Direction)
+	}
+	if !pos.Quantity.Equal(decimal.NewFromInt(6)) || !pos.RealizedPnL.Equal(decimal.NewFromInt(1)) {
+		t.Fatalf("qty=%s realized=%s", pos.Quantity, pos.RealizedPnL)
+	}
+	if got := closeSideByDirection(pos.Direction); got != "SELL_OVER_2_5" {
+		t.Fatalf("close side=%s", got)
+	}
+}
@@ -19,27 +19,41 @@ type PositionSyncService struct {
 }
 
 func (s *PositionSyncService) SyncFromFill(ctx context.Context, fill models.Fill) error {
-	if s == nil || s.Repo == nil {
+	if !s.Enabled(ctx) {
 		return nil
 	}
-	if s.Flags != nil && !s.Flags.IsEnabled(ctx, FeaturePositionSync, true) {
+	return s.ApplyFill(ctx, s.Repo, fill)
+}
+
+// Enabled reports whether fills should move positions.
+func (s *PositionSyncService) Enabled(ctx context.Context) bool {
+	if s == nil || s.Repo == nil {
+		return false
+	}
+	return s.Flags == nil || s.Flags.IsEnabled(ctx, FeaturePositionSync, true)
+}
+
+// ApplyFill is SyncFromFill through repo, e.g. one bound to the transaction
+// that records the fill, without the feature switch check.
+func (s *PositionSyncService) ApplyFill(ctx context.Context, repo repository.Repository, fill models.Fill) error {
+	if repo == nil {
 		return nil
 	}
 	tokenID := strings.TrimSpace(fill.TokenID)
 	if tokenID == "" {
 		return nil
 	}
-	plan, err := s.Repo.GetExecutionPlanByID(ctx, fill.PlanID)
+	plan, err := repo.GetExecutionPlanByID(ctx, fill.PlanID)
 	if err != nil || plan == nil {
 		return err
 	}
-	tokens, err := s.Repo.ListTokensByIDs(ctx, []string{tokenID})
+	tokens, err := repo.ListTokensByIDs(ctx, []string{tokenID})
 	if err != nil || len(tokens) == 0 {
 		return err
 	}
 	tok := tokens[0]
 
-	opp, _ := s.Repo.GetOpportunityByID(ctx, plan.OpportunityID)
+	opp, _ := repo.GetOpportunityByID(ctx, plan.OpportunityID)
 	eventID := ""
 	if opp != nil && opp.EventID != nil {
 		eventID = strings.TrimSpace(*opp.EventID)
@@ -47,14 +61,12 @@ func (s *PositionSyncService) SyncFromFill(ctx context.Context, fill models.Fill
 
 	direction := normalizePositionDirection(fill.Direction)
 	if direction == "" {
-		direction = "YES"
+		direction = models.OutcomeCode(tok.Outcome)
 	}
-	sideSign := fillSideSign(fill.Direction)
-	if sideSign == 0 {
-		sideSign = 1
+	if direction == "" {
+		direction = models.OutcomeYes
 	}
-
-	pos, err := s.Repo.GetPositionByTokenID(ctx, tokenID)
+	pos, err := repo.GetPositionByTokenID(ctx, plan.Tenant, tokenID)
 	if err != nil {
 		return err
 	}
@@ -63,6 +75,7 @@ func (s *PositionSyncService) SyncFromFill(ctx context.Context, fill models.Fill
 			TokenID:       tokenID,
 			MarketID:      strings.TrimSpace(tok.MarketID),
 			EventID:       eventID,
+			Tenant:        plan.Tenant,
 			Direction:     direction,
 			Quantity:      decimal.Zero,
 			AvgEntryPrice: decimal.Zero,
@@ -77,52 +90,11 @@ func (s *PositionSyncService) SyncFromFill(ctx context.Context, fill models.Fill
 		}
 	}
 
-	oldQty := pos.Quantity
-	oldAvg := pos.AvgEntryPrice
-	qtyDelta := fill.FilledSize.Mul(decimal.NewFromInt(int64(sideSign)))
-	newQty := oldQty.Add(qtyDelta)
-
-	if sideSign > 0 {
-		totalCost := pos.CostBasis.Add(fill.AvgPrice.Mul(fill.FilledSize)).Add(fill.Fee)
-		if newQty.GreaterThan(decimal.Zero) {
-			pos.AvgEntryPrice = totalCost.Div(newQty)
-		}
-		pos.CostBasis = totalCost
-	} else {
-		sellQty := fill.FilledSize
-		if sellQty.GreaterThan(oldQty) {
-			sellQty = oldQty
-		}
-		realizedDelta := fill.AvgPrice.Sub(oldAvg).Mul(sellQty).Sub(fill.Fee)
-		pos.RealizedPnL = pos.RealizedPnL.Add(realizedDelta)
-		if newQty.GreaterThan(decimal.Zero) {
-			pos.CostBasis = oldAvg.Mul(newQty)
-			pos.AvgEntryPrice = oldAvg
-		} else {
-			pos.CostBasis = decimal.Zero
-			pos.AvgEntryPrice = decimal.Zero
-		}
-	}
-
-	if newQty.LessThanOrEqual(decimal.Zero) {
-		pos.Quantity = decimal.Zero
-		pos.Status = "closed"
-		now := time.Now().UTC()
-		pos.ClosedAt = &now
-		pos.UnrealizedPnL = decimal.Zero
-	} else {
-		pos.Quantity = newQty
-		pos.Status = "open"
-		pos.ClosedAt = nil
-		pos.UnrealizedPnL = pos.CurrentPrice.Sub(pos.AvgEntryPrice).Mul(pos.Quantity)
-	}
+	applyFillToPosition(pos, fill, time.Now().UTC())
 	pos.StrategyName = plan.StrategyName
 	pos.UpdatedAt = time.Now().UTC()
 
-	if err := s.Repo.UpsertPosition(ctx, pos); err != nil {
-		return err
-	}
-	return nil
+	return repo.UpsertPosition(ctx, pos)
 }
 
 func (s *PositionSyncService) RefreshOpenPositionsPrices(ctx context.Context) error {
@@ -192,25 +164,69 @@ func (s *PositionSyncService) SnapshotPortfolio(ctx context.Context) error {
 	return s.Repo.InsertPortfolioSnapshot(ctx, item)
 }
 
+// applyFillToPosition updates quantity, average entry, cost basis and
+// realized PnL of pos for one fill. closedAt is used when the fill flattens
+// the position.
+func applyFillToPosition(pos *models.Position, fill models.Fill, closedAt time.Time) {
+	sideSign := fillSideSign(fill.Direction)
+	if sideSign == 0 {
+		sideSign = 1
+	}
+	oldQty := pos.Quantity
+	oldAvg := pos.AvgEntryPrice
+	qtyDelta := fill.FilledSize.Mul(decimal.NewFromInt(int64(sideSign)))
+	newQty := oldQty.Add(qtyDelta)
+
+	if sideSign > 0 {
+		totalCost := pos.CostBasis.Add(fill.AvgPrice.Mul(fill.FilledSize)).Add(fill.Fee)
+		if newQty.GreaterThan(decimal.Zero) {
+			pos.AvgEntryPrice = totalCost.Div(newQty)
+		}
+		pos.CostBasis = totalCost
+	} else {
+		sellQty := fill.FilledSize
+		if sellQty.GreaterThan(oldQty) {
+			sellQty = oldQty
+		}
+		realizedDelta := fill.AvgPrice.Sub(oldAvg).Mul(sellQty).Sub(fill.Fee)
+		pos.RealizedPnL = pos.RealizedPnL.Add(realizedDelta)
+		if newQty.GreaterThan(decimal.Zero) {
+			pos.CostBasis = oldAvg.Mul(newQty)
+			pos.AvgEntryPrice = oldAvg
+		} else {
+			pos.CostBasis = decimal.Zero
+			pos.AvgEntryPrice = decimal.Zero
+		}
+	}
+
+	if newQty.LessThanOrEqual(decimal.Zero) {
+		pos.Quantity = decimal.Zero
+		pos.Status = "closed"
+		pos.ClosedAt = &closedAt
+		pos.UnrealizedPnL = decimal.Zero
+	} else {
+		pos.Quantity = newQty
+		pos.Status = "open"
+		pos.ClosedAt = nil
+		pos.UnrealizedPnL = pos.CurrentPrice.Sub(pos.AvgEntryPrice).Mul(pos.Quantity)
+	}
+}
+
 func fillSideSign(fillDirection string) int {
-	dir := strings.ToUpper(strings.TrimSpace(fillDirection))
-	switch dir {
-	case "BUY_YES", "BUY_NO", "BUY":
-		return 1
-	case "SELL_YES", "SELL_NO", "SELL":
+	action, _, ok := models.SplitDirection(fillDirection)
+	switch {
+	case !ok:
+		return 0
+	case action == "SELL":
 		return -1
 	default:
-		return 0
+		return 1
 	}
 }
 
+// normalizePositionDirection returns the outcome code a fill trades, e.g.
+// YES for BUY_YES or LAKERS for SELL_LAKERS, and "" for a bare BUY or SELL.
 func normalizePositionDirection(fillDirection string) string {
-	dir := strings.ToUpper(strings.TrimSpace(fillDirection))
-	switch {
-	case strings.HasSuffix(dir, "_YES"):
-		return "YES"
-	case strings.HasSuffix(dir, "_NO"):
-		return "NO"
-	}
-	return ""
+	_, outcome, _ := models.SplitDirection(fillDirection)
+	return outcome
 }
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+)
+
+// RepricerConfig tunes passive repricing of resting orders. When the best
+// competing price on our side of the book moves away from a resting order by
+// at least Threshold, the order is amended back to AggressionTicks ahead of
+// that price, moving at most MaxStep per reprice.
+type RepricerConfig struct {
+	Enabled         bool
+	Threshold       float64
+	AggressionTicks int
+	MaxStep         float64
+	// MinInterval spaces reprices of one lineage, counting maker steps.
+	MinInterval time.Duration
+}
+
+// competingTouch is the best price on side's book other than our own
+// remaining shares at price. It reports false when nobody else is quoting.
+func competingTouch(side string, price float64, book makerBook, ownShares float64) (float64, bool) {
+	levels, better := book.Bids, func(a, b float64) bool { return a > b }
+	if isSellSide(side) {
+		levels, better = book.Asks, func(a, b float64) bool { return a < b }
+	}
+	best, found := 0.0, false
+	for _, l := range levels {
+		size := l.Size
+		if samePrice(l.Price, price) {
+			size -= ownShares
+		}
+		if size <= 1e-9 {
+			continue
+		}
+		if !found || better(l.Price, best) {
+			best, found = l.Price, true
+		}
+	}
+	return best, found
+}
+
+// improvedPrice returns the price a resting order should move to after the
+// book moved in its favor: a buy standing Threshold or more above the best
+// competing bid drops to AggressionTicks above it, a sell mirrors that.
+func improvedPrice(side string, current float64, book makerBook, ownShares float64, cfg RepricerConfig, tick float64) (float64, bool) {
+	if tick <= 0 {
+		tick = 0.01
+	}
+	competing, ok := competingTouch(side, current, book, ownShares)
+	if !ok || cfg.Threshold <= 0 {
+		return 0, false
+	}
+	dir := 1.0
+	if isSellSide(side) {
+		dir = -1.0
+	}
+	if (current-competing)*dir < cfg.Threshold-1e-9 {
+		return 0, false
+	}
+	target := competing + dir*float64(cfg.AggressionTicks)*tick
+	if cfg.MaxStep > 0 && (current-target)*dir > cfg.MaxStep {
+		target = current - dir*cfg.MaxStep
+	}
+	target = roundToTick(target, tick)
+	if (current-target)*dir <= 1e-9 || target <= 0 || target >= 1 {
+		return 0, false
+	}
+	return target, true
+}
+
+// improveRestingOrders amends resting orders whose book moved in our favor
+// and records the per-share improvement on the lineage's live row.
+func (e *CLOBExecutor) improveRestingOrders(ctx context.Context) error {
+	cfg := e.Config.Repricer
+	if !cfg.Enabled {
+		return nil
+	}
+	candidates, err := e.listLiveSyncCandidates(ctx)
+	if err != nil {
+		return err
+	}
+	orders := make([]models.Order, 0, len(candidates))
+	tokenIDs := make([]string, 0, len(candidates))
+	now := time.Now().UTC()
+	for _, o := range candidates {
+		if strings.TrimSpace(o.ClobOrderID) == "" || !o.Price.IsPositive() || o.MergedIntoID != nil {
+			// A merged book order belongs to several plans; leave it be.
+			continue
+		}
+		if now.Sub(makerRepricedAt(o)) < cfg.MinInterval {
+			continue
+		}
+		orders = append(orders, o)
+		tokenIDs = append(tokenIDs, o.TokenID)
+	}
+	if len(orders) == 0 {
+		return nil
+	}
+	rows, err := e.Repo.ListOrderbookLatestByTokenIDs(ctx, tokenIDs)
+	if err != nil {
+		return err
+	}
+	books := make(map[string]makerBook, len(rows))
+	for _, r := range rows {
+		books[r.TokenID] = makerBookFromLatest(r)
+	}
+	for _, order := range orders {
+		book, ok := books[order.TokenID]
+		if !ok {
+			continue
+		}
+		current := order.Price.InexactFloat64()
+		ownShares := order.SizeUSD.Sub(order.FilledUSD).Div(order.Price).InexactFloat64()
+		target, ok := improvedPrice(order.Side, current, book, ownShares, cfg, e.Config.Maker.TickSize)
+		if !ok {
+			continue
+		}
+		price := decimal.NewFromFloat(target)
+		size := repricedSize(order, price)
+		res, err := e.AmendOrder(ctx, order.ID, AmendOrderRequest{Price: &price, SizeUSD: &size})
+		if err != nil {
+			if e.Logger != nil {
+				e.Logger.Warn("price improvement reprice failed", zap.Uint64("order_id", order.ID), zap.Error(err))
+			}
+			continue
+		}
+		if res == nil || res.Order == nil {
+			continue
+		}
+		gain := decimal.NewFromFloat(math.Abs(current - target))
+		if err := e.Repo.UpdateOrderStatus(ctx, res.Order.ID, res.Order.Status, map[string]any{
+			"price_improvement": order.PriceImprovement.Add(gain),
+			"repriced_at":       &now,
+		}); err != nil && e.Logger != nil {
+			e.Logger.Warn("record price improvement failed", zap.Uint64("order_id", res.Order.ID), zap.Error(err))
+		}
+		if e.Logger != nil {
+			e.Logger.Info("resting order price improved",
+				zap.Uint64("order_id", res.Order.ID),
+				zap.Float64("from", current),
+				zap.Float64("to", target),
+			)
+		}
+	}
+	return nil
+}
+
+// repricedSize is the total size of order at price with its unfilled shares
+// unchanged, so an improvement changes what the order pays, not how much it
+// buys or sells. The filled part keeps its notional.
+func repricedSize(order models.Order, price decimal.Decimal) decimal.Decimal {
+	if !order.Price.IsPositive() {
+		return order.SizeUSD
+	}
+	shares := order.SizeUSD.Sub(order.FilledUSD).Div(order.Price)
+	return order.FilledUSD.Add(shares.Mul(price)).Round(6)
+}
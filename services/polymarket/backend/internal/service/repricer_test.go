@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestImprovedPrice(t *testing.T) {
+	cfg := RepricerConfig{Threshold: 0.02, AggressionTicks: 1, MaxStep: 0.05}
+	// Our 100 shares sit alone at 0.45; the next bid fell to 0.40.
+	book := makerBook{Bids: []priceLevel{{Price: 0.45, Size: 100}, {Price: 0.40, Size: 200}}, Asks: []priceLevel{{Price: 0.50, Size: 50}}}
+	if p, ok := improvedPrice("BUY_YES", 0.45, book, 100, cfg, 0.01); !ok || !samePrice(p, 0.41) {
+		t.Fatalf("buy p=%v ok=%v", p, ok)
+	}
+	// Others still quote our level: nothing to improve.
+	if _, ok := improvedPrice("BUY_YES", 0.45, book, 60, cfg, 0.01); ok {
+		t.Fatalf("improved while sharing the touch")
+	}
+	// Below the threshold the order holds.
+	if _, ok := improvedPrice("BUY_YES", 0.41, makerBook{Bids: []priceLevel{{Price: 0.41, Size: 10}, {Price: 0.40, Size: 5}}}, 10, cfg, 0.01); ok {
+		t.Fatalf("improved under threshold")
+	}
+	// MaxStep caps a large move.
+	far := makerBook{Bids: []priceLevel{{Price: 0.45, Size: 100}, {Price: 0.20, Size: 5}}}
+	if p, _ := improvedPrice("BUY_YES", 0.45, far, 100, cfg, 0.01); !samePrice(p, 0.40) {
+		t.Fatalf("capped p=%v", p)
+	}
+	sell := makerBook{Asks: []priceLevel{{Price: 0.50, Size: 40}, {Price: 0.56, Size: 10}}}
+	if p, ok := improvedPrice("SELL_YES", 0.50, sell, 40, cfg, 0.01); !ok || !samePrice(p, 0.55) {
+		t.Fatalf("sell p=%v ok=%v", p, ok)
+	}
+}
+
+func TestRepricedSizeKeepsShares(t *testing.T) {
+	d := decimal.RequireFromString
+	cases := []struct {
+		name                   string
+		size, filled, from, to string
+		want                   string
+	}{
+		// 100 shares at 0.45 become 100 shares at 0.41.
+		{"buy lowered", "45", "0", "0.45", "0.41", "41"},
+		// 40 shares at 0.50 become 40 shares at 0.55.
+		{"sell raised", "20", "0", "0.50", "0.55", "22"},
+		// 10 of 45 filled; the other 77.78 shares move to 0.41.
+		{"partly filled", "45", "10", "0.45", "0.41", "41.888889"},
+	}
+	for _, tc := range cases {
+		order := models.Order{SizeUSD: d(tc.size), FilledUSD: d(tc.filled), Price: d(tc.from)}
+		if got := repricedSize(order, d(tc.to)); !got.Equal(d(tc.want)) {
+			t.Errorf("%s: size = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
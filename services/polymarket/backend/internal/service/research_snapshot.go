@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// MaxSnapshotMarkets caps how many markets one research snapshot captures.
+const MaxSnapshotMarkets = 500
+
+// SnapshotQuery selects the markets of a research snapshot: the union of
+// MarketIDs, the markets of EventID and the markets carrying Label.
+type SnapshotQuery struct {
+	MarketIDs []string `json:"market_ids,omitempty"`
+	EventID   string   `json:"event_id,omitempty"`
+	Label     string   `json:"label,omitempty"`
+	// ActiveOnly drops closed or inactive markets from the selection.
+	ActiveOnly bool `json:"active_only,omitempty"`
+}
+
+// SnapshotPayload is the frozen content of a research snapshot.
+type SnapshotPayload struct {
+	TakenAt time.Time        `json:"taken_at"`
+	Markets []SnapshotMarket `json:"markets"`
+}
+
+type SnapshotMarket struct {
+	ID        string           `json:"id"`
+	EventID   string           `json:"event_id"`
+	Question  string           `json:"question"`
+	Slug      *string          `json:"slug,omitempty"`
+	Active    bool             `json:"active"`
+	Closed    bool             `json:"closed"`
+	TickSize  decimal.Decimal  `json:"tick_size"`
+	Volume    *decimal.Decimal `json:"volume,omitempty"`
+	Liquidity *decimal.Decimal `json:"liquidity,omitempty"`
+	Labels    []string         `json:"labels"`
+	Tokens    []SnapshotToken  `json:"tokens"`
+}
+
+type SnapshotToken struct {
+	ID      string        `json:"id"`
+	Outcome string        `json:"outcome"`
+	Book    *SnapshotBook `json:"book,omitempty"`
+}
+
+type SnapshotBook struct {
+	Bids       json.RawMessage `json:"bids"`
+	Asks       json.RawMessage `json:"asks"`
+	BestBid    *float64        `json:"best_bid,omitempty"`
+	BestAsk    *float64        `json:"best_ask,omitempty"`
+	Mid        *float64        `json:"mid,omitempty"`
+	SnapshotTS time.Time       `json:"snapshot_ts"`
+}
+
+// ErrEmptySnapshot is returned when a snapshot query selects no markets.
+var ErrEmptySnapshot = errors.New("query selects no markets")
+
+// ResearchSnapshotService captures research snapshots.
+type ResearchSnapshotService struct {
+	Repo repository.Repository
+}
+
+// Create captures the markets selected by q with their tokens, latest books
+// and labels, and stores them under name.
+func (s *ResearchSnapshotService) Create(ctx context.Context, name, tenant string, q SnapshotQuery, now time.Time) (*models.ResearchSnapshot, error) {
+	markets, err := s.selectMarkets(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(markets) == 0 {
+		return nil, ErrEmptySnapshot
+	}
+	payload, err := s.capture(ctx, markets, now)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	query, _ := json.Marshal(q)
+	sum := sha256.Sum256(body)
+	item := &models.ResearchSnapshot{
+		Name:        strings.TrimSpace(name),
+		Tenant:      tenant,
+		Query:       query,
+		MarketCount: len(payload.Markets),
+		Payload:     body,
+		Checksum:    hex.EncodeToString(sum[:]),
+		TakenAt:     now,
+	}
+	if err := s.Repo.InsertResearchSnapshot(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *ResearchSnapshotService) selectMarkets(ctx context.Context, q SnapshotQuery) ([]models.Market, error) {
+	ids := make([]string, 0, len(q.MarketIDs))
+	for _, id := range q.MarketIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if label := strings.TrimSpace(q.Label); label != "" {
+		rows, err := s.Repo.ListMarketLabels(ctx, repository.ListMarketLabelsParams{Label: &label, Limit: MaxSnapshotMarkets})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			ids = append(ids, r.MarketID)
+		}
+	}
+	var markets []models.Market
+	if len(ids) > 0 {
+		rows, err := s.Repo.ListMarketsByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		markets = append(markets, rows...)
+	}
+	if eventID := strings.TrimSpace(q.EventID); eventID != "" {
+		rows, err := s.Repo.ListMarkets(ctx, repository.ListMarketsParams{EventID: &eventID, Limit: MaxSnapshotMarkets})
+		if err != nil {
+			return nil, err
+		}
+		markets = append(markets, rows...)
+	}
+	seen := map[string]bool{}
+	out := make([]models.Market, 0, len(markets))
+	for _, m := range markets {
+		if seen[m.ID] || (q.ActiveOnly && (!m.Active || m.Closed)) {
+			continue
+		}
+		seen[m.ID] = true
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	if len(out) > MaxSnapshotMarkets {
+		out = out[:MaxSnapshotMarkets]
+	}
+	return out, nil
+}
+
+func (s *ResearchSnapshotService) capture(ctx context.Context, markets []models.Market, now time.Time) (SnapshotPayload, error) {
+	ids := make([]string, 0, len(markets))
+	for _, m := range markets {
+		ids = append(ids, m.ID)
+	}
+	tokens, err := s.Repo.ListTokensByMarketIDs(ctx, ids)
+	if err != nil {
+		return SnapshotPayload{}, err
+	}
+	tokenIDs := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		tokenIDs = append(tokenIDs, t.ID)
+	}
+	books, err := s.Repo.ListOrderbookLatestByTokenIDs(ctx, tokenIDs)
+	if err != nil {
+		return SnapshotPayload{}, err
+	}
+	labels, err := s.Repo.ListMarketLabelsByMarketIDs(ctx, ids)
+	if err != nil {
+		return SnapshotPayload{}, err
+	}
+	return buildSnapshotPayload(markets, tokens, books, labels, now), nil
+}
+
+// buildSnapshotPayload assembles the payload in a stable order so equal
+// inputs hash to equal checksums.
+func buildSnapshotPayload(markets []models.Market, tokens []models.Token, books []models.OrderbookLatest, labels []models.MarketLabel, now time.Time) SnapshotPayload {
+	bookByToken := make(map[string]models.OrderbookLatest, len(books))
+	for _, b := range books {
+		bookByToken[b.TokenID] = b
+	}
+	tokensByMarket := map[string][]SnapshotToken{}
+	for _, t := range tokens {
+		st := SnapshotToken{ID: t.ID, Outcome: t.Outcome}
+		if b, ok := bookByToken[t.ID]; ok {
+			st.Book = &SnapshotBook{
+				Bids:       json.RawMessage(b.BidsJSON),
+				Asks:       json.RawMessage(b.AsksJSON),
+				BestBid:    b.BestBid,
+				BestAsk:    b.BestAsk,
+				Mid:        b.Mid,
+				SnapshotTS: b.SnapshotTS.UTC(),
+			}
+		}
+		tokensByMarket[t.MarketID] = append(tokensByMarket[t.MarketID], st)
+	}
+	labelsByMarket := map[string][]string{}
+	for _, l := range labels {
+		labelsByMarket[l.MarketID] = append(labelsByMarket[l.MarketID], l.Label)
+	}
+	out := SnapshotPayload{TakenAt: now.UTC(), Markets: make([]SnapshotMarket, 0, len(markets))}
+	for _, m := range markets {
+		toks := tokensByMarket[m.ID]
+		sort.Slice(toks, func(i, j int) bool { return toks[i].ID < toks[j].ID })
+		if toks == nil {
+			toks = []SnapshotToken{}
+		}
+		lbls := dedupeSorted(labelsByMarket[m.ID])
+		out.Markets = append(out.Markets, SnapshotMarket{
+			ID:        m.ID,
+			EventID:   m.EventID,
+			Question:  m.Question,
+			Slug:      m.Slug,
+			Active:    m.Active,
+			Closed:    m.Closed,
+			TickSize:  m.TickSize,
+			Volume:    m.Volume,
+			Liquidity: m.Liquidity,
+			Labels:    lbls,
+			Tokens:    toks,
+		})
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestBuildSnapshotPayload(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	bid, ask := 0.41, 0.44
+	markets := []models.Market{
+		{ID: "m1", EventID: "e1", Question: "Q1", TickSize: decimal.RequireFromString("0.01"), Active: true},
+		{ID: "m2", EventID: "e1", Question: "Q2", TickSize: decimal.RequireFromString("0.01")},
+	}
+	tokens := []models.Token{
+		{ID: "t2", MarketID: "m1", Outcome: "No"},
+		{ID: "t1", MarketID: "m1", Outcome: "Yes"},
+	}
+	books := []models.OrderbookLatest{{TokenID: "t1", BidsJSON: []byte(`[]`), AsksJSON: []byte(`[]`), BestBid: &bid, BestAsk: &ask, SnapshotTS: now}}
+	labels := []models.MarketLabel{{MarketID: "m1", Label: "sports"}, {MarketID: "m1", Label: "safe_no"}}
+
+	p := buildSnapshotPayload(markets, tokens, books, labels, now)
+	if len(p.Markets) != 2 || len(p.Markets[0].Tokens) != 2 || p.Markets[0].Tokens[0].ID != "t1" {
+		t.Fatalf("payload=%+v", p)
+	}
+	if p.Markets[0].Tokens[0].Book == nil || p.Markets[0].Tokens[1].Book != nil {
+		t.Fatalf("books not attached to their tokens: %+v", p.Markets[0].Tokens)
+	}
+	if got := p.Markets[0].Labels; len(got) != 2 || got[0] != "safe_no" {
+		t.Fatalf("labels=%v", got)
+	}
+	// Markets without tokens or labels serialize as empty lists, and equal
+	// inputs in a different order produce identical bytes.
+	a, _ := json.Marshal(p)
+	b, _ := json.Marshal(buildSnapshotPayload(markets, []models.Token{tokens[1], tokens[0]}, books, []models.MarketLabel{labels[1], labels[0]}, now))
+	if string(a) != string(b) {
+		t.Fatalf("payload not stable:\n%s\n%s", a, b)
+	}
+	if p.Markets[1].Tokens == nil || p.Markets[1].Labels == nil {
+		t.Fatalf("empty lists must not be null: %+v", p.Markets[1])
+	}
+}
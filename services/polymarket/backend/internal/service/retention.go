@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/governor"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// RetentionSettingPrefix prefixes the system setting holding a table's
+// retention policy, e.g. retention.raw_ws_events.
+const RetentionSettingPrefix = "retention."
+
+// RetentionPolicy is how long rows of one table are kept. An empty or zero
+// window keeps them forever. RunHour is the UTC hour the nightly purge of
+// the table runs at; nil uses retention.run_hour.
+type RetentionPolicy struct {
+	Table       string `json:"table"`
+	Description string `json:"description"`
+	Window      string `json:"window"`
+	Enabled     bool   `json:"enabled"`
+	RunHour     *int   `json:"run_hour,omitempty"`
+	// Source is "default" or "setting".
+	Source string `json:"source"`
+}
+
+// window parses Window; errors are reported when a policy is saved, so a
+// bad stored value just disables the policy here.
+func (p RetentionPolicy) window() time.Duration {
+	d, err := parseRetentionWindow(p.Window)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+func parseRetentionWindow(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window %q", s)
+	}
+	if d < time.Hour {
+		return 0, errors.New("window must be at least 1h")
+	}
+	return d, nil
+}
+
+// defaultRetentionPolicies apply to tables without a retention setting.
+// Only raw and derived market data expire by default; the change journals
+// and the risk decision audit are kept until a window is configured.
+var defaultRetentionPolicies = []RetentionPolicy{
+	{Table: "raw_ws_events", Description: "raw CLOB websocket events", Window: "168h", Enabled: true},
+	{Table: "raw_rest_snapshots", Description: "raw REST orderbook snapshots", Window: "168h", Enabled: true},
+	{Table: "signals", Description: "ingested signals", Window: "720h", Enabled: true},
+	{Table: "price_candles", Description: "price history candles"},
+	{Table: "catalog_market_changes", Description: "market field change log"},
+	{Table: "catalog_changes", Description: "catalog change journal"},
+	{Table: "wallet_position_changes", Description: "tracked wallet position change log"},
+	{Table: "risk_decisions", Description: "risk filter decision audit"},
+	{Table: "slo_samples", Description: "pipeline freshness SLO samples"},
+}
+
+// RetentionTableResult is the outcome of purging, or previewing, one table.
+type RetentionTableResult struct {
+	Table      string     `json:"table"`
+	Window     string     `json:"window"`
+	Cutoff     time.Time  `json:"cutoff"`
+	Matched    int64      `json:"matched"`
+	Deleted    int64      `json:"deleted"`
+	Oldest     *time.Time `json:"oldest,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// RetentionRun summarizes one purge over several tables.
+type RetentionRun struct {
+	Trigger    string                 `json:"trigger"`
+	DryRun     bool                   `json:"dry_run"`
+	StartedAt  time.Time              `json:"started_at"`
+	FinishedAt time.Time              `json:"finished_at"`
+	Deleted    int64                  `json:"deleted"`
+	Tables     []RetentionTableResult `json:"tables"`
+}
+
+var ErrUnknownRetentionTable = errors.New("unknown retention table")
+
+// RetentionService enforces the retention policies: each enabled policy is
+// purged once a day at its run hour while feature.retention is on, and can
+// be previewed or purged on demand.
+type RetentionService struct {
+	Repo     repository.Repository
+	Settings *SystemSettingsService
+	Config   config.RetentionConfig
+	Logger   *zap.Logger
+	// Governor, when set, admits each scheduled purge as an admin job.
+	Governor *governor.Governor
+
+	mu      sync.Mutex
+	purged  map[string]string // table -> UTC date of the last purge
+	lastRun *RetentionRun
+}
+
+// Policies returns every table's effective policy, sorted by table.
+func (s *RetentionService) Policies(ctx context.Context) ([]RetentionPolicy, error) {
+	out := make([]RetentionPolicy, 0, len(defaultRetentionPolicies))
+	for _, def := range defaultRetentionPolicies {
+		p := def
+		p.Source = "default"
+		if s != nil && s.Repo != nil {
+			row, err := s.Repo.GetSystemSettingByKey(ctx, RetentionSettingPrefix+p.Table)
+			if err != nil {
+				return nil, err
+			}
+			if row != nil && len(row.Value) > 0 {
+				var stored RetentionPolicy
+				if err := json.Unmarshal(row.Value, &stored); err == nil {
+					p.Window, p.Enabled, p.RunHour = stored.Window, stored.Enabled, stored.RunHour
+					p.Source = "setting"
+				}
+			}
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Table < out[j].Table })
+	return out, nil
+}
+
+// Policy returns the effective policy of table.
+func (s *RetentionService) Policy(ctx context.Context, table string) (RetentionPolicy, error) {
+	policies, err := s.Policies(ctx)
+	if err != nil {
+		return RetentionPolicy{}, err
+	}
+	for _, p := range policies {
+		if p.Table == table {
+			return p, nil
+		}
+	}
+	return RetentionPolicy{}, ErrUnknownRetentionTable
+}
+
+// SavePolicy validates p and stores it as the table's retention setting.
+func (s *RetentionService) SavePolicy(ctx context.Context, p RetentionPolicy) (RetentionPolicy, error) {
+	current, err := s.Policy(ctx, p.Table)
+	if err != nil {
+		return RetentionPolicy{}, err
+	}
+	if _, err := parseRetentionWindow(p.Window); err != nil {
+		return RetentionPolicy{}, err
+	}
+	if p.RunHour != nil && (*p.RunHour < 0 || *p.RunHour > 23) {
+		return RetentionPolicy{}, errors.New("run_hour must be 0-23")
+	}
+	current.Window = strings.TrimSpace(p.Window)
+	current.Enabled = p.Enabled
+	current.RunHour = p.RunHour
+	current.Source = "setting"
+	raw, _ := json.Marshal(struct {
+		Window  string `json:"window"`
+		Enabled bool   `json:"enabled"`
+		RunHour *int   `json:"run_hour,omitempty"`
+	}{current.Window, current.Enabled, current.RunHour})
+	now := time.Now().UTC()
+	item := &models.SystemSetting{
+		Key:         RetentionSettingPrefix + current.Table,
+		Value:       datatypes.JSON(raw),
+		Description: "retention policy: " + current.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.Repo.UpsertSystemSetting(ctx, item); err != nil {
+		return RetentionPolicy{}, err
+	}
+	s.Settings.Changed(item.Key, item.Value)
+	return current, nil
+}
+
+func (s *RetentionService) runHour(p RetentionPolicy) int {
+	if p.RunHour != nil {
+		return *p.RunHour
+	}
+	return s.Config.RunHour
+}
+
+// due lists the enabled policies whose run hour is now and which were not
+// purged yet today.
+func (s *RetentionService) due(policies []RetentionPolicy, now time.Time) []string {
+	now = now.UTC()
+	day := now.Format("2006-01-02")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []string{}
+	for _, p := range policies {
+		if !p.Enabled || p.window() <= 0 || s.runHour(p) != now.Hour() {
+			continue
+		}
+		if s.purged[p.Table] == day {
+			continue
+		}
+		out = append(out, p.Table)
+	}
+	return out
+}
+
+// Purge deletes, or with dryRun counts, the rows of tables older than their
+// policy window. Empty tables means every enabled policy; a named table is
+// purged even when its policy is disabled, but never without a window.
+func (s *RetentionService) Purge(ctx context.Context, tables []string, dryRun bool, trigger string, now time.Time) (*RetentionRun, error) {
+	if s == nil || s.Repo == nil {
+		return nil, errors.New("retention unavailable")
+	}
+	policies, err := s.Policies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byTable := make(map[string]RetentionPolicy, len(policies))
+	for _, p := range policies {
+		byTable[p.Table] = p
+	}
+	selected := make([]RetentionPolicy, 0, len(policies))
+	if len(tables) == 0 {
+		for _, p := range policies {
+			if p.Enabled && p.window() > 0 {
+				selected = append(selected, p)
+			}
+		}
+	}
+	for _, t := range tables {
+		p, ok := byTable[t]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRetentionTable, t)
+		}
+		if p.window() > 0 {
+			selected = append(selected, p)
+		}
+	}
+
+	batch := s.Config.BatchSize
+	if batch <= 0 {
+		batch = 5000
+	}
+	run := &RetentionRun{Trigger: trigger, DryRun: dryRun, StartedAt: now.UTC(), Tables: make([]RetentionTableResult, 0, len(selected))}
+	for _, p := range selected {
+		start := time.Now()
+		res := RetentionTableResult{Table: p.Table, Window: p.Window, Cutoff: now.UTC().Add(-p.window())}
+		backlog, err := s.Repo.RetentionBacklog(ctx, p.Table, res.Cutoff)
+		if err != nil {
+			res.Error = err.Error()
+		} else {
+			res.Matched, res.Oldest = backlog.Rows, backlog.Oldest
+		}
+		if err == nil && !dryRun && res.Matched > 0 {
+			for {
+				n, err := s.Repo.PurgeRetention(ctx, p.Table, res.Cutoff, batch)
+				res.Deleted += n
+				if err != nil {
+					res.Error = err.Error()
+					break
+				}
+				if n < int64(batch) {
+					break
+				}
+				if err := governor.Yield(ctx); err != nil {
+					res.Error = err.Error()
+					break
+				}
+			}
+		}
+		res.DurationMs = time.Since(start).Milliseconds()
+		run.Deleted += res.Deleted
+		run.Tables = append(run.Tables, res)
+	}
+	run.FinishedAt = time.Now().UTC()
+	if !dryRun {
+		s.record(run, now)
+		s.audit(ctx, run)
+	}
+	return run, nil
+}
+
+func (s *RetentionService) record(run *RetentionRun, now time.Time) {
+	day := now.UTC().Format("2006-01-02")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.purged == nil {
+		s.purged = map[string]string{}
+	}
+	for _, t := range run.Tables {
+		if t.Error == "" {
+			s.purged[t.Table] = day
+		}
+	}
+	s.lastRun = run
+}
+
+// audit logs what a purge deleted; runs that touched nothing are skipped.
+func (s *RetentionService) audit(ctx context.Context, run *RetentionRun) {
+	tables := make([]map[string]any, 0, len(run.Tables))
+	failed := false
+	for _, t := range run.Tables {
+		if t.Deleted == 0 && t.Error == "" {
+			continue
+		}
+		row := map[string]any{"table": t.Table, "cutoff": t.Cutoff.Format(time.RFC3339), "deleted": t.Deleted}
+		if t.Error != "" {
+			row["error"] = t.Error
+			failed = true
+		}
+		tables = append(tables, row)
+	}
+	if len(tables) == 0 {
+		return
+	}
+	level := "info"
+	if failed {
+		level = "warn"
+	}
+	paas.LogBestEffortCtx(ctx, "polymarket_retention_purged", level, map[string]any{
+		"trigger": run.Trigger,
+		"deleted": run.Deleted,
+		"tables":  tables,
+	})
+	if s.Logger != nil {
+		s.Logger.Info("retention purge", zap.String("trigger", run.Trigger), zap.Int64("deleted", run.Deleted), zap.Bool("failed", failed))
+	}
+}
+
+// LastRun returns the last purge that was not a dry run, or nil.
+func (s *RetentionService) LastRun() *RetentionRun {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun
+}
+
+// Run checks every CheckInterval for policies due and purges them.
+func (s *RetentionService) Run(ctx context.Context) error {
+	if s == nil || s.Repo == nil {
+		return nil
+	}
+	interval := s.Config.CheckInterval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if !s.Settings.IsEnabled(ctx, FeatureRetention, false) {
+				continue
+			}
+			policies, err := s.Policies(ctx)
+			if err != nil {
+				s.logWarn("retention policies unavailable", err)
+				continue
+			}
+			now := time.Now().UTC()
+			due := s.due(policies, now)
+			if len(due) == 0 {
+				continue
+			}
+			err = s.Governor.Run(ctx, governor.ClassAdmin, func(ctx context.Context) error {
+				_, err := s.Purge(ctx, due, false, "schedule", now)
+				return err
+			})
+			if err != nil {
+				s.logWarn("retention purge failed", err)
+			}
+		}
+	}
+}
+
+func (s *RetentionService) logWarn(msg string, err error) {
+	if s.Logger != nil {
+		s.Logger.Warn(msg, zap.Error(err))
+	}
+}
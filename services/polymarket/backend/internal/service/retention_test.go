@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type retentionRepo struct {
+	repository.Repository
+	settings map[string]datatypes.JSON
+	rows     map[string]int64
+	purges   []string
+}
+
+func (r *retentionRepo) GetSystemSettingByKey(ctx context.Context, key string) (*models.SystemSetting, error) {
+	v, ok := r.settings[key]
+	if !ok {
+		return nil, nil
+	}
+	return &models.SystemSetting{Key: key, Value: v}, nil
+}
+
+func (r *retentionRepo) UpsertSystemSetting(ctx context.Context, item *models.SystemSetting) error {
+	r.settings[item.Key] = item.Value
+	return nil
+}
+
+func (r *retentionRepo) RetentionBacklog(ctx context.Context, table string, before time.Time) (repository.RetentionBacklog, error) {
+	return repository.RetentionBacklog{Rows: r.rows[table]}, nil
+}
+
+func (r *retentionRepo) PurgeRetention(ctx context.Context, table string, before time.Time, limit int) (int64, error) {
+	r.purges = append(r.purges, table)
+	n := r.rows[table]
+	if n > int64(limit) {
+		n = int64(limit)
+	}
+	r.rows[table] -= n
+	return n, nil
+}
+
+func TestRetentionPoliciesCoverRetentionColumns(t *testing.T) {
+	for _, p := range defaultRetentionPolicies {
+		if _, ok := repository.RetentionColumns[p.Table]; !ok {
+			t.Fatalf("%s has no retention column", p.Table)
+		}
+	}
+	if len(defaultRetentionPolicies) != len(repository.RetentionColumns) {
+		t.Fatalf("%d policies for %d tables", len(defaultRetentionPolicies), len(repository.RetentionColumns))
+	}
+}
+
+func TestRetentionSavePolicyAndDue(t *testing.T) {
+	repo := &retentionRepo{settings: map[string]datatypes.JSON{}}
+	s := &RetentionService{Repo: repo, Config: config.RetentionConfig{RunHour: 3}}
+	ctx := context.Background()
+
+	if _, err := s.SavePolicy(ctx, RetentionPolicy{Table: "catalog_changes", Window: "30m", Enabled: true}); err == nil {
+		t.Fatalf("expected a window under 1h to be rejected")
+	}
+	if _, err := s.SavePolicy(ctx, RetentionPolicy{Table: "audit_records", Window: "720h"}); err != ErrUnknownRetentionTable {
+		t.Fatalf("err=%v want unknown table", err)
+	}
+	hour := 5
+	saved, err := s.SavePolicy(ctx, RetentionPolicy{Table: "catalog_changes", Window: "2160h", Enabled: true, RunHour: &hour})
+	if err != nil || saved.Source != "setting" || saved.Description == "" {
+		t.Fatalf("saved=%+v err=%v", saved, err)
+	}
+
+	policies, err := s.Policies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at3 := time.Date(2026, 10, 16, 3, 20, 0, 0, time.UTC)
+	if got := s.due(policies, at3); len(got) != 3 || got[0] != "raw_rest_snapshots" || got[1] != "raw_ws_events" || got[2] != "signals" {
+		t.Fatalf("due at 03:00 = %v", got)
+	}
+	if got := s.due(policies, at3.Add(2*time.Hour)); len(got) != 1 || got[0] != "catalog_changes" {
+		t.Fatalf("due at 05:00 = %v", got)
+	}
+}
+
+func TestRetentionPurgeBatchesAndDryRun(t *testing.T) {
+	repo := &retentionRepo{
+		settings: map[string]datatypes.JSON{},
+		rows:     map[string]int64{"raw_ws_events": 25, "signals": 3, "catalog_changes": 9},
+	}
+	s := &RetentionService{Repo: repo, Config: config.RetentionConfig{RunHour: 3, BatchSize: 10}}
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+
+	preview, err := s.Purge(ctx, nil, true, "manual", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.purges) != 0 || preview.Deleted != 0 || s.LastRun() != nil {
+		t.Fatalf("dry run deleted: purges=%v run=%+v", repo.purges, preview)
+	}
+	if len(preview.Tables) != 3 {
+		t.Fatalf("tables=%+v", preview.Tables)
+	}
+	for _, r := range preview.Tables {
+		if r.Table == "catalog_changes" {
+			t.Fatalf("policy without a window was selected")
+		}
+		if r.Table == "raw_ws_events" && (r.Matched != 25 || !r.Cutoff.Equal(now.Add(-168*time.Hour))) {
+			t.Fatalf("preview=%+v", r)
+		}
+	}
+
+	run, err := s.Purge(ctx, []string{"raw_ws_events"}, false, "manual", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Deleted != 25 || len(repo.purges) != 3 || repo.rows["raw_ws_events"] != 0 {
+		t.Fatalf("run=%+v purges=%v", run, repo.purges)
+	}
+	if s.LastRun() != run {
+		t.Fatalf("last run not recorded")
+	}
+	policies, _ := s.Policies(ctx)
+	if got := s.due(policies, now); len(got) != 2 {
+		t.Fatalf("purged table still due: %v", got)
+	}
+	if _, err := s.Purge(ctx, []string{"nope"}, true, "manual", now); err == nil {
+		t.Fatalf("expected unknown table error")
+	}
+}
@@ -88,8 +88,10 @@ func (s *ReviewService) RunOnce(ctx context.Context) error {
 			if rec != nil && rec.RealizedPnL != nil {
 				actualPnL = *rec.RealizedPnL
 			}
-			opID := p.OpportunityID
-			opportunityID = &opID
+			if p.OpportunityID > 0 {
+				opID := p.OpportunityID
+				opportunityID = &opID
+			}
 		}
 		finalPrice := st.FinalYesPrice
 		if finalPrice == nil {
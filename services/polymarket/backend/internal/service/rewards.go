@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// RewardTracker samples our resting orders against the latest books to
+// measure liquidity-reward quoting per market and reward epoch (UTC day):
+// uptime, spread compliance, size at the touch and an estimated payout.
+//
+// The estimate follows the Polymarket scoring shape: an order within the
+// market's max spread s of the midpoint scores ((v-s)/v)^2 * size, where v
+// is the max spread. Bid and ask scores combine as min(bid, ask), or as
+// max(min, max/3) when the midpoint is in [0.10, 0.90] so single-sided
+// quotes earn a third. Our share is our combined score over the book's,
+// and each sample earns DailyRate * share * Interval/24h. Competing makers
+// are only visible through the top levels we store, so the share is an
+// upper bound.
+type RewardTracker struct {
+	Repo     repository.Repository
+	Logger   *zap.Logger
+	Interval time.Duration
+}
+
+func (t *RewardTracker) interval() time.Duration {
+	if t.Interval <= 0 {
+		return time.Minute
+	}
+	return t.Interval
+}
+
+// RewardEpochOf returns the reward epoch (UTC date) containing at.
+func RewardEpochOf(at time.Time) string {
+	return at.UTC().Format("2006-01-02")
+}
+
+func (t *RewardTracker) Run(ctx context.Context) error {
+	if t == nil || t.Repo == nil {
+		return nil
+	}
+	ticker := time.NewTicker(t.interval())
+	defer ticker.Stop()
+	for {
+		if err := t.Sample(ctx, time.Now().UTC()); err != nil && t.Logger != nil {
+			t.Logger.Warn("reward sampling failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// rewardParams are a market's liquidity reward program parameters.
+type rewardParams struct {
+	MaxSpreadCents float64
+	MinSize        float64
+	DailyRateUSD   float64
+}
+
+// rewardParamsFromMarket reads the reward fields Gamma returns with a
+// market: rewardsMaxSpread (cents), rewardsMinSize (shares) and the daily
+// rate from clobRewards. ok is false for markets without a program.
+func rewardParamsFromMarket(m models.Market) (rewardParams, bool) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(m.RawJSON, &raw); err != nil {
+		return rewardParams{}, false
+	}
+	p := rewardParams{
+		MaxSpreadCents: jsonNumber(raw["rewardsMaxSpread"]),
+		MinSize:        jsonNumber(raw["rewardsMinSize"]),
+		DailyRateUSD:   jsonNumber(raw["rewardsDailyRate"]),
+	}
+	var programs []map[string]json.RawMessage
+	if err := json.Unmarshal(raw["clobRewards"], &programs); err == nil {
+		for _, prog := range programs {
+			p.DailyRateUSD += jsonNumber(prog["rewardsDailyRate"])
+		}
+	}
+	return p, p.MaxSpreadCents > 0
+}
+
+func jsonNumber(raw json.RawMessage) float64 {
+	if len(raw) == 0 {
+		return 0
+	}
+	var f float64
+	if err := json.Unmarshal(raw, &f); err == nil {
+		return f
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		f, _ = strconv.ParseFloat(strings.TrimSpace(s), 64)
+	}
+	return f
+}
+
+// rewardQuote is one resting order in YES price space.
+type rewardQuote struct {
+	Bid    bool
+	Price  float64
+	Shares float64
+}
+
+// Sample records one observation for every market we quote in now and
+// every market already tracked in the current epoch.
+func (t *RewardTracker) Sample(ctx context.Context, now time.Time) error {
+	epoch := RewardEpochOf(now)
+	var orders []models.Order
+	for _, status := range []string{"submitted", "partial"} {
+		status := status
+		items, err := t.Repo.ListOrders(ctx, repository.ListOrdersParams{Limit: 500, Status: &status})
+		if err != nil {
+			return err
+		}
+		orders = append(orders, items...)
+	}
+	orderTokenIDs := make([]string, 0, len(orders))
+	for _, o := range orders {
+		orderTokenIDs = append(orderTokenIDs, o.TokenID)
+	}
+	orderTokens, err := t.Repo.ListTokensByIDs(ctx, orderTokenIDs)
+	if err != nil {
+		return err
+	}
+	tracked, err := t.Repo.ListRewardEpochs(ctx, repository.ListRewardEpochsParams{Limit: 500, Since: &epoch, Until: &epoch})
+	if err != nil {
+		return err
+	}
+	marketSet := map[string]bool{}
+	for _, tok := range orderTokens {
+		marketSet[tok.MarketID] = true
+	}
+	for _, row := range tracked {
+		marketSet[row.MarketID] = true
+	}
+	if len(marketSet) == 0 {
+		return nil
+	}
+	marketIDs := make([]string, 0, len(marketSet))
+	for id := range marketSet {
+		marketIDs = append(marketIDs, id)
+	}
+	markets, err := t.Repo.ListMarketsByIDs(ctx, marketIDs)
+	if err != nil {
+		return err
+	}
+	tokens, err := t.Repo.ListTokensByMarketIDs(ctx, marketIDs)
+	if err != nil {
+		return err
+	}
+	tokenByID := map[string]models.Token{}
+	yesByMarket := map[string]string{}
+	for _, tok := range tokens {
+		tokenByID[tok.ID] = tok
+		if tok.OutcomeIndex == 0 {
+			yesByMarket[tok.MarketID] = tok.ID
+		}
+	}
+	yesIDs := make([]string, 0, len(yesByMarket))
+	for _, id := range yesByMarket {
+		yesIDs = append(yesIDs, id)
+	}
+	books, err := t.Repo.ListOrderbookLatestByTokenIDs(ctx, yesIDs)
+	if err != nil {
+		return err
+	}
+	bookByToken := map[string]models.OrderbookLatest{}
+	for _, b := range books {
+		bookByToken[b.TokenID] = b
+	}
+
+	quotes := map[string][]rewardQuote{}
+	for _, o := range orders {
+		tok, ok := tokenByID[o.TokenID]
+		if !ok {
+			continue
+		}
+		price := o.Price.InexactFloat64()
+		if price <= 0 || price >= 1 {
+			continue
+		}
+		shares := o.SizeUSD.Sub(o.FilledUSD).InexactFloat64() / price
+		if shares <= 0 {
+			continue
+		}
+		bid := !isSellSide(o.Side)
+		if tok.OutcomeIndex != 0 {
+			// A NO bid at p rests on the YES book as an ask at 1-p.
+			price, bid = 1-price, !bid
+		}
+		quotes[tok.MarketID] = append(quotes[tok.MarketID], rewardQuote{Bid: bid, Price: price, Shares: shares})
+	}
+
+	trackedByMarket := map[string]models.RewardEpoch{}
+	for _, row := range tracked {
+		trackedByMarket[row.MarketID] = row
+	}
+	weight := t.interval().Hours() / 24
+	for _, m := range markets {
+		params, ok := rewardParamsFromMarket(m)
+		if !ok {
+			continue
+		}
+		book, ok := bookByToken[yesByMarket[m.ID]]
+		if !ok {
+			continue
+		}
+		obs, ok := observeRewards(makerBookFromLatest(book), params, quotes[m.ID])
+		if !ok {
+			continue
+		}
+		row, ok := trackedByMarket[m.ID]
+		if !ok {
+			if len(quotes[m.ID]) == 0 {
+				continue
+			}
+			row = models.RewardEpoch{Epoch: epoch, MarketID: m.ID}
+		}
+		row.MaxSpreadCents = params.MaxSpreadCents
+		row.MinSize = params.MinSize
+		row.DailyRateUSD = params.DailyRateUSD
+		row.Samples++
+		if obs.Quoted {
+			row.QuotedSamples++
+		}
+		if obs.Compliant {
+			row.CompliantSamples++
+		}
+		if obs.TwoSided {
+			row.TwoSidedSamples++
+		}
+		row.SizeAtBBO += obs.SizeAtBBO
+		row.ShareSum += obs.Share
+		row.EstimatedUSD = row.EstimatedUSD.Add(decimal.NewFromFloat(params.DailyRateUSD * obs.Share * weight))
+		sampledAt := now
+		row.LastSampledAt = &sampledAt
+		if err := t.Repo.SaveRewardEpoch(ctx, &row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewardObservation is one market sample.
+type rewardObservation struct {
+	Quoted    bool
+	Compliant bool
+	TwoSided  bool
+	SizeAtBBO float64
+	Share     float64
+}
+
+// observeRewards scores quotes (in YES price space) against the YES book.
+// ok is false when the book has no midpoint.
+func observeRewards(book makerBook, params rewardParams, quotes []rewardQuote) (rewardObservation, bool) {
+	if !book.twoSided() {
+		return rewardObservation{}, false
+	}
+	mid := (book.BestBid + book.BestAsk) / 2
+	score := func(price, shares float64) float64 {
+		spread := math.Abs(price-mid) * 100
+		if spread > params.MaxSpreadCents || shares < params.MinSize {
+			return 0
+		}
+		r := (params.MaxSpreadCents - spread) / params.MaxSpreadCents
+		return r * r * shares
+	}
+	obs := rewardObservation{Quoted: len(quotes) > 0}
+	var ourBid, ourAsk float64
+	for _, q := range quotes {
+		s := score(q.Price, q.Shares)
+		if s > 0 {
+			obs.Compliant = true
+		}
+		if q.Bid {
+			ourBid += s
+			if q.Price >= book.BestBid {
+				obs.SizeAtBBO += q.Shares
+			}
+		} else {
+			ourAsk += s
+			if q.Price <= book.BestAsk {
+				obs.SizeAtBBO += q.Shares
+			}
+		}
+	}
+	obs.TwoSided = ourBid > 0 && ourAsk > 0
+	var bookBid, bookAsk float64
+	for _, l := range book.Bids {
+		bookBid += score(l.Price, l.Size)
+	}
+	for _, l := range book.Asks {
+		bookAsk += score(l.Price, l.Size)
+	}
+	// Our orders are in the book; stored levels may lag them.
+	bookBid, bookAsk = math.Max(bookBid, ourBid), math.Max(bookAsk, ourAsk)
+	combine := func(bid, ask float64) float64 {
+		lo, hi := math.Min(bid, ask), math.Max(bid, ask)
+		if mid >= 0.10 && mid <= 0.90 {
+			return math.Max(lo, hi/3)
+		}
+		return lo
+	}
+	if total := combine(bookBid, bookAsk); total > 0 {
+		obs.Share = math.Min(combine(ourBid, ourAsk)/total, 1)
+	}
+	return obs, true
+}
+
+// RewardReportRow is one market epoch with derived ratios.
+type RewardReportRow struct {
+	models.RewardEpoch
+	UptimePct        float64  `json:"uptime_pct"`
+	CompliancePct    float64  `json:"compliance_pct"`
+	TwoSidedPct      float64  `json:"two_sided_pct"`
+	AvgSizeAtBBO     float64  `json:"avg_size_at_bbo"`
+	AvgSharePct      float64  `json:"avg_share_pct"`
+	DiffUSD          *float64 `json:"diff_usd,omitempty"`
+	ReceivedPctOfEst *float64 `json:"received_pct_of_estimate,omitempty"`
+}
+
+// NewRewardReportRow derives the ratios of row. DiffUSD is received minus
+// estimated and is only set once a payout was recorded.
+func NewRewardReportRow(row models.RewardEpoch) RewardReportRow {
+	out := RewardReportRow{RewardEpoch: row}
+	if row.Samples > 0 {
+		out.UptimePct = float64(row.QuotedSamples) / float64(row.Samples) * 100
+		out.AvgSharePct = row.ShareSum / float64(row.Samples) * 100
+	}
+	if row.QuotedSamples > 0 {
+		out.CompliancePct = float64(row.CompliantSamples) / float64(row.QuotedSamples) * 100
+		out.TwoSidedPct = float64(row.TwoSidedSamples) / float64(row.QuotedSamples) * 100
+		out.AvgSizeAtBBO = row.SizeAtBBO / float64(row.QuotedSamples)
+	}
+	if row.ReceivedUSD != nil {
+		diff := row.ReceivedUSD.Sub(row.EstimatedUSD).InexactFloat64()
+		out.DiffUSD = &diff
+		if est := row.EstimatedUSD.InexactFloat64(); est > 0 {
+			pct := row.ReceivedUSD.InexactFloat64() / est * 100
+			out.ReceivedPctOfEst = &pct
+		}
+	}
+	return out
+}
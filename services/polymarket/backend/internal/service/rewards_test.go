@@ -0,0 +1,56 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"polymarket/internal/models"
+)
+
+func TestObserveRewards_ScoresAgainstBook(t *testing.T) {
+	params := rewardParams{MaxSpreadCents: 3, MinSize: 100, DailyRateUSD: 50}
+	book := makerBook{
+		BestBid: 0.48,
+		BestAsk: 0.52,
+		Bids:    []priceLevel{{Price: 0.48, Size: 200}, {Price: 0.47, Size: 100}},
+		Asks:    []priceLevel{{Price: 0.52, Size: 200}},
+	}
+	// One-sided: our bid scores 100/9 against 200/9 per side, and a single
+	// side earns a third near the midpoint.
+	obs, ok := observeRewards(book, params, []rewardQuote{{Bid: true, Price: 0.48, Shares: 100}})
+	if !ok || !obs.Quoted || !obs.Compliant || obs.TwoSided || obs.SizeAtBBO != 100 {
+		t.Fatalf("obs=%+v ok=%v", obs, ok)
+	}
+	if math.Abs(obs.Share-1.0/6) > 1e-9 {
+		t.Fatalf("share=%v want 1/6", obs.Share)
+	}
+
+	obs, _ = observeRewards(book, params, []rewardQuote{
+		{Bid: true, Price: 0.48, Shares: 100},
+		{Bid: false, Price: 0.52, Shares: 100},
+	})
+	if !obs.TwoSided || math.Abs(obs.Share-0.5) > 1e-9 {
+		t.Fatalf("two-sided obs=%+v", obs)
+	}
+
+	// Too small or too wide earns nothing.
+	obs, _ = observeRewards(book, params, []rewardQuote{{Bid: true, Price: 0.48, Shares: 50}, {Bid: true, Price: 0.46, Shares: 500}})
+	if obs.Compliant || obs.Share != 0 {
+		t.Fatalf("non-compliant obs=%+v", obs)
+	}
+
+	if _, ok := observeRewards(makerBook{BestBid: 0.48}, params, nil); ok {
+		t.Fatalf("one-sided book has no midpoint")
+	}
+}
+
+func TestRewardParamsFromMarket(t *testing.T) {
+	m := models.Market{RawJSON: []byte(`{"rewardsMaxSpread":"3.5","rewardsMinSize":50,"clobRewards":[{"rewardsDailyRate":10},{"rewardsDailyRate":"2.5"}]}`)}
+	p, ok := rewardParamsFromMarket(m)
+	if !ok || p.MaxSpreadCents != 3.5 || p.MinSize != 50 || p.DailyRateUSD != 12.5 {
+		t.Fatalf("params=%+v ok=%v", p, ok)
+	}
+	if _, ok := rewardParamsFromMarket(models.Market{RawJSON: []byte(`{"question":"x"}`)}); ok {
+		t.Fatalf("market without program must be skipped")
+	}
+}
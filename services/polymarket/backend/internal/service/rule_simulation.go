@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/expr"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/tradingday"
+)
+
+// Rejection reasons of a simulated execution rule, in gate order.
+const (
+	SimRejectShadow     = "shadow"
+	SimRejectConfidence = "min_confidence"
+	SimRejectEdge       = "min_edge"
+	SimRejectCondition  = "condition"
+	SimRejectDailyCap   = "max_daily_trades"
+)
+
+// Exit reasons of a simulated leg. Open legs are marked to the last candle;
+// no_data legs had no candles after entry and carry no PnL.
+const (
+	SimExitStopLoss   = "stop_loss"
+	SimExitTakeProfit = "take_profit"
+	SimExitMaxHold    = "max_hold_hours"
+	SimExitOpen       = "open"
+	SimExitNoData     = "no_data"
+)
+
+// ExecutionRuleSimulator replays a strategy's past opportunities through a
+// candidate execution rule: the auto executor gates decide which would have
+// executed, and the position manager exits, checked on candle closes, price
+// them. Budgets, risk limits and campaigns are not simulated.
+type ExecutionRuleSimulator struct {
+	Repo   repository.Repository
+	Config config.AutoExecutorConfig
+	// Calendar sets when MaxDailyTrades resets; nil is UTC midnight.
+	Calendar *tradingday.Calendar
+	// MaxOpportunities caps the replay; 0 is 5000.
+	MaxOpportunities int
+}
+
+// RuleSimulationResult summarizes a replay. PnL sums legs that had candles;
+// ActualPnLUSD sums the realized PnL of plans the opportunities really got.
+type RuleSimulationResult struct {
+	Strategy     string               `json:"strategy"`
+	Since        time.Time            `json:"since"`
+	Until        time.Time            `json:"until"`
+	Evaluated    int                  `json:"evaluated"`
+	WouldExecute int                  `json:"would_execute"`
+	Rejected     map[string]int       `json:"rejected"`
+	Exits        map[string]int       `json:"exits"`
+	PnLUSD       float64              `json:"pnl_usd"`
+	Wins         int                  `json:"wins"`
+	Losses       int                  `json:"losses"`
+	WinRate      float64              `json:"win_rate"`
+	ActualPlans  int                  `json:"actual_plans"`
+	ActualPnLUSD float64              `json:"actual_pnl_usd"`
+	Truncated    bool                 `json:"truncated"`
+	Trades       []SimulatedRuleTrade `json:"trades"`
+}
+
+// SimulatedRuleTrade is one opportunity the rule would have executed.
+type SimulatedRuleTrade struct {
+	OpportunityID uint64             `json:"opportunity_id"`
+	CreatedAt     time.Time          `json:"created_at"`
+	EdgePct       decimal.Decimal    `json:"edge_pct"`
+	Confidence    float64            `json:"confidence"`
+	SizeUSD       float64            `json:"size_usd"`
+	PnLUSD        float64            `json:"pnl_usd"`
+	Legs          []SimulatedRuleLeg `json:"legs"`
+	// ActualPlanID and ActualPnLUSD describe what really happened, if a plan
+	// was created from the opportunity.
+	ActualPlanID *uint64          `json:"actual_plan_id,omitempty"`
+	ActualPnLUSD *decimal.Decimal `json:"actual_pnl_usd,omitempty"`
+}
+
+// SimulatedRuleLeg is the hypothetical entry and exit of one leg.
+type SimulatedRuleLeg struct {
+	TokenID    string     `json:"token_id"`
+	Direction  string     `json:"direction"`
+	SizeUSD    float64    `json:"size_usd"`
+	EntryPrice float64    `json:"entry_price"`
+	ExitPrice  float64    `json:"exit_price"`
+	ExitAt     *time.Time `json:"exit_at,omitempty"`
+	ExitReason string     `json:"exit_reason"`
+	PnLUSD     float64    `json:"pnl_usd"`
+}
+
+// Simulate replays the strategy's opportunities created in [since, until)
+// through rule. Gates use the edge and confidence the strategy reported, not
+// later decay. AutoExecute is ignored: the point is to try a rule first.
+func (s *ExecutionRuleSimulator) Simulate(ctx context.Context, strategy string, rule models.ExecutionRule, since, until time.Time) (*RuleSimulationResult, error) {
+	if s == nil || s.Repo == nil {
+		return nil, errors.New("simulator unavailable")
+	}
+	strategy = strings.TrimSpace(strategy)
+	if strategy == "" {
+		return nil, errors.New("strategy required")
+	}
+	maxOpps := s.MaxOpportunities
+	if maxOpps <= 0 {
+		maxOpps = 5000
+	}
+	r, err := s.newReplay(strategy, rule, since, until)
+	if err != nil {
+		return nil, err
+	}
+	const pageSize = 500
+	for offset := 0; ; offset += pageSize {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		opps, err := s.Repo.ListOpportunities(ctx, repository.ListOpportunitiesParams{
+			StrategyName: &strategy,
+			Since:        &since,
+			Until:        &until,
+			Limit:        pageSize,
+			Offset:       offset,
+			OrderBy:      "created_at",
+			Asc:          boolPtrAuto(true),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, opp := range opps {
+			if r.out.Evaluated >= maxOpps {
+				r.out.Truncated = true
+				break
+			}
+			if err := r.add(ctx, opp); err != nil {
+				return nil, err
+			}
+		}
+		if r.out.Truncated || len(opps) < pageSize {
+			break
+		}
+	}
+	return r.finish(), nil
+}
+
+// SimulateOpportunities replays opps, which need not be stored, through rule
+// like Simulate does. opps must be in creation order.
+func (s *ExecutionRuleSimulator) SimulateOpportunities(ctx context.Context, strategy string, rule models.ExecutionRule, opps []models.Opportunity, since, until time.Time) (*RuleSimulationResult, error) {
+	if s == nil || s.Repo == nil {
+		return nil, errors.New("simulator unavailable")
+	}
+	r, err := s.newReplay(strings.TrimSpace(strategy), rule, since, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, opp := range opps {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err := r.add(ctx, opp); err != nil {
+			return nil, err
+		}
+	}
+	return r.finish(), nil
+}
+
+// ruleReplay accumulates one simulation run.
+type ruleReplay struct {
+	sim           *ExecutionRuleSimulator
+	rule          models.ExecutionRule
+	prog          *expr.Program
+	minConfidence float64
+	minEdge       decimal.Decimal
+	until         time.Time
+	perDay        map[time.Time]int
+	out           *RuleSimulationResult
+}
+
+func (s *ExecutionRuleSimulator) newReplay(strategy string, rule models.ExecutionRule, since, until time.Time) (*ruleReplay, error) {
+	var prog *expr.Program
+	if cond := strings.TrimSpace(rule.Condition); cond != "" {
+		p, err := CompileOpportunityCondition(cond)
+		if err != nil {
+			return nil, fmt.Errorf("execution rule condition: %w", err)
+		}
+		prog = p
+	}
+	minConfidence, minEdge := s.thresholds(rule)
+	return &ruleReplay{
+		sim:           s,
+		rule:          rule,
+		prog:          prog,
+		minConfidence: minConfidence,
+		minEdge:       minEdge,
+		until:         until,
+		perDay:        map[time.Time]int{},
+		out: &RuleSimulationResult{
+			Strategy: strategy,
+			Since:    since,
+			Until:    until,
+			Rejected: map[string]int{},
+			Exits:    map[string]int{},
+			Trades:   []SimulatedRuleTrade{},
+		},
+	}, nil
+}
+
+func (r *ruleReplay) add(ctx context.Context, opp models.Opportunity) error {
+	out := r.out
+	out.Evaluated++
+	reason, err := simulateRuleGates(ctx, r.sim.Repo, opp, r.minConfidence, r.minEdge, r.prog)
+	if err != nil {
+		return err
+	}
+	if reason == "" && r.rule.MaxDailyTrades > 0 {
+		day := r.sim.Calendar.Start(opp.CreatedAt)
+		if r.perDay[day] >= r.rule.MaxDailyTrades {
+			reason = SimRejectDailyCap
+		} else {
+			r.perDay[day]++
+		}
+	}
+	if reason != "" {
+		out.Rejected[reason]++
+		return nil
+	}
+	trade, err := r.sim.simulateTrade(ctx, opp, r.rule, r.until)
+	if err != nil {
+		return err
+	}
+	out.WouldExecute++
+	for _, leg := range trade.Legs {
+		out.Exits[leg.ExitReason]++
+	}
+	out.PnLUSD += trade.PnLUSD
+	switch {
+	case trade.PnLUSD > 0:
+		out.Wins++
+	case trade.PnLUSD < 0:
+		out.Losses++
+	}
+	if trade.ActualPlanID != nil {
+		out.ActualPlans++
+		if trade.ActualPnLUSD != nil {
+			out.ActualPnLUSD += trade.ActualPnLUSD.InexactFloat64()
+		}
+	}
+	out.Trades = append(out.Trades, trade)
+	return nil
+}
+
+func (r *ruleReplay) finish() *RuleSimulationResult {
+	out := r.out
+	if decided := out.Wins + out.Losses; decided > 0 {
+		out.WinRate = float64(out.Wins) / float64(decided)
+	}
+	out.PnLUSD = roundUSD(out.PnLUSD)
+	out.ActualPnLUSD = roundUSD(out.ActualPnLUSD)
+	return out
+}
+
+// thresholds resolves the rule's minimums like the auto executor does.
+func (s *ExecutionRuleSimulator) thresholds(rule models.ExecutionRule) (float64, decimal.Decimal) {
+	minConfidence := rule.MinConfidence
+	if minConfidence <= 0 {
+		minConfidence = s.Config.DefaultMinConfidence
+		if minConfidence <= 0 {
+			minConfidence = 0.8
+		}
+	}
+	minEdge := rule.MinEdgePct
+	if minEdge.LessThanOrEqual(decimal.Zero) {
+		minEdge = decimal.NewFromFloat(s.Config.DefaultMinEdgePct)
+		if minEdge.LessThanOrEqual(decimal.Zero) {
+			minEdge = decimal.NewFromFloat(0.05)
+		}
+	}
+	return minConfidence, minEdge
+}
+
+// simulateRuleGates returns the first gate opp fails, or "" when it passes.
+func simulateRuleGates(ctx context.Context, repo repository.Repository, opp models.Opportunity, minConfidence float64, minEdge decimal.Decimal, prog *expr.Program) (string, error) {
+	// Judge the opportunity as reported, not as decayed since.
+	opp.DecayedConfidence = nil
+	opp.DecayedEdgePct = nil
+	if opp.Shadow {
+		return SimRejectShadow, nil
+	}
+	if opp.Confidence < minConfidence {
+		return SimRejectConfidence, nil
+	}
+	if opp.EdgePct.LessThan(minEdge) {
+		return SimRejectEdge, nil
+	}
+	if prog != nil {
+		env, err := OpportunityConditionEnv(ctx, repo, opp, prog)
+		if err != nil {
+			return "", err
+		}
+		ok, err := prog.Eval(env)
+		if err != nil {
+			return "", fmt.Errorf("execution rule condition: %w", err)
+		}
+		if !ok {
+			return SimRejectCondition, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *ExecutionRuleSimulator) simulateTrade(ctx context.Context, opp models.Opportunity, rule models.ExecutionRule, until time.Time) (SimulatedRuleTrade, error) {
+	trade := SimulatedRuleTrade{
+		OpportunityID: opp.ID,
+		CreatedAt:     opp.CreatedAt,
+		EdgePct:       opp.EdgePct,
+		Confidence:    opp.Confidence,
+		Legs:          []SimulatedRuleLeg{},
+	}
+	var legs []autoPlanLeg
+	_ = json.Unmarshal(addAutoPlanLegSizing(opp.Legs, opp.MaxSize), &legs)
+	end := until
+	if now := time.Now().UTC(); end.After(now) {
+		end = now
+	}
+	if rule.MaxHoldHours > 0 {
+		if hold := opp.CreatedAt.Add(time.Duration(rule.MaxHoldHours) * time.Hour); hold.Before(end) {
+			end = hold
+		}
+	}
+	for _, leg := range legs {
+		tokenID := strings.TrimSpace(leg.TokenID)
+		entry := 0.0
+		if leg.TargetPrice != nil && *leg.TargetPrice > 0 {
+			entry = *leg.TargetPrice
+		} else if leg.CurrentBestAsk != nil && *leg.CurrentBestAsk > 0 {
+			entry = *leg.CurrentBestAsk
+		}
+		size := 0.0
+		if leg.SizeUSD != nil {
+			size = *leg.SizeUSD
+		}
+		if tokenID == "" || entry <= 0 || size <= 0 {
+			continue
+		}
+		var candles []models.PriceCandle
+		if end.After(opp.CreatedAt) {
+			var err error
+			candles, err = s.Repo.ListPriceCandles(ctx, tokenID, opp.CreatedAt, end)
+			if err != nil {
+				return trade, err
+			}
+		}
+		sl := SimulateRuleLeg(leg.Direction, entry, size, candles, rule, opp.CreatedAt, end)
+		sl.TokenID = tokenID
+		trade.SizeUSD += size
+		trade.PnLUSD += sl.PnLUSD
+		trade.Legs = append(trade.Legs, sl)
+	}
+	trade.SizeUSD = roundUSD(trade.SizeUSD)
+	trade.PnLUSD = roundUSD(trade.PnLUSD)
+	if opp.ID == 0 {
+		return trade, nil
+	}
+
+	plans, err := s.Repo.ListExecutionPlans(ctx, repository.ListExecutionPlansParams{OpportunityID: &opp.ID, Limit: 1})
+	if err != nil {
+		return trade, err
+	}
+	if len(plans) > 0 {
+		id := plans[0].ID
+		trade.ActualPlanID = &id
+		rec, err := s.Repo.GetPnLRecordByPlanID(ctx, id)
+		if err != nil {
+			return trade, err
+		}
+		if rec != nil && rec.RealizedPnL != nil {
+			pnl := *rec.RealizedPnL
+			trade.ActualPnLUSD = &pnl
+		}
+	}
+	return trade, nil
+}
+
+// SimulateRuleLeg walks candles after opened and exits the leg the way the
+// position manager would on each close: stop loss when the return falls
+// below -StopLossPct, take profit when it rises above TakeProfitPct, and at
+// end once MaxHoldHours have passed. Otherwise the leg stays open at the last
+// close. SELL legs gain when the price falls.
+func SimulateRuleLeg(direction string, entry, sizeUSD float64, candles []models.PriceCandle, rule models.ExecutionRule, opened, end time.Time) SimulatedRuleLeg {
+	out := SimulatedRuleLeg{
+		Direction:  strings.ToUpper(strings.TrimSpace(direction)),
+		SizeUSD:    sizeUSD,
+		EntryPrice: entry,
+		ExitReason: SimExitNoData,
+	}
+	if out.Direction == "" {
+		out.Direction = "BUY"
+	}
+	if entry <= 0 || sizeUSD <= 0 {
+		return out
+	}
+	ret := func(price float64) float64 {
+		r := price/entry - 1
+		if out.Direction == "SELL" {
+			r = -r
+		}
+		return r
+	}
+	stopLoss := rule.StopLossPct.InexactFloat64()
+	takeProfit := rule.TakeProfitPct.InexactFloat64()
+	for _, c := range candles {
+		if !c.CloseTS.After(opened) || c.CloseTS.After(end) || c.Close <= 0 {
+			continue
+		}
+		at := c.CloseTS
+		out.ExitPrice = c.Close
+		out.ExitAt = &at
+		out.ExitReason = SimExitOpen
+		r := ret(c.Close)
+		if r < -stopLoss {
+			out.ExitReason = SimExitStopLoss
+			break
+		}
+		if r > takeProfit {
+			out.ExitReason = SimExitTakeProfit
+			break
+		}
+	}
+	if out.ExitReason == SimExitNoData {
+		return out
+	}
+	if out.ExitReason == SimExitOpen && rule.MaxHoldHours > 0 &&
+		!end.Before(opened.Add(time.Duration(rule.MaxHoldHours)*time.Hour)) {
+		out.ExitReason = SimExitMaxHold
+	}
+	out.PnLUSD = roundUSD(sizeUSD * ret(out.ExitPrice))
+	return out
+}
+
+func roundUSD(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
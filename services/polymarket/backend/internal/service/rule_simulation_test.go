@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func simRule() models.ExecutionRule {
+	return models.ExecutionRule{
+		MinConfidence:  0.7,
+		MinEdgePct:     decimal.NewFromFloat(0.05),
+		StopLossPct:    decimal.NewFromFloat(0.10),
+		TakeProfitPct:  decimal.NewFromFloat(0.20),
+		MaxHoldHours:   24,
+		MaxDailyTrades: 1,
+	}
+}
+
+func simCandles(opened time.Time, closes ...float64) []models.PriceCandle {
+	out := make([]models.PriceCandle, 0, len(closes))
+	for i, c := range closes {
+		at := opened.Add(time.Duration(i+1) * time.Hour)
+		out = append(out, models.PriceCandle{BucketStart: at.Add(-time.Hour), CloseTS: at, Close: c})
+	}
+	return out
+}
+
+func TestSimulateRuleLegExits(t *testing.T) {
+	opened := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	rule := simRule()
+	end := opened.Add(24 * time.Hour)
+
+	leg := SimulateRuleLeg("BUY", 0.5, 100, simCandles(opened, 0.52, 0.44, 0.60), rule, opened, end)
+	if leg.ExitReason != SimExitStopLoss || leg.ExitPrice != 0.44 || leg.PnLUSD != -12 {
+		t.Fatalf("stop loss leg=%+v", leg)
+	}
+	leg = SimulateRuleLeg("BUY", 0.5, 100, simCandles(opened, 0.55, 0.61), rule, opened, end)
+	if leg.ExitReason != SimExitTakeProfit || leg.PnLUSD != 22 {
+		t.Fatalf("take profit leg=%+v", leg)
+	}
+	leg = SimulateRuleLeg("SELL", 0.5, 100, simCandles(opened, 0.48), rule, opened, end)
+	if leg.ExitReason != SimExitMaxHold || leg.PnLUSD != 4 {
+		t.Fatalf("max hold leg=%+v", leg)
+	}
+	leg = SimulateRuleLeg("BUY", 0.5, 100, simCandles(opened, 0.48), rule, opened, opened.Add(2*time.Hour))
+	if leg.ExitReason != SimExitOpen {
+		t.Fatalf("open leg=%+v", leg)
+	}
+	leg = SimulateRuleLeg("BUY", 0.5, 100, nil, rule, opened, end)
+	if leg.ExitReason != SimExitNoData || leg.PnLUSD != 0 {
+		t.Fatalf("no data leg=%+v", leg)
+	}
+}
+
+type simRepo struct {
+	repository.Repository
+	opps    []models.Opportunity
+	candles []models.PriceCandle
+	plans   map[uint64]models.ExecutionPlan
+	pnl     map[uint64]models.PnLRecord
+}
+
+func (r *simRepo) ListOpportunities(ctx context.Context, params repository.ListOpportunitiesParams) ([]models.Opportunity, error) {
+	if params.Offset > 0 {
+		return nil, nil
+	}
+	return r.opps, nil
+}
+
+func (r *simRepo) ListPriceCandles(ctx context.Context, tokenID string, since, until time.Time) ([]models.PriceCandle, error) {
+	var out []models.PriceCandle
+	for _, c := range r.candles {
+		if !c.BucketStart.Before(since) && c.BucketStart.Before(until) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (r *simRepo) ListExecutionPlans(ctx context.Context, params repository.ListExecutionPlansParams) ([]models.ExecutionPlan, error) {
+	if p, ok := r.plans[*params.OpportunityID]; ok {
+		return []models.ExecutionPlan{p}, nil
+	}
+	return nil, nil
+}
+
+func (r *simRepo) GetPnLRecordByPlanID(ctx context.Context, planID uint64) (*models.PnLRecord, error) {
+	if rec, ok := r.pnl[planID]; ok {
+		return &rec, nil
+	}
+	return nil, nil
+}
+
+func TestExecutionRuleSimulatorGates(t *testing.T) {
+	day := time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)
+	legs := datatypes.JSON(`[{"token_id":"t1","direction":"BUY","target_price":0.5}]`)
+	decayed := 0.1
+	opp := func(id uint64, at time.Time, edge, conf float64) models.Opportunity {
+		return models.Opportunity{
+			ID: id, CreatedAt: at, EdgePct: decimal.NewFromFloat(edge), Confidence: conf,
+			MaxSize: decimal.NewFromInt(100), Legs: legs, Strategy: models.Strategy{Name: "s"},
+		}
+	}
+	first := opp(1, day, 0.08, 0.9)
+	// Decayed since; the simulation judges the reported values.
+	first.DecayedConfidence = &decayed
+	shadow := opp(5, day.Add(3*time.Hour), 0.08, 0.9)
+	shadow.Shadow = true
+	realized := decimal.NewFromInt(7)
+	repo := &simRepo{
+		opps: []models.Opportunity{
+			first,
+			opp(2, day.Add(time.Hour), 0.02, 0.9),
+			opp(3, day.Add(2*time.Hour), 0.08, 0.5),
+			opp(4, day.Add(4*time.Hour), 0.08, 0.9),
+			shadow,
+			opp(6, day.Add(24*time.Hour), 0.09, 0.95),
+		},
+		candles: append(simCandles(day, 0.55, 0.65), simCandles(day.Add(24*time.Hour), 0.7)...),
+		plans:   map[uint64]models.ExecutionPlan{1: {ID: 11}},
+		pnl:     map[uint64]models.PnLRecord{11: {PlanID: 11, RealizedPnL: &realized}},
+	}
+	sim := &ExecutionRuleSimulator{Repo: repo}
+	rule := simRule()
+	rule.Condition = "edge_pct > 0.085"
+
+	out, err := sim.Simulate(context.Background(), "s", rule, day, day.Add(48*time.Hour))
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	// Only #6 clears the condition.
+	if out.Evaluated != 6 || out.WouldExecute != 1 || out.Rejected[SimRejectCondition] != 2 ||
+		out.Rejected[SimRejectEdge] != 1 || out.Rejected[SimRejectConfidence] != 1 || out.Rejected[SimRejectShadow] != 1 {
+		t.Fatalf("result=%+v", out)
+	}
+
+	rule.Condition = ""
+	out, err = sim.Simulate(context.Background(), "s", rule, day, day.Add(48*time.Hour))
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	// #1 and #6 pass; #4 hits the one-trade daily cap behind #1.
+	if out.WouldExecute != 2 || out.Rejected[SimRejectDailyCap] != 1 || out.Exits[SimExitTakeProfit] != 2 {
+		t.Fatalf("result=%+v", out)
+	}
+	if out.PnLUSD != 70 || out.WinRate != 1 || out.ActualPlans != 1 || out.ActualPnLUSD != 7 {
+		t.Fatalf("pnl=%+v", out)
+	}
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+var (
+	ErrRuleTuningInvalid     = errors.New("invalid rule tuning")
+	ErrRuleTuningNotProposed = errors.New("only proposed tunings can be reviewed")
+)
+
+// RuleTuningGrid lists the values to try per execution rule field. A field
+// left empty keeps the base rule's value.
+type RuleTuningGrid struct {
+	MinConfidence []float64         `json:"min_confidence,omitempty"`
+	MinEdgePct    []decimal.Decimal `json:"min_edge_pct,omitempty"`
+	StopLossPct   []decimal.Decimal `json:"stop_loss_pct,omitempty"`
+	TakeProfitPct []decimal.Decimal `json:"take_profit_pct,omitempty"`
+	MaxHoldHours  []int             `json:"max_hold_hours,omitempty"`
+}
+
+// RuleTuningParams are the tuned fields of one candidate rule.
+type RuleTuningParams struct {
+	MinConfidence float64         `json:"min_confidence"`
+	MinEdgePct    decimal.Decimal `json:"min_edge_pct"`
+	StopLossPct   decimal.Decimal `json:"stop_loss_pct"`
+	TakeProfitPct decimal.Decimal `json:"take_profit_pct"`
+	MaxHoldHours  int             `json:"max_hold_hours"`
+}
+
+// RuleTuningScore is a candidate's replay over one window. Sharpe is the
+// mean over the standard deviation of per-trade PnL, 0 under two trades.
+type RuleTuningScore struct {
+	Trades  int     `json:"trades"`
+	PnLUSD  float64 `json:"pnl_usd"`
+	WinRate float64 `json:"win_rate"`
+	Sharpe  float64 `json:"sharpe"`
+}
+
+// RuleTuningCandidate is one point of the grid with its in-sample (Train)
+// and out-of-sample (Test) scores.
+type RuleTuningCandidate struct {
+	Params RuleTuningParams `json:"params"`
+	Train  RuleTuningScore  `json:"train"`
+	Test   RuleTuningScore  `json:"test"`
+}
+
+// ExecutionRuleTuner grid-searches a strategy's execution rule over its past
+// opportunities with the rule simulator. Candidates are ranked on the
+// training window only; the best is proposed when it also beats the current
+// rule on the held-out window, and nothing is written to the rule until the
+// proposal is approved.
+type ExecutionRuleTuner struct {
+	Repo      repository.Repository
+	Simulator *ExecutionRuleSimulator
+	// MaxCandidates caps the grid size; 0 is 100.
+	MaxCandidates int
+	// FrontierSize is how many candidates a run keeps; 0 is 10.
+	FrontierSize int
+}
+
+// Tune replays every grid candidate built on base over [since, until),
+// split at trainFrac of the window, and stores the run.
+func (t *ExecutionRuleTuner) Tune(ctx context.Context, base models.ExecutionRule, grid RuleTuningGrid, since, until time.Time, trainFrac float64) (*models.RuleTuning, error) {
+	if t == nil || t.Repo == nil || t.Simulator == nil {
+		return nil, errors.New("rule tuner unavailable")
+	}
+	if !since.Before(until) {
+		return nil, fmt.Errorf("%w: since must be before until", ErrRuleTuningInvalid)
+	}
+	if trainFrac == 0 {
+		trainFrac = 0.7
+	}
+	if trainFrac <= 0 || trainFrac >= 1 {
+		return nil, fmt.Errorf("%w: train_fraction must be between 0 and 1", ErrRuleTuningInvalid)
+	}
+	candidates, err := grid.expand(ruleTuningParams(base))
+	if err != nil {
+		return nil, err
+	}
+	maxCandidates := t.MaxCandidates
+	if maxCandidates <= 0 {
+		maxCandidates = 100
+	}
+	if len(candidates) > maxCandidates {
+		return nil, fmt.Errorf("%w: grid has %d candidates, max %d", ErrRuleTuningInvalid, len(candidates), maxCandidates)
+	}
+	split := since.Add(time.Duration(float64(until.Sub(since)) * trainFrac))
+
+	baseline, err := t.score(ctx, base, ruleTuningParams(base), since, split, until)
+	if err != nil {
+		return nil, err
+	}
+	scored := make([]RuleTuningCandidate, 0, len(candidates))
+	for _, p := range candidates {
+		c, err := t.score(ctx, base, p, since, split, until)
+		if err != nil {
+			return nil, err
+		}
+		scored = append(scored, c)
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].Train.PnLUSD != scored[j].Train.PnLUSD {
+			return scored[i].Train.PnLUSD > scored[j].Train.PnLUSD
+		}
+		return scored[i].Train.Sharpe > scored[j].Train.Sharpe
+	})
+	size := t.FrontierSize
+	if size <= 0 {
+		size = 10
+	}
+	frontier := scored
+	if len(frontier) > size {
+		frontier = frontier[:size]
+	}
+
+	run := &models.RuleTuning{
+		StrategyName: base.StrategyName,
+		Status:       models.RuleTuningNoImprovement,
+		Since:        since,
+		SplitAt:      split,
+		Until:        until,
+		Candidates:   len(candidates),
+		Grid:         mustJSON(grid),
+		Baseline:     mustJSON(baseline),
+		Frontier:     mustJSON(frontier),
+	}
+	if len(frontier) > 0 {
+		best := frontier[0]
+		if !best.Params.equal(baseline.Params) && best.Test.PnLUSD > baseline.Test.PnLUSD {
+			run.Status = models.RuleTuningProposed
+			run.Proposed = mustJSON(best.Params)
+		}
+	}
+	if err := t.Repo.InsertRuleTuning(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (t *ExecutionRuleTuner) score(ctx context.Context, base models.ExecutionRule, p RuleTuningParams, since, split, until time.Time) (RuleTuningCandidate, error) {
+	rule := base
+	p.apply(&rule)
+	out := RuleTuningCandidate{Params: p}
+	train, err := t.Simulator.Simulate(ctx, base.StrategyName, rule, since, split)
+	if err != nil {
+		return out, err
+	}
+	test, err := t.Simulator.Simulate(ctx, base.StrategyName, rule, split, until)
+	if err != nil {
+		return out, err
+	}
+	out.Train = ruleTuningScore(train)
+	out.Test = ruleTuningScore(test)
+	return out, nil
+}
+
+// Approve writes the run's proposed params to the strategy's execution
+// rule and marks the run approved, in one transaction. It returns nil when
+// the run does not exist.
+func (t *ExecutionRuleTuner) Approve(ctx context.Context, id uint64, reviewer, note string, now time.Time) (*models.RuleTuning, error) {
+	return t.review(ctx, id, models.RuleTuningApproved, reviewer, note, now)
+}
+
+// Reject closes a proposed run without touching the rule.
+func (t *ExecutionRuleTuner) Reject(ctx context.Context, id uint64, reviewer, note string, now time.Time) (*models.RuleTuning, error) {
+	return t.review(ctx, id, models.RuleTuningRejected, reviewer, note, now)
+}
+
+func (t *ExecutionRuleTuner) review(ctx context.Context, id uint64, status, reviewer, note string, now time.Time) (*models.RuleTuning, error) {
+	if t == nil || t.Repo == nil {
+		return nil, errors.New("rule tuner unavailable")
+	}
+	var out *models.RuleTuning
+	err := t.Repo.InTx(ctx, func(tx *gorm.DB) error {
+		repo := t.Repo.WithTx(tx)
+		run, err := repo.GetRuleTuning(ctx, id)
+		if err != nil || run == nil {
+			return err
+		}
+		if run.Status != models.RuleTuningProposed {
+			return ErrRuleTuningNotProposed
+		}
+		if status == models.RuleTuningApproved {
+			var p RuleTuningParams
+			if err := json.Unmarshal(run.Proposed, &p); err != nil {
+				return err
+			}
+			rule, err := repo.GetExecutionRuleByStrategyName(ctx, run.StrategyName)
+			if err != nil {
+				return err
+			}
+			if rule == nil {
+				return fmt.Errorf("%w: execution rule for %s no longer exists", ErrRuleTuningInvalid, run.StrategyName)
+			}
+			p.apply(rule)
+			rule.UpdatedAt = now
+			if err := repo.UpsertExecutionRule(ctx, rule); err != nil {
+				return err
+			}
+		}
+		run.Status = status
+		run.ReviewedBy = strings.TrimSpace(reviewer)
+		run.ReviewNote = strings.TrimSpace(note)
+		run.ReviewedAt = &now
+		if err := repo.UpdateRuleTuning(ctx, run); err != nil {
+			return err
+		}
+		out = run
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func ruleTuningParams(rule models.ExecutionRule) RuleTuningParams {
+	return RuleTuningParams{
+		MinConfidence: rule.MinConfidence,
+		MinEdgePct:    rule.MinEdgePct,
+		StopLossPct:   rule.StopLossPct,
+		TakeProfitPct: rule.TakeProfitPct,
+		MaxHoldHours:  rule.MaxHoldHours,
+	}
+}
+
+func (p RuleTuningParams) apply(rule *models.ExecutionRule) {
+	rule.MinConfidence = p.MinConfidence
+	rule.MinEdgePct = p.MinEdgePct
+	rule.StopLossPct = p.StopLossPct
+	rule.TakeProfitPct = p.TakeProfitPct
+	rule.MaxHoldHours = p.MaxHoldHours
+}
+
+func (p RuleTuningParams) equal(o RuleTuningParams) bool {
+	return p.MinConfidence == o.MinConfidence && p.MinEdgePct.Equal(o.MinEdgePct) &&
+		p.StopLossPct.Equal(o.StopLossPct) && p.TakeProfitPct.Equal(o.TakeProfitPct) &&
+		p.MaxHoldHours == o.MaxHoldHours
+}
+
+// expand returns the cartesian product of the grid, filling empty fields
+// from base. Values are validated like a rule edit would be.
+func (g RuleTuningGrid) expand(base RuleTuningParams) ([]RuleTuningParams, error) {
+	confidences := g.MinConfidence
+	if len(confidences) == 0 {
+		confidences = []float64{base.MinConfidence}
+	}
+	for _, v := range confidences {
+		if v < 0 || v > 1 {
+			return nil, fmt.Errorf("%w: min_confidence must be between 0 and 1", ErrRuleTuningInvalid)
+		}
+	}
+	edges, err := positiveDecimals("min_edge_pct", g.MinEdgePct, base.MinEdgePct)
+	if err != nil {
+		return nil, err
+	}
+	stops, err := positiveDecimals("stop_loss_pct", g.StopLossPct, base.StopLossPct)
+	if err != nil {
+		return nil, err
+	}
+	takes, err := positiveDecimals("take_profit_pct", g.TakeProfitPct, base.TakeProfitPct)
+	if err != nil {
+		return nil, err
+	}
+	holds := g.MaxHoldHours
+	if len(holds) == 0 {
+		holds = []int{base.MaxHoldHours}
+	}
+	for _, v := range holds {
+		if v < 0 {
+			return nil, fmt.Errorf("%w: max_hold_hours must not be negative", ErrRuleTuningInvalid)
+		}
+	}
+	var out []RuleTuningParams
+	for _, conf := range confidences {
+		for _, edge := range edges {
+			for _, stop := range stops {
+				for _, take := range takes {
+					for _, hold := range holds {
+						out = append(out, RuleTuningParams{MinConfidence: conf, MinEdgePct: edge, StopLossPct: stop, TakeProfitPct: take, MaxHoldHours: hold})
+					}
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+func positiveDecimals(field string, values []decimal.Decimal, base decimal.Decimal) ([]decimal.Decimal, error) {
+	if len(values) == 0 {
+		return []decimal.Decimal{base}, nil
+	}
+	for _, v := range values {
+		if !v.IsPositive() {
+			return nil, fmt.Errorf("%w: %s values must be positive", ErrRuleTuningInvalid, field)
+		}
+	}
+	return values, nil
+}
+
+func ruleTuningScore(res *RuleSimulationResult) RuleTuningScore {
+	out := RuleTuningScore{Trades: res.WouldExecute, PnLUSD: res.PnLUSD, WinRate: res.WinRate}
+	n := float64(len(res.Trades))
+	if n < 2 {
+		return out
+	}
+	mean := 0.0
+	for _, tr := range res.Trades {
+		mean += tr.PnLUSD
+	}
+	mean /= n
+	variance := 0.0
+	for _, tr := range res.Trades {
+		variance += (tr.PnLUSD - mean) * (tr.PnLUSD - mean)
+	}
+	if sd := math.Sqrt(variance / (n - 1)); sd > 0 {
+		out.Sharpe = math.Round(mean/sd*1e4) / 1e4
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type tuningRepo struct {
+	simRepo
+	byToken map[string][]models.PriceCandle
+	rule    *models.ExecutionRule
+	runs    []models.RuleTuning
+}
+
+func (r *tuningRepo) ListOpportunities(ctx context.Context, params repository.ListOpportunitiesParams) ([]models.Opportunity, error) {
+	if params.Offset > 0 {
+		return nil, nil
+	}
+	var out []models.Opportunity
+	for _, o := range r.opps {
+		if !o.CreatedAt.Before(*params.Since) && o.CreatedAt.Before(*params.Until) {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (r *tuningRepo) ListPriceCandles(ctx context.Context, tokenID string, since, until time.Time) ([]models.PriceCandle, error) {
+	var out []models.PriceCandle
+	for _, c := range r.byToken[tokenID] {
+		if !c.BucketStart.Before(since) && c.BucketStart.Before(until) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (r *tuningRepo) InTx(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+func (r *tuningRepo) WithTx(tx *gorm.DB) repository.Repository {
+	return r
+}
+
+func (r *tuningRepo) GetExecutionRuleByStrategyName(ctx context.Context, name string) (*models.ExecutionRule, error) {
+	return r.rule, nil
+}
+
+func (r *tuningRepo) UpsertExecutionRule(ctx context.Context, item *models.ExecutionRule) error {
+	r.rule = item
+	return nil
+}
+
+func (r *tuningRepo) InsertRuleTuning(ctx context.Context, item *models.RuleTuning) error {
+	item.ID = uint64(len(r.runs) + 1)
+	r.runs = append(r.runs, *item)
+	return nil
+}
+
+func (r *tuningRepo) UpdateRuleTuning(ctx context.Context, item *models.RuleTuning) error {
+	r.runs[item.ID-1] = *item
+	return nil
+}
+
+func (r *tuningRepo) GetRuleTuning(ctx context.Context, id uint64) (*models.RuleTuning, error) {
+	if id == 0 || int(id) > len(r.runs) {
+		return nil, nil
+	}
+	run := r.runs[id-1]
+	return &run, nil
+}
+
+func TestExecutionRuleTunerProposesAndApproves(t *testing.T) {
+	day := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	opp := func(id uint64, at time.Time, edge float64, token string) models.Opportunity {
+		return models.Opportunity{
+			ID: id, CreatedAt: at, EdgePct: decimal.NewFromFloat(edge), Confidence: 0.9, MaxSize: decimal.NewFromInt(100),
+			Legs: datatypes.JSON(fmt.Sprintf(`[{"token_id":%q,"direction":"BUY","target_price":0.5}]`, token)),
+		}
+	}
+	// Thin-edge opportunities lose in both halves of the window.
+	repo := &tuningRepo{
+		simRepo: simRepo{opps: []models.Opportunity{
+			opp(1, day.Add(time.Hour), 0.06, "lose1"),
+			opp(2, day.Add(2*time.Hour), 0.10, "win1"),
+			opp(3, day.Add(50*time.Hour), 0.06, "lose2"),
+			opp(4, day.Add(51*time.Hour), 0.10, "win2"),
+		}},
+		byToken: map[string][]models.PriceCandle{
+			"lose1": simCandles(day.Add(time.Hour), 0.4),
+			"win1":  simCandles(day.Add(2*time.Hour), 0.65),
+			"lose2": simCandles(day.Add(50*time.Hour), 0.4),
+			"win2":  simCandles(day.Add(51*time.Hour), 0.65),
+		},
+	}
+	rule := simRule()
+	rule.StrategyName = "s"
+	rule.MaxDailyTrades = 10
+	repo.rule = &rule
+	tuner := &ExecutionRuleTuner{Repo: repo, Simulator: &ExecutionRuleSimulator{Repo: repo}}
+	ctx := context.Background()
+
+	grid := RuleTuningGrid{MinEdgePct: []decimal.Decimal{decimal.NewFromFloat(0.05), decimal.NewFromFloat(0.08)}}
+	run, err := tuner.Tune(ctx, rule, grid, day, day.Add(96*time.Hour), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != models.RuleTuningProposed || run.Candidates != 2 {
+		t.Fatalf("run = %+v", run)
+	}
+	var frontier []RuleTuningCandidate
+	_ = json.Unmarshal(run.Frontier, &frontier)
+	if len(frontier) != 2 || frontier[0].Train.PnLUSD != 30 || frontier[0].Test.PnLUSD != 30 || frontier[1].Test.PnLUSD != 10 {
+		t.Fatalf("frontier = %+v", frontier)
+	}
+	if !repo.rule.MinEdgePct.Equal(decimal.NewFromFloat(0.05)) {
+		t.Fatalf("rule changed before approval: %s", repo.rule.MinEdgePct)
+	}
+
+	approved, err := tuner.Approve(ctx, run.ID, "desk/admin", "ok", day.Add(100*time.Hour))
+	if err != nil || approved.Status != models.RuleTuningApproved || approved.ReviewedBy != "desk/admin" {
+		t.Fatalf("approve = %+v, %v", approved, err)
+	}
+	if !repo.rule.MinEdgePct.Equal(decimal.NewFromFloat(0.08)) {
+		t.Fatalf("rule min edge = %s", repo.rule.MinEdgePct)
+	}
+	if _, err := tuner.Reject(ctx, run.ID, "", "", day); !errors.Is(err, ErrRuleTuningNotProposed) {
+		t.Fatalf("second review err = %v", err)
+	}
+
+	// A grid that only holds the current rule has nothing to propose.
+	run, err = tuner.Tune(ctx, *repo.rule, RuleTuningGrid{}, day, day.Add(96*time.Hour), 0.5)
+	if err != nil || run.Status != models.RuleTuningNoImprovement || run.Proposed != nil {
+		t.Fatalf("no-op run = %+v, %v", run, err)
+	}
+	if _, err := tuner.Tune(ctx, rule, RuleTuningGrid{StopLossPct: []decimal.Decimal{decimal.Zero}}, day, day.Add(time.Hour), 0); !errors.Is(err, ErrRuleTuningInvalid) {
+		t.Fatalf("invalid grid err = %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// SafeModeSettingKey stores the current safe mode state and the switches
+// it turned off, so resume survives restarts.
+const SafeModeSettingKey = "system.safe_mode"
+
+// safeModeOrderStatuses are the order states safe mode cancels.
+var safeModeOrderStatuses = models.OpenOrderStatuses()
+
+// SafeModeState is the stored safe mode record. Switches holds the value
+// each switch had before safe mode turned it off.
+type SafeModeState struct {
+	Active    bool            `json:"active"`
+	Reason    string          `json:"reason,omitempty"`
+	EnteredAt *time.Time      `json:"entered_at,omitempty"`
+	ResumedAt *time.Time      `json:"resumed_at,omitempty"`
+	Switches  map[string]bool `json:"switches,omitempty"`
+}
+
+// SafeModeResult reports what one enter or resume call changed.
+type SafeModeResult struct {
+	State           SafeModeState `json:"state"`
+	SwitchesChanged []string      `json:"switches_changed"`
+	OrdersCancelled []uint64      `json:"orders_cancelled,omitempty"`
+	OrdersFailed    []uint64      `json:"orders_failed,omitempty"`
+}
+
+// SafeModeService stops automated trading and background syncs in one call
+// and undoes exactly that on resume.
+type SafeModeService struct {
+	Repo     repository.Repository
+	Settings *SystemSettingsService
+	// Orders cancels open orders on enter; nil skips cancellation.
+	Orders interface {
+		CancelOrder(ctx context.Context, orderID uint64) error
+	}
+	Config config.SafeModeConfig
+	Logger *zap.Logger
+	// Notify routes safe mode alerts to the notification dispatcher; nil
+	// broadcasts through the platform client in ctx.
+	Notify func(ctx context.Context, event, message string) error
+
+	mu sync.Mutex
+}
+
+// switches are the feature switches safe mode turns off.
+func (s *SafeModeService) switches() []string {
+	out := []string{FeatureAutoExecutor, FeatureStrategyEngine}
+	seen := map[string]bool{FeatureAutoExecutor: true, FeatureStrategyEngine: true}
+	for _, key := range s.Config.PauseSwitches {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] || key == FeaturePositionSync {
+			continue
+		}
+		seen[key] = true
+		out = append(out, key)
+	}
+	return out
+}
+
+// Status returns the stored safe mode state.
+func (s *SafeModeService) Status(ctx context.Context) (SafeModeState, error) {
+	var state SafeModeState
+	if s == nil || s.Repo == nil {
+		return state, nil
+	}
+	item, err := s.Repo.GetSystemSettingByKey(ctx, SafeModeSettingKey)
+	if err != nil || item == nil || len(item.Value) == 0 {
+		return state, err
+	}
+	if err := json.Unmarshal(item.Value, &state); err != nil {
+		return SafeModeState{}, err
+	}
+	return state, nil
+}
+
+// Enter turns off the safe mode switches and records the state in one
+// write, then cancels open orders when cancelOrders is true and notifies.
+// Entering again while active keeps the first recorded switch values.
+func (s *SafeModeService) Enter(ctx context.Context, reason string, cancelOrders bool, now time.Time) (*SafeModeResult, error) {
+	if s == nil || s.Repo == nil || s.Settings == nil {
+		return nil, fmt.Errorf("safe mode unavailable")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, err := s.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !state.Active {
+		state = SafeModeState{Switches: map[string]bool{}}
+		at := now.UTC()
+		state.EnteredAt = &at
+	}
+	if state.Switches == nil {
+		state.Switches = map[string]bool{}
+	}
+	state.Active = true
+	state.ResumedAt = nil
+	if r := strings.TrimSpace(reason); r != "" {
+		state.Reason = r
+	}
+
+	res := &SafeModeResult{SwitchesChanged: []string{}}
+	updates := map[string]bool{}
+	for _, key := range s.switches() {
+		fallback := DefaultFeatureSwitches()[key]
+		if !s.Settings.IsEnabled(ctx, key, fallback) {
+			continue
+		}
+		if _, ok := state.Switches[key]; !ok {
+			state.Switches[key] = true
+		}
+		updates[key] = false
+		res.SwitchesChanged = append(res.SwitchesChanged, key)
+	}
+	if err := s.write(ctx, updates, state, now); err != nil {
+		return nil, err
+	}
+	res.State = state
+
+	if cancelOrders && s.Orders != nil {
+		res.OrdersCancelled, res.OrdersFailed = s.cancelOpenOrders(ctx)
+	}
+	s.announce(ctx, "enter", res)
+	return res, nil
+}
+
+// Resume turns back on the switches safe mode turned off and clears the
+// state. Open orders cancelled on enter are not restored.
+func (s *SafeModeService) Resume(ctx context.Context, now time.Time) (*SafeModeResult, error) {
+	if s == nil || s.Repo == nil || s.Settings == nil {
+		return nil, fmt.Errorf("safe mode unavailable")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, err := s.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &SafeModeResult{SwitchesChanged: []string{}}
+	if !state.Active {
+		res.State = state
+		return res, nil
+	}
+	updates := map[string]bool{}
+	for key, prev := range state.Switches {
+		if prev {
+			updates[key] = true
+			res.SwitchesChanged = append(res.SwitchesChanged, key)
+		}
+	}
+	sort.Strings(res.SwitchesChanged)
+	at := now.UTC()
+	state.Active = false
+	state.ResumedAt = &at
+	state.Switches = nil
+	if err := s.write(ctx, updates, state, now); err != nil {
+		return nil, err
+	}
+	res.State = state
+	s.announce(ctx, "resume", res)
+	return res, nil
+}
+
+// write stores the switch updates and the state in one transaction.
+func (s *SafeModeService) write(ctx context.Context, updates map[string]bool, state SafeModeState, now time.Time) error {
+	now = now.UTC()
+	items := make([]models.SystemSetting, 0, len(updates)+1)
+	for key, enabled := range updates {
+		raw, _ := json.Marshal(enabled)
+		items = append(items, models.SystemSetting{
+			Key:         key,
+			Value:       datatypes.JSON(raw),
+			Description: "feature switch",
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	items = append(items, models.SystemSetting{
+		Key:         SafeModeSettingKey,
+		Value:       datatypes.JSON(raw),
+		Description: "safe mode state",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err := s.Repo.UpsertSystemSettings(ctx, items); err != nil {
+		return err
+	}
+	for _, it := range items {
+		s.Settings.Changed(it.Key, it.Value)
+	}
+	return nil
+}
+
+func (s *SafeModeService) cancelOpenOrders(ctx context.Context) ([]uint64, []uint64) {
+	var cancelled, failed []uint64
+	for _, status := range safeModeOrderStatuses {
+		status := status
+		orders, err := s.Repo.ListOrders(ctx, repository.ListOrdersParams{Limit: 500, Status: &status, OrderBy: "created_at", Asc: boolPtrExecutor(true)})
+		if err != nil {
+			if s.Logger != nil {
+				s.Logger.Warn("safe mode order list failed", zap.String("status", status), zap.Error(err))
+			}
+			continue
+		}
+		for _, o := range orders {
+			if err := s.Orders.CancelOrder(ctx, o.ID); err != nil {
+				failed = append(failed, o.ID)
+				if s.Logger != nil {
+					s.Logger.Warn("safe mode order cancel failed", zap.Uint64("order_id", o.ID), zap.Error(err))
+				}
+				continue
+			}
+			cancelled = append(cancelled, o.ID)
+		}
+	}
+	return cancelled, failed
+}
+
+func (s *SafeModeService) announce(ctx context.Context, action string, res *SafeModeResult) {
+	if s.Logger != nil {
+		s.Logger.Warn("safe mode "+action,
+			zap.String("reason", res.State.Reason),
+			zap.Strings("switches", res.SwitchesChanged),
+			zap.Int("orders_cancelled", len(res.OrdersCancelled)),
+			zap.Int("orders_failed", len(res.OrdersFailed)),
+		)
+	}
+	paas.LogBestEffortCtx(ctx, "polymarket_safe_mode", "warn", map[string]any{
+		"action":           action,
+		"reason":           res.State.Reason,
+		"switches":         res.SwitchesChanged,
+		"orders_cancelled": len(res.OrdersCancelled),
+		"orders_failed":    len(res.OrdersFailed),
+	})
+	notify := s.Notify
+	if notify == nil {
+		notify = paas.BroadcastCtx
+	}
+	var message string
+	if action == "enter" {
+		message = fmt.Sprintf("[critical] polymarket safe mode entered: %d switches off, %d orders cancelled", len(res.SwitchesChanged), len(res.OrdersCancelled))
+		if len(res.OrdersFailed) > 0 {
+			message += fmt.Sprintf(", %d cancels failed", len(res.OrdersFailed))
+		}
+		if res.State.Reason != "" {
+			message += ": " + res.State.Reason
+		}
+	} else {
+		message = fmt.Sprintf("[info] polymarket safe mode resumed: %d switches back on", len(res.SwitchesChanged))
+	}
+	if err := notify(ctx, s.Config.NotifyEvent, message); err != nil && s.Logger != nil {
+		s.Logger.Warn("safe mode notify failed", zap.String("action", action), zap.Error(err))
+	}
+}
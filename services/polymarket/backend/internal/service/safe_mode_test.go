@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	gormrepository "polymarket/internal/repository/gorm"
+)
+
+type recordingCanceller struct {
+	ids []uint64
+}
+
+func (r *recordingCanceller) CancelOrder(ctx context.Context, orderID uint64) error {
+	r.ids = append(r.ids, orderID)
+	return nil
+}
+
+func TestSafeModeEnterAndResume(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.SystemSetting{}, &models.Order{}); err != nil {
+		t.Fatal(err)
+	}
+	store := gormrepository.New(conn.Gorm)
+	settings := &SystemSettingsService{Repo: store}
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	for key, on := range map[string]bool{
+		FeatureAutoExecutor:   true,
+		FeatureStrategyEngine: true,
+		FeatureCatalogSync:    true,
+		FeatureLabeler:        false,
+		FeaturePositionSync:   true,
+	} {
+		if err := settings.SetEnabled(ctx, key, on); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orders := []models.Order{
+		{PlanID: 1, TokenID: "t1", Side: "BUY", Price: decimal.RequireFromString("0.4"), SizeUSD: decimal.NewFromInt(10), Status: "submitted", CreatedAt: now, UpdatedAt: now},
+		{PlanID: 1, TokenID: "t2", Side: "BUY", Price: decimal.RequireFromString("0.4"), SizeUSD: decimal.NewFromInt(10), Status: "filled", CreatedAt: now, UpdatedAt: now},
+		{PlanID: 1, TokenID: "t3", Side: "BUY", Price: decimal.RequireFromString("0.4"), SizeUSD: decimal.NewFromInt(10), Status: OrderStatusNeedsReview, CreatedAt: now, UpdatedAt: now},
+	}
+	if err := conn.Gorm.Create(&orders).Error; err != nil {
+		t.Fatal(err)
+	}
+	if n, err := store.CountOpenOrders(ctx); err != nil || n != 2 {
+		t.Fatalf("open orders = %d, %v", n, err)
+	}
+
+	canceller := &recordingCanceller{}
+	var notified []string
+	svc := &SafeModeService{
+		Repo:     store,
+		Settings: settings,
+		Orders:   canceller,
+		Config:   config.SafeModeConfig{PauseSwitches: []string{FeatureCatalogSync, FeatureLabeler, FeaturePositionSync}},
+		Notify: func(ctx context.Context, event, message string) error {
+			notified = append(notified, message)
+			return nil
+		},
+	}
+
+	res, err := svc.Enter(ctx, "bad fills", true, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{FeatureAutoExecutor, FeatureStrategyEngine, FeatureCatalogSync}; !reflect.DeepEqual(res.SwitchesChanged, want) {
+		t.Fatalf("switches changed = %v, want %v", res.SwitchesChanged, want)
+	}
+	if want := []uint64{orders[0].ID, orders[2].ID}; !reflect.DeepEqual(canceller.ids, want) || !reflect.DeepEqual(res.OrdersCancelled, want) {
+		t.Fatalf("cancelled %v, result %v", canceller.ids, res.OrdersCancelled)
+	}
+	for _, key := range []string{FeatureAutoExecutor, FeatureStrategyEngine, FeatureCatalogSync} {
+		if settings.IsEnabled(ctx, key, true) {
+			t.Fatalf("%s still on", key)
+		}
+	}
+	if !settings.IsEnabled(ctx, FeaturePositionSync, false) {
+		t.Fatal("position sync paused")
+	}
+	state, err := svc.Status(ctx)
+	if err != nil || !state.Active || state.Reason != "bad fills" {
+		t.Fatalf("state = %+v, %v", state, err)
+	}
+
+	// A second enter keeps the switch values recorded by the first.
+	if _, err := svc.Enter(ctx, "", false, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = svc.Resume(ctx, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{FeatureAutoExecutor, FeatureCatalogSync, FeatureStrategyEngine}; !reflect.DeepEqual(res.SwitchesChanged, want) {
+		t.Fatalf("switches resumed = %v, want %v", res.SwitchesChanged, want)
+	}
+	for _, key := range []string{FeatureAutoExecutor, FeatureStrategyEngine, FeatureCatalogSync} {
+		if !settings.IsEnabled(ctx, key, false) {
+			t.Fatalf("%s not restored", key)
+		}
+	}
+	if settings.IsEnabled(ctx, FeatureLabeler, true) {
+		t.Fatal("labeler turned on though it was off before safe mode")
+	}
+	if state, _ := svc.Status(ctx); state.Active || state.ResumedAt == nil {
+		t.Fatalf("state after resume = %+v", state)
+	}
+	if len(notified) != 3 {
+		t.Fatalf("notifications = %v", notified)
+	}
+}
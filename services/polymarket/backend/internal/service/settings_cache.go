@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/repository"
+)
+
+// SettingsCache serves system_settings from an in-memory snapshot so hot
+// paths (executor mode, feature switches, broker credentials) do not query
+// the table on every call. The snapshot is reloaded once it is older than
+// RefreshInterval, so a flip made by another process takes effect within
+// that window; writes made through this process are applied immediately.
+type SettingsCache struct {
+	Repo            repository.Repository
+	Logger          *zap.Logger
+	RefreshInterval time.Duration
+
+	mu       sync.RWMutex
+	values   map[string]datatypes.JSON
+	loadedAt time.Time
+	loading  sync.Mutex
+}
+
+// Raw returns the stored value of key as written, still encrypted for
+// sensitive keys.
+func (c *SettingsCache) Raw(ctx context.Context, key string) (datatypes.JSON, bool) {
+	key = strings.TrimSpace(key)
+	if c == nil || key == "" {
+		return nil, false
+	}
+	if c.stale() {
+		c.refresh(ctx)
+	}
+	c.mu.RLock()
+	loaded := c.values != nil
+	v, ok := c.values[key]
+	c.mu.RUnlock()
+	if loaded {
+		return v, ok && len(v) > 0
+	}
+	// No snapshot yet (the first load failed): read through.
+	if c.Repo == nil {
+		return nil, false
+	}
+	row, err := c.Repo.GetSystemSettingByKey(ctx, key)
+	if err != nil || row == nil || len(row.Value) == 0 {
+		return nil, false
+	}
+	return row.Value, true
+}
+
+// Bool decodes a JSON boolean setting, returning fallback when the key is
+// missing or not a boolean.
+func (c *SettingsCache) Bool(ctx context.Context, key string, fallback bool) bool {
+	raw, ok := c.Raw(ctx, key)
+	if !ok {
+		return fallback
+	}
+	var v bool
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return fallback
+	}
+	return v
+}
+
+// String decodes a JSON string setting, decrypting sensitive keys. Missing
+// or non-string values return "".
+func (c *SettingsCache) String(ctx context.Context, key string) string {
+	raw, ok := c.Raw(ctx, key)
+	if !ok {
+		return ""
+	}
+	var v string
+	if err := json.Unmarshal(RevealSettingValue(key, raw), &v); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(v)
+}
+
+// Put applies a value this process just wrote so it is visible without
+// waiting for the next reload.
+func (c *SettingsCache) Put(key string, raw datatypes.JSON) {
+	key = strings.TrimSpace(key)
+	if c == nil || key == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		return
+	}
+	c.values[key] = raw
+}
+
+// Invalidate forces the next read to reload the snapshot.
+func (c *SettingsCache) Invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.loadedAt = time.Time{}
+	c.mu.Unlock()
+}
+
+// Run reloads the snapshot every RefreshInterval so reads stay cheap even
+// when the cache sits idle between bursts.
+func (c *SettingsCache) Run(ctx context.Context) error {
+	if c == nil || c.Repo == nil {
+		return nil
+	}
+	t := time.NewTicker(c.interval())
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			c.refresh(ctx)
+		}
+	}
+}
+
+func (c *SettingsCache) interval() time.Duration {
+	if c.RefreshInterval <= 0 {
+		return 5 * time.Second
+	}
+	return c.RefreshInterval
+}
+
+func (c *SettingsCache) stale() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.values == nil || time.Since(c.loadedAt) >= c.interval()
+}
+
+func (c *SettingsCache) refresh(ctx context.Context) {
+	if c.Repo == nil {
+		return
+	}
+	c.loading.Lock()
+	defer c.loading.Unlock()
+	// Another caller may have reloaded while we waited.
+	if !c.stale() {
+		return
+	}
+	values := make(map[string]datatypes.JSON)
+	for offset := 0; ; offset += 500 {
+		items, err := c.Repo.ListSystemSettings(ctx, repository.ListSystemSettingsParams{
+			Limit:  500,
+			Offset: offset,
+		})
+		if err != nil {
+			// Keep serving the previous snapshot until the next interval.
+			if c.Logger != nil {
+				c.Logger.Warn("settings cache reload failed", zap.Error(err))
+			}
+			c.mu.Lock()
+			if c.values != nil {
+				c.loadedAt = time.Now()
+			}
+			c.mu.Unlock()
+			return
+		}
+		for _, it := range items {
+			// Desk rows shadow shared settings only for that desk's requests;
+			// the process-wide snapshot holds the shared rows.
+			if t := strings.TrimSpace(it.Tenant); t != "" && t != "default" {
+				continue
+			}
+			values[it.Key] = it.Value
+		}
+		if len(items) < 500 {
+			break
+		}
+	}
+	c.mu.Lock()
+	c.values = values
+	c.loadedAt = time.Now()
+	c.mu.Unlock()
+}
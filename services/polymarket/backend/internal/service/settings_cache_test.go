@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type settingsRepo struct {
+	repository.Repository
+	rows  []models.SystemSetting
+	loads int
+}
+
+func (r *settingsRepo) ListSystemSettings(_ context.Context, _ repository.ListSystemSettingsParams) ([]models.SystemSetting, error) {
+	r.loads++
+	return r.rows, nil
+}
+
+func TestSettingsCache_ServesSnapshotUntilStale(t *testing.T) {
+	repo := &settingsRepo{rows: []models.SystemSetting{
+		{Key: FeatureAutoExecutor, Value: datatypes.JSON(`true`)},
+		{Key: "trading.executor_mode", Value: datatypes.JSON(`" live "`)},
+	}}
+	c := &SettingsCache{Repo: repo, RefreshInterval: time.Hour}
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if !c.Bool(ctx, FeatureAutoExecutor, false) || c.String(ctx, "trading.executor_mode") != "live" {
+			t.Fatalf("unexpected values")
+		}
+	}
+	if c.Bool(ctx, "feature.missing", true) != true || repo.loads != 1 {
+		t.Fatalf("loads=%d", repo.loads)
+	}
+
+	// A local write is visible at once; an external one after invalidation.
+	c.Put(FeatureAutoExecutor, datatypes.JSON(`false`))
+	if c.Bool(ctx, FeatureAutoExecutor, true) {
+		t.Fatalf("put not applied")
+	}
+	repo.rows = []models.SystemSetting{{Key: "trading.executor_mode", Value: datatypes.JSON(`"dry-run"`)}}
+	c.Invalidate()
+	if c.String(ctx, "trading.executor_mode") != "dry-run" || repo.loads != 2 {
+		t.Fatalf("reload not applied, loads=%d", repo.loads)
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// SettlementEnricher fills fields the settlement source left empty before a
+// settlement row is stored. Enrichers must not overwrite values already set.
+type SettlementEnricher interface {
+	Enrich(ctx context.Context, market models.Market, item *models.MarketSettlementHistory) error
+}
+
+// Provenance values for MarketSettlementHistory.InitialYesPriceSource.
+const (
+	InitialPriceSourceGamma       = "gamma"
+	InitialPriceSourceManual      = "manual"
+	InitialPriceSourcePriceCandle = "price_candle"
+	InitialPriceSourceWSTrade     = "ws_trade"
+	InitialPriceSourceWSBook      = "ws_book"
+	InitialPriceSourceRESTBook    = "rest_book"
+)
+
+// PriceObservation is one locally recorded YES price.
+type PriceObservation struct {
+	Price  float64
+	At     time.Time
+	Source string
+}
+
+// EarliestPriceObservation picks the earliest usable observation at or before
+// cutoff. Prices outside (0,1) are not valid YES probabilities and are skipped;
+// a zero cutoff disables the bound.
+func EarliestPriceObservation(obs []PriceObservation, cutoff time.Time) (PriceObservation, bool) {
+	var best PriceObservation
+	found := false
+	for _, o := range obs {
+		if o.Price <= 0 || o.Price >= 1 || o.At.IsZero() {
+			continue
+		}
+		if !cutoff.IsZero() && o.At.After(cutoff) {
+			continue
+		}
+		if !found || o.At.Before(best.At) {
+			best, found = o, true
+		}
+	}
+	return best, found
+}
+
+// InitialPriceEnricher captures the earliest YES price we observed ourselves
+// (candles, raw WS events, raw REST book snapshots) for markets whose
+// settlement source has no initial price, typically markets discovered late.
+type InitialPriceEnricher struct {
+	Repo repository.Repository
+}
+
+func (e *InitialPriceEnricher) Enrich(ctx context.Context, market models.Market, item *models.MarketSettlementHistory) error {
+	if e == nil || e.Repo == nil || item == nil || item.InitialYesPrice != nil {
+		return nil
+	}
+	tokenID, err := e.yesTokenID(ctx, market.ID)
+	if err != nil || tokenID == "" {
+		return err
+	}
+	obs, err := e.observations(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	best, ok := EarliestPriceObservation(obs, item.SettledAt)
+	if !ok {
+		return nil
+	}
+	price := decimal.NewFromFloat(best.Price)
+	at := best.At.UTC()
+	item.InitialYesPrice = &price
+	item.InitialYesPriceSource = best.Source
+	item.InitialYesPriceAt = &at
+	return nil
+}
+
+func (e *InitialPriceEnricher) yesTokenID(ctx context.Context, marketID string) (string, error) {
+	tokens, err := e.Repo.ListTokensByMarketIDs(ctx, []string{marketID})
+	if err != nil {
+		return "", err
+	}
+	for _, t := range tokens {
+		if strings.EqualFold(strings.TrimSpace(t.Outcome), "yes") {
+			return t.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// observations returns the earliest record of each source for the token.
+func (e *InitialPriceEnricher) observations(ctx context.Context, tokenID string) ([]PriceObservation, error) {
+	var out []PriceObservation
+	candle, err := e.Repo.GetEarliestPriceCandle(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if candle != nil {
+		out = append(out, PriceObservation{Price: candle.Open, At: candle.OpenTS, Source: InitialPriceSourcePriceCandle})
+	}
+	trade, err := e.Repo.GetEarliestRawWSEvent(ctx, tokenID, "last_trade_price")
+	if err != nil {
+		return nil, err
+	}
+	if trade != nil {
+		out = append(out, PriceObservation{Price: parseLastTradePrice(trade.Payload), At: trade.ReceivedAt, Source: InitialPriceSourceWSTrade})
+	}
+	book, err := e.Repo.GetEarliestRawWSEvent(ctx, tokenID, "book")
+	if err != nil {
+		return nil, err
+	}
+	if book != nil {
+		out = append(out, PriceObservation{Price: bookMidFromPayload(book.Payload), At: book.ReceivedAt, Source: InitialPriceSourceWSBook})
+	}
+	snap, err := e.Repo.GetEarliestRawRESTSnapshot(ctx, tokenID, "orderbook")
+	if err != nil {
+		return nil, err
+	}
+	if snap != nil {
+		out = append(out, PriceObservation{Price: bookMidFromPayload(snap.Payload), At: snap.FetchedAt, Source: InitialPriceSourceRESTBook})
+	}
+	return out, nil
+}
+
+// bookMidFromPayload returns the mid of a raw book, or 0 when one side is
+// empty. Levels are scanned rather than read from the top since REST and WS
+// books do not share an ordering.
+func bookMidFromPayload(raw []byte) float64 {
+	book, err := parseBookPayload(raw)
+	if err != nil {
+		return 0
+	}
+	bid, ask := 0.0, 0.0
+	for _, l := range book.Bids {
+		if l.Price > bid {
+			bid = l.Price
+		}
+	}
+	for _, l := range book.Asks {
+		if l.Price > 0 && (ask == 0 || l.Price < ask) {
+			ask = l.Price
+		}
+	}
+	if bid <= 0 || ask <= 0 {
+		return 0
+	}
+	return (bid + ask) / 2
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEarliestPriceObservation(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	obs := []PriceObservation{
+		{Price: 0.42, At: t0.Add(2 * time.Hour), Source: InitialPriceSourcePriceCandle},
+		{Price: 0, At: t0, Source: InitialPriceSourceWSTrade},
+		{Price: 0.35, At: t0.Add(time.Hour), Source: InitialPriceSourceRESTBook},
+		{Price: 0.99, At: t0.Add(-time.Hour).Add(72 * time.Hour), Source: InitialPriceSourceWSBook},
+	}
+	got, ok := EarliestPriceObservation(obs, t0.Add(48*time.Hour))
+	if !ok || got.Source != InitialPriceSourceRESTBook || got.Price != 0.35 {
+		t.Fatalf("got=%+v ok=%v", got, ok)
+	}
+	if _, ok := EarliestPriceObservation(obs[3:], t0.Add(48*time.Hour)); ok {
+		t.Fatalf("observation after settlement must be ignored")
+	}
+}
+
+func TestBookMidFromPayload(t *testing.T) {
+	// REST books list bids ascending, so the best bid is last.
+	raw := []byte(`{"bids":[{"price":"0.30","size":"10"},{"price":"0.40","size":"5"}],"asks":[{"price":"0.50","size":"3"},{"price":"0.44","size":"1"}]}`)
+	if got := bookMidFromPayload(raw); got < 0.4199 || got > 0.4201 {
+		t.Fatalf("mid=%v want 0.42", got)
+	}
+	if got := bookMidFromPayload([]byte(`{"bids":[],"asks":[{"price":"0.5","size":"1"}]}`)); got != 0 {
+		t.Fatalf("one-sided book mid=%v", got)
+	}
+}
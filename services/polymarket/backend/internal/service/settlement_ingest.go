@@ -13,6 +13,7 @@ import (
 
 	polymarketgamma "polymarket/internal/client/polymarket/gamma"
 	"polymarket/internal/config"
+	"polymarket/internal/governor"
 	"polymarket/internal/models"
 	"polymarket/internal/repository"
 )
@@ -29,6 +30,11 @@ type SettlementIngestService struct {
 	Config config.SettlementIngestConfig
 	Logger *zap.Logger
 	Flags  *SystemSettingsService
+	// Enrichers run on every new row, and on existing rows still missing an
+	// initial price, before they are stored.
+	Enrichers []SettlementEnricher
+	// Governor, when set, admits each scheduled run as a backfill job.
+	Governor *governor.Governor
 }
 
 func (s *SettlementIngestService) Run(ctx context.Context) error {
@@ -55,10 +61,13 @@ func (s *SettlementIngestService) Run(ctx context.Context) error {
 }
 
 func (s *SettlementIngestService) runOnceIfEnabled(ctx context.Context) error {
-	if s != nil && s.Flags != nil && !s.Flags.IsEnabled(ctx, FeatureSettlementIngest, false) {
+	if s == nil {
 		return nil
 	}
-	return s.RunOnce(ctx)
+	if s.Flags != nil && !s.Flags.IsEnabled(ctx, FeatureSettlementIngest, false) {
+		return nil
+	}
+	return s.Governor.Run(ctx, governor.ClassBackfill, s.RunOnce)
 }
 
 func (s *SettlementIngestService) RunOnce(ctx context.Context) error {
@@ -108,10 +117,10 @@ func (s *SettlementIngestService) RunOnce(ctx context.Context) error {
 			return nil
 		}
 		existing, _ := s.Repo.ListMarketSettlementHistoryByMarketIDs(ctx, marketIDs)
-		exists := map[string]struct{}{}
+		exists := map[string]models.MarketSettlementHistory{}
 		for _, row := range existing {
 			if strings.TrimSpace(row.MarketID) != "" {
-				exists[strings.TrimSpace(row.MarketID)] = struct{}{}
+				exists[strings.TrimSpace(row.MarketID)] = row
 			}
 		}
 
@@ -120,7 +129,10 @@ func (s *SettlementIngestService) RunOnce(ctx context.Context) error {
 			if marketID == "" {
 				continue
 			}
-			if _, ok := exists[marketID]; ok {
+			if row, ok := exists[marketID]; ok {
+				if row.InitialYesPrice == nil {
+					s.reenrich(ctx, mkt, row)
+				}
 				continue
 			}
 			raw, err := s.Gamma.GetMarketRawByID(ctx, marketID, nil)
@@ -178,6 +190,10 @@ func (s *SettlementIngestService) RunOnce(ctx context.Context) error {
 				SettledAt:       settledAt,
 				CreatedAt:       now,
 			}
+			if initialYes != nil {
+				item.InitialYesPriceSource = InitialPriceSourceGamma
+			}
+			s.enrich(ctx, mkt, item)
 			if err := s.Repo.UpsertMarketSettlementHistory(ctx, item); err != nil {
 				s.logWarn("upsert settlement history failed", err, zap.String("market_id", marketID))
 			}
@@ -186,10 +202,38 @@ func (s *SettlementIngestService) RunOnce(ctx context.Context) error {
 		if len(markets) < batch {
 			return nil
 		}
+		if err := governor.Yield(ctx); err != nil {
+			return err
+		}
 		offset += batch
 	}
 }
 
+func (s *SettlementIngestService) enrich(ctx context.Context, mkt models.Market, item *models.MarketSettlementHistory) {
+	for _, e := range s.Enrichers {
+		if err := e.Enrich(ctx, mkt, item); err != nil {
+			s.logWarn("settlement enrichment failed", err, zap.String("market_id", item.MarketID))
+		}
+	}
+}
+
+// reenrich retries enrichment of a stored row that is missing an initial
+// price and writes it back only when something was found.
+func (s *SettlementIngestService) reenrich(ctx context.Context, mkt models.Market, row models.MarketSettlementHistory) {
+	if len(s.Enrichers) == 0 {
+		return
+	}
+	s.enrich(ctx, mkt, &row)
+	if row.InitialYesPrice == nil {
+		return
+	}
+	// The upsert targets market_id; a set primary key would conflict first.
+	row.ID = 0
+	if err := s.Repo.UpsertMarketSettlementHistory(ctx, &row); err != nil {
+		s.logWarn("upsert enriched settlement history failed", err, zap.String("market_id", row.MarketID))
+	}
+}
+
 // extractBinarySettlement tries to decode a YES/NO settlement from raw Gamma market JSON.
 // This is best-effort: it returns an error if it cannot find a usable outcome.
 func extractBinarySettlement(raw []byte) (outcome string, settledAt time.Time, initialYes *decimal.Decimal, finalYes *decimal.Decimal, err error) {
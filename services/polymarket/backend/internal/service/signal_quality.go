@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/repository"
+)
+
+// signalQualityPriorWeight pulls sparse signal types towards a coin flip.
+const signalQualityPriorWeight = 5.0
+
+// SignalQuality is the rolling precision of one signal type: how many of the
+// opportunities it triggered settled with a positive realized PnL.
+type SignalQuality struct {
+	SignalType    string  `json:"signal_type"`
+	Signals       int64   `json:"signals"`
+	Opportunities int64   `json:"opportunities"`
+	Settled       int64   `json:"settled"`
+	Profitable    int64   `json:"profitable"`
+	RealizedUSD   float64 `json:"realized_usd"`
+	Precision     float64 `json:"precision"`
+	Score         float64 `json:"score"`
+	Weight        float64 `json:"weight"`
+	Status        string  `json:"status"`
+}
+
+type SignalQualityReport struct {
+	Since    time.Time       `json:"since"`
+	Computed time.Time       `json:"computed_at"`
+	Items    []SignalQuality `json:"items"`
+
+	byType map[string]SignalQuality
+}
+
+// ScoreSignalQuality turns raw counts into scores and engine weights. signals
+// is the number of signals seen per type in the same window and may be nil.
+func ScoreSignalQuality(rows []repository.SignalQualityRow, signals map[string]int64, cfg config.SignalQualityConfig, since, now time.Time) *SignalQualityReport {
+	out := &SignalQualityReport{Since: since.UTC(), Computed: now.UTC(), byType: map[string]SignalQuality{}}
+	seen := map[string]bool{}
+	for _, r := range rows {
+		typ := strings.TrimSpace(r.SignalType)
+		if typ == "" {
+			continue
+		}
+		seen[typ] = true
+		q := SignalQuality{
+			SignalType:    typ,
+			Signals:       signals[typ],
+			Opportunities: r.Opportunities,
+			Settled:       r.Settled,
+			Profitable:    r.Profitable,
+			RealizedUSD:   r.RealizedUSD,
+		}
+		if r.Settled > 0 {
+			q.Precision = float64(r.Profitable) / float64(r.Settled)
+		}
+		q.Score = (float64(r.Profitable) + 0.5*signalQualityPriorWeight) / (float64(r.Settled) + signalQualityPriorWeight)
+		q.Weight, q.Status = signalQualityWeight(q, cfg)
+		out.Items = append(out.Items, q)
+	}
+	for typ, n := range signals {
+		if seen[typ] || strings.TrimSpace(typ) == "" {
+			continue
+		}
+		out.Items = append(out.Items, SignalQuality{SignalType: typ, Signals: n, Score: 0.5, Weight: 1, Status: "insufficient_data"})
+	}
+	sort.Slice(out.Items, func(i, j int) bool { return out.Items[i].SignalType < out.Items[j].SignalType })
+	for _, q := range out.Items {
+		out.byType[q.SignalType] = q
+	}
+	return out
+}
+
+func signalQualityWeight(q SignalQuality, cfg config.SignalQualityConfig) (float64, string) {
+	minSamples := cfg.MinSamples
+	if minSamples <= 0 {
+		minSamples = 20
+	}
+	if q.Settled < int64(minSamples) {
+		return 1, "insufficient_data"
+	}
+	ignoreBelow := cfg.IgnoreBelow
+	fullAt := cfg.FullWeightAt
+	if fullAt <= ignoreBelow {
+		fullAt = ignoreBelow + 0.3
+	}
+	minWeight := cfg.MinWeight
+	if minWeight <= 0 || minWeight > 1 {
+		minWeight = 0.25
+	}
+	switch {
+	case q.Score < ignoreBelow:
+		return 0, "ignored"
+	case q.Score >= fullAt:
+		return 1, "ok"
+	}
+	return minWeight + (1-minWeight)*(q.Score-ignoreBelow)/(fullAt-ignoreBelow), "down_weighted"
+}
+
+// Get returns the quality entry for one signal type.
+func (r *SignalQualityReport) Get(signalType string) (SignalQuality, bool) {
+	if r == nil {
+		return SignalQuality{}, false
+	}
+	q, ok := r.byType[strings.TrimSpace(signalType)]
+	return q, ok
+}
+
+// SignalQualityService caches signal quality scores for the strategy engine.
+type SignalQualityService struct {
+	Repo   repository.Repository
+	Config config.SignalQualityConfig
+
+	mu     sync.Mutex
+	report *SignalQualityReport
+}
+
+func (s *SignalQualityService) Compute(ctx context.Context, since *time.Time) (*SignalQualityReport, error) {
+	if s == nil || s.Repo == nil {
+		return nil, fmt.Errorf("repo unavailable")
+	}
+	now := time.Now().UTC()
+	from := now.Add(-s.window())
+	if since != nil && !since.IsZero() {
+		from = since.UTC()
+	}
+	rows, err := s.Repo.SignalQualityStats(ctx, &from)
+	if err != nil {
+		return nil, err
+	}
+	signals, _ := s.Repo.CountSignalsByType(ctx, &from)
+	return ScoreSignalQuality(rows, signals, s.Config, from, now), nil
+}
+
+// Report returns the cached report, recomputing it once RefreshTTL has passed.
+func (s *SignalQualityService) Report(ctx context.Context) (*SignalQualityReport, error) {
+	if s == nil {
+		return nil, nil
+	}
+	ttl := s.Config.RefreshTTL
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.report != nil && time.Since(s.report.Computed) < ttl {
+		return s.report, nil
+	}
+	report, err := s.Compute(ctx, nil)
+	if err != nil {
+		if s.report != nil {
+			return s.report, nil
+		}
+		return nil, err
+	}
+	s.report = report
+	return report, nil
+}
+
+// Weight is the engine hook: 1 keeps the signal type as is, 0 ignores it and
+// values in between scale opportunity confidence. Errors and disabled scoring
+// fall back to 1.
+func (s *SignalQualityService) Weight(ctx context.Context, signalType string) float64 {
+	if s == nil || !s.Config.Enabled {
+		return 1
+	}
+	report, err := s.Report(ctx)
+	if err != nil || report == nil {
+		return 1
+	}
+	q, ok := report.Get(signalType)
+	if !ok {
+		return 1
+	}
+	return q.Weight
+}
+
+func (s *SignalQualityService) window() time.Duration {
+	if s.Config.Window > 0 {
+		return s.Config.Window
+	}
+	return 30 * 24 * time.Hour
+}
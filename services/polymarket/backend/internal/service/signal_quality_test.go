@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/repository"
+)
+
+func TestScoreSignalQuality_Weights(t *testing.T) {
+	cfg := config.SignalQualityConfig{Enabled: true, MinSamples: 10, IgnoreBelow: 0.2, FullWeightAt: 0.5, MinWeight: 0.25}
+	rows := []repository.SignalQualityRow{
+		{SignalType: "good", Opportunities: 40, Settled: 30, Profitable: 20},
+		{SignalType: "bad", Opportunities: 40, Settled: 30, Profitable: 1},
+		{SignalType: "meh", Opportunities: 40, Settled: 30, Profitable: 10},
+		{SignalType: "new", Opportunities: 3, Settled: 2, Profitable: 0},
+	}
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	report := ScoreSignalQuality(rows, map[string]int64{"good": 100, "quiet": 7}, cfg, now.Add(-time.Hour), now)
+
+	good, _ := report.Get("good")
+	if good.Weight != 1 || good.Status != "ok" || good.Signals != 100 {
+		t.Fatalf("good=%+v", good)
+	}
+	bad, _ := report.Get("bad")
+	if bad.Weight != 0 || bad.Status != "ignored" {
+		t.Fatalf("bad=%+v", bad)
+	}
+	meh, _ := report.Get("meh")
+	if meh.Status != "down_weighted" || meh.Weight <= cfg.MinWeight || meh.Weight >= 1 {
+		t.Fatalf("meh=%+v", meh)
+	}
+	fresh, _ := report.Get("new")
+	if fresh.Weight != 1 || fresh.Status != "insufficient_data" {
+		t.Fatalf("new=%+v", fresh)
+	}
+	quiet, ok := report.Get("quiet")
+	if !ok || quiet.Weight != 1 || quiet.Opportunities != 0 {
+		t.Fatalf("quiet=%+v ok=%v", quiet, ok)
+	}
+	if len(report.Items) != 5 || report.Items[0].SignalType != "bad" {
+		t.Fatalf("items=%+v", report.Items)
+	}
+}
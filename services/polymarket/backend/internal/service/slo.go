@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// Pipeline freshness SLOs.
+const (
+	SLOCatalog     = "catalog_freshness"
+	SLOBooks       = "book_freshness"
+	SLOSettlements = "settlement_latency"
+)
+
+// SLOService evaluates the data pipeline freshness SLOs, stores one sample
+// per SLO and evaluation, and alerts when an SLO burns its error budget
+// faster than a configured burn rate over both windows of an alert.
+type SLOService struct {
+	Repo   repository.Repository
+	Config config.SLOConfig
+	// Stream supplies the streamed tokens; without subscriptions the book
+	// SLO is not evaluated.
+	Stream *CLOBStreamService
+	// Flags skips the settlement SLO while settlement ingest is switched off.
+	Flags  *SystemSettingsService
+	Logger *zap.Logger
+	// Notify routes alerts to the notification dispatcher; nil broadcasts
+	// through the platform client in ctx.
+	Notify func(ctx context.Context, event, message string) error
+
+	mu      sync.Mutex
+	firing  map[string]*sloFiring
+	lastRun *time.Time
+}
+
+type sloFiring struct {
+	since      time.Time
+	notifiedAt time.Time
+}
+
+// SLOWindow is an SLO's items and burn rate over one window.
+type SLOWindow struct {
+	Window   string  `json:"window"`
+	Good     int64   `json:"good"`
+	Total    int64   `json:"total"`
+	BurnRate float64 `json:"burn_rate"`
+}
+
+// SLOAlertStatus is one burn-rate alert of an SLO.
+type SLOAlertStatus struct {
+	Severity  string     `json:"severity"`
+	Threshold float64    `json:"threshold"`
+	Long      SLOWindow  `json:"long"`
+	Short     SLOWindow  `json:"short"`
+	Firing    bool       `json:"firing"`
+	Since     *time.Time `json:"since,omitempty"`
+}
+
+// SLOStatus is an SLO's objective, latest sample and alerts.
+// BudgetRemaining is the share of the error budget left over the slow
+// alert's long window; it is negative once the budget is spent.
+type SLOStatus struct {
+	SLO             string            `json:"slo"`
+	Description     string            `json:"description"`
+	MaxAge          string            `json:"max_age"`
+	Objective       float64           `json:"objective"`
+	Evaluated       bool              `json:"evaluated"`
+	Latest          *models.SLOSample `json:"latest,omitempty"`
+	BudgetRemaining float64           `json:"budget_remaining"`
+	Alerts          []SLOAlertStatus  `json:"alerts"`
+}
+
+// BurnRate is how many times faster than allowed the bad share of total
+// items spends the error budget 1-objective; 0 without items.
+func BurnRate(good, total int64, objective float64) float64 {
+	if total <= 0 || objective >= 1 {
+		return 0
+	}
+	bad := float64(total-good) / float64(total)
+	return bad / (1 - objective)
+}
+
+// MeasureCatalogFreshness samples the sync states of scopes: a scope is good
+// when its last success is within maxAge. A scope that never succeeded is bad.
+func MeasureCatalogFreshness(states []models.SyncState, scopes []string, maxAge time.Duration, now time.Time) models.SLOSample {
+	byScope := make(map[string]models.SyncState, len(states))
+	for _, st := range states {
+		byScope[st.Scope] = st
+	}
+	out := models.SLOSample{SLO: SLOCatalog, EvaluatedAt: now}
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		out.Total++
+		st, ok := byScope[scope]
+		if !ok || st.LastSuccessAt == nil {
+			continue
+		}
+		age := now.Sub(*st.LastSuccessAt)
+		out.WorstAge = max(out.WorstAge, age.Seconds())
+		if age <= maxAge {
+			out.Good++
+		}
+	}
+	return out
+}
+
+// MeasureBookFreshness samples the latest books of tokenIDs: a book is good
+// when updated within maxAge. A token without a book is bad.
+func MeasureBookFreshness(books []models.OrderbookLatest, tokenIDs []string, maxAge time.Duration, now time.Time) models.SLOSample {
+	updated := make(map[string]time.Time, len(books))
+	for _, b := range books {
+		updated[b.TokenID] = b.UpdatedAt
+	}
+	out := models.SLOSample{SLO: SLOBooks, EvaluatedAt: now}
+	for _, id := range tokenIDs {
+		out.Total++
+		at, ok := updated[id]
+		if !ok {
+			continue
+		}
+		age := now.Sub(at)
+		out.WorstAge = max(out.WorstAge, age.Seconds())
+		if age <= maxAge {
+			out.Good++
+		}
+	}
+	return out
+}
+
+func (s *SLOService) enabled() bool {
+	return s != nil && s.Repo != nil && s.Config.Enabled
+}
+
+func (s *SLOService) targets(ctx context.Context) []string {
+	out := []string{SLOCatalog}
+	if s.Stream != nil && s.Stream.Subscriptions != nil {
+		out = append(out, SLOBooks)
+	}
+	if s.Flags == nil || s.Flags.IsEnabled(ctx, FeatureSettlementIngest, false) {
+		out = append(out, SLOSettlements)
+	}
+	return out
+}
+
+func (s *SLOService) target(slo string) config.SLOTarget {
+	switch slo {
+	case SLOBooks:
+		return s.Config.Books
+	case SLOSettlements:
+		return s.Config.Settlements
+	default:
+		return s.Config.Catalog
+	}
+}
+
+// Evaluate measures every evaluated SLO at now and stores the samples. SLOs
+// without items to check (no streamed tokens, no closed markets) store none.
+func (s *SLOService) Evaluate(ctx context.Context, now time.Time) ([]models.SLOSample, error) {
+	if !s.enabled() {
+		return nil, nil
+	}
+	now = now.UTC()
+	var out []models.SLOSample
+	for _, slo := range s.targets(ctx) {
+		item, err := s.measure(ctx, slo, now)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", slo, err)
+		}
+		if item.Total > 0 {
+			out = append(out, item)
+		}
+	}
+	if err := s.Repo.InsertSLOSamples(ctx, out); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.lastRun = &now
+	s.mu.Unlock()
+	return out, nil
+}
+
+func (s *SLOService) measure(ctx context.Context, slo string, now time.Time) (models.SLOSample, error) {
+	target := s.target(slo)
+	switch slo {
+	case SLOBooks:
+		var tokenIDs []string
+		for _, sub := range s.Stream.Subscriptions.List("") {
+			if sub.Subscribed {
+				tokenIDs = append(tokenIDs, sub.TokenID)
+			}
+		}
+		if len(tokenIDs) == 0 {
+			return models.SLOSample{SLO: slo}, nil
+		}
+		books, err := s.Repo.ListOrderbookLatestByTokenIDs(ctx, tokenIDs)
+		if err != nil {
+			return models.SLOSample{}, err
+		}
+		return MeasureBookFreshness(books, tokenIDs, target.MaxAge, now), nil
+	case SLOSettlements:
+		lookback := s.Config.SettlementLookback
+		if lookback <= target.MaxAge {
+			lookback = target.MaxAge + 24*time.Hour
+		}
+		// Only markets closed longer than MaxAge ago count: until then a
+		// missing settlement is not late yet.
+		closed, settled, err := s.Repo.CountClosedMarketSettlements(ctx, now.Add(-lookback), now.Add(-target.MaxAge))
+		if err != nil {
+			return models.SLOSample{}, err
+		}
+		return models.SLOSample{SLO: slo, Good: int(settled), Total: int(closed), EvaluatedAt: now}, nil
+	default:
+		states, err := s.Repo.ListSyncStates(ctx)
+		if err != nil {
+			return models.SLOSample{}, err
+		}
+		return MeasureCatalogFreshness(states, s.Config.CatalogScopes, target.MaxAge, now), nil
+	}
+}
+
+func sloDescription(slo string, target config.SLOTarget) string {
+	switch slo {
+	case SLOBooks:
+		return fmt.Sprintf("books of streamed tokens updated within %s", target.MaxAge)
+	case SLOSettlements:
+		return fmt.Sprintf("closed markets settled within %s of closing", target.MaxAge)
+	default:
+		return fmt.Sprintf("catalog sync scopes succeeded within %s", target.MaxAge)
+	}
+}
+
+func (s *SLOService) alerts() []config.SLOBurnAlert {
+	var out []config.SLOBurnAlert
+	for _, a := range []config.SLOBurnAlert{s.Config.Fast, s.Config.Slow} {
+		if a.LongWindow > 0 && a.BurnRate > 0 {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func (s *SLOService) window(ctx context.Context, slo string, objective float64, d time.Duration, now time.Time) (SLOWindow, error) {
+	good, total, err := s.Repo.SumSLOSamples(ctx, slo, now.Add(-d))
+	if err != nil {
+		return SLOWindow{}, err
+	}
+	return SLOWindow{Window: d.String(), Good: good, Total: total, BurnRate: BurnRate(good, total, objective)}, nil
+}
+
+// Status reports every SLO with its burn over each alert's windows as of
+// now. Firing reflects the windows, Since when the service first saw it.
+func (s *SLOService) Status(ctx context.Context, now time.Time) ([]SLOStatus, error) {
+	if s == nil || s.Repo == nil {
+		return nil, nil
+	}
+	now = now.UTC()
+	evaluated := map[string]bool{}
+	if s.Config.Enabled {
+		for _, slo := range s.targets(ctx) {
+			evaluated[slo] = true
+		}
+	}
+	var out []SLOStatus
+	for _, slo := range []string{SLOCatalog, SLOBooks, SLOSettlements} {
+		target := s.target(slo)
+		st := SLOStatus{
+			SLO:             slo,
+			Description:     sloDescription(slo, target),
+			MaxAge:          target.MaxAge.String(),
+			Objective:       target.Objective,
+			Evaluated:       evaluated[slo],
+			BudgetRemaining: 1,
+			Alerts:          []SLOAlertStatus{},
+		}
+		name := slo
+		latest, err := s.Repo.ListSLOSamples(ctx, repository.ListSLOSamplesParams{Limit: 1, SLO: &name})
+		if err != nil {
+			return nil, err
+		}
+		if len(latest) > 0 {
+			st.Latest = &latest[0]
+		}
+		for _, a := range s.alerts() {
+			long, err := s.window(ctx, slo, target.Objective, a.LongWindow, now)
+			if err != nil {
+				return nil, err
+			}
+			short := long
+			if a.ShortWindow > 0 {
+				if short, err = s.window(ctx, slo, target.Objective, a.ShortWindow, now); err != nil {
+					return nil, err
+				}
+			}
+			alert := SLOAlertStatus{
+				Severity:  a.Severity,
+				Threshold: a.BurnRate,
+				Long:      long,
+				Short:     short,
+				Firing:    long.BurnRate >= a.BurnRate && short.BurnRate >= a.BurnRate,
+			}
+			if alert.Firing {
+				s.mu.Lock()
+				if f := s.firing[slo+"/"+a.Severity]; f != nil {
+					since := f.since
+					alert.Since = &since
+				}
+				s.mu.Unlock()
+			}
+			st.Alerts = append(st.Alerts, alert)
+		}
+		if s.Config.Slow.LongWindow > 0 {
+			w, err := s.window(ctx, slo, target.Objective, s.Config.Slow.LongWindow, now)
+			if err != nil {
+				return nil, err
+			}
+			st.BudgetRemaining = 1 - w.BurnRate
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// LastRun is when Evaluate last stored samples.
+func (s *SLOService) LastRun() *time.Time {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun
+}
+
+// RunOnce evaluates the SLOs and notifies alerts that started firing, are
+// still firing after the cooldown, or resolved.
+func (s *SLOService) RunOnce(ctx context.Context, now time.Time) ([]SLOStatus, error) {
+	if !s.enabled() {
+		return nil, nil
+	}
+	if _, err := s.Evaluate(ctx, now); err != nil {
+		return nil, err
+	}
+	statuses, err := s.Status(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	s.alert(ctx, statuses, now.UTC())
+	return statuses, nil
+}
+
+func (s *SLOService) alert(ctx context.Context, statuses []SLOStatus, now time.Time) {
+	type notice struct {
+		status SLOStatus
+		alert  SLOAlertStatus
+		state  string
+	}
+	var notices []notice
+	s.mu.Lock()
+	if s.firing == nil {
+		s.firing = map[string]*sloFiring{}
+	}
+	for _, st := range statuses {
+		for _, a := range st.Alerts {
+			key := st.SLO + "/" + a.Severity
+			f := s.firing[key]
+			switch {
+			case a.Firing && f == nil:
+				s.firing[key] = &sloFiring{since: now, notifiedAt: now}
+				notices = append(notices, notice{st, a, "firing"})
+			case a.Firing && s.Config.Cooldown > 0 && now.Sub(f.notifiedAt) >= s.Config.Cooldown:
+				f.notifiedAt = now
+				notices = append(notices, notice{st, a, "firing"})
+			case !a.Firing && f != nil:
+				delete(s.firing, key)
+				notices = append(notices, notice{st, a, "resolved"})
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	for _, n := range notices {
+		level := "warn"
+		if n.state == "resolved" {
+			level = "info"
+		} else if n.alert.Severity == "critical" {
+			level = "error"
+		}
+		message := fmt.Sprintf("[%s] polymarket SLO %s %s: burn %.1fx over %s, %.1fx over %s (threshold %.1fx; %s, objective %.2f%%)",
+			n.alert.Severity, n.status.SLO, n.state,
+			n.alert.Long.BurnRate, n.alert.Long.Window, n.alert.Short.BurnRate, n.alert.Short.Window,
+			n.alert.Threshold, n.status.Description, n.status.Objective*100)
+		if s.Logger != nil {
+			s.Logger.Warn("slo alert "+n.state,
+				zap.String("slo", n.status.SLO),
+				zap.String("severity", n.alert.Severity),
+				zap.Float64("long_burn_rate", n.alert.Long.BurnRate),
+				zap.Float64("short_burn_rate", n.alert.Short.BurnRate),
+			)
+		}
+		paas.LogBestEffortCtx(ctx, "polymarket_slo_alert_"+n.state, level, map[string]any{
+			"slo":             n.status.SLO,
+			"severity":        n.alert.Severity,
+			"threshold":       n.alert.Threshold,
+			"long_window":     n.alert.Long.Window,
+			"long_burn_rate":  n.alert.Long.BurnRate,
+			"short_window":    n.alert.Short.Window,
+			"short_burn_rate": n.alert.Short.BurnRate,
+		})
+		notify := s.Notify
+		if notify == nil {
+			notify = paas.BroadcastCtx
+		}
+		if err := notify(ctx, s.Config.NotifyEvent, message); err != nil && s.Logger != nil {
+			s.Logger.Warn("slo alert notify failed", zap.String("slo", n.status.SLO), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestBurnRate(t *testing.T) {
+	// 2% bad against a 1% budget burns twice as fast as allowed.
+	if got := BurnRate(98, 100, 0.99); math.Abs(got-2) > 1e-9 {
+		t.Fatalf("burn=%v", got)
+	}
+	if got := BurnRate(0, 0, 0.99); got != 0 {
+		t.Fatalf("burn without items=%v", got)
+	}
+}
+
+func TestMeasureFreshness(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	recent, old := now.Add(-5*time.Minute), now.Add(-2*time.Hour)
+	states := []models.SyncState{
+		{Scope: "events", LastSuccessAt: &recent},
+		{Scope: "markets", LastSuccessAt: &old},
+		{Scope: "tags"},
+	}
+	got := MeasureCatalogFreshness(states, []string{"events", "markets", "series"}, 30*time.Minute, now)
+	if got.Good != 1 || got.Total != 3 || got.WorstAge != 7200 {
+		t.Fatalf("catalog=%+v", got)
+	}
+
+	books := []models.OrderbookLatest{
+		{TokenID: "t1", UpdatedAt: now.Add(-10 * time.Second)},
+		{TokenID: "t2", UpdatedAt: now.Add(-90 * time.Second)},
+	}
+	got = MeasureBookFreshness(books, []string{"t1", "t2", "t3"}, time.Minute, now)
+	if got.Good != 1 || got.Total != 3 || got.SLO != SLOBooks {
+		t.Fatalf("books=%+v", got)
+	}
+}
+
+type sloRepo struct {
+	repository.Repository
+	states  []models.SyncState
+	samples []models.SLOSample
+}
+
+func (r *sloRepo) ListSyncStates(ctx context.Context) ([]models.SyncState, error) {
+	return r.states, nil
+}
+
+func (r *sloRepo) InsertSLOSamples(ctx context.Context, items []models.SLOSample) error {
+	r.samples = append(r.samples, items...)
+	return nil
+}
+
+func (r *sloRepo) SumSLOSamples(ctx context.Context, slo string, since time.Time) (int64, int64, error) {
+	var good, total int64
+	for _, s := range r.samples {
+		if s.SLO == slo && !s.EvaluatedAt.Before(since) {
+			good += int64(s.Good)
+			total += int64(s.Total)
+		}
+	}
+	return good, total, nil
+}
+
+func (r *sloRepo) ListSLOSamples(ctx context.Context, params repository.ListSLOSamplesParams) ([]models.SLOSample, error) {
+	for i := len(r.samples) - 1; i >= 0; i-- {
+		if params.SLO == nil || r.samples[i].SLO == *params.SLO {
+			return []models.SLOSample{r.samples[i]}, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestSLOServiceAlerts(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	stale := now.Add(-3 * time.Hour)
+	repo := &sloRepo{states: []models.SyncState{{Scope: "markets", LastSuccessAt: &stale}}}
+	var sent []string
+	s := &SLOService{
+		Repo: repo,
+		Config: config.SLOConfig{
+			Enabled:       true,
+			Catalog:       config.SLOTarget{MaxAge: 30 * time.Minute, Objective: 0.99},
+			CatalogScopes: []string{"markets"},
+			Fast:          config.SLOBurnAlert{Severity: "critical", LongWindow: time.Hour, ShortWindow: 5 * time.Minute, BurnRate: 14.4},
+			Cooldown:      time.Hour,
+			NotifyEvent:   "polymarket.slo_burn",
+		},
+		// Settlement ingest off: only the catalog SLO is evaluated.
+		Flags: &SystemSettingsService{},
+		Notify: func(ctx context.Context, event, message string) error {
+			sent = append(sent, event+" "+message)
+			return nil
+		},
+	}
+	ctx := context.Background()
+
+	statuses, err := s.RunOnce(ctx, now)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(statuses) != 3 || !statuses[0].Evaluated || statuses[1].Evaluated || !statuses[0].Alerts[0].Firing {
+		t.Fatalf("statuses=%+v", statuses)
+	}
+	if len(sent) != 1 || !strings.Contains(sent[0], "polymarket.slo_burn [critical] polymarket SLO catalog_freshness firing") {
+		t.Fatalf("sent=%v", sent)
+	}
+
+	// Still firing within the cooldown: no repeat.
+	if _, err := s.RunOnce(ctx, now.Add(time.Minute)); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("repeated within cooldown: %v", sent)
+	}
+
+	// Fresh again: the short window recovers first and the alert resolves.
+	fresh := now.Add(10 * time.Minute)
+	repo.states[0].LastSuccessAt = &fresh
+	for i := 2; i <= 12; i++ {
+		if _, err := s.RunOnce(ctx, now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	}
+	if len(sent) != 2 || !strings.Contains(sent[1], "resolved") {
+		t.Fatalf("sent=%v", sent)
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+	"polymarket/internal/tradingday"
+)
+
+// Budget caps reported in BudgetUsage.Exceeded and StrategyBudget.PauseReason.
+const (
+	BudgetDailyTurnover  = "daily_turnover"
+	BudgetWeeklyTurnover = "weekly_turnover"
+	BudgetDailyFees      = "daily_fees"
+	BudgetWeeklyFees     = "weekly_fees"
+)
+
+// StrategyBudgetService accounts a strategy's traded notional and fees
+// against its budget and pauses auto execution while the budget is spent.
+type StrategyBudgetService struct {
+	Repo   repository.Repository
+	Logger *zap.Logger
+	// Calendar sets the day and week boundaries; nil is UTC midnight.
+	Calendar *tradingday.Calendar
+}
+
+// BudgetUsage is a strategy's consumption in the current day and week.
+type BudgetUsage struct {
+	Strategy  string                      `json:"strategy"`
+	Budget    *models.StrategyBudget      `json:"budget"`
+	DayStart  time.Time                   `json:"day_start"`
+	WeekStart time.Time                   `json:"week_start"`
+	Day       repository.StrategyTurnover `json:"day"`
+	Week      repository.StrategyTurnover `json:"week"`
+	Exceeded  []string                    `json:"exceeded"`
+	Paused    bool                        `json:"paused"`
+}
+
+// weekStart is the start of the trading week (from Monday) containing now.
+func weekStart(cal *tradingday.Calendar, now time.Time) time.Time {
+	date := cal.Date(now)
+	back := (int(date.Weekday()) + 6) % 7
+	return cal.DateStart(date.AddDate(0, 0, -back))
+}
+
+// exceededBudgets lists the caps of b that day and week have reached.
+func exceededBudgets(b models.StrategyBudget, day, week repository.StrategyTurnover) []string {
+	out := []string{}
+	reached := func(used, limit decimal.Decimal) bool {
+		return limit.IsPositive() && used.GreaterThanOrEqual(limit)
+	}
+	if reached(day.NotionalUSD, b.DailyTurnoverUSD) {
+		out = append(out, BudgetDailyTurnover)
+	}
+	if reached(week.NotionalUSD, b.WeeklyTurnoverUSD) {
+		out = append(out, BudgetWeeklyTurnover)
+	}
+	if reached(day.FeesUSD, b.DailyFeesUSD) {
+		out = append(out, BudgetDailyFees)
+	}
+	if reached(week.FeesUSD, b.WeeklyFeesUSD) {
+		out = append(out, BudgetWeeklyFees)
+	}
+	return out
+}
+
+// Usage reports the strategy's consumption; Budget is nil when none is set.
+func (s *StrategyBudgetService) Usage(ctx context.Context, strategyName string, now time.Time) (*BudgetUsage, error) {
+	name := strings.TrimSpace(strategyName)
+	budget, err := s.Repo.GetStrategyBudget(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	out := &BudgetUsage{
+		Strategy:  name,
+		Budget:    budget,
+		DayStart:  s.Calendar.Start(now),
+		WeekStart: weekStart(s.Calendar, now),
+		Exceeded:  []string{},
+	}
+	if out.Day, err = s.Repo.StrategyTurnover(ctx, name, out.DayStart); err != nil {
+		return nil, err
+	}
+	if out.Week, err = s.Repo.StrategyTurnover(ctx, name, out.WeekStart); err != nil {
+		return nil, err
+	}
+	if budget != nil {
+		out.Exceeded = exceededBudgets(*budget, out.Day, out.Week)
+		out.Paused = budget.PausedAt != nil
+	}
+	return out, nil
+}
+
+// Check reports whether the strategy must not trade. Crossing into or out
+// of an exceeded budget updates the pause state and notifies.
+func (s *StrategyBudgetService) Check(ctx context.Context, strategyName string, now time.Time) (bool, error) {
+	if s == nil || s.Repo == nil {
+		return false, nil
+	}
+	usage, err := s.Usage(ctx, strategyName, now)
+	if err != nil || usage.Budget == nil {
+		return false, err
+	}
+	budget := usage.Budget
+	exceeded := len(usage.Exceeded) > 0
+	reason := strings.Join(usage.Exceeded, ",")
+	switch {
+	case exceeded && (budget.PausedAt == nil || budget.PauseReason != reason):
+		if budget.PausedAt == nil {
+			budget.PausedAt = &now
+		}
+		budget.PauseReason = reason
+		if err := s.Repo.SaveStrategyBudget(ctx, budget); err != nil {
+			return true, err
+		}
+		s.notify(ctx, "polymarket_strategy_budget_exceeded", "warn", usage)
+	case !exceeded && budget.PausedAt != nil:
+		budget.PausedAt = nil
+		budget.PauseReason = ""
+		if err := s.Repo.SaveStrategyBudget(ctx, budget); err != nil {
+			return false, err
+		}
+		s.notify(ctx, "polymarket_strategy_budget_resumed", "info", usage)
+	}
+	return exceeded, nil
+}
+
+func (s *StrategyBudgetService) notify(ctx context.Context, action, level string, usage *BudgetUsage) {
+	if s.Logger != nil {
+		s.Logger.Warn("strategy budget "+strings.TrimPrefix(action, "polymarket_strategy_budget_"),
+			zap.String("strategy", usage.Strategy),
+			zap.Strings("exceeded", usage.Exceeded),
+			zap.String("day_turnover_usd", usage.Day.NotionalUSD.StringFixed(2)),
+			zap.String("week_turnover_usd", usage.Week.NotionalUSD.StringFixed(2)),
+		)
+	}
+	paas.LogBestEffortCtx(ctx, action, level, map[string]any{
+		"strategy":          usage.Strategy,
+		"exceeded":          usage.Exceeded,
+		"day_turnover_usd":  usage.Day.NotionalUSD.StringFixed(2),
+		"week_turnover_usd": usage.Week.NotionalUSD.StringFixed(2),
+		"day_fees_usd":      usage.Day.FeesUSD.StringFixed(2),
+		"week_fees_usd":     usage.Week.FeesUSD.StringFixed(2),
+	})
+}
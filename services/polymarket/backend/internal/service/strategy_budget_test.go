@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestExceededBudgets(t *testing.T) {
+	b := models.StrategyBudget{
+		DailyTurnoverUSD: decimal.NewFromInt(1000),
+		WeeklyFeesUSD:    decimal.NewFromInt(20),
+	}
+	day := repository.StrategyTurnover{NotionalUSD: decimal.NewFromInt(999), FeesUSD: decimal.NewFromInt(5)}
+	week := repository.StrategyTurnover{NotionalUSD: decimal.NewFromInt(50000), FeesUSD: decimal.NewFromInt(20)}
+	got := exceededBudgets(b, day, week)
+	// Weekly turnover is uncapped; weekly fees reached their cap exactly.
+	if len(got) != 1 || got[0] != BudgetWeeklyFees {
+		t.Fatalf("exceeded=%v", got)
+	}
+	day.NotionalUSD = decimal.NewFromInt(1000)
+	if got := exceededBudgets(b, day, week); len(got) != 2 || got[0] != BudgetDailyTurnover {
+		t.Fatalf("exceeded=%v", got)
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	// Wednesday 2024-05-15 -> Monday 2024-05-13 at UTC midnight.
+	got := weekStart(nil, time.Date(2024, 5, 15, 9, 30, 0, 0, time.UTC))
+	if want := time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("week start=%v want %v", got, want)
+	}
+	// Sunday belongs to the week that started the Monday before.
+	got = weekStart(nil, time.Date(2024, 5, 19, 23, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("week start=%v want %v", got, want)
+	}
+}
@@ -0,0 +1,465 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+const (
+	// StrategyBundleFormat identifies version 1 of the bundle layout.
+	StrategyBundleFormat = "polymarket.strategy-bundle/v1"
+
+	strategyBundleKeyEnv = "PM_STRATEGY_BUNDLE_SIGNING_KEY"
+)
+
+// Params keys that carry a strategy's label requirements and market universe.
+// Bundles lift them out of params into their own fields.
+const (
+	bundleRequiredLabelsParam = "required_labels"
+	bundleUniverseParam       = "universe"
+)
+
+var (
+	ErrBundleUnsigned     = errors.New("bundle is not signed")
+	ErrBundleBadSignature = errors.New("bundle signature does not verify")
+	ErrBundleUntrustedKey = errors.New("bundle signed by an untrusted key")
+	ErrBundleInvalid      = errors.New("invalid bundle")
+)
+
+// StrategyBundle is a portable set of strategy definitions that can be
+// exported from one deployment and imported into another.
+type StrategyBundle struct {
+	Format     string           `json:"format"`
+	ExportedAt time.Time        `json:"exported_at"`
+	Strategies []BundleStrategy `json:"strategies"`
+	Signature  *BundleSignature `json:"signature,omitempty"`
+}
+
+// BundleStrategy is one strategy's definition. Tenant, enablement, launch
+// stage and stats are deployment state and are not carried.
+type BundleStrategy struct {
+	Name            string               `json:"name"`
+	DisplayName     string               `json:"display_name"`
+	Description     string               `json:"description,omitempty"`
+	Category        string               `json:"category"`
+	Priority        int                  `json:"priority"`
+	Params          json.RawMessage      `json:"params"`
+	RequiredSignals []string             `json:"required_signals,omitempty"`
+	RequiredLabels  []string             `json:"required_labels,omitempty"`
+	Universe        json.RawMessage      `json:"universe,omitempty"`
+	ExecutionRule   *BundleExecutionRule `json:"execution_rule,omitempty"`
+}
+
+type BundleExecutionRule struct {
+	AutoExecute    bool            `json:"auto_execute"`
+	MinConfidence  float64         `json:"min_confidence"`
+	MinEdgePct     decimal.Decimal `json:"min_edge_pct"`
+	StopLossPct    decimal.Decimal `json:"stop_loss_pct"`
+	TakeProfitPct  decimal.Decimal `json:"take_profit_pct"`
+	MaxHoldHours   int             `json:"max_hold_hours"`
+	MaxDailyTrades int             `json:"max_daily_trades"`
+	Condition      string          `json:"condition,omitempty"`
+}
+
+// BundleSignature is an ed25519 signature over the canonical JSON of the
+// bundle without its signature.
+type BundleSignature struct {
+	Alg       string `json:"alg"`
+	PublicKey string `json:"public_key"`
+	Value     string `json:"value"`
+}
+
+type BundleImportOptions struct {
+	DryRun    bool
+	Overwrite bool
+}
+
+type BundleImportResult struct {
+	Name   string   `json:"name"`
+	Action string   `json:"action"`
+	Notes  []string `json:"notes,omitempty"`
+}
+
+type BundleImportReport struct {
+	Signed   bool                 `json:"signed"`
+	SignedBy string               `json:"signed_by,omitempty"`
+	DryRun   bool                 `json:"dry_run"`
+	Results  []BundleImportResult `json:"results"`
+}
+
+// StrategyBundleService exports and imports strategy bundles. Imported
+// strategies arrive disabled at NewStrategyStage, and a bundle never turns
+// auto-execution on.
+type StrategyBundleService struct {
+	Repo             repository.Repository
+	TrustedKeys      []string
+	RequireSignature bool
+	NewStrategyStage string
+}
+
+// Export bundles the named strategies, or all strategies when names is empty.
+// The bundle is signed when PM_STRATEGY_BUNDLE_SIGNING_KEY is set.
+func (s *StrategyBundleService) Export(ctx context.Context, names []string) (*StrategyBundle, error) {
+	if s == nil || s.Repo == nil {
+		return nil, errors.New("repo unavailable")
+	}
+	var items []models.Strategy
+	if len(names) == 0 {
+		all, err := s.Repo.ListStrategies(ctx)
+		if err != nil {
+			return nil, err
+		}
+		items = all
+	} else {
+		for _, name := range names {
+			item, err := s.Repo.GetStrategyByName(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			if item == nil {
+				return nil, fmt.Errorf("%w: strategy not found: %s", ErrBundleInvalid, name)
+			}
+			items = append(items, *item)
+		}
+	}
+
+	bundle := &StrategyBundle{Format: StrategyBundleFormat, ExportedAt: time.Now().UTC(), Strategies: []BundleStrategy{}}
+	for _, item := range items {
+		entry, err := bundleStrategyFrom(item)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := s.Repo.GetExecutionRuleByStrategyName(ctx, item.Name)
+		if err != nil {
+			return nil, err
+		}
+		if rule != nil {
+			entry.ExecutionRule = &BundleExecutionRule{
+				AutoExecute:    rule.AutoExecute,
+				MinConfidence:  rule.MinConfidence,
+				MinEdgePct:     rule.MinEdgePct,
+				StopLossPct:    rule.StopLossPct,
+				TakeProfitPct:  rule.TakeProfitPct,
+				MaxHoldHours:   rule.MaxHoldHours,
+				MaxDailyTrades: rule.MaxDailyTrades,
+				Condition:      rule.Condition,
+			}
+		}
+		bundle.Strategies = append(bundle.Strategies, entry)
+	}
+	if key := loadBundleSigningKey(); key != nil {
+		if err := SignStrategyBundle(bundle, key); err != nil {
+			return nil, err
+		}
+	}
+	return bundle, nil
+}
+
+// Import verifies the bundle and writes its strategies and execution rules.
+// Existing strategies are skipped unless opts.Overwrite is set; overwriting
+// keeps their tenant, enablement and launch stage.
+func (s *StrategyBundleService) Import(ctx context.Context, bundle *StrategyBundle, opts BundleImportOptions) (*BundleImportReport, error) {
+	if s == nil || s.Repo == nil {
+		return nil, errors.New("repo unavailable")
+	}
+	if bundle == nil {
+		return nil, fmt.Errorf("%w: bundle required", ErrBundleInvalid)
+	}
+	if bundle.Format != StrategyBundleFormat {
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrBundleInvalid, bundle.Format)
+	}
+	report := &BundleImportReport{DryRun: opts.DryRun, Results: []BundleImportResult{}}
+	if bundle.Signature != nil {
+		if err := VerifyStrategyBundle(bundle, s.trustedKeys()); err != nil {
+			return nil, err
+		}
+		report.Signed = true
+		report.SignedBy = bundle.Signature.PublicKey
+	} else if s.RequireSignature {
+		return nil, ErrBundleUnsigned
+	}
+
+	seen := map[string]struct{}{}
+	for _, entry := range bundle.Strategies {
+		name := strings.TrimSpace(entry.Name)
+		if name == "" || len(name) > 50 {
+			return nil, fmt.Errorf("%w: strategy name %q", ErrBundleInvalid, entry.Name)
+		}
+		if _, dup := seen[name]; dup {
+			return nil, fmt.Errorf("%w: duplicate strategy %q", ErrBundleInvalid, name)
+		}
+		seen[name] = struct{}{}
+		if _, err := entry.params(); err != nil {
+			return nil, fmt.Errorf("%w: strategy %s: %v", ErrBundleInvalid, name, err)
+		}
+		if r := entry.ExecutionRule; r != nil && strings.TrimSpace(r.Condition) != "" {
+			if _, err := CompileOpportunityCondition(r.Condition); err != nil {
+				return nil, fmt.Errorf("%w: strategy %s: execution rule condition: %v", ErrBundleInvalid, name, err)
+			}
+		}
+	}
+
+	for _, entry := range bundle.Strategies {
+		name := strings.TrimSpace(entry.Name)
+		result := BundleImportResult{Name: name}
+		existing, err := s.Repo.GetStrategyByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case existing != nil && !opts.Overwrite:
+			result.Action = "skipped"
+			result.Notes = append(result.Notes, "strategy exists; pass overwrite to replace its definition")
+			report.Results = append(report.Results, result)
+			continue
+		case existing != nil:
+			result.Action = "updated"
+		default:
+			result.Action = "created"
+		}
+		if entry.ExecutionRule != nil && entry.ExecutionRule.AutoExecute {
+			result.Notes = append(result.Notes, "auto_execute not imported")
+		}
+		if opts.DryRun {
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		item, err := s.strategyFrom(entry, existing)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.Repo.UpsertStrategy(ctx, item); err != nil {
+			return nil, err
+		}
+		if r := entry.ExecutionRule; r != nil {
+			// An existing rule keeps its own auto-execute switch.
+			cur, err := s.Repo.GetExecutionRuleByStrategyName(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			now := time.Now().UTC()
+			if err := s.Repo.UpsertExecutionRule(ctx, &models.ExecutionRule{
+				StrategyName:   name,
+				AutoExecute:    cur != nil && cur.AutoExecute,
+				MinConfidence:  r.MinConfidence,
+				MinEdgePct:     r.MinEdgePct,
+				StopLossPct:    r.StopLossPct,
+				TakeProfitPct:  r.TakeProfitPct,
+				MaxHoldHours:   r.MaxHoldHours,
+				MaxDailyTrades: r.MaxDailyTrades,
+				Condition:      strings.TrimSpace(r.Condition),
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+func (s *StrategyBundleService) strategyFrom(entry BundleStrategy, existing *models.Strategy) (*models.Strategy, error) {
+	params, err := entry.params()
+	if err != nil {
+		return nil, err
+	}
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	signals, err := json.Marshal(entry.RequiredSignals)
+	if err != nil {
+		return nil, err
+	}
+	if entry.RequiredSignals == nil {
+		signals = []byte("[]")
+	}
+	item := &models.Strategy{
+		Name:            strings.TrimSpace(entry.Name),
+		DisplayName:     entry.DisplayName,
+		Description:     entry.Description,
+		Category:        entry.Category,
+		Priority:        entry.Priority,
+		Params:          rawParams,
+		RequiredSignals: signals,
+		LaunchStage:     s.NewStrategyStage,
+	}
+	if item.DisplayName == "" {
+		item.DisplayName = item.Name
+	}
+	if !models.ValidLaunchStage(item.LaunchStage) {
+		item.LaunchStage = models.LaunchStageDark
+	}
+	if existing != nil {
+		item.Enabled = existing.Enabled
+		item.Stats = existing.Stats
+	}
+	return item, nil
+}
+
+func (s *StrategyBundleService) trustedKeys() []string {
+	keys := append([]string{}, s.TrustedKeys...)
+	if key := loadBundleSigningKey(); key != nil {
+		keys = append(keys, base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)))
+	}
+	return keys
+}
+
+// params merges required labels and universe back into the strategy params.
+func (e BundleStrategy) params() (map[string]any, error) {
+	params := map[string]any{}
+	if len(bytes.TrimSpace(e.Params)) > 0 && !bytes.Equal(bytes.TrimSpace(e.Params), []byte("null")) {
+		if err := json.Unmarshal(e.Params, &params); err != nil {
+			return nil, errors.New("params must be a JSON object")
+		}
+	}
+	if len(e.RequiredLabels) > 0 {
+		params[bundleRequiredLabelsParam] = e.RequiredLabels
+	}
+	if len(bytes.TrimSpace(e.Universe)) > 0 {
+		var universe map[string]any
+		if err := json.Unmarshal(e.Universe, &universe); err != nil {
+			return nil, errors.New("universe must be a JSON object")
+		}
+		params[bundleUniverseParam] = universe
+	}
+	return params, nil
+}
+
+func bundleStrategyFrom(item models.Strategy) (BundleStrategy, error) {
+	entry := BundleStrategy{
+		Name:        item.Name,
+		DisplayName: item.DisplayName,
+		Description: item.Description,
+		Category:    item.Category,
+		Priority:    item.Priority,
+	}
+	params := map[string]json.RawMessage{}
+	if len(item.Params) > 0 {
+		if err := json.Unmarshal(item.Params, &params); err != nil {
+			return entry, fmt.Errorf("strategy %s: params: %w", item.Name, err)
+		}
+	}
+	if raw, ok := params[bundleRequiredLabelsParam]; ok {
+		_ = json.Unmarshal(raw, &entry.RequiredLabels)
+		delete(params, bundleRequiredLabelsParam)
+	}
+	if raw, ok := params[bundleUniverseParam]; ok {
+		entry.Universe = raw
+		delete(params, bundleUniverseParam)
+	}
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return entry, err
+	}
+	entry.Params = raw
+	if len(item.RequiredSignals) > 0 {
+		_ = json.Unmarshal(item.RequiredSignals, &entry.RequiredSignals)
+	}
+	return entry, nil
+}
+
+// SignStrategyBundle sets bundle.Signature using key.
+func SignStrategyBundle(bundle *StrategyBundle, key ed25519.PrivateKey) error {
+	payload, err := canonicalBundle(bundle)
+	if err != nil {
+		return err
+	}
+	bundle.Signature = &BundleSignature{
+		Alg:       "ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Value:     base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+	}
+	return nil
+}
+
+// VerifyStrategyBundle checks that the bundle is signed by one of trusted
+// (base64 ed25519 public keys) and unchanged since.
+func VerifyStrategyBundle(bundle *StrategyBundle, trusted []string) error {
+	sig := bundle.Signature
+	if sig == nil {
+		return ErrBundleUnsigned
+	}
+	if sig.Alg != "ed25519" {
+		return fmt.Errorf("%w: unsupported alg %q", ErrBundleBadSignature, sig.Alg)
+	}
+	trustedKey := false
+	for _, k := range trusted {
+		if strings.TrimSpace(k) == sig.PublicKey {
+			trustedKey = true
+			break
+		}
+	}
+	if !trustedKey {
+		return ErrBundleUntrustedKey
+	}
+	pub, err := base64.StdEncoding.DecodeString(sig.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return ErrBundleBadSignature
+	}
+	value, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return ErrBundleBadSignature
+	}
+	payload, err := canonicalBundle(bundle)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), payload, value) {
+		return ErrBundleBadSignature
+	}
+	return nil
+}
+
+// canonicalBundle is the signed form: the bundle without its signature,
+// re-encoded through generic values so object keys are sorted and
+// whitespace inside params does not matter.
+func canonicalBundle(bundle *StrategyBundle) ([]byte, error) {
+	unsigned := *bundle
+	unsigned.Signature = nil
+	raw, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
+
+// loadBundleSigningKey reads a base64 ed25519 seed or private key.
+func loadBundleSigningKey() ed25519.PrivateKey {
+	raw := strings.TrimSpace(os.Getenv(strategyBundleKeyEnv))
+	if raw == "" {
+		return nil
+	}
+	b, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return nil
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b)
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type bundleRepo struct {
+	repository.Repository
+	strategies map[string]*models.Strategy
+	rules      map[string]*models.ExecutionRule
+}
+
+func (r *bundleRepo) ListStrategies(_ context.Context) ([]models.Strategy, error) {
+	out := []models.Strategy{}
+	for _, s := range r.strategies {
+		out = append(out, *s)
+	}
+	return out, nil
+}
+
+func (r *bundleRepo) GetStrategyByName(_ context.Context, name string) (*models.Strategy, error) {
+	return r.strategies[name], nil
+}
+
+func (r *bundleRepo) UpsertStrategy(_ context.Context, item *models.Strategy) error {
+	cp := *item
+	r.strategies[item.Name] = &cp
+	return nil
+}
+
+func (r *bundleRepo) GetExecutionRuleByStrategyName(_ context.Context, name string) (*models.ExecutionRule, error) {
+	return r.rules[name], nil
+}
+
+func (r *bundleRepo) UpsertExecutionRule(_ context.Context, item *models.ExecutionRule) error {
+	cp := *item
+	r.rules[item.StrategyName] = &cp
+	return nil
+}
+
+func TestStrategyBundle_ExportSignImport(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	seed[0] = 7
+	t.Setenv(strategyBundleKeyEnv, base64.StdEncoding.EncodeToString(seed))
+
+	src := &bundleRepo{
+		strategies: map[string]*models.Strategy{"systematic_no": {
+			Name:            "systematic_no",
+			DisplayName:     "Systematic NO",
+			Category:        "systematic",
+			Enabled:         true,
+			LaunchStage:     models.LaunchStageFull,
+			Params:          datatypes.JSON(`{"min_yes_price":0.9,"required_labels":["hype"],"universe":{"categories":["politics"]}}`),
+			RequiredSignals: datatypes.JSON(`["market_price"]`),
+		}},
+		rules: map[string]*models.ExecutionRule{"systematic_no": {
+			StrategyName: "systematic_no", AutoExecute: true, MinConfidence: 0.7,
+			MinEdgePct: decimal.RequireFromString("0.03"), MaxHoldHours: 48, MaxDailyTrades: 5,
+		}},
+	}
+	bundle, err := (&StrategyBundleService{Repo: src}).Export(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if bundle.Signature == nil || len(bundle.Strategies) != 1 {
+		t.Fatalf("unexpected bundle: %+v", bundle)
+	}
+	got := bundle.Strategies[0]
+	if len(got.RequiredLabels) != 1 || got.RequiredLabels[0] != "hype" || len(got.Universe) == 0 {
+		t.Fatalf("labels/universe not lifted: %+v", got)
+	}
+
+	// Round-trip through JSON as a transfer would.
+	raw, _ := json.Marshal(bundle)
+	var received StrategyBundle
+	if err := json.Unmarshal(raw, &received); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	pub := bundle.Signature.PublicKey
+	if err := VerifyStrategyBundle(&received, []string{pub}); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	t.Setenv(strategyBundleKeyEnv, "")
+	dst := &bundleRepo{strategies: map[string]*models.Strategy{}, rules: map[string]*models.ExecutionRule{}}
+	svc := &StrategyBundleService{Repo: dst, RequireSignature: true, NewStrategyStage: models.LaunchStageDark}
+	if _, err := svc.Import(context.Background(), &received, BundleImportOptions{}); !errors.Is(err, ErrBundleUntrustedKey) {
+		t.Fatalf("expected untrusted key, got %v", err)
+	}
+	svc.TrustedKeys = []string{pub}
+	report, err := svc.Import(context.Background(), &received, BundleImportOptions{})
+	if err != nil || len(report.Results) != 1 || report.Results[0].Action != "created" {
+		t.Fatalf("import: %+v %v", report, err)
+	}
+	imported := dst.strategies["systematic_no"]
+	if imported.Enabled || imported.LaunchStage != models.LaunchStageDark {
+		t.Fatalf("imported strategy should start disabled and dark: %+v", imported)
+	}
+	var params map[string]any
+	_ = json.Unmarshal(imported.Params, &params)
+	if params["min_yes_price"] != 0.9 || params["required_labels"] == nil || params["universe"] == nil {
+		t.Fatalf("params not restored: %s", imported.Params)
+	}
+	if rule := dst.rules["systematic_no"]; rule == nil || rule.AutoExecute || rule.MaxHoldHours != 48 {
+		t.Fatalf("rule not imported safely: %+v", rule)
+	}
+
+	// Existing strategies are skipped without overwrite.
+	report, err = svc.Import(context.Background(), &received, BundleImportOptions{})
+	if err != nil || report.Results[0].Action != "skipped" {
+		t.Fatalf("expected skip: %+v %v", report, err)
+	}
+
+	// Any change after signing breaks the signature.
+	received.Strategies[0].Params = json.RawMessage(`{"min_yes_price":0.5}`)
+	if err := VerifyStrategyBundle(&received, []string{pub}); !errors.Is(err, ErrBundleBadSignature) {
+		t.Fatalf("expected bad signature, got %v", err)
+	}
+	received.Signature = nil
+	if _, err := svc.Import(context.Background(), &received, BundleImportOptions{}); !errors.Is(err, ErrBundleUnsigned) {
+		t.Fatalf("expected unsigned, got %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+	"polymarket/internal/risk"
+)
+
+// capacitySearchStepUSD is the precision the per-opportunity size search
+// stops at.
+const capacitySearchStepUSD = 1.0
+
+var ErrStrategyNotFound = errors.New("strategy not found")
+
+// StrategyCapacityService estimates how much capital each strategy can
+// deploy before its edge is consumed by the depth of its markets.
+type StrategyCapacityService struct {
+	Repo   repository.Repository
+	Config config.CapacityConfig
+	Costs  *CostForecaster
+	// Risk supplies the strategy exposure caps, per desk.
+	Risk   *risk.Manager
+	Logger *zap.Logger
+	// Notify routes cap warnings to the notification dispatcher; nil
+	// broadcasts through the platform client in ctx.
+	Notify func(ctx context.Context, event, message string) error
+}
+
+// MarketCapacity is how far one opportunity can be sized up.
+type MarketCapacity struct {
+	OpportunityID uint64  `json:"opportunity_id"`
+	MarketKey     string  `json:"market_key"`
+	EdgePct       float64 `json:"edge_pct"`
+	CapacityUSD   float64 `json:"capacity_usd"`
+	// NetEdgePct is the net edge at CapacityUSD.
+	NetEdgePct float64 `json:"net_edge_pct"`
+	// DepthLimited is set when the book ran out before the edge did.
+	DepthLimited bool `json:"depth_limited"`
+}
+
+// RunOnce estimates every enabled strategy.
+func (s *StrategyCapacityService) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	if s == nil || s.Repo == nil || !s.Config.Enabled {
+		return 0, nil
+	}
+	strategies, err := s.Repo.ListStrategies(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, strat := range strategies {
+		if !strat.Enabled {
+			continue
+		}
+		if _, err := s.Estimate(ctx, strat.Name, now); err != nil {
+			return n, fmt.Errorf("%s: %w", strat.Name, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Estimate computes and stores the strategy's capacity at now, and warns
+// when its exposure cap newly exceeds it.
+func (s *StrategyCapacityService) Estimate(ctx context.Context, name string, now time.Time) (*models.StrategyCapacity, error) {
+	if s == nil || s.Repo == nil || s.Costs == nil {
+		return nil, errors.New("capacity unavailable")
+	}
+	name = strings.TrimSpace(name)
+	strat, err := s.Repo.GetStrategyByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if strat == nil {
+		return nil, ErrStrategyNotFound
+	}
+	prev, err := s.Repo.GetLatestStrategyCapacity(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	impact, samples, err := s.impactFactor(ctx, name, now)
+	if err != nil {
+		return nil, err
+	}
+	active := "active"
+	opps, err := s.Repo.ListOpportunities(ctx, repository.ListOpportunitiesParams{
+		Limit:        s.Config.MaxOpportunities,
+		Status:       &active,
+		StrategyName: &name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	markets := make([]MarketCapacity, 0, len(opps))
+	total := 0.0
+	for _, opp := range opps {
+		books, err := s.Costs.legBooks(ctx, opp)
+		if err != nil {
+			return nil, err
+		}
+		mc := s.opportunityCapacity(opp, books, impact)
+		total += mc.CapacityUSD
+		markets = append(markets, mc)
+	}
+	sort.SliceStable(markets, func(i, j int) bool { return markets[i].CapacityUSD > markets[j].CapacityUSD })
+	raw, err := json.Marshal(markets)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.StrategyCapacity{
+		StrategyName:    name,
+		CapacityUSD:     decimal.NewFromFloat(total).Round(2),
+		MedianMarketUSD: decimal.NewFromFloat(medianCapacity(markets)).Round(2),
+		Opportunities:   len(markets),
+		ImpactFactor:    impact,
+		ImpactSamples:   samples,
+		Markets:         datatypes.JSON(raw),
+		EstimatedAt:     now.UTC(),
+	}
+	if s.Risk != nil {
+		if limit := s.Risk.ForTenant(strat.Tenant).Config.MaxPerStrategyUSD; limit > 0 {
+			item.ExposureCapUSD = decimal.NewFromFloat(limit)
+			item.CapExceeded = item.ExposureCapUSD.GreaterThan(item.CapacityUSD)
+		}
+	}
+	if err := s.Repo.InsertStrategyCapacity(ctx, item); err != nil {
+		return nil, err
+	}
+	if item.CapExceeded && (prev == nil || !prev.CapExceeded) {
+		s.warnCapExceeded(ctx, item)
+	}
+	return item, nil
+}
+
+// impactFactor is the strategy's realized to forecast execution cost over
+// the lookback, or 1 until it has enough plans.
+func (s *StrategyCapacityService) impactFactor(ctx context.Context, name string, now time.Time) (float64, int, error) {
+	params := repository.CostForecastOutcomeParams{StrategyName: &name}
+	if s.Config.ImpactLookback > 0 {
+		since := now.Add(-s.Config.ImpactLookback)
+		params.Since = &since
+	}
+	accs, err := s.Costs.Accuracy(ctx, params)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, a := range accs {
+		if a.Strategy != name {
+			continue
+		}
+		if a.Plans >= s.Config.ImpactMinSamples && a.RealizedToForecastCost > 0 {
+			return a.RealizedToForecastCost, a.Plans, nil
+		}
+		return 1, a.Plans, nil
+	}
+	return 1, 0, nil
+}
+
+// opportunityCapacity finds the largest size at which opp still clears the
+// net edge floor with every leg filled from visible depth. Net edge per
+// dollar only falls as size grows, so the search bisects.
+func (s *StrategyCapacityService) opportunityCapacity(opp models.Opportunity, books map[string]models.OrderbookLatest, impact float64) MarketCapacity {
+	out := MarketCapacity{
+		OpportunityID: opp.ID,
+		MarketKey:     opportunityMarketKey(opp),
+		EdgePct:       opp.CurrentEdgePct().InexactFloat64(),
+	}
+	fits := func(size float64) (bool, bool, float64) {
+		fc := s.Costs.forecast(opp, decimal.NewFromFloat(size), books)
+		if len(fc.Legs) == 0 {
+			return false, false, 0
+		}
+		for _, l := range fc.Legs {
+			if l.UnknownDepth {
+				return false, true, 0
+			}
+		}
+		slippage := fc.SlippageUSD.InexactFloat64() * impact
+		net := fc.GrossEdgeUSD.Sub(fc.FeesUSD).Sub(fc.AdverseSelectionUSD).InexactFloat64() - slippage
+		pct := net / size
+		return pct > s.Config.MinNetEdgePct, false, pct
+	}
+	hi := s.Config.MaxSizeUSD
+	if hi <= 0 {
+		return out
+	}
+	ok, depth, pct := fits(capacitySearchStepUSD)
+	if !ok {
+		out.DepthLimited = depth
+		return out
+	}
+	lo := capacitySearchStepUSD
+	out.NetEdgePct = pct
+	if ok, depth, pct = fits(hi); ok {
+		out.CapacityUSD, out.NetEdgePct = hi, pct
+		return out
+	}
+	out.DepthLimited = depth
+	for hi-lo > capacitySearchStepUSD {
+		mid := (lo + hi) / 2
+		ok, depth, pct := fits(mid)
+		if ok {
+			lo, out.NetEdgePct = mid, pct
+		} else {
+			hi, out.DepthLimited = mid, depth
+		}
+	}
+	out.CapacityUSD = lo
+	return out
+}
+
+// opportunityMarketKey names the market an opportunity trades: its event,
+// else its primary market.
+func opportunityMarketKey(opp models.Opportunity) string {
+	if opp.EventID != nil && strings.TrimSpace(*opp.EventID) != "" {
+		return "event:" + strings.TrimSpace(*opp.EventID)
+	}
+	if opp.PrimaryMarketID != nil && strings.TrimSpace(*opp.PrimaryMarketID) != "" {
+		return "market:" + strings.TrimSpace(*opp.PrimaryMarketID)
+	}
+	return fmt.Sprintf("opportunity:%d", opp.ID)
+}
+
+func medianCapacity(markets []MarketCapacity) float64 {
+	if len(markets) == 0 {
+		return 0
+	}
+	values := make([]float64, len(markets))
+	for i, m := range markets {
+		values[i] = m.CapacityUSD
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+func (s *StrategyCapacityService) warnCapExceeded(ctx context.Context, item *models.StrategyCapacity) {
+	if s.Logger != nil {
+		s.Logger.Warn("strategy exposure cap above capacity",
+			zap.String("strategy", item.StrategyName),
+			zap.String("capacity_usd", item.CapacityUSD.String()),
+			zap.String("exposure_cap_usd", item.ExposureCapUSD.String()),
+		)
+	}
+	paas.LogBestEffortCtx(ctx, "polymarket_strategy_capacity_exceeded", "warn", map[string]any{
+		"strategy":         item.StrategyName,
+		"capacity_usd":     item.CapacityUSD.String(),
+		"exposure_cap_usd": item.ExposureCapUSD.String(),
+		"opportunities":    item.Opportunities,
+	})
+	notify := s.Notify
+	if notify == nil {
+		notify = paas.BroadcastCtx
+	}
+	message := fmt.Sprintf("[warning] polymarket strategy %s exposure cap %s USD is above its estimated capacity %s USD across %d opportunities",
+		item.StrategyName, item.ExposureCapUSD.StringFixed(0), item.CapacityUSD.StringFixed(0), item.Opportunities)
+	if err := notify(ctx, s.Config.NotifyEvent, message); err != nil && s.Logger != nil {
+		s.Logger.Warn("strategy capacity notify failed", zap.String("strategy", item.StrategyName), zap.Error(err))
+	}
+}
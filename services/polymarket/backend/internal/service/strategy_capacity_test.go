@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/risk"
+)
+
+type capacityRepo struct {
+	repository.Repository
+	opps     []models.Opportunity
+	books    []models.OrderbookLatest
+	inserted []models.StrategyCapacity
+}
+
+func (r *capacityRepo) GetStrategyByName(_ context.Context, name string) (*models.Strategy, error) {
+	if name != "arb" {
+		return nil, nil
+	}
+	return &models.Strategy{Name: name, Enabled: true}, nil
+}
+
+func (r *capacityRepo) GetLatestStrategyCapacity(context.Context, string) (*models.StrategyCapacity, error) {
+	if len(r.inserted) == 0 {
+		return nil, nil
+	}
+	latest := r.inserted[len(r.inserted)-1]
+	return &latest, nil
+}
+
+func (r *capacityRepo) InsertStrategyCapacity(_ context.Context, item *models.StrategyCapacity) error {
+	r.inserted = append(r.inserted, *item)
+	return nil
+}
+
+func (r *capacityRepo) ListOpportunities(context.Context, repository.ListOpportunitiesParams) ([]models.Opportunity, error) {
+	return r.opps, nil
+}
+
+func (r *capacityRepo) ListOrderbookLatestByTokenIDs(context.Context, []string) ([]models.OrderbookLatest, error) {
+	return r.books, nil
+}
+
+func (r *capacityRepo) ListCostForecastOutcomes(context.Context, repository.CostForecastOutcomeParams) ([]repository.CostForecastOutcomeRow, error) {
+	return nil, nil
+}
+
+func capacityOpportunity(id uint64, edgePct string) models.Opportunity {
+	return models.Opportunity{
+		ID:      id,
+		EdgePct: decimal.RequireFromString(edgePct),
+		Legs:    []byte(`[{"token_id":"t1","direction":"BUY_YES"}]`),
+	}
+}
+
+func TestOpportunityCapacity_EdgeAndDepthLimited(t *testing.T) {
+	ask := 0.40
+	// 20 USD of depth at 0.40 and 50 USD at 0.50.
+	books := map[string]models.OrderbookLatest{
+		"t1": {TokenID: "t1", BestAsk: &ask, AsksJSON: []byte(`[{"price":"0.40","size":"50"},{"price":"0.50","size":"100"}]`)},
+	}
+	s := &StrategyCapacityService{
+		Config: config.CapacityConfig{MaxSizeUSD: 1000},
+		Costs:  &CostForecaster{},
+	}
+
+	// A 5% edge is gone once the average price passes 0.40/0.95, at about
+	// 26.67 USD.
+	mc := s.opportunityCapacity(capacityOpportunity(1, "0.05"), books, 1)
+	if mc.DepthLimited || math.Abs(mc.CapacityUSD-26.67) > 1.5 {
+		t.Fatalf("edge limited=%+v", mc)
+	}
+	// Doubling the realized impact halves the room past the first level.
+	if hot := s.opportunityCapacity(capacityOpportunity(1, "0.05"), books, 2); hot.CapacityUSD >= mc.CapacityUSD {
+		t.Fatalf("impact scaled=%+v base=%+v", hot, mc)
+	}
+	// A wide edge outlasts the 70 USD of visible depth.
+	wide := s.opportunityCapacity(capacityOpportunity(2, "0.50"), books, 1)
+	if !wide.DepthLimited || wide.CapacityUSD > 70 || wide.CapacityUSD < 68 {
+		t.Fatalf("depth limited=%+v", wide)
+	}
+	// No book means no visible depth at all.
+	if none := s.opportunityCapacity(capacityOpportunity(3, "0.50"), nil, 1); none.CapacityUSD != 0 || !none.DepthLimited {
+		t.Fatalf("no book=%+v", none)
+	}
+}
+
+func TestStrategyCapacity_WarnsOnceWhenCapExceeded(t *testing.T) {
+	ask := 0.40
+	repo := &capacityRepo{
+		opps: []models.Opportunity{capacityOpportunity(1, "0.50")},
+		books: []models.OrderbookLatest{
+			{TokenID: "t1", BestAsk: &ask, AsksJSON: []byte(`[{"price":"0.40","size":"50"},{"price":"0.50","size":"100"}]`)},
+		},
+	}
+	var notes []string
+	s := &StrategyCapacityService{
+		Repo:   repo,
+		Config: config.CapacityConfig{Enabled: true, MaxSizeUSD: 1000, NotifyEvent: "capacity"},
+		Costs:  &CostForecaster{Repo: repo},
+		Risk:   &risk.Manager{Config: config.RiskConfig{MaxPerStrategyUSD: 500}, Repo: repo},
+		Notify: func(_ context.Context, event, message string) error {
+			notes = append(notes, message)
+			return nil
+		},
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		item, err := s.Estimate(context.Background(), "arb", now.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatalf("estimate: %v", err)
+		}
+		if !item.CapExceeded || item.Opportunities != 1 || item.ImpactFactor != 1 {
+			t.Fatalf("item=%+v", item)
+		}
+	}
+	if len(repo.inserted) != 2 || len(notes) != 1 {
+		t.Fatalf("inserted=%d notes=%v", len(repo.inserted), notes)
+	}
+	if _, err := s.Estimate(context.Background(), "missing", now); err != ErrStrategyNotFound {
+		t.Fatalf("missing err=%v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// Verdicts of a strategy change analysis.
+const (
+	ChangeImproved         = "improved"
+	ChangeWorse            = "worse"
+	ChangeNoDifference     = "no_difference"
+	ChangeInsufficientData = "insufficient_data"
+)
+
+// changeSignificanceT is the |t| above which a cohort difference in mean PnL
+// is called, roughly 95% two-sided once the cohorts are not tiny.
+const changeSignificanceT = 2.0
+
+var ErrStrategyChangeNotFound = errors.New("strategy change not found")
+
+// StrategyChangeService records strategy params changes with a holdout and
+// judges them by how the control and treatment cohorts performed.
+type StrategyChangeService struct {
+	Repo   repository.Repository
+	Config config.StrategyHoldoutConfig
+}
+
+// ChangeParams replaces the strategy's params and records the change. pct
+// and duration override the configured holdout; a zero pct records the
+// change without holding any markets out. Params equal to the current ones
+// are written without a new change, so the running holdout is kept.
+func (s *StrategyChangeService) ChangeParams(ctx context.Context, name string, params []byte, pct *float64, duration *time.Duration) (*models.StrategyChange, error) {
+	if s == nil || s.Repo == nil {
+		return nil, errors.New("repo unavailable")
+	}
+	name = strings.TrimSpace(name)
+	strat, err := s.Repo.GetStrategyByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var oldParams datatypes.JSON
+	if strat != nil {
+		oldParams = strat.Params
+	}
+	if err := s.Repo.UpdateStrategyParams(ctx, name, params); err != nil {
+		return nil, err
+	}
+	if strat == nil || sameJSON(oldParams, params) {
+		return nil, nil
+	}
+	holdoutPct, holdoutFor := 0.0, s.Config.Duration
+	if s.Config.Enabled {
+		holdoutPct = s.Config.Pct
+	}
+	if pct != nil {
+		holdoutPct = *pct
+	}
+	if duration != nil {
+		holdoutFor = *duration
+	}
+	change := &models.StrategyChange{
+		StrategyName: name,
+		OldParams:    oldParams,
+		NewParams:    datatypes.JSON(params),
+	}
+	if len(change.OldParams) == 0 {
+		change.OldParams = datatypes.JSON(`{}`)
+	}
+	if holdoutPct > 0 && holdoutFor > 0 {
+		until := time.Now().UTC().Add(holdoutFor)
+		change.HoldoutPct = math.Min(holdoutPct, 100)
+		change.HoldoutUntil = &until
+	}
+	if err := s.Repo.InsertStrategyChange(ctx, change); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+func sameJSON(a, b []byte) bool {
+	var x, y any
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return bytes.Equal(a, b)
+	}
+	xs, _ := json.Marshal(x)
+	ys, _ := json.Marshal(y)
+	return bytes.Equal(xs, ys)
+}
+
+// CohortStats is how one cohort of a change performed on its settled plans.
+type CohortStats struct {
+	Cohort        string  `json:"cohort"`
+	Opportunities int64   `json:"opportunities"`
+	Plans         int64   `json:"plans"`
+	Settled       int64   `json:"settled"`
+	WinRate       float64 `json:"win_rate"`
+	TotalPnL      float64 `json:"total_pnl"`
+	MeanPnL       float64 `json:"mean_pnl"`
+	StdDevPnL     float64 `json:"stddev_pnl"`
+}
+
+// StrategyChangeAnalysis compares a change's treatment cohort, on the new
+// params, with its control cohort, on the old ones. TStat is Welch's t on
+// mean PnL per settled plan, nil while either cohort is too small.
+type StrategyChangeAnalysis struct {
+	Change        models.StrategyChange `json:"change"`
+	HoldoutActive bool                  `json:"holdout_active"`
+	Control       CohortStats           `json:"control"`
+	Treatment     CohortStats           `json:"treatment"`
+	MeanPnLDiff   float64               `json:"mean_pnl_diff"`
+	TStat         *float64              `json:"t_stat"`
+	Verdict       string                `json:"verdict"`
+}
+
+// Analyze reports on the strategy's change changeID, or its latest change
+// when changeID is 0.
+func (s *StrategyChangeService) Analyze(ctx context.Context, name string, changeID uint64, now time.Time) (*StrategyChangeAnalysis, error) {
+	if s == nil || s.Repo == nil {
+		return nil, errors.New("repo unavailable")
+	}
+	name = strings.TrimSpace(name)
+	var (
+		change *models.StrategyChange
+		err    error
+	)
+	if changeID > 0 {
+		change, err = s.Repo.GetStrategyChange(ctx, changeID)
+	} else {
+		change, err = s.Repo.GetLatestStrategyChange(ctx, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if change == nil || change.StrategyName != name {
+		return nil, ErrStrategyChangeNotFound
+	}
+	rows, err := s.Repo.StrategyChangeCohorts(ctx, change.ID)
+	if err != nil {
+		return nil, err
+	}
+	out := &StrategyChangeAnalysis{
+		Change:        *change,
+		HoldoutActive: change.HoldoutActive(now),
+		Control:       CohortStats{Cohort: models.CohortControl},
+		Treatment:     CohortStats{Cohort: models.CohortTreatment},
+	}
+	for _, row := range rows {
+		switch row.Cohort {
+		case models.CohortControl:
+			out.Control = cohortStats(row)
+		case models.CohortTreatment:
+			out.Treatment = cohortStats(row)
+		}
+	}
+	out.MeanPnLDiff = out.Treatment.MeanPnL - out.Control.MeanPnL
+	out.Verdict = ChangeInsufficientData
+	minSamples := int64(s.Config.MinSamples)
+	if minSamples < 2 {
+		minSamples = 2
+	}
+	if out.Control.Settled < minSamples || out.Treatment.Settled < minSamples {
+		return out, nil
+	}
+	se := math.Sqrt(sq(out.Control.StdDevPnL)/float64(out.Control.Settled) + sq(out.Treatment.StdDevPnL)/float64(out.Treatment.Settled))
+	switch {
+	case se > 0:
+		t := out.MeanPnLDiff / se
+		out.TStat = &t
+		out.Verdict = changeVerdict(t)
+	case out.MeanPnLDiff > 0:
+		out.Verdict = ChangeImproved
+	case out.MeanPnLDiff < 0:
+		out.Verdict = ChangeWorse
+	default:
+		out.Verdict = ChangeNoDifference
+	}
+	return out, nil
+}
+
+func changeVerdict(t float64) string {
+	switch {
+	case t >= changeSignificanceT:
+		return ChangeImproved
+	case t <= -changeSignificanceT:
+		return ChangeWorse
+	default:
+		return ChangeNoDifference
+	}
+}
+
+// cohortStats derives the cohort's mean and sample standard deviation from
+// its sums.
+func cohortStats(row repository.CohortOutcome) CohortStats {
+	out := CohortStats{
+		Cohort:        row.Cohort,
+		Opportunities: row.Opportunities,
+		Plans:         row.Plans,
+		Settled:       row.Settled,
+		TotalPnL:      row.SumPnL,
+	}
+	if row.Settled == 0 {
+		return out
+	}
+	n := float64(row.Settled)
+	out.WinRate = float64(row.Wins) / n
+	out.MeanPnL = row.SumPnL / n
+	if row.Settled > 1 {
+		variance := (row.SumPnLSq - n*out.MeanPnL*out.MeanPnL) / (n - 1)
+		out.StdDevPnL = math.Sqrt(math.Max(variance, 0))
+	}
+	return out
+}
+
+func sq(x float64) float64 { return x * x }
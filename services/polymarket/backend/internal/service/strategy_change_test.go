@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type strategyChangeRepo struct {
+	repository.Repository
+	strat   *models.Strategy
+	changes []models.StrategyChange
+	cohorts []repository.CohortOutcome
+}
+
+func (r *strategyChangeRepo) GetStrategyByName(ctx context.Context, name string) (*models.Strategy, error) {
+	return r.strat, nil
+}
+
+func (r *strategyChangeRepo) UpdateStrategyParams(ctx context.Context, name string, params []byte) error {
+	r.strat.Params = datatypes.JSON(params)
+	return nil
+}
+
+func (r *strategyChangeRepo) InsertStrategyChange(ctx context.Context, item *models.StrategyChange) error {
+	item.ID = uint64(len(r.changes) + 1)
+	r.changes = append(r.changes, *item)
+	return nil
+}
+
+func (r *strategyChangeRepo) GetLatestStrategyChange(ctx context.Context, name string) (*models.StrategyChange, error) {
+	if len(r.changes) == 0 {
+		return nil, nil
+	}
+	c := r.changes[len(r.changes)-1]
+	return &c, nil
+}
+
+func (r *strategyChangeRepo) StrategyChangeCohorts(ctx context.Context, changeID uint64) ([]repository.CohortOutcome, error) {
+	return r.cohorts, nil
+}
+
+func TestStrategyChangeParamsRecordsHoldout(t *testing.T) {
+	repo := &strategyChangeRepo{strat: &models.Strategy{Name: "s", Params: datatypes.JSON(`{"a":1}`)}}
+	svc := &StrategyChangeService{Repo: repo, Config: config.StrategyHoldoutConfig{Enabled: true, Pct: 20, Duration: time.Hour}}
+	ctx := context.Background()
+
+	change, err := svc.ChangeParams(ctx, "s", []byte(`{"a":2}`), nil, nil)
+	if err != nil || change == nil {
+		t.Fatalf("change=%v err=%v", change, err)
+	}
+	if string(change.OldParams) != `{"a":1}` || change.HoldoutPct != 20 || !change.HoldoutActive(time.Now().UTC()) {
+		t.Fatalf("change=%+v", change)
+	}
+	// Rewriting the same params keeps the running holdout.
+	if again, err := svc.ChangeParams(ctx, "s", []byte(`{ "a": 2 }`), nil, nil); err != nil || again != nil {
+		t.Fatalf("same params recorded %+v err=%v", again, err)
+	}
+	zero := 0.0
+	change, _ = svc.ChangeParams(ctx, "s", []byte(`{"a":3}`), &zero, nil)
+	if change == nil || change.HoldoutPct != 0 || change.HoldoutUntil != nil {
+		t.Fatalf("no-holdout change=%+v", change)
+	}
+}
+
+func TestStrategyChangeAnalyzeVerdict(t *testing.T) {
+	repo := &strategyChangeRepo{changes: []models.StrategyChange{{ID: 1, StrategyName: "s"}}}
+	svc := &StrategyChangeService{Repo: repo, Config: config.StrategyHoldoutConfig{MinSamples: 5}}
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// Control: 10 settled at mean 0, sd 1; treatment: 10 at mean 2, sd 1.
+	repo.cohorts = []repository.CohortOutcome{
+		{Cohort: models.CohortControl, Opportunities: 12, Plans: 10, Settled: 10, Wins: 5, SumPnL: 0, SumPnLSq: 9},
+		{Cohort: models.CohortTreatment, Opportunities: 30, Plans: 10, Settled: 10, Wins: 9, SumPnL: 20, SumPnLSq: 49},
+	}
+	out, err := svc.Analyze(ctx, "s", 0, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Verdict != ChangeImproved || out.TStat == nil || *out.TStat < 4 || out.Treatment.WinRate != 0.9 {
+		t.Fatalf("analysis=%+v", out)
+	}
+
+	repo.cohorts[1].Settled, repo.cohorts[1].SumPnL, repo.cohorts[1].SumPnLSq = 3, 6, 14
+	if out, _ := svc.Analyze(ctx, "s", 0, now); out.Verdict != ChangeInsufficientData || out.TStat != nil {
+		t.Fatalf("small cohort verdict=%s", out.Verdict)
+	}
+	if _, err := svc.Analyze(ctx, "other", 0, now); err != ErrStrategyChangeNotFound {
+		t.Fatalf("other strategy err=%v", err)
+	}
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// SignalFeatureSwitches maps each signal type to the feature switch of the
+// collector that produces it. Signal types missing here come from collectors
+// that always run with the strategy engine (internal scan, settlement history).
+var SignalFeatureSwitches = map[string]string{
+	"btc_depth_imbalance":    FeatureSignalBinanceWS,
+	"btc_price_change":       FeatureSignalBinancePrice,
+	"crypto_price_change":    FeatureSignalBinancePrice,
+	"weather_deviation":      FeatureSignalWeatherAPI,
+	"news_alpha":             FeatureSignalPriceChange,
+	"volatility_spread":      FeatureSignalPriceChange,
+	"fear_spike":             FeatureSignalOrderbook,
+	"mm_inventory_skew":      FeatureSignalOrderbook,
+	"certainty_sweep":        FeatureSignalCertainty,
+	"market_close_countdown": FeatureSignalMarketClose,
+	"smart_money_move":       FeatureSignalSmartMoney,
+}
+
+// StrategyDependencies returns the feature switches a strategy needs, derived
+// from its required signals. The strategy engine switch is always first.
+func StrategyDependencies(s models.Strategy) []string {
+	out := []string{FeatureStrategyEngine}
+	var signals []string
+	if len(s.RequiredSignals) > 0 {
+		_ = json.Unmarshal(s.RequiredSignals, &signals)
+	}
+	seen := map[string]bool{FeatureStrategyEngine: true}
+	for _, sig := range signals {
+		key := SignalFeatureSwitches[strings.TrimSpace(sig)]
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, key)
+	}
+	return out
+}
+
+type StrategyToggleRequest struct {
+	Names    []string `json:"names"`
+	Category string   `json:"category"`
+	Enable   bool     `json:"-"`
+	// AutoSwitches turns required switches on when enabling, and turns signal
+	// switches off once no enabled strategy needs them when disabling. Without
+	// it, enabling a strategy with a disabled dependency is blocked.
+	AutoSwitches bool `json:"auto_switches"`
+	DryRun       bool `json:"dry_run"`
+}
+
+type StrategyTogglePlanItem struct {
+	Name     string   `json:"name"`
+	Before   bool     `json:"enabled_before"`
+	After    bool     `json:"enabled_after"`
+	Requires []string `json:"requires"`
+	Missing  []string `json:"missing,omitempty"`
+	Status   string   `json:"status"`
+}
+
+type SwitchChange struct {
+	Key        string   `json:"key"`
+	From       bool     `json:"from"`
+	To         bool     `json:"to"`
+	RequiredBy []string `json:"required_by,omitempty"`
+}
+
+type StrategyTogglePlan struct {
+	Action     string                   `json:"action"`
+	DryRun     bool                     `json:"dry_run"`
+	Strategies []StrategyTogglePlanItem `json:"strategies"`
+	Switches   []SwitchChange           `json:"switches"`
+	NotFound   []string                 `json:"not_found,omitempty"`
+	// RestartRequired is set when any switch changes: the engine and its
+	// collectors are wired at startup.
+	RestartRequired bool `json:"restart_required"`
+	Applied         bool `json:"applied"`
+}
+
+// PlanStrategyToggle computes the strategy and switch changes for a bulk
+// enable/disable without touching storage. all is every known strategy and
+// switches the current switch state.
+func PlanStrategyToggle(all []models.Strategy, req StrategyToggleRequest, switches map[string]bool) StrategyTogglePlan {
+	plan := StrategyTogglePlan{Action: "disable", DryRun: req.DryRun, Strategies: []StrategyTogglePlanItem{}, Switches: []SwitchChange{}}
+	if req.Enable {
+		plan.Action = "enable"
+	}
+	byName := map[string]models.Strategy{}
+	for _, s := range all {
+		byName[s.Name] = s
+	}
+	targets := map[string]bool{}
+	for _, name := range req.Names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := byName[name]; !ok {
+			plan.NotFound = append(plan.NotFound, name)
+			continue
+		}
+		targets[name] = true
+	}
+	if cat := strings.TrimSpace(req.Category); cat != "" {
+		for _, s := range all {
+			if strings.EqualFold(s.Category, cat) {
+				targets[s.Name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changes := map[string]*SwitchChange{}
+	change := func(key string, to bool, by string) {
+		ch := changes[key]
+		if ch == nil {
+			ch = &SwitchChange{Key: key, From: switches[key], To: to}
+			changes[key] = ch
+		}
+		if by != "" {
+			ch.RequiredBy = append(ch.RequiredBy, by)
+		}
+	}
+
+	enabledAfter := map[string]bool{}
+	for _, s := range all {
+		enabledAfter[s.Name] = s.Enabled
+	}
+	for _, name := range names {
+		s := byName[name]
+		item := StrategyTogglePlanItem{Name: name, Before: s.Enabled, After: s.Enabled, Requires: StrategyDependencies(s)}
+		if req.Enable {
+			for _, key := range item.Requires {
+				if !switches[key] {
+					item.Missing = append(item.Missing, key)
+				}
+			}
+			switch {
+			case len(item.Missing) > 0 && !req.AutoSwitches:
+				item.Status = "blocked"
+			case s.Enabled && len(item.Missing) == 0:
+				item.Status = "unchanged"
+			default:
+				item.After = true
+				item.Status = "enable"
+				for _, key := range item.Missing {
+					change(key, true, name)
+				}
+			}
+		} else {
+			item.After = false
+			item.Status = "disable"
+			if !s.Enabled {
+				item.Status = "unchanged"
+			}
+		}
+		enabledAfter[name] = item.After
+		plan.Strategies = append(plan.Strategies, item)
+	}
+
+	if !req.Enable && req.AutoSwitches {
+		// Turn off collector switches no remaining strategy needs. The strategy
+		// engine switch is shared infrastructure and is left alone.
+		needed := map[string]bool{}
+		for _, s := range all {
+			if !enabledAfter[s.Name] {
+				continue
+			}
+			for _, key := range StrategyDependencies(s) {
+				needed[key] = true
+			}
+		}
+		for _, name := range names {
+			for _, key := range StrategyDependencies(byName[name]) {
+				if key == FeatureStrategyEngine || needed[key] || !switches[key] {
+					continue
+				}
+				change(key, false, "")
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		plan.Switches = append(plan.Switches, *changes[key])
+	}
+	plan.RestartRequired = len(plan.Switches) > 0
+	return plan
+}
+
+// StrategyToggleService applies bulk strategy enable/disable plans.
+type StrategyToggleService struct {
+	Repo     repository.Repository
+	Settings *SystemSettingsService
+}
+
+// Apply plans the change and, unless DryRun is set, writes the switch changes
+// first and then the strategy states. Blocked strategies are never changed.
+func (s *StrategyToggleService) Apply(ctx context.Context, req StrategyToggleRequest, visible func(models.Strategy) bool) (StrategyTogglePlan, error) {
+	if s == nil || s.Repo == nil {
+		return StrategyTogglePlan{}, fmt.Errorf("repo unavailable")
+	}
+	all, err := s.Repo.ListStrategies(ctx)
+	if err != nil {
+		return StrategyTogglePlan{}, err
+	}
+	if visible != nil {
+		filtered := make([]models.Strategy, 0, len(all))
+		for _, it := range all {
+			if visible(it) {
+				filtered = append(filtered, it)
+			}
+		}
+		all = filtered
+	}
+	switches := map[string]bool{}
+	defaults := DefaultFeatureSwitches()
+	for _, it := range all {
+		for _, key := range StrategyDependencies(it) {
+			switches[key] = s.Settings.IsEnabled(ctx, key, defaults[key])
+		}
+	}
+	plan := PlanStrategyToggle(all, req, switches)
+	if req.DryRun {
+		return plan, nil
+	}
+	for _, ch := range plan.Switches {
+		if err := s.Settings.SetEnabled(ctx, ch.Key, ch.To); err != nil {
+			return plan, err
+		}
+	}
+	for _, item := range plan.Strategies {
+		if item.Status != "enable" && item.Status != "disable" {
+			continue
+		}
+		if err := s.Repo.SetStrategyEnabled(ctx, item.Name, item.After); err != nil {
+			return plan, err
+		}
+	}
+	plan.Applied = true
+	return plan, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+)
+
+func depStrategy(name string, enabled bool, signals string) models.Strategy {
+	return models.Strategy{Name: name, Category: "test", Enabled: enabled, RequiredSignals: datatypes.JSON(signals)}
+}
+
+func TestPlanStrategyToggle_EnableBlockedWithoutAutoSwitches(t *testing.T) {
+	all := []models.Strategy{
+		depStrategy("copy_flow", false, `["smart_money_move"]`),
+		depStrategy("arb_sum", false, `["arb_sum_deviation"]`),
+	}
+	switches := map[string]bool{FeatureStrategyEngine: true}
+	plan := PlanStrategyToggle(all, StrategyToggleRequest{Names: []string{"copy_flow", "arb_sum", "nope"}, Enable: true}, switches)
+
+	if len(plan.Strategies) != 2 || len(plan.NotFound) != 1 || len(plan.Switches) != 0 {
+		t.Fatalf("plan=%+v", plan)
+	}
+	for _, it := range plan.Strategies {
+		switch it.Name {
+		case "arb_sum":
+			if it.Status != "enable" || !it.After {
+				t.Fatalf("arb_sum=%+v", it)
+			}
+		case "copy_flow":
+			if it.Status != "blocked" || it.After || len(it.Missing) != 1 || it.Missing[0] != FeatureSignalSmartMoney {
+				t.Fatalf("copy_flow=%+v", it)
+			}
+		}
+	}
+}
+
+func TestPlanStrategyToggle_EnableTurnsOnSwitches(t *testing.T) {
+	all := []models.Strategy{depStrategy("copy_flow", false, `["smart_money_move"]`)}
+	plan := PlanStrategyToggle(all, StrategyToggleRequest{Names: []string{"copy_flow"}, Enable: true, AutoSwitches: true}, map[string]bool{})
+
+	if len(plan.Switches) != 2 || !plan.RestartRequired {
+		t.Fatalf("plan=%+v", plan)
+	}
+	if plan.Switches[0].Key != FeatureSignalSmartMoney || !plan.Switches[0].To || plan.Switches[0].RequiredBy[0] != "copy_flow" || plan.Switches[1].Key != FeatureStrategyEngine {
+		t.Fatalf("switches=%+v", plan.Switches)
+	}
+}
+
+func TestPlanStrategyToggle_DisableKeepsSharedSwitches(t *testing.T) {
+	all := []models.Strategy{
+		depStrategy("news_alpha", true, `["news_alpha"]`),
+		depStrategy("volatility_arb", true, `["volatility_spread"]`),
+		depStrategy("copy_flow", true, `["smart_money_move"]`),
+	}
+	switches := map[string]bool{FeatureStrategyEngine: true, FeatureSignalPriceChange: true, FeatureSignalSmartMoney: true}
+	plan := PlanStrategyToggle(all, StrategyToggleRequest{Names: []string{"news_alpha", "copy_flow"}, AutoSwitches: true}, switches)
+
+	if len(plan.Switches) != 1 || plan.Switches[0].Key != FeatureSignalSmartMoney || plan.Switches[0].To {
+		t.Fatalf("switches=%+v", plan.Switches)
+	}
+	for _, it := range plan.Strategies {
+		if it.After || it.Status != "disable" {
+			t.Fatalf("item=%+v", it)
+		}
+	}
+}
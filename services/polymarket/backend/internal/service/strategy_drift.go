@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
+)
+
+// Strategy drift flags.
+const (
+	DriftTradeFrequencyCollapse = "trade_frequency_collapse"
+	DriftWinRateDrop            = "win_rate_drop"
+	DriftEdgeDrop               = "edge_drop"
+)
+
+var ErrNoStrategyExpectation = errors.New("strategy has no expectation")
+
+// StrategyDriftService compares live strategy_daily_stats with the
+// strategies' backtest expectations.
+type StrategyDriftService struct {
+	Repo   repository.Repository
+	Config config.StrategyDriftConfig
+	Logger *zap.Logger
+	// Notify routes drift warnings to the notification dispatcher; nil
+	// broadcasts through the platform client in ctx.
+	Notify func(ctx context.Context, event, message string) error
+}
+
+// StrategyDriftSummary is a strategy's expectation and its latest report,
+// nil until one has run.
+type StrategyDriftSummary struct {
+	Expectation models.StrategyExpectation  `json:"expectation"`
+	Latest      *models.StrategyDriftReport `json:"latest"`
+}
+
+// RunOnce evaluates every enabled strategy that has an expectation.
+func (s *StrategyDriftService) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	if s == nil || s.Repo == nil || !s.Config.Enabled {
+		return 0, nil
+	}
+	exps, err := s.Repo.ListStrategyExpectations(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, exp := range exps {
+		strat, err := s.Repo.GetStrategyByName(ctx, exp.StrategyName)
+		if err != nil {
+			return n, err
+		}
+		if strat == nil || !strat.Enabled {
+			continue
+		}
+		if _, err := s.Evaluate(ctx, exp.StrategyName, now); err != nil {
+			return n, fmt.Errorf("%s: %w", exp.StrategyName, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Report lists every expectation with its latest drift report.
+func (s *StrategyDriftService) Report(ctx context.Context) ([]StrategyDriftSummary, error) {
+	if s == nil || s.Repo == nil {
+		return nil, errors.New("strategy drift unavailable")
+	}
+	exps, err := s.Repo.ListStrategyExpectations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]StrategyDriftSummary, 0, len(exps))
+	for _, exp := range exps {
+		latest, err := s.Repo.GetLatestStrategyDriftReport(ctx, exp.StrategyName)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, StrategyDriftSummary{Expectation: exp, Latest: latest})
+	}
+	return out, nil
+}
+
+// Evaluate stores a drift report for the strategy at now and warns when it
+// newly starts drifting.
+func (s *StrategyDriftService) Evaluate(ctx context.Context, name string, now time.Time) (*models.StrategyDriftReport, error) {
+	if s == nil || s.Repo == nil {
+		return nil, errors.New("strategy drift unavailable")
+	}
+	name = strings.TrimSpace(name)
+	exp, err := s.Repo.GetStrategyExpectation(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if exp == nil {
+		return nil, ErrNoStrategyExpectation
+	}
+	prev, err := s.Repo.GetLatestStrategyDriftReport(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	days := driftWindowDays(s.Config.Window)
+	since := now.UTC().AddDate(0, 0, -days)
+	stats, err := s.Repo.ListStrategyDailyStats(ctx, repository.ListDailyStatsParams{
+		Limit:        days + 1,
+		StrategyName: &name,
+		Since:        &since,
+	})
+	if err != nil {
+		return nil, err
+	}
+	item := driftReport(*exp, stats, days, s.Config)
+	item.EvaluatedAt = now.UTC()
+	if err := s.Repo.InsertStrategyDriftReport(ctx, item); err != nil {
+		return nil, err
+	}
+	if item.Status == models.StrategyDriftDrifting && (prev == nil || prev.Status != models.StrategyDriftDrifting) {
+		s.warnDrift(ctx, item)
+	}
+	return item, nil
+}
+
+func driftWindowDays(window time.Duration) int {
+	days := int(math.Ceil(window.Hours() / 24))
+	if days < 1 {
+		return 1
+	}
+	return days
+}
+
+// driftReport sums the window's daily rows and flags how they fall short
+// of exp. The edge is averaged over trades.
+func driftReport(exp models.StrategyExpectation, stats []models.StrategyDailyStats, days int, cfg config.StrategyDriftConfig) *models.StrategyDriftReport {
+	out := &models.StrategyDriftReport{
+		StrategyName:         exp.StrategyName,
+		WindowDays:           days,
+		ExpectedTradesPerDay: exp.TradesPerDay,
+		ExpectedWinRate:      exp.WinRate,
+		ExpectedEdgePct:      exp.EdgePct,
+	}
+	wins, losses := 0, 0
+	edge := 0.0
+	for _, st := range stats {
+		out.Trades += st.TradesCount
+		wins += st.WinCount
+		losses += st.LossCount
+		edge += st.AvgEdgePct.InexactFloat64() * float64(st.TradesCount)
+	}
+	out.TradesPerDay = float64(out.Trades) / float64(days)
+	if wins+losses > 0 {
+		out.WinRate = float64(wins) / float64(wins+losses)
+	}
+	if out.Trades > 0 {
+		out.EdgePct = edge / float64(out.Trades)
+	}
+
+	flags := []string{}
+	if exp.TradesPerDay > 0 && out.TradesPerDay < exp.TradesPerDay*(1-cfg.FrequencyDropPct) {
+		flags = append(flags, DriftTradeFrequencyCollapse)
+	}
+	enough := out.Trades >= cfg.MinTrades
+	if enough && exp.WinRate > 0 && wins+losses > 0 && out.WinRate < exp.WinRate-cfg.WinRateDrop {
+		flags = append(flags, DriftWinRateDrop)
+	}
+	if enough && exp.EdgePct > 0 && out.EdgePct < exp.EdgePct*(1-cfg.EdgeDropPct) {
+		flags = append(flags, DriftEdgeDrop)
+	}
+	raw, _ := json.Marshal(flags)
+	out.Flags = datatypes.JSON(raw)
+	switch {
+	case len(flags) > 0:
+		out.Status = models.StrategyDriftDrifting
+	case !enough:
+		out.Status = models.StrategyDriftInsufficient
+	default:
+		out.Status = models.StrategyDriftOK
+	}
+	return out
+}
+
+func (s *StrategyDriftService) warnDrift(ctx context.Context, item *models.StrategyDriftReport) {
+	var flags []string
+	_ = json.Unmarshal(item.Flags, &flags)
+	if s.Logger != nil {
+		s.Logger.Warn("strategy drifting from backtest expectation",
+			zap.String("strategy", item.StrategyName),
+			zap.Strings("flags", flags),
+		)
+	}
+	paas.LogBestEffortCtx(ctx, "polymarket_strategy_drift", "warn", map[string]any{
+		"strategy":       item.StrategyName,
+		"flags":          flags,
+		"window_days":    item.WindowDays,
+		"trades_per_day": item.TradesPerDay,
+		"win_rate":       item.WinRate,
+		"edge_pct":       item.EdgePct,
+	})
+	notify := s.Notify
+	if notify == nil {
+		notify = paas.BroadcastCtx
+	}
+	message := fmt.Sprintf("[warning] polymarket strategy %s drifting from its backtest over %d days (%s): %.2f trades/day vs %.2f, win rate %.0f%% vs %.0f%%, edge %.2f%% vs %.2f%%",
+		item.StrategyName, item.WindowDays, strings.Join(flags, ", "),
+		item.TradesPerDay, item.ExpectedTradesPerDay,
+		item.WinRate*100, item.ExpectedWinRate*100,
+		item.EdgePct*100, item.ExpectedEdgePct*100)
+	if err := notify(ctx, s.Config.NotifyEvent, message); err != nil && s.Logger != nil {
+		s.Logger.Warn("strategy drift notify failed", zap.String("strategy", item.StrategyName), zap.Error(err))
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type driftRepo struct {
+	repository.Repository
+	exp      *models.StrategyExpectation
+	stats    []models.StrategyDailyStats
+	inserted []models.StrategyDriftReport
+}
+
+func (r *driftRepo) GetStrategyExpectation(context.Context, string) (*models.StrategyExpectation, error) {
+	return r.exp, nil
+}
+
+func (r *driftRepo) GetLatestStrategyDriftReport(context.Context, string) (*models.StrategyDriftReport, error) {
+	if len(r.inserted) == 0 {
+		return nil, nil
+	}
+	latest := r.inserted[len(r.inserted)-1]
+	return &latest, nil
+}
+
+func (r *driftRepo) InsertStrategyDriftReport(_ context.Context, item *models.StrategyDriftReport) error {
+	r.inserted = append(r.inserted, *item)
+	return nil
+}
+
+func (r *driftRepo) ListStrategyDailyStats(context.Context, repository.ListDailyStatsParams) ([]models.StrategyDailyStats, error) {
+	return r.stats, nil
+}
+
+func driftDay(trades, wins, losses int, edge string) models.StrategyDailyStats {
+	return models.StrategyDailyStats{StrategyName: "arb", TradesCount: trades, WinCount: wins, LossCount: losses, AvgEdgePct: decimal.RequireFromString(edge)}
+}
+
+func TestStrategyDriftEvaluate(t *testing.T) {
+	cfg := config.StrategyDriftConfig{Window: 4 * 24 * time.Hour, MinTrades: 20, FrequencyDropPct: 0.5, WinRateDrop: 0.15, EdgeDropPct: 0.5}
+	exp := &models.StrategyExpectation{StrategyName: "arb", WinRate: 0.6, EdgePct: 0.04, TradesPerDay: 5}
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	flagsOf := func(r models.StrategyDriftReport) []string {
+		var out []string
+		_ = json.Unmarshal(r.Flags, &out)
+		return out
+	}
+
+	cases := []struct {
+		name   string
+		stats  []models.StrategyDailyStats
+		status string
+		flags  []string
+	}{
+		{"on track", []models.StrategyDailyStats{driftDay(10, 6, 4, "0.04"), driftDay(10, 6, 4, "0.05")}, models.StrategyDriftOK, []string{}},
+		{"too few trades", []models.StrategyDailyStats{driftDay(15, 0, 15, "0.001")}, models.StrategyDriftInsufficient, []string{}},
+		{"frequency collapse", []models.StrategyDailyStats{driftDay(2, 1, 1, "0.04")}, models.StrategyDriftDrifting, []string{DriftTradeFrequencyCollapse}},
+		{"win rate and edge", []models.StrategyDailyStats{driftDay(10, 3, 7, "0.01"), driftDay(10, 4, 6, "0.02")}, models.StrategyDriftDrifting, []string{DriftWinRateDrop, DriftEdgeDrop}},
+	}
+	for _, tc := range cases {
+		repo := &driftRepo{exp: exp, stats: tc.stats}
+		svc := &StrategyDriftService{Repo: repo, Config: cfg}
+		item, err := svc.Evaluate(context.Background(), "arb", now)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if item.Status != tc.status || !reflect.DeepEqual(flagsOf(*item), tc.flags) {
+			t.Fatalf("%s: status=%s flags=%v", tc.name, item.Status, flagsOf(*item))
+		}
+	}
+}
+
+func TestStrategyDriftNotifiesOnce(t *testing.T) {
+	cfg := config.StrategyDriftConfig{Window: 24 * time.Hour, MinTrades: 1, FrequencyDropPct: 0.5, NotifyEvent: "polymarket.strategy_drift"}
+	repo := &driftRepo{exp: &models.StrategyExpectation{StrategyName: "arb", TradesPerDay: 10}}
+	var events []string
+	svc := &StrategyDriftService{Repo: repo, Config: cfg, Notify: func(_ context.Context, event, _ string) error {
+		events = append(events, event)
+		return nil
+	}}
+	now := time.Now().UTC()
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Evaluate(context.Background(), "arb", now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(events) != 1 || events[0] != "polymarket.strategy_drift" {
+		t.Fatalf("events=%v", events)
+	}
+
+	repo.exp = nil
+	if _, err := svc.Evaluate(context.Background(), "arb", now); err != ErrNoStrategyExpectation {
+		t.Fatalf("err=%v", err)
+	}
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+	"polymarket/internal/strategy"
+)
+
+var ErrParamTuningInvalid = errors.New("invalid param tuning")
+
+// ParamTuningGrid lists the values to try per strategy param. Keys must be
+// params the strategy has; a param left out keeps its current value.
+type ParamTuningGrid map[string][]json.RawMessage
+
+// ParamTuningCandidate is one point of the grid, holding only the grid
+// keys, with its in-sample (Train) and out-of-sample (Test) scores.
+type ParamTuningCandidate struct {
+	Params map[string]json.RawMessage `json:"params"`
+	Train  RuleTuningScore            `json:"train"`
+	Test   RuleTuningScore            `json:"test"`
+}
+
+// StrategyParamTuner grid-searches a strategy's params. The strategy's
+// stored signals are fed one at a time to a fresh evaluator per candidate,
+// which sees order books rebuilt from candles as of each signal (see
+// strategy.ReplayRepository), and the opportunities it would have emitted
+// are priced by the rule simulator under the strategy's execution rule.
+// Candidates are ranked on the training window only; the best is proposed
+// when it also beats the current params on the held-out window, and nothing
+// is written to strategies.params until the proposal is approved.
+type StrategyParamTuner struct {
+	Repo      repository.Repository
+	Simulator *ExecutionRuleSimulator
+	Logger    *zap.Logger
+	// Outbox records the review log in the review transaction.
+	Outbox *OutboxDispatcher
+	// Defaults are the config strategy defaults the engine merges params
+	// with (config.strategy_defaults).
+	Defaults map[string]any
+	// NewEvaluator builds the evaluator to replay; nil is
+	// strategy.NewReplayEvaluator.
+	NewEvaluator func(name string, repo repository.Repository, logger *zap.Logger) (strategy.StrategyEvaluator, bool)
+	// MaxCandidates caps the grid size; 0 is 50.
+	MaxCandidates int
+	// MaxSignals caps the replayed signals; 0 is 2000.
+	MaxSignals int
+	// FrontierSize is how many candidates a run keeps; 0 is 10.
+	FrontierSize int
+}
+
+// Tune replays every grid candidate over [since, until), split at
+// trainFrac of the window, and stores the run. It returns nil when the
+// strategy does not exist.
+func (t *StrategyParamTuner) Tune(ctx context.Context, name string, grid ParamTuningGrid, since, until time.Time, trainFrac float64) (*models.StrategyParamTuning, error) {
+	if t == nil || t.Repo == nil || t.Simulator == nil {
+		return nil, errors.New("param tuner unavailable")
+	}
+	name = strings.TrimSpace(name)
+	if !since.Before(until) {
+		return nil, fmt.Errorf("%w: since must be before until", ErrParamTuningInvalid)
+	}
+	if trainFrac == 0 {
+		trainFrac = 0.7
+	}
+	if trainFrac <= 0 || trainFrac >= 1 {
+		return nil, fmt.Errorf("%w: train_fraction must be between 0 and 1", ErrParamTuningInvalid)
+	}
+	strat, err := t.Repo.GetStrategyByName(ctx, name)
+	if err != nil || strat == nil {
+		return nil, err
+	}
+	replay := &strategy.ReplayRepository{Repository: t.Repo}
+	probe, ok := t.newEvaluator(name, replay)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s cannot be replayed", ErrParamTuningInvalid, name)
+	}
+	rule, err := t.Repo.GetExecutionRuleByStrategyName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("%w: %s has no execution rule to price trades with", ErrParamTuningInvalid, name)
+	}
+	base := map[string]json.RawMessage{}
+	if err := json.Unmarshal(strategy.EffectiveParams(probe, t.Defaults, name, strat.Params), &base); err != nil {
+		return nil, err
+	}
+	candidates, err := grid.expand(base)
+	if err != nil {
+		return nil, err
+	}
+	maxCandidates := t.MaxCandidates
+	if maxCandidates <= 0 {
+		maxCandidates = 50
+	}
+	if len(candidates) > maxCandidates {
+		return nil, fmt.Errorf("%w: grid has %d candidates, max %d", ErrParamTuningInvalid, len(candidates), maxCandidates)
+	}
+	signals, err := t.signals(ctx, probe.RequiredSignals(), since, until)
+	if err != nil {
+		return nil, err
+	}
+	split := since.Add(time.Duration(float64(until.Sub(since)) * trainFrac))
+
+	current := map[string]json.RawMessage{}
+	for k := range grid {
+		current[k] = base[k]
+	}
+	baseline, err := t.score(ctx, name, *rule, base, current, signals, since, split, until)
+	if err != nil {
+		return nil, err
+	}
+	scored := make([]ParamTuningCandidate, 0, len(candidates))
+	for _, p := range candidates {
+		c, err := t.score(ctx, name, *rule, base, p, signals, since, split, until)
+		if err != nil {
+			return nil, err
+		}
+		scored = append(scored, c)
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].Train.PnLUSD != scored[j].Train.PnLUSD {
+			return scored[i].Train.PnLUSD > scored[j].Train.PnLUSD
+		}
+		return scored[i].Train.Sharpe > scored[j].Train.Sharpe
+	})
+	size := t.FrontierSize
+	if size <= 0 {
+		size = 10
+	}
+	frontier := scored
+	if len(frontier) > size {
+		frontier = frontier[:size]
+	}
+
+	run := &models.StrategyParamTuning{
+		StrategyName: name,
+		Status:       models.RuleTuningNoImprovement,
+		Since:        since,
+		SplitAt:      split,
+		Until:        until,
+		Candidates:   len(candidates),
+		Signals:      len(signals),
+		BaseParams:   mustJSON(base),
+		Grid:         mustJSON(grid),
+		Baseline:     mustJSON(baseline),
+		Frontier:     mustJSON(frontier),
+	}
+	if len(frontier) > 0 {
+		best := frontier[0]
+		if !sameParams(best.Params, baseline.Params) && best.Test.PnLUSD > baseline.Test.PnLUSD {
+			run.Status = models.RuleTuningProposed
+			run.Proposed = mustJSON(best.Params)
+		}
+	}
+	if err := t.Repo.InsertStrategyParamTuning(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (t *StrategyParamTuner) newEvaluator(name string, repo repository.Repository) (strategy.StrategyEvaluator, bool) {
+	if t.NewEvaluator != nil {
+		return t.NewEvaluator(name, repo, t.Logger)
+	}
+	return strategy.NewReplayEvaluator(name, repo, t.Logger)
+}
+
+// signals loads the stored signals of the given types created in
+// [since, until), oldest first.
+func (t *StrategyParamTuner) signals(ctx context.Context, types []string, since, until time.Time) ([]models.Signal, error) {
+	maxSignals := t.MaxSignals
+	if maxSignals <= 0 {
+		maxSignals = 2000
+	}
+	const pageSize = 500
+	var out []models.Signal
+	for _, typ := range types {
+	pages:
+		for offset := 0; ; offset += pageSize {
+			items, err := t.Repo.ListSignals(ctx, repository.ListSignalsParams{
+				Type:    &typ,
+				Since:   &since,
+				Limit:   pageSize,
+				Offset:  offset,
+				OrderBy: "created_at",
+				Asc:     boolPtrAuto(true),
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, sig := range items {
+				if !sig.CreatedAt.Before(until) {
+					break pages
+				}
+				if len(out) >= maxSignals {
+					return nil, fmt.Errorf("%w: more than %d signals in the window", ErrParamTuningInvalid, maxSignals)
+				}
+				out = append(out, sig)
+			}
+			if len(items) < pageSize {
+				break
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// score replays signals through an evaluator set to base overlaid with p
+// and prices what it emits on either side of split.
+func (t *StrategyParamTuner) score(ctx context.Context, name string, rule models.ExecutionRule, base, p map[string]json.RawMessage, signals []models.Signal, since, split, until time.Time) (ParamTuningCandidate, error) {
+	out := ParamTuningCandidate{Params: p}
+	params := map[string]json.RawMessage{}
+	for k, v := range base {
+		params[k] = v
+	}
+	for k, v := range p {
+		params[k] = v
+	}
+	replay := &strategy.ReplayRepository{Repository: t.Repo}
+	ev, _ := t.newEvaluator(name, replay)
+	if err := strategy.SetEvaluatorParams(ev, json.RawMessage(mustJSON(params))); err != nil {
+		return out, fmt.Errorf("%w: %v", ErrParamTuningInvalid, err)
+	}
+	var train, test []models.Opportunity
+	for _, sig := range signals {
+		if ctx.Err() != nil {
+			return out, ctx.Err()
+		}
+		replay.At = sig.CreatedAt
+		opps, err := ev.Evaluate(ctx, []models.Signal{sig})
+		if err != nil {
+			// The engine skips a failed batch too.
+			if t.Logger != nil {
+				t.Logger.Debug("param tuning replay evaluate failed", zap.String("strategy", name), zap.Uint64("signal_id", sig.ID), zap.Error(err))
+			}
+			continue
+		}
+		for _, opp := range opps {
+			opp.ID = 0
+			opp.CreatedAt = sig.CreatedAt
+			opp.SignalType = sig.SignalType
+			if opp.CreatedAt.Before(split) {
+				train = append(train, opp)
+			} else {
+				test = append(test, opp)
+			}
+		}
+	}
+	trainRes, err := t.Simulator.SimulateOpportunities(ctx, name, rule, train, since, split)
+	if err != nil {
+		return out, err
+	}
+	testRes, err := t.Simulator.SimulateOpportunities(ctx, name, rule, test, split, until)
+	if err != nil {
+		return out, err
+	}
+	out.Train = ruleTuningScore(trainRes)
+	out.Test = ruleTuningScore(testRes)
+	return out, nil
+}
+
+// Approve writes the run's proposed params over the strategy's stored
+// params and marks the run approved, in one transaction. Params outside the
+// grid are left as stored. It returns nil when the run does not exist.
+func (t *StrategyParamTuner) Approve(ctx context.Context, id uint64, reviewer, note string, now time.Time) (*models.StrategyParamTuning, error) {
+	return t.review(ctx, id, models.RuleTuningApproved, reviewer, note, now)
+}
+
+// Reject closes a proposed run without touching the params.
+func (t *StrategyParamTuner) Reject(ctx context.Context, id uint64, reviewer, note string, now time.Time) (*models.StrategyParamTuning, error) {
+	return t.review(ctx, id, models.RuleTuningRejected, reviewer, note, now)
+}
+
+func (t *StrategyParamTuner) review(ctx context.Context, id uint64, status, reviewer, note string, now time.Time) (*models.StrategyParamTuning, error) {
+	if t == nil || t.Repo == nil {
+		return nil, errors.New("param tuner unavailable")
+	}
+	var out *models.StrategyParamTuning
+	err := WriteWithOutbox(ctx, t.Repo, t.Outbox, func(tx *gorm.DB) ([]models.OutboxMessage, error) {
+		repo := t.Repo.WithTx(tx)
+		run, err := repo.GetStrategyParamTuning(ctx, id)
+		if err != nil || run == nil {
+			return nil, err
+		}
+		if run.Status != models.RuleTuningProposed {
+			return nil, ErrRuleTuningNotProposed
+		}
+		if status == models.RuleTuningApproved {
+			var proposed map[string]json.RawMessage
+			if err := json.Unmarshal(run.Proposed, &proposed); err != nil {
+				return nil, err
+			}
+			strat, err := repo.GetStrategyByName(ctx, run.StrategyName)
+			if err != nil {
+				return nil, err
+			}
+			if strat == nil {
+				return nil, fmt.Errorf("%w: strategy %s no longer exists", ErrParamTuningInvalid, run.StrategyName)
+			}
+			stored := map[string]json.RawMessage{}
+			if len(strat.Params) > 0 {
+				if err := json.Unmarshal(strat.Params, &stored); err != nil {
+					return nil, err
+				}
+			}
+			for k, v := range proposed {
+				stored[k] = v
+			}
+			if err := repo.UpdateStrategyParams(ctx, run.StrategyName, mustJSON(stored)); err != nil {
+				return nil, err
+			}
+		}
+		run.Status = status
+		run.ReviewedBy = strings.TrimSpace(reviewer)
+		run.ReviewNote = strings.TrimSpace(note)
+		run.ReviewedAt = &now
+		if err := repo.UpdateStrategyParamTuning(ctx, run); err != nil {
+			return nil, err
+		}
+		out = run
+		return []models.OutboxMessage{NewOutboxPaasLog(ctx, "polymarket_strategy_param_tuning_"+status, "info", map[string]any{
+			"id":       run.ID,
+			"strategy": run.StrategyName,
+			"proposed": json.RawMessage(run.Proposed),
+			"reviewer": run.ReviewedBy,
+		})}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// expand returns the cartesian product of the grid in key order. Every key
+// must be a param in base and every value valid JSON.
+func (g ParamTuningGrid) expand(base map[string]json.RawMessage) ([]map[string]json.RawMessage, error) {
+	if len(g) == 0 {
+		return nil, fmt.Errorf("%w: grid required", ErrParamTuningInvalid)
+	}
+	keys := make([]string, 0, len(g))
+	for k, values := range g {
+		if _, ok := base[k]; !ok {
+			return nil, fmt.Errorf("%w: unknown param %s", ErrParamTuningInvalid, k)
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("%w: %s has no values", ErrParamTuningInvalid, k)
+		}
+		for _, v := range values {
+			if !json.Valid(v) {
+				return nil, fmt.Errorf("%w: %s has an invalid value", ErrParamTuningInvalid, k)
+			}
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := []map[string]json.RawMessage{{}}
+	for _, k := range keys {
+		next := make([]map[string]json.RawMessage, 0, len(out)*len(g[k]))
+		for _, partial := range out {
+			for _, v := range g[k] {
+				p := make(map[string]json.RawMessage, len(partial)+1)
+				for pk, pv := range partial {
+					p[pk] = pv
+				}
+				p[k] = v
+				next = append(next, p)
+			}
+		}
+		out = next
+	}
+	return out, nil
+}
+
+// sameParams compares by value, so 10 and 10.0 are the same param.
+func sameParams(a, b map[string]json.RawMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		var x, y any
+		if json.Unmarshal(v, &x) != nil || json.Unmarshal(b[k], &y) != nil {
+			return false
+		}
+		if !reflect.DeepEqual(x, y) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/db"
+	"polymarket/internal/models"
+	gormrepository "polymarket/internal/repository/gorm"
+)
+
+func TestStrategyParamTunerSweepsAndApprovesParams(t *testing.T) {
+	conn, err := db.Open(config.DBConfig{Driver: "sqlite", DSN: "file::memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(conn)
+	if err := conn.Gorm.AutoMigrate(&models.Strategy{}, &models.ExecutionRule{}, &models.Signal{}, &models.PriceCandle{}, &models.StrategyParamTuning{}); err != nil {
+		t.Fatal(err)
+	}
+	store := gormrepository.New(conn.Gorm)
+	ctx := context.Background()
+
+	if err := store.UpsertStrategy(ctx, &models.Strategy{Name: "systematic_no", Params: datatypes.JSON(`{"stop_loss_no_price":0.9}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpsertExecutionRule(ctx, &models.ExecutionRule{
+		StrategyName: "systematic_no", MinConfidence: 0.5, MinEdgePct: decimal.NewFromFloat(0.05),
+		StopLossPct: decimal.NewFromFloat(0.2), TakeProfitPct: decimal.NewFromFloat(0.2), MaxHoldHours: 48, MaxDailyTrades: 10,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	until := time.Now().UTC().Truncate(time.Minute)
+	since := until.Add(-10 * 24 * time.Hour)
+	// Thin-EV signals are followed by the NO price falling, rich ones by it
+	// rising, in both halves of the window.
+	signal := func(at time.Time, token string, evPct, exit float64) {
+		market := "m-" + token
+		sig := &models.Signal{
+			SignalType: "no_bias", Source: "test", MarketID: &market, TokenID: &token, Strength: 0.9,
+			Payload:   datatypes.JSON(fmt.Sprintf(`{"label":"l","no_rate":0.9,"ev_pct":%v}`, evPct)),
+			CreatedAt: at,
+		}
+		if err := store.InsertSignal(ctx, sig); err != nil {
+			t.Fatal(err)
+		}
+		candle := func(start time.Time, price float64) models.PriceCandle {
+			return models.PriceCandle{TokenID: token, BucketStart: start, Open: price, High: price, Low: price, Close: price, OpenTS: start, CloseTS: start.Add(59 * time.Second)}
+		}
+		if err := store.UpsertPriceCandles(ctx, []models.PriceCandle{
+			candle(at.Add(-2*time.Minute), 0.5),
+			candle(at.Add(time.Hour), exit),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	signal(since.Add(24*time.Hour), "thin1", 12, 0.3)
+	signal(since.Add(25*time.Hour), "rich1", 30, 0.7)
+	signal(since.Add(7*24*time.Hour), "thin2", 12, 0.3)
+	signal(since.Add(7*24*time.Hour+time.Hour), "rich2", 30, 0.7)
+
+	tuner := &StrategyParamTuner{Repo: store, Simulator: &ExecutionRuleSimulator{Repo: store}}
+	grid := ParamTuningGrid{"min_ev_pct": {json.RawMessage(`10`), json.RawMessage(`20`)}}
+	run, err := tuner.Tune(ctx, "systematic_no", grid, since, until, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != models.RuleTuningProposed || run.Candidates != 2 || run.Signals != 4 {
+		t.Fatalf("run = %+v", run)
+	}
+	var baseline ParamTuningCandidate
+	_ = json.Unmarshal(run.Baseline, &baseline)
+	if baseline.Train.Trades != 2 || baseline.Test.Trades != 2 {
+		t.Fatalf("baseline = %+v, want both signals traded per window", baseline)
+	}
+	var proposed map[string]json.RawMessage
+	_ = json.Unmarshal(run.Proposed, &proposed)
+	if string(proposed["min_ev_pct"]) != "20" {
+		t.Fatalf("proposed = %s", run.Proposed)
+	}
+
+	if _, err := tuner.Tune(ctx, "systematic_no", ParamTuningGrid{"nope": {json.RawMessage(`1`)}}, since, until, 0.5); err == nil {
+		t.Fatal("expected an unknown param to be rejected")
+	}
+
+	approved, err := tuner.Approve(ctx, run.ID, "ops", "ship it", until)
+	if err != nil || approved == nil || approved.Status != models.RuleTuningApproved {
+		t.Fatalf("approved = %+v, %v", approved, err)
+	}
+	strat, err := store.GetStrategyByName(ctx, "systematic_no")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var params map[string]float64
+	_ = json.Unmarshal(strat.Params, &params)
+	if params["min_ev_pct"] != 20 || params["stop_loss_no_price"] != 0.9 {
+		t.Fatalf("params = %s, want min_ev_pct written over the stored params", strat.Params)
+	}
+	if _, err := tuner.Reject(ctx, run.ID, "ops", "", until); !errors.Is(err, ErrRuleTuningNotProposed) {
+		t.Fatalf("err = %v, want ErrRuleTuningNotProposed", err)
+	}
+}
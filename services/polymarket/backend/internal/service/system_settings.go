@@ -22,14 +22,19 @@ const (
 	FeaturePositionSync       = "feature.position_sync"
 	FeaturePortfolioSnapshot  = "feature.portfolio_snapshot"
 	FeaturePositionManager    = "feature.position_manager"
+	FeaturePositionImport     = "feature.position_import"
 	FeatureDailyStats         = "feature.daily_stats"
 	FeatureMarketReview       = "feature.market_review"
+	FeatureMarketDataBackfill = "feature.market_data_backfill"
+	FeatureRetention          = "feature.retention"
 	FeatureSignalBinanceWS    = "feature.signal.binance_ws"
 	FeatureSignalBinancePrice = "feature.signal.binance_price"
 	FeatureSignalWeatherAPI   = "feature.signal.weather_api"
 	FeatureSignalPriceChange  = "feature.signal.price_change"
 	FeatureSignalOrderbook    = "feature.signal.orderbook_pattern"
 	FeatureSignalCertainty    = "feature.signal.certainty_sweep"
+	FeatureSignalSmartMoney   = "feature.signal.smart_money"
+	FeatureSignalMarketClose  = "feature.signal.market_close"
 )
 
 func DefaultFeatureSwitches() map[string]bool {
@@ -43,19 +48,26 @@ func DefaultFeatureSwitches() map[string]bool {
 		FeaturePositionSync:       true,
 		FeaturePortfolioSnapshot:  true,
 		FeaturePositionManager:    false,
+		FeaturePositionImport:     false, // reconciles positions of wallets in position_import.wallets
 		FeatureDailyStats:         true,
 		FeatureMarketReview:       true,
+		FeatureMarketDataBackfill: true,
+		FeatureRetention:          false, // purges rows past their retention.<table> window
 		FeatureSignalBinanceWS:    false,
 		FeatureSignalBinancePrice: false,
 		FeatureSignalWeatherAPI:   false,
 		FeatureSignalPriceChange:  true,  // internal DB poller — feeds news_alpha, volatility_spread
 		FeatureSignalOrderbook:    true,  // internal DB poller — feeds fear_spike, mm_inventory_skew
 		FeatureSignalCertainty:    true,  // internal DB poller — feeds certainty_sweep
+		FeatureSignalSmartMoney:   false, // polls tracked wallets — feeds copy_flow
+		FeatureSignalMarketClose:  true,  // internal DB poller — feeds certainty_sweep ladder
 	}
 }
 
 type SystemSettingsService struct {
 	Repo repository.Repository
+	// Cache, when set, serves IsEnabled from memory.
+	Cache *SettingsCache
 }
 
 func (s *SystemSettingsService) EnsureDefaultSwitches(ctx context.Context) error {
@@ -107,6 +119,9 @@ func (s *SystemSettingsService) IsEnabled(ctx context.Context, key string, fallb
 	if key == "" {
 		return fallback
 	}
+	if s.Cache != nil {
+		return s.Cache.Bool(ctx, key, fallback)
+	}
 	item, err := s.Repo.GetSystemSettingByKey(ctx, key)
 	if err != nil || item == nil || len(item.Value) == 0 {
 		return fallback
@@ -133,5 +148,18 @@ func (s *SystemSettingsService) SetEnabled(ctx context.Context, key string, enab
 		Description: "feature switch",
 		UpdatedAt:   time.Now().UTC(),
 	}
-	return s.Repo.UpsertSystemSetting(ctx, item)
+	if err := s.Repo.UpsertSystemSetting(ctx, item); err != nil {
+		return err
+	}
+	s.Cache.Put(key, item.Value)
+	return nil
+}
+
+// Changed records a setting written outside SetEnabled so cached readers
+// see it immediately.
+func (s *SystemSettingsService) Changed(key string, raw datatypes.JSON) {
+	if s == nil {
+		return
+	}
+	s.Cache.Put(key, raw)
 }
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/config"
+	"polymarket/internal/events"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+const (
+	// TradeWebhookSecretKey is the setting key trade webhook secrets are
+	// protected under; it names a secret so ProtectSettingValue encrypts it.
+	TradeWebhookSecretKey = "trade_webhook.secret"
+
+	tradeWebhookPingType    = "ping"
+	tradeWebhookCacheTTL    = 30 * time.Second
+	tradePlanStatusExecuted = "executed"
+)
+
+// TradeEventTypes lists the events a trade webhook can subscribe to.
+var TradeEventTypes = []string{models.TradeEventOrderFilled, models.TradeEventPlanExecuted, models.TradeEventPositionClosed}
+
+// TradeWebhookService tells external risk and compliance systems about
+// fills, executed plans and closed positions. Run turns the repository's
+// row-change events into one delivery per matching subscription; the
+// deliveries are sent through the outbox, which retries them, and every
+// attempt is logged on the delivery.
+type TradeWebhookService struct {
+	Repo   repository.Repository
+	Config config.TradeWebhooksConfig
+	Outbox *OutboxDispatcher
+	Client *http.Client
+	Logger *zap.Logger
+	// Events carries fill_created, plan_status and position_closed.
+	Events <-chan events.Event
+
+	mu       sync.Mutex
+	cached   []models.TradeWebhook
+	cachedAt time.Time
+}
+
+// TradeWebhookPayload is the JSON body of a delivery. Data is a
+// TradeFillData, TradePlanData or TradePositionData by Type.
+type TradeWebhookPayload struct {
+	Type       string    `json:"type"`
+	WebhookID  uint64    `json:"webhook_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+type TradeFillData struct {
+	FillID     uint64          `json:"fill_id"`
+	PlanID     uint64          `json:"plan_id"`
+	Strategy   string          `json:"strategy,omitempty"`
+	Tenant     string          `json:"tenant,omitempty"`
+	TokenID    string          `json:"token_id"`
+	Direction  string          `json:"direction"`
+	FilledSize decimal.Decimal `json:"filled_size"`
+	AvgPrice   decimal.Decimal `json:"avg_price"`
+	Fee        decimal.Decimal `json:"fee"`
+	FilledAt   time.Time       `json:"filled_at"`
+}
+
+type TradePlanData struct {
+	PlanID         uint64          `json:"plan_id"`
+	Strategy       string          `json:"strategy"`
+	Tenant         string          `json:"tenant"`
+	Source         string          `json:"source"`
+	PlannedSizeUSD decimal.Decimal `json:"planned_size_usd"`
+	ExecutedAt     *time.Time      `json:"executed_at"`
+}
+
+type TradePositionData struct {
+	PositionID  uint64          `json:"position_id"`
+	Strategy    string          `json:"strategy,omitempty"`
+	Tenant      string          `json:"tenant"`
+	TokenID     string          `json:"token_id"`
+	MarketID    string          `json:"market_id"`
+	Direction   string          `json:"direction"`
+	RealizedPnL decimal.Decimal `json:"realized_pnl"`
+	ClosedAt    time.Time       `json:"closed_at"`
+}
+
+// tradeEvent is a resolved row change: what to send and who may see it.
+type tradeEvent struct {
+	Type       string
+	Key        string
+	Tenant     string
+	Strategy   string
+	OccurredAt time.Time
+	Data       any
+}
+
+// Enabled reports whether events are turned into deliveries.
+func (s *TradeWebhookService) Enabled() bool {
+	return s != nil && s.Config.Enabled && s.Repo != nil
+}
+
+// Invalidate drops the cached subscription list after an edit.
+func (s *TradeWebhookService) Invalidate() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.cached = nil
+	s.cachedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *TradeWebhookService) subscriptions(ctx context.Context) ([]models.TradeWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.cachedAt.IsZero() && time.Since(s.cachedAt) < tradeWebhookCacheTTL {
+		return s.cached, nil
+	}
+	hooks, err := s.Repo.ListTradeWebhooks(ctx, repository.ListTradeWebhooksParams{EnabledOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	s.cached = hooks
+	s.cachedAt = time.Now()
+	return hooks, nil
+}
+
+// Run queues deliveries for the events on Events until ctx is done.
+func (s *TradeWebhookService) Run(ctx context.Context) {
+	if !s.Enabled() || s.Events == nil {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-s.Events:
+			if _, err := s.Publish(ctx, ev); err != nil && ctx.Err() == nil {
+				s.warn("trade webhooks: publish", zap.String("topic", ev.Topic), zap.Uint64("id", ev.ID), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Publish queues a delivery of ev to every matching subscription and
+// returns how many were queued. Each replica sees the event, but a webhook
+// gets one delivery per event: the first replica to record it sends it.
+func (s *TradeWebhookService) Publish(ctx context.Context, ev events.Event) (int, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+	hooks, err := s.subscriptions(ctx)
+	if err != nil || len(hooks) == 0 {
+		return 0, err
+	}
+	te, err := s.resolve(ctx, ev)
+	if err != nil || te == nil {
+		return 0, err
+	}
+	queued := 0
+	for _, hook := range hooks {
+		if !wantsTradeEvent(hook, *te) {
+			continue
+		}
+		body, err := json.Marshal(TradeWebhookPayload{Type: te.Type, WebhookID: hook.ID, OccurredAt: te.OccurredAt, Data: te.Data})
+		if err != nil {
+			return queued, err
+		}
+		delivery := &models.TradeWebhookDelivery{WebhookID: hook.ID, EventType: te.Type, EventKey: te.Key, Payload: datatypes.JSON(body)}
+		var inserted bool
+		err = s.Repo.InTx(ctx, func(tx *gorm.DB) error {
+			var err error
+			if inserted, err = s.Repo.InsertTradeWebhookDeliveryTx(ctx, tx, delivery); err != nil || !inserted {
+				return err
+			}
+			if s.Outbox.Enabled() {
+				return s.Repo.InsertOutboxMessagesTx(ctx, tx, []models.OutboxMessage{NewOutboxTradeWebhook(delivery.ID)})
+			}
+			return nil
+		})
+		if err != nil {
+			return queued, err
+		}
+		if !inserted {
+			continue
+		}
+		queued++
+		if !s.Outbox.Enabled() {
+			if err := s.DeliverQueued(ctx, delivery.ID); err != nil {
+				s.warn("trade webhooks: delivery failed", zap.Uint64("delivery_id", delivery.ID), zap.Error(err))
+			}
+		}
+	}
+	return queued, nil
+}
+
+// resolve loads the row behind ev; nil when ev is not a trade event.
+func (s *TradeWebhookService) resolve(ctx context.Context, ev events.Event) (*tradeEvent, error) {
+	switch ev.Topic {
+	case events.TopicFillCreated:
+		fill, err := s.Repo.GetFillByID(ctx, ev.ID)
+		if err != nil || fill == nil {
+			return nil, err
+		}
+		data := TradeFillData{
+			FillID:     fill.ID,
+			PlanID:     fill.PlanID,
+			TokenID:    fill.TokenID,
+			Direction:  fill.Direction,
+			FilledSize: fill.FilledSize,
+			AvgPrice:   fill.AvgPrice,
+			Fee:        fill.Fee,
+			FilledAt:   fill.FilledAt.UTC(),
+		}
+		if plan, err := s.Repo.GetExecutionPlanByID(ctx, fill.PlanID); err != nil {
+			return nil, err
+		} else if plan != nil {
+			data.Strategy, data.Tenant = plan.StrategyName, plan.Tenant
+		}
+		return &tradeEvent{
+			Type: models.TradeEventOrderFilled, Key: fmt.Sprintf("fill:%d", fill.ID),
+			Tenant: data.Tenant, Strategy: data.Strategy, OccurredAt: data.FilledAt, Data: data,
+		}, nil
+	case events.TopicPlanStatus:
+		if ev.Status != "" && ev.Status != tradePlanStatusExecuted {
+			return nil, nil
+		}
+		plan, err := s.Repo.GetExecutionPlanByID(ctx, ev.ID)
+		if err != nil || plan == nil || plan.Status != tradePlanStatusExecuted {
+			return nil, err
+		}
+		at := plan.UpdatedAt.UTC()
+		if plan.ExecutedAt != nil {
+			at = plan.ExecutedAt.UTC()
+		}
+		return &tradeEvent{
+			Type: models.TradeEventPlanExecuted, Key: fmt.Sprintf("plan:%d", plan.ID),
+			Tenant: plan.Tenant, Strategy: plan.StrategyName, OccurredAt: at,
+			Data: TradePlanData{
+				PlanID:         plan.ID,
+				Strategy:       plan.StrategyName,
+				Tenant:         plan.Tenant,
+				Source:         plan.Source,
+				PlannedSizeUSD: plan.PlannedSizeUSD,
+				ExecutedAt:     plan.ExecutedAt,
+			},
+		}, nil
+	case events.TopicPositionClosed:
+		pos, err := s.Repo.GetPositionByID(ctx, ev.ID)
+		if err != nil || pos == nil || pos.Status != "closed" {
+			return nil, err
+		}
+		at := pos.UpdatedAt.UTC()
+		if pos.ClosedAt != nil {
+			at = pos.ClosedAt.UTC()
+		}
+		// A token's position row is reused when it reopens, so each close
+		// is its own event.
+		return &tradeEvent{
+			Type: models.TradeEventPositionClosed, Key: fmt.Sprintf("position:%d:%d", pos.ID, at.Unix()),
+			Tenant: pos.Tenant, Strategy: pos.StrategyName, OccurredAt: at,
+			Data: TradePositionData{
+				PositionID:  pos.ID,
+				Strategy:    pos.StrategyName,
+				Tenant:      pos.Tenant,
+				TokenID:     pos.TokenID,
+				MarketID:    pos.MarketID,
+				Direction:   pos.Direction,
+				RealizedPnL: pos.RealizedPnL,
+				ClosedAt:    at,
+			},
+		}, nil
+	}
+	return nil, nil
+}
+
+// wantsTradeEvent applies the webhook's desk, event type and strategy
+// filters.
+func wantsTradeEvent(hook models.TradeWebhook, te tradeEvent) bool {
+	if hook.Tenant != "" && !strings.EqualFold(hook.Tenant, te.Tenant) {
+		return false
+	}
+	if types := lowerList(decodeStringList(hook.EventTypes)); len(types) > 0 && !intersects(types, []string{te.Type}) {
+		return false
+	}
+	if strategies := lowerList(decodeStringList(hook.Strategies)); len(strategies) > 0 && !intersects(strategies, []string{strings.ToLower(te.Strategy)}) {
+		return false
+	}
+	return true
+}
+
+// OutboxTradeWebhookPayload is the body of a trade webhook outbox message.
+type OutboxTradeWebhookPayload struct {
+	DeliveryID uint64 `json:"delivery_id"`
+}
+
+func NewOutboxTradeWebhook(deliveryID uint64) models.OutboxMessage {
+	return newOutboxMessage(models.OutboxKindTradeWebhook, OutboxTradeWebhookPayload{DeliveryID: deliveryID})
+}
+
+// DeliverQueued sends a queued delivery once and logs the attempt. A
+// delivery whose webhook was deleted or disabled is dropped.
+func (s *TradeWebhookService) DeliverQueued(ctx context.Context, deliveryID uint64) error {
+	if s == nil || s.Repo == nil {
+		return errors.New("trade webhooks unavailable")
+	}
+	delivery, err := s.Repo.GetTradeWebhookDelivery(ctx, deliveryID)
+	if err != nil || delivery == nil || delivery.Status == models.TradeDeliveryDelivered {
+		return err
+	}
+	hook, err := s.Repo.GetTradeWebhookByID(ctx, delivery.WebhookID)
+	if err != nil || hook == nil || !hook.Enabled {
+		return err
+	}
+	status, errMsg := s.post(ctx, *hook, delivery.ID, delivery.EventType, delivery.Payload)
+	if err := s.Repo.RecordTradeWebhookAttempt(ctx, delivery.ID, status, errMsg, time.Now().UTC()); err != nil {
+		s.warn("trade webhooks: record attempt", zap.Uint64("delivery_id", delivery.ID), zap.Error(err))
+	}
+	if errMsg != "" {
+		return errors.New(errMsg)
+	}
+	return nil
+}
+
+// Ping sends a signed ping to hook without logging a delivery.
+func (s *TradeWebhookService) Ping(ctx context.Context, hook models.TradeWebhook) CatalogDeliveryResult {
+	body, _ := json.Marshal(TradeWebhookPayload{Type: tradeWebhookPingType, WebhookID: hook.ID, OccurredAt: time.Now().UTC(), Data: map[string]any{}})
+	res := CatalogDeliveryResult{DeliveryID: newDeliveryID()}
+	res.Status, res.Error = s.post(ctx, hook, 0, tradeWebhookPingType, body)
+	return res
+}
+
+func (s *TradeWebhookService) post(ctx context.Context, hook models.TradeWebhook, deliveryID uint64, eventType string, body []byte) (int, string) {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	secret := string(RevealSettingValue(TradeWebhookSecretKey, []byte(hook.Secret)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", strconv.FormatUint(hook.ID, 10))
+	req.Header.Set("X-Webhook-Event", eventType)
+	// Receivers dedupe retries on the delivery ID.
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(deliveryID, 10))
+	req.Header.Set("X-Webhook-Timestamp", ts)
+	req.Header.Set("X-Webhook-Signature", SignCatalogWebhook(secret, ts, body))
+
+	client := s.Client
+	if client == nil {
+		timeout := s.Config.Timeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, ""
+}
+
+func (s *TradeWebhookService) warn(msg string, fields ...zap.Field) {
+	if s.Logger != nil {
+		s.Logger.Warn(msg, fields...)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"polymarket/internal/config"
+	"polymarket/internal/events"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type tradeWebhookRepo struct {
+	repository.Repository
+	hooks      []models.TradeWebhook
+	fill       models.Fill
+	plan       models.ExecutionPlan
+	deliveries map[uint64]*models.TradeWebhookDelivery
+	attempts   map[uint64]int
+}
+
+func (r *tradeWebhookRepo) InTx(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+func (r *tradeWebhookRepo) ListTradeWebhooks(ctx context.Context, params repository.ListTradeWebhooksParams) ([]models.TradeWebhook, error) {
+	return r.hooks, nil
+}
+
+func (r *tradeWebhookRepo) GetTradeWebhookByID(ctx context.Context, id uint64) (*models.TradeWebhook, error) {
+	for i := range r.hooks {
+		if r.hooks[i].ID == id {
+			return &r.hooks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *tradeWebhookRepo) GetFillByID(ctx context.Context, id uint64) (*models.Fill, error) {
+	if id != r.fill.ID {
+		return nil, nil
+	}
+	return &r.fill, nil
+}
+
+func (r *tradeWebhookRepo) GetExecutionPlanByID(ctx context.Context, id uint64) (*models.ExecutionPlan, error) {
+	return &r.plan, nil
+}
+
+func (r *tradeWebhookRepo) InsertTradeWebhookDeliveryTx(ctx context.Context, tx *gorm.DB, item *models.TradeWebhookDelivery) (bool, error) {
+	for _, d := range r.deliveries {
+		if d.WebhookID == item.WebhookID && d.EventKey == item.EventKey {
+			return false, nil
+		}
+	}
+	item.ID = uint64(len(r.deliveries) + 1)
+	r.deliveries[item.ID] = item
+	return true, nil
+}
+
+func (r *tradeWebhookRepo) GetTradeWebhookDelivery(ctx context.Context, id uint64) (*models.TradeWebhookDelivery, error) {
+	return r.deliveries[id], nil
+}
+
+func (r *tradeWebhookRepo) RecordTradeWebhookAttempt(ctx context.Context, deliveryID uint64, status int, errMsg string, at time.Time) error {
+	r.attempts[deliveryID] = status
+	return nil
+}
+
+func TestTradeWebhookPublishFiltersAndSigns(t *testing.T) {
+	type post struct {
+		hook, event, sig string
+		body             []byte
+		ts               string
+	}
+	var posts []post
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		posts = append(posts, post{
+			hook:  r.Header.Get("X-Webhook-Id"),
+			event: r.Header.Get("X-Webhook-Event"),
+			sig:   r.Header.Get("X-Webhook-Signature"),
+			ts:    r.Header.Get("X-Webhook-Timestamp"),
+			body:  body,
+		})
+	}))
+	defer srv.Close()
+
+	repo := &tradeWebhookRepo{
+		hooks: []models.TradeWebhook{
+			{ID: 1, URL: srv.URL, Secret: "s1", Enabled: true},
+			{ID: 2, URL: srv.URL, Secret: "s2", Enabled: true, Tenant: "other"},
+			{ID: 3, URL: srv.URL, Secret: "s3", Enabled: true, EventTypes: datatypes.JSON(`["position.closed"]`)},
+			{ID: 4, URL: srv.URL, Secret: "s4", Enabled: true, Strategies: datatypes.JSON(`["Arb_Sum"]`)},
+		},
+		fill:       models.Fill{ID: 9, PlanID: 5, TokenID: "t", Direction: "BUY", FilledSize: decimal.NewFromInt(10), AvgPrice: decimal.RequireFromString("0.4"), FilledAt: time.Now()},
+		plan:       models.ExecutionPlan{ID: 5, StrategyName: "arb_sum", Tenant: "desk"},
+		deliveries: map[uint64]*models.TradeWebhookDelivery{},
+		attempts:   map[uint64]int{},
+	}
+	svc := &TradeWebhookService{Repo: repo, Config: config.TradeWebhooksConfig{Enabled: true}}
+
+	ev := events.Event{Topic: events.TopicFillCreated, ID: 9, PlanID: 5}
+	n, err := svc.Publish(context.Background(), ev)
+	if err != nil || n != 2 {
+		t.Fatalf("queued=%d err=%v", n, err)
+	}
+	if len(posts) != 2 || posts[0].hook != "1" || posts[1].hook != "4" {
+		t.Fatalf("posts=%+v", posts)
+	}
+	for i, p := range posts {
+		secret := map[string]string{"1": "s1", "4": "s4"}[p.hook]
+		if p.event != models.TradeEventOrderFilled || p.sig != SignCatalogWebhook(secret, p.ts, p.body) {
+			t.Fatalf("post %d event=%q bad signature", i, p.event)
+		}
+		var payload struct {
+			Type string        `json:"type"`
+			Data TradeFillData `json:"data"`
+		}
+		if err := json.Unmarshal(p.body, &payload); err != nil || payload.Data.FillID != 9 || payload.Data.Strategy != "arb_sum" {
+			t.Fatalf("payload=%s err=%v", p.body, err)
+		}
+	}
+	if repo.attempts[1] != http.StatusOK || repo.attempts[2] != http.StatusOK {
+		t.Fatalf("attempts=%v", repo.attempts)
+	}
+
+	// The same fill seen again, e.g. by another replica, is not resent.
+	if n, err := svc.Publish(context.Background(), ev); err != nil || n != 0 || len(posts) != 2 {
+		t.Fatalf("republish queued=%d err=%v posts=%d", n, err, len(posts))
+	}
+}
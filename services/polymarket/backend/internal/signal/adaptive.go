@@ -0,0 +1,177 @@
+package signal
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/config"
+	"polymarket/internal/repository"
+)
+
+// neutralActivity keeps collectors at their configured interval; it is used
+// until the first sample and whenever there is no data to judge by.
+const neutralActivity = 0.5
+
+// AdaptiveScheduler samples market activity and turns it into poll intervals
+// for the hub's collectors: busy markets are polled faster, quiet ones slower,
+// within each collector's configured bounds.
+type AdaptiveScheduler struct {
+	Repo   repository.Repository
+	Logger *zap.Logger
+	Config config.AdaptiveScheduleConfig
+
+	mu       sync.RWMutex
+	activity float64
+	sampled  bool
+}
+
+// Activity is the last sampled activity in [0,1].
+func (s *AdaptiveScheduler) Activity() float64 {
+	if s == nil {
+		return neutralActivity
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.sampled {
+		return neutralActivity
+	}
+	return s.activity
+}
+
+// Interval is how long the named collector should wait before its next poll.
+// Collectors without bounds, or with the scheduler disabled, keep base.
+func (s *AdaptiveScheduler) Interval(name string, base time.Duration) time.Duration {
+	if s == nil || !s.Config.Enabled || base <= 0 {
+		return base
+	}
+	bounds, ok := s.Config.Collectors[name]
+	if !ok {
+		return base
+	}
+	return scaleInterval(base, bounds, s.Activity())
+}
+
+// Run samples activity every SampleInterval until ctx ends.
+func (s *AdaptiveScheduler) Run(ctx context.Context) error {
+	if s == nil || !s.Config.Enabled || s.Repo == nil {
+		return nil
+	}
+	interval := s.Config.SampleInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	s.Sample(ctx, time.Now().UTC())
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			s.Sample(ctx, time.Now().UTC())
+		}
+	}
+}
+
+// Sample refreshes activity from market data updated within Window. A failed
+// or empty sample falls back to neutral so collectors return to base.
+func (s *AdaptiveScheduler) Sample(ctx context.Context, now time.Time) float64 {
+	window := s.Config.Window
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+	activity := neutralActivity
+	sample, err := s.Repo.MarketActivity(ctx, now.Add(-window), s.Config.JumpBps)
+	if err != nil {
+		if s.Logger != nil {
+			s.Logger.Warn("adaptive scheduler sample failed", zap.Error(err))
+		}
+	} else if sample.Tokens > 0 {
+		jump := float64(sample.Jumping) / float64(sample.Tokens)
+		trade := float64(sample.Traded) / float64(sample.Tokens)
+		activity = max(
+			shareScore(jump, s.Config.QuietJumpShare, s.Config.HotJumpShare),
+			shareScore(trade, s.Config.QuietTradeShare, s.Config.HotTradeShare),
+		)
+	}
+
+	s.mu.Lock()
+	prev, had := s.activity, s.sampled
+	s.activity = activity
+	s.sampled = true
+	s.mu.Unlock()
+	if s.Logger != nil && (!had || math.Abs(activity-prev) >= 0.25) {
+		s.Logger.Info("adaptive scheduler activity changed",
+			zap.Float64("activity", activity),
+			zap.Int64("tokens", sample.Tokens),
+			zap.Int64("jumping", sample.Jumping),
+			zap.Int64("traded", sample.Traded),
+		)
+	}
+	return activity
+}
+
+// shareScore maps share onto [0,1]: at or below quiet is 0, at or above hot
+// is 1, linear in between.
+func shareScore(share, quiet, hot float64) float64 {
+	if hot <= quiet {
+		if share > quiet {
+			return 1
+		}
+		return 0
+	}
+	switch {
+	case share <= quiet:
+		return 0
+	case share >= hot:
+		return 1
+	}
+	return (share - quiet) / (hot - quiet)
+}
+
+// scaleInterval moves base toward bounds.MaxInterval as activity falls below
+// neutral and toward bounds.MinInterval as it rises above. Missing bounds
+// default to base.
+func scaleInterval(base time.Duration, bounds config.CollectorBoundsConfig, activity float64) time.Duration {
+	lo, hi := bounds.MinInterval, bounds.MaxInterval
+	if lo <= 0 || lo > base {
+		lo = base
+	}
+	if hi < base {
+		hi = base
+	}
+	if activity >= neutralActivity {
+		f := (activity - neutralActivity) / (1 - neutralActivity)
+		return base - time.Duration(f*float64(base-lo))
+	}
+	f := (neutralActivity - activity) / neutralActivity
+	return base + time.Duration(f*float64(hi-base))
+}
+
+// pacing is embedded by polling collectors so the hub can hand them its
+// scheduler. Without one, collectors poll at their configured interval.
+type pacing struct {
+	scheduler *AdaptiveScheduler
+}
+
+func (p *pacing) setScheduler(s *AdaptiveScheduler) { p.scheduler = s }
+
+// every calls poll after each interval until ctx ends. The interval is asked
+// for again before every wait, so pacing follows activity.
+func (p *pacing) every(ctx context.Context, name string, base time.Duration, poll func()) error {
+	t := time.NewTimer(p.scheduler.Interval(name, base))
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			poll()
+			t.Reset(p.scheduler.Interval(name, base))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package signal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/repository"
+)
+
+type activityRepo struct {
+	repository.Repository
+	sample repository.MarketActivity
+}
+
+func (r *activityRepo) MarketActivity(_ context.Context, _ time.Time, _ float64) (repository.MarketActivity, error) {
+	return r.sample, nil
+}
+
+func TestAdaptiveScheduler_Interval(t *testing.T) {
+	repo := &activityRepo{}
+	s := &AdaptiveScheduler{Repo: repo, Config: config.AdaptiveScheduleConfig{
+		Enabled:         true,
+		QuietJumpShare:  0.01,
+		HotJumpShare:    0.1,
+		QuietTradeShare: 0.05,
+		HotTradeShare:   0.4,
+		Collectors: map[string]config.CollectorBoundsConfig{
+			"price_change": {MinInterval: 2 * time.Second, MaxInterval: 30 * time.Second},
+		},
+	}}
+	base := 5 * time.Second
+	now := time.Now().UTC()
+
+	// Before any sample collectors keep their base interval.
+	if got := s.Interval("price_change", base); got != base {
+		t.Fatalf("unsampled interval=%v", got)
+	}
+
+	repo.sample = repository.MarketActivity{Tokens: 100, Jumping: 20, Traded: 0}
+	s.Sample(context.Background(), now)
+	if got := s.Interval("price_change", base); got != 2*time.Second {
+		t.Fatalf("hot interval=%v", got)
+	}
+	if got := s.Interval("weather_api", base); got != base {
+		t.Fatalf("unbounded collector should keep base, got %v", got)
+	}
+
+	repo.sample = repository.MarketActivity{Tokens: 100, Jumping: 0, Traded: 2}
+	s.Sample(context.Background(), now)
+	if got := s.Interval("price_change", base); got != 30*time.Second {
+		t.Fatalf("quiet interval=%v", got)
+	}
+
+	// No data is treated as neutral rather than quiet.
+	repo.sample = repository.MarketActivity{}
+	s.Sample(context.Background(), now)
+	if got := s.Interval("price_change", base); got != base {
+		t.Fatalf("neutral interval=%v", got)
+	}
+
+	s.Config.Enabled = false
+	repo.sample = repository.MarketActivity{Tokens: 100, Jumping: 50}
+	s.Sample(context.Background(), now)
+	if got := s.Interval("price_change", base); got != base {
+		t.Fatalf("disabled interval=%v", got)
+	}
+}
+
+func TestScaleInterval_Midpoints(t *testing.T) {
+	bounds := config.CollectorBoundsConfig{MinInterval: 2 * time.Second, MaxInterval: 20 * time.Second}
+	if got := scaleInterval(10*time.Second, bounds, 0.75); got != 6*time.Second {
+		t.Fatalf("got %v", got)
+	}
+	if got := scaleInterval(10*time.Second, bounds, 0.25); got != 15*time.Second {
+		t.Fatalf("got %v", got)
+	}
+	// Bounds that exclude base are widened to include it.
+	if got := scaleInterval(10*time.Second, config.CollectorBoundsConfig{MinInterval: 30 * time.Second}, 1); got != 10*time.Second {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"polymarket/internal/config"
 	"polymarket/internal/models"
 )
 
@@ -28,6 +29,14 @@ type BinancePriceCollector struct {
 	WindowSeconds int
 	TriggerPct    float64
 
+	// Symbol is the Binance pair being polled, added to the payload when set.
+	Symbol string
+	// Fanout, when set, replaces each triggered signal with the signals it
+	// returns (CryptoSymbolCollector turns one move into one signal per market).
+	Fanout func(sig models.Signal, price float64) []models.Signal
+
+	pacing
+
 	mu        sync.Mutex
 	lastPoll  *time.Time
 	lastError *string
@@ -70,16 +79,41 @@ func (c *BinancePriceCollector) Start(ctx context.Context, out chan<- models.Sig
 	// Run immediately once.
 	c.pollOnce(ctx, out)
 
-	t := time.NewTicker(interval)
-	defer t.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-t.C:
-			c.pollOnce(ctx, out)
-		}
+	return c.every(ctx, c.Name(), interval, func() {
+		c.pollOnce(ctx, out)
+	})
+}
+
+func (c *BinancePriceCollector) settings() config.BinancePriceConfig {
+	return config.BinancePriceConfig{
+		Endpoint:      c.Endpoint,
+		PollInterval:  c.PollInterval,
+		WindowSeconds: c.WindowSeconds,
+		TriggerPct:    c.TriggerPct,
+	}
+}
+
+func (c *BinancePriceCollector) Settings() map[string]any { return settingsMap(c.settings()) }
+
+// WithSettings returns a fresh collector; its price window starts empty.
+func (c *BinancePriceCollector) WithSettings(raw json.RawMessage) (SignalCollector, error) {
+	cfg, err := mergeSettings(c.settings(), raw)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(cfg.Endpoint) == "" {
+		return nil, fmt.Errorf("%w: endpoint required", ErrInvalidSettings)
 	}
+	return &BinancePriceCollector{
+		HTTP:          c.HTTP,
+		Logger:        c.Logger,
+		Endpoint:      cfg.Endpoint,
+		PollInterval:  cfg.PollInterval,
+		WindowSeconds: cfg.WindowSeconds,
+		TriggerPct:    cfg.TriggerPct,
+		Symbol:        c.Symbol,
+		Fanout:        c.Fanout,
+	}, nil
 }
 
 func (c *BinancePriceCollector) Stop() error { return nil }
@@ -172,6 +206,9 @@ func (c *BinancePriceCollector) pollOnce(ctx context.Context, out chan<- models.
 		"base_timestamp":   base.ts.Format(time.RFC3339Nano),
 		"sample_timestamp": now.Format(time.RFC3339Nano),
 	}
+	if c.Symbol != "" {
+		payload["symbol"] = c.Symbol
+	}
 	raw, _ := json.Marshal(payload)
 
 	expires := now.Add(time.Duration(window) * time.Second)
@@ -184,15 +221,26 @@ func (c *BinancePriceCollector) pollOnce(ctx context.Context, out chan<- models.
 		ExpiresAt:  &expires,
 		CreatedAt:  now,
 	}
-	select {
-	case out <- sig:
-	default:
+	sigs := []models.Signal{sig}
+	if c.Fanout != nil {
+		sigs = c.Fanout(sig, price)
+	}
+	for _, s := range sigs {
+		select {
+		case out <- s:
+		default:
+		}
 	}
 }
 
 func (c *BinancePriceCollector) fetchPrice(ctx context.Context, endpoint string) (float64, error) {
+	return FetchTickerPrice(ctx, c.HTTP, endpoint)
+}
+
+// FetchTickerPrice reads the last price from a Binance ticker/price endpoint.
+func FetchTickerPrice(ctx context.Context, client *http.Client, endpoint string) (float64, error) {
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
-	resp, err := c.HTTP.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return 0, err
 	}
@@ -23,6 +23,8 @@ type CertaintySweepCollector struct {
 
 	Config config.CertaintySweepConfig
 
+	pacing
+
 	mu        sync.Mutex
 	lastPoll  *time.Time
 	lastError *string
@@ -32,21 +34,27 @@ type CertaintySweepCollector struct {
 func (c *CertaintySweepCollector) Name() string { return "certainty_sweep" }
 
 func (c *CertaintySweepCollector) SourceInfo() SourceInfo {
-	interval := c.Config.Interval
-	if interval <= 0 {
-		interval = 30 * time.Second
+	return SourceInfo{SourceType: "internal_scan", Endpoint: "db", PollInterval: c.interval()}
+}
+
+func (c *CertaintySweepCollector) interval() time.Duration {
+	if c.Config.Interval > 0 {
+		return c.Config.Interval
 	}
-	return SourceInfo{SourceType: "internal_scan", Endpoint: "db", PollInterval: interval}
+	return 30 * time.Second
 }
 
 func (c *CertaintySweepCollector) Start(ctx context.Context, out chan<- models.Signal) error {
 	if c == nil {
 		return nil
 	}
-	interval := c.Config.Interval
-	if interval <= 0 {
-		interval = 30 * time.Second
-	}
+	return c.every(ctx, c.Name(), c.interval(), func() {
+		c.CollectOnce(ctx, out)
+	})
+}
+
+// CollectOnce runs one sweep with the configured window.
+func (c *CertaintySweepCollector) CollectOnce(ctx context.Context, out chan<- models.Signal) {
 	hours := c.Config.HoursToExpiry
 	if hours <= 0 {
 		hours = 6
@@ -55,16 +63,17 @@ func (c *CertaintySweepCollector) Start(ctx context.Context, out chan<- models.S
 	if limit <= 0 {
 		limit = 50
 	}
-	t := time.NewTicker(interval)
-	defer t.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-t.C:
-			c.pollOnce(ctx, out, hours, limit)
-		}
+	c.pollOnce(ctx, out, hours, limit)
+}
+
+func (c *CertaintySweepCollector) Settings() map[string]any { return settingsMap(c.Config) }
+
+func (c *CertaintySweepCollector) WithSettings(raw json.RawMessage) (SignalCollector, error) {
+	cfg, err := mergeSettings(c.Config, raw)
+	if err != nil {
+		return nil, err
 	}
+	return &CertaintySweepCollector{Repo: c.Repo, Logger: c.Logger, Config: cfg}, nil
 }
 
 func (c *CertaintySweepCollector) Stop() error { return nil }
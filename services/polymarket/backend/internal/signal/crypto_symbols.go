@@ -0,0 +1,461 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// defaultCryptoAssets maps the names and tickers used in market questions to
+// Binance base assets. Config assets extend or override it.
+var defaultCryptoAssets = map[string]string{
+	"bitcoin":     "BTC",
+	"btc":         "BTC",
+	"ethereum":    "ETH",
+	"ether":       "ETH",
+	"eth":         "ETH",
+	"solana":      "SOL",
+	"sol":         "SOL",
+	"xrp":         "XRP",
+	"ripple":      "XRP",
+	"dogecoin":    "DOGE",
+	"doge":        "DOGE",
+	"bnb":         "BNB",
+	"cardano":     "ADA",
+	"hyperliquid": "HYPE",
+}
+
+// CryptoMarketMapping is a crypto threshold market resolved to the exchange
+// pair it depends on: YES pays when Symbol ends Comparator ("above"/"below")
+// Strike by Deadline.
+type CryptoMarketMapping struct {
+	MarketID   string     `json:"market_id"`
+	EventID    string     `json:"event_id"`
+	Asset      string     `json:"asset"`
+	Symbol     string     `json:"symbol"`
+	Strike     float64    `json:"strike"`
+	Comparator string     `json:"comparator"`
+	Deadline   *time.Time `json:"deadline,omitempty"`
+}
+
+var (
+	cryptoWordRe      = regexp.MustCompile(`[A-Za-z]+`)
+	cryptoThresholdRe = regexp.MustCompile(`(?i)\b(above|over|higher than|greater than|at least|reach|hit|below|under|lower than|less than|dip to|fall to|drop to)\s+\$\s*([0-9][0-9,]*(?:\.[0-9]+)?)\s*([km])?\b`)
+)
+
+// parseCryptoThreshold reads the asset, comparator and strike from a question
+// such as "Will Bitcoin be above $100k on March 31?". ok is false for
+// questions without a known asset or a dollar threshold, which excludes
+// "up or down" markets.
+func parseCryptoThreshold(question string, assets map[string]string) (asset, comparator string, strike float64, ok bool) {
+	for _, w := range cryptoWordRe.FindAllString(question, -1) {
+		if a, hit := assets[strings.ToLower(w)]; hit {
+			asset = a
+			break
+		}
+	}
+	m := cryptoThresholdRe.FindStringSubmatch(question)
+	if asset == "" || m == nil {
+		return "", "", 0, false
+	}
+	v, err := strconv.ParseFloat(strings.ReplaceAll(m[2], ",", ""), 64)
+	if err != nil || v <= 0 {
+		return "", "", 0, false
+	}
+	switch strings.ToLower(m[3]) {
+	case "k":
+		v *= 1e3
+	case "m":
+		v *= 1e6
+	}
+	comparator = "above"
+	switch strings.ToLower(m[1]) {
+	case "below", "under", "lower than", "less than", "dip to", "fall to", "drop to":
+		comparator = "below"
+	}
+	return asset, comparator, v, true
+}
+
+// CryptoSymbolCollector discovers crypto threshold markets in the catalog,
+// derives their Binance pairs and runs one BinancePriceCollector per pair.
+// Pairs are started when their first market appears and stopped once no
+// active market needs them. A triggered move is emitted as one
+// "crypto_price_change" signal per mapped market, carrying strike, deadline
+// and distance from the strike.
+type CryptoSymbolCollector struct {
+	Repo   repository.Repository
+	Logger *zap.Logger
+	HTTP   *http.Client
+
+	Config config.CryptoSymbolConfig
+	// Price supplies the poll interval, window and trigger for every pair.
+	Price config.BinancePriceConfig
+
+	pacing
+
+	mu        sync.Mutex
+	lastPoll  *time.Time
+	lastError *string
+	status    string
+	mappings  map[string][]CryptoMarketMapping
+	children  map[string]*cryptoChild
+}
+
+type cryptoChild struct {
+	collector *BinancePriceCollector
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+func (c *CryptoSymbolCollector) Name() string { return "crypto_symbol_map" }
+
+func (c *CryptoSymbolCollector) SourceInfo() SourceInfo {
+	return SourceInfo{SourceType: "internal_scan", Endpoint: "db", PollInterval: c.interval()}
+}
+
+func (c *CryptoSymbolCollector) Start(ctx context.Context, out chan<- models.Signal) error {
+	if c == nil {
+		return nil
+	}
+	defer c.stopAll()
+	c.refresh(ctx, out)
+	return c.every(ctx, c.Name(), c.interval(), func() {
+		c.refresh(ctx, out)
+	})
+}
+
+func (c *CryptoSymbolCollector) Settings() map[string]any { return settingsMap(c.Config) }
+
+// WithSettings returns a fresh collector; symbols are rediscovered when it
+// starts.
+func (c *CryptoSymbolCollector) WithSettings(raw json.RawMessage) (SignalCollector, error) {
+	cfg, err := mergeSettings(c.Config, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoSymbolCollector{Repo: c.Repo, Logger: c.Logger, HTTP: c.HTTP, Config: cfg, Price: c.Price}, nil
+}
+
+func (c *CryptoSymbolCollector) Stop() error {
+	if c != nil {
+		c.stopAll()
+	}
+	return nil
+}
+
+func (c *CryptoSymbolCollector) Health() HealthStatus {
+	if c == nil {
+		return HealthStatus{Status: "unknown"}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := c.status
+	if strings.TrimSpace(status) == "" {
+		status = "unknown"
+	}
+	symbols := map[string]any{}
+	for symbol, child := range c.children {
+		h := child.collector.Health()
+		symbols[symbol] = map[string]any{"markets": len(c.mappings[symbol]), "status": h.Status, "last_error": h.LastError}
+	}
+	return HealthStatus{Status: status, LastPollAt: c.lastPoll, LastError: c.lastError, Details: map[string]any{"symbols": symbols}}
+}
+
+func (c *CryptoSymbolCollector) interval() time.Duration {
+	if c.Config.RefreshInterval > 0 {
+		return c.Config.RefreshInterval
+	}
+	return 10 * time.Minute
+}
+
+func (c *CryptoSymbolCollector) assets() map[string]string {
+	return cryptoAssets(c.Config)
+}
+
+func cryptoAssets(cfg config.CryptoSymbolConfig) map[string]string {
+	out := make(map[string]string, len(defaultCryptoAssets)+len(cfg.Assets))
+	for k, v := range defaultCryptoAssets {
+		out[k] = v
+	}
+	for k, v := range cfg.Assets {
+		k, v = strings.ToLower(strings.TrimSpace(k)), strings.ToUpper(strings.TrimSpace(v))
+		if k != "" && v != "" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func cryptoQuote(cfg config.CryptoSymbolConfig) string {
+	if quote := strings.ToUpper(strings.TrimSpace(cfg.Quote)); quote != "" {
+		return quote
+	}
+	return "USDT"
+}
+
+// MapCryptoMarket resolves a market to the pair its question depends on,
+// with the collector's aliases and quote. Deadline is left unset; it is the
+// event's end time.
+func MapCryptoMarket(m models.Market, cfg config.CryptoSymbolConfig) (CryptoMarketMapping, bool) {
+	asset, cmp, strike, ok := parseCryptoThreshold(m.Question, cryptoAssets(cfg))
+	if !ok {
+		return CryptoMarketMapping{}, false
+	}
+	return CryptoMarketMapping{
+		MarketID:   m.ID,
+		EventID:    m.EventID,
+		Asset:      asset,
+		Symbol:     asset + cryptoQuote(cfg),
+		Strike:     strike,
+		Comparator: cmp,
+	}, true
+}
+
+// CryptoPriceEndpoint returns the ticker URL for symbol.
+func CryptoPriceEndpoint(cfg config.CryptoSymbolConfig, symbol string) string {
+	template := strings.TrimSpace(cfg.EndpointTemplate)
+	if template == "" {
+		template = "https://api.binance.com/api/v3/ticker/price?symbol=%s"
+	}
+	return fmt.Sprintf(template, symbol)
+}
+
+// searchTerms picks the longest alias of each asset for the catalog search;
+// short tickers such as "eth" match too many unrelated questions.
+func searchTerms(assets map[string]string) []string {
+	longest := map[string]string{}
+	for alias, asset := range assets {
+		if cur := longest[asset]; len(alias) > len(cur) || (len(alias) == len(cur) && alias < cur) {
+			longest[asset] = alias
+		}
+	}
+	terms := make([]string, 0, len(longest))
+	for _, alias := range longest {
+		terms = append(terms, alias)
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+func (c *CryptoSymbolCollector) refresh(ctx context.Context, out chan<- models.Signal) {
+	now := time.Now().UTC()
+	mappings, err := c.discover(ctx)
+	if err != nil {
+		c.setHealth(now, "down", strPtr(err.Error()))
+		return
+	}
+	c.sync(ctx, out, mappings)
+	c.setHealth(now, "healthy", nil)
+}
+
+// discover maps every active crypto threshold market, grouped by pair. When
+// there are more pairs than MaxSymbols, the ones with the most markets win.
+func (c *CryptoSymbolCollector) discover(ctx context.Context) (map[string][]CryptoMarketMapping, error) {
+	if c.Repo == nil {
+		return nil, fmt.Errorf("repo unavailable")
+	}
+	assets := c.assets()
+	quote := cryptoQuote(c.Config)
+	active, closed := true, false
+	seen := map[string]bool{}
+	var found []CryptoMarketMapping
+	eventIDs := map[string]bool{}
+	for _, term := range searchTerms(assets) {
+		term := term
+		markets, err := c.Repo.ListMarkets(ctx, repository.ListMarketsParams{Limit: 500, Active: &active, Closed: &closed, Question: &term})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range markets {
+			if seen[m.ID] {
+				continue
+			}
+			seen[m.ID] = true
+			asset, cmp, strike, ok := parseCryptoThreshold(m.Question, assets)
+			if !ok {
+				continue
+			}
+			found = append(found, CryptoMarketMapping{
+				MarketID:   m.ID,
+				EventID:    m.EventID,
+				Asset:      asset,
+				Symbol:     asset + quote,
+				Strike:     strike,
+				Comparator: cmp,
+			})
+			eventIDs[m.EventID] = true
+		}
+	}
+	if len(eventIDs) > 0 {
+		ids := make([]string, 0, len(eventIDs))
+		for id := range eventIDs {
+			ids = append(ids, id)
+		}
+		events, err := c.Repo.ListEventsByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		ends := map[string]*time.Time{}
+		for _, e := range events {
+			ends[e.ID] = e.EndTime
+		}
+		for i := range found {
+			found[i].Deadline = ends[found[i].EventID]
+		}
+	}
+
+	bySymbol := map[string][]CryptoMarketMapping{}
+	for _, m := range found {
+		if m.Deadline != nil && m.Deadline.Before(time.Now()) {
+			continue
+		}
+		bySymbol[m.Symbol] = append(bySymbol[m.Symbol], m)
+	}
+	maxSymbols := c.Config.MaxSymbols
+	if maxSymbols <= 0 {
+		maxSymbols = 10
+	}
+	if len(bySymbol) > maxSymbols {
+		symbols := make([]string, 0, len(bySymbol))
+		for s := range bySymbol {
+			symbols = append(symbols, s)
+		}
+		sort.Slice(symbols, func(i, j int) bool {
+			if len(bySymbol[symbols[i]]) != len(bySymbol[symbols[j]]) {
+				return len(bySymbol[symbols[i]]) > len(bySymbol[symbols[j]])
+			}
+			return symbols[i] < symbols[j]
+		})
+		for _, s := range symbols[maxSymbols:] {
+			delete(bySymbol, s)
+		}
+	}
+	return bySymbol, nil
+}
+
+// sync starts collectors for new pairs and stops those no longer mapped.
+func (c *CryptoSymbolCollector) sync(ctx context.Context, out chan<- models.Signal, mappings map[string][]CryptoMarketMapping) {
+	c.mu.Lock()
+	if c.children == nil {
+		c.children = map[string]*cryptoChild{}
+	}
+	c.mappings = mappings
+	stopped := map[string]*cryptoChild{}
+	for symbol, child := range c.children {
+		if _, ok := mappings[symbol]; !ok {
+			stopped[symbol] = child
+			delete(c.children, symbol)
+		}
+	}
+	for symbol := range mappings {
+		if _, ok := c.children[symbol]; ok {
+			continue
+		}
+		c.children[symbol] = c.startChild(ctx, out, symbol)
+		if c.Logger != nil {
+			c.Logger.Info("crypto symbol collector started", zap.String("symbol", symbol), zap.Int("markets", len(mappings[symbol])))
+		}
+	}
+	c.mu.Unlock()
+
+	// Children may be inside fanout, which takes c.mu; wait for them unlocked.
+	for symbol, child := range stopped {
+		child.cancel()
+		<-child.done
+		if c.Logger != nil {
+			c.Logger.Info("crypto symbol collector stopped", zap.String("symbol", symbol))
+		}
+	}
+}
+
+func (c *CryptoSymbolCollector) startChild(ctx context.Context, out chan<- models.Signal, symbol string) *cryptoChild {
+	collector := &BinancePriceCollector{
+		HTTP:          c.HTTP,
+		Logger:        c.Logger,
+		Endpoint:      CryptoPriceEndpoint(c.Config, symbol),
+		PollInterval:  c.Price.PollInterval,
+		WindowSeconds: c.Price.WindowSeconds,
+		TriggerPct:    c.Price.TriggerPct,
+		Symbol:        symbol,
+		pacing:        c.pacing,
+	}
+	collector.Fanout = func(sig models.Signal, price float64) []models.Signal {
+		return c.fanout(symbol, sig, price)
+	}
+	childCtx, cancel := context.WithCancel(ctx)
+	child := &cryptoChild{collector: collector, cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(child.done)
+		_ = collector.Start(childCtx, out)
+	}()
+	return child
+}
+
+// fanout turns one price move into a signal per market mapped to symbol.
+func (c *CryptoSymbolCollector) fanout(symbol string, sig models.Signal, price float64) []models.Signal {
+	c.mu.Lock()
+	markets := append([]CryptoMarketMapping(nil), c.mappings[symbol]...)
+	c.mu.Unlock()
+
+	var base map[string]any
+	_ = json.Unmarshal(sig.Payload, &base)
+	out := make([]models.Signal, 0, len(markets))
+	for _, m := range markets {
+		payload := make(map[string]any, len(base)+6)
+		for k, v := range base {
+			payload[k] = v
+		}
+		payload["asset"] = m.Asset
+		payload["strike"] = m.Strike
+		payload["comparator"] = m.Comparator
+		payload["distance_pct"] = (price - m.Strike) / m.Strike * 100.0
+		if m.Deadline != nil {
+			payload["deadline"] = m.Deadline.UTC().Format(time.RFC3339)
+			payload["hours_left"] = time.Until(*m.Deadline).Hours()
+		}
+		raw, _ := json.Marshal(payload)
+
+		s := sig
+		s.SignalType = "crypto_price_change"
+		s.Source = c.Name()
+		s.MarketID = strPtr(m.MarketID)
+		if m.EventID != "" {
+			s.EventID = strPtr(m.EventID)
+		}
+		s.Payload = raw
+		out = append(out, s)
+	}
+	return out
+}
+
+func (c *CryptoSymbolCollector) stopAll() {
+	c.mu.Lock()
+	children := c.children
+	c.children = nil
+	c.mu.Unlock()
+	for _, child := range children {
+		child.cancel()
+		<-child.done
+	}
+}
+
+func (c *CryptoSymbolCollector) setHealth(ts time.Time, status string, errStr *string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastPoll = &ts
+	c.status = status
+	c.lastError = errStr
+}
@@ -0,0 +1,147 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+func TestParseCryptoThreshold(t *testing.T) {
+	cases := []struct {
+		question   string
+		asset, cmp string
+		strike     float64
+		ok         bool
+	}{
+		{"Will Bitcoin be above $100,000 on March 31?", "BTC", "above", 100000, true},
+		{"Will ETH dip to $2.5k in June?", "ETH", "below", 2500, true},
+		{"Will Solana reach $300 by December 31?", "SOL", "above", 300, true},
+		{"Bitcoin Up or Down - March 3, 4PM ET", "", "", 0, false},
+		{"Will Bitcoin ETF flows top 1B?", "", "", 0, false},
+		{"Will Tesla be above $300 on Friday?", "", "", 0, false},
+	}
+	for _, tc := range cases {
+		asset, cmp, strike, ok := parseCryptoThreshold(tc.question, defaultCryptoAssets)
+		if ok != tc.ok || asset != tc.asset || cmp != tc.cmp || strike != tc.strike {
+			t.Fatalf("%q: got %s %s %v %v", tc.question, asset, cmp, strike, ok)
+		}
+	}
+}
+
+type cryptoCatalogRepo struct {
+	repository.Repository
+	mu      sync.Mutex
+	markets []models.Market
+	end     time.Time
+}
+
+func (r *cryptoCatalogRepo) ListMarkets(_ context.Context, p repository.ListMarketsParams) ([]models.Market, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.Market
+	for _, m := range r.markets {
+		if p.Question == nil || strings.Contains(strings.ToLower(m.Question), *p.Question) {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (r *cryptoCatalogRepo) ListEventsByIDs(_ context.Context, ids []string) ([]models.Event, error) {
+	out := make([]models.Event, 0, len(ids))
+	for _, id := range ids {
+		end := r.end
+		out = append(out, models.Event{ID: id, EndTime: &end})
+	}
+	return out, nil
+}
+
+func TestCryptoSymbolCollector_ChildLifecycle(t *testing.T) {
+	var mu sync.Mutex
+	prices := map[string]float64{"BTCUSDT": 100000, "ETHUSDT": 3000}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		symbol := r.URL.Query().Get("symbol")
+		_ = json.NewEncoder(w).Encode(map[string]string{"symbol": symbol, "price": jsonFloat(prices[symbol])})
+	}))
+	defer srv.Close()
+
+	repo := &cryptoCatalogRepo{
+		end: time.Now().Add(48 * time.Hour),
+		markets: []models.Market{
+			{ID: "m1", EventID: "e1", Question: "Will Bitcoin be above $105,000 on Friday?"},
+			{ID: "m2", EventID: "e1", Question: "Will Bitcoin be above $110,000 on Friday?"},
+			{ID: "m3", EventID: "e2", Question: "Will Ethereum be below $2,800 on Friday?"},
+		},
+	}
+	c := &CryptoSymbolCollector{
+		Repo:   repo,
+		Config: config.CryptoSymbolConfig{EndpointTemplate: srv.URL + "/price?symbol=%s"},
+		Price:  config.BinancePriceConfig{PollInterval: 10 * time.Millisecond, WindowSeconds: 60, TriggerPct: 1},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan models.Signal, 16)
+
+	c.refresh(ctx, out)
+	details := c.Health().Details["symbols"].(map[string]any)
+	if len(details) != 2 || details["BTCUSDT"].(map[string]any)["markets"] != 2 {
+		t.Fatalf("symbols after first refresh: %+v", details)
+	}
+
+	// A 3% BTC move, once the baseline is sampled, fans out to both BTC markets.
+	for c.Health().Details["symbols"].(map[string]any)["BTCUSDT"].(map[string]any)["status"] != "healthy" {
+		time.Sleep(5 * time.Millisecond)
+	}
+	mu.Lock()
+	prices["BTCUSDT"] = 103000
+	mu.Unlock()
+	got := map[string]map[string]any{}
+	deadline := time.After(2 * time.Second)
+	for len(got) < 2 {
+		select {
+		case sig := <-out:
+			if sig.SignalType != "crypto_price_change" || sig.Source != "crypto_symbol_map" || sig.MarketID == nil {
+				t.Fatalf("unexpected signal: %+v", sig)
+			}
+			var payload map[string]any
+			_ = json.Unmarshal(sig.Payload, &payload)
+			got[*sig.MarketID] = payload
+		case <-deadline:
+			t.Fatalf("timed out waiting for fanout, got %v", got)
+		}
+	}
+	if p := got["m1"]; p["symbol"] != "BTCUSDT" || p["strike"] != 105000.0 || p["comparator"] != "above" || p["deadline"] == nil {
+		t.Fatalf("m1 payload: %+v", p)
+	}
+
+	// Once the ETH market leaves the catalog its collector is stopped.
+	repo.mu.Lock()
+	repo.markets = repo.markets[:2]
+	repo.mu.Unlock()
+	c.refresh(ctx, out)
+	details = c.Health().Details["symbols"].(map[string]any)
+	if _, ok := details["ETHUSDT"]; ok || len(details) != 1 {
+		t.Fatalf("symbols after second refresh: %+v", details)
+	}
+
+	c.stopAll()
+	if d := c.Health().Details["symbols"].(map[string]any); len(d) != 0 {
+		t.Fatalf("children left after stop: %+v", d)
+	}
+}
+
+func jsonFloat(v float64) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -2,6 +2,7 @@ package signal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -19,8 +20,15 @@ type SignalHub struct {
 	subs       map[string][]chan models.Signal
 	mu         sync.RWMutex
 
-	repo   repository.Repository
-	logger *zap.Logger
+	repo      repository.Repository
+	logger    *zap.Logger
+	scheduler *AdaptiveScheduler
+
+	// runCtx and out are set while Run is active so Reconfigure can start
+	// replacement collectors; cancels stops each running collector.
+	runCtx  context.Context
+	out     chan models.Signal
+	cancels map[string]context.CancelFunc
 
 	dedupMu       sync.Mutex
 	lastSeen      map[string]time.Time
@@ -47,6 +55,16 @@ func (h *SignalHub) Register(c SignalCollector) {
 	h.collectors[c.Name()] = c
 }
 
+// SetScheduler paces registered polling collectors by market activity.
+func (h *SignalHub) SetScheduler(s *AdaptiveScheduler) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.scheduler = s
+}
+
 // Subscribe returns a channel that receives persisted signals for a given type.
 func (h *SignalHub) Subscribe(signalType string, buf int) <-chan models.Signal {
 	if buf <= 0 {
@@ -65,22 +83,27 @@ func (h *SignalHub) Run(ctx context.Context) error {
 	}
 	out := make(chan models.Signal, 128)
 
-	h.mu.RLock()
+	h.mu.Lock()
+	h.runCtx, h.out, h.cancels = ctx, out, map[string]context.CancelFunc{}
 	collectors := make([]SignalCollector, 0, len(h.collectors))
 	for _, c := range h.collectors {
 		collectors = append(collectors, c)
 	}
-	h.mu.RUnlock()
+	scheduler := h.scheduler
+	h.mu.Unlock()
 
-	for _, c := range collectors {
-		c := c
-		h.upsertSource(ctx, c, HealthStatus{Status: "unknown"})
+	if scheduler != nil {
 		go func() {
-			if err := c.Start(ctx, out); err != nil && h.logger != nil {
-				h.logger.Warn("signal collector stopped", zap.String("collector", c.Name()), zap.Error(err))
+			if err := scheduler.Run(ctx); err != nil && !errors.Is(err, context.Canceled) && h.logger != nil {
+				h.logger.Warn("adaptive scheduler stopped", zap.Error(err))
 			}
 		}()
 	}
+	for _, c := range collectors {
+		h.mu.Lock()
+		h.startLocked(c)
+		h.mu.Unlock()
+	}
 
 	healthTicker := time.NewTicker(30 * time.Second)
 	defer healthTicker.Stop()
@@ -90,12 +113,24 @@ func (h *SignalHub) Run(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
-			for _, c := range collectors {
+			h.mu.Lock()
+			for name, c := range h.collectors {
+				if cancel := h.cancels[name]; cancel != nil {
+					cancel()
+				}
 				_ = c.Stop()
 			}
+			h.runCtx, h.cancels = nil, nil
+			h.mu.Unlock()
 			return ctx.Err()
 		case <-healthTicker.C:
-			for _, c := range collectors {
+			h.mu.RLock()
+			current := make([]SignalCollector, 0, len(h.collectors))
+			for _, c := range h.collectors {
+				current = append(current, c)
+			}
+			h.mu.RUnlock()
+			for _, c := range current {
 				h.upsertSource(ctx, c, c.Health())
 			}
 		case <-statsTicker.C:
@@ -103,22 +138,67 @@ func (h *SignalHub) Run(ctx context.Context) error {
 				h.logger.Info("signal hub stats",
 					zap.Uint64("dropped_dedup", atomic.LoadUint64(&h.droppedDedup)),
 					zap.Uint64("dropped_fanout", atomic.LoadUint64(&h.droppedFanout)),
+					zap.Float64("activity", scheduler.Activity()),
 				)
 			}
 		case sig := <-out:
-			sig = h.normalize(sig)
-			if h.shouldDrop(sig) {
-				atomic.AddUint64(&h.droppedDedup, 1)
-				continue
-			}
-			if h.repo != nil {
-				_ = h.repo.InsertSignal(ctx, &sig)
-			}
-			h.fanout(sig)
+			h.Ingest(ctx, sig)
 		}
 	}
 }
 
+// startLocked runs c under its own context so it can be replaced alone.
+// The caller holds h.mu; upsertSource takes the read lock itself, so it
+// runs in the collector's goroutine.
+func (h *SignalHub) startLocked(c SignalCollector) {
+	ctx, cancel := context.WithCancel(h.runCtx)
+	h.cancels[c.Name()] = cancel
+	if p, ok := c.(interface{ setScheduler(*AdaptiveScheduler) }); ok {
+		p.setScheduler(h.scheduler)
+	}
+	out := h.out
+	go func() {
+		h.upsertSource(ctx, c, HealthStatus{Status: "unknown"})
+		if err := c.Start(ctx, out); err != nil && !errors.Is(err, context.Canceled) && h.logger != nil {
+			h.logger.Warn("signal collector stopped", zap.String("collector", c.Name()), zap.Error(err))
+		}
+	}()
+}
+
+// replace swaps old for next under the same name, restarting it when the
+// hub is running.
+func (h *SignalHub) replace(old, next SignalCollector) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.collectors[next.Name()] = next
+	if h.runCtx == nil || h.runCtx.Err() != nil {
+		return
+	}
+	if cancel := h.cancels[old.Name()]; cancel != nil {
+		cancel()
+	}
+	_ = old.Stop()
+	h.startLocked(next)
+	if h.logger != nil {
+		h.logger.Info("signal collector reconfigured", zap.String("collector", next.Name()))
+	}
+}
+
+// Ingest normalizes, deduplicates, persists and fans out one collected
+// signal. It reports false when the signal was dropped as a duplicate.
+func (h *SignalHub) Ingest(ctx context.Context, sig models.Signal) bool {
+	sig = h.normalize(sig)
+	if h.shouldDrop(sig) {
+		atomic.AddUint64(&h.droppedDedup, 1)
+		return false
+	}
+	if h.repo != nil {
+		_ = h.repo.InsertSignal(ctx, &sig)
+	}
+	h.fanout(sig)
+	return true
+}
+
 func (h *SignalHub) fanout(sig models.Signal) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -165,6 +245,19 @@ func (h *SignalHub) shouldDrop(sig models.Signal) bool {
 	return false
 }
 
+// wouldDrop is shouldDrop without recording the signal.
+func (h *SignalHub) wouldDrop(sig models.Signal) bool {
+	window := defaultDedupWindow(sig.SignalType)
+	key := dedupKey(sig)
+	if window <= 0 || key == "" {
+		return false
+	}
+	h.dedupMu.Lock()
+	defer h.dedupMu.Unlock()
+	last, ok := h.lastSeen[key]
+	return ok && sig.CreatedAt.Sub(last) < window
+}
+
 func dedupKey(sig models.Signal) string {
 	// Keep this stable and coarse; it is meant to suppress spam, not provide perfect idempotency.
 	return fmt.Sprintf("%s|%s|%s|%s|%s|%s",
@@ -226,6 +319,9 @@ func (h *SignalHub) upsertSource(ctx context.Context, c SignalCollector, health
 	if hs == "" {
 		hs = "unknown"
 	}
+	h.mu.RLock()
+	interval := h.scheduler.Interval(c.Name(), info.PollInterval)
+	h.mu.RUnlock()
 	now := time.Now().UTC()
 	lastPoll := health.LastPollAt
 	if lastPoll == nil {
@@ -235,7 +331,7 @@ func (h *SignalHub) upsertSource(ctx context.Context, c SignalCollector, health
 		Name:         c.Name(),
 		SourceType:   info.SourceType,
 		Endpoint:     info.Endpoint,
-		PollInterval: durationString(info.PollInterval),
+		PollInterval: durationString(interval),
 		Enabled:      true,
 		LastPollAt:   lastPoll,
 		LastError:    health.LastError,
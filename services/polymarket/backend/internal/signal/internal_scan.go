@@ -38,6 +38,8 @@ type InternalScanCollector struct {
 	NoBiasMinEVPct   float64
 	NoBiasMaxPerTick int
 
+	pacing
+
 	lastNoBias map[string]time.Time
 
 	mu      sync.Mutex
@@ -74,26 +76,18 @@ func (c *InternalScanCollector) Start(ctx context.Context, out chan<- models.Sig
 		minSpread = 200
 	}
 
-	t := time.NewTicker(interval)
-	defer t.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-t.C:
-			if c.Repo == nil {
-				continue
-			}
-			now := time.Now().UTC()
-			c.setRun(now, nil)
-			c.emitLiquidityGap(ctx, out, now, limit, minSpread)
-			c.emitArbSumDeviation(ctx, out, now)
-			c.emitNoBias(ctx, out, now)
-			c.emitFDVOverpriced(ctx, out, now)
-			c.emitPriceAnomaly(ctx, out, now)
+	return c.every(ctx, c.Name(), interval, func() {
+		if c.Repo == nil {
+			return
 		}
-	}
+		now := time.Now().UTC()
+		c.setRun(now, nil)
+		c.emitLiquidityGap(ctx, out, now, limit, minSpread)
+		c.emitArbSumDeviation(ctx, out, now)
+		c.emitNoBias(ctx, out, now)
+		c.emitFDVOverpriced(ctx, out, now)
+		c.emitPriceAnomaly(ctx, out, now)
+	})
 }
 
 func (c *InternalScanCollector) emitLiquidityGap(ctx context.Context, out chan<- models.Signal, now time.Time, limit int, minSpread float64) {
@@ -171,7 +165,9 @@ func (c *InternalScanCollector) emitArbSumDeviation(ctx context.Context, out cha
 		return
 	}
 	for _, agg := range aggs {
-		if agg.EventID == "" || agg.MarketCount < minMarkets {
+		// A single non-binary market is an outcome set of its own, so events
+		// below minMarkets are still scanned.
+		if agg.EventID == "" || agg.MarketCount < 1 {
 			continue
 		}
 		if agg.SumLiquidity.LessThan(decimal.NewFromFloat(minLiq)) {
@@ -179,10 +175,7 @@ func (c *InternalScanCollector) emitArbSumDeviation(ctx context.Context, out cha
 		}
 		eventID := strings.TrimSpace(agg.EventID)
 		markets, err := c.Repo.ListMarketsByEventID(ctx, eventID)
-		if err != nil {
-			continue
-		}
-		if len(markets) < minMarkets {
+		if err != nil || len(markets) == 0 {
 			continue
 		}
 		marketIDs := make([]string, 0, len(markets))
@@ -195,74 +188,87 @@ func (c *InternalScanCollector) emitArbSumDeviation(ctx context.Context, out cha
 		if err != nil {
 			continue
 		}
-		yesTokenByMarket := map[string]string{}
-		for _, tok := range tokens {
-			if tok.MarketID == "" || tok.ID == "" {
+		for _, set := range models.OutcomeSets(markets, tokens) {
+			// The YES tokens of binary markets need minMarkets legs; a
+			// non-binary market always lists all of its outcomes.
+			if set.MarketID == "" && len(set.Legs) < minMarkets {
 				continue
 			}
-			if strings.EqualFold(strings.TrimSpace(tok.Outcome), "yes") {
-				yesTokenByMarket[tok.MarketID] = tok.ID
-			}
-		}
-		yesTokenIDs := make([]string, 0, len(yesTokenByMarket))
-		for _, m := range markets {
-			if id := yesTokenByMarket[m.ID]; id != "" {
-				yesTokenIDs = append(yesTokenIDs, id)
+			if len(set.Legs) < 2 {
+				continue
 			}
+			c.emitOutcomeSetDeviation(ctx, out, now, eventID, set, minDevPct)
 		}
-		if len(yesTokenIDs) < minMarkets {
-			continue
-		}
-		books, _ := c.Repo.ListOrderbookLatestByTokenIDs(ctx, yesTokenIDs)
-		trades, _ := c.Repo.ListLastTradePricesByTokenIDs(ctx, yesTokenIDs)
-		bookByToken := map[string]models.OrderbookLatest{}
-		for _, b := range books {
-			bookByToken[b.TokenID] = b
-		}
-		tradeByToken := map[string]models.LastTradePrice{}
-		for _, tr := range trades {
-			tradeByToken[tr.TokenID] = tr
-		}
+	}
+}
 
-		sum := 0.0
-		prices := map[string]float64{} // token -> price used
-		for _, tokenID := range yesTokenIDs {
-			price, ok := currentPrice(bookByToken[tokenID], tradeByToken[tokenID])
-			if !ok {
-				sum = 0
-				break
-			}
-			sum += price
-			prices[tokenID] = price
-		}
-		if sum <= 0 {
-			continue
-		}
-		devPct := math.Abs(sum-1.0) * 100.0
-		if devPct < minDevPct {
-			continue
-		}
-		direction := "BOTH"
-		if sum < 1.0 {
-			direction = "YES"
-		} else if sum > 1.0 {
-			direction = "NO"
-		}
-		payload, _ := json.Marshal(map[string]any{
-			"sum":           sum,
-			"deviation_pct": devPct,
-			"yes_token_ids": yesTokenIDs,
-			"prices":        prices,
-		})
-		out <- models.Signal{
-			SignalType: "arb_sum_deviation",
-			Source:     "internal_scan",
-			EventID:    strPtr(eventID),
-			Strength:   clamp01(devPct / 10.0), // 10% => 1.0
-			Direction:  direction,
-			Payload:    datatypes.JSON(payload),
-			CreatedAt:  now,
+// emitOutcomeSetDeviation signals when the prices of an outcome set, which
+// should sum to 1, deviate by at least minDevPct.
+func (c *InternalScanCollector) emitOutcomeSetDeviation(ctx context.Context, out chan<- models.Signal, now time.Time, eventID string, set models.OutcomeSet, minDevPct float64) {
+	tokenIDs := make([]string, 0, len(set.Legs))
+	outcomes := map[string]string{}
+	for _, leg := range set.Legs {
+		tokenIDs = append(tokenIDs, leg.TokenID)
+		outcomes[leg.TokenID] = leg.Outcome
+	}
+	books, _ := c.Repo.ListOrderbookLatestByTokenIDs(ctx, tokenIDs)
+	trades, _ := c.Repo.ListLastTradePricesByTokenIDs(ctx, tokenIDs)
+	bookByToken := map[string]models.OrderbookLatest{}
+	for _, b := range books {
+		bookByToken[b.TokenID] = b
+	}
+	tradeByToken := map[string]models.LastTradePrice{}
+	for _, tr := range trades {
+		tradeByToken[tr.TokenID] = tr
+	}
+
+	sum := 0.0
+	prices := map[string]float64{} // token -> price used
+	for _, tokenID := range tokenIDs {
+		price, ok := currentPrice(bookByToken[tokenID], tradeByToken[tokenID])
+		if !ok {
+			return
 		}
+		sum += price
+		prices[tokenID] = price
+	}
+	if sum <= 0 {
+		return
+	}
+	devPct := math.Abs(sum-1.0) * 100.0
+	if devPct < minDevPct {
+		return
+	}
+	// YES buys every outcome of the set, NO buys their complements.
+	direction := "BOTH"
+	if sum < 1.0 {
+		direction = "YES"
+	} else if sum > 1.0 {
+		direction = "NO"
+	}
+	body := map[string]any{
+		"sum":           sum,
+		"deviation_pct": devPct,
+		"token_ids":     tokenIDs,
+		"outcomes":      outcomes,
+		"prices":        prices,
+		"outcome_set":   "event",
+	}
+	if set.MarketID != "" {
+		body["outcome_set"] = "market"
+	} else {
+		body["yes_token_ids"] = tokenIDs
+	}
+	payload, _ := json.Marshal(body)
+	out <- models.Signal{
+		SignalType: "arb_sum_deviation",
+		Source:     "internal_scan",
+		EventID:    strPtr(eventID),
+		MarketID:   strPtr(set.MarketID),
+		Strength:   clamp01(devPct / 10.0), // 10% => 1.0
+		Direction:  direction,
+		Payload:    datatypes.JSON(payload),
+		CreatedAt:  now,
 	}
 }
 
@@ -0,0 +1,190 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// MarketCloseCollector scans events ending within the countdown window and emits "market_close_countdown"
+// for markets already priced near certainty. The payload carries hours_left so the CertaintySweepStrategy
+// can apply its time ladder.
+type MarketCloseCollector struct {
+	Repo   repository.Repository
+	Logger *zap.Logger
+
+	Config config.MarketCloseConfig
+
+	pacing
+
+	mu        sync.Mutex
+	lastPoll  *time.Time
+	lastError *string
+	status    string
+}
+
+func (c *MarketCloseCollector) Name() string { return "market_close" }
+
+func (c *MarketCloseCollector) SourceInfo() SourceInfo {
+	return SourceInfo{SourceType: "internal_scan", Endpoint: "db", PollInterval: c.interval()}
+}
+
+func (c *MarketCloseCollector) Start(ctx context.Context, out chan<- models.Signal) error {
+	if c == nil {
+		return nil
+	}
+	return c.every(ctx, c.Name(), c.interval(), func() {
+		c.pollOnce(ctx, out)
+	})
+}
+
+// CollectOnce runs one countdown scan.
+func (c *MarketCloseCollector) CollectOnce(ctx context.Context, out chan<- models.Signal) {
+	c.pollOnce(ctx, out)
+}
+
+func (c *MarketCloseCollector) Settings() map[string]any { return settingsMap(c.Config) }
+
+func (c *MarketCloseCollector) WithSettings(raw json.RawMessage) (SignalCollector, error) {
+	cfg, err := mergeSettings(c.Config, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &MarketCloseCollector{Repo: c.Repo, Logger: c.Logger, Config: cfg}, nil
+}
+
+func (c *MarketCloseCollector) Stop() error { return nil }
+
+func (c *MarketCloseCollector) Health() HealthStatus {
+	if c == nil {
+		return HealthStatus{Status: "unknown"}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := c.status
+	if strings.TrimSpace(status) == "" {
+		status = "unknown"
+	}
+	return HealthStatus{Status: status, LastPollAt: c.lastPoll, LastError: c.lastError}
+}
+
+func (c *MarketCloseCollector) interval() time.Duration {
+	if c.Config.Interval > 0 {
+		return c.Config.Interval
+	}
+	return time.Minute
+}
+
+func (c *MarketCloseCollector) pollOnce(ctx context.Context, out chan<- models.Signal) {
+	now := time.Now().UTC()
+	if c.Repo == nil {
+		c.setHealth(now, "down", strPtr("repo unavailable"))
+		return
+	}
+	hours := c.Config.HoursToExpiry
+	if hours <= 0 {
+		hours = 24
+	}
+	limit := c.Config.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	minPrice := c.Config.MinPrice
+	if minPrice <= 0.5 || minPrice >= 1 {
+		minPrice = 0.95
+	}
+	events, err := c.Repo.ListActiveEventsEndingSoon(ctx, hours, limit)
+	if err != nil {
+		c.setHealth(now, "down", strPtr(err.Error()))
+		return
+	}
+	for _, ev := range events {
+		if strings.TrimSpace(ev.ID) == "" || ev.EndTime == nil {
+			continue
+		}
+		hoursLeft := ev.EndTime.Sub(now).Hours()
+		if hoursLeft <= 0 {
+			continue
+		}
+		markets, err := c.Repo.ListMarketsByEventID(ctx, ev.ID)
+		if err != nil || len(markets) == 0 {
+			continue
+		}
+		marketIDs := make([]string, 0, len(markets))
+		for _, m := range markets {
+			if strings.TrimSpace(m.ID) != "" {
+				marketIDs = append(marketIDs, strings.TrimSpace(m.ID))
+			}
+		}
+		toks, err := c.Repo.ListTokensByMarketIDs(ctx, marketIDs)
+		if err != nil || len(toks) == 0 {
+			continue
+		}
+		yesByMarket := map[string]string{}
+		yesIDs := make([]string, 0, len(toks))
+		for _, t := range toks {
+			if strings.EqualFold(strings.TrimSpace(t.Outcome), "yes") {
+				yesByMarket[t.MarketID] = t.ID
+				yesIDs = append(yesIDs, t.ID)
+			}
+		}
+		books, _ := c.Repo.ListOrderbookLatestByTokenIDs(ctx, yesIDs)
+		bookByToken := map[string]models.OrderbookLatest{}
+		for _, b := range books {
+			bookByToken[b.TokenID] = b
+		}
+		for mid, tid := range yesByMarket {
+			book := bookByToken[tid]
+			if book.TokenID == "" || book.BestAsk == nil {
+				continue
+			}
+			ask := *book.BestAsk
+			if ask < minPrice && ask > 1-minPrice {
+				continue
+			}
+			payload, _ := json.Marshal(map[string]any{
+				"event_id":     ev.ID,
+				"market_id":    mid,
+				"token_id":     tid,
+				"yes_best_ask": ask,
+				"end_time":     ev.EndTime.UTC().Format(time.RFC3339),
+				"hours_left":   hoursLeft,
+			})
+			// The countdown is only valid until the next scan refreshes hours_left.
+			expires := now.Add(2 * c.interval())
+			if ev.EndTime.Before(expires) {
+				expires = ev.EndTime.UTC()
+			}
+			out <- models.Signal{
+				SignalType: "market_close_countdown",
+				Source:     "market_close",
+				EventID:    strPtr(ev.ID),
+				MarketID:   strPtr(mid),
+				TokenID:    strPtr(tid),
+				Strength:   clamp01(abs(ask-0.5) / 0.5),
+				Direction:  "NEUTRAL",
+				Payload:    datatypes.JSON(payload),
+				ExpiresAt:  &expires,
+				CreatedAt:  now,
+			}
+		}
+	}
+	c.setHealth(now, "healthy", nil)
+}
+
+func (c *MarketCloseCollector) setHealth(ts time.Time, status string, errStr *string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastPoll = &ts
+	c.status = status
+	c.lastError = errStr
+}
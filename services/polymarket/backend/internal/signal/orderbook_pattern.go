@@ -25,6 +25,8 @@ type OrderbookPatternCollector struct {
 
 	Config config.OrderbookPatternConfig
 
+	pacing
+
 	mu        sync.Mutex
 	lastPoll  *time.Time
 	lastError *string
@@ -34,25 +36,31 @@ type OrderbookPatternCollector struct {
 func (c *OrderbookPatternCollector) Name() string { return "orderbook_pattern" }
 
 func (c *OrderbookPatternCollector) SourceInfo() SourceInfo {
-	interval := c.Config.Interval
-	if interval <= 0 {
-		interval = 10 * time.Second
-	}
 	return SourceInfo{
 		SourceType:   "rest_poll",
 		Endpoint:     "db:market_data_health",
-		PollInterval: interval,
+		PollInterval: c.interval(),
+	}
+}
+
+func (c *OrderbookPatternCollector) interval() time.Duration {
+	if c.Config.Interval > 0 {
+		return c.Config.Interval
 	}
+	return 10 * time.Second
 }
 
 func (c *OrderbookPatternCollector) Start(ctx context.Context, out chan<- models.Signal) error {
 	if c == nil {
 		return nil
 	}
-	interval := c.Config.Interval
-	if interval <= 0 {
-		interval = 10 * time.Second
-	}
+	return c.every(ctx, c.Name(), c.interval(), func() {
+		c.CollectOnce(ctx, out)
+	})
+}
+
+// CollectOnce runs one poll with the configured thresholds.
+func (c *OrderbookPatternCollector) CollectOnce(ctx context.Context, out chan<- models.Signal) {
 	limit := c.Config.Limit
 	if limit <= 0 {
 		limit = 100
@@ -65,17 +73,17 @@ func (c *OrderbookPatternCollector) Start(ctx context.Context, out chan<- models
 	if minJump <= 0 {
 		minJump = 600
 	}
+	c.pollOnce(ctx, out, limit, minSpread, minJump)
+}
 
-	t := time.NewTicker(interval)
-	defer t.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-t.C:
-			c.pollOnce(ctx, out, limit, minSpread, minJump)
-		}
+func (c *OrderbookPatternCollector) Settings() map[string]any { return settingsMap(c.Config) }
+
+func (c *OrderbookPatternCollector) WithSettings(raw json.RawMessage) (SignalCollector, error) {
+	cfg, err := mergeSettings(c.Config, raw)
+	if err != nil {
+		return nil, err
 	}
+	return &OrderbookPatternCollector{Repo: c.Repo, Logger: c.Logger, Config: cfg}, nil
 }
 
 func (c *OrderbookPatternCollector) Stop() error { return nil }
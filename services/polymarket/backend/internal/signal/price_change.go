@@ -22,6 +22,8 @@ type PriceChangeCollector struct {
 
 	Config config.PriceChangeConfig
 
+	pacing
+
 	mu        sync.Mutex
 	lastPoll  *time.Time
 	lastError *string
@@ -31,25 +33,31 @@ type PriceChangeCollector struct {
 func (c *PriceChangeCollector) Name() string { return "price_change" }
 
 func (c *PriceChangeCollector) SourceInfo() SourceInfo {
-	interval := c.Config.Interval
-	if interval <= 0 {
-		interval = 5 * time.Second
-	}
 	return SourceInfo{
 		SourceType:   "rest_poll",
 		Endpoint:     "db:market_data_health",
-		PollInterval: interval,
+		PollInterval: c.interval(),
+	}
+}
+
+func (c *PriceChangeCollector) interval() time.Duration {
+	if c.Config.Interval > 0 {
+		return c.Config.Interval
 	}
+	return 5 * time.Second
 }
 
 func (c *PriceChangeCollector) Start(ctx context.Context, out chan<- models.Signal) error {
 	if c == nil {
 		return nil
 	}
-	interval := c.Config.Interval
-	if interval <= 0 {
-		interval = 5 * time.Second
-	}
+	return c.every(ctx, c.Name(), c.interval(), func() {
+		c.CollectOnce(ctx, out)
+	})
+}
+
+// CollectOnce runs one poll with the configured thresholds.
+func (c *PriceChangeCollector) CollectOnce(ctx context.Context, out chan<- models.Signal) {
 	limit := c.Config.Limit
 	if limit <= 0 {
 		limit = 50
@@ -62,17 +70,17 @@ func (c *PriceChangeCollector) Start(ctx context.Context, out chan<- models.Sign
 	if maxSpread <= 0 {
 		maxSpread = 400
 	}
+	c.pollOnce(ctx, out, limit, minJump, maxSpread)
+}
 
-	t := time.NewTicker(interval)
-	defer t.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-t.C:
-			c.pollOnce(ctx, out, limit, minJump, maxSpread)
-		}
+func (c *PriceChangeCollector) Settings() map[string]any { return settingsMap(c.Config) }
+
+func (c *PriceChangeCollector) WithSettings(raw json.RawMessage) (SignalCollector, error) {
+	cfg, err := mergeSettings(c.Config, raw)
+	if err != nil {
+		return nil, err
 	}
+	return &PriceChangeCollector{Repo: c.Repo, Logger: c.Logger, Config: cfg}, nil
 }
 
 func (c *PriceChangeCollector) Stop() error { return nil }
@@ -0,0 +1,281 @@
+package signal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+
+	"polymarket/internal/models"
+)
+
+var (
+	ErrUnknownCollector  = errors.New("unknown collector")
+	ErrNotReconfigurable = errors.New("collector does not support runtime settings")
+	ErrNoTestFire        = errors.New("collector does not support test-fire")
+	ErrInvalidSettings   = errors.New("invalid collector settings")
+)
+
+// Reconfigurable collectors can be rebuilt with new settings at runtime.
+// Settings reports the settings in effect, keyed as in the config file with
+// durations as strings. WithSettings returns a new, unstarted collector with
+// raw merged over them; the receiver is left untouched.
+type Reconfigurable interface {
+	Settings() map[string]any
+	WithSettings(raw json.RawMessage) (SignalCollector, error)
+}
+
+// TestFirer collectors run one collection cycle on demand. CollectOnce must
+// not persist anything, so collectors that write state while collecting
+// (wallet positions, price windows) don't implement it.
+type TestFirer interface {
+	CollectOnce(ctx context.Context, out chan<- models.Signal)
+}
+
+// TestFireResult is one sandboxed cycle: what the collector would emit
+// through the hub, and its health after the cycle.
+type TestFireResult struct {
+	Collector  string          `json:"collector"`
+	Settings   map[string]any  `json:"settings"`
+	Signals    []TestFireEntry `json:"signals"`
+	Duplicates int             `json:"duplicates"`
+	Status     string          `json:"status"`
+	LastError  *string         `json:"last_error,omitempty"`
+	DurationMS int64           `json:"duration_ms"`
+}
+
+// TestFireEntry is a normalized signal; Duplicate means the hub's dedup
+// window would drop it right now.
+type TestFireEntry struct {
+	models.Signal
+	Duplicate bool `json:"duplicate"`
+}
+
+// CollectorSettings is a registered collector with what it supports.
+type CollectorSettings struct {
+	Name           string         `json:"name"`
+	Reconfigurable bool           `json:"reconfigurable"`
+	TestFire       bool           `json:"test_fire"`
+	Settings       map[string]any `json:"settings,omitempty"`
+}
+
+// Collectors lists the registered collectors by name.
+func (h *SignalHub) Collectors() []CollectorSettings {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	out := make([]CollectorSettings, 0, len(h.collectors))
+	for _, c := range h.collectors {
+		out = append(out, describeCollector(c))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Collector describes one registered collector.
+func (h *SignalHub) Collector(name string) (CollectorSettings, error) {
+	c, err := h.collector(name)
+	if err != nil {
+		return CollectorSettings{}, err
+	}
+	return describeCollector(c), nil
+}
+
+func describeCollector(c SignalCollector) CollectorSettings {
+	out := CollectorSettings{Name: c.Name()}
+	if r, ok := c.(Reconfigurable); ok {
+		out.Reconfigurable = true
+		out.Settings = r.Settings()
+	}
+	_, out.TestFire = c.(TestFirer)
+	return out
+}
+
+func (h *SignalHub) collector(name string) (SignalCollector, error) {
+	if h == nil {
+		return nil, ErrUnknownCollector
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	c, ok := h.collectors[strings.TrimSpace(name)]
+	if !ok {
+		return nil, ErrUnknownCollector
+	}
+	return c, nil
+}
+
+// Reconfigure rebuilds the named collector with raw merged over its
+// settings and, when the hub is running, stops the old instance and starts
+// the new one. Settings last until the process restarts.
+func (h *SignalHub) Reconfigure(name string, raw json.RawMessage) (CollectorSettings, error) {
+	c, err := h.collector(name)
+	if err != nil {
+		return CollectorSettings{}, err
+	}
+	r, ok := c.(Reconfigurable)
+	if !ok {
+		return CollectorSettings{}, ErrNotReconfigurable
+	}
+	next, err := r.WithSettings(raw)
+	if err != nil {
+		return CollectorSettings{}, err
+	}
+	h.replace(c, next)
+	return describeCollector(next), nil
+}
+
+// TestFire runs one cycle of the named collector in a sandbox: raw, when
+// given, is merged over a copy of the settings without applying them, and
+// nothing is persisted, deduplicated or fanned out.
+func (h *SignalHub) TestFire(ctx context.Context, name string, raw json.RawMessage) (TestFireResult, error) {
+	c, err := h.collector(name)
+	if err != nil {
+		return TestFireResult{}, err
+	}
+	sandbox := c
+	if r, ok := c.(Reconfigurable); ok {
+		if sandbox, err = r.WithSettings(raw); err != nil {
+			return TestFireResult{}, err
+		}
+	} else if len(bytes.TrimSpace(raw)) > 0 && string(bytes.TrimSpace(raw)) != "null" {
+		return TestFireResult{}, ErrNotReconfigurable
+	}
+	t, ok := sandbox.(TestFirer)
+	if !ok {
+		return TestFireResult{}, ErrNoTestFire
+	}
+
+	start := time.Now()
+	sigs := collectOnce(ctx, t)
+	health := sandbox.Health()
+	res := TestFireResult{
+		Collector:  c.Name(),
+		Signals:    make([]TestFireEntry, 0, len(sigs)),
+		Status:     health.Status,
+		LastError:  health.LastError,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if r, ok := sandbox.(Reconfigurable); ok {
+		res.Settings = r.Settings()
+	}
+	for _, sig := range sigs {
+		sig = h.normalize(sig)
+		dup := h.wouldDrop(sig)
+		if dup {
+			res.Duplicates++
+		}
+		res.Signals = append(res.Signals, TestFireEntry{Signal: sig, Duplicate: dup})
+	}
+	return res, nil
+}
+
+// collectOnce runs one cycle of t and gathers what it emits; collectors
+// send blocking, so the channel is drained while the cycle runs.
+func collectOnce(ctx context.Context, t TestFirer) []models.Signal {
+	ch := make(chan models.Signal, 64)
+	done := make(chan struct{})
+	var sigs []models.Signal
+	go func() {
+		defer close(done)
+		for sig := range ch {
+			sigs = append(sigs, sig)
+		}
+	}()
+	t.CollectOnce(ctx, ch)
+	close(ch)
+	<-done
+	return sigs
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// settingsMap renders a config struct by its mapstructure keys, durations
+// as strings. The top-level enabled switch is left out: whether a collector
+// is registered is decided at boot.
+func settingsMap(v any) map[string]any {
+	out, _ := settingsValue(reflect.ValueOf(v)).(map[string]any)
+	if out == nil {
+		out = map[string]any{}
+	}
+	delete(out, "enabled")
+	return out
+}
+
+func settingsValue(v reflect.Value) any {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		out := map[string]any{}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			key, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+			if !f.IsExported() || key == "" || key == "-" {
+				continue
+			}
+			out[key] = settingsValue(v.Field(i))
+		}
+		return out
+	case reflect.Slice:
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = settingsValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = settingsValue(iter.Value())
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// mergeSettings overlays the JSON object raw on cur. Keys are those of
+// settingsMap; an omitted key keeps its value and a given one replaces it
+// whole, so a list can be shortened.
+func mergeSettings[T any](cur T, raw json.RawMessage) (T, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return cur, nil
+	}
+	var patch map[string]any
+	if err := json.Unmarshal(raw, &patch); err != nil {
+		return cur, fmt.Errorf("%w: %v", ErrInvalidSettings, err)
+	}
+	full := settingsMap(cur)
+	for k, v := range patch {
+		if _, ok := full[k]; !ok {
+			return cur, fmt.Errorf("%w: unknown key %q", ErrInvalidSettings, k)
+		}
+		full[k] = v
+	}
+	var next T
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+		Result: &next,
+	})
+	if err != nil {
+		return cur, err
+	}
+	if err := dec.Decode(full); err != nil {
+		return cur, fmt.Errorf("%w: %v", ErrInvalidSettings, err)
+	}
+	return next, nil
+}
@@ -0,0 +1,186 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+type jumpRepo struct {
+	repository.Repository
+	mu       sync.Mutex
+	polls    int
+	inserted int
+}
+
+func (r *jumpRepo) ListYesTokenJumpCandidates(_ context.Context, _ int, minJumpBps float64, _ float64) ([]repository.TokenJumpCandidate, error) {
+	r.mu.Lock()
+	r.polls++
+	r.mu.Unlock()
+	if minJumpBps > 800 {
+		return nil, nil
+	}
+	return []repository.TokenJumpCandidate{{TokenID: "t1", MarketID: "m1", PriceJumpBps: 900}}, nil
+}
+
+func (r *jumpRepo) InsertSignal(context.Context, *models.Signal) error {
+	r.inserted++
+	return nil
+}
+
+func (r *jumpRepo) UpsertSignalSource(context.Context, *models.SignalSource) error { return nil }
+
+func TestMergeSettings(t *testing.T) {
+	cur := config.WeatherAPIConfig{
+		Enabled: true,
+		Cities:  []string{"nyc", "london", "tokyo"},
+		Sources: []config.WeatherAPISource{{Name: "a", Endpoint: "http://a", PollInterval: time.Minute}},
+	}
+	got := settingsMap(cur)
+	if _, ok := got["enabled"]; ok {
+		t.Fatal("enabled exposed in settings")
+	}
+	if src := got["sources"].([]any)[0].(map[string]any); src["poll_interval"] != "1m0s" {
+		t.Fatalf("poll_interval = %v", src["poll_interval"])
+	}
+
+	next, err := mergeSettings(cur, json.RawMessage(`{"cities":["paris"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Cities) != 1 || next.Cities[0] != "paris" || len(next.Sources) != 1 || next.Sources[0].PollInterval != time.Minute {
+		t.Fatalf("merged = %+v", next)
+	}
+	if len(cur.Cities) != 3 {
+		t.Fatal("merge modified the current settings")
+	}
+
+	pc, err := mergeSettings(config.PriceChangeConfig{MinJumpBps: 500, Limit: 50}, json.RawMessage(`{"interval":"15s","min_jump_bps":300}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pc.Interval != 15*time.Second || pc.MinJumpBps != 300 || pc.Limit != 50 {
+		t.Fatalf("price change = %+v", pc)
+	}
+
+	for _, raw := range []string{`{"min_jump":1}`, `{"limit":"many"}`, `[1]`} {
+		if _, err := mergeSettings(config.PriceChangeConfig{}, json.RawMessage(raw)); !errors.Is(err, ErrInvalidSettings) {
+			t.Fatalf("%s: err = %v, want invalid settings", raw, err)
+		}
+	}
+}
+
+func TestHubReconfigureAndTestFire(t *testing.T) {
+	repo := &jumpRepo{}
+	hub := NewHub(repo, nil)
+	hub.Register(&PriceChangeCollector{Repo: repo, Config: config.PriceChangeConfig{MinJumpBps: 500, Interval: time.Hour}})
+	hub.Register(&BinanceDepthCollector{})
+	ctx := context.Background()
+
+	// A sandbox override is tried, not applied, and nothing is persisted.
+	res, err := hub.TestFire(ctx, "price_change", json.RawMessage(`{"min_jump_bps":900}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Signals) != 0 || res.Status != "healthy" || res.Settings["min_jump_bps"] != 900.0 {
+		t.Fatalf("sandboxed = %+v", res)
+	}
+	res, err = hub.TestFire(ctx, "price_change", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Signals) != 2 || res.Signals[0].ExpiresAt == nil || res.Duplicates != 0 {
+		t.Fatalf("test-fire = %+v", res)
+	}
+	if repo.inserted != 0 {
+		t.Fatalf("test-fire persisted %d signals", repo.inserted)
+	}
+	if cur, _ := hub.Collector("price_change"); cur.Settings["min_jump_bps"] != 500.0 {
+		t.Fatalf("settings after test-fire = %v", cur.Settings)
+	}
+
+	// Once the hub has seen a signal, test-fire flags the repeat.
+	hub.Ingest(ctx, res.Signals[0].Signal)
+	res, _ = hub.TestFire(ctx, "price_change", nil)
+	if res.Duplicates != 1 {
+		t.Fatalf("duplicates = %d, want 1", res.Duplicates)
+	}
+
+	item, err := hub.Reconfigure("price_change", json.RawMessage(`{"min_jump_bps":900}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Settings["min_jump_bps"] != 900.0 || !item.TestFire {
+		t.Fatalf("reconfigured = %+v", item)
+	}
+	res, _ = hub.TestFire(ctx, "price_change", nil)
+	if len(res.Signals) != 0 {
+		t.Fatalf("new settings not in effect: %+v", res)
+	}
+
+	if _, err := hub.Reconfigure("price_change", json.RawMessage(`{"nope":1}`)); !errors.Is(err, ErrInvalidSettings) {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := hub.Reconfigure("binance_ws", json.RawMessage(`{}`)); !errors.Is(err, ErrNotReconfigurable) {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := hub.TestFire(ctx, "binance_ws", nil); !errors.Is(err, ErrNoTestFire) {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := hub.TestFire(ctx, "missing", nil); !errors.Is(err, ErrUnknownCollector) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestHubReconfigureRestartsRunningCollector(t *testing.T) {
+	repo := &jumpRepo{}
+	hub := NewHub(repo, nil)
+	old := &PriceChangeCollector{Repo: repo, Config: config.PriceChangeConfig{Interval: time.Hour}}
+	hub.Register(old)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		_ = hub.Run(ctx)
+		close(done)
+	}()
+
+	// Wait for Run to start the collector, then swap in a fast one.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		hub.mu.RLock()
+		started := hub.cancels["price_change"] != nil
+		hub.mu.RUnlock()
+		if started {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("collector not started")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, err := hub.Reconfigure("price_change", json.RawMessage(`{"interval":"10ms"}`)); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		repo.mu.Lock()
+		n := repo.polls
+		repo.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("reconfigured collector never polled")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
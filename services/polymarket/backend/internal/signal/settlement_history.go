@@ -24,6 +24,8 @@ type SettlementHistoryCollector struct {
 	Interval   time.Duration
 	MinSamples int64
 
+	pacing
+
 	mu      sync.Mutex
 	lastRun *time.Time
 	lastErr *string
@@ -49,17 +51,9 @@ func (c *SettlementHistoryCollector) Start(ctx context.Context, out chan<- model
 	// Run immediately on start.
 	c.runOnce(ctx, out, time.Now().UTC())
 
-	t := time.NewTicker(interval)
-	defer t.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-t.C:
-			c.runOnce(ctx, out, time.Now().UTC())
-		}
-	}
+	return c.every(ctx, c.Name(), interval, func() {
+		c.runOnce(ctx, out, time.Now().UTC())
+	})
 }
 
 func (c *SettlementHistoryCollector) runOnce(ctx context.Context, out chan<- models.Signal, now time.Time) {
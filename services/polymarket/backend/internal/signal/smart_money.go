@@ -0,0 +1,363 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/config"
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// SmartMoneyCollector polls Polymarket's public positions API for tracked (peer benchmark) wallets,
+// persists their position changes, and emits "smart_money_move" when a wallet builds a large position.
+// The first poll of a wallet with no stored positions only records a baseline.
+type SmartMoneyCollector struct {
+	Repo   repository.Repository
+	HTTP   *http.Client
+	Logger *zap.Logger
+
+	Config config.SmartMoneyConfig
+
+	pacing
+
+	mu        sync.Mutex
+	seeded    map[string]bool
+	lastPoll  *time.Time
+	lastError *string
+	status    string
+}
+
+// walletPosition mirrors one row of the data-api /positions response.
+type walletPosition struct {
+	Asset        string  `json:"asset"`
+	ConditionID  string  `json:"conditionId"`
+	Size         float64 `json:"size"`
+	AvgPrice     float64 `json:"avgPrice"`
+	CurPrice     float64 `json:"curPrice"`
+	CurrentValue float64 `json:"currentValue"`
+	Title        string  `json:"title"`
+	Outcome      string  `json:"outcome"`
+}
+
+func (c *SmartMoneyCollector) Name() string { return "smart_money" }
+
+func (c *SmartMoneyCollector) SourceInfo() SourceInfo {
+	return SourceInfo{
+		SourceType:   "api_poll",
+		Endpoint:     c.endpoint(),
+		PollInterval: c.pollInterval(),
+	}
+}
+
+func (c *SmartMoneyCollector) Start(ctx context.Context, out chan<- models.Signal) error {
+	if c == nil {
+		return nil
+	}
+	if c.HTTP == nil {
+		c.HTTP = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	// Run immediately once so a fresh wallet gets its baseline.
+	c.pollOnce(ctx, out)
+
+	return c.every(ctx, c.Name(), c.pollInterval(), func() {
+		c.pollOnce(ctx, out)
+	})
+}
+
+func (c *SmartMoneyCollector) Settings() map[string]any { return settingsMap(c.Config) }
+
+// WithSettings returns a fresh collector; wallets without stored positions
+// record a baseline again on its first poll.
+func (c *SmartMoneyCollector) WithSettings(raw json.RawMessage) (SignalCollector, error) {
+	cfg, err := mergeSettings(c.Config, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &SmartMoneyCollector{Repo: c.Repo, HTTP: c.HTTP, Logger: c.Logger, Config: cfg}, nil
+}
+
+func (c *SmartMoneyCollector) Stop() error { return nil }
+
+func (c *SmartMoneyCollector) Health() HealthStatus {
+	if c == nil {
+		return HealthStatus{Status: "unknown"}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := c.status
+	if strings.TrimSpace(status) == "" {
+		status = "unknown"
+	}
+	return HealthStatus{
+		Status:     status,
+		LastPollAt: c.lastPoll,
+		LastError:  c.lastError,
+		Details:    map[string]any{"wallets": len(cleanWalletList(c.Config.Wallets))},
+	}
+}
+
+func (c *SmartMoneyCollector) endpoint() string {
+	if v := strings.TrimSpace(c.Config.Endpoint); v != "" {
+		return v
+	}
+	return "https://data-api.polymarket.com/positions"
+}
+
+func (c *SmartMoneyCollector) pollInterval() time.Duration {
+	if c.Config.PollInterval > 0 {
+		return c.Config.PollInterval
+	}
+	return time.Minute
+}
+
+func (c *SmartMoneyCollector) minDeltaUSD() float64 {
+	if c.Config.MinDeltaUSD > 0 {
+		return c.Config.MinDeltaUSD
+	}
+	return 1000
+}
+
+func (c *SmartMoneyCollector) pollOnce(ctx context.Context, out chan<- models.Signal) {
+	now := time.Now().UTC()
+	if c.Repo == nil {
+		c.setHealth(now, "down", strPtr("repo unavailable"))
+		return
+	}
+	wallets := cleanWalletList(c.Config.Wallets)
+	if len(wallets) == 0 {
+		c.setHealth(now, "degraded", stringPtr("no wallets configured"))
+		return
+	}
+
+	okCount := 0
+	var lastErr error
+	for _, wallet := range wallets {
+		if err := c.syncWallet(ctx, out, wallet, now); err != nil {
+			lastErr = err
+			if c.Logger != nil {
+				c.Logger.Warn("smart money wallet sync failed", zap.String("wallet", wallet), zap.Error(err))
+			}
+			continue
+		}
+		okCount++
+	}
+
+	switch {
+	case okCount == len(wallets):
+		c.setHealth(now, "healthy", nil)
+	case okCount > 0:
+		c.setHealth(now, "degraded", stringPtr(lastErr.Error()))
+	default:
+		c.setHealth(now, "down", stringPtr(lastErr.Error()))
+	}
+}
+
+func (c *SmartMoneyCollector) syncWallet(ctx context.Context, out chan<- models.Signal, wallet string, now time.Time) error {
+	fetched, err := c.fetchPositions(ctx, wallet)
+	if err != nil {
+		return err
+	}
+	stored, err := c.Repo.ListWalletPositions(ctx, wallet)
+	if err != nil {
+		return err
+	}
+	prevByToken := make(map[string]models.WalletPosition, len(stored))
+	for _, p := range stored {
+		prevByToken[p.TokenID] = p
+	}
+
+	c.mu.Lock()
+	if c.seeded == nil {
+		c.seeded = map[string]bool{}
+	}
+	baseline := len(stored) == 0 && !c.seeded[wallet]
+	c.seeded[wallet] = true
+	c.mu.Unlock()
+
+	conditionIDs := make([]string, 0, len(fetched))
+	for _, p := range fetched {
+		if id := strings.TrimSpace(p.ConditionID); id != "" {
+			conditionIDs = append(conditionIDs, id)
+		}
+	}
+	marketByCondition := map[string]string{}
+	if markets, err := c.Repo.FindMarketsByConditionIDs(ctx, conditionIDs); err == nil {
+		for _, m := range markets {
+			marketByCondition[m.ConditionID] = m.ID
+		}
+	}
+
+	minDelta := decimal.NewFromFloat(c.minDeltaUSD())
+	seen := make(map[string]struct{}, len(fetched))
+	for _, p := range fetched {
+		tokenID := strings.TrimSpace(p.Asset)
+		if tokenID == "" || p.Size <= 0 {
+			continue
+		}
+		seen[tokenID] = struct{}{}
+		item := models.WalletPosition{
+			Wallet:       wallet,
+			TokenID:      tokenID,
+			ConditionID:  strings.TrimSpace(p.ConditionID),
+			MarketID:     marketByCondition[strings.TrimSpace(p.ConditionID)],
+			Outcome:      strings.TrimSpace(p.Outcome),
+			Title:        strings.TrimSpace(p.Title),
+			Size:         decimal.NewFromFloat(p.Size),
+			AvgPrice:     decimal.NewFromFloat(p.AvgPrice),
+			CurrentPrice: decimal.NewFromFloat(p.CurPrice),
+			CurrentValue: decimal.NewFromFloat(p.CurrentValue),
+			ObservedAt:   now,
+		}
+		if !baseline {
+			prev := prevByToken[tokenID]
+			if !item.Size.Equal(prev.Size) {
+				change := newWalletPositionChange(item, prev.Size, now)
+				if change.DeltaSize.IsPositive() && change.DeltaUSD.GreaterThanOrEqual(minDelta) {
+					change.SignalEmitted = c.emit(out, change, item.ConditionID, now)
+				}
+				if err := c.Repo.InsertWalletPositionChange(ctx, &change); err != nil {
+					return err
+				}
+			}
+		}
+		if err := c.Repo.UpsertWalletPosition(ctx, &item); err != nil {
+			return err
+		}
+	}
+
+	// Positions missing from the response were closed or redeemed.
+	for tokenID, prev := range prevByToken {
+		if _, ok := seen[tokenID]; ok {
+			continue
+		}
+		closed := prev
+		closed.Size = decimal.Zero
+		change := newWalletPositionChange(closed, prev.Size, now)
+		if err := c.Repo.InsertWalletPositionChange(ctx, &change); err != nil {
+			return err
+		}
+		if err := c.Repo.DeleteWalletPosition(ctx, wallet, tokenID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newWalletPositionChange(cur models.WalletPosition, prevSize decimal.Decimal, now time.Time) models.WalletPositionChange {
+	delta := cur.Size.Sub(prevSize)
+	return models.WalletPositionChange{
+		Wallet:     cur.Wallet,
+		TokenID:    cur.TokenID,
+		MarketID:   cur.MarketID,
+		Outcome:    cur.Outcome,
+		Title:      cur.Title,
+		PrevSize:   prevSize,
+		NewSize:    cur.Size,
+		DeltaSize:  delta,
+		DeltaUSD:   delta.Mul(cur.CurrentPrice).Abs(),
+		Price:      cur.CurrentPrice,
+		ObservedAt: now,
+	}
+}
+
+func (c *SmartMoneyCollector) emit(out chan<- models.Signal, change models.WalletPositionChange, conditionID string, now time.Time) bool {
+	payload, _ := json.Marshal(map[string]any{
+		"wallet":       change.Wallet,
+		"condition_id": conditionID,
+		"token_id":     change.TokenID,
+		"outcome":      change.Outcome,
+		"title":        change.Title,
+		"prev_size":    change.PrevSize.InexactFloat64(),
+		"new_size":     change.NewSize.InexactFloat64(),
+		"delta_size":   change.DeltaSize.InexactFloat64(),
+		"delta_usd":    change.DeltaUSD.InexactFloat64(),
+		"price":        change.Price.InexactFloat64(),
+	})
+	// Direction is the outcome being built: YES, NO or a non-binary code.
+	direction := models.OutcomeCode(change.Outcome)
+	if direction == "" {
+		direction = "NEUTRAL"
+	}
+	// Strength saturates at 10x the configured minimum build size.
+	strength := clamp01(0.5 + 0.5*change.DeltaUSD.InexactFloat64()/(10*c.minDeltaUSD()))
+	expires := now.Add(2 * c.pollInterval())
+	if expires.Before(now.Add(15 * time.Minute)) {
+		expires = now.Add(15 * time.Minute)
+	}
+	sig := models.Signal{
+		SignalType: "smart_money_move",
+		Source:     "smart_money",
+		MarketID:   strPtr(change.MarketID),
+		TokenID:    strPtr(change.TokenID),
+		Strength:   strength,
+		Direction:  direction,
+		Payload:    datatypes.JSON(payload),
+		ExpiresAt:  &expires,
+		CreatedAt:  now,
+	}
+	select {
+	case out <- sig:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *SmartMoneyCollector) fetchPositions(ctx context.Context, wallet string) ([]walletPosition, error) {
+	url := c.endpoint()
+	sep := "?"
+	if strings.Contains(url, "?") {
+		sep = "&"
+	}
+	url = url + sep + "user=" + urlQueryEscape(wallet) + "&sizeThreshold=0&limit=500"
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("http %d", resp.StatusCode)
+	}
+	var items []walletPosition
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (c *SmartMoneyCollector) setHealth(ts time.Time, status string, errStr *string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastPoll = &ts
+	c.status = status
+	c.lastError = errStr
+}
+
+func cleanWalletList(items []string) []string {
+	out := make([]string, 0, len(items))
+	seen := map[string]struct{}{}
+	for _, raw := range items {
+		val := strings.ToLower(strings.TrimSpace(raw))
+		if val == "" {
+			continue
+		}
+		if _, ok := seen[val]; ok {
+			continue
+		}
+		seen[val] = struct{}{}
+		out = append(out, val)
+	}
+	return out
+}
@@ -0,0 +1,226 @@
+package signal
+
+import "sort"
+
+// SignalTypeInfo documents one signal type for API clients. TTL and
+// DedupWindow are the hub's effective values.
+type SignalTypeInfo struct {
+	Type        string         `json:"type"`
+	Source      string         `json:"source"`
+	Description string         `json:"description"`
+	Payload     []PayloadField `json:"payload"`
+	TTL         string         `json:"ttl"`
+	DedupWindow string         `json:"dedup_window"`
+}
+
+// PayloadField documents one key of a signal's JSON payload.
+type PayloadField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+type signalTypeDoc struct {
+	source      string
+	description string
+	payload     []PayloadField
+}
+
+// signalTypes registers every signal type a collector emits. Add an entry
+// with each new signal type so /api/v2/signals/types stays complete.
+var signalTypes = map[string]signalTypeDoc{
+	"arb_sum_deviation": {
+		source:      "internal_scan",
+		description: "Outcome prices of a market or event sum away from 1; YES buys every outcome, NO buys their complements.",
+		payload: []PayloadField{
+			{"sum", "number", "sum of outcome prices"},
+			{"deviation_pct", "number", "|sum - 1| in percent"},
+			{"token_ids", "array", "outcome token ids"},
+			{"outcomes", "array", "outcome labels"},
+			{"prices", "array", "outcome prices"},
+			{"outcome_set", "string", "market or event"},
+		},
+	},
+	"no_bias": {
+		source:      "internal_scan",
+		description: "A labelled market's NO price is cheap against the label's historical NO resolution rate.",
+		payload: []PayloadField{
+			{"label", "string", "market label"},
+			{"no_rate", "number", "historical NO rate of the label"},
+			{"no_price", "number", "current NO price"},
+			{"ev_pct", "number", "expected value of buying NO, percent"},
+			{"price_min", "number", "lower NO price bound"},
+			{"price_max", "number", "upper NO price bound"},
+			{"min_ev_pct", "number", "EV floor applied"},
+		},
+	},
+	"liquidity_gap": {
+		source:      "internal_scan",
+		description: "A YES token's spread is wide enough to quote inside; strength scales with spread (1000 bps = 1).",
+		payload:     []PayloadField{},
+	},
+	"fdv_overpriced": {
+		source:      "internal_scan",
+		description: "A pre-market FDV market sits in the NO sweet spot inside the entry window before TGE.",
+		payload: []PayloadField{
+			{"label", "string", "always pre_market_fdv"},
+			{"days_to_end", "number", "days until the event ends"},
+			{"entry_window", "array", "[min, max] days before TGE"},
+			{"no_price", "number", "current NO price"},
+			{"no_sweet_spot", "array", "[min, max] NO price"},
+			{"expected_no_rate", "number", "assumed NO resolution rate"},
+		},
+	},
+	"price_anomaly": {
+		source:      "internal_scan",
+		description: "A YES price sits at an extreme and is expected to revert.",
+		payload: []PayloadField{
+			{"anomaly_type", "string", "which extreme was hit"},
+			{"yes_price", "number", "current YES price"},
+		},
+	},
+	"btc_depth_imbalance": {
+		source:      "binance_ws",
+		description: "Binance order book depth is lopsided between bids and asks.",
+		payload: []PayloadField{
+			{"symbol", "string", "Binance symbol"},
+			{"bid_notional", "number", "bid notional over the levels"},
+			{"ask_notional", "number", "ask notional over the levels"},
+			{"ratio", "number", "bid/ask notional ratio"},
+			{"levels", "integer", "book levels summed"},
+		},
+	},
+	"btc_price_change": {
+		source:      "binance_price",
+		description: "Spot moved more than the trigger over the sampling window.",
+		payload: []PayloadField{
+			{"price", "number", "latest price"},
+			{"base_price", "number", "price at the window start"},
+			{"window_seconds", "number", "window length"},
+			{"change_pct", "number", "move in percent"},
+			{"trigger_pct", "number", "trigger applied"},
+		},
+	},
+	"crypto_price_change": {
+		source:      "crypto_symbol_map",
+		description: "A spot move fanned out to each crypto threshold market mapped to the symbol.",
+		payload: []PayloadField{
+			{"asset", "string", "underlying asset, e.g. BTC"},
+			{"strike", "number", "market threshold"},
+			{"comparator", "string", "above or below"},
+			{"distance_pct", "number", "spot distance from strike, percent"},
+			{"deadline", "string", "RFC3339 market deadline, when known"},
+			{"hours_left", "number", "hours to deadline, when known"},
+			{"change_pct", "number", "spot move in percent"},
+		},
+	},
+	"weather_deviation": {
+		source:      "weather_api",
+		description: "Blended forecasts for a city disagree with the market-implied temperature.",
+		payload: []PayloadField{
+			{"city", "string", "forecast city"},
+			{"forecast_temp_f", "number", "weighted forecast, °F"},
+			{"details", "object", "per-source forecasts"},
+		},
+	},
+	"news_alpha": {
+		source:      "price_change",
+		description: "A sharp price jump on a token, read as news to fade or follow.",
+		payload:     priceMovePayload,
+	},
+	"volatility_spread": {
+		source:      "price_change",
+		description: "A price jump with a wide spread, read as mispriced volatility.",
+		payload:     priceMovePayload,
+	},
+	"fear_spike": {
+		source:      "orderbook_pattern",
+		description: "Book jumped with spread blowing out, a panic move likely to revert.",
+		payload:     bookMovePayload,
+	},
+	"mm_inventory_skew": {
+		source:      "orderbook_pattern",
+		description: "Quotes skewed the way a market maker offloads inventory.",
+		payload:     bookMovePayload,
+	},
+	"certainty_sweep": {
+		source:      "certainty_sweep",
+		description: "A market's YES ask is at 0.97+ or 0.03-, near-certain but not yet settled.",
+		payload: []PayloadField{
+			{"event_id", "string", ""},
+			{"market_id", "string", ""},
+			{"token_id", "string", "YES token"},
+			{"yes_best_ask", "number", "best YES ask"},
+		},
+	},
+	"market_close_countdown": {
+		source:      "market_close",
+		description: "A near-certain market is close to its end time.",
+		payload: []PayloadField{
+			{"event_id", "string", ""},
+			{"market_id", "string", ""},
+			{"token_id", "string", "YES token"},
+			{"yes_best_ask", "number", "best YES ask"},
+			{"end_time", "string", "RFC3339 end time"},
+			{"hours_left", "number", "hours to end time"},
+		},
+	},
+	"settlement_no_rates": {
+		source:      "settlement_history",
+		description: "Refreshed NO resolution rates per label from settlement history.",
+		payload: []PayloadField{
+			{"min_samples", "integer", "samples a label needs to be included"},
+			{"rows", "array", "per-label rates"},
+			{"updated_at", "string", "RFC3339"},
+		},
+	},
+	"smart_money_move": {
+		source:      "smart_money",
+		description: "A tracked wallet changed its position.",
+		payload: []PayloadField{
+			{"wallet", "string", "wallet address"},
+			{"condition_id", "string", ""},
+			{"token_id", "string", ""},
+			{"outcome", "string", ""},
+			{"title", "string", "market title"},
+			{"prev_size", "number", "shares before"},
+			{"new_size", "number", "shares after"},
+			{"delta_size", "number", "share change"},
+			{"delta_usd", "number", "change in USD"},
+			{"price", "number", "price at the change"},
+		},
+	},
+}
+
+var priceMovePayload = []PayloadField{
+	{"token_id", "string", ""},
+	{"market_id", "string", ""},
+	{"price_jump_bps", "number", "last price jump"},
+	{"spread_bps", "number", "current spread"},
+	{"updated_at", "string", "book update time"},
+}
+
+var bookMovePayload = []PayloadField{
+	{"token_id", "string", ""},
+	{"market_id", "string", ""},
+	{"spread_bps", "number", "current spread"},
+	{"price_jump_bps", "number", "last price jump"},
+	{"updated_at", "string", "book update time"},
+}
+
+// SignalTypes lists the registered signal types by name.
+func SignalTypes() []SignalTypeInfo {
+	out := make([]SignalTypeInfo, 0, len(signalTypes))
+	for name, doc := range signalTypes {
+		out = append(out, SignalTypeInfo{
+			Type:        name,
+			Source:      doc.source,
+			Description: doc.description,
+			Payload:     doc.payload,
+			TTL:         defaultSignalTTL(name).String(),
+			DedupWindow: defaultDedupWindow(name).String(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out
+}
@@ -25,6 +25,8 @@ type WeatherAPICollector struct {
 	Cities  []string
 	Sources []config.WeatherAPISource
 
+	pacing
+
 	mu        sync.Mutex
 	lastPoll  *time.Time
 	lastError *string
@@ -61,16 +63,36 @@ func (c *WeatherAPICollector) Start(ctx context.Context, out chan<- models.Signa
 	// Run immediately once.
 	c.pollOnce(ctx, out)
 
-	t := time.NewTicker(interval)
-	defer t.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-t.C:
-			c.pollOnce(ctx, out)
+	return c.every(ctx, c.Name(), interval, func() {
+		c.pollOnce(ctx, out)
+	})
+}
+
+// CollectOnce fetches every city once.
+func (c *WeatherAPICollector) CollectOnce(ctx context.Context, out chan<- models.Signal) {
+	c.pollOnce(ctx, out)
+}
+
+func (c *WeatherAPICollector) Settings() map[string]any {
+	return settingsMap(config.WeatherAPIConfig{Cities: c.Cities, Sources: c.Sources})
+}
+
+// WithSettings takes cities and sources; every source needs an endpoint.
+func (c *WeatherAPICollector) WithSettings(raw json.RawMessage) (SignalCollector, error) {
+	cfg, err := mergeSettings(config.WeatherAPIConfig{Cities: c.Cities, Sources: c.Sources}, raw)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range cfg.Sources {
+		if strings.TrimSpace(s.Endpoint) == "" {
+			return nil, fmt.Errorf("%w: sources[%d] has no endpoint", ErrInvalidSettings, i)
 		}
 	}
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &WeatherAPICollector{HTTP: httpClient, Logger: c.Logger, Cities: cfg.Cities, Sources: cfg.Sources}, nil
 }
 
 func (c *WeatherAPICollector) Stop() error { return nil }
@@ -114,59 +114,63 @@ func (s *ArbitrageSumStrategy) Evaluate(ctx context.Context, signals []models.Si
 		maxLegs = 10
 	}
 
+	// A signal naming a market is about that non-binary market's outcomes;
+	// otherwise the set is the YES tokens of the event's binary markets.
+	setMarketID := ""
+	if sig.MarketID != nil {
+		setMarketID = strings.TrimSpace(*sig.MarketID)
+	}
 	markets, err := s.Repo.ListMarketsByEventID(ctx, eventID)
 	if err != nil {
 		return nil, err
 	}
-	if len(markets) < 2 {
-		return nil, nil
-	}
-	if maxLegs > 0 && len(markets) > maxLegs {
-		sort.Slice(markets, func(i, j int) bool {
-			li := decimal.Zero
-			lj := decimal.Zero
-			if markets[i].Liquidity != nil {
-				li = *markets[i].Liquidity
-			}
-			if markets[j].Liquidity != nil {
-				lj = *markets[j].Liquidity
+	if setMarketID != "" {
+		kept := make([]models.Market, 0, 1)
+		for _, m := range markets {
+			if m.ID == setMarketID {
+				kept = append(kept, m)
 			}
-			return li.GreaterThan(lj)
-		})
-		markets = markets[:maxLegs]
+		}
+		markets = kept
 	}
-	marketIDs := make([]string, 0, len(markets))
+	if len(markets) == 0 {
+		return nil, nil
+	}
+	allMarketIDs := make([]string, 0, len(markets))
 	for _, m := range markets {
 		if m.ID != "" {
-			marketIDs = append(marketIDs, m.ID)
+			allMarketIDs = append(allMarketIDs, m.ID)
 		}
 	}
-
-	tokens, err := s.Repo.ListTokensByMarketIDs(ctx, marketIDs)
+	tokens, err := s.Repo.ListTokensByMarketIDs(ctx, allMarketIDs)
 	if err != nil {
 		return nil, err
 	}
-	yesTokenByMarket := map[string]string{}
-	noTokenByMarket := map[string]string{}
-	for _, tok := range tokens {
-		if tok.MarketID == "" || tok.ID == "" {
-			continue
-		}
-		switch strings.ToLower(strings.TrimSpace(tok.Outcome)) {
-		case "yes":
-			yesTokenByMarket[tok.MarketID] = tok.ID
-		case "no":
-			noTokenByMarket[tok.MarketID] = tok.ID
-		}
+	set, ok := pickOutcomeSet(markets, tokens, setMarketID)
+	if !ok || len(set.Legs) < 2 {
+		return nil, nil
 	}
-	yesTokenIDs := make([]string, 0, len(markets))
-	for _, m := range markets {
-		if id := yesTokenByMarket[m.ID]; id != "" {
-			yesTokenIDs = append(yesTokenIDs, id)
+	if setMarketID == "" && maxLegs > 0 && len(set.Legs) > maxLegs {
+		liquidity := map[string]decimal.Decimal{}
+		for _, m := range markets {
+			if m.Liquidity != nil {
+				liquidity[m.ID] = *m.Liquidity
+			}
+		}
+		sort.SliceStable(set.Legs, func(i, j int) bool {
+			return liquidity[set.Legs[i].MarketID].GreaterThan(liquidity[set.Legs[j].MarketID])
+		})
+		set.Legs = set.Legs[:maxLegs]
+	}
+	marketIDs := make([]string, 0, len(set.Legs))
+	seenMarket := map[string]struct{}{}
+	yesTokenIDs := make([]string, 0, len(set.Legs))
+	for _, leg := range set.Legs {
+		yesTokenIDs = append(yesTokenIDs, leg.TokenID)
+		if _, ok := seenMarket[leg.MarketID]; !ok {
+			seenMarket[leg.MarketID] = struct{}{}
+			marketIDs = append(marketIDs, leg.MarketID)
 		}
-	}
-	if len(yesTokenIDs) < 2 {
-		return nil, nil
 	}
 	yesBooks, _ := s.Repo.ListOrderbookLatestByTokenIDs(ctx, yesTokenIDs)
 	yesTrades, _ := s.Repo.ListLastTradePricesByTokenIDs(ctx, yesTokenIDs)
@@ -178,6 +182,7 @@ func (s *ArbitrageSumStrategy) Evaluate(ctx context.Context, signals []models.Si
 	for _, tr := range yesTrades {
 		yesTradeByToken[tr.TokenID] = tr
 	}
+	// sumYes sums every outcome of the set, which should total 1.
 	sumYes := 0.0
 	for _, tokenID := range yesTokenIDs {
 		price, ok := currentPrice(yesBookByToken[tokenID], yesTradeByToken[tokenID])
@@ -191,21 +196,24 @@ func (s *ArbitrageSumStrategy) Evaluate(ctx context.Context, signals []models.Si
 		return nil, nil
 	}
 
-	// Determine trade direction from current sum.
+	// Determine trade direction from current sum: below 1 buy every outcome,
+	// above 1 buy every complement. Non-binary outcomes have no complement
+	// token, so an overpriced non-binary market is left alone.
 	action := "BUY_YES"
 	if sumYes > 1.0 {
+		if setMarketID != "" {
+			return nil, nil
+		}
 		action = "BUY_NO"
 	}
 
 	// Fetch books for the tokens we intend to buy.
-	buyTokenIDs := make([]string, 0, len(markets))
-	legs := make([]map[string]any, 0, len(markets))
-	for _, m := range markets {
-		var tokenID string
-		if action == "BUY_YES" {
-			tokenID = yesTokenByMarket[m.ID]
-		} else {
-			tokenID = noTokenByMarket[m.ID]
+	buyTokenIDs := make([]string, 0, len(set.Legs))
+	legs := make([]map[string]any, 0, len(set.Legs))
+	for _, leg := range set.Legs {
+		tokenID, outcome := leg.TokenID, leg.Outcome
+		if action == "BUY_NO" {
+			tokenID, outcome = leg.NoTokenID, models.OutcomeNo
 		}
 		if tokenID == "" {
 			return nil, nil
@@ -213,8 +221,9 @@ func (s *ArbitrageSumStrategy) Evaluate(ctx context.Context, signals []models.Si
 		buyTokenIDs = append(buyTokenIDs, tokenID)
 		legs = append(legs, map[string]any{
 			"token_id":  tokenID,
-			"market_id": m.ID,
-			"direction": action,
+			"market_id": leg.MarketID,
+			"outcome":   outcome,
+			"direction": models.Direction("BUY", outcome),
 		})
 	}
 
@@ -353,8 +362,12 @@ func (s *ArbitrageSumStrategy) Evaluate(ctx context.Context, signals []models.Si
 	legsJSON, _ := json.Marshal(legs)
 	marketIDsJSON, _ := json.Marshal(marketIDs)
 	signalIDsJSON, _ := json.Marshal([]uint64{sig.ID})
-	reasoning := fmt.Sprintf("arb_sum event=%s sum_yes=%.4f deviation=%.2f%% action=%s cost_per_share=%s profit_per_share=%s",
-		eventID, sumYes, devPct, action, costPerShare.StringFixed(4), profitPerShare.StringFixed(4))
+	setName := "event"
+	if setMarketID != "" {
+		setName = "market:" + setMarketID
+	}
+	reasoning := fmt.Sprintf("arb_sum event=%s set=%s outcomes=%d sum=%.4f deviation=%.2f%% action=%s cost_per_share=%s profit_per_share=%s",
+		eventID, setName, len(legs), sumYes, devPct, action, costPerShare.StringFixed(4), profitPerShare.StringFixed(4))
 
 	opp := models.Opportunity{
 		Status:     "active",
@@ -378,6 +391,17 @@ func (s *ArbitrageSumStrategy) Evaluate(ctx context.Context, signals []models.Si
 	return []models.Opportunity{opp}, nil
 }
 
+// pickOutcomeSet returns the set for marketID, or the event's binary set
+// when marketID is empty.
+func pickOutcomeSet(markets []models.Market, tokens []models.Token, marketID string) (models.OutcomeSet, bool) {
+	for _, set := range models.OutcomeSets(markets, tokens) {
+		if set.MarketID == marketID {
+			return set, true
+		}
+	}
+	return models.OutcomeSet{}, false
+}
+
 func avgAskForSize(levels []askLevel, size decimal.Decimal) (avg decimal.Decimal, worst decimal.Decimal, ok bool) {
 	if size.LessThanOrEqual(decimal.Zero) {
 		return decimal.Zero, decimal.Zero, false
@@ -16,30 +16,49 @@ import (
 	"polymarket/internal/repository"
 )
 
-// CertaintySweepStrategy (P2) consumes "certainty_sweep" and proposes trades when the market is priced near certainty.
+// CertaintySweepStrategy (P2) consumes "certainty_sweep" and "market_close_countdown" and proposes trades when the
+// market is priced near certainty.
 // MVP:
-// - If YES ask >= 0.97: BUY_YES if edge vs expected 0.995 is positive.
-// - If YES ask <= 0.03: BUY_NO if edge vs expected 0.995 is positive.
+// - If YES ask >= threshold: BUY_YES if edge vs expected payout is positive.
+// - If YES ask <= 1-threshold: BUY_NO if edge vs expected payout is positive.
+//
+// Countdown signals carry hours_left; the threshold then comes from a time ladder (per-label overrides first).
+// Signals without hours_left use the flat 0.97 / 0.995 rule.
 type CertaintySweepStrategy struct {
 	Repo   repository.Repository
 	Logger *zap.Logger
 
 	mu sync.RWMutex
 
-	MinEdgePct float64
+	MinEdgePct     float64
+	Ladder         []SweepRung
+	LabelOverrides map[string][]SweepRung
+}
+
+// SweepRung applies to markets with at most MaxHoursLeft hours to close.
+// The rung with the smallest matching MaxHoursLeft wins.
+type SweepRung struct {
+	MaxHoursLeft   float64  `json:"max_hours_left"`
+	MinPrice       float64  `json:"min_price"`
+	ExpectedPayout float64  `json:"expected_payout"`
+	MinEdgePct     *float64 `json:"min_edge_pct,omitempty"`
 }
 
 func (s *CertaintySweepStrategy) Name() string { return "certainty_sweep" }
 
-func (s *CertaintySweepStrategy) RequiredSignals() []string { return []string{"certainty_sweep"} }
+func (s *CertaintySweepStrategy) RequiredSignals() []string {
+	return []string{"certainty_sweep", "market_close_countdown"}
+}
 
 func (s *CertaintySweepStrategy) DefaultParams() json.RawMessage {
-	return json.RawMessage(`{"min_edge_pct":0.01}`)
+	return json.RawMessage(`{"min_edge_pct":0.01,"ladder":[{"max_hours_left":24,"min_price":0.97,"expected_payout":0.995},{"max_hours_left":2,"min_price":0.99,"expected_payout":0.999,"min_edge_pct":0.005}],"label_overrides":{}}`)
 }
 
 func (s *CertaintySweepStrategy) SetParams(raw json.RawMessage) error {
 	var p struct {
-		MinEdgePct *float64 `json:"min_edge_pct"`
+		MinEdgePct     *float64               `json:"min_edge_pct"`
+		Ladder         []SweepRung            `json:"ladder"`
+		LabelOverrides map[string][]SweepRung `json:"label_overrides"`
 	}
 	if len(raw) > 0 {
 		_ = json.Unmarshal(raw, &p)
@@ -49,9 +68,59 @@ func (s *CertaintySweepStrategy) SetParams(raw json.RawMessage) error {
 	if p.MinEdgePct != nil {
 		s.MinEdgePct = *p.MinEdgePct
 	}
+	if p.Ladder != nil {
+		s.Ladder = p.Ladder
+	}
+	if p.LabelOverrides != nil {
+		s.LabelOverrides = map[string][]SweepRung{}
+		for label, rungs := range p.LabelOverrides {
+			s.LabelOverrides[strings.ToLower(strings.TrimSpace(label))] = rungs
+		}
+	}
 	return nil
 }
 
+// selectRung returns the tightest rung covering hoursLeft, or false when the market is further from close
+// than every rung.
+func selectRung(ladder []SweepRung, hoursLeft float64) (SweepRung, bool) {
+	var best SweepRung
+	found := false
+	for _, r := range ladder {
+		if r.MaxHoursLeft <= 0 || r.MinPrice <= 0.5 || r.MinPrice >= 1 {
+			continue
+		}
+		if hoursLeft > r.MaxHoursLeft {
+			continue
+		}
+		if !found || r.MaxHoursLeft < best.MaxHoursLeft {
+			best = r
+			found = true
+		}
+	}
+	return best, found
+}
+
+// ladderFor returns the label override ladder for the market if any, else the default ladder.
+func (s *CertaintySweepStrategy) ladderFor(ctx context.Context, marketID string) []SweepRung {
+	s.mu.RLock()
+	ladder := s.Ladder
+	overrides := s.LabelOverrides
+	s.mu.RUnlock()
+	if len(overrides) == 0 {
+		return ladder
+	}
+	labels, err := s.Repo.ListMarketLabels(ctx, repository.ListMarketLabelsParams{MarketID: &marketID, Limit: 50, OrderBy: "label", Asc: boolPtr(true)})
+	if err != nil {
+		return ladder
+	}
+	for _, l := range labels {
+		if rungs, ok := overrides[strings.ToLower(strings.TrimSpace(l.Label))]; ok && len(rungs) > 0 {
+			return rungs
+		}
+	}
+	return ladder
+}
+
 func (s *CertaintySweepStrategy) Evaluate(ctx context.Context, signals []models.Signal) ([]models.Opportunity, error) {
 	if s == nil || s.Repo == nil || len(signals) == 0 {
 		return nil, nil
@@ -75,15 +144,43 @@ func (s *CertaintySweepStrategy) Evaluate(ctx context.Context, signals []models.
 		return nil, nil
 	}
 
+	s.mu.RLock()
+	minEdgeRaw := s.MinEdgePct
+	s.mu.RUnlock()
+	if minEdgeRaw <= 0 {
+		minEdgeRaw = 0.01
+	}
+	threshold := 0.97
+	expPayout := 0.995
+	hoursLeft := -1.0
+	var payload struct {
+		HoursLeft *float64 `json:"hours_left"`
+	}
+	if len(sig.Payload) > 0 {
+		_ = json.Unmarshal(sig.Payload, &payload)
+	}
+	if payload.HoursLeft != nil {
+		hoursLeft = *payload.HoursLeft
+		rung, ok := selectRung(s.ladderFor(ctx, marketID), hoursLeft)
+		if !ok {
+			return nil, nil
+		}
+		threshold = rung.MinPrice
+		if rung.ExpectedPayout > 0 && rung.ExpectedPayout <= 1 {
+			expPayout = rung.ExpectedPayout
+		}
+		if rung.MinEdgePct != nil && *rung.MinEdgePct > 0 {
+			minEdgeRaw = *rung.MinEdgePct
+		}
+	}
+
 	side := ""
-	expPayout := 0.0
 	tokenID := ""
 	switch {
-	case yesAsk.GreaterThanOrEqual(decimal.NewFromFloat(0.97)):
+	case yesAsk.GreaterThanOrEqual(decimal.NewFromFloat(threshold)):
 		side = "BUY_YES"
 		tokenID = yesTokenID
-		expPayout = 0.995
-	case yesAsk.LessThanOrEqual(decimal.NewFromFloat(0.03)):
+	case yesAsk.LessThanOrEqual(decimal.NewFromFloat(1 - threshold)):
 		// Need NO token ID.
 		toks, err := s.Repo.ListTokensByMarketIDs(ctx, []string{marketID})
 		if err != nil {
@@ -101,7 +198,6 @@ func (s *CertaintySweepStrategy) Evaluate(ctx context.Context, signals []models.
 		}
 		side = "BUY_NO"
 		tokenID = noTokenID
-		expPayout = 0.995
 	default:
 		return nil, nil
 	}
@@ -123,12 +219,6 @@ func (s *CertaintySweepStrategy) Evaluate(ctx context.Context, signals []models.
 		return nil, nil
 	}
 	edgePct := expProfitPerShare.Div(askPrice)
-	s.mu.RLock()
-	minEdgeRaw := s.MinEdgePct
-	s.mu.RUnlock()
-	if minEdgeRaw <= 0 {
-		minEdgeRaw = 0.01
-	}
 	if edgePct.LessThan(decimal.NewFromFloat(minEdgeRaw)) {
 		return nil, nil
 	}
@@ -144,14 +234,18 @@ func (s *CertaintySweepStrategy) Evaluate(ctx context.Context, signals []models.
 			"current_best_ask": askPrice.InexactFloat64(),
 			"fillable_size":    askSize.InexactFloat64(),
 			"expected_payout":  expPayout,
+			"threshold":        threshold,
 		},
 	}
 	legsJSON, _ := json.Marshal(legs)
 	marketIDsJSON, _ := json.Marshal([]string{marketID})
 	signalIDsJSON, _ := json.Marshal([]uint64{sig.ID})
 
-	reasoning := fmt.Sprintf("certainty_sweep market=%s side=%s entry=%s expected_payout=%.3f",
-		marketID, side, askPrice.StringFixed(4), expPayout)
+	reasoning := fmt.Sprintf("certainty_sweep market=%s side=%s entry=%s expected_payout=%.3f threshold=%.3f",
+		marketID, side, askPrice.StringFixed(4), expPayout, threshold)
+	if hoursLeft >= 0 {
+		reasoning += fmt.Sprintf(" hours_left=%.1f", hoursLeft)
+	}
 	now := time.Now().UTC()
 
 	opp := models.Opportunity{
@@ -0,0 +1,187 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// CopyFlowStrategy (P2) consumes "smart_money_move" (from SmartMoneyCollector) and follows tracked wallets
+// into the same outcome token when they build a large position.
+//
+// MVP heuristic:
+// - Ignore builds smaller than min_delta_usd.
+// - Skip if the current ask is more than max_chase_pct above the wallet's fill price.
+// - Expected payout = wallet price * (1 + follow_edge_pct), i.e. the wallet is assumed to know something.
+type CopyFlowStrategy struct {
+	Repo   repository.Repository
+	Logger *zap.Logger
+
+	mu sync.RWMutex
+
+	MinDeltaUSD   float64
+	MaxChasePct   float64
+	FollowEdgePct float64
+}
+
+func (s *CopyFlowStrategy) Name() string { return "copy_flow" }
+
+func (s *CopyFlowStrategy) RequiredSignals() []string { return []string{"smart_money_move"} }
+
+func (s *CopyFlowStrategy) DefaultParams() json.RawMessage {
+	return json.RawMessage(`{"min_delta_usd":1000,"max_chase_pct":0.03,"follow_edge_pct":0.08}`)
+}
+
+func (s *CopyFlowStrategy) SetParams(raw json.RawMessage) error {
+	var p struct {
+		MinDeltaUSD   *float64 `json:"min_delta_usd"`
+		MaxChasePct   *float64 `json:"max_chase_pct"`
+		FollowEdgePct *float64 `json:"follow_edge_pct"`
+	}
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &p)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.MinDeltaUSD != nil {
+		s.MinDeltaUSD = *p.MinDeltaUSD
+	}
+	if p.MaxChasePct != nil {
+		s.MaxChasePct = *p.MaxChasePct
+	}
+	if p.FollowEdgePct != nil {
+		s.FollowEdgePct = *p.FollowEdgePct
+	}
+	return nil
+}
+
+func (s *CopyFlowStrategy) Evaluate(ctx context.Context, signals []models.Signal) ([]models.Opportunity, error) {
+	if s == nil || s.Repo == nil || len(signals) == 0 {
+		return nil, nil
+	}
+	sig := signals[0]
+	if sig.MarketID == nil || sig.TokenID == nil {
+		return nil, nil
+	}
+	marketID := strings.TrimSpace(*sig.MarketID)
+	tokenID := strings.TrimSpace(*sig.TokenID)
+	if marketID == "" || tokenID == "" {
+		return nil, nil
+	}
+	var payload struct {
+		Wallet   string  `json:"wallet"`
+		Outcome  string  `json:"outcome"`
+		DeltaUSD float64 `json:"delta_usd"`
+		Price    float64 `json:"price"`
+	}
+	if len(sig.Payload) > 0 {
+		_ = json.Unmarshal(sig.Payload, &payload)
+	}
+	if payload.Price <= 0 || payload.Price >= 1 {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	minDeltaUSD := s.MinDeltaUSD
+	maxChasePct := s.MaxChasePct
+	followEdgePct := s.FollowEdgePct
+	s.mu.RUnlock()
+	if minDeltaUSD <= 0 {
+		minDeltaUSD = 1000
+	}
+	if maxChasePct <= 0 {
+		maxChasePct = 0.03
+	}
+	if followEdgePct <= 0 {
+		followEdgePct = 0.08
+	}
+	if payload.DeltaUSD < minDeltaUSD {
+		return nil, nil
+	}
+
+	books, _ := s.Repo.ListOrderbookLatestByTokenIDs(ctx, []string{tokenID})
+	if len(books) == 0 {
+		return nil, nil
+	}
+	askPrice, askSize, ok := bestAsk(books[0])
+	if !ok || askPrice.LessThanOrEqual(decimal.Zero) {
+		return nil, nil
+	}
+	if askSize.LessThanOrEqual(decimal.Zero) {
+		askSize = decimal.NewFromInt(10)
+	}
+	walletPrice := decimal.NewFromFloat(payload.Price)
+	if askPrice.GreaterThan(walletPrice.Mul(decimal.NewFromFloat(1 + maxChasePct))) {
+		return nil, nil
+	}
+
+	expPayout := payload.Price * (1 + followEdgePct)
+	if expPayout > 0.99 {
+		expPayout = 0.99
+	}
+	expProfitPerShare := decimal.NewFromFloat(expPayout).Sub(askPrice)
+	if expProfitPerShare.LessThanOrEqual(decimal.Zero) {
+		return nil, nil
+	}
+	edgePct := expProfitPerShare.Div(askPrice)
+	cost := askPrice.Mul(askSize)
+	edgeUSD := expProfitPerShare.Mul(askSize)
+
+	// Follow into the wallet's outcome: BUY_NO, BUY_LAKERS; YES when unlabelled.
+	side := "BUY_YES"
+	if models.OutcomeCode(payload.Outcome) != "" {
+		side = models.Direction("BUY", payload.Outcome)
+	}
+	legs := []map[string]any{
+		{
+			"token_id":         tokenID,
+			"market_id":        marketID,
+			"direction":        side,
+			"target_price":     askPrice.InexactFloat64(),
+			"current_best_ask": askPrice.InexactFloat64(),
+			"fillable_size":    askSize.InexactFloat64(),
+			"wallet":           payload.Wallet,
+			"wallet_price":     payload.Price,
+			"expected_payout":  expPayout,
+		},
+	}
+	legsJSON, _ := json.Marshal(legs)
+	marketIDsJSON, _ := json.Marshal([]string{marketID})
+	signalIDsJSON, _ := json.Marshal([]uint64{sig.ID})
+
+	reasoning := fmt.Sprintf("copy_flow market=%s side=%s wallet=%s build_usd=%.0f wallet_price=%.4f entry=%s",
+		marketID, side, payload.Wallet, payload.DeltaUSD, payload.Price, askPrice.StringFixed(4))
+	now := time.Now().UTC()
+
+	opp := models.Opportunity{
+		Status:          "active",
+		EventID:         sig.EventID,
+		PrimaryMarketID: strPtr(marketID),
+		MarketIDs:       datatypes.JSON(marketIDsJSON),
+		EdgePct:         edgePct,
+		EdgeUSD:         edgeUSD,
+		MaxSize:         cost,
+		Confidence:      clamp01(sig.Strength),
+		RiskScore:       0.7,
+		DecayType:       "exponential",
+		ExpiresAt:       sig.ExpiresAt,
+		Legs:            datatypes.JSON(legsJSON),
+		SignalIDs:       datatypes.JSON(signalIDsJSON),
+		Reasoning:       reasoning,
+		DataAgeMs:       int(time.Since(books[0].UpdatedAt).Milliseconds()),
+		Warnings:        datatypes.JSON([]byte(`["copy_trade"]`)),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	return []models.Opportunity{opp}, nil
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 	"gorm.io/datatypes"
 
@@ -27,17 +28,39 @@ type Engine struct {
 	Opps interface {
 		Upsert(context.Context, *models.Opportunity) error
 	}
+	// Quality scores signal types by past precision. A weight of 0 skips the
+	// batch; weights below 1 scale opportunity confidence. Nil disables it.
+	Quality interface {
+		Weight(ctx context.Context, signalType string) float64
+	}
 
 	// StrategyDefaults is the config-sourced default override map (config.strategy_defaults).
 	// Shape: { "arb_sum": { "enabled": true, ... }, ... }
 	StrategyDefaults map[string]any
 
+	// NewStrategyStage is the launch stage given to strategies registered for
+	// the first time; empty means full. CappedMaxUSD clamps opportunity size
+	// for strategies in the capped stage.
+	NewStrategyStage string
+	CappedMaxUSD     decimal.Decimal
+
+	// Running, when set, is checked before each evaluation so turning the
+	// engine switch off, as safe mode does, stops evaluation without a
+	// restart. Nil always runs.
+	Running func(ctx context.Context) bool
+
 	enabledMu     sync.RWMutex
 	enabledByName map[string]bool
 
 	paramsMu     sync.RWMutex
 	paramsByName map[string]datatypes.JSON
 
+	holdoutMu     sync.RWMutex
+	holdoutByName map[string]models.StrategyChange
+
+	evalMu    sync.Mutex
+	evalLocks map[string]*sync.Mutex
+
 	evByName map[string]StrategyEvaluator
 }
 
@@ -73,6 +96,9 @@ func (e *Engine) Run(ctx context.Context) error {
 		if err := e.ensureStrategyRow(ctx, ev); err != nil && e.Logger != nil {
 			e.Logger.Warn("ensure strategy row failed", zap.String("strategy", ev.Name()), zap.Error(err))
 		}
+		if c, ok := ev.(OpportunityCombiner); ok {
+			go e.runCombiner(ctx, c)
+		}
 		for _, sigType := range ev.RequiredSignals() {
 			ch := e.Hub.Subscribe(sigType, 64)
 			go e.runWorker(ctx, ev, sigType, ch)
@@ -101,7 +127,7 @@ func (e *Engine) runWorker(ctx context.Context, ev StrategyEvaluator, sigType st
 		if len(batch) == 0 {
 			return
 		}
-		if !e.isEnabled(ev.Name()) {
+		if !e.running(ctx) || !e.isEnabled(ev.Name()) {
 			batch = batch[:0]
 			return
 		}
@@ -110,9 +136,21 @@ func (e *Engine) runWorker(ctx context.Context, ev StrategyEvaluator, sigType st
 			batch = batch[:0]
 			return
 		}
-		opps, err := ev.Evaluate(ctx, batch)
+		run := newEvaluationRun(ev.Name(), sigType, batch)
+		weight := e.signalWeight(ctx, sigType)
+		if weight <= 0 {
+			batch = batch[:0]
+			e.recordRun(ctx, run, map[string]int{"signal_quality": run.SignalsConsumed})
+			return
+		}
+		opps, err := e.evaluate(ctx, ev, batch)
 		batch = batch[:0]
+		run.OpportunitiesFound = len(opps)
 		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				run.Error = err.Error()
+				e.recordRun(ctx, run, nil)
+			}
 			if e.Logger != nil && !errors.Is(err, context.Canceled) {
 				e.Logger.Warn("strategy evaluate failed", zap.String("strategy", ev.Name()), zap.Error(err))
 			}
@@ -124,25 +162,10 @@ func (e *Engine) runWorker(ctx context.Context, ev StrategyEvaluator, sigType st
 		}
 		backoff = 200 * time.Millisecond
 		if len(opps) == 0 {
+			e.recordRun(ctx, run, nil)
 			return
 		}
-		// Assign strategy before risk so risk can apply per-strategy gating.
-		for i := range opps {
-			opps[i].StrategyID = strat.ID
-		}
-		if e.Risk != nil {
-			opps = e.Risk.Filter(opps)
-		}
-		if len(opps) == 0 {
-			return
-		}
-		for i := range opps {
-			if e.Opps != nil {
-				_ = e.Opps.Upsert(ctx, &opps[i])
-			} else {
-				_ = e.Repo.UpsertActiveOpportunity(ctx, &opps[i])
-			}
-		}
+		e.publish(ctx, strat, run, sigType, weight, opps, nil)
 	}
 
 	for {
@@ -172,6 +195,115 @@ func (e *Engine) runWorker(ctx context.Context, ev StrategyEvaluator, sigType st
 	}
 }
 
+// publish takes a strategy's opportunities through its launch stage and
+// risk, stores the survivors and hands them to the combiners. rejects are
+// the reasons the strategy itself dropped candidates, if any.
+func (e *Engine) publish(ctx context.Context, strat *models.Strategy, run *models.EvaluationRun, sigType string, weight float64, opps []models.Opportunity, rejects map[string]int) {
+	if rejects == nil {
+		rejects = map[string]int{}
+	}
+	if strat.LaunchStage == models.LaunchStageDark {
+		rejects["launch_dark"] += len(opps)
+		e.recordRun(ctx, run, rejects)
+		return
+	}
+	// Assign strategy before risk so risk can apply per-strategy gating.
+	for i := range opps {
+		opps[i].StrategyID = strat.ID
+		opps[i].Tenant = strat.Tenant
+		if sigType != "" {
+			opps[i].SignalType = sigType
+		}
+		if weight < 1 {
+			opps[i].Confidence *= weight
+		}
+	}
+	applyLaunchStage(strat.LaunchStage, opps, e.CappedMaxUSD)
+	before := len(opps)
+	var riskRejects map[string]int
+	if scoped, ok := e.Risk.(interface {
+		FilterForTenantWithReasons(string, []models.Opportunity) ([]models.Opportunity, map[string]int)
+	}); ok {
+		opps, riskRejects = scoped.FilterForTenantWithReasons(strat.Tenant, opps)
+	} else if e.Risk != nil {
+		opps = e.Risk.Filter(opps)
+	}
+	if dropped := before - len(opps); dropped > 0 && len(riskRejects) == 0 {
+		riskRejects = map[string]int{"risk": dropped}
+	}
+	for k, v := range riskRejects {
+		rejects[k] += v
+	}
+	run.OpportunitiesEmitted = len(opps)
+	e.recordRun(ctx, run, rejects)
+	if len(opps) == 0 {
+		return
+	}
+	for i := range opps {
+		if e.Opps != nil {
+			_ = e.Opps.Upsert(ctx, &opps[i])
+		} else {
+			_ = e.Repo.UpsertActiveOpportunity(ctx, &opps[i])
+		}
+	}
+	e.observe(strat.Name, opps)
+}
+
+// observe feeds executable opportunities of a base strategy to the enabled
+// combiners. Combiner output is not fed back.
+func (e *Engine) observe(strategy string, opps []models.Opportunity) {
+	if _, ok := e.evByName[strategy].(OpportunityCombiner); ok {
+		return
+	}
+	var live []models.Opportunity
+	for _, o := range opps {
+		if !o.Shadow {
+			live = append(live, o)
+		}
+	}
+	if len(live) == 0 {
+		return
+	}
+	for _, ev := range e.Evaluators {
+		if c, ok := ev.(OpportunityCombiner); ok && e.isEnabled(c.Name()) {
+			c.Observe(strategy, live)
+		}
+	}
+}
+
+// runCombiner publishes what c combined every c.Window().
+func (e *Engine) runCombiner(ctx context.Context, c OpportunityCombiner) {
+	if e.Logger != nil {
+		e.Logger.Info("strategy combiner started", zap.String("strategy", c.Name()))
+	}
+	for {
+		t := time.NewTimer(c.Window())
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case now := <-t.C:
+			res := c.Combine(now.UTC())
+			if res.Consumed == 0 || !e.running(ctx) || !e.isEnabled(c.Name()) {
+				continue
+			}
+			strat, _ := e.Repo.GetStrategyByName(ctx, c.Name())
+			if strat == nil {
+				continue
+			}
+			run := &models.EvaluationRun{
+				StrategyName:       c.Name(),
+				SignalType:         "opportunity",
+				SignalsConsumed:    res.Consumed,
+				MarketsScanned:     res.Markets,
+				OpportunitiesFound: len(res.Opportunities),
+				StartedAt:          now.UTC(),
+			}
+			e.publish(ctx, strat, run, "", 1, res.Opportunities, res.Rejects)
+		}
+	}
+}
+
 func (e *Engine) reloadEnabledLoop(ctx context.Context) {
 	t := time.NewTicker(15 * time.Second)
 	defer t.Stop()
@@ -203,8 +335,11 @@ func (e *Engine) reloadStrategies(ctx context.Context) {
 		ev := e.evByName[it.Name]
 		merged := mergeParams(ev, e.StrategyDefaults, it.Name, it.Params)
 		nextParams[it.Name] = merged
-		if p, ok := ev.(interface{ SetParams(json.RawMessage) error }); ok && len(merged) > 0 {
+		if p, ok := ev.(paramSetter); ok && len(merged) > 0 {
+			mu := e.evalLock(it.Name)
+			mu.Lock()
 			_ = p.SetParams(json.RawMessage(merged))
+			mu.Unlock()
 		}
 	}
 	e.enabledMu.Lock()
@@ -213,6 +348,22 @@ func (e *Engine) reloadStrategies(ctx context.Context) {
 	e.paramsMu.Lock()
 	e.paramsByName = nextParams
 	e.paramsMu.Unlock()
+	e.reloadHoldouts(ctx)
+}
+
+func (e *Engine) signalWeight(ctx context.Context, sigType string) float64 {
+	if e == nil || e.Quality == nil {
+		return 1
+	}
+	w := e.Quality.Weight(ctx, sigType)
+	if w > 1 {
+		return 1
+	}
+	return w
+}
+
+func (e *Engine) running(ctx context.Context) bool {
+	return e.Running == nil || e.Running(ctx)
 }
 
 func (e *Engine) isEnabled(name string) bool {
@@ -311,6 +462,12 @@ func (e *Engine) ensureStrategyRow(ctx context.Context, ev StrategyEvaluator) er
 	case "market_anomaly":
 		category = "data_driven"
 		priority = 2
+	case "copy_flow":
+		category = "sentiment"
+		priority = 2
+	case "ensemble":
+		category = "meta"
+		priority = 1
 	}
 
 	enabled := false
@@ -330,6 +487,10 @@ func (e *Engine) ensureStrategyRow(ctx context.Context, ev StrategyEvaluator) er
 			enabled = v
 		}
 	}
+	stage := ""
+	if existing == nil && models.ValidLaunchStage(e.NewStrategyStage) {
+		stage = e.NewStrategyStage
+	}
 	item := &models.Strategy{
 		Name:            ev.Name(),
 		DisplayName:     ev.Name(),
@@ -337,6 +498,7 @@ func (e *Engine) ensureStrategyRow(ctx context.Context, ev StrategyEvaluator) er
 		Category:        category,
 		Enabled:         enabled,
 		Priority:        priority,
+		LaunchStage:     stage,
 		Params:          params,
 		RequiredSignals: datatypes.JSON(req),
 		Stats:           stats,
@@ -344,6 +506,22 @@ func (e *Engine) ensureStrategyRow(ctx context.Context, ev StrategyEvaluator) er
 	return e.Repo.UpsertStrategy(ctx, item)
 }
 
+// applyLaunchStage restricts opportunities of a strategy that is not yet
+// fully launched: shadow ones are flagged non-executable and capped ones are
+// clamped to capUSD with their expected profit scaled to match.
+func applyLaunchStage(stage string, opps []models.Opportunity, capUSD decimal.Decimal) {
+	for i := range opps {
+		opps[i].Shadow = stage == models.LaunchStageShadow
+		if stage != models.LaunchStageCapped || capUSD.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+		if opps[i].MaxSize.GreaterThan(capUSD) {
+			opps[i].EdgeUSD = opps[i].EdgeUSD.Mul(capUSD).Div(opps[i].MaxSize)
+			opps[i].MaxSize = capUSD
+		}
+	}
+}
+
 func defaultEnabled(defaults map[string]any, name string) (bool, bool) {
 	if len(defaults) == 0 || name == "" {
 		return false, false
@@ -363,3 +541,39 @@ func defaultEnabled(defaults map[string]any, name string) (bool, bool) {
 	b, ok := v.(bool)
 	return b, ok
 }
+
+func newEvaluationRun(strategyName string, sigType string, batch []models.Signal) *models.EvaluationRun {
+	markets := map[string]struct{}{}
+	for _, sig := range batch {
+		if sig.MarketID != nil && strings.TrimSpace(*sig.MarketID) != "" {
+			markets[strings.TrimSpace(*sig.MarketID)] = struct{}{}
+		}
+	}
+	return &models.EvaluationRun{
+		StrategyName:    strategyName,
+		SignalType:      sigType,
+		SignalsConsumed: len(batch),
+		MarketsScanned:  len(markets),
+		StartedAt:       time.Now().UTC(),
+	}
+}
+
+// recordRun persists the evaluation run; failures only affect debugging so
+// they are logged and dropped.
+func (e *Engine) recordRun(ctx context.Context, run *models.EvaluationRun, rejects map[string]int) {
+	if e == nil || e.Repo == nil || run == nil {
+		return
+	}
+	run.DurationMs = time.Since(run.StartedAt).Milliseconds()
+	if rejects == nil {
+		rejects = map[string]int{}
+	}
+	if dropped := run.OpportunitiesFound - run.OpportunitiesEmitted; dropped > 0 && len(rejects) == 0 && run.Error == "" {
+		rejects["risk"] = dropped
+	}
+	raw, _ := json.Marshal(rejects)
+	run.Rejects = datatypes.JSON(raw)
+	if err := e.Repo.InsertEvaluationRun(ctx, run); err != nil && e.Logger != nil && !errors.Is(err, context.Canceled) {
+		e.Logger.Debug("record evaluation run failed", zap.String("strategy", run.StrategyName), zap.Error(err))
+	}
+}
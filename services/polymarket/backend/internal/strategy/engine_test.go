@@ -0,0 +1,40 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"polymarket/internal/models"
+)
+
+func TestApplyLaunchStage(t *testing.T) {
+	mk := func() []models.Opportunity {
+		return []models.Opportunity{
+			{MaxSize: decimal.NewFromInt(100), EdgeUSD: decimal.NewFromInt(8)},
+			{MaxSize: decimal.NewFromInt(10), EdgeUSD: decimal.NewFromInt(1)},
+		}
+	}
+	capUSD := decimal.NewFromInt(25)
+
+	opps := mk()
+	applyLaunchStage(models.LaunchStageShadow, opps, capUSD)
+	if !opps[0].Shadow || !opps[1].Shadow || !opps[0].MaxSize.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("shadow=%+v", opps)
+	}
+
+	opps = mk()
+	applyLaunchStage(models.LaunchStageCapped, opps, capUSD)
+	if opps[0].Shadow || !opps[0].MaxSize.Equal(capUSD) || !opps[0].EdgeUSD.Equal(decimal.NewFromInt(2)) {
+		t.Fatalf("capped=%+v", opps[0])
+	}
+	if !opps[1].MaxSize.Equal(decimal.NewFromInt(10)) {
+		t.Fatalf("below cap changed: %+v", opps[1])
+	}
+
+	opps = mk()
+	applyLaunchStage(models.LaunchStageFull, opps, capUSD)
+	if opps[0].Shadow || !opps[0].MaxSize.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("full=%+v", opps[0])
+	}
+}
@@ -0,0 +1,319 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+)
+
+// OpportunityCombiner is a strategy fed the opportunities other strategies
+// publish rather than signals. The engine hands it every published batch via
+// Observe and calls Combine once per Window.
+type OpportunityCombiner interface {
+	StrategyEvaluator
+	Observe(strategy string, opps []models.Opportunity)
+	Combine(now time.Time) CombineResult
+	Window() time.Duration
+}
+
+// CombineResult is one Combine pass: the opportunities to publish, how many
+// base opportunities it consumed and why the others were dropped.
+type CombineResult struct {
+	Opportunities []models.Opportunity
+	Consumed      int
+	Markets       int
+	Rejects       map[string]int
+}
+
+// EnsembleStrategy (meta) combines base strategies that agree. Within each
+// window it groups single-leg opportunities by market and direction; when
+// at least min_agreement strategies back the same side it emits one
+// opportunity for it, and a market where strategies take different sides is
+// suppressed. Confidence combines as 1 - Π(1 - c) for independent evidence,
+// blended toward the strongest member by correlation since base strategies
+// often read the same signals.
+type EnsembleStrategy struct {
+	Logger *zap.Logger
+
+	mu sync.Mutex
+
+	MinAgreement  int
+	WindowSeconds float64
+	Correlation   float64
+	MinConfidence float64
+
+	pending []ensembleMember
+}
+
+type ensembleMember struct {
+	strategy  string
+	marketID  string
+	direction string
+	opp       models.Opportunity
+}
+
+func (s *EnsembleStrategy) Name() string { return "ensemble" }
+
+// RequiredSignals is empty: the engine feeds the ensemble opportunities.
+func (s *EnsembleStrategy) RequiredSignals() []string { return nil }
+
+func (s *EnsembleStrategy) DefaultParams() json.RawMessage {
+	return json.RawMessage(`{"min_agreement":2,"window_seconds":5,"correlation":0.5,"min_confidence":0.5}`)
+}
+
+func (s *EnsembleStrategy) SetParams(raw json.RawMessage) error {
+	var p struct {
+		MinAgreement  *int     `json:"min_agreement"`
+		WindowSeconds *float64 `json:"window_seconds"`
+		Correlation   *float64 `json:"correlation"`
+		MinConfidence *float64 `json:"min_confidence"`
+	}
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &p)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.MinAgreement != nil {
+		s.MinAgreement = *p.MinAgreement
+	}
+	if p.WindowSeconds != nil {
+		s.WindowSeconds = *p.WindowSeconds
+	}
+	if p.Correlation != nil {
+		s.Correlation = *p.Correlation
+	}
+	if p.MinConfidence != nil {
+		s.MinConfidence = *p.MinConfidence
+	}
+	return nil
+}
+
+func (s *EnsembleStrategy) Evaluate(ctx context.Context, signals []models.Signal) ([]models.Opportunity, error) {
+	return nil, nil
+}
+
+func (s *EnsembleStrategy) Window() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.WindowSeconds <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(s.WindowSeconds * float64(time.Second))
+}
+
+// Observe buffers the single-leg opportunities of strategy until the next
+// Combine. Multi-leg opportunities (arbitrage baskets) have no single side
+// to agree on and are ignored.
+func (s *EnsembleStrategy) Observe(strategy string, opps []models.Opportunity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range opps {
+		var legs []map[string]any
+		if err := json.Unmarshal(o.Legs, &legs); err != nil || len(legs) != 1 {
+			continue
+		}
+		marketID, _ := legs[0]["market_id"].(string)
+		direction, _ := legs[0]["direction"].(string)
+		if marketID == "" && o.PrimaryMarketID != nil {
+			marketID = *o.PrimaryMarketID
+		}
+		marketID = strings.TrimSpace(marketID)
+		direction = strings.ToUpper(strings.TrimSpace(direction))
+		if marketID == "" || direction == "" {
+			continue
+		}
+		s.pending = append(s.pending, ensembleMember{strategy: strategy, marketID: marketID, direction: direction, opp: o})
+	}
+}
+
+// Combine drains the buffered opportunities and returns the ensemble ones.
+func (s *EnsembleStrategy) Combine(now time.Time) CombineResult {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	minAgree := s.MinAgreement
+	rho := clamp01(s.Correlation)
+	minConf := s.MinConfidence
+	s.mu.Unlock()
+	if minAgree < 2 {
+		minAgree = 2
+	}
+
+	out := CombineResult{Consumed: len(pending), Rejects: map[string]int{}}
+	// market -> direction -> strategy -> strongest member
+	byMarket := map[string]map[string]map[string]ensembleMember{}
+	for _, m := range pending {
+		sides := byMarket[m.marketID]
+		if sides == nil {
+			sides = map[string]map[string]ensembleMember{}
+			byMarket[m.marketID] = sides
+		}
+		members := sides[m.direction]
+		if members == nil {
+			members = map[string]ensembleMember{}
+			sides[m.direction] = members
+		}
+		if prev, ok := members[m.strategy]; !ok || m.opp.Confidence > prev.opp.Confidence {
+			members[m.strategy] = m
+		}
+	}
+	out.Markets = len(byMarket)
+
+	marketIDs := make([]string, 0, len(byMarket))
+	for id := range byMarket {
+		marketIDs = append(marketIDs, id)
+	}
+	sort.Strings(marketIDs)
+	for _, marketID := range marketIDs {
+		sides := byMarket[marketID]
+		if len(sides) > 1 {
+			out.Rejects["conflict"]++
+			continue
+		}
+		for direction, byStrategy := range sides {
+			if len(byStrategy) < minAgree {
+				out.Rejects["no_agreement"]++
+				continue
+			}
+			members := make([]ensembleMember, 0, len(byStrategy))
+			for _, m := range byStrategy {
+				members = append(members, m)
+			}
+			sort.Slice(members, func(i, j int) bool {
+				if members[i].opp.Confidence != members[j].opp.Confidence {
+					return members[i].opp.Confidence > members[j].opp.Confidence
+				}
+				return members[i].strategy < members[j].strategy
+			})
+			conf := ensembleConfidence(members, rho)
+			if conf < minConf {
+				out.Rejects["low_confidence"]++
+				continue
+			}
+			out.Opportunities = append(out.Opportunities, ensembleOpportunity(marketID, direction, members, conf, now))
+		}
+	}
+	return out
+}
+
+// ensembleConfidence blends the noisy-OR of the members' confidences with
+// the strongest one; members are sorted strongest first.
+func ensembleConfidence(members []ensembleMember, correlation float64) float64 {
+	miss := 1.0
+	for _, m := range members {
+		miss *= 1 - clamp01(m.opp.Confidence)
+	}
+	independent := 1 - miss
+	strongest := clamp01(members[0].opp.Confidence)
+	return clamp01((1-correlation)*independent + correlation*strongest)
+}
+
+// ensembleOpportunity builds the combined opportunity on the strongest
+// member's leg. It takes the smallest size, the highest risk score and the
+// confidence weighted mean edge of the members; the leg lists every member
+// as provenance.
+func ensembleOpportunity(marketID, direction string, members []ensembleMember, conf float64, now time.Time) models.Opportunity {
+	lead := members[0].opp
+	var legs []map[string]any
+	_ = json.Unmarshal(lead.Legs, &legs)
+
+	maxSize := lead.MaxSize
+	riskScore := lead.RiskScore
+	expiresAt := lead.ExpiresAt
+	dataAge := lead.DataAgeMs
+	weighted, weights := decimal.Zero, decimal.Zero
+	signalIDs := []uint64{}
+	seenSignals := map[uint64]bool{}
+	warnings := []string{}
+	seenWarnings := map[string]bool{}
+	provenance := make([]map[string]any, 0, len(members))
+	names := make([]string, 0, len(members))
+	for _, m := range members {
+		o := m.opp
+		if o.MaxSize.LessThan(maxSize) {
+			maxSize = o.MaxSize
+		}
+		if o.RiskScore > riskScore {
+			riskScore = o.RiskScore
+		}
+		if o.ExpiresAt != nil && (expiresAt == nil || o.ExpiresAt.Before(*expiresAt)) {
+			expiresAt = o.ExpiresAt
+		}
+		if o.DataAgeMs > dataAge {
+			dataAge = o.DataAgeMs
+		}
+		w := decimal.NewFromFloat(o.Confidence)
+		weighted = weighted.Add(o.EdgePct.Mul(w))
+		weights = weights.Add(w)
+		var ids []uint64
+		_ = json.Unmarshal(o.SignalIDs, &ids)
+		for _, id := range ids {
+			if !seenSignals[id] {
+				seenSignals[id] = true
+				signalIDs = append(signalIDs, id)
+			}
+		}
+		var ws []string
+		_ = json.Unmarshal(o.Warnings, &ws)
+		for _, w := range ws {
+			if !seenWarnings[w] {
+				seenWarnings[w] = true
+				warnings = append(warnings, w)
+			}
+		}
+		p := map[string]any{
+			"strategy":   m.strategy,
+			"confidence": o.Confidence,
+			"edge_pct":   o.EdgePct.InexactFloat64(),
+		}
+		if o.ID != 0 {
+			p["opportunity_id"] = o.ID
+		}
+		provenance = append(provenance, p)
+		names = append(names, m.strategy)
+	}
+	edgePct := lead.EdgePct
+	if weights.IsPositive() {
+		edgePct = weighted.Div(weights)
+	}
+	if len(legs) == 1 {
+		legs[0]["ensemble_members"] = provenance
+	}
+	legsJSON, _ := json.Marshal(legs)
+	marketIDsJSON, _ := json.Marshal([]string{marketID})
+	signalIDsJSON, _ := json.Marshal(signalIDs)
+	warningsJSON, _ := json.Marshal(warnings)
+
+	return models.Opportunity{
+		Status:          "active",
+		EventID:         lead.EventID,
+		PrimaryMarketID: strPtr(marketID),
+		MarketIDs:       datatypes.JSON(marketIDsJSON),
+		EdgePct:         edgePct,
+		EdgeUSD:         edgePct.Mul(maxSize),
+		MaxSize:         maxSize,
+		Confidence:      conf,
+		RiskScore:       riskScore,
+		DecayType:       lead.DecayType,
+		ExpiresAt:       expiresAt,
+		Legs:            datatypes.JSON(legsJSON),
+		SignalIDs:       datatypes.JSON(signalIDsJSON),
+		SignalType:      lead.SignalType,
+		Reasoning: fmt.Sprintf("ensemble market=%s side=%s agree=%d strategies=%s confidence=%.2f",
+			marketID, direction, len(members), strings.Join(names, ","), conf),
+		DataAgeMs: dataAge,
+		Warnings:  datatypes.JSON(warningsJSON),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,99 @@
+package strategy
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+)
+
+func ensembleOpp(id uint64, marketID, direction string, conf float64, edge, size string, signalIDs ...uint64) models.Opportunity {
+	legs, _ := json.Marshal([]map[string]any{{"token_id": marketID + "-" + direction, "market_id": marketID, "direction": direction}})
+	sigs, _ := json.Marshal(signalIDs)
+	return models.Opportunity{
+		ID:         id,
+		Confidence: conf,
+		EdgePct:    decimal.RequireFromString(edge),
+		MaxSize:    decimal.RequireFromString(size),
+		RiskScore:  0.5,
+		Legs:       datatypes.JSON(legs),
+		SignalIDs:  datatypes.JSON(sigs),
+		Warnings:   datatypes.JSON(`["price_jump"]`),
+	}
+}
+
+func TestEnsembleStrategy_CombinesAgreementAndSuppressesConflicts(t *testing.T) {
+	s := &EnsembleStrategy{}
+	_ = s.SetParams(s.DefaultParams())
+
+	s.Observe("news_alpha", []models.Opportunity{
+		ensembleOpp(1, "m1", "BUY_YES", 0.6, "0.10", "50", 11),
+		ensembleOpp(2, "m2", "BUY_YES", 0.7, "0.10", "50"),
+		ensembleOpp(3, "m3", "BUY_NO", 0.9, "0.10", "50"),
+	})
+	s.Observe("market_anomaly", []models.Opportunity{
+		ensembleOpp(4, "m1", "BUY_YES", 0.5, "0.20", "20", 11, 12),
+		ensembleOpp(5, "m2", "BUY_NO", 0.8, "0.10", "50"),
+	})
+	// A stronger duplicate from the same strategy replaces its weaker one.
+	s.Observe("news_alpha", []models.Opportunity{ensembleOpp(6, "m1", "BUY_YES", 0.65, "0.10", "50")})
+	// Baskets have no single side and are ignored.
+	basket, _ := json.Marshal([]map[string]any{{"market_id": "m4", "direction": "BUY_YES"}, {"market_id": "m5", "direction": "BUY_YES"}})
+	s.Observe("arb_sum", []models.Opportunity{{Confidence: 0.9, Legs: datatypes.JSON(basket)}})
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	res := s.Combine(now)
+	if res.Consumed != 6 || res.Markets != 3 {
+		t.Fatalf("consumed=%d markets=%d", res.Consumed, res.Markets)
+	}
+	if res.Rejects["conflict"] != 1 || res.Rejects["no_agreement"] != 1 {
+		t.Fatalf("rejects=%v", res.Rejects)
+	}
+	if len(res.Opportunities) != 1 {
+		t.Fatalf("opportunities=%d", len(res.Opportunities))
+	}
+	got := res.Opportunities[0]
+	// noisy-OR 1-(0.35*0.5)=0.825 blended halfway with the strongest 0.65.
+	if math.Abs(got.Confidence-0.7375) > 1e-9 {
+		t.Fatalf("confidence=%v", got.Confidence)
+	}
+	if *got.PrimaryMarketID != "m1" || !got.MaxSize.Equal(decimal.NewFromInt(20)) {
+		t.Fatalf("market=%s size=%s", *got.PrimaryMarketID, got.MaxSize)
+	}
+	// (0.10*0.65 + 0.20*0.5) / 1.15
+	if got.EdgePct.Round(4).String() != "0.1435" || !got.EdgeUSD.Equal(got.EdgePct.Mul(got.MaxSize)) {
+		t.Fatalf("edge=%s edge_usd=%s", got.EdgePct, got.EdgeUSD)
+	}
+	var legs []map[string]any
+	_ = json.Unmarshal(got.Legs, &legs)
+	members, _ := legs[0]["ensemble_members"].([]any)
+	if len(legs) != 1 || legs[0]["direction"] != "BUY_YES" || len(members) != 2 {
+		t.Fatalf("legs=%s", got.Legs)
+	}
+	if lead := members[0].(map[string]any); lead["strategy"] != "news_alpha" || lead["opportunity_id"] != float64(6) {
+		t.Fatalf("lead member=%v", lead)
+	}
+	if string(got.SignalIDs) != "[11,12]" || string(got.Warnings) != `["price_jump"]` {
+		t.Fatalf("signals=%s warnings=%s", got.SignalIDs, got.Warnings)
+	}
+
+	if res := s.Combine(now); res.Consumed != 0 || len(res.Opportunities) != 0 {
+		t.Fatalf("buffer not drained: %+v", res)
+	}
+}
+
+func TestEnsembleStrategy_MinConfidence(t *testing.T) {
+	s := &EnsembleStrategy{}
+	_ = s.SetParams(json.RawMessage(`{"min_agreement":2,"correlation":1,"min_confidence":0.5}`))
+	s.Observe("a", []models.Opportunity{ensembleOpp(1, "m1", "BUY_NO", 0.4, "0.1", "10")})
+	s.Observe("b", []models.Opportunity{ensembleOpp(2, "m1", "BUY_NO", 0.3, "0.1", "10")})
+	res := s.Combine(time.Now())
+	if len(res.Opportunities) != 0 || res.Rejects["low_confidence"] != 1 {
+		t.Fatalf("res=%+v", res)
+	}
+}
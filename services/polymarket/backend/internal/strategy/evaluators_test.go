@@ -3,6 +3,8 @@ package strategy
 import (
 	"context"
 	"encoding/json"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +12,8 @@ import (
 	"gorm.io/datatypes"
 
 	"polymarket/internal/models"
+	"polymarket/internal/paas"
+	"polymarket/internal/repository"
 )
 
 func mkBook(t *testing.T, tokenID string, ask float64, askSize float64, now time.Time) models.OrderbookLatest {
@@ -83,6 +87,59 @@ func TestArbSumStrategy_Evaluate_BuyNo(t *testing.T) {
 	}
 }
 
+func TestArbitrageSumStrategy_NonBinaryMarket(t *testing.T) {
+	now := time.Now().UTC()
+	mid := func(token string, price float64) models.OrderbookLatest {
+		b := mkBook(t, token, price, 100, now)
+		b.Mid = &price
+		return b
+	}
+	repo := &stubRepo{
+		marketsByEvent: map[string][]models.Market{
+			"e1": {{ID: "m1", EventID: "e1", Question: "Who wins?", LastSeenAt: now}},
+		},
+		tokensByMarket: map[string][]models.Token{
+			"m1": {
+				{ID: "a", MarketID: "m1", Outcome: "Lakers", OutcomeIndex: 0},
+				{ID: "b", MarketID: "m1", Outcome: "Celtics", OutcomeIndex: 1},
+				{ID: "c", MarketID: "m1", Outcome: "Draw", OutcomeIndex: 2},
+			},
+		},
+		// Outcomes sum to 0.90: buying all three pays 1.
+		booksByToken: map[string]models.OrderbookLatest{
+			"a": mid("a", 0.40),
+			"b": mid("b", 0.35),
+			"c": mid("c", 0.15),
+		},
+		tradesByToken: map[string]models.LastTradePrice{},
+	}
+	s := &ArbitrageSumStrategy{Repo: repo}
+	_ = s.SetParams(s.DefaultParams())
+
+	sig := models.Signal{ID: 1, SignalType: "arb_sum_deviation", EventID: strPtr("e1"), MarketID: strPtr("m1"), CreatedAt: now}
+	opps, err := s.Evaluate(context.Background(), []models.Signal{sig})
+	if err != nil || len(opps) != 1 {
+		t.Fatalf("opps=%d err=%v", len(opps), err)
+	}
+	var legs []map[string]any
+	_ = json.Unmarshal(opps[0].Legs, &legs)
+	want := []string{"BUY_LAKERS", "BUY_CELTICS", "BUY_DRAW"}
+	if len(legs) != len(want) {
+		t.Fatalf("legs=%v", legs)
+	}
+	for i, leg := range legs {
+		if leg["direction"] != want[i] {
+			t.Fatalf("leg %d direction=%v want %s", i, leg["direction"], want[i])
+		}
+	}
+
+	// Overpriced outcomes have no complement to buy.
+	repo.booksByToken["a"] = mid("a", 0.60)
+	if opps, _ := s.Evaluate(context.Background(), []models.Signal{sig}); len(opps) != 0 {
+		t.Fatalf("expected no opportunity when sum > 1, got %d", len(opps))
+	}
+}
+
 func TestSystematicNOStrategy_Evaluate(t *testing.T) {
 	now := time.Now().UTC()
 	repo := &stubRepo{
@@ -125,6 +182,50 @@ func TestPreMarketFDVStrategy_Evaluate(t *testing.T) {
 	}
 }
 
+type fakeTokenRisk struct{ token *paas.TokenRisk }
+
+func (f fakeTokenRisk) Lookup(_ context.Context, project string) (*paas.TokenRisk, error) {
+	out := *f.token
+	out.Query = project
+	return &out, nil
+}
+
+func TestPreMarketFDVStrategy_TokenRiskAdjustsConfidence(t *testing.T) {
+	now := time.Now().UTC()
+	repo := &stubRepo{
+		marketsByID:  map[string]models.Market{"m1": {ID: "m1", Question: "Will Monad's FDV be above $3B one day after launch?"}},
+		booksByToken: map[string]models.OrderbookLatest{"n1": mkBook(t, "n1", 0.45, 100, now)},
+	}
+	sig := models.Signal{ID: 3, SignalType: "fdv_overpriced", MarketID: strPtr("m1"), TokenID: strPtr("n1"), Strength: 0.7, Direction: "NO", Payload: datatypes.JSON(`{}`), CreatedAt: now}
+
+	cases := []struct {
+		name  string
+		token paas.TokenRisk
+		want  float64
+	}{
+		{"untraded", paas.TokenRisk{}, 0.7},
+		{"past threshold", paas.TokenRisk{Found: true, Symbol: "MON", LiquidityUSD: 1e6, FDVUSD: 4e9}, 0.35},
+		{"thin liquidity ignores fdv", paas.TokenRisk{Found: true, LiquidityUSD: 1000, FDVUSD: 4e9}, 0.7},
+		{"far below with flags", paas.TokenRisk{Found: true, LiquidityUSD: 1e6, FDVUSD: 1e9, Flags: []string{"is_mintable"}}, 0.7 * 1.1 * 1.1},
+	}
+	for _, tc := range cases {
+		s := &PreMarketFDVStrategy{Repo: repo, TokenRisk: fakeTokenRisk{token: &tc.token}}
+		_ = s.SetParams(s.DefaultParams())
+		opps, err := s.Evaluate(context.Background(), []models.Signal{sig})
+		if err != nil || len(opps) != 1 {
+			t.Fatalf("%s: opps=%d err=%v", tc.name, len(opps), err)
+		}
+		if math.Abs(opps[0].Confidence-tc.want) > 1e-9 {
+			t.Fatalf("%s: confidence=%v want=%v (warnings %s)", tc.name, opps[0].Confidence, tc.want, opps[0].Warnings)
+		}
+	}
+
+	q := parseFDVQuestion("MegaETH FDV below $500M at launch?")
+	if q.Project != "MegaETH" || q.ThresholdUSD != 5e8 || !q.Below {
+		t.Fatalf("parsed %+v", q)
+	}
+}
+
 func TestNewsAlphaStrategy_Evaluate(t *testing.T) {
 	now := time.Now().UTC()
 	repo := &stubRepo{
@@ -301,6 +402,44 @@ func TestMMBehaviorStrategy_Evaluate(t *testing.T) {
 	}
 }
 
+func TestMMBehaviorStrategy_VolumeProfileAnchor(t *testing.T) {
+	now := time.Now().UTC()
+	repo := &stubRepo{
+		tokensByMarket: map[string][]models.Token{
+			"m1": {
+				{ID: "y1", MarketID: "m1", Outcome: "Yes"},
+				{ID: "n1", MarketID: "m1", Outcome: "No"},
+			},
+		},
+		booksByToken: map[string]models.OrderbookLatest{
+			"y1": mkBook(t, "y1", 0.80, 100, now),
+			"n1": mkBook(t, "n1", 0.20, 100, now),
+		},
+		// The tape has mostly traded at 0.80: the extreme is accepted value.
+		profiles: map[string]repository.VolumeProfile{
+			"y1": {TokenID: "y1", Volume: 2000, BuyVolume: 1000, SellVolume: 1000, POC: 0.80},
+		},
+	}
+	s := &MMBehaviorStrategy{Repo: repo}
+	_ = s.SetParams(s.DefaultParams())
+	sig := models.Signal{ID: 9, SignalType: "mm_inventory_skew", MarketID: strPtr("m1"), TokenID: strPtr("y1"), Strength: 0.9, Payload: datatypes.JSON([]byte(`{}`)), CreatedAt: now}
+	opps, _ := s.Evaluate(context.Background(), []models.Signal{sig})
+	if len(opps) != 0 {
+		t.Fatalf("expected no fade against the point of control, got %d", len(opps))
+	}
+
+	// A POC near the middle with takers still lifting YES keeps the fade but
+	// with lower confidence.
+	repo.profiles["y1"] = repository.VolumeProfile{TokenID: "y1", Volume: 2000, BuyVolume: 1800, SellVolume: 200, POC: 0.55}
+	opps, _ = s.Evaluate(context.Background(), []models.Signal{sig})
+	if len(opps) != 1 {
+		t.Fatalf("opps=%d want=1", len(opps))
+	}
+	if math.Abs(opps[0].Confidence-0.72) > 1e-9 || !strings.Contains(string(opps[0].Warnings), "one_sided_flow") {
+		t.Fatalf("confidence=%v warnings=%s", opps[0].Confidence, opps[0].Warnings)
+	}
+}
+
 func TestCertaintySweepStrategy_Evaluate(t *testing.T) {
 	now := time.Now().UTC()
 	repo := &stubRepo{
@@ -383,3 +522,67 @@ func TestMarketAnomalyStrategy_Evaluate(t *testing.T) {
 		t.Fatalf("edge_pct=%s want>0", opps[0].EdgePct.String())
 	}
 }
+
+func TestCopyFlowStrategy_Evaluate(t *testing.T) {
+	now := time.Now().UTC()
+	repo := &stubRepo{
+		booksByToken: map[string]models.OrderbookLatest{
+			"y1": mkBook(t, "y1", 0.41, 100, now),
+		},
+	}
+	s := &CopyFlowStrategy{Repo: repo}
+	_ = s.SetParams(s.DefaultParams())
+
+	payload := datatypes.JSON([]byte(`{"wallet":"0xabc","outcome":"Yes","delta_usd":5000,"price":0.40}`))
+	sig := models.Signal{ID: 13, SignalType: "smart_money_move", Source: "smart_money", MarketID: strPtr("m1"), TokenID: strPtr("y1"), Strength: 0.8, Direction: "YES", Payload: payload, CreatedAt: now}
+	opps, err := s.Evaluate(context.Background(), []models.Signal{sig})
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if len(opps) != 1 {
+		t.Fatalf("opps=%d want=1", len(opps))
+	}
+
+	// Ask has run away from the wallet's price: do not chase.
+	repo.booksByToken["y1"] = mkBook(t, "y1", 0.45, 100, now)
+	opps, err = s.Evaluate(context.Background(), []models.Signal{sig})
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if len(opps) != 0 {
+		t.Fatalf("opps=%d want=0", len(opps))
+	}
+}
+
+func TestCertaintySweepStrategy_TimeLadder(t *testing.T) {
+	now := time.Now().UTC()
+	repo := &stubRepo{
+		booksByToken: map[string]models.OrderbookLatest{
+			"y1": mkBook(t, "y1", 0.975, 100, now),
+		},
+	}
+	s := &CertaintySweepStrategy{Repo: repo}
+	_ = s.SetParams(s.DefaultParams())
+
+	mk := func(hoursLeft float64) models.Signal {
+		raw, _ := json.Marshal(map[string]any{"hours_left": hoursLeft})
+		return models.Signal{ID: 14, SignalType: "market_close_countdown", Source: "market_close", MarketID: strPtr("m1"), TokenID: strPtr("y1"), Strength: 0.9, Payload: datatypes.JSON(raw), CreatedAt: now}
+	}
+	cases := []struct {
+		hoursLeft float64
+		want      int
+	}{
+		{hoursLeft: 12, want: 1}, // 24h rung: 0.975 >= 0.97
+		{hoursLeft: 1, want: 0},  // 2h rung: 0.975 < 0.99
+		{hoursLeft: 48, want: 0}, // outside every rung
+	}
+	for _, tc := range cases {
+		opps, err := s.Evaluate(context.Background(), []models.Signal{mk(tc.hoursLeft)})
+		if err != nil {
+			t.Fatalf("hours_left=%v err=%v", tc.hoursLeft, err)
+		}
+		if len(opps) != tc.want {
+			t.Fatalf("hours_left=%v opps=%d want=%d", tc.hoursLeft, len(opps), tc.want)
+		}
+	}
+}
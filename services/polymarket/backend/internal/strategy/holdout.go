@@ -0,0 +1,149 @@
+package strategy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"polymarket/internal/models"
+)
+
+type paramSetter interface {
+	SetParams(json.RawMessage) error
+}
+
+// evaluate runs ev on batch. While the strategy's latest params change is
+// under holdout, the batch is evaluated twice: under the current params for
+// the treatment cohort and under the old params for the control cohort, and
+// each opportunity is kept from the run of the cohort its market falls in.
+func (e *Engine) evaluate(ctx context.Context, ev StrategyEvaluator, batch []models.Signal) ([]models.Opportunity, error) {
+	change, ok := e.activeHoldout(ev.Name(), time.Now().UTC())
+	setter, settable := ev.(paramSetter)
+	if _, combiner := ev.(OpportunityCombiner); !ok || !settable || combiner {
+		return ev.Evaluate(ctx, batch)
+	}
+	// Params are swapped on the shared evaluator, so its other workers and
+	// the reload loop wait until the current params are back.
+	mu := e.evalLock(ev.Name())
+	mu.Lock()
+	defer mu.Unlock()
+
+	treatment, err := ev.Evaluate(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	old := mergeParams(ev, e.StrategyDefaults, ev.Name(), change.OldParams)
+	if err := setter.SetParams(json.RawMessage(old)); err != nil {
+		// The old params no longer parse: there is no control cohort, and
+		// untagged opportunities stay out of the comparison.
+		if e.Logger != nil {
+			e.Logger.Warn("holdout params rejected", zap.String("strategy", ev.Name()), zap.Uint64("change_id", change.ID), zap.Error(err))
+		}
+		return treatment, nil
+	}
+	control, err := ev.Evaluate(ctx, batch)
+	if current := e.currentParams(ev.Name()); len(current) > 0 {
+		_ = setter.SetParams(json.RawMessage(current))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]models.Opportunity, 0, len(treatment)+len(control))
+	for _, o := range treatment {
+		if !inHoldout(change, o) {
+			out = append(out, tagCohort(o, change.ID, models.CohortTreatment))
+		}
+	}
+	for _, o := range control {
+		if inHoldout(change, o) {
+			out = append(out, tagCohort(o, change.ID, models.CohortControl))
+		}
+	}
+	return out, nil
+}
+
+func tagCohort(o models.Opportunity, changeID uint64, cohort string) models.Opportunity {
+	id := changeID
+	o.StrategyChangeID = &id
+	o.Cohort = cohort
+	return o
+}
+
+// inHoldout reports whether o's market is in the change's control cohort.
+// The split hashes the change ID with the market key, so a market stays in
+// one cohort for the whole holdout and the next change reshuffles.
+func inHoldout(change models.StrategyChange, o models.Opportunity) bool {
+	sum := sha256.Sum256([]byte(strconv.FormatUint(change.ID, 10) + ":" + holdoutKey(o)))
+	return float64(binary.BigEndian.Uint64(sum[:8]))/math.MaxUint64*100 < change.HoldoutPct
+}
+
+// holdoutKey is the market an opportunity is about, as UpsertActiveOpportunity
+// keys it: the event, else the primary market, else its markets or legs.
+func holdoutKey(o models.Opportunity) string {
+	if o.EventID != nil && strings.TrimSpace(*o.EventID) != "" {
+		return "e:" + strings.TrimSpace(*o.EventID)
+	}
+	if o.PrimaryMarketID != nil && strings.TrimSpace(*o.PrimaryMarketID) != "" {
+		return "m:" + strings.TrimSpace(*o.PrimaryMarketID)
+	}
+	if len(o.MarketIDs) > 0 {
+		return "ms:" + string(o.MarketIDs)
+	}
+	return "l:" + string(o.Legs)
+}
+
+func (e *Engine) activeHoldout(name string, now time.Time) (models.StrategyChange, bool) {
+	e.holdoutMu.RLock()
+	change, ok := e.holdoutByName[name]
+	e.holdoutMu.RUnlock()
+	if !ok || !change.HoldoutActive(now) {
+		return models.StrategyChange{}, false
+	}
+	return change, true
+}
+
+func (e *Engine) reloadHoldouts(ctx context.Context) {
+	items, err := e.Repo.ListActiveStrategyChanges(ctx, time.Now().UTC())
+	if err != nil {
+		if e.Logger != nil {
+			e.Logger.Debug("load strategy holdouts failed", zap.Error(err))
+		}
+		return
+	}
+	next := make(map[string]models.StrategyChange, len(items))
+	for _, it := range items {
+		next[it.StrategyName] = it
+	}
+	e.holdoutMu.Lock()
+	e.holdoutByName = next
+	e.holdoutMu.Unlock()
+}
+
+func (e *Engine) currentParams(name string) json.RawMessage {
+	e.paramsMu.RLock()
+	defer e.paramsMu.RUnlock()
+	return json.RawMessage(e.paramsByName[name])
+}
+
+func (e *Engine) evalLock(name string) *sync.Mutex {
+	e.evalMu.Lock()
+	defer e.evalMu.Unlock()
+	if e.evalLocks == nil {
+		e.evalLocks = map[string]*sync.Mutex{}
+	}
+	mu, ok := e.evalLocks[name]
+	if !ok {
+		mu = &sync.Mutex{}
+		e.evalLocks[name] = mu
+	}
+	return mu
+}
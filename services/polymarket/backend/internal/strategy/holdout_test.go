@@ -0,0 +1,89 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+)
+
+// versionEvaluator emits one opportunity per signal market, reasoned with
+// the params version it ran under.
+type versionEvaluator struct {
+	Version string `json:"version"`
+}
+
+func (v *versionEvaluator) Name() string              { return "versioned" }
+func (v *versionEvaluator) RequiredSignals() []string { return []string{"test"} }
+func (v *versionEvaluator) DefaultParams() json.RawMessage {
+	return json.RawMessage(`{"version":"default"}`)
+}
+func (v *versionEvaluator) SetParams(raw json.RawMessage) error {
+	return json.Unmarshal(raw, v)
+}
+func (v *versionEvaluator) Evaluate(ctx context.Context, signals []models.Signal) ([]models.Opportunity, error) {
+	out := make([]models.Opportunity, 0, len(signals))
+	for _, sig := range signals {
+		out = append(out, models.Opportunity{PrimaryMarketID: sig.MarketID, Reasoning: v.Version})
+	}
+	return out, nil
+}
+
+func TestEvaluateSplitsHoldoutCohorts(t *testing.T) {
+	ev := &versionEvaluator{Version: "new"}
+	until := time.Now().UTC().Add(time.Hour)
+	change := models.StrategyChange{ID: 7, StrategyName: ev.Name(), OldParams: datatypes.JSON(`{"version":"old"}`), HoldoutPct: 30, HoldoutUntil: &until}
+	e := &Engine{
+		paramsByName:  map[string]datatypes.JSON{ev.Name(): datatypes.JSON(`{"version":"new"}`)},
+		holdoutByName: map[string]models.StrategyChange{ev.Name(): change},
+	}
+	batch := make([]models.Signal, 200)
+	for i := range batch {
+		id := fmt.Sprintf("m%d", i)
+		batch[i].MarketID = &id
+	}
+
+	opps, err := e.evaluate(context.Background(), ev, batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opps) != len(batch) {
+		t.Fatalf("opps=%d want one per market", len(opps))
+	}
+	control := 0
+	for _, o := range opps {
+		if o.StrategyChangeID == nil || *o.StrategyChangeID != change.ID {
+			t.Fatalf("untagged opportunity %+v", o)
+		}
+		wantVersion := "new"
+		if o.Cohort == models.CohortControl {
+			control++
+			wantVersion = "old"
+		}
+		if o.Reasoning != wantVersion || inHoldout(change, o) != (o.Cohort == models.CohortControl) {
+			t.Fatalf("cohort %s ran on %s", o.Cohort, o.Reasoning)
+		}
+	}
+	if control < 30 || control > 90 {
+		t.Fatalf("control=%d of %d at 30%%", control, len(opps))
+	}
+	if ev.Version != "new" {
+		t.Fatalf("params not restored: %s", ev.Version)
+	}
+
+	// Once the holdout ends every market runs on the new params, untagged.
+	past := time.Now().UTC().Add(-time.Minute)
+	change.HoldoutUntil = &past
+	e.holdoutByName[ev.Name()] = change
+	opps, _ = e.evaluate(context.Background(), ev, batch)
+	for _, o := range opps {
+		if o.Cohort != "" || o.Reasoning != "new" {
+			t.Fatalf("after holdout %+v", o)
+		}
+	}
+}
@@ -17,7 +17,10 @@ import (
 )
 
 // MMBehaviorStrategy (P2) consumes "mm_inventory_skew" signals.
-// MVP: uses the same contrarian extreme pricing heuristic to surface opportunities around wide spreads.
+// It fades extreme YES prices around wide spreads. Prices revert toward the
+// YES token's volume point of control from the trade tape, or toward 0.5
+// when the tape is too thin; one-sided taker flow into the extreme lowers
+// confidence.
 type MMBehaviorStrategy struct {
 	Repo   repository.Repository
 	Logger *zap.Logger
@@ -28,6 +31,11 @@ type MMBehaviorStrategy struct {
 	YesExtremeMin    float64
 	YesExtremeMax    float64
 	MeanRevertWeight float64
+	// VolumeWindowHours and ProfileBucket shape the volume profile; below
+	// MinTapeVolume shares traded the profile is ignored.
+	VolumeWindowHours float64
+	ProfileBucket     float64
+	MinTapeVolume     float64
 }
 
 func (s *MMBehaviorStrategy) Name() string { return "mm_behavior" }
@@ -35,15 +43,18 @@ func (s *MMBehaviorStrategy) Name() string { return "mm_behavior" }
 func (s *MMBehaviorStrategy) RequiredSignals() []string { return []string{"mm_inventory_skew"} }
 
 func (s *MMBehaviorStrategy) DefaultParams() json.RawMessage {
-	return json.RawMessage(`{"min_edge_pct":0.05,"yes_extreme_min":0.75,"yes_extreme_max":0.25,"mean_revert_weight":0.5}`)
+	return json.RawMessage(`{"min_edge_pct":0.05,"yes_extreme_min":0.75,"yes_extreme_max":0.25,"mean_revert_weight":0.5,"volume_window_hours":6,"profile_bucket":0.01,"min_tape_volume":500}`)
 }
 
 func (s *MMBehaviorStrategy) SetParams(raw json.RawMessage) error {
 	var p struct {
-		MinEdgePct       *float64 `json:"min_edge_pct"`
-		YesExtremeMin    *float64 `json:"yes_extreme_min"`
-		YesExtremeMax    *float64 `json:"yes_extreme_max"`
-		MeanRevertWeight *float64 `json:"mean_revert_weight"`
+		MinEdgePct        *float64 `json:"min_edge_pct"`
+		YesExtremeMin     *float64 `json:"yes_extreme_min"`
+		YesExtremeMax     *float64 `json:"yes_extreme_max"`
+		MeanRevertWeight  *float64 `json:"mean_revert_weight"`
+		VolumeWindowHours *float64 `json:"volume_window_hours"`
+		ProfileBucket     *float64 `json:"profile_bucket"`
+		MinTapeVolume     *float64 `json:"min_tape_volume"`
 	}
 	if len(raw) > 0 {
 		_ = json.Unmarshal(raw, &p)
@@ -62,6 +73,15 @@ func (s *MMBehaviorStrategy) SetParams(raw json.RawMessage) error {
 	if p.MeanRevertWeight != nil {
 		s.MeanRevertWeight = *p.MeanRevertWeight
 	}
+	if p.VolumeWindowHours != nil {
+		s.VolumeWindowHours = *p.VolumeWindowHours
+	}
+	if p.ProfileBucket != nil {
+		s.ProfileBucket = *p.ProfileBucket
+	}
+	if p.MinTapeVolume != nil {
+		s.MinTapeVolume = *p.MinTapeVolume
+	}
 	return nil
 }
 
@@ -111,6 +131,9 @@ func (s *MMBehaviorStrategy) Evaluate(ctx context.Context, signals []models.Sign
 	yesExtremeMin := s.YesExtremeMin
 	yesExtremeMax := s.YesExtremeMax
 	meanRevertWeight := s.MeanRevertWeight
+	windowHours := s.VolumeWindowHours
+	bucket := s.ProfileBucket
+	minTape := s.MinTapeVolume
 	s.mu.RUnlock()
 	if minEdgeRaw <= 0 {
 		minEdgeRaw = 0.05
@@ -124,6 +147,15 @@ func (s *MMBehaviorStrategy) Evaluate(ctx context.Context, signals []models.Sign
 	if meanRevertWeight <= 0 || meanRevertWeight > 1 {
 		meanRevertWeight = 0.5
 	}
+	if windowHours <= 0 {
+		windowHours = 6
+	}
+	if bucket <= 0 {
+		bucket = 0.01
+	}
+	if minTape <= 0 {
+		minTape = 500
+	}
 
 	side := ""
 	if yesAsk.GreaterThanOrEqual(decimal.NewFromFloat(yesExtremeMin)) {
@@ -135,7 +167,23 @@ func (s *MMBehaviorStrategy) Evaluate(ctx context.Context, signals []models.Sign
 	}
 
 	pYesNow, _ := yesAsk.Float64()
-	pYesExp := (1.0-meanRevertWeight)*pYesNow + meanRevertWeight*0.5
+	anchor := 0.5
+	confidence := clamp01(sig.Strength)
+	warnings := []string{"wide_spread"}
+	since := time.Now().UTC().Add(-time.Duration(windowHours * float64(time.Hour)))
+	profile, err := s.Repo.TradeVolumeProfile(ctx, yesTokenID, since, bucket)
+	if err == nil && profile.Volume >= minTape && profile.POC > 0 {
+		anchor = profile.POC
+		// Takers still lifting (or hitting) into the extreme keep pushing it.
+		buyShare := profile.BuyVolume / profile.Volume
+		if (side == "BUY_NO" && buyShare >= 0.7) || (side == "BUY_YES" && buyShare <= 0.3) {
+			confidence = clamp01(confidence * 0.8)
+			warnings = append(warnings, "one_sided_flow")
+		}
+	} else {
+		warnings = append(warnings, "thin_tape")
+	}
+	pYesExp := (1.0-meanRevertWeight)*pYesNow + meanRevertWeight*anchor
 	pYesExp = clamp01(pYesExp)
 
 	tokenID := yesTokenID
@@ -179,14 +227,16 @@ func (s *MMBehaviorStrategy) Evaluate(ctx context.Context, signals []models.Sign
 			"fillable_size":    askSize.InexactFloat64(),
 			"p_yes_now":        pYesNow,
 			"p_yes_expected":   pYesExp,
+			"anchor":           anchor,
 		},
 	}
 	legsJSON, _ := json.Marshal(legs)
 	marketIDsJSON, _ := json.Marshal([]string{marketID})
 	signalIDsJSON, _ := json.Marshal([]uint64{sig.ID})
 
-	reasoning := fmt.Sprintf("mm_behavior market=%s side=%s yes_ask=%s p_yes_expected=%.2f entry=%s",
-		marketID, side, yesAsk.StringFixed(4), pYesExp, askPrice.StringFixed(4))
+	reasoning := fmt.Sprintf("mm_behavior market=%s side=%s yes_ask=%s anchor=%.3f tape_volume=%.0f p_yes_expected=%.2f entry=%s",
+		marketID, side, yesAsk.StringFixed(4), anchor, profile.Volume, pYesExp, askPrice.StringFixed(4))
+	warningsJSON, _ := json.Marshal(warnings)
 	now := time.Now().UTC()
 
 	opp := models.Opportunity{
@@ -197,7 +247,7 @@ func (s *MMBehaviorStrategy) Evaluate(ctx context.Context, signals []models.Sign
 		EdgePct:         edgePct,
 		EdgeUSD:         edgeUSD,
 		MaxSize:         cost,
-		Confidence:      clamp01(sig.Strength),
+		Confidence:      confidence,
 		RiskScore:       0.88,
 		DecayType:       "step",
 		ExpiresAt:       sig.ExpiresAt,
@@ -205,7 +255,7 @@ func (s *MMBehaviorStrategy) Evaluate(ctx context.Context, signals []models.Sign
 		SignalIDs:       datatypes.JSON(signalIDsJSON),
 		Reasoning:       reasoning,
 		DataAgeMs:       int(time.Since(books[0].UpdatedAt).Milliseconds()),
-		Warnings:        datatypes.JSON([]byte(`["wide_spread"]`)),
+		Warnings:        datatypes.JSON(warningsJSON),
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +16,7 @@ import (
 
 	polymarketclob "polymarket/internal/client/polymarket/clob"
 	"polymarket/internal/models"
+	"polymarket/internal/paas"
 	"polymarket/internal/repository"
 )
 
@@ -21,11 +24,20 @@ type PreMarketFDVStrategy struct {
 	Repo   repository.Repository
 	Logger *zap.Logger
 
+	// TokenRisk looks up the project's token through the platform's
+	// Dexscreener/GoPlus integrations. Nil leaves confidence to the signal.
+	TokenRisk interface {
+		Lookup(ctx context.Context, project string) (*paas.TokenRisk, error)
+	}
+
 	mu sync.RWMutex
 
 	MinNoRate  float64
 	NoPriceMin float64
 	NoPriceMax float64
+	// TokenMinLiquidityUSD is the DEX liquidity below which a traded token's
+	// FDV is too thin to compare with the market threshold.
+	TokenMinLiquidityUSD float64
 }
 
 func (s *PreMarketFDVStrategy) Name() string { return "pre_market_fdv" }
@@ -33,13 +45,14 @@ func (s *PreMarketFDVStrategy) Name() string { return "pre_market_fdv" }
 func (s *PreMarketFDVStrategy) RequiredSignals() []string { return []string{"fdv_overpriced"} }
 
 func (s *PreMarketFDVStrategy) DefaultParams() json.RawMessage {
-	return json.RawMessage(`{"entry_window_days_before_tge":[14,28],"no_price_sweet_spot":[0.35,0.55],"min_liquidity_usd":500,"expected_no_rate":0.85,"exit_no_price_take_profit":0.15,"stop_loss_no_price":0.70,"avoid_first_week":true}`)
+	return json.RawMessage(`{"entry_window_days_before_tge":[14,28],"no_price_sweet_spot":[0.35,0.55],"min_liquidity_usd":500,"expected_no_rate":0.85,"exit_no_price_take_profit":0.15,"stop_loss_no_price":0.70,"avoid_first_week":true,"token_min_liquidity_usd":50000}`)
 }
 
 func (s *PreMarketFDVStrategy) SetParams(raw json.RawMessage) error {
 	var p struct {
-		ExpectedNoRate   *float64  `json:"expected_no_rate"`
-		NoPriceSweetSpot []float64 `json:"no_price_sweet_spot"`
+		ExpectedNoRate       *float64  `json:"expected_no_rate"`
+		NoPriceSweetSpot     []float64 `json:"no_price_sweet_spot"`
+		TokenMinLiquidityUSD *float64  `json:"token_min_liquidity_usd"`
 	}
 	if len(raw) > 0 {
 		_ = json.Unmarshal(raw, &p)
@@ -53,6 +66,9 @@ func (s *PreMarketFDVStrategy) SetParams(raw json.RawMessage) error {
 		s.NoPriceMin = p.NoPriceSweetSpot[0]
 		s.NoPriceMax = p.NoPriceSweetSpot[1]
 	}
+	if p.TokenMinLiquidityUSD != nil {
+		s.TokenMinLiquidityUSD = *p.TokenMinLiquidityUSD
+	}
 	return nil
 }
 
@@ -133,6 +149,22 @@ func (s *PreMarketFDVStrategy) Evaluate(ctx context.Context, signals []models.Si
 
 	reasoning := fmt.Sprintf("pre_market_fdv market=%s expected_no=%.3f days_to_end=%d entry=%s",
 		marketID, expectedNo, payload.DaysToEnd, askPrice.StringFixed(4))
+	confidence := clamp01(sig.Strength)
+	warnings := []string{}
+	if token, q := s.lookupToken(ctx, marketID); token != nil {
+		s.mu.RLock()
+		minLiq := s.TokenMinLiquidityUSD
+		s.mu.RUnlock()
+		factor, notes := tokenRiskAdjustment(token, q, minLiq)
+		confidence = clamp01(confidence * factor)
+		warnings = append(warnings, notes...)
+		if token.Found {
+			reasoning += fmt.Sprintf(" token=%s@%s dex_fdv=%.0f dex_liquidity=%.0f", token.Symbol, token.Chain, token.FDVUSD, token.LiquidityUSD)
+		} else {
+			reasoning += " token=untraded"
+		}
+	}
+	warningsJSON, _ := json.Marshal(warnings)
 	now := time.Now().UTC()
 
 	opp := models.Opportunity{
@@ -143,7 +175,7 @@ func (s *PreMarketFDVStrategy) Evaluate(ctx context.Context, signals []models.Si
 		EdgePct:         edgePct,
 		EdgeUSD:         edgeUSD,
 		MaxSize:         cost,
-		Confidence:      clamp01(sig.Strength),
+		Confidence:      confidence,
 		RiskScore:       0.7,
 		DecayType:       "time_bound",
 		ExpiresAt:       sig.ExpiresAt,
@@ -151,11 +183,109 @@ func (s *PreMarketFDVStrategy) Evaluate(ctx context.Context, signals []models.Si
 		SignalIDs:       datatypes.JSON(signalIDsJSON),
 		Reasoning:       reasoning,
 		DataAgeMs:       int(time.Since(books[0].UpdatedAt).Milliseconds()),
-		Warnings:        datatypes.JSON([]byte(`[]`)),
+		Warnings:        datatypes.JSON(warningsJSON),
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
 	return []models.Opportunity{opp}, nil
 }
 
+// lookupToken resolves the market's project and fetches its token risk. Any
+// failure returns nil so the opportunity keeps the signal's confidence.
+func (s *PreMarketFDVStrategy) lookupToken(ctx context.Context, marketID string) (*paas.TokenRisk, fdvQuestion) {
+	if s.TokenRisk == nil {
+		return nil, fdvQuestion{}
+	}
+	markets, err := s.Repo.ListMarketsByIDs(ctx, []string{marketID})
+	if err != nil || len(markets) == 0 {
+		return nil, fdvQuestion{}
+	}
+	q := parseFDVQuestion(markets[0].Question)
+	if q.Project == "" {
+		return nil, q
+	}
+	token, err := s.TokenRisk.Lookup(ctx, q.Project)
+	if err != nil {
+		if s.Logger != nil {
+			s.Logger.Debug("pre_market_fdv token lookup failed", zap.String("project", q.Project), zap.Error(err))
+		}
+		return nil, q
+	}
+	return token, q
+}
+
+// fdvQuestion is what an FDV market asks: whether Project's FDV ends above
+// (or, with Below, below) ThresholdUSD.
+type fdvQuestion struct {
+	Project      string
+	ThresholdUSD float64
+	Below        bool
+}
+
+var (
+	fdvProjectRe   = regexp.MustCompile(`(?i)^\s*(?:will\s+)?(?:the\s+)?(.+?)(?:'s)?\s+(?:fdv|fully diluted)`)
+	fdvThresholdRe = regexp.MustCompile(`(?i)\$\s*([0-9][0-9.,]*)\s*([kmb]|million|billion)?\b`)
+	fdvBelowRe     = regexp.MustCompile(`(?i)\b(below|under|less than)\b`)
+)
+
+func parseFDVQuestion(question string) fdvQuestion {
+	var q fdvQuestion
+	if m := fdvProjectRe.FindStringSubmatch(question); m != nil {
+		q.Project = strings.TrimSpace(m[1])
+	}
+	if m := fdvThresholdRe.FindStringSubmatch(question); m != nil {
+		if v, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64); err == nil {
+			switch strings.ToLower(m[2]) {
+			case "k":
+				v *= 1e3
+			case "m", "million":
+				v *= 1e6
+			case "b", "billion":
+				v *= 1e9
+			}
+			q.ThresholdUSD = v
+		}
+	}
+	q.Below = fdvBelowRe.MatchString(question)
+	return q
+}
+
+// tokenRiskAdjustment scales confidence in NO by what the token already
+// shows on-chain. A liquid DEX FDV on the YES side of the threshold halves
+// it; one far on the NO side, or GoPlus security flags, raise it by 10%.
+// Untraded tokens leave it unchanged.
+func tokenRiskAdjustment(token *paas.TokenRisk, q fdvQuestion, minLiquidityUSD float64) (float64, []string) {
+	if token == nil || !token.Found {
+		return 1, nil
+	}
+	if minLiquidityUSD <= 0 {
+		minLiquidityUSD = 50000
+	}
+	factor := 1.0
+	var notes []string
+	switch {
+	case token.LiquidityUSD < minLiquidityUSD:
+		notes = append(notes, "thin_dex_liquidity")
+	case q.ThresholdUSD > 0 && token.FDVUSD > 0:
+		ratio := token.FDVUSD / q.ThresholdUSD
+		yesSide := ratio >= 1
+		farNoSide := ratio < 0.5
+		if q.Below {
+			yesSide, farNoSide = ratio < 1, ratio >= 2
+		}
+		if yesSide {
+			factor *= 0.5
+			notes = append(notes, "dex_fdv_past_threshold")
+		} else if farNoSide {
+			factor *= 1.1
+			notes = append(notes, "dex_fdv_far_from_threshold")
+		}
+	}
+	if len(token.Flags) > 0 {
+		factor *= 1.1
+		notes = append(notes, "token_flags:"+strings.Join(token.Flags, ","))
+	}
+	return factor, notes
+}
+
 var _ = polymarketclob.OrderBook{}
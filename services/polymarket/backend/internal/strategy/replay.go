@@ -0,0 +1,167 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"polymarket/internal/models"
+	"polymarket/internal/repository"
+)
+
+// ReplayRepository shows an evaluator the market as it was at At, for
+// backtests. Order books and last trade prices are rebuilt from the
+// one-minute price candles: one level a side, Spread apart around the last
+// close, DepthShares deep. Everything else reads through to Repository, so
+// catalog data, labels and the volume profile are as of now.
+type ReplayRepository struct {
+	repository.Repository
+	At time.Time
+	// Spread is the full bid/ask spread around the close; 0 is 0.02.
+	Spread float64
+	// DepthShares is the size of each level; 0 is 100.
+	DepthShares float64
+	// Lookback is how old the last candle may be; 0 is one hour.
+	Lookback time.Duration
+}
+
+// ListOrderbookLatestByTokenIDs returns a book per token that traded within
+// Lookback before At.
+func (r *ReplayRepository) ListOrderbookLatestByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.OrderbookLatest, error) {
+	spread := r.Spread
+	if spread <= 0 {
+		spread = 0.02
+	}
+	depth := r.DepthShares
+	if depth <= 0 {
+		depth = 100
+	}
+	source := "replay"
+	out := make([]models.OrderbookLatest, 0, len(tokenIDs))
+	for _, id := range tokenIDs {
+		c, err := r.candleAt(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			continue
+		}
+		bid := math.Max(c.Close-spread/2, 0.001)
+		ask := math.Min(c.Close+spread/2, 0.999)
+		mid := (bid + ask) / 2
+		out = append(out, models.OrderbookLatest{
+			TokenID:        id,
+			SnapshotTS:     c.CloseTS,
+			BidsJSON:       replayLevel(bid, depth),
+			AsksJSON:       replayLevel(ask, depth),
+			BestBid:        &bid,
+			BestAsk:        &ask,
+			Mid:            &mid,
+			Source:         &source,
+			DataAgeSeconds: int(r.At.Sub(c.CloseTS).Seconds()),
+			UpdatedAt:      c.CloseTS,
+		})
+	}
+	return out, nil
+}
+
+// ListLastTradePricesByTokenIDs returns the last close before At per token.
+func (r *ReplayRepository) ListLastTradePricesByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.LastTradePrice, error) {
+	source := "replay"
+	out := make([]models.LastTradePrice, 0, len(tokenIDs))
+	for _, id := range tokenIDs {
+		c, err := r.candleAt(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			continue
+		}
+		ts := c.CloseTS
+		out = append(out, models.LastTradePrice{TokenID: id, Price: c.Close, TradeTS: &ts, Source: &source, UpdatedAt: ts})
+	}
+	return out, nil
+}
+
+func (r *ReplayRepository) candleAt(ctx context.Context, tokenID string) (*models.PriceCandle, error) {
+	tokenID = strings.TrimSpace(tokenID)
+	if tokenID == "" {
+		return nil, nil
+	}
+	lookback := r.Lookback
+	if lookback <= 0 {
+		lookback = time.Hour
+	}
+	candles, err := r.Repository.ListPriceCandles(ctx, tokenID, r.At.Add(-lookback), r.At)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(candles) - 1; i >= 0; i-- {
+		if c := candles[i]; c.Close > 0 && !c.CloseTS.After(r.At) {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func replayLevel(price, size float64) datatypes.JSON {
+	raw, _ := json.Marshal([]map[string]string{{
+		"price": strconv.FormatFloat(price, 'f', -1, 64),
+		"size":  strconv.FormatFloat(size, 'f', -1, 64),
+	}})
+	return datatypes.JSON(raw)
+}
+
+// NewReplayEvaluator builds a fresh evaluator for name that reads through
+// repo, for replaying stored signals. The ensemble, which only combines other
+// strategies, and pre_market_fdv, which needs live token risk, cannot be
+// replayed.
+func NewReplayEvaluator(name string, repo repository.Repository, logger *zap.Logger) (StrategyEvaluator, bool) {
+	switch name {
+	case "arb_sum":
+		return &ArbitrageSumStrategy{Repo: repo, Logger: logger}, true
+	case "systematic_no":
+		return &SystematicNOStrategy{Repo: repo, Logger: logger}, true
+	case "news_alpha":
+		return &NewsAlphaStrategy{Repo: repo, Logger: logger}, true
+	case "volatility_arb":
+		return &VolatilityArbStrategy{Repo: repo, Logger: logger}, true
+	case "weather":
+		return &WeatherStrategy{Repo: repo, Logger: logger}, true
+	case "btc_short_term":
+		return &BTCShortTermStrategy{Repo: repo, Logger: logger}, true
+	case "contrarian_fear":
+		return &ContrarianFearStrategy{Repo: repo, Logger: logger}, true
+	case "mm_behavior":
+		return &MMBehaviorStrategy{Repo: repo, Logger: logger}, true
+	case "certainty_sweep":
+		return &CertaintySweepStrategy{Repo: repo, Logger: logger}, true
+	case "liquidity_reward":
+		return &LiquidityRewardStrategy{Repo: repo, Logger: logger}, true
+	case "market_anomaly":
+		return &MarketAnomalyStrategy{Repo: repo, Logger: logger}, true
+	case "copy_flow":
+		return &CopyFlowStrategy{Repo: repo, Logger: logger}, true
+	}
+	return nil, false
+}
+
+// SetEvaluatorParams applies params to ev when it takes any.
+func SetEvaluatorParams(ev StrategyEvaluator, params json.RawMessage) error {
+	if p, ok := ev.(paramSetter); ok && len(params) > 0 {
+		return p.SetParams(params)
+	}
+	return nil
+}
+
+// EffectiveParams are the params the engine runs ev with: its defaults,
+// then the config defaults for name, then the stored params.
+func EffectiveParams(ev StrategyEvaluator, defaults map[string]any, name string, stored datatypes.JSON) datatypes.JSON {
+	return mergeParams(ev, defaults, name, stored)
+}
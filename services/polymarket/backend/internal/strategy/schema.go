@@ -0,0 +1,277 @@
+package strategy
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// StrategySchema documents a strategy for API clients so forms can be
+// rendered from it. Params come from DefaultParams, so names, types and
+// defaults always match the code; descriptions and ranges come from
+// strategyDocs.
+type StrategySchema struct {
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	RequiredSignals []string        `json:"required_signals"`
+	Params          []ParamSchema   `json:"params"`
+	Defaults        json.RawMessage `json:"defaults"`
+}
+
+// ParamSchema documents one strategy parameter. Type is a JSON type:
+// number, integer, boolean, string, array or object.
+type ParamSchema struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Default     json.RawMessage `json:"default,omitempty"`
+	Min         *float64        `json:"min,omitempty"`
+	Max         *float64        `json:"max,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+type strategyDoc struct {
+	description string
+	params      map[string]paramDoc
+}
+
+type paramDoc struct {
+	description string
+	min, max    *float64
+	// integer marks whole-number params; other numbers are "number".
+	integer bool
+}
+
+func between(min, max float64, description string) paramDoc {
+	return paramDoc{description: description, min: &min, max: &max}
+}
+
+func atLeast(min float64, description string) paramDoc {
+	return paramDoc{description: description, min: &min}
+}
+
+func described(description string) paramDoc {
+	return paramDoc{description: description}
+}
+
+func integer(p paramDoc) paramDoc {
+	p.integer = true
+	return p
+}
+
+// Shared parameter docs of the mean-reversion strategies.
+var (
+	minEdgeDoc          = between(0, 1, "minimum edge as a fraction of price to open an opportunity")
+	yesExtremeMinDoc    = between(0, 1, "YES price at or above which the move is treated as an overshoot")
+	yesExtremeMaxDoc    = between(0, 1, "YES price at or below which the move is treated as an overshoot")
+	meanRevertWeightDoc = between(0, 1, "share of the distance back to fair value expected to revert")
+)
+
+// strategyDocs registers the description and parameter docs of every
+// built-in strategy. Keep it in step with DefaultParams.
+var strategyDocs = map[string]strategyDoc{
+	"arb_sum": {
+		description: "Buys every outcome (or every complement) when outcome prices sum away from 1.",
+		params: map[string]paramDoc{
+			"min_deviation_pct":   atLeast(0, "minimum |sum - 1| in percent"),
+			"min_profit_usd":      atLeast(0, "minimum locked-in profit after sizing"),
+			"min_liquidity_usd":   atLeast(0, "minimum market liquidity"),
+			"alpha_extraction":    between(0, 1, "share of the deviation assumed capturable after slippage"),
+			"use_orderbook_depth": described("size legs from book depth instead of top of book"),
+			"max_legs":            integer(between(2, 50, "largest outcome set traded")),
+		},
+	},
+	"systematic_no": {
+		description: "Buys NO on labels that historically resolve NO more often than the NO price implies.",
+		params: map[string]paramDoc{
+			"no_price_range":     described("[min, max] NO price traded"),
+			"min_ev_pct":         atLeast(0, "minimum expected value in percent"),
+			"historical_no_rate": between(0, 1, "fallback NO rate when a label has no history"),
+			"category_no_rates":  described("NO rate overrides by label"),
+			"stop_loss_no_price": between(0, 1, "NO price at which the position is cut"),
+		},
+	},
+	"pre_market_fdv": {
+		description: "Sells overpriced pre-market FDV markets (buys NO) in the weeks before TGE.",
+		params: map[string]paramDoc{
+			"entry_window_days_before_tge": described("[min, max] days before TGE to enter"),
+			"no_price_sweet_spot":          described("[min, max] NO price to enter"),
+			"min_liquidity_usd":            atLeast(0, "minimum market liquidity"),
+			"expected_no_rate":             between(0, 1, "assumed NO resolution rate"),
+			"exit_no_price_take_profit":    between(0, 1, "take profit once NO is this far above entry"),
+			"stop_loss_no_price":           between(0, 1, "NO price at which the position is cut"),
+			"avoid_first_week":             described("skip markets in their first week"),
+			"token_min_liquidity_usd":      atLeast(0, "minimum liquidity of the underlying token when token risk is enabled"),
+		},
+	},
+	"news_alpha": {
+		description: "Fades sharp news-driven price jumps that overshoot.",
+		params: map[string]paramDoc{
+			"min_edge_pct":       minEdgeDoc,
+			"yes_extreme_min":    yesExtremeMinDoc,
+			"yes_extreme_max":    yesExtremeMaxDoc,
+			"mean_revert_weight": meanRevertWeightDoc,
+		},
+	},
+	"volatility_arb": {
+		description: "Trades back toward fair value when a jump comes with a wide spread.",
+		params: map[string]paramDoc{
+			"min_edge_pct":       minEdgeDoc,
+			"yes_extreme_min":    yesExtremeMinDoc,
+			"yes_extreme_max":    yesExtremeMaxDoc,
+			"mean_revert_weight": meanRevertWeightDoc,
+		},
+	},
+	"weather": {
+		description: "Trades temperature markets against blended weather forecasts.",
+		params: map[string]paramDoc{
+			"min_edge_pct":   minEdgeDoc,
+			"min_confidence": between(0, 1, "minimum forecast confidence"),
+		},
+	},
+	"btc_short_term": {
+		description: "Trades short-dated BTC markets on Binance order book imbalance.",
+		params: map[string]paramDoc{
+			"min_edge_pct": minEdgeDoc,
+		},
+	},
+	"contrarian_fear": {
+		description: "Buys the panic side after fear spikes in the book.",
+		params: map[string]paramDoc{
+			"min_edge_pct":       minEdgeDoc,
+			"yes_extreme_min":    yesExtremeMinDoc,
+			"yes_extreme_max":    yesExtremeMaxDoc,
+			"mean_revert_weight": meanRevertWeightDoc,
+		},
+	},
+	"mm_behavior": {
+		description: "Takes the other side of market maker inventory offloading.",
+		params: map[string]paramDoc{
+			"min_edge_pct":        minEdgeDoc,
+			"yes_extreme_min":     yesExtremeMinDoc,
+			"yes_extreme_max":     yesExtremeMaxDoc,
+			"mean_revert_weight":  meanRevertWeightDoc,
+			"volume_window_hours": between(1, 168, "tape window used for the volume profile"),
+			"profile_bucket":      between(0.001, 0.1, "price bucket width of the volume profile"),
+			"min_tape_volume":     atLeast(0, "minimum tape volume before the profile is trusted"),
+		},
+	},
+	"certainty_sweep": {
+		description: "Buys near-certain outcomes close to their end time for the remaining payout.",
+		params: map[string]paramDoc{
+			"min_edge_pct":    between(0, 1, "minimum edge when a rung sets none"),
+			"ladder":          described("rungs of {max_hours_left, min_price, expected_payout, min_edge_pct}; the tightest matching rung applies"),
+			"label_overrides": described("ladders by market label replacing the default ladder"),
+		},
+	},
+	"liquidity_reward": {
+		description: "Quotes inside wide spreads to earn liquidity rewards.",
+		params: map[string]paramDoc{
+			"min_edge_pct": minEdgeDoc,
+		},
+	},
+	"market_anomaly": {
+		description: "Fades YES prices stuck at extremes without news.",
+		params: map[string]paramDoc{
+			"min_edge_pct":       minEdgeDoc,
+			"mean_revert_target": between(0, 1, "fair value assumed for the reversion"),
+			"mean_revert_weight": meanRevertWeightDoc,
+		},
+	},
+	"copy_flow": {
+		description: "Follows position changes of tracked wallets.",
+		params: map[string]paramDoc{
+			"min_delta_usd":   atLeast(0, "smallest wallet position change followed"),
+			"max_chase_pct":   between(0, 1, "largest price move since the wallet's fill still followed"),
+			"follow_edge_pct": between(0, 1, "edge assumed for a followed move"),
+		},
+	},
+	"ensemble": {
+		description: "Combines base strategies that agree on a market side into one opportunity.",
+		params: map[string]paramDoc{
+			"min_agreement":  integer(atLeast(2, "strategies that must back the same side")),
+			"window_seconds": atLeast(1, "how long published opportunities are collected before combining"),
+			"correlation":    between(0, 1, "0 treats members as independent evidence, 1 keeps the strongest confidence"),
+			"min_confidence": between(0, 1, "smallest combined confidence emitted"),
+		},
+	},
+}
+
+// Builtins returns zero-value instances of the built-in strategies, for
+// metadata only: they have no repository and must not be evaluated.
+func Builtins() []StrategyEvaluator {
+	return []StrategyEvaluator{
+		&ArbitrageSumStrategy{},
+		&SystematicNOStrategy{},
+		&PreMarketFDVStrategy{},
+		&NewsAlphaStrategy{},
+		&VolatilityArbStrategy{},
+		&WeatherStrategy{},
+		&BTCShortTermStrategy{},
+		&ContrarianFearStrategy{},
+		&MMBehaviorStrategy{},
+		&CertaintySweepStrategy{},
+		&LiquidityRewardStrategy{},
+		&MarketAnomalyStrategy{},
+		&CopyFlowStrategy{},
+		&EnsembleStrategy{},
+	}
+}
+
+// Schema builds the schema of ev.
+func Schema(ev StrategyEvaluator) StrategySchema {
+	doc := strategyDocs[ev.Name()]
+	out := StrategySchema{
+		Name:            ev.Name(),
+		Description:     doc.description,
+		RequiredSignals: ev.RequiredSignals(),
+		Params:          []ParamSchema{},
+		Defaults:        ev.DefaultParams(),
+	}
+	var defaults map[string]json.RawMessage
+	_ = json.Unmarshal(out.Defaults, &defaults)
+	for name, raw := range defaults {
+		p := doc.params[name]
+		typ := jsonType(raw)
+		if typ == "number" && p.integer {
+			typ = "integer"
+		}
+		out.Params = append(out.Params, ParamSchema{
+			Name:        name,
+			Type:        typ,
+			Default:     raw,
+			Min:         p.min,
+			Max:         p.max,
+			Description: p.description,
+		})
+	}
+	sort.Slice(out.Params, func(i, j int) bool { return out.Params[i].Name < out.Params[j].Name })
+	return out
+}
+
+// FindSchema returns the schema of the built-in strategy name.
+func FindSchema(name string) (StrategySchema, bool) {
+	for _, ev := range Builtins() {
+		if ev.Name() == name {
+			return Schema(ev), true
+		}
+	}
+	return StrategySchema{}, false
+}
+
+func jsonType(raw json.RawMessage) string {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "string"
+	}
+	switch v.(type) {
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return "string"
+	}
+}
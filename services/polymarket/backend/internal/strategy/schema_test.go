@@ -0,0 +1,64 @@
+package strategy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"polymarket/internal/signal"
+)
+
+func TestSchema_DocumentsEveryBuiltin(t *testing.T) {
+	known := map[string]bool{}
+	for _, info := range signal.SignalTypes() {
+		known[info.Type] = true
+	}
+	for _, ev := range Builtins() {
+		doc, ok := strategyDocs[ev.Name()]
+		if !ok || doc.description == "" {
+			t.Fatalf("%s: no strategy doc", ev.Name())
+		}
+		s := Schema(ev)
+		if len(s.Params) == 0 {
+			t.Fatalf("%s: no params", ev.Name())
+		}
+		for _, p := range s.Params {
+			if _, ok := doc.params[p.Name]; !ok {
+				t.Fatalf("%s.%s: undocumented param", ev.Name(), p.Name)
+			}
+		}
+		var defaults map[string]json.RawMessage
+		_ = json.Unmarshal(s.Defaults, &defaults)
+		for name := range doc.params {
+			if _, ok := defaults[name]; !ok {
+				t.Fatalf("%s.%s: documented param has no default", ev.Name(), name)
+			}
+		}
+		for _, sig := range s.RequiredSignals {
+			if !known[sig] {
+				t.Fatalf("%s: required signal %q not registered", ev.Name(), sig)
+			}
+		}
+	}
+}
+
+func TestSchema_IntegerAndRange(t *testing.T) {
+	s, ok := FindSchema("arb_sum")
+	if !ok {
+		t.Fatal("arb_sum not found")
+	}
+	for _, p := range s.Params {
+		switch p.Name {
+		case "max_legs":
+			if p.Type != "integer" || p.Min == nil || *p.Min != 2 {
+				t.Fatalf("max_legs = %+v", p)
+			}
+		case "use_orderbook_depth":
+			if p.Type != "boolean" {
+				t.Fatalf("use_orderbook_depth type = %s", p.Type)
+			}
+		}
+	}
+	if _, ok := FindSchema("nope"); ok {
+		t.Fatal("unknown strategy found")
+	}
+}
@@ -54,7 +54,7 @@ func (u *StatsUpdater) UpdateOnce(ctx context.Context) error {
 		u.logWarn("list strategies failed", err)
 		return err
 	}
-	analytics, err := u.Repo.AnalyticsByStrategy(ctx)
+	analytics, err := u.Repo.AnalyticsByStrategy(ctx, "")
 	if err != nil {
 		u.logWarn("analytics by strategy failed", err)
 	}
@@ -9,6 +9,7 @@ import (
 
 	"polymarket/internal/models"
 	"polymarket/internal/repository"
+	"polymarket/internal/tradingday"
 )
 
 // stubRepo is a test-only in-memory implementation of repository.Repository.
@@ -20,10 +21,12 @@ type stubRepo struct {
 	tokensByID     map[string]models.Token
 	booksByToken   map[string]models.OrderbookLatest
 	tradesByToken  map[string]models.LastTradePrice
+	profiles       map[string]repository.VolumeProfile
 	labels         []models.MarketLabel
 }
 
 func (s *stubRepo) InTx(ctx context.Context, fn func(tx *gorm.DB) error) error { return fn(nil) }
+func (s *stubRepo) WithTx(tx *gorm.DB) repository.Repository                   { return s }
 func (s *stubRepo) UpsertEventsTx(ctx context.Context, tx *gorm.DB, items []models.Event) error {
 	return nil
 }
@@ -55,6 +58,40 @@ func (s *stubRepo) InsertRawWSEvent(ctx context.Context, item *models.RawWSEvent
 func (s *stubRepo) InsertRawRESTSnapshot(ctx context.Context, item *models.RawRESTSnapshot) error {
 	return nil
 }
+func (s *stubRepo) UpsertPriceCandles(ctx context.Context, items []models.PriceCandle) error {
+	return nil
+}
+func (s *stubRepo) ListPriceCandles(ctx context.Context, tokenID string, since, until time.Time) ([]models.PriceCandle, error) {
+	return nil, nil
+}
+func (s *stubRepo) InsertTrade(ctx context.Context, item *models.Trade) error { return nil }
+func (s *stubRepo) ListTrades(ctx context.Context, params repository.ListTradesParams) ([]models.Trade, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountTrades(ctx context.Context, params repository.ListTradesParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) TradeVolumeProfile(ctx context.Context, tokenID string, since time.Time, bucket float64) (repository.VolumeProfile, error) {
+	return s.profiles[tokenID], nil
+}
+func (s *stubRepo) PruneTrades(ctx context.Context, before time.Time, maxPerToken int) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) ListRawWSEventTimes(ctx context.Context, tokenID string, since, until time.Time) ([]time.Time, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListRawWSEventTokenIDs(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	return nil, nil
+}
+func (s *stubRepo) GetEarliestPriceCandle(ctx context.Context, tokenID string) (*models.PriceCandle, error) {
+	return nil, nil
+}
+func (s *stubRepo) GetEarliestRawWSEvent(ctx context.Context, tokenID string, eventType string) (*models.RawWSEvent, error) {
+	return nil, nil
+}
+func (s *stubRepo) GetEarliestRawRESTSnapshot(ctx context.Context, tokenID string, snapshotType string) (*models.RawRESTSnapshot, error) {
+	return nil, nil
+}
 func (s *stubRepo) FindMarketsByConditionIDs(ctx context.Context, conditionIDs []string) ([]models.Market, error) {
 	return nil, nil
 }
@@ -92,6 +129,18 @@ func (s *stubRepo) ListMarketsByIDs(ctx context.Context, marketIDs []string) ([]
 func (s *stubRepo) ListMarketIDsForStream(ctx context.Context, limit int) ([]string, error) {
 	return nil, nil
 }
+func (s *stubRepo) ListMarketBaselines(ctx context.Context, marketIDs []string) ([]models.MarketBaseline, error) {
+	return nil, nil
+}
+func (s *stubRepo) UpsertMarketBaselines(ctx context.Context, items []models.MarketBaseline) error {
+	return nil
+}
+func (s *stubRepo) ListMarketsForOnchainCheck(ctx context.Context, limit int) ([]models.Market, error) {
+	return nil, nil
+}
+func (s *stubRepo) SaveOnchainCondition(ctx context.Context, marketID string, state repository.OnchainConditionState) error {
+	return nil
+}
 func (s *stubRepo) ListTokensByMarketIDs(ctx context.Context, marketIDs []string) ([]models.Token, error) {
 	out := make([]models.Token, 0, 2*len(marketIDs))
 	for _, mid := range marketIDs {
@@ -120,6 +169,9 @@ func (s *stubRepo) ListOrderbookLatestByTokenIDs(ctx context.Context, tokenIDs [
 	}
 	return out, nil
 }
+func (s *stubRepo) ListCatalogBooks(ctx context.Context, tokenIDs []string) ([]repository.CatalogBook, error) {
+	return nil, nil
+}
 func (s *stubRepo) ListLastTradePricesByTokenIDs(ctx context.Context, tokenIDs []string) ([]models.LastTradePrice, error) {
 	out := make([]models.LastTradePrice, 0, len(tokenIDs))
 	for _, id := range tokenIDs {
@@ -163,6 +215,42 @@ func (s *stubRepo) ListSyncStates(ctx context.Context) ([]models.SyncState, erro
 func (s *stubRepo) ListActiveEventsEndingSoon(ctx context.Context, hoursToExpiry int, limit int) ([]models.Event, error) {
 	return nil, nil
 }
+func (s *stubRepo) InsertMarketChangesTx(ctx context.Context, tx *gorm.DB, items []models.MarketChange) error {
+	return nil
+}
+func (s *stubRepo) ListMarketChanges(ctx context.Context, params repository.ListMarketChangesParams) ([]models.MarketChange, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountMarketChanges(ctx context.Context, params repository.ListMarketChangesParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) ListHeldMarketIDs(ctx context.Context, marketIDs []string) ([]string, error) {
+	return nil, nil
+}
+func (s *stubRepo) InsertCatalogChangesTx(ctx context.Context, tx *gorm.DB, items []models.CatalogChange) error {
+	return nil
+}
+func (s *stubRepo) ListCatalogChanges(ctx context.Context, params repository.ListCatalogChangesParams) ([]models.CatalogChange, error) {
+	return nil, nil
+}
+func (s *stubRepo) UpsertCatalogQuarantineTx(ctx context.Context, tx *gorm.DB, items []models.CatalogQuarantine) error {
+	return nil
+}
+func (s *stubRepo) ReleaseCatalogQuarantineTx(ctx context.Context, tx *gorm.DB, entityType string, entityIDs []string, at time.Time) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) ReleaseCatalogQuarantineByIDsTx(ctx context.Context, tx *gorm.DB, ids []uint64, at time.Time) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) ListCatalogQuarantine(ctx context.Context, params repository.ListCatalogQuarantineParams) ([]models.CatalogQuarantine, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountCatalogQuarantine(ctx context.Context, params repository.ListCatalogQuarantineParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) CatalogQuarantineMetrics(ctx context.Context) ([]repository.CatalogQuarantineMetricRow, error) {
+	return nil, nil
+}
 
 func (s *stubRepo) InsertSignal(ctx context.Context, item *models.Signal) error { return nil }
 func (s *stubRepo) ListSignals(ctx context.Context, params repository.ListSignalsParams) ([]models.Signal, error) {
@@ -183,6 +271,9 @@ func (s *stubRepo) ListMarketDataHealthCandidates(ctx context.Context, limit int
 func (s *stubRepo) ListYesTokenJumpCandidates(ctx context.Context, limit int, minJumpBps float64, maxSpreadBps float64) ([]repository.TokenJumpCandidate, error) {
 	return nil, nil
 }
+func (s *stubRepo) MarketActivity(ctx context.Context, since time.Time, minJumpBps float64) (repository.MarketActivity, error) {
+	return repository.MarketActivity{}, nil
+}
 func (s *stubRepo) ListTagsByEventIDs(ctx context.Context, eventIDs []string) (map[string][]models.Tag, error) {
 	return map[string][]models.Tag{}, nil
 }
@@ -201,6 +292,433 @@ func (s *stubRepo) UpdateStrategyParams(ctx context.Context, name string, params
 func (s *stubRepo) UpdateStrategyStats(ctx context.Context, name string, stats []byte) error {
 	return nil
 }
+func (s *stubRepo) SetStrategyTenant(ctx context.Context, name string, tenant string) error {
+	return nil
+}
+func (s *stubRepo) SetStrategyLaunchStage(ctx context.Context, name string, stage string) error {
+	return nil
+}
+func (s *stubRepo) ListWalletPositions(ctx context.Context, wallet string) ([]models.WalletPosition, error) {
+	return nil, nil
+}
+func (s *stubRepo) UpsertWalletPosition(ctx context.Context, item *models.WalletPosition) error {
+	return nil
+}
+func (s *stubRepo) DeleteWalletPosition(ctx context.Context, wallet string, tokenID string) error {
+	return nil
+}
+func (s *stubRepo) InsertWalletPositionChange(ctx context.Context, item *models.WalletPositionChange) error {
+	return nil
+}
+func (s *stubRepo) ListWalletPositionChanges(ctx context.Context, params repository.ListWalletPositionChangesParams) ([]models.WalletPositionChange, error) {
+	return nil, nil
+}
+func (s *stubRepo) InsertMarketDataGap(ctx context.Context, item *models.MarketDataGap) (bool, error) {
+	return false, nil
+}
+func (s *stubRepo) UpdateMarketDataGap(ctx context.Context, item *models.MarketDataGap) error {
+	return nil
+}
+func (s *stubRepo) ListMarketDataGaps(ctx context.Context, params repository.ListMarketDataGapsParams) ([]models.MarketDataGap, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountMarketDataGaps(ctx context.Context, params repository.ListMarketDataGapsParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) CountWalletPositionChanges(ctx context.Context, params repository.ListWalletPositionChangesParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) AppendAuditRecord(ctx context.Context, item *models.AuditRecord, seal func(prev *models.AuditRecord, item *models.AuditRecord)) error {
+	return nil
+}
+func (s *stubRepo) ListAuditRecords(ctx context.Context, params repository.ListAuditRecordsParams) ([]models.AuditRecord, error) {
+	return nil, nil
+}
+func (s *stubRepo) InsertEvaluationRun(ctx context.Context, item *models.EvaluationRun) error {
+	return nil
+}
+func (s *stubRepo) ListEvaluationRuns(ctx context.Context, params repository.ListEvaluationRunsParams) ([]models.EvaluationRun, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountEvaluationRuns(ctx context.Context, params repository.ListEvaluationRunsParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) ReserveIdempotencyKey(ctx context.Context, item *models.IdempotencyKey) (*models.IdempotencyKey, bool, error) {
+	return item, true, nil
+}
+func (s *stubRepo) CompleteIdempotencyKey(ctx context.Context, id uint64, status int, contentType string, body string, expiresAt time.Time) error {
+	return nil
+}
+func (s *stubRepo) RenewIdempotencyKey(ctx context.Context, id uint64, expiresAt time.Time) error {
+	return nil
+}
+func (s *stubRepo) DeleteIdempotencyKey(ctx context.Context, id uint64) error { return nil }
+func (s *stubRepo) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) InsertComplianceOverride(ctx context.Context, item *models.ComplianceOverride) error {
+	return nil
+}
+func (s *stubRepo) GetComplianceOverrideByID(ctx context.Context, id uint64) (*models.ComplianceOverride, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListComplianceOverrides(ctx context.Context, params repository.ListComplianceOverridesParams) ([]models.ComplianceOverride, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountComplianceOverrides(ctx context.Context, params repository.ListComplianceOverridesParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) ListActiveComplianceOverrides(ctx context.Context, marketIDs []string, now time.Time) ([]models.ComplianceOverride, error) {
+	return nil, nil
+}
+func (s *stubRepo) RevokeComplianceOverride(ctx context.Context, id uint64, reason string, at time.Time) error {
+	return nil
+}
+func (s *stubRepo) InsertCampaign(ctx context.Context, item *models.Campaign) error { return nil }
+func (s *stubRepo) UpdateCampaign(ctx context.Context, item *models.Campaign) error { return nil }
+func (s *stubRepo) GetCampaignByID(ctx context.Context, id uint64) (*models.Campaign, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListCampaigns(ctx context.Context, params repository.ListCampaignsParams) ([]models.Campaign, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountCampaigns(ctx context.Context, params repository.ListCampaignsParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) AssignPlansToCampaign(ctx context.Context, campaignID *uint64, planIDs []uint64) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) AssignOpportunitiesToCampaign(ctx context.Context, campaignID *uint64, opportunityIDs []uint64) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) CampaignStats(ctx context.Context, campaignID uint64) (repository.CampaignStats, error) {
+	return repository.CampaignStats{}, nil
+}
+func (s *stubRepo) InsertTradeTicket(ctx context.Context, item *models.TradeTicket) error { return nil }
+func (s *stubRepo) UpdateTradeTicket(ctx context.Context, item *models.TradeTicket) error { return nil }
+func (s *stubRepo) GetTradeTicketByID(ctx context.Context, id uint64) (*models.TradeTicket, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListTradeTickets(ctx context.Context, params repository.ListTradeTicketsParams) ([]models.TradeTicket, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountTradeTickets(ctx context.Context, params repository.ListTradeTicketsParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) InsertResearchSnapshot(ctx context.Context, item *models.ResearchSnapshot) error {
+	return nil
+}
+func (s *stubRepo) GetResearchSnapshotByID(ctx context.Context, id uint64) (*models.ResearchSnapshot, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListResearchSnapshots(ctx context.Context, params repository.ListResearchSnapshotsParams) ([]models.ResearchSnapshot, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountResearchSnapshots(ctx context.Context, params repository.ListResearchSnapshotsParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) CountResearchSnapshotsByIDs(ctx context.Context, ids []uint64, tenant *string) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) InsertAnalyticsExport(ctx context.Context, item *models.AnalyticsExport) error {
+	return nil
+}
+func (s *stubRepo) GetAnalyticsExportByID(ctx context.Context, id uint64) (*models.AnalyticsExport, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListAnalyticsExports(ctx context.Context, params repository.ListAnalyticsExportsParams) ([]models.AnalyticsExport, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountAnalyticsExports(ctx context.Context, params repository.ListAnalyticsExportsParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) DeleteAnalyticsExport(ctx context.Context, id uint64) error {
+	return nil
+}
+func (s *stubRepo) RecordAnalyticsExportRun(ctx context.Context, id uint64, watermark string, at time.Time) error {
+	return nil
+}
+func (s *stubRepo) ListSettledTrades(ctx context.Context, params repository.SettledTradeParams) ([]repository.SettledTradeRow, error) {
+	return nil, nil
+}
+func (s *stubRepo) InsertCashOperation(ctx context.Context, item *models.CashOperation) error {
+	return nil
+}
+func (s *stubRepo) UpdateCashOperation(ctx context.Context, item *models.CashOperation) error {
+	return nil
+}
+func (s *stubRepo) GetCashOperationByID(ctx context.Context, id uint64) (*models.CashOperation, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListCashOperations(ctx context.Context, params repository.ListCashOperationsParams) ([]models.CashOperation, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountCashOperations(ctx context.Context, params repository.ListCashOperationsParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) CashBalance(ctx context.Context, tenant *string) (repository.CashBalance, error) {
+	return repository.CashBalance{}, nil
+}
+
+func (s *stubRepo) RetentionBacklog(ctx context.Context, table string, before time.Time) (repository.RetentionBacklog, error) {
+	return repository.RetentionBacklog{}, nil
+}
+
+func (s *stubRepo) PurgeRetention(ctx context.Context, table string, before time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) InsertCostForecast(ctx context.Context, item *models.CostForecast) error {
+	return nil
+}
+func (s *stubRepo) GetCostForecastByPlanID(ctx context.Context, planID uint64) (*models.CostForecast, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListCostForecastOutcomes(ctx context.Context, params repository.CostForecastOutcomeParams) ([]repository.CostForecastOutcomeRow, error) {
+	return nil, nil
+}
+func (s *stubRepo) UpdateExecutionPlanTrigger(ctx context.Context, item *models.ExecutionPlan) error {
+	return nil
+}
+func (s *stubRepo) ListDuePlanTriggers(ctx context.Context, now time.Time, limit int) ([]models.ExecutionPlan, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListRecordedExternalTradeIDs(ctx context.Context, ids []string) ([]string, error) {
+	return nil, nil
+}
+func (s *stubRepo) InsertRiskDecisions(ctx context.Context, items []models.RiskDecision) error {
+	return nil
+}
+func (s *stubRepo) ListRiskDecisions(ctx context.Context, params repository.ListRiskDecisionsParams) ([]models.RiskDecision, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountRiskDecisions(ctx context.Context, params repository.ListRiskDecisionsParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) ListCategoryTokens(ctx context.Context, category string, limit int) ([]models.Token, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountCategorySettlements(ctx context.Context, category string, since time.Time) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) InsertMarketRegimes(ctx context.Context, items []models.MarketRegime) error {
+	return nil
+}
+func (s *stubRepo) ListLatestMarketRegimes(ctx context.Context) ([]models.MarketRegime, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListMarketRegimes(ctx context.Context, params repository.ListMarketRegimesParams) ([]models.MarketRegime, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountMarketRegimes(ctx context.Context, params repository.ListMarketRegimesParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) InsertSLOSamples(ctx context.Context, items []models.SLOSample) error {
+	return nil
+}
+func (s *stubRepo) SumSLOSamples(ctx context.Context, slo string, since time.Time) (int64, int64, error) {
+	return 0, 0, nil
+}
+func (s *stubRepo) ListSLOSamples(ctx context.Context, params repository.ListSLOSamplesParams) ([]models.SLOSample, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountSLOSamples(ctx context.Context, params repository.ListSLOSamplesParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) CountClosedMarketSettlements(ctx context.Context, from, to time.Time) (int64, int64, error) {
+	return 0, 0, nil
+}
+func (s *stubRepo) InsertOutboxMessagesTx(ctx context.Context, tx *gorm.DB, items []models.OutboxMessage) error {
+	return nil
+}
+func (s *stubRepo) ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
+	return nil, nil
+}
+func (s *stubRepo) MarkOutboxMessageDelivered(ctx context.Context, id uint64, at time.Time) error {
+	return nil
+}
+func (s *stubRepo) MarkOutboxMessageFailed(ctx context.Context, id uint64, errMsg string, retryAt *time.Time) error {
+	return nil
+}
+func (s *stubRepo) RetryOutboxMessage(ctx context.Context, id uint64, now time.Time) (bool, error) {
+	return false, nil
+}
+func (s *stubRepo) ListOutboxMessages(ctx context.Context, params repository.ListOutboxMessagesParams) ([]models.OutboxMessage, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountOutboxMessages(ctx context.Context, params repository.ListOutboxMessagesParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) CountOutboxMessagesByStatus(ctx context.Context) (map[string]int64, error) {
+	return nil, nil
+}
+func (s *stubRepo) DeleteDeliveredOutboxMessages(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) InsertStrategyChange(ctx context.Context, item *models.StrategyChange) error {
+	return nil
+}
+func (s *stubRepo) GetStrategyChange(ctx context.Context, id uint64) (*models.StrategyChange, error) {
+	return nil, nil
+}
+func (s *stubRepo) GetLatestStrategyChange(ctx context.Context, strategyName string) (*models.StrategyChange, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListActiveStrategyChanges(ctx context.Context, now time.Time) ([]models.StrategyChange, error) {
+	return nil, nil
+}
+func (s *stubRepo) StrategyChangeCohorts(ctx context.Context, changeID uint64) ([]repository.CohortOutcome, error) {
+	return nil, nil
+}
+func (s *stubRepo) InsertTradeWebhook(ctx context.Context, item *models.TradeWebhook) error {
+	return nil
+}
+func (s *stubRepo) UpdateTradeWebhook(ctx context.Context, item *models.TradeWebhook) error {
+	return nil
+}
+func (s *stubRepo) GetTradeWebhookByID(ctx context.Context, id uint64) (*models.TradeWebhook, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListTradeWebhooks(ctx context.Context, params repository.ListTradeWebhooksParams) ([]models.TradeWebhook, error) {
+	return nil, nil
+}
+func (s *stubRepo) DeleteTradeWebhook(ctx context.Context, id uint64) error { return nil }
+func (s *stubRepo) InsertTradeWebhookDeliveryTx(ctx context.Context, tx *gorm.DB, item *models.TradeWebhookDelivery) (bool, error) {
+	return false, nil
+}
+func (s *stubRepo) GetTradeWebhookDelivery(ctx context.Context, id uint64) (*models.TradeWebhookDelivery, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListTradeWebhookDeliveries(ctx context.Context, params repository.ListTradeWebhookDeliveriesParams) ([]models.TradeWebhookDelivery, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountTradeWebhookDeliveries(ctx context.Context, params repository.ListTradeWebhookDeliveriesParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) RecordTradeWebhookAttempt(ctx context.Context, deliveryID uint64, status int, errMsg string, at time.Time) error {
+	return nil
+}
+func (s *stubRepo) GetFillByID(ctx context.Context, id uint64) (*models.Fill, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountFillsBetween(ctx context.Context, since, until time.Time) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) InsertPortfolioAnomaly(ctx context.Context, item *models.PortfolioAnomaly) (bool, error) {
+	return false, nil
+}
+func (s *stubRepo) ListPortfolioAnomalies(ctx context.Context, params repository.ListPortfolioAnomaliesParams) ([]models.PortfolioAnomaly, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountPortfolioAnomalies(ctx context.Context, params repository.ListPortfolioAnomaliesParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) InsertExecutorIntent(ctx context.Context, item *models.ExecutorIntent) error {
+	return nil
+}
+func (s *stubRepo) ResolveExecutorIntent(ctx context.Context, id uint64, status string, updates map[string]any) error {
+	return nil
+}
+func (s *stubRepo) ListExecutorIntents(ctx context.Context, params repository.ListExecutorIntentsParams) ([]models.ExecutorIntent, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountExecutorIntents(ctx context.Context, params repository.ListExecutorIntentsParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) CountExecutorIntentsByStatus(ctx context.Context) (map[string]int64, error) {
+	return nil, nil
+}
+func (s *stubRepo) InsertStrategyCapacity(ctx context.Context, item *models.StrategyCapacity) error {
+	return nil
+}
+func (s *stubRepo) GetLatestStrategyCapacity(ctx context.Context, strategyName string) (*models.StrategyCapacity, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListStrategyCapacities(ctx context.Context, strategyName string, limit int) ([]models.StrategyCapacity, error) {
+	return nil, nil
+}
+func (s *stubRepo) GetStrategyExpectation(ctx context.Context, strategyName string) (*models.StrategyExpectation, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListStrategyExpectations(ctx context.Context) ([]models.StrategyExpectation, error) {
+	return nil, nil
+}
+func (s *stubRepo) SaveStrategyExpectation(ctx context.Context, item *models.StrategyExpectation) error {
+	return nil
+}
+func (s *stubRepo) DeleteStrategyExpectation(ctx context.Context, strategyName string) error {
+	return nil
+}
+func (s *stubRepo) InsertStrategyDriftReport(ctx context.Context, item *models.StrategyDriftReport) error {
+	return nil
+}
+func (s *stubRepo) GetLatestStrategyDriftReport(ctx context.Context, strategyName string) (*models.StrategyDriftReport, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListStrategyDriftReports(ctx context.Context, strategyName string, limit int) ([]models.StrategyDriftReport, error) {
+	return nil, nil
+}
+func (s *stubRepo) InsertRuleTuning(ctx context.Context, item *models.RuleTuning) error {
+	return nil
+}
+func (s *stubRepo) UpdateRuleTuning(ctx context.Context, item *models.RuleTuning) error {
+	return nil
+}
+func (s *stubRepo) GetRuleTuning(ctx context.Context, id uint64) (*models.RuleTuning, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListRuleTunings(ctx context.Context, strategyName string, limit int) ([]models.RuleTuning, error) {
+	return nil, nil
+}
+func (s *stubRepo) InsertStrategyParamTuning(ctx context.Context, item *models.StrategyParamTuning) error {
+	return nil
+}
+func (s *stubRepo) UpdateStrategyParamTuning(ctx context.Context, item *models.StrategyParamTuning) error {
+	return nil
+}
+func (s *stubRepo) GetStrategyParamTuning(ctx context.Context, id uint64) (*models.StrategyParamTuning, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListStrategyParamTunings(ctx context.Context, strategyName string, limit int) ([]models.StrategyParamTuning, error) {
+	return nil, nil
+}
+func (s *stubRepo) GetStrategyBudget(ctx context.Context, strategyName string) (*models.StrategyBudget, error) {
+	return nil, nil
+}
+func (s *stubRepo) SaveStrategyBudget(ctx context.Context, item *models.StrategyBudget) error {
+	return nil
+}
+func (s *stubRepo) StrategyTurnover(ctx context.Context, strategyName string, since time.Time) (repository.StrategyTurnover, error) {
+	return repository.StrategyTurnover{}, nil
+}
+func (s *stubRepo) GetRewardEpoch(ctx context.Context, epoch, marketID string) (*models.RewardEpoch, error) {
+	return nil, nil
+}
+func (s *stubRepo) SaveRewardEpoch(ctx context.Context, item *models.RewardEpoch) error { return nil }
+func (s *stubRepo) ListRewardEpochs(ctx context.Context, params repository.ListRewardEpochsParams) ([]models.RewardEpoch, error) {
+	return nil, nil
+}
+func (s *stubRepo) CountRewardEpochs(ctx context.Context, params repository.ListRewardEpochsParams) (int64, error) {
+	return 0, nil
+}
+func (s *stubRepo) InsertCatalogWebhook(ctx context.Context, item *models.CatalogWebhook) error {
+	return nil
+}
+func (s *stubRepo) UpdateCatalogWebhook(ctx context.Context, item *models.CatalogWebhook) error {
+	return nil
+}
+func (s *stubRepo) GetCatalogWebhookByID(ctx context.Context, id uint64) (*models.CatalogWebhook, error) {
+	return nil, nil
+}
+func (s *stubRepo) ListCatalogWebhooks(ctx context.Context, params repository.ListCatalogWebhooksParams) ([]models.CatalogWebhook, error) {
+	return nil, nil
+}
+func (s *stubRepo) DeleteCatalogWebhook(ctx context.Context, id uint64) error { return nil }
+func (s *stubRepo) RecordCatalogWebhookDelivery(ctx context.Context, id uint64, status int, errMsg string, at time.Time) error {
+	return nil
+}
+func (s *stubRepo) DeleteEvaluationRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
 
 func (s *stubRepo) InsertOpportunity(ctx context.Context, item *models.Opportunity) error { return nil }
 func (s *stubRepo) UpsertActiveOpportunity(ctx context.Context, item *models.Opportunity) error {
@@ -218,9 +736,15 @@ func (s *stubRepo) CountOpportunities(ctx context.Context, params repository.Lis
 func (s *stubRepo) UpdateOpportunityStatus(ctx context.Context, id uint64, status string) error {
 	return nil
 }
+func (s *stubRepo) UpdateOpportunityStatusTx(ctx context.Context, tx *gorm.DB, id uint64, status string) error {
+	return nil
+}
 func (s *stubRepo) ExpireDueOpportunities(ctx context.Context, now time.Time) (int64, error) {
 	return 0, nil
 }
+func (s *stubRepo) UpdateOpportunityDecay(ctx context.Context, id uint64, decay repository.OpportunityDecay) error {
+	return nil
+}
 func (s *stubRepo) CountActiveOpportunities(ctx context.Context) (int64, error) { return 0, nil }
 func (s *stubRepo) ListOldestActiveOpportunityIDs(ctx context.Context, limit int) ([]uint64, error) {
 	return nil, nil
@@ -230,6 +754,9 @@ func (s *stubRepo) BulkUpdateOpportunityStatus(ctx context.Context, ids []uint64
 }
 
 func (s *stubRepo) UpsertMarketLabel(ctx context.Context, item *models.MarketLabel) error { return nil }
+func (s *stubRepo) ListMarketLabelsByMarketIDs(ctx context.Context, marketIDs []string) ([]models.MarketLabel, error) {
+	return nil, nil
+}
 func (s *stubRepo) ListMarketLabels(ctx context.Context, params repository.ListMarketLabelsParams) ([]models.MarketLabel, error) {
 	limit := params.Limit
 	if limit <= 0 {
@@ -262,6 +789,9 @@ func (s *stubRepo) DeleteMarketLabel(ctx context.Context, marketID string, label
 func (s *stubRepo) InsertExecutionPlan(ctx context.Context, item *models.ExecutionPlan) error {
 	return nil
 }
+func (s *stubRepo) InsertExecutionPlanTx(ctx context.Context, tx *gorm.DB, item *models.ExecutionPlan) error {
+	return nil
+}
 func (s *stubRepo) GetExecutionPlanByID(ctx context.Context, id uint64) (*models.ExecutionPlan, error) {
 	return nil, nil
 }
@@ -277,16 +807,31 @@ func (s *stubRepo) ListExecutionPlansByStatuses(ctx context.Context, statuses []
 func (s *stubRepo) UpdateExecutionPlanStatus(ctx context.Context, id uint64, status string) error {
 	return nil
 }
-func (s *stubRepo) UpdateExecutionPlanPreflight(ctx context.Context, id uint64, status string, preflightResult []byte) error {
+func (s *stubRepo) UpdateExecutionPlanStatusTx(ctx context.Context, tx *gorm.DB, id uint64, status string) error {
+	return nil
+}
+func (s *stubRepo) UpdateExecutionPlanSizing(ctx context.Context, id uint64, plannedSizeUSD, maxLossUSD decimal.Decimal, legs []byte) error {
+	return nil
+}
+func (s *stubRepo) UpdateExecutionPlanPreflight(ctx context.Context, id uint64, status string, preflightResult []byte, riskReport []byte) error {
 	return nil
 }
 func (s *stubRepo) UpdateExecutionPlanExecutedAt(ctx context.Context, id uint64, status string, executedAt *time.Time) error {
 	return nil
 }
+func (s *stubRepo) UpdateExecutionPlanExecutedAtTx(ctx context.Context, tx *gorm.DB, id uint64, status string, executedAt *time.Time) error {
+	return nil
+}
 func (s *stubRepo) CountExecutionPlansByStrategySince(ctx context.Context, strategyName string, since time.Time) (int64, error) {
 	return 0, nil
 }
+func (s *stubRepo) CountExecutionPlansSince(ctx context.Context, since time.Time, autoExecuted *bool) (int64, error) {
+	return 0, nil
+}
 func (s *stubRepo) InsertFill(ctx context.Context, item *models.Fill) error { return nil }
+func (s *stubRepo) ListFillsChronological(ctx context.Context, tokenID string) ([]models.Fill, error) {
+	return nil, nil
+}
 func (s *stubRepo) ListFillsByPlanID(ctx context.Context, planID uint64) ([]models.Fill, error) {
 	return nil, nil
 }
@@ -294,9 +839,15 @@ func (s *stubRepo) UpsertPnLRecord(ctx context.Context, item *models.PnLRecord)
 func (s *stubRepo) GetPnLRecordByPlanID(ctx context.Context, planID uint64) (*models.PnLRecord, error) {
 	return nil, nil
 }
+func (s *stubRepo) UpsertPnLRecordTx(ctx context.Context, tx *gorm.DB, item *models.PnLRecord) error {
+	return nil
+}
 func (s *stubRepo) SumRealizedPnLSince(ctx context.Context, since time.Time) (decimal.Decimal, error) {
 	return decimal.Zero, nil
 }
+func (s *stubRepo) SumRealizedPnLSinceForTenant(ctx context.Context, since time.Time, tenant string) (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
 func (s *stubRepo) UpsertExecutionRule(ctx context.Context, item *models.ExecutionRule) error {
 	return nil
 }
@@ -306,6 +857,9 @@ func (s *stubRepo) GetExecutionRuleByStrategyName(ctx context.Context, strategyN
 func (s *stubRepo) ListExecutionRules(ctx context.Context) ([]models.ExecutionRule, error) {
 	return nil, nil
 }
+func (s *stubRepo) OpportunityConversionByStrategy(ctx context.Context, since time.Time, tenant string) ([]repository.StrategyConversion, error) {
+	return nil, nil
+}
 func (s *stubRepo) DeleteExecutionRuleByStrategyName(ctx context.Context, strategyName string) error {
 	return nil
 }
@@ -319,6 +873,9 @@ func (s *stubRepo) GetTradeJournalByPlanID(ctx context.Context, planID uint64) (
 func (s *stubRepo) UpdateTradeJournalExit(ctx context.Context, planID uint64, updates map[string]any) error {
 	return nil
 }
+func (s *stubRepo) UpdateTradeJournalSnapshots(ctx context.Context, planID uint64, snapshotIDs []byte) error {
+	return nil
+}
 func (s *stubRepo) UpdateTradeJournalNotes(ctx context.Context, planID uint64, notes string, tags []byte, reviewedAt *time.Time) error {
 	return nil
 }
@@ -331,9 +888,15 @@ func (s *stubRepo) CountTradeJournals(ctx context.Context, params repository.Lis
 func (s *stubRepo) UpsertSystemSetting(ctx context.Context, item *models.SystemSetting) error {
 	return nil
 }
+func (s *stubRepo) UpsertSystemSettings(ctx context.Context, items []models.SystemSetting) error {
+	return nil
+}
 func (s *stubRepo) GetSystemSettingByKey(ctx context.Context, key string) (*models.SystemSetting, error) {
 	return nil, nil
 }
+func (s *stubRepo) GetTenantSystemSetting(ctx context.Context, tenant, key string) (*models.SystemSetting, error) {
+	return nil, nil
+}
 func (s *stubRepo) ListSystemSettings(ctx context.Context, params repository.ListSystemSettingsParams) ([]models.SystemSetting, error) {
 	return nil, nil
 }
@@ -344,7 +907,7 @@ func (s *stubRepo) UpsertPosition(ctx context.Context, item *models.Position) er
 func (s *stubRepo) GetPositionByID(ctx context.Context, id uint64) (*models.Position, error) {
 	return nil, nil
 }
-func (s *stubRepo) GetPositionByTokenID(ctx context.Context, tokenID string) (*models.Position, error) {
+func (s *stubRepo) GetPositionByTokenID(ctx context.Context, tenant, tokenID string) (*models.Position, error) {
 	return nil, nil
 }
 func (s *stubRepo) ListPositions(ctx context.Context, params repository.ListPositionsParams) ([]models.Position, error) {
@@ -376,28 +939,32 @@ func (s *stubRepo) ListOrders(ctx context.Context, params repository.ListOrdersP
 func (s *stubRepo) CountOrders(ctx context.Context, params repository.ListOrdersParams) (int64, error) {
 	return 0, nil
 }
+func (s *stubRepo) CountOpenOrders(ctx context.Context) (int64, error) { return 0, nil }
 func (s *stubRepo) UpdateOrderStatus(ctx context.Context, id uint64, status string, updates map[string]any) error {
 	return nil
 }
+func (s *stubRepo) AddOrderImprovementUSD(ctx context.Context, id uint64, usd decimal.Decimal) error {
+	return nil
+}
 func (s *stubRepo) UpsertStrategyDailyStats(ctx context.Context, item *models.StrategyDailyStats) error {
 	return nil
 }
 func (s *stubRepo) ListStrategyDailyStats(ctx context.Context, params repository.ListDailyStatsParams) ([]models.StrategyDailyStats, error) {
 	return nil, nil
 }
-func (s *stubRepo) AttributionByStrategy(ctx context.Context, strategyName string, since, until *time.Time) (repository.AttributionResult, error) {
+func (s *stubRepo) AttributionByStrategy(ctx context.Context, strategyName string, since, until, asOf *time.Time) (repository.AttributionResult, error) {
 	return repository.AttributionResult{}, nil
 }
-func (s *stubRepo) PortfolioDrawdown(ctx context.Context) (repository.DrawdownResult, error) {
+func (s *stubRepo) PortfolioDrawdown(ctx context.Context, tenant string) (repository.DrawdownResult, error) {
 	return repository.DrawdownResult{}, nil
 }
-func (s *stubRepo) StrategyCorrelation(ctx context.Context, since, until *time.Time) ([]repository.CorrelationRow, error) {
+func (s *stubRepo) StrategyCorrelation(ctx context.Context, since, until *time.Time, tenant string) ([]repository.CorrelationRow, error) {
 	return nil, nil
 }
-func (s *stubRepo) PerformanceRatios(ctx context.Context, since, until *time.Time) (repository.RatiosResult, error) {
+func (s *stubRepo) PerformanceRatios(ctx context.Context, since, until, asOf *time.Time, tenant string) (repository.RatiosResult, error) {
 	return repository.RatiosResult{}, nil
 }
-func (s *stubRepo) RebuildStrategyDailyStats(ctx context.Context, since, until *time.Time) (int, error) {
+func (s *stubRepo) RebuildStrategyDailyStats(ctx context.Context, since, until *time.Time, cal *tradingday.Calendar) (int, error) {
 	return 0, nil
 }
 
@@ -413,6 +980,9 @@ func (s *stubRepo) ListRecentMarketSettlementHistory(ctx context.Context, since
 func (s *stubRepo) ListLabelNoRateStats(ctx context.Context, labels []string) ([]repository.LabelNoRateRow, error) {
 	return nil, nil
 }
+func (s *stubRepo) ListCalibrationSamples(ctx context.Context, since, until *time.Time, limit int) ([]repository.CalibrationSampleRow, error) {
+	return nil, nil
+}
 func (s *stubRepo) UpsertMarketReview(ctx context.Context, item *models.MarketReview) error {
 	return nil
 }
@@ -435,16 +1005,31 @@ func (s *stubRepo) UpdateMarketReviewNotes(ctx context.Context, id uint64, notes
 	return nil
 }
 
-func (s *stubRepo) AnalyticsOverview(ctx context.Context) (repository.AnalyticsOverview, error) {
+func (s *stubRepo) AnalyticsOverview(ctx context.Context, asOf *time.Time, tenant string) (repository.AnalyticsOverview, error) {
 	return repository.AnalyticsOverview{}, nil
 }
-func (s *stubRepo) AnalyticsByStrategy(ctx context.Context) ([]repository.StrategyAnalyticsRow, error) {
+func (s *stubRepo) SignalQualityStats(ctx context.Context, since *time.Time) ([]repository.SignalQualityRow, error) {
+	return nil, nil
+}
+func (s *stubRepo) AnalyticsByStrategy(ctx context.Context, tenant string) ([]repository.StrategyAnalyticsRow, error) {
 	return nil, nil
 }
 func (s *stubRepo) AnalyticsStrategyOutcomes(ctx context.Context) ([]repository.StrategyOutcomeRow, error) {
 	return nil, nil
 }
-func (s *stubRepo) AnalyticsFailures(ctx context.Context) ([]repository.FailureAnalyticsRow, error) {
+func (s *stubRepo) AnalyticsFailures(ctx context.Context, tenant string) ([]repository.FailureAnalyticsRow, error) {
+	return nil, nil
+}
+func (s *stubRepo) PnLBreakdownByLabel(ctx context.Context, params repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+	return nil, nil
+}
+func (s *stubRepo) PnLBreakdownByEntryHour(ctx context.Context, params repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+	return nil, nil
+}
+func (s *stubRepo) PnLBreakdownByHoldDuration(ctx context.Context, params repository.PnLBreakdownParams) ([]repository.PnLBreakdownRow, error) {
+	return nil, nil
+}
+func (s *stubRepo) EdgeRealizationHeatmap(ctx context.Context, params repository.EdgeHeatmapParams) ([]repository.EdgeHeatmapCell, error) {
 	return nil, nil
 }
 
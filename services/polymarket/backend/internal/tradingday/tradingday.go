@@ -0,0 +1,74 @@
+// Package tradingday maps instants to trading days. Daily stats, daily loss
+// limits and daily trade caps all bucket by the same Calendar so a trading
+// evening is not split across two days.
+package tradingday
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"polymarket/internal/config"
+)
+
+// Calendar starts each trading day at BoundaryHour local time in its
+// location. A day is labelled with the local date it starts on. The nil
+// Calendar is UTC midnight.
+type Calendar struct {
+	loc      *time.Location
+	boundary int
+}
+
+// New builds a Calendar from config. An empty timezone is UTC.
+func New(cfg config.TradingDayConfig) (*Calendar, error) {
+	name := strings.TrimSpace(cfg.Timezone)
+	if name == "" {
+		name = "UTC"
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("trading_day.timezone: %w", err)
+	}
+	if cfg.BoundaryHour < 0 || cfg.BoundaryHour > 23 {
+		return nil, fmt.Errorf("trading_day.boundary_hour must be 0-23, got %d", cfg.BoundaryHour)
+	}
+	return &Calendar{loc: loc, boundary: cfg.BoundaryHour}, nil
+}
+
+// Start returns the start of the trading day containing t, in UTC.
+func (c *Calendar) Start(t time.Time) time.Time {
+	loc, boundary := c.parts()
+	local := t.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), boundary, 0, 0, 0, loc)
+	if local.Before(start) {
+		start = time.Date(local.Year(), local.Month(), local.Day()-1, boundary, 0, 0, 0, loc)
+	}
+	return start.UTC()
+}
+
+// Date returns the label of the trading day containing t as midnight UTC of
+// that date, the form stored in date columns.
+func (c *Calendar) Date(t time.Time) time.Time {
+	loc, _ := c.parts()
+	local := c.Start(t).In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DateStart returns the start, in UTC, of the trading day labelled date.
+func (c *Calendar) DateStart(date time.Time) time.Time {
+	loc, boundary := c.parts()
+	return time.Date(date.Year(), date.Month(), date.Day(), boundary, 0, 0, 0, loc).UTC()
+}
+
+// String identifies the boundary, e.g. "America/New_York@17:00".
+func (c *Calendar) String() string {
+	loc, boundary := c.parts()
+	return fmt.Sprintf("%s@%02d:00", loc, boundary)
+}
+
+func (c *Calendar) parts() (*time.Location, int) {
+	if c == nil || c.loc == nil {
+		return time.UTC, 0
+	}
+	return c.loc, c.boundary
+}
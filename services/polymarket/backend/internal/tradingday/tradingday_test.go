@@ -0,0 +1,50 @@
+package tradingday
+
+import (
+	"testing"
+	"time"
+
+	"polymarket/internal/config"
+)
+
+func TestCalendar_NewYorkEvening(t *testing.T) {
+	cal, err := New(config.TradingDayConfig{Timezone: "America/New_York", BoundaryHour: 17})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 20:00 and 23:30 New York on Oct 14 straddle UTC midnight but share
+	// the trading day that started at 17:00 that afternoon.
+	evening := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2026, 10, 15, 3, 30, 0, 0, time.UTC)
+	wantStart := time.Date(2026, 10, 14, 21, 0, 0, 0, time.UTC)
+	wantDate := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	for _, at := range []time.Time{evening, late} {
+		if got := cal.Start(at); !got.Equal(wantStart) {
+			t.Fatalf("Start(%v) = %v, want %v", at, got, wantStart)
+		}
+		if got := cal.Date(at); !got.Equal(wantDate) {
+			t.Fatalf("Date(%v) = %v, want %v", at, got, wantDate)
+		}
+	}
+	if got := cal.DateStart(wantDate); !got.Equal(wantStart) {
+		t.Fatalf("DateStart = %v, want %v", got, wantStart)
+	}
+	// After the boundary the next day begins.
+	if got := cal.Date(time.Date(2026, 10, 15, 21, 0, 0, 0, time.UTC)); !got.Equal(wantDate.AddDate(0, 0, 1)) {
+		t.Fatalf("Date at boundary = %v", got)
+	}
+}
+
+func TestCalendar_NilIsUTCMidnight(t *testing.T) {
+	var cal *Calendar
+	at := time.Date(2026, 10, 15, 23, 59, 0, 0, time.UTC)
+	if got := cal.Start(at); !got.Equal(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Start = %v", got)
+	}
+	if cal.String() != "UTC@00:00" {
+		t.Fatalf("String = %q", cal.String())
+	}
+	if _, err := New(config.TradingDayConfig{Timezone: "UTC", BoundaryHour: 24}); err == nil {
+		t.Fatal("boundary 24 accepted")
+	}
+}
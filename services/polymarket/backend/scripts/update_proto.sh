@@ -0,0 +1,17 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+repo_root=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+cd "$repo_root"
+
+for tool in buf protoc-gen-go protoc-gen-go-grpc; do
+  if ! command -v "$tool" >/dev/null 2>&1; then
+    echo "$tool not found in PATH. Install with:" >&2
+    echo "  go install github.com/bufbuild/buf/cmd/buf@latest" >&2
+    echo "  go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.36.9" >&2
+    echo "  go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.5.1" >&2
+    exit 1
+  fi
+done
+
+buf generate